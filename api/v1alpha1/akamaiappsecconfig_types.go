@@ -0,0 +1,239 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// AkamaiAppSecConfigSpec defines the desired state of an Application Security configuration
+// +kubebuilder:validation:XValidation:rule="!has(self.matchTargets) || self.matchTargets.all(t, has(self.policies) && self.policies.exists(p, p.name == t.policy))",message="matchTargets must reference policies of the spec"
+// +kubebuilder:validation:XValidation:rule="!has(self.ratePolicies) || self.ratePolicies.all(r, !has(r.actions) || r.actions.all(a, has(self.policies) && self.policies.exists(p, p.name == a.policy)))",message="ratePolicies actions must reference policies of the spec"
+type AkamaiAppSecConfigSpec struct {
+	// Name is the name of the security configuration. Defaults to the name of the resource.
+	Name string `json:"name,omitempty"`
+
+	// Description describes the security configuration
+	Description string `json:"description,omitempty"`
+
+	// ContractID is the Akamai contract ID the security configuration is created in
+	ContractID string `json:"contractId"`
+
+	// GroupID is the Akamai group ID the security configuration is created in, e.g. grp_12345
+	GroupID string `json:"groupId"`
+
+	// Hostnames are the hostnames the security configuration protects
+	// +kubebuilder:validation:MinItems=1
+	Hostnames []string `json:"hostnames"`
+
+	// Policies are the security policies of the configuration. Policies not listed are left alone.
+	Policies []AppSecPolicySpec `json:"policies,omitempty"`
+
+	// MatchTargets assign hostnames and paths to the policies. When set, they replace the website
+	// match targets of the configuration.
+	MatchTargets []AppSecMatchTargetSpec `json:"matchTargets,omitempty"`
+
+	// RatePolicies limit the requests a client may send and the actions the security policies take
+	// on clients exceeding them. Rate policies not listed are left alone.
+	RatePolicies []AppSecRatePolicySpec `json:"ratePolicies,omitempty"`
+
+	// Activation activates the latest version of the configuration on the Akamai networks
+	Activation *AppSecActivationSpec `json:"activation,omitempty"`
+
+	// DeletionPolicy controls what happens to the security configuration in Akamai when the
+	// resource is deleted: Delete removes it, Retain (the default) leaves it in place
+	// +kubebuilder:validation:Enum=Delete;Retain
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
+}
+
+// AppSecPolicySpec defines a security policy
+type AppSecPolicySpec struct {
+	// Name is the name of the security policy
+	Name string `json:"name"`
+
+	// Prefix is the four character prefix of the policy ID a new policy is created with
+	// +kubebuilder:validation:Pattern=`^[A-Za-z0-9]{4}$`
+	Prefix string `json:"prefix"`
+
+	// WAFMode is how the web application firewall rules are updated: AAG and ASE_AUTO follow
+	// Akamai's recommendations automatically, KRS and ASE_MANUAL only on request
+	// +kubebuilder:validation:Enum=AAG;KRS;ASE_AUTO;ASE_MANUAL
+	WAFMode string `json:"wafMode,omitempty"`
+
+	// RuleActions set the action of individual WAF rules, which requires the KRS or ASE_MANUAL
+	// mode. Rules not listed are left alone.
+	RuleActions []AppSecRuleAction `json:"ruleActions,omitempty"`
+}
+
+// AppSecRuleAction sets the action of a WAF rule
+type AppSecRuleAction struct {
+	// RuleID is the ID of the rule
+	RuleID int `json:"ruleId"`
+
+	// Action is what the rule does when it matches: alert, deny, none or a custom deny action
+	// such as deny_custom_12345
+	// +kubebuilder:validation:Pattern=`^(alert|deny|none|deny_custom_[0-9]+)$`
+	Action string `json:"action"`
+}
+
+// AppSecMatchTargetSpec assigns requests to a security policy
+type AppSecMatchTargetSpec struct {
+	// Policy is the name of the security policy of the matched requests
+	Policy string `json:"policy"`
+
+	// Hostnames are the hostnames matched. Defaults to all hostnames of the configuration.
+	Hostnames []string `json:"hostnames,omitempty"`
+
+	// Paths are the paths matched, e.g. /api/*. Defaults to all paths.
+	Paths []string `json:"paths,omitempty"`
+}
+
+// AppSecRatePolicySpec defines a rate policy
+type AppSecRatePolicySpec struct {
+	// Name is the name of the rate policy
+	Name string `json:"name"`
+
+	// Description describes the rate policy
+	Description string `json:"description,omitempty"`
+
+	// AverageThreshold is the number of requests per second a client may send, averaged over two
+	// minutes
+	// +kubebuilder:validation:Minimum=1
+	AverageThreshold int `json:"averageThreshold"`
+
+	// BurstThreshold is the number of requests per second a client may send, averaged over five
+	// seconds
+	// +kubebuilder:validation:Minimum=1
+	BurstThreshold int `json:"burstThreshold"`
+
+	// ClientIdentifier is how clients are told apart: by IP address (the default), by IP address
+	// and user agent, or by API key
+	// +kubebuilder:validation:Enum=ip;ip-useragent;api-key
+	ClientIdentifier string `json:"clientIdentifier,omitempty"`
+
+	// RequestType is what is counted: client requests (the default), origin responses, forward
+	// requests or forward responses
+	// +kubebuilder:validation:Enum=ClientRequest;ClientResponse;ForwardRequest;ForwardResponse
+	RequestType string `json:"requestType,omitempty"`
+
+	// Hostnames are the hostnames counted. Defaults to all hostnames of the configuration.
+	Hostnames []string `json:"hostnames,omitempty"`
+
+	// Paths are the paths counted, e.g. /login. Defaults to all requests.
+	Paths []string `json:"paths,omitempty"`
+
+	// Actions are what the security policies do with clients exceeding the rate policy. Security
+	// policies not listed are left alone.
+	Actions []AppSecRatePolicyAction `json:"actions,omitempty"`
+}
+
+// AppSecRatePolicyAction sets the action a security policy takes on clients exceeding a rate policy
+type AppSecRatePolicyAction struct {
+	// Policy is the name of the security policy
+	Policy string `json:"policy"`
+
+	// Action is taken on IPv4 and IPv6 clients: alert, deny, none or a custom deny action such as
+	// deny_custom_12345
+	// +kubebuilder:validation:Pattern=`^(alert|deny|none|deny_custom_[0-9]+)$`
+	Action string `json:"action"`
+}
+
+// AppSecActivationSpec defines on which networks the configuration is activated
+type AppSecActivationSpec struct {
+	// Staging activates each new version on the staging network
+	Staging bool `json:"staging,omitempty"`
+
+	// Production activates each new version on the production network, after staging when both
+	// are enabled
+	Production bool `json:"production,omitempty"`
+
+	// Note is the note of the activations
+	Note string `json:"note,omitempty"`
+
+	// NotificationEmails are notified about the activations
+	NotificationEmails []string `json:"notificationEmails,omitempty"`
+}
+
+// AppSecActivationStatus is the state of the last activation of the configuration on a network
+type AppSecActivationStatus struct {
+	// ActivationID is the ID of the activation
+	ActivationID int `json:"activationId,omitempty"`
+
+	// Version is the version of the configuration activated
+	Version int `json:"version,omitempty"`
+
+	// Status is the status of the activation, e.g. RECEIVED, ACTIVATED or FAILED
+	Status string `json:"status,omitempty"`
+}
+
+// AkamaiAppSecConfigStatus defines the observed state of an Application Security configuration
+type AkamaiAppSecConfigStatus struct {
+	// ConfigID is the ID of the security configuration
+	ConfigID int `json:"configId,omitempty"`
+
+	// LatestVersion is the latest version of the configuration
+	LatestVersion int `json:"latestVersion,omitempty"`
+
+	// StagingVersion is the version active on the staging network
+	StagingVersion int `json:"stagingVersion,omitempty"`
+
+	// ProductionVersion is the version active on the production network
+	ProductionVersion int `json:"productionVersion,omitempty"`
+
+	// Policies are the IDs of the security policies of the spec by name
+	Policies map[string]string `json:"policies,omitempty"`
+
+	// RatePolicies are the IDs of the rate policies of the spec by name
+	RatePolicies map[string]int `json:"ratePolicies,omitempty"`
+
+	// LastChanges are the changes made to the configuration by the last update
+	LastChanges []string `json:"lastChanges,omitempty"`
+
+	// Staging is the last activation on the staging network
+	Staging *AppSecActivationStatus `json:"staging,omitempty"`
+
+	// Production is the last activation on the production network
+	Production *AppSecActivationStatus `json:"production,omitempty"`
+
+	// ObservedGeneration is the metadata.generation the status belongs to
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Phase represents the current phase of the security configuration
+	Phase string `json:"phase,omitempty"`
+
+	// LastUpdated is the timestamp of the last status update
+	LastUpdated *metav1.Time `json:"lastUpdated,omitempty"`
+
+	// Conditions represent the latest available observations of the configuration's state
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:resource:scope=Cluster
+//+kubebuilder:printcolumn:name="Config ID",type=integer,JSONPath=`.status.configId`
+//+kubebuilder:printcolumn:name="Latest",type=integer,JSONPath=`.status.latestVersion`
+//+kubebuilder:printcolumn:name="Staging",type=integer,JSONPath=`.status.stagingVersion`
+//+kubebuilder:printcolumn:name="Production",type=integer,JSONPath=`.status.productionVersion`
+//+kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// AkamaiAppSecConfig is the Schema for the akamaiappsecconfigs API
+type AkamaiAppSecConfig struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   AkamaiAppSecConfigSpec   `json:"spec,omitempty"`
+	Status AkamaiAppSecConfigStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// AkamaiAppSecConfigList contains a list of AkamaiAppSecConfig
+type AkamaiAppSecConfigList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []AkamaiAppSecConfig `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&AkamaiAppSecConfig{}, &AkamaiAppSecConfigList{})
+}
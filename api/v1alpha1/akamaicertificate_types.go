@@ -0,0 +1,175 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// AkamaiCertificateSpec defines the desired state of a CPS enrollment and its certificate
+type AkamaiCertificateSpec struct {
+	// ContractID is the contract the enrollment belongs to
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="contractId is immutable"
+	ContractID string `json:"contractId"`
+
+	// ValidationType is how the certificate is validated: dv (Let's Encrypt), ov, ev or
+	// third-party for a certificate signed by a CA of your choice
+	// +kubebuilder:validation:Enum=dv;ov;ev;third-party
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="validationType is immutable"
+	ValidationType string `json:"validationType"`
+
+	// CommonName is the common name of the certificate, which identifies the enrollment in the
+	// contract
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="commonName is immutable"
+	CommonName string `json:"commonName"`
+
+	// SANs are the subject alternative names of the certificate besides the common name
+	SANs []string `json:"sans,omitempty"`
+
+	// SecureNetwork is the network the certificate is deployed to
+	// +kubebuilder:validation:Enum=enhanced-tls;standard-tls
+	// +kubebuilder:default=enhanced-tls
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="secureNetwork is immutable"
+	SecureNetwork string `json:"secureNetwork,omitempty"`
+
+	// SNIOnly serves the certificate only to clients sending SNI
+	// +kubebuilder:default=true
+	SNIOnly *bool `json:"sniOnly,omitempty"`
+
+	// Geography is where the certificate is deployed: core, china+core or russia+core
+	// +kubebuilder:validation:Enum=core;china+core;russia+core
+	// +kubebuilder:default=core
+	Geography string `json:"geography,omitempty"`
+
+	// ChangeManagement holds the deployment to production until the certificate was tested on
+	// staging and the change is acknowledged in Akamai
+	ChangeManagement bool `json:"changeManagement,omitempty"`
+
+	// Organization is the organization the certificate is issued to
+	Organization CertificateOrganization `json:"organization"`
+
+	// AdminContact is the contact of the organization for the certificate
+	AdminContact CertificateContact `json:"adminContact"`
+
+	// TechContact is the technical contact at Akamai for the certificate
+	TechContact CertificateContact `json:"techContact"`
+
+	// DeletionPolicy controls what happens to the enrollment in Akamai when the resource is
+	// deleted: Delete removes it with its certificates, Retain (the default) leaves it in place
+	// +kubebuilder:validation:Enum=Delete;Retain
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
+}
+
+// CertificateOrganization is the organization a certificate is issued to
+type CertificateOrganization struct {
+	Name           string `json:"name"`
+	AddressLineOne string `json:"addressLineOne,omitempty"`
+	AddressLineTwo string `json:"addressLineTwo,omitempty"`
+	City           string `json:"city,omitempty"`
+	Region         string `json:"region,omitempty"`
+	PostalCode     string `json:"postalCode,omitempty"`
+	// Country is the two letter country code, e.g. CH
+	Country string `json:"country,omitempty"`
+	Phone   string `json:"phone,omitempty"`
+}
+
+// CertificateContact is a contact of a certificate enrollment
+type CertificateContact struct {
+	FirstName        string `json:"firstName"`
+	LastName         string `json:"lastName"`
+	Email            string `json:"email"`
+	Phone            string `json:"phone"`
+	Title            string `json:"title,omitempty"`
+	OrganizationName string `json:"organizationName,omitempty"`
+	AddressLineOne   string `json:"addressLineOne,omitempty"`
+	AddressLineTwo   string `json:"addressLineTwo,omitempty"`
+	City             string `json:"city,omitempty"`
+	Region           string `json:"region,omitempty"`
+	PostalCode       string `json:"postalCode,omitempty"`
+	Country          string `json:"country,omitempty"`
+}
+
+// CertificateDeploymentStatus is the certificate deployed on a network
+type CertificateDeploymentStatus struct {
+	// Expiry is when the deployed certificate expires
+	Expiry string `json:"expiry,omitempty"`
+
+	// DNSNames are the names the deployed certificate covers
+	DNSNames []string `json:"dnsNames,omitempty"`
+}
+
+// CertificateChangeStatus is the state of the pending change of an enrollment
+type CertificateChangeStatus struct {
+	// Status is the status of the change, e.g. wait-upload-third-party or wait-review-cert-warning
+	Status string `json:"status,omitempty"`
+
+	// Description describes the current step of the change
+	Description string `json:"description,omitempty"`
+
+	// Error describes why the change failed
+	Error string `json:"error,omitempty"`
+
+	// RequiredInput are the inputs the change waits for, e.g. third-party-certificate or
+	// change-management-info
+	RequiredInput []string `json:"requiredInput,omitempty"`
+}
+
+// AkamaiCertificateStatus defines the observed state of a CPS enrollment
+type AkamaiCertificateStatus struct {
+	// EnrollmentID is the ID of the enrollment, used by edge hostnames referencing the certificate
+	EnrollmentID int `json:"enrollmentId,omitempty"`
+
+	// SANs are the subject alternative names of the enrollment
+	SANs []string `json:"sans,omitempty"`
+
+	// PendingChange is the change of the enrollment in progress
+	PendingChange *CertificateChangeStatus `json:"pendingChange,omitempty"`
+
+	// Staging is the certificate deployed on the staging network
+	Staging *CertificateDeploymentStatus `json:"staging,omitempty"`
+
+	// Production is the certificate deployed on the production network
+	Production *CertificateDeploymentStatus `json:"production,omitempty"`
+
+	// ObservedGeneration is the metadata.generation the status belongs to
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Phase represents the current phase of the certificate
+	Phase string `json:"phase,omitempty"`
+
+	// LastUpdated is the timestamp of the last status update
+	LastUpdated *metav1.Time `json:"lastUpdated,omitempty"`
+
+	// Conditions represent the latest available observations of the certificate's state
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:resource:scope=Cluster
+//+kubebuilder:printcolumn:name="Enrollment",type=integer,JSONPath=`.status.enrollmentId`
+//+kubebuilder:printcolumn:name="Common Name",type=string,JSONPath=`.spec.commonName`
+//+kubebuilder:printcolumn:name="Change",type=string,JSONPath=`.status.pendingChange.status`
+//+kubebuilder:printcolumn:name="Expiry",type=string,JSONPath=`.status.production.expiry`
+//+kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// AkamaiCertificate is the Schema for the akamaicertificates API
+type AkamaiCertificate struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   AkamaiCertificateSpec   `json:"spec,omitempty"`
+	Status AkamaiCertificateStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// AkamaiCertificateList contains a list of AkamaiCertificate
+type AkamaiCertificateList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []AkamaiCertificate `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&AkamaiCertificate{}, &AkamaiCertificateList{})
+}
@@ -0,0 +1,150 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// AkamaiClientListSpec defines the desired state of an Akamai client list
+type AkamaiClientListSpec struct {
+	// Name is the name of the client list in Akamai. Defaults to the name of the resource.
+	Name string `json:"name,omitempty"`
+
+	// Type is the type of the items: IP addresses and CIDR blocks, GEO country codes, ASN
+	// autonomous system numbers, TLS_FINGERPRINT client fingerprints or FILE_HASH hashes. It
+	// can't be changed once the list exists.
+	// +kubebuilder:validation:Enum=IP;GEO;ASN;TLS_FINGERPRINT;FILE_HASH
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="type is immutable"
+	Type string `json:"type"`
+
+	// Notes describe the client list in Akamai
+	Notes string `json:"notes,omitempty"`
+
+	// Tags label the client list in Akamai
+	Tags []string `json:"tags,omitempty"`
+
+	// ContractID is the Akamai contract ID the client list is created in
+	ContractID string `json:"contractId,omitempty"`
+
+	// GroupID is the Akamai group ID the client list is created in, e.g. grp_12345
+	GroupID string `json:"groupId,omitempty"`
+
+	// Items are the entries of the list
+	Items []ClientListItem `json:"items,omitempty"`
+
+	// Activation activates the client list on the Akamai networks
+	Activation *ClientListActivationSpec `json:"activation,omitempty"`
+
+	// DeletionPolicy controls what happens to the client list in Akamai when the resource is
+	// deleted: Delete removes it, Retain (the default) leaves it in place
+	// +kubebuilder:validation:Enum=Delete;Retain
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
+}
+
+// ClientListItem is an entry of a client list
+type ClientListItem struct {
+	// Value is the IP address or CIDR block, country code, autonomous system number,
+	// fingerprint or hash of the entry
+	Value string `json:"value"`
+
+	// Description describes the entry
+	Description string `json:"description,omitempty"`
+
+	// Tags label the entry
+	Tags []string `json:"tags,omitempty"`
+
+	// ExpirationDate removes the entry from the list at the given time, e.g.
+	// 2027-01-01T00:00:00Z
+	// +kubebuilder:validation:Format=date-time
+	ExpirationDate string `json:"expirationDate,omitempty"`
+}
+
+// ClientListActivationSpec defines on which networks the client list is activated
+type ClientListActivationSpec struct {
+	// Staging activates each change of the list on the staging network
+	Staging bool `json:"staging,omitempty"`
+
+	// Production activates each change of the list on the production network
+	Production bool `json:"production,omitempty"`
+
+	// Comments are the comments of the activations
+	Comments string `json:"comments,omitempty"`
+
+	// NotificationEmails are notified about the activations
+	NotificationEmails []string `json:"notificationEmails,omitempty"`
+}
+
+// ClientListActivationStatus is the activation state of a client list on a network
+type ClientListActivationStatus struct {
+	// Status is the activation status, e.g. ACTIVE, PENDING_ACTIVATION or INACTIVE
+	Status string `json:"status,omitempty"`
+
+	// Version is the version of the list the status belongs to
+	Version int64 `json:"version,omitempty"`
+
+	// ActivationID is the ID of the last activation
+	ActivationID int64 `json:"activationId,omitempty"`
+}
+
+// AkamaiClientListStatus defines the observed state of an Akamai client list
+type AkamaiClientListStatus struct {
+	// ListID is the ID of the client list, used to reference it from security configurations
+	// and match criteria
+	ListID string `json:"listId,omitempty"`
+
+	// Version is the current version of the client list
+	Version int64 `json:"version,omitempty"`
+
+	// ItemCount is the number of items of the client list
+	ItemCount int64 `json:"itemCount,omitempty"`
+
+	// Staging is the activation state of the client list on the staging network
+	Staging *ClientListActivationStatus `json:"staging,omitempty"`
+
+	// Production is the activation state of the client list on the production network
+	Production *ClientListActivationStatus `json:"production,omitempty"`
+
+	// ObservedGeneration is the metadata.generation the status belongs to
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Phase represents the current phase of the client list
+	Phase string `json:"phase,omitempty"`
+
+	// LastUpdated is the timestamp of the last status update
+	LastUpdated *metav1.Time `json:"lastUpdated,omitempty"`
+
+	// Conditions represent the latest available observations of the client list's state
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:resource:scope=Cluster
+//+kubebuilder:printcolumn:name="ID",type=string,JSONPath=`.status.listId`
+//+kubebuilder:printcolumn:name="Type",type=string,JSONPath=`.spec.type`
+//+kubebuilder:printcolumn:name="Items",type=integer,JSONPath=`.status.itemCount`
+//+kubebuilder:printcolumn:name="Staging",type=string,JSONPath=`.status.staging.status`
+//+kubebuilder:printcolumn:name="Production",type=string,JSONPath=`.status.production.status`
+//+kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// AkamaiClientList is the Schema for the akamaiclientlists API
+type AkamaiClientList struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   AkamaiClientListSpec   `json:"spec,omitempty"`
+	Status AkamaiClientListStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// AkamaiClientListList contains a list of AkamaiClientList
+type AkamaiClientListList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []AkamaiClientList `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&AkamaiClientList{}, &AkamaiClientListList{})
+}
@@ -0,0 +1,127 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// AkamaiCloudletPolicySpec defines the desired state of a Cloudlets shared policy
+type AkamaiCloudletPolicySpec struct {
+	// Name is the name of the policy. Defaults to the name of the resource with dashes and dots
+	// replaced by underscores. It can't be changed.
+	// +kubebuilder:validation:MaxLength=64
+	// +kubebuilder:validation:Pattern=`^[a-zA-Z0-9_]+$`
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="name is immutable"
+	Name string `json:"name,omitempty"`
+
+	// CloudletType is the Cloudlet the policy is for: ER (Edge Redirector), CD (Phased Release)
+	// or AS (Audience Segmentation). It can't be changed.
+	// +kubebuilder:validation:Enum=ER;CD;AS
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="cloudletType is immutable"
+	CloudletType string `json:"cloudletType"`
+
+	// GroupID is the Akamai group the policy belongs to, e.g. grp_12345
+	GroupID string `json:"groupId"`
+
+	// Description describes the policy and its versions in Akamai
+	// +kubebuilder:validation:MaxLength=255
+	Description string `json:"description,omitempty"`
+
+	// MatchRules are the match rules of the policy in the format of the Cloudlets API, e.g. with
+	// matches and redirectURL for Edge Redirector. The type of each rule defaults to the one of
+	// the Cloudlet.
+	// +kubebuilder:pruning:PreserveUnknownFields
+	MatchRules []runtime.RawExtension `json:"matchRules,omitempty"`
+
+	// Activation activates the policy on the Akamai networks
+	Activation *CloudletPolicyActivationSpec `json:"activation,omitempty"`
+
+	// DeletionPolicy controls what happens to the policy in Akamai when the resource is deleted:
+	// Delete deactivates and removes it, Retain (the default) leaves it in place
+	// +kubebuilder:validation:Enum=Delete;Retain
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
+}
+
+// CloudletPolicyActivationSpec defines on which networks the policy is activated
+type CloudletPolicyActivationSpec struct {
+	// Staging activates each version of the policy on the staging network
+	Staging bool `json:"staging,omitempty"`
+
+	// Production activates each version of the policy on the production network
+	Production bool `json:"production,omitempty"`
+}
+
+// CloudletPolicyActivationStatus is the activation state of a policy on a network
+type CloudletPolicyActivationStatus struct {
+	// Status is the status of the last activation: IN_PROGRESS, SUCCESS or FAILED
+	Status string `json:"status,omitempty"`
+
+	// Version is the version of the policy the last activation activated
+	Version int64 `json:"version,omitempty"`
+
+	// ActivationID is the ID of the last activation
+	ActivationID int64 `json:"activationId,omitempty"`
+}
+
+// AkamaiCloudletPolicyStatus defines the observed state of a Cloudlets shared policy
+type AkamaiCloudletPolicyStatus struct {
+	// PolicyID is the ID of the policy, used to reference it from property rules
+	PolicyID int64 `json:"policyId,omitempty"`
+
+	// Version is the version of the policy holding the match rules of the spec
+	Version int64 `json:"version,omitempty"`
+
+	// Staging is the activation state of the policy on the staging network
+	Staging *CloudletPolicyActivationStatus `json:"staging,omitempty"`
+
+	// Production is the activation state of the policy on the production network
+	Production *CloudletPolicyActivationStatus `json:"production,omitempty"`
+
+	// Warnings are the warnings Akamai reported for the match rules of the version
+	Warnings []string `json:"warnings,omitempty"`
+
+	// ObservedGeneration is the metadata.generation the status belongs to
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Phase represents the current phase of the policy
+	Phase string `json:"phase,omitempty"`
+
+	// LastUpdated is the timestamp of the last status update
+	LastUpdated *metav1.Time `json:"lastUpdated,omitempty"`
+
+	// Conditions represent the latest available observations of the policy's state
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:resource:scope=Cluster
+//+kubebuilder:printcolumn:name="ID",type=integer,JSONPath=`.status.policyId`
+//+kubebuilder:printcolumn:name="Type",type=string,JSONPath=`.spec.cloudletType`
+//+kubebuilder:printcolumn:name="Version",type=integer,JSONPath=`.status.version`
+//+kubebuilder:printcolumn:name="Staging",type=string,JSONPath=`.status.staging.status`
+//+kubebuilder:printcolumn:name="Production",type=string,JSONPath=`.status.production.status`
+//+kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// AkamaiCloudletPolicy is the Schema for the akamaicloudletpolicies API
+type AkamaiCloudletPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   AkamaiCloudletPolicySpec   `json:"spec,omitempty"`
+	Status AkamaiCloudletPolicyStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// AkamaiCloudletPolicyList contains a list of AkamaiCloudletPolicy
+type AkamaiCloudletPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []AkamaiCloudletPolicy `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&AkamaiCloudletPolicy{}, &AkamaiCloudletPolicyList{})
+}
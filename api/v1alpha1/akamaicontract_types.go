@@ -0,0 +1,61 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// AkamaiContractSpec mirrors an Akamai contract available to the operator's API client
+type AkamaiContractSpec struct {
+	// ContractID is the Akamai contract ID
+	ContractID string `json:"contractId"`
+
+	// ContractTypeName is the type of the contract (e.g., "AKAMAI_INTERNAL", "DIRECT_CUSTOMER")
+	ContractTypeName string `json:"contractTypeName,omitempty"`
+}
+
+// AkamaiProduct describes a product available on a contract
+type AkamaiProduct struct {
+	// ProductID is the Akamai product ID (e.g., "prd_Fresca")
+	ProductID string `json:"productId"`
+
+	// ProductName is the human readable product name
+	ProductName string `json:"productName,omitempty"`
+}
+
+// AkamaiContractStatus defines the observed state of AkamaiContract
+type AkamaiContractStatus struct {
+	// Products are the products available on the contract
+	Products []AkamaiProduct `json:"products,omitempty"`
+
+	// LastSynced is the timestamp when the contract was last refreshed from Akamai
+	LastSynced *metav1.Time `json:"lastSynced,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:resource:scope=Cluster
+//+kubebuilder:printcolumn:name="Contract ID",type=string,JSONPath=`.spec.contractId`
+//+kubebuilder:printcolumn:name="Type",type=string,JSONPath=`.spec.contractTypeName`
+//+kubebuilder:printcolumn:name="Last Synced",type=date,JSONPath=`.status.lastSynced`
+
+// AkamaiContract is a read-only mirror of an Akamai contract maintained by the operator
+type AkamaiContract struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   AkamaiContractSpec   `json:"spec,omitempty"`
+	Status AkamaiContractStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// AkamaiContractList contains a list of AkamaiContract
+type AkamaiContractList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []AkamaiContract `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&AkamaiContract{}, &AkamaiContractList{})
+}
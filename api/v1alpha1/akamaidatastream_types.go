@@ -0,0 +1,178 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// AkamaiDataStreamSpec defines the desired state of a DataStream 2 stream delivering the edge
+// logs of properties
+// +kubebuilder:validation:XValidation:rule="(has(self.propertyRefs) && size(self.propertyRefs) > 0) || (has(self.propertyIds) && size(self.propertyIds) > 0)",message="at least one of propertyRefs and propertyIds is required"
+type AkamaiDataStreamSpec struct {
+	// ContractID is the contract the stream belongs to
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="contractId is immutable"
+	ContractID string `json:"contractId"`
+
+	// GroupID is the group the stream belongs to, e.g. grp_12345
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="groupId is immutable"
+	GroupID string `json:"groupId"`
+
+	// StreamName is the name of the stream. Defaults to the name of the resource.
+	StreamName string `json:"streamName,omitempty"`
+
+	// PropertyRefs are the names of the AkamaiProperties whose logs are delivered
+	PropertyRefs []string `json:"propertyRefs,omitempty"`
+
+	// PropertyIDs are the IDs of properties not managed by the operator whose logs are delivered,
+	// e.g. prp_12345
+	PropertyIDs []string `json:"propertyIds,omitempty"`
+
+	// DatasetFieldIDs are the IDs of the log fields delivered, in the order of the log lines,
+	// e.g. 1000 (CP code) and 1002 (request ID)
+	// +kubebuilder:validation:MinItems=1
+	DatasetFieldIDs []int `json:"datasetFieldIds"`
+
+	// Format is the format of the log lines: JSON or STRUCTURED (space delimited)
+	// +kubebuilder:validation:Enum=JSON;STRUCTURED
+	// +kubebuilder:default=JSON
+	Format string `json:"format,omitempty"`
+
+	// UploadIntervalSeconds is how often log files are sent to the destination: 30 or 60
+	// +kubebuilder:validation:Enum=30;60
+	// +kubebuilder:default=60
+	UploadIntervalSeconds int `json:"uploadIntervalSeconds,omitempty"`
+
+	// UploadFilePrefix and UploadFileSuffix are added to the names of the log files sent to
+	// object storage destinations
+	UploadFilePrefix string `json:"uploadFilePrefix,omitempty"`
+	UploadFileSuffix string `json:"uploadFileSuffix,omitempty"`
+
+	// CollectMidgress also delivers the logs of requests between edge servers
+	CollectMidgress bool `json:"collectMidgress,omitempty"`
+
+	// NotificationEmails are notified about activations and delivery failures of the stream
+	NotificationEmails []string `json:"notificationEmails,omitempty"`
+
+	// Destination is where the logs are delivered to
+	Destination DataStreamDestination `json:"destination"`
+
+	// Active delivers the logs; false deactivates the stream
+	// +kubebuilder:default=true
+	Active *bool `json:"active,omitempty"`
+
+	// DeletionPolicy controls what happens to the stream in Akamai when the resource is deleted:
+	// Delete deactivates and deletes it, Retain (the default) leaves it in place
+	// +kubebuilder:validation:Enum=Delete;Retain
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
+}
+
+// DataStreamDestination defines where a stream delivers its logs to. The credentials are read
+// from the keys of the Secret: accessKey and secretAccessKey for S3, eventCollectorToken for
+// SPLUNK, authToken for DATADOG and username and password for HTTPS with BASIC authentication.
+// caCert, clientCert and clientKey configure mTLS for SPLUNK and HTTPS.
+// +kubebuilder:validation:XValidation:rule="self.type != 'S3' || (has(self.bucket) && has(self.region))",message="bucket and region are required with type S3"
+// +kubebuilder:validation:XValidation:rule="self.type == 'S3' || has(self.endpoint)",message="endpoint is required with type SPLUNK, DATADOG and HTTPS"
+type DataStreamDestination struct {
+	// Type is the kind of destination
+	// +kubebuilder:validation:Enum=S3;SPLUNK;DATADOG;HTTPS
+	Type string `json:"type"`
+
+	// DisplayName is the name of the destination in Akamai. Defaults to the stream name.
+	DisplayName string `json:"displayName,omitempty"`
+
+	// Endpoint is the URL logs are sent to with SPLUNK, DATADOG and HTTPS
+	Endpoint string `json:"endpoint,omitempty"`
+
+	// Bucket, Region and Path locate the log files in S3
+	Bucket string `json:"bucket,omitempty"`
+	Region string `json:"region,omitempty"`
+	Path   string `json:"path,omitempty"`
+
+	// CompressLogs sends gzip compressed logs to SPLUNK, DATADOG and HTTPS
+	CompressLogs bool `json:"compressLogs,omitempty"`
+
+	// AuthenticationType is how HTTPS destinations are authenticated to
+	// +kubebuilder:validation:Enum=NONE;BASIC
+	AuthenticationType string `json:"authenticationType,omitempty"`
+
+	// ContentType is the content type of the requests to HTTPS destinations
+	ContentType string `json:"contentType,omitempty"`
+
+	// CustomHeaderName and CustomHeaderValue are sent with every request to SPLUNK and HTTPS
+	// destinations
+	CustomHeaderName  string `json:"customHeaderName,omitempty"`
+	CustomHeaderValue string `json:"customHeaderValue,omitempty"`
+
+	// TLSHostname is the hostname verified in the certificate of SPLUNK and HTTPS destinations
+	TLSHostname string `json:"tlsHostname,omitempty"`
+
+	// Service, Source and Tags label the logs sent to DATADOG
+	Service string `json:"service,omitempty"`
+	Source  string `json:"source,omitempty"`
+	Tags    string `json:"tags,omitempty"`
+
+	// SecretRef references the Secret with the credentials of the destination
+	SecretRef *SecretReference `json:"secretRef,omitempty"`
+}
+
+// AkamaiDataStreamStatus defines the observed state of a DataStream 2 stream
+type AkamaiDataStreamStatus struct {
+	// StreamID is the ID of the stream
+	StreamID int64 `json:"streamId,omitempty"`
+
+	// StreamVersion is the latest version of the stream
+	StreamVersion int64 `json:"streamVersion,omitempty"`
+
+	// StreamStatus is the activation status of the stream, e.g. ACTIVATED or INACTIVE
+	StreamStatus string `json:"streamStatus,omitempty"`
+
+	// PropertyIDs are the properties whose logs the stream delivers
+	PropertyIDs []string `json:"propertyIds,omitempty"`
+
+	// ConfigurationHash is the hash of the configuration the latest version was written with,
+	// including the credentials of the destination
+	ConfigurationHash string `json:"configurationHash,omitempty"`
+
+	// ObservedGeneration is the metadata.generation the status belongs to
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Phase represents the current phase of the stream
+	Phase string `json:"phase,omitempty"`
+
+	// LastUpdated is the timestamp of the last status update
+	LastUpdated *metav1.Time `json:"lastUpdated,omitempty"`
+
+	// Conditions represent the latest available observations of the stream's state
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:resource:scope=Cluster
+//+kubebuilder:printcolumn:name="Stream",type=integer,JSONPath=`.status.streamId`
+//+kubebuilder:printcolumn:name="Destination",type=string,JSONPath=`.spec.destination.type`
+//+kubebuilder:printcolumn:name="Version",type=integer,JSONPath=`.status.streamVersion`
+//+kubebuilder:printcolumn:name="Status",type=string,JSONPath=`.status.streamStatus`
+//+kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// AkamaiDataStream is the Schema for the akamaidatastreams API
+type AkamaiDataStream struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   AkamaiDataStreamSpec   `json:"spec,omitempty"`
+	Status AkamaiDataStreamStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// AkamaiDataStreamList contains a list of AkamaiDataStream
+type AkamaiDataStreamList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []AkamaiDataStream `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&AkamaiDataStream{}, &AkamaiDataStreamList{})
+}
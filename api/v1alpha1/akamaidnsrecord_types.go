@@ -0,0 +1,102 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// AkamaiDnsRecordSpec defines the desired state of an Edge DNS record set
+// +kubebuilder:validation:XValidation:rule="has(self.targets) != has(self.edgeHostnameRef)",message="exactly one of targets and edgeHostnameRef must be set"
+// +kubebuilder:validation:XValidation:rule="!has(self.edgeHostnameRef) || self.type == 'CNAME'",message="edgeHostnameRef is only valid for CNAME records"
+// +kubebuilder:validation:XValidation:rule="self.type != 'CNAME' || !has(self.targets) || size(self.targets) == 1",message="a CNAME record has a single target"
+type AkamaiDnsRecordSpec struct {
+	// Zone is the Edge DNS zone the record set belongs to, e.g. example.com
+	Zone string `json:"zone"`
+
+	// Name is the fully qualified name of the record set, e.g. www.example.com. Defaults to the
+	// name of the resource.
+	Name string `json:"name,omitempty"`
+
+	// Type is the type of the record set
+	// +kubebuilder:validation:Enum=A;AAAA;CNAME;TXT
+	Type string `json:"type"`
+
+	// TTL is the time to live of the record set in seconds
+	// +kubebuilder:validation:Minimum=30
+	// +kubebuilder:default=300
+	TTL int `json:"ttl,omitempty"`
+
+	// Targets are the values of the record set: IP addresses, a hostname or texts
+	// +kubebuilder:validation:MinItems=1
+	Targets []string `json:"targets,omitempty"`
+
+	// EdgeHostnameRef is the name of an AkamaiEdgeHostname whose domain is the target of a CNAME
+	// record, instead of targets
+	EdgeHostnameRef string `json:"edgeHostnameRef,omitempty"`
+
+	// DeletionPolicy controls what happens to the record set in Edge DNS when the resource is
+	// deleted: Delete (the default) removes it, Retain leaves it in place
+	// +kubebuilder:validation:Enum=Delete;Retain
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
+}
+
+// AkamaiDnsRecordStatus defines the observed state of an Edge DNS record set
+type AkamaiDnsRecordStatus struct {
+	// Zone is the Edge DNS zone the record set was created in
+	Zone string `json:"zone,omitempty"`
+
+	// Name is the fully qualified name of the record set created in Edge DNS
+	Name string `json:"name,omitempty"`
+
+	// Type is the type of the record set created in Edge DNS
+	Type string `json:"type,omitempty"`
+
+	// Targets are the values of the record set in Edge DNS
+	Targets []string `json:"targets,omitempty"`
+
+	// LastDriftCorrected is when the record set was last restored after it was changed or
+	// deleted outside the operator
+	LastDriftCorrected *metav1.Time `json:"lastDriftCorrected,omitempty"`
+
+	// ObservedGeneration is the metadata.generation the status belongs to
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Phase represents the current phase of the record set
+	Phase string `json:"phase,omitempty"`
+
+	// LastUpdated is the timestamp of the last status update
+	LastUpdated *metav1.Time `json:"lastUpdated,omitempty"`
+
+	// Conditions represent the latest available observations of the record set's state
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:resource:scope=Cluster
+//+kubebuilder:printcolumn:name="Name",type=string,JSONPath=`.status.name`
+//+kubebuilder:printcolumn:name="Type",type=string,JSONPath=`.spec.type`
+//+kubebuilder:printcolumn:name="Targets",type=string,JSONPath=`.status.targets`
+//+kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// AkamaiDnsRecord is the Schema for the akamaidnsrecords API
+type AkamaiDnsRecord struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   AkamaiDnsRecordSpec   `json:"spec,omitempty"`
+	Status AkamaiDnsRecordStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// AkamaiDnsRecordList contains a list of AkamaiDnsRecord
+type AkamaiDnsRecordList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []AkamaiDnsRecord `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&AkamaiDnsRecord{}, &AkamaiDnsRecordList{})
+}
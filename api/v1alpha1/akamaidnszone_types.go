@@ -0,0 +1,107 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// AkamaiDnsZoneSpec defines the desired state of an Edge DNS zone
+// +kubebuilder:validation:XValidation:rule="self.type != 'SECONDARY' || (has(self.masters) && size(self.masters) > 0)",message="masters is required for SECONDARY zones"
+// +kubebuilder:validation:XValidation:rule="self.type != 'PRIMARY' || !has(self.masters) || size(self.masters) == 0",message="masters is only valid for SECONDARY zones"
+// +kubebuilder:validation:XValidation:rule="!has(self.signAndServeAlgorithm) || self.signAndServe",message="signAndServeAlgorithm requires signAndServe"
+type AkamaiDnsZoneSpec struct {
+	// ContractID is the Akamai contract ID the zone is created in
+	ContractID string `json:"contractId"`
+
+	// GroupID is the Akamai group ID the zone is created in. Defaults to the contract's default group.
+	GroupID string `json:"groupId,omitempty"`
+
+	// Zone is the name of the zone, e.g. example.com. Defaults to the name of the resource.
+	Zone string `json:"zone,omitempty"`
+
+	// Type is the type of the zone: PRIMARY zones hold their records in Edge DNS, SECONDARY zones
+	// are transferred from the masters
+	// +kubebuilder:validation:Enum=PRIMARY;SECONDARY
+	// +kubebuilder:default=PRIMARY
+	Type string `json:"type,omitempty"`
+
+	// Masters are the IP addresses of the name servers a SECONDARY zone is transferred from
+	Masters []string `json:"masters,omitempty"`
+
+	// Comment describes the zone in Edge DNS
+	Comment string `json:"comment,omitempty"`
+
+	// SignAndServe serves the zone signed with DNSSEC
+	SignAndServe bool `json:"signAndServe,omitempty"`
+
+	// SignAndServeAlgorithm is the algorithm the zone is signed with. Defaults to the algorithm
+	// Edge DNS chooses.
+	// +kubebuilder:validation:Enum=RSA_SHA1;RSA_SHA256;RSA_SHA512;ECDSA_P256_SHA256;ECDSA_P384_SHA384
+	SignAndServeAlgorithm string `json:"signAndServeAlgorithm,omitempty"`
+
+	// DeletionPolicy controls what happens to the zone in Edge DNS when the resource is deleted:
+	// Delete removes it, Retain (the default) leaves it in place
+	// +kubebuilder:validation:Enum=Delete;Retain
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
+}
+
+// AkamaiDnsZoneStatus defines the observed state of an Edge DNS zone
+type AkamaiDnsZoneStatus struct {
+	// Zone is the name of the zone created or adopted in Edge DNS
+	Zone string `json:"zone,omitempty"`
+
+	// ActivationState is the activation state of the zone in Edge DNS, e.g. ACTIVE or PENDING
+	ActivationState string `json:"activationState,omitempty"`
+
+	// VersionID is the ID of the current version of the zone
+	VersionID string `json:"versionId,omitempty"`
+
+	// LastActivationDate is when the current version of the zone was activated
+	LastActivationDate string `json:"lastActivationDate,omitempty"`
+
+	// DSRecord is the DS record of a zone served with sign and serve, to be published in the
+	// parent zone
+	DSRecord string `json:"dsRecord,omitempty"`
+
+	// ObservedGeneration is the metadata.generation the status belongs to
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Phase represents the current phase of the zone
+	Phase string `json:"phase,omitempty"`
+
+	// LastUpdated is the timestamp of the last status update
+	LastUpdated *metav1.Time `json:"lastUpdated,omitempty"`
+
+	// Conditions represent the latest available observations of the zone's state
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:resource:scope=Cluster
+//+kubebuilder:printcolumn:name="Zone",type=string,JSONPath=`.status.zone`
+//+kubebuilder:printcolumn:name="Type",type=string,JSONPath=`.spec.type`
+//+kubebuilder:printcolumn:name="Activation",type=string,JSONPath=`.status.activationState`
+//+kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// AkamaiDnsZone is the Schema for the akamaidnszones API
+type AkamaiDnsZone struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   AkamaiDnsZoneSpec   `json:"spec,omitempty"`
+	Status AkamaiDnsZoneStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// AkamaiDnsZoneList contains a list of AkamaiDnsZone
+type AkamaiDnsZoneList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []AkamaiDnsZone `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&AkamaiDnsZone{}, &AkamaiDnsZoneList{})
+}
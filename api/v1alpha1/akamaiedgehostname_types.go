@@ -0,0 +1,146 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// AkamaiEdgeHostnameSpec defines the desired state of an edge hostname, the target property
+// hostnames are CNAMEd to
+// +kubebuilder:validation:XValidation:rule="!has(self.secureNetwork) || self.secureNetwork != 'ENHANCED_TLS' || has(self.certEnrollmentId) || has(self.certificateRef)",message="certEnrollmentId or certificateRef is required with secureNetwork ENHANCED_TLS"
+// +kubebuilder:validation:XValidation:rule="!has(self.certEnrollmentId) || !has(self.certificateRef)",message="only one of certEnrollmentId and certificateRef may be set"
+type AkamaiEdgeHostnameSpec struct {
+	// ContractID is the Akamai contract ID. Defaults to the default contract of the provider config.
+	ContractID string `json:"contractId,omitempty"`
+
+	// GroupID is the Akamai group ID. Defaults to the default group of the provider config.
+	GroupID string `json:"groupId,omitempty"`
+
+	// CredentialsRef selects a Secret with the EdgeGrid credentials of the Akamai account the
+	// edge hostname belongs to, which has to be the account of the properties using it. Defaults to
+	// the operator's credentials.
+	CredentialsRef *CredentialsReference `json:"credentialsRef,omitempty"`
+
+	// ProviderConfigRef is the name of the AkamaiProviderConfig with the connection profile of
+	// the Akamai account the edge hostname belongs to
+	ProviderConfigRef string `json:"providerConfigRef,omitempty"`
+
+	// ProductID is the Akamai product ID the edge hostname is created with
+	ProductID string `json:"productId"`
+
+	// DomainPrefix is the prefix of the edge hostname. Defaults to the name of the resource.
+	DomainPrefix string `json:"domainPrefix,omitempty"`
+
+	// DomainSuffix is the suffix of the edge hostname
+	// +kubebuilder:validation:Enum=edgesuite.net;edgekey.net;akamaized.net
+	DomainSuffix string `json:"domainSuffix"`
+
+	// SecureNetwork specifies the secure network type, e.g. ENHANCED_TLS
+	SecureNetwork string `json:"secureNetwork,omitempty"`
+
+	// CertEnrollmentID is the ID of the CPS enrollment whose certificate an ENHANCED_TLS edge
+	// hostname is created with; required with ENHANCED_TLS unless certificateRef is set
+	// +kubebuilder:validation:Minimum=1
+	CertEnrollmentID int `json:"certEnrollmentId,omitempty"`
+
+	// CertificateRef is the name of the AkamaiCertificate whose enrollment an ENHANCED_TLS edge
+	// hostname is created with, instead of certEnrollmentId
+	CertificateRef string `json:"certificateRef,omitempty"`
+
+	// SlotNumber is the slot of the certificate of the CPS enrollment the edge hostname is
+	// created with
+	// +kubebuilder:validation:Minimum=1
+	SlotNumber int `json:"slotNumber,omitempty"`
+
+	// IPVersionBehavior specifies IP version behavior. Defaults to IPV4.
+	IPVersionBehavior string `json:"ipVersionBehavior,omitempty"`
+
+	// UseCases map the edge hostname to the delivery of specific traffic; they are set when the
+	// edge hostname is created
+	UseCases []EdgeHostnameUseCase `json:"useCases,omitempty"`
+
+	// DeletionPolicy controls what happens to the edge hostname in Akamai when the resource is
+	// deleted: Delete removes it, Retain (the default) leaves it in place
+	// +kubebuilder:validation:Enum=Delete;Retain
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
+}
+
+// EdgeHostnameCertificate is the certificate state of a property hostname CNAMEd to an edge hostname
+type EdgeHostnameCertificate struct {
+	// Hostname is the property hostname
+	Hostname string `json:"hostname"`
+
+	// Property is the name of the AkamaiProperty serving the hostname
+	Property string `json:"property"`
+
+	// StagingCertStatus is the Default DV certificate status on staging
+	StagingCertStatus string `json:"stagingCertStatus,omitempty"`
+
+	// ProductionCertStatus is the Default DV certificate status on production
+	ProductionCertStatus string `json:"productionCertStatus,omitempty"`
+}
+
+// AkamaiEdgeHostnameStatus defines the observed state of an edge hostname
+type AkamaiEdgeHostnameStatus struct {
+	// EdgeHostnameID is the Akamai edge hostname ID
+	EdgeHostnameID string `json:"edgeHostnameId,omitempty"`
+
+	// Domain is the full edge hostname, e.g. www.example.com.edgekey.net
+	Domain string `json:"domain,omitempty"`
+
+	// Status is the status of the edge hostname in Akamai
+	Status string `json:"status,omitempty"`
+
+	// CertStatus summarizes the production certificates of the hostnames CNAMEd to the edge
+	// hostname: DEPLOYED once all of them are, otherwise the status of the first one that isn't
+	CertStatus string `json:"certStatus,omitempty"`
+
+	// Certificates lists the certificate state of the hostnames of the AkamaiProperties
+	// referencing the edge hostname
+	Certificates []EdgeHostnameCertificate `json:"certificates,omitempty"`
+
+	// ReferencedBy lists the AkamaiProperties whose hostnames reference the edge hostname
+	ReferencedBy []string `json:"referencedBy,omitempty"`
+
+	// ObservedGeneration is the metadata.generation the status belongs to
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Phase represents the current phase of the edge hostname
+	Phase string `json:"phase,omitempty"`
+
+	// LastUpdated is the timestamp of the last status update
+	LastUpdated *metav1.Time `json:"lastUpdated,omitempty"`
+
+	// Conditions represent the latest available observations of the edge hostname's state
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:resource:scope=Cluster
+//+kubebuilder:printcolumn:name="Domain",type=string,JSONPath=`.status.domain`
+//+kubebuilder:printcolumn:name="Edge Hostname ID",type=string,JSONPath=`.status.edgeHostnameId`
+//+kubebuilder:printcolumn:name="Certificate",type=string,JSONPath=`.status.certStatus`
+//+kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// AkamaiEdgeHostname is the Schema for the akamaiedgehostnames API
+type AkamaiEdgeHostname struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   AkamaiEdgeHostnameSpec   `json:"spec,omitempty"`
+	Status AkamaiEdgeHostnameStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// AkamaiEdgeHostnameList contains a list of AkamaiEdgeHostname
+type AkamaiEdgeHostnameList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []AkamaiEdgeHostname `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&AkamaiEdgeHostname{}, &AkamaiEdgeHostnameList{})
+}
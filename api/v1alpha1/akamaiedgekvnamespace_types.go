@@ -0,0 +1,127 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// AkamaiEdgeKVNamespaceSpec defines the desired state of an EdgeKV namespace
+type AkamaiEdgeKVNamespaceSpec struct {
+	// Name is the name of the namespace. Defaults to the name of the resource. It can't be changed.
+	// +kubebuilder:validation:MaxLength=32
+	// +kubebuilder:validation:Pattern=`^[A-Za-z0-9_-]+$`
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="name is immutable"
+	Name string `json:"name,omitempty"`
+
+	// Networks are the networks the namespace is created on. Each network has its own namespace
+	// and items.
+	// +kubebuilder:validation:items:Enum=staging;production
+	// +kubebuilder:default={staging,production}
+	Networks []string `json:"networks,omitempty"`
+
+	// GeoLocation is where the data of the namespace is stored on production; staging namespaces
+	// are always stored in the US. It can't be changed.
+	// +kubebuilder:validation:Enum=US;EU;JP;GLOBAL
+	// +kubebuilder:default=US
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="geoLocation is immutable"
+	GeoLocation string `json:"geoLocation,omitempty"`
+
+	// RetentionSeconds is how long items are kept after they were last written, between one day
+	// and ten years. 0 (the default) keeps them forever.
+	// +kubebuilder:validation:XValidation:rule="self == 0 || (self >= 86400 && self <= 315360000)",message="retentionSeconds must be 0 or between 86400 and 315360000"
+	RetentionSeconds int `json:"retentionSeconds,omitempty"`
+
+	// GroupID is the Akamai group whose EdgeWorkers may access the namespace, e.g. grp_12345.
+	// All groups may access it when it isn't set.
+	GroupID string `json:"groupId,omitempty"`
+
+	// Seed writes the keys of a ConfigMap or Secret as items of the namespace
+	Seed *EdgeKVSeedSpec `json:"seed,omitempty"`
+}
+
+// EdgeKVSeedSpec defines the items an EdgeKV namespace is seeded with
+// +kubebuilder:validation:XValidation:rule="has(self.configMapRef) != has(self.secretRef)",message="exactly one of configMapRef and secretRef must be set"
+type EdgeKVSeedSpec struct {
+	// Group is the group of the namespace the items are written to
+	// +kubebuilder:validation:MaxLength=128
+	Group string `json:"group"`
+
+	// ConfigMapRef references the ConfigMap whose data are the items
+	ConfigMapRef *ConfigMapReference `json:"configMapRef,omitempty"`
+
+	// SecretRef references the Secret whose data are the items
+	SecretRef *SecretReference `json:"secretRef,omitempty"`
+
+	// Overwrite replaces items whose value differs from the ConfigMap or Secret. By default only
+	// missing items are written, so values changed by EdgeWorkers or other tools are kept.
+	Overwrite bool `json:"overwrite,omitempty"`
+}
+
+// EdgeKVNamespaceNetworkStatus is the state of an EdgeKV namespace on a network
+type EdgeKVNamespaceNetworkStatus struct {
+	// GeoLocation is where the data of the namespace is stored
+	GeoLocation string `json:"geoLocation,omitempty"`
+
+	// RetentionSeconds is how long items are kept; 0 keeps them forever
+	RetentionSeconds int `json:"retentionSeconds,omitempty"`
+
+	// SeededItems is the number of items of the seed that exist in the namespace
+	SeededItems int `json:"seededItems,omitempty"`
+}
+
+// AkamaiEdgeKVNamespaceStatus defines the observed state of an EdgeKV namespace
+type AkamaiEdgeKVNamespaceStatus struct {
+	// Initialization is the status of the EdgeKV database of the account, e.g. INITIALIZED or
+	// PENDING
+	Initialization string `json:"initialization,omitempty"`
+
+	// Staging is the state of the namespace on the staging network
+	Staging *EdgeKVNamespaceNetworkStatus `json:"staging,omitempty"`
+
+	// Production is the state of the namespace on the production network
+	Production *EdgeKVNamespaceNetworkStatus `json:"production,omitempty"`
+
+	// LastChanges are the changes made to the namespace by the last update
+	LastChanges []string `json:"lastChanges,omitempty"`
+
+	// ObservedGeneration is the metadata.generation the status belongs to
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Phase represents the current phase of the namespace
+	Phase string `json:"phase,omitempty"`
+
+	// LastUpdated is the timestamp of the last status update
+	LastUpdated *metav1.Time `json:"lastUpdated,omitempty"`
+
+	// Conditions represent the latest available observations of the namespace's state
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:resource:scope=Cluster
+//+kubebuilder:printcolumn:name="Namespace",type=string,JSONPath=`.spec.name`
+//+kubebuilder:printcolumn:name="Geo",type=string,JSONPath=`.spec.geoLocation`
+//+kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// AkamaiEdgeKVNamespace is the Schema for the akamaiedgekvnamespaces API
+type AkamaiEdgeKVNamespace struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   AkamaiEdgeKVNamespaceSpec   `json:"spec,omitempty"`
+	Status AkamaiEdgeKVNamespaceStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// AkamaiEdgeKVNamespaceList contains a list of AkamaiEdgeKVNamespace
+type AkamaiEdgeKVNamespaceList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []AkamaiEdgeKVNamespace `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&AkamaiEdgeKVNamespace{}, &AkamaiEdgeKVNamespaceList{})
+}
@@ -0,0 +1,55 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// AkamaiGroupSpec mirrors an Akamai group available to the operator's API client
+type AkamaiGroupSpec struct {
+	// GroupID is the Akamai group ID
+	GroupID string `json:"groupId"`
+
+	// GroupName is the name of the group
+	GroupName string `json:"groupName,omitempty"`
+
+	// ParentGroupID is the ID of the parent group, if any
+	ParentGroupID string `json:"parentGroupId,omitempty"`
+
+	// ContractIDs are the contracts the group can use
+	ContractIDs []string `json:"contractIds,omitempty"`
+}
+
+// AkamaiGroupStatus defines the observed state of AkamaiGroup
+type AkamaiGroupStatus struct {
+	// LastSynced is the timestamp when the group was last refreshed from Akamai
+	LastSynced *metav1.Time `json:"lastSynced,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:resource:scope=Cluster
+//+kubebuilder:printcolumn:name="Group ID",type=string,JSONPath=`.spec.groupId`
+//+kubebuilder:printcolumn:name="Group Name",type=string,JSONPath=`.spec.groupName`
+//+kubebuilder:printcolumn:name="Last Synced",type=date,JSONPath=`.status.lastSynced`
+
+// AkamaiGroup is a read-only mirror of an Akamai group maintained by the operator
+type AkamaiGroup struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   AkamaiGroupSpec   `json:"spec,omitempty"`
+	Status AkamaiGroupStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// AkamaiGroupList contains a list of AkamaiGroup
+type AkamaiGroupList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []AkamaiGroup `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&AkamaiGroup{}, &AkamaiGroupList{})
+}
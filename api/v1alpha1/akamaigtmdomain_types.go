@@ -0,0 +1,182 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// AkamaiGtmDomainSpec defines the desired state of a Global Traffic Management domain
+// +kubebuilder:validation:XValidation:rule="!has(self.properties) || self.properties.all(p, p.trafficTargets.all(t, has(self.datacenters) && self.datacenters.exists(d, d.nickname == t.datacenter)))",message="trafficTargets must reference datacenters of the spec"
+type AkamaiGtmDomainSpec struct {
+	// Name is the name of the domain, e.g. example.akadns.net. Defaults to the name of the
+	// resource. It can't be changed.
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="name is immutable"
+	Name string `json:"name,omitempty"`
+
+	// Type is the type of the domain, which limits the load balancing the properties can use
+	// +kubebuilder:validation:Enum=failover-only;static;weighted;basic;full
+	// +kubebuilder:default=basic
+	Type string `json:"type,omitempty"`
+
+	// ContractID is the Akamai contract ID the domain is created in
+	ContractID string `json:"contractId"`
+
+	// GroupID is the Akamai group ID the domain is created in, e.g. grp_12345
+	GroupID string `json:"groupId,omitempty"`
+
+	// NotificationEmails are notified about changes of the domain
+	NotificationEmails []string `json:"notificationEmails,omitempty"`
+
+	// Datacenters are the datacenters the traffic targets of the properties point to, by nickname.
+	// Datacenters not listed are left alone.
+	Datacenters []GtmDatacenterSpec `json:"datacenters,omitempty"`
+
+	// Properties are the hostnames of the domain the traffic is steered for. Properties not
+	// listed are left alone.
+	Properties []GtmPropertySpec `json:"properties,omitempty"`
+
+	// DeletionPolicy controls what happens to the domain in Akamai when the resource is deleted:
+	// Delete removes it, Retain (the default) leaves it in place
+	// +kubebuilder:validation:Enum=Delete;Retain
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
+}
+
+// GtmDatacenterSpec defines a datacenter of a GTM domain
+type GtmDatacenterSpec struct {
+	// Nickname identifies the datacenter within the domain
+	Nickname string `json:"nickname"`
+
+	// City is the city the datacenter is located in
+	City string `json:"city,omitempty"`
+
+	// Country is the two-letter code of the country the datacenter is located in
+	Country string `json:"country,omitempty"`
+
+	// Continent is the two-letter code of the continent the datacenter is located in, e.g. EU
+	Continent string `json:"continent,omitempty"`
+}
+
+// GtmPropertySpec defines a property of a GTM domain
+type GtmPropertySpec struct {
+	// Name is the name of the property; it is served as <name>.<domain>
+	Name string `json:"name"`
+
+	// Type is how the traffic is distributed: failover sends it to the first enabled traffic
+	// target that is up, weighted-round-robin and weighted-hashed by the weights of the targets
+	// +kubebuilder:validation:Enum=failover;weighted-round-robin;weighted-hashed
+	Type string `json:"type"`
+
+	// IPv6 serves AAAA instead of A records for the servers of the traffic targets
+	IPv6 bool `json:"ipv6,omitempty"`
+
+	// DynamicTTL is the TTL of the answers in seconds. Defaults to 60.
+	// +kubebuilder:validation:Minimum=30
+	DynamicTTL int `json:"dynamicTTL,omitempty"`
+
+	// TrafficTargets are where the traffic goes, in order of preference for failover properties
+	// +kubebuilder:validation:MinItems=1
+	TrafficTargets []GtmTrafficTargetSpec `json:"trafficTargets"`
+
+	// LivenessTest checks the servers of the traffic targets; unhealthy targets get no traffic
+	LivenessTest *GtmLivenessTestSpec `json:"livenessTest,omitempty"`
+}
+
+// GtmTrafficTargetSpec defines a traffic target of a GTM property
+// +kubebuilder:validation:XValidation:rule="has(self.servers) != has(self.handoutCName)",message="exactly one of servers and handoutCName must be set"
+type GtmTrafficTargetSpec struct {
+	// Datacenter is the nickname of the datacenter of the target
+	Datacenter string `json:"datacenter"`
+
+	// Disabled stops sending traffic to the target
+	Disabled bool `json:"disabled,omitempty"`
+
+	// Weight is the share of the traffic of weighted properties. Failover properties ignore it.
+	// +kubebuilder:validation:Minimum=0
+	Weight int `json:"weight,omitempty"`
+
+	// Servers are the IP addresses or hostnames of the servers of the target
+	Servers []string `json:"servers,omitempty"`
+
+	// HandoutCName is handed out as CNAME instead of the servers, e.g. the edge hostname of a
+	// property
+	HandoutCName string `json:"handoutCName,omitempty"`
+}
+
+// GtmLivenessTestSpec defines the liveness test of a GTM property
+// +kubebuilder:validation:XValidation:rule="self.protocol != 'TCP' || has(self.port)",message="TCP tests need a port"
+type GtmLivenessTestSpec struct {
+	// Protocol is the protocol of the test
+	// +kubebuilder:validation:Enum=HTTP;HTTPS;TCP
+	Protocol string `json:"protocol"`
+
+	// Path is the path requested by HTTP and HTTPS tests, e.g. /healthz
+	Path string `json:"path,omitempty"`
+
+	// Port is the port tested. Defaults to the port of the protocol.
+	Port int `json:"port,omitempty"`
+
+	// IntervalSeconds is how often the servers are tested. Defaults to 60.
+	// +kubebuilder:validation:Minimum=10
+	IntervalSeconds int `json:"intervalSeconds,omitempty"`
+
+	// TimeoutSeconds is how long a test may take. Defaults to 10.
+	// +kubebuilder:validation:Minimum=1
+	TimeoutSeconds int `json:"timeoutSeconds,omitempty"`
+}
+
+// AkamaiGtmDomainStatus defines the observed state of a Global Traffic Management domain
+type AkamaiGtmDomainStatus struct {
+	// Datacenters are the IDs of the datacenters of the spec by nickname
+	Datacenters map[string]int `json:"datacenters,omitempty"`
+
+	// PropagationStatus is the propagation status of the last change: PENDING, COMPLETE or DENIED
+	PropagationStatus string `json:"propagationStatus,omitempty"`
+
+	// ChangeID is the ID of the last change of the domain
+	ChangeID string `json:"changeId,omitempty"`
+
+	// LastChanges are the changes made to the domain by the last update
+	LastChanges []string `json:"lastChanges,omitempty"`
+
+	// ObservedGeneration is the metadata.generation the status belongs to
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Phase represents the current phase of the domain
+	Phase string `json:"phase,omitempty"`
+
+	// LastUpdated is the timestamp of the last status update
+	LastUpdated *metav1.Time `json:"lastUpdated,omitempty"`
+
+	// Conditions represent the latest available observations of the domain's state
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:resource:scope=Cluster
+//+kubebuilder:printcolumn:name="Domain",type=string,JSONPath=`.spec.name`
+//+kubebuilder:printcolumn:name="Type",type=string,JSONPath=`.spec.type`
+//+kubebuilder:printcolumn:name="Propagation",type=string,JSONPath=`.status.propagationStatus`
+//+kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// AkamaiGtmDomain is the Schema for the akamaigtmdomains API
+type AkamaiGtmDomain struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   AkamaiGtmDomainSpec   `json:"spec,omitempty"`
+	Status AkamaiGtmDomainStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// AkamaiGtmDomainList contains a list of AkamaiGtmDomain
+type AkamaiGtmDomainList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []AkamaiGtmDomain `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&AkamaiGtmDomain{}, &AkamaiGtmDomainList{})
+}
@@ -0,0 +1,128 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// AkamaiNetworkListSpec defines the desired state of an Akamai network list
+type AkamaiNetworkListSpec struct {
+	// Name is the name of the network list in Akamai. Defaults to the name of the resource.
+	Name string `json:"name,omitempty"`
+
+	// Type is the type of the elements: IP addresses and CIDR blocks, or GEO country codes. It
+	// can't be changed once the list exists.
+	// +kubebuilder:validation:Enum=IP;GEO
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="type is immutable"
+	Type string `json:"type"`
+
+	// Description describes the network list in Akamai
+	Description string `json:"description,omitempty"`
+
+	// ContractID is the Akamai contract ID the network list is created in
+	ContractID string `json:"contractId,omitempty"`
+
+	// GroupID is the Akamai group ID the network list is created in, e.g. grp_12345
+	GroupID string `json:"groupId,omitempty"`
+
+	// Elements are the IP addresses and CIDR blocks, or the two-letter country codes, of the list
+	Elements []string `json:"elements,omitempty"`
+
+	// Activation activates the network list on the Akamai networks
+	Activation *NetworkListActivationSpec `json:"activation,omitempty"`
+
+	// DeletionPolicy controls what happens to the network list in Akamai when the resource is
+	// deleted: Delete removes it, Retain (the default) leaves it in place
+	// +kubebuilder:validation:Enum=Delete;Retain
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
+}
+
+// NetworkListActivationSpec defines on which networks the network list is activated
+type NetworkListActivationSpec struct {
+	// Staging activates each change of the list on the staging network
+	Staging bool `json:"staging,omitempty"`
+
+	// Production activates each change of the list on the production network
+	Production bool `json:"production,omitempty"`
+
+	// Comments are the comments of the activations
+	Comments string `json:"comments,omitempty"`
+
+	// NotificationEmails are notified about the activations
+	NotificationEmails []string `json:"notificationEmails,omitempty"`
+}
+
+// NetworkListActivationStatus is the activation state of a network list on a network
+type NetworkListActivationStatus struct {
+	// Status is the activation status, e.g. ACTIVE, PENDING_ACTIVATION or INACTIVE
+	Status string `json:"status,omitempty"`
+
+	// SyncPoint is the version of the list the status belongs to
+	SyncPoint int `json:"syncPoint,omitempty"`
+
+	// ActivationID is the ID of the last activation
+	ActivationID int `json:"activationId,omitempty"`
+}
+
+// AkamaiNetworkListStatus defines the observed state of an Akamai network list
+type AkamaiNetworkListStatus struct {
+	// UniqueID is the ID of the network list, used to reference it from security configurations
+	// and match criteria
+	UniqueID string `json:"uniqueId,omitempty"`
+
+	// SyncPoint is the current version of the network list
+	SyncPoint int `json:"syncPoint,omitempty"`
+
+	// ElementCount is the number of elements of the network list
+	ElementCount int `json:"elementCount,omitempty"`
+
+	// Staging is the activation state of the network list on the staging network
+	Staging *NetworkListActivationStatus `json:"staging,omitempty"`
+
+	// Production is the activation state of the network list on the production network
+	Production *NetworkListActivationStatus `json:"production,omitempty"`
+
+	// ObservedGeneration is the metadata.generation the status belongs to
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Phase represents the current phase of the network list
+	Phase string `json:"phase,omitempty"`
+
+	// LastUpdated is the timestamp of the last status update
+	LastUpdated *metav1.Time `json:"lastUpdated,omitempty"`
+
+	// Conditions represent the latest available observations of the network list's state
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:resource:scope=Cluster
+//+kubebuilder:printcolumn:name="ID",type=string,JSONPath=`.status.uniqueId`
+//+kubebuilder:printcolumn:name="Type",type=string,JSONPath=`.spec.type`
+//+kubebuilder:printcolumn:name="Elements",type=integer,JSONPath=`.status.elementCount`
+//+kubebuilder:printcolumn:name="Staging",type=string,JSONPath=`.status.staging.status`
+//+kubebuilder:printcolumn:name="Production",type=string,JSONPath=`.status.production.status`
+//+kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// AkamaiNetworkList is the Schema for the akamainetworklists API
+type AkamaiNetworkList struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   AkamaiNetworkListSpec   `json:"spec,omitempty"`
+	Status AkamaiNetworkListStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// AkamaiNetworkListList contains a list of AkamaiNetworkList
+type AkamaiNetworkListList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []AkamaiNetworkList `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&AkamaiNetworkList{}, &AkamaiNetworkListList{})
+}
@@ -9,6 +9,8 @@ import (
 // NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
 
 // AkamaiPropertySpec defines the desired state of AkamaiProperty
+// +kubebuilder:validation:XValidation:rule="!(has(self.credentialsRef) && has(self.providerConfigRef))",message="credentialsRef and providerConfigRef are mutually exclusive"
+// +kubebuilder:validation:XValidation:rule="!(has(self.expiresAt) && has(self.ttl))",message="expiresAt and ttl are mutually exclusive"
 type AkamaiPropertySpec struct {
 	// INSERT ADDITIONAL SPEC FIELDS - desired state of cluster
 	// Important: Run "make" to regenerate code after modifying this file
@@ -16,38 +18,405 @@ type AkamaiPropertySpec struct {
 	// PropertyName is the name of the Akamai property
 	PropertyName string `json:"propertyName"`
 
-	// GroupID is the Akamai group ID where the property should be created
-	GroupID string `json:"groupId"`
+	// GroupID is the Akamai group ID where the property should be created.
+	// Defaults to the default group of the provider config of providerConfigRef; required
+	// without one, also with credentialsRef.
+	GroupID string `json:"groupId,omitempty"`
 
-	// ContractID is the Akamai contract ID
-	ContractID string `json:"contractId"`
+	// ContractID is the Akamai contract ID. Defaults to the default contract of the provider
+	// config of providerConfigRef; required without one, also with credentialsRef.
+	ContractID string `json:"contractId,omitempty"`
 
-	// ProductID is the Akamai product ID (e.g., "prd_Fresca")
+	// ProductID is the Akamai product ID (e.g., "prd_Fresca"). Always required, there is no
+	// default.
 	ProductID string `json:"productId"`
 
+	// RuleFormat is the rule format the property is created with (e.g. "v2024-02-12").
+	// Defaults to "v2023-01-05".
+	RuleFormat string `json:"ruleFormat,omitempty"`
+
+	// Description is a human readable description written into the property version notes,
+	// starting with the initial version created by the operator
+	Description string `json:"description,omitempty"`
+
+	// Metadata are custom fields (e.g. owner team, repository URL) written into the
+	// property version notes as "key: value" lines
+	Metadata map[string]string `json:"metadata,omitempty"`
+
 	// Hostnames are the hostnames that this property should handle
 	Hostnames []Hostname `json:"hostnames,omitempty"`
 
 	// Rules contains the property rules configuration
 	Rules *PropertyRules `json:"rules,omitempty"`
 
+	// Renderer selects how the final rule tree is produced. Defaults to the inline spec.rules.
+	Renderer *RendererSpec `json:"renderer,omitempty"`
+
+	// RulesFrom loads the rule tree, or the files of the renderer, from an HTTPS URL or an OCI
+	// artifact instead of spec.rules or spec.renderer.configMapRef
+	RulesFrom *RulesSource `json:"rulesFrom,omitempty"`
+
+	// RulesPatches are applied in order on top of the rule tree of spec.rulesFrom or the
+	// renderer, e.g. to override the origin or cpCode of a centrally managed base template
+	RulesPatches []RulesPatch `json:"rulesPatches,omitempty"`
+
 	// EdgeHostname specifies the edge hostname configuration
 	EdgeHostname *EdgeHostnameSpec `json:"edgeHostname,omitempty"`
 
 	// Activation specifies the activation configuration for the property
 	Activation *ActivationSpec `json:"activation,omitempty"`
+
+	// Variables are rule tree variables merged into the top-level rule at render time.
+	// A variable declared here replaces a variable with the same name in rules.variables.
+	Variables []PropertyVariable `json:"variables,omitempty"`
+
+	// SyncLabels lists metadata label keys whose values are written into the property version notes
+	SyncLabels []string `json:"syncLabels,omitempty"`
+
+	// EdgeEndpoints optionally publishes the hostname to edge hostname mapping into a ConfigMap
+	EdgeEndpoints *EdgeEndpointsSpec `json:"edgeEndpoints,omitempty"`
+
+	// DNS optionally points the hostnames at their edge hostnames in DNS
+	DNS *PropertyDNSSpec `json:"dns,omitempty"`
+
+	// VersionStrategy controls which property version changes are written to.
+	// Defaults to ReuseUnpublished.
+	VersionStrategy VersionStrategy `json:"versionStrategy,omitempty"`
+
+	// ForeignVersionPolicy controls what happens when the latest version was created outside the
+	// operator (e.g. by Terraform or a console user). Defaults to Overwrite.
+	ForeignVersionPolicy ForeignVersionPolicy `json:"foreignVersionPolicy,omitempty"`
+
+	// DriftPolicy controls what happens when the rules or hostnames in Akamai were changed
+	// outside the operator, e.g. in Property Manager, after the spec was applied. Defaults to
+	// Correct.
+	DriftPolicy DriftPolicy `json:"driftPolicy,omitempty"`
+
+	// RulesMergeStrategy controls how the rule tree is applied to the rules in Akamai.
+	// Defaults to Replace.
+	RulesMergeStrategy RulesMergeStrategy `json:"rulesMergeStrategy,omitempty"`
+
+	// CredentialsRef selects a Secret with the EdgeGrid credentials of the Akamai account the
+	// property belongs to. Defaults to the operator's credentials.
+	CredentialsRef *CredentialsReference `json:"credentialsRef,omitempty"`
+
+	// ProviderConfigRef is the name of the AkamaiProviderConfig with the connection profile of
+	// the Akamai account the property belongs to
+	ProviderConfigRef string `json:"providerConfigRef,omitempty"`
+
+	// ProgressDeadlineSeconds is the number of seconds a generation may take to become Ready
+	// before the property is reported Stalled with reason ProgressDeadlineExceeded, like the
+	// progress deadline of a Deployment. Not set by default.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	ProgressDeadlineSeconds *int32 `json:"progressDeadlineSeconds,omitempty"`
+
+	// Preview configures the short-lived preview property of a resource labeled
+	// akamai.com/preview=true, e.g. one created for a pull request
+	Preview *PreviewSpec `json:"preview,omitempty"`
+
+	// PreserveBehaviors lists behavior names (e.g. "siteShield") the operator never removes when
+	// it enforces spec.rules. Such behaviors added to the rule tree outside the operator, e.g. by
+	// Akamai managed services, are kept and reported in status.preservedBehaviors.
+	PreserveBehaviors []string `json:"preserveBehaviors,omitempty"`
+
+	// DeletionPolicy controls what happens to the Akamai property when the resource is deleted.
+	// Defaults to Delete.
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
+
+	// ExpiresAt is when a temporary property, e.g. of a campaign site, expires. The operator then
+	// deletes the resource, which deactivates and deletes the Akamai property according to
+	// spec.deletionPolicy.
+	// +optional
+	ExpiresAt *metav1.Time `json:"expiresAt,omitempty"`
+
+	// Origins renders the origin selection of the property from a list of weighted origins with
+	// an optional failover origin, instead of hand-written conditional origin rules
+	Origins *OriginsSpec `json:"origins,omitempty"`
+
+	// TTL is how long after the creation of the resource the property expires, e.g. "720h".
+	// Alternative to spec.expiresAt.
+	// +optional
+	TTL *metav1.Duration `json:"ttl,omitempty"`
+
+	// Suspend stops all Akamai API calls for the property, e.g. during an incident freeze, while
+	// its status stays visible. Same as the akamai.com/suspend or akamai.com/paused annotation.
+	// +optional
+	Suspend bool `json:"suspend,omitempty"`
+
+	// DryRun compares the spec with the live property and lists the changes the operator would
+	// make in status.pendingChanges, without changing anything in Akamai
+	// +optional
+	DryRun bool `json:"dryRun,omitempty"`
+
+	// Manage selects the parts of the property the operator manages, so it can be adopted
+	// incrementally while other tooling keeps the rest. All parts are managed by default.
+	// +optional
+	Manage *ManageSpec `json:"manage,omitempty"`
+
+	// Origin sets the origin behavior of the top-level rule, so simple properties need no
+	// hand-written rule tree. Mutually exclusive with spec.origins.
+	// +optional
+	Origin *OriginServer `json:"origin,omitempty"`
+
+	// Caching sets the caching behavior of the top-level rule
+	// +optional
+	Caching *CachingSpec `json:"caching,omitempty"`
+
+	// CPCode sets the cpCode behavior of the top-level rule
+	// +optional
+	CPCode *CPCodeSpec `json:"cpCode,omitempty"`
+
+	// Redirect adds a managed child rule redirecting plain HTTP requests to HTTPS
+	// +optional
+	Redirect *RedirectSpec `json:"redirect,omitempty"`
 }
 
+// CachingSpec is the caching behavior of the top-level rule
+type CachingSpec struct {
+	// Behavior is how objects are cached: MAX_AGE caches them for spec.caching.ttl, NO_STORE and
+	// BYPASS_CACHE don't cache them
+	// +kubebuilder:validation:Enum=MAX_AGE;NO_STORE;BYPASS_CACHE
+	Behavior string `json:"behavior"`
+
+	// TTL is how long objects are cached with MAX_AGE, e.g. "1d" or "30m"
+	// +kubebuilder:validation:Pattern=`^[0-9]+[smhd]$`
+	// +optional
+	TTL string `json:"ttl,omitempty"`
+
+	// MustRevalidate serves stale objects only after revalidating them with the origin
+	// +optional
+	MustRevalidate bool `json:"mustRevalidate,omitempty"`
+}
+
+// CPCodeSpec is the CP code the traffic of the property is reported and billed under
+type CPCodeSpec struct {
+	// ID is the numeric CP code, without the cpc_ prefix
+	// +kubebuilder:validation:Minimum=1
+	ID int64 `json:"id"`
+}
+
+// RedirectSpec redirects plain HTTP requests to HTTPS
+type RedirectSpec struct {
+	// HTTPToHTTPS redirects requests made over HTTP to the same URL over HTTPS
+	HTTPToHTTPS bool `json:"httpToHttps"`
+
+	// ResponseCode is the status code of the redirect. Defaults to 301.
+	// +kubebuilder:validation:Enum=301;302;303;307
+	// +optional
+	ResponseCode int32 `json:"responseCode,omitempty"`
+}
+
+// VersionStrategy controls how the operator picks the property version it writes changes to
+// +kubebuilder:validation:Enum=AlwaysNew;ReuseUnpublished;Manual
+type VersionStrategy string
+
+const (
+	// VersionStrategyAlwaysNew creates a fresh version for every spec generation
+	VersionStrategyAlwaysNew VersionStrategy = "AlwaysNew"
+
+	// VersionStrategyReuseUnpublished edits the latest version while it is unpublished and
+	// only creates a new version once the latest one has been activated
+	VersionStrategyReuseUnpublished VersionStrategy = "ReuseUnpublished"
+
+	// VersionStrategyManual never creates versions; changes wait until an editable
+	// version has been created outside the operator
+	VersionStrategyManual VersionStrategy = "Manual"
+)
+
+// RulesMergeStrategy controls how the operator applies the rule tree to the rules in Akamai
+// +kubebuilder:validation:Enum=Replace;ThreeWay
+type RulesMergeStrategy string
+
+const (
+	// RulesMergeStrategyReplace replaces the rules in Akamai with the rule tree
+	RulesMergeStrategyReplace RulesMergeStrategy = "Replace"
+
+	// RulesMergeStrategyThreeWay merges the rule tree into the rules in Akamai like kubectl
+	// apply: parts removed from the rule tree since it was last applied are removed, parts added
+	// outside the operator are kept
+	RulesMergeStrategyThreeWay RulesMergeStrategy = "ThreeWay"
+)
+
+// AppliedRules is a snapshot of the rule tree last applied to Akamai
+type AppliedRules struct {
+	// Digest is the sha256 digest of the rule tree
+	Digest string `json:"digest"`
+
+	// Snapshot is the gzip compressed, base64 encoded JSON of the rule tree. Values of sensitive
+	// variables and values resolved from Secrets are masked.
+	Snapshot string `json:"snapshot"`
+}
+
+// DeletionPolicy controls what the operator does with the Akamai property of a deleted resource
+// +kubebuilder:validation:Enum=Delete;Retain;Deactivate
+type DeletionPolicy string
+
+const (
+	// DeletionPolicyDelete removes the property from Akamai
+	DeletionPolicyDelete DeletionPolicy = "Delete"
+
+	// DeletionPolicyRetain leaves the property and its activations untouched in Akamai
+	DeletionPolicyRetain DeletionPolicy = "Retain"
+
+	// DeletionPolicyDeactivate deactivates the property on staging and production and keeps it
+	DeletionPolicyDeactivate DeletionPolicy = "Deactivate"
+)
+
+// DriftPolicy controls how the operator treats changes made in Akamai outside the operator
+// +kubebuilder:validation:Enum=Correct;Warn;Ignore
+type DriftPolicy string
+
+const (
+	// DriftPolicyCorrect overwrites out-of-band changes with the spec
+	DriftPolicyCorrect DriftPolicy = "Correct"
+
+	// DriftPolicyWarn keeps out-of-band changes and reports them in the DriftDetected condition
+	// and an event
+	DriftPolicyWarn DriftPolicy = "Warn"
+
+	// DriftPolicyIgnore keeps out-of-band changes without reporting them
+	DriftPolicyIgnore DriftPolicy = "Ignore"
+)
+
+// ForeignVersionPolicy controls how the operator treats an unpublished latest version it did not create
+// +kubebuilder:validation:Enum=Overwrite;Refuse;CreateVersion
+type ForeignVersionPolicy string
+
+const (
+	// ForeignVersionPolicyOverwrite writes changes to the latest version regardless of who created it
+	ForeignVersionPolicyOverwrite ForeignVersionPolicy = "Overwrite"
+
+	// ForeignVersionPolicyRefuse leaves versions created outside the operator untouched and waits
+	// until they are activated or a newer version exists
+	ForeignVersionPolicyRefuse ForeignVersionPolicy = "Refuse"
+
+	// ForeignVersionPolicyCreateVersion writes changes to a new version instead of one created
+	// outside the operator
+	ForeignVersionPolicyCreateVersion ForeignVersionPolicy = "CreateVersion"
+)
+
 // Hostname represents a hostname configuration for the property
 type Hostname struct {
 	// CNAMEFrom is the hostname that will be CNAMEd
 	CNAMEFrom string `json:"cnameFrom"`
 
-	// CNAMETo is the edge hostname target
-	CNAMETo string `json:"cnameTo"`
+	// CNAMETo is the edge hostname target. Required unless edgeHostnameRef is set.
+	CNAMETo string `json:"cnameTo,omitempty"`
+
+	// EdgeHostnameRef is the name of an AkamaiEdgeHostname whose domain is the edge hostname
+	// target, instead of cnameTo
+	EdgeHostnameRef string `json:"edgeHostnameRef,omitempty"`
 
 	// CertProvisioningType specifies how SSL certificates are provisioned
 	CertProvisioningType string `json:"certProvisioningType,omitempty"`
+
+	// ICPLicense is the ICP filing or license number of the hostname, e.g. "京ICP备12345678号-1".
+	// Required for hostnames CNAMEd to a China CDN edge hostname; it is not sent to Akamai.
+	ICPLicense string `json:"icpLicense,omitempty"`
+}
+
+// RendererType selects the renderer producing the rule tree of a property
+// +kubebuilder:validation:Enum=Inline;Template;Pipeline;External
+type RendererType string
+
+const (
+	// RendererTypeInline applies spec.rules as is
+	RendererTypeInline RendererType = "Inline"
+
+	// RendererTypeTemplate renders the entry file of configMapRef as a Go template
+	RendererTypeTemplate RendererType = "Template"
+
+	// RendererTypePipeline renders the entry file of configMapRef in the Akamai CLI pipeline
+	// format, resolving "#include:<file>" snippets and "${env.<name>}" variables
+	RendererTypePipeline RendererType = "Pipeline"
+
+	// RendererTypeExternal calls an external renderer registered with --external-renderers
+	RendererTypeExternal RendererType = "External"
+)
+
+// RendererSpec configures the renderer of the property rule tree
+type RendererSpec struct {
+	// Type selects the renderer. Defaults to Inline.
+	Type RendererType `json:"type,omitempty"`
+
+	// ConfigMapRef references the ConfigMap holding the files of the Template and Pipeline
+	// renderers; its entries are passed to external renderers as files
+	ConfigMapRef *ConfigMapReference `json:"configMapRef,omitempty"`
+
+	// Entry is the file rendered first. Defaults to "main.json".
+	Entry string `json:"entry,omitempty"`
+
+	// Values are passed to the renderer: .Values in templates, ${env.<name>} in pipeline files
+	Values map[string]string `json:"values,omitempty"`
+
+	// External is the name of the external renderer, required with type External
+	External string `json:"external,omitempty"`
+}
+
+// RulesSource is a source of the rule tree outside the cluster. Exactly one of URL and OCIRef
+// must be set.
+type RulesSource struct {
+	// URL is an HTTPS URL of a rule tree, or of the entry file of the renderer
+	// +kubebuilder:validation:Pattern=`^https://`
+	URL string `json:"url,omitempty"`
+
+	// OCIRef references an OCI artifact as registry/repository:tag or registry/repository@digest,
+	// e.g. pushed with `oras push`. Its layers titled with org.opencontainers.image.title are
+	// the files of the renderer.
+	OCIRef string `json:"ociRef,omitempty"`
+
+	// Checksum pins the content to a digest: of the file at URL, or of the artifact manifest.
+	// Content with a different digest is rejected.
+	// +kubebuilder:validation:Pattern=`^sha256:[a-f0-9]{64}$`
+	Checksum string `json:"checksum,omitempty"`
+
+	// SecretRef references a Secret with the "username" and "password" keys authenticating to the
+	// web server or registry
+	SecretRef *SecretReference `json:"secretRef,omitempty"`
+}
+
+// RulesPatchType defines how a rules patch is applied
+// +kubebuilder:validation:Enum=JSONPatch;Merge
+type RulesPatchType string
+
+const (
+	// RulesPatchTypeJSONPatch applies a list of RFC 6902 operations to the rule tree
+	RulesPatchTypeJSONPatch RulesPatchType = "JSONPatch"
+
+	// RulesPatchTypeMerge merges a partial rule tree into the rule tree, matching child rules,
+	// behaviors, criteria and variables by name
+	RulesPatchTypeMerge RulesPatchType = "Merge"
+)
+
+// RulesPatch is a patch applied to a base rule tree
+type RulesPatch struct {
+	// Type selects how the patch is applied. Defaults to JSONPatch.
+	// +optional
+	Type RulesPatchType `json:"type,omitempty"`
+
+	// Patch is the list of RFC 6902 operations of a JSONPatch, or the partial rule tree of a
+	// Merge. An item with "$patch: delete" in a Merge removes the item of the same name.
+	// +kubebuilder:pruning:PreserveUnknownFields
+	Patch runtime.RawExtension `json:"patch"`
+}
+
+// SecretReference references a Secret
+type SecretReference struct {
+	// Namespace is the namespace of the Secret
+	Namespace string `json:"namespace"`
+
+	// Name is the name of the Secret
+	Name string `json:"name"`
+}
+
+// ConfigMapReference references a ConfigMap
+type ConfigMapReference struct {
+	// Namespace is the namespace of the ConfigMap
+	Namespace string `json:"namespace"`
+
+	// Name is the name of the ConfigMap
+	Name string `json:"name"`
 }
 
 // PropertyRules contains the rules configuration for the property
@@ -63,13 +432,16 @@ type PropertyRules struct {
 	// Criteria defines the match criteria for the rule
 	Criteria []RuleCriteria `json:"criteria,omitempty"`
 
-	// CriteriaMustSatisfy defines how criteria are evaluated (all, any)
-	CriteriaMustSatisfy string `json:"criteriaMustSatisfy,omitempty"`
+	// CriteriaMustSatisfy defines whether all or any of the criteria must match. Defaults to all.
+	// +optional
+	CriteriaMustSatisfy CriteriaMustSatisfy `json:"criteriaMustSatisfy,omitempty"`
 
 	// Behaviors defines the behaviors to apply when criteria match
 	Behaviors []RuleBehavior `json:"behaviors,omitempty"`
 
-	// Children contains nested rules as raw JSON to avoid recursive type issues
+	// Children contains nested rules as raw JSON to avoid recursive type issues. Child rules are
+	// evaluated in order, later rules overriding earlier ones, so the operator applies them exactly
+	// in the order listed.
 	// +kubebuilder:pruning:PreserveUnknownFields
 	Children []runtime.RawExtension `json:"children,omitempty"`
 
@@ -91,6 +463,18 @@ type PropertyRules struct {
 	CustomOverride runtime.RawExtension `json:"customOverride,omitempty"`
 }
 
+// CriteriaMustSatisfy defines how the criteria of a rule are combined
+// +kubebuilder:validation:Enum=all;any
+type CriteriaMustSatisfy string
+
+const (
+	// CriteriaMustSatisfyAll matches when all criteria match
+	CriteriaMustSatisfyAll CriteriaMustSatisfy = "all"
+
+	// CriteriaMustSatisfyAny matches when any criterion matches
+	CriteriaMustSatisfyAny CriteriaMustSatisfy = "any"
+)
+
 // RuleCriteria defines a criterion for rule matching
 type RuleCriteria struct {
 	// Name is the criterion type (e.g., "hostname", "path", "requestMethod")
@@ -142,10 +526,153 @@ type RuleVariable struct {
 	Sensitive bool `json:"sensitive,omitempty"`
 }
 
+// PropertyVariable declares a rule tree variable managed as a first-class spec field
+type PropertyVariable struct {
+	// Name is the unique name of the variable (e.g., "PMUSER_ORIGIN")
+	Name string `json:"name"`
+
+	// Value initializes a default value (omitting initializes with empty string)
+	Value string `json:"value,omitempty"`
+
+	// ValueFrom sources the value from a Secret at render time; takes precedence over Value
+	ValueFrom *VariableValueSource `json:"valueFrom,omitempty"`
+
+	// Description is text to track how the variable is used
+	Description string `json:"description,omitempty"`
+
+	// Hidden suppresses the variable from session response headers
+	Hidden bool `json:"hidden,omitempty"`
+
+	// Sensitive suppresses the variable from session responses and masks its value in operator logs
+	Sensitive bool `json:"sensitive,omitempty"`
+}
+
+// VariableValueSource describes where a variable value is read from
+type VariableValueSource struct {
+	// SecretKeyRef selects a key of a Secret
+	SecretKeyRef *SecretKeyReference `json:"secretKeyRef,omitempty"`
+}
+
+// SecretKeyReference selects a key of a Secret in a given namespace
+type SecretKeyReference struct {
+	// Namespace is the namespace of the Secret
+	Namespace string `json:"namespace"`
+
+	// Name is the name of the Secret
+	Name string `json:"name"`
+
+	// Key is the key within the Secret data
+	Key string `json:"key"`
+}
+
+// PreviewSpec configures a preview property. The preview is cloned from the version of the base
+// property active on production, serves the base hostnames renamed with HostnameTemplate and is
+// only ever activated on staging.
+type PreviewSpec struct {
+	// BaseRef is the name of the AkamaiProperty the preview is cloned from
+	// +kubebuilder:validation:MinLength=1
+	BaseRef string `json:"baseRef"`
+
+	// HostnameTemplate renders the preview hostname of each hostname. {hostname} is the base
+	// hostname, {name} the resource name, and any other placeholder the value of the resource
+	// label with that key. Defaults to "{name}.{hostname}".
+	HostnameTemplate string `json:"hostnameTemplate,omitempty"`
+}
+
+// OriginsSpec splits the traffic of a property across several origins by weight. It is rendered
+// into a managed child rule, the first child of the top-level rule so path specific rules can
+// still override the origin.
+type OriginsSpec struct {
+	// Origins are the origins requests are distributed across in proportion to their weights
+	// +kubebuilder:validation:MinItems=1
+	Origins []WeightedOrigin `json:"origins"`
+
+	// Failover is the origin requests are sent to when the selected origin can't be reached
+	Failover *OriginServer `json:"failover,omitempty"`
+}
+
+// WeightedOrigin is an origin receiving a share of the requests
+type WeightedOrigin struct {
+	OriginServer `json:",inline"`
+
+	// Name identifies the origin in the rendered rule names, e.g. "eu-west"
+	// +kubebuilder:validation:Pattern=`^[A-Za-z0-9][A-Za-z0-9_.-]*$`
+	Name string `json:"name"`
+
+	// Weight is the relative share of requests the origin receives; 0 drains it
+	// +kubebuilder:validation:Minimum=0
+	Weight int32 `json:"weight"`
+}
+
+// OriginServer is an origin server requests are forwarded to
+type OriginServer struct {
+	// Hostname is the hostname of the origin server
+	// +kubebuilder:validation:MinLength=1
+	Hostname string `json:"hostname"`
+
+	// ForwardHostHeader is the Host header sent to the origin: REQUEST_HOST_HEADER (default) or
+	// ORIGIN_HOSTNAME
+	// +kubebuilder:validation:Enum=REQUEST_HOST_HEADER;ORIGIN_HOSTNAME
+	ForwardHostHeader string `json:"forwardHostHeader,omitempty"`
+}
+
+// CredentialsReference selects a Secret holding EdgeGrid credentials. The key defaults match the
+// layout of the operator's own akamai-credentials Secret.
+type CredentialsReference struct {
+	// Namespace is the namespace of the Secret
+	Namespace string `json:"namespace"`
+
+	// Name is the name of the Secret
+	Name string `json:"name"`
+
+	// HostKey is the key of the API hostname. Defaults to "host".
+	HostKey string `json:"hostKey,omitempty"`
+
+	// ClientTokenKey is the key of the client token. Defaults to "client_token".
+	ClientTokenKey string `json:"clientTokenKey,omitempty"`
+
+	// ClientSecretKey is the key of the client secret. Defaults to "client_secret".
+	ClientSecretKey string `json:"clientSecretKey,omitempty"`
+
+	// AccessTokenKey is the key of the access token. Defaults to "access_token".
+	AccessTokenKey string `json:"accessTokenKey,omitempty"`
+}
+
+// HostnameCertificate is the state of the Default DV certificate of a property hostname
+type HostnameCertificate struct {
+	// Hostname is the property hostname (cnameFrom)
+	Hostname string `json:"hostname"`
+
+	// StagingStatus is the certificate status on staging, e.g. PENDING or DEPLOYED
+	StagingStatus string `json:"stagingStatus,omitempty"`
+
+	// ProductionStatus is the certificate status on production
+	ProductionStatus string `json:"productionStatus,omitempty"`
+
+	// ValidationRecords are the DNS records to create so the certificate authority can validate
+	// the domain; empty once the certificate is deployed on both networks
+	ValidationRecords []DNSRecord `json:"validationRecords,omitempty"`
+}
+
+// DNSRecord is a DNS record to create
+type DNSRecord struct {
+	// Type is the record type, e.g. CNAME
+	Type string `json:"type"`
+
+	// Name is the fully qualified record name
+	Name string `json:"name"`
+
+	// Value is the record value
+	Value string `json:"value"`
+}
+
 // EdgeHostnameSpec defines the edge hostname configuration
+// +kubebuilder:validation:XValidation:rule="!has(self.secureNetwork) || self.secureNetwork != 'ENHANCED_TLS' || has(self.certEnrollmentId) || has(self.certificateRef)",message="certEnrollmentId or certificateRef is required with secureNetwork ENHANCED_TLS"
+// +kubebuilder:validation:XValidation:rule="!has(self.certEnrollmentId) || !has(self.certificateRef)",message="only one of certEnrollmentId and certificateRef may be set"
 type EdgeHostnameSpec struct {
-	// DomainPrefix is the prefix for the edge hostname
-	DomainPrefix string `json:"domainPrefix"`
+	// DomainPrefix is the prefix for the edge hostname. When empty, it is rendered from the
+	// operator's --edge-hostname-template.
+	DomainPrefix string `json:"domainPrefix,omitempty"`
 
 	// DomainSuffix is the suffix for the edge hostname
 	DomainSuffix string `json:"domainSuffix"`
@@ -153,8 +680,61 @@ type EdgeHostnameSpec struct {
 	// SecureNetwork specifies the secure network type
 	SecureNetwork string `json:"secureNetwork,omitempty"`
 
+	// CertEnrollmentID is the ID of the CPS enrollment whose certificate an ENHANCED_TLS edge
+	// hostname is created with; required with ENHANCED_TLS unless certificateRef is set
+	// +kubebuilder:validation:Minimum=1
+	CertEnrollmentID int `json:"certEnrollmentId,omitempty"`
+
+	// CertificateRef is the name of the AkamaiCertificate whose enrollment an ENHANCED_TLS edge
+	// hostname is created with, instead of certEnrollmentId
+	CertificateRef string `json:"certificateRef,omitempty"`
+
+	// SlotNumber is the slot of the certificate of the CPS enrollment the edge hostname is
+	// created with
+	// +kubebuilder:validation:Minimum=1
+	SlotNumber int `json:"slotNumber,omitempty"`
+
 	// IPVersionBehavior specifies IP version behavior
 	IPVersionBehavior string `json:"ipVersionBehavior,omitempty"`
+
+	// UseCases map the edge hostname to the delivery of specific traffic, e.g. background
+	// downloads; they are set when the edge hostname is created
+	UseCases []EdgeHostnameUseCase `json:"useCases,omitempty"`
+
+	// ChinaCDN marks the edge hostname as delivered through China CDN. Every hostname CNAMEd to
+	// it must then have an icpLicense.
+	ChinaCDN bool `json:"chinaCdn,omitempty"`
+}
+
+// EdgeHostnameUseCase maps an edge hostname to the delivery of a type of traffic
+type EdgeHostnameUseCase struct {
+	// UseCase is the traffic the mapping applies to, e.g. "Download_Mode"
+	// +kubebuilder:validation:MinLength=1
+	UseCase string `json:"useCase"`
+
+	// Option selects the mapping of the use case
+	// +kubebuilder:validation:Enum=BACKGROUND;FOREGROUND
+	Option string `json:"option"`
+
+	// Type is the scope of the use case. Defaults to GLOBAL.
+	// +kubebuilder:validation:Enum=GLOBAL
+	Type string `json:"type,omitempty"`
+}
+
+// ManageSpec selects the parts of a property the operator manages. A part that isn't managed is
+// neither compared nor changed, and is left as other tooling sets it in Akamai.
+type ManageSpec struct {
+	// Rules manages the rule tree and version notes from spec.rules or spec.renderer
+	// +optional
+	Rules *bool `json:"rules,omitempty"`
+
+	// Hostnames manages the hostnames of spec.hostnames and the edge hostnames they need
+	// +optional
+	Hostnames *bool `json:"hostnames,omitempty"`
+
+	// Activation activates versions according to spec.activation and promotion requests
+	// +optional
+	Activation *bool `json:"activation,omitempty"`
 }
 
 // ActivationSpec defines the activation configuration for the property
@@ -170,9 +750,32 @@ type ActivationSpec struct {
 	// Note is a descriptive log comment for the activation
 	Note string `json:"note,omitempty"`
 
+	// Trigger decides when the operator starts an activation. Defaults to NoteChange.
+	Trigger ActivationTrigger `json:"trigger,omitempty"`
+
+	// Mode selects how activations are started. Defaults to Inline.
+	Mode ActivationMode `json:"mode,omitempty"`
+
+	// CancelSuperseded cancels the activation of the latest version while it is still PENDING
+	// when the spec changes, instead of waiting for it before writing the changes. The changed
+	// version is activated in its place, also with the NoteChange trigger.
+	CancelSuperseded bool `json:"cancelSuperseded,omitempty"`
+
+	// AcknowledgeWarnings lists the message IDs of activation warnings to acknowledge
+	AcknowledgeWarnings []string `json:"acknowledgeWarnings,omitempty"`
+
 	// AcknowledgeAllWarnings when true, skips acknowledging each warning individually
 	AcknowledgeAllWarnings bool `json:"acknowledgeAllWarnings,omitempty"`
 
+	// Guardrails lists the guard checks run on the rendered rules before a production activation,
+	// in addition to the operator-wide --guardrails
+	// +kubebuilder:validation:items:Enum=caching-removed;zero-ttl-at-default;no-store-at-default
+	Guardrails []string `json:"guardrails,omitempty"`
+
+	// AcknowledgeGuardrails lists the guardrail findings accepted for production activation, as
+	// "<guardrail>/v<version>", e.g. "caching-removed/v12"
+	AcknowledgeGuardrails []string `json:"acknowledgeGuardrails,omitempty"`
+
 	// UseFastFallback enables fast fallback for quick rollback (within 1 hour)
 	UseFastFallback bool `json:"useFastFallback,omitempty"`
 
@@ -181,6 +784,257 @@ type ActivationSpec struct {
 
 	// IgnoreHttpErrors ignores HTTP errors when pushing fast metadata activation
 	IgnoreHttpErrors *bool `json:"ignoreHttpErrors,omitempty"`
+
+	// Staging overrides the notification settings of activations on the staging network
+	Staging *ActivationNotification `json:"staging,omitempty"`
+
+	// Production overrides the notification settings of activations on the production network
+	Production *ActivationNotification `json:"production,omitempty"`
+}
+
+// ActivationNotification holds the notification settings of activations on one network
+type ActivationNotification struct {
+	// NotifyEmails replaces the top-level notifyEmails for activations on this network
+	NotifyEmails []string `json:"notifyEmails,omitempty"`
+
+	// NoteTemplate is a Go template rendering the activation note sent to Akamai. It can
+	// reference {{.Note}}, {{.Version}}, {{.Network}} and {{.PropertyName}}.
+	NoteTemplate string `json:"noteTemplate,omitempty"`
+}
+
+// ActivationTrigger decides when the operator starts a property activation
+// +kubebuilder:validation:Enum=OnChange;Manual;NoteChange
+type ActivationTrigger string
+
+const (
+	// ActivationTriggerOnChange activates the latest version whenever it differs from the
+	// version active on the network; the note is purely informational
+	ActivationTriggerOnChange ActivationTrigger = "OnChange"
+
+	// ActivationTriggerManual never starts activations; the operator only tracks activations
+	// started outside of it
+	ActivationTriggerManual ActivationTrigger = "Manual"
+
+	// ActivationTriggerNoteChange activates the latest version when the note changes, or when
+	// nothing is active on the network yet
+	ActivationTriggerNoteChange ActivationTrigger = "NoteChange"
+)
+
+// ActivationMode selects how the operator starts property activations
+// +kubebuilder:validation:Enum=Inline;Resources
+type ActivationMode string
+
+const (
+	// ActivationModeInline activates versions directly from the property controller and records
+	// the last activation of each network in the property status
+	ActivationModeInline ActivationMode = "Inline"
+
+	// ActivationModeResources creates an AkamaiPropertyActivation for each version to activate,
+	// so every activation keeps its own status history
+	ActivationModeResources ActivationMode = "Resources"
+)
+
+// EdgeEndpointsSpec defines where the edge endpoints ConfigMap is published
+type EdgeEndpointsSpec struct {
+	// Namespace is the namespace in which the edge endpoints ConfigMap is maintained
+	Namespace string `json:"namespace"`
+
+	// ConfigMapName overrides the name of the ConfigMap (defaults to "akamai-edge-endpoints")
+	ConfigMapName string `json:"configMapName,omitempty"`
+}
+
+// DNSProvider selects where the CNAMEs of the property hostnames are created
+type DNSProvider string
+
+const (
+	// DNSProviderEdgeDNS creates an AkamaiDnsRecord per hostname in Edge DNS
+	DNSProviderEdgeDNS DNSProvider = "EdgeDNS"
+
+	// DNSProviderExternalDNS publishes the CNAMEs in a DNSEndpoint of external-dns
+	DNSProviderExternalDNS DNSProvider = "ExternalDNS"
+)
+
+// PropertyDNSSpec defines the DNS records maintained for the property hostnames
+// +kubebuilder:validation:XValidation:rule="!has(self.provider) || self.provider != 'ExternalDNS' || has(self.namespace)",message="namespace is required with the ExternalDNS provider"
+type PropertyDNSSpec struct {
+	// AutoCreateCNAMEs creates a CNAME from each hostname to its edge hostname once the hostname
+	// is active on production, and removes it with the hostname
+	AutoCreateCNAMEs bool `json:"autoCreateCNAMEs,omitempty"`
+
+	// Provider selects where the CNAMEs are created. Defaults to EdgeDNS.
+	// +kubebuilder:validation:Enum=EdgeDNS;ExternalDNS
+	Provider DNSProvider `json:"provider,omitempty"`
+
+	// Zone is the Edge DNS zone of the hostnames. Defaults to the zone of the AkamaiDnsZone
+	// that is the longest suffix of each hostname.
+	Zone string `json:"zone,omitempty"`
+
+	// TTL is the time to live of the CNAMEs in seconds
+	// +kubebuilder:validation:Minimum=30
+	// +kubebuilder:default=300
+	TTL int `json:"ttl,omitempty"`
+
+	// Namespace is the namespace of the DNSEndpoint of the ExternalDNS provider
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// PromotionState is the state of an annotation-driven production promotion
+type PromotionState string
+
+const (
+	// PromotionStatePending means the production activation of the version is in progress
+	PromotionStatePending PromotionState = "Pending"
+
+	// PromotionStateActive means the version is active on production
+	PromotionStateActive PromotionState = "Active"
+
+	// PromotionStateFailed means the production activation of the version failed
+	PromotionStateFailed PromotionState = "Failed"
+
+	// PromotionStateRejected means the version was not promoted because it failed validation
+	PromotionStateRejected PromotionState = "Rejected"
+)
+
+// PromotionStatus tracks the production promotion of a property version
+type PromotionStatus struct {
+	// Version is the property version requested for promotion
+	Version int `json:"version"`
+
+	// State is the state of the promotion
+	State PromotionState `json:"state"`
+
+	// ActivationID is the ID of the production activation started for the promotion
+	ActivationID string `json:"activationId,omitempty"`
+
+	// Message explains the state, e.g. why the version was rejected
+	Message string `json:"message,omitempty"`
+}
+
+// ValidationStatus holds the validation results reported by Akamai for a rule tree
+type ValidationStatus struct {
+	// Warnings are the de-duplicated warnings of the last rules update (e.g. deprecated behaviors)
+	Warnings []ValidationWarning `json:"warnings,omitempty"`
+}
+
+// ValidationWarning is a non-blocking rule tree validation warning
+type ValidationWarning struct {
+	// Type identifies the kind of warning
+	Type string `json:"type"`
+
+	// Title is the short description of the warning
+	Title string `json:"title,omitempty"`
+
+	// Detail is the full warning text
+	Detail string `json:"detail,omitempty"`
+
+	// Location is the JSON pointer of the rule or behavior the warning refers to
+	Location string `json:"location,omitempty"`
+}
+
+// APIErrorStatus describes a failed Akamai API request
+type APIErrorStatus struct {
+	// Operation is what the operator tried to do, e.g. "update rules"
+	Operation string `json:"operation"`
+
+	// Time is when the request failed
+	Time metav1.Time `json:"time"`
+
+	// StatusCode is the HTTP status of the response
+	StatusCode int `json:"statusCode,omitempty"`
+
+	// Type is the problem type URI returned by the API
+	Type string `json:"type,omitempty"`
+
+	// Title is the short description of the problem
+	Title string `json:"title,omitempty"`
+
+	// Detail is the explanation of the problem, truncated to 1024 characters
+	Detail string `json:"detail,omitempty"`
+
+	// Instance identifies this occurrence of the problem; quote it when contacting Akamai support
+	Instance string `json:"instance,omitempty"`
+
+	// RequestInstance identifies the request, when the API returns it
+	RequestInstance string `json:"requestInstance,omitempty"`
+
+	// RequestID identifies the request in APIs that return a request ID instead
+	// +optional
+	RequestID string `json:"requestId,omitempty"`
+
+	// Errors are the individual problems of the response, e.g. the offending behavior and its
+	// location in the rule tree, limited to the first 10
+	// +optional
+	Errors []string `json:"errors,omitempty"`
+}
+
+// WriteCheckpoint records the progress of a sequence of Akamai writes, e.g. "version 7 created,
+// hostnames updated, rules pending"
+type WriteCheckpoint struct {
+	// Step is the write in progress: CreateVersion, UpdateProperty (edge hostnames and hostnames)
+	// or UpdateRules. It is empty between steps.
+	Step string `json:"step,omitempty"`
+
+	// Version is the property version written to; while a version is created, the version it
+	// is created from
+	Version int `json:"version,omitempty"`
+
+	// Generation is the metadata.generation the sequence writes
+	Generation int64 `json:"generation,omitempty"`
+
+	// Completed lists the steps already done on Version for Generation
+	Completed []string `json:"completed,omitempty"`
+
+	// StartedAt is when the current step started
+	StartedAt metav1.Time `json:"startedAt"`
+}
+
+// PendingGuardrail is a guardrail finding waiting to be acknowledged
+type PendingGuardrail struct {
+	// ID is the value to add to activation.acknowledgeGuardrails
+	ID string `json:"id"`
+
+	// Path is the rule the finding applies to
+	Path string `json:"path,omitempty"`
+
+	// Message describes the finding
+	Message string `json:"message,omitempty"`
+}
+
+// PendingWarning is an activation warning waiting to be acknowledged
+type PendingWarning struct {
+	// MessageID is the ID to add to activation.acknowledgeWarnings
+	MessageID string `json:"messageId"`
+
+	// Title is the short description of the warning
+	Title string `json:"title,omitempty"`
+
+	// Detail is the full warning text
+	Detail string `json:"detail,omitempty"`
+}
+
+// PlannedActivation is an activation the operator has planned but not completed, or the
+// deactivation of a property when it expires
+type PlannedActivation struct {
+	// Network is STAGING or PRODUCTION
+	Network string `json:"network"`
+
+	// Action is Activate or Deactivate
+	Action string `json:"action"`
+
+	// Version is the property version activated or deactivated
+	Version int `json:"version,omitempty"`
+
+	// Reason tells what the activation waits for, e.g. ActivationQueued or GuardrailsNotAcknowledged
+	Reason string `json:"reason"`
+
+	// Message explains the reason
+	Message string `json:"message,omitempty"`
+
+	// Since is when the activation entered its current state
+	Since *metav1.Time `json:"since,omitempty"`
+
+	// ScheduledAt is when the activation runs, if it is bound to a time
+	ScheduledAt *metav1.Time `json:"scheduledAt,omitempty"`
 }
 
 // AkamaiPropertyStatus defines the observed state of AkamaiProperty
@@ -221,6 +1075,108 @@ type AkamaiPropertyStatus struct {
 	// Conditions represent the latest available observations of the property's state
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
 
+	// Validation holds the validation results reported by Akamai for the last rules update
+	Validation *ValidationStatus `json:"validation,omitempty"`
+
+	// Serving summarizes how many spec hostnames are served by the production version with a
+	// ready certificate, e.g. "5/5"
+	Serving string `json:"serving,omitempty"`
+
+	// HostnameCertificates are the Default DV certificates of the hostnames with
+	// certProvisioningType DEFAULT on the latest version, with the DNS records their domain
+	// validation waits for
+	HostnameCertificates []HostnameCertificate `json:"hostnameCertificates,omitempty"`
+
+	// DNSEndpoint is the namespace/name of the DNSEndpoint publishing the CNAMEs of the hostnames
+	// to external-dns
+	DNSEndpoint string `json:"dnsEndpoint,omitempty"`
+
+	// EdgeEndpoints is the namespace/name of the ConfigMap the edge endpoints of the property are
+	// published in, under the key `<name>.json`
+	EdgeEndpoints string `json:"edgeEndpoints,omitempty"`
+
+	// PendingWarnings are the activation warnings Akamai requires to be acknowledged before the
+	// last activation attempt can proceed; add their message IDs to activation.acknowledgeWarnings
+	PendingWarnings []PendingWarning `json:"pendingWarnings,omitempty"`
+
+	// LastAPIError is the most recent failed Akamai API request, with the identifiers to quote
+	// in support tickets
+	LastAPIError *APIErrorStatus `json:"lastApiError,omitempty"`
+
+	// Checkpoint is the step of the Akamai write sequence in progress. It is cleared once the
+	// sequence completes; one left behind by a shutdown or crash is resumed by the next reconcile.
+	Checkpoint *WriteCheckpoint `json:"checkpoint,omitempty"`
+
+	// PendingGuardrails are the guardrail findings blocking the production activation of the
+	// latest version; add their IDs to activation.acknowledgeGuardrails
+	PendingGuardrails []PendingGuardrail `json:"pendingGuardrails,omitempty"`
+
+	// PreservedBehaviors lists the behaviors missing from spec.rules that the operator kept
+	// because they are listed in spec.preserveBehaviors or --preserve-behaviors, as
+	// "rule path/behavior name"
+	PreservedBehaviors []string `json:"preservedBehaviors,omitempty"`
+
+	// Drift lists the parts of the property changed outside the operator and kept because of
+	// spec.driftPolicy Warn or the observe-only mode of the operator: "hostnames" and "rules"
+	Drift []string `json:"drift,omitempty"`
+
+	// PlannedActivations lists the activations the operator plans or waits to run and the
+	// deactivations of an expiring property
+	PlannedActivations []PlannedActivation `json:"plannedActivations,omitempty"`
+
+	// RulesDigest is the digest of the content of spec.rulesFrom the rule tree was last rendered from
+	RulesDigest string `json:"rulesDigest,omitempty"`
+
+	// ValueSources lists the Secrets and ConfigMaps the placeholders in the rule options were last
+	// resolved from, e.g. `secret:edge/origin-auth`
+	ValueSources []string `json:"valueSources,omitempty"`
+
+	// CPCodes lists the CP codes set by the cpCode behaviors of the rule tree last rendered for
+	// Akamai; --report-traffic reports the traffic of these CP codes
+	CPCodes []string `json:"cpCodes,omitempty"`
+
+	// ValuesDigest is the digest of the versions of the value sources the rules in Akamai were
+	// last resolved from
+	ValuesDigest string `json:"valuesDigest,omitempty"`
+
+	// LastAppliedRules is the rule tree last applied with spec.rulesMergeStrategy ThreeWay
+	LastAppliedRules *AppliedRules `json:"lastAppliedRules,omitempty"`
+
+	// LastRulesDiff lists the changes of the last rules update, e.g.
+	// `Change behavior "caching" of rule "default/Images"`, truncated to 20 changes
+	LastRulesDiff []string `json:"lastRulesDiff,omitempty"`
+
+	// PendingChanges lists the changes a reconcile would make while spec.dryRun is set, e.g.
+	// `Add hostname www.example.com -> www.example.com.edgekey.net`
+	PendingChanges []string `json:"pendingChanges,omitempty"`
+
+	// Promotion tracks the production promotion requested with the akamai.com/promote-version annotation
+	Promotion *PromotionStatus `json:"promotion,omitempty"`
+
+	// ManagedHostnames are the hostnames (cnameFrom) applied by the operator; only these are
+	// removed from the property when they disappear from the spec
+	ManagedHostnames []string `json:"managedHostnames,omitempty"`
+
+	// OwnedEdgeHostnames are edge hostnames created by the operator that this property is
+	// responsible for deleting once no other AkamaiProperty references them
+	OwnedEdgeHostnames []string `json:"ownedEdgeHostnames,omitempty"`
+
+	// ValidatedGeneration is the last metadata.generation whose spec passed validation and linting
+	ValidatedGeneration int64 `json:"validatedGeneration,omitempty"`
+
+	// VersionGeneration is the metadata.generation for which the operator created LatestVersion
+	VersionGeneration int64 `json:"versionGeneration,omitempty"`
+
+	// ObservedGeneration is the last metadata.generation the operator finished reconciling, either
+	// successfully or with a stalled condition that needs user intervention
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// ExpiresAt is when the resource is deleted, from spec.expiresAt or spec.ttl
+	ExpiresAt *metav1.Time `json:"expiresAt,omitempty"`
+
+	// LastHandledReconcileAt is the last handled value of the reconcile.fluxcd.io/requestedAt annotation
+	LastHandledReconcileAt string `json:"lastHandledReconcileAt,omitempty"`
+
 	// Phase represents the current phase of the property lifecycle
 	Phase string `json:"phase,omitempty"`
 
@@ -235,7 +1191,9 @@ type AkamaiPropertyStatus struct {
 //+kubebuilder:printcolumn:name="Latest Version",type=integer,JSONPath=`.status.latestVersion`
 //+kubebuilder:printcolumn:name="Staging Version",type=integer,JSONPath=`.status.stagingVersion`
 //+kubebuilder:printcolumn:name="Production Version",type=integer,JSONPath=`.status.productionVersion`
+//+kubebuilder:printcolumn:name="Serving",type=string,JSONPath=`.status.serving`
 //+kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+//+kubebuilder:printcolumn:name="Expires",type=date,JSONPath=`.status.expiresAt`,priority=1
 //+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 
 // AkamaiProperty is the Schema for the akamaiproperties API
@@ -0,0 +1,120 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// AkamaiPropertyActivationSpec defines the activation of a property version on a network
+type AkamaiPropertyActivationSpec struct {
+	// PropertyRef is the name of the AkamaiProperty whose version is activated
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="propertyRef is immutable"
+	PropertyRef string `json:"propertyRef"`
+
+	// Version is the property version to activate. Defaults to the latest version when the
+	// activation is submitted.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="version is immutable"
+	Version int `json:"version,omitempty"`
+
+	// Network is the network to activate on (STAGING or PRODUCTION)
+	// +kubebuilder:validation:Enum=STAGING;PRODUCTION
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="network is immutable"
+	Network string `json:"network"`
+
+	// Note is the log comment of the activation. The note templates of the property's
+	// spec.activation are applied to it.
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="note is immutable"
+	Note string `json:"note,omitempty"`
+
+	// NotifyEmails are notified when the activation status changes. Defaults to the notify emails
+	// of the property's spec.activation.
+	NotifyEmails []string `json:"notifyEmails,omitempty"`
+
+	// AcknowledgeWarnings lists the message IDs of activation warnings to acknowledge, in addition
+	// to those of the property's spec.activation
+	AcknowledgeWarnings []string `json:"acknowledgeWarnings,omitempty"`
+
+	// AcknowledgeAllWarnings when true, skips acknowledging each warning individually
+	AcknowledgeAllWarnings bool `json:"acknowledgeAllWarnings,omitempty"`
+
+	// UseFastFallback enables fast fallback for quick rollback (within 1 hour)
+	UseFastFallback bool `json:"useFastFallback,omitempty"`
+}
+
+// ActivationHistoryEntry is a status the activation went through
+type ActivationHistoryEntry struct {
+	// Status is the Akamai activation status, e.g. PENDING, ACTIVE or FAILED
+	Status string `json:"status"`
+
+	// Time is when the operator observed the status
+	Time metav1.Time `json:"time"`
+}
+
+// AkamaiPropertyActivationStatus defines the observed state of the activation
+type AkamaiPropertyActivationStatus struct {
+	// PropertyID is the ID of the activated Akamai property
+	PropertyID string `json:"propertyId,omitempty"`
+
+	// Version is the property version that was submitted for activation
+	Version int `json:"version,omitempty"`
+
+	// ActivationID is the ID of the activation in Akamai
+	ActivationID string `json:"activationId,omitempty"`
+
+	// Status is the Akamai activation status, e.g. PENDING, ACTIVE or FAILED
+	Status string `json:"status,omitempty"`
+
+	// SubmittedAt is when the activation was submitted to Akamai
+	SubmittedAt *metav1.Time `json:"submittedAt,omitempty"`
+
+	// History lists the statuses the activation went through, oldest first
+	History []ActivationHistoryEntry `json:"history,omitempty"`
+
+	// PendingWarnings are the activation warnings Akamai requires to be acknowledged before the
+	// activation is accepted
+	PendingWarnings []PendingWarning `json:"pendingWarnings,omitempty"`
+
+	// ObservedGeneration is the metadata.generation the status belongs to
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Phase represents the current phase of the activation
+	Phase string `json:"phase,omitempty"`
+
+	// LastUpdated is the timestamp of the last status update
+	LastUpdated *metav1.Time `json:"lastUpdated,omitempty"`
+
+	// Conditions represent the latest available observations of the activation's state
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:resource:scope=Cluster
+//+kubebuilder:printcolumn:name="Property",type=string,JSONPath=`.spec.propertyRef`
+//+kubebuilder:printcolumn:name="Version",type=integer,JSONPath=`.status.version`
+//+kubebuilder:printcolumn:name="Network",type=string,JSONPath=`.spec.network`
+//+kubebuilder:printcolumn:name="Status",type=string,JSONPath=`.status.status`
+//+kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// AkamaiPropertyActivation activates a version of an AkamaiProperty on a network
+type AkamaiPropertyActivation struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   AkamaiPropertyActivationSpec   `json:"spec,omitempty"`
+	Status AkamaiPropertyActivationStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// AkamaiPropertyActivationList contains a list of AkamaiPropertyActivation
+type AkamaiPropertyActivationList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []AkamaiPropertyActivation `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&AkamaiPropertyActivation{}, &AkamaiPropertyActivationList{})
+}
@@ -0,0 +1,139 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// AkamaiPropertyIncludeSpec defines the desired state of a PAPI include, a rule tree shared by
+// the properties that reference it from an include behavior
+type AkamaiPropertyIncludeSpec struct {
+	// IncludeName is the name of the include in Akamai
+	IncludeName string `json:"includeName"`
+
+	// ContractID is the Akamai contract ID. Defaults to the default contract of the provider config.
+	ContractID string `json:"contractId,omitempty"`
+
+	// GroupID is the Akamai group ID. Defaults to the default group of the provider config.
+	GroupID string `json:"groupId,omitempty"`
+
+	// CredentialsRef selects a Secret with the EdgeGrid credentials of the Akamai account the
+	// include belongs to, which has to be the account of the properties using it. Defaults to
+	// the operator's credentials.
+	CredentialsRef *CredentialsReference `json:"credentialsRef,omitempty"`
+
+	// ProviderConfigRef is the name of the AkamaiProviderConfig with the connection profile of
+	// the Akamai account the include belongs to
+	ProviderConfigRef string `json:"providerConfigRef,omitempty"`
+
+	// ProductID is the Akamai product ID the include is created with
+	ProductID string `json:"productId"`
+
+	// IncludeType is the type of the include. Defaults to COMMON_SETTINGS.
+	// +kubebuilder:validation:Enum=COMMON_SETTINGS;MICROSERVICES
+	IncludeType string `json:"includeType,omitempty"`
+
+	// RuleFormat is the rule format the include is created with. Defaults to the latest.
+	RuleFormat string `json:"ruleFormat,omitempty"`
+
+	// Rules is the rule tree of the include
+	Rules PropertyRules `json:"rules"`
+
+	// Activation activates the latest version of the include on the listed networks
+	Activation *IncludeActivationSpec `json:"activation,omitempty"`
+}
+
+// IncludeActivationSpec defines the activation configuration of an include
+type IncludeActivationSpec struct {
+	// Networks lists the networks the latest version is activated on
+	// +kubebuilder:validation:MinItems=1
+	// +kubebuilder:validation:items:Enum=STAGING;PRODUCTION
+	Networks []string `json:"networks"`
+
+	// NotifyEmails are email addresses to notify when activation status changes
+	// +kubebuilder:validation:MinItems=1
+	NotifyEmails []string `json:"notifyEmails"`
+
+	// Note is a descriptive log comment for the activation
+	Note string `json:"note,omitempty"`
+
+	// AcknowledgeAllWarnings acknowledges all activation warnings
+	AcknowledgeAllWarnings bool `json:"acknowledgeAllWarnings,omitempty"`
+}
+
+// IncludeNetworkStatus is the state of an include on one network
+type IncludeNetworkStatus struct {
+	// Version is the include version active on the network
+	Version int `json:"version,omitempty"`
+
+	// ActivationID is the ID of the last activation the operator started
+	ActivationID string `json:"activationId,omitempty"`
+
+	// ActivationVersion is the include version of the last activation the operator started
+	ActivationVersion int `json:"activationVersion,omitempty"`
+
+	// ActivationStatus is the status of the last activation the operator started
+	ActivationStatus string `json:"activationStatus,omitempty"`
+}
+
+// AkamaiPropertyIncludeStatus defines the observed state of an include
+type AkamaiPropertyIncludeStatus struct {
+	// IncludeID is the Akamai include ID
+	IncludeID string `json:"includeId,omitempty"`
+
+	// LatestVersion is the latest version of the include
+	LatestVersion int `json:"latestVersion,omitempty"`
+
+	// Staging is the state of the include on the staging network
+	Staging *IncludeNetworkStatus `json:"staging,omitempty"`
+
+	// Production is the state of the include on the production network
+	Production *IncludeNetworkStatus `json:"production,omitempty"`
+
+	// Validation holds the de-duplicated warnings of the last rules update
+	Validation *ValidationStatus `json:"validation,omitempty"`
+
+	// ObservedGeneration is the metadata.generation the status belongs to
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Phase represents the current phase of the include
+	Phase string `json:"phase,omitempty"`
+
+	// LastUpdated is the timestamp of the last status update
+	LastUpdated *metav1.Time `json:"lastUpdated,omitempty"`
+
+	// Conditions represent the latest available observations of the include's state
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:resource:scope=Cluster
+//+kubebuilder:printcolumn:name="Include Name",type=string,JSONPath=`.spec.includeName`
+//+kubebuilder:printcolumn:name="Include ID",type=string,JSONPath=`.status.includeId`
+//+kubebuilder:printcolumn:name="Latest",type=integer,JSONPath=`.status.latestVersion`
+//+kubebuilder:printcolumn:name="Staging",type=integer,JSONPath=`.status.staging.version`
+//+kubebuilder:printcolumn:name="Production",type=integer,JSONPath=`.status.production.version`
+//+kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// AkamaiPropertyInclude is the Schema for the akamaipropertyincludes API
+type AkamaiPropertyInclude struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   AkamaiPropertyIncludeSpec   `json:"spec,omitempty"`
+	Status AkamaiPropertyIncludeStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// AkamaiPropertyIncludeList contains a list of AkamaiPropertyInclude
+type AkamaiPropertyIncludeList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []AkamaiPropertyInclude `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&AkamaiPropertyInclude{}, &AkamaiPropertyIncludeList{})
+}
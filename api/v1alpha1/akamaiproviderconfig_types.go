@@ -0,0 +1,51 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// AkamaiProviderConfigSpec is a named Akamai connection profile shared by AkamaiProperties
+type AkamaiProviderConfigSpec struct {
+	// CredentialsRef selects a Secret with the EdgeGrid credentials of the profile.
+	// Defaults to the operator's credentials.
+	CredentialsRef *CredentialsReference `json:"credentialsRef,omitempty"`
+
+	// AccountSwitchKey makes requests on behalf of another account the API client can manage,
+	// e.g. a tenant of a partner or reseller account
+	AccountSwitchKey string `json:"accountSwitchKey,omitempty"`
+
+	// DefaultContractID is used by properties that don't set spec.contractId
+	DefaultContractID string `json:"defaultContractId,omitempty"`
+
+	// DefaultGroupID is used by properties that don't set spec.groupId
+	DefaultGroupID string `json:"defaultGroupId,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:resource:scope=Cluster
+//+kubebuilder:printcolumn:name="Secret",type=string,JSONPath=`.spec.credentialsRef.name`
+//+kubebuilder:printcolumn:name="Default Contract",type=string,JSONPath=`.spec.defaultContractId`
+//+kubebuilder:printcolumn:name="Default Group",type=string,JSONPath=`.spec.defaultGroupId`
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// AkamaiProviderConfig is a named Akamai connection profile referenced by AkamaiProperties with
+// spec.providerConfigRef, so several Akamai tenants can be managed from one cluster
+type AkamaiProviderConfig struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec AkamaiProviderConfigSpec `json:"spec,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// AkamaiProviderConfigList contains a list of AkamaiProviderConfig
+type AkamaiProviderConfigList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []AkamaiProviderConfig `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&AkamaiProviderConfig{}, &AkamaiProviderConfigList{})
+}
@@ -0,0 +1,91 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// AkamaiPurgeSpec defines the content to remove from the Akamai cache with the Fast Purge API
+// +kubebuilder:validation:XValidation:rule="[has(self.urls), has(self.cpCodes), has(self.tags)].filter(x, x).size() == 1",message="exactly one of urls, cpCodes and tags must be set"
+type AkamaiPurgeSpec struct {
+	// URLs are the URLs to purge, e.g. https://www.example.com/index.html
+	// +kubebuilder:validation:MinItems=1
+	URLs []string `json:"urls,omitempty"`
+
+	// CPCodes are the CP codes whose content is purged
+	// +kubebuilder:validation:MinItems=1
+	CPCodes []int64 `json:"cpCodes,omitempty"`
+
+	// Tags are the cache tags whose content is purged, as set with the Edge-Cache-Tag header
+	// +kubebuilder:validation:MinItems=1
+	Tags []string `json:"tags,omitempty"`
+
+	// Action is how the content is purged: invalidate (the default) marks it stale so it is
+	// revalidated with the origin, delete removes it so it is fetched again
+	// +kubebuilder:validation:Enum=invalidate;delete
+	// +kubebuilder:default=invalidate
+	Action string `json:"action,omitempty"`
+
+	// Network is the network the content is purged on
+	// +kubebuilder:validation:Enum=staging;production
+	// +kubebuilder:default=production
+	Network string `json:"network,omitempty"`
+}
+
+// AkamaiPurgeStatus defines the outcome of the purge
+type AkamaiPurgeStatus struct {
+	// Phase is InProgress once the purge was accepted, Completed once its estimated time has
+	// passed, Failed when Akamai refused it and Error while it is retried
+	Phase string `json:"phase,omitempty"`
+
+	// ObservedGeneration is the metadata.generation that was purged
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// PurgeIDs are the IDs of the purge requests, one per batch of objects
+	PurgeIDs []string `json:"purgeIds,omitempty"`
+
+	// Objects is the number of URLs, CP codes or tags that were purged
+	Objects int `json:"objects,omitempty"`
+
+	// EstimatedSeconds is how long Akamai estimated the purge to take
+	EstimatedSeconds int `json:"estimatedSeconds,omitempty"`
+
+	// SubmittedAt is when the purge was accepted
+	SubmittedAt *metav1.Time `json:"submittedAt,omitempty"`
+
+	// CompletedAt is when the purge is considered complete
+	CompletedAt *metav1.Time `json:"completedAt,omitempty"`
+
+	// Message explains a Failed or Error phase
+	Message string `json:"message,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:resource:scope=Cluster
+//+kubebuilder:printcolumn:name="Action",type=string,JSONPath=`.spec.action`
+//+kubebuilder:printcolumn:name="Network",type=string,JSONPath=`.spec.network`
+//+kubebuilder:printcolumn:name="Objects",type=integer,JSONPath=`.status.objects`
+//+kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// AkamaiPurge purges URLs, CP codes or cache tags from the Akamai cache once
+type AkamaiPurge struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   AkamaiPurgeSpec   `json:"spec,omitempty"`
+	Status AkamaiPurgeStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// AkamaiPurgeList contains a list of AkamaiPurge
+type AkamaiPurgeList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []AkamaiPurge `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&AkamaiPurge{}, &AkamaiPurgeList{})
+}
@@ -0,0 +1,73 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// AkamaiRuleValidationSpec defines a candidate rule tree to validate against an existing property
+type AkamaiRuleValidationSpec struct {
+	// PropertyRef is the name of the AkamaiProperty whose Akamai property validates the rules
+	PropertyRef string `json:"propertyRef"`
+
+	// Version is the property version to validate against. Defaults to the latest version.
+	// +kubebuilder:validation:Minimum=1
+	Version int `json:"version,omitempty"`
+
+	// Rules is the candidate rule tree
+	Rules PropertyRules `json:"rules"`
+}
+
+// AkamaiRuleValidationStatus defines the outcome of the validation
+type AkamaiRuleValidationStatus struct {
+	// Result is Valid or Invalid once the rules were validated, Error if they could not be
+	// +kubebuilder:validation:Enum=Valid;Invalid;Error
+	Result string `json:"result,omitempty"`
+
+	// ObservedGeneration is the metadata.generation the result belongs to
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// PropertyVersion is the property version the rules were validated against
+	PropertyVersion int `json:"propertyVersion,omitempty"`
+
+	// Errors are the validation errors that would keep the rules from being saved
+	Errors []ValidationWarning `json:"errors,omitempty"`
+
+	// Warnings are the de-duplicated validation warnings
+	Warnings []ValidationWarning `json:"warnings,omitempty"`
+
+	// Message explains an Error result
+	Message string `json:"message,omitempty"`
+
+	// LastValidated is the timestamp of the last validation
+	LastValidated *metav1.Time `json:"lastValidated,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:resource:scope=Cluster
+//+kubebuilder:printcolumn:name="Property",type=string,JSONPath=`.spec.propertyRef`
+//+kubebuilder:printcolumn:name="Version",type=integer,JSONPath=`.status.propertyVersion`
+//+kubebuilder:printcolumn:name="Result",type=string,JSONPath=`.status.result`
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// AkamaiRuleValidation validates a candidate rule tree with Akamai without creating a property version
+type AkamaiRuleValidation struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   AkamaiRuleValidationSpec   `json:"spec,omitempty"`
+	Status AkamaiRuleValidationStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// AkamaiRuleValidationList contains a list of AkamaiRuleValidation
+type AkamaiRuleValidationList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []AkamaiRuleValidation `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&AkamaiRuleValidation{}, &AkamaiRuleValidationList{})
+}
@@ -9,6 +9,63 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIErrorStatus) DeepCopyInto(out *APIErrorStatus) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+	if in.Errors != nil {
+		in, out := &in.Errors, &out.Errors
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIErrorStatus.
+func (in *APIErrorStatus) DeepCopy() *APIErrorStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(APIErrorStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ActivationHistoryEntry) DeepCopyInto(out *ActivationHistoryEntry) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ActivationHistoryEntry.
+func (in *ActivationHistoryEntry) DeepCopy() *ActivationHistoryEntry {
+	if in == nil {
+		return nil
+	}
+	out := new(ActivationHistoryEntry)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ActivationNotification) DeepCopyInto(out *ActivationNotification) {
+	*out = *in
+	if in.NotifyEmails != nil {
+		in, out := &in.NotifyEmails, &out.NotifyEmails
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ActivationNotification.
+func (in *ActivationNotification) DeepCopy() *ActivationNotification {
+	if in == nil {
+		return nil
+	}
+	out := new(ActivationNotification)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ActivationSpec) DeepCopyInto(out *ActivationSpec) {
 	*out = *in
@@ -17,6 +74,21 @@ func (in *ActivationSpec) DeepCopyInto(out *ActivationSpec) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.AcknowledgeWarnings != nil {
+		in, out := &in.AcknowledgeWarnings, &out.AcknowledgeWarnings
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Guardrails != nil {
+		in, out := &in.Guardrails, &out.Guardrails
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AcknowledgeGuardrails != nil {
+		in, out := &in.AcknowledgeGuardrails, &out.AcknowledgeGuardrails
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	if in.FastPush != nil {
 		in, out := &in.FastPush, &out.FastPush
 		*out = new(bool)
@@ -27,6 +99,16 @@ func (in *ActivationSpec) DeepCopyInto(out *ActivationSpec) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.Staging != nil {
+		in, out := &in.Staging, &out.Staging
+		*out = new(ActivationNotification)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Production != nil {
+		in, out := &in.Production, &out.Production
+		*out = new(ActivationNotification)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ActivationSpec.
@@ -40,7 +122,7 @@ func (in *ActivationSpec) DeepCopy() *ActivationSpec {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *AkamaiProperty) DeepCopyInto(out *AkamaiProperty) {
+func (in *AkamaiAppSecConfig) DeepCopyInto(out *AkamaiAppSecConfig) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
@@ -48,18 +130,18 @@ func (in *AkamaiProperty) DeepCopyInto(out *AkamaiProperty) {
 	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AkamaiProperty.
-func (in *AkamaiProperty) DeepCopy() *AkamaiProperty {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AkamaiAppSecConfig.
+func (in *AkamaiAppSecConfig) DeepCopy() *AkamaiAppSecConfig {
 	if in == nil {
 		return nil
 	}
-	out := new(AkamaiProperty)
+	out := new(AkamaiAppSecConfig)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *AkamaiProperty) DeepCopyObject() runtime.Object {
+func (in *AkamaiAppSecConfig) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -67,31 +149,31 @@ func (in *AkamaiProperty) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *AkamaiPropertyList) DeepCopyInto(out *AkamaiPropertyList) {
+func (in *AkamaiAppSecConfigList) DeepCopyInto(out *AkamaiAppSecConfigList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]AkamaiProperty, len(*in))
+		*out = make([]AkamaiAppSecConfig, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AkamaiPropertyList.
-func (in *AkamaiPropertyList) DeepCopy() *AkamaiPropertyList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AkamaiAppSecConfigList.
+func (in *AkamaiAppSecConfigList) DeepCopy() *AkamaiAppSecConfigList {
 	if in == nil {
 		return nil
 	}
-	out := new(AkamaiPropertyList)
+	out := new(AkamaiAppSecConfigList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *AkamaiPropertyList) DeepCopyObject() runtime.Object {
+func (in *AkamaiAppSecConfigList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -99,43 +181,87 @@ func (in *AkamaiPropertyList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *AkamaiPropertySpec) DeepCopyInto(out *AkamaiPropertySpec) {
+func (in *AkamaiAppSecConfigSpec) DeepCopyInto(out *AkamaiAppSecConfigSpec) {
 	*out = *in
 	if in.Hostnames != nil {
 		in, out := &in.Hostnames, &out.Hostnames
-		*out = make([]Hostname, len(*in))
+		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
-	if in.Rules != nil {
-		in, out := &in.Rules, &out.Rules
-		*out = new(PropertyRules)
-		(*in).DeepCopyInto(*out)
+	if in.Policies != nil {
+		in, out := &in.Policies, &out.Policies
+		*out = make([]AppSecPolicySpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
-	if in.EdgeHostname != nil {
-		in, out := &in.EdgeHostname, &out.EdgeHostname
-		*out = new(EdgeHostnameSpec)
-		**out = **in
+	if in.MatchTargets != nil {
+		in, out := &in.MatchTargets, &out.MatchTargets
+		*out = make([]AppSecMatchTargetSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.RatePolicies != nil {
+		in, out := &in.RatePolicies, &out.RatePolicies
+		*out = make([]AppSecRatePolicySpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 	if in.Activation != nil {
 		in, out := &in.Activation, &out.Activation
-		*out = new(ActivationSpec)
+		*out = new(AppSecActivationSpec)
 		(*in).DeepCopyInto(*out)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AkamaiPropertySpec.
-func (in *AkamaiPropertySpec) DeepCopy() *AkamaiPropertySpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AkamaiAppSecConfigSpec.
+func (in *AkamaiAppSecConfigSpec) DeepCopy() *AkamaiAppSecConfigSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(AkamaiPropertySpec)
+	out := new(AkamaiAppSecConfigSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *AkamaiPropertyStatus) DeepCopyInto(out *AkamaiPropertyStatus) {
+func (in *AkamaiAppSecConfigStatus) DeepCopyInto(out *AkamaiAppSecConfigStatus) {
 	*out = *in
+	if in.Policies != nil {
+		in, out := &in.Policies, &out.Policies
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.RatePolicies != nil {
+		in, out := &in.RatePolicies, &out.RatePolicies
+		*out = make(map[string]int, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.LastChanges != nil {
+		in, out := &in.LastChanges, &out.LastChanges
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Staging != nil {
+		in, out := &in.Staging, &out.Staging
+		*out = new(AppSecActivationStatus)
+		**out = **in
+	}
+	if in.Production != nil {
+		in, out := &in.Production, &out.Production
+		*out = new(AppSecActivationStatus)
+		**out = **in
+	}
+	if in.LastUpdated != nil {
+		in, out := &in.LastUpdated, &out.LastUpdated
+		*out = (*in).DeepCopy()
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make([]v1.Condition, len(*in))
@@ -143,138 +269,3500 @@ func (in *AkamaiPropertyStatus) DeepCopyInto(out *AkamaiPropertyStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
-	if in.LastUpdated != nil {
-		in, out := &in.LastUpdated, &out.LastUpdated
-		*out = (*in).DeepCopy()
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AkamaiAppSecConfigStatus.
+func (in *AkamaiAppSecConfigStatus) DeepCopy() *AkamaiAppSecConfigStatus {
+	if in == nil {
+		return nil
 	}
+	out := new(AkamaiAppSecConfigStatus)
+	in.DeepCopyInto(out)
+	return out
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AkamaiPropertyStatus.
-func (in *AkamaiPropertyStatus) DeepCopy() *AkamaiPropertyStatus {
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AkamaiCertificate) DeepCopyInto(out *AkamaiCertificate) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AkamaiCertificate.
+func (in *AkamaiCertificate) DeepCopy() *AkamaiCertificate {
 	if in == nil {
 		return nil
 	}
-	out := new(AkamaiPropertyStatus)
+	out := new(AkamaiCertificate)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AkamaiCertificate) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *EdgeHostnameSpec) DeepCopyInto(out *EdgeHostnameSpec) {
+func (in *AkamaiCertificateList) DeepCopyInto(out *AkamaiCertificateList) {
 	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]AkamaiCertificate, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EdgeHostnameSpec.
-func (in *EdgeHostnameSpec) DeepCopy() *EdgeHostnameSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AkamaiCertificateList.
+func (in *AkamaiCertificateList) DeepCopy() *AkamaiCertificateList {
 	if in == nil {
 		return nil
 	}
-	out := new(EdgeHostnameSpec)
+	out := new(AkamaiCertificateList)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AkamaiCertificateList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Hostname) DeepCopyInto(out *Hostname) {
+func (in *AkamaiCertificateSpec) DeepCopyInto(out *AkamaiCertificateSpec) {
 	*out = *in
+	if in.SANs != nil {
+		in, out := &in.SANs, &out.SANs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.SNIOnly != nil {
+		in, out := &in.SNIOnly, &out.SNIOnly
+		*out = new(bool)
+		**out = **in
+	}
+	out.Organization = in.Organization
+	out.AdminContact = in.AdminContact
+	out.TechContact = in.TechContact
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Hostname.
-func (in *Hostname) DeepCopy() *Hostname {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AkamaiCertificateSpec.
+func (in *AkamaiCertificateSpec) DeepCopy() *AkamaiCertificateSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(Hostname)
+	out := new(AkamaiCertificateSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PropertyRules) DeepCopyInto(out *PropertyRules) {
+func (in *AkamaiCertificateStatus) DeepCopyInto(out *AkamaiCertificateStatus) {
 	*out = *in
-	if in.Criteria != nil {
-		in, out := &in.Criteria, &out.Criteria
-		*out = make([]RuleCriteria, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.SANs != nil {
+		in, out := &in.SANs, &out.SANs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
-	if in.Behaviors != nil {
-		in, out := &in.Behaviors, &out.Behaviors
-		*out = make([]RuleBehavior, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.PendingChange != nil {
+		in, out := &in.PendingChange, &out.PendingChange
+		*out = new(CertificateChangeStatus)
+		(*in).DeepCopyInto(*out)
 	}
-	if in.Children != nil {
-		in, out := &in.Children, &out.Children
-		*out = make([]runtime.RawExtension, len(*in))
+	if in.Staging != nil {
+		in, out := &in.Staging, &out.Staging
+		*out = new(CertificateDeploymentStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Production != nil {
+		in, out := &in.Production, &out.Production
+		*out = new(CertificateDeploymentStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LastUpdated != nil {
+		in, out := &in.LastUpdated, &out.LastUpdated
+		*out = (*in).DeepCopy()
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
-	if in.Variables != nil {
-		in, out := &in.Variables, &out.Variables
-		*out = make([]RuleVariable, len(*in))
-		copy(*out, *in)
-	}
-	in.Options.DeepCopyInto(&out.Options)
-	in.CustomOverride.DeepCopyInto(&out.CustomOverride)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PropertyRules.
-func (in *PropertyRules) DeepCopy() *PropertyRules {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AkamaiCertificateStatus.
+func (in *AkamaiCertificateStatus) DeepCopy() *AkamaiCertificateStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(PropertyRules)
+	out := new(AkamaiCertificateStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RuleBehavior) DeepCopyInto(out *RuleBehavior) {
+func (in *AkamaiClientList) DeepCopyInto(out *AkamaiClientList) {
 	*out = *in
-	in.Options.DeepCopyInto(&out.Options)
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RuleBehavior.
-func (in *RuleBehavior) DeepCopy() *RuleBehavior {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AkamaiClientList.
+func (in *AkamaiClientList) DeepCopy() *AkamaiClientList {
 	if in == nil {
 		return nil
 	}
-	out := new(RuleBehavior)
+	out := new(AkamaiClientList)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AkamaiClientList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RuleCriteria) DeepCopyInto(out *RuleCriteria) {
+func (in *AkamaiClientListList) DeepCopyInto(out *AkamaiClientListList) {
 	*out = *in
-	in.Options.DeepCopyInto(&out.Options)
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]AkamaiClientList, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RuleCriteria.
-func (in *RuleCriteria) DeepCopy() *RuleCriteria {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AkamaiClientListList.
+func (in *AkamaiClientListList) DeepCopy() *AkamaiClientListList {
 	if in == nil {
 		return nil
 	}
-	out := new(RuleCriteria)
+	out := new(AkamaiClientListList)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AkamaiClientListList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RuleVariable) DeepCopyInto(out *RuleVariable) {
+func (in *AkamaiClientListSpec) DeepCopyInto(out *AkamaiClientListSpec) {
 	*out = *in
+	if in.Tags != nil {
+		in, out := &in.Tags, &out.Tags
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ClientListItem, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Activation != nil {
+		in, out := &in.Activation, &out.Activation
+		*out = new(ClientListActivationSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RuleVariable.
-func (in *RuleVariable) DeepCopy() *RuleVariable {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AkamaiClientListSpec.
+func (in *AkamaiClientListSpec) DeepCopy() *AkamaiClientListSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(RuleVariable)
+	out := new(AkamaiClientListSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AkamaiClientListStatus) DeepCopyInto(out *AkamaiClientListStatus) {
+	*out = *in
+	if in.Staging != nil {
+		in, out := &in.Staging, &out.Staging
+		*out = new(ClientListActivationStatus)
+		**out = **in
+	}
+	if in.Production != nil {
+		in, out := &in.Production, &out.Production
+		*out = new(ClientListActivationStatus)
+		**out = **in
+	}
+	if in.LastUpdated != nil {
+		in, out := &in.LastUpdated, &out.LastUpdated
+		*out = (*in).DeepCopy()
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AkamaiClientListStatus.
+func (in *AkamaiClientListStatus) DeepCopy() *AkamaiClientListStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AkamaiClientListStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AkamaiCloudletPolicy) DeepCopyInto(out *AkamaiCloudletPolicy) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AkamaiCloudletPolicy.
+func (in *AkamaiCloudletPolicy) DeepCopy() *AkamaiCloudletPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(AkamaiCloudletPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AkamaiCloudletPolicy) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AkamaiCloudletPolicyList) DeepCopyInto(out *AkamaiCloudletPolicyList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]AkamaiCloudletPolicy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AkamaiCloudletPolicyList.
+func (in *AkamaiCloudletPolicyList) DeepCopy() *AkamaiCloudletPolicyList {
+	if in == nil {
+		return nil
+	}
+	out := new(AkamaiCloudletPolicyList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AkamaiCloudletPolicyList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AkamaiCloudletPolicySpec) DeepCopyInto(out *AkamaiCloudletPolicySpec) {
+	*out = *in
+	if in.MatchRules != nil {
+		in, out := &in.MatchRules, &out.MatchRules
+		*out = make([]runtime.RawExtension, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Activation != nil {
+		in, out := &in.Activation, &out.Activation
+		*out = new(CloudletPolicyActivationSpec)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AkamaiCloudletPolicySpec.
+func (in *AkamaiCloudletPolicySpec) DeepCopy() *AkamaiCloudletPolicySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AkamaiCloudletPolicySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AkamaiCloudletPolicyStatus) DeepCopyInto(out *AkamaiCloudletPolicyStatus) {
+	*out = *in
+	if in.Staging != nil {
+		in, out := &in.Staging, &out.Staging
+		*out = new(CloudletPolicyActivationStatus)
+		**out = **in
+	}
+	if in.Production != nil {
+		in, out := &in.Production, &out.Production
+		*out = new(CloudletPolicyActivationStatus)
+		**out = **in
+	}
+	if in.Warnings != nil {
+		in, out := &in.Warnings, &out.Warnings
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.LastUpdated != nil {
+		in, out := &in.LastUpdated, &out.LastUpdated
+		*out = (*in).DeepCopy()
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AkamaiCloudletPolicyStatus.
+func (in *AkamaiCloudletPolicyStatus) DeepCopy() *AkamaiCloudletPolicyStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AkamaiCloudletPolicyStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AkamaiContract) DeepCopyInto(out *AkamaiContract) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AkamaiContract.
+func (in *AkamaiContract) DeepCopy() *AkamaiContract {
+	if in == nil {
+		return nil
+	}
+	out := new(AkamaiContract)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AkamaiContract) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AkamaiContractList) DeepCopyInto(out *AkamaiContractList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]AkamaiContract, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AkamaiContractList.
+func (in *AkamaiContractList) DeepCopy() *AkamaiContractList {
+	if in == nil {
+		return nil
+	}
+	out := new(AkamaiContractList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AkamaiContractList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AkamaiContractSpec) DeepCopyInto(out *AkamaiContractSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AkamaiContractSpec.
+func (in *AkamaiContractSpec) DeepCopy() *AkamaiContractSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AkamaiContractSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AkamaiContractStatus) DeepCopyInto(out *AkamaiContractStatus) {
+	*out = *in
+	if in.Products != nil {
+		in, out := &in.Products, &out.Products
+		*out = make([]AkamaiProduct, len(*in))
+		copy(*out, *in)
+	}
+	if in.LastSynced != nil {
+		in, out := &in.LastSynced, &out.LastSynced
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AkamaiContractStatus.
+func (in *AkamaiContractStatus) DeepCopy() *AkamaiContractStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AkamaiContractStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AkamaiDataStream) DeepCopyInto(out *AkamaiDataStream) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AkamaiDataStream.
+func (in *AkamaiDataStream) DeepCopy() *AkamaiDataStream {
+	if in == nil {
+		return nil
+	}
+	out := new(AkamaiDataStream)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AkamaiDataStream) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AkamaiDataStreamList) DeepCopyInto(out *AkamaiDataStreamList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]AkamaiDataStream, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AkamaiDataStreamList.
+func (in *AkamaiDataStreamList) DeepCopy() *AkamaiDataStreamList {
+	if in == nil {
+		return nil
+	}
+	out := new(AkamaiDataStreamList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AkamaiDataStreamList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AkamaiDataStreamSpec) DeepCopyInto(out *AkamaiDataStreamSpec) {
+	*out = *in
+	if in.PropertyRefs != nil {
+		in, out := &in.PropertyRefs, &out.PropertyRefs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PropertyIDs != nil {
+		in, out := &in.PropertyIDs, &out.PropertyIDs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.DatasetFieldIDs != nil {
+		in, out := &in.DatasetFieldIDs, &out.DatasetFieldIDs
+		*out = make([]int, len(*in))
+		copy(*out, *in)
+	}
+	if in.NotificationEmails != nil {
+		in, out := &in.NotificationEmails, &out.NotificationEmails
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.Destination.DeepCopyInto(&out.Destination)
+	if in.Active != nil {
+		in, out := &in.Active, &out.Active
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AkamaiDataStreamSpec.
+func (in *AkamaiDataStreamSpec) DeepCopy() *AkamaiDataStreamSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AkamaiDataStreamSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AkamaiDataStreamStatus) DeepCopyInto(out *AkamaiDataStreamStatus) {
+	*out = *in
+	if in.PropertyIDs != nil {
+		in, out := &in.PropertyIDs, &out.PropertyIDs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.LastUpdated != nil {
+		in, out := &in.LastUpdated, &out.LastUpdated
+		*out = (*in).DeepCopy()
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AkamaiDataStreamStatus.
+func (in *AkamaiDataStreamStatus) DeepCopy() *AkamaiDataStreamStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AkamaiDataStreamStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AkamaiDnsRecord) DeepCopyInto(out *AkamaiDnsRecord) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AkamaiDnsRecord.
+func (in *AkamaiDnsRecord) DeepCopy() *AkamaiDnsRecord {
+	if in == nil {
+		return nil
+	}
+	out := new(AkamaiDnsRecord)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AkamaiDnsRecord) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AkamaiDnsRecordList) DeepCopyInto(out *AkamaiDnsRecordList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]AkamaiDnsRecord, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AkamaiDnsRecordList.
+func (in *AkamaiDnsRecordList) DeepCopy() *AkamaiDnsRecordList {
+	if in == nil {
+		return nil
+	}
+	out := new(AkamaiDnsRecordList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AkamaiDnsRecordList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AkamaiDnsRecordSpec) DeepCopyInto(out *AkamaiDnsRecordSpec) {
+	*out = *in
+	if in.Targets != nil {
+		in, out := &in.Targets, &out.Targets
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AkamaiDnsRecordSpec.
+func (in *AkamaiDnsRecordSpec) DeepCopy() *AkamaiDnsRecordSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AkamaiDnsRecordSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AkamaiDnsRecordStatus) DeepCopyInto(out *AkamaiDnsRecordStatus) {
+	*out = *in
+	if in.Targets != nil {
+		in, out := &in.Targets, &out.Targets
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.LastDriftCorrected != nil {
+		in, out := &in.LastDriftCorrected, &out.LastDriftCorrected
+		*out = (*in).DeepCopy()
+	}
+	if in.LastUpdated != nil {
+		in, out := &in.LastUpdated, &out.LastUpdated
+		*out = (*in).DeepCopy()
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AkamaiDnsRecordStatus.
+func (in *AkamaiDnsRecordStatus) DeepCopy() *AkamaiDnsRecordStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AkamaiDnsRecordStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AkamaiDnsZone) DeepCopyInto(out *AkamaiDnsZone) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AkamaiDnsZone.
+func (in *AkamaiDnsZone) DeepCopy() *AkamaiDnsZone {
+	if in == nil {
+		return nil
+	}
+	out := new(AkamaiDnsZone)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AkamaiDnsZone) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AkamaiDnsZoneList) DeepCopyInto(out *AkamaiDnsZoneList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]AkamaiDnsZone, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AkamaiDnsZoneList.
+func (in *AkamaiDnsZoneList) DeepCopy() *AkamaiDnsZoneList {
+	if in == nil {
+		return nil
+	}
+	out := new(AkamaiDnsZoneList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AkamaiDnsZoneList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AkamaiDnsZoneSpec) DeepCopyInto(out *AkamaiDnsZoneSpec) {
+	*out = *in
+	if in.Masters != nil {
+		in, out := &in.Masters, &out.Masters
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AkamaiDnsZoneSpec.
+func (in *AkamaiDnsZoneSpec) DeepCopy() *AkamaiDnsZoneSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AkamaiDnsZoneSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AkamaiDnsZoneStatus) DeepCopyInto(out *AkamaiDnsZoneStatus) {
+	*out = *in
+	if in.LastUpdated != nil {
+		in, out := &in.LastUpdated, &out.LastUpdated
+		*out = (*in).DeepCopy()
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AkamaiDnsZoneStatus.
+func (in *AkamaiDnsZoneStatus) DeepCopy() *AkamaiDnsZoneStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AkamaiDnsZoneStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AkamaiEdgeHostname) DeepCopyInto(out *AkamaiEdgeHostname) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AkamaiEdgeHostname.
+func (in *AkamaiEdgeHostname) DeepCopy() *AkamaiEdgeHostname {
+	if in == nil {
+		return nil
+	}
+	out := new(AkamaiEdgeHostname)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AkamaiEdgeHostname) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AkamaiEdgeHostnameList) DeepCopyInto(out *AkamaiEdgeHostnameList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]AkamaiEdgeHostname, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AkamaiEdgeHostnameList.
+func (in *AkamaiEdgeHostnameList) DeepCopy() *AkamaiEdgeHostnameList {
+	if in == nil {
+		return nil
+	}
+	out := new(AkamaiEdgeHostnameList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AkamaiEdgeHostnameList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AkamaiEdgeHostnameSpec) DeepCopyInto(out *AkamaiEdgeHostnameSpec) {
+	*out = *in
+	if in.CredentialsRef != nil {
+		in, out := &in.CredentialsRef, &out.CredentialsRef
+		*out = new(CredentialsReference)
+		**out = **in
+	}
+	if in.UseCases != nil {
+		in, out := &in.UseCases, &out.UseCases
+		*out = make([]EdgeHostnameUseCase, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AkamaiEdgeHostnameSpec.
+func (in *AkamaiEdgeHostnameSpec) DeepCopy() *AkamaiEdgeHostnameSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AkamaiEdgeHostnameSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AkamaiEdgeHostnameStatus) DeepCopyInto(out *AkamaiEdgeHostnameStatus) {
+	*out = *in
+	if in.Certificates != nil {
+		in, out := &in.Certificates, &out.Certificates
+		*out = make([]EdgeHostnameCertificate, len(*in))
+		copy(*out, *in)
+	}
+	if in.ReferencedBy != nil {
+		in, out := &in.ReferencedBy, &out.ReferencedBy
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.LastUpdated != nil {
+		in, out := &in.LastUpdated, &out.LastUpdated
+		*out = (*in).DeepCopy()
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AkamaiEdgeHostnameStatus.
+func (in *AkamaiEdgeHostnameStatus) DeepCopy() *AkamaiEdgeHostnameStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AkamaiEdgeHostnameStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AkamaiEdgeKVNamespace) DeepCopyInto(out *AkamaiEdgeKVNamespace) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AkamaiEdgeKVNamespace.
+func (in *AkamaiEdgeKVNamespace) DeepCopy() *AkamaiEdgeKVNamespace {
+	if in == nil {
+		return nil
+	}
+	out := new(AkamaiEdgeKVNamespace)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AkamaiEdgeKVNamespace) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AkamaiEdgeKVNamespaceList) DeepCopyInto(out *AkamaiEdgeKVNamespaceList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]AkamaiEdgeKVNamespace, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AkamaiEdgeKVNamespaceList.
+func (in *AkamaiEdgeKVNamespaceList) DeepCopy() *AkamaiEdgeKVNamespaceList {
+	if in == nil {
+		return nil
+	}
+	out := new(AkamaiEdgeKVNamespaceList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AkamaiEdgeKVNamespaceList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AkamaiEdgeKVNamespaceSpec) DeepCopyInto(out *AkamaiEdgeKVNamespaceSpec) {
+	*out = *in
+	if in.Networks != nil {
+		in, out := &in.Networks, &out.Networks
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Seed != nil {
+		in, out := &in.Seed, &out.Seed
+		*out = new(EdgeKVSeedSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AkamaiEdgeKVNamespaceSpec.
+func (in *AkamaiEdgeKVNamespaceSpec) DeepCopy() *AkamaiEdgeKVNamespaceSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AkamaiEdgeKVNamespaceSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AkamaiEdgeKVNamespaceStatus) DeepCopyInto(out *AkamaiEdgeKVNamespaceStatus) {
+	*out = *in
+	if in.Staging != nil {
+		in, out := &in.Staging, &out.Staging
+		*out = new(EdgeKVNamespaceNetworkStatus)
+		**out = **in
+	}
+	if in.Production != nil {
+		in, out := &in.Production, &out.Production
+		*out = new(EdgeKVNamespaceNetworkStatus)
+		**out = **in
+	}
+	if in.LastChanges != nil {
+		in, out := &in.LastChanges, &out.LastChanges
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.LastUpdated != nil {
+		in, out := &in.LastUpdated, &out.LastUpdated
+		*out = (*in).DeepCopy()
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AkamaiEdgeKVNamespaceStatus.
+func (in *AkamaiEdgeKVNamespaceStatus) DeepCopy() *AkamaiEdgeKVNamespaceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AkamaiEdgeKVNamespaceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AkamaiGroup) DeepCopyInto(out *AkamaiGroup) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AkamaiGroup.
+func (in *AkamaiGroup) DeepCopy() *AkamaiGroup {
+	if in == nil {
+		return nil
+	}
+	out := new(AkamaiGroup)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AkamaiGroup) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AkamaiGroupList) DeepCopyInto(out *AkamaiGroupList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]AkamaiGroup, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AkamaiGroupList.
+func (in *AkamaiGroupList) DeepCopy() *AkamaiGroupList {
+	if in == nil {
+		return nil
+	}
+	out := new(AkamaiGroupList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AkamaiGroupList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AkamaiGroupSpec) DeepCopyInto(out *AkamaiGroupSpec) {
+	*out = *in
+	if in.ContractIDs != nil {
+		in, out := &in.ContractIDs, &out.ContractIDs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AkamaiGroupSpec.
+func (in *AkamaiGroupSpec) DeepCopy() *AkamaiGroupSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AkamaiGroupSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AkamaiGroupStatus) DeepCopyInto(out *AkamaiGroupStatus) {
+	*out = *in
+	if in.LastSynced != nil {
+		in, out := &in.LastSynced, &out.LastSynced
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AkamaiGroupStatus.
+func (in *AkamaiGroupStatus) DeepCopy() *AkamaiGroupStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AkamaiGroupStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AkamaiGtmDomain) DeepCopyInto(out *AkamaiGtmDomain) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AkamaiGtmDomain.
+func (in *AkamaiGtmDomain) DeepCopy() *AkamaiGtmDomain {
+	if in == nil {
+		return nil
+	}
+	out := new(AkamaiGtmDomain)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AkamaiGtmDomain) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AkamaiGtmDomainList) DeepCopyInto(out *AkamaiGtmDomainList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]AkamaiGtmDomain, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AkamaiGtmDomainList.
+func (in *AkamaiGtmDomainList) DeepCopy() *AkamaiGtmDomainList {
+	if in == nil {
+		return nil
+	}
+	out := new(AkamaiGtmDomainList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AkamaiGtmDomainList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AkamaiGtmDomainSpec) DeepCopyInto(out *AkamaiGtmDomainSpec) {
+	*out = *in
+	if in.NotificationEmails != nil {
+		in, out := &in.NotificationEmails, &out.NotificationEmails
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Datacenters != nil {
+		in, out := &in.Datacenters, &out.Datacenters
+		*out = make([]GtmDatacenterSpec, len(*in))
+		copy(*out, *in)
+	}
+	if in.Properties != nil {
+		in, out := &in.Properties, &out.Properties
+		*out = make([]GtmPropertySpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AkamaiGtmDomainSpec.
+func (in *AkamaiGtmDomainSpec) DeepCopy() *AkamaiGtmDomainSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AkamaiGtmDomainSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AkamaiGtmDomainStatus) DeepCopyInto(out *AkamaiGtmDomainStatus) {
+	*out = *in
+	if in.Datacenters != nil {
+		in, out := &in.Datacenters, &out.Datacenters
+		*out = make(map[string]int, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.LastChanges != nil {
+		in, out := &in.LastChanges, &out.LastChanges
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.LastUpdated != nil {
+		in, out := &in.LastUpdated, &out.LastUpdated
+		*out = (*in).DeepCopy()
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AkamaiGtmDomainStatus.
+func (in *AkamaiGtmDomainStatus) DeepCopy() *AkamaiGtmDomainStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AkamaiGtmDomainStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AkamaiNetworkList) DeepCopyInto(out *AkamaiNetworkList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AkamaiNetworkList.
+func (in *AkamaiNetworkList) DeepCopy() *AkamaiNetworkList {
+	if in == nil {
+		return nil
+	}
+	out := new(AkamaiNetworkList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AkamaiNetworkList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AkamaiNetworkListList) DeepCopyInto(out *AkamaiNetworkListList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]AkamaiNetworkList, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AkamaiNetworkListList.
+func (in *AkamaiNetworkListList) DeepCopy() *AkamaiNetworkListList {
+	if in == nil {
+		return nil
+	}
+	out := new(AkamaiNetworkListList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AkamaiNetworkListList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AkamaiNetworkListSpec) DeepCopyInto(out *AkamaiNetworkListSpec) {
+	*out = *in
+	if in.Elements != nil {
+		in, out := &in.Elements, &out.Elements
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Activation != nil {
+		in, out := &in.Activation, &out.Activation
+		*out = new(NetworkListActivationSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AkamaiNetworkListSpec.
+func (in *AkamaiNetworkListSpec) DeepCopy() *AkamaiNetworkListSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AkamaiNetworkListSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AkamaiNetworkListStatus) DeepCopyInto(out *AkamaiNetworkListStatus) {
+	*out = *in
+	if in.Staging != nil {
+		in, out := &in.Staging, &out.Staging
+		*out = new(NetworkListActivationStatus)
+		**out = **in
+	}
+	if in.Production != nil {
+		in, out := &in.Production, &out.Production
+		*out = new(NetworkListActivationStatus)
+		**out = **in
+	}
+	if in.LastUpdated != nil {
+		in, out := &in.LastUpdated, &out.LastUpdated
+		*out = (*in).DeepCopy()
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AkamaiNetworkListStatus.
+func (in *AkamaiNetworkListStatus) DeepCopy() *AkamaiNetworkListStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AkamaiNetworkListStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AkamaiProduct) DeepCopyInto(out *AkamaiProduct) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AkamaiProduct.
+func (in *AkamaiProduct) DeepCopy() *AkamaiProduct {
+	if in == nil {
+		return nil
+	}
+	out := new(AkamaiProduct)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AkamaiProperty) DeepCopyInto(out *AkamaiProperty) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AkamaiProperty.
+func (in *AkamaiProperty) DeepCopy() *AkamaiProperty {
+	if in == nil {
+		return nil
+	}
+	out := new(AkamaiProperty)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AkamaiProperty) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AkamaiPropertyActivation) DeepCopyInto(out *AkamaiPropertyActivation) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AkamaiPropertyActivation.
+func (in *AkamaiPropertyActivation) DeepCopy() *AkamaiPropertyActivation {
+	if in == nil {
+		return nil
+	}
+	out := new(AkamaiPropertyActivation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AkamaiPropertyActivation) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AkamaiPropertyActivationList) DeepCopyInto(out *AkamaiPropertyActivationList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]AkamaiPropertyActivation, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AkamaiPropertyActivationList.
+func (in *AkamaiPropertyActivationList) DeepCopy() *AkamaiPropertyActivationList {
+	if in == nil {
+		return nil
+	}
+	out := new(AkamaiPropertyActivationList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AkamaiPropertyActivationList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AkamaiPropertyActivationSpec) DeepCopyInto(out *AkamaiPropertyActivationSpec) {
+	*out = *in
+	if in.NotifyEmails != nil {
+		in, out := &in.NotifyEmails, &out.NotifyEmails
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AcknowledgeWarnings != nil {
+		in, out := &in.AcknowledgeWarnings, &out.AcknowledgeWarnings
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AkamaiPropertyActivationSpec.
+func (in *AkamaiPropertyActivationSpec) DeepCopy() *AkamaiPropertyActivationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AkamaiPropertyActivationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AkamaiPropertyActivationStatus) DeepCopyInto(out *AkamaiPropertyActivationStatus) {
+	*out = *in
+	if in.SubmittedAt != nil {
+		in, out := &in.SubmittedAt, &out.SubmittedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.History != nil {
+		in, out := &in.History, &out.History
+		*out = make([]ActivationHistoryEntry, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PendingWarnings != nil {
+		in, out := &in.PendingWarnings, &out.PendingWarnings
+		*out = make([]PendingWarning, len(*in))
+		copy(*out, *in)
+	}
+	if in.LastUpdated != nil {
+		in, out := &in.LastUpdated, &out.LastUpdated
+		*out = (*in).DeepCopy()
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AkamaiPropertyActivationStatus.
+func (in *AkamaiPropertyActivationStatus) DeepCopy() *AkamaiPropertyActivationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AkamaiPropertyActivationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AkamaiPropertyInclude) DeepCopyInto(out *AkamaiPropertyInclude) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AkamaiPropertyInclude.
+func (in *AkamaiPropertyInclude) DeepCopy() *AkamaiPropertyInclude {
+	if in == nil {
+		return nil
+	}
+	out := new(AkamaiPropertyInclude)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AkamaiPropertyInclude) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AkamaiPropertyIncludeList) DeepCopyInto(out *AkamaiPropertyIncludeList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]AkamaiPropertyInclude, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AkamaiPropertyIncludeList.
+func (in *AkamaiPropertyIncludeList) DeepCopy() *AkamaiPropertyIncludeList {
+	if in == nil {
+		return nil
+	}
+	out := new(AkamaiPropertyIncludeList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AkamaiPropertyIncludeList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AkamaiPropertyIncludeSpec) DeepCopyInto(out *AkamaiPropertyIncludeSpec) {
+	*out = *in
+	if in.CredentialsRef != nil {
+		in, out := &in.CredentialsRef, &out.CredentialsRef
+		*out = new(CredentialsReference)
+		**out = **in
+	}
+	in.Rules.DeepCopyInto(&out.Rules)
+	if in.Activation != nil {
+		in, out := &in.Activation, &out.Activation
+		*out = new(IncludeActivationSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AkamaiPropertyIncludeSpec.
+func (in *AkamaiPropertyIncludeSpec) DeepCopy() *AkamaiPropertyIncludeSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AkamaiPropertyIncludeSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AkamaiPropertyIncludeStatus) DeepCopyInto(out *AkamaiPropertyIncludeStatus) {
+	*out = *in
+	if in.Staging != nil {
+		in, out := &in.Staging, &out.Staging
+		*out = new(IncludeNetworkStatus)
+		**out = **in
+	}
+	if in.Production != nil {
+		in, out := &in.Production, &out.Production
+		*out = new(IncludeNetworkStatus)
+		**out = **in
+	}
+	if in.Validation != nil {
+		in, out := &in.Validation, &out.Validation
+		*out = new(ValidationStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LastUpdated != nil {
+		in, out := &in.LastUpdated, &out.LastUpdated
+		*out = (*in).DeepCopy()
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AkamaiPropertyIncludeStatus.
+func (in *AkamaiPropertyIncludeStatus) DeepCopy() *AkamaiPropertyIncludeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AkamaiPropertyIncludeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AkamaiPropertyList) DeepCopyInto(out *AkamaiPropertyList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]AkamaiProperty, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AkamaiPropertyList.
+func (in *AkamaiPropertyList) DeepCopy() *AkamaiPropertyList {
+	if in == nil {
+		return nil
+	}
+	out := new(AkamaiPropertyList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AkamaiPropertyList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AkamaiPropertySpec) DeepCopyInto(out *AkamaiPropertySpec) {
+	*out = *in
+	if in.Metadata != nil {
+		in, out := &in.Metadata, &out.Metadata
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Hostnames != nil {
+		in, out := &in.Hostnames, &out.Hostnames
+		*out = make([]Hostname, len(*in))
+		copy(*out, *in)
+	}
+	if in.Rules != nil {
+		in, out := &in.Rules, &out.Rules
+		*out = new(PropertyRules)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Renderer != nil {
+		in, out := &in.Renderer, &out.Renderer
+		*out = new(RendererSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RulesFrom != nil {
+		in, out := &in.RulesFrom, &out.RulesFrom
+		*out = new(RulesSource)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RulesPatches != nil {
+		in, out := &in.RulesPatches, &out.RulesPatches
+		*out = make([]RulesPatch, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.EdgeHostname != nil {
+		in, out := &in.EdgeHostname, &out.EdgeHostname
+		*out = new(EdgeHostnameSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Activation != nil {
+		in, out := &in.Activation, &out.Activation
+		*out = new(ActivationSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Variables != nil {
+		in, out := &in.Variables, &out.Variables
+		*out = make([]PropertyVariable, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.SyncLabels != nil {
+		in, out := &in.SyncLabels, &out.SyncLabels
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.EdgeEndpoints != nil {
+		in, out := &in.EdgeEndpoints, &out.EdgeEndpoints
+		*out = new(EdgeEndpointsSpec)
+		**out = **in
+	}
+	if in.DNS != nil {
+		in, out := &in.DNS, &out.DNS
+		*out = new(PropertyDNSSpec)
+		**out = **in
+	}
+	if in.CredentialsRef != nil {
+		in, out := &in.CredentialsRef, &out.CredentialsRef
+		*out = new(CredentialsReference)
+		**out = **in
+	}
+	if in.ProgressDeadlineSeconds != nil {
+		in, out := &in.ProgressDeadlineSeconds, &out.ProgressDeadlineSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Preview != nil {
+		in, out := &in.Preview, &out.Preview
+		*out = new(PreviewSpec)
+		**out = **in
+	}
+	if in.PreserveBehaviors != nil {
+		in, out := &in.PreserveBehaviors, &out.PreserveBehaviors
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ExpiresAt != nil {
+		in, out := &in.ExpiresAt, &out.ExpiresAt
+		*out = (*in).DeepCopy()
+	}
+	if in.Origins != nil {
+		in, out := &in.Origins, &out.Origins
+		*out = new(OriginsSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TTL != nil {
+		in, out := &in.TTL, &out.TTL
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.Manage != nil {
+		in, out := &in.Manage, &out.Manage
+		*out = new(ManageSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Origin != nil {
+		in, out := &in.Origin, &out.Origin
+		*out = new(OriginServer)
+		**out = **in
+	}
+	if in.Caching != nil {
+		in, out := &in.Caching, &out.Caching
+		*out = new(CachingSpec)
+		**out = **in
+	}
+	if in.CPCode != nil {
+		in, out := &in.CPCode, &out.CPCode
+		*out = new(CPCodeSpec)
+		**out = **in
+	}
+	if in.Redirect != nil {
+		in, out := &in.Redirect, &out.Redirect
+		*out = new(RedirectSpec)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AkamaiPropertySpec.
+func (in *AkamaiPropertySpec) DeepCopy() *AkamaiPropertySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AkamaiPropertySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AkamaiPropertyStatus) DeepCopyInto(out *AkamaiPropertyStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Validation != nil {
+		in, out := &in.Validation, &out.Validation
+		*out = new(ValidationStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HostnameCertificates != nil {
+		in, out := &in.HostnameCertificates, &out.HostnameCertificates
+		*out = make([]HostnameCertificate, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PendingWarnings != nil {
+		in, out := &in.PendingWarnings, &out.PendingWarnings
+		*out = make([]PendingWarning, len(*in))
+		copy(*out, *in)
+	}
+	if in.LastAPIError != nil {
+		in, out := &in.LastAPIError, &out.LastAPIError
+		*out = new(APIErrorStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Checkpoint != nil {
+		in, out := &in.Checkpoint, &out.Checkpoint
+		*out = new(WriteCheckpoint)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PendingGuardrails != nil {
+		in, out := &in.PendingGuardrails, &out.PendingGuardrails
+		*out = make([]PendingGuardrail, len(*in))
+		copy(*out, *in)
+	}
+	if in.PreservedBehaviors != nil {
+		in, out := &in.PreservedBehaviors, &out.PreservedBehaviors
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Drift != nil {
+		in, out := &in.Drift, &out.Drift
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PlannedActivations != nil {
+		in, out := &in.PlannedActivations, &out.PlannedActivations
+		*out = make([]PlannedActivation, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ValueSources != nil {
+		in, out := &in.ValueSources, &out.ValueSources
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.CPCodes != nil {
+		in, out := &in.CPCodes, &out.CPCodes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.LastAppliedRules != nil {
+		in, out := &in.LastAppliedRules, &out.LastAppliedRules
+		*out = new(AppliedRules)
+		**out = **in
+	}
+	if in.LastRulesDiff != nil {
+		in, out := &in.LastRulesDiff, &out.LastRulesDiff
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PendingChanges != nil {
+		in, out := &in.PendingChanges, &out.PendingChanges
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Promotion != nil {
+		in, out := &in.Promotion, &out.Promotion
+		*out = new(PromotionStatus)
+		**out = **in
+	}
+	if in.ManagedHostnames != nil {
+		in, out := &in.ManagedHostnames, &out.ManagedHostnames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.OwnedEdgeHostnames != nil {
+		in, out := &in.OwnedEdgeHostnames, &out.OwnedEdgeHostnames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ExpiresAt != nil {
+		in, out := &in.ExpiresAt, &out.ExpiresAt
+		*out = (*in).DeepCopy()
+	}
+	if in.LastUpdated != nil {
+		in, out := &in.LastUpdated, &out.LastUpdated
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AkamaiPropertyStatus.
+func (in *AkamaiPropertyStatus) DeepCopy() *AkamaiPropertyStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AkamaiPropertyStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AkamaiProviderConfig) DeepCopyInto(out *AkamaiProviderConfig) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AkamaiProviderConfig.
+func (in *AkamaiProviderConfig) DeepCopy() *AkamaiProviderConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(AkamaiProviderConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AkamaiProviderConfig) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AkamaiProviderConfigList) DeepCopyInto(out *AkamaiProviderConfigList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]AkamaiProviderConfig, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AkamaiProviderConfigList.
+func (in *AkamaiProviderConfigList) DeepCopy() *AkamaiProviderConfigList {
+	if in == nil {
+		return nil
+	}
+	out := new(AkamaiProviderConfigList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AkamaiProviderConfigList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AkamaiProviderConfigSpec) DeepCopyInto(out *AkamaiProviderConfigSpec) {
+	*out = *in
+	if in.CredentialsRef != nil {
+		in, out := &in.CredentialsRef, &out.CredentialsRef
+		*out = new(CredentialsReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AkamaiProviderConfigSpec.
+func (in *AkamaiProviderConfigSpec) DeepCopy() *AkamaiProviderConfigSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AkamaiProviderConfigSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AkamaiPurge) DeepCopyInto(out *AkamaiPurge) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AkamaiPurge.
+func (in *AkamaiPurge) DeepCopy() *AkamaiPurge {
+	if in == nil {
+		return nil
+	}
+	out := new(AkamaiPurge)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AkamaiPurge) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AkamaiPurgeList) DeepCopyInto(out *AkamaiPurgeList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]AkamaiPurge, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AkamaiPurgeList.
+func (in *AkamaiPurgeList) DeepCopy() *AkamaiPurgeList {
+	if in == nil {
+		return nil
+	}
+	out := new(AkamaiPurgeList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AkamaiPurgeList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AkamaiPurgeSpec) DeepCopyInto(out *AkamaiPurgeSpec) {
+	*out = *in
+	if in.URLs != nil {
+		in, out := &in.URLs, &out.URLs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.CPCodes != nil {
+		in, out := &in.CPCodes, &out.CPCodes
+		*out = make([]int64, len(*in))
+		copy(*out, *in)
+	}
+	if in.Tags != nil {
+		in, out := &in.Tags, &out.Tags
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AkamaiPurgeSpec.
+func (in *AkamaiPurgeSpec) DeepCopy() *AkamaiPurgeSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AkamaiPurgeSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AkamaiPurgeStatus) DeepCopyInto(out *AkamaiPurgeStatus) {
+	*out = *in
+	if in.PurgeIDs != nil {
+		in, out := &in.PurgeIDs, &out.PurgeIDs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.SubmittedAt != nil {
+		in, out := &in.SubmittedAt, &out.SubmittedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletedAt != nil {
+		in, out := &in.CompletedAt, &out.CompletedAt
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AkamaiPurgeStatus.
+func (in *AkamaiPurgeStatus) DeepCopy() *AkamaiPurgeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AkamaiPurgeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AkamaiRuleValidation) DeepCopyInto(out *AkamaiRuleValidation) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AkamaiRuleValidation.
+func (in *AkamaiRuleValidation) DeepCopy() *AkamaiRuleValidation {
+	if in == nil {
+		return nil
+	}
+	out := new(AkamaiRuleValidation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AkamaiRuleValidation) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AkamaiRuleValidationList) DeepCopyInto(out *AkamaiRuleValidationList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]AkamaiRuleValidation, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AkamaiRuleValidationList.
+func (in *AkamaiRuleValidationList) DeepCopy() *AkamaiRuleValidationList {
+	if in == nil {
+		return nil
+	}
+	out := new(AkamaiRuleValidationList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AkamaiRuleValidationList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AkamaiRuleValidationSpec) DeepCopyInto(out *AkamaiRuleValidationSpec) {
+	*out = *in
+	in.Rules.DeepCopyInto(&out.Rules)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AkamaiRuleValidationSpec.
+func (in *AkamaiRuleValidationSpec) DeepCopy() *AkamaiRuleValidationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AkamaiRuleValidationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AkamaiRuleValidationStatus) DeepCopyInto(out *AkamaiRuleValidationStatus) {
+	*out = *in
+	if in.Errors != nil {
+		in, out := &in.Errors, &out.Errors
+		*out = make([]ValidationWarning, len(*in))
+		copy(*out, *in)
+	}
+	if in.Warnings != nil {
+		in, out := &in.Warnings, &out.Warnings
+		*out = make([]ValidationWarning, len(*in))
+		copy(*out, *in)
+	}
+	if in.LastValidated != nil {
+		in, out := &in.LastValidated, &out.LastValidated
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AkamaiRuleValidationStatus.
+func (in *AkamaiRuleValidationStatus) DeepCopy() *AkamaiRuleValidationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AkamaiRuleValidationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AppSecActivationSpec) DeepCopyInto(out *AppSecActivationSpec) {
+	*out = *in
+	if in.NotificationEmails != nil {
+		in, out := &in.NotificationEmails, &out.NotificationEmails
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AppSecActivationSpec.
+func (in *AppSecActivationSpec) DeepCopy() *AppSecActivationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AppSecActivationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AppSecActivationStatus) DeepCopyInto(out *AppSecActivationStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AppSecActivationStatus.
+func (in *AppSecActivationStatus) DeepCopy() *AppSecActivationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AppSecActivationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AppSecMatchTargetSpec) DeepCopyInto(out *AppSecMatchTargetSpec) {
+	*out = *in
+	if in.Hostnames != nil {
+		in, out := &in.Hostnames, &out.Hostnames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Paths != nil {
+		in, out := &in.Paths, &out.Paths
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AppSecMatchTargetSpec.
+func (in *AppSecMatchTargetSpec) DeepCopy() *AppSecMatchTargetSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AppSecMatchTargetSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AppSecPolicySpec) DeepCopyInto(out *AppSecPolicySpec) {
+	*out = *in
+	if in.RuleActions != nil {
+		in, out := &in.RuleActions, &out.RuleActions
+		*out = make([]AppSecRuleAction, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AppSecPolicySpec.
+func (in *AppSecPolicySpec) DeepCopy() *AppSecPolicySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AppSecPolicySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AppSecRatePolicyAction) DeepCopyInto(out *AppSecRatePolicyAction) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AppSecRatePolicyAction.
+func (in *AppSecRatePolicyAction) DeepCopy() *AppSecRatePolicyAction {
+	if in == nil {
+		return nil
+	}
+	out := new(AppSecRatePolicyAction)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AppSecRatePolicySpec) DeepCopyInto(out *AppSecRatePolicySpec) {
+	*out = *in
+	if in.Hostnames != nil {
+		in, out := &in.Hostnames, &out.Hostnames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Paths != nil {
+		in, out := &in.Paths, &out.Paths
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Actions != nil {
+		in, out := &in.Actions, &out.Actions
+		*out = make([]AppSecRatePolicyAction, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AppSecRatePolicySpec.
+func (in *AppSecRatePolicySpec) DeepCopy() *AppSecRatePolicySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AppSecRatePolicySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AppSecRuleAction) DeepCopyInto(out *AppSecRuleAction) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AppSecRuleAction.
+func (in *AppSecRuleAction) DeepCopy() *AppSecRuleAction {
+	if in == nil {
+		return nil
+	}
+	out := new(AppSecRuleAction)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AppliedRules) DeepCopyInto(out *AppliedRules) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AppliedRules.
+func (in *AppliedRules) DeepCopy() *AppliedRules {
+	if in == nil {
+		return nil
+	}
+	out := new(AppliedRules)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CPCodeSpec) DeepCopyInto(out *CPCodeSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CPCodeSpec.
+func (in *CPCodeSpec) DeepCopy() *CPCodeSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CPCodeSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CachingSpec) DeepCopyInto(out *CachingSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CachingSpec.
+func (in *CachingSpec) DeepCopy() *CachingSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CachingSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertificateChangeStatus) DeepCopyInto(out *CertificateChangeStatus) {
+	*out = *in
+	if in.RequiredInput != nil {
+		in, out := &in.RequiredInput, &out.RequiredInput
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificateChangeStatus.
+func (in *CertificateChangeStatus) DeepCopy() *CertificateChangeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CertificateChangeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertificateContact) DeepCopyInto(out *CertificateContact) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificateContact.
+func (in *CertificateContact) DeepCopy() *CertificateContact {
+	if in == nil {
+		return nil
+	}
+	out := new(CertificateContact)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertificateDeploymentStatus) DeepCopyInto(out *CertificateDeploymentStatus) {
+	*out = *in
+	if in.DNSNames != nil {
+		in, out := &in.DNSNames, &out.DNSNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificateDeploymentStatus.
+func (in *CertificateDeploymentStatus) DeepCopy() *CertificateDeploymentStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CertificateDeploymentStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertificateOrganization) DeepCopyInto(out *CertificateOrganization) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificateOrganization.
+func (in *CertificateOrganization) DeepCopy() *CertificateOrganization {
+	if in == nil {
+		return nil
+	}
+	out := new(CertificateOrganization)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClientListActivationSpec) DeepCopyInto(out *ClientListActivationSpec) {
+	*out = *in
+	if in.NotificationEmails != nil {
+		in, out := &in.NotificationEmails, &out.NotificationEmails
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClientListActivationSpec.
+func (in *ClientListActivationSpec) DeepCopy() *ClientListActivationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ClientListActivationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClientListActivationStatus) DeepCopyInto(out *ClientListActivationStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClientListActivationStatus.
+func (in *ClientListActivationStatus) DeepCopy() *ClientListActivationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ClientListActivationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClientListItem) DeepCopyInto(out *ClientListItem) {
+	*out = *in
+	if in.Tags != nil {
+		in, out := &in.Tags, &out.Tags
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClientListItem.
+func (in *ClientListItem) DeepCopy() *ClientListItem {
+	if in == nil {
+		return nil
+	}
+	out := new(ClientListItem)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CloudletPolicyActivationSpec) DeepCopyInto(out *CloudletPolicyActivationSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloudletPolicyActivationSpec.
+func (in *CloudletPolicyActivationSpec) DeepCopy() *CloudletPolicyActivationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CloudletPolicyActivationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CloudletPolicyActivationStatus) DeepCopyInto(out *CloudletPolicyActivationStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloudletPolicyActivationStatus.
+func (in *CloudletPolicyActivationStatus) DeepCopy() *CloudletPolicyActivationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CloudletPolicyActivationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigMapReference) DeepCopyInto(out *ConfigMapReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigMapReference.
+func (in *ConfigMapReference) DeepCopy() *ConfigMapReference {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigMapReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CredentialsReference) DeepCopyInto(out *CredentialsReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CredentialsReference.
+func (in *CredentialsReference) DeepCopy() *CredentialsReference {
+	if in == nil {
+		return nil
+	}
+	out := new(CredentialsReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DNSRecord) DeepCopyInto(out *DNSRecord) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DNSRecord.
+func (in *DNSRecord) DeepCopy() *DNSRecord {
+	if in == nil {
+		return nil
+	}
+	out := new(DNSRecord)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataStreamDestination) DeepCopyInto(out *DataStreamDestination) {
+	*out = *in
+	if in.SecretRef != nil {
+		in, out := &in.SecretRef, &out.SecretRef
+		*out = new(SecretReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataStreamDestination.
+func (in *DataStreamDestination) DeepCopy() *DataStreamDestination {
+	if in == nil {
+		return nil
+	}
+	out := new(DataStreamDestination)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EdgeEndpointsSpec) DeepCopyInto(out *EdgeEndpointsSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EdgeEndpointsSpec.
+func (in *EdgeEndpointsSpec) DeepCopy() *EdgeEndpointsSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(EdgeEndpointsSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EdgeHostnameCertificate) DeepCopyInto(out *EdgeHostnameCertificate) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EdgeHostnameCertificate.
+func (in *EdgeHostnameCertificate) DeepCopy() *EdgeHostnameCertificate {
+	if in == nil {
+		return nil
+	}
+	out := new(EdgeHostnameCertificate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EdgeHostnameSpec) DeepCopyInto(out *EdgeHostnameSpec) {
+	*out = *in
+	if in.UseCases != nil {
+		in, out := &in.UseCases, &out.UseCases
+		*out = make([]EdgeHostnameUseCase, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EdgeHostnameSpec.
+func (in *EdgeHostnameSpec) DeepCopy() *EdgeHostnameSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(EdgeHostnameSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EdgeHostnameUseCase) DeepCopyInto(out *EdgeHostnameUseCase) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EdgeHostnameUseCase.
+func (in *EdgeHostnameUseCase) DeepCopy() *EdgeHostnameUseCase {
+	if in == nil {
+		return nil
+	}
+	out := new(EdgeHostnameUseCase)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EdgeKVNamespaceNetworkStatus) DeepCopyInto(out *EdgeKVNamespaceNetworkStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EdgeKVNamespaceNetworkStatus.
+func (in *EdgeKVNamespaceNetworkStatus) DeepCopy() *EdgeKVNamespaceNetworkStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(EdgeKVNamespaceNetworkStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EdgeKVSeedSpec) DeepCopyInto(out *EdgeKVSeedSpec) {
+	*out = *in
+	if in.ConfigMapRef != nil {
+		in, out := &in.ConfigMapRef, &out.ConfigMapRef
+		*out = new(ConfigMapReference)
+		**out = **in
+	}
+	if in.SecretRef != nil {
+		in, out := &in.SecretRef, &out.SecretRef
+		*out = new(SecretReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EdgeKVSeedSpec.
+func (in *EdgeKVSeedSpec) DeepCopy() *EdgeKVSeedSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(EdgeKVSeedSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GtmDatacenterSpec) DeepCopyInto(out *GtmDatacenterSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GtmDatacenterSpec.
+func (in *GtmDatacenterSpec) DeepCopy() *GtmDatacenterSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(GtmDatacenterSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GtmLivenessTestSpec) DeepCopyInto(out *GtmLivenessTestSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GtmLivenessTestSpec.
+func (in *GtmLivenessTestSpec) DeepCopy() *GtmLivenessTestSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(GtmLivenessTestSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GtmPropertySpec) DeepCopyInto(out *GtmPropertySpec) {
+	*out = *in
+	if in.TrafficTargets != nil {
+		in, out := &in.TrafficTargets, &out.TrafficTargets
+		*out = make([]GtmTrafficTargetSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LivenessTest != nil {
+		in, out := &in.LivenessTest, &out.LivenessTest
+		*out = new(GtmLivenessTestSpec)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GtmPropertySpec.
+func (in *GtmPropertySpec) DeepCopy() *GtmPropertySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(GtmPropertySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GtmTrafficTargetSpec) DeepCopyInto(out *GtmTrafficTargetSpec) {
+	*out = *in
+	if in.Servers != nil {
+		in, out := &in.Servers, &out.Servers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GtmTrafficTargetSpec.
+func (in *GtmTrafficTargetSpec) DeepCopy() *GtmTrafficTargetSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(GtmTrafficTargetSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Hostname) DeepCopyInto(out *Hostname) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Hostname.
+func (in *Hostname) DeepCopy() *Hostname {
+	if in == nil {
+		return nil
+	}
+	out := new(Hostname)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HostnameCertificate) DeepCopyInto(out *HostnameCertificate) {
+	*out = *in
+	if in.ValidationRecords != nil {
+		in, out := &in.ValidationRecords, &out.ValidationRecords
+		*out = make([]DNSRecord, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostnameCertificate.
+func (in *HostnameCertificate) DeepCopy() *HostnameCertificate {
+	if in == nil {
+		return nil
+	}
+	out := new(HostnameCertificate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IncludeActivationSpec) DeepCopyInto(out *IncludeActivationSpec) {
+	*out = *in
+	if in.Networks != nil {
+		in, out := &in.Networks, &out.Networks
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.NotifyEmails != nil {
+		in, out := &in.NotifyEmails, &out.NotifyEmails
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IncludeActivationSpec.
+func (in *IncludeActivationSpec) DeepCopy() *IncludeActivationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(IncludeActivationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IncludeNetworkStatus) DeepCopyInto(out *IncludeNetworkStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IncludeNetworkStatus.
+func (in *IncludeNetworkStatus) DeepCopy() *IncludeNetworkStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(IncludeNetworkStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManageSpec) DeepCopyInto(out *ManageSpec) {
+	*out = *in
+	if in.Rules != nil {
+		in, out := &in.Rules, &out.Rules
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Hostnames != nil {
+		in, out := &in.Hostnames, &out.Hostnames
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Activation != nil {
+		in, out := &in.Activation, &out.Activation
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManageSpec.
+func (in *ManageSpec) DeepCopy() *ManageSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ManageSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkListActivationSpec) DeepCopyInto(out *NetworkListActivationSpec) {
+	*out = *in
+	if in.NotificationEmails != nil {
+		in, out := &in.NotificationEmails, &out.NotificationEmails
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkListActivationSpec.
+func (in *NetworkListActivationSpec) DeepCopy() *NetworkListActivationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkListActivationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkListActivationStatus) DeepCopyInto(out *NetworkListActivationStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkListActivationStatus.
+func (in *NetworkListActivationStatus) DeepCopy() *NetworkListActivationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkListActivationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OriginServer) DeepCopyInto(out *OriginServer) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OriginServer.
+func (in *OriginServer) DeepCopy() *OriginServer {
+	if in == nil {
+		return nil
+	}
+	out := new(OriginServer)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OriginsSpec) DeepCopyInto(out *OriginsSpec) {
+	*out = *in
+	if in.Origins != nil {
+		in, out := &in.Origins, &out.Origins
+		*out = make([]WeightedOrigin, len(*in))
+		copy(*out, *in)
+	}
+	if in.Failover != nil {
+		in, out := &in.Failover, &out.Failover
+		*out = new(OriginServer)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OriginsSpec.
+func (in *OriginsSpec) DeepCopy() *OriginsSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OriginsSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PendingGuardrail) DeepCopyInto(out *PendingGuardrail) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PendingGuardrail.
+func (in *PendingGuardrail) DeepCopy() *PendingGuardrail {
+	if in == nil {
+		return nil
+	}
+	out := new(PendingGuardrail)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PendingWarning) DeepCopyInto(out *PendingWarning) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PendingWarning.
+func (in *PendingWarning) DeepCopy() *PendingWarning {
+	if in == nil {
+		return nil
+	}
+	out := new(PendingWarning)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PlannedActivation) DeepCopyInto(out *PlannedActivation) {
+	*out = *in
+	if in.Since != nil {
+		in, out := &in.Since, &out.Since
+		*out = (*in).DeepCopy()
+	}
+	if in.ScheduledAt != nil {
+		in, out := &in.ScheduledAt, &out.ScheduledAt
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PlannedActivation.
+func (in *PlannedActivation) DeepCopy() *PlannedActivation {
+	if in == nil {
+		return nil
+	}
+	out := new(PlannedActivation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PreviewSpec) DeepCopyInto(out *PreviewSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PreviewSpec.
+func (in *PreviewSpec) DeepCopy() *PreviewSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PreviewSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PromotionStatus) DeepCopyInto(out *PromotionStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PromotionStatus.
+func (in *PromotionStatus) DeepCopy() *PromotionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PromotionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PropertyDNSSpec) DeepCopyInto(out *PropertyDNSSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PropertyDNSSpec.
+func (in *PropertyDNSSpec) DeepCopy() *PropertyDNSSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PropertyDNSSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PropertyRules) DeepCopyInto(out *PropertyRules) {
+	*out = *in
+	if in.Criteria != nil {
+		in, out := &in.Criteria, &out.Criteria
+		*out = make([]RuleCriteria, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Behaviors != nil {
+		in, out := &in.Behaviors, &out.Behaviors
+		*out = make([]RuleBehavior, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Children != nil {
+		in, out := &in.Children, &out.Children
+		*out = make([]runtime.RawExtension, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Variables != nil {
+		in, out := &in.Variables, &out.Variables
+		*out = make([]RuleVariable, len(*in))
+		copy(*out, *in)
+	}
+	in.Options.DeepCopyInto(&out.Options)
+	in.CustomOverride.DeepCopyInto(&out.CustomOverride)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PropertyRules.
+func (in *PropertyRules) DeepCopy() *PropertyRules {
+	if in == nil {
+		return nil
+	}
+	out := new(PropertyRules)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PropertyVariable) DeepCopyInto(out *PropertyVariable) {
+	*out = *in
+	if in.ValueFrom != nil {
+		in, out := &in.ValueFrom, &out.ValueFrom
+		*out = new(VariableValueSource)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PropertyVariable.
+func (in *PropertyVariable) DeepCopy() *PropertyVariable {
+	if in == nil {
+		return nil
+	}
+	out := new(PropertyVariable)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RedirectSpec) DeepCopyInto(out *RedirectSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RedirectSpec.
+func (in *RedirectSpec) DeepCopy() *RedirectSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RedirectSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RendererSpec) DeepCopyInto(out *RendererSpec) {
+	*out = *in
+	if in.ConfigMapRef != nil {
+		in, out := &in.ConfigMapRef, &out.ConfigMapRef
+		*out = new(ConfigMapReference)
+		**out = **in
+	}
+	if in.Values != nil {
+		in, out := &in.Values, &out.Values
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RendererSpec.
+func (in *RendererSpec) DeepCopy() *RendererSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RendererSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RuleBehavior) DeepCopyInto(out *RuleBehavior) {
+	*out = *in
+	in.Options.DeepCopyInto(&out.Options)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RuleBehavior.
+func (in *RuleBehavior) DeepCopy() *RuleBehavior {
+	if in == nil {
+		return nil
+	}
+	out := new(RuleBehavior)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RuleCriteria) DeepCopyInto(out *RuleCriteria) {
+	*out = *in
+	in.Options.DeepCopyInto(&out.Options)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RuleCriteria.
+func (in *RuleCriteria) DeepCopy() *RuleCriteria {
+	if in == nil {
+		return nil
+	}
+	out := new(RuleCriteria)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RuleVariable) DeepCopyInto(out *RuleVariable) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RuleVariable.
+func (in *RuleVariable) DeepCopy() *RuleVariable {
+	if in == nil {
+		return nil
+	}
+	out := new(RuleVariable)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RulesPatch) DeepCopyInto(out *RulesPatch) {
+	*out = *in
+	in.Patch.DeepCopyInto(&out.Patch)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RulesPatch.
+func (in *RulesPatch) DeepCopy() *RulesPatch {
+	if in == nil {
+		return nil
+	}
+	out := new(RulesPatch)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RulesSource) DeepCopyInto(out *RulesSource) {
+	*out = *in
+	if in.SecretRef != nil {
+		in, out := &in.SecretRef, &out.SecretRef
+		*out = new(SecretReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RulesSource.
+func (in *RulesSource) DeepCopy() *RulesSource {
+	if in == nil {
+		return nil
+	}
+	out := new(RulesSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretKeyReference) DeepCopyInto(out *SecretKeyReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretKeyReference.
+func (in *SecretKeyReference) DeepCopy() *SecretKeyReference {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretKeyReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretReference) DeepCopyInto(out *SecretReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretReference.
+func (in *SecretReference) DeepCopy() *SecretReference {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ValidationStatus) DeepCopyInto(out *ValidationStatus) {
+	*out = *in
+	if in.Warnings != nil {
+		in, out := &in.Warnings, &out.Warnings
+		*out = make([]ValidationWarning, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ValidationStatus.
+func (in *ValidationStatus) DeepCopy() *ValidationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ValidationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ValidationWarning) DeepCopyInto(out *ValidationWarning) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ValidationWarning.
+func (in *ValidationWarning) DeepCopy() *ValidationWarning {
+	if in == nil {
+		return nil
+	}
+	out := new(ValidationWarning)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VariableValueSource) DeepCopyInto(out *VariableValueSource) {
+	*out = *in
+	if in.SecretKeyRef != nil {
+		in, out := &in.SecretKeyRef, &out.SecretKeyRef
+		*out = new(SecretKeyReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VariableValueSource.
+func (in *VariableValueSource) DeepCopy() *VariableValueSource {
+	if in == nil {
+		return nil
+	}
+	out := new(VariableValueSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WeightedOrigin) DeepCopyInto(out *WeightedOrigin) {
+	*out = *in
+	out.OriginServer = in.OriginServer
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WeightedOrigin.
+func (in *WeightedOrigin) DeepCopy() *WeightedOrigin {
+	if in == nil {
+		return nil
+	}
+	out := new(WeightedOrigin)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WriteCheckpoint) DeepCopyInto(out *WriteCheckpoint) {
+	*out = *in
+	if in.Completed != nil {
+		in, out := &in.Completed, &out.Completed
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.StartedAt.DeepCopyInto(&out.StartedAt)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WriteCheckpoint.
+func (in *WriteCheckpoint) DeepCopy() *WriteCheckpoint {
+	if in == nil {
+		return nil
+	}
+	out := new(WriteCheckpoint)
 	in.DeepCopyInto(out)
 	return out
 }
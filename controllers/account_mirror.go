@@ -0,0 +1,267 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+	"github.com/mmz-srf/akamai-operator/pkg/akamai"
+)
+
+// AccountMirror periodically mirrors the contracts, groups and products available to the
+// operator's API client into read-only AkamaiContract and AkamaiGroup resources
+type AccountMirror struct {
+	client.Client
+	AkamaiClient *akamai.Client
+
+	// Credentials selects the credential source the Akamai client is created with
+	Credentials akamai.Credentials
+
+	// Interval is the time between two refreshes
+	Interval time.Duration
+}
+
+//+kubebuilder:rbac:groups=akamai.com,resources=akamaicontracts,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=akamai.com,resources=akamaicontracts/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=akamai.com,resources=akamaigroups,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=akamai.com,resources=akamaigroups/status,verbs=get;update;patch
+
+// Start runs the mirror loop until the context is cancelled
+func (m *AccountMirror) Start(ctx context.Context) error {
+	logger := log.FromContext(ctx).WithName("account-mirror")
+	ctx = log.IntoContext(ctx, logger)
+
+	interval := m.Interval
+	if interval <= 0 {
+		interval = time.Hour
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := m.refresh(ctx); err != nil {
+			logger.Error(err, "Failed to refresh Akamai account mirror")
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// NeedLeaderElection ensures only the leader refreshes the mirror
+func (m *AccountMirror) NeedLeaderElection() bool {
+	return true
+}
+
+// refresh synchronizes the mirror resources with the Akamai account
+func (m *AccountMirror) refresh(ctx context.Context) error {
+	logger := log.FromContext(ctx)
+
+	if m.AkamaiClient == nil {
+		akamaiClient, err := akamai.NewClient(m.Credentials)
+		if err != nil {
+			return fmt.Errorf("failed to create Akamai client: %w", err)
+		}
+		m.AkamaiClient = akamaiClient
+	}
+
+	contracts, err := m.AkamaiClient.ListContracts(ctx)
+	if err != nil {
+		return err
+	}
+	groups, err := m.AkamaiClient.ListGroups(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := metav1.NewTime(time.Now())
+
+	contractNames := make(map[string]bool)
+	for _, contract := range contracts {
+		products, err := m.AkamaiClient.ListProducts(ctx, contract.ContractID)
+		if err != nil {
+			// Products are informational; keep mirroring the contract with the last known products
+			logger.Error(err, "Failed to list products", "contractID", contract.ContractID)
+		}
+		name := mirrorResourceName(contract.ContractID)
+		contractNames[name] = true
+		if err := m.syncContract(ctx, name, contract, products, err == nil, now); err != nil {
+			return err
+		}
+	}
+
+	groupNames := make(map[string]bool)
+	for _, group := range groups {
+		name := mirrorResourceName(group.GroupID)
+		groupNames[name] = true
+		if err := m.syncGroup(ctx, name, group, now); err != nil {
+			return err
+		}
+	}
+
+	if err := m.pruneContracts(ctx, contractNames); err != nil {
+		return err
+	}
+	if err := m.pruneGroups(ctx, groupNames); err != nil {
+		return err
+	}
+
+	logger.V(1).Info("Refreshed Akamai account mirror", "contracts", len(contracts), "groups", len(groups))
+	return nil
+}
+
+// syncContract creates or updates the AkamaiContract mirror for a contract. The mirrored products
+// are only replaced when they were listed.
+func (m *AccountMirror) syncContract(ctx context.Context, name string, contract akamai.Contract, products []akamai.Product, productsListed bool, now metav1.Time) error {
+	var mirror akamaiV1alpha1.AkamaiContract
+	err := m.Get(ctx, client.ObjectKey{Name: name}, &mirror)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to get AkamaiContract %s: %w", name, err)
+	}
+
+	spec := akamaiV1alpha1.AkamaiContractSpec{
+		ContractID:       contract.ContractID,
+		ContractTypeName: contract.ContractTypeName,
+	}
+
+	if apierrors.IsNotFound(err) {
+		mirror = akamaiV1alpha1.AkamaiContract{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   name,
+				Labels: map[string]string{ManagedByLabel: ManagedByValue},
+			},
+			Spec: spec,
+		}
+		if err := m.Create(ctx, &mirror); err != nil {
+			return fmt.Errorf("failed to create AkamaiContract %s: %w", name, err)
+		}
+	} else if mirror.Spec != spec {
+		mirror.Spec = spec
+		if err := m.Update(ctx, &mirror); err != nil {
+			return fmt.Errorf("failed to update AkamaiContract %s: %w", name, err)
+		}
+	}
+
+	if productsListed {
+		mirror.Status.Products = make([]akamaiV1alpha1.AkamaiProduct, 0, len(products))
+		for _, product := range products {
+			mirror.Status.Products = append(mirror.Status.Products, akamaiV1alpha1.AkamaiProduct{
+				ProductID:   product.ProductID,
+				ProductName: product.ProductName,
+			})
+		}
+	}
+	mirror.Status.LastSynced = &now
+	if err := m.Status().Update(ctx, &mirror); err != nil {
+		return fmt.Errorf("failed to update AkamaiContract %s status: %w", name, err)
+	}
+
+	return nil
+}
+
+// syncGroup creates or updates the AkamaiGroup mirror for a group
+func (m *AccountMirror) syncGroup(ctx context.Context, name string, group akamai.Group, now metav1.Time) error {
+	var mirror akamaiV1alpha1.AkamaiGroup
+	err := m.Get(ctx, client.ObjectKey{Name: name}, &mirror)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to get AkamaiGroup %s: %w", name, err)
+	}
+
+	spec := akamaiV1alpha1.AkamaiGroupSpec{
+		GroupID:       group.GroupID,
+		GroupName:     group.GroupName,
+		ParentGroupID: group.ParentGroupID,
+		ContractIDs:   group.ContractIDs,
+	}
+
+	if apierrors.IsNotFound(err) {
+		mirror = akamaiV1alpha1.AkamaiGroup{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   name,
+				Labels: map[string]string{ManagedByLabel: ManagedByValue},
+			},
+			Spec: spec,
+		}
+		if err := m.Create(ctx, &mirror); err != nil {
+			return fmt.Errorf("failed to create AkamaiGroup %s: %w", name, err)
+		}
+	} else if !groupSpecEqual(mirror.Spec, spec) {
+		mirror.Spec = spec
+		if err := m.Update(ctx, &mirror); err != nil {
+			return fmt.Errorf("failed to update AkamaiGroup %s: %w", name, err)
+		}
+	}
+
+	mirror.Status.LastSynced = &now
+	if err := m.Status().Update(ctx, &mirror); err != nil {
+		return fmt.Errorf("failed to update AkamaiGroup %s status: %w", name, err)
+	}
+
+	return nil
+}
+
+// pruneContracts deletes mirrored contracts that are no longer available
+func (m *AccountMirror) pruneContracts(ctx context.Context, keep map[string]bool) error {
+	var list akamaiV1alpha1.AkamaiContractList
+	if err := m.List(ctx, &list, client.MatchingLabels{ManagedByLabel: ManagedByValue}); err != nil {
+		return fmt.Errorf("failed to list AkamaiContracts: %w", err)
+	}
+	for i := range list.Items {
+		if keep[list.Items[i].Name] {
+			continue
+		}
+		if err := m.Delete(ctx, &list.Items[i]); client.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("failed to delete AkamaiContract %s: %w", list.Items[i].Name, err)
+		}
+	}
+	return nil
+}
+
+// pruneGroups deletes mirrored groups that are no longer available
+func (m *AccountMirror) pruneGroups(ctx context.Context, keep map[string]bool) error {
+	var list akamaiV1alpha1.AkamaiGroupList
+	if err := m.List(ctx, &list, client.MatchingLabels{ManagedByLabel: ManagedByValue}); err != nil {
+		return fmt.Errorf("failed to list AkamaiGroups: %w", err)
+	}
+	for i := range list.Items {
+		if keep[list.Items[i].Name] {
+			continue
+		}
+		if err := m.Delete(ctx, &list.Items[i]); client.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("failed to delete AkamaiGroup %s: %w", list.Items[i].Name, err)
+		}
+	}
+	return nil
+}
+
+// groupSpecEqual compares two group specs
+func groupSpecEqual(a, b akamaiV1alpha1.AkamaiGroupSpec) bool {
+	if a.GroupID != b.GroupID || a.GroupName != b.GroupName || a.ParentGroupID != b.ParentGroupID {
+		return false
+	}
+	if len(a.ContractIDs) != len(b.ContractIDs) {
+		return false
+	}
+	for i := range a.ContractIDs {
+		if a.ContractIDs[i] != b.ContractIDs[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// mirrorResourceName converts an Akamai ID (e.g. "ctr_C-1234567") into a valid resource name
+func mirrorResourceName(id string) string {
+	return strings.ToLower(strings.ReplaceAll(id, "_", "-"))
+}
@@ -0,0 +1,129 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v8/pkg/papi"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+	"github.com/mmz-srf/akamai-operator/pkg/akamai"
+)
+
+// accountPAPI serves the contracts, groups and products of an account
+type accountPAPI struct {
+	papi.PAPI
+	contracts   []string
+	groups      []string
+	productsErr error
+}
+
+func (s *accountPAPI) GetContracts(context.Context) (*papi.GetContractsResponse, error) {
+	response := &papi.GetContractsResponse{}
+	for _, id := range s.contracts {
+		response.Contracts.Items = append(response.Contracts.Items, &papi.Contract{ContractID: id, ContractTypeName: "DIRECT_CUSTOMER"})
+	}
+	return response, nil
+}
+
+func (s *accountPAPI) GetGroups(context.Context) (*papi.GetGroupsResponse, error) {
+	response := &papi.GetGroupsResponse{}
+	for _, id := range s.groups {
+		response.Groups.Items = append(response.Groups.Items, &papi.Group{GroupID: id, GroupName: "Group " + id, ContractIDs: s.contracts})
+	}
+	return response, nil
+}
+
+func (s *accountPAPI) GetProducts(context.Context, papi.GetProductsRequest) (*papi.GetProductsResponse, error) {
+	if s.productsErr != nil {
+		return nil, s.productsErr
+	}
+	return &papi.GetProductsResponse{Products: papi.ProductsItems{Items: []papi.ProductItem{
+		{ProductID: "prd_Fresca", ProductName: "Ion Standard"},
+	}}}, nil
+}
+
+func TestAccountMirrorRefresh(t *testing.T) {
+	ctx := context.Background()
+	managed := metav1.ObjectMeta{Labels: map[string]string{ManagedByLabel: ManagedByValue}}
+	mirrored := func(name string, products ...string) *akamaiV1alpha1.AkamaiContract {
+		contract := &akamaiV1alpha1.AkamaiContract{ObjectMeta: *managed.DeepCopy()}
+		contract.Name = name
+		for _, product := range products {
+			contract.Status.Products = append(contract.Status.Products, akamaiV1alpha1.AkamaiProduct{ProductID: product})
+		}
+		return contract
+	}
+	removedGroup := &akamaiV1alpha1.AkamaiGroup{ObjectMeta: *managed.DeepCopy()}
+	removedGroup.Name = "grp-2"
+
+	tests := []struct {
+		name             string
+		stub             *accountPAPI
+		existing         []client.Object
+		expectedProducts []string
+	}{
+		{
+			name:             "mirrored",
+			stub:             &accountPAPI{contracts: []string{"ctr_1"}, groups: []string{"grp_1"}},
+			expectedProducts: []string{"prd_Fresca"},
+		},
+		{
+			name:             "removed contracts and groups pruned",
+			stub:             &accountPAPI{contracts: []string{"ctr_1"}, groups: []string{"grp_1"}},
+			existing:         []client.Object{mirrored("ctr-2"), removedGroup},
+			expectedProducts: []string{"prd_Fresca"},
+		},
+		{
+			name:             "products kept when listing fails",
+			stub:             &accountPAPI{contracts: []string{"ctr_1"}, groups: []string{"grp_1"}, productsErr: errors.New("service unavailable")},
+			existing:         []client.Object{mirrored("ctr-1", "prd_SPM")},
+			expectedProducts: []string{"prd_SPM"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeClient := fake.NewClientBuilder().
+				WithScheme(propertyActivationScheme(t)).
+				WithObjects(tt.existing...).
+				WithStatusSubresource(&akamaiV1alpha1.AkamaiContract{}, &akamaiV1alpha1.AkamaiGroup{}).
+				Build()
+			m := &AccountMirror{Client: fakeClient, AkamaiClient: akamai.NewClientWithPAPI(tt.stub)}
+
+			if err := m.refresh(ctx); err != nil {
+				t.Fatalf("refresh() error = %v", err)
+			}
+
+			var contracts akamaiV1alpha1.AkamaiContractList
+			if err := m.List(ctx, &contracts); err != nil {
+				t.Fatalf("failed to list contracts: %v", err)
+			}
+			if len(contracts.Items) != 1 || contracts.Items[0].Name != "ctr-1" || contracts.Items[0].Spec.ContractID != "ctr_1" {
+				t.Fatalf("contracts = %+v, expected only ctr-1", contracts.Items)
+			}
+			var products []string
+			for _, product := range contracts.Items[0].Status.Products {
+				products = append(products, product.ProductID)
+			}
+			if len(products) != len(tt.expectedProducts) || products[0] != tt.expectedProducts[0] {
+				t.Errorf("products = %v, expected %v", products, tt.expectedProducts)
+			}
+			if contracts.Items[0].Status.LastSynced == nil {
+				t.Error("expected the contract to be marked as synced")
+			}
+
+			var groups akamaiV1alpha1.AkamaiGroupList
+			if err := m.List(ctx, &groups); err != nil {
+				t.Fatalf("failed to list groups: %v", err)
+			}
+			if len(groups.Items) != 1 || groups.Items[0].Name != "grp-1" || groups.Items[0].Spec.GroupName != "Group grp_1" {
+				t.Errorf("groups = %+v, expected only grp-1", groups.Items)
+			}
+		})
+	}
+}
@@ -0,0 +1,226 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v8/pkg/papi"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+	"github.com/mmz-srf/akamai-operator/pkg/akamai"
+)
+
+func TestCancelActivation(t *testing.T) {
+	ctx := context.Background()
+	property := func(annotation string, cancelSuperseded bool) *akamaiV1alpha1.AkamaiProperty {
+		p := &akamaiV1alpha1.AkamaiProperty{
+			ObjectMeta: metav1.ObjectMeta{Name: "example"},
+			Spec: akamaiV1alpha1.AkamaiPropertySpec{
+				PropertyName: "www.example.com",
+				ContractID:   "ctr_1",
+				GroupID:      "grp_1",
+				Activation:   &akamaiV1alpha1.ActivationSpec{Network: "PRODUCTION", CancelSuperseded: cancelSuperseded},
+			},
+			Status: akamaiV1alpha1.AkamaiPropertyStatus{
+				PropertyID:                 "prp_1",
+				LatestVersion:              3,
+				ProductionActivationID:     "atv_1",
+				ProductionActivationStatus: "PENDING",
+				ProductionActivationNote:   "release",
+			},
+		}
+		if annotation != "" {
+			p.Annotations = map[string]string{AnnotationAbortActivation: annotation}
+		}
+		return p
+	}
+
+	tests := []struct {
+		name              string
+		property          *akamaiV1alpha1.AkamaiProperty
+		stub              *propertyActivationPAPI
+		superseded        bool
+		expectedCancelled bool
+		expectedID        string
+		expectedStatus    string
+	}{
+		{
+			name:              "abort requested while pending",
+			property:          property("atv_1", false),
+			stub:              &propertyActivationPAPI{version: 3, status: papi.ActivationStatusPending},
+			expectedCancelled: true,
+			expectedID:        "atv_1",
+			expectedStatus:    "ABORTED",
+		},
+		{
+			name:           "abort requested too late",
+			property:       property("atv_1", false),
+			stub:           &propertyActivationPAPI{version: 3, status: papi.ActivationStatusZone1},
+			expectedID:     "atv_1",
+			expectedStatus: "PENDING",
+		},
+		{
+			name:           "abort of an activation not in flight",
+			property:       property("atv_2", false),
+			stub:           &propertyActivationPAPI{version: 3, status: papi.ActivationStatusPending},
+			expectedID:     "atv_1",
+			expectedStatus: "PENDING",
+		},
+		{
+			name:              "superseded activation forgotten",
+			property:          property("", true),
+			stub:              &propertyActivationPAPI{version: 3, status: papi.ActivationStatusPending},
+			superseded:        true,
+			expectedCancelled: true,
+		},
+		{
+			name:           "superseded activation of an older version",
+			property:       property("", true),
+			stub:           &propertyActivationPAPI{version: 2, status: papi.ActivationStatusPending},
+			superseded:     true,
+			expectedID:     "atv_1",
+			expectedStatus: "PENDING",
+		},
+		{
+			name:           "cancelling superseded activations disabled",
+			property:       property("", false),
+			stub:           &propertyActivationPAPI{version: 3, status: papi.ActivationStatusPending},
+			superseded:     true,
+			expectedID:     "atv_1",
+			expectedStatus: "PENDING",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeClient := fake.NewClientBuilder().
+				WithScheme(propertyActivationScheme(t)).
+				WithObjects(tt.property).
+				WithStatusSubresource(&akamaiV1alpha1.AkamaiProperty{}).
+				Build()
+			r := &AkamaiPropertyReconciler{
+				Client:       fakeClient,
+				AkamaiClient: akamai.NewClientWithPAPI(tt.stub),
+			}
+
+			var cancelled bool
+			var err error
+			if tt.superseded {
+				cancelled, err = r.cancelSupersededActivation(ctx, tt.property)
+			} else {
+				cancelled, err = r.handleAbortActivation(ctx, tt.property)
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if cancelled != tt.expectedCancelled || (tt.stub.cancelled != nil) != tt.expectedCancelled {
+				t.Errorf("cancelled = %v (request %+v), expected %v", cancelled, tt.stub.cancelled, tt.expectedCancelled)
+			}
+
+			var got akamaiV1alpha1.AkamaiProperty
+			if err := r.Get(ctx, types.NamespacedName{Name: "example"}, &got); err != nil {
+				t.Fatalf("failed to get property: %v", err)
+			}
+			if got.Status.ProductionActivationID != tt.expectedID || got.Status.ProductionActivationStatus != tt.expectedStatus {
+				t.Errorf("production activation = %q %q, expected %q %q",
+					got.Status.ProductionActivationID, got.Status.ProductionActivationStatus, tt.expectedID, tt.expectedStatus)
+			}
+		})
+	}
+}
+
+func TestPropertyActivationAbort(t *testing.T) {
+	ctx := context.Background()
+	property := &akamaiV1alpha1.AkamaiProperty{
+		ObjectMeta: metav1.ObjectMeta{Name: "example"},
+		Spec: akamaiV1alpha1.AkamaiPropertySpec{
+			PropertyName: "www.example.com",
+			ContractID:   "ctr_1",
+			GroupID:      "grp_1",
+			Activation:   &akamaiV1alpha1.ActivationSpec{Network: "STAGING", NotifyEmails: []string{"cdn@example.com"}},
+		},
+		Status: akamaiV1alpha1.AkamaiPropertyStatus{PropertyID: "prp_1", LatestVersion: 3},
+	}
+	submitted := akamaiV1alpha1.AkamaiPropertyActivationStatus{
+		PropertyID:   "prp_1",
+		Version:      3,
+		ActivationID: "atv_1",
+		Status:       "PENDING",
+		History:      []akamaiV1alpha1.ActivationHistoryEntry{{Status: "PENDING"}},
+	}
+
+	tests := []struct {
+		name              string
+		status            akamaiV1alpha1.AkamaiPropertyActivationStatus
+		stub              *propertyActivationPAPI
+		expectedCancelled bool
+		expectedPhase     string
+		expectedStatus    string
+	}{
+		{
+			name:           "aborted before submission",
+			stub:           &propertyActivationPAPI{},
+			expectedPhase:  PhaseError,
+			expectedStatus: "ABORTED",
+		},
+		{
+			name:              "pending activation cancelled",
+			status:            submitted,
+			stub:              &propertyActivationPAPI{status: papi.ActivationStatusPending},
+			expectedCancelled: true,
+			expectedPhase:     PhaseError,
+			expectedStatus:    "ABORTED",
+		},
+		{
+			name:           "activation past the cancellation window",
+			status:         submitted,
+			stub:           &propertyActivationPAPI{status: papi.ActivationStatusZone1},
+			expectedPhase:  PhaseActivating,
+			expectedStatus: "ZONE_1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			activation := &akamaiV1alpha1.AkamaiPropertyActivation{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "release",
+					Annotations: map[string]string{AnnotationAbortActivation: "true"},
+				},
+				Spec:   akamaiV1alpha1.AkamaiPropertyActivationSpec{PropertyRef: "example", Network: "STAGING"},
+				Status: tt.status,
+			}
+			fakeClient := fake.NewClientBuilder().
+				WithScheme(propertyActivationScheme(t)).
+				WithObjects(property.DeepCopy(), activation).
+				WithStatusSubresource(&akamaiV1alpha1.AkamaiPropertyActivation{}).
+				Build()
+			r := &AkamaiPropertyActivationReconciler{
+				Client:       fakeClient,
+				AkamaiClient: akamai.NewClientWithPAPI(tt.stub),
+			}
+
+			if _, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: "release"}}); err != nil {
+				t.Fatalf("Reconcile() error = %v", err)
+			}
+			if (tt.stub.cancelled != nil) != tt.expectedCancelled {
+				t.Errorf("cancel request = %+v, expected cancelled %v", tt.stub.cancelled, tt.expectedCancelled)
+			}
+			if tt.stub.created != nil {
+				t.Errorf("unexpected activation %+v", tt.stub.created)
+			}
+
+			var got akamaiV1alpha1.AkamaiPropertyActivation
+			if err := r.Get(ctx, types.NamespacedName{Name: "release"}, &got); err != nil {
+				t.Fatalf("failed to get activation: %v", err)
+			}
+			if got.Status.Phase != tt.expectedPhase || got.Status.Status != tt.expectedStatus {
+				t.Errorf("status = phase %q, status %q, expected %q and %q", got.Status.Phase, got.Status.Status, tt.expectedPhase, tt.expectedStatus)
+			}
+		})
+	}
+}
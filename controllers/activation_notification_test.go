@@ -0,0 +1,98 @@
+package controllers
+
+import (
+	"reflect"
+	"testing"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+)
+
+func TestActivationRequestSpec(t *testing.T) {
+	tests := []struct {
+		name           string
+		network        string
+		staging        *akamaiV1alpha1.ActivationNotification
+		production     *akamaiV1alpha1.ActivationNotification
+		expectedEmails []string
+		expectedNote   string
+		expectErr      bool
+	}{
+		{
+			name:           "no overrides",
+			network:        "PRODUCTION",
+			expectedEmails: []string{"ops@example.com"},
+			expectedNote:   "release 42",
+		},
+		{
+			name:           "staging override",
+			network:        "STAGING",
+			staging:        &akamaiV1alpha1.ActivationNotification{NotifyEmails: []string{"dev@example.com"}},
+			production:     &akamaiV1alpha1.ActivationNotification{NotifyEmails: []string{"change@example.com"}},
+			expectedEmails: []string{"dev@example.com"},
+			expectedNote:   "release 42",
+		},
+		{
+			name:           "production template",
+			network:        "PRODUCTION",
+			staging:        &akamaiV1alpha1.ActivationNotification{NotifyEmails: []string{"dev@example.com"}},
+			production:     &akamaiV1alpha1.ActivationNotification{NoteTemplate: "CHG: {{.Note}} ({{.PropertyName}} v{{.Version}} on {{.Network}})"},
+			expectedEmails: []string{"ops@example.com"},
+			expectedNote:   "CHG: release 42 (www.example.com v7 on PRODUCTION)",
+		},
+		{
+			name:      "unknown template field",
+			network:   "STAGING",
+			staging:   &akamaiV1alpha1.ActivationNotification{NoteTemplate: "{{.Ticket}}"},
+			expectErr: true,
+		},
+		{
+			name:       "unparsable template",
+			network:    "PRODUCTION",
+			production: &akamaiV1alpha1.ActivationNotification{NoteTemplate: "{{.Note"},
+			expectErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			property := &akamaiV1alpha1.AkamaiProperty{
+				Spec: akamaiV1alpha1.AkamaiPropertySpec{
+					PropertyName: "www.example.com",
+					Activation: &akamaiV1alpha1.ActivationSpec{
+						Network:      "STAGING",
+						NotifyEmails: []string{"ops@example.com"},
+						Note:         "release 42",
+						Staging:      tt.staging,
+						Production:   tt.production,
+					},
+				},
+			}
+
+			got, err := activationRequestSpec(property, tt.network, 7)
+			if tt.expectErr {
+				if err == nil {
+					t.Fatal("activationRequestSpec() expected an error")
+				}
+				if validateActivationNotifications(property) == nil {
+					t.Error("validateActivationNotifications() expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("activationRequestSpec() error = %v", err)
+			}
+			if got.Network != tt.network {
+				t.Errorf("Network = %q, expected %q", got.Network, tt.network)
+			}
+			if !reflect.DeepEqual(got.NotifyEmails, tt.expectedEmails) {
+				t.Errorf("NotifyEmails = %v, expected %v", got.NotifyEmails, tt.expectedEmails)
+			}
+			if got.Note != tt.expectedNote {
+				t.Errorf("Note = %q, expected %q", got.Note, tt.expectedNote)
+			}
+			if property.Spec.Activation.Note != "release 42" {
+				t.Errorf("activationRequestSpec() modified the spec note: %q", property.Spec.Activation.Note)
+			}
+		})
+	}
+}
@@ -0,0 +1,146 @@
+package controllers
+
+import (
+	"sync"
+	"time"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+	"github.com/mmz-srf/akamai-operator/pkg/akamai"
+)
+
+// activationWaiterTTL is how long a queued activation keeps its place without being asked for
+// again, so resources that stopped waiting (deleted, trigger changed) don't block the queue
+const activationWaiterTTL = 10 * time.Minute
+
+// ActivationScheduler limits how many activations the operator has in flight in each Akamai
+// account, since Akamai caps concurrent activations per account. Slots are handed out first come,
+// first served within an account, and each property holds at most one slot per network. The
+// limit applies to this operator instance only: activations of other shards or other tooling in
+// the same account aren't counted.
+type ActivationScheduler struct {
+	// MaxConcurrent is the number of activations allowed in flight at once in an account; zero
+	// means unlimited
+	MaxConcurrent int
+
+	mu      sync.Mutex
+	now     func() time.Time
+	running map[activationSlot]bool
+	waiting []activationWaiter
+}
+
+// activationSlot identifies the slot of the activations of a property on a network, counted
+// against the limit of the account the property is activated in
+type activationSlot struct {
+	account string
+	name    string
+}
+
+type activationWaiter struct {
+	key      activationSlot
+	lastSeen time.Time
+}
+
+// NewActivationScheduler creates a scheduler allowing maxConcurrent activations in flight
+func NewActivationScheduler(maxConcurrent int) *ActivationScheduler {
+	return &ActivationScheduler{
+		MaxConcurrent: maxConcurrent,
+		now:           time.Now,
+		running:       make(map[activationSlot]bool),
+	}
+}
+
+// activationKey identifies the activation slot of a property on a network in the account of
+// the client activating it
+func activationKey(akamaiClient *akamai.Client, akamaiProperty *akamaiV1alpha1.AkamaiProperty, network string) activationSlot {
+	return activationSlot{account: akamaiClient.Account(), name: akamaiProperty.Name + "/" + network}
+}
+
+// Acquire requests a slot for submitting an activation. It returns true if the activation may be
+// submitted now; otherwise the caller is queued and the returned position (starting at 1) is its
+// place in the queue of its account. Callers keep their place by asking again before
+// activationWaiterTTL passes.
+func (s *ActivationScheduler) Acquire(key activationSlot) (bool, int) {
+	if s == nil || s.MaxConcurrent <= 0 {
+		return true, 0
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.running[key] {
+		return true, 0
+	}
+
+	now := s.now()
+	position, queued := 0, 0
+	waiting := s.waiting[:0]
+	for _, waiter := range s.waiting {
+		if now.Sub(waiter.lastSeen) > activationWaiterTTL {
+			continue
+		}
+		if waiter.key.account == key.account {
+			queued++
+			if waiter.key == key {
+				waiter.lastSeen = now
+				position = queued
+			}
+		}
+		waiting = append(waiting, waiter)
+	}
+	s.waiting = waiting
+	if position == 0 {
+		s.waiting = append(s.waiting, activationWaiter{key: key, lastSeen: now})
+		position = queued + 1
+	}
+
+	// Only the waiters at the head of the queue are served, so a busy property can't starve others
+	if position > s.MaxConcurrent-s.runningIn(key.account) {
+		return false, position
+	}
+	s.dropWaiter(key)
+	s.running[key] = true
+	return true, 0
+}
+
+// runningIn counts the activations in flight in an account
+func (s *ActivationScheduler) runningIn(account string) int {
+	running := 0
+	for key := range s.running {
+		if key.account == account {
+			running++
+		}
+	}
+	return running
+}
+
+// dropWaiter removes a key from the queue
+func (s *ActivationScheduler) dropWaiter(key activationSlot) {
+	for i, waiter := range s.waiting {
+		if waiter.key == key {
+			s.waiting = append(s.waiting[:i], s.waiting[i+1:]...)
+			return
+		}
+	}
+}
+
+// Track records an activation that is already in flight, e.g. one submitted before the operator
+// restarted, so it counts against the limit until it is released
+func (s *ActivationScheduler) Track(key activationSlot) {
+	if s == nil || s.MaxConcurrent <= 0 {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.running[key] = true
+}
+
+// Release frees the slot of a finished activation and drops the key from the queue
+func (s *ActivationScheduler) Release(key activationSlot) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.running, key)
+	s.dropWaiter(key)
+}
@@ -0,0 +1,104 @@
+package controllers
+
+import (
+	"testing"
+	"time"
+)
+
+func TestActivationSchedulerAcquire(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	s := NewActivationScheduler(2)
+	s.now = func() time.Time { return now }
+
+	type step struct {
+		name             string
+		do               func() (bool, int)
+		expectedGranted  bool
+		expectedPosition int
+	}
+	acquire := func(key string) func() (bool, int) {
+		return func() (bool, int) { return s.Acquire(activationSlot{name: key}) }
+	}
+	release := func(key string) func() (bool, int) {
+		return func() (bool, int) { s.Release(activationSlot{name: key}); return true, 0 }
+	}
+
+	steps := []step{
+		{name: "first slot", do: acquire("a/STAGING"), expectedGranted: true},
+		{name: "second slot", do: acquire("b/STAGING"), expectedGranted: true},
+		{name: "holder asks again", do: acquire("a/STAGING"), expectedGranted: true},
+		{name: "limit reached", do: acquire("c/STAGING"), expectedGranted: false, expectedPosition: 1},
+		{name: "queued behind c", do: acquire("d/STAGING"), expectedGranted: false, expectedPosition: 2},
+		{name: "waiter keeps its place", do: acquire("c/STAGING"), expectedGranted: false, expectedPosition: 1},
+		{name: "free slot", do: release("a/STAGING"), expectedGranted: true},
+		{name: "later waiter can't jump the queue", do: acquire("d/STAGING"), expectedGranted: false, expectedPosition: 2},
+		{name: "head of queue is served", do: acquire("c/STAGING"), expectedGranted: true},
+		{name: "next waiter moves up", do: acquire("d/STAGING"), expectedGranted: false, expectedPosition: 1},
+	}
+
+	for _, st := range steps {
+		granted, position := st.do()
+		if granted != st.expectedGranted || position != st.expectedPosition {
+			t.Errorf("%s: got (%v, %d), expected (%v, %d)", st.name, granted, position, st.expectedGranted, st.expectedPosition)
+		}
+	}
+}
+
+func TestActivationSchedulerExpiresWaiters(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	s := NewActivationScheduler(1)
+	s.now = func() time.Time { return now }
+
+	s.Track(activationSlot{name: "a/PRODUCTION"})
+	if granted, position := s.Acquire(activationSlot{name: "b/PRODUCTION"}); granted || position != 1 {
+		t.Fatalf("Acquire(b) = (%v, %d), expected to be queued first", granted, position)
+	}
+
+	// b stops asking; once its place expires c is at the head of the queue
+	now = now.Add(activationWaiterTTL + time.Second)
+	s.Release(activationSlot{name: "a/PRODUCTION"})
+	if granted, _ := s.Acquire(activationSlot{name: "c/PRODUCTION"}); !granted {
+		t.Error("Acquire(c) was not granted after the stale waiter expired")
+	}
+}
+
+func TestActivationSchedulerUnlimited(t *testing.T) {
+	var nilScheduler *ActivationScheduler
+	for _, s := range []*ActivationScheduler{nilScheduler, NewActivationScheduler(0)} {
+		s.Track(activationSlot{name: "a/STAGING"})
+		for _, key := range []string{"a/STAGING", "b/STAGING", "c/STAGING"} {
+			if granted, _ := s.Acquire(activationSlot{name: key}); !granted {
+				t.Errorf("Acquire(%s) was not granted without a limit", key)
+			}
+		}
+		s.Release(activationSlot{name: "a/STAGING"})
+	}
+}
+
+func TestActivationSchedulerLimitsEachAccount(t *testing.T) {
+	s := NewActivationScheduler(1)
+	first := activationSlot{account: "akab-1.luna.akamaiapis.net/", name: "a/STAGING"}
+	second := activationSlot{account: "akab-2.luna.akamaiapis.net/", name: "a/STAGING"}
+	switched := activationSlot{account: "akab-1.luna.akamaiapis.net/1-ABCD", name: "b/STAGING"}
+
+	// Properties of the same name in other accounts, or switched to another account, get their own slots
+	for _, key := range []activationSlot{first, second, switched} {
+		if granted, _ := s.Acquire(key); !granted {
+			t.Errorf("Acquire(%+v) was not granted in its own account", key)
+		}
+	}
+
+	// The limit still applies within an account
+	queued := activationSlot{account: first.account, name: "c/STAGING"}
+	if granted, position := s.Acquire(queued); granted || position != 1 {
+		t.Errorf("Acquire(%+v) = (%v, %d), expected to be queued first in its account", queued, granted, position)
+	}
+	s.Release(second)
+	if granted, _ := s.Acquire(queued); granted {
+		t.Error("expected a slot freed in another account not to be handed out")
+	}
+	s.Release(first)
+	if granted, _ := s.Acquire(queued); !granted {
+		t.Error("expected the slot freed in the account to be handed out")
+	}
+}
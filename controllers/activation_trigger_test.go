@@ -0,0 +1,35 @@
+package controllers
+
+import (
+	"testing"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+)
+
+func TestShouldActivate(t *testing.T) {
+	tests := []struct {
+		name          string
+		trigger       akamaiV1alpha1.ActivationTrigger
+		noteChanged   bool
+		latestVersion int
+		activeVersion int
+		expected      bool
+	}{
+		{name: "default initial activation", latestVersion: 1, expected: true},
+		{name: "default note unchanged", latestVersion: 3, activeVersion: 2, expected: false},
+		{name: "default note changed", noteChanged: true, latestVersion: 2, activeVersion: 2, expected: true},
+		{name: "note change initial activation", trigger: akamaiV1alpha1.ActivationTriggerNoteChange, latestVersion: 1, expected: true},
+		{name: "on change newer version", trigger: akamaiV1alpha1.ActivationTriggerOnChange, latestVersion: 3, activeVersion: 2, expected: true},
+		{name: "on change ignores note", trigger: akamaiV1alpha1.ActivationTriggerOnChange, noteChanged: true, latestVersion: 2, activeVersion: 2, expected: false},
+		{name: "manual never activates", trigger: akamaiV1alpha1.ActivationTriggerManual, noteChanged: true, latestVersion: 3, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := shouldActivate(tt.trigger, tt.noteChanged, tt.latestVersion, tt.activeVersion)
+			if got != tt.expected {
+				t.Errorf("shouldActivate() = %v, expected %v", got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package controllers
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v8/pkg/papi"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+	"github.com/mmz-srf/akamai-operator/pkg/akamai"
+)
+
+// activationPAPI reports an activation as pending for the first polls
+type activationPAPI struct {
+	papi.PAPI
+	pendingPolls int32
+	polls        atomic.Int32
+}
+
+func (s *activationPAPI) GetActivation(_ context.Context, request papi.GetActivationRequest) (*papi.GetActivationResponse, error) {
+	status := papi.ActivationStatusActive
+	if s.polls.Add(1) <= s.pendingPolls {
+		status = papi.ActivationStatusPending
+	}
+	return &papi.GetActivationResponse{GetActivationsResponse: papi.GetActivationsResponse{
+		Activations: papi.ActivationsItems{Items: []*papi.Activation{{
+			ActivationID: request.ActivationID,
+			PropertyID:   request.PropertyID,
+			Status:       status,
+		}}},
+	}}, nil
+}
+
+func TestWatchActivation(t *testing.T) {
+	property := &akamaiV1alpha1.AkamaiProperty{
+		ObjectMeta: metav1.ObjectMeta{Name: "example"},
+		Status:     akamaiV1alpha1.AkamaiPropertyStatus{PropertyID: "prp_1"},
+	}
+	stub := &activationPAPI{pendingPolls: 2}
+	r := &AkamaiPropertyReconciler{
+		AkamaiClient:      akamai.NewClientWithPAPI(stub),
+		ActivationWatcher: &akamai.ActivationWatcher{Options: akamai.PollOptions{Interval: time.Millisecond}},
+		activationEvents:  make(chan event.GenericEvent, 1),
+	}
+
+	r.watchActivation(property, "atv_1")
+	// A reconcile while the activation is watched doesn't start a second poller
+	r.watchActivation(property, "atv_1")
+
+	select {
+	case finished := <-r.activationEvents:
+		if finished.Object.GetName() != "example" {
+			t.Errorf("expected a reconcile of example, got %q", finished.Object.GetName())
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected a reconcile once the activation finished")
+	}
+	if polls := stub.polls.Load(); polls != 3 {
+		t.Errorf("expected the activation to be polled 3 times, got %d", polls)
+	}
+	select {
+	case extra := <-r.activationEvents:
+		t.Errorf("unexpected second reconcile %v", extra)
+	case <-time.After(20 * time.Millisecond):
+	}
+}
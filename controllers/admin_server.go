@@ -0,0 +1,199 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+)
+
+// AdminServer applies fleet-wide operations to all AkamaiProperty resources matching a label
+// selector over HTTP, e.g. suspending every property with team=news, by annotating them in bulk.
+// Every request must present Token as a bearer token.
+type AdminServer struct {
+	client.Client
+
+	// BindAddress is the address the server listens on, e.g. ":8083"
+	BindAddress string
+
+	// Token is the bearer token clients authenticate with
+	Token string
+
+	// now returns the current time; defaults to time.Now
+	now func() time.Time
+}
+
+// adminAction is an operation applied to every selected property
+type adminAction func(akamaiProperty *akamaiV1alpha1.AkamaiProperty, now time.Time) bool
+
+// adminActions are the operations of the admin API by name
+var adminActions = map[string]adminAction{
+	"suspend":              setAnnotation(AnnotationSuspend, "true"),
+	"resume":               removeAnnotations(AnnotationSuspend, AnnotationPaused),
+	"freeze-activations":   setAnnotation(AnnotationFreezeActivations, "true"),
+	"unfreeze-activations": removeAnnotations(AnnotationFreezeActivations),
+	"reconcile": func(akamaiProperty *akamaiV1alpha1.AkamaiProperty, now time.Time) bool {
+		return setAnnotation(AnnotationReconcileRequestedAt, now.UTC().Format(time.RFC3339Nano))(akamaiProperty, now)
+	},
+}
+
+// adminResult is the answer of the admin API: the selected properties and those changed
+type adminResult struct {
+	Action     string            `json:"action"`
+	Selector   string            `json:"selector"`
+	DryRun     bool              `json:"dryRun,omitempty"`
+	Properties []string          `json:"properties"`
+	Changed    []string          `json:"changed"`
+	Failed     map[string]string `json:"failed,omitempty"`
+}
+
+//+kubebuilder:rbac:groups=akamai.com,resources=akamaiproperties,verbs=get;list;watch;patch
+
+// NeedLeaderElection reports that the admin API is served by every replica
+func (s *AdminServer) NeedLeaderElection() bool {
+	return false
+}
+
+// Start serves the admin API until the context is cancelled
+func (s *AdminServer) Start(ctx context.Context) error {
+	logger := log.FromContext(ctx).WithName("admin-server")
+	server := &http.Server{
+		Addr:              s.BindAddress,
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+		BaseContext:       func(_ net.Listener) context.Context { return log.IntoContext(ctx, logger) },
+	}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			logger.Error(err, "Failed to shut down admin server")
+		}
+	}()
+
+	logger.Info("Serving admin API", "address", s.BindAddress)
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// Handler returns the authenticated HTTP handler of the admin API:
+//
+//	POST /admin/suspend?selector=...               set akamai.com/suspend
+//	POST /admin/resume?selector=...                remove akamai.com/suspend and akamai.com/paused
+//	POST /admin/freeze-activations?selector=...    set akamai.com/freeze-activations
+//	POST /admin/unfreeze-activations?selector=...  remove akamai.com/freeze-activations
+//	POST /admin/reconcile?selector=...             request a full sync with reconcile.fluxcd.io/requestedAt
+//
+// The selector is a Kubernetes label selector, e.g. "team=news,env in (prod)". With dryRun=true
+// the selected properties are listed without changing them.
+func (s *AdminServer) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /admin/{action}", s.apply)
+	return bearerAuth(s.Token, mux)
+}
+
+// apply applies an action to the properties matching the selector of the request
+func (s *AdminServer) apply(w http.ResponseWriter, req *http.Request) {
+	ctx := req.Context()
+	name := req.PathValue("action")
+	action, ok := adminActions[name]
+	if !ok {
+		http.Error(w, fmt.Sprintf("unknown action %q", name), http.StatusNotFound)
+		return
+	}
+	query := req.URL.Query()
+	// An empty selector would match every property; fleet-wide actions must say so explicitly
+	if query.Get("selector") == "" {
+		http.Error(w, "a label selector is required", http.StatusBadRequest)
+		return
+	}
+	selector, err := labels.Parse(query.Get("selector"))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("invalid label selector: %v", err), http.StatusBadRequest)
+		return
+	}
+	dryRun, _ := strconv.ParseBool(query.Get("dryRun"))
+
+	var list akamaiV1alpha1.AkamaiPropertyList
+	if err := s.List(ctx, &list, client.MatchingLabelsSelector{Selector: selector}); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to list AkamaiProperties")
+		http.Error(w, "failed to list properties", http.StatusInternalServerError)
+		return
+	}
+	sort.Slice(list.Items, func(i, j int) bool {
+		return list.Items[i].Name < list.Items[j].Name
+	})
+
+	now := time.Now()
+	if s.now != nil {
+		now = s.now()
+	}
+	result := adminResult{Action: name, Selector: selector.String(), DryRun: dryRun, Properties: []string{}, Changed: []string{}}
+	for i := range list.Items {
+		akamaiProperty := &list.Items[i]
+		result.Properties = append(result.Properties, akamaiProperty.Name)
+		patch := client.MergeFrom(akamaiProperty.DeepCopy())
+		if !action(akamaiProperty, now) {
+			continue
+		}
+		if !dryRun {
+			if err := s.Patch(ctx, akamaiProperty, patch); err != nil {
+				if result.Failed == nil {
+					result.Failed = make(map[string]string)
+				}
+				result.Failed[akamaiProperty.Name] = err.Error()
+				continue
+			}
+		}
+		result.Changed = append(result.Changed, akamaiProperty.Name)
+	}
+	log.FromContext(ctx).Info("Applied admin action", "action", name, "selector", result.Selector, "dryRun", dryRun,
+		"selected", len(result.Properties), "changed", len(result.Changed), "failed", len(result.Failed))
+
+	if len(result.Failed) > 0 {
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+	writeInventory(ctx, w, result)
+}
+
+// setAnnotation returns an action setting an annotation to value
+func setAnnotation(annotation, value string) adminAction {
+	return func(akamaiProperty *akamaiV1alpha1.AkamaiProperty, _ time.Time) bool {
+		if current, ok := akamaiProperty.Annotations[annotation]; ok && current == value {
+			return false
+		}
+		if akamaiProperty.Annotations == nil {
+			akamaiProperty.Annotations = make(map[string]string)
+		}
+		akamaiProperty.Annotations[annotation] = value
+		return true
+	}
+}
+
+// removeAnnotations returns an action removing annotations
+func removeAnnotations(annotations ...string) adminAction {
+	return func(akamaiProperty *akamaiV1alpha1.AkamaiProperty, _ time.Time) bool {
+		changed := false
+		for _, annotation := range annotations {
+			if _, ok := akamaiProperty.Annotations[annotation]; ok {
+				delete(akamaiProperty.Annotations, annotation)
+				changed = true
+			}
+		}
+		return changed
+	}
+}
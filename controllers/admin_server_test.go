@@ -0,0 +1,178 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+)
+
+func TestAdminServer(t *testing.T) {
+	property := func(name string, labels map[string]string, annotations map[string]string) *akamaiV1alpha1.AkamaiProperty {
+		return &akamaiV1alpha1.AkamaiProperty{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels, Annotations: annotations},
+			Spec:       akamaiV1alpha1.AkamaiPropertySpec{PropertyName: name + ".example.com"},
+		}
+	}
+	r := newFakeReconciler(t,
+		property("news-www", map[string]string{"team": "news", "env": "prod"}, nil),
+		property("news-api", map[string]string{"team": "news", "env": "staging"}, map[string]string{AnnotationPaused: "true"}),
+		property("sport-www", map[string]string{"team": "sport", "env": "prod"}, nil),
+	)
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	server := httptest.NewServer((&AdminServer{Client: r.Client, Token: "secret", now: func() time.Time { return now }}).Handler())
+	defer server.Close()
+
+	post := func(t *testing.T, action, selector, token string, dryRun bool) (int, adminResult) {
+		t.Helper()
+		query := url.Values{"selector": []string{selector}}
+		if dryRun {
+			query.Set("dryRun", "true")
+		}
+		req, err := http.NewRequest(http.MethodPost, server.URL+"/admin/"+action+"?"+query.Encode(), nil)
+		if err != nil {
+			t.Fatalf("failed to build request: %v", err)
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("request failed: %v", err)
+		}
+		defer resp.Body.Close()
+		var result adminResult
+		if resp.StatusCode == http.StatusOK {
+			if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+		}
+		return resp.StatusCode, result
+	}
+	annotation := func(t *testing.T, name, key string) (string, bool) {
+		t.Helper()
+		var akamaiProperty akamaiV1alpha1.AkamaiProperty
+		if err := r.Get(context.Background(), client.ObjectKey{Name: name}, &akamaiProperty); err != nil {
+			t.Fatalf("failed to get %s: %v", name, err)
+		}
+		value, ok := akamaiProperty.Annotations[key]
+		return value, ok
+	}
+
+	tests := []struct {
+		name       string
+		action     string
+		selector   string
+		dryRun     bool
+		properties []string
+		changed    []string
+		check      func(t *testing.T)
+	}{
+		{
+			name: "dry run", action: "suspend", selector: "team=news", dryRun: true,
+			properties: []string{"news-api", "news-www"}, changed: []string{"news-api", "news-www"},
+			check: func(t *testing.T) {
+				if _, ok := annotation(t, "news-www", AnnotationSuspend); ok {
+					t.Error("expected a dry run not to suspend news-www")
+				}
+			},
+		},
+		{
+			name: "suspend", action: "suspend", selector: "team=news",
+			properties: []string{"news-api", "news-www"}, changed: []string{"news-api", "news-www"},
+			check: func(t *testing.T) {
+				if value, _ := annotation(t, "news-www", AnnotationSuspend); value != "true" {
+					t.Errorf("expected news-www to be suspended, got %q", value)
+				}
+				if _, ok := annotation(t, "sport-www", AnnotationSuspend); ok {
+					t.Error("expected sport-www not to be selected")
+				}
+			},
+		},
+		{
+			name: "resume removes both annotations", action: "resume", selector: "team=news,env=staging",
+			properties: []string{"news-api"}, changed: []string{"news-api"},
+			check: func(t *testing.T) {
+				if _, ok := annotation(t, "news-api", AnnotationPaused); ok {
+					t.Error("expected news-api to be resumed")
+				}
+			},
+		},
+		{
+			name: "freeze activations", action: "freeze-activations", selector: "env=prod",
+			properties: []string{"news-www", "sport-www"}, changed: []string{"news-www", "sport-www"},
+		},
+		{
+			name: "freeze frozen activations", action: "freeze-activations", selector: "env in (prod)",
+			properties: []string{"news-www", "sport-www"}, changed: []string{},
+		},
+		{
+			name: "reconcile", action: "reconcile", selector: "team!=news",
+			properties: []string{"sport-www"}, changed: []string{"sport-www"},
+			check: func(t *testing.T) {
+				if value, _ := annotation(t, "sport-www", AnnotationReconcileRequestedAt); value != "2026-10-18T12:00:00Z" {
+					t.Errorf("expected a requested reconcile, got %q", value)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, result := post(t, tt.action, tt.selector, "secret", tt.dryRun)
+			if status != http.StatusOK {
+				t.Fatalf("status = %d", status)
+			}
+			if !reflect.DeepEqual(result.Properties, tt.properties) || !reflect.DeepEqual(result.Changed, tt.changed) {
+				t.Errorf("result = %+v, expected properties %v and changed %v", result, tt.properties, tt.changed)
+			}
+			if tt.check != nil {
+				tt.check(t)
+			}
+		})
+	}
+
+	for _, tt := range []struct {
+		name, action, selector, token string
+		status                        int
+	}{
+		{name: "wrong token", action: "suspend", selector: "team=news", token: "wrong", status: http.StatusUnauthorized},
+		{name: "unknown action", action: "purge", selector: "team=news", token: "secret", status: http.StatusNotFound},
+		{name: "missing selector", action: "suspend", token: "secret", status: http.StatusBadRequest},
+		{name: "invalid selector", action: "suspend", selector: "team in (news", token: "secret", status: http.StatusBadRequest},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if status, _ := post(t, tt.action, tt.selector, tt.token, false); status != tt.status {
+				t.Errorf("status = %d, expected %d", status, tt.status)
+			}
+		})
+	}
+}
+
+func TestHandlePromotionActivationsFrozen(t *testing.T) {
+	property := &akamaiV1alpha1.AkamaiProperty{
+		ObjectMeta: metav1.ObjectMeta{Name: "example", Annotations: map[string]string{
+			AnnotationPromoteVersion:    "3",
+			AnnotationFreezeActivations: "true",
+		}},
+		Spec:   akamaiV1alpha1.AkamaiPropertySpec{Activation: &akamaiV1alpha1.ActivationSpec{Network: "STAGING"}},
+		Status: akamaiV1alpha1.AkamaiPropertyStatus{LatestVersion: 4},
+	}
+	r := newFakeReconciler(t, property)
+
+	// The promotion waits without asking Akamai and is neither started nor rejected
+	result, err := r.handlePromotion(context.Background(), property)
+	if err != nil || !result.IsZero() {
+		t.Fatalf("handlePromotion() = %+v, %v", result, err)
+	}
+	if property.Status.Promotion != nil {
+		t.Errorf("status.promotion = %+v, expected the promotion to wait", property.Status.Promotion)
+	}
+}
@@ -0,0 +1,223 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+	"github.com/mmz-srf/akamai-operator/pkg/akamai"
+)
+
+//+kubebuilder:rbac:groups=akamai.com,resources=akamaiproviderconfigs,verbs=get;list;watch
+
+// AkamaiClientCache caches the Akamai clients of the accounts referenced by spec.credentialsRef
+// and AkamaiProviderConfigs, so properties of different Akamai accounts can be reconciled by one
+// operator. A client is rebuilt when the Secret or provider config it was built from changes.
+// A nil cache builds a new client on every call.
+type AkamaiClientCache struct {
+	mu      sync.Mutex
+	clients map[string]cachedAkamaiClient
+}
+
+// cachedAkamaiClient is a client built from a given revision of its source
+type cachedAkamaiClient struct {
+	revision string
+	client   *akamai.Client
+}
+
+// NewAkamaiClientCache creates an empty client cache
+func NewAkamaiClientCache() *AkamaiClientCache {
+	return &AkamaiClientCache{clients: make(map[string]cachedAkamaiClient)}
+}
+
+// cached returns the client cached under key for revision, building it if needed
+func (c *AkamaiClientCache) cached(key, revision string, build func() (*akamai.Client, error)) (*akamai.Client, error) {
+	if c == nil {
+		return build()
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if cached, ok := c.clients[key]; ok && cached.revision == revision {
+		return cached.client, nil
+	}
+	akamaiClient, err := build()
+	if err != nil {
+		return nil, err
+	}
+	c.clients[key] = cachedAkamaiClient{revision: revision, client: akamaiClient}
+	return akamaiClient, nil
+}
+
+// clientFor returns the Akamai client for the credentials Secret referenced by ref
+func (c *AkamaiClientCache) clientFor(ctx context.Context, reader client.Reader, ref *akamaiV1alpha1.CredentialsReference, accountSwitchKey string) (*akamai.Client, error) {
+	key := types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}
+	var secret corev1.Secret
+	if err := reader.Get(ctx, key, &secret); err != nil {
+		return nil, fmt.Errorf("failed to get credentials secret %s: %w", key, err)
+	}
+
+	return c.cached("secret/"+key.String()+"/"+accountSwitchKey, secret.ResourceVersion, func() (*akamai.Client, error) {
+		credentials, err := credentialsFromSecret(ref, &secret)
+		if err != nil {
+			return nil, err
+		}
+		credentials.AccountSwitchKey = accountSwitchKey
+		akamaiClient, err := akamai.NewClient(credentials)
+		if err != nil {
+			return nil, fmt.Errorf("invalid credentials in secret %s: %w", key, err)
+		}
+		return akamaiClient, nil
+	})
+}
+
+// akamaiAccount points at the fields selecting the Akamai account of a resource, like a property
+// or an include, and at the contract and group it is created in
+type akamaiAccount struct {
+	credentialsRef    *akamaiV1alpha1.CredentialsReference
+	providerConfigRef string
+	contractID        *string
+	groupID           *string
+}
+
+// propertyAccount returns the account fields of a property
+func propertyAccount(akamaiProperty *akamaiV1alpha1.AkamaiProperty) akamaiAccount {
+	spec := &akamaiProperty.Spec
+	return akamaiAccount{spec.CredentialsRef, spec.ProviderConfigRef, &spec.ContractID, &spec.GroupID}
+}
+
+// includeAccount returns the account fields of an include
+func includeAccount(include *akamaiV1alpha1.AkamaiPropertyInclude) akamaiAccount {
+	spec := &include.Spec
+	return akamaiAccount{spec.CredentialsRef, spec.ProviderConfigRef, &spec.ContractID, &spec.GroupID}
+}
+
+// edgeHostnameAccount returns the account fields of an edge hostname
+func edgeHostnameAccount(edgeHostname *akamaiV1alpha1.AkamaiEdgeHostname) akamaiAccount {
+	spec := &edgeHostname.Spec
+	return akamaiAccount{spec.CredentialsRef, spec.ProviderConfigRef, &spec.ContractID, &spec.GroupID}
+}
+
+// accountClient returns the Akamai client of the account a resource belongs to: the client of
+// its credentialsRef or of its provider config, or nil for the operator's own account
+func (c *AkamaiClientCache) accountClient(ctx context.Context, reader client.Reader, defaults akamai.Credentials, account akamaiAccount, providerConfig *akamaiV1alpha1.AkamaiProviderConfig) (*akamai.Client, error) {
+	switch {
+	case account.credentialsRef != nil:
+		return c.clientFor(ctx, reader, account.credentialsRef, "")
+	case providerConfig == nil:
+		return nil, nil
+	case providerConfig.Spec.CredentialsRef != nil:
+		return c.clientFor(ctx, reader, providerConfig.Spec.CredentialsRef, providerConfig.Spec.AccountSwitchKey)
+	default:
+		// The operator's credentials, switched to the account of the provider config
+		return c.cached("provider/"+providerConfig.Name, providerConfig.ResourceVersion, func() (*akamai.Client, error) {
+			credentials := defaults
+			credentials.AccountSwitchKey = providerConfig.Spec.AccountSwitchKey
+			return akamai.NewClient(credentials)
+		})
+	}
+}
+
+// credentialsFromSecret reads the EdgeGrid credentials from the keys of a credentials reference
+func credentialsFromSecret(ref *akamaiV1alpha1.CredentialsReference, secret *corev1.Secret) (akamai.Credentials, error) {
+	var credentials akamai.Credentials
+	fields := []struct {
+		key, defaultKey string
+		value           *string
+	}{
+		{ref.HostKey, "host", &credentials.Host},
+		{ref.ClientTokenKey, "client_token", &credentials.ClientToken},
+		{ref.ClientSecretKey, "client_secret", &credentials.ClientSecret},
+		{ref.AccessTokenKey, "access_token", &credentials.AccessToken},
+	}
+	for _, field := range fields {
+		key := field.key
+		if key == "" {
+			key = field.defaultKey
+		}
+		value, ok := secret.Data[key]
+		if !ok || len(value) == 0 {
+			return akamai.Credentials{}, fmt.Errorf("key %s not found in credentials secret %s/%s", key, secret.Namespace, secret.Name)
+		}
+		*field.value = string(value)
+	}
+	return credentials, nil
+}
+
+// providerConfigFor returns the AkamaiProviderConfig referenced by the account, or nil
+func providerConfigFor(ctx context.Context, reader client.Reader, account akamaiAccount) (*akamaiV1alpha1.AkamaiProviderConfig, error) {
+	name := account.providerConfigRef
+	if name == "" {
+		return nil, nil
+	}
+	var providerConfig akamaiV1alpha1.AkamaiProviderConfig
+	if err := reader.Get(ctx, types.NamespacedName{Name: name}, &providerConfig); err != nil {
+		return nil, fmt.Errorf("failed to get AkamaiProviderConfig %s: %w", name, err)
+	}
+	return &providerConfig, nil
+}
+
+// applyAccountDefaults fills the contract and group the spec leaves empty from the provider
+// config. The defaults are only applied in memory and never written back to the spec.
+func applyAccountDefaults(account akamaiAccount, providerConfig *akamaiV1alpha1.AkamaiProviderConfig) error {
+	if providerConfig != nil {
+		if *account.contractID == "" {
+			*account.contractID = providerConfig.Spec.DefaultContractID
+		}
+		if *account.groupID == "" {
+			*account.groupID = providerConfig.Spec.DefaultGroupID
+		}
+	}
+	if *account.contractID == "" || *account.groupID == "" {
+		return fmt.Errorf("spec.contractId and spec.groupId are required unless the provider config defines defaults")
+	}
+	return nil
+}
+
+// resolveAccount resolves the provider config of a resource, applies its defaults and returns
+// the Akamai client of the resource's account, or nil for the operator's own account
+func resolveAccount(ctx context.Context, reader client.Reader, cache *AkamaiClientCache, defaults akamai.Credentials, account akamaiAccount) (*akamai.Client, error) {
+	providerConfig, err := providerConfigFor(ctx, reader, account)
+	if err != nil {
+		return nil, err
+	}
+	if err := applyAccountDefaults(account, providerConfig); err != nil {
+		return nil, err
+	}
+	return cache.accountClient(ctx, reader, defaults, account, providerConfig)
+}
+
+// operatorClientMu guards the lazy creation of the operator's Akamai client by properties
+// reconciled in parallel
+var operatorClientMu sync.Mutex
+
+// forAccount returns the reconciler to reconcile a property with: r itself using the
+// operator's Akamai client, or a copy using the client of the property's account
+func (r *AkamaiPropertyReconciler) forAccount(ctx context.Context, akamaiProperty *akamaiV1alpha1.AkamaiProperty) (*AkamaiPropertyReconciler, error) {
+	akamaiClient, err := resolveAccount(ctx, r.Client, r.ClientCache, r.Credentials, propertyAccount(akamaiProperty))
+	if err != nil {
+		return nil, err
+	}
+
+	operatorClientMu.Lock()
+	defer operatorClientMu.Unlock()
+	if akamaiClient != nil {
+		scoped := *r
+		scoped.AkamaiClient = akamaiClient
+		return &scoped, nil
+	}
+
+	if r.AkamaiClient == nil {
+		akamaiClient, err := akamai.NewClient(r.Credentials)
+		if err != nil {
+			return nil, err
+		}
+		r.AkamaiClient = akamaiClient
+	}
+	return r, nil
+}
@@ -0,0 +1,257 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+	"github.com/mmz-srf/akamai-operator/pkg/akamai"
+)
+
+func testCredentialsSecret(data map[string]string) *corev1.Secret {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "account-b", Namespace: "akamai-operator-system"},
+		Data:       map[string][]byte{},
+	}
+	for key, value := range data {
+		secret.Data[key] = []byte(value)
+	}
+	return secret
+}
+
+var testCredentialsData = map[string]string{
+	"host":          "akab-account-b.luna.akamaiapis.net",
+	"client_token":  "akab-account-b-client-token-xx",
+	"client_secret": "account-b-client-secret-xxxxxxxx",
+	"access_token":  "akab-account-b-access-token-xx",
+}
+
+func TestCredentialsFromSecret(t *testing.T) {
+	tests := []struct {
+		name         string
+		ref          akamaiV1alpha1.CredentialsReference
+		data         map[string]string
+		expectedHost string
+		expectErr    bool
+	}{
+		{name: "default keys", data: testCredentialsData, expectedHost: "akab-account-b.luna.akamaiapis.net"},
+		{
+			name: "custom keys",
+			ref:  akamaiV1alpha1.CredentialsReference{HostKey: "AKAMAI_HOST", AccessTokenKey: "AKAMAI_ACCESS_TOKEN"},
+			data: map[string]string{
+				"AKAMAI_HOST":         "akab-custom.luna.akamaiapis.net",
+				"client_token":        "token",
+				"client_secret":       "secret",
+				"AKAMAI_ACCESS_TOKEN": "access",
+			},
+			expectedHost: "akab-custom.luna.akamaiapis.net",
+		},
+		{name: "missing key", data: map[string]string{"host": "akab-account-b.luna.akamaiapis.net"}, expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			credentials, err := credentialsFromSecret(&tt.ref, testCredentialsSecret(tt.data))
+			if (err != nil) != tt.expectErr {
+				t.Fatalf("credentialsFromSecret() error = %v, expectErr %v", err, tt.expectErr)
+			}
+			if credentials.Host != tt.expectedHost {
+				t.Errorf("credentialsFromSecret() host = %q, expected %q", credentials.Host, tt.expectedHost)
+			}
+		})
+	}
+}
+
+func TestAkamaiClientCache(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+	secret := testCredentialsSecret(testCredentialsData)
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(secret).Build()
+	ref := &akamaiV1alpha1.CredentialsReference{Namespace: secret.Namespace, Name: secret.Name}
+	cache := NewAkamaiClientCache()
+
+	first, err := cache.clientFor(ctx, fakeClient, ref, "")
+	if err != nil {
+		t.Fatalf("clientFor() unexpected error: %v", err)
+	}
+	second, err := cache.clientFor(ctx, fakeClient, ref, "")
+	if err != nil {
+		t.Fatalf("clientFor() unexpected error: %v", err)
+	}
+	if first != second {
+		t.Error("expected the cached client to be reused while the secret is unchanged")
+	}
+
+	// Rotated credentials build a new client
+	secret.Data["client_secret"] = []byte("account-b-rotated-secret-xxxxxxx")
+	if err := fakeClient.Update(ctx, secret); err != nil {
+		t.Fatalf("failed to update secret: %v", err)
+	}
+	rotated, err := cache.clientFor(ctx, fakeClient, ref, "")
+	if err != nil {
+		t.Fatalf("clientFor() unexpected error: %v", err)
+	}
+	if rotated == first {
+		t.Error("expected a new client after the secret changed")
+	}
+
+	// A nil cache still builds clients
+	var noCache *AkamaiClientCache
+	if _, err := noCache.clientFor(ctx, fakeClient, ref, ""); err != nil {
+		t.Errorf("clientFor() on a nil cache unexpected error: %v", err)
+	}
+	if _, err := cache.clientFor(ctx, fakeClient, &akamaiV1alpha1.CredentialsReference{Namespace: "akamai-operator-system", Name: "missing"}, ""); err == nil {
+		t.Error("expected an error for a missing secret")
+	}
+}
+
+func TestApplyAccountDefaults(t *testing.T) {
+	providerConfig := &akamaiV1alpha1.AkamaiProviderConfig{
+		Spec: akamaiV1alpha1.AkamaiProviderConfigSpec{DefaultContractID: "ctr_default", DefaultGroupID: "grp_default"},
+	}
+
+	tests := []struct {
+		name             string
+		spec             akamaiV1alpha1.AkamaiPropertySpec
+		providerConfig   *akamaiV1alpha1.AkamaiProviderConfig
+		expectedContract string
+		expectedGroup    string
+		expectErr        bool
+	}{
+		{name: "defaults from provider config", providerConfig: providerConfig, expectedContract: "ctr_default", expectedGroup: "grp_default"},
+		{
+			name:             "spec wins over defaults",
+			spec:             akamaiV1alpha1.AkamaiPropertySpec{ContractID: "ctr_spec"},
+			providerConfig:   providerConfig,
+			expectedContract: "ctr_spec",
+			expectedGroup:    "grp_default",
+		},
+		{name: "no provider config", spec: akamaiV1alpha1.AkamaiPropertySpec{ContractID: "ctr_spec", GroupID: "grp_spec"}, expectedContract: "ctr_spec", expectedGroup: "grp_spec"},
+		{name: "missing group", spec: akamaiV1alpha1.AkamaiPropertySpec{ContractID: "ctr_spec"}, expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			property := &akamaiV1alpha1.AkamaiProperty{Spec: tt.spec}
+			err := applyAccountDefaults(propertyAccount(property), tt.providerConfig)
+			if (err != nil) != tt.expectErr {
+				t.Fatalf("applyAccountDefaults() error = %v, expectErr %v", err, tt.expectErr)
+			}
+			if tt.expectErr {
+				return
+			}
+			if property.Spec.ContractID != tt.expectedContract || property.Spec.GroupID != tt.expectedGroup {
+				t.Errorf("applyAccountDefaults() = %s/%s, expected %s/%s", property.Spec.ContractID, property.Spec.GroupID, tt.expectedContract, tt.expectedGroup)
+			}
+		})
+	}
+}
+
+func TestResolveAccount(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+	_ = akamaiV1alpha1.AddToScheme(scheme)
+	secret := testCredentialsSecret(testCredentialsData)
+	withSecret := &akamaiV1alpha1.AkamaiProviderConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "tenant-a"},
+		Spec: akamaiV1alpha1.AkamaiProviderConfigSpec{
+			CredentialsRef:    &akamaiV1alpha1.CredentialsReference{Namespace: secret.Namespace, Name: secret.Name},
+			DefaultContractID: "ctr_a",
+			DefaultGroupID:    "grp_a",
+		},
+	}
+	switchOnly := &akamaiV1alpha1.AkamaiProviderConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "tenant-b"},
+		Spec:       akamaiV1alpha1.AkamaiProviderConfigSpec{AccountSwitchKey: "1-ABCDE:1-2RBL"},
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(secret, withSecret, switchOnly).Build()
+	defaults := akamai.Credentials{
+		Host:         "akab-operator.luna.akamaiapis.net",
+		ClientToken:  "akab-operator-client-token-xxxx",
+		ClientSecret: "operator-client-secret-xxxxxxxxx",
+		AccessToken:  "akab-operator-access-token-xxxx",
+	}
+	cache := NewAkamaiClientCache()
+
+	tests := []struct {
+		name         string
+		spec         akamaiV1alpha1.AkamaiPropertySpec
+		expectClient bool
+		expectErr    bool
+	}{
+		{name: "operator account", spec: akamaiV1alpha1.AkamaiPropertySpec{ContractID: "ctr_1", GroupID: "grp_1"}},
+		{name: "provider config with secret", spec: akamaiV1alpha1.AkamaiPropertySpec{ProviderConfigRef: "tenant-a"}, expectClient: true},
+		{name: "provider config switching the operator account", spec: akamaiV1alpha1.AkamaiPropertySpec{ProviderConfigRef: "tenant-b", ContractID: "ctr_b", GroupID: "grp_b"}, expectClient: true},
+		{name: "provider config without defaults", spec: akamaiV1alpha1.AkamaiPropertySpec{ProviderConfigRef: "tenant-b"}, expectErr: true},
+		{name: "missing provider config", spec: akamaiV1alpha1.AkamaiPropertySpec{ProviderConfigRef: "missing", ContractID: "ctr_1", GroupID: "grp_1"}, expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			property := &akamaiV1alpha1.AkamaiProperty{Spec: tt.spec}
+			akamaiClient, err := resolveAccount(ctx, fakeClient, cache, defaults, propertyAccount(property))
+			if (err != nil) != tt.expectErr {
+				t.Fatalf("resolveAccount() error = %v, expectErr %v", err, tt.expectErr)
+			}
+			if (akamaiClient != nil) != tt.expectClient {
+				t.Errorf("resolveAccount() client = %v, expectClient %v", akamaiClient, tt.expectClient)
+			}
+		})
+	}
+}
+
+func TestIncludeAndEdgeHostnameAccounts(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+	_ = akamaiV1alpha1.AddToScheme(scheme)
+	secret := testCredentialsSecret(testCredentialsData)
+	providerConfig := &akamaiV1alpha1.AkamaiProviderConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "tenant-a"},
+		Spec: akamaiV1alpha1.AkamaiProviderConfigSpec{
+			CredentialsRef:    &akamaiV1alpha1.CredentialsReference{Namespace: secret.Namespace, Name: secret.Name},
+			DefaultContractID: "ctr_a",
+			DefaultGroupID:    "grp_a",
+		},
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(secret, providerConfig).Build()
+	operatorClient := akamai.NewClientWithPAPI(nil)
+
+	include := &akamaiV1alpha1.AkamaiPropertyInclude{Spec: akamaiV1alpha1.AkamaiPropertyIncludeSpec{ProviderConfigRef: "tenant-a"}}
+	includes := &AkamaiPropertyIncludeReconciler{Client: fakeClient, AkamaiClient: operatorClient, ClientCache: NewAkamaiClientCache()}
+	scopedInclude, err := includes.forAccount(ctx, include)
+	if err != nil {
+		t.Fatalf("include forAccount() error = %v", err)
+	}
+	if scopedInclude.AkamaiClient == operatorClient || include.Spec.ContractID != "ctr_a" || include.Spec.GroupID != "grp_a" {
+		t.Errorf("include resolved to the operator's client or %s/%s, expected the account of tenant-a with its defaults",
+			include.Spec.ContractID, include.Spec.GroupID)
+	}
+
+	edgeHostname := &akamaiV1alpha1.AkamaiEdgeHostname{Spec: akamaiV1alpha1.AkamaiEdgeHostnameSpec{
+		ContractID:     "ctr_b",
+		GroupID:        "grp_b",
+		CredentialsRef: &akamaiV1alpha1.CredentialsReference{Namespace: secret.Namespace, Name: secret.Name},
+	}}
+	edgeHostnames := &AkamaiEdgeHostnameReconciler{Client: fakeClient, AkamaiClient: operatorClient, ClientCache: NewAkamaiClientCache()}
+	scopedEdgeHostname, err := edgeHostnames.forAccount(ctx, edgeHostname)
+	if err != nil {
+		t.Fatalf("edge hostname forAccount() error = %v", err)
+	}
+	if scopedEdgeHostname.AkamaiClient == operatorClient || edgeHostnames.AkamaiClient != operatorClient {
+		t.Error("expected the edge hostname to use the client of its credentialsRef without changing the reconciler")
+	}
+
+	// Without a reference, the operator's client is used
+	unscoped, err := edgeHostnames.forAccount(ctx, &akamaiV1alpha1.AkamaiEdgeHostname{Spec: akamaiV1alpha1.AkamaiEdgeHostnameSpec{ContractID: "ctr_1", GroupID: "grp_1"}})
+	if err != nil || unscoped.AkamaiClient != operatorClient {
+		t.Errorf("forAccount() = %v, %v, expected the operator's client", unscoped, err)
+	}
+}
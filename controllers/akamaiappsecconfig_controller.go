@@ -0,0 +1,620 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v8/pkg/appsec"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+	"github.com/mmz-srf/akamai-operator/pkg/akamai"
+)
+
+const (
+	// appSecResyncInterval is how often a security configuration is compared with Akamai
+	appSecResyncInterval = 10 * time.Minute
+
+	// appSecActivationPollInterval is how often a running activation is checked
+	appSecActivationPollInterval = time.Minute
+
+	// appSecErrorRetryInterval is how long a failed security configuration reconcile waits before it is retried
+	appSecErrorRetryInterval = 2 * time.Minute
+
+	// defaultAppSecActivationNote is the note of activations without their own
+	defaultAppSecActivationNote = "Activated by akamai-operator"
+
+	// defaultAppSecMatchTargetPath is the path of match targets without their own paths
+	defaultAppSecMatchTargetPath = "/*"
+
+	// defaultRatePolicyClientIdentifier tells the clients of rate policies apart by IP address
+	defaultRatePolicyClientIdentifier = "ip"
+
+	// defaultRatePolicyRequestType counts the client requests of rate policies
+	defaultRatePolicyRequestType = "ClientRequest"
+)
+
+// AkamaiAppSecConfigReconciler creates Application Security configurations, keeps their
+// hostnames, security policies and match targets in line with the spec and activates each new
+// version on the networks the spec selects, like property versions
+type AkamaiAppSecConfigReconciler struct {
+	client.Client
+	Scheme       *runtime.Scheme
+	AkamaiClient *akamai.Client
+
+	// Credentials selects the credential source the Akamai client is created with
+	Credentials akamai.Credentials
+
+	// Shard is the part of the fleet this instance manages; nil manages all security configurations
+	Shard *Shard
+
+	// ObserveOnly compares the security configurations with Akamai and reports differences
+	// without changing or activating them
+	ObserveOnly bool
+}
+
+//+kubebuilder:rbac:groups=akamai.com,resources=akamaiappsecconfigs,verbs=get;list;watch;update;patch
+//+kubebuilder:rbac:groups=akamai.com,resources=akamaiappsecconfigs/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=akamai.com,resources=akamaiappsecconfigs/finalizers,verbs=update
+
+// Reconcile brings an Application Security configuration to the state of its AkamaiAppSecConfig
+func (r *AkamaiAppSecConfigReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	var config akamaiV1alpha1.AkamaiAppSecConfig
+	if err := r.Get(ctx, req.NamespacedName, &config); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	// Security configurations of other shards are left to the instances managing them
+	if !r.Shard.Contains(config.Labels, config.Spec.ContractID) {
+		logger.V(1).Info("Security configuration belongs to another shard", "shard", r.Shard.Name)
+		return ctrl.Result{}, nil
+	}
+
+	if r.AkamaiClient == nil {
+		akamaiClient, err := akamai.NewClient(r.Credentials)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		r.AkamaiClient = akamaiClient
+	}
+
+	if config.DeletionTimestamp != nil {
+		return r.handleDeletion(ctx, &config)
+	}
+	// The finalizer is added before the configuration is created, so it is never left behind
+	if !controllerutil.ContainsFinalizer(&config, AppSecConfigFinalizerName) {
+		controllerutil.AddFinalizer(&config, AppSecConfigFinalizerName)
+		if err := r.Update(ctx, &config); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	activating, err := r.syncAppSecConfig(ctx, &config)
+	if message, observed := observeOnlyMessage(r.ObserveOnly, err); observed {
+		r.setAppSecConfigCondition(&config, PhaseObserving, metav1.ConditionFalse, ReasonObserveOnly, message)
+		if err := r.Status().Update(ctx, &config); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{RequeueAfter: appSecResyncInterval}, nil
+	}
+	if err != nil {
+		logger.Error(err, "Failed to reconcile security configuration", "name", appSecConfigName(&config))
+		r.setAppSecConfigCondition(&config, PhaseError, metav1.ConditionFalse, "ReconcileError", akamai.ErrorMessage(err))
+		if updateErr := r.Status().Update(ctx, &config); updateErr != nil {
+			return ctrl.Result{}, updateErr
+		}
+		return ctrl.Result{RequeueAfter: appSecErrorRetryInterval}, nil
+	}
+
+	if activating {
+		r.setAppSecConfigCondition(&config, PhaseActivating, metav1.ConditionFalse, "ActivationInProgress",
+			fmt.Sprintf("Security configuration %d version %d is being activated", config.Status.ConfigID, config.Status.LatestVersion))
+		if err := r.Status().Update(ctx, &config); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{RequeueAfter: appSecActivationPollInterval}, nil
+	}
+	r.setAppSecConfigCondition(&config, PhaseReady, metav1.ConditionTrue, "AppSecConfigReady",
+		fmt.Sprintf("Security configuration %d version %d is up to date", config.Status.ConfigID, config.Status.LatestVersion))
+	if err := r.Status().Update(ctx, &config); err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{RequeueAfter: appSecResyncInterval}, nil
+}
+
+// syncAppSecConfig creates or adopts the security configuration, brings its latest version in
+// line with the spec, activates it and records its state in the status. It reports whether an
+// activation is still running.
+func (r *AkamaiAppSecConfigReconciler) syncAppSecConfig(ctx context.Context, config *akamaiV1alpha1.AkamaiAppSecConfig) (bool, error) {
+	logger := log.FromContext(ctx)
+	name := appSecConfigName(config)
+
+	var current *appsec.GetConfigurationResponse
+	configID := config.Status.ConfigID
+	if configID != 0 {
+		var err error
+		if current, err = r.AkamaiClient.GetAppSecConfig(ctx, configID); err != nil {
+			return false, err
+		}
+		if current == nil {
+			logger.Info("Security configuration was deleted outside the operator, recreating it", "configId", configID)
+			config.Status.Staging, config.Status.Production = nil, nil
+		}
+	}
+	if current == nil {
+		var err error
+		if configID, err = r.AkamaiClient.FindAppSecConfig(ctx, name); err != nil {
+			return false, err
+		}
+		if configID != 0 {
+			logger.Info("Adopting existing security configuration", "name", name, "configId", configID)
+		} else if r.ObserveOnly {
+			return false, observedDifference(fmt.Sprintf("Security configuration %s doesn't exist in Akamai", name))
+		} else {
+			groupID, err := numericGroupID(config.Spec.GroupID)
+			if err != nil {
+				return false, err
+			}
+			if configID, err = r.AkamaiClient.CreateAppSecConfig(ctx, appsec.CreateConfigurationRequest{
+				Name:        name,
+				Description: appSecConfigDescription(config),
+				ContractID:  strings.TrimPrefix(config.Spec.ContractID, "ctr_"),
+				GroupID:     groupID,
+				Hostnames:   config.Spec.Hostnames,
+			}); err != nil {
+				return false, err
+			}
+			logger.Info("Created security configuration", "name", name, "configId", configID)
+		}
+		if current, err = r.AkamaiClient.GetAppSecConfig(ctx, configID); err != nil {
+			return false, err
+		}
+		if current == nil {
+			return false, fmt.Errorf("security configuration %d was created but can't be found", configID)
+		}
+	}
+	config.Status.ConfigID = configID
+
+	// A version that is active or being activated isn't edited; changes go to a new version cloned
+	// from it. The dry run finds out whether there are any.
+	version := current.LatestVersion
+	changes, err := r.syncAppSecVersion(ctx, config, version, false)
+	if err != nil {
+		return false, err
+	}
+	if len(changes) > 0 && r.ObserveOnly {
+		return false, observedDifference(fmt.Sprintf("Security configuration %d version %d differs from the spec: %s", configID, version, strings.Join(changes, ", ")))
+	}
+	if len(changes) > 0 {
+		if appSecVersionLocked(config, current, version) {
+			if version, err = r.AkamaiClient.CloneAppSecVersion(ctx, configID, version); err != nil {
+				return false, err
+			}
+			logger.Info("Created security configuration version", "configId", configID, "version", version)
+		}
+		if changes, err = r.syncAppSecVersion(ctx, config, version, true); err != nil {
+			return false, err
+		}
+		logger.Info("Updated security configuration", "configId", configID, "version", version, "changes", changes)
+		config.Status.LastChanges = changes
+		if current, err = r.AkamaiClient.GetAppSecConfig(ctx, configID); err != nil {
+			return false, err
+		}
+		if current == nil {
+			return false, fmt.Errorf("security configuration %d was updated but can't be found", configID)
+		}
+	}
+
+	config.Status.LatestVersion = current.LatestVersion
+	config.Status.StagingVersion = current.StagingVersion
+	config.Status.ProductionVersion = current.ProductionVersion
+
+	activation := config.Spec.Activation
+	if activation == nil {
+		activation = &akamaiV1alpha1.AppSecActivationSpec{}
+	}
+	stagingActivating, err := r.activateAppSecConfig(ctx, config, string(appsec.NetworkStaging), activation.Staging, current.StagingVersion, &config.Status.Staging)
+	if err != nil {
+		return false, err
+	}
+	// Like property versions, a version reaches production only once it is active on staging
+	if stagingActivating {
+		return true, nil
+	}
+	return r.activateAppSecConfig(ctx, config, string(appsec.NetworkProduction), activation.Production, current.ProductionVersion, &config.Status.Production)
+}
+
+// syncAppSecVersion compares a version of the security configuration with the spec and returns
+// the changes it needs. With apply the changes are made, which requires an editable version.
+func (r *AkamaiAppSecConfigReconciler) syncAppSecVersion(ctx context.Context, config *akamaiV1alpha1.AkamaiAppSecConfig, version int, apply bool) ([]string, error) {
+	configID := config.Status.ConfigID
+	var changes []string
+
+	hostnames, err := r.AkamaiClient.GetAppSecHostnames(ctx, configID, version)
+	if err != nil {
+		return nil, err
+	}
+	if !sameStrings(hostnames, config.Spec.Hostnames) {
+		changes = append(changes, "update hostnames")
+		if apply {
+			if err := r.AkamaiClient.UpdateAppSecHostnames(ctx, configID, version, config.Spec.Hostnames); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	policies, err := r.AkamaiClient.GetAppSecPolicies(ctx, configID, version)
+	if err != nil {
+		return nil, err
+	}
+	policyIDs := make(map[string]string, len(config.Spec.Policies))
+	for _, policy := range config.Spec.Policies {
+		policyID, ok := policies[policy.Name]
+		if !ok {
+			changes = append(changes, fmt.Sprintf("create policy %s", policy.Name))
+			if !apply {
+				// The settings of a policy that doesn't exist yet are made once it is created
+				continue
+			}
+			if policyID, err = r.AkamaiClient.CreateAppSecPolicy(ctx, configID, version, policy.Name, policy.Prefix); err != nil {
+				return nil, err
+			}
+		}
+		policyIDs[policy.Name] = policyID
+
+		if policy.WAFMode != "" {
+			mode, err := r.AkamaiClient.GetWAFMode(ctx, configID, version, policyID)
+			if err != nil {
+				return nil, err
+			}
+			if mode != policy.WAFMode {
+				changes = append(changes, fmt.Sprintf("set WAF mode of policy %s to %s", policy.Name, policy.WAFMode))
+				if apply {
+					if err := r.AkamaiClient.UpdateWAFMode(ctx, configID, version, policyID, policy.WAFMode); err != nil {
+						return nil, err
+					}
+				}
+			}
+		}
+
+		if len(policy.RuleActions) > 0 {
+			actions, err := r.AkamaiClient.GetRuleActions(ctx, configID, version, policyID)
+			if err != nil {
+				return nil, err
+			}
+			for _, rule := range policy.RuleActions {
+				if actions[rule.RuleID] == rule.Action {
+					continue
+				}
+				changes = append(changes, fmt.Sprintf("set action of rule %d of policy %s to %s", rule.RuleID, policy.Name, rule.Action))
+				if apply {
+					if err := r.AkamaiClient.UpdateRuleAction(ctx, configID, version, policyID, rule.RuleID, rule.Action); err != nil {
+						return nil, err
+					}
+				}
+			}
+		}
+	}
+	config.Status.Policies = policyIDs
+
+	ratePolicyChanges, err := r.syncRatePolicies(ctx, config, version, policyIDs, apply)
+	if err != nil {
+		return nil, err
+	}
+	changes = append(changes, ratePolicyChanges...)
+
+	if len(config.Spec.MatchTargets) > 0 {
+		current, err := r.AkamaiClient.GetWebsiteMatchTargets(ctx, configID, version)
+		if err != nil {
+			return nil, err
+		}
+		desired, complete := appSecMatchTargets(config, policyIDs)
+		if !complete || !sameMatchTargets(current, desired) {
+			changes = append(changes, "replace match targets")
+			if apply {
+				for _, target := range current {
+					if err := r.AkamaiClient.RemoveMatchTarget(ctx, configID, version, target.ID); err != nil {
+						return nil, err
+					}
+				}
+				for _, target := range desired {
+					if err := r.AkamaiClient.CreateWebsiteMatchTarget(ctx, configID, version, target); err != nil {
+						return nil, err
+					}
+				}
+			}
+		}
+	}
+	return changes, nil
+}
+
+// syncRatePolicies compares the rate policies of a version and the actions the security policies
+// take on them with the spec and returns the changes they need. With apply the changes are made.
+func (r *AkamaiAppSecConfigReconciler) syncRatePolicies(ctx context.Context, config *akamaiV1alpha1.AkamaiAppSecConfig, version int, policyIDs map[string]string, apply bool) ([]string, error) {
+	configID := config.Status.ConfigID
+	var changes []string
+	if len(config.Spec.RatePolicies) == 0 {
+		config.Status.RatePolicies = nil
+		return nil, nil
+	}
+
+	current, err := r.AkamaiClient.GetRatePolicies(ctx, configID, version)
+	if err != nil {
+		return nil, err
+	}
+	ratePolicyIDs := make(map[string]int, len(config.Spec.RatePolicies))
+	policyActions := map[string]map[int]string{}
+	for _, spec := range config.Spec.RatePolicies {
+		desired := appSecRatePolicy(spec)
+		index := slices.IndexFunc(current, func(policy akamai.AppSecRatePolicy) bool { return policy.Name == spec.Name })
+		switch {
+		case index < 0:
+			changes = append(changes, fmt.Sprintf("create rate policy %s", spec.Name))
+			if !apply {
+				// The actions on a rate policy that doesn't exist yet are set once it is created
+				continue
+			}
+			if desired.ID, err = r.AkamaiClient.CreateRatePolicy(ctx, configID, version, desired); err != nil {
+				return nil, err
+			}
+		case !sameRatePolicy(current[index], desired):
+			changes = append(changes, fmt.Sprintf("update rate policy %s", spec.Name))
+			desired.ID = current[index].ID
+			if apply {
+				if err := r.AkamaiClient.UpdateRatePolicy(ctx, configID, version, desired); err != nil {
+					return nil, err
+				}
+			}
+		default:
+			desired.ID = current[index].ID
+		}
+		ratePolicyIDs[spec.Name] = desired.ID
+
+		for _, action := range spec.Actions {
+			policyID, ok := policyIDs[action.Policy]
+			if !ok {
+				// The security policy is created by this update
+				continue
+			}
+			if _, ok := policyActions[policyID]; !ok {
+				if policyActions[policyID], err = r.AkamaiClient.GetRatePolicyActions(ctx, configID, version, policyID); err != nil {
+					return nil, err
+				}
+			}
+			if policyActions[policyID][desired.ID] == action.Action {
+				continue
+			}
+			changes = append(changes, fmt.Sprintf("set action of rate policy %s in policy %s to %s", spec.Name, action.Policy, action.Action))
+			if apply {
+				if err := r.AkamaiClient.UpdateRatePolicyAction(ctx, configID, version, policyID, desired.ID, action.Action); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+	config.Status.RatePolicies = ratePolicyIDs
+	return changes, nil
+}
+
+// activateAppSecConfig activates the latest version of the configuration on a network unless it
+// is active or being activated there, and records the activation in the status. It reports
+// whether an activation is running.
+func (r *AkamaiAppSecConfigReconciler) activateAppSecConfig(ctx context.Context, config *akamaiV1alpha1.AkamaiAppSecConfig, network string, enabled bool, activeVersion int, status **akamaiV1alpha1.AppSecActivationStatus) (bool, error) {
+	if !enabled {
+		*status = nil
+		return false, nil
+	}
+	version := config.Status.LatestVersion
+	if activeVersion == version {
+		if *status == nil || (*status).Version != version {
+			*status = &akamaiV1alpha1.AppSecActivationStatus{Version: version}
+		}
+		(*status).Status = string(appsec.StatusActive)
+		return false, nil
+	}
+
+	// Follow the activation of the latest version started earlier
+	if *status != nil && (*status).Version == version && (*status).ActivationID != 0 {
+		state, err := r.AkamaiClient.GetAppSecActivation(ctx, (*status).ActivationID)
+		if err != nil {
+			return false, err
+		}
+		(*status).Status = state
+		switch appsec.StatusValue(state) {
+		case appsec.StatusActive:
+			return false, nil
+		case appsec.StatusFailed, appsec.StatusAborted:
+			// Activating the same version again would fail the same way
+			return false, fmt.Errorf("activation %d of security configuration %d version %d on %s failed",
+				(*status).ActivationID, config.Status.ConfigID, version, network)
+		default:
+			return true, nil
+		}
+	}
+
+	if r.ObserveOnly {
+		return false, observedDifference(fmt.Sprintf("Security configuration %d version %d isn't active on %s", config.Status.ConfigID, version, network))
+	}
+
+	note := defaultAppSecActivationNote
+	if config.Spec.Activation.Note != "" {
+		note = config.Spec.Activation.Note
+	}
+	activated, err := r.AkamaiClient.ActivateAppSecConfig(ctx, config.Status.ConfigID, version, network, note, config.Spec.Activation.NotificationEmails)
+	if err != nil {
+		return false, err
+	}
+	log.FromContext(ctx).Info("Activating security configuration", "configId", config.Status.ConfigID, "network", network,
+		"version", version, "activationId", activated.ActivationID)
+	*status = &akamaiV1alpha1.AppSecActivationStatus{ActivationID: activated.ActivationID, Version: version, Status: string(activated.Status)}
+	return activated.Status != appsec.StatusActive, nil
+}
+
+// handleDeletion deletes the security configuration with the Delete deletion policy and removes
+// the finalizer. Akamai refuses to delete configurations that are active on a network.
+func (r *AkamaiAppSecConfigReconciler) handleDeletion(ctx context.Context, config *akamaiV1alpha1.AkamaiAppSecConfig) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+	if !controllerutil.ContainsFinalizer(config, AppSecConfigFinalizerName) {
+		return ctrl.Result{}, nil
+	}
+
+	// Nothing is deleted in Akamai in observe-only mode
+	if config.Spec.DeletionPolicy == akamaiV1alpha1.DeletionPolicyDelete && config.Status.ConfigID != 0 && !r.ObserveOnly {
+		if err := r.AkamaiClient.DeleteAppSecConfig(ctx, config.Status.ConfigID); err != nil {
+			logger.Error(err, "Failed to delete security configuration", "configId", config.Status.ConfigID)
+			r.setAppSecConfigCondition(config, PhaseDeleting, metav1.ConditionFalse, "DeletionFailed", akamai.ErrorMessage(err))
+			if updateErr := r.Status().Update(ctx, config); updateErr != nil {
+				return ctrl.Result{}, updateErr
+			}
+			return ctrl.Result{RequeueAfter: appSecErrorRetryInterval}, nil
+		}
+		logger.Info("Deleted security configuration", "configId", config.Status.ConfigID)
+	}
+
+	controllerutil.RemoveFinalizer(config, AppSecConfigFinalizerName)
+	return ctrl.Result{}, r.Update(ctx, config)
+}
+
+// appSecConfigName returns the name of the security configuration, defaulting to the name of
+// the resource
+func appSecConfigName(config *akamaiV1alpha1.AkamaiAppSecConfig) string {
+	if config.Spec.Name != "" {
+		return config.Spec.Name
+	}
+	return config.Name
+}
+
+// appSecConfigDescription returns the description of the security configuration; Akamai
+// requires one
+func appSecConfigDescription(config *akamaiV1alpha1.AkamaiAppSecConfig) string {
+	if config.Spec.Description != "" {
+		return config.Spec.Description
+	}
+	return "Managed by akamai-operator"
+}
+
+// appSecVersionLocked reports whether a version of the configuration is active on a network or
+// has been activated by the operator, and must not be edited anymore
+func appSecVersionLocked(config *akamaiV1alpha1.AkamaiAppSecConfig, current *appsec.GetConfigurationResponse, version int) bool {
+	if version == current.StagingVersion || version == current.ProductionVersion {
+		return true
+	}
+	for _, activation := range []*akamaiV1alpha1.AppSecActivationStatus{config.Status.Staging, config.Status.Production} {
+		if activation != nil && activation.Version == version {
+			return true
+		}
+	}
+	return false
+}
+
+// appSecMatchTargets returns the website match targets of the spec in order. It reports false
+// when a policy they reference doesn't exist yet.
+func appSecMatchTargets(config *akamaiV1alpha1.AkamaiAppSecConfig, policyIDs map[string]string) ([]akamai.AppSecMatchTarget, bool) {
+	targets := make([]akamai.AppSecMatchTarget, 0, len(config.Spec.MatchTargets))
+	for _, target := range config.Spec.MatchTargets {
+		policyID, ok := policyIDs[target.Policy]
+		if !ok {
+			return nil, false
+		}
+		hostnames := target.Hostnames
+		if len(hostnames) == 0 {
+			hostnames = config.Spec.Hostnames
+		}
+		paths := target.Paths
+		if len(paths) == 0 {
+			paths = []string{defaultAppSecMatchTargetPath}
+		}
+		targets = append(targets, akamai.AppSecMatchTarget{PolicyID: policyID, Hostnames: hostnames, Paths: paths})
+	}
+	return targets, true
+}
+
+// appSecRatePolicy returns the rate policy of the spec, with the default client identifier and
+// request type
+func appSecRatePolicy(spec akamaiV1alpha1.AppSecRatePolicySpec) akamai.AppSecRatePolicy {
+	policy := akamai.AppSecRatePolicy{
+		Name:             spec.Name,
+		Description:      spec.Description,
+		AverageThreshold: spec.AverageThreshold,
+		BurstThreshold:   spec.BurstThreshold,
+		ClientIdentifier: spec.ClientIdentifier,
+		RequestType:      spec.RequestType,
+		Hostnames:        spec.Hostnames,
+		Paths:            spec.Paths,
+	}
+	if policy.ClientIdentifier == "" {
+		policy.ClientIdentifier = defaultRatePolicyClientIdentifier
+	}
+	if policy.RequestType == "" {
+		policy.RequestType = defaultRatePolicyRequestType
+	}
+	return policy
+}
+
+// sameRatePolicy reports whether a rate policy in Akamai has the settings of the spec. Hostnames
+// and paths are compared ignoring their order.
+func sameRatePolicy(current, desired akamai.AppSecRatePolicy) bool {
+	return current.Description == desired.Description &&
+		current.AverageThreshold == desired.AverageThreshold &&
+		current.BurstThreshold == desired.BurstThreshold &&
+		current.ClientIdentifier == desired.ClientIdentifier &&
+		current.RequestType == desired.RequestType &&
+		sameStrings(current.Hostnames, desired.Hostnames) &&
+		sameStrings(current.Paths, desired.Paths)
+}
+
+// sameMatchTargets reports whether two lists of match targets assign the same hostnames and paths
+// to the same policies in the same order, ignoring their IDs
+func sameMatchTargets(a, b []akamai.AppSecMatchTarget) bool {
+	return slices.EqualFunc(a, b, func(x, y akamai.AppSecMatchTarget) bool {
+		return x.PolicyID == y.PolicyID && sameStrings(x.Hostnames, y.Hostnames) && sameStrings(x.Paths, y.Paths)
+	})
+}
+
+// sameStrings reports whether two lists hold the same strings, ignoring their order and case
+func sameStrings(a, b []string) bool {
+	normalize := func(values []string) []string {
+		normalized := make([]string, 0, len(values))
+		for _, value := range values {
+			normalized = append(normalized, strings.ToLower(value))
+		}
+		slices.Sort(normalized)
+		return slices.Compact(normalized)
+	}
+	return slices.Equal(normalize(a), normalize(b))
+}
+
+// setAppSecConfigCondition sets the phase and the Ready condition of the security configuration
+func (r *AkamaiAppSecConfigReconciler) setAppSecConfigCondition(config *akamaiV1alpha1.AkamaiAppSecConfig, phase string, status metav1.ConditionStatus, reason, message string) {
+	setReadyCondition(config.Generation, readyStatus{
+		Phase:              &config.Status.Phase,
+		ObservedGeneration: &config.Status.ObservedGeneration,
+		LastUpdated:        &config.Status.LastUpdated,
+		Conditions:         &config.Status.Conditions,
+	}, phase, status, reason, message)
+}
+
+// SetupWithManager sets up the controller with the Manager. Status updates don't trigger
+// reconciles; security configurations are requeued to follow activations and changes made
+// outside the operator.
+func (r *AkamaiAppSecConfigReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&akamaiV1alpha1.AkamaiAppSecConfig{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Complete(r)
+}
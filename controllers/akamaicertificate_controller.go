@@ -0,0 +1,417 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v8/pkg/cps"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+	"github.com/mmz-srf/akamai-operator/pkg/akamai"
+)
+
+const (
+	// certificateResyncInterval is how often a CPS enrollment is compared with Akamai
+	certificateResyncInterval = 30 * time.Minute
+
+	// certificateChangePollInterval is how often a pending change of an enrollment is checked;
+	// validating and deploying a certificate takes minutes to days
+	certificateChangePollInterval = 5 * time.Minute
+
+	// certificateErrorRetryInterval is how long a failed certificate reconcile waits before it is
+	// retried
+	certificateErrorRetryInterval = 2 * time.Minute
+)
+
+// certificateRegistrationAuthorities are the CPS registration authorities by validation type
+var certificateRegistrationAuthorities = map[string]string{
+	"dv":          "lets-encrypt",
+	"ov":          "symantec",
+	"ev":          "symantec",
+	"third-party": "third-party",
+}
+
+// AkamaiCertificateReconciler creates CPS enrollments, keeps their SANs and network settings in
+// line with the spec and reports the pending changes and the deployed certificates
+type AkamaiCertificateReconciler struct {
+	client.Client
+	Scheme       *runtime.Scheme
+	AkamaiClient *akamai.Client
+
+	// Credentials selects the credential source the Akamai client is created with
+	Credentials akamai.Credentials
+
+	// Shard is the part of the fleet this instance manages; nil manages all certificates
+	Shard *Shard
+
+	// ObserveOnly compares the enrollments with CPS and reports differences without changing them
+	ObserveOnly bool
+}
+
+//+kubebuilder:rbac:groups=akamai.com,resources=akamaicertificates,verbs=get;list;watch;update;patch
+//+kubebuilder:rbac:groups=akamai.com,resources=akamaicertificates/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=akamai.com,resources=akamaicertificates/finalizers,verbs=update
+
+// Reconcile brings a CPS enrollment to the state of its AkamaiCertificate
+func (r *AkamaiCertificateReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	var certificate akamaiV1alpha1.AkamaiCertificate
+	if err := r.Get(ctx, req.NamespacedName, &certificate); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	// Certificates of other shards are left to the instances managing them
+	if !r.Shard.Contains(certificate.Labels, certificate.Spec.ContractID) {
+		logger.V(1).Info("Certificate belongs to another shard", "shard", r.Shard.Name)
+		return ctrl.Result{}, nil
+	}
+
+	if r.AkamaiClient == nil {
+		akamaiClient, err := akamai.NewClient(r.Credentials)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		r.AkamaiClient = akamaiClient
+	}
+
+	if certificate.DeletionTimestamp != nil {
+		return r.handleDeletion(ctx, &certificate)
+	}
+	// The finalizer is added before the enrollment is created, so it is never left behind
+	if !controllerutil.ContainsFinalizer(&certificate, CertificateFinalizerName) {
+		controllerutil.AddFinalizer(&certificate, CertificateFinalizerName)
+		if err := r.Update(ctx, &certificate); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	err := r.syncCertificate(ctx, &certificate)
+	if message, observed := observeOnlyMessage(r.ObserveOnly, err); observed {
+		r.setCertificateCondition(&certificate, PhaseObserving, metav1.ConditionFalse, ReasonObserveOnly, message)
+		if err := r.Status().Update(ctx, &certificate); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{RequeueAfter: certificateResyncInterval}, nil
+	}
+	if err != nil {
+		logger.Error(err, "Failed to reconcile certificate", "commonName", certificate.Spec.CommonName)
+		r.setCertificateCondition(&certificate, PhaseError, metav1.ConditionFalse, "ReconcileError", akamai.ErrorMessage(err))
+		if updateErr := r.Status().Update(ctx, &certificate); updateErr != nil {
+			return ctrl.Result{}, updateErr
+		}
+		return ctrl.Result{RequeueAfter: certificateErrorRetryInterval}, nil
+	}
+
+	if change := certificate.Status.PendingChange; change != nil {
+		phase := PhaseUpdating
+		if certificate.Status.Production == nil {
+			phase = PhaseCreating
+		}
+		message := fmt.Sprintf("Enrollment %d has a pending change: %s", certificate.Status.EnrollmentID, change.Status)
+		if change.Description != "" {
+			message += " (" + change.Description + ")"
+		}
+		if len(change.RequiredInput) > 0 {
+			message += "; waiting for " + strings.Join(change.RequiredInput, ", ")
+		}
+		r.setCertificateCondition(&certificate, phase, metav1.ConditionFalse, "ChangeInProgress", message)
+		if err := r.Status().Update(ctx, &certificate); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{RequeueAfter: certificateChangePollInterval}, nil
+	}
+	r.setCertificateCondition(&certificate, PhaseReady, metav1.ConditionTrue, "CertificateReady",
+		fmt.Sprintf("Enrollment %d is up to date", certificate.Status.EnrollmentID))
+	if err := r.Status().Update(ctx, &certificate); err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{RequeueAfter: certificateResyncInterval}, nil
+}
+
+// syncCertificate creates, adopts or updates the enrollment and records its pending change and
+// deployments in the status
+func (r *AkamaiCertificateReconciler) syncCertificate(ctx context.Context, certificate *akamaiV1alpha1.AkamaiCertificate) error {
+	logger := log.FromContext(ctx)
+	spec := certificate.Spec
+	desired := certificateEnrollment(certificate)
+
+	var enrollment *cps.Enrollment
+	var err error
+	if certificate.Status.EnrollmentID != 0 {
+		if enrollment, err = r.AkamaiClient.GetEnrollment(ctx, certificate.Status.EnrollmentID); err != nil {
+			return err
+		}
+		if enrollment == nil {
+			logger.Info("Enrollment was removed outside the operator, recreating it", "enrollmentId", certificate.Status.EnrollmentID)
+			certificate.Status = akamaiV1alpha1.AkamaiCertificateStatus{Conditions: certificate.Status.Conditions}
+		}
+	}
+	if enrollment == nil {
+		if enrollment, err = r.AkamaiClient.FindEnrollment(ctx, spec.ContractID, spec.CommonName); err != nil {
+			return err
+		}
+		if enrollment != nil {
+			logger.Info("Adopting existing enrollment", "commonName", spec.CommonName, "enrollmentId", enrollment.ID)
+		} else if r.ObserveOnly {
+			return observedDifference(fmt.Sprintf("No enrollment for %s exists in CPS", spec.CommonName))
+		} else {
+			enrollmentID, err := r.AkamaiClient.CreateEnrollment(ctx, spec.ContractID, desired)
+			if err != nil {
+				return err
+			}
+			logger.Info("Created enrollment", "commonName", spec.CommonName, "enrollmentId", enrollmentID)
+			if enrollment, err = r.AkamaiClient.GetEnrollment(ctx, enrollmentID); err != nil {
+				return err
+			}
+			if enrollment == nil {
+				return fmt.Errorf("enrollment %d was not found after it was created", enrollmentID)
+			}
+		}
+	}
+	certificate.Status.EnrollmentID = enrollment.ID
+
+	// Changes are queued by CPS one at a time; the spec is applied once the pending one completes
+	if len(enrollment.PendingChanges) == 0 && !enrollmentMatches(enrollment, desired) {
+		if r.ObserveOnly {
+			return observedDifference(fmt.Sprintf("Enrollment %d differs from the spec", enrollment.ID))
+		}
+		if err := r.AkamaiClient.UpdateEnrollment(ctx, enrollment.ID, desired); err != nil {
+			return err
+		}
+		logger.Info("Updated enrollment", "enrollmentId", enrollment.ID, "sans", desired.CSR.SANS)
+		if enrollment, err = r.AkamaiClient.GetEnrollment(ctx, enrollment.ID); err != nil {
+			return err
+		}
+		if enrollment == nil {
+			return fmt.Errorf("enrollment %d was not found after it was updated", certificate.Status.EnrollmentID)
+		}
+	}
+	certificate.Status.SANs = nil
+	if enrollment.CSR != nil {
+		certificate.Status.SANs = enrollment.CSR.SANS
+	}
+
+	certificate.Status.PendingChange = nil
+	if len(enrollment.PendingChanges) > 0 {
+		change, err := r.AkamaiClient.GetEnrollmentChange(ctx, enrollment.ID, enrollment.PendingChanges[0].Location)
+		if err != nil {
+			return err
+		}
+		certificate.Status.PendingChange = certificateChangeStatus(enrollment.PendingChanges[0], change)
+		if certificate.Status.PendingChange.Error != "" {
+			return fmt.Errorf("change %s of enrollment %d failed: %s", enrollment.PendingChanges[0].ChangeType,
+				enrollment.ID, certificate.Status.PendingChange.Error)
+		}
+	}
+
+	if certificate.Status.Staging, err = r.certificateDeployment(ctx, enrollment.ID, akamai.CPSNetworkStaging); err != nil {
+		return err
+	}
+	certificate.Status.Production, err = r.certificateDeployment(ctx, enrollment.ID, akamai.CPSNetworkProduction)
+	return err
+}
+
+// certificateDeployment returns the certificate of the enrollment deployed on a network, nil when
+// none is
+func (r *AkamaiCertificateReconciler) certificateDeployment(ctx context.Context, enrollmentID int, network string) (*akamaiV1alpha1.CertificateDeploymentStatus, error) {
+	deployment, err := r.AkamaiClient.GetEnrollmentDeployment(ctx, enrollmentID, network)
+	if err != nil || deployment == nil {
+		return nil, err
+	}
+	return &akamaiV1alpha1.CertificateDeploymentStatus{
+		Expiry:   deployment.PrimaryCertificate.Expiry,
+		DNSNames: deployment.NetworkConfiguration.DNSNames,
+	}, nil
+}
+
+// handleDeletion removes the enrollment with the Delete deletion policy and removes the finalizer
+func (r *AkamaiCertificateReconciler) handleDeletion(ctx context.Context, certificate *akamaiV1alpha1.AkamaiCertificate) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+	if !controllerutil.ContainsFinalizer(certificate, CertificateFinalizerName) {
+		return ctrl.Result{}, nil
+	}
+
+	// Nothing is removed from CPS in observe-only mode
+	if certificate.Spec.DeletionPolicy == akamaiV1alpha1.DeletionPolicyDelete && certificate.Status.EnrollmentID != 0 && !r.ObserveOnly {
+		if err := r.AkamaiClient.RemoveEnrollment(ctx, certificate.Status.EnrollmentID); err != nil {
+			logger.Error(err, "Failed to remove enrollment", "enrollmentId", certificate.Status.EnrollmentID)
+			r.setCertificateCondition(certificate, PhaseDeleting, metav1.ConditionFalse, "DeletionFailed", akamai.ErrorMessage(err))
+			if updateErr := r.Status().Update(ctx, certificate); updateErr != nil {
+				return ctrl.Result{}, updateErr
+			}
+			return ctrl.Result{RequeueAfter: certificateErrorRetryInterval}, nil
+		}
+		logger.Info("Removed enrollment", "enrollmentId", certificate.Status.EnrollmentID)
+	}
+
+	controllerutil.RemoveFinalizer(certificate, CertificateFinalizerName)
+	return ctrl.Result{}, r.Update(ctx, certificate)
+}
+
+// certificateEnrollment returns the enrollment of the spec
+func certificateEnrollment(certificate *akamaiV1alpha1.AkamaiCertificate) cps.EnrollmentRequestBody {
+	spec := certificate.Spec
+	org := spec.Organization
+	sniOnly := spec.SNIOnly == nil || *spec.SNIOnly
+	geography := spec.Geography
+	if geography == "" {
+		geography = "core"
+	}
+	secureNetwork := spec.SecureNetwork
+	if secureNetwork == "" {
+		secureNetwork = "enhanced-tls"
+	}
+	certificateType := "san"
+	var thirdParty *cps.ThirdParty
+	if spec.ValidationType == "third-party" {
+		certificateType = "third-party"
+		thirdParty = &cps.ThirdParty{}
+	}
+
+	return cps.EnrollmentRequestBody{
+		RA:                 certificateRegistrationAuthorities[spec.ValidationType],
+		ValidationType:     spec.ValidationType,
+		CertificateType:    certificateType,
+		ThirdParty:         thirdParty,
+		SignatureAlgorithm: "SHA-256",
+		ChangeManagement:   spec.ChangeManagement,
+		CSR: &cps.CSR{
+			CN:   spec.CommonName,
+			SANS: certificateSANs(certificate),
+			C:    org.Country,
+			ST:   org.Region,
+			L:    org.City,
+			O:    org.Name,
+		},
+		NetworkConfiguration: &cps.NetworkConfiguration{
+			Geography:       geography,
+			SecureNetwork:   secureNetwork,
+			SNIOnly:         sniOnly,
+			DNSNameSettings: &cps.DNSNameSettings{CloneDNSNames: true},
+		},
+		Org: &cps.Org{
+			Name:           org.Name,
+			AddressLineOne: org.AddressLineOne,
+			AddressLineTwo: org.AddressLineTwo,
+			City:           org.City,
+			Region:         org.Region,
+			PostalCode:     org.PostalCode,
+			Country:        org.Country,
+			Phone:          org.Phone,
+		},
+		AdminContact: certificateContact(spec.AdminContact),
+		TechContact:  certificateContact(spec.TechContact),
+	}
+}
+
+// certificateSANs returns the sorted SANs of the spec, which always include the common name
+func certificateSANs(certificate *akamaiV1alpha1.AkamaiCertificate) []string {
+	sans := append([]string{certificate.Spec.CommonName}, certificate.Spec.SANs...)
+	slices.Sort(sans)
+	return slices.Compact(sans)
+}
+
+// certificateContact converts a contact of the spec
+func certificateContact(contact akamaiV1alpha1.CertificateContact) *cps.Contact {
+	return &cps.Contact{
+		FirstName:        contact.FirstName,
+		LastName:         contact.LastName,
+		Email:            contact.Email,
+		Phone:            contact.Phone,
+		Title:            contact.Title,
+		OrganizationName: contact.OrganizationName,
+		AddressLineOne:   contact.AddressLineOne,
+		AddressLineTwo:   contact.AddressLineTwo,
+		City:             contact.City,
+		Region:           contact.Region,
+		PostalCode:       contact.PostalCode,
+		Country:          contact.Country,
+	}
+}
+
+// enrollmentMatches reports whether the enrollment has the SANs, network settings and change
+// management of the desired enrollment. Organization and contacts are only compared as part of
+// an update.
+func enrollmentMatches(enrollment *cps.Enrollment, desired cps.EnrollmentRequestBody) bool {
+	if enrollment.CSR == nil || enrollment.NetworkConfiguration == nil {
+		return false
+	}
+	sans := slices.Clone(enrollment.CSR.SANS)
+	slices.Sort(sans)
+	network := enrollment.NetworkConfiguration
+	return slices.Equal(slices.Compact(sans), desired.CSR.SANS) &&
+		enrollment.ChangeManagement == desired.ChangeManagement &&
+		network.Geography == desired.NetworkConfiguration.Geography &&
+		network.SNIOnly == desired.NetworkConfiguration.SNIOnly
+}
+
+// certificateChangeStatus converts the status of a pending change of an enrollment
+func certificateChangeStatus(pending cps.PendingChange, change *cps.Change) *akamaiV1alpha1.CertificateChangeStatus {
+	status := &akamaiV1alpha1.CertificateChangeStatus{Status: pending.ChangeType}
+	if change == nil {
+		return status
+	}
+	if info := change.StatusInfo; info != nil {
+		status.Status = info.Status
+		status.Description = info.Description
+		if info.Error != nil {
+			status.Error = strings.TrimSpace(info.Error.Code + " " + info.Error.Description)
+		}
+	}
+	for _, input := range change.AllowedInput {
+		if input.RequiredToProceed {
+			status.RequiredInput = append(status.RequiredInput, input.Type)
+		}
+	}
+	return status
+}
+
+// certificateEnrollmentID returns the enrollment ID of an AkamaiCertificate referenced by name
+func certificateEnrollmentID(ctx context.Context, c client.Reader, name string) (int, error) {
+	var certificate akamaiV1alpha1.AkamaiCertificate
+	if err := c.Get(ctx, types.NamespacedName{Name: name}, &certificate); err != nil {
+		if apierrors.IsNotFound(err) {
+			return 0, fmt.Errorf("AkamaiCertificate %q not found", name)
+		}
+		return 0, fmt.Errorf("failed to get AkamaiCertificate %q: %w", name, err)
+	}
+	if certificate.Status.EnrollmentID == 0 {
+		return 0, fmt.Errorf("AkamaiCertificate %q has not been created in Akamai yet", name)
+	}
+	return certificate.Status.EnrollmentID, nil
+}
+
+// setCertificateCondition sets the phase and the Ready condition of the certificate
+func (r *AkamaiCertificateReconciler) setCertificateCondition(certificate *akamaiV1alpha1.AkamaiCertificate, phase string, status metav1.ConditionStatus, reason, message string) {
+	setReadyCondition(certificate.Generation, readyStatus{
+		Phase:              &certificate.Status.Phase,
+		ObservedGeneration: &certificate.Status.ObservedGeneration,
+		LastUpdated:        &certificate.Status.LastUpdated,
+		Conditions:         &certificate.Status.Conditions,
+	}, phase, status, reason, message)
+}
+
+// SetupWithManager sets up the controller with the Manager. Status updates don't trigger
+// reconciles; certificates are requeued to follow their pending changes and deployments.
+func (r *AkamaiCertificateReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&akamaiV1alpha1.AkamaiCertificate{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Complete(r)
+}
@@ -0,0 +1,415 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v8/pkg/clientlists"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+	"github.com/mmz-srf/akamai-operator/pkg/akamai"
+)
+
+const (
+	// clientListResyncInterval is how often a client list is compared with Akamai
+	clientListResyncInterval = 10 * time.Minute
+
+	// clientListActivationPollInterval is how often a running activation is checked
+	clientListActivationPollInterval = time.Minute
+
+	// clientListErrorRetryInterval is how long a failed client list reconcile waits before it is retried
+	clientListErrorRetryInterval = 2 * time.Minute
+)
+
+// AkamaiClientListReconciler creates Akamai client lists, keeps their items, notes and tags in
+// line with the spec and activates each change on the networks the spec selects
+type AkamaiClientListReconciler struct {
+	client.Client
+	Scheme       *runtime.Scheme
+	AkamaiClient *akamai.Client
+
+	// Credentials selects the credential source the Akamai client is created with
+	Credentials akamai.Credentials
+
+	// Shard is the part of the fleet this instance manages; nil manages all client lists
+	Shard *Shard
+
+	// ObserveOnly compares the client lists with Akamai and reports differences without changing them
+	ObserveOnly bool
+}
+
+//+kubebuilder:rbac:groups=akamai.com,resources=akamaiclientlists,verbs=get;list;watch;update;patch
+//+kubebuilder:rbac:groups=akamai.com,resources=akamaiclientlists/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=akamai.com,resources=akamaiclientlists/finalizers,verbs=update
+
+// Reconcile brings an Akamai client list to the state of its AkamaiClientList
+func (r *AkamaiClientListReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	var list akamaiV1alpha1.AkamaiClientList
+	if err := r.Get(ctx, req.NamespacedName, &list); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	// Client lists of other shards are left to the instances managing them
+	if !r.Shard.Contains(list.Labels, list.Spec.ContractID) {
+		logger.V(1).Info("Client list belongs to another shard", "shard", r.Shard.Name)
+		return ctrl.Result{}, nil
+	}
+
+	if r.AkamaiClient == nil {
+		akamaiClient, err := akamai.NewClient(r.Credentials)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		r.AkamaiClient = akamaiClient
+	}
+
+	if list.DeletionTimestamp != nil {
+		return r.handleDeletion(ctx, &list)
+	}
+	// The finalizer is added before the list is created, so it is never left behind
+	if !controllerutil.ContainsFinalizer(&list, ClientListFinalizerName) {
+		controllerutil.AddFinalizer(&list, ClientListFinalizerName)
+		if err := r.Update(ctx, &list); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	activating, err := r.syncClientList(ctx, &list)
+	if message, observed := observeOnlyMessage(r.ObserveOnly, err); observed && !activating {
+		r.setClientListCondition(&list, PhaseObserving, metav1.ConditionFalse, ReasonObserveOnly, message)
+		if err := r.Status().Update(ctx, &list); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{RequeueAfter: clientListResyncInterval}, nil
+	}
+	if err != nil {
+		logger.Error(err, "Failed to reconcile client list", "name", clientListName(&list))
+		r.setClientListCondition(&list, PhaseError, metav1.ConditionFalse, "ReconcileError", akamai.ErrorMessage(err))
+		if updateErr := r.Status().Update(ctx, &list); updateErr != nil {
+			return ctrl.Result{}, updateErr
+		}
+		return ctrl.Result{RequeueAfter: clientListErrorRetryInterval}, nil
+	}
+
+	if activating {
+		r.setClientListCondition(&list, PhaseActivating, metav1.ConditionFalse, "ActivationInProgress",
+			fmt.Sprintf("Client list %s version %d is being activated", list.Status.ListID, list.Status.Version))
+		if err := r.Status().Update(ctx, &list); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{RequeueAfter: clientListActivationPollInterval}, nil
+	}
+	r.setClientListCondition(&list, PhaseReady, metav1.ConditionTrue, "ClientListReady",
+		fmt.Sprintf("Client list %s is up to date", list.Status.ListID))
+	if err := r.Status().Update(ctx, &list); err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{RequeueAfter: clientListResyncInterval}, nil
+}
+
+// syncClientList creates, adopts or updates the client list, activates it and records its state
+// in the status. It reports whether an activation is still running.
+func (r *AkamaiClientListReconciler) syncClientList(ctx context.Context, list *akamaiV1alpha1.AkamaiClientList) (bool, error) {
+	logger := log.FromContext(ctx)
+	name := clientListName(list)
+
+	var current *clientlists.GetClientListResponse
+	listID := list.Status.ListID
+	if listID != "" {
+		var err error
+		if current, err = r.AkamaiClient.GetClientList(ctx, listID); err != nil {
+			return false, err
+		}
+		if current == nil {
+			logger.Info("Client list was deleted outside the operator, recreating it", "listId", listID)
+			listID = ""
+		}
+	}
+	if current == nil {
+		var err error
+		if listID, err = r.AkamaiClient.FindClientList(ctx, name, list.Spec.Type); err != nil {
+			return false, err
+		}
+		if listID != "" {
+			logger.Info("Adopting existing client list", "name", name, "listId", listID)
+		} else if r.ObserveOnly {
+			return false, observedDifference(fmt.Sprintf("Client list %s doesn't exist in Akamai", name))
+		} else {
+			groupID, err := numericGroupID(list.Spec.GroupID)
+			if err != nil {
+				return false, err
+			}
+			if listID, err = r.AkamaiClient.CreateClientList(ctx, clientlists.CreateClientListRequest{
+				ContractID: strings.TrimPrefix(list.Spec.ContractID, "ctr_"),
+				GroupID:    int64(groupID),
+				Name:       name,
+				Type:       clientlists.ClientListType(list.Spec.Type),
+				Notes:      list.Spec.Notes,
+				Tags:       list.Spec.Tags,
+				Items:      clientListItems(list),
+			}); err != nil {
+				return false, err
+			}
+			logger.Info("Created client list", "name", name, "listId", listID)
+		}
+		if current, err = r.AkamaiClient.GetClientList(ctx, listID); err != nil {
+			return false, err
+		}
+		if current == nil {
+			return false, fmt.Errorf("client list %s was created but can't be found", listID)
+		}
+	}
+
+	updated := false
+	if current.Name != name || current.Notes != list.Spec.Notes || !sameTags(current.Tags, list.Spec.Tags) {
+		if r.ObserveOnly {
+			return false, observedDifference(fmt.Sprintf("The name, notes or tags of client list %s differ from the spec", current.ListID))
+		}
+		if err := r.AkamaiClient.UpdateClientList(ctx, current.ListID, clientlists.UpdateClientList{
+			Name:  name,
+			Notes: list.Spec.Notes,
+			Tags:  list.Spec.Tags,
+		}); err != nil {
+			return false, err
+		}
+		logger.Info("Updated client list", "listId", current.ListID)
+		updated = true
+	}
+	if changes := clientListItemChanges(list, current); len(changes.Append)+len(changes.Update)+len(changes.Delete) > 0 {
+		if r.ObserveOnly {
+			return false, observedDifference(fmt.Sprintf("Client list %s has %d items to append, %d to update and %d to delete",
+				current.ListID, len(changes.Append), len(changes.Update), len(changes.Delete)))
+		}
+		if err := r.AkamaiClient.UpdateClientListItems(ctx, current.ListID, changes); err != nil {
+			return false, err
+		}
+		logger.Info("Updated client list items", "listId", current.ListID,
+			"appended", len(changes.Append), "updated", len(changes.Update), "deleted", len(changes.Delete))
+		updated = true
+	}
+	if updated {
+		refreshed, err := r.AkamaiClient.GetClientList(ctx, current.ListID)
+		if err != nil {
+			return false, err
+		}
+		if refreshed == nil {
+			return false, fmt.Errorf("client list %s was updated but can't be found", current.ListID)
+		}
+		current = refreshed
+	}
+
+	list.Status.ListID = current.ListID
+	list.Status.Version = current.Version
+	list.Status.ItemCount = current.ItemsCount
+
+	activation := list.Spec.Activation
+	if activation == nil {
+		activation = &akamaiV1alpha1.ClientListActivationSpec{}
+	}
+	stagingActivating, err := r.activateClientList(ctx, list, activation, string(clientlists.Staging), activation.Staging, &list.Status.Staging)
+	if err != nil {
+		return false, err
+	}
+	productionActivating, err := r.activateClientList(ctx, list, activation, string(clientlists.Production), activation.Production, &list.Status.Production)
+	if err != nil {
+		return false, err
+	}
+	return stagingActivating || productionActivating, nil
+}
+
+// activateClientList activates the current version of the list on a network unless it is active
+// or being activated there, and records the activation state. It reports whether an activation
+// is running.
+func (r *AkamaiClientListReconciler) activateClientList(ctx context.Context, list *akamaiV1alpha1.AkamaiClientList, activation *akamaiV1alpha1.ClientListActivationSpec, network string, enabled bool, status **akamaiV1alpha1.ClientListActivationStatus) (bool, error) {
+	if !enabled {
+		*status = nil
+		return false, nil
+	}
+	state, err := r.AkamaiClient.GetClientListActivation(ctx, list.Status.ListID, network)
+	if err != nil {
+		return false, err
+	}
+	*status = &akamaiV1alpha1.ClientListActivationStatus{Status: string(state.ActivationStatus), Version: state.Version, ActivationID: state.ActivationID}
+	if state.Version == list.Status.Version {
+		switch state.ActivationStatus {
+		case clientlists.Active:
+			return false, nil
+		case clientlists.PendingActivation:
+			return true, nil
+		case clientlists.Failed:
+			// Activating the same version again would fail the same way
+			return false, fmt.Errorf("activation %d of client list %s version %d on %s failed",
+				state.ActivationID, list.Status.ListID, state.Version, network)
+		}
+	}
+
+	if r.ObserveOnly {
+		return false, observedDifference(fmt.Sprintf("Client list %s version %d isn't active on %s", list.Status.ListID, list.Status.Version, network))
+	}
+	comments := defaultNetworkListActivationComments
+	if activation.Comments != "" {
+		comments = activation.Comments
+	}
+	activated, err := r.AkamaiClient.ActivateClientList(ctx, list.Status.ListID, network, comments, activation.NotificationEmails)
+	if err != nil {
+		return false, err
+	}
+	log.FromContext(ctx).Info("Activating client list", "listId", list.Status.ListID, "network", network,
+		"version", list.Status.Version, "activationId", activated.ActivationID)
+	*status = &akamaiV1alpha1.ClientListActivationStatus{Status: string(activated.ActivationStatus), Version: activated.Version, ActivationID: activated.ActivationID}
+	return activated.ActivationStatus != clientlists.Active, nil
+}
+
+// handleDeletion deletes the client list with the Delete deletion policy and removes the finalizer
+func (r *AkamaiClientListReconciler) handleDeletion(ctx context.Context, list *akamaiV1alpha1.AkamaiClientList) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+	if !controllerutil.ContainsFinalizer(list, ClientListFinalizerName) {
+		return ctrl.Result{}, nil
+	}
+
+	// Nothing is deleted in Akamai in observe-only mode
+	if list.Spec.DeletionPolicy == akamaiV1alpha1.DeletionPolicyDelete && list.Status.ListID != "" && !r.ObserveOnly {
+		if err := r.AkamaiClient.DeleteClientList(ctx, list.Status.ListID); err != nil {
+			logger.Error(err, "Failed to delete client list", "listId", list.Status.ListID)
+			r.setClientListCondition(list, PhaseDeleting, metav1.ConditionFalse, "DeletionFailed", akamai.ErrorMessage(err))
+			if updateErr := r.Status().Update(ctx, list); updateErr != nil {
+				return ctrl.Result{}, updateErr
+			}
+			return ctrl.Result{RequeueAfter: clientListErrorRetryInterval}, nil
+		}
+		logger.Info("Deleted client list", "listId", list.Status.ListID)
+	}
+
+	controllerutil.RemoveFinalizer(list, ClientListFinalizerName)
+	return ctrl.Result{}, r.Update(ctx, list)
+}
+
+// clientListName returns the name of the client list, defaulting to the name of the resource
+func clientListName(list *akamaiV1alpha1.AkamaiClientList) string {
+	if list.Spec.Name != "" {
+		return list.Spec.Name
+	}
+	return list.Name
+}
+
+// clientListValue returns the canonical form of an item value: country codes upper case, the
+// other types lower case
+func clientListValue(listType, value string) string {
+	value = strings.TrimSpace(value)
+	if listType == string(clientlists.GEO) {
+		return strings.ToUpper(value)
+	}
+	return strings.ToLower(value)
+}
+
+// clientListItems returns the items of the spec with canonical values, sorted by value. Of
+// duplicate values the first item is kept; expired items are left out, as Akamai refuses them.
+func clientListItems(list *akamaiV1alpha1.AkamaiClientList) []clientlists.ListItemPayload {
+	now := time.Now()
+	items := make([]clientlists.ListItemPayload, 0, len(list.Spec.Items))
+	seen := make(map[string]bool, len(list.Spec.Items))
+	for _, item := range list.Spec.Items {
+		value := clientListValue(list.Spec.Type, item.Value)
+		if seen[value] {
+			continue
+		}
+		if expiration, err := time.Parse(time.RFC3339, item.ExpirationDate); err == nil && !expiration.After(now) {
+			continue
+		}
+		seen[value] = true
+		items = append(items, clientlists.ListItemPayload{
+			Value:          value,
+			Tags:           item.Tags,
+			Description:    item.Description,
+			ExpirationDate: item.ExpirationDate,
+		})
+	}
+	slices.SortFunc(items, func(a, b clientlists.ListItemPayload) int { return strings.Compare(a.Value, b.Value) })
+	return items
+}
+
+// clientListItemChanges returns the items to append to, update in and delete from the client
+// list in Akamai to match the spec. Items are matched by their canonical value.
+func clientListItemChanges(list *akamaiV1alpha1.AkamaiClientList, current *clientlists.GetClientListResponse) clientlists.UpdateClientListItems {
+	existing := make(map[string]clientlists.ListItemContent, len(current.Items))
+	for _, item := range current.Items {
+		existing[clientListValue(list.Spec.Type, item.Value)] = item
+	}
+
+	var changes clientlists.UpdateClientListItems
+	for _, item := range clientListItems(list) {
+		currentItem, ok := existing[item.Value]
+		switch {
+		case !ok:
+			changes.Append = append(changes.Append, item)
+		case currentItem.Description != item.Description || !sameTags(currentItem.Tags, item.Tags) ||
+			!sameExpirationDate(currentItem.ExpirationDate, item.ExpirationDate):
+			// Akamai matches the item by the value it stores
+			item.Value = currentItem.Value
+			changes.Update = append(changes.Update, item)
+		}
+		delete(existing, item.Value)
+	}
+	for _, item := range current.Items {
+		if _, ok := existing[clientListValue(list.Spec.Type, item.Value)]; ok {
+			changes.Delete = append(changes.Delete, clientlists.ListItemPayload{Value: item.Value})
+		}
+	}
+	return changes
+}
+
+// sameTags reports whether two sets of tags are equal, ignoring their order
+func sameTags(a, b []string) bool {
+	a, b = slices.Clone(a), slices.Clone(b)
+	slices.Sort(a)
+	slices.Sort(b)
+	return slices.Equal(a, b)
+}
+
+// sameExpirationDate reports whether two expiration dates are the same point in time. Akamai
+// may return them in another notation, e.g. with milliseconds, so dates that parse are compared
+// as times.
+func sameExpirationDate(a, b string) bool {
+	timeA, errA := time.Parse(time.RFC3339, a)
+	timeB, errB := time.Parse(time.RFC3339, b)
+	if errA != nil || errB != nil {
+		return a == b
+	}
+	return timeA.Equal(timeB)
+}
+
+// setClientListCondition sets the phase and the Ready condition of the client list
+func (r *AkamaiClientListReconciler) setClientListCondition(list *akamaiV1alpha1.AkamaiClientList, phase string, status metav1.ConditionStatus, reason, message string) {
+	setReadyCondition(list.Generation, readyStatus{
+		Phase:              &list.Status.Phase,
+		ObservedGeneration: &list.Status.ObservedGeneration,
+		LastUpdated:        &list.Status.LastUpdated,
+		Conditions:         &list.Status.Conditions,
+	}, phase, status, reason, message)
+}
+
+// SetupWithManager sets up the controller with the Manager. Status updates don't trigger
+// reconciles; client lists are requeued to follow activations and changes made outside the
+// operator.
+func (r *AkamaiClientListReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&akamaiV1alpha1.AkamaiClientList{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Complete(r)
+}
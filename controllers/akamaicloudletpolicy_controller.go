@@ -0,0 +1,446 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	cloudlets "github.com/akamai/AkamaiOPEN-edgegrid-golang/v8/pkg/cloudlets/v3"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+	"github.com/mmz-srf/akamai-operator/pkg/akamai"
+)
+
+const (
+	// cloudletPolicyResyncInterval is how often a Cloudlets policy is compared with Akamai
+	cloudletPolicyResyncInterval = 10 * time.Minute
+
+	// cloudletPolicyActivationPollInterval is how often a running activation is checked
+	cloudletPolicyActivationPollInterval = time.Minute
+
+	// cloudletPolicyErrorRetryInterval is how long a failed Cloudlets policy reconcile waits before
+	// it is retried
+	cloudletPolicyErrorRetryInterval = 2 * time.Minute
+)
+
+// cloudletMatchRuleTypes are the match rule types of the Cloudlets by cloudlet type
+var cloudletMatchRuleTypes = map[string]cloudlets.MatchRuleType{
+	"ER": cloudlets.MatchRuleTypeER,
+	"CD": cloudlets.MatchRuleTypePR,
+	"AS": cloudlets.MatchRuleTypeAS,
+}
+
+// AkamaiCloudletPolicyReconciler creates Cloudlets shared policies, writes the match rules of the
+// spec to a policy version and activates it on the networks the spec selects
+type AkamaiCloudletPolicyReconciler struct {
+	client.Client
+	Scheme       *runtime.Scheme
+	AkamaiClient *akamai.Client
+
+	// Credentials selects the credential source the Akamai client is created with
+	Credentials akamai.Credentials
+
+	// Shard is the part of the fleet this instance manages; nil manages all Cloudlets policies
+	Shard *Shard
+
+	// ObserveOnly compares the Cloudlets policies with Akamai and reports differences without
+	// changing or activating them
+	ObserveOnly bool
+}
+
+//+kubebuilder:rbac:groups=akamai.com,resources=akamaicloudletpolicies,verbs=get;list;watch;update;patch
+//+kubebuilder:rbac:groups=akamai.com,resources=akamaicloudletpolicies/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=akamai.com,resources=akamaicloudletpolicies/finalizers,verbs=update
+
+// Reconcile brings a Cloudlets shared policy to the state of its AkamaiCloudletPolicy
+func (r *AkamaiCloudletPolicyReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	var policy akamaiV1alpha1.AkamaiCloudletPolicy
+	if err := r.Get(ctx, req.NamespacedName, &policy); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	// Cloudlets policies of other shards are left to the instances managing them
+	if !r.Shard.containsAccountWide(policy.Labels) {
+		logger.V(1).Info("Cloudlet policy belongs to another shard", "shard", r.Shard.Name)
+		return ctrl.Result{}, nil
+	}
+
+	if r.AkamaiClient == nil {
+		akamaiClient, err := akamai.NewClient(r.Credentials)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		r.AkamaiClient = akamaiClient
+	}
+
+	if policy.DeletionTimestamp != nil {
+		return r.handleDeletion(ctx, &policy)
+	}
+	// The finalizer is added before the policy is created, so it is never left behind
+	if !controllerutil.ContainsFinalizer(&policy, CloudletPolicyFinalizerName) {
+		controllerutil.AddFinalizer(&policy, CloudletPolicyFinalizerName)
+		if err := r.Update(ctx, &policy); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	activating, err := r.syncCloudletPolicy(ctx, &policy)
+	if message, observed := observeOnlyMessage(r.ObserveOnly, err); observed {
+		r.setCloudletPolicyCondition(&policy, PhaseObserving, metav1.ConditionFalse, ReasonObserveOnly, message)
+		if err := r.Status().Update(ctx, &policy); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{RequeueAfter: cloudletPolicyResyncInterval}, nil
+	}
+	if err != nil {
+		logger.Error(err, "Failed to reconcile cloudlet policy", "name", cloudletPolicyName(&policy))
+		r.setCloudletPolicyCondition(&policy, PhaseError, metav1.ConditionFalse, "ReconcileError", akamai.ErrorMessage(err))
+		if updateErr := r.Status().Update(ctx, &policy); updateErr != nil {
+			return ctrl.Result{}, updateErr
+		}
+		return ctrl.Result{RequeueAfter: cloudletPolicyErrorRetryInterval}, nil
+	}
+
+	if activating {
+		r.setCloudletPolicyCondition(&policy, PhaseActivating, metav1.ConditionFalse, "ActivationInProgress",
+			fmt.Sprintf("Cloudlet policy %d version %d is being activated", policy.Status.PolicyID, policy.Status.Version))
+		if err := r.Status().Update(ctx, &policy); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{RequeueAfter: cloudletPolicyActivationPollInterval}, nil
+	}
+	r.setCloudletPolicyCondition(&policy, PhaseReady, metav1.ConditionTrue, "CloudletPolicyReady",
+		fmt.Sprintf("Cloudlet policy %d is up to date", policy.Status.PolicyID))
+	if err := r.Status().Update(ctx, &policy); err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{RequeueAfter: cloudletPolicyResyncInterval}, nil
+}
+
+// syncCloudletPolicy creates, adopts or updates the policy, writes the match rules to a version,
+// activates it and records the state in the status. It reports whether an activation is still
+// running.
+func (r *AkamaiCloudletPolicyReconciler) syncCloudletPolicy(ctx context.Context, policy *akamaiV1alpha1.AkamaiCloudletPolicy) (bool, error) {
+	logger := log.FromContext(ctx)
+	name := cloudletPolicyName(policy)
+
+	groupID, err := numericGroupID(policy.Spec.GroupID)
+	if err != nil {
+		return false, err
+	}
+	matchRules, err := cloudletMatchRules(policy)
+	if err != nil {
+		return false, err
+	}
+
+	var current *cloudlets.Policy
+	if policy.Status.PolicyID != 0 {
+		if current, err = r.AkamaiClient.GetCloudletPolicy(ctx, policy.Status.PolicyID); err != nil {
+			return false, err
+		}
+		if current == nil {
+			logger.Info("Cloudlet policy was deleted outside the operator, recreating it", "policyId", policy.Status.PolicyID)
+			policy.Status = akamaiV1alpha1.AkamaiCloudletPolicyStatus{Conditions: policy.Status.Conditions}
+		}
+	}
+	if current == nil {
+		if current, err = r.AkamaiClient.FindCloudletPolicy(ctx, name); err != nil {
+			return false, err
+		}
+		if current != nil {
+			if string(current.CloudletType) != policy.Spec.CloudletType {
+				return false, fmt.Errorf("cloudlet policy %s exists for cloudlet %s instead of %s", name, current.CloudletType, policy.Spec.CloudletType)
+			}
+			logger.Info("Adopting existing cloudlet policy", "name", name, "policyId", current.ID)
+		} else if r.ObserveOnly {
+			return false, observedDifference(fmt.Sprintf("Cloudlet policy %s doesn't exist in Akamai", name))
+		} else {
+			description := policy.Spec.Description
+			if current, err = r.AkamaiClient.CreateCloudletPolicy(ctx, cloudlets.CreatePolicyRequest{
+				Name:         name,
+				CloudletType: cloudlets.CloudletType(policy.Spec.CloudletType),
+				GroupID:      int64(groupID),
+				Description:  &description,
+			}); err != nil {
+				return false, err
+			}
+			logger.Info("Created cloudlet policy", "name", name, "policyId", current.ID)
+		}
+	}
+	policy.Status.PolicyID = current.ID
+
+	if current.GroupID != int64(groupID) || cloudletPolicyDescription(current) != policy.Spec.Description {
+		if r.ObserveOnly {
+			return false, observedDifference(fmt.Sprintf("The group or description of cloudlet policy %d differs from the spec", current.ID))
+		}
+		if err := r.AkamaiClient.UpdateCloudletPolicy(ctx, current.ID, int64(groupID), policy.Spec.Description); err != nil {
+			return false, err
+		}
+		logger.Info("Updated cloudlet policy", "policyId", current.ID)
+	}
+
+	// Versions that were activated can't be changed, so changed match rules go to a new version
+	version, err := r.AkamaiClient.GetLatestCloudletPolicyVersion(ctx, current.ID)
+	if err != nil {
+		return false, err
+	}
+	same, err := sameCloudletMatchRules(version, matchRules)
+	if err != nil {
+		return false, err
+	}
+	if !same && r.ObserveOnly {
+		return false, observedDifference(fmt.Sprintf("The match rules of cloudlet policy %d differ from the spec", current.ID))
+	}
+	if !same {
+		if version != nil && !version.Immutable {
+			if version, err = r.AkamaiClient.UpdateCloudletPolicyVersion(ctx, current.ID, version.PolicyVersion, policy.Spec.Description, matchRules); err != nil {
+				return false, err
+			}
+			logger.Info("Updated cloudlet policy version", "policyId", current.ID, "version", version.PolicyVersion)
+		} else {
+			if version, err = r.AkamaiClient.CreateCloudletPolicyVersion(ctx, current.ID, policy.Spec.Description, matchRules); err != nil {
+				return false, err
+			}
+			logger.Info("Created cloudlet policy version", "policyId", current.ID, "version", version.PolicyVersion)
+		}
+	}
+	policy.Status.Version = version.PolicyVersion
+	policy.Status.Warnings = nil
+	for _, warning := range version.MatchRulesWarnings {
+		policy.Status.Warnings = append(policy.Status.Warnings, strings.TrimSpace(warning.Title+": "+warning.Detail))
+	}
+
+	activation := policy.Spec.Activation
+	if activation == nil {
+		activation = &akamaiV1alpha1.CloudletPolicyActivationSpec{}
+	}
+	stagingActivating, err := r.activateCloudletPolicy(ctx, policy, cloudlets.StagingNetwork, activation.Staging, current.CurrentActivations.Staging, &policy.Status.Staging)
+	if err != nil {
+		return false, err
+	}
+	productionActivating, err := r.activateCloudletPolicy(ctx, policy, cloudlets.ProductionNetwork, activation.Production, current.CurrentActivations.Production, &policy.Status.Production)
+	if err != nil {
+		return false, err
+	}
+	return stagingActivating || productionActivating, nil
+}
+
+// activateCloudletPolicy activates the version of the status on a network unless its last
+// activation there is of that version, and records the activation state. It reports whether an
+// activation is running.
+func (r *AkamaiCloudletPolicyReconciler) activateCloudletPolicy(ctx context.Context, policy *akamaiV1alpha1.AkamaiCloudletPolicy, network cloudlets.Network, enabled bool, info cloudlets.ActivationInfo, status **akamaiV1alpha1.CloudletPolicyActivationStatus) (bool, error) {
+	if !enabled {
+		*status = nil
+		return false, nil
+	}
+	if latest := info.Latest; latest != nil && latest.Operation == cloudlets.OperationActivation && latest.PolicyVersion == policy.Status.Version {
+		*status = &akamaiV1alpha1.CloudletPolicyActivationStatus{Status: string(latest.Status), Version: latest.PolicyVersion, ActivationID: latest.ID}
+		switch latest.Status {
+		case cloudlets.ActivationStatusSuccess:
+			return false, nil
+		case cloudlets.ActivationStatusFailed:
+			// Activating the same version again would fail the same way
+			return false, fmt.Errorf("activation %d of cloudlet policy %d version %d on %s failed",
+				latest.ID, policy.Status.PolicyID, latest.PolicyVersion, network)
+		default:
+			return true, nil
+		}
+	}
+
+	if r.ObserveOnly {
+		return false, observedDifference(fmt.Sprintf("Cloudlet policy %d version %d isn't active on %s", policy.Status.PolicyID, policy.Status.Version, network))
+	}
+
+	activated, err := r.AkamaiClient.ActivateCloudletPolicy(ctx, policy.Status.PolicyID, policy.Status.Version, string(network))
+	if err != nil {
+		return false, err
+	}
+	log.FromContext(ctx).Info("Activating cloudlet policy", "policyId", policy.Status.PolicyID, "network", network,
+		"version", policy.Status.Version, "activationId", activated.ID)
+	*status = &akamaiV1alpha1.CloudletPolicyActivationStatus{Status: string(activated.Status), Version: activated.PolicyVersion, ActivationID: activated.ID}
+	return activated.Status != cloudlets.ActivationStatusSuccess, nil
+}
+
+// handleDeletion deactivates and deletes the policy with the Delete deletion policy and removes
+// the finalizer. Akamai only deletes policies that are active on no network.
+func (r *AkamaiCloudletPolicyReconciler) handleDeletion(ctx context.Context, policy *akamaiV1alpha1.AkamaiCloudletPolicy) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+	if !controllerutil.ContainsFinalizer(policy, CloudletPolicyFinalizerName) {
+		return ctrl.Result{}, nil
+	}
+
+	// Nothing is deactivated or deleted in Akamai in observe-only mode
+	if policy.Spec.DeletionPolicy == akamaiV1alpha1.DeletionPolicyDelete && policy.Status.PolicyID != 0 && !r.ObserveOnly {
+		deactivating, err := r.deactivateCloudletPolicy(ctx, policy)
+		if err == nil && !deactivating {
+			err = r.AkamaiClient.DeleteCloudletPolicy(ctx, policy.Status.PolicyID)
+		}
+		if err != nil {
+			logger.Error(err, "Failed to delete cloudlet policy", "policyId", policy.Status.PolicyID)
+			r.setCloudletPolicyCondition(policy, PhaseDeleting, metav1.ConditionFalse, "DeletionFailed", akamai.ErrorMessage(err))
+			if updateErr := r.Status().Update(ctx, policy); updateErr != nil {
+				return ctrl.Result{}, updateErr
+			}
+			return ctrl.Result{RequeueAfter: cloudletPolicyErrorRetryInterval}, nil
+		}
+		if deactivating {
+			r.setCloudletPolicyCondition(policy, PhaseDeleting, metav1.ConditionFalse, "DeactivationInProgress",
+				fmt.Sprintf("Cloudlet policy %d is being deactivated before it is deleted", policy.Status.PolicyID))
+			if err := r.Status().Update(ctx, policy); err != nil {
+				return ctrl.Result{}, err
+			}
+			return ctrl.Result{RequeueAfter: cloudletPolicyActivationPollInterval}, nil
+		}
+		logger.Info("Deleted cloudlet policy", "policyId", policy.Status.PolicyID)
+	}
+
+	controllerutil.RemoveFinalizer(policy, CloudletPolicyFinalizerName)
+	return ctrl.Result{}, r.Update(ctx, policy)
+}
+
+// deactivateCloudletPolicy deactivates the policy on the networks it is active on and reports
+// whether an activation or deactivation is still running
+func (r *AkamaiCloudletPolicyReconciler) deactivateCloudletPolicy(ctx context.Context, policy *akamaiV1alpha1.AkamaiCloudletPolicy) (bool, error) {
+	current, err := r.AkamaiClient.GetCloudletPolicy(ctx, policy.Status.PolicyID)
+	if err != nil || current == nil {
+		return false, err
+	}
+	running := false
+	for network, info := range map[cloudlets.Network]cloudlets.ActivationInfo{
+		cloudlets.StagingNetwork:    current.CurrentActivations.Staging,
+		cloudlets.ProductionNetwork: current.CurrentActivations.Production,
+	} {
+		if info.Latest != nil && info.Latest.Status == cloudlets.ActivationStatusInProgress {
+			running = true
+			continue
+		}
+		if effective := info.Effective; effective != nil && effective.Operation == cloudlets.OperationActivation {
+			if err := r.AkamaiClient.DeactivateCloudletPolicy(ctx, current.ID, effective.PolicyVersion, string(network)); err != nil {
+				return false, err
+			}
+			log.FromContext(ctx).Info("Deactivating cloudlet policy", "policyId", current.ID, "network", network)
+			running = true
+		}
+	}
+	return running, nil
+}
+
+// cloudletPolicyName returns the name of the policy, defaulting to the name of the resource with
+// the characters policy names don't allow replaced by underscores
+func cloudletPolicyName(policy *akamaiV1alpha1.AkamaiCloudletPolicy) string {
+	if policy.Spec.Name != "" {
+		return policy.Spec.Name
+	}
+	return strings.NewReplacer("-", "_", ".", "_").Replace(policy.Name)
+}
+
+// cloudletPolicyDescription returns the description of a policy in Akamai
+func cloudletPolicyDescription(policy *cloudlets.Policy) string {
+	if policy.Description == nil {
+		return ""
+	}
+	return *policy.Description
+}
+
+// cloudletMatchRules decodes the match rules of the spec, defaulting their type to the one of the
+// Cloudlet
+func cloudletMatchRules(policy *akamaiV1alpha1.AkamaiCloudletPolicy) (cloudlets.MatchRules, error) {
+	ruleType, ok := cloudletMatchRuleTypes[policy.Spec.CloudletType]
+	if !ok {
+		return nil, fmt.Errorf("unsupported cloudlet type %q", policy.Spec.CloudletType)
+	}
+	rules := make([]map[string]interface{}, 0, len(policy.Spec.MatchRules))
+	for i, raw := range policy.Spec.MatchRules {
+		rule := map[string]interface{}{}
+		if err := json.Unmarshal(raw.Raw, &rule); err != nil {
+			return nil, fmt.Errorf("invalid match rule %d: %w", i, err)
+		}
+		if _, ok := rule["type"]; !ok {
+			rule["type"] = string(ruleType)
+		} else if rule["type"] != string(ruleType) {
+			return nil, fmt.Errorf("match rule %d has type %v, expected %s", i, rule["type"], ruleType)
+		}
+		rules = append(rules, rule)
+	}
+	raw, err := json.Marshal(rules)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal match rules: %w", err)
+	}
+	matchRules := cloudlets.MatchRules{}
+	if err := json.Unmarshal(raw, &matchRules); err != nil {
+		return nil, fmt.Errorf("invalid match rules: %w", err)
+	}
+	if err := matchRules.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid match rules: %w", err)
+	}
+	return matchRules, nil
+}
+
+// sameCloudletMatchRules reports whether a policy version has the given match rules. Both are
+// compared in the form the Cloudlets API serializes them, without the IDs Akamai assigns to rules.
+func sameCloudletMatchRules(version *cloudlets.PolicyVersion, matchRules cloudlets.MatchRules) (bool, error) {
+	if version == nil {
+		return false, nil
+	}
+	current, err := normalizedCloudletMatchRules(version.MatchRules)
+	if err != nil {
+		return false, err
+	}
+	desired, err := normalizedCloudletMatchRules(matchRules)
+	if err != nil {
+		return false, err
+	}
+	return equality.Semantic.DeepEqual(current, desired), nil
+}
+
+// normalizedCloudletMatchRules returns the match rules as generic JSON without rule IDs
+func normalizedCloudletMatchRules(matchRules cloudlets.MatchRules) ([]map[string]interface{}, error) {
+	raw, err := json.Marshal(matchRules)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal match rules: %w", err)
+	}
+	rules := []map[string]interface{}{}
+	if err := json.Unmarshal(raw, &rules); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal match rules: %w", err)
+	}
+	for _, rule := range rules {
+		delete(rule, "id")
+	}
+	return rules, nil
+}
+
+// setCloudletPolicyCondition sets the phase and the Ready condition of the Cloudlets policy
+func (r *AkamaiCloudletPolicyReconciler) setCloudletPolicyCondition(policy *akamaiV1alpha1.AkamaiCloudletPolicy, phase string, status metav1.ConditionStatus, reason, message string) {
+	setReadyCondition(policy.Generation, readyStatus{
+		Phase:              &policy.Status.Phase,
+		ObservedGeneration: &policy.Status.ObservedGeneration,
+		LastUpdated:        &policy.Status.LastUpdated,
+		Conditions:         &policy.Status.Conditions,
+	}, phase, status, reason, message)
+}
+
+// SetupWithManager sets up the controller with the Manager. Status updates don't trigger
+// reconciles; Cloudlets policies are requeued to follow activations and changes made outside the
+// operator.
+func (r *AkamaiCloudletPolicyReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&akamaiV1alpha1.AkamaiCloudletPolicy{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Complete(r)
+}
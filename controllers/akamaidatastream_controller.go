@@ -0,0 +1,572 @@
+package controllers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v8/pkg/datastream"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+	"github.com/mmz-srf/akamai-operator/pkg/akamai"
+)
+
+const (
+	// dataStreamResyncInterval is how often a stream is compared with Akamai
+	dataStreamResyncInterval = 10 * time.Minute
+
+	// dataStreamActivationPollInterval is how often a running activation or deactivation is checked
+	dataStreamActivationPollInterval = time.Minute
+
+	// dataStreamErrorRetryInterval is how long a failed stream reconcile waits before it is retried
+	dataStreamErrorRetryInterval = 2 * time.Minute
+)
+
+// dataStreamSecretKeys are the Secret keys a destination type requires
+var dataStreamSecretKeys = map[string][]string{
+	string(datastream.DestinationTypeS3):      {"accessKey", "secretAccessKey"},
+	string(datastream.DestinationTypeSplunk):  {"eventCollectorToken"},
+	string(datastream.DestinationTypeDataDog): {"authToken"},
+}
+
+// AkamaiDataStreamReconciler creates DataStream 2 streams for the logs of properties, writes new
+// versions when their configuration changes and activates or deactivates them
+type AkamaiDataStreamReconciler struct {
+	client.Client
+	Scheme       *runtime.Scheme
+	AkamaiClient *akamai.Client
+
+	// Credentials selects the credential source the Akamai client is created with
+	Credentials akamai.Credentials
+
+	// Shard is the part of the fleet this instance manages; nil manages all streams
+	Shard *Shard
+
+	// ObserveOnly compares the streams with DataStream and reports differences without changing them
+	ObserveOnly bool
+}
+
+//+kubebuilder:rbac:groups=akamai.com,resources=akamaidatastreams,verbs=get;list;watch;update;patch
+//+kubebuilder:rbac:groups=akamai.com,resources=akamaidatastreams/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=akamai.com,resources=akamaidatastreams/finalizers,verbs=update
+//+kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch
+
+// Reconcile brings a DataStream stream to the state of its AkamaiDataStream
+func (r *AkamaiDataStreamReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	var stream akamaiV1alpha1.AkamaiDataStream
+	if err := r.Get(ctx, req.NamespacedName, &stream); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	// Streams of other shards are left to the instances managing them
+	if !r.Shard.Contains(stream.Labels, stream.Spec.ContractID) {
+		logger.V(1).Info("Stream belongs to another shard", "shard", r.Shard.Name)
+		return ctrl.Result{}, nil
+	}
+
+	if r.AkamaiClient == nil {
+		akamaiClient, err := akamai.NewClient(r.Credentials)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		r.AkamaiClient = akamaiClient
+	}
+
+	if stream.DeletionTimestamp != nil {
+		return r.handleDeletion(ctx, &stream)
+	}
+	// The finalizer is added before the stream is created, so it is never left behind
+	if !controllerutil.ContainsFinalizer(&stream, DataStreamFinalizerName) {
+		controllerutil.AddFinalizer(&stream, DataStreamFinalizerName)
+		if err := r.Update(ctx, &stream); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	activating, err := r.syncDataStream(ctx, &stream)
+	if message, observed := observeOnlyMessage(r.ObserveOnly, err); observed && !activating {
+		r.setDataStreamCondition(&stream, PhaseObserving, metav1.ConditionFalse, ReasonObserveOnly, message)
+		if err := r.Status().Update(ctx, &stream); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{RequeueAfter: dataStreamResyncInterval}, nil
+	}
+	if err != nil {
+		logger.Error(err, "Failed to reconcile stream", "streamName", dataStreamName(&stream))
+		r.setDataStreamCondition(&stream, PhaseError, metav1.ConditionFalse, "ReconcileError", akamai.ErrorMessage(err))
+		if updateErr := r.Status().Update(ctx, &stream); updateErr != nil {
+			return ctrl.Result{}, updateErr
+		}
+		return ctrl.Result{RequeueAfter: dataStreamErrorRetryInterval}, nil
+	}
+
+	if activating {
+		r.setDataStreamCondition(&stream, PhaseActivating, metav1.ConditionFalse, "ActivationInProgress",
+			fmt.Sprintf("Stream %d version %d is %s", stream.Status.StreamID, stream.Status.StreamVersion, strings.ToLower(stream.Status.StreamStatus)))
+		if err := r.Status().Update(ctx, &stream); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{RequeueAfter: dataStreamActivationPollInterval}, nil
+	}
+	r.setDataStreamCondition(&stream, PhaseReady, metav1.ConditionTrue, "DataStreamReady",
+		fmt.Sprintf("Stream %d version %d is %s", stream.Status.StreamID, stream.Status.StreamVersion, strings.ToLower(stream.Status.StreamStatus)))
+	if err := r.Status().Update(ctx, &stream); err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{RequeueAfter: dataStreamResyncInterval}, nil
+}
+
+// syncDataStream creates or adopts the stream, writes a new version when the configuration
+// changed, activates or deactivates it and records the state in the status. It reports whether an
+// activation or deactivation is still running.
+func (r *AkamaiDataStreamReconciler) syncDataStream(ctx context.Context, stream *akamaiV1alpha1.AkamaiDataStream) (bool, error) {
+	logger := log.FromContext(ctx)
+	name := dataStreamName(stream)
+	active := stream.Spec.Active == nil || *stream.Spec.Active
+
+	groupID, err := numericGroupID(stream.Spec.GroupID)
+	if err != nil {
+		return false, err
+	}
+	propertyIDs, err := r.dataStreamPropertyIDs(ctx, stream)
+	if err != nil {
+		return false, err
+	}
+	secret, err := r.dataStreamSecret(ctx, stream)
+	if err != nil {
+		return false, err
+	}
+	config, err := dataStreamConfiguration(stream, groupID, propertyIDs, secret)
+	if err != nil {
+		return false, err
+	}
+	hash, err := dataStreamConfigurationHash(config)
+	if err != nil {
+		return false, err
+	}
+
+	var current *datastream.DetailedStreamVersion
+	if stream.Status.StreamID != 0 {
+		if current, err = r.AkamaiClient.GetStream(ctx, stream.Status.StreamID); err != nil {
+			return false, err
+		}
+		if current == nil {
+			logger.Info("Stream was deleted outside the operator, recreating it", "streamId", stream.Status.StreamID)
+			stream.Status = akamaiV1alpha1.AkamaiDataStreamStatus{Conditions: stream.Status.Conditions}
+		}
+	}
+	if current == nil {
+		existing, err := r.AkamaiClient.FindStream(ctx, groupID, name)
+		if err != nil {
+			return false, err
+		}
+		if existing != nil {
+			// The configuration of an adopted stream is unknown; the next version is written with the spec
+			logger.Info("Adopting existing stream", "streamName", name, "streamId", existing.StreamID)
+			stream.Status.StreamID = existing.StreamID
+			stream.Status.ConfigurationHash = ""
+		} else if r.ObserveOnly {
+			return false, observedDifference(fmt.Sprintf("Stream %s doesn't exist in DataStream", name))
+		} else {
+			streamID, err := r.AkamaiClient.CreateStream(ctx, config, active)
+			if err != nil {
+				return false, err
+			}
+			logger.Info("Created stream", "streamName", name, "streamId", streamID, "activate", active)
+			stream.Status.StreamID = streamID
+			stream.Status.ConfigurationHash = hash
+		}
+		if current, err = r.AkamaiClient.GetStream(ctx, stream.Status.StreamID); err != nil {
+			return false, err
+		}
+		if current == nil {
+			return false, fmt.Errorf("stream %d was not found", stream.Status.StreamID)
+		}
+	}
+	stream.Status.PropertyIDs = dataStreamPropertyIDStrings(propertyIDs)
+
+	// A stream can't be changed while it is being activated or deactivated
+	if !dataStreamTransitioning(current.StreamStatus) {
+		if difference := dataStreamDifference(stream, current, hash, active); r.ObserveOnly && difference != "" {
+			r.setDataStreamVersion(stream, current)
+			return false, observedDifference(difference)
+		}
+		switch {
+		case stream.Status.ConfigurationHash != hash:
+			if err := r.AkamaiClient.UpdateStream(ctx, stream.Status.StreamID, config, active); err != nil {
+				return false, err
+			}
+			logger.Info("Updated stream", "streamId", stream.Status.StreamID, "activate", active)
+			stream.Status.ConfigurationHash = hash
+		case active && current.StreamStatus != datastream.StreamStatusActivated:
+			if err := r.AkamaiClient.ActivateStream(ctx, stream.Status.StreamID); err != nil {
+				return false, err
+			}
+			logger.Info("Activated stream", "streamId", stream.Status.StreamID)
+		case !active && current.StreamStatus == datastream.StreamStatusActivated:
+			if err := r.AkamaiClient.DeactivateStream(ctx, stream.Status.StreamID); err != nil {
+				return false, err
+			}
+			logger.Info("Deactivated stream", "streamId", stream.Status.StreamID)
+		default:
+			r.setDataStreamVersion(stream, current)
+			return false, nil
+		}
+		if current, err = r.AkamaiClient.GetStream(ctx, stream.Status.StreamID); err != nil {
+			return false, err
+		}
+		if current == nil {
+			return false, fmt.Errorf("stream %d was not found", stream.Status.StreamID)
+		}
+	}
+	r.setDataStreamVersion(stream, current)
+	return dataStreamTransitioning(current.StreamStatus) ||
+		active != (current.StreamStatus == datastream.StreamStatusActivated), nil
+}
+
+// setDataStreamVersion records the version and activation status of the stream
+func (r *AkamaiDataStreamReconciler) setDataStreamVersion(stream *akamaiV1alpha1.AkamaiDataStream, current *datastream.DetailedStreamVersion) {
+	stream.Status.StreamVersion = current.StreamVersion
+	stream.Status.StreamStatus = string(current.StreamStatus)
+}
+
+// dataStreamPropertyIDs returns the sorted numeric IDs of the properties of the stream. Referenced
+// AkamaiProperties must have been created in Akamai.
+func (r *AkamaiDataStreamReconciler) dataStreamPropertyIDs(ctx context.Context, stream *akamaiV1alpha1.AkamaiDataStream) ([]int, error) {
+	propertyIDs := slices.Clone(stream.Spec.PropertyIDs)
+	for _, name := range stream.Spec.PropertyRefs {
+		var property akamaiV1alpha1.AkamaiProperty
+		if err := r.Get(ctx, types.NamespacedName{Name: name}, &property); err != nil {
+			if apierrors.IsNotFound(err) {
+				return nil, fmt.Errorf("AkamaiProperty %q not found", name)
+			}
+			return nil, fmt.Errorf("failed to get AkamaiProperty %q: %w", name, err)
+		}
+		if property.Status.PropertyID == "" {
+			return nil, fmt.Errorf("AkamaiProperty %q has not been created in Akamai yet", name)
+		}
+		propertyIDs = append(propertyIDs, property.Status.PropertyID)
+	}
+
+	ids := make([]int, 0, len(propertyIDs))
+	for _, propertyID := range propertyIDs {
+		id, err := strconv.Atoi(strings.TrimPrefix(propertyID, "prp_"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid property ID %q", propertyID)
+		}
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+	return slices.Compact(ids), nil
+}
+
+// dataStreamSecret reads the credentials of the destination and checks the keys its type
+// requires
+func (r *AkamaiDataStreamReconciler) dataStreamSecret(ctx context.Context, stream *akamaiV1alpha1.AkamaiDataStream) (map[string]string, error) {
+	destination := stream.Spec.Destination
+	required := dataStreamSecretKeys[destination.Type]
+	if destination.Type == string(datastream.DestinationTypeHTTPS) && destination.AuthenticationType == string(datastream.AuthenticationTypeBasic) {
+		required = []string{"username", "password"}
+	}
+
+	values := map[string]string{}
+	ref := destination.SecretRef
+	if ref == nil {
+		if len(required) > 0 {
+			return nil, fmt.Errorf("destination %s requires a secretRef with the keys %s", destination.Type, strings.Join(required, ", "))
+		}
+		return values, nil
+	}
+	var secret corev1.Secret
+	if err := r.Get(ctx, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, &secret); err != nil {
+		return nil, fmt.Errorf("failed to get destination secret %s/%s: %w", ref.Namespace, ref.Name, err)
+	}
+	for key, value := range secret.Data {
+		values[key] = string(value)
+	}
+	for _, key := range required {
+		if values[key] == "" {
+			return nil, fmt.Errorf("destination secret %s/%s has no %s", ref.Namespace, ref.Name, key)
+		}
+	}
+	return values, nil
+}
+
+// dataStreamConfiguration returns the configuration of the stream with its destination
+// credentials
+func dataStreamConfiguration(stream *akamaiV1alpha1.AkamaiDataStream, groupID int, propertyIDs []int, secret map[string]string) (datastream.StreamConfiguration, error) {
+	spec := stream.Spec
+	destination, err := dataStreamConnector(stream, secret)
+	if err != nil {
+		return datastream.StreamConfiguration{}, err
+	}
+
+	format := datastream.FormatType(spec.Format)
+	if format == "" {
+		format = datastream.FormatTypeJson
+	}
+	interval := datastream.IntervalInSeconds(spec.UploadIntervalSeconds)
+	if interval == 0 {
+		interval = datastream.IntervalInSeconds60
+	}
+	delivery := datastream.DeliveryConfiguration{
+		Format:           format,
+		Frequency:        datastream.Frequency{IntervalInSeconds: interval},
+		UploadFilePrefix: spec.UploadFilePrefix,
+		UploadFileSuffix: spec.UploadFileSuffix,
+	}
+	if format == datastream.FormatTypeStructured {
+		delivery.Delimiter = datastream.DelimiterTypePtr(datastream.DelimiterTypeSpace)
+	}
+
+	config := datastream.StreamConfiguration{
+		ContractID:            spec.ContractID,
+		GroupID:               groupID,
+		StreamName:            dataStreamName(stream),
+		CollectMidgress:       spec.CollectMidgress,
+		DeliveryConfiguration: delivery,
+		Destination:           destination,
+		NotificationEmails:    spec.NotificationEmails,
+	}
+	for _, id := range spec.DatasetFieldIDs {
+		config.DatasetFields = append(config.DatasetFields, datastream.DatasetFieldID{DatasetFieldID: id})
+	}
+	for _, id := range propertyIDs {
+		config.Properties = append(config.Properties, datastream.PropertyID{PropertyID: id})
+	}
+	return config, nil
+}
+
+// dataStreamConnector returns the destination of the stream with its credentials
+func dataStreamConnector(stream *akamaiV1alpha1.AkamaiDataStream, secret map[string]string) (datastream.AbstractConnector, error) {
+	destination := stream.Spec.Destination
+	displayName := destination.DisplayName
+	if displayName == "" {
+		displayName = dataStreamName(stream)
+	}
+
+	var connector datastream.AbstractConnector
+	switch datastream.DestinationType(destination.Type) {
+	case datastream.DestinationTypeS3:
+		connector = &datastream.S3Connector{
+			DisplayName:     displayName,
+			Bucket:          destination.Bucket,
+			Region:          destination.Region,
+			Path:            destination.Path,
+			AccessKey:       secret["accessKey"],
+			SecretAccessKey: secret["secretAccessKey"],
+		}
+	case datastream.DestinationTypeSplunk:
+		connector = &datastream.SplunkConnector{
+			DisplayName:         displayName,
+			Endpoint:            destination.Endpoint,
+			CompressLogs:        destination.CompressLogs,
+			EventCollectorToken: secret["eventCollectorToken"],
+			CustomHeaderName:    destination.CustomHeaderName,
+			CustomHeaderValue:   destination.CustomHeaderValue,
+			TLSHostname:         destination.TLSHostname,
+			CACert:              secret["caCert"],
+			ClientCert:          secret["clientCert"],
+			ClientKey:           secret["clientKey"],
+		}
+	case datastream.DestinationTypeDataDog:
+		connector = &datastream.DatadogConnector{
+			DisplayName:  displayName,
+			Endpoint:     destination.Endpoint,
+			CompressLogs: destination.CompressLogs,
+			AuthToken:    secret["authToken"],
+			Service:      destination.Service,
+			Source:       destination.Source,
+			Tags:         destination.Tags,
+		}
+	case datastream.DestinationTypeHTTPS:
+		authenticationType := datastream.AuthenticationType(destination.AuthenticationType)
+		if authenticationType == "" {
+			authenticationType = datastream.AuthenticationTypeNone
+		}
+		connector = &datastream.CustomHTTPSConnector{
+			DisplayName:        displayName,
+			Endpoint:           destination.Endpoint,
+			CompressLogs:       destination.CompressLogs,
+			AuthenticationType: authenticationType,
+			UserName:           secret["username"],
+			Password:           secret["password"],
+			ContentType:        destination.ContentType,
+			CustomHeaderName:   destination.CustomHeaderName,
+			CustomHeaderValue:  destination.CustomHeaderValue,
+			TLSHostname:        destination.TLSHostname,
+			CACert:             secret["caCert"],
+			ClientCert:         secret["clientCert"],
+			ClientKey:          secret["clientKey"],
+		}
+	default:
+		return nil, fmt.Errorf("unsupported destination type %q", destination.Type)
+	}
+	connector.SetDestinationType()
+	return connector, nil
+}
+
+// dataStreamConfigurationHash returns the hash of a stream configuration. Akamai doesn't return
+// the credentials of a destination, so changes are detected by comparing hashes.
+func dataStreamConfigurationHash(config datastream.StreamConfiguration) (string, error) {
+	raw, err := json.Marshal(config)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal stream configuration: %w", err)
+	}
+	sum := sha256.Sum256(raw)
+	return checksumPrefix + hex.EncodeToString(sum[:]), nil
+}
+
+// dataStreamTransitioning reports whether a stream is being activated or deactivated
+func dataStreamTransitioning(status datastream.StreamStatus) bool {
+	return status == datastream.StreamStatusActivating || status == datastream.StreamStatusDeactivating
+}
+
+// dataStreamPropertyIDStrings returns numeric property IDs in their prp_ form
+func dataStreamPropertyIDStrings(ids []int) []string {
+	propertyIDs := make([]string, 0, len(ids))
+	for _, id := range ids {
+		propertyIDs = append(propertyIDs, fmt.Sprintf("prp_%d", id))
+	}
+	return propertyIDs
+}
+
+// dataStreamDifference describes the change the sync would make to a stream that isn't being
+// activated or deactivated, or returns an empty string when it matches the spec
+func dataStreamDifference(stream *akamaiV1alpha1.AkamaiDataStream, current *datastream.DetailedStreamVersion, hash string, active bool) string {
+	switch {
+	case stream.Status.ConfigurationHash != hash:
+		return fmt.Sprintf("The configuration of stream %d differs from the spec", stream.Status.StreamID)
+	case active && current.StreamStatus != datastream.StreamStatusActivated:
+		return fmt.Sprintf("Stream %d isn't activated", stream.Status.StreamID)
+	case !active && current.StreamStatus == datastream.StreamStatusActivated:
+		return fmt.Sprintf("Stream %d is activated", stream.Status.StreamID)
+	}
+	return ""
+}
+
+// dataStreamName returns the name of the stream, defaulting to the name of the resource
+func dataStreamName(stream *akamaiV1alpha1.AkamaiDataStream) string {
+	if stream.Spec.StreamName != "" {
+		return stream.Spec.StreamName
+	}
+	return stream.Name
+}
+
+// handleDeletion deactivates and deletes the stream with the Delete deletion policy and removes
+// the finalizer
+func (r *AkamaiDataStreamReconciler) handleDeletion(ctx context.Context, stream *akamaiV1alpha1.AkamaiDataStream) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+	if !controllerutil.ContainsFinalizer(stream, DataStreamFinalizerName) {
+		return ctrl.Result{}, nil
+	}
+
+	// Nothing is deactivated or deleted in DataStream in observe-only mode
+	if stream.Spec.DeletionPolicy == akamaiV1alpha1.DeletionPolicyDelete && stream.Status.StreamID != 0 && !r.ObserveOnly {
+		deactivating, err := r.deactivateDataStream(ctx, stream)
+		if err == nil && !deactivating {
+			err = r.AkamaiClient.DeleteStream(ctx, stream.Status.StreamID)
+		}
+		if err != nil {
+			logger.Error(err, "Failed to delete stream", "streamId", stream.Status.StreamID)
+			r.setDataStreamCondition(stream, PhaseDeleting, metav1.ConditionFalse, "DeletionFailed", akamai.ErrorMessage(err))
+			if updateErr := r.Status().Update(ctx, stream); updateErr != nil {
+				return ctrl.Result{}, updateErr
+			}
+			return ctrl.Result{RequeueAfter: dataStreamErrorRetryInterval}, nil
+		}
+		if deactivating {
+			r.setDataStreamCondition(stream, PhaseDeleting, metav1.ConditionFalse, "DeactivationInProgress",
+				fmt.Sprintf("Stream %d is being deactivated before it is deleted", stream.Status.StreamID))
+			if err := r.Status().Update(ctx, stream); err != nil {
+				return ctrl.Result{}, err
+			}
+			return ctrl.Result{RequeueAfter: dataStreamActivationPollInterval}, nil
+		}
+		logger.Info("Deleted stream", "streamId", stream.Status.StreamID)
+	}
+
+	controllerutil.RemoveFinalizer(stream, DataStreamFinalizerName)
+	return ctrl.Result{}, r.Update(ctx, stream)
+}
+
+// deactivateDataStream starts the deactivation of an active stream, which must complete before
+// it can be deleted. It reports whether the stream is still being activated or deactivated.
+func (r *AkamaiDataStreamReconciler) deactivateDataStream(ctx context.Context, stream *akamaiV1alpha1.AkamaiDataStream) (bool, error) {
+	current, err := r.AkamaiClient.GetStream(ctx, stream.Status.StreamID)
+	if err != nil || current == nil {
+		return false, err
+	}
+	stream.Status.StreamStatus = string(current.StreamStatus)
+	if current.StreamStatus == datastream.StreamStatusActivated {
+		if err := r.AkamaiClient.DeactivateStream(ctx, stream.Status.StreamID); err != nil {
+			return false, err
+		}
+		return true, nil
+	}
+	return dataStreamTransitioning(current.StreamStatus), nil
+}
+
+// setDataStreamCondition sets the phase and the Ready condition of the stream
+func (r *AkamaiDataStreamReconciler) setDataStreamCondition(stream *akamaiV1alpha1.AkamaiDataStream, phase string, status metav1.ConditionStatus, reason, message string) {
+	setReadyCondition(stream.Generation, readyStatus{
+		Phase:              &stream.Status.Phase,
+		ObservedGeneration: &stream.Status.ObservedGeneration,
+		LastUpdated:        &stream.Status.LastUpdated,
+		Conditions:         &stream.Status.Conditions,
+	}, phase, status, reason, message)
+}
+
+// dataStreamsWaitingForProperty enqueues the streams referencing a changed AkamaiProperty that
+// don't deliver its logs yet, so they pick up its ID as soon as it is created
+func (r *AkamaiDataStreamReconciler) dataStreamsWaitingForProperty(ctx context.Context, obj client.Object) []reconcile.Request {
+	property, ok := obj.(*akamaiV1alpha1.AkamaiProperty)
+	if !ok || property.Status.PropertyID == "" {
+		return nil
+	}
+	var streams akamaiV1alpha1.AkamaiDataStreamList
+	if err := r.List(ctx, &streams); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to list streams referencing property", "property", property.Name)
+		return nil
+	}
+	var requests []reconcile.Request
+	for _, stream := range streams.Items {
+		if slices.Contains(stream.Spec.PropertyRefs, property.Name) && !slices.Contains(stream.Status.PropertyIDs, property.Status.PropertyID) {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKey{Name: stream.Name}})
+		}
+	}
+	return requests
+}
+
+// SetupWithManager sets up the controller with the Manager. Status updates don't trigger
+// reconciles; streams are requeued to follow their activations.
+func (r *AkamaiDataStreamReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&akamaiV1alpha1.AkamaiDataStream{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Watches(&akamaiV1alpha1.AkamaiProperty{}, handler.EnqueueRequestsFromMapFunc(r.dataStreamsWaitingForProperty)).
+		Complete(r)
+}
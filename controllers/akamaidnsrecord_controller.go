@@ -0,0 +1,350 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v8/pkg/dns"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+	"github.com/mmz-srf/akamai-operator/pkg/akamai"
+)
+
+const (
+	// dnsRecordResyncInterval is how often a record set is compared with Edge DNS to restore
+	// changes made outside the operator
+	dnsRecordResyncInterval = 10 * time.Minute
+
+	// dnsRecordErrorRetryInterval is how long a failed record set reconcile waits before it is retried
+	dnsRecordErrorRetryInterval = 2 * time.Minute
+
+	// defaultDNSRecordTTL is the TTL of record sets without one
+	defaultDNSRecordTTL = 300
+)
+
+// AkamaiDnsRecordReconciler keeps Edge DNS record sets in line with their AkamaiDnsRecords,
+// restoring changes made outside the operator, and deletes them with the resource
+type AkamaiDnsRecordReconciler struct {
+	client.Client
+	Scheme       *runtime.Scheme
+	AkamaiClient *akamai.Client
+
+	// Credentials selects the credential source the Akamai client is created with
+	Credentials akamai.Credentials
+
+	// Shard is the part of the fleet this instance manages; nil manages all record sets
+	Shard *Shard
+
+	// ObserveOnly compares the record sets with Edge DNS and reports differences without
+	// changing them
+	ObserveOnly bool
+}
+
+//+kubebuilder:rbac:groups=akamai.com,resources=akamaidnsrecords,verbs=get;list;watch;update;patch
+//+kubebuilder:rbac:groups=akamai.com,resources=akamaidnsrecords/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=akamai.com,resources=akamaidnsrecords/finalizers,verbs=update
+
+// Reconcile brings an Edge DNS record set to the state of its AkamaiDnsRecord
+func (r *AkamaiDnsRecordReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	var record akamaiV1alpha1.AkamaiDnsRecord
+	if err := r.Get(ctx, req.NamespacedName, &record); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	// Record sets of other shards are left to the instances managing them
+	if inShard, err := r.Shard.containsDNSRecord(ctx, r.Client, &record); err != nil || !inShard {
+		if !inShard && err == nil {
+			logger.V(1).Info("DNS record belongs to another shard", "shard", r.Shard.Name)
+		}
+		return ctrl.Result{}, err
+	}
+
+	if r.AkamaiClient == nil {
+		akamaiClient, err := akamai.NewClient(r.Credentials)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		r.AkamaiClient = akamaiClient
+	}
+
+	if record.DeletionTimestamp != nil {
+		return r.handleDeletion(ctx, &record)
+	}
+	// The finalizer is added before the record set is created, so it is never left behind
+	if !controllerutil.ContainsFinalizer(&record, DNSRecordFinalizerName) {
+		controllerutil.AddFinalizer(&record, DNSRecordFinalizerName)
+		if err := r.Update(ctx, &record); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	desired, err := r.desiredDNSRecord(ctx, &record)
+	if err != nil {
+		r.setDNSRecordCondition(&record, PhaseError, metav1.ConditionFalse, "EdgeHostnameNotResolved", err.Error())
+		if updateErr := r.Status().Update(ctx, &record); updateErr != nil {
+			return ctrl.Result{}, updateErr
+		}
+		return ctrl.Result{RequeueAfter: dnsRecordErrorRetryInterval}, nil
+	}
+
+	corrected, err := r.syncDNSRecord(ctx, &record, desired)
+	if message, observed := observeOnlyMessage(r.ObserveOnly, err); observed {
+		r.setDNSRecordCondition(&record, PhaseObserving, metav1.ConditionFalse, ReasonObserveOnly, message)
+		if err := r.Status().Update(ctx, &record); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{RequeueAfter: dnsRecordResyncInterval}, nil
+	}
+	if err != nil {
+		logger.Error(err, "Failed to reconcile DNS record", "name", desired.Name, "type", desired.RecordType)
+		r.setDNSRecordCondition(&record, PhaseError, metav1.ConditionFalse, "ReconcileError", akamai.ErrorMessage(err))
+		if updateErr := r.Status().Update(ctx, &record); updateErr != nil {
+			return ctrl.Result{}, updateErr
+		}
+		return ctrl.Result{RequeueAfter: dnsRecordErrorRetryInterval}, nil
+	}
+
+	if corrected {
+		now := metav1.Now()
+		record.Status.LastDriftCorrected = &now
+		r.setDNSRecordCondition(&record, PhaseReady, metav1.ConditionTrue, "DriftCorrected",
+			fmt.Sprintf("%s record %s was changed outside the operator and has been restored", desired.RecordType, desired.Name))
+	} else {
+		r.setDNSRecordCondition(&record, PhaseReady, metav1.ConditionTrue, "DNSRecordReady",
+			fmt.Sprintf("%s record %s is up to date", desired.RecordType, desired.Name))
+	}
+	if err := r.Status().Update(ctx, &record); err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{RequeueAfter: dnsRecordResyncInterval}, nil
+}
+
+// desiredDNSRecord returns the record set of the spec, with the domain of the referenced
+// AkamaiEdgeHostname as target of a CNAME record
+func (r *AkamaiDnsRecordReconciler) desiredDNSRecord(ctx context.Context, record *akamaiV1alpha1.AkamaiDnsRecord) (*dns.RecordBody, error) {
+	targets := record.Spec.Targets
+	if ref := record.Spec.EdgeHostnameRef; ref != "" {
+		var edgeHostname akamaiV1alpha1.AkamaiEdgeHostname
+		if err := r.Get(ctx, client.ObjectKey{Name: ref}, &edgeHostname); err != nil {
+			if apierrors.IsNotFound(err) {
+				return nil, fmt.Errorf("AkamaiEdgeHostname %s not found", ref)
+			}
+			return nil, fmt.Errorf("failed to get AkamaiEdgeHostname %s: %w", ref, err)
+		}
+		if edgeHostname.Status.Domain == "" {
+			return nil, fmt.Errorf("AkamaiEdgeHostname %s is not created yet", ref)
+		}
+		targets = []string{edgeHostname.Status.Domain}
+	}
+	return dnsRecordBody(record, targets), nil
+}
+
+// syncDNSRecord creates or updates the record set and records it in the status. A record set
+// moved to another name, type or zone is deleted at its old place. It reports whether a record
+// set already in sync with the current generation had to be restored.
+func (r *AkamaiDnsRecordReconciler) syncDNSRecord(ctx context.Context, record *akamaiV1alpha1.AkamaiDnsRecord, desired *dns.RecordBody) (bool, error) {
+	logger := log.FromContext(ctx)
+	zone := record.Spec.Zone
+	status := record.Status
+
+	moved := status.Name != "" && (status.Zone != zone || status.Name != desired.Name || status.Type != desired.RecordType)
+	if moved && r.ObserveOnly {
+		return false, observedDifference(fmt.Sprintf("%s record %s moved from %s record %s in zone %s", desired.RecordType, desired.Name, status.Type, status.Name, status.Zone))
+	}
+	if moved {
+		previous := &dns.RecordBody{Name: status.Name, RecordType: status.Type, TTL: desired.TTL, Target: status.Targets}
+		if err := r.AkamaiClient.DeleteDNSRecord(ctx, status.Zone, previous); err != nil {
+			return false, err
+		}
+		logger.Info("Deleted moved DNS record", "zone", status.Zone, "name", status.Name, "type", status.Type)
+	}
+	// Differences found while the status reflects the current generation were made outside the operator
+	inSync := !moved && status.Phase == PhaseReady && status.ObservedGeneration == record.Generation
+
+	current, err := r.AkamaiClient.GetDNSRecord(ctx, zone, desired.Name, desired.RecordType)
+	if err != nil {
+		return false, err
+	}
+	corrected := false
+	switch {
+	case current == nil && r.ObserveOnly:
+		return false, observedDifference(fmt.Sprintf("%s record %s doesn't exist in zone %s", desired.RecordType, desired.Name, zone))
+	case current != nil && !dnsRecordInSync(desired, current) && r.ObserveOnly:
+		return false, observedDifference(fmt.Sprintf("%s record %s in zone %s differs from the spec", desired.RecordType, desired.Name, zone))
+	case current == nil:
+		if err := r.AkamaiClient.CreateDNSRecord(ctx, zone, desired); err != nil {
+			return false, err
+		}
+		logger.Info("Created DNS record", "zone", zone, "name", desired.Name, "type", desired.RecordType)
+		corrected = inSync
+	case !dnsRecordInSync(desired, current):
+		if err := r.AkamaiClient.UpdateDNSRecord(ctx, zone, desired); err != nil {
+			return false, err
+		}
+		logger.Info("Updated DNS record", "zone", zone, "name", desired.Name, "type", desired.RecordType,
+			"previousTargets", current.Target, "previousTTL", current.TTL)
+		corrected = inSync
+	}
+
+	record.Status.Zone = zone
+	record.Status.Name = desired.Name
+	record.Status.Type = desired.RecordType
+	record.Status.Targets = desired.Target
+	return corrected, nil
+}
+
+// handleDeletion deletes the record set unless the deletion policy retains it and removes the finalizer
+func (r *AkamaiDnsRecordReconciler) handleDeletion(ctx context.Context, record *akamaiV1alpha1.AkamaiDnsRecord) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+	if !controllerutil.ContainsFinalizer(record, DNSRecordFinalizerName) {
+		return ctrl.Result{}, nil
+	}
+
+	status := record.Status
+	// Nothing is deleted in Edge DNS in observe-only mode
+	if record.Spec.DeletionPolicy != akamaiV1alpha1.DeletionPolicyRetain && status.Name != "" && !r.ObserveOnly {
+		ttl := record.Spec.TTL
+		if ttl == 0 {
+			ttl = defaultDNSRecordTTL
+		}
+		current := &dns.RecordBody{Name: status.Name, RecordType: status.Type, TTL: ttl, Target: status.Targets}
+		if err := r.AkamaiClient.DeleteDNSRecord(ctx, status.Zone, current); err != nil {
+			logger.Error(err, "Failed to delete DNS record", "name", status.Name, "type", status.Type)
+			r.setDNSRecordCondition(record, PhaseDeleting, metav1.ConditionFalse, "DeletionFailed", akamai.ErrorMessage(err))
+			if updateErr := r.Status().Update(ctx, record); updateErr != nil {
+				return ctrl.Result{}, updateErr
+			}
+			return ctrl.Result{RequeueAfter: dnsRecordErrorRetryInterval}, nil
+		}
+		logger.Info("Deleted DNS record", "zone", status.Zone, "name", status.Name, "type", status.Type)
+	}
+
+	controllerutil.RemoveFinalizer(record, DNSRecordFinalizerName)
+	return ctrl.Result{}, r.Update(ctx, record)
+}
+
+// dnsRecordName returns the name of the record set, defaulting to the name of the resource
+func dnsRecordName(record *akamaiV1alpha1.AkamaiDnsRecord) string {
+	if record.Spec.Name != "" {
+		return record.Spec.Name
+	}
+	return record.Name
+}
+
+// dnsRecordBody returns the record set of the spec with the given targets. TXT targets are sent
+// as quoted strings.
+func dnsRecordBody(record *akamaiV1alpha1.AkamaiDnsRecord, targets []string) *dns.RecordBody {
+	ttl := record.Spec.TTL
+	if ttl == 0 {
+		ttl = defaultDNSRecordTTL
+	}
+	body := &dns.RecordBody{Name: dnsRecordName(record), RecordType: record.Spec.Type, TTL: ttl}
+	for _, target := range targets {
+		if record.Spec.Type == "TXT" {
+			target = quoteDNSText(target)
+		}
+		body.Target = append(body.Target, target)
+	}
+	return body
+}
+
+// quoteDNSText quotes a TXT text the way Edge DNS expects it, unless it is already quoted
+func quoteDNSText(text string) string {
+	if strings.HasPrefix(text, `"`) {
+		return text
+	}
+	return `"` + strings.ReplaceAll(text, `"`, `\"`) + `"`
+}
+
+// dnsRecordInSync reports whether a record set in Edge DNS has the desired TTL and targets. The
+// targets are compared in the form Edge DNS returns them, regardless of their order.
+func dnsRecordInSync(desired, current *dns.RecordBody) bool {
+	if desired.TTL != current.TTL || len(desired.Target) != len(current.Target) {
+		return false
+	}
+	normalize := func(targets []string) []string {
+		normalized := make([]string, 0, len(targets))
+		for _, target := range targets {
+			normalized = append(normalized, normalizeDNSTarget(desired.RecordType, target))
+		}
+		slices.Sort(normalized)
+		return normalized
+	}
+	return slices.Equal(normalize(desired.Target), normalize(current.Target))
+}
+
+// normalizeDNSTarget returns the canonical form of a target: IP addresses in their shortest
+// form, hostnames lowercased without the trailing dot and texts unquoted, with the strings Edge
+// DNS splits long texts into joined
+func normalizeDNSTarget(recordType, target string) string {
+	switch recordType {
+	case "A", "AAAA":
+		if addr, err := netip.ParseAddr(target); err == nil {
+			return addr.String()
+		}
+	case "CNAME":
+		return strings.TrimSuffix(strings.ToLower(target), ".")
+	case "TXT":
+		if len(target) >= 2 && strings.HasPrefix(target, `"`) && strings.HasSuffix(target, `"`) {
+			target = strings.ReplaceAll(target[1:len(target)-1], `" "`, "")
+		}
+		return strings.ReplaceAll(target, `\"`, `"`)
+	}
+	return target
+}
+
+// setDNSRecordCondition sets the phase and the Ready condition of the record set
+func (r *AkamaiDnsRecordReconciler) setDNSRecordCondition(record *akamaiV1alpha1.AkamaiDnsRecord, phase string, status metav1.ConditionStatus, reason, message string) {
+	setReadyCondition(record.Generation, readyStatus{
+		Phase:              &record.Status.Phase,
+		ObservedGeneration: &record.Status.ObservedGeneration,
+		LastUpdated:        &record.Status.LastUpdated,
+		Conditions:         &record.Status.Conditions,
+	}, phase, status, reason, message)
+}
+
+// dnsRecordsReferencing enqueues the record sets whose CNAME targets a changed AkamaiEdgeHostname,
+// so they follow it once it is created
+func (r *AkamaiDnsRecordReconciler) dnsRecordsReferencing(ctx context.Context, obj client.Object) []reconcile.Request {
+	var list akamaiV1alpha1.AkamaiDnsRecordList
+	if err := r.List(ctx, &list); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to list AkamaiDnsRecords")
+		return nil
+	}
+	var requests []reconcile.Request
+	for _, record := range list.Items {
+		if record.Spec.EdgeHostnameRef == obj.GetName() {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKey{Name: record.Name}})
+		}
+	}
+	return requests
+}
+
+// SetupWithManager sets up the controller with the Manager. Status updates of record sets don't
+// trigger reconciles, while those of edge hostnames do, since their domain is set in the status.
+func (r *AkamaiDnsRecordReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&akamaiV1alpha1.AkamaiDnsRecord{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Watches(&akamaiV1alpha1.AkamaiEdgeHostname{}, handler.EnqueueRequestsFromMapFunc(r.dnsRecordsReferencing)).
+		Complete(r)
+}
@@ -0,0 +1,262 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v8/pkg/dns"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+	"github.com/mmz-srf/akamai-operator/pkg/akamai"
+)
+
+const (
+	// dnsZoneResyncInterval is how often a zone is compared with Edge DNS
+	dnsZoneResyncInterval = 10 * time.Minute
+
+	// dnsZoneErrorRetryInterval is how long a failed zone reconcile waits before it is retried
+	dnsZoneErrorRetryInterval = 2 * time.Minute
+)
+
+// AkamaiDnsZoneReconciler creates Edge DNS zones, keeps their settings in line with the spec and
+// deletes them with the Delete deletion policy. The records of the zones are not managed.
+type AkamaiDnsZoneReconciler struct {
+	client.Client
+	Scheme       *runtime.Scheme
+	AkamaiClient *akamai.Client
+
+	// Credentials selects the credential source the Akamai client is created with
+	Credentials akamai.Credentials
+
+	// Shard is the part of the fleet this instance manages; nil manages all zones
+	Shard *Shard
+
+	// ObserveOnly compares the zones with Edge DNS and reports differences without changing them
+	ObserveOnly bool
+}
+
+//+kubebuilder:rbac:groups=akamai.com,resources=akamaidnszones,verbs=get;list;watch;update;patch
+//+kubebuilder:rbac:groups=akamai.com,resources=akamaidnszones/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=akamai.com,resources=akamaidnszones/finalizers,verbs=update
+
+// Reconcile brings an Edge DNS zone to the state of its AkamaiDnsZone
+func (r *AkamaiDnsZoneReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	var zone akamaiV1alpha1.AkamaiDnsZone
+	if err := r.Get(ctx, req.NamespacedName, &zone); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	// Zones of other shards are left to the instances managing them
+	if !r.Shard.Contains(zone.Labels, zone.Spec.ContractID) {
+		logger.V(1).Info("DNS zone belongs to another shard", "shard", r.Shard.Name)
+		return ctrl.Result{}, nil
+	}
+
+	if r.AkamaiClient == nil {
+		akamaiClient, err := akamai.NewClient(r.Credentials)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		r.AkamaiClient = akamaiClient
+	}
+
+	if zone.DeletionTimestamp != nil {
+		return r.handleDeletion(ctx, &zone)
+	}
+	// The finalizer is added before the zone is created, so it is never left behind
+	if !controllerutil.ContainsFinalizer(&zone, DNSZoneFinalizerName) {
+		controllerutil.AddFinalizer(&zone, DNSZoneFinalizerName)
+		if err := r.Update(ctx, &zone); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	// A zone can't be renamed once it is created
+	if name := dnsZoneName(&zone); zone.Status.Zone != "" && zone.Status.Zone != name {
+		r.setDNSZoneCondition(&zone, PhaseError, metav1.ConditionFalse, "InvalidSpec",
+			fmt.Sprintf("DNS zone %s was created as %s and can't be renamed; create another AkamaiDnsZone instead", name, zone.Status.Zone))
+		return ctrl.Result{}, r.Status().Update(ctx, &zone)
+	}
+
+	err := r.syncDNSZone(ctx, &zone)
+	if message, observed := observeOnlyMessage(r.ObserveOnly, err); observed {
+		r.setDNSZoneCondition(&zone, PhaseObserving, metav1.ConditionFalse, ReasonObserveOnly, message)
+		if err := r.Status().Update(ctx, &zone); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{RequeueAfter: dnsZoneResyncInterval}, nil
+	}
+	if err != nil {
+		logger.Error(err, "Failed to reconcile DNS zone", "zone", dnsZoneName(&zone))
+		r.setDNSZoneCondition(&zone, PhaseError, metav1.ConditionFalse, "ReconcileError", akamai.ErrorMessage(err))
+		if updateErr := r.Status().Update(ctx, &zone); updateErr != nil {
+			return ctrl.Result{}, updateErr
+		}
+		return ctrl.Result{RequeueAfter: dnsZoneErrorRetryInterval}, nil
+	}
+
+	r.setDNSZoneCondition(&zone, PhaseReady, metav1.ConditionTrue, "DNSZoneReady",
+		fmt.Sprintf("DNS zone %s is up to date", zone.Status.Zone))
+	if err := r.Status().Update(ctx, &zone); err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{RequeueAfter: dnsZoneResyncInterval}, nil
+}
+
+// syncDNSZone creates, adopts or updates the zone and records its state in the status
+func (r *AkamaiDnsZoneReconciler) syncDNSZone(ctx context.Context, zone *akamaiV1alpha1.AkamaiDnsZone) error {
+	logger := log.FromContext(ctx)
+	desired := dnsZoneCreate(zone)
+
+	current, err := r.AkamaiClient.GetDNSZone(ctx, desired.Zone)
+	if err != nil {
+		return err
+	}
+	switch {
+	case current == nil && r.ObserveOnly:
+		return observedDifference(fmt.Sprintf("DNS zone %s doesn't exist in Edge DNS", desired.Zone))
+	case current == nil:
+		if err := r.AkamaiClient.CreateDNSZone(ctx, desired, zone.Spec.ContractID, zone.Spec.GroupID); err != nil {
+			return err
+		}
+		logger.Info("Created DNS zone", "zone", desired.Zone, "type", desired.Type)
+		if current, err = r.AkamaiClient.GetDNSZone(ctx, desired.Zone); err != nil {
+			return err
+		}
+		if current == nil {
+			return fmt.Errorf("DNS zone %s was created but can't be found", desired.Zone)
+		}
+	case !strings.EqualFold(current.Type, desired.Type):
+		return fmt.Errorf("DNS zone %s is a %s zone in Edge DNS and can't be changed to %s", desired.Zone, current.Type, desired.Type)
+	case dnsZoneNeedsUpdate(desired, current) && r.ObserveOnly:
+		return observedDifference(fmt.Sprintf("The settings of DNS zone %s differ from the spec", desired.Zone))
+	case dnsZoneNeedsUpdate(desired, current):
+		// Edge DNS keeps the algorithm it chose unless the spec sets one
+		if desired.SignAndServeAlgorithm == "" {
+			desired.SignAndServeAlgorithm = current.SignAndServeAlgorithm
+		}
+		if err := r.AkamaiClient.UpdateDNSZone(ctx, desired); err != nil {
+			return err
+		}
+		logger.Info("Updated DNS zone", "zone", desired.Zone)
+		if current, err = r.AkamaiClient.GetDNSZone(ctx, desired.Zone); err != nil {
+			return err
+		}
+		if current == nil {
+			return fmt.Errorf("DNS zone %s was updated but can't be found", desired.Zone)
+		}
+	case zone.Status.Zone == "":
+		logger.Info("Adopting existing DNS zone", "zone", desired.Zone)
+	}
+
+	zone.Status.Zone = desired.Zone
+	zone.Status.ActivationState = current.ActivationState
+	zone.Status.VersionID = current.VersionID
+	zone.Status.LastActivationDate = current.LastActivationDate
+	zone.Status.DSRecord = ""
+	if current.SignAndServe {
+		status, err := r.AkamaiClient.GetDNSSECStatus(ctx, desired.Zone)
+		if err != nil {
+			return err
+		}
+		zone.Status.DSRecord = status.CurrentRecords.DSRecord
+	}
+	return nil
+}
+
+// handleDeletion deletes the zone in Edge DNS with the Delete deletion policy and removes the finalizer
+func (r *AkamaiDnsZoneReconciler) handleDeletion(ctx context.Context, zone *akamaiV1alpha1.AkamaiDnsZone) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+	if !controllerutil.ContainsFinalizer(zone, DNSZoneFinalizerName) {
+		return ctrl.Result{}, nil
+	}
+
+	// Nothing is deleted in Edge DNS in observe-only mode
+	if zone.Spec.DeletionPolicy == akamaiV1alpha1.DeletionPolicyDelete && zone.Status.Zone != "" && !r.ObserveOnly {
+		requestID, err := r.AkamaiClient.DeleteDNSZone(ctx, zone.Status.Zone)
+		if err != nil {
+			logger.Error(err, "Failed to delete DNS zone", "zone", zone.Status.Zone)
+			r.setDNSZoneCondition(zone, PhaseDeleting, metav1.ConditionFalse, "DeletionFailed", akamai.ErrorMessage(err))
+			if updateErr := r.Status().Update(ctx, zone); updateErr != nil {
+				return ctrl.Result{}, updateErr
+			}
+			return ctrl.Result{RequeueAfter: dnsZoneErrorRetryInterval}, nil
+		}
+		logger.Info("Submitted DNS zone deletion", "zone", zone.Status.Zone, "requestID", requestID)
+	}
+
+	controllerutil.RemoveFinalizer(zone, DNSZoneFinalizerName)
+	return ctrl.Result{}, r.Update(ctx, zone)
+}
+
+// dnsZoneName returns the name of the zone, defaulting to the name of the resource
+func dnsZoneName(zone *akamaiV1alpha1.AkamaiDnsZone) string {
+	if zone.Spec.Zone != "" {
+		return zone.Spec.Zone
+	}
+	return zone.Name
+}
+
+// dnsZoneCreate returns the Edge DNS zone of the spec
+func dnsZoneCreate(zone *akamaiV1alpha1.AkamaiDnsZone) *dns.ZoneCreate {
+	zoneType := zone.Spec.Type
+	if zoneType == "" {
+		zoneType = akamai.DNSZoneTypePrimary
+	}
+	return &dns.ZoneCreate{
+		Zone:                  dnsZoneName(zone),
+		Type:                  zoneType,
+		Masters:               zone.Spec.Masters,
+		Comment:               zone.Spec.Comment,
+		SignAndServe:          zone.Spec.SignAndServe,
+		SignAndServeAlgorithm: zone.Spec.SignAndServeAlgorithm,
+		ContractID:            zone.Spec.ContractID,
+	}
+}
+
+// dnsZoneNeedsUpdate reports whether the settings of the zone in Edge DNS differ from the
+// desired ones. The order of the masters doesn't matter, and the sign and serve algorithm only
+// when the spec sets one.
+func dnsZoneNeedsUpdate(desired *dns.ZoneCreate, current *dns.ZoneResponse) bool {
+	desiredMasters := slices.Sorted(slices.Values(desired.Masters))
+	currentMasters := slices.Sorted(slices.Values(current.Masters))
+	return desired.Comment != current.Comment ||
+		desired.SignAndServe != current.SignAndServe ||
+		(desired.SignAndServeAlgorithm != "" && desired.SignAndServeAlgorithm != current.SignAndServeAlgorithm) ||
+		!slices.Equal(desiredMasters, currentMasters)
+}
+
+// setDNSZoneCondition sets the phase and the Ready condition of the zone
+func (r *AkamaiDnsZoneReconciler) setDNSZoneCondition(zone *akamaiV1alpha1.AkamaiDnsZone, phase string, status metav1.ConditionStatus, reason, message string) {
+	setReadyCondition(zone.Generation, readyStatus{
+		Phase:              &zone.Status.Phase,
+		ObservedGeneration: &zone.Status.ObservedGeneration,
+		LastUpdated:        &zone.Status.LastUpdated,
+		Conditions:         &zone.Status.Conditions,
+	}, phase, status, reason, message)
+}
+
+// SetupWithManager sets up the controller with the Manager. Status updates don't trigger
+// reconciles; zones are requeued to follow changes made outside the operator.
+func (r *AkamaiDnsZoneReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&akamaiV1alpha1.AkamaiDnsZone{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Complete(r)
+}
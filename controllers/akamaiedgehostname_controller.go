@@ -0,0 +1,417 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+	"github.com/mmz-srf/akamai-operator/pkg/akamai"
+)
+
+const (
+	// edgeHostnameResyncInterval is how often an edge hostname and the certificates of the
+	// hostnames CNAMEd to it are refreshed
+	edgeHostnameResyncInterval = 10 * time.Minute
+
+	// edgeHostnameCertPollInterval is how often certificates that are not deployed yet are polled
+	edgeHostnameCertPollInterval = 2 * time.Minute
+
+	// edgeHostnameErrorRetryInterval is how long a failed edge hostname reconcile waits before it
+	// is retried
+	edgeHostnameErrorRetryInterval = 2 * time.Minute
+)
+
+// AkamaiEdgeHostnameReconciler creates edge hostnames, reports the certificates of the property
+// hostnames CNAMEd to them and deletes them with the Delete deletion policy once no AkamaiProperty
+// references them anymore
+type AkamaiEdgeHostnameReconciler struct {
+	client.Client
+	Scheme       *runtime.Scheme
+	AkamaiClient *akamai.Client
+
+	// Credentials selects the credential source the Akamai client is created with
+	Credentials akamai.Credentials
+
+	// ClientCache holds the clients of the accounts referenced by spec.credentialsRef and
+	// spec.providerConfigRef
+	ClientCache *AkamaiClientCache
+
+	// Shard is the part of the fleet this instance manages; nil manages all edge hostnames
+	Shard *Shard
+
+	// ObserveOnly compares the edge hostnames with Akamai and reports differences without
+	// changing them
+	ObserveOnly bool
+}
+
+//+kubebuilder:rbac:groups=akamai.com,resources=akamaiedgehostnames,verbs=get;list;watch;update;patch
+//+kubebuilder:rbac:groups=akamai.com,resources=akamaiedgehostnames/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=akamai.com,resources=akamaiedgehostnames/finalizers,verbs=update
+
+// Reconcile brings an edge hostname in Akamai to the state of its AkamaiEdgeHostname
+func (r *AkamaiEdgeHostnameReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	var edgeHostname akamaiV1alpha1.AkamaiEdgeHostname
+	if err := r.Get(ctx, req.NamespacedName, &edgeHostname); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	// Edge hostnames of other shards are left to the instances managing them
+	contains, err := r.Shard.containsAccount(ctx, r.Client, edgeHostname.Labels, edgeHostnameAccount(&edgeHostname))
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if !contains {
+		logger.V(1).Info("Edge hostname belongs to another shard", "shard", r.Shard.Name)
+		return ctrl.Result{}, nil
+	}
+
+	// The contract and group defaults of the provider config are only applied in memory, so the
+	// finalizer is patched rather than updated with the spec
+	scoped, err := r.forAccount(ctx, &edgeHostname)
+	if err != nil {
+		logger.Error(err, "Failed to create Akamai client")
+		r.setEdgeHostnameCondition(&edgeHostname, PhaseError, metav1.ConditionFalse, "FailedToCreateClient", akamai.ErrorMessage(err))
+		if updateErr := r.Status().Update(ctx, &edgeHostname); updateErr != nil {
+			return ctrl.Result{}, updateErr
+		}
+		return ctrl.Result{RequeueAfter: edgeHostnameErrorRetryInterval}, nil
+	}
+
+	if edgeHostname.DeletionTimestamp != nil {
+		return scoped.handleDeletion(ctx, &edgeHostname)
+	}
+	// The finalizer is added before the edge hostname is created, so it is never left behind
+	if !controllerutil.ContainsFinalizer(&edgeHostname, EdgeHostnameFinalizerName) {
+		patch := client.MergeFrom(edgeHostname.DeepCopy())
+		controllerutil.AddFinalizer(&edgeHostname, EdgeHostnameFinalizerName)
+		if err := r.Patch(ctx, &edgeHostname, patch); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	// The domain of an edge hostname can't be changed once it is created
+	if domain := edgeHostnameDomain(&edgeHostname); edgeHostname.Status.Domain != "" && edgeHostname.Status.Domain != domain {
+		r.setEdgeHostnameCondition(&edgeHostname, PhaseError, metav1.ConditionFalse, "InvalidSpec",
+			fmt.Sprintf("edge hostname %s was created as %s and can't be renamed; create another AkamaiEdgeHostname instead", domain, edgeHostname.Status.Domain))
+		return ctrl.Result{}, r.Status().Update(ctx, &edgeHostname)
+	}
+
+	err = scoped.syncEdgeHostname(ctx, &edgeHostname)
+	if message, observed := observeOnlyMessage(r.ObserveOnly, err); observed {
+		r.setEdgeHostnameCondition(&edgeHostname, PhaseObserving, metav1.ConditionFalse, ReasonObserveOnly, message)
+		if err := r.Status().Update(ctx, &edgeHostname); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{RequeueAfter: edgeHostnameResyncInterval}, nil
+	}
+	if err != nil {
+		logger.Error(err, "Failed to reconcile edge hostname", "edgeHostname", edgeHostnameDomain(&edgeHostname))
+		r.setEdgeHostnameCondition(&edgeHostname, PhaseError, metav1.ConditionFalse, "ReconcileError", akamai.ErrorMessage(err))
+		if updateErr := r.Status().Update(ctx, &edgeHostname); updateErr != nil {
+			return ctrl.Result{}, updateErr
+		}
+		return ctrl.Result{RequeueAfter: edgeHostnameErrorRetryInterval}, nil
+	}
+
+	r.setEdgeHostnameCondition(&edgeHostname, PhaseReady, metav1.ConditionTrue, "EdgeHostnameReady",
+		fmt.Sprintf("Edge hostname %s is up to date", edgeHostname.Status.Domain))
+	if err := r.Status().Update(ctx, &edgeHostname); err != nil {
+		return ctrl.Result{}, err
+	}
+	if certificatesPending(edgeHostname.Status.Certificates) {
+		return ctrl.Result{RequeueAfter: edgeHostnameCertPollInterval}, nil
+	}
+	return ctrl.Result{RequeueAfter: edgeHostnameResyncInterval}, nil
+}
+
+// forAccount returns the reconciler to reconcile an edge hostname with: r itself using the
+// operator's Akamai client, or a copy using the client of the edge hostname's account
+func (r *AkamaiEdgeHostnameReconciler) forAccount(ctx context.Context, edgeHostname *akamaiV1alpha1.AkamaiEdgeHostname) (*AkamaiEdgeHostnameReconciler, error) {
+	akamaiClient, err := resolveAccount(ctx, r.Client, r.ClientCache, r.Credentials, edgeHostnameAccount(edgeHostname))
+	if err != nil {
+		return nil, err
+	}
+
+	operatorClientMu.Lock()
+	defer operatorClientMu.Unlock()
+	if akamaiClient != nil {
+		scoped := *r
+		scoped.AkamaiClient = akamaiClient
+		return &scoped, nil
+	}
+	if r.AkamaiClient == nil {
+		akamaiClient, err := akamai.NewClient(r.Credentials)
+		if err != nil {
+			return nil, err
+		}
+		r.AkamaiClient = akamaiClient
+	}
+	return r, nil
+}
+
+// syncEdgeHostname creates or adopts the edge hostname and records its state and the
+// certificates of the hostnames CNAMEd to it in the status
+func (r *AkamaiEdgeHostnameReconciler) syncEdgeHostname(ctx context.Context, edgeHostname *akamaiV1alpha1.AkamaiEdgeHostname) error {
+	spec := edgeHostname.Spec
+	if edgeHostname.Status.EdgeHostnameID == "" {
+		edgeHostnameID, err := r.ensureEdgeHostname(ctx, edgeHostname)
+		if err != nil {
+			return err
+		}
+		edgeHostname.Status.EdgeHostnameID = edgeHostnameID
+	}
+
+	current, err := r.AkamaiClient.GetEdgeHostname(ctx, edgeHostname.Status.EdgeHostnameID, spec.ContractID, spec.GroupID)
+	if err != nil {
+		return err
+	}
+	edgeHostname.Status.Domain = current.Domain
+	edgeHostname.Status.Status = current.Status
+
+	properties, err := referencingProperties(ctx, r.Client, edgeHostname.Name)
+	if err != nil {
+		return err
+	}
+	edgeHostname.Status.ReferencedBy = nil
+	var certificates []akamaiV1alpha1.EdgeHostnameCertificate
+	for _, property := range properties {
+		edgeHostname.Status.ReferencedBy = append(edgeHostname.Status.ReferencedBy, property.Name)
+		if property.Status.PropertyID == "" || property.Status.LatestVersion == 0 {
+			continue
+		}
+		hostnames, err := r.AkamaiClient.GetPropertyHostnames(ctx, property.Status.PropertyID,
+			property.Spec.ContractID, property.Spec.GroupID, property.Status.LatestVersion)
+		if err != nil {
+			return fmt.Errorf("failed to get the hostnames of AkamaiProperty %s: %w", property.Name, err)
+		}
+		certificates = append(certificates, edgeHostnameCertificates(property.Name, current.Domain, hostnames)...)
+	}
+	edgeHostname.Status.Certificates = certificates
+	edgeHostname.Status.CertStatus = certStatusSummary(certificates)
+	return nil
+}
+
+// ensureEdgeHostname returns the ID of the edge hostname of the spec, creating it if it doesn't exist
+func (r *AkamaiEdgeHostnameReconciler) ensureEdgeHostname(ctx context.Context, edgeHostname *akamaiV1alpha1.AkamaiEdgeHostname) (string, error) {
+	logger := log.FromContext(ctx)
+	spec := edgeHostname.Spec
+	domain := edgeHostnameDomain(edgeHostname)
+
+	existing, err := r.AkamaiClient.ListEdgeHostnames(ctx, spec.ContractID, spec.GroupID)
+	if err != nil {
+		return "", err
+	}
+	for _, item := range existing {
+		if item.Domain == domain {
+			logger.Info("Adopting existing edge hostname", "edgeHostname", domain, "edgeHostnameID", item.ID)
+			return item.ID, nil
+		}
+	}
+
+	if r.ObserveOnly {
+		return "", observedDifference(fmt.Sprintf("Edge hostname %s doesn't exist in Akamai", domain))
+	}
+
+	certEnrollmentID := spec.CertEnrollmentID
+	if spec.CertificateRef != "" {
+		if certEnrollmentID, err = certificateEnrollmentID(ctx, r.Client, spec.CertificateRef); err != nil {
+			return "", err
+		}
+	}
+	edgeHostnameID, err := r.AkamaiClient.CreateEdgeHostname(ctx, &akamaiV1alpha1.EdgeHostnameSpec{
+		DomainPrefix:      edgeHostnamePrefix(edgeHostname),
+		DomainSuffix:      spec.DomainSuffix,
+		SecureNetwork:     spec.SecureNetwork,
+		CertEnrollmentID:  certEnrollmentID,
+		SlotNumber:        spec.SlotNumber,
+		IPVersionBehavior: spec.IPVersionBehavior,
+		UseCases:          spec.UseCases,
+	}, spec.ProductID, spec.ContractID, spec.GroupID)
+	if err != nil {
+		return "", err
+	}
+	logger.Info("Created edge hostname", "edgeHostname", domain, "edgeHostnameID", edgeHostnameID)
+	return edgeHostnameID, nil
+}
+
+// handleDeletion waits until no AkamaiProperty references the edge hostname anymore, deletes it
+// in Akamai with the Delete deletion policy and removes the finalizer
+func (r *AkamaiEdgeHostnameReconciler) handleDeletion(ctx context.Context, edgeHostname *akamaiV1alpha1.AkamaiEdgeHostname) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+	if !controllerutil.ContainsFinalizer(edgeHostname, EdgeHostnameFinalizerName) {
+		return ctrl.Result{}, nil
+	}
+
+	properties, err := referencingProperties(ctx, r.Client, edgeHostname.Name)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if len(properties) > 0 {
+		names := make([]string, 0, len(properties))
+		for _, property := range properties {
+			names = append(names, property.Name)
+		}
+		r.setEdgeHostnameCondition(edgeHostname, PhaseDeleting, metav1.ConditionFalse, "InUse",
+			fmt.Sprintf("Waiting for the AkamaiProperties %s to stop referencing the edge hostname", strings.Join(names, ", ")))
+		return ctrl.Result{RequeueAfter: edgeHostnameCertPollInterval}, r.Status().Update(ctx, edgeHostname)
+	}
+
+	// Nothing is deleted in Akamai in observe-only mode
+	if edgeHostname.Spec.DeletionPolicy == akamaiV1alpha1.DeletionPolicyDelete && edgeHostname.Status.Domain != "" && !r.ObserveOnly {
+		// Akamai refuses the deletion while a property outside the operator still uses the edge hostname
+		if err := r.AkamaiClient.DeleteEdgeHostname(ctx, edgeHostname.Status.Domain); err != nil {
+			logger.Error(err, "Failed to delete edge hostname", "edgeHostname", edgeHostname.Status.Domain)
+			r.setEdgeHostnameCondition(edgeHostname, PhaseDeleting, metav1.ConditionFalse, "DeletionFailed", akamai.ErrorMessage(err))
+			if updateErr := r.Status().Update(ctx, edgeHostname); updateErr != nil {
+				return ctrl.Result{}, updateErr
+			}
+			return ctrl.Result{RequeueAfter: edgeHostnameErrorRetryInterval}, nil
+		}
+		logger.Info("Deleted edge hostname", "edgeHostname", edgeHostname.Status.Domain)
+	}
+
+	patch := client.MergeFrom(edgeHostname.DeepCopy())
+	controllerutil.RemoveFinalizer(edgeHostname, EdgeHostnameFinalizerName)
+	return ctrl.Result{}, r.Patch(ctx, edgeHostname, patch)
+}
+
+// edgeHostnamePrefix returns the domain prefix of an edge hostname, defaulting to its name
+func edgeHostnamePrefix(edgeHostname *akamaiV1alpha1.AkamaiEdgeHostname) string {
+	if edgeHostname.Spec.DomainPrefix != "" {
+		return edgeHostname.Spec.DomainPrefix
+	}
+	return edgeHostname.Name
+}
+
+// edgeHostnameDomain returns the full edge hostname of the spec
+func edgeHostnameDomain(edgeHostname *akamaiV1alpha1.AkamaiEdgeHostname) string {
+	return edgeHostnamePrefix(edgeHostname) + "." + edgeHostname.Spec.DomainSuffix
+}
+
+// referencingProperties returns the AkamaiProperties not being deleted with a hostname
+// referencing the AkamaiEdgeHostname of the given name, sorted by name
+func referencingProperties(ctx context.Context, reader client.Reader, name string) ([]akamaiV1alpha1.AkamaiProperty, error) {
+	var list akamaiV1alpha1.AkamaiPropertyList
+	if err := reader.List(ctx, &list); err != nil {
+		return nil, fmt.Errorf("failed to list AkamaiProperties: %w", err)
+	}
+	var properties []akamaiV1alpha1.AkamaiProperty
+	for _, property := range list.Items {
+		if property.DeletionTimestamp.IsZero() && edgeHostnameRefs(&property)[name] {
+			properties = append(properties, property)
+		}
+	}
+	sort.Slice(properties, func(i, j int) bool { return properties[i].Name < properties[j].Name })
+	return properties, nil
+}
+
+// edgeHostnameCertificates returns the certificate state of the hostnames CNAMEd to the domain
+func edgeHostnameCertificates(propertyName, domain string, hostnames []akamai.Hostname) []akamaiV1alpha1.EdgeHostnameCertificate {
+	var certificates []akamaiV1alpha1.EdgeHostnameCertificate
+	for _, h := range hostnames {
+		if h.CNAMETo != domain {
+			continue
+		}
+		certificates = append(certificates, akamaiV1alpha1.EdgeHostnameCertificate{
+			Hostname:             h.CNAMEFrom,
+			Property:             propertyName,
+			StagingCertStatus:    h.StagingCertStatus,
+			ProductionCertStatus: h.ProductionCertStatus,
+		})
+	}
+	return certificates
+}
+
+// certStatusSummary returns DEPLOYED when the production certificates managed by PAPI are all
+// deployed, the status of the first one that isn't otherwise, and nothing without such certificates
+func certStatusSummary(certificates []akamaiV1alpha1.EdgeHostnameCertificate) string {
+	summary := ""
+	for _, certificate := range certificates {
+		switch certificate.ProductionCertStatus {
+		case "":
+		case akamai.CertStatusDeployed:
+			summary = akamai.CertStatusDeployed
+		default:
+			return certificate.ProductionCertStatus
+		}
+	}
+	return summary
+}
+
+// certificatesPending reports whether a certificate managed by PAPI is not deployed yet
+func certificatesPending(certificates []akamaiV1alpha1.EdgeHostnameCertificate) bool {
+	status := certStatusSummary(certificates)
+	return status != "" && status != akamai.CertStatusDeployed
+}
+
+// setEdgeHostnameCondition sets the phase and the Ready condition of the edge hostname
+func (r *AkamaiEdgeHostnameReconciler) setEdgeHostnameCondition(edgeHostname *akamaiV1alpha1.AkamaiEdgeHostname, phase string, status metav1.ConditionStatus, reason, message string) {
+	setReadyCondition(edgeHostname.Generation, readyStatus{
+		Phase:              &edgeHostname.Status.Phase,
+		ObservedGeneration: &edgeHostname.Status.ObservedGeneration,
+		LastUpdated:        &edgeHostname.Status.LastUpdated,
+		Conditions:         &edgeHostname.Status.Conditions,
+	}, phase, status, reason, message)
+}
+
+// edgeHostnamesReferencedBy enqueues the edge hostnames a changed AkamaiProperty references, so
+// status.referencedBy follows the property and a pending deletion completes once it is released
+func (r *AkamaiEdgeHostnameReconciler) edgeHostnamesReferencedBy(_ context.Context, obj client.Object) []reconcile.Request {
+	property, ok := obj.(*akamaiV1alpha1.AkamaiProperty)
+	if !ok {
+		return nil
+	}
+	var requests []reconcile.Request
+	for name := range edgeHostnameRefs(property) {
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKey{Name: name}})
+	}
+	return requests
+}
+
+// edgeHostnamesWaitingForCertificate enqueues the edge hostnames not created yet that reference a
+// changed AkamaiCertificate, so they are created as soon as its enrollment exists
+func (r *AkamaiEdgeHostnameReconciler) edgeHostnamesWaitingForCertificate(ctx context.Context, obj client.Object) []reconcile.Request {
+	var edgeHostnames akamaiV1alpha1.AkamaiEdgeHostnameList
+	if err := r.List(ctx, &edgeHostnames); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to list edge hostnames referencing certificate", "certificate", obj.GetName())
+		return nil
+	}
+	var requests []reconcile.Request
+	for _, edgeHostname := range edgeHostnames.Items {
+		if edgeHostname.Spec.CertificateRef == obj.GetName() && edgeHostname.Status.EdgeHostnameID == "" {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKey{Name: edgeHostname.Name}})
+		}
+	}
+	return requests
+}
+
+// SetupWithManager sets up the controller with the Manager. Status updates don't trigger
+// reconciles; edge hostnames are requeued to poll the certificates of their hostnames.
+func (r *AkamaiEdgeHostnameReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&akamaiV1alpha1.AkamaiEdgeHostname{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Watches(&akamaiV1alpha1.AkamaiProperty{}, handler.EnqueueRequestsFromMapFunc(r.edgeHostnamesReferencedBy),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Watches(&akamaiV1alpha1.AkamaiCertificate{}, handler.EnqueueRequestsFromMapFunc(r.edgeHostnamesWaitingForCertificate)).
+		Complete(r)
+}
@@ -0,0 +1,375 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v8/pkg/edgeworkers"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+	"github.com/mmz-srf/akamai-operator/pkg/akamai"
+)
+
+const (
+	// edgeKVNamespaceResyncInterval is how often an EdgeKV namespace is compared with Akamai
+	edgeKVNamespaceResyncInterval = 10 * time.Minute
+
+	// edgeKVInitializationPollInterval is how often the initialization of EdgeKV is checked
+	edgeKVInitializationPollInterval = time.Minute
+
+	// edgeKVNamespaceErrorRetryInterval is how long a failed EdgeKV namespace reconcile waits
+	// before it is retried
+	edgeKVNamespaceErrorRetryInterval = 2 * time.Minute
+
+	// edgeKVStagingGeoLocation is the only geo location of staging namespaces
+	edgeKVStagingGeoLocation = "US"
+)
+
+// AkamaiEdgeKVNamespaceReconciler creates EdgeKV namespaces on the networks of the spec, keeps
+// their retention and access group in line with it and seeds them with the items of a ConfigMap
+// or Secret. EdgeKV is initialized for the account when it isn't yet.
+type AkamaiEdgeKVNamespaceReconciler struct {
+	client.Client
+	Scheme       *runtime.Scheme
+	AkamaiClient *akamai.Client
+
+	// Credentials selects the credential source the Akamai client is created with
+	Credentials akamai.Credentials
+
+	// Shard is the part of the fleet this instance manages; nil manages all EdgeKV namespaces
+	Shard *Shard
+
+	// ObserveOnly compares the EdgeKV namespaces with Akamai and reports differences without
+	// changing them
+	ObserveOnly bool
+}
+
+//+kubebuilder:rbac:groups=akamai.com,resources=akamaiedgekvnamespaces,verbs=get;list;watch;update;patch
+//+kubebuilder:rbac:groups=akamai.com,resources=akamaiedgekvnamespaces/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch
+//+kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch
+
+// Reconcile brings an EdgeKV namespace to the state of its AkamaiEdgeKVNamespace. Namespaces
+// can't be deleted through the EdgeKV API, so deleting the resource leaves them in place.
+func (r *AkamaiEdgeKVNamespaceReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	var kv akamaiV1alpha1.AkamaiEdgeKVNamespace
+	if err := r.Get(ctx, req.NamespacedName, &kv); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+	if kv.DeletionTimestamp != nil {
+		return ctrl.Result{}, nil
+	}
+
+	// EdgeKV namespaces of other shards are left to the instances managing them
+	if !r.Shard.containsAccountWide(kv.Labels) {
+		logger.V(1).Info("EdgeKV namespace belongs to another shard", "shard", r.Shard.Name)
+		return ctrl.Result{}, nil
+	}
+
+	if r.AkamaiClient == nil {
+		akamaiClient, err := akamai.NewClient(r.Credentials)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		r.AkamaiClient = akamaiClient
+	}
+
+	initializing, err := r.syncEdgeKVNamespace(ctx, &kv)
+	if message, observed := observeOnlyMessage(r.ObserveOnly, err); observed {
+		r.setEdgeKVNamespaceCondition(&kv, PhaseObserving, metav1.ConditionFalse, ReasonObserveOnly, message)
+		if err := r.Status().Update(ctx, &kv); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{RequeueAfter: edgeKVNamespaceResyncInterval}, nil
+	}
+	if err != nil {
+		logger.Error(err, "Failed to reconcile EdgeKV namespace", "namespace", edgeKVNamespaceName(&kv))
+		r.setEdgeKVNamespaceCondition(&kv, PhaseError, metav1.ConditionFalse, "ReconcileError", akamai.ErrorMessage(err))
+		if updateErr := r.Status().Update(ctx, &kv); updateErr != nil {
+			return ctrl.Result{}, updateErr
+		}
+		return ctrl.Result{RequeueAfter: edgeKVNamespaceErrorRetryInterval}, nil
+	}
+
+	if initializing {
+		r.setEdgeKVNamespaceCondition(&kv, PhaseCreating, metav1.ConditionFalse, "InitializationInProgress",
+			fmt.Sprintf("EdgeKV is being initialized for the account (%s)", kv.Status.Initialization))
+		if err := r.Status().Update(ctx, &kv); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{RequeueAfter: edgeKVInitializationPollInterval}, nil
+	}
+	r.setEdgeKVNamespaceCondition(&kv, PhaseReady, metav1.ConditionTrue, "EdgeKVNamespaceReady",
+		fmt.Sprintf("EdgeKV namespace %s is up to date", edgeKVNamespaceName(&kv)))
+	if err := r.Status().Update(ctx, &kv); err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{RequeueAfter: edgeKVNamespaceResyncInterval}, nil
+}
+
+// syncEdgeKVNamespace initializes EdgeKV, creates or updates the namespace on each network of
+// the spec and seeds its items. It reports whether EdgeKV is still being initialized.
+func (r *AkamaiEdgeKVNamespaceReconciler) syncEdgeKVNamespace(ctx context.Context, kv *akamaiV1alpha1.AkamaiEdgeKVNamespace) (bool, error) {
+	logger := log.FromContext(ctx)
+
+	// Namespaces can only be created once the EdgeKV database of the account exists
+	if kv.Status.Initialization != akamai.EdgeKVInitialized {
+		initialization, err := r.AkamaiClient.GetEdgeKVInitialization(ctx)
+		if err != nil {
+			return false, err
+		}
+		if initialization.AccountStatus == akamai.EdgeKVUninitialized && r.ObserveOnly {
+			return false, observedDifference("EdgeKV isn't initialized for the account")
+		}
+		if initialization.AccountStatus == akamai.EdgeKVUninitialized {
+			if initialization, err = r.AkamaiClient.InitializeEdgeKV(ctx); err != nil {
+				return false, err
+			}
+			logger.Info("Initializing EdgeKV for the account")
+		}
+		kv.Status.Initialization = initialization.AccountStatus
+		if initialization.AccountStatus != akamai.EdgeKVInitialized {
+			return true, nil
+		}
+	}
+
+	groupID, err := numericGroupID(kv.Spec.GroupID)
+	if err != nil {
+		return false, err
+	}
+	items, err := r.edgeKVSeedItems(ctx, kv)
+	if err != nil {
+		return false, err
+	}
+
+	var changes []string
+	kv.Status.Staging, kv.Status.Production = nil, nil
+	for _, network := range edgeKVNamespaceNetworks(kv) {
+		status, networkChanges, err := r.syncEdgeKVNamespaceNetwork(ctx, kv, network, groupID, items)
+		if err != nil {
+			return false, err
+		}
+		if network == string(edgeworkers.NamespaceStagingNetwork) {
+			kv.Status.Staging = status
+		} else {
+			kv.Status.Production = status
+		}
+		changes = append(changes, networkChanges...)
+	}
+	if len(changes) > 0 && r.ObserveOnly {
+		return false, observedDifference(fmt.Sprintf("EdgeKV namespace %s differs from the spec: %s", edgeKVNamespaceName(kv), strings.Join(changes, ", ")))
+	}
+	if len(changes) > 0 {
+		kv.Status.LastChanges = changes
+	}
+	return false, nil
+}
+
+// syncEdgeKVNamespaceNetwork creates or updates the namespace on a network and writes the seed
+// items. It returns the state of the namespace and the changes made.
+func (r *AkamaiEdgeKVNamespaceReconciler) syncEdgeKVNamespaceNetwork(ctx context.Context, kv *akamaiV1alpha1.AkamaiEdgeKVNamespace, network string, groupID int, items map[string]string) (*akamaiV1alpha1.EdgeKVNamespaceNetworkStatus, []string, error) {
+	logger := log.FromContext(ctx)
+	name := edgeKVNamespaceName(kv)
+	retention := kv.Spec.RetentionSeconds
+
+	var changes []string
+	current, err := r.AkamaiClient.GetEdgeKVNamespace(ctx, network, name)
+	if err != nil {
+		return nil, nil, err
+	}
+	if current == nil && r.ObserveOnly {
+		return nil, nil, observedDifference(fmt.Sprintf("EdgeKV namespace %s doesn't exist on %s", name, network))
+	}
+	if current == nil {
+		created := edgeworkers.Namespace{Name: name, Retention: &retention, GroupID: &groupID}
+		if network == string(edgeworkers.NamespaceProductionNetwork) {
+			created.GeoLocation = edgeKVGeoLocation(kv)
+		}
+		if err := r.AkamaiClient.CreateEdgeKVNamespace(ctx, network, created); err != nil {
+			return nil, nil, err
+		}
+		logger.Info("Created EdgeKV namespace", "namespace", name, "network", network)
+		changes = append(changes, fmt.Sprintf("create namespace on %s", network))
+		current = &created
+	} else {
+		if network == string(edgeworkers.NamespaceProductionNetwork) && current.GeoLocation != "" && current.GeoLocation != edgeKVGeoLocation(kv) {
+			return nil, nil, fmt.Errorf("EdgeKV namespace %s exists on production with geo location %s instead of %s",
+				name, current.GeoLocation, edgeKVGeoLocation(kv))
+		}
+		if (edgeKVIntValue(current.Retention) != retention || edgeKVIntValue(current.GroupID) != groupID) && r.ObserveOnly {
+			changes = append(changes, fmt.Sprintf("update namespace on %s", network))
+		} else if edgeKVIntValue(current.Retention) != retention || edgeKVIntValue(current.GroupID) != groupID {
+			if err := r.AkamaiClient.UpdateEdgeKVNamespace(ctx, network, edgeworkers.UpdateNamespace{
+				Name: name, Retention: &retention, GroupID: &groupID,
+			}); err != nil {
+				return nil, nil, err
+			}
+			logger.Info("Updated EdgeKV namespace", "namespace", name, "network", network)
+			changes = append(changes, fmt.Sprintf("update namespace on %s", network))
+			current.Retention, current.GroupID = &retention, &groupID
+		}
+	}
+
+	status := &akamaiV1alpha1.EdgeKVNamespaceNetworkStatus{GeoLocation: current.GeoLocation, RetentionSeconds: edgeKVIntValue(current.Retention)}
+	if status.GeoLocation == "" && network == string(edgeworkers.NamespaceStagingNetwork) {
+		status.GeoLocation = edgeKVStagingGeoLocation
+	}
+	if kv.Spec.Seed == nil {
+		return status, changes, nil
+	}
+
+	group := kv.Spec.Seed.Group
+	existing, err := r.AkamaiClient.ListEdgeKVItems(ctx, network, name, group)
+	if err != nil {
+		return nil, nil, err
+	}
+	keys := make([]string, 0, len(items))
+	for key := range items {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	for _, key := range keys {
+		if slices.Contains(existing, key) {
+			status.SeededItems++
+			if !kv.Spec.Seed.Overwrite {
+				continue
+			}
+			value, err := r.AkamaiClient.GetEdgeKVItem(ctx, network, name, group, key)
+			if err != nil {
+				return nil, nil, err
+			}
+			if value != nil && *value == items[key] {
+				continue
+			}
+			if !r.ObserveOnly {
+				if err := r.AkamaiClient.PutEdgeKVItem(ctx, network, name, group, key, items[key]); err != nil {
+					return nil, nil, err
+				}
+			}
+			changes = append(changes, fmt.Sprintf("update item %s/%s on %s", group, key, network))
+			continue
+		}
+		if r.ObserveOnly {
+			changes = append(changes, fmt.Sprintf("create item %s/%s on %s", group, key, network))
+			continue
+		}
+		if err := r.AkamaiClient.PutEdgeKVItem(ctx, network, name, group, key, items[key]); err != nil {
+			return nil, nil, err
+		}
+		status.SeededItems++
+		changes = append(changes, fmt.Sprintf("create item %s/%s on %s", group, key, network))
+	}
+	if len(changes) > 0 && !r.ObserveOnly {
+		logger.Info("Seeded EdgeKV namespace", "namespace", name, "network", network, "group", group, "items", status.SeededItems)
+	}
+	return status, changes, nil
+}
+
+// edgeKVSeedItems reads the items of the seed from its ConfigMap or Secret, or returns nil
+// without a seed
+func (r *AkamaiEdgeKVNamespaceReconciler) edgeKVSeedItems(ctx context.Context, kv *akamaiV1alpha1.AkamaiEdgeKVNamespace) (map[string]string, error) {
+	seed := kv.Spec.Seed
+	if seed == nil {
+		return nil, nil
+	}
+	items := map[string]string{}
+	if ref := seed.ConfigMapRef; ref != nil {
+		var configMap corev1.ConfigMap
+		if err := r.Get(ctx, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, &configMap); err != nil {
+			return nil, fmt.Errorf("failed to get seed ConfigMap %s/%s: %w", ref.Namespace, ref.Name, err)
+		}
+		for key, value := range configMap.Data {
+			items[key] = value
+		}
+		for key, value := range configMap.BinaryData {
+			items[key] = string(value)
+		}
+	}
+	if ref := seed.SecretRef; ref != nil {
+		var secret corev1.Secret
+		if err := r.Get(ctx, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, &secret); err != nil {
+			return nil, fmt.Errorf("failed to get seed secret %s/%s: %w", ref.Namespace, ref.Name, err)
+		}
+		for key, value := range secret.Data {
+			items[key] = string(value)
+		}
+	}
+	return items, nil
+}
+
+// edgeKVNamespaceName returns the name of the EdgeKV namespace, defaulting to the name of the
+// resource
+func edgeKVNamespaceName(kv *akamaiV1alpha1.AkamaiEdgeKVNamespace) string {
+	if kv.Spec.Name != "" {
+		return kv.Spec.Name
+	}
+	return kv.Name
+}
+
+// edgeKVNamespaceNetworks returns the networks of the namespace, staging first, defaulting to
+// both networks
+func edgeKVNamespaceNetworks(kv *akamaiV1alpha1.AkamaiEdgeKVNamespace) []string {
+	if len(kv.Spec.Networks) == 0 {
+		return []string{string(edgeworkers.NamespaceStagingNetwork), string(edgeworkers.NamespaceProductionNetwork)}
+	}
+	var networks []string
+	for _, network := range []edgeworkers.NamespaceNetwork{edgeworkers.NamespaceStagingNetwork, edgeworkers.NamespaceProductionNetwork} {
+		if slices.Contains(kv.Spec.Networks, string(network)) {
+			networks = append(networks, string(network))
+		}
+	}
+	return networks
+}
+
+// edgeKVGeoLocation returns the geo location of the production namespace, defaulting to US
+func edgeKVGeoLocation(kv *akamaiV1alpha1.AkamaiEdgeKVNamespace) string {
+	if kv.Spec.GeoLocation != "" {
+		return kv.Spec.GeoLocation
+	}
+	return edgeKVStagingGeoLocation
+}
+
+// edgeKVIntValue returns the value of an optional number of the EdgeKV API, 0 when it is unset
+func edgeKVIntValue(value *int) int {
+	if value == nil {
+		return 0
+	}
+	return *value
+}
+
+// setEdgeKVNamespaceCondition sets the phase and the Ready condition of the EdgeKV namespace
+func (r *AkamaiEdgeKVNamespaceReconciler) setEdgeKVNamespaceCondition(kv *akamaiV1alpha1.AkamaiEdgeKVNamespace, phase string, status metav1.ConditionStatus, reason, message string) {
+	setReadyCondition(kv.Generation, readyStatus{
+		Phase:              &kv.Status.Phase,
+		ObservedGeneration: &kv.Status.ObservedGeneration,
+		LastUpdated:        &kv.Status.LastUpdated,
+		Conditions:         &kv.Status.Conditions,
+	}, phase, status, reason, message)
+}
+
+// SetupWithManager sets up the controller with the Manager. Status updates don't trigger
+// reconciles; EdgeKV namespaces are requeued to follow the initialization of EdgeKV, changes made
+// outside the operator and changes of their seed.
+func (r *AkamaiEdgeKVNamespaceReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&akamaiV1alpha1.AkamaiEdgeKVNamespace{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Complete(r)
+}
@@ -0,0 +1,449 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v8/pkg/gtm"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+	"github.com/mmz-srf/akamai-operator/pkg/akamai"
+)
+
+const (
+	// gtmDomainResyncInterval is how often a GTM domain is compared with Akamai
+	gtmDomainResyncInterval = 10 * time.Minute
+
+	// gtmPropagationPollInterval is how often the propagation of a change is checked
+	gtmPropagationPollInterval = time.Minute
+
+	// gtmDomainErrorRetryInterval is how long a failed GTM domain reconcile waits before it is retried
+	gtmDomainErrorRetryInterval = 2 * time.Minute
+
+	// defaultGTMDynamicTTL is the TTL of the answers of properties without their own
+	defaultGTMDynamicTTL = 60
+
+	// defaultGTMTestInterval and defaultGTMTestTimeout are the interval and timeout in seconds of
+	// liveness tests without their own
+	defaultGTMTestInterval = 60
+	defaultGTMTestTimeout  = 10
+
+	// gtmLivenessTestName is the name of the liveness test of the properties
+	gtmLivenessTestName = "health"
+)
+
+// AkamaiGtmDomainReconciler creates Global Traffic Management domains and keeps their datacenters
+// and properties in line with the spec, following the propagation of each change
+type AkamaiGtmDomainReconciler struct {
+	client.Client
+	Scheme       *runtime.Scheme
+	AkamaiClient *akamai.Client
+
+	// Credentials selects the credential source the Akamai client is created with
+	Credentials akamai.Credentials
+
+	// Shard is the part of the fleet this instance manages; nil manages all GTM domains
+	Shard *Shard
+
+	// ObserveOnly compares the GTM domains with Akamai and reports differences without changing
+	// them
+	ObserveOnly bool
+}
+
+//+kubebuilder:rbac:groups=akamai.com,resources=akamaigtmdomains,verbs=get;list;watch;update;patch
+//+kubebuilder:rbac:groups=akamai.com,resources=akamaigtmdomains/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=akamai.com,resources=akamaigtmdomains/finalizers,verbs=update
+
+// Reconcile brings a GTM domain to the state of its AkamaiGtmDomain
+func (r *AkamaiGtmDomainReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	var domain akamaiV1alpha1.AkamaiGtmDomain
+	if err := r.Get(ctx, req.NamespacedName, &domain); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	// GTM domains of other shards are left to the instances managing them
+	if !r.Shard.Contains(domain.Labels, domain.Spec.ContractID) {
+		logger.V(1).Info("GTM domain belongs to another shard", "shard", r.Shard.Name)
+		return ctrl.Result{}, nil
+	}
+
+	if r.AkamaiClient == nil {
+		akamaiClient, err := akamai.NewClient(r.Credentials)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		r.AkamaiClient = akamaiClient
+	}
+
+	if domain.DeletionTimestamp != nil {
+		return r.handleDeletion(ctx, &domain)
+	}
+	// The finalizer is added before the domain is created, so it is never left behind
+	if !controllerutil.ContainsFinalizer(&domain, GtmDomainFinalizerName) {
+		controllerutil.AddFinalizer(&domain, GtmDomainFinalizerName)
+		if err := r.Update(ctx, &domain); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	propagating, err := r.syncGtmDomain(ctx, &domain)
+	if message, observed := observeOnlyMessage(r.ObserveOnly, err); observed {
+		r.setGtmDomainCondition(&domain, PhaseObserving, metav1.ConditionFalse, ReasonObserveOnly, message)
+		if err := r.Status().Update(ctx, &domain); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{RequeueAfter: gtmDomainResyncInterval}, nil
+	}
+	if err != nil {
+		logger.Error(err, "Failed to reconcile GTM domain", "domain", gtmDomainName(&domain))
+		r.setGtmDomainCondition(&domain, PhaseError, metav1.ConditionFalse, "ReconcileError", akamai.ErrorMessage(err))
+		if updateErr := r.Status().Update(ctx, &domain); updateErr != nil {
+			return ctrl.Result{}, updateErr
+		}
+		return ctrl.Result{RequeueAfter: gtmDomainErrorRetryInterval}, nil
+	}
+
+	if propagating {
+		r.setGtmDomainCondition(&domain, PhaseActivating, metav1.ConditionFalse, "PropagationInProgress",
+			fmt.Sprintf("Change %s of GTM domain %s is propagating", domain.Status.ChangeID, gtmDomainName(&domain)))
+		if err := r.Status().Update(ctx, &domain); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{RequeueAfter: gtmPropagationPollInterval}, nil
+	}
+	r.setGtmDomainCondition(&domain, PhaseReady, metav1.ConditionTrue, "GtmDomainReady",
+		fmt.Sprintf("GTM domain %s is up to date", gtmDomainName(&domain)))
+	if err := r.Status().Update(ctx, &domain); err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{RequeueAfter: gtmDomainResyncInterval}, nil
+}
+
+// syncGtmDomain creates the domain, brings its settings, datacenters and properties in line with
+// the spec and records the propagation of the last change in the status. It reports whether the
+// change is still propagating.
+func (r *AkamaiGtmDomainReconciler) syncGtmDomain(ctx context.Context, domain *akamaiV1alpha1.AkamaiGtmDomain) (bool, error) {
+	logger := log.FromContext(ctx)
+	name := gtmDomainName(domain)
+	var changes []string
+
+	current, err := r.AkamaiClient.GetGTMDomain(ctx, name)
+	if err != nil {
+		return false, err
+	}
+	switch {
+	case current == nil && r.ObserveOnly:
+		return false, observedDifference(fmt.Sprintf("GTM domain %s doesn't exist in Akamai", name))
+	case current == nil:
+		if err := r.AkamaiClient.CreateGTMDomain(ctx, &gtm.Domain{
+			Name:                  name,
+			Type:                  gtmDomainType(domain),
+			EmailNotificationList: domain.Spec.NotificationEmails,
+		}, domain.Spec.ContractID, domain.Spec.GroupID); err != nil {
+			return false, err
+		}
+		logger.Info("Created GTM domain", "domain", name)
+		changes = append(changes, "create domain")
+	case (current.Type != gtmDomainType(domain) || !sameStrings(current.EmailNotificationList, domain.Spec.NotificationEmails)) && r.ObserveOnly:
+		changes = append(changes, "update domain settings")
+	case current.Type != gtmDomainType(domain) || !sameStrings(current.EmailNotificationList, domain.Spec.NotificationEmails):
+		// The domain is replaced as a whole, including the datacenters and properties it came with
+		current.Type = gtmDomainType(domain)
+		current.EmailNotificationList = domain.Spec.NotificationEmails
+		if err := r.AkamaiClient.UpdateGTMDomain(ctx, current); err != nil {
+			return false, err
+		}
+		changes = append(changes, "update domain settings")
+	}
+
+	datacenters, err := r.AkamaiClient.ListGTMDatacenters(ctx, name)
+	if err != nil {
+		return false, err
+	}
+	datacenterIDs := make(map[string]int, len(domain.Spec.Datacenters))
+	for _, spec := range domain.Spec.Datacenters {
+		index := slices.IndexFunc(datacenters, func(datacenter *gtm.Datacenter) bool { return datacenter.Nickname == spec.Nickname })
+		if index < 0 && r.ObserveOnly {
+			changes = append(changes, fmt.Sprintf("create datacenter %s", spec.Nickname))
+			continue
+		}
+		if index < 0 {
+			id, err := r.AkamaiClient.CreateGTMDatacenter(ctx, name, &gtm.Datacenter{
+				Nickname: spec.Nickname, City: spec.City, Country: spec.Country, Continent: spec.Continent,
+			})
+			if err != nil {
+				return false, err
+			}
+			datacenterIDs[spec.Nickname] = id
+			changes = append(changes, fmt.Sprintf("create datacenter %s", spec.Nickname))
+			continue
+		}
+		datacenter := datacenters[index]
+		datacenterIDs[spec.Nickname] = datacenter.DatacenterID
+		if (datacenter.City != spec.City || datacenter.Country != spec.Country || datacenter.Continent != spec.Continent) && r.ObserveOnly {
+			changes = append(changes, fmt.Sprintf("update datacenter %s", spec.Nickname))
+		} else if datacenter.City != spec.City || datacenter.Country != spec.Country || datacenter.Continent != spec.Continent {
+			datacenter.City, datacenter.Country, datacenter.Continent = spec.City, spec.Country, spec.Continent
+			if err := r.AkamaiClient.UpdateGTMDatacenter(ctx, name, datacenter); err != nil {
+				return false, err
+			}
+			changes = append(changes, fmt.Sprintf("update datacenter %s", spec.Nickname))
+		}
+	}
+	domain.Status.Datacenters = datacenterIDs
+	// The properties can't be compared before their datacenters exist
+	if r.ObserveOnly && len(changes) > 0 {
+		return false, observedDifference(fmt.Sprintf("GTM domain %s differs from the spec: %s", name, strings.Join(changes, ", ")))
+	}
+
+	properties, err := r.AkamaiClient.ListGTMProperties(ctx, name)
+	if err != nil {
+		return false, err
+	}
+	for _, spec := range domain.Spec.Properties {
+		index := slices.IndexFunc(properties, func(property *gtm.Property) bool { return property.Name == spec.Name })
+		var property *gtm.Property
+		if index < 0 {
+			// Settings the spec doesn't cover get Akamai's recommended values
+			property = &gtm.Property{Name: spec.Name, ScoreAggregationType: "worst", HandoutMode: "normal", HandoutLimit: 8}
+		} else {
+			copied := *properties[index]
+			property = &copied
+		}
+		if err := setGTMProperty(property, spec, datacenterIDs); err != nil {
+			return false, err
+		}
+		if index >= 0 && equality.Semantic.DeepEqual(gtmPropertyStateOf(properties[index]), gtmPropertyStateOf(property)) {
+			continue
+		}
+		if !r.ObserveOnly {
+			if err := r.AkamaiClient.PutGTMProperty(ctx, name, property); err != nil {
+				return false, err
+			}
+		}
+		if index < 0 {
+			changes = append(changes, fmt.Sprintf("create property %s", spec.Name))
+		} else {
+			changes = append(changes, fmt.Sprintf("update property %s", spec.Name))
+		}
+	}
+	if r.ObserveOnly && len(changes) > 0 {
+		return false, observedDifference(fmt.Sprintf("GTM domain %s differs from the spec: %s", name, strings.Join(changes, ", ")))
+	}
+	if len(changes) > 0 {
+		logger.Info("Updated GTM domain", "domain", name, "changes", changes)
+		domain.Status.LastChanges = changes
+	}
+
+	status, err := r.AkamaiClient.GetGTMDomainStatus(ctx, name)
+	if err != nil {
+		return false, err
+	}
+	domain.Status.PropagationStatus = status.PropagationStatus
+	domain.Status.ChangeID = status.ChangeID
+	switch status.PropagationStatus {
+	case akamai.GTMPropagationPending:
+		return true, nil
+	case akamai.GTMPropagationDenied:
+		return false, fmt.Errorf("change %s of GTM domain %s was denied: %s", status.ChangeID, name, status.Message)
+	}
+	return false, nil
+}
+
+// handleDeletion deletes the GTM domain with the Delete deletion policy and removes the finalizer
+func (r *AkamaiGtmDomainReconciler) handleDeletion(ctx context.Context, domain *akamaiV1alpha1.AkamaiGtmDomain) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+	if !controllerutil.ContainsFinalizer(domain, GtmDomainFinalizerName) {
+		return ctrl.Result{}, nil
+	}
+
+	// Nothing is deleted in Akamai in observe-only mode
+	if domain.Spec.DeletionPolicy == akamaiV1alpha1.DeletionPolicyDelete && !r.ObserveOnly {
+		if err := r.AkamaiClient.DeleteGTMDomain(ctx, gtmDomainName(domain)); err != nil {
+			logger.Error(err, "Failed to delete GTM domain", "domain", gtmDomainName(domain))
+			r.setGtmDomainCondition(domain, PhaseDeleting, metav1.ConditionFalse, "DeletionFailed", akamai.ErrorMessage(err))
+			if updateErr := r.Status().Update(ctx, domain); updateErr != nil {
+				return ctrl.Result{}, updateErr
+			}
+			return ctrl.Result{RequeueAfter: gtmDomainErrorRetryInterval}, nil
+		}
+		logger.Info("Deleted GTM domain", "domain", gtmDomainName(domain))
+	}
+
+	controllerutil.RemoveFinalizer(domain, GtmDomainFinalizerName)
+	return ctrl.Result{}, r.Update(ctx, domain)
+}
+
+// gtmDomainName returns the name of the GTM domain, defaulting to the name of the resource
+func gtmDomainName(domain *akamaiV1alpha1.AkamaiGtmDomain) string {
+	if domain.Spec.Name != "" {
+		return domain.Spec.Name
+	}
+	return domain.Name
+}
+
+// gtmDomainType returns the type of the GTM domain, defaulting to basic
+func gtmDomainType(domain *akamaiV1alpha1.AkamaiGtmDomain) string {
+	if domain.Spec.Type != "" {
+		return domain.Spec.Type
+	}
+	return "basic"
+}
+
+// setGTMProperty sets the settings of a property the spec covers. The traffic targets of a
+// failover property are weighted by their order: the first enabled target is the primary.
+func setGTMProperty(property *gtm.Property, spec akamaiV1alpha1.GtmPropertySpec, datacenterIDs map[string]int) error {
+	property.Type = spec.Type
+	property.IPv6 = spec.IPv6
+	property.DynamicTTL = spec.DynamicTTL
+	if property.DynamicTTL == 0 {
+		property.DynamicTTL = defaultGTMDynamicTTL
+	}
+
+	property.TrafficTargets = make([]*gtm.TrafficTarget, 0, len(spec.TrafficTargets))
+	primary := true
+	for _, target := range spec.TrafficTargets {
+		datacenterID, ok := datacenterIDs[target.Datacenter]
+		if !ok {
+			return fmt.Errorf("traffic target of property %s references unknown datacenter %s", spec.Name, target.Datacenter)
+		}
+		weight := float64(target.Weight)
+		if spec.Type == "failover" {
+			weight = 0
+			if primary && !target.Disabled {
+				weight, primary = 1, false
+			}
+		}
+		property.TrafficTargets = append(property.TrafficTargets, &gtm.TrafficTarget{
+			DatacenterID: datacenterID,
+			Enabled:      !target.Disabled,
+			Weight:       weight,
+			Servers:      target.Servers,
+			HandoutCName: target.HandoutCName,
+		})
+	}
+
+	property.LivenessTests = nil
+	if test := spec.LivenessTest; test != nil {
+		interval, timeout := test.IntervalSeconds, test.TimeoutSeconds
+		if interval == 0 {
+			interval = defaultGTMTestInterval
+		}
+		if timeout == 0 {
+			timeout = defaultGTMTestTimeout
+		}
+		property.LivenessTests = []*gtm.LivenessTest{{
+			Name:               gtmLivenessTestName,
+			TestObjectProtocol: test.Protocol,
+			TestObject:         test.Path,
+			TestObjectPort:     gtmTestPort(test),
+			TestInterval:       interval,
+			TestTimeout:        float32(timeout),
+			HTTPError4xx:       true,
+			HTTPError5xx:       true,
+		}}
+	}
+	return nil
+}
+
+// gtmTestPort returns the port of a liveness test, defaulting to the port of its protocol
+func gtmTestPort(test *akamaiV1alpha1.GtmLivenessTestSpec) int {
+	switch {
+	case test.Port != 0:
+		return test.Port
+	case test.Protocol == "HTTPS":
+		return 443
+	default:
+		return 80
+	}
+}
+
+// gtmPropertyState is the part of a GTM property the spec covers, in a comparable form
+type gtmPropertyState struct {
+	Type           string
+	IPv6           bool
+	DynamicTTL     int
+	TrafficTargets []gtmTrafficTargetState
+	LivenessTests  []gtmLivenessTestState
+}
+
+type gtmTrafficTargetState struct {
+	DatacenterID int
+	Enabled      bool
+	Weight       float64
+	Servers      []string
+	HandoutCName string
+}
+
+type gtmLivenessTestState struct {
+	Protocol string
+	Object   string
+	Port     int
+	Interval int
+	Timeout  float32
+}
+
+// gtmPropertyStateOf returns the state of a property the spec covers, with the traffic targets
+// sorted by datacenter and their servers sorted
+func gtmPropertyStateOf(property *gtm.Property) gtmPropertyState {
+	state := gtmPropertyState{Type: property.Type, IPv6: property.IPv6, DynamicTTL: property.DynamicTTL}
+	for _, target := range property.TrafficTargets {
+		servers := slices.Clone(target.Servers)
+		slices.Sort(servers)
+		state.TrafficTargets = append(state.TrafficTargets, gtmTrafficTargetState{
+			DatacenterID: target.DatacenterID,
+			Enabled:      target.Enabled,
+			Weight:       target.Weight,
+			Servers:      servers,
+			HandoutCName: strings.TrimSuffix(target.HandoutCName, "."),
+		})
+	}
+	slices.SortFunc(state.TrafficTargets, func(a, b gtmTrafficTargetState) int { return a.DatacenterID - b.DatacenterID })
+	for _, test := range property.LivenessTests {
+		state.LivenessTests = append(state.LivenessTests, gtmLivenessTestState{
+			Protocol: test.TestObjectProtocol,
+			Object:   test.TestObject,
+			Port:     test.TestObjectPort,
+			Interval: test.TestInterval,
+			Timeout:  test.TestTimeout,
+		})
+	}
+	return state
+}
+
+// setGtmDomainCondition sets the phase and the Ready condition of the GTM domain
+func (r *AkamaiGtmDomainReconciler) setGtmDomainCondition(domain *akamaiV1alpha1.AkamaiGtmDomain, phase string, status metav1.ConditionStatus, reason, message string) {
+	setReadyCondition(domain.Generation, readyStatus{
+		Phase:              &domain.Status.Phase,
+		ObservedGeneration: &domain.Status.ObservedGeneration,
+		LastUpdated:        &domain.Status.LastUpdated,
+		Conditions:         &domain.Status.Conditions,
+	}, phase, status, reason, message)
+}
+
+// SetupWithManager sets up the controller with the Manager. Status updates don't trigger
+// reconciles; GTM domains are requeued to follow propagations and changes made outside the
+// operator.
+func (r *AkamaiGtmDomainReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&akamaiV1alpha1.AkamaiGtmDomain{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Complete(r)
+}
@@ -0,0 +1,367 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v8/pkg/networklists"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+	"github.com/mmz-srf/akamai-operator/pkg/akamai"
+)
+
+const (
+	// networkListResyncInterval is how often a network list is compared with Akamai
+	networkListResyncInterval = 10 * time.Minute
+
+	// networkListActivationPollInterval is how often a running activation is checked
+	networkListActivationPollInterval = time.Minute
+
+	// networkListErrorRetryInterval is how long a failed network list reconcile waits before it is retried
+	networkListErrorRetryInterval = 2 * time.Minute
+
+	// defaultNetworkListActivationComments are the comments of activations without their own
+	defaultNetworkListActivationComments = "Activated by akamai-operator"
+)
+
+// AkamaiNetworkListReconciler creates Akamai network lists, keeps their elements in line with
+// the spec and activates each change on the networks the spec selects
+type AkamaiNetworkListReconciler struct {
+	client.Client
+	Scheme       *runtime.Scheme
+	AkamaiClient *akamai.Client
+
+	// Credentials selects the credential source the Akamai client is created with
+	Credentials akamai.Credentials
+
+	// Shard is the part of the fleet this instance manages; nil manages all network lists
+	Shard *Shard
+
+	// ObserveOnly compares the network lists with Akamai and reports differences without
+	// changing or activating them
+	ObserveOnly bool
+}
+
+//+kubebuilder:rbac:groups=akamai.com,resources=akamainetworklists,verbs=get;list;watch;update;patch
+//+kubebuilder:rbac:groups=akamai.com,resources=akamainetworklists/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=akamai.com,resources=akamainetworklists/finalizers,verbs=update
+
+// Reconcile brings an Akamai network list to the state of its AkamaiNetworkList
+func (r *AkamaiNetworkListReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	var list akamaiV1alpha1.AkamaiNetworkList
+	if err := r.Get(ctx, req.NamespacedName, &list); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	// Network lists of other shards are left to the instances managing them
+	if !r.Shard.Contains(list.Labels, list.Spec.ContractID) {
+		logger.V(1).Info("Network list belongs to another shard", "shard", r.Shard.Name)
+		return ctrl.Result{}, nil
+	}
+
+	if r.AkamaiClient == nil {
+		akamaiClient, err := akamai.NewClient(r.Credentials)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		r.AkamaiClient = akamaiClient
+	}
+
+	if list.DeletionTimestamp != nil {
+		return r.handleDeletion(ctx, &list)
+	}
+	// The finalizer is added before the list is created, so it is never left behind
+	if !controllerutil.ContainsFinalizer(&list, NetworkListFinalizerName) {
+		controllerutil.AddFinalizer(&list, NetworkListFinalizerName)
+		if err := r.Update(ctx, &list); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	activating, err := r.syncNetworkList(ctx, &list)
+	if message, observed := observeOnlyMessage(r.ObserveOnly, err); observed {
+		r.setNetworkListCondition(&list, PhaseObserving, metav1.ConditionFalse, ReasonObserveOnly, message)
+		if err := r.Status().Update(ctx, &list); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{RequeueAfter: networkListResyncInterval}, nil
+	}
+	if err != nil {
+		logger.Error(err, "Failed to reconcile network list", "name", networkListName(&list))
+		r.setNetworkListCondition(&list, PhaseError, metav1.ConditionFalse, "ReconcileError", akamai.ErrorMessage(err))
+		if updateErr := r.Status().Update(ctx, &list); updateErr != nil {
+			return ctrl.Result{}, updateErr
+		}
+		return ctrl.Result{RequeueAfter: networkListErrorRetryInterval}, nil
+	}
+
+	if activating {
+		r.setNetworkListCondition(&list, PhaseActivating, metav1.ConditionFalse, "ActivationInProgress",
+			fmt.Sprintf("Network list %s version %d is being activated", list.Status.UniqueID, list.Status.SyncPoint))
+		if err := r.Status().Update(ctx, &list); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{RequeueAfter: networkListActivationPollInterval}, nil
+	}
+	r.setNetworkListCondition(&list, PhaseReady, metav1.ConditionTrue, "NetworkListReady",
+		fmt.Sprintf("Network list %s is up to date", list.Status.UniqueID))
+	if err := r.Status().Update(ctx, &list); err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{RequeueAfter: networkListResyncInterval}, nil
+}
+
+// syncNetworkList creates, adopts or updates the network list, activates it and records its
+// state in the status. It reports whether an activation is still running.
+func (r *AkamaiNetworkListReconciler) syncNetworkList(ctx context.Context, list *akamaiV1alpha1.AkamaiNetworkList) (bool, error) {
+	logger := log.FromContext(ctx)
+	name := networkListName(list)
+
+	var current *networklists.GetNetworkListResponse
+	uniqueID := list.Status.UniqueID
+	if uniqueID != "" {
+		var err error
+		if current, err = r.AkamaiClient.GetNetworkList(ctx, uniqueID); err != nil {
+			return false, err
+		}
+		if current == nil {
+			logger.Info("Network list was deleted outside the operator, recreating it", "uniqueId", uniqueID)
+			uniqueID = ""
+		}
+	}
+	if current == nil {
+		var err error
+		if uniqueID, err = r.AkamaiClient.FindNetworkList(ctx, name, list.Spec.Type); err != nil {
+			return false, err
+		}
+		if uniqueID != "" {
+			logger.Info("Adopting existing network list", "name", name, "uniqueId", uniqueID)
+		} else if r.ObserveOnly {
+			return false, observedDifference(fmt.Sprintf("Network list %s doesn't exist in Akamai", name))
+		} else {
+			groupID, err := numericGroupID(list.Spec.GroupID)
+			if err != nil {
+				return false, err
+			}
+			if uniqueID, err = r.AkamaiClient.CreateNetworkList(ctx, networklists.CreateNetworkListRequest{
+				Name:        name,
+				Type:        list.Spec.Type,
+				Description: list.Spec.Description,
+				ContractID:  strings.TrimPrefix(list.Spec.ContractID, "ctr_"),
+				GroupID:     groupID,
+				List:        networkListElements(list),
+			}); err != nil {
+				return false, err
+			}
+			logger.Info("Created network list", "name", name, "uniqueId", uniqueID)
+		}
+		if current, err = r.AkamaiClient.GetNetworkList(ctx, uniqueID); err != nil {
+			return false, err
+		}
+		if current == nil {
+			return false, fmt.Errorf("network list %s was created but can't be found", uniqueID)
+		}
+	}
+
+	if networkListNeedsUpdate(list, current) {
+		if r.ObserveOnly {
+			return false, observedDifference(fmt.Sprintf("The elements of network list %s differ from the spec", current.UniqueID))
+		}
+		if err := r.AkamaiClient.UpdateNetworkList(ctx, networklists.UpdateNetworkListRequest{
+			UniqueID:    current.UniqueID,
+			Name:        current.Name,
+			Type:        current.Type,
+			Description: list.Spec.Description,
+			SyncPoint:   current.SyncPoint,
+			List:        networkListElements(list),
+		}); err != nil {
+			return false, err
+		}
+		logger.Info("Updated network list", "uniqueId", current.UniqueID, "elements", len(list.Spec.Elements))
+		updated, err := r.AkamaiClient.GetNetworkList(ctx, current.UniqueID)
+		if err != nil {
+			return false, err
+		}
+		if updated == nil {
+			return false, fmt.Errorf("network list %s was updated but can't be found", current.UniqueID)
+		}
+		current = updated
+	}
+
+	list.Status.UniqueID = current.UniqueID
+	list.Status.SyncPoint = current.SyncPoint
+	list.Status.ElementCount = current.ElementCount
+
+	activation := list.Spec.Activation
+	if activation == nil {
+		activation = &akamaiV1alpha1.NetworkListActivationSpec{}
+	}
+	stagingActivating, err := r.activateNetworkList(ctx, list, "STAGING", activation.Staging, &list.Status.Staging)
+	if err != nil {
+		return false, err
+	}
+	productionActivating, err := r.activateNetworkList(ctx, list, "PRODUCTION", activation.Production, &list.Status.Production)
+	if err != nil {
+		return false, err
+	}
+	return stagingActivating || productionActivating, nil
+}
+
+// activateNetworkList activates the current version of the list on a network unless it is
+// active or being activated there, and records the activation state. It reports whether an
+// activation is running.
+func (r *AkamaiNetworkListReconciler) activateNetworkList(ctx context.Context, list *akamaiV1alpha1.AkamaiNetworkList, network string, enabled bool, status **akamaiV1alpha1.NetworkListActivationStatus) (bool, error) {
+	if !enabled {
+		*status = nil
+		return false, nil
+	}
+	state, err := r.AkamaiClient.GetNetworkListActivation(ctx, list.Status.UniqueID, network)
+	if err != nil {
+		return false, err
+	}
+	*status = &akamaiV1alpha1.NetworkListActivationStatus{Status: state.ActivationStatus, SyncPoint: state.SyncPoint, ActivationID: state.ActivationID}
+	if state.SyncPoint == list.Status.SyncPoint {
+		switch state.ActivationStatus {
+		case akamai.NetworkListStatusActive:
+			return false, nil
+		case akamai.NetworkListStatusPendingActivation:
+			return true, nil
+		case akamai.NetworkListStatusFailed:
+			// Activating the same version again would fail the same way
+			return false, fmt.Errorf("activation %d of network list %s version %d on %s failed",
+				state.ActivationID, list.Status.UniqueID, state.SyncPoint, network)
+		}
+	}
+
+	if r.ObserveOnly {
+		return false, observedDifference(fmt.Sprintf("Network list %s version %d isn't active on %s", list.Status.UniqueID, list.Status.SyncPoint, network))
+	}
+
+	comments := defaultNetworkListActivationComments
+	if list.Spec.Activation.Comments != "" {
+		comments = list.Spec.Activation.Comments
+	}
+	activated, err := r.AkamaiClient.ActivateNetworkList(ctx, list.Status.UniqueID, network, comments, list.Spec.Activation.NotificationEmails)
+	if err != nil {
+		return false, err
+	}
+	log.FromContext(ctx).Info("Activating network list", "uniqueId", list.Status.UniqueID, "network", network,
+		"syncPoint", list.Status.SyncPoint, "activationId", activated.ActivationID)
+	*status = &akamaiV1alpha1.NetworkListActivationStatus{Status: activated.ActivationStatus, SyncPoint: activated.SyncPoint, ActivationID: activated.ActivationID}
+	return activated.ActivationStatus != akamai.NetworkListStatusActive, nil
+}
+
+// handleDeletion deletes the network list with the Delete deletion policy and removes the finalizer
+func (r *AkamaiNetworkListReconciler) handleDeletion(ctx context.Context, list *akamaiV1alpha1.AkamaiNetworkList) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+	if !controllerutil.ContainsFinalizer(list, NetworkListFinalizerName) {
+		return ctrl.Result{}, nil
+	}
+
+	// Nothing is deleted in Akamai in observe-only mode
+	if list.Spec.DeletionPolicy == akamaiV1alpha1.DeletionPolicyDelete && list.Status.UniqueID != "" && !r.ObserveOnly {
+		if err := r.AkamaiClient.DeleteNetworkList(ctx, list.Status.UniqueID); err != nil {
+			logger.Error(err, "Failed to delete network list", "uniqueId", list.Status.UniqueID)
+			r.setNetworkListCondition(list, PhaseDeleting, metav1.ConditionFalse, "DeletionFailed", akamai.ErrorMessage(err))
+			if updateErr := r.Status().Update(ctx, list); updateErr != nil {
+				return ctrl.Result{}, updateErr
+			}
+			return ctrl.Result{RequeueAfter: networkListErrorRetryInterval}, nil
+		}
+		logger.Info("Deleted network list", "uniqueId", list.Status.UniqueID)
+	}
+
+	controllerutil.RemoveFinalizer(list, NetworkListFinalizerName)
+	return ctrl.Result{}, r.Update(ctx, list)
+}
+
+// networkListName returns the name of the network list, defaulting to the name of the resource
+func networkListName(list *akamaiV1alpha1.AkamaiNetworkList) string {
+	if list.Spec.Name != "" {
+		return list.Spec.Name
+	}
+	return list.Name
+}
+
+// numericGroupID returns the numeric group ID the Network Lists and Application Security APIs
+// expect, or 0 when no group is set
+func numericGroupID(groupID string) (int, error) {
+	if groupID == "" {
+		return 0, nil
+	}
+	id, err := strconv.Atoi(strings.TrimPrefix(groupID, "grp_"))
+	if err != nil {
+		return 0, fmt.Errorf("invalid group ID %q", groupID)
+	}
+	return id, nil
+}
+
+// networkListElements returns the elements of the spec in their canonical form, sorted and
+// without duplicates: country codes upper case, IP addresses lower case
+func networkListElements(list *akamaiV1alpha1.AkamaiNetworkList) []string {
+	elements := make([]string, 0, len(list.Spec.Elements))
+	for _, element := range list.Spec.Elements {
+		element = strings.TrimSpace(element)
+		if list.Spec.Type == "GEO" {
+			element = strings.ToUpper(element)
+		} else {
+			element = strings.ToLower(element)
+		}
+		elements = append(elements, element)
+	}
+	slices.Sort(elements)
+	return slices.Compact(elements)
+}
+
+// networkListNeedsUpdate reports whether the description or the elements of the network list in
+// Akamai differ from the spec. The order of the elements doesn't matter.
+func networkListNeedsUpdate(list *akamaiV1alpha1.AkamaiNetworkList, current *networklists.GetNetworkListResponse) bool {
+	currentElements := make([]string, 0, len(current.List))
+	for _, element := range current.List {
+		if list.Spec.Type == "GEO" {
+			currentElements = append(currentElements, strings.ToUpper(element))
+		} else {
+			currentElements = append(currentElements, strings.ToLower(element))
+		}
+	}
+	slices.Sort(currentElements)
+	return list.Spec.Description != current.Description ||
+		!slices.Equal(networkListElements(list), slices.Compact(currentElements))
+}
+
+// setNetworkListCondition sets the phase and the Ready condition of the network list
+func (r *AkamaiNetworkListReconciler) setNetworkListCondition(list *akamaiV1alpha1.AkamaiNetworkList, phase string, status metav1.ConditionStatus, reason, message string) {
+	setReadyCondition(list.Generation, readyStatus{
+		Phase:              &list.Status.Phase,
+		ObservedGeneration: &list.Status.ObservedGeneration,
+		LastUpdated:        &list.Status.LastUpdated,
+		Conditions:         &list.Status.Conditions,
+	}, phase, status, reason, message)
+}
+
+// SetupWithManager sets up the controller with the Manager. Status updates don't trigger
+// reconciles; network lists are requeued to follow activations and changes made outside the
+// operator.
+func (r *AkamaiNetworkListReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&akamaiV1alpha1.AkamaiNetworkList{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Complete(r)
+}
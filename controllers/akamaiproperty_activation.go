@@ -2,6 +2,7 @@ package controllers
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -32,15 +33,21 @@ func (r *AkamaiPropertyReconciler) handleActivation(ctx context.Context, akamaiP
 		lastActivationNote = akamaiProperty.Status.ProductionActivationNote
 	}
 
-	// Check if activation note has changed - this is the trigger for new activation
+	// Check if activation note has changed - this is the trigger for new activation with the NoteChange trigger
 	activationNoteChanged := activationSpec.Note != lastActivationNote
+	trigger := activationTrigger(activationSpec)
+	schedulerKey := activationKey(r.AkamaiClient, akamaiProperty, activationSpec.Network)
 
 	// Check if we need to start a new activation
 	needsActivation := false
 	if currentActivationID == "" {
 		// No previous activation
-		needsActivation = true
-		logger.Info("No previous activation found, will activate", "network", activationSpec.Network, "version", versionToActivate)
+		needsActivation = shouldActivate(trigger, activationNoteChanged, versionToActivate, 0)
+		if needsActivation {
+			logger.Info("No previous activation found, will activate", "network", activationSpec.Network, "version", versionToActivate)
+		} else {
+			logger.V(1).Info("No previous activation found, waiting for a manual activation", "network", activationSpec.Network, "trigger", trigger)
+		}
 	} else {
 		// Check if there's already an activation in progress
 		if currentActivationStatus == "PENDING" || currentActivationStatus == "ACTIVATING" {
@@ -48,12 +55,19 @@ func (r *AkamaiPropertyReconciler) handleActivation(ctx context.Context, akamaiP
 			activation, err := r.AkamaiClient.GetActivation(ctx, akamaiProperty.Status.PropertyID, currentActivationID)
 			if err != nil {
 				logger.Error(err, "Failed to get activation status")
-				return ctrl.Result{RequeueAfter: time.Minute * 2}, nil
+				return r.retryAfterError(akamaiProperty, err), nil
 			}
 
 			// Update the status based on the current activation
 			r.updateActivationStatus(akamaiProperty, activationSpec.Network, activation)
 
+			// Keep the scheduler in sync, also for activations submitted before a restart
+			if akamai.ActivationFinished(activation.Status) {
+				r.ActivationScheduler.Release(schedulerKey)
+			} else {
+				r.ActivationScheduler.Track(schedulerKey)
+			}
+
 			// Check if the in-progress activation is for an older version
 			if activation.PropertyVersion < versionToActivate {
 				logger.Info("Found activation for older version, will activate newer version after current completes",
@@ -67,25 +81,28 @@ func (r *AkamaiPropertyReconciler) handleActivation(ctx context.Context, akamaiP
 						"network", activationSpec.Network,
 						"oldVersion", activation.PropertyVersion,
 						"newVersion", versionToActivate)
-					return ctrl.Result{RequeueAfter: time.Minute * 2, Requeue: true}, nil
+					return ctrl.Result{RequeueAfter: r.versionPollInterval()}, nil
 				}
 				// Old activation completed (ACTIVE/FAILED/etc)
-				// Only activate if the note has changed (to prevent auto-activation loops)
-				if activationNoteChanged {
-					logger.Info("Old activation complete and note changed, will activate new version",
+				// Only activate if the trigger asks for it (to prevent auto-activation loops)
+				if shouldActivate(trigger, activationNoteChanged, versionToActivate, activation.PropertyVersion) {
+					logger.Info("Old activation complete, will activate new version",
 						"network", activationSpec.Network,
+						"trigger", trigger,
 						"oldVersion", activation.PropertyVersion,
 						"newVersion", versionToActivate)
 					needsActivation = true
 				} else {
-					logger.Info("Old activation complete but note unchanged, skipping activation",
+					logger.Info("Old activation complete, skipping activation",
 						"network", activationSpec.Network,
+						"trigger", trigger,
 						"latestVersion", versionToActivate,
 						"activeVersion", activation.PropertyVersion)
 				}
 			} else if activation.PropertyVersion == versionToActivate && (activation.Status == "PENDING" || activation.Status == "ACTIVATING") {
 				// Activation already in progress for current version, just monitor it
 				logger.Info("Activation in progress for current version", "network", activationSpec.Network, "status", activation.Status, "version", versionToActivate)
+				r.watchActivation(akamaiProperty, currentActivationID)
 				r.updateStatus(ctx, akamaiProperty, PhaseActivating, "ActivationInProgress", fmt.Sprintf("Status: %s", activation.Status))
 				return ctrl.Result{RequeueAfter: time.Minute * 2, Requeue: true}, nil
 			} else if activation.Status == "ACTIVE" {
@@ -98,10 +115,14 @@ func (r *AkamaiPropertyReconciler) handleActivation(ctx context.Context, akamaiP
 			} else {
 				// Still in progress for current version
 				logger.Info("Activation in progress", "network", activationSpec.Network, "status", activation.Status)
+				r.watchActivation(akamaiProperty, currentActivationID)
 				r.updateStatus(ctx, akamaiProperty, PhaseActivating, "ActivationInProgress", fmt.Sprintf("Status: %s", activation.Status))
 				return ctrl.Result{RequeueAfter: time.Minute * 2, Requeue: true}, nil
 			}
 		} else {
+			// No activation is in flight for this network, so it must not hold a scheduler slot
+			r.ActivationScheduler.Release(schedulerKey)
+
 			// Check if we need to activate a newer version based on note change
 			var currentActiveVersion int
 			if activationSpec.Network == "STAGING" {
@@ -110,26 +131,37 @@ func (r *AkamaiPropertyReconciler) handleActivation(ctx context.Context, akamaiP
 				currentActiveVersion = akamaiProperty.Status.ProductionVersion
 			}
 
-			// Only activate if:
-			// 1. The activation note has changed (user explicitly wants new activation), OR
-			// 2. There's a newer version AND no active version yet (initial activation)
-			if activationNoteChanged {
-				logger.Info("Activation note changed, will activate latest version",
+			// With OnChange a version whose activation failed is not retried until a newer version
+			// exists, and an aborted one not while the abort annotation still names its activation
+			abortRequested := abortedActivationID(akamaiProperty) == currentActivationID
+			if (trigger == akamaiV1alpha1.ActivationTriggerOnChange && currentActivationStatus == "FAILED") ||
+				(akamai.ActivationAborted(currentActivationStatus) && abortRequested) {
+				activation, err := r.AkamaiClient.GetActivation(ctx, akamaiProperty.Status.PropertyID, currentActivationID)
+				if err != nil {
+					logger.Error(err, "Failed to get activation status")
+					return r.retryAfterError(akamaiProperty, err), nil
+				}
+				if activation.PropertyVersion >= versionToActivate {
+					logger.V(1).Info("Activation of latest version failed or was aborted, waiting for a new version",
+						"network", activationSpec.Network,
+						"version", activation.PropertyVersion)
+					return ctrl.Result{}, nil
+				}
+			}
+
+			if shouldActivate(trigger, activationNoteChanged, versionToActivate, currentActiveVersion) {
+				logger.Info("Activation triggered, will activate latest version",
 					"network", activationSpec.Network,
+					"trigger", trigger,
 					"latestVersion", versionToActivate,
 					"currentActiveVersion", currentActiveVersion,
 					"newNote", activationSpec.Note,
 					"oldNote", lastActivationNote)
 				needsActivation = true
-			} else if versionToActivate > currentActiveVersion && currentActiveVersion == 0 {
-				// Initial activation case - no active version yet
-				logger.Info("No active version yet, will activate latest version",
-					"network", activationSpec.Network,
-					"version", versionToActivate)
-				needsActivation = true
 			} else {
-				logger.V(1).Info("Activation not needed - note unchanged and version already active",
+				logger.V(1).Info("Activation not needed",
 					"network", activationSpec.Network,
+					"trigger", trigger,
 					"latestVersion", versionToActivate,
 					"activeVersion", currentActiveVersion)
 			}
@@ -141,7 +173,7 @@ func (r *AkamaiPropertyReconciler) handleActivation(ctx context.Context, akamaiP
 		pendingActivation, err := r.AkamaiClient.GetPendingActivationForVersion(ctx, akamaiProperty.Status.PropertyID, versionToActivate, activationSpec.Network)
 		if err != nil {
 			logger.Error(err, "Failed to check for pending activation")
-			return ctrl.Result{RequeueAfter: time.Minute * 2}, nil
+			return r.retryAfterError(akamaiProperty, err), nil
 		}
 
 		if pendingActivation != nil {
@@ -166,15 +198,63 @@ func (r *AkamaiPropertyReconciler) handleActivation(ctx context.Context, akamaiP
 			if err := r.updateStatusWithRetry(ctx, akamaiProperty); err != nil {
 				return ctrl.Result{}, err
 			}
+			r.ActivationScheduler.Track(schedulerKey)
 
 			r.updateStatus(ctx, akamaiProperty, PhaseActivating, "ActivationInProgress", fmt.Sprintf("Monitoring existing activation for version %d", versionToActivate))
 			return ctrl.Result{RequeueAfter: time.Minute * 2, Requeue: true}, nil
 		}
 
+		if activationSpec.Network == "PRODUCTION" {
+			blocked, err := r.checkGuardrails(ctx, akamaiProperty, versionToActivate)
+			if err != nil {
+				logger.Error(err, "Failed to check guardrails")
+				r.updateStatus(ctx, akamaiProperty, PhaseError, "FailedToCheckGuardrails", akamai.ErrorMessage(err))
+				return r.retryAfterError(akamaiProperty, err), nil
+			}
+			if blocked {
+				// Retrying cannot help until the findings are acknowledged. The acknowledging spec change
+				// is reconciled right away, so only poll slowly to keep checking for drift meanwhile
+				logger.Info("Activation blocked by guardrails", "version", versionToActivate)
+				r.updateStatus(ctx, akamaiProperty, PhaseError, "GuardrailsNotAcknowledged",
+					fmt.Sprintf("Guardrails block the production activation of version %d", versionToActivate))
+				return ctrl.Result{RequeueAfter: time.Minute * 5}, nil
+			}
+		}
+
+		// Wait for a slot so activations across all properties stay within the account limit
+		if granted, position := r.ActivationScheduler.Acquire(schedulerKey); !granted {
+			logger.Info("Activation queued, waiting for a free activation slot", "network", activationSpec.Network, "version", versionToActivate, "position", position)
+			r.updateStatus(ctx, akamaiProperty, PhaseActivating, "ActivationQueued",
+				fmt.Sprintf("Waiting for a free activation slot to activate version %d on %s (position %d)", versionToActivate, activationSpec.Network, position))
+			return ctrl.Result{RequeueAfter: r.versionPollInterval()}, nil
+		}
+
 		logger.Info("Starting property activation", "network", activationSpec.Network, "version", versionToActivate, "note", activationSpec.Note)
 		r.updateStatus(ctx, akamaiProperty, PhaseActivating, "StartingActivation", fmt.Sprintf("Activating version %d on %s", versionToActivate, activationSpec.Network))
 
-		activationID, err := r.AkamaiClient.ActivateProperty(ctx, akamaiProperty.Status.PropertyID, versionToActivate, activationSpec, akamaiProperty.Spec.ContractID, akamaiProperty.Spec.GroupID)
+		requestSpec, err := activationRequestSpec(akamaiProperty, activationSpec.Network, versionToActivate)
+		if err != nil {
+			r.ActivationScheduler.Release(schedulerKey)
+			logger.Error(err, "Failed to prepare activation")
+			r.updateStatus(ctx, akamaiProperty, PhaseError, "FailedToPrepareActivation", akamai.ErrorMessage(err))
+			return r.retryAfterError(akamaiProperty, err), nil
+		}
+
+		activationID, err := r.AkamaiClient.ActivateProperty(ctx, akamaiProperty.Status.PropertyID, versionToActivate, requestSpec, akamaiProperty.Spec.ContractID, akamaiProperty.Spec.GroupID)
+		if err != nil {
+			r.ActivationScheduler.Release(schedulerKey)
+		}
+		var warningsErr *akamai.WarningsNotAcknowledgedError
+		if errors.As(err, &warningsErr) {
+			// Retrying cannot help until the warnings are acknowledged. The acknowledging spec change
+			// is reconciled right away, so only poll slowly to keep checking for drift meanwhile
+			logger.Info("Activation blocked by unacknowledged warnings", "network", activationSpec.Network, "warnings", len(warningsErr.Warnings))
+			if err := r.recordPendingWarnings(ctx, akamaiProperty, warningsErr.Warnings); err != nil {
+				return ctrl.Result{}, err
+			}
+			r.updateStatus(ctx, akamaiProperty, PhaseError, "WarningsNotAcknowledged", warningsErr.Error())
+			return ctrl.Result{RequeueAfter: time.Minute * 5}, nil
+		}
 		if err != nil {
 			return ctrl.Result{}, fmt.Errorf("failed to activate property: %w", err)
 		}
@@ -189,18 +269,42 @@ func (r *AkamaiPropertyReconciler) handleActivation(ctx context.Context, akamaiP
 			akamaiProperty.Status.ProductionActivationStatus = "PENDING"
 			akamaiProperty.Status.ProductionActivationNote = activationSpec.Note
 		}
+		clearPendingWarnings(akamaiProperty)
 
 		if err := r.updateStatusWithRetry(ctx, akamaiProperty); err != nil {
 			return ctrl.Result{}, err
 		}
 
 		logger.Info("Successfully started activation", "activationID", activationID, "network", activationSpec.Network)
+		r.watchActivation(akamaiProperty, activationID)
 		return ctrl.Result{RequeueAfter: time.Minute * 2, Requeue: true}, nil
 	}
 
 	return ctrl.Result{}, nil
 }
 
+// activationTrigger returns the configured activation trigger, defaulting to NoteChange
+func activationTrigger(activationSpec *akamaiV1alpha1.ActivationSpec) akamaiV1alpha1.ActivationTrigger {
+	if activationSpec.Trigger == "" {
+		return akamaiV1alpha1.ActivationTriggerNoteChange
+	}
+	return activationSpec.Trigger
+}
+
+// shouldActivate decides whether the latest version has to be activated on a network whose
+// active version is activeVersion (0 if nothing is active yet)
+func shouldActivate(trigger akamaiV1alpha1.ActivationTrigger, noteChanged bool, latestVersion, activeVersion int) bool {
+	switch trigger {
+	case akamaiV1alpha1.ActivationTriggerManual:
+		return false
+	case akamaiV1alpha1.ActivationTriggerOnChange:
+		return latestVersion > activeVersion
+	default:
+		// The note is the explicit signal; the initial activation happens without it
+		return noteChanged || (activeVersion == 0 && latestVersion > activeVersion)
+	}
+}
+
 // updateActivationStatus updates the activation status in the AkamaiProperty resource
 func (r *AkamaiPropertyReconciler) updateActivationStatus(akamaiProperty *akamaiV1alpha1.AkamaiProperty, network string, activation *akamai.Activation) {
 	if network == "STAGING" {
@@ -0,0 +1,149 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+	"github.com/mmz-srf/akamai-operator/pkg/akamai"
+)
+
+const (
+	// ReasonActivationCancelled is the event reason of an activation the operator cancelled
+	ReasonActivationCancelled = "ActivationCancelled"
+
+	// ReasonActivationNotCancellable is the event reason of an abort request that came too late
+	ReasonActivationNotCancellable = "ActivationNotCancellable"
+
+	// abortSuperseded is the akamai.com/abort-activation value the property controller sets on an
+	// AkamaiPropertyActivation whose version was changed while it was pending
+	abortSuperseded = "superseded"
+)
+
+// handleAbortActivation cancels the activation named by the akamai.com/abort-activation annotation
+// while it is still PENDING. It reports whether an activation was cancelled.
+func (r *AkamaiPropertyReconciler) handleAbortActivation(ctx context.Context, akamaiProperty *akamaiV1alpha1.AkamaiProperty) (bool, error) {
+	activationID := abortedActivationID(akamaiProperty)
+	if activationID == "" {
+		return false, nil
+	}
+	network := activationNetwork(akamaiProperty, activationID)
+	if network == "" {
+		// Only activations in flight the property is tracking can be aborted
+		log.FromContext(ctx).V(1).Info("Abort requested for an activation not in flight", "activationID", activationID)
+		return false, nil
+	}
+	return r.cancelActivation(ctx, akamaiProperty, network, activationID, false)
+}
+
+// cancelSupersededActivation cancels the PENDING activation of the latest version when the spec
+// changed and spec.activation.cancelSuperseded is set. It reports whether the activation is being
+// cancelled; the changes are written and activated once it is aborted.
+func (r *AkamaiPropertyReconciler) cancelSupersededActivation(ctx context.Context, akamaiProperty *akamaiV1alpha1.AkamaiProperty) (bool, error) {
+	activationSpec := akamaiProperty.Spec.Activation
+	if activationSpec == nil || !activationSpec.CancelSuperseded || !managesActivation(akamaiProperty) {
+		return false, nil
+	}
+
+	if activationResourcesMode(akamaiProperty) {
+		// The activation controller cancels the activation once it is annotated
+		current, err := r.lastPropertyActivation(ctx, akamaiProperty, activationSpec.Network)
+		if err != nil || current == nil || akamai.ActivationFinished(current.Status.Status) ||
+			propertyActivationVersion(current) != akamaiProperty.Status.LatestVersion {
+			return false, err
+		}
+		if current.Annotations[AnnotationAbortActivation] != "" {
+			return true, nil
+		}
+		patch := client.MergeFrom(current.DeepCopy())
+		if current.Annotations == nil {
+			current.Annotations = map[string]string{}
+		}
+		current.Annotations[AnnotationAbortActivation] = abortSuperseded
+		if err := r.Patch(ctx, current, patch); err != nil {
+			return false, fmt.Errorf("failed to abort AkamaiPropertyActivation %s: %w", current.Name, err)
+		}
+		log.FromContext(ctx).Info("Aborting superseded activation", "activation", current.Name)
+		return true, nil
+	}
+
+	activationID := akamaiProperty.Status.StagingActivationID
+	if activationSpec.Network == "PRODUCTION" {
+		activationID = akamaiProperty.Status.ProductionActivationID
+	}
+	if activationID == "" {
+		return false, nil
+	}
+	return r.cancelActivation(ctx, akamaiProperty, activationSpec.Network, activationID, true)
+}
+
+// cancelActivation cancels an activation of the property on network if it is still PENDING and,
+// for superseded activations, of the latest version. A cancelled superseded activation is
+// forgotten, so the changed version is activated like a new request; other cancelled activations
+// are recorded as aborted and not retried while the annotation names them.
+func (r *AkamaiPropertyReconciler) cancelActivation(ctx context.Context, akamaiProperty *akamaiV1alpha1.AkamaiProperty, network, activationID string, superseded bool) (bool, error) {
+	logger := log.FromContext(ctx)
+
+	activation, err := r.AkamaiClient.GetActivation(ctx, akamaiProperty.Status.PropertyID, activationID)
+	if err != nil {
+		return false, err
+	}
+	if superseded && activation.PropertyVersion != akamaiProperty.Status.LatestVersion {
+		return false, nil
+	}
+	if !akamai.ActivationCancellable(activation.Status) {
+		if !superseded && !akamai.ActivationFinished(activation.Status) && !akamai.ActivationAborted(activation.Status) {
+			r.recordEvent(akamaiProperty, corev1.EventTypeWarning, ReasonActivationNotCancellable, "Cancel",
+				"Activation %s of version %d on %s can no longer be cancelled (status %s)", activationID, activation.PropertyVersion, network, activation.Status)
+		}
+		return false, nil
+	}
+
+	status, err := r.AkamaiClient.CancelActivation(ctx, akamaiProperty.Status.PropertyID, activationID,
+		akamaiProperty.Spec.ContractID, akamaiProperty.Spec.GroupID)
+	if err != nil {
+		return false, err
+	}
+	r.ActivationScheduler.Release(activationKey(r.AkamaiClient, akamaiProperty, network))
+	logger.Info("Cancelled activation", "activationID", activationID, "network", network, "version", activation.PropertyVersion, "superseded", superseded)
+	r.recordEvent(akamaiProperty, corev1.EventTypeNormal, ReasonActivationCancelled, "Cancel",
+		"Cancelled activation %s of version %d on %s", activationID, activation.PropertyVersion, network)
+
+	id, state, note := &akamaiProperty.Status.StagingActivationID, &akamaiProperty.Status.StagingActivationStatus, &akamaiProperty.Status.StagingActivationNote
+	if network == "PRODUCTION" {
+		id, state, note = &akamaiProperty.Status.ProductionActivationID, &akamaiProperty.Status.ProductionActivationStatus, &akamaiProperty.Status.ProductionActivationNote
+	}
+	switch {
+	case superseded:
+		*id, *state, *note = "", "", ""
+	case *id == activationID:
+		*state = status
+	}
+	return true, r.updateStatusWithRetry(ctx, akamaiProperty)
+}
+
+// abortedActivationID returns the activation ID of the akamai.com/abort-activation annotation
+func abortedActivationID(akamaiProperty *akamaiV1alpha1.AkamaiProperty) string {
+	return strings.TrimSpace(akamaiProperty.Annotations[AnnotationAbortActivation])
+}
+
+// activationNetwork returns the network of an activation the property tracks in its status as in
+// flight, or "" if it tracks no such activation with the ID
+func activationNetwork(akamaiProperty *akamaiV1alpha1.AkamaiProperty, activationID string) string {
+	status := akamaiProperty.Status
+	switch {
+	case activationID == status.StagingActivationID && !akamai.ActivationFinished(status.StagingActivationStatus):
+		return "STAGING"
+	case activationID == status.ProductionActivationID && !akamai.ActivationFinished(status.ProductionActivationStatus):
+		return "PRODUCTION"
+	case status.Promotion != nil && status.Promotion.State == akamaiV1alpha1.PromotionStatePending && activationID == status.Promotion.ActivationID:
+		return promotionNetwork
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,75 @@
+package controllers
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+)
+
+// activationNoteData is the data available to activation note templates
+type activationNoteData struct {
+	Note         string
+	Version      int
+	Network      string
+	PropertyName string
+}
+
+// activationNotification returns the notification settings overriding the activation spec on a network
+func activationNotification(activationSpec *akamaiV1alpha1.ActivationSpec, network string) *akamaiV1alpha1.ActivationNotification {
+	switch network {
+	case "STAGING":
+		return activationSpec.Staging
+	case "PRODUCTION":
+		return activationSpec.Production
+	default:
+		return nil
+	}
+}
+
+// activationRequestSpec returns the activation spec to submit for version on network, with the
+// notify emails and note of the network's notification settings applied
+func activationRequestSpec(akamaiProperty *akamaiV1alpha1.AkamaiProperty, network string, version int) (*akamaiV1alpha1.ActivationSpec, error) {
+	requestSpec := *akamaiProperty.Spec.Activation
+	requestSpec.Network = network
+
+	notification := activationNotification(&requestSpec, network)
+	if notification == nil {
+		return &requestSpec, nil
+	}
+	if len(notification.NotifyEmails) > 0 {
+		requestSpec.NotifyEmails = notification.NotifyEmails
+	}
+	if notification.NoteTemplate != "" {
+		tmpl, err := template.New("note").Option("missingkey=error").Parse(notification.NoteTemplate)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s note template: %w", strings.ToLower(network), err)
+		}
+		var note strings.Builder
+		err = tmpl.Execute(&note, activationNoteData{
+			Note:         requestSpec.Note,
+			Version:      version,
+			Network:      network,
+			PropertyName: akamaiProperty.Spec.PropertyName,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to render %s note template: %w", strings.ToLower(network), err)
+		}
+		requestSpec.Note = note.String()
+	}
+	return &requestSpec, nil
+}
+
+// validateActivationNotifications checks that the note templates of all networks render
+func validateActivationNotifications(akamaiProperty *akamaiV1alpha1.AkamaiProperty) error {
+	if akamaiProperty.Spec.Activation == nil {
+		return nil
+	}
+	for _, network := range []string{"STAGING", "PRODUCTION"} {
+		if _, err := activationRequestSpec(akamaiProperty, network, 1); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,188 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"strings"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+	"github.com/mmz-srf/akamai-operator/pkg/akamai"
+)
+
+// activationResourcesMode reports whether the property's activations are AkamaiPropertyActivation
+// resources instead of being started by the property controller itself
+func activationResourcesMode(akamaiProperty *akamaiV1alpha1.AkamaiProperty) bool {
+	return akamaiProperty.Spec.Activation != nil && akamaiProperty.Spec.Activation.Mode == akamaiV1alpha1.ActivationModeResources
+}
+
+// handleActivationResources creates an AkamaiPropertyActivation for the latest version when it
+// isn't active on the network yet, and mirrors the state of the property's last activation into
+// the property status. Unlike inline activations, the note doesn't trigger anything; it is only
+// passed on to the activation.
+func (r *AkamaiPropertyReconciler) handleActivationResources(ctx context.Context, akamaiProperty *akamaiV1alpha1.AkamaiProperty) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+	activationSpec := akamaiProperty.Spec.Activation
+	network := activationSpec.Network
+	latestVersion := akamaiProperty.Status.LatestVersion
+
+	activeVersion := akamaiProperty.Status.StagingVersion
+	if network == "PRODUCTION" {
+		activeVersion = akamaiProperty.Status.ProductionVersion
+	}
+
+	current, err := r.lastPropertyActivation(ctx, akamaiProperty, network)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if current != nil {
+		if err := r.mirrorActivation(ctx, akamaiProperty, current); err != nil {
+			return ctrl.Result{}, err
+		}
+		version := propertyActivationVersion(current)
+		switch {
+		case current.Status.Phase != PhaseError && !akamai.ActivationFinished(current.Status.Status):
+			r.updateStatus(ctx, akamaiProperty, PhaseActivating, "ActivationInProgress",
+				fmt.Sprintf("AkamaiPropertyActivation %s is activating version %d on %s", current.Name, version, network))
+			return ctrl.Result{RequeueAfter: time.Minute * 2, Requeue: true}, nil
+		case version != 0 && version < latestVersion:
+			// A newer version supersedes the outcome of the last activation
+		case akamai.ActivationAborted(current.Status.Status) && current.Annotations[AnnotationAbortActivation] == abortSuperseded &&
+			metav1.IsControlledBy(current, akamaiProperty):
+			// The changes that superseded the activation are activated under its name
+			if err := r.Delete(ctx, current); client.IgnoreNotFound(err) != nil {
+				return ctrl.Result{}, fmt.Errorf("failed to delete superseded AkamaiPropertyActivation %s: %w", current.Name, err)
+			}
+		case current.Status.Phase == PhaseError:
+			reason, message := "ActivationFailed", fmt.Sprintf("AkamaiPropertyActivation %s failed", current.Name)
+			if ready := meta.FindStatusCondition(current.Status.Conditions, ConditionTypeReady); ready != nil {
+				reason, message = ready.Reason, fmt.Sprintf("AkamaiPropertyActivation %s: %s", current.Name, ready.Message)
+			}
+			r.updateStatus(ctx, akamaiProperty, PhaseError, reason, message)
+			return ctrl.Result{RequeueAfter: time.Minute * 5, Requeue: true}, nil
+		default:
+			// The latest version is active; a failed version is not retried until a newer one exists
+			return ctrl.Result{}, nil
+		}
+	}
+
+	if activationTrigger(activationSpec) == akamaiV1alpha1.ActivationTriggerManual || latestVersion <= activeVersion {
+		return ctrl.Result{}, nil
+	}
+
+	if network == "PRODUCTION" {
+		blocked, err := r.checkGuardrails(ctx, akamaiProperty, latestVersion)
+		if err != nil {
+			logger.Error(err, "Failed to check guardrails")
+			r.updateStatus(ctx, akamaiProperty, PhaseError, "FailedToCheckGuardrails", akamai.ErrorMessage(err))
+			return r.retryAfterError(akamaiProperty, err), nil
+		}
+		if blocked {
+			logger.Info("Activation blocked by guardrails", "version", latestVersion)
+			r.updateStatus(ctx, akamaiProperty, PhaseError, "GuardrailsNotAcknowledged",
+				fmt.Sprintf("Guardrails block the production activation of version %d", latestVersion))
+			return ctrl.Result{RequeueAfter: time.Minute * 5, Requeue: true}, nil
+		}
+	}
+
+	// The activation carries the labels of the property to stay in its shard
+	labels := maps.Clone(akamaiProperty.Labels)
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	labels[ManagedByLabel] = ManagedByValue
+	activation := &akamaiV1alpha1.AkamaiPropertyActivation{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   propertyActivationName(akamaiProperty, network, latestVersion),
+			Labels: labels,
+		},
+		Spec: akamaiV1alpha1.AkamaiPropertyActivationSpec{
+			PropertyRef: akamaiProperty.Name,
+			Version:     latestVersion,
+			Network:     network,
+			Note:        activationSpec.Note,
+		},
+	}
+	if err := controllerutil.SetControllerReference(akamaiProperty, activation, r.Scheme); err != nil {
+		return ctrl.Result{}, err
+	}
+	if err := r.Create(ctx, activation); err != nil && !apierrors.IsAlreadyExists(err) {
+		return ctrl.Result{}, fmt.Errorf("failed to create AkamaiPropertyActivation %s: %w", activation.Name, err)
+	}
+	logger.Info("Created property activation", "activation", activation.Name, "network", network, "version", latestVersion)
+	r.updateStatus(ctx, akamaiProperty, PhaseActivating, "ActivationCreated",
+		fmt.Sprintf("Created AkamaiPropertyActivation %s to activate version %d on %s", activation.Name, latestVersion, network))
+	return ctrl.Result{RequeueAfter: time.Minute * 2, Requeue: true}, nil
+}
+
+// activationPropertyRefField indexes AkamaiPropertyActivations by the property they activate
+const activationPropertyRefField = "spec.propertyRef"
+
+// indexActivationPropertyRef returns the index values of activationPropertyRefField
+func indexActivationPropertyRef(obj client.Object) []string {
+	activation, ok := obj.(*akamaiV1alpha1.AkamaiPropertyActivation)
+	if !ok || activation.Spec.PropertyRef == "" {
+		return nil
+	}
+	return []string{activation.Spec.PropertyRef}
+}
+
+// lastPropertyActivation returns the property's activation on network that is still in flight,
+// or else the one of the highest version
+func (r *AkamaiPropertyReconciler) lastPropertyActivation(ctx context.Context, akamaiProperty *akamaiV1alpha1.AkamaiProperty, network string) (*akamaiV1alpha1.AkamaiPropertyActivation, error) {
+	var activations akamaiV1alpha1.AkamaiPropertyActivationList
+	if err := r.List(ctx, &activations, client.MatchingFields{activationPropertyRefField: akamaiProperty.Name}); err != nil {
+		return nil, fmt.Errorf("failed to list property activations: %w", err)
+	}
+	var last *akamaiV1alpha1.AkamaiPropertyActivation
+	for i := range activations.Items {
+		activation := &activations.Items[i]
+		if activation.Spec.Network != network {
+			continue
+		}
+		if activation.Status.Phase != PhaseError && !akamai.ActivationFinished(activation.Status.Status) {
+			return activation, nil
+		}
+		if last == nil || propertyActivationVersion(activation) > propertyActivationVersion(last) {
+			last = activation
+		}
+	}
+	return last, nil
+}
+
+// mirrorActivation records the activation in the network's activation fields of the property status
+func (r *AkamaiPropertyReconciler) mirrorActivation(ctx context.Context, akamaiProperty *akamaiV1alpha1.AkamaiProperty, activation *akamaiV1alpha1.AkamaiPropertyActivation) error {
+	status := &akamaiProperty.Status
+	id, state, note := &status.StagingActivationID, &status.StagingActivationStatus, &status.StagingActivationNote
+	if activation.Spec.Network == "PRODUCTION" {
+		id, state, note = &status.ProductionActivationID, &status.ProductionActivationStatus, &status.ProductionActivationNote
+	}
+	if activation.Status.ActivationID == "" || (*id == activation.Status.ActivationID && *state == activation.Status.Status && *note == activation.Spec.Note) {
+		return nil
+	}
+	*id, *state, *note = activation.Status.ActivationID, activation.Status.Status, activation.Spec.Note
+	return r.updateStatusWithRetry(ctx, akamaiProperty)
+}
+
+// propertyActivationVersion returns the version an activation activates, or 0 if it defaults to
+// the latest version and wasn't submitted yet
+func propertyActivationVersion(activation *akamaiV1alpha1.AkamaiPropertyActivation) int {
+	if activation.Status.Version != 0 {
+		return activation.Status.Version
+	}
+	return activation.Spec.Version
+}
+
+// propertyActivationName is the name of the activation the property controller creates for a version
+func propertyActivationName(akamaiProperty *akamaiV1alpha1.AkamaiProperty, network string, version int) string {
+	return fmt.Sprintf("%s-%s-v%d", akamaiProperty.Name, strings.ToLower(network), version)
+}
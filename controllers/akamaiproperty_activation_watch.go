@@ -0,0 +1,43 @@
+package controllers
+
+import (
+	"context"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+	"github.com/mmz-srf/akamai-operator/pkg/akamai"
+)
+
+// activationEventBuffer is the number of finished activations queued for the controller
+const activationEventBuffer = 64
+
+// watchActivation has the ActivationWatcher follow an activation or deactivation in flight and
+// reconciles the property as soon as it finishes, rather than on the next requeue. The periodic
+// requeue stays in place for activations submitted before a restart or by other tooling.
+func (r *AkamaiPropertyReconciler) watchActivation(akamaiProperty *akamaiV1alpha1.AkamaiProperty, activationID string) {
+	if r.ActivationWatcher == nil || r.activationEvents == nil || activationID == "" {
+		return
+	}
+	propertyID := akamaiProperty.Status.PropertyID
+	key := propertyID + "/" + activationID
+	if r.ActivationWatcher.Watching(key) {
+		return
+	}
+
+	// The client of the property's account is used, as the watch outlives the reconcile
+	akamaiClient := r.AkamaiClient
+	updates := r.ActivationWatcher.Subscribe(context.Background(), key, func(ctx context.Context) (*akamai.Activation, error) {
+		return akamaiClient.GetActivation(ctx, propertyID, activationID)
+	})
+	object := &akamaiV1alpha1.AkamaiProperty{ObjectMeta: metav1.ObjectMeta{Name: akamaiProperty.Name}}
+	events := r.activationEvents
+	go func() {
+		for update := range updates {
+			if update.Err == nil && akamai.ActivationFinished(update.Activation.Status) {
+				events <- event.GenericEvent{Object: object}
+			}
+		}
+	}()
+}
@@ -0,0 +1,50 @@
+package controllers
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+	"github.com/mmz-srf/akamai-operator/pkg/akamai"
+)
+
+// maxAPIErrorDetail bounds the detail of an API error kept in status
+const maxAPIErrorDetail = 1024
+
+// maxAPIErrorProblems bounds the individual problems of an API error kept in status
+const maxAPIErrorProblems = 10
+
+// recordAPIError keeps the references of a failed Akamai API request in status.lastApiError, so
+// support tickets can quote them without searching the operator logs. Errors that are not API
+// responses are ignored. The status is written by the caller.
+func recordAPIError(akamaiProperty *akamaiV1alpha1.AkamaiProperty, operation string, err error) {
+	apiErr, ok := akamai.AsAPIError(err)
+	if !ok {
+		return
+	}
+
+	detail := apiErr.Detail
+	if len(detail) > maxAPIErrorDetail {
+		detail = detail[:maxAPIErrorDetail-3] + "..."
+	}
+	var problems []string
+	for _, problem := range apiErr.Errors {
+		if len(problems) == maxAPIErrorProblems {
+			break
+		}
+		problems = append(problems, problem.String())
+	}
+	akamaiProperty.Status.LastAPIError = &akamaiV1alpha1.APIErrorStatus{
+		Operation:       operation,
+		Time:            metav1.NewTime(time.Now()),
+		StatusCode:      apiErr.StatusCode,
+		Type:            apiErr.Type,
+		Title:           apiErr.Title,
+		Detail:          detail,
+		Instance:        apiErr.Instance,
+		RequestInstance: apiErr.RequestInstance,
+		RequestID:       apiErr.RequestID,
+		Errors:          problems,
+	}
+}
@@ -0,0 +1,169 @@
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/runtime"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+)
+
+// redirectRuleName is the name of the child rule spec.redirect is rendered into
+const redirectRuleName = "Redirect to HTTPS (managed by akamai-operator)"
+
+// defaultRedirectResponseCode is the status code of the HTTPS redirect unless spec.redirect sets one
+const defaultRedirectResponseCode = 301
+
+// hasTypedBehaviors reports whether the spec sets any of the typed behaviors spec.origin,
+// spec.caching, spec.cpCode or spec.redirect
+func hasTypedBehaviors(akamaiProperty *akamaiV1alpha1.AkamaiProperty) bool {
+	spec := akamaiProperty.Spec
+	return spec.Origin != nil || spec.Caching != nil || spec.CPCode != nil ||
+		(spec.Redirect != nil && spec.Redirect.HTTPToHTTPS)
+}
+
+// validateTypedBehaviors checks that the typed behaviors are complete and don't conflict
+func validateTypedBehaviors(akamaiProperty *akamaiV1alpha1.AkamaiProperty) error {
+	spec := akamaiProperty.Spec
+	if spec.Origin != nil {
+		if spec.Origins != nil {
+			return fmt.Errorf("origin and origins are mutually exclusive")
+		}
+		if spec.Origin.Hostname == "" {
+			return fmt.Errorf("origin has no hostname")
+		}
+	}
+	if spec.Caching != nil {
+		if spec.Caching.Behavior == "MAX_AGE" && spec.Caching.TTL == "" {
+			return fmt.Errorf("caching: behavior MAX_AGE requires a ttl")
+		}
+		if spec.Caching.Behavior != "MAX_AGE" && spec.Caching.TTL != "" {
+			return fmt.Errorf("caching: ttl is only used with behavior MAX_AGE")
+		}
+	}
+	if spec.CPCode != nil && spec.CPCode.ID <= 0 {
+		return fmt.Errorf("cpCode: id must be positive")
+	}
+	return nil
+}
+
+// specRules returns the rule tree of spec.rules with the typed behaviors applied, the rules the
+// spec is validated and linted with. Without spec.rules, the typed behaviors make up the
+// top-level rule on their own.
+func specRules(akamaiProperty *akamaiV1alpha1.AkamaiProperty) (*akamaiV1alpha1.PropertyRules, error) {
+	rules := akamaiProperty.Spec.Rules
+	if !hasTypedBehaviors(akamaiProperty) {
+		return rules, nil
+	}
+	if rules == nil {
+		rules = &akamaiV1alpha1.PropertyRules{Name: "default"}
+	} else {
+		rules = rules.DeepCopy()
+	}
+	if err := applyTypedBehaviors(rules, akamaiProperty); err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+// applyTypedBehaviors renders the typed behaviors into the top-level rule. A behavior of the rule
+// tree with the same name is replaced in place, otherwise the behavior is appended; the HTTPS
+// redirect becomes the first child rule.
+func applyTypedBehaviors(rules *akamaiV1alpha1.PropertyRules, akamaiProperty *akamaiV1alpha1.AkamaiProperty) error {
+	spec := akamaiProperty.Spec
+	if spec.Origin != nil {
+		if err := setBehavior(rules, "origin", originBehavior(*spec.Origin)["options"]); err != nil {
+			return err
+		}
+	}
+	if spec.Caching != nil {
+		options := map[string]interface{}{
+			"behavior":       spec.Caching.Behavior,
+			"mustRevalidate": spec.Caching.MustRevalidate,
+		}
+		if spec.Caching.Behavior == "MAX_AGE" {
+			options["ttl"] = spec.Caching.TTL
+		}
+		if err := setBehavior(rules, "caching", options); err != nil {
+			return err
+		}
+	}
+	if spec.CPCode != nil {
+		options := map[string]interface{}{"value": map[string]interface{}{"id": spec.CPCode.ID}}
+		if err := setBehavior(rules, "cpCode", options); err != nil {
+			return err
+		}
+	}
+	if spec.Redirect != nil && spec.Redirect.HTTPToHTTPS {
+		if err := applyHTTPSRedirect(rules, spec.Redirect); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setBehavior replaces the behavior of the same name in the top-level rule or appends it
+func setBehavior(rules *akamaiV1alpha1.PropertyRules, name string, options interface{}) error {
+	raw, err := json.Marshal(options)
+	if err != nil {
+		return fmt.Errorf("failed to render %s behavior: %w", name, err)
+	}
+	rendered := akamaiV1alpha1.RuleBehavior{Name: name, Options: runtime.RawExtension{Raw: raw}}
+	for i := range rules.Behaviors {
+		if rules.Behaviors[i].Name == rendered.Name {
+			// Keep the uuid and locked flag of behaviors exported from Akamai
+			rendered.UUID, rendered.Locked = rules.Behaviors[i].UUID, rules.Behaviors[i].Locked
+			rules.Behaviors[i] = rendered
+			return nil
+		}
+	}
+	rules.Behaviors = append(rules.Behaviors, rendered)
+	return nil
+}
+
+// applyHTTPSRedirect inserts a child rule redirecting plain HTTP requests to HTTPS as the first
+// child of the top-level rule, replacing a rendering left over in a rule tree exported from Akamai
+func applyHTTPSRedirect(rules *akamaiV1alpha1.PropertyRules, redirect *akamaiV1alpha1.RedirectSpec) error {
+	responseCode := redirect.ResponseCode
+	if responseCode == 0 {
+		responseCode = defaultRedirectResponseCode
+	}
+	rule := map[string]interface{}{
+		"name":     redirectRuleName,
+		"comments": "Rendered from spec.redirect; changes made here are overwritten",
+		"criteria": []interface{}{map[string]interface{}{
+			"name":    "requestProtocol",
+			"options": map[string]interface{}{"value": "HTTP"},
+		}},
+		"criteriaMustSatisfy": "all",
+		"behaviors": []interface{}{map[string]interface{}{
+			"name": "redirect",
+			"options": map[string]interface{}{
+				"mobileDefaultChoice": "DEFAULT",
+				"destinationProtocol": "HTTPS",
+				"destinationHostname": "SAME_AS_REQUEST",
+				"destinationPath":     "SAME_AS_REQUEST",
+				"queryString":         "APPEND",
+				"responseCode":        responseCode,
+			},
+		}},
+		"children": []interface{}{},
+	}
+	raw, err := json.Marshal(rule)
+	if err != nil {
+		return fmt.Errorf("failed to render HTTPS redirect: %w", err)
+	}
+	children := []runtime.RawExtension{{Raw: raw}}
+	for _, child := range rules.Children {
+		var named struct {
+			Name string `json:"name"`
+		}
+		if json.Unmarshal(child.Raw, &named) == nil && named.Name == redirectRuleName {
+			continue
+		}
+		children = append(children, child)
+	}
+	rules.Children = children
+	return nil
+}
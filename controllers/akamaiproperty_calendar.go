@@ -0,0 +1,76 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/meta"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+)
+
+// pendingActivationReasons are the reasons of the Ready condition while an activation of
+// spec.activation is planned but not completed
+var pendingActivationReasons = map[string]bool{
+	"ActivationQueued":          true,
+	"StartingActivation":        true,
+	"ActivationInProgress":      true,
+	"WarningsNotAcknowledged":   true,
+	"GuardrailsNotAcknowledged": true,
+}
+
+// plannedActivations derives the planned activations of the property from its status: the
+// activation of spec.activation while it is queued, blocked by a gate or in progress, and the
+// deactivation of the active versions when a temporary property expires
+func (r *AkamaiPropertyReconciler) plannedActivations(akamaiProperty *akamaiV1alpha1.AkamaiProperty) []akamaiV1alpha1.PlannedActivation {
+	var planned []akamaiV1alpha1.PlannedActivation
+	ready := meta.FindStatusCondition(akamaiProperty.Status.Conditions, ConditionTypeReady)
+	if akamaiProperty.Spec.Activation != nil && ready != nil && pendingActivationReasons[ready.Reason] {
+		since := ready.LastTransitionTime
+		planned = append(planned, akamaiV1alpha1.PlannedActivation{
+			Network: akamaiProperty.Spec.Activation.Network,
+			Action:  PlannedActionActivate,
+			Version: akamaiProperty.Status.LatestVersion,
+			Reason:  ready.Reason,
+			Message: ready.Message,
+			Since:   &since,
+		})
+	}
+
+	// Expired properties are only deactivated when they are deleted in Akamai
+	deadline := expiryDeadline(akamaiProperty)
+	if deadline == nil || r.readOnly(akamaiProperty) || deletionPolicy(akamaiProperty) == akamaiV1alpha1.DeletionPolicyRetain {
+		return planned
+	}
+	for _, active := range []struct {
+		network string
+		version int
+	}{
+		{network: "STAGING", version: akamaiProperty.Status.StagingVersion},
+		{network: "PRODUCTION", version: akamaiProperty.Status.ProductionVersion},
+	} {
+		if active.version == 0 {
+			continue
+		}
+		planned = append(planned, akamaiV1alpha1.PlannedActivation{
+			Network:     active.network,
+			Action:      PlannedActionDeactivate,
+			Version:     active.version,
+			Reason:      ReasonPropertyExpires,
+			Message:     fmt.Sprintf("The property expires and is deactivated with deletion policy %s", deletionPolicy(akamaiProperty)),
+			ScheduledAt: deadline,
+		})
+	}
+	return planned
+}
+
+// recordPlannedActivations updates status.plannedActivations if the planned activations changed
+func (r *AkamaiPropertyReconciler) recordPlannedActivations(ctx context.Context, akamaiProperty *akamaiV1alpha1.AkamaiProperty) error {
+	planned := r.plannedActivations(akamaiProperty)
+	if equality.Semantic.DeepEqual(planned, akamaiProperty.Status.PlannedActivations) {
+		return nil
+	}
+	akamaiProperty.Status.PlannedActivations = planned
+	return r.updateStatusWithRetry(ctx, akamaiProperty)
+}
@@ -0,0 +1,87 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+	"github.com/mmz-srf/akamai-operator/pkg/akamai"
+)
+
+const (
+	// certProvisioningDefault is the certProvisioningType of hostnames with a Default DV
+	// certificate managed by Akamai (Secure by Default)
+	certProvisioningDefault = "DEFAULT"
+
+	// certificateValidationPollInterval is how often a ready property is reconciled while a Default
+	// DV certificate waits for its domain validation, so DNS automation sees new records quickly
+	certificateValidationPollInterval = 5 * time.Minute
+)
+
+// hostnameCertificates returns the certificate state of the desired hostnames with a Default DV
+// certificate, with the validation records of the certificates not deployed on both networks
+func hostnameCertificates(desired []akamaiV1alpha1.Hostname, current []akamai.Hostname) []akamaiV1alpha1.HostnameCertificate {
+	currentMap := make(map[string]akamai.Hostname, len(current))
+	for _, h := range current {
+		currentMap[h.CNAMEFrom] = h
+	}
+
+	var certificates []akamaiV1alpha1.HostnameCertificate
+	for _, h := range desired {
+		ch, ok := currentMap[h.CNAMEFrom]
+		if h.CertProvisioningType != certProvisioningDefault || !ok {
+			continue
+		}
+		certificate := akamaiV1alpha1.HostnameCertificate{
+			Hostname:         h.CNAMEFrom,
+			StagingStatus:    ch.StagingCertStatus,
+			ProductionStatus: ch.ProductionCertStatus,
+		}
+		deployed := ch.StagingCertStatus == akamai.CertStatusDeployed && ch.ProductionCertStatus == akamai.CertStatusDeployed
+		if !deployed && ch.ValidationCNAME != "" {
+			certificate.ValidationRecords = []akamaiV1alpha1.DNSRecord{{Type: "CNAME", Name: ch.ValidationCNAME, Value: ch.ValidationTarget}}
+		}
+		certificates = append(certificates, certificate)
+	}
+	return certificates
+}
+
+// certificatesAwaitingValidation reports whether a Default DV certificate waits for the DNS
+// records of its domain validation
+func certificatesAwaitingValidation(akamaiProperty *akamaiV1alpha1.AkamaiProperty) bool {
+	for _, certificate := range akamaiProperty.Status.HostnameCertificates {
+		if len(certificate.ValidationRecords) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// updateHostnameCertificates refreshes status.hostnameCertificates from the hostnames of the
+// latest version, where Akamai starts provisioning the certificate of a new hostname
+func (r *AkamaiPropertyReconciler) updateHostnameCertificates(ctx context.Context, akamaiProperty *akamaiV1alpha1.AkamaiProperty) error {
+	var certificates []akamaiV1alpha1.HostnameCertificate
+	usesDefault := false
+	for _, h := range akamaiProperty.Spec.Hostnames {
+		usesDefault = usesDefault || h.CertProvisioningType == certProvisioningDefault
+	}
+	if usesDefault && akamaiProperty.Status.LatestVersion > 0 {
+		hostnames, err := r.AkamaiClient.GetPropertyHostnames(ctx,
+			akamaiProperty.Status.PropertyID,
+			akamaiProperty.Spec.ContractID,
+			akamaiProperty.Spec.GroupID,
+			akamaiProperty.Status.LatestVersion)
+		if err != nil {
+			return fmt.Errorf("failed to get hostnames of the latest version: %w", err)
+		}
+		certificates = hostnameCertificates(akamaiProperty.Spec.Hostnames, hostnames)
+	}
+
+	if reflect.DeepEqual(certificates, akamaiProperty.Status.HostnameCertificates) {
+		return nil
+	}
+	akamaiProperty.Status.HostnameCertificates = certificates
+	return r.updateStatusWithRetry(ctx, akamaiProperty)
+}
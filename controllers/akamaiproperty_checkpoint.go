@@ -0,0 +1,119 @@
+package controllers
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+	"github.com/mmz-srf/akamai-operator/pkg/akamai"
+)
+
+// Steps of the write sequence recorded in status.checkpoint
+const (
+	CheckpointCreateVersion  = "CreateVersion"
+	CheckpointUpdateProperty = "UpdateProperty"
+	CheckpointUpdateRules    = "UpdateRules"
+)
+
+// setCheckpoint persists the step of the write sequence about to run, so a sequence interrupted
+// by a shutdown, crash or conflict is resumed by the next reconcile. The steps completed on the
+// same version for the same generation are kept.
+func (r *AkamaiPropertyReconciler) setCheckpoint(ctx context.Context, akamaiProperty *akamaiV1alpha1.AkamaiProperty, step string, version int) error {
+	checkpoint := &akamaiV1alpha1.WriteCheckpoint{
+		Step:       step,
+		Version:    version,
+		Generation: akamaiProperty.Generation,
+		StartedAt:  metav1.NewTime(time.Now()),
+	}
+	if previous := akamaiProperty.Status.Checkpoint; previous != nil &&
+		previous.Version == version && previous.Generation == akamaiProperty.Generation {
+		checkpoint.Completed = previous.Completed
+	}
+	akamaiProperty.Status.Checkpoint = checkpoint
+	return r.updateStatusWithRetry(ctx, akamaiProperty)
+}
+
+// completeStep marks a step of the checkpoint as done on version. It only changes the status in
+// memory, so it is persisted atomically with the status update recording the result of the step.
+func completeStep(akamaiProperty *akamaiV1alpha1.AkamaiProperty, step string, version int) {
+	checkpoint := akamaiProperty.Status.Checkpoint
+	if checkpoint == nil || checkpoint.Generation != akamaiProperty.Generation || checkpoint.Version != version {
+		checkpoint = &akamaiV1alpha1.WriteCheckpoint{
+			Version:    version,
+			Generation: akamaiProperty.Generation,
+			StartedAt:  metav1.NewTime(time.Now()),
+		}
+	}
+	if checkpoint.Step == step {
+		checkpoint.Step = ""
+	}
+	if !slices.Contains(checkpoint.Completed, step) {
+		checkpoint.Completed = append(slices.Clone(checkpoint.Completed), step)
+	}
+	akamaiProperty.Status.Checkpoint = checkpoint
+}
+
+// clearCheckpoint removes the checkpoint once the write sequence completed
+func (r *AkamaiPropertyReconciler) clearCheckpoint(ctx context.Context, akamaiProperty *akamaiV1alpha1.AkamaiProperty) error {
+	if akamaiProperty.Status.Checkpoint == nil {
+		return nil
+	}
+	akamaiProperty.Status.Checkpoint = nil
+	return r.updateStatusWithRetry(ctx, akamaiProperty)
+}
+
+// resumesStep reports whether the write sequence was interrupted at step, logging the resume
+func resumesStep(ctx context.Context, akamaiProperty *akamaiV1alpha1.AkamaiProperty, step string) bool {
+	checkpoint := akamaiProperty.Status.Checkpoint
+	if checkpoint == nil || checkpoint.Step != step {
+		return false
+	}
+	log.FromContext(ctx).Info("Resuming interrupted Akamai write", "step", checkpoint.Step,
+		"version", checkpoint.Version, "completed", checkpoint.Completed, "startedAt", checkpoint.StartedAt.Time)
+	return true
+}
+
+// checkpointClockSkew is how much earlier than the checkpoint Akamai may date a version created
+// by the interrupted request, to allow for clock differences between the operator and Akamai
+const checkpointClockSkew = time.Minute
+
+// resumedVersion returns the version created for the current generation by an interrupted
+// sequence, which must be written to instead of creating another version. That is the version
+// recorded as created, or a latest version directly following the one a creation was interrupted
+// from that was created by the operator: latest is its state, and it must carry the managed-by
+// marker or have been updated after the creation started. Any other version, e.g. one created
+// in Property Manager in the meantime, gets a new one created.
+func resumedVersion(akamaiProperty *akamaiV1alpha1.AkamaiProperty, latest *akamai.PropertyVersion) (int, bool) {
+	checkpoint := akamaiProperty.Status.Checkpoint
+	latestVersion := akamaiProperty.Status.LatestVersion
+	if checkpoint == nil || checkpoint.Generation != akamaiProperty.Generation {
+		return 0, false
+	}
+	if slices.Contains(checkpoint.Completed, CheckpointCreateVersion) && checkpoint.Version == latestVersion {
+		return latestVersion, true
+	}
+	if checkpoint.Step == CheckpointCreateVersion && latestVersion == checkpoint.Version+1 && createdByCheckpoint(checkpoint, latest) {
+		return latestVersion, true
+	}
+	return 0, false
+}
+
+// createdByCheckpoint reports whether the version following the one checkpoint creates from looks
+// like the one the interrupted creation produced. Neither sign is proof on its own: new versions
+// inherit the notes of the version they are created from, and a version created by someone else
+// right after the checkpoint matches its time, so resumedVersion only asks this of the one
+// version the operator's request would have created.
+func createdByCheckpoint(checkpoint *akamaiV1alpha1.WriteCheckpoint, version *akamai.PropertyVersion) bool {
+	if version == nil {
+		return false
+	}
+	if hasManagedByMarker(version.Note) {
+		return true
+	}
+	updated, err := time.Parse(time.RFC3339, version.UpdatedDate)
+	return err == nil && !updated.Before(checkpoint.StartedAt.Add(-checkpointClockSkew))
+}
@@ -0,0 +1,67 @@
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+)
+
+// checksumPrefix names the hash algorithm of the applied checksum annotation
+const checksumPrefix = "sha256:"
+
+// specChecksum returns the checksum of a property spec. The spec is hashed as compact JSON with
+// sorted keys and a trailing newline, the output of `jq -cS .spec`, so tools without Akamai
+// access can compute the same checksum from a rendered manifest.
+func specChecksum(spec *akamaiV1alpha1.AkamaiPropertySpec) (string, error) {
+	raw, err := json.Marshal(spec)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal spec: %w", err)
+	}
+	// Round-trip through a map to sort the keys of every object
+	var canonical interface{}
+	if err := json.Unmarshal(raw, &canonical); err != nil {
+		return "", fmt.Errorf("failed to unmarshal spec: %w", err)
+	}
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(canonical); err != nil {
+		return "", fmt.Errorf("failed to encode spec: %w", err)
+	}
+
+	sum := sha256.Sum256(buf.Bytes())
+	return checksumPrefix + hex.EncodeToString(sum[:]), nil
+}
+
+// recordAppliedChecksum writes the checksum of the spec applied to Akamai into the
+// akamai.com/applied-checksum annotation. Only the annotation is patched, so account defaults
+// applied to the spec in memory are never persisted.
+func (r *AkamaiPropertyReconciler) recordAppliedChecksum(ctx context.Context, akamaiProperty *akamaiV1alpha1.AkamaiProperty) error {
+	checksum, err := specChecksum(&akamaiProperty.Spec)
+	if err != nil {
+		return err
+	}
+	if akamaiProperty.Annotations[AnnotationAppliedChecksum] == checksum {
+		return nil
+	}
+
+	patch := client.MergeFrom(akamaiProperty.DeepCopy())
+	if akamaiProperty.Annotations == nil {
+		akamaiProperty.Annotations = make(map[string]string)
+	}
+	akamaiProperty.Annotations[AnnotationAppliedChecksum] = checksum
+	if err := r.Patch(ctx, akamaiProperty, patch); err != nil {
+		return fmt.Errorf("failed to record applied checksum: %w", err)
+	}
+	log.FromContext(ctx).V(1).Info("Recorded applied checksum", "checksum", checksum)
+	return nil
+}
@@ -0,0 +1,56 @@
+package controllers
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+)
+
+// icpLicensePattern matches ICP filing numbers (e.g. 京ICP备12345678号-1) and ICP license
+// numbers (e.g. 京ICP证123456号)
+var icpLicensePattern = regexp.MustCompile(`^\p{Han}ICP[备证]\d+号(-\d+)?$`)
+
+// validateChinaCDN checks the ICP licenses of the hostnames and the use cases of the edge
+// hostname, and that every hostname CNAMEd to a China CDN edge hostname has an ICP license
+func (r *AkamaiPropertyReconciler) validateChinaCDN(akamaiProperty *akamaiV1alpha1.AkamaiProperty) error {
+	for _, hostname := range akamaiProperty.Spec.Hostnames {
+		if hostname.ICPLicense != "" && !icpLicensePattern.MatchString(hostname.ICPLicense) {
+			return fmt.Errorf("hostname %s: invalid icpLicense %q, expected a number like 京ICP备12345678号-1", hostname.CNAMEFrom, hostname.ICPLicense)
+		}
+	}
+
+	spec := akamaiProperty.Spec.EdgeHostname
+	if spec == nil {
+		return nil
+	}
+	seen := make(map[string]bool, len(spec.UseCases))
+	for _, useCase := range spec.UseCases {
+		if seen[useCase.UseCase] {
+			return fmt.Errorf("edgeHostname: duplicate use case %q", useCase.UseCase)
+		}
+		seen[useCase.UseCase] = true
+	}
+	if !spec.ChinaCDN {
+		return nil
+	}
+
+	// Without a rendered prefix, e.g. a label of the edge hostname template is still missing,
+	// every hostname in the edge hostname's domain is checked
+	matches := func(cnameTo string) bool { return strings.HasSuffix(cnameTo, "."+spec.DomainSuffix) }
+	if rendered, err := r.edgeHostnameSpec(akamaiProperty); err == nil && rendered != nil {
+		edgeHostname := rendered.DomainPrefix + "." + rendered.DomainSuffix
+		matches = func(cnameTo string) bool { return cnameTo == edgeHostname }
+	}
+	var unlicensed []string
+	for _, hostname := range akamaiProperty.Spec.Hostnames {
+		if matches(hostname.CNAMETo) && hostname.ICPLicense == "" {
+			unlicensed = append(unlicensed, hostname.CNAMEFrom)
+		}
+	}
+	if len(unlicensed) > 0 {
+		return fmt.Errorf("hostnames delivered through China CDN need an icpLicense: %s", strings.Join(unlicensed, ", "))
+	}
+	return nil
+}
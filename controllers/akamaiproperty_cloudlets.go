@@ -0,0 +1,113 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+)
+
+// cloudletPolicyRefPattern matches a reference like ${cloudletPolicy:redirects} to the shared
+// policy of an AkamaiCloudletPolicy, used as the cloudletSharedPolicy option of a Cloudlet behavior
+var cloudletPolicyRefPattern = regexp.MustCompile(`\$\{cloudletPolicy:([a-z0-9]([-.a-z0-9]*[a-z0-9])?)\}`)
+
+// resolveCloudletPolicyRefs replaces the Cloudlets policy references in the behavior and criterion
+// options of the rule tree with the policy IDs. An option that is only a reference becomes the
+// number PAPI expects. Rules without references are returned as they are.
+func (r *AkamaiPropertyReconciler) resolveCloudletPolicyRefs(ctx context.Context, rules *akamaiV1alpha1.PropertyRules) (*akamaiV1alpha1.PropertyRules, error) {
+	if rules == nil {
+		return nil, nil
+	}
+	tree, err := ruleTree(rules)
+	if err != nil {
+		return nil, err
+	}
+	found, err := walkOptionValues(tree, func(value string) (interface{}, error) {
+		if match := cloudletPolicyRefPattern.FindStringSubmatch(value); match != nil && match[0] == value {
+			return r.cloudletPolicyID(ctx, match[1])
+		}
+		var resolveErr error
+		resolved := cloudletPolicyRefPattern.ReplaceAllStringFunc(value, func(ref string) string {
+			policyID, err := r.cloudletPolicyID(ctx, cloudletPolicyRefPattern.FindStringSubmatch(ref)[1])
+			if err != nil && resolveErr == nil {
+				resolveErr = err
+			}
+			return strconv.FormatInt(policyID, 10)
+		})
+		return resolved, resolveErr
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve cloudlet policy references: %w", err)
+	}
+	if !found {
+		return rules, nil
+	}
+
+	raw, err := json.Marshal(tree)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal resolved rules: %w", err)
+	}
+	var resolved akamaiV1alpha1.PropertyRules
+	if err := json.Unmarshal(raw, &resolved); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal resolved rules: %w", err)
+	}
+	return &resolved, nil
+}
+
+// cloudletPolicyID returns the ID of the shared policy of an AkamaiCloudletPolicy
+func (r *AkamaiPropertyReconciler) cloudletPolicyID(ctx context.Context, name string) (int64, error) {
+	var policy akamaiV1alpha1.AkamaiCloudletPolicy
+	if err := r.Get(ctx, types.NamespacedName{Name: name}, &policy); err != nil {
+		if apierrors.IsNotFound(err) {
+			return 0, fmt.Errorf("AkamaiCloudletPolicy %q not found", name)
+		}
+		return 0, fmt.Errorf("failed to get AkamaiCloudletPolicy %q: %w", name, err)
+	}
+	if policy.Status.PolicyID == 0 {
+		return 0, fmt.Errorf("AkamaiCloudletPolicy %q has not been created in Akamai yet", name)
+	}
+	return policy.Status.PolicyID, nil
+}
+
+// cloudletPolicyRefs returns the names of the AkamaiCloudletPolicies spec.rules references
+func cloudletPolicyRefs(akamaiProperty *akamaiV1alpha1.AkamaiProperty) map[string]bool {
+	refs := map[string]bool{}
+	if akamaiProperty.Spec.Rules == nil {
+		return refs
+	}
+	tree, err := ruleTree(akamaiProperty.Spec.Rules)
+	if err != nil {
+		return refs
+	}
+	_, _ = walkOptionStrings(tree, func(value string) (string, error) {
+		for _, match := range cloudletPolicyRefPattern.FindAllStringSubmatch(value, -1) {
+			refs[match[1]] = true
+		}
+		return value, nil
+	})
+	return refs
+}
+
+// propertiesReferencingCloudletPolicy enqueues the properties referencing a changed
+// AkamaiCloudletPolicy, so they are written once the policy is created in Akamai
+func (r *AkamaiPropertyReconciler) propertiesReferencingCloudletPolicy(ctx context.Context, obj client.Object) []reconcile.Request {
+	var properties akamaiV1alpha1.AkamaiPropertyList
+	if err := r.List(ctx, &properties); err != nil {
+		return nil
+	}
+	var requests []reconcile.Request
+	for i := range properties.Items {
+		if cloudletPolicyRefs(&properties.Items[i])[obj.GetName()] {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&properties.Items[i])})
+		}
+	}
+	return requests
+}
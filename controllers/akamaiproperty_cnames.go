@@ -0,0 +1,299 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+	"github.com/mmz-srf/akamai-operator/pkg/akamai"
+)
+
+// dnsEndpointGVK is the kind of the external-dns CRD source the ExternalDNS provider publishes to
+var dnsEndpointGVK = schema.GroupVersionKind{Group: "externaldns.k8s.io", Version: "v1alpha1", Kind: "DNSEndpoint"}
+
+// servedCNAMEs returns the spec hostnames the production version serves with their edge hostname
+func servedCNAMEs(desired []akamaiV1alpha1.Hostname, production []akamai.Hostname) []akamai.Hostname {
+	productionMap := make(map[string]akamai.Hostname, len(production))
+	for _, h := range production {
+		productionMap[h.CNAMEFrom] = h
+	}
+
+	var cnames []akamai.Hostname
+	for _, h := range desired {
+		if ph, ok := productionMap[h.CNAMEFrom]; ok && ph.CNAMETo == h.CNAMETo {
+			cnames = append(cnames, akamai.Hostname{CNAMEFrom: h.CNAMEFrom, CNAMETo: h.CNAMETo})
+		}
+	}
+	return cnames
+}
+
+// cnameZone returns the zone of the AkamaiDnsZone that is the longest suffix of the hostname
+func cnameZone(zones []akamaiV1alpha1.AkamaiDnsZone, hostname string) string {
+	names := make([]string, 0, len(zones))
+	for i := range zones {
+		names = append(names, dnsZoneName(&zones[i]))
+	}
+	return longestZoneSuffix(names, hostname)
+}
+
+// longestZoneSuffix returns the zone that is the longest suffix of the hostname, or "" when none is
+func longestZoneSuffix(zones []string, hostname string) string {
+	match := ""
+	for _, zone := range zones {
+		if (hostname == zone || strings.HasSuffix(hostname, "."+zone)) && len(zone) > len(match) {
+			match = zone
+		}
+	}
+	return match
+}
+
+// cnameRecordName returns the name of the AkamaiDnsRecord of a hostname; wildcards aren't valid
+// in resource names
+func cnameRecordName(hostname string) string {
+	return strings.ReplaceAll(hostname, "*", "wildcard")
+}
+
+// cnameTTL returns the TTL of the CNAMEs of the property
+func cnameTTL(akamaiProperty *akamaiV1alpha1.AkamaiProperty) int {
+	if akamaiProperty.Spec.DNS != nil && akamaiProperty.Spec.DNS.TTL > 0 {
+		return akamaiProperty.Spec.DNS.TTL
+	}
+	return defaultDNSRecordTTL
+}
+
+// syncCNAMEs points the hostnames served on production at their edge hostnames with the DNS
+// provider of spec.dns, and removes the CNAMEs of hostnames that are no longer served
+func (r *AkamaiPropertyReconciler) syncCNAMEs(ctx context.Context, akamaiProperty *akamaiV1alpha1.AkamaiProperty) error {
+	if r.readOnly(akamaiProperty) {
+		return nil
+	}
+	dnsSpec := akamaiProperty.Spec.DNS
+
+	var cnames []akamai.Hostname
+	if dnsSpec != nil && dnsSpec.AutoCreateCNAMEs && akamaiProperty.Status.ProductionVersion > 0 {
+		production, err := r.AkamaiClient.GetPropertyHostnames(ctx,
+			akamaiProperty.Status.PropertyID,
+			akamaiProperty.Spec.ContractID,
+			akamaiProperty.Spec.GroupID,
+			akamaiProperty.Status.ProductionVersion)
+		if err != nil {
+			return fmt.Errorf("failed to get production hostnames: %w", err)
+		}
+		cnames = servedCNAMEs(akamaiProperty.Spec.Hostnames, production)
+	}
+
+	// Switching the provider removes the CNAMEs of the other one
+	var edgeDNS, externalDNS []akamai.Hostname
+	if dnsSpec != nil && dnsSpec.Provider == akamaiV1alpha1.DNSProviderExternalDNS {
+		externalDNS = cnames
+	} else {
+		edgeDNS = cnames
+	}
+	if err := r.syncCNAMERecords(ctx, akamaiProperty, edgeDNS); err != nil {
+		return err
+	}
+	return r.syncDNSEndpoint(ctx, akamaiProperty, externalDNS)
+}
+
+// removeCNAMEs removes the CNAMEs of all hostnames of a property being deleted
+func (r *AkamaiPropertyReconciler) removeCNAMEs(ctx context.Context, akamaiProperty *akamaiV1alpha1.AkamaiProperty) error {
+	if err := r.syncCNAMERecords(ctx, akamaiProperty, nil); err != nil {
+		return err
+	}
+	return r.syncDNSEndpoint(ctx, akamaiProperty, nil)
+}
+
+// syncCNAMERecords maintains an AkamaiDnsRecord per CNAME and deletes those of other hostnames;
+// the records themselves are created in Edge DNS by their own controller. Hostnames without a
+// zone or whose record belongs to someone else are reported after the others are handled.
+func (r *AkamaiPropertyReconciler) syncCNAMERecords(ctx context.Context, akamaiProperty *akamaiV1alpha1.AkamaiProperty, cnames []akamai.Hostname) error {
+	logger := log.FromContext(ctx)
+
+	var existing akamaiV1alpha1.AkamaiDnsRecordList
+	if err := r.List(ctx, &existing, client.MatchingLabels{LabelCNAMEProperty: akamaiProperty.Name}); err != nil {
+		// Without the AkamaiDnsRecord CRD there is nothing to clean up
+		if meta.IsNoMatchError(err) && len(cnames) == 0 {
+			return nil
+		}
+		return fmt.Errorf("failed to list AkamaiDnsRecords: %w", err)
+	}
+
+	zone := ""
+	var zones akamaiV1alpha1.AkamaiDnsZoneList
+	if len(cnames) > 0 {
+		zone = akamaiProperty.Spec.DNS.Zone
+		if zone == "" {
+			if err := r.List(ctx, &zones); err != nil {
+				return fmt.Errorf("failed to list AkamaiDnsZones: %w", err)
+			}
+		}
+	}
+
+	wanted := make(map[string]bool, len(cnames))
+	var problems []string
+	for _, cname := range cnames {
+		recordZone := zone
+		if recordZone == "" {
+			recordZone = cnameZone(zones.Items, cname.CNAMEFrom)
+		}
+		if recordZone == "" {
+			problems = append(problems, fmt.Sprintf("no AkamaiDnsZone found for %s", cname.CNAMEFrom))
+			continue
+		}
+		name := cnameRecordName(cname.CNAMEFrom)
+		wanted[name] = true
+		spec := akamaiV1alpha1.AkamaiDnsRecordSpec{
+			Zone:    recordZone,
+			Name:    cname.CNAMEFrom,
+			Type:    "CNAME",
+			TTL:     cnameTTL(akamaiProperty),
+			Targets: []string{cname.CNAMETo},
+		}
+		if err := r.ensureCNAMERecord(ctx, akamaiProperty, name, spec); err != nil {
+			problems = append(problems, err.Error())
+		}
+	}
+
+	for i := range existing.Items {
+		record := &existing.Items[i]
+		if wanted[record.Name] {
+			continue
+		}
+		if err := r.Delete(ctx, record); err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete AkamaiDnsRecord %s: %w", record.Name, err)
+		}
+		logger.Info("Deleted CNAME of a hostname no longer served", "record", record.Name)
+	}
+
+	if len(problems) > 0 {
+		return errors.New(strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// ensureCNAMERecord creates or updates the AkamaiDnsRecord of a CNAME. The record carries the
+// labels of the property, so it falls into the same shard.
+func (r *AkamaiPropertyReconciler) ensureCNAMERecord(ctx context.Context, akamaiProperty *akamaiV1alpha1.AkamaiProperty, name string, spec akamaiV1alpha1.AkamaiDnsRecordSpec) error {
+	var record akamaiV1alpha1.AkamaiDnsRecord
+	err := r.Get(ctx, client.ObjectKey{Name: name}, &record)
+	if apierrors.IsNotFound(err) {
+		labels := make(map[string]string, len(akamaiProperty.Labels)+2)
+		for key, value := range akamaiProperty.Labels {
+			labels[key] = value
+		}
+		labels[ManagedByLabel] = ManagedByValue
+		labels[LabelCNAMEProperty] = akamaiProperty.Name
+		record = akamaiV1alpha1.AkamaiDnsRecord{}
+		record.Name = name
+		record.Labels = labels
+		record.Spec = spec
+		if err := r.Create(ctx, &record); err != nil {
+			return fmt.Errorf("failed to create AkamaiDnsRecord %s: %w", name, err)
+		}
+		log.FromContext(ctx).Info("Created CNAME of a hostname served on production", "record", name, "target", spec.Targets[0])
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get AkamaiDnsRecord %s: %w", name, err)
+	}
+
+	if owner := record.Labels[LabelCNAMEProperty]; owner != akamaiProperty.Name {
+		return fmt.Errorf("AkamaiDnsRecord %s already exists and is not managed by this property", name)
+	}
+	if reflect.DeepEqual(record.Spec, spec) {
+		return nil
+	}
+	record.Spec = spec
+	if err := r.Update(ctx, &record); err != nil {
+		return fmt.Errorf("failed to update AkamaiDnsRecord %s: %w", name, err)
+	}
+	return nil
+}
+
+// syncDNSEndpoint publishes the CNAMEs in a DNSEndpoint named after the property, removing the
+// one published before when the namespace changed or no CNAMEs are left
+func (r *AkamaiPropertyReconciler) syncDNSEndpoint(ctx context.Context, akamaiProperty *akamaiV1alpha1.AkamaiProperty, cnames []akamai.Hostname) error {
+	logger := log.FromContext(ctx)
+	published := ""
+	if len(cnames) > 0 {
+		published = akamaiProperty.Spec.DNS.Namespace + "/" + akamaiProperty.Name
+	}
+
+	if previous := akamaiProperty.Status.DNSEndpoint; previous != "" && previous != published {
+		namespace, name, _ := strings.Cut(previous, "/")
+		endpoint := &unstructured.Unstructured{}
+		endpoint.SetGroupVersionKind(dnsEndpointGVK)
+		endpoint.SetNamespace(namespace)
+		endpoint.SetName(name)
+		if err := r.Delete(ctx, endpoint); err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete DNSEndpoint %s: %w", previous, err)
+		}
+		logger.Info("Deleted DNSEndpoint", "dnsEndpoint", previous)
+		akamaiProperty.Status.DNSEndpoint = ""
+		if err := r.updateStatusWithRetry(ctx, akamaiProperty); err != nil {
+			return err
+		}
+	}
+	if published == "" {
+		return nil
+	}
+
+	endpoints := make([]interface{}, 0, len(cnames))
+	for _, cname := range cnames {
+		endpoints = append(endpoints, map[string]interface{}{
+			"dnsName":    cname.CNAMEFrom,
+			"recordType": "CNAME",
+			"recordTTL":  int64(cnameTTL(akamaiProperty)),
+			"targets":    []interface{}{cname.CNAMETo},
+		})
+	}
+
+	key := types.NamespacedName{Namespace: akamaiProperty.Spec.DNS.Namespace, Name: akamaiProperty.Name}
+	endpoint := &unstructured.Unstructured{}
+	endpoint.SetGroupVersionKind(dnsEndpointGVK)
+	err := r.Get(ctx, key, endpoint)
+	switch {
+	case apierrors.IsNotFound(err):
+		endpoint.SetNamespace(key.Namespace)
+		endpoint.SetName(key.Name)
+		endpoint.SetLabels(map[string]string{ManagedByLabel: ManagedByValue, LabelCNAMEProperty: akamaiProperty.Name})
+		if err := unstructured.SetNestedSlice(endpoint.Object, endpoints, "spec", "endpoints"); err != nil {
+			return fmt.Errorf("failed to build DNSEndpoint %s: %w", published, err)
+		}
+		if err := r.Create(ctx, endpoint); err != nil {
+			return fmt.Errorf("failed to create DNSEndpoint %s: %w", published, err)
+		}
+		logger.Info("Created DNSEndpoint", "dnsEndpoint", published, "endpoints", len(endpoints))
+	case err != nil:
+		return fmt.Errorf("failed to get DNSEndpoint %s: %w", published, err)
+	default:
+		current, _, _ := unstructured.NestedSlice(endpoint.Object, "spec", "endpoints")
+		if !equality.Semantic.DeepEqual(current, endpoints) {
+			if err := unstructured.SetNestedSlice(endpoint.Object, endpoints, "spec", "endpoints"); err != nil {
+				return fmt.Errorf("failed to build DNSEndpoint %s: %w", published, err)
+			}
+			if err := r.Update(ctx, endpoint); err != nil {
+				return fmt.Errorf("failed to update DNSEndpoint %s: %w", published, err)
+			}
+			logger.Info("Updated DNSEndpoint", "dnsEndpoint", published, "endpoints", len(endpoints))
+		}
+	}
+
+	if akamaiProperty.Status.DNSEndpoint == published {
+		return nil
+	}
+	akamaiProperty.Status.DNSEndpoint = published
+	return r.updateStatusWithRetry(ctx, akamaiProperty)
+}
@@ -2,17 +2,26 @@ package controllers
 
 import (
 	"context"
+	"fmt"
 	"time"
 
+	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/events"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
 	"github.com/mmz-srf/akamai-operator/pkg/akamai"
+	"github.com/mmz-srf/akamai-operator/pkg/lint"
+	"github.com/mmz-srf/akamai-operator/pkg/render"
 )
 
 // AkamaiPropertyReconciler reconciles a AkamaiProperty object
@@ -20,11 +29,98 @@ type AkamaiPropertyReconciler struct {
 	client.Client
 	Scheme       *runtime.Scheme
 	AkamaiClient *akamai.Client
+
+	// Credentials selects the credential source the Akamai client is created with
+	Credentials akamai.Credentials
+
+	// ClientCache holds the Akamai clients of properties with a credentialsRef; nil builds a
+	// new client on every reconcile
+	ClientCache *AkamaiClientCache
+
+	// Linter lints the rule tree before it is applied; nil disables linting
+	Linter *lint.Linter
+
+	// VersionPollInterval is how often a version locked by a pending activation is polled
+	VersionPollInterval time.Duration
+
+	// InjectRuleComments appends a managed-by block to the top-level rule comments
+	InjectRuleComments bool
+
+	// CheckRuleFormats validates that spec.ruleFormat is supported for spec.productId
+	CheckRuleFormats bool
+
+	// ValidateRuleSchemas validates the behaviors and criteria of the rules against the PAPI
+	// schema of spec.productId and spec.ruleFormat before they are pushed
+	ValidateRuleSchemas bool
+
+	// EdgeHostnameTemplate renders the domain prefix of edge hostnames whose spec leaves it empty,
+	// e.g. "{team}-{env}-{property}"; see renderNameTemplate
+	EdgeHostnameTemplate string
+
+	// ActivationScheduler limits concurrent activations across all properties; nil means unlimited
+	ActivationScheduler *ActivationScheduler
+
+	// ActivationWatcher follows activations in flight and reconciles their property as soon as
+	// they finish; nil only polls them with requeues
+	ActivationWatcher *akamai.ActivationWatcher
+
+	// activationEvents receives the properties whose watched activations finished
+	activationEvents chan event.GenericEvent
+
+	// includeUsers remembers the includes the rendered rule tree of each property references
+	includeUsers *includeUsers
+
+	// PreserveBehaviors lists behavior names the operator never removes from any property, in
+	// addition to the spec.preserveBehaviors of each property
+	PreserveBehaviors []string
+
+	// Guardrails lists guard checks run on the rendered rules before every production activation,
+	// in addition to the spec.activation.guardrails of each property
+	Guardrails []string
+
+	// Renderers are the external rule tree renderers properties can select by name
+	Renderers map[string]render.Renderer
+
+	// RulesFetcher loads and caches spec.rulesFrom; nil uses a fetcher shared by all reconcilers
+	RulesFetcher *render.Fetcher
+
+	// Drain lets the Akamai writes in flight complete at shutdown; nil aborts them with the reconcile
+	Drain *WriteDrain
+
+	// Backoff computes the retry delay of failed reconciles; nil retries every two minutes
+	Backoff *ErrorBackoff
+
+	// Recorder emits events about the properties; nil discards them
+	Recorder events.EventRecorder
+
+	// ExpiryWarning is how long before spec.expiresAt or spec.ttl runs out a temporary property
+	// is warned about
+	ExpiryWarning time.Duration
+
+	// MaxConcurrentReconciles is the number of properties reconciled in parallel; zero
+	// reconciles one at a time
+	MaxConcurrentReconciles int
+
+	// ObserveOnly compares the properties with Akamai and reports differences without changing
+	// anything in Akamai: no properties are created, updated, activated or deleted
+	ObserveOnly bool
+
+	// Shard is the part of the fleet this instance manages; nil manages all properties
+	Shard *Shard
+
+	// CollectEdgeHostnames deletes the edge hostnames a property created once none of its
+	// hostnames and versions use them anymore, instead of only when the property is deleted
+	CollectEdgeHostnames bool
 }
 
 //+kubebuilder:rbac:groups=akamai.com,resources=akamaiproperties,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=akamai.com,resources=akamaiproperties/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=akamai.com,resources=akamaiproperties/finalizers,verbs=update
+//+kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch;create;update;patch
+//+kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch
+//+kubebuilder:rbac:groups=events.k8s.io,resources=events,verbs=create;patch
+//+kubebuilder:rbac:groups=akamai.com,resources=akamaidnsrecords,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=externaldns.k8s.io,resources=dnsendpoints,verbs=get;list;watch;create;update;patch;delete
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -42,24 +138,24 @@ func (r *AkamaiPropertyReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 		return ctrl.Result{}, err
 	}
 
-	// Initialize Akamai client if not already done
-	if r.AkamaiClient == nil {
-		akamaiClient, err := akamai.NewClient()
-		if err != nil {
-			logger.Error(err, "Failed to create Akamai client")
-			r.updateStatus(ctx, &akamaiProperty, PhaseError, "FailedToInitializeAkamaiClient", err.Error())
-			return ctrl.Result{RequeueAfter: time.Minute * 5}, nil
+	// Properties of other shards are left to the instances managing them
+	if inShard, err := r.Shard.containsProperty(ctx, r.Client, &akamaiProperty); err != nil || !inShard {
+		if err == nil {
+			logger.V(1).Info("Property belongs to another shard", "shard", r.Shard.Name)
 		}
-		r.AkamaiClient = akamaiClient
+		return ctrl.Result{}, err
 	}
 
-	// Handle deletion
-	if akamaiProperty.ObjectMeta.DeletionTimestamp != nil {
-		return r.handleDeletion(ctx, &akamaiProperty)
+	// Suspended properties are left alone until they are resumed; deletion still proceeds
+	if by := suspendedBy(&akamaiProperty); akamaiProperty.ObjectMeta.DeletionTimestamp == nil && by != "" {
+		logger.V(1).Info("Reconciliation is suspended", "by", by)
+		r.updateStatus(ctx, &akamaiProperty, PhaseSuspended, "Suspended",
+			fmt.Sprintf("Reconciliation is suspended by %s", by))
+		return ctrl.Result{}, nil
 	}
 
-	// Add finalizer if not present
-	if !controllerutil.ContainsFinalizer(&akamaiProperty, FinalizerName) {
+	// Add finalizer if not present, before account defaults are applied to the spec in memory
+	if akamaiProperty.ObjectMeta.DeletionTimestamp == nil && !controllerutil.ContainsFinalizer(&akamaiProperty, FinalizerName) {
 		controllerutil.AddFinalizer(&akamaiProperty, FinalizerName)
 		if err := r.Update(ctx, &akamaiProperty); err != nil {
 			return ctrl.Result{}, err
@@ -67,13 +163,111 @@ func (r *AkamaiPropertyReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 		return ctrl.Result{}, nil
 	}
 
+	// Previews are reconciled with the spec of their preview property, applied in memory only
+	if isPreview(&akamaiProperty) {
+		if err := r.preparePreview(ctx, &akamaiProperty); err != nil {
+			if akamaiProperty.ObjectMeta.DeletionTimestamp == nil {
+				logger.Error(err, "Failed to prepare preview property")
+				r.updateStatus(ctx, &akamaiProperty, PhaseError, "FailedToPreparePreview", akamai.ErrorMessage(err))
+				return r.retryAfterError(&akamaiProperty, err), nil
+			}
+			// The preview is cleaned up with its own spec when the base is already gone
+			logger.Info("Deleting preview without its base", "error", err.Error())
+		}
+	}
+
+	// Initialize the Akamai client of the property's account if not already done
+	reconciler, err := r.forAccount(ctx, &akamaiProperty)
+	if err != nil {
+		logger.Error(err, "Failed to create Akamai client")
+		r.updateStatus(ctx, &akamaiProperty, PhaseError, "FailedToInitializeAkamaiClient", akamai.ErrorMessage(err))
+		return r.retryAfterError(&akamaiProperty, err), nil
+	}
+
+	// Akamai writes started by this reconcile complete even when the operator shuts down meanwhile
+	ctx, done, err := r.Drain.Begin(ctx)
+	if err != nil {
+		logger.Info("Not starting Akamai writes", "reason", err.Error())
+		return ctrl.Result{RequeueAfter: time.Minute}, nil
+	}
+	defer done()
+
+	// Handle deletion
+	if akamaiProperty.ObjectMeta.DeletionTimestamp != nil {
+		return reconciler.handleDeletion(ctx, &akamaiProperty)
+	}
+
+	// A manual sync requested through the Flux annotation re-runs all checks of the generation
+	requestedAt, syncRequested := requestedReconcile(&akamaiProperty)
+	if syncRequested {
+		logger.Info("Manual sync requested", "requestedAt", requestedAt)
+		forgetValidation(&akamaiProperty)
+	}
+
+	// A spec edit makes the Ready condition stale until the new generation is reconciled
+	if staleReady(&akamaiProperty) {
+		if err := r.updateStatusWithRetry(ctx, &akamaiProperty); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	// Temporary properties are deleted once they expire
+	if expired, err := r.handleExpiry(ctx, &akamaiProperty, time.Now()); expired || err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// Hostnames referencing an AkamaiEdgeHostname are CNAMEd to its domain
+	if err := r.resolveEdgeHostnameRefs(ctx, &akamaiProperty); err != nil {
+		logger.Info("Edge hostnames are not resolved", "reason", err.Error())
+		r.updateStatus(ctx, &akamaiProperty, PhaseError, "EdgeHostnameNotResolved", err.Error())
+		return r.retryAfterError(&akamaiProperty, err), nil
+	}
+
 	// Reconcile the property
-	return r.reconcileProperty(ctx, &akamaiProperty)
+	result, err := reconciler.reconcileProperty(ctx, &akamaiProperty)
+	if err == nil && akamaiProperty.Status.Phase != PhaseError {
+		r.Backoff.Forget(akamaiProperty.Name)
+	}
+	if err == nil {
+		// Publish what the property plans to activate for the activation calendar
+		if err := r.recordPlannedActivations(ctx, &akamaiProperty); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+	result = r.expiryRequeue(&akamaiProperty, result, time.Now())
+	if err != nil || !syncRequested {
+		return result, err
+	}
+	akamaiProperty.Status.LastHandledReconcileAt = requestedAt
+	if err := r.updateStatusWithRetry(ctx, &akamaiProperty); err != nil {
+		return ctrl.Result{}, err
+	}
+	return result, nil
 }
 
-// SetupWithManager sets up the controller with the Manager.
+// SetupWithManager sets up the controller with the Manager and indexes activations by their property.
 func (r *AkamaiPropertyReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &akamaiV1alpha1.AkamaiPropertyActivation{},
+		activationPropertyRefField, indexActivationPropertyRef); err != nil {
+		return err
+	}
+	r.includeUsers = &includeUsers{}
+	builder := ctrl.NewControllerManagedBy(mgr).
 		For(&akamaiV1alpha1.AkamaiProperty{}).
-		Complete(r)
+		WithOptions(controller.Options{MaxConcurrentReconciles: r.MaxConcurrentReconciles})
+	if r.ActivationWatcher != nil {
+		r.activationEvents = make(chan event.GenericEvent, activationEventBuffer)
+		builder = builder.WatchesRawSource(source.Channel(r.activationEvents, &handler.EnqueueRequestForObject{}))
+	}
+	// Properties read values from Secrets and ConfigMaps, include IDs, Cloudlets policy IDs and
+	// edge hostnames at reconcile time; changes are applied right away instead of on the next
+	// periodic reconcile
+	builder = builder.
+		Watches(&corev1.Secret{}, handler.EnqueueRequestsFromMapFunc(r.propertiesReadingFrom(placeholderSecret))).
+		Watches(&corev1.ConfigMap{}, handler.EnqueueRequestsFromMapFunc(r.propertiesReadingFrom(placeholderConfigMap))).
+		Watches(&akamaiV1alpha1.AkamaiPropertyInclude{}, handler.EnqueueRequestsFromMapFunc(r.propertiesIncluding)).
+		Watches(&akamaiV1alpha1.AkamaiCloudletPolicy{}, handler.EnqueueRequestsFromMapFunc(r.propertiesReferencingCloudletPolicy)).
+		Watches(&akamaiV1alpha1.AkamaiEdgeHostname{}, handler.EnqueueRequestsFromMapFunc(r.propertiesReferencingEdgeHostname)).
+		Owns(&akamaiV1alpha1.AkamaiPropertyActivation{})
+	return builder.Complete(r)
 }
@@ -0,0 +1,95 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+	"github.com/mmz-srf/akamai-operator/pkg/akamai"
+)
+
+// deletionPolicy returns the deletion policy of the property, defaulting to Delete
+func deletionPolicy(akamaiProperty *akamaiV1alpha1.AkamaiProperty) akamaiV1alpha1.DeletionPolicy {
+	if akamaiProperty.Spec.DeletionPolicy == "" {
+		return akamaiV1alpha1.DeletionPolicyDelete
+	}
+	return akamaiProperty.Spec.DeletionPolicy
+}
+
+// deactivateProperty deactivates the versions of the property active on the given networks. It
+// reports whether the property is inactive on all of them; otherwise a deactivation (or an
+// activation that has to finish first) is in progress and the caller should retry later.
+func (r *AkamaiPropertyReconciler) deactivateProperty(ctx context.Context, akamaiProperty *akamaiV1alpha1.AkamaiProperty, networks ...string) (bool, error) {
+	logger := log.FromContext(ctx)
+	propertyID := akamaiProperty.Status.PropertyID
+
+	property, err := r.AkamaiClient.GetProperty(ctx, propertyID)
+	if err != nil {
+		return false, err
+	}
+	activeVersions := make(map[string]int, len(networks))
+	for _, network := range networks {
+		version := property.StagingVersion
+		if network == "PRODUCTION" {
+			version = property.ProductionVersion
+		}
+		if version != 0 {
+			activeVersions[network] = version
+		}
+	}
+	if len(activeVersions) == 0 {
+		return true, nil
+	}
+
+	// Akamai accepts one activation or deactivation per network at a time
+	activations, err := r.AkamaiClient.ListActivations(ctx, propertyID)
+	if err != nil {
+		return false, err
+	}
+	inFlight := make(map[string]bool, len(networks))
+	for _, activation := range activations {
+		if _, active := activeVersions[activation.Network]; active && !akamai.ActivationFinished(activation.Status) {
+			logger.Info("Waiting for activation to finish before deactivating",
+				"network", activation.Network, "activationID", activation.ActivationID,
+				"type", activation.ActivationType, "status", activation.Status)
+			inFlight[activation.Network] = true
+			r.watchActivation(akamaiProperty, activation.ActivationID)
+		}
+	}
+
+	for _, network := range networks {
+		version, active := activeVersions[network]
+		if !active || inFlight[network] {
+			continue
+		}
+		notifyEmails := deactivationNotifyEmails(akamaiProperty, activations, network, version)
+		if len(notifyEmails) == 0 {
+			return false, fmt.Errorf("version %d is active on %s, but neither spec.activation nor its activation has notifyEmails to deactivate it with", version, network)
+		}
+		activationID, err := r.AkamaiClient.DeactivateProperty(ctx, propertyID, version, network,
+			notifyEmails, akamaiProperty.Spec.ContractID, akamaiProperty.Spec.GroupID)
+		if err != nil {
+			return false, err
+		}
+		logger.Info("Deactivating property", "network", network, "version", version, "activationID", activationID)
+		r.watchActivation(akamaiProperty, activationID)
+	}
+	return false, nil
+}
+
+// deactivationNotifyEmails returns the addresses notified about a deactivation: those of
+// spec.activation, or of the activation of the active version for properties activated outside
+// the operator
+func deactivationNotifyEmails(akamaiProperty *akamaiV1alpha1.AkamaiProperty, activations []akamai.Activation, network string, version int) []string {
+	if akamaiProperty.Spec.Activation != nil && len(akamaiProperty.Spec.Activation.NotifyEmails) > 0 {
+		return akamaiProperty.Spec.Activation.NotifyEmails
+	}
+	for _, activation := range activations {
+		if activation.Network == network && activation.PropertyVersion == version && len(activation.NotifyEmails) > 0 {
+			return activation.NotifyEmails
+		}
+	}
+	return nil
+}
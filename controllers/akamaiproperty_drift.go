@@ -0,0 +1,137 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+)
+
+// Parts of a property checked for drift
+const (
+	driftHostnames = "hostnames"
+	driftRules     = "rules"
+)
+
+// driftPolicy returns the drift policy of the property, Correct by default
+func driftPolicy(akamaiProperty *akamaiV1alpha1.AkamaiProperty) akamaiV1alpha1.DriftPolicy {
+	if akamaiProperty.Spec.DriftPolicy == "" {
+		return akamaiV1alpha1.DriftPolicyCorrect
+	}
+	return akamaiProperty.Spec.DriftPolicy
+}
+
+// specApplied reports whether the spec is unchanged since it was last applied to Akamai. Only then
+// a difference between the spec and Akamai is drift rather than a spec change to apply.
+func specApplied(akamaiProperty *akamaiV1alpha1.AkamaiProperty) bool {
+	applied := akamaiProperty.Annotations[AnnotationAppliedChecksum]
+	if applied == "" {
+		return false
+	}
+	checksum, err := specChecksum(&akamaiProperty.Spec)
+	return err == nil && checksum == applied
+}
+
+// handleDrift is called when a part of the property in Akamai differs from the spec and reports
+// whether the operator overwrites it. Spec changes are always applied; out-of-band changes are
+// corrected, reported or ignored according to spec.driftPolicy. In observe-only mode every
+// difference is reported and nothing is overwritten.
+func (r *AkamaiPropertyReconciler) handleDrift(ctx context.Context, akamaiProperty *akamaiV1alpha1.AkamaiProperty, part string) bool {
+	if r.ObserveOnly {
+		r.reportDrift(ctx, akamaiProperty, part)
+		return false
+	}
+	if !specApplied(akamaiProperty) {
+		r.resolveDrift(ctx, akamaiProperty, part)
+		return true
+	}
+
+	logger := log.FromContext(ctx)
+	switch driftPolicy(akamaiProperty) {
+	case akamaiV1alpha1.DriftPolicyWarn:
+		r.reportDrift(ctx, akamaiProperty, part)
+		return false
+	case akamaiV1alpha1.DriftPolicyIgnore:
+		logger.V(1).Info("Ignoring Akamai property changed outside the operator", "part", part)
+		r.resolveDrift(ctx, akamaiProperty, part)
+		return false
+	default:
+		logger.Info("Correcting Akamai property changed outside the operator", "part", part)
+		r.recordEvent(akamaiProperty, corev1.EventTypeNormal, ReasonDriftCorrected, "Correct",
+			"The %s of property %s were changed outside the operator; restoring the spec", part, akamaiProperty.Spec.PropertyName)
+		r.resolveDrift(ctx, akamaiProperty, part)
+		return true
+	}
+}
+
+// reportDrift records that a part of the property differs from the spec and is kept, emitting an
+// event the first time
+func (r *AkamaiPropertyReconciler) reportDrift(ctx context.Context, akamaiProperty *akamaiV1alpha1.AkamaiProperty, part string) {
+	uncorrected := r.uncorrectedDrift(akamaiProperty)
+	log.FromContext(ctx).Info("Akamai property differs from the spec, keeping it", "part", part, "reason", uncorrected)
+	if setDrift(akamaiProperty, part, true, uncorrected) {
+		r.recordEvent(akamaiProperty, corev1.EventTypeWarning, ReasonDriftDetected, "Compare",
+			"The %s of property %s differ from the spec; not correcting them %s",
+			part, akamaiProperty.Spec.PropertyName, uncorrected)
+		r.persistDrift(ctx, akamaiProperty)
+	}
+}
+
+// uncorrectedDrift explains why drift of the property is kept
+func (r *AkamaiPropertyReconciler) uncorrectedDrift(akamaiProperty *akamaiV1alpha1.AkamaiProperty) string {
+	if r.ObserveOnly {
+		return "in observe-only mode"
+	}
+	return fmt.Sprintf("with drift policy %s", driftPolicy(akamaiProperty))
+}
+
+// resolveDrift records that a part of the property no longer drifts
+func (r *AkamaiPropertyReconciler) resolveDrift(ctx context.Context, akamaiProperty *akamaiV1alpha1.AkamaiProperty, part string) {
+	if setDrift(akamaiProperty, part, false, r.uncorrectedDrift(akamaiProperty)) {
+		r.persistDrift(ctx, akamaiProperty)
+	}
+}
+
+// persistDrift writes status.drift and the DriftDetected condition
+func (r *AkamaiPropertyReconciler) persistDrift(ctx context.Context, akamaiProperty *akamaiV1alpha1.AkamaiProperty) {
+	if err := r.updateStatusWithRetry(ctx, akamaiProperty); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to record drift")
+	}
+}
+
+// setDrift adds or removes a part of status.drift and updates the DriftDetected condition, whose
+// message explains with uncorrected why the drift is kept. It reports whether status.drift changed.
+func setDrift(akamaiProperty *akamaiV1alpha1.AkamaiProperty, part string, drifted bool, uncorrected string) bool {
+	index := slices.Index(akamaiProperty.Status.Drift, part)
+	switch {
+	case drifted && index == -1:
+		akamaiProperty.Status.Drift = append(akamaiProperty.Status.Drift, part)
+		slices.Sort(akamaiProperty.Status.Drift)
+	case !drifted && index != -1:
+		akamaiProperty.Status.Drift = slices.Delete(akamaiProperty.Status.Drift, index, index+1)
+	default:
+		return false
+	}
+
+	if len(akamaiProperty.Status.Drift) == 0 {
+		akamaiProperty.Status.Drift = nil
+		meta.RemoveStatusCondition(&akamaiProperty.Status.Conditions, ConditionTypeDriftDetected)
+		return true
+	}
+	meta.SetStatusCondition(&akamaiProperty.Status.Conditions, metav1.Condition{
+		Type:   ConditionTypeDriftDetected,
+		Status: metav1.ConditionTrue,
+		Reason: ReasonDriftDetected,
+		Message: fmt.Sprintf("Differs from the spec and not corrected %s: %s",
+			uncorrected, strings.Join(akamaiProperty.Status.Drift, ", ")),
+		ObservedGeneration: akamaiProperty.Generation,
+	})
+	return true
+}
@@ -0,0 +1,188 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+	"github.com/mmz-srf/akamai-operator/pkg/akamai"
+)
+
+// dryRunPlan collects the changes a dry run would make in Akamai
+type dryRunPlan struct {
+	changes []string
+}
+
+// add records a change
+func (p *dryRunPlan) add(format string, args ...interface{}) {
+	p.changes = append(p.changes, fmt.Sprintf(format, args...))
+}
+
+// dryRun reports whether the reconcile of the property previews its changes. Observe-only mode
+// takes precedence: it reports differences as drift.
+func (r *AkamaiPropertyReconciler) dryRun(akamaiProperty *akamaiV1alpha1.AkamaiProperty) bool {
+	return akamaiProperty.Spec.DryRun && !r.ObserveOnly
+}
+
+// readOnly reports whether the reconcile of the property must not change anything in Akamai
+func (r *AkamaiPropertyReconciler) readOnly(akamaiProperty *akamaiV1alpha1.AkamaiProperty) bool {
+	return akamaiProperty.Spec.DryRun || r.ObserveOnly
+}
+
+// planPropertyCreation records the creation of a property that doesn't exist in Akamai yet
+func planPropertyCreation(plan *dryRunPlan, akamaiProperty *akamaiV1alpha1.AkamaiProperty) {
+	spec := &akamaiProperty.Spec
+	plan.add("Create property %q with product %s in contract %s and group %s", spec.PropertyName, spec.ProductID, spec.ContractID, spec.GroupID)
+	if managesHostnames(akamaiProperty) {
+		for _, hostname := range spec.Hostnames {
+			plan.add("Add hostname %s -> %s", hostname.CNAMEFrom, hostname.CNAMETo)
+		}
+	}
+	if managesRules(akamaiProperty) {
+		plan.add("Set the rules of version 1")
+	}
+}
+
+// planPropertyUpdate records the property name and hostname changes of a property
+func planPropertyUpdate(plan *dryRunPlan, akamaiProperty *akamaiV1alpha1.AkamaiProperty, current *akamai.Property) {
+	if akamaiProperty.Spec.PropertyName != current.PropertyName {
+		plan.add("Change the property name from %q to %q", current.PropertyName, akamaiProperty.Spec.PropertyName)
+	}
+	if len(akamaiProperty.Spec.Hostnames) == 0 {
+		return
+	}
+	add, remove := akamai.ComputeHostnameDelta(akamaiProperty.Spec.Hostnames, current.Hostnames, akamaiProperty.Status.ManagedHostnames)
+	for _, hostname := range add {
+		plan.add("Add hostname %s -> %s", hostname.CNAMEFrom, hostname.CNAMETo)
+	}
+	for _, hostname := range remove {
+		plan.add("Remove hostname %s", hostname)
+	}
+}
+
+// planRulesUpdate records the rule tree and version notes changes of a property
+func (r *AkamaiPropertyReconciler) planRulesUpdate(plan *dryRunPlan, desiredRules *akamaiV1alpha1.PropertyRules, versionNotes string, currentRules *akamai.PropertyRules, rulesDiffer bool) error {
+	if rulesDiffer {
+		changes, err := r.rulesDiff(desiredRules, currentRules.Rules)
+		if err != nil {
+			return fmt.Errorf("failed to describe rule changes: %w", err)
+		}
+		plan.changes = append(plan.changes, changes...)
+	}
+	if versionNotes != "" && versionNotes != currentRules.Comments {
+		plan.add("Update the version notes")
+	}
+	return nil
+}
+
+// planVersion describes the version the changes of a dry run would be written to and returns
+// its number, following the version strategy and foreign version policy without creating it
+func (r *AkamaiPropertyReconciler) planVersion(ctx context.Context, akamaiProperty *akamaiV1alpha1.AkamaiProperty) (string, int, error) {
+	latestVersion := akamaiProperty.Status.LatestVersion
+	versionState, err := r.AkamaiClient.GetPropertyVersion(ctx,
+		akamaiProperty.Status.PropertyID,
+		latestVersion,
+		akamaiProperty.Spec.ContractID,
+		akamaiProperty.Spec.GroupID)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to check if version %d is published: %w", latestVersion, err)
+	}
+	if versionState.IsPending() {
+		return fmt.Sprintf("Wait for the pending activation of version %d before writing the changes", latestVersion), latestVersion, nil
+	}
+
+	createVersion, err := needsNewVersion(akamaiProperty, versionState.IsPublished())
+	if errors.Is(err, errManualVersionRequired) {
+		return fmt.Sprintf("Wait for a new version created in Control Center, version %d is published", latestVersion), latestVersion, nil
+	}
+	if err != nil {
+		return "", 0, err
+	}
+	if !createVersion && isForeignVersion(akamaiProperty, versionState) {
+		if foreignVersionPolicy(akamaiProperty) == akamaiV1alpha1.ForeignVersionPolicyRefuse {
+			return fmt.Sprintf("Wait until version %d, last updated by %q, is activated", latestVersion, versionState.UpdatedByUser), latestVersion, nil
+		}
+		createVersion = true
+	}
+	if createVersion {
+		return fmt.Sprintf("Create version %d from version %d", latestVersion+1, latestVersion), latestVersion + 1, nil
+	}
+	return fmt.Sprintf("Write the changes to version %d", latestVersion), latestVersion, nil
+}
+
+// planActivation records the activation of spec.activation the changes would lead to
+func planActivation(plan *dryRunPlan, akamaiProperty *akamaiV1alpha1.AkamaiProperty, version int) {
+	activation := akamaiProperty.Spec.Activation
+	if activation == nil || !managesActivation(akamaiProperty) || activationsFrozen(akamaiProperty) {
+		return
+	}
+	activeVersion, lastNote := akamaiProperty.Status.StagingVersion, akamaiProperty.Status.StagingActivationNote
+	if activation.Network == "PRODUCTION" {
+		activeVersion, lastNote = akamaiProperty.Status.ProductionVersion, akamaiProperty.Status.ProductionActivationNote
+	}
+	if shouldActivate(activationTrigger(activation), activation.Note != lastNote, version, activeVersion) {
+		plan.add("Activate version %d on %s", version, activation.Network)
+	}
+}
+
+// completeDryRun publishes the changes collected by a dry run in status.pendingChanges and the
+// DryRun condition, adding the version they would be written to and the resulting activation
+func (r *AkamaiPropertyReconciler) completeDryRun(ctx context.Context, akamaiProperty *akamaiV1alpha1.AkamaiProperty, plan *dryRunPlan) (ctrl.Result, error) {
+	version := akamaiProperty.Status.LatestVersion
+	switch {
+	case akamaiProperty.Status.PropertyID == "":
+		version = 1
+	case len(plan.changes) > 0:
+		description, planned, err := r.planVersion(ctx, akamaiProperty)
+		if err != nil {
+			if result, denied := r.permissionsInsufficient(ctx, akamaiProperty, accessRead, "read property version", err); denied {
+				return result, nil
+			}
+			r.updateStatus(ctx, akamaiProperty, PhaseError, "FailedToPlanDryRun", akamai.ErrorMessage(err))
+			return r.retryAfterError(akamaiProperty, err), nil
+		}
+		plan.changes = append([]string{description}, plan.changes...)
+		version = planned
+	}
+	planActivation(plan, akamaiProperty, version)
+
+	akamaiProperty.Status.PendingChanges = plan.changes
+	condition := metav1.Condition{
+		Type:               ConditionTypeDryRun,
+		Status:             metav1.ConditionTrue,
+		Reason:             ReasonNoChanges,
+		Message:            "Akamai matches the spec",
+		ObservedGeneration: akamaiProperty.Generation,
+	}
+	if len(plan.changes) > 0 {
+		condition.Reason = ReasonChangesPending
+		condition.Message = strings.Join(plan.changes, "; ")
+	}
+	meta.SetStatusCondition(&akamaiProperty.Status.Conditions, condition)
+	clearPermissionsInsufficient(akamaiProperty)
+	akamaiProperty.Status.ObservedGeneration = akamaiProperty.Generation
+	if err := r.updateStatusWithRetry(ctx, akamaiProperty); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	message := "Dry run: no changes pending"
+	if len(plan.changes) > 0 {
+		message = fmt.Sprintf("Dry run: %d changes pending, see status.pendingChanges", len(plan.changes))
+	}
+	r.updateStatus(ctx, akamaiProperty, PhaseDryRun, ReasonDryRun, message)
+	return ctrl.Result{RequeueAfter: time.Minute * 30}, nil
+}
+
+// clearPendingChanges removes the results of a dry run. It reports whether the status changed.
+func clearPendingChanges(akamaiProperty *akamaiV1alpha1.AkamaiProperty) bool {
+	changed := akamaiProperty.Status.PendingChanges != nil
+	akamaiProperty.Status.PendingChanges = nil
+	return meta.RemoveStatusCondition(&akamaiProperty.Status.Conditions, ConditionTypeDryRun) || changed
+}
@@ -0,0 +1,208 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+	"github.com/mmz-srf/akamai-operator/pkg/akamai"
+)
+
+const (
+	// DefaultEdgeEndpointsConfigMapName is the well-known name of the edge endpoints ConfigMap
+	DefaultEdgeEndpointsConfigMapName = "akamai-edge-endpoints"
+
+	// ManagedByLabel marks Kubernetes objects maintained by the operator
+	ManagedByLabel = "app.kubernetes.io/managed-by"
+
+	// ManagedByValue is the value of ManagedByLabel for objects maintained by the operator
+	ManagedByValue = "akamai-operator"
+)
+
+// EdgeEndpoint maps a property hostname to its edge hostname
+type EdgeEndpoint struct {
+	Hostname     string `json:"hostname"`
+	EdgeHostname string `json:"edgeHostname"`
+}
+
+// EdgeEndpointsEntry is the document published for a single AkamaiProperty
+type EdgeEndpointsEntry struct {
+	PropertyName      string         `json:"propertyName"`
+	PropertyID        string         `json:"propertyId"`
+	LatestVersion     int            `json:"latestVersion,omitempty"`
+	StagingVersion    int            `json:"stagingVersion,omitempty"`
+	ProductionVersion int            `json:"productionVersion,omitempty"`
+	Endpoints         []EdgeEndpoint `json:"endpoints"`
+}
+
+// buildEdgeEndpointsEntry builds the edge endpoints document for a property serving hostnames
+func buildEdgeEndpointsEntry(akamaiProperty *akamaiV1alpha1.AkamaiProperty, hostnames []akamai.Hostname) EdgeEndpointsEntry {
+	entry := EdgeEndpointsEntry{
+		PropertyName:      akamaiProperty.Spec.PropertyName,
+		PropertyID:        akamaiProperty.Status.PropertyID,
+		LatestVersion:     akamaiProperty.Status.LatestVersion,
+		StagingVersion:    akamaiProperty.Status.StagingVersion,
+		ProductionVersion: akamaiProperty.Status.ProductionVersion,
+		Endpoints:         make([]EdgeEndpoint, 0, len(hostnames)),
+	}
+	for _, h := range hostnames {
+		entry.Endpoints = append(entry.Endpoints, EdgeEndpoint{
+			Hostname:     h.CNAMEFrom,
+			EdgeHostname: h.CNAMETo,
+		})
+	}
+	return entry
+}
+
+// activeHostnames returns the hostnames of the version active on production, or on staging
+// before the property reaches production; none before any version is active
+func (r *AkamaiPropertyReconciler) activeHostnames(ctx context.Context, akamaiProperty *akamaiV1alpha1.AkamaiProperty) ([]akamai.Hostname, error) {
+	version := akamaiProperty.Status.ProductionVersion
+	if version == 0 {
+		version = akamaiProperty.Status.StagingVersion
+	}
+	if version == 0 {
+		return nil, nil
+	}
+	hostnames, err := r.AkamaiClient.GetPropertyHostnames(ctx,
+		akamaiProperty.Status.PropertyID,
+		akamaiProperty.Spec.ContractID,
+		akamaiProperty.Spec.GroupID,
+		version)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the hostnames of version %d: %w", version, err)
+	}
+	return hostnames, nil
+}
+
+// edgeEndpointsConfigMapKey returns the namespaced name of the edge endpoints ConfigMap
+func edgeEndpointsConfigMapKey(spec *akamaiV1alpha1.EdgeEndpointsSpec) types.NamespacedName {
+	name := spec.ConfigMapName
+	if name == "" {
+		name = DefaultEdgeEndpointsConfigMapName
+	}
+	return types.NamespacedName{Namespace: spec.Namespace, Name: name}
+}
+
+// edgeEndpointsDataKey returns the ConfigMap data key used for a property
+func edgeEndpointsDataKey(akamaiProperty *akamaiV1alpha1.AkamaiProperty) string {
+	return akamaiProperty.Name + ".json"
+}
+
+// publishEdgeEndpoints writes the edge endpoints of the active version into the configured
+// ConfigMap, removing the entry from the ConfigMap published to before when spec.edgeEndpoints
+// moved it or was removed
+func (r *AkamaiPropertyReconciler) publishEdgeEndpoints(ctx context.Context, akamaiProperty *akamaiV1alpha1.AkamaiProperty) error {
+	spec := akamaiProperty.Spec.EdgeEndpoints
+	published := ""
+	if spec != nil && spec.Namespace != "" {
+		published = edgeEndpointsConfigMapKey(spec).String()
+	}
+	if previous := akamaiProperty.Status.EdgeEndpoints; previous != "" && previous != published {
+		if err := r.removeEdgeEndpointsEntry(ctx, akamaiProperty, previous); err != nil {
+			return err
+		}
+		akamaiProperty.Status.EdgeEndpoints = ""
+		if err := r.updateStatusWithRetry(ctx, akamaiProperty); err != nil {
+			return err
+		}
+	}
+	if published == "" {
+		return nil
+	}
+	logger := log.FromContext(ctx)
+
+	hostnames, err := r.activeHostnames(ctx, akamaiProperty)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(buildEdgeEndpointsEntry(akamaiProperty, hostnames))
+	if err != nil {
+		return fmt.Errorf("failed to marshal edge endpoints: %w", err)
+	}
+
+	key := edgeEndpointsConfigMapKey(spec)
+	dataKey := edgeEndpointsDataKey(akamaiProperty)
+
+	var configMap corev1.ConfigMap
+	if err := r.Get(ctx, key, &configMap); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to get edge endpoints ConfigMap: %w", err)
+		}
+
+		configMap = corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      key.Name,
+				Namespace: key.Namespace,
+				Labels:    map[string]string{ManagedByLabel: ManagedByValue},
+			},
+			Data: map[string]string{dataKey: string(data)},
+		}
+		if err := r.Create(ctx, &configMap); err != nil {
+			return fmt.Errorf("failed to create edge endpoints ConfigMap: %w", err)
+		}
+		logger.Info("Created edge endpoints ConfigMap", "namespace", key.Namespace, "name", key.Name)
+	} else if configMap.Data[dataKey] != string(data) {
+		if configMap.Data == nil {
+			configMap.Data = map[string]string{}
+		}
+		configMap.Data[dataKey] = string(data)
+		if err := r.Update(ctx, &configMap); err != nil {
+			return fmt.Errorf("failed to update edge endpoints ConfigMap: %w", err)
+		}
+		logger.V(1).Info("Updated edge endpoints ConfigMap", "namespace", key.Namespace, "name", key.Name, "key", dataKey)
+	}
+
+	if akamaiProperty.Status.EdgeEndpoints == published {
+		return nil
+	}
+	akamaiProperty.Status.EdgeEndpoints = published
+	return r.updateStatusWithRetry(ctx, akamaiProperty)
+}
+
+// removeEdgeEndpoints removes the property's entry from the edge endpoints ConfigMaps it was
+// published to and is configured for
+func (r *AkamaiPropertyReconciler) removeEdgeEndpoints(ctx context.Context, akamaiProperty *akamaiV1alpha1.AkamaiProperty) error {
+	if previous := akamaiProperty.Status.EdgeEndpoints; previous != "" {
+		if err := r.removeEdgeEndpointsEntry(ctx, akamaiProperty, previous); err != nil {
+			return err
+		}
+	}
+	if spec := akamaiProperty.Spec.EdgeEndpoints; spec != nil && spec.Namespace != "" {
+		if key := edgeEndpointsConfigMapKey(spec).String(); key != akamaiProperty.Status.EdgeEndpoints {
+			return r.removeEdgeEndpointsEntry(ctx, akamaiProperty, key)
+		}
+	}
+	return nil
+}
+
+// removeEdgeEndpointsEntry removes the property's entry from the ConfigMap namespace/name
+func (r *AkamaiPropertyReconciler) removeEdgeEndpointsEntry(ctx context.Context, akamaiProperty *akamaiV1alpha1.AkamaiProperty, configMapKey string) error {
+	namespace, name, _ := strings.Cut(configMapKey, "/")
+	var configMap corev1.ConfigMap
+	if err := r.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, &configMap); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to get edge endpoints ConfigMap: %w", err)
+	}
+
+	dataKey := edgeEndpointsDataKey(akamaiProperty)
+	if _, ok := configMap.Data[dataKey]; !ok {
+		return nil
+	}
+	delete(configMap.Data, dataKey)
+	if err := r.Update(ctx, &configMap); err != nil {
+		return fmt.Errorf("failed to update edge endpoints ConfigMap: %w", err)
+	}
+	log.FromContext(ctx).Info("Removed the property from the edge endpoints ConfigMap", "configMap", configMapKey, "key", dataKey)
+	return nil
+}
@@ -0,0 +1,281 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+)
+
+// ensureEdgeHostnames creates missing edge hostnames and records the ones it created as owned
+// by the property, so they can be garbage collected when the last referencing property is deleted.
+// Edge hostnames referenced by name are managed by their AkamaiEdgeHostname.
+func (r *AkamaiPropertyReconciler) ensureEdgeHostnames(ctx context.Context, akamaiProperty *akamaiV1alpha1.AkamaiProperty) error {
+	edgeHostnameSpec, err := r.edgeHostnameSpec(akamaiProperty)
+	if err != nil {
+		return err
+	}
+	if edgeHostnameSpec != nil && edgeHostnameSpec.CertificateRef != "" {
+		certEnrollmentID, err := certificateEnrollmentID(ctx, r.Client, edgeHostnameSpec.CertificateRef)
+		if err != nil {
+			return err
+		}
+		resolved := *edgeHostnameSpec
+		resolved.CertEnrollmentID = certEnrollmentID
+		edgeHostnameSpec = &resolved
+	}
+
+	var hostnames []akamaiV1alpha1.Hostname
+	for _, h := range akamaiProperty.Spec.Hostnames {
+		if h.EdgeHostnameRef == "" {
+			hostnames = append(hostnames, h)
+		}
+	}
+	created, err := r.AkamaiClient.EnsureEdgeHostnamesExist(ctx,
+		hostnames,
+		edgeHostnameSpec,
+		akamaiProperty.Spec.ProductID,
+		akamaiProperty.Spec.ContractID,
+		akamaiProperty.Spec.GroupID)
+
+	// Record what was created even if a later edge hostname failed
+	if len(created) > 0 {
+		akamaiProperty.Status.OwnedEdgeHostnames = appendMissing(akamaiProperty.Status.OwnedEdgeHostnames, created...)
+		if statusErr := r.updateStatusWithRetry(ctx, akamaiProperty); statusErr != nil && err == nil {
+			err = statusErr
+		}
+	}
+	return err
+}
+
+// edgeHostnameReferences returns, per edge hostname, the sorted names of the other AkamaiProperties
+// referencing it. Properties being deleted no longer count as references.
+func (r *AkamaiPropertyReconciler) edgeHostnameReferences(ctx context.Context, akamaiProperty *akamaiV1alpha1.AkamaiProperty) (map[string][]string, error) {
+	var list akamaiV1alpha1.AkamaiPropertyList
+	if err := r.List(ctx, &list); err != nil {
+		return nil, fmt.Errorf("failed to list AkamaiProperties: %w", err)
+	}
+
+	references := make(map[string][]string)
+	for _, other := range list.Items {
+		if other.UID == akamaiProperty.UID || !other.DeletionTimestamp.IsZero() {
+			continue
+		}
+		seen := make(map[string]bool)
+		for _, h := range other.Spec.Hostnames {
+			// cnameTo of a referenced AkamaiEdgeHostname is only resolved in memory
+			if h.CNAMETo == "" || seen[h.CNAMETo] {
+				continue
+			}
+			seen[h.CNAMETo] = true
+			references[h.CNAMETo] = append(references[h.CNAMETo], other.Name)
+		}
+	}
+	for edgeHostname := range references {
+		sort.Strings(references[edgeHostname])
+	}
+	return references, nil
+}
+
+// planEdgeHostnameRelease decides for each owned edge hostname whether ownership moves to another
+// referencing property (returned as edge hostname -> property name) or the edge hostname is deleted
+func planEdgeHostnameRelease(owned []string, references map[string][]string) (map[string]string, []string) {
+	transfers := make(map[string]string)
+	var deletions []string
+	for _, edgeHostname := range owned {
+		if holders := references[edgeHostname]; len(holders) > 0 {
+			transfers[edgeHostname] = holders[0]
+			continue
+		}
+		deletions = append(deletions, edgeHostname)
+	}
+	return transfers, deletions
+}
+
+// releaseEdgeHostnames releases the edge hostnames owned by a property being deleted. Edge hostnames
+// still referenced by other properties are handed over to one of them; unreferenced ones are deleted.
+// Deletion is best effort: Akamai refuses it while a property outside the operator still uses the
+// edge hostname, which must not block the finalizer.
+func (r *AkamaiPropertyReconciler) releaseEdgeHostnames(ctx context.Context, akamaiProperty *akamaiV1alpha1.AkamaiProperty) error {
+	if len(akamaiProperty.Status.OwnedEdgeHostnames) == 0 {
+		return nil
+	}
+	if _, err := r.handOverOrDelete(ctx, akamaiProperty, akamaiProperty.Status.OwnedEdgeHostnames); err != nil {
+		return err
+	}
+	akamaiProperty.Status.OwnedEdgeHostnames = nil
+	return nil
+}
+
+// handOverOrDelete hands owned edge hostnames over to another property referencing them or deletes
+// them, and returns the ones the property no longer owns. Failed deletions are logged and kept.
+func (r *AkamaiPropertyReconciler) handOverOrDelete(ctx context.Context, akamaiProperty *akamaiV1alpha1.AkamaiProperty, edgeHostnames []string) ([]string, error) {
+	logger := log.FromContext(ctx)
+
+	references, err := r.edgeHostnameReferences(ctx, akamaiProperty)
+	if err != nil {
+		return nil, err
+	}
+
+	var released []string
+	transfers, deletions := planEdgeHostnameRelease(edgeHostnames, references)
+	for edgeHostname, holderName := range transfers {
+		var holder akamaiV1alpha1.AkamaiProperty
+		if err := r.Get(ctx, client.ObjectKey{Name: holderName}, &holder); err != nil {
+			return released, fmt.Errorf("failed to get AkamaiProperty %s: %w", holderName, err)
+		}
+		holder.Status.OwnedEdgeHostnames = appendMissing(holder.Status.OwnedEdgeHostnames, edgeHostname)
+		if err := r.updateStatusWithRetry(ctx, &holder); err != nil {
+			return released, fmt.Errorf("failed to transfer edge hostname %s to %s: %w", edgeHostname, holderName, err)
+		}
+		released = append(released, edgeHostname)
+		logger.Info("Edge hostname still referenced, transferred ownership",
+			"edgeHostname", edgeHostname,
+			"newOwner", holderName,
+			"references", len(references[edgeHostname]))
+	}
+
+	for _, edgeHostname := range deletions {
+		if err := r.AkamaiClient.DeleteEdgeHostname(ctx, edgeHostname); err != nil {
+			logger.Error(err, "Failed to delete edge hostname", "edgeHostname", edgeHostname)
+			continue
+		}
+		released = append(released, edgeHostname)
+		logger.Info("Deleted unreferenced edge hostname", "edgeHostname", edgeHostname)
+		r.recordEvent(akamaiProperty, corev1.EventTypeNormal, ReasonEdgeHostnameDeleted, "Delete",
+			"Deleted edge hostname %s, no AkamaiProperty references it anymore", edgeHostname)
+	}
+	return released, nil
+}
+
+// collectEdgeHostnames deletes the edge hostnames the property created once neither its spec nor
+// any of its versions CNAMEs a hostname to them anymore. Akamai doesn't stop the deletion of an
+// edge hostname inactive versions still use, so every version is checked, newest first, to keep
+// rollbacks to older versions working. Edge hostnames other properties reference are handed over
+// to one of them instead. Akamai refuses the deletion while a property outside the operator uses
+// the edge hostname; it is then retried with the next periodic reconcile.
+func (r *AkamaiPropertyReconciler) collectEdgeHostnames(ctx context.Context, akamaiProperty *akamaiV1alpha1.AkamaiProperty) error {
+	if !r.CollectEdgeHostnames || r.readOnly(akamaiProperty) {
+		return nil
+	}
+	candidates := unusedEdgeHostnames(akamaiProperty.Status.OwnedEdgeHostnames, akamaiProperty.Spec.Hostnames)
+
+	status := akamaiProperty.Status
+	for version := status.LatestVersion; version > 0 && len(candidates) > 0; version-- {
+		hostnames, err := r.AkamaiClient.GetPropertyHostnames(ctx, status.PropertyID,
+			akamaiProperty.Spec.ContractID, akamaiProperty.Spec.GroupID, version)
+		if err != nil {
+			return fmt.Errorf("failed to get the hostnames of version %d: %w", version, err)
+		}
+		var used []akamaiV1alpha1.Hostname
+		for _, h := range hostnames {
+			used = append(used, akamaiV1alpha1.Hostname{CNAMEFrom: h.CNAMEFrom, CNAMETo: h.CNAMETo})
+		}
+		candidates = unusedEdgeHostnames(candidates, used)
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	released, err := r.handOverOrDelete(ctx, akamaiProperty, candidates)
+	if len(released) > 0 {
+		akamaiProperty.Status.OwnedEdgeHostnames = slices.DeleteFunc(slices.Clone(akamaiProperty.Status.OwnedEdgeHostnames),
+			func(edgeHostname string) bool { return slices.Contains(released, edgeHostname) })
+		if statusErr := r.updateStatusWithRetry(ctx, akamaiProperty); statusErr != nil && err == nil {
+			err = statusErr
+		}
+	}
+	return err
+}
+
+// unusedEdgeHostnames returns the edge hostnames no hostname is CNAMEd to
+func unusedEdgeHostnames(edgeHostnames []string, hostnames []akamaiV1alpha1.Hostname) []string {
+	var unused []string
+	for _, edgeHostname := range edgeHostnames {
+		if !slices.ContainsFunc(hostnames, func(h akamaiV1alpha1.Hostname) bool { return h.CNAMETo == edgeHostname }) {
+			unused = append(unused, edgeHostname)
+		}
+	}
+	return unused
+}
+
+// resolveEdgeHostnameRefs sets cnameTo of the hostnames referencing an AkamaiEdgeHostname to its
+// domain. Like the account defaults, the resolved targets are only applied in memory.
+func (r *AkamaiPropertyReconciler) resolveEdgeHostnameRefs(ctx context.Context, akamaiProperty *akamaiV1alpha1.AkamaiProperty) error {
+	for i := range akamaiProperty.Spec.Hostnames {
+		hostname := &akamaiProperty.Spec.Hostnames[i]
+		switch {
+		case hostname.EdgeHostnameRef == "" && hostname.CNAMETo == "":
+			return fmt.Errorf("hostname %s needs cnameTo or edgeHostnameRef", hostname.CNAMEFrom)
+		case hostname.EdgeHostnameRef == "":
+			continue
+		case hostname.CNAMETo != "":
+			return fmt.Errorf("hostname %s sets both cnameTo and edgeHostnameRef", hostname.CNAMEFrom)
+		}
+
+		var edgeHostname akamaiV1alpha1.AkamaiEdgeHostname
+		if err := r.Get(ctx, client.ObjectKey{Name: hostname.EdgeHostnameRef}, &edgeHostname); err != nil {
+			if apierrors.IsNotFound(err) {
+				return fmt.Errorf("AkamaiEdgeHostname %q of hostname %s not found", hostname.EdgeHostnameRef, hostname.CNAMEFrom)
+			}
+			return fmt.Errorf("failed to get AkamaiEdgeHostname %q: %w", hostname.EdgeHostnameRef, err)
+		}
+		if edgeHostname.Status.EdgeHostnameID == "" || edgeHostname.Status.Domain == "" {
+			return fmt.Errorf("AkamaiEdgeHostname %q has not been created in Akamai yet", hostname.EdgeHostnameRef)
+		}
+		hostname.CNAMETo = edgeHostname.Status.Domain
+	}
+	return nil
+}
+
+// edgeHostnameRefs returns the names of the AkamaiEdgeHostnames the hostnames of a property reference
+func edgeHostnameRefs(akamaiProperty *akamaiV1alpha1.AkamaiProperty) map[string]bool {
+	refs := map[string]bool{}
+	for _, h := range akamaiProperty.Spec.Hostnames {
+		if h.EdgeHostnameRef != "" {
+			refs[h.EdgeHostnameRef] = true
+		}
+	}
+	return refs
+}
+
+// propertiesReferencingEdgeHostname enqueues the properties referencing a changed
+// AkamaiEdgeHostname, so their hostnames are written once the edge hostname is created in Akamai
+func (r *AkamaiPropertyReconciler) propertiesReferencingEdgeHostname(ctx context.Context, obj client.Object) []reconcile.Request {
+	var properties akamaiV1alpha1.AkamaiPropertyList
+	if err := r.List(ctx, &properties); err != nil {
+		return nil
+	}
+	var requests []reconcile.Request
+	for i := range properties.Items {
+		if edgeHostnameRefs(&properties.Items[i])[obj.GetName()] {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&properties.Items[i])})
+		}
+	}
+	return requests
+}
+
+// appendMissing appends the values not yet contained in list
+func appendMissing(list []string, values ...string) []string {
+	for _, value := range values {
+		found := false
+		for _, existing := range list {
+			if existing == value {
+				found = true
+				break
+			}
+		}
+		if !found {
+			list = append(list, value)
+		}
+	}
+	return list
+}
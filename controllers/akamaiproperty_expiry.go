@@ -0,0 +1,125 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+)
+
+// expiryDeadline returns when a temporary property expires: spec.expiresAt, or spec.ttl after
+// the creation of the resource. It returns nil for properties that don't expire.
+func expiryDeadline(akamaiProperty *akamaiV1alpha1.AkamaiProperty) *metav1.Time {
+	switch {
+	case akamaiProperty.Spec.ExpiresAt != nil:
+		return akamaiProperty.Spec.ExpiresAt.DeepCopy()
+	case akamaiProperty.Spec.TTL != nil:
+		deadline := metav1.NewTime(akamaiProperty.CreationTimestamp.Add(akamaiProperty.Spec.TTL.Duration))
+		return &deadline
+	default:
+		return nil
+	}
+}
+
+// handleExpiry deletes the resource of a temporary property once its deadline has passed, which
+// deactivates and deletes the Akamai property through the finalizer. Within the warning period
+// before the deadline the Expiring condition is set and a warning event emitted once. Dry runs and
+// observe-only mode don't delete an expired property; the Expiring condition reports the pending
+// expiry instead. It reports whether the resource was deleted.
+func (r *AkamaiPropertyReconciler) handleExpiry(ctx context.Context, akamaiProperty *akamaiV1alpha1.AkamaiProperty, now time.Time) (bool, error) {
+	logger := log.FromContext(ctx)
+	deadline := expiryDeadline(akamaiProperty)
+	changed := !deadline.Equal(akamaiProperty.Status.ExpiresAt)
+	akamaiProperty.Status.ExpiresAt = deadline
+
+	switch {
+	case deadline == nil:
+		if meta.RemoveStatusCondition(&akamaiProperty.Status.Conditions, ConditionTypeExpiring) {
+			changed = true
+		}
+	case !now.Before(deadline.Time) && r.readOnly(akamaiProperty):
+		// Deleting the resource would leave the Akamai property live but unmanaged, since the
+		// finalizer doesn't touch Akamai in read-only mode
+		if condition := meta.FindStatusCondition(akamaiProperty.Status.Conditions, ConditionTypeExpiring); condition == nil || condition.Reason != ReasonExpired {
+			message := fmt.Sprintf("Property expired at %s; it is not deleted in dry run or observe-only mode",
+				deadline.UTC().Format(time.RFC3339))
+			meta.SetStatusCondition(&akamaiProperty.Status.Conditions, metav1.Condition{
+				Type:               ConditionTypeExpiring,
+				Status:             metav1.ConditionTrue,
+				Reason:             ReasonExpired,
+				Message:            message,
+				ObservedGeneration: akamaiProperty.Generation,
+			})
+			r.recordEvent(akamaiProperty, corev1.EventTypeWarning, ReasonExpired, "Expire", message)
+			changed = true
+		}
+	case !now.Before(deadline.Time):
+		logger.Info("Temporary property expired, deleting it", "expiresAt", deadline.Time)
+		r.recordEvent(akamaiProperty, corev1.EventTypeWarning, ReasonExpired, "Delete",
+			"Property expired at %s, deleting it with deletion policy %s", deadline.UTC().Format(time.RFC3339), deletionPolicy(akamaiProperty))
+		if err := r.Delete(ctx, akamaiProperty); client.IgnoreNotFound(err) != nil {
+			return false, fmt.Errorf("failed to delete expired property: %w", err)
+		}
+		return true, nil
+	case deadline.Sub(now) <= r.expiryWarning():
+		if condition := meta.FindStatusCondition(akamaiProperty.Status.Conditions, ConditionTypeExpiring); condition == nil || condition.Reason != ReasonExpiringSoon {
+			message := fmt.Sprintf("Property expires at %s and will be deleted with deletion policy %s",
+				deadline.UTC().Format(time.RFC3339), deletionPolicy(akamaiProperty))
+			meta.SetStatusCondition(&akamaiProperty.Status.Conditions, metav1.Condition{
+				Type:               ConditionTypeExpiring,
+				Status:             metav1.ConditionTrue,
+				Reason:             ReasonExpiringSoon,
+				Message:            message,
+				ObservedGeneration: akamaiProperty.Generation,
+			})
+			r.recordEvent(akamaiProperty, corev1.EventTypeWarning, ReasonExpiringSoon, "Expire", message)
+			changed = true
+		}
+	default:
+		// The deadline moved out of the warning period again
+		if meta.RemoveStatusCondition(&akamaiProperty.Status.Conditions, ConditionTypeExpiring) {
+			changed = true
+		}
+	}
+
+	if !changed {
+		return false, nil
+	}
+	return false, r.updateStatusWithRetry(ctx, akamaiProperty)
+}
+
+// expiryRequeue shortens the requeue of the result so the property is reconciled again when its
+// expiry warning is due and when it expires. Expired properties kept in read-only mode are left
+// to the regular requeue.
+func (r *AkamaiPropertyReconciler) expiryRequeue(akamaiProperty *akamaiV1alpha1.AkamaiProperty, result ctrl.Result, now time.Time) ctrl.Result {
+	deadline := expiryDeadline(akamaiProperty)
+	if deadline == nil || !now.Before(deadline.Time) {
+		return result
+	}
+	next := deadline.Sub(now)
+	if warning := next - r.expiryWarning(); warning > 0 {
+		next = warning
+	}
+	// Round up so the deadline has passed when the property is reconciled again
+	next = next.Truncate(time.Second) + time.Second
+	if result.RequeueAfter == 0 || next < result.RequeueAfter {
+		result.RequeueAfter = next
+	}
+	return result
+}
+
+// expiryWarning returns how long before its expiry a temporary property is warned about
+func (r *AkamaiPropertyReconciler) expiryWarning() time.Duration {
+	if r.ExpiryWarning > 0 {
+		return r.ExpiryWarning
+	}
+	return 24 * time.Hour
+}
@@ -0,0 +1,48 @@
+package controllers
+
+import (
+	"errors"
+	"strings"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+	"github.com/mmz-srf/akamai-operator/pkg/akamai"
+)
+
+// versionNotesManagedByMarker is the version notes line identifying versions written by the operator
+const versionNotesManagedByMarker = "managed-by: akamai-operator"
+
+// errForeignVersion signals that the Refuse foreign version policy keeps the operator from writing
+// to a version created by another tool
+var errForeignVersion = errors.New("latest property version was created outside the operator")
+
+// foreignVersionPolicy returns the configured foreign version policy, defaulting to Overwrite
+func foreignVersionPolicy(akamaiProperty *akamaiV1alpha1.AkamaiProperty) akamaiV1alpha1.ForeignVersionPolicy {
+	if akamaiProperty.Spec.ForeignVersionPolicy == "" {
+		return akamaiV1alpha1.ForeignVersionPolicyOverwrite
+	}
+	return akamaiProperty.Spec.ForeignVersionPolicy
+}
+
+// foreignVersionGuardEnabled reports whether versions are stamped with the managed-by marker and
+// checked for it. The Manual version strategy relies on versions created outside the operator,
+// so the guard does not apply to it.
+func foreignVersionGuardEnabled(akamaiProperty *akamaiV1alpha1.AkamaiProperty) bool {
+	return foreignVersionPolicy(akamaiProperty) != akamaiV1alpha1.ForeignVersionPolicyOverwrite &&
+		versionStrategy(akamaiProperty) != akamaiV1alpha1.VersionStrategyManual
+}
+
+// hasManagedByMarker reports whether version notes carry the operator's managed-by marker
+func hasManagedByMarker(notes string) bool {
+	for _, line := range strings.Split(notes, "\n") {
+		if strings.TrimSpace(line) == versionNotesManagedByMarker {
+			return true
+		}
+	}
+	return false
+}
+
+// isForeignVersion reports whether the guard should keep the operator from writing to a version.
+// Published versions are never written to, so only unpublished drafts can be foreign.
+func isForeignVersion(akamaiProperty *akamaiV1alpha1.AkamaiProperty, version *akamai.PropertyVersion) bool {
+	return foreignVersionGuardEnabled(akamaiProperty) && !version.IsPublished() && !hasManagedByMarker(version.Note)
+}
@@ -0,0 +1,149 @@
+package controllers
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+)
+
+// suspendedBy returns what suspends the reconciliation of the property: spec.suspend, or the
+// akamai.com/suspend or akamai.com/paused annotation. It returns "" if reconciliation isn't suspended.
+func suspendedBy(akamaiProperty *akamaiV1alpha1.AkamaiProperty) string {
+	if akamaiProperty.Spec.Suspend {
+		return "spec.suspend"
+	}
+	for _, annotation := range []string{AnnotationSuspend, AnnotationPaused} {
+		if strings.EqualFold(strings.TrimSpace(akamaiProperty.Annotations[annotation]), "true") {
+			return fmt.Sprintf("the %s annotation", annotation)
+		}
+	}
+	return ""
+}
+
+// activationsFrozen reports whether the akamai.com/freeze-activations annotation holds back the
+// activations and promotions of the property
+func activationsFrozen(akamaiProperty *akamaiV1alpha1.AkamaiProperty) bool {
+	return strings.EqualFold(strings.TrimSpace(akamaiProperty.Annotations[AnnotationFreezeActivations]), "true")
+}
+
+// requestedReconcile returns the value of a reconcile.fluxcd.io/requestedAt annotation that has
+// not been handled yet
+func requestedReconcile(akamaiProperty *akamaiV1alpha1.AkamaiProperty) (string, bool) {
+	requestedAt, ok := akamaiProperty.Annotations[AnnotationReconcileRequestedAt]
+	if !ok || requestedAt == akamaiProperty.Status.LastHandledReconcileAt {
+		return "", false
+	}
+	return requestedAt, true
+}
+
+// forgetValidation makes the next reconcile validate the spec again, including the checks that
+// read from Akamai, instead of trusting the result recorded for the generation
+func forgetValidation(akamaiProperty *akamaiV1alpha1.AkamaiProperty) {
+	akamaiProperty.Status.ValidatedGeneration = 0
+	meta.RemoveStatusCondition(&akamaiProperty.Status.Conditions, ConditionTypeInvalidSpec)
+}
+
+// stalledReason returns why the property can't make progress without user intervention, or "".
+// A generation that exceeded its progress deadline counts as stalled until it becomes Ready.
+func stalledReason(akamaiProperty *akamaiV1alpha1.AkamaiProperty) (string, string) {
+	for _, conditionType := range []string{ConditionTypeInvalidSpec, ConditionTypePermissionsInsufficient} {
+		if condition := meta.FindStatusCondition(akamaiProperty.Status.Conditions, conditionType); condition != nil && condition.Status == metav1.ConditionTrue {
+			return conditionType, condition.Message
+		}
+	}
+	if condition := progressDeadlineExceeded(akamaiProperty); condition != nil {
+		return condition.Reason, condition.Message
+	}
+	return "", ""
+}
+
+// setHealthConditions derives the Reconciling and Stalled conditions of the kstatus convention
+// from the phase and conditions, so Flux, kstatus and Argo CD can assess health without custom
+// checks. It reports whether a condition changed.
+func setHealthConditions(akamaiProperty *akamaiV1alpha1.AkamaiProperty) bool {
+	reconciling := metav1.Condition{
+		Type:               ConditionTypeReconciling,
+		Status:             metav1.ConditionFalse,
+		Reason:             "ReconcileSucceeded",
+		ObservedGeneration: akamaiProperty.Generation,
+	}
+	stalled := metav1.Condition{
+		Type:               ConditionTypeStalled,
+		Status:             metav1.ConditionFalse,
+		Reason:             "NotStalled",
+		ObservedGeneration: akamaiProperty.Generation,
+	}
+
+	if reason, message := stalledReason(akamaiProperty); reason != "" {
+		stalled.Status = metav1.ConditionTrue
+		stalled.Reason = reason
+		stalled.Message = message
+		reconciling.Reason = "Stalled"
+	} else {
+		switch akamaiProperty.Status.Phase {
+		case PhaseReady:
+		case PhaseSuspended:
+			reconciling.Reason = PhaseSuspended
+		default:
+			// Errors are retried, so the property is still reconciling towards its spec
+			reconciling.Status = metav1.ConditionTrue
+			reconciling.Reason = "Progressing"
+			if ready := meta.FindStatusCondition(akamaiProperty.Status.Conditions, ConditionTypeReady); ready != nil {
+				reconciling.Reason = ready.Reason
+				reconciling.Message = ready.Message
+			}
+		}
+	}
+
+	changed := meta.SetStatusCondition(&akamaiProperty.Status.Conditions, reconciling)
+	if meta.SetStatusCondition(&akamaiProperty.Status.Conditions, stalled) {
+		changed = true
+	}
+	return changed
+}
+
+// staleReady reports whether the Ready condition is True for a generation the operator has not
+// finished reconciling, e.g. right after a spec edit
+func staleReady(akamaiProperty *akamaiV1alpha1.AkamaiProperty) bool {
+	ready := meta.FindStatusCondition(akamaiProperty.Status.Conditions, ConditionTypeReady)
+	return ready != nil && ready.Status == metav1.ConditionTrue &&
+		akamaiProperty.Status.ObservedGeneration != akamaiProperty.Generation
+}
+
+// setReadyGeneration turns a stale Ready condition False with reason NewGeneration until
+// status.observedGeneration catches up with metadata.generation, so that
+// `kubectl wait --for=condition=Ready` waits for a spec edit to be applied. It reports whether
+// the condition changed.
+func setReadyGeneration(akamaiProperty *akamaiV1alpha1.AkamaiProperty) bool {
+	if !staleReady(akamaiProperty) {
+		return false
+	}
+	return meta.SetStatusCondition(&akamaiProperty.Status.Conditions, metav1.Condition{
+		Type:               ConditionTypeReady,
+		Status:             metav1.ConditionFalse,
+		Reason:             ReasonNewGeneration,
+		Message:            fmt.Sprintf("Generation %d is not reconciled yet", akamaiProperty.Generation),
+		ObservedGeneration: akamaiProperty.Generation,
+	})
+}
+
+// setDerivedConditions refreshes the conditions aggregated from the rest of the status and
+// reports whether one of them changed
+func setDerivedConditions(akamaiProperty *akamaiV1alpha1.AkamaiProperty) bool {
+	changed := setReadyGeneration(akamaiProperty)
+	if setProgressCondition(akamaiProperty, time.Now()) {
+		changed = true
+	}
+	if setHealthConditions(akamaiProperty) {
+		changed = true
+	}
+	if setSummaryCondition(akamaiProperty) {
+		changed = true
+	}
+	return changed
+}
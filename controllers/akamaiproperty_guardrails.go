@@ -0,0 +1,127 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+	"github.com/mmz-srf/akamai-operator/pkg/akamai"
+	"github.com/mmz-srf/akamai-operator/pkg/lint"
+)
+
+// guardrailNames returns the guardrails checked before a production activation of the property:
+// the operator-wide guardrails and those of spec.activation.guardrails, sorted and de-duplicated
+func guardrailNames(operatorGuardrails []string, akamaiProperty *akamaiV1alpha1.AkamaiProperty) []string {
+	seen := make(map[string]bool)
+	var names []string
+	add := func(name string) {
+		if name != "" && !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	for _, name := range operatorGuardrails {
+		add(name)
+	}
+	if akamaiProperty.Spec.Activation != nil {
+		for _, name := range akamaiProperty.Spec.Activation.Guardrails {
+			add(name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// guardrailID identifies a guardrail finding of a version in activation.acknowledgeGuardrails
+func guardrailID(guardrail string, version int) string {
+	return fmt.Sprintf("%s/v%d", guardrail, version)
+}
+
+// pendingGuardrails returns the findings of version that are not acknowledged in
+// spec.activation.acknowledgeGuardrails
+func pendingGuardrails(akamaiProperty *akamaiV1alpha1.AkamaiProperty, findings []lint.GuardFinding, version int) []akamaiV1alpha1.PendingGuardrail {
+	acknowledged := make(map[string]bool)
+	if akamaiProperty.Spec.Activation != nil {
+		for _, id := range akamaiProperty.Spec.Activation.AcknowledgeGuardrails {
+			acknowledged[id] = true
+		}
+	}
+
+	var pending []akamaiV1alpha1.PendingGuardrail
+	for _, finding := range findings {
+		id := guardrailID(finding.Guardrail, version)
+		if acknowledged[id] {
+			continue
+		}
+		pending = append(pending, akamaiV1alpha1.PendingGuardrail{ID: id, Path: finding.Path, Message: finding.Message})
+	}
+	return pending
+}
+
+// unacknowledgedGuardrails runs the guardrails against the rules of the version about to be
+// activated on production, compared with the version active there, and returns the findings that
+// are not acknowledged
+func unacknowledgedGuardrails(ctx context.Context, akamaiClient *akamai.Client, names []string, akamaiProperty *akamaiV1alpha1.AkamaiProperty, version int) ([]akamaiV1alpha1.PendingGuardrail, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+	logger := log.FromContext(ctx)
+
+	rules, err := akamaiClient.GetPropertyRules(ctx, akamaiProperty.Status.PropertyID, version, akamaiProperty.Spec.ContractID, akamaiProperty.Spec.GroupID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rules of version %d: %w", version, err)
+	}
+	var active interface{}
+	if activeVersion := akamaiProperty.Status.ProductionVersion; activeVersion > 0 && activeVersion != version {
+		activeRules, err := akamaiClient.GetPropertyRules(ctx, akamaiProperty.Status.PropertyID, activeVersion, akamaiProperty.Spec.ContractID, akamaiProperty.Spec.GroupID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get rules of active version %d: %w", activeVersion, err)
+		}
+		active = activeRules.Rules
+	}
+
+	findings := lint.Guard(names, rules.Rules, active)
+	for _, finding := range findings {
+		logger.Info("Guardrail finding", "guardrail", finding.Guardrail, "path", finding.Path, "version", version)
+	}
+	return pendingGuardrails(akamaiProperty, findings, version), nil
+}
+
+// guardrailIDs returns the IDs to acknowledge the pending guardrails with
+func guardrailIDs(pending []akamaiV1alpha1.PendingGuardrail) []string {
+	ids := make([]string, 0, len(pending))
+	for _, guardrail := range pending {
+		ids = append(ids, guardrail.ID)
+	}
+	return ids
+}
+
+// checkGuardrails runs the guardrails against the rendered rules of the version about to be
+// activated on production. Findings that are not acknowledged are recorded in
+// status.pendingGuardrails and the PendingAcknowledgement condition, and block the activation.
+func (r *AkamaiPropertyReconciler) checkGuardrails(ctx context.Context, akamaiProperty *akamaiV1alpha1.AkamaiProperty, version int) (bool, error) {
+	pending, err := unacknowledgedGuardrails(ctx, r.AkamaiClient, guardrailNames(r.Guardrails, akamaiProperty), akamaiProperty, version)
+	if err != nil || len(pending) == 0 {
+		return false, err
+	}
+
+	ids := guardrailIDs(pending)
+	akamaiProperty.Status.PendingGuardrails = pending
+	meta.SetStatusCondition(&akamaiProperty.Status.Conditions, metav1.Condition{
+		Type:               ConditionTypePendingAcknowledgement,
+		Status:             metav1.ConditionTrue,
+		Reason:             "GuardrailsNotAcknowledged",
+		Message:            fmt.Sprintf("Add to activation.acknowledgeGuardrails: %s", strings.Join(ids, ", ")),
+		ObservedGeneration: akamaiProperty.Generation,
+	})
+	if err := r.updateStatusWithRetry(ctx, akamaiProperty); err != nil {
+		return false, fmt.Errorf("failed to record pending guardrails: %w", err)
+	}
+	return true, nil
+}
@@ -0,0 +1,153 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/types"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+)
+
+// errUndefinedVariables signals that the rule tree or its includes reference undeclared variables
+var errUndefinedVariables = errors.New("undefined variables")
+
+// userVariablePattern matches user variable references such as {{user.PMUSER_ORIGIN}}
+var userVariablePattern = regexp.MustCompile(`\{\{\s*user\.(PMUSER_[A-Za-z0-9_]+)\s*\}\}`)
+
+// variableUsage collects the variables a rule tree declares and references, and the includes it uses
+type variableUsage struct {
+	declared   map[string]bool
+	referenced map[string][]string
+	includes   []string
+}
+
+func newVariableUsage() *variableUsage {
+	return &variableUsage{
+		declared:   make(map[string]bool),
+		referenced: make(map[string][]string),
+	}
+}
+
+// addTree walks a rule tree, recording references under source
+func (u *variableUsage) addTree(tree interface{}, source string) error {
+	raw, err := json.Marshal(tree)
+	if err != nil {
+		return fmt.Errorf("failed to marshal rules of %s: %w", source, err)
+	}
+	var node interface{}
+	if err := json.Unmarshal(raw, &node); err != nil {
+		return fmt.Errorf("failed to unmarshal rules of %s: %w", source, err)
+	}
+	u.walk(node, source)
+	return nil
+}
+
+func (u *variableUsage) walk(node interface{}, source string) {
+	switch value := node.(type) {
+	case map[string]interface{}:
+		if variables, ok := value["variables"].([]interface{}); ok {
+			for _, item := range variables {
+				if variable, ok := item.(map[string]interface{}); ok {
+					if name, ok := variable["name"].(string); ok {
+						u.declared[name] = true
+					}
+				}
+			}
+		}
+		if value["name"] == "include" {
+			if options, ok := value["options"].(map[string]interface{}); ok {
+				if id, ok := options["id"].(string); ok && id != "" && !containsString(u.includes, id) {
+					u.includes = append(u.includes, id)
+				}
+			}
+		}
+		for key, child := range value {
+			if key == "variables" {
+				continue
+			}
+			// setVariable behaviors and matchVariable criteria name the variable without braces
+			if name, ok := child.(string); ok && key == "variableName" && strings.HasPrefix(name, "PMUSER_") {
+				u.reference(name, source)
+				continue
+			}
+			u.walk(child, source)
+		}
+	case []interface{}:
+		for _, child := range value {
+			u.walk(child, source)
+		}
+	case string:
+		for _, match := range userVariablePattern.FindAllStringSubmatch(value, -1) {
+			u.reference(match[1], source)
+		}
+	}
+}
+
+func (u *variableUsage) reference(name, source string) {
+	if !containsString(u.referenced[name], source) {
+		u.referenced[name] = append(u.referenced[name], source)
+	}
+}
+
+// missing lists the referenced but undeclared variables with where they are used, sorted by name
+func (u *variableUsage) missing() []string {
+	var missing []string
+	for name, sources := range u.referenced {
+		if !u.declared[name] {
+			missing = append(missing, fmt.Sprintf("%s (used in %s)", name, strings.Join(sources, ", ")))
+		}
+	}
+	sort.Strings(missing)
+	return missing
+}
+
+// checkIncludeVariables verifies that every variable referenced by the rule tree or the includes
+// it uses is declared in rules.variables, spec.variables or one of the includes. Includes are
+// read from Akamai, so errors other than errUndefinedVariables are transient.
+func (r *AkamaiPropertyReconciler) checkIncludeVariables(ctx context.Context, akamaiProperty *akamaiV1alpha1.AkamaiProperty) error {
+	if akamaiProperty.Spec.Rules == nil {
+		return nil
+	}
+
+	usage := newVariableUsage()
+	for _, variable := range akamaiProperty.Spec.Variables {
+		usage.declared[variable.Name] = true
+	}
+	if err := usage.addTree(akamaiProperty.Spec.Rules, "rules"); err != nil {
+		return err
+	}
+
+	// Includes don't nest, so only the includes of the property's own rule tree are read
+	includes := append([]string(nil), usage.includes...)
+	for _, includeID := range includes {
+		// Includes managed by an AkamaiPropertyInclude are checked with the rules of its spec
+		if match := includeRefPattern.FindStringSubmatch(includeID); match != nil {
+			var include akamaiV1alpha1.AkamaiPropertyInclude
+			if err := r.Get(ctx, types.NamespacedName{Name: match[1]}, &include); err != nil {
+				return fmt.Errorf("failed to get AkamaiPropertyInclude %q: %w", match[1], err)
+			}
+			if err := usage.addTree(&include.Spec.Rules, "include "+match[1]); err != nil {
+				return err
+			}
+			continue
+		}
+		includeRules, err := r.AkamaiClient.GetIncludeRules(ctx, includeID, akamaiProperty.Spec.ContractID, akamaiProperty.Spec.GroupID)
+		if err != nil {
+			return err
+		}
+		if err := usage.addTree(includeRules.Rules, "include "+includeID); err != nil {
+			return err
+		}
+	}
+
+	if missing := usage.missing(); len(missing) > 0 {
+		return fmt.Errorf("%w: %s", errUndefinedVariables, strings.Join(missing, "; "))
+	}
+	return nil
+}
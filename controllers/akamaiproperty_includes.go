@@ -0,0 +1,142 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sync"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+)
+
+// includeRefPattern matches a reference like ${include:security-headers} to the include of an
+// AkamaiPropertyInclude, used as the id of an include behavior
+var includeRefPattern = regexp.MustCompile(`\$\{include:([a-z0-9]([-.a-z0-9]*[a-z0-9])?)\}`)
+
+// resolveIncludeRefs replaces the include references in the behavior and criterion options of the
+// rule tree with the IDs of the includes. Rules without references are returned as they are.
+func (r *AkamaiPropertyReconciler) resolveIncludeRefs(ctx context.Context, rules *akamaiV1alpha1.PropertyRules) (*akamaiV1alpha1.PropertyRules, error) {
+	if rules == nil {
+		return nil, nil
+	}
+	tree, err := ruleTree(rules)
+	if err != nil {
+		return nil, err
+	}
+	found, err := walkOptionStrings(tree, func(value string) (string, error) {
+		var resolveErr error
+		resolved := includeRefPattern.ReplaceAllStringFunc(value, func(ref string) string {
+			includeID, err := r.includeID(ctx, includeRefPattern.FindStringSubmatch(ref)[1])
+			if err != nil && resolveErr == nil {
+				resolveErr = err
+			}
+			return includeID
+		})
+		return resolved, resolveErr
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve include references: %w", err)
+	}
+	if !found {
+		return rules, nil
+	}
+
+	raw, err := json.Marshal(tree)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal resolved rules: %w", err)
+	}
+	var resolved akamaiV1alpha1.PropertyRules
+	if err := json.Unmarshal(raw, &resolved); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal resolved rules: %w", err)
+	}
+	return &resolved, nil
+}
+
+// includeID returns the Akamai ID of the include of an AkamaiPropertyInclude
+func (r *AkamaiPropertyReconciler) includeID(ctx context.Context, name string) (string, error) {
+	var include akamaiV1alpha1.AkamaiPropertyInclude
+	if err := r.Get(ctx, types.NamespacedName{Name: name}, &include); err != nil {
+		if apierrors.IsNotFound(err) {
+			return "", fmt.Errorf("AkamaiPropertyInclude %q not found", name)
+		}
+		return "", fmt.Errorf("failed to get AkamaiPropertyInclude %q: %w", name, err)
+	}
+	if include.Status.IncludeID == "" {
+		return "", fmt.Errorf("AkamaiPropertyInclude %q has not been created in Akamai yet", name)
+	}
+	return include.Status.IncludeID, nil
+}
+
+// includeRefs returns the names of the AkamaiPropertyIncludes a rule tree references
+func includeRefs(rules *akamaiV1alpha1.PropertyRules) map[string]bool {
+	refs := map[string]bool{}
+	if rules == nil {
+		return refs
+	}
+	tree, err := ruleTree(rules)
+	if err != nil {
+		return refs
+	}
+	_, _ = walkOptionStrings(tree, func(value string) (string, error) {
+		for _, match := range includeRefPattern.FindAllStringSubmatch(value, -1) {
+			refs[match[1]] = true
+		}
+		return value, nil
+	})
+	return refs
+}
+
+// includeUsers remembers the includes referenced by the rendered rule tree of each property,
+// which may come from a renderer instead of spec.rules. References only exist until they are
+// resolved, so they are kept in memory; every property is rendered again after a restart.
+type includeUsers struct {
+	mu   sync.Mutex
+	refs map[string]map[string]bool
+}
+
+// record remembers the includes the rule tree rendered for a property references
+func (u *includeUsers) record(property string, refs map[string]bool) {
+	if u == nil {
+		return
+	}
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if u.refs == nil {
+		u.refs = make(map[string]map[string]bool)
+	}
+	u.refs[property] = refs
+}
+
+// references reports whether the rule tree last rendered for a property references an include
+func (u *includeUsers) references(property, include string) bool {
+	if u == nil {
+		return false
+	}
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return u.refs[property][include]
+}
+
+// propertiesIncluding enqueues the properties referencing a changed AkamaiPropertyInclude, so
+// they are written once the include is created in Akamai. Properties not rendered yet are
+// matched by the references in spec.rules.
+func (r *AkamaiPropertyReconciler) propertiesIncluding(ctx context.Context, obj client.Object) []reconcile.Request {
+	var properties akamaiV1alpha1.AkamaiPropertyList
+	if err := r.List(ctx, &properties); err != nil {
+		return nil
+	}
+	var requests []reconcile.Request
+	for i := range properties.Items {
+		property := &properties.Items[i]
+		if r.includeUsers.references(property.Name, obj.GetName()) || includeRefs(property.Spec.Rules)[obj.GetName()] {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(property)})
+		}
+	}
+	return requests
+}
@@ -0,0 +1,80 @@
+package controllers
+
+import (
+	"sort"
+	"strings"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+)
+
+// versionNotesLabelsPrefix prefixes the label block written into the property version notes
+const versionNotesLabelsPrefix = "labels: "
+
+// renderVersionNotes renders the property version notes from spec.description, spec.metadata
+// and the labels selected by spec.syncLabels, separated by blank lines, followed by the
+// managed-by marker when the foreign version guard is enabled. Metadata and labels are sorted
+// by key so the notes are stable across reconciles. Returns an empty string when none of them
+// is set, in which case the operator leaves the version notes alone.
+func renderVersionNotes(akamaiProperty *akamaiV1alpha1.AkamaiProperty) string {
+	var blocks []string
+
+	if description := strings.TrimSpace(akamaiProperty.Spec.Description); description != "" {
+		blocks = append(blocks, description)
+	}
+	if metadata := renderMetadata(akamaiProperty.Spec.Metadata); metadata != "" {
+		blocks = append(blocks, metadata)
+	}
+	if labels := renderSyncedLabels(akamaiProperty); labels != "" {
+		blocks = append(blocks, labels)
+	}
+	if foreignVersionGuardEnabled(akamaiProperty) {
+		blocks = append(blocks, versionNotesManagedByMarker)
+	}
+
+	return strings.Join(blocks, "\n\n")
+}
+
+// renderMetadata renders custom metadata fields as sorted "key: value" lines
+func renderMetadata(metadata map[string]string) string {
+	if len(metadata) == 0 {
+		return ""
+	}
+
+	keys := make([]string, 0, len(metadata))
+	for key := range metadata {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	lines := make([]string, 0, len(keys))
+	for _, key := range keys {
+		lines = append(lines, key+": "+metadata[key])
+	}
+	return strings.Join(lines, "\n")
+}
+
+// renderSyncedLabels renders the labels selected by spec.syncLabels on a single line
+func renderSyncedLabels(akamaiProperty *akamaiV1alpha1.AkamaiProperty) string {
+	if len(akamaiProperty.Spec.SyncLabels) == 0 {
+		return ""
+	}
+
+	keys := make([]string, 0, len(akamaiProperty.Spec.SyncLabels))
+	seen := make(map[string]bool)
+	for _, key := range akamaiProperty.Spec.SyncLabels {
+		if _, ok := akamaiProperty.Labels[key]; ok && !seen[key] {
+			keys = append(keys, key)
+			seen[key] = true
+		}
+	}
+	if len(keys) == 0 {
+		return ""
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, key := range keys {
+		pairs = append(pairs, key+"="+akamaiProperty.Labels[key])
+	}
+	return versionNotesLabelsPrefix + strings.Join(pairs, "; ")
+}
@@ -0,0 +1,20 @@
+package controllers
+
+import (
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+)
+
+// managesHostnames reports whether the operator manages the hostnames of the property
+func managesHostnames(akamaiProperty *akamaiV1alpha1.AkamaiProperty) bool {
+	return akamaiProperty.Spec.Manage == nil || managedByDefault(akamaiProperty.Spec.Manage.Hostnames)
+}
+
+// managesActivation reports whether the operator activates versions of the property
+func managesActivation(akamaiProperty *akamaiV1alpha1.AkamaiProperty) bool {
+	return akamaiProperty.Spec.Manage == nil || managedByDefault(akamaiProperty.Spec.Manage.Activation)
+}
+
+// managedByDefault returns the value of a spec.manage switch, true when it isn't set
+func managedByDefault(manage *bool) bool {
+	return manage == nil || *manage
+}
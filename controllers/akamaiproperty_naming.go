@@ -0,0 +1,73 @@
+package controllers
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+)
+
+// namePlaceholderPattern matches the {placeholder} tokens of a naming template
+var namePlaceholderPattern = regexp.MustCompile(`\{([^{}]+)\}`)
+
+// invalidNameChars matches characters not allowed in generated Akamai object names
+var invalidNameChars = regexp.MustCompile(`[^a-z0-9.-]+`)
+
+// renderNameTemplate renders an operator naming template such as "{team}-{env}-{property}" for a
+// property. {property} is spec.propertyName, {name} the resource name, and any other placeholder
+// the value of the resource label with that key. The result is lowercased and characters that
+// are not valid in a hostname are replaced with dashes.
+func renderNameTemplate(template string, akamaiProperty *akamaiV1alpha1.AkamaiProperty) (string, error) {
+	var missing []string
+	rendered := namePlaceholderPattern.ReplaceAllStringFunc(template, func(token string) string {
+		key := strings.TrimSpace(token[1 : len(token)-1])
+		switch key {
+		case "property":
+			return akamaiProperty.Spec.PropertyName
+		case "name":
+			return akamaiProperty.Name
+		}
+		value, ok := akamaiProperty.Labels[key]
+		if !ok || value == "" {
+			missing = append(missing, key)
+		}
+		return value
+	})
+	if len(missing) > 0 {
+		return "", fmt.Errorf("naming template %q needs the labels %s", template, strings.Join(missing, ", "))
+	}
+
+	name := invalidNameChars.ReplaceAllString(strings.ToLower(rendered), "-")
+	name = strings.Trim(name, "-.")
+	if name == "" {
+		return "", fmt.Errorf("naming template %q renders an empty name", template)
+	}
+	return name, nil
+}
+
+// edgeHostnameSpec returns the edge hostname configuration to create edge hostnames with, rendering
+// the domain prefix from the operator's edge hostname template when the spec leaves it empty
+func (r *AkamaiPropertyReconciler) edgeHostnameSpec(akamaiProperty *akamaiV1alpha1.AkamaiProperty) (*akamaiV1alpha1.EdgeHostnameSpec, error) {
+	return renderEdgeHostnameSpec(r.EdgeHostnameTemplate, akamaiProperty)
+}
+
+// renderEdgeHostnameSpec renders the edge hostname configuration of the property with the given
+// edge hostname template
+func renderEdgeHostnameSpec(template string, akamaiProperty *akamaiV1alpha1.AkamaiProperty) (*akamaiV1alpha1.EdgeHostnameSpec, error) {
+	spec := akamaiProperty.Spec.EdgeHostname
+	if spec == nil || spec.DomainPrefix != "" {
+		return spec, nil
+	}
+	if template == "" {
+		return nil, fmt.Errorf("edgeHostname.domainPrefix is empty and no edge hostname template is configured")
+	}
+
+	prefix, err := renderNameTemplate(template, akamaiProperty)
+	if err != nil {
+		return nil, err
+	}
+	rendered := *spec
+	rendered.DomainPrefix = prefix
+	return &rendered, nil
+}
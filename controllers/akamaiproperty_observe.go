@@ -0,0 +1,49 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+	"github.com/mmz-srf/akamai-operator/pkg/akamai"
+)
+
+// observed completes the reconcile of a property in observe-only mode, summarizing how it
+// compares with Akamai instead of activating and recording the spec as applied
+func (r *AkamaiPropertyReconciler) observed(ctx context.Context, akamaiProperty *akamaiV1alpha1.AkamaiProperty) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	// Serving summary and edge endpoints only read Akamai
+	if err := r.updateServing(ctx, akamaiProperty); err != nil {
+		logger.Error(err, "Failed to update serving summary")
+	}
+	if err := r.publishEdgeEndpoints(ctx, akamaiProperty); err != nil {
+		logger.Error(err, "Failed to publish edge endpoints")
+		r.updateStatus(ctx, akamaiProperty, PhaseError, "FailedToPublishEdgeEndpoints", akamai.ErrorMessage(err))
+		return r.retryAfterError(akamaiProperty, err), nil
+	}
+
+	if clearPermissionsInsufficient(akamaiProperty) || akamaiProperty.Status.ObservedGeneration != akamaiProperty.Generation {
+		akamaiProperty.Status.ObservedGeneration = akamaiProperty.Generation
+		if err := r.updateStatusWithRetry(ctx, akamaiProperty); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	r.updateStatus(ctx, akamaiProperty, PhaseObserving, ReasonObserveOnly, observedMessage(akamaiProperty))
+	return ctrl.Result{RequeueAfter: time.Minute * 30}, nil
+}
+
+// observedMessage summarizes how a property observed without changes compares with Akamai
+func observedMessage(akamaiProperty *akamaiV1alpha1.AkamaiProperty) string {
+	if len(akamaiProperty.Status.Drift) == 0 {
+		return "Akamai matches the spec; observe-only mode makes no changes"
+	}
+	return fmt.Sprintf("The %s in Akamai differ from the spec; observe-only mode makes no changes",
+		strings.Join(akamaiProperty.Status.Drift, " and "))
+}
@@ -0,0 +1,170 @@
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"k8s.io/apimachinery/pkg/runtime"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+)
+
+// originsRuleName is the name of the child rule spec.origins is rendered into
+const originsRuleName = "Origins (managed by akamai-operator)"
+
+// originBucketVariable holds the random number that picks the origin of a request
+const originBucketVariable = "PMUSER_ORIGIN_BUCKET"
+
+// validateOrigins checks that spec.origins has unique origin names and at least one origin
+// receiving traffic
+func validateOrigins(akamaiProperty *akamaiV1alpha1.AkamaiProperty) error {
+	spec := akamaiProperty.Spec.Origins
+	if spec == nil {
+		return nil
+	}
+	if len(spec.Origins) == 0 {
+		return fmt.Errorf("origins: at least one origin is required")
+	}
+	seen := make(map[string]bool, len(spec.Origins))
+	var total int32
+	for _, origin := range spec.Origins {
+		if seen[origin.Name] {
+			return fmt.Errorf("origins: duplicate origin %q", origin.Name)
+		}
+		seen[origin.Name] = true
+		if origin.Hostname == "" {
+			return fmt.Errorf("origins: origin %q has no hostname", origin.Name)
+		}
+		if origin.Weight < 0 {
+			return fmt.Errorf("origins: origin %q has a negative weight", origin.Name)
+		}
+		total += origin.Weight
+	}
+	if total == 0 {
+		return fmt.Errorf("origins: every origin has weight 0, at least one must receive traffic")
+	}
+	if spec.Failover != nil && spec.Failover.Hostname == "" {
+		return fmt.Errorf("origins: failover has no hostname")
+	}
+	return nil
+}
+
+// applyOrigins renders spec.origins into rules: a child rule inserted as the first child of the
+// top-level rule. With several origins receiving traffic, each request draws a random number
+// between 1 and the sum of the weights and a nested rule per origin matches its range.
+func applyOrigins(rules *akamaiV1alpha1.PropertyRules, spec *akamaiV1alpha1.OriginsSpec) error {
+	var active []akamaiV1alpha1.WeightedOrigin
+	var total int32
+	for _, origin := range spec.Origins {
+		if origin.Weight > 0 {
+			active = append(active, origin)
+			total += origin.Weight
+		}
+	}
+
+	rule := map[string]interface{}{
+		"name":                originsRuleName,
+		"comments":            "Rendered from spec.origins; changes made here are overwritten",
+		"criteria":            []interface{}{},
+		"criteriaMustSatisfy": "all",
+	}
+	var behaviors []interface{}
+	originRules := []interface{}{}
+	if len(active) == 1 {
+		behaviors = append(behaviors, originBehavior(active[0].OriginServer))
+	} else {
+		behaviors = append(behaviors, map[string]interface{}{
+			"name": "setVariable",
+			"options": map[string]interface{}{
+				"variableName":    originBucketVariable,
+				"valueSource":     "GENERATE",
+				"generator":       "RAND",
+				"minRandomNumber": "1",
+				"maxRandomNumber": strconv.Itoa(int(total)),
+			},
+		})
+		var lower int32 = 1
+		for _, origin := range active {
+			upper := lower + origin.Weight - 1
+			originRules = append(originRules, map[string]interface{}{
+				"name": "Origin " + origin.Name,
+				"criteria": []interface{}{map[string]interface{}{
+					"name": "matchVariable",
+					"options": map[string]interface{}{
+						"variableName":  originBucketVariable,
+						"matchOperator": "IS_BETWEEN",
+						"lowerBound":    strconv.Itoa(int(lower)),
+						"upperBound":    strconv.Itoa(int(upper)),
+					},
+				}},
+				"criteriaMustSatisfy": "all",
+				"behaviors":           []interface{}{originBehavior(origin.OriginServer)},
+				"children":            []interface{}{},
+			})
+			lower = upper + 1
+		}
+		rules.Variables = mergeVariables(rules.Variables, []akamaiV1alpha1.RuleVariable{{
+			Name:        originBucketVariable,
+			Description: "Random number selecting the origin of spec.origins",
+			Hidden:      true,
+		}})
+	}
+	if spec.Failover != nil {
+		behaviors = append(behaviors, map[string]interface{}{
+			"name": "failAction",
+			"options": map[string]interface{}{
+				"enabled":             true,
+				"actionType":          "RECREATED_CEX",
+				"cexHostname":         spec.Failover.Hostname,
+				"cexCustomPath":       false,
+				"modifyProtocol":      false,
+				"preserveQueryString": true,
+			},
+		})
+	}
+	rule["behaviors"] = behaviors
+	rule["children"] = originRules
+
+	raw, err := json.Marshal(rule)
+	if err != nil {
+		return fmt.Errorf("failed to render origins: %w", err)
+	}
+	// A rule tree exported from Akamai may already contain a rendering of spec.origins
+	children := []runtime.RawExtension{{Raw: raw}}
+	for _, child := range rules.Children {
+		var named struct {
+			Name string `json:"name"`
+		}
+		if json.Unmarshal(child.Raw, &named) == nil && named.Name == originsRuleName {
+			continue
+		}
+		children = append(children, child)
+	}
+	rules.Children = children
+	return nil
+}
+
+// originBehavior renders the origin behavior forwarding requests to the origin server. The cache
+// key uses the request hostname, so all origins share the cached objects.
+func originBehavior(origin akamaiV1alpha1.OriginServer) map[string]interface{} {
+	forwardHostHeader := origin.ForwardHostHeader
+	if forwardHostHeader == "" {
+		forwardHostHeader = "REQUEST_HOST_HEADER"
+	}
+	return map[string]interface{}{
+		"name": "origin",
+		"options": map[string]interface{}{
+			"originType":         "CUSTOMER",
+			"hostname":           origin.Hostname,
+			"forwardHostHeader":  forwardHostHeader,
+			"cacheKeyHostname":   "REQUEST_HOST_HEADER",
+			"compress":           true,
+			"enableTrueClientIp": false,
+			"httpPort":           80,
+			"httpsPort":          443,
+			"originSni":          true,
+			"verificationMode":   "PLATFORM_SETTINGS",
+		},
+	}
+}
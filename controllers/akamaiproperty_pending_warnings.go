@@ -0,0 +1,65 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+	"github.com/mmz-srf/akamai-operator/pkg/akamai"
+)
+
+// recordPendingWarnings surfaces the warnings blocking an activation in status.pendingWarnings and
+// the PendingAcknowledgement condition
+func (r *AkamaiPropertyReconciler) recordPendingWarnings(ctx context.Context, akamaiProperty *akamaiV1alpha1.AkamaiProperty, warnings []akamai.ActivationWarning) error {
+	ids := make([]string, 0, len(warnings))
+	for _, warning := range warnings {
+		ids = append(ids, warning.MessageID)
+	}
+
+	akamaiProperty.Status.PendingWarnings = pendingWarnings(warnings)
+	meta.SetStatusCondition(&akamaiProperty.Status.Conditions, metav1.Condition{
+		Type:               ConditionTypePendingAcknowledgement,
+		Status:             metav1.ConditionTrue,
+		Reason:             "WarningsNotAcknowledged",
+		Message:            fmt.Sprintf("Add to activation.acknowledgeWarnings: %s", strings.Join(ids, ", ")),
+		ObservedGeneration: akamaiProperty.Generation,
+	})
+
+	if err := r.updateStatusWithRetry(ctx, akamaiProperty); err != nil {
+		return fmt.Errorf("failed to record pending warnings: %w", err)
+	}
+	return nil
+}
+
+// pendingWarnings converts the warnings blocking an activation into their status form
+func pendingWarnings(warnings []akamai.ActivationWarning) []akamaiV1alpha1.PendingWarning {
+	pending := make([]akamaiV1alpha1.PendingWarning, 0, len(warnings))
+	for _, warning := range warnings {
+		pending = append(pending, akamaiV1alpha1.PendingWarning{
+			MessageID: warning.MessageID,
+			Title:     warning.Title,
+			Detail:    warning.Detail,
+		})
+	}
+	return pending
+}
+
+// clearPendingWarnings resets the pending warnings and guardrails once an activation has been accepted
+func clearPendingWarnings(akamaiProperty *akamaiV1alpha1.AkamaiProperty) {
+	akamaiProperty.Status.PendingWarnings = nil
+	akamaiProperty.Status.PendingGuardrails = nil
+	if meta.FindStatusCondition(akamaiProperty.Status.Conditions, ConditionTypePendingAcknowledgement) == nil {
+		return
+	}
+	meta.SetStatusCondition(&akamaiProperty.Status.Conditions, metav1.Condition{
+		Type:               ConditionTypePendingAcknowledgement,
+		Status:             metav1.ConditionFalse,
+		Reason:             "WarningsAcknowledged",
+		Message:            "No activation warnings or guardrails pending acknowledgement",
+		ObservedGeneration: akamaiProperty.Generation,
+	})
+}
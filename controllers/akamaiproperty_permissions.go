@@ -0,0 +1,86 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+	"github.com/mmz-srf/akamai-operator/pkg/akamai"
+)
+
+// Access scopes an Akamai request needs on the property's contract and group
+const (
+	accessRead  = "read"
+	accessWrite = "write"
+)
+
+// permissionRetryInterval is how often a property whose credentials lack access is retried.
+// Grants rarely change on their own, so retrying every few minutes only burns rate limit.
+const permissionRetryInterval = time.Minute * 30
+
+// permissionsMessage describes the access the credentials are missing
+func permissionsMessage(akamaiProperty *akamaiV1alpha1.AkamaiProperty, access, operation, detail string) string {
+	message := fmt.Sprintf("API client lacks %s access to contract %s, group %s (%s)",
+		access, akamaiProperty.Spec.ContractID, akamaiProperty.Spec.GroupID, operation)
+	if detail != "" {
+		message += ": " + detail
+	}
+	return message
+}
+
+// permissionsInsufficient checks whether err is Akamai denying the credentials access. If so it
+// sets the PermissionsInsufficient condition with the missing access scope and returns the
+// result to back off with, instead of retrying like a transient error. Any Akamai API error is
+// recorded in status.lastApiError, persisted with the next status update.
+func (r *AkamaiPropertyReconciler) permissionsInsufficient(ctx context.Context, akamaiProperty *akamaiV1alpha1.AkamaiProperty, access, operation string, err error) (ctrl.Result, bool) {
+	recordAPIError(akamaiProperty, operation, err)
+	detail, denied := akamai.PermissionDenied(err)
+	if !denied {
+		return ctrl.Result{}, false
+	}
+
+	message := permissionsMessage(akamaiProperty, access, operation, detail)
+	log.FromContext(ctx).Info("Insufficient Akamai permissions, backing off", "access", access,
+		"operation", operation, "retryAfter", permissionRetryInterval, "detail", detail)
+	meta.SetStatusCondition(&akamaiProperty.Status.Conditions, metav1.Condition{
+		Type:               ConditionTypePermissionsInsufficient,
+		Status:             metav1.ConditionTrue,
+		Reason:             permissionsReason(access),
+		Message:            message,
+		ObservedGeneration: akamaiProperty.Generation,
+	})
+	akamaiProperty.Status.ObservedGeneration = akamaiProperty.Generation
+	if err := r.updateStatusWithRetry(ctx, akamaiProperty); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to record insufficient permissions")
+	}
+	r.updateStatus(ctx, akamaiProperty, PhaseError, "PermissionsInsufficient", message)
+	return ctrl.Result{RequeueAfter: permissionRetryInterval}, true
+}
+
+// permissionsReason returns the PermissionsInsufficient condition reason for an access scope
+func permissionsReason(access string) string {
+	if access == accessRead {
+		return "MissingReadAccess"
+	}
+	return "MissingWriteAccess"
+}
+
+// clearPermissionsInsufficient marks the PermissionsInsufficient condition as resolved and
+// reports whether the condition changed
+func clearPermissionsInsufficient(akamaiProperty *akamaiV1alpha1.AkamaiProperty) bool {
+	if !meta.IsStatusConditionTrue(akamaiProperty.Status.Conditions, ConditionTypePermissionsInsufficient) {
+		return false
+	}
+	return meta.SetStatusCondition(&akamaiProperty.Status.Conditions, metav1.Condition{
+		Type:               ConditionTypePermissionsInsufficient,
+		Status:             metav1.ConditionFalse,
+		Reason:             "PermissionsSufficient",
+		ObservedGeneration: akamaiProperty.Generation,
+	})
+}
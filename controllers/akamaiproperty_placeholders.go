@@ -0,0 +1,363 @@
+package controllers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+)
+
+// Kinds of the objects placeholders in rule options read their values from
+const (
+	placeholderSecret    = "secret"
+	placeholderConfigMap = "configmap"
+)
+
+// placeholderPattern matches a placeholder like ${secret:namespace/name/key} in a rule option
+var placeholderPattern = regexp.MustCompile(`\$\{(secret|configmap):([^/{}\s]+)/([^/{}\s]+)/([^/{}\s]+)\}`)
+
+// placeholderStart matches the start of a placeholder, well-formed or not
+var placeholderStart = regexp.MustCompile(`\$\{(secret|configmap):`)
+
+// valueRef references a key of a Secret or ConfigMap from a placeholder
+type valueRef struct {
+	kind      string
+	namespace string
+	name      string
+	key       string
+}
+
+// source returns the object the value is read from, e.g. `secret:edge/origin-auth`
+func (v valueRef) source() string {
+	return v.kind + ":" + v.namespace + "/" + v.name
+}
+
+// rulesSources identifies the external content the desired rules were built from
+type rulesSources struct {
+	// digest is the digest of the content of spec.rulesFrom
+	digest string
+	// valueSources lists the Secrets and ConfigMaps placeholders were resolved from
+	valueSources []string
+	// valuesDigest is the digest of the versions of the value sources
+	valuesDigest string
+	// cpCodes are the CP codes set by the cpCode behaviors of the rendered rule tree
+	cpCodes []string
+	// secretOptions are the option values Secret values were resolved into
+	secretOptions maskedOptions
+}
+
+// maskedOptions holds rule option values that are masked wherever rules are logged or recorded
+type maskedOptions map[string]bool
+
+// mask replaces the masked option values in a normalized rule map. Only whole values are
+// replaced, so other values merely containing the same text stay readable.
+func (m maskedOptions) mask(rules map[string]interface{}) {
+	if len(m) == 0 {
+		return
+	}
+	changed := false
+	_, _ = walkStrings(rules, func(value string) (string, error) {
+		if m[value] {
+			return maskedValue, nil
+		}
+		return value, nil
+	}, &changed)
+}
+
+// parsePlaceholders returns the placeholders in a rule option value and fails on malformed ones
+func parsePlaceholders(value string) ([]valueRef, error) {
+	matches := placeholderPattern.FindAllStringSubmatch(value, -1)
+	if len(placeholderStart.FindAllStringIndex(value, -1)) != len(matches) {
+		return nil, fmt.Errorf("malformed placeholder in %q, expected ${secret:namespace/name/key} or ${configmap:namespace/name/key}", value)
+	}
+	refs := make([]valueRef, 0, len(matches))
+	for _, match := range matches {
+		refs = append(refs, valueRef{kind: match[1], namespace: match[2], name: match[3], key: match[4]})
+	}
+	return refs, nil
+}
+
+// validatePlaceholders checks that every placeholder in the behavior and criterion options of the
+// rule tree is well-formed
+func validatePlaceholders(rules *akamaiV1alpha1.PropertyRules) error {
+	if rules == nil {
+		return nil
+	}
+	tree, err := ruleTree(rules)
+	if err != nil {
+		return err
+	}
+	_, err = walkOptionStrings(tree, func(value string) (string, error) {
+		_, err := parsePlaceholders(value)
+		return value, err
+	})
+	return err
+}
+
+// resolvePlaceholders replaces the placeholders in the behavior and criterion options of the rule
+// tree with the values of the Secret and ConfigMap keys they reference, and records the objects
+// read and the option values holding Secret values in sources. Rules without placeholders are
+// returned as they are. The values digest covers the resource versions of the objects read, so
+// changed values are applied like a change of the spec without the values being exposed.
+func (r *AkamaiPropertyReconciler) resolvePlaceholders(ctx context.Context, rules *akamaiV1alpha1.PropertyRules, sources *rulesSources) (*akamaiV1alpha1.PropertyRules, error) {
+	if rules == nil {
+		return nil, nil
+	}
+	tree, err := ruleTree(rules)
+	if err != nil {
+		return nil, err
+	}
+
+	versions := map[string]string{}
+	values := map[valueRef]string{}
+	secretOptions := maskedOptions{}
+	found, err := walkOptionStrings(tree, func(value string) (string, error) {
+		refs, err := parsePlaceholders(value)
+		if err != nil || len(refs) == 0 {
+			return value, err
+		}
+		hasSecret := false
+		for _, ref := range refs {
+			if ref.kind == placeholderSecret {
+				hasSecret = true
+			}
+			if _, ok := values[ref]; ok {
+				continue
+			}
+			resolved, version, err := r.readValueRef(ctx, ref)
+			if err != nil {
+				return "", err
+			}
+			values[ref] = resolved
+			versions[ref.source()] = version
+		}
+		resolved := placeholderPattern.ReplaceAllStringFunc(value, func(placeholder string) string {
+			match := placeholderPattern.FindStringSubmatch(placeholder)
+			return values[valueRef{kind: match[1], namespace: match[2], name: match[3], key: match[4]}]
+		})
+		if hasSecret && resolved != "" {
+			secretOptions[resolved] = true
+		}
+		return resolved, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve placeholders: %w", err)
+	}
+	if !found {
+		return rules, nil
+	}
+
+	raw, err := json.Marshal(tree)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal resolved rules: %w", err)
+	}
+	var resolved akamaiV1alpha1.PropertyRules
+	if err := json.Unmarshal(raw, &resolved); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal resolved rules: %w", err)
+	}
+
+	valueSources := make([]string, 0, len(versions))
+	for source := range versions {
+		valueSources = append(valueSources, source)
+	}
+	sort.Strings(valueSources)
+	hash := sha256.New()
+	for _, source := range valueSources {
+		fmt.Fprintf(hash, "%s@%s\n", source, versions[source])
+	}
+	sources.valueSources = valueSources
+	sources.valuesDigest = "sha256:" + hex.EncodeToString(hash.Sum(nil))
+	sources.secretOptions = secretOptions
+	return &resolved, nil
+}
+
+// readValueRef reads the value a placeholder references and the resource version it was read at
+func (r *AkamaiPropertyReconciler) readValueRef(ctx context.Context, ref valueRef) (string, string, error) {
+	key := types.NamespacedName{Namespace: ref.namespace, Name: ref.name}
+	if ref.kind == placeholderSecret {
+		secret, err := r.getRuleValueSecret(ctx, key)
+		if err != nil {
+			return "", "", err
+		}
+		value, ok := secret.Data[ref.key]
+		if !ok {
+			return "", "", fmt.Errorf("key %s not found in secret %s", ref.key, key)
+		}
+		return string(value), secret.ResourceVersion, nil
+	}
+	var configMap corev1.ConfigMap
+	if err := r.Get(ctx, key, &configMap); err != nil {
+		return "", "", fmt.Errorf("failed to get configmap %s: %w", key, err)
+	}
+	value, ok := configMap.Data[ref.key]
+	if !ok {
+		return "", "", fmt.Errorf("key %s not found in configmap %s", ref.key, key)
+	}
+	return value, configMap.ResourceVersion, nil
+}
+
+// getRuleValueSecret reads a Secret rule values are taken from. Only Secrets labelled with
+// LabelRuleValues may be read, so properties can't copy arbitrary Secrets of the cluster into
+// their rules.
+func (r *AkamaiPropertyReconciler) getRuleValueSecret(ctx context.Context, key types.NamespacedName) (*corev1.Secret, error) {
+	var secret corev1.Secret
+	if err := r.Get(ctx, key, &secret); err != nil {
+		return nil, fmt.Errorf("failed to get secret %s: %w", key, err)
+	}
+	if secret.Labels[LabelRuleValues] != "true" {
+		return nil, fmt.Errorf("secret %s is not labelled %s=true", key, LabelRuleValues)
+	}
+	return &secret, nil
+}
+
+// ruleTree decodes the rule tree into a generic map
+func ruleTree(rules *akamaiV1alpha1.PropertyRules) (map[string]interface{}, error) {
+	raw, err := json.Marshal(rules)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal rules: %w", err)
+	}
+	tree := map[string]interface{}{}
+	if err := json.Unmarshal(raw, &tree); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal rules: %w", err)
+	}
+	return tree, nil
+}
+
+// walkOptionStrings replaces every string in the behavior and criterion options of a rule and its
+// children with the result of fn and reports whether any string changed
+func walkOptionStrings(rule map[string]interface{}, fn func(string) (string, error)) (bool, error) {
+	return walkOptionValues(rule, func(value string) (interface{}, error) {
+		return fn(value)
+	})
+}
+
+// walkOptionValues replaces every string in the behavior and criterion options of a rule and its
+// children with the value fn returns for it, e.g. a number, and reports whether any value changed
+func walkOptionValues(rule map[string]interface{}, fn func(string) (interface{}, error)) (bool, error) {
+	changed := false
+	for _, list := range []string{"behaviors", "criteria"} {
+		items, _ := rule[list].([]interface{})
+		for _, item := range items {
+			entry, ok := item.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			options, err := walkValues(entry["options"], fn, &changed)
+			if err != nil {
+				name, _ := entry["name"].(string)
+				return false, fmt.Errorf("options of %q: %w", name, err)
+			}
+			if options != nil {
+				entry["options"] = options
+			}
+		}
+	}
+	children, _ := rule["children"].([]interface{})
+	for _, child := range children {
+		childRule, ok := child.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		childChanged, err := walkOptionValues(childRule, fn)
+		if err != nil {
+			return false, err
+		}
+		changed = changed || childChanged
+	}
+	return changed, nil
+}
+
+// walkStrings replaces the strings in a decoded JSON value with the result of fn
+func walkStrings(value interface{}, fn func(string) (string, error), changed *bool) (interface{}, error) {
+	return walkValues(value, func(value string) (interface{}, error) {
+		return fn(value)
+	}, changed)
+}
+
+// walkValues replaces the strings in a decoded JSON value with the value fn returns for them
+func walkValues(value interface{}, fn func(string) (interface{}, error), changed *bool) (interface{}, error) {
+	switch v := value.(type) {
+	case string:
+		replaced, err := fn(v)
+		if err != nil {
+			return nil, err
+		}
+		if replaced != interface{}(v) {
+			*changed = true
+		}
+		return replaced, nil
+	case map[string]interface{}:
+		for key, item := range v {
+			replaced, err := walkValues(item, fn, changed)
+			if err != nil {
+				return nil, err
+			}
+			v[key] = replaced
+		}
+	case []interface{}:
+		for i, item := range v {
+			replaced, err := walkValues(item, fn, changed)
+			if err != nil {
+				return nil, err
+			}
+			v[i] = replaced
+		}
+	}
+	return value, nil
+}
+
+// referencedValueSources returns the Secrets and ConfigMaps a property reads values from: the
+// placeholders in spec.rules, the Secret references of spec.variables and the sources recorded
+// for rendered rules
+func referencedValueSources(akamaiProperty *akamaiV1alpha1.AkamaiProperty) map[string]bool {
+	sources := map[string]bool{}
+	for _, source := range akamaiProperty.Status.ValueSources {
+		sources[source] = true
+	}
+	for _, variable := range akamaiProperty.Spec.Variables {
+		if variable.ValueFrom != nil && variable.ValueFrom.SecretKeyRef != nil {
+			ref := variable.ValueFrom.SecretKeyRef
+			sources[valueRef{kind: placeholderSecret, namespace: ref.Namespace, name: ref.Name}.source()] = true
+		}
+	}
+	if rules := akamaiProperty.Spec.Rules; rules != nil {
+		if tree, err := ruleTree(rules); err == nil {
+			_, _ = walkOptionStrings(tree, func(value string) (string, error) {
+				for _, match := range placeholderPattern.FindAllStringSubmatch(value, -1) {
+					sources[valueRef{kind: match[1], namespace: match[2], name: match[3]}.source()] = true
+				}
+				return value, nil
+			})
+		}
+	}
+	return sources
+}
+
+// propertiesReadingFrom returns a map function enqueuing the properties reading values from a
+// changed Secret or ConfigMap of the given placeholder kind
+func (r *AkamaiPropertyReconciler) propertiesReadingFrom(kind string) func(context.Context, client.Object) []reconcile.Request {
+	return func(ctx context.Context, obj client.Object) []reconcile.Request {
+		var properties akamaiV1alpha1.AkamaiPropertyList
+		if err := r.List(ctx, &properties); err != nil {
+			return nil
+		}
+		source := valueRef{kind: kind, namespace: obj.GetNamespace(), name: obj.GetName()}.source()
+		var requests []reconcile.Request
+		for i := range properties.Items {
+			if referencedValueSources(&properties.Items[i])[source] {
+				requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&properties.Items[i])})
+			}
+		}
+		return requests
+	}
+}
@@ -0,0 +1,150 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+)
+
+// preservedBehaviorNames returns the behavior names the operator must never remove: those of the
+// --preserve-behaviors flag and of spec.preserveBehaviors
+func (r *AkamaiPropertyReconciler) preservedBehaviorNames(akamaiProperty *akamaiV1alpha1.AkamaiProperty) map[string]bool {
+	names := make(map[string]bool, len(r.PreserveBehaviors)+len(akamaiProperty.Spec.PreserveBehaviors))
+	for _, name := range r.PreserveBehaviors {
+		names[name] = true
+	}
+	for _, name := range akamaiProperty.Spec.PreserveBehaviors {
+		names[name] = true
+	}
+	return names
+}
+
+// preserveBehaviors adds the preserved behaviors found in the current rule tree but missing from
+// the desired one back into a copy of the desired rules, so enforcing the spec never deletes
+// protections managed outside the operator. Rules are matched by name along their path; a child
+// rule missing from the desired tree is kept as a whole when it contains a preserved behavior.
+// Returns the rules to enforce and the "rule path/behavior" locations that were preserved.
+func preserveBehaviors(desired *akamaiV1alpha1.PropertyRules, current interface{}, names map[string]bool) (*akamaiV1alpha1.PropertyRules, []string, error) {
+	if desired == nil || len(names) == 0 {
+		return desired, nil, nil
+	}
+
+	currentBytes, err := json.Marshal(current)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal current rules: %w", err)
+	}
+	var currentRules akamaiV1alpha1.PropertyRules
+	if err := json.Unmarshal(currentBytes, &currentRules); err != nil {
+		return nil, nil, fmt.Errorf("failed to unmarshal current rules: %w", err)
+	}
+
+	enforced := desired.DeepCopy()
+	preserved, err := mergePreservedBehaviors(enforced, &currentRules, names, enforced.Name)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(preserved) == 0 {
+		return desired, nil, nil
+	}
+	return enforced, preserved, nil
+}
+
+// mergePreservedBehaviors merges the preserved behaviors of current into desired, recursing into
+// the child rules with the same name
+func mergePreservedBehaviors(desired, current *akamaiV1alpha1.PropertyRules, names map[string]bool, path string) ([]string, error) {
+	var preserved []string
+
+	desiredBehaviors := make(map[string]bool, len(desired.Behaviors))
+	for _, behavior := range desired.Behaviors {
+		desiredBehaviors[behavior.Name] = true
+	}
+	for _, behavior := range current.Behaviors {
+		if names[behavior.Name] && !desiredBehaviors[behavior.Name] {
+			desired.Behaviors = append(desired.Behaviors, behavior)
+			preserved = append(preserved, path+"/"+behavior.Name)
+		}
+	}
+
+	desiredChildren := make(map[string]int, len(desired.Children))
+	for i, childRaw := range desired.Children {
+		var child akamaiV1alpha1.PropertyRules
+		if err := json.Unmarshal(childRaw.Raw, &child); err != nil {
+			return nil, fmt.Errorf("failed to parse child rule %d of %s: %w", i, path, err)
+		}
+		desiredChildren[child.Name] = i
+	}
+
+	for _, childRaw := range current.Children {
+		var currentChild akamaiV1alpha1.PropertyRules
+		if err := json.Unmarshal(childRaw.Raw, &currentChild); err != nil {
+			continue
+		}
+		childPath := path + "/" + currentChild.Name
+
+		i, ok := desiredChildren[currentChild.Name]
+		if !ok {
+			// A rule added outside the operator survives when it holds a preserved behavior
+			if locations := preservedBehaviorLocations(&currentChild, names, childPath); len(locations) > 0 {
+				desired.Children = append(desired.Children, runtime.RawExtension{Raw: childRaw.Raw})
+				preserved = append(preserved, locations...)
+			}
+			continue
+		}
+
+		var desiredChild akamaiV1alpha1.PropertyRules
+		if err := json.Unmarshal(desired.Children[i].Raw, &desiredChild); err != nil {
+			return nil, fmt.Errorf("failed to parse child rule %s: %w", childPath, err)
+		}
+		locations, err := mergePreservedBehaviors(&desiredChild, &currentChild, names, childPath)
+		if err != nil {
+			return nil, err
+		}
+		if len(locations) == 0 {
+			continue
+		}
+		childBytes, err := json.Marshal(desiredChild)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal child rule %s: %w", childPath, err)
+		}
+		desired.Children[i] = runtime.RawExtension{Raw: childBytes}
+		preserved = append(preserved, locations...)
+	}
+	return preserved, nil
+}
+
+// preservedBehaviorLocations lists the preserved behaviors of a rule and its children
+func preservedBehaviorLocations(rules *akamaiV1alpha1.PropertyRules, names map[string]bool, path string) []string {
+	var locations []string
+	for _, behavior := range rules.Behaviors {
+		if names[behavior.Name] {
+			locations = append(locations, path+"/"+behavior.Name)
+		}
+	}
+	for _, childRaw := range rules.Children {
+		var child akamaiV1alpha1.PropertyRules
+		if err := json.Unmarshal(childRaw.Raw, &child); err != nil {
+			continue
+		}
+		locations = append(locations, preservedBehaviorLocations(&child, names, path+"/"+child.Name)...)
+	}
+	return locations
+}
+
+// recordPreservedBehaviors reports the preserved behaviors in status.preservedBehaviors and
+// reports whether they changed. The status is persisted by the caller.
+func (r *AkamaiPropertyReconciler) recordPreservedBehaviors(ctx context.Context, akamaiProperty *akamaiV1alpha1.AkamaiProperty, preserved []string) bool {
+	if slices.Equal(akamaiProperty.Status.PreservedBehaviors, preserved) {
+		return false
+	}
+	if len(preserved) > 0 {
+		log.FromContext(ctx).Info("Keeping behaviors added outside the operator", "behaviors", preserved)
+	}
+	akamaiProperty.Status.PreservedBehaviors = preserved
+	return true
+}
@@ -0,0 +1,127 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+)
+
+const (
+	// defaultPreviewHostnameTemplate renders preview hostnames as subdomains of the base hostnames
+	defaultPreviewHostnameTemplate = "{name}.{hostname}"
+
+	// previewNetwork is the only network previews are activated on
+	previewNetwork = "STAGING"
+)
+
+// isPreview reports whether the resource is labeled as a preview with akamai.com/preview
+func isPreview(akamaiProperty *akamaiV1alpha1.AkamaiProperty) bool {
+	return strings.EqualFold(strings.TrimSpace(akamaiProperty.Labels[LabelPreview]), "true")
+}
+
+// previewBase returns the AkamaiProperty a preview is cloned from
+func previewBase(ctx context.Context, reader client.Reader, akamaiProperty *akamaiV1alpha1.AkamaiProperty) (*akamaiV1alpha1.AkamaiProperty, error) {
+	if akamaiProperty.Spec.Preview == nil || akamaiProperty.Spec.Preview.BaseRef == "" {
+		return nil, fmt.Errorf("resources labeled %s=true need spec.preview.baseRef", LabelPreview)
+	}
+	name := akamaiProperty.Spec.Preview.BaseRef
+	var base akamaiV1alpha1.AkamaiProperty
+	if err := reader.Get(ctx, types.NamespacedName{Name: name}, &base); err != nil {
+		return nil, fmt.Errorf("failed to get base property %s: %w", name, err)
+	}
+	if isPreview(&base) {
+		return nil, fmt.Errorf("base property %s is a preview itself", name)
+	}
+	return &base, nil
+}
+
+// applyPreview turns the spec of a preview into the spec of its preview property. The account,
+// hostnames and activation settings the spec leaves empty are taken from the base, hostnames
+// are renamed with the hostname template and activations are moved to staging. Like account
+// defaults, the result is only applied in memory and never written back to the spec.
+func applyPreview(akamaiProperty, base *akamaiV1alpha1.AkamaiProperty) error {
+	spec := &akamaiProperty.Spec
+	if spec.CredentialsRef == nil && spec.ProviderConfigRef == "" {
+		spec.CredentialsRef = base.Spec.CredentialsRef
+		spec.ProviderConfigRef = base.Spec.ProviderConfigRef
+	}
+	if spec.ContractID == "" {
+		spec.ContractID = base.Spec.ContractID
+	}
+	if spec.GroupID == "" {
+		spec.GroupID = base.Spec.GroupID
+	}
+
+	hostnames := spec.Hostnames
+	if len(hostnames) == 0 {
+		hostnames = base.Spec.Hostnames
+	}
+	template := spec.Preview.HostnameTemplate
+	if template == "" {
+		template = defaultPreviewHostnameTemplate
+	}
+	previewHostnames := make([]akamaiV1alpha1.Hostname, 0, len(hostnames))
+	for _, hostname := range hostnames {
+		name, err := renderNameTemplate(strings.ReplaceAll(template, "{hostname}", hostname.CNAMEFrom), akamaiProperty)
+		if err != nil {
+			return fmt.Errorf("failed to render preview hostname of %s: %w", hostname.CNAMEFrom, err)
+		}
+		hostname.CNAMEFrom = name
+		previewHostnames = append(previewHostnames, hostname)
+	}
+	spec.Hostnames = previewHostnames
+
+	activation := spec.Activation
+	if activation == nil {
+		activation = base.Spec.Activation
+	}
+	if activation != nil {
+		activation = activation.DeepCopy()
+		activation.Network = previewNetwork
+	}
+	spec.Activation = activation
+	return nil
+}
+
+// preparePreview resolves the base of a preview and applies it to the spec
+func (r *AkamaiPropertyReconciler) preparePreview(ctx context.Context, akamaiProperty *akamaiV1alpha1.AkamaiProperty) error {
+	base, err := previewBase(ctx, r.Client, akamaiProperty)
+	if err != nil {
+		return err
+	}
+	return applyPreview(akamaiProperty, base)
+}
+
+// previewCloneVersion returns the version of the base property a preview is cloned from: the
+// version active on production, or the latest version if none is
+func previewCloneVersion(base *akamaiV1alpha1.AkamaiProperty) int {
+	if base.Status.ProductionVersion != 0 {
+		return base.Status.ProductionVersion
+	}
+	return base.Status.LatestVersion
+}
+
+// createProperty creates the Akamai property of the resource. Previews are cloned from their
+// base property, so they start with its rules when spec.rules is not set.
+func (r *AkamaiPropertyReconciler) createProperty(ctx context.Context, akamaiProperty *akamaiV1alpha1.AkamaiProperty) (string, error) {
+	if !isPreview(akamaiProperty) {
+		return r.AkamaiClient.CreateProperty(ctx, &akamaiProperty.Spec)
+	}
+
+	base, err := previewBase(ctx, r.Client, akamaiProperty)
+	if err != nil {
+		return "", err
+	}
+	if base.Status.PropertyID == "" {
+		return "", fmt.Errorf("base property %s has not been created in Akamai yet", base.Name)
+	}
+	version := previewCloneVersion(base)
+	log.FromContext(ctx).Info("Cloning preview property", "base", base.Name, "basePropertyID", base.Status.PropertyID, "version", version)
+	return r.AkamaiClient.CloneProperty(ctx, &akamaiProperty.Spec, base.Status.PropertyID, version)
+}
@@ -0,0 +1,86 @@
+package controllers
+
+import (
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+)
+
+// setProgressCondition tracks how long the current generation has been working towards Ready in
+// the Progressing condition, mirroring the progress deadline of a Deployment. The condition's
+// LastTransitionTime is when the generation started progressing. Once spec.progressDeadlineSeconds
+// has passed without the generation becoming Ready, the condition turns False with reason
+// ProgressDeadlineExceeded and the generation counts as observed, so it is reported Stalled until
+// it becomes Ready or the spec changes. Suspended properties keep their condition. It reports
+// whether the condition changed.
+func setProgressCondition(akamaiProperty *akamaiV1alpha1.AkamaiProperty, now time.Time) bool {
+	if akamaiProperty.Status.Phase == PhaseSuspended {
+		return false
+	}
+
+	generation := akamaiProperty.Generation
+	existing := meta.FindStatusCondition(akamaiProperty.Status.Conditions, ConditionTypeProgressing)
+
+	if akamaiProperty.Status.Phase == PhaseReady && akamaiProperty.Status.ObservedGeneration == generation {
+		return meta.SetStatusCondition(&akamaiProperty.Status.Conditions, metav1.Condition{
+			Type:               ConditionTypeProgressing,
+			Status:             metav1.ConditionTrue,
+			Reason:             ReasonGenerationReady,
+			Message:            fmt.Sprintf("Generation %d is ready", generation),
+			ObservedGeneration: generation,
+		})
+	}
+
+	// A new generation, or one progressing again after it was ready, restarts the clock
+	if existing == nil || existing.ObservedGeneration != generation || existing.Reason == ReasonGenerationReady {
+		meta.RemoveStatusCondition(&akamaiProperty.Status.Conditions, ConditionTypeProgressing)
+		meta.SetStatusCondition(&akamaiProperty.Status.Conditions, metav1.Condition{
+			Type:               ConditionTypeProgressing,
+			Status:             metav1.ConditionTrue,
+			Reason:             ReasonGenerationProgressing,
+			Message:            fmt.Sprintf("Generation %d is progressing", generation),
+			ObservedGeneration: generation,
+			LastTransitionTime: metav1.NewTime(now),
+		})
+		return true
+	}
+
+	deadline := akamaiProperty.Spec.ProgressDeadlineSeconds
+	if deadline == nil {
+		return false
+	}
+	if existing.Reason != ReasonProgressDeadlineExceeded && now.Sub(existing.LastTransitionTime.Time) <= time.Duration(*deadline)*time.Second {
+		return false
+	}
+
+	message := fmt.Sprintf("Generation %d did not become ready within %ds", generation, *deadline)
+	if ready := meta.FindStatusCondition(akamaiProperty.Status.Conditions, ConditionTypeReady); ready != nil && ready.Reason != "" {
+		message = fmt.Sprintf("%s, blocked by %s", message, ready.Reason)
+		if ready.Message != "" {
+			message = fmt.Sprintf("%s: %s", message, ready.Message)
+		}
+	}
+	akamaiProperty.Status.ObservedGeneration = generation
+	return meta.SetStatusCondition(&akamaiProperty.Status.Conditions, metav1.Condition{
+		Type:               ConditionTypeProgressing,
+		Status:             metav1.ConditionFalse,
+		Reason:             ReasonProgressDeadlineExceeded,
+		Message:            message,
+		ObservedGeneration: generation,
+		LastTransitionTime: metav1.NewTime(now),
+	})
+}
+
+// progressDeadlineExceeded returns the Progressing condition if the current generation exceeded
+// its progress deadline, or nil
+func progressDeadlineExceeded(akamaiProperty *akamaiV1alpha1.AkamaiProperty) *metav1.Condition {
+	condition := meta.FindStatusCondition(akamaiProperty.Status.Conditions, ConditionTypeProgressing)
+	if condition == nil || condition.Reason != ReasonProgressDeadlineExceeded || condition.ObservedGeneration != akamaiProperty.Generation {
+		return nil
+	}
+	return condition
+}
@@ -0,0 +1,202 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+	"github.com/mmz-srf/akamai-operator/pkg/akamai"
+)
+
+// promotionNetwork is the network versions are promoted to
+const promotionNetwork = "PRODUCTION"
+
+// requestedPromotion returns the version requested by the akamai.com/promote-version annotation
+func requestedPromotion(akamaiProperty *akamaiV1alpha1.AkamaiProperty) (int, bool, error) {
+	raw, ok := akamaiProperty.Annotations[AnnotationPromoteVersion]
+	if !ok {
+		return 0, false, nil
+	}
+	version, err := strconv.Atoi(strings.TrimSpace(raw))
+	if err != nil || version < 1 {
+		return 0, true, fmt.Errorf("invalid %s annotation %q: must be a positive version number", AnnotationPromoteVersion, raw)
+	}
+	return version, true, nil
+}
+
+// promotionRejection returns why a version can't be promoted to production, or "" if it can
+func promotionRejection(akamaiProperty *akamaiV1alpha1.AkamaiProperty, version int, versionState *akamai.PropertyVersion) string {
+	if isPreview(akamaiProperty) {
+		return "previews are only activated on staging"
+	}
+	activationSpec := akamaiProperty.Spec.Activation
+	if activationSpec == nil {
+		return "spec.activation is required for its notification settings"
+	}
+	if activationSpec.Network == promotionNetwork && activationTrigger(activationSpec) != akamaiV1alpha1.ActivationTriggerManual {
+		return "production activations are already managed by spec.activation; use trigger Manual to promote with the annotation"
+	}
+	if versionState == nil {
+		return fmt.Sprintf("version %d does not exist (latest version is %d)", version, akamaiProperty.Status.LatestVersion)
+	}
+	if versionState.StagingStatus != "ACTIVE" {
+		return fmt.Sprintf("version %d is not active on staging (staging status %s)", version, versionState.StagingStatus)
+	}
+	return ""
+}
+
+// handlePromotion activates the version requested by the akamai.com/promote-version annotation on
+// production, once it is validated to exist and to be active on staging. A non-zero result means
+// the promotion is in progress and the reconcile should requeue.
+func (r *AkamaiPropertyReconciler) handlePromotion(ctx context.Context, akamaiProperty *akamaiV1alpha1.AkamaiProperty) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	version, requested, err := requestedPromotion(akamaiProperty)
+	if !requested {
+		return ctrl.Result{}, nil
+	}
+	if err != nil {
+		return ctrl.Result{}, r.setPromotion(ctx, akamaiProperty, 0, akamaiV1alpha1.PromotionStateRejected, "", err.Error())
+	}
+
+	// Every annotation value is promoted once; a finished promotion is only repeated for a new value
+	if promotion := akamaiProperty.Status.Promotion; promotion != nil && promotion.Version == version {
+		if promotion.State == akamaiV1alpha1.PromotionStatePending {
+			return r.monitorPromotion(ctx, akamaiProperty)
+		}
+		return ctrl.Result{}, nil
+	}
+	if activationsFrozen(akamaiProperty) {
+		logger.Info("Promotion waits until activations are unfrozen", "version", version)
+		return ctrl.Result{}, nil
+	}
+	if !managesActivation(akamaiProperty) {
+		return ctrl.Result{}, r.setPromotion(ctx, akamaiProperty, version, akamaiV1alpha1.PromotionStateRejected, "",
+			"activations are left to other tooling with spec.manage.activation false")
+	}
+
+	var versionState *akamai.PropertyVersion
+	if version <= akamaiProperty.Status.LatestVersion {
+		versionState, err = r.AkamaiClient.GetPropertyVersion(ctx,
+			akamaiProperty.Status.PropertyID,
+			version,
+			akamaiProperty.Spec.ContractID,
+			akamaiProperty.Spec.GroupID)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+	if versionState != nil && versionState.ProductionStatus == "ACTIVE" {
+		return ctrl.Result{}, r.setPromotion(ctx, akamaiProperty, version, akamaiV1alpha1.PromotionStateActive, "",
+			fmt.Sprintf("Version %d is active on production", version))
+	}
+	if reason := promotionRejection(akamaiProperty, version, versionState); reason != "" {
+		logger.Info("Rejected promotion", "version", version, "reason", reason)
+		return ctrl.Result{}, r.setPromotion(ctx, akamaiProperty, version, akamaiV1alpha1.PromotionStateRejected, "", reason)
+	}
+
+	// Pick up a production activation of the version that is already running
+	pendingActivation, err := r.AkamaiClient.GetPendingActivationForVersion(ctx, akamaiProperty.Status.PropertyID, version, promotionNetwork)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	schedulerKey := activationKey(r.AkamaiClient, akamaiProperty, promotionNetwork)
+	if pendingActivation != nil {
+		r.ActivationScheduler.Track(schedulerKey)
+		err := r.setPromotion(ctx, akamaiProperty, version, akamaiV1alpha1.PromotionStatePending, pendingActivation.ActivationID,
+			fmt.Sprintf("Monitoring existing production activation of version %d", version))
+		return ctrl.Result{RequeueAfter: time.Minute * 2}, err
+	}
+
+	blocked, err := r.checkGuardrails(ctx, akamaiProperty, version)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if blocked {
+		logger.Info("Promotion blocked by guardrails", "version", version)
+		r.updateStatus(ctx, akamaiProperty, PhaseError, "GuardrailsNotAcknowledged",
+			fmt.Sprintf("Guardrails block the promotion of version %d", version))
+		return ctrl.Result{RequeueAfter: time.Minute * 5}, nil
+	}
+
+	if granted, position := r.ActivationScheduler.Acquire(schedulerKey); !granted {
+		logger.Info("Promotion queued, waiting for a free activation slot", "version", version, "position", position)
+		r.updateStatus(ctx, akamaiProperty, PhaseActivating, "ActivationQueued",
+			fmt.Sprintf("Waiting for a free activation slot to promote version %d (position %d)", version, position))
+		return ctrl.Result{RequeueAfter: r.versionPollInterval()}, nil
+	}
+
+	requestSpec, err := activationRequestSpec(akamaiProperty, promotionNetwork, version)
+	if err != nil {
+		r.ActivationScheduler.Release(schedulerKey)
+		return ctrl.Result{}, err
+	}
+	logger.Info("Promoting version to production", "version", version)
+	activationID, err := r.AkamaiClient.ActivateProperty(ctx, akamaiProperty.Status.PropertyID, version, requestSpec, akamaiProperty.Spec.ContractID, akamaiProperty.Spec.GroupID)
+	if err != nil {
+		r.ActivationScheduler.Release(schedulerKey)
+	}
+	var warningsErr *akamai.WarningsNotAcknowledgedError
+	if errors.As(err, &warningsErr) {
+		if err := r.recordPendingWarnings(ctx, akamaiProperty, warningsErr.Warnings); err != nil {
+			return ctrl.Result{}, err
+		}
+		r.updateStatus(ctx, akamaiProperty, PhaseError, "WarningsNotAcknowledged", warningsErr.Error())
+		return ctrl.Result{RequeueAfter: time.Minute * 5}, nil
+	}
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to activate version %d on production: %w", version, err)
+	}
+	clearPendingWarnings(akamaiProperty)
+
+	err = r.setPromotion(ctx, akamaiProperty, version, akamaiV1alpha1.PromotionStatePending, activationID,
+		fmt.Sprintf("Activating version %d on production", version))
+	r.watchActivation(akamaiProperty, activationID)
+	return ctrl.Result{RequeueAfter: time.Minute * 2}, err
+}
+
+// monitorPromotion follows the production activation of a pending promotion
+func (r *AkamaiPropertyReconciler) monitorPromotion(ctx context.Context, akamaiProperty *akamaiV1alpha1.AkamaiProperty) (ctrl.Result, error) {
+	promotion := akamaiProperty.Status.Promotion
+	schedulerKey := activationKey(r.AkamaiClient, akamaiProperty, promotionNetwork)
+
+	activation, err := r.AkamaiClient.GetActivation(ctx, akamaiProperty.Status.PropertyID, promotion.ActivationID)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if !akamai.ActivationFinished(activation.Status) {
+		r.ActivationScheduler.Track(schedulerKey)
+		r.watchActivation(akamaiProperty, promotion.ActivationID)
+		r.updateStatus(ctx, akamaiProperty, PhaseActivating, "PromotionInProgress",
+			fmt.Sprintf("Promoting version %d to production: %s", promotion.Version, activation.Status))
+		return ctrl.Result{RequeueAfter: time.Minute * 2}, nil
+	}
+
+	r.ActivationScheduler.Release(schedulerKey)
+	if activation.Status != "ACTIVE" {
+		log.FromContext(ctx).Error(nil, "Promotion failed", "version", promotion.Version, "status", activation.Status)
+		return ctrl.Result{}, r.setPromotion(ctx, akamaiProperty, promotion.Version, akamaiV1alpha1.PromotionStateFailed, promotion.ActivationID,
+			fmt.Sprintf("Production activation of version %d finished with status %s", promotion.Version, activation.Status))
+	}
+	akamaiProperty.Status.ProductionVersion = promotion.Version
+	return ctrl.Result{}, r.setPromotion(ctx, akamaiProperty, promotion.Version, akamaiV1alpha1.PromotionStateActive, promotion.ActivationID,
+		fmt.Sprintf("Version %d is active on production", promotion.Version))
+}
+
+// setPromotion records the state of the promotion in the status
+func (r *AkamaiPropertyReconciler) setPromotion(ctx context.Context, akamaiProperty *akamaiV1alpha1.AkamaiProperty, version int, state akamaiV1alpha1.PromotionState, activationID, message string) error {
+	akamaiProperty.Status.Promotion = &akamaiV1alpha1.PromotionStatus{
+		Version:      version,
+		State:        state,
+		ActivationID: activationID,
+		Message:      message,
+	}
+	return r.updateStatusWithRetry(ctx, akamaiProperty)
+}
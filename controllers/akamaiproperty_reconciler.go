@@ -2,8 +2,11 @@ package controllers
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"time"
 
+	corev1 "k8s.io/api/core/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/log"
@@ -16,6 +19,50 @@ import (
 func (r *AkamaiPropertyReconciler) reconcileProperty(ctx context.Context, akamaiProperty *akamaiV1alpha1.AkamaiProperty) (ctrl.Result, error) {
 	logger := log.FromContext(ctx)
 
+	// Skip all Akamai work for generations known to be invalid
+	valid, err := r.checkSpecValidity(ctx, akamaiProperty)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if !valid {
+		return ctrl.Result{}, nil
+	}
+
+	// A dry run collects the changes it would make instead of making them
+	var plan *dryRunPlan
+	if r.dryRun(akamaiProperty) {
+		plan = &dryRunPlan{}
+	} else if clearPendingChanges(akamaiProperty) {
+		if err := r.updateStatusWithRetry(ctx, akamaiProperty); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	// Adopt an existing property named by annotation, e.g. one re-created by a restore
+	if akamaiProperty.Status.PropertyID == "" && akamaiProperty.Annotations[AnnotationPropertyID] != "" {
+		if err := r.adoptProperty(ctx, akamaiProperty, akamaiProperty.Annotations[AnnotationPropertyID]); err != nil {
+			if result, denied := r.permissionsInsufficient(ctx, akamaiProperty, accessRead, "read property", err); denied {
+				return result, nil
+			}
+			logger.Error(err, "Failed to adopt Akamai property")
+			r.updateStatus(ctx, akamaiProperty, PhaseError, "FailedToAdoptProperty", akamai.ErrorMessage(err))
+			return r.retryAfterError(akamaiProperty, err), nil
+		}
+	}
+
+	// Properties are only compared when they exist in Akamai and are known to the operator
+	if akamaiProperty.Status.PropertyID == "" && r.ObserveOnly {
+		logger.Info("Not creating Akamai property in observe-only mode", "propertyName", akamaiProperty.Spec.PropertyName)
+		r.updateStatus(ctx, akamaiProperty, PhaseObserving, ReasonObserveOnly,
+			fmt.Sprintf("Not creating the property in observe-only mode; set the %s annotation to observe an existing property", AnnotationPropertyID))
+		return ctrl.Result{RequeueAfter: time.Minute * 30}, nil
+	}
+
+	if akamaiProperty.Status.PropertyID == "" && plan != nil {
+		planPropertyCreation(plan, akamaiProperty)
+		return r.completeDryRun(ctx, akamaiProperty, plan)
+	}
+
 	// Check if property exists in Akamai
 	if akamaiProperty.Status.PropertyID == "" {
 		// Property doesn't exist, create it
@@ -23,26 +70,26 @@ func (r *AkamaiPropertyReconciler) reconcileProperty(ctx context.Context, akamai
 		r.updateStatus(ctx, akamaiProperty, PhaseCreating, "CreatingAkamaiProperty", "")
 
 		// Ensure edge hostnames exist before creating property with hostnames
-		if len(akamaiProperty.Spec.Hostnames) > 0 {
+		if managesHostnames(akamaiProperty) && len(akamaiProperty.Spec.Hostnames) > 0 {
 			logger.Info("Ensuring edge hostnames exist", "count", len(akamaiProperty.Spec.Hostnames))
-			err := r.AkamaiClient.EnsureEdgeHostnamesExist(ctx,
-				akamaiProperty.Spec.Hostnames,
-				akamaiProperty.Spec.EdgeHostname,
-				akamaiProperty.Spec.ProductID,
-				akamaiProperty.Spec.ContractID,
-				akamaiProperty.Spec.GroupID)
-			if err != nil {
+			if err := r.ensureEdgeHostnames(ctx, akamaiProperty); err != nil {
+				if result, denied := r.permissionsInsufficient(ctx, akamaiProperty, accessWrite, "create edge hostnames", err); denied {
+					return result, nil
+				}
 				logger.Error(err, "Failed to ensure edge hostnames exist")
-				r.updateStatus(ctx, akamaiProperty, PhaseError, "FailedToEnsureEdgeHostnames", err.Error())
-				return ctrl.Result{RequeueAfter: time.Minute * 2}, nil
+				r.updateStatus(ctx, akamaiProperty, PhaseError, "FailedToEnsureEdgeHostnames", akamai.ErrorMessage(err))
+				return r.retryAfterError(akamaiProperty, err), nil
 			}
 		}
 
-		propertyID, err := r.AkamaiClient.CreateProperty(ctx, &akamaiProperty.Spec)
+		propertyID, err := r.createProperty(ctx, akamaiProperty)
 		if err != nil {
+			if result, denied := r.permissionsInsufficient(ctx, akamaiProperty, accessWrite, "create property", err); denied {
+				return result, nil
+			}
 			logger.Error(err, "Failed to create Akamai property")
-			r.updateStatus(ctx, akamaiProperty, PhaseError, "FailedToCreateProperty", err.Error())
-			return ctrl.Result{RequeueAfter: time.Minute * 2}, nil
+			r.updateStatus(ctx, akamaiProperty, PhaseError, "FailedToCreateProperty", akamai.ErrorMessage(err))
+			return r.retryAfterError(akamaiProperty, err), nil
 		}
 
 		akamaiProperty.Status.PropertyID = propertyID
@@ -53,19 +100,44 @@ func (r *AkamaiPropertyReconciler) reconcileProperty(ctx context.Context, akamai
 			return ctrl.Result{}, err
 		}
 
+		// Make the new property self-describing from the start
+		if notes := renderVersionNotes(akamaiProperty); notes != "" {
+			err = r.AkamaiClient.SetVersionNotes(ctx, propertyID,
+				1, // Initial version is 1
+				akamaiProperty.Spec.ContractID,
+				akamaiProperty.Spec.GroupID,
+				notes)
+			if err != nil {
+				if result, denied := r.permissionsInsufficient(ctx, akamaiProperty, accessWrite, "set version notes", err); denied {
+					return result, nil
+				}
+				logger.Error(err, "Failed to set initial version notes")
+				r.updateStatus(ctx, akamaiProperty, PhaseError, "FailedToSetInitialVersionNotes", akamai.ErrorMessage(err))
+				return r.retryAfterError(akamaiProperty, err), nil
+			}
+		}
+
 		// Update hostnames if specified after property creation
-		if len(akamaiProperty.Spec.Hostnames) > 0 {
+		if managesHostnames(akamaiProperty) && len(akamaiProperty.Spec.Hostnames) > 0 {
 			err = r.AkamaiClient.SetPropertyHostnames(ctx, propertyID,
 				akamaiProperty.Spec.ContractID,
 				akamaiProperty.Spec.GroupID,
 				1, // Initial version is 1
 				akamaiProperty.Spec.Hostnames)
 			if err != nil {
+				if result, denied := r.permissionsInsufficient(ctx, akamaiProperty, accessWrite, "set hostnames", err); denied {
+					return result, nil
+				}
 				logger.Error(err, "Failed to set initial hostnames")
-				r.updateStatus(ctx, akamaiProperty, PhaseError, "FailedToSetInitialHostnames", err.Error())
-				return ctrl.Result{RequeueAfter: time.Minute * 2}, nil
+				r.updateStatus(ctx, akamaiProperty, PhaseError, "FailedToSetInitialHostnames", akamai.ErrorMessage(err))
+				return r.retryAfterError(akamaiProperty, err), nil
 			}
 			logger.Info("Successfully set initial hostnames", "count", len(akamaiProperty.Spec.Hostnames))
+
+			akamaiProperty.Status.ManagedHostnames = hostnameNames(akamaiProperty.Spec.Hostnames)
+			if err := r.updateStatusWithRetry(ctx, akamaiProperty); err != nil {
+				return ctrl.Result{}, err
+			}
 		}
 
 		logger.Info("Successfully created Akamai property", "propertyID", propertyID)
@@ -76,9 +148,12 @@ func (r *AkamaiPropertyReconciler) reconcileProperty(ctx context.Context, akamai
 	// Property exists, check if it needs to be updated
 	currentProperty, err := r.AkamaiClient.GetProperty(ctx, akamaiProperty.Status.PropertyID)
 	if err != nil {
+		if result, denied := r.permissionsInsufficient(ctx, akamaiProperty, accessRead, "read property", err); denied {
+			return result, nil
+		}
 		logger.Error(err, "Failed to get Akamai property")
-		r.updateStatus(ctx, akamaiProperty, PhaseError, "FailedToRetrieveProperty", err.Error())
-		return ctrl.Result{RequeueAfter: time.Minute * 2}, nil
+		r.updateStatus(ctx, akamaiProperty, PhaseError, "FailedToRetrieveProperty", akamai.ErrorMessage(err))
+		return r.retryAfterError(akamaiProperty, err), nil
 	}
 
 	// Sync observed versions from Akamai to CR status to avoid stale display
@@ -101,35 +176,88 @@ func (r *AkamaiPropertyReconciler) reconcileProperty(ctx context.Context, akamai
 		return ctrl.Result{}, err
 	}
 
-	// Check if property needs to be updated
-	if r.needsUpdate(akamaiProperty, currentProperty) {
+	// Cancel an activation on request before anything waits for it to finish
+	if !r.ObserveOnly && plan == nil {
+		cancelled, err := r.handleAbortActivation(ctx, akamaiProperty)
+		if err != nil {
+			if result, denied := r.permissionsInsufficient(ctx, akamaiProperty, accessWrite, "cancel activation", err); denied {
+				return result, nil
+			}
+			logger.Error(err, "Failed to cancel activation")
+			r.updateStatus(ctx, akamaiProperty, PhaseError, "FailedToCancelActivation", akamai.ErrorMessage(err))
+			return r.retryAfterError(akamaiProperty, err), nil
+		}
+		if cancelled {
+			return ctrl.Result{RequeueAfter: r.versionPollInterval()}, nil
+		}
+	}
+
+	// Check if property needs to be updated; hostnames changed outside the operator are only
+	// overwritten according to the drift policy, and hostnames left to other tooling never
+	updateProperty := false
+	switch {
+	case !managesHostnames(akamaiProperty):
+		r.resolveDrift(ctx, akamaiProperty, driftHostnames)
+	case !r.readOnly(akamaiProperty) && resumesStep(ctx, akamaiProperty, CheckpointUpdateProperty):
+		updateProperty = true
+	case r.needsUpdate(akamaiProperty, currentProperty):
+		updateProperty = r.handleDrift(ctx, akamaiProperty, driftHostnames)
+	default:
+		r.resolveDrift(ctx, akamaiProperty, driftHostnames)
+	}
+	if updateProperty && plan != nil {
+		planPropertyUpdate(plan, akamaiProperty, currentProperty)
+		updateProperty = false
+	}
+	if updateProperty {
 		logger.Info("Updating Akamai property", "propertyID", akamaiProperty.Status.PropertyID)
 		r.updateStatus(ctx, akamaiProperty, PhaseUpdating, "UpdatingAkamaiProperty", "")
 
 		// Ensure edge hostnames exist before updating property with new hostnames
 		if len(akamaiProperty.Spec.Hostnames) > 0 {
 			logger.Info("Ensuring edge hostnames exist before update", "count", len(akamaiProperty.Spec.Hostnames))
-			err := r.AkamaiClient.EnsureEdgeHostnamesExist(ctx,
-				akamaiProperty.Spec.Hostnames,
-				akamaiProperty.Spec.EdgeHostname,
-				akamaiProperty.Spec.ProductID,
-				akamaiProperty.Spec.ContractID,
-				akamaiProperty.Spec.GroupID)
-			if err != nil {
+			if err := r.ensureEdgeHostnames(ctx, akamaiProperty); err != nil {
+				if result, denied := r.permissionsInsufficient(ctx, akamaiProperty, accessWrite, "create edge hostnames", err); denied {
+					return result, nil
+				}
 				logger.Error(err, "Failed to ensure edge hostnames exist")
-				r.updateStatus(ctx, akamaiProperty, PhaseError, "FailedToEnsureEdgeHostnames", err.Error())
-				return ctrl.Result{RequeueAfter: time.Minute * 2}, nil
+				r.updateStatus(ctx, akamaiProperty, PhaseError, "FailedToEnsureEdgeHostnames", akamai.ErrorMessage(err))
+				return r.retryAfterError(akamaiProperty, err), nil
 			}
 		}
 
-		newVersion, err := r.AkamaiClient.UpdateProperty(ctx, akamaiProperty.Status.PropertyID, &akamaiProperty.Spec)
+		newVersion, err := r.editableVersion(ctx, akamaiProperty)
+		if result, waiting := r.waitForEditableVersion(ctx, akamaiProperty, err); waiting {
+			return result, nil
+		}
+		if err != nil {
+			if result, denied := r.permissionsInsufficient(ctx, akamaiProperty, accessWrite, "create property version", err); denied {
+				return result, nil
+			}
+			logger.Error(err, "Failed to get editable property version")
+			r.updateStatus(ctx, akamaiProperty, PhaseError, "FailedToCreatePropertyVersion", akamai.ErrorMessage(err))
+			return r.retryAfterError(akamaiProperty, err), nil
+		}
+		if err := r.setCheckpoint(ctx, akamaiProperty, CheckpointUpdateProperty, newVersion); err != nil {
+			return ctrl.Result{}, err
+		}
+
+		err = r.AkamaiClient.UpdateProperty(ctx, akamaiProperty.Status.PropertyID, newVersion, &akamaiProperty.Spec, akamaiProperty.Status.ManagedHostnames)
 		if err != nil {
+			if result, denied := r.permissionsInsufficient(ctx, akamaiProperty, accessWrite, "update property", err); denied {
+				return result, nil
+			}
 			logger.Error(err, "Failed to update Akamai property")
-			r.updateStatus(ctx, akamaiProperty, PhaseError, "FailedToUpdateProperty", err.Error())
-			return ctrl.Result{RequeueAfter: time.Minute * 2}, nil
+			reason := "FailedToUpdateProperty"
+			if errors.Is(err, akamai.ErrHostnameConflict) {
+				reason = "HostnameConflict"
+			}
+			r.updateStatus(ctx, akamaiProperty, PhaseError, reason, akamai.ErrorMessage(err))
+			return r.retryAfterError(akamaiProperty, err), nil
 		}
 
-		akamaiProperty.Status.LatestVersion = newVersion
+		akamaiProperty.Status.ManagedHostnames = hostnameNames(akamaiProperty.Spec.Hostnames)
+		completeStep(akamaiProperty, CheckpointUpdateProperty, newVersion)
 		if err := r.updateStatusWithRetry(ctx, akamaiProperty); err != nil {
 			return ctrl.Result{}, err
 		}
@@ -138,12 +266,18 @@ func (r *AkamaiPropertyReconciler) reconcileProperty(ctx context.Context, akamai
 	}
 
 	// Check if rules need to be updated
-	if akamaiProperty.Spec.Rules != nil {
-		rulesUpdated, err := r.updateRulesIfNeeded(ctx, akamaiProperty)
+	if managesRules(akamaiProperty) {
+		rulesUpdated, err := r.updateRulesIfNeeded(ctx, akamaiProperty, plan)
+		if result, waiting := r.waitForEditableVersion(ctx, akamaiProperty, err); waiting {
+			return result, nil
+		}
 		if err != nil {
+			if result, denied := r.permissionsInsufficient(ctx, akamaiProperty, accessWrite, "update rules", err); denied {
+				return result, nil
+			}
 			logger.Error(err, "Failed to update property rules")
-			r.updateStatus(ctx, akamaiProperty, PhaseError, "FailedToUpdateRules", err.Error())
-			return ctrl.Result{RequeueAfter: time.Minute * 2}, nil
+			r.updateStatus(ctx, akamaiProperty, PhaseError, "FailedToUpdateRules", akamai.ErrorMessage(err))
+			return r.retryAfterError(akamaiProperty, err), nil
 		}
 		if rulesUpdated {
 			logger.Info("Successfully updated property rules", "propertyID", akamaiProperty.Status.PropertyID)
@@ -152,21 +286,113 @@ func (r *AkamaiPropertyReconciler) reconcileProperty(ctx context.Context, akamai
 		logger.V(1).Info("Property is up to date, no update needed", "propertyID", akamaiProperty.Status.PropertyID)
 	}
 
-	// Handle activation if specified
-	if akamaiProperty.Spec.Activation != nil {
-		activationResult, err := r.handleActivation(ctx, akamaiProperty)
+	// Nothing was written, so there is nothing to activate, promote or record as applied
+	if r.ObserveOnly {
+		return r.observed(ctx, akamaiProperty)
+	}
+	if plan != nil {
+		return r.completeDryRun(ctx, akamaiProperty, plan)
+	}
+
+	// The version now holds both the property and the rules changes
+	if err := r.clearCheckpoint(ctx, akamaiProperty); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// Handle activation if specified, not left to other tooling and not frozen
+	frozen := activationsFrozen(akamaiProperty)
+	if frozen {
+		logger.V(1).Info("Activations are frozen", "annotation", AnnotationFreezeActivations)
+	}
+	if akamaiProperty.Spec.Activation != nil && managesActivation(akamaiProperty) && !frozen {
+		handle := r.handleActivation
+		if activationResourcesMode(akamaiProperty) {
+			handle = r.handleActivationResources
+		}
+		activationResult, err := handle(ctx, akamaiProperty)
 		if err != nil {
+			if result, denied := r.permissionsInsufficient(ctx, akamaiProperty, accessWrite, "activate property", err); denied {
+				return result, nil
+			}
 			logger.Error(err, "Failed to handle activation")
-			r.updateStatus(ctx, akamaiProperty, PhaseError, "FailedToHandleActivation", err.Error())
-			return ctrl.Result{RequeueAfter: time.Minute * 2}, nil
+			r.updateStatus(ctx, akamaiProperty, PhaseError, "FailedToHandleActivation", akamai.ErrorMessage(err))
+			return r.retryAfterError(akamaiProperty, err), nil
 		}
 		if activationResult.Requeue {
 			return activationResult, nil
 		}
 	}
 
+	// Promote a version tested on staging when an external tool asks for it
+	promotionResult, err := r.handlePromotion(ctx, akamaiProperty)
+	if err != nil {
+		if result, denied := r.permissionsInsufficient(ctx, akamaiProperty, accessWrite, "activate property", err); denied {
+			return result, nil
+		}
+		logger.Error(err, "Failed to promote property version")
+		r.updateStatus(ctx, akamaiProperty, PhaseError, "FailedToPromoteVersion", akamai.ErrorMessage(err))
+		return r.retryAfterError(akamaiProperty, err), nil
+	}
+	if !promotionResult.IsZero() {
+		return promotionResult, nil
+	}
+
+	// Summarize which hostnames are served on production; the summary is informational only
+	if err := r.updateServing(ctx, akamaiProperty); err != nil {
+		logger.Error(err, "Failed to update serving summary")
+	}
+
+	// Surface the domain validation records of Default DV certificates for DNS automation
+	if err := r.updateHostnameCertificates(ctx, akamaiProperty); err != nil {
+		logger.Error(err, "Failed to update hostname certificates")
+	}
+
+	// Delete the edge hostnames the property created and no longer uses
+	if err := r.collectEdgeHostnames(ctx, akamaiProperty); err != nil {
+		logger.Error(err, "Failed to collect unused edge hostnames")
+	}
+
+	// Point the hostnames served on production at their edge hostnames in DNS
+	if err := r.syncCNAMEs(ctx, akamaiProperty); err != nil {
+		logger.Error(err, "Failed to sync CNAMEs")
+		r.recordEvent(akamaiProperty, corev1.EventTypeWarning, ReasonCNAMEsFailed, "SyncCNAMEs", akamai.ErrorMessage(err))
+	}
+
+	// Publish the edge endpoints mapping if requested
+	if err := r.publishEdgeEndpoints(ctx, akamaiProperty); err != nil {
+		logger.Error(err, "Failed to publish edge endpoints")
+		r.updateStatus(ctx, akamaiProperty, PhaseError, "FailedToPublishEdgeEndpoints", akamai.ErrorMessage(err))
+		return r.retryAfterError(akamaiProperty, err), nil
+	}
+
+	// Let external drift detectors compare rendered manifests against what is deployed
+	if err := r.recordAppliedChecksum(ctx, akamaiProperty); err != nil {
+		logger.Error(err, "Failed to record applied checksum")
+		r.updateStatus(ctx, akamaiProperty, PhaseError, "FailedToRecordAppliedChecksum", akamai.ErrorMessage(err))
+		return r.retryAfterError(akamaiProperty, err), nil
+	}
+
+	// The generation is fully reconciled
+	if clearPermissionsInsufficient(akamaiProperty) || akamaiProperty.Status.ObservedGeneration != akamaiProperty.Generation {
+		akamaiProperty.Status.ObservedGeneration = akamaiProperty.Generation
+		if err := r.updateStatusWithRetry(ctx, akamaiProperty); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	// Certificates waiting for their validation records are polled until they are deployed
+	requeueAfter := time.Minute * 30
+	if certificatesAwaitingValidation(akamaiProperty) {
+		requeueAfter = certificateValidationPollInterval
+	}
+
+	if frozen {
+		r.updateStatus(ctx, akamaiProperty, PhaseReady, ReasonActivationsFrozen,
+			fmt.Sprintf("Activations are held back by the %s annotation", AnnotationFreezeActivations))
+		return ctrl.Result{RequeueAfter: requeueAfter}, nil
+	}
 	r.updateStatus(ctx, akamaiProperty, PhaseReady, "PropertyIsReady", "")
-	return ctrl.Result{RequeueAfter: time.Minute * 30}, nil
+	return ctrl.Result{RequeueAfter: requeueAfter}, nil
 }
 
 // handleDeletion handles the deletion of the AkamaiProperty resource
@@ -177,20 +403,77 @@ func (r *AkamaiPropertyReconciler) handleDeletion(ctx context.Context, akamaiPro
 		// Update status to indicate deletion is in progress
 		r.updateStatus(ctx, akamaiProperty, PhaseDeleting, "DeletingAkamaiProperty", "")
 
-		// Delete the property from Akamai if it exists
-		if akamaiProperty.Status.PropertyID != "" {
-			logger.Info("Deleting Akamai property", "propertyID", akamaiProperty.Status.PropertyID)
-
-			err := r.AkamaiClient.DeleteProperty(ctx, akamaiProperty.Status.PropertyID)
+		// Nothing is deactivated or deleted in Akamai in observe-only mode or a dry run
+		policy := deletionPolicy(akamaiProperty)
+		if r.readOnly(akamaiProperty) {
+			policy = akamaiV1alpha1.DeletionPolicyRetain
+		}
+		switch {
+		case akamaiProperty.Status.PropertyID == "":
+			// Nothing was created in Akamai
+		case policy == akamaiV1alpha1.DeletionPolicyRetain:
+			logger.Info("Retaining Akamai property", "propertyID", akamaiProperty.Status.PropertyID)
+		default:
+			// Akamai refuses to remove a property while a version is active, so deactivate it
+			// on both networks first and wait for the deactivations to complete
+			inactive, err := r.deactivateProperty(ctx, akamaiProperty, "STAGING", "PRODUCTION")
 			if err != nil {
+				if result, denied := r.permissionsInsufficient(ctx, akamaiProperty, accessWrite, "deactivate property", err); denied {
+					return result, nil
+				}
+				logger.Error(err, "Failed to deactivate Akamai property")
+				r.updateStatus(ctx, akamaiProperty, PhaseError, "FailedToDeactivateProperty", akamai.ErrorMessage(err))
+				return r.retryAfterError(akamaiProperty, err), nil
+			}
+			if !inactive {
+				r.updateStatus(ctx, akamaiProperty, PhaseDeleting, "DeactivatingAkamaiProperty", "Waiting for the property to be deactivated")
+				return ctrl.Result{RequeueAfter: r.versionPollInterval()}, nil
+			}
+			if policy == akamaiV1alpha1.DeletionPolicyDeactivate {
+				logger.Info("Deactivated Akamai property, keeping it", "propertyID", akamaiProperty.Status.PropertyID)
+				break
+			}
+
+			logger.Info("Deleting Akamai property", "propertyID", akamaiProperty.Status.PropertyID)
+			if err := r.AkamaiClient.DeleteProperty(ctx, akamaiProperty.Status.PropertyID); err != nil {
+				if result, denied := r.permissionsInsufficient(ctx, akamaiProperty, accessWrite, "delete property", err); denied {
+					return result, nil
+				}
 				logger.Error(err, "Failed to delete Akamai property")
-				r.updateStatus(ctx, akamaiProperty, PhaseError, "FailedToDeleteProperty", err.Error())
-				return ctrl.Result{RequeueAfter: time.Minute * 2}, nil
+				r.updateStatus(ctx, akamaiProperty, PhaseError, "FailedToDeleteProperty", akamai.ErrorMessage(err))
+				return r.retryAfterError(akamaiProperty, err), nil
 			}
 
 			logger.Info("Successfully deleted Akamai property", "propertyID", akamaiProperty.Status.PropertyID)
 		}
 
+		// Give up the activation slots and queue positions of the property
+		r.ActivationScheduler.Release(activationKey(r.AkamaiClient, akamaiProperty, "STAGING"))
+		r.ActivationScheduler.Release(activationKey(r.AkamaiClient, akamaiProperty, "PRODUCTION"))
+
+		// Garbage collect edge hostnames no other property references anymore; a kept property
+		// still references its edge hostnames
+		if policy == akamaiV1alpha1.DeletionPolicyDelete {
+			if err := r.releaseEdgeHostnames(ctx, akamaiProperty); err != nil {
+				logger.Error(err, "Failed to release edge hostnames")
+				return r.retryAfterError(akamaiProperty, err), nil
+			}
+		}
+
+		// The hostnames of a deleted or deactivated property are no longer served
+		if policy != akamaiV1alpha1.DeletionPolicyRetain {
+			if err := r.removeCNAMEs(ctx, akamaiProperty); err != nil {
+				logger.Error(err, "Failed to remove CNAMEs")
+				return r.retryAfterError(akamaiProperty, err), nil
+			}
+		}
+
+		// Remove the property from the edge endpoints ConfigMap
+		if err := r.removeEdgeEndpoints(ctx, akamaiProperty); err != nil {
+			logger.Error(err, "Failed to remove edge endpoints")
+			return r.retryAfterError(akamaiProperty, err), nil
+		}
+
 		// Remove the finalizer
 		controllerutil.RemoveFinalizer(akamaiProperty, FinalizerName)
 		if err := r.Update(ctx, akamaiProperty); err != nil {
@@ -213,10 +496,11 @@ func (r *AkamaiPropertyReconciler) needsUpdate(desired *akamaiV1alpha1.AkamaiPro
 
 	// Compare hostnames if specified in the desired state
 	if len(desired.Spec.Hostnames) > 0 {
-		if akamai.CompareHostnames(desired.Spec.Hostnames, current.Hostnames) {
+		add, remove := akamai.ComputeHostnameDelta(desired.Spec.Hostnames, current.Hostnames, desired.Status.ManagedHostnames)
+		if len(add) > 0 || len(remove) > 0 {
 			logger.V(1).Info("Hostnames differ, update needed",
-				"desiredCount", len(desired.Spec.Hostnames),
-				"currentCount", len(current.Hostnames))
+				"add", len(add),
+				"remove", len(remove))
 			return true
 		}
 	}
@@ -225,3 +509,28 @@ func (r *AkamaiPropertyReconciler) needsUpdate(desired *akamaiV1alpha1.AkamaiPro
 	logger.V(1).Info("Property is up to date", "propertyName", current.PropertyName)
 	return false
 }
+
+// hostnameNames returns the cnameFrom values of the given hostnames
+func hostnameNames(hostnames []akamaiV1alpha1.Hostname) []string {
+	names := make([]string, 0, len(hostnames))
+	for _, h := range hostnames {
+		names = append(names, h.CNAMEFrom)
+	}
+	return names
+}
+
+// recordEvent emits an event about the property when a recorder is configured
+func (r *AkamaiPropertyReconciler) recordEvent(akamaiProperty *akamaiV1alpha1.AkamaiProperty, eventType, reason, action, note string, args ...interface{}) {
+	if r.Recorder == nil {
+		return
+	}
+	r.Recorder.Eventf(akamaiProperty, nil, eventType, reason, action, note, args...)
+}
+
+// versionPollInterval returns the interval used to poll a version that is not yet editable
+func (r *AkamaiPropertyReconciler) versionPollInterval() time.Duration {
+	if r.VersionPollInterval > 0 {
+		return r.VersionPollInterval
+	}
+	return time.Second * 30
+}
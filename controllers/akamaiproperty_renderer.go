@@ -0,0 +1,207 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+	"github.com/mmz-srf/akamai-operator/pkg/render"
+)
+
+// rendererType returns the renderer type of the property, defaulting to Inline
+func rendererType(akamaiProperty *akamaiV1alpha1.AkamaiProperty) akamaiV1alpha1.RendererType {
+	if akamaiProperty.Spec.Renderer == nil || akamaiProperty.Spec.Renderer.Type == "" {
+		return akamaiV1alpha1.RendererTypeInline
+	}
+	return akamaiProperty.Spec.Renderer.Type
+}
+
+// defaultRulesFetcher loads spec.rulesFrom for reconcilers without a RulesFetcher
+var defaultRulesFetcher = &render.Fetcher{}
+
+// managesRules reports whether the operator manages the rule tree of the property, either from
+// spec.rules, spec.rulesFrom or a renderer, unless spec.manage.rules leaves it to other tooling
+func managesRules(akamaiProperty *akamaiV1alpha1.AkamaiProperty) bool {
+	if akamaiProperty.Spec.Manage != nil && !managedByDefault(akamaiProperty.Spec.Manage.Rules) {
+		return false
+	}
+	return akamaiProperty.Spec.Rules != nil || akamaiProperty.Spec.RulesFrom != nil ||
+		rendererType(akamaiProperty) != akamaiV1alpha1.RendererTypeInline || hasTypedBehaviors(akamaiProperty)
+}
+
+// rendersRules reports whether the base rule tree is only known once rendered rather than given
+// by spec.rules
+func rendersRules(akamaiProperty *akamaiV1alpha1.AkamaiProperty) bool {
+	return akamaiProperty.Spec.RulesFrom != nil || rendererType(akamaiProperty) != akamaiV1alpha1.RendererTypeInline
+}
+
+// validateRenderer checks that the renderer configuration is complete
+func (r *AkamaiPropertyReconciler) validateRenderer(akamaiProperty *akamaiV1alpha1.AkamaiProperty) error {
+	if err := validateRulesFrom(akamaiProperty); err != nil {
+		return err
+	}
+	spec := akamaiProperty.Spec.Renderer
+	switch rendererType(akamaiProperty) {
+	case akamaiV1alpha1.RendererTypeInline:
+		return nil
+	case akamaiV1alpha1.RendererTypeTemplate, akamaiV1alpha1.RendererTypePipeline:
+		if spec.ConfigMapRef == nil && akamaiProperty.Spec.RulesFrom == nil {
+			return fmt.Errorf("renderer %s requires configMapRef or rulesFrom", spec.Type)
+		}
+	case akamaiV1alpha1.RendererTypeExternal:
+		if spec.External == "" {
+			return fmt.Errorf("renderer External requires external")
+		}
+		if _, ok := r.Renderers[spec.External]; !ok {
+			return fmt.Errorf("external renderer %q is not registered with the operator", spec.External)
+		}
+	default:
+		return fmt.Errorf("unknown renderer %q", spec.Type)
+	}
+	if akamaiProperty.Spec.Rules != nil {
+		return fmt.Errorf("rules must be empty with renderer %s", spec.Type)
+	}
+	return nil
+}
+
+// validateRulesFrom checks that spec.rulesFrom names exactly one source and replaces the other
+// sources of the rule tree
+func validateRulesFrom(akamaiProperty *akamaiV1alpha1.AkamaiProperty) error {
+	source := akamaiProperty.Spec.RulesFrom
+	if source == nil {
+		return nil
+	}
+	if (source.URL == "") == (source.OCIRef == "") {
+		return fmt.Errorf("rulesFrom requires exactly one of url and ociRef")
+	}
+	if source.URL != "" && !strings.HasPrefix(source.URL, "https://") {
+		return fmt.Errorf("rulesFrom url %q must use https", source.URL)
+	}
+	if source.OCIRef != "" {
+		if _, _, _, err := render.ParseOCIRef(source.OCIRef); err != nil {
+			return fmt.Errorf("rulesFrom: %w", err)
+		}
+	}
+	if akamaiProperty.Spec.Rules != nil {
+		return fmt.Errorf("rules must be empty with rulesFrom")
+	}
+	if akamaiProperty.Spec.Renderer != nil && akamaiProperty.Spec.Renderer.ConfigMapRef != nil {
+		return fmt.Errorf("renderer configMapRef must be empty with rulesFrom")
+	}
+	return nil
+}
+
+// renderer returns the renderer of the property
+func (r *AkamaiPropertyReconciler) renderer(akamaiProperty *akamaiV1alpha1.AkamaiProperty) (render.Renderer, error) {
+	switch rendererType(akamaiProperty) {
+	case akamaiV1alpha1.RendererTypeTemplate:
+		return render.Template{}, nil
+	case akamaiV1alpha1.RendererTypePipeline:
+		return render.Pipeline{}, nil
+	case akamaiV1alpha1.RendererTypeExternal:
+		renderer, ok := r.Renderers[akamaiProperty.Spec.Renderer.External]
+		if !ok {
+			return nil, fmt.Errorf("external renderer %q is not registered with the operator", akamaiProperty.Spec.Renderer.External)
+		}
+		return renderer, nil
+	default:
+		if akamaiProperty.Spec.RulesFrom != nil {
+			return render.File{}, nil
+		}
+		return render.Inline{}, nil
+	}
+}
+
+// renderRules produces the rule tree of the property with its renderer, resolving the
+// ConfigMap referenced by spec.renderer.configMapRef or loading spec.rulesFrom. It returns the
+// digest of the content of spec.rulesFrom with the rule tree.
+func (r *AkamaiPropertyReconciler) renderRules(ctx context.Context, akamaiProperty *akamaiV1alpha1.AkamaiProperty) (*akamaiV1alpha1.PropertyRules, string, error) {
+	renderer, err := r.renderer(akamaiProperty)
+	if err != nil {
+		return nil, "", err
+	}
+
+	input := render.Input{Property: akamaiProperty}
+	if spec := akamaiProperty.Spec.Renderer; spec != nil {
+		input.Entry = spec.Entry
+		input.Values = spec.Values
+		if ref := spec.ConfigMapRef; ref != nil {
+			var configMap corev1.ConfigMap
+			if err := r.Get(ctx, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, &configMap); err != nil {
+				return nil, "", fmt.Errorf("failed to get renderer ConfigMap %s/%s: %w", ref.Namespace, ref.Name, err)
+			}
+			input.Files = configMap.Data
+		}
+	}
+	var digest string
+	if akamaiProperty.Spec.RulesFrom != nil {
+		fetched, err := r.fetchRulesFrom(ctx, akamaiProperty.Spec.RulesFrom, input.Entry)
+		if err != nil {
+			return nil, "", err
+		}
+		input.Files, digest = fetched.Files, fetched.Digest
+	}
+
+	rules, err := renderer.Render(ctx, input)
+	if err != nil {
+		return nil, "", fmt.Errorf("renderer %s failed: %w", rendererType(akamaiProperty), err)
+	}
+	return rules, digest, nil
+}
+
+// fetchRulesFrom loads the files of spec.rulesFrom with the credentials of its Secret
+func (r *AkamaiPropertyReconciler) fetchRulesFrom(ctx context.Context, source *akamaiV1alpha1.RulesSource, entry string) (*render.Fetched, error) {
+	remote := render.Remote{URL: source.URL, OCIRef: source.OCIRef, Checksum: source.Checksum, Entry: entry}
+	if ref := source.SecretRef; ref != nil {
+		var secret corev1.Secret
+		if err := r.Get(ctx, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, &secret); err != nil {
+			return nil, fmt.Errorf("failed to get rulesFrom secret %s/%s: %w", ref.Namespace, ref.Name, err)
+		}
+		remote.Username = string(secret.Data["username"])
+		remote.Password = string(secret.Data["password"])
+	}
+
+	fetcher := r.RulesFetcher
+	if fetcher == nil {
+		fetcher = defaultRulesFetcher
+	}
+	fetched, err := fetcher.Fetch(ctx, remote)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load rulesFrom: %w", err)
+	}
+	return fetched, nil
+}
+
+// recordRulesDigest records the digest of the content of spec.rulesFrom, the values resolved
+// from Secrets and ConfigMaps and the CP codes the rules in Akamai match and reports whether any
+// of them changed. The status is persisted by the caller.
+func (r *AkamaiPropertyReconciler) recordRulesDigest(akamaiProperty *akamaiV1alpha1.AkamaiProperty, sources rulesSources) bool {
+	changed := false
+	if akamaiProperty.Status.ValuesDigest != sources.valuesDigest || !slices.Equal(akamaiProperty.Status.ValueSources, sources.valueSources) {
+		if akamaiProperty.Status.ValuesDigest != "" && sources.valuesDigest != "" {
+			r.recordEvent(akamaiProperty, corev1.EventTypeNormal, ReasonRulesSourceChanged, "Apply",
+				"Property %s follows the values of %s", akamaiProperty.Spec.PropertyName, strings.Join(sources.valueSources, ", "))
+		}
+		akamaiProperty.Status.ValuesDigest = sources.valuesDigest
+		akamaiProperty.Status.ValueSources = sources.valueSources
+		changed = true
+	}
+	if !slices.Equal(akamaiProperty.Status.CPCodes, sources.cpCodes) {
+		akamaiProperty.Status.CPCodes = sources.cpCodes
+		changed = true
+	}
+	if akamaiProperty.Status.RulesDigest == sources.digest {
+		return changed
+	}
+	akamaiProperty.Status.RulesDigest = sources.digest
+	if sources.digest != "" {
+		r.recordEvent(akamaiProperty, corev1.EventTypeNormal, ReasonRulesSourceChanged, "Apply",
+			"Property %s follows the rules of rulesFrom at %s", akamaiProperty.Spec.PropertyName, sources.digest)
+	}
+	return true
+}
@@ -0,0 +1,52 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+	"github.com/mmz-srf/akamai-operator/pkg/akamai"
+)
+
+// errIncompatibleRuleFormat marks a rule format the product does not support
+var errIncompatibleRuleFormat = errors.New("incompatible rule format")
+
+// checkRuleFormat verifies that the rule format exists and is supported by the product, so an
+// incompatible combination fails validation instead of PAPI rejecting the property later.
+// Errors wrapping errIncompatibleRuleFormat are spec errors; any other error is transient.
+func (r *AkamaiPropertyReconciler) checkRuleFormat(ctx context.Context, akamaiProperty *akamaiV1alpha1.AkamaiProperty) error {
+	if !r.CheckRuleFormats {
+		return nil
+	}
+
+	ruleFormat := akamai.RuleFormatOrDefault(akamaiProperty.Spec.RuleFormat)
+	if ruleFormat != akamai.RuleFormatLatest {
+		ruleFormats, err := r.AkamaiClient.ListRuleFormats(ctx)
+		if err != nil {
+			return err
+		}
+		if !containsString(ruleFormats, ruleFormat) {
+			return fmt.Errorf("%w: rule format %s does not exist", errIncompatibleRuleFormat, ruleFormat)
+		}
+	}
+
+	supported, err := r.AkamaiClient.IsRuleFormatSupported(ctx, akamaiProperty.Spec.ProductID, ruleFormat)
+	if err != nil {
+		return err
+	}
+	if !supported {
+		return fmt.Errorf("%w: rule format %s is not supported for product %s", errIncompatibleRuleFormat, ruleFormat, akamaiProperty.Spec.ProductID)
+	}
+	return nil
+}
+
+// containsString reports whether list contains value
+func containsString(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
@@ -3,21 +3,25 @@ package controllers
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 
 	"sigs.k8s.io/controller-runtime/pkg/log"
 
 	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+	"github.com/mmz-srf/akamai-operator/pkg/akamai"
 )
 
-// updateRulesIfNeeded checks if rules need to be updated and updates them if necessary
-func (r *AkamaiPropertyReconciler) updateRulesIfNeeded(ctx context.Context, akamaiProperty *akamaiV1alpha1.AkamaiProperty) (bool, error) {
-	logger := log.FromContext(ctx)
+// maxEtagConflictRetries bounds the attempts to update rules that keep being modified concurrently
+const maxEtagConflictRetries = 3
 
-	// Validate the rules configuration first
-	if err := r.validatePropertyRules(akamaiProperty.Spec.Rules); err != nil {
-		return false, fmt.Errorf("rule validation failed: %w", err)
-	}
+// errVersionNotEditable signals that the version to update is locked by a pending activation
+var errVersionNotEditable = errors.New("property version is not editable")
+
+// updateRulesIfNeeded checks if rules need to be updated and updates them if necessary. A dry
+// run records the changes in plan instead.
+func (r *AkamaiPropertyReconciler) updateRulesIfNeeded(ctx context.Context, akamaiProperty *akamaiV1alpha1.AkamaiProperty, plan *dryRunPlan) (bool, error) {
+	logger := log.FromContext(ctx)
 
 	// Always inspect the existing latest version first (avoid premature version bumps)
 	latestVersion := akamaiProperty.Status.LatestVersion
@@ -32,74 +36,228 @@ func (r *AkamaiPropertyReconciler) updateRulesIfNeeded(ctx context.Context, akam
 	}
 
 	// Determine if a rules update is actually required
-	needsUpdate, err := r.rulesNeedUpdate(akamaiProperty.Spec.Rules, currentRules.Rules)
+	desiredRules, sources, err := r.desiredRules(ctx, akamaiProperty)
 	if err != nil {
-		return false, fmt.Errorf("failed to compare rules: %w", err)
+		return false, fmt.Errorf("failed to render desired rules: %w", err)
+	}
+	// checkSpecValidity only sees the spec; the rule tree that is written also carries typed
+	// behaviors, origins, references and resolved placeholders, so it is checked on every render
+	if err := r.validatePropertyRules(desiredRules); err != nil {
+		return false, fmt.Errorf("desired rules are invalid: %w", err)
+	}
+	if err := r.lintPropertyRules(ctx, akamaiProperty, desiredRules); err != nil {
+		return false, err
+	}
+	if err := r.checkRulesSchema(ctx, akamaiProperty, desiredRules); err != nil {
+		return false, fmt.Errorf("desired rules are invalid: %w", err)
+	}
+	specRules := desiredRules
+	preservedNames := r.preservedBehaviorNames(akamaiProperty)
+	desiredRules, err = r.mergeLastApplied(ctx, akamaiProperty, specRules, currentRules.Rules)
+	if err != nil {
+		return false, fmt.Errorf("failed to merge with the last applied rules: %w", err)
+	}
+	desiredRules, preserved, err := preserveBehaviors(desiredRules, currentRules.Rules, preservedNames)
+	if err != nil {
+		return false, fmt.Errorf("failed to preserve behaviors: %w", err)
+	}
+	preservedChanged := r.recordPreservedBehaviors(ctx, akamaiProperty, preserved)
+
+	versionNotes := renderVersionNotes(akamaiProperty)
+	needsUpdate, err := r.versionNeedsUpdate(ctx, desiredRules, versionNotes, currentRules, sources.secretOptions)
+	if err != nil {
+		return false, err
+	}
+
+	// Rules changed outside the operator are only overwritten according to the drift policy;
+	// differing version notes alone, e.g. from synchronized labels, are always updated
+	rulesDiffer := false
+	if needsUpdate {
+		if rulesDiffer, err = r.rulesNeedUpdate(desiredRules, currentRules.Rules, sources.secretOptions); err != nil {
+			return false, fmt.Errorf("failed to compare rules: %w", err)
+		}
+	}
+	// New content of spec.rulesFrom or new values of referenced Secrets and ConfigMaps are a
+	// change of the spec rather than drift
+	sourceChanged := sources.digest != akamaiProperty.Status.RulesDigest ||
+		sources.valuesDigest != akamaiProperty.Status.ValuesDigest
+	if !rulesDiffer || (sourceChanged && !r.ObserveOnly) {
+		r.resolveDrift(ctx, akamaiProperty, driftRules)
+	} else if !r.handleDrift(ctx, akamaiProperty, driftRules) {
+		return false, nil
+	}
+	if needsUpdate && plan != nil {
+		return false, r.planRulesUpdate(plan, desiredRules, versionNotes, currentRules, rulesDiffer)
+	}
+	if needsUpdate && r.ObserveOnly {
+		logger.V(1).Info("Not updating property version notes in observe-only mode", "propertyID", akamaiProperty.Status.PropertyID)
+		return false, nil
 	}
 	if !needsUpdate {
 		// No change -> do not create a new version even if published
 		logger.V(1).Info("Property rules are up to date; no version bump", "propertyID", akamaiProperty.Status.PropertyID, "version", latestVersion)
+		appliedChanged := r.recordLastApplied(ctx, akamaiProperty, specRules, sources.secretOptions)
+		if r.recordRulesDigest(akamaiProperty, sources) || preservedChanged || appliedChanged {
+			if err := r.updateStatusWithRetry(ctx, akamaiProperty); err != nil {
+				return false, fmt.Errorf("failed to record preserved behaviors: %w", err)
+			}
+		}
 		return false, nil
 	}
 
-	// We have a change. Only now decide whether we need a new version (if the current is published)
-	isPublished, publishedNetwork, err := r.AkamaiClient.IsVersionPublished(ctx, akamaiProperty.Status.PropertyID, latestVersion)
+	// We have a change. Only now pick the version to write to according to the version strategy.
+	versionToUpdate, err := r.editableVersion(ctx, akamaiProperty)
 	if err != nil {
-		return false, fmt.Errorf("failed to check if version %d is published: %w", latestVersion, err)
+		return false, err
 	}
 
-	versionToUpdate := latestVersion
-	if isPublished {
-		// Create a new editable version cloned from the published one
-		logger.Info("Latest version is published; creating new version for rules update",
-			"currentVersion", latestVersion,
-			"publishedNetwork", publishedNetwork)
+	logger.Info("Property rules need updating", "propertyID", akamaiProperty.Status.PropertyID, "targetVersion", versionToUpdate)
+	if rulesDiffer {
+		r.recordRulesDiff(ctx, akamaiProperty, desiredRules, currentRules.Rules, versionToUpdate)
+	}
+	r.updateStatus(ctx, akamaiProperty, PhaseUpdating, "UpdatingPropertyRules", "")
+	if err := r.setCheckpoint(ctx, akamaiProperty, CheckpointUpdateRules, versionToUpdate); err != nil {
+		return false, fmt.Errorf("failed to record checkpoint: %w", err)
+	}
 
-		newVersion, err := r.AkamaiClient.UpdateProperty(ctx, akamaiProperty.Status.PropertyID, &akamaiProperty.Spec)
+	// The etag read above only guards the version it was read from; a new version is re-read so
+	// the write is guarded by its own etag and keeps the preserved behaviors it was created with
+	if versionToUpdate != latestVersion {
+		currentRules, desiredRules, err = r.rereadRules(ctx, akamaiProperty, versionToUpdate, specRules, preservedNames)
 		if err != nil {
-			return false, fmt.Errorf("failed to create new property version for rules update: %w", err)
-		}
-
-		versionToUpdate = newVersion
-		akamaiProperty.Status.LatestVersion = newVersion
-		if err := r.updateStatusWithRetry(ctx, akamaiProperty); err != nil {
-			return false, fmt.Errorf("failed to update status with new version: %w", err)
+			return false, err
 		}
-
-		// Refresh currentRules etag if needed (optional: not strictly required because we'll overwrite rules)
-		logger.Info("Created new version for rules update", "newVersion", newVersion)
 	}
-
-	logger.Info("Property rules need updating", "propertyID", akamaiProperty.Status.PropertyID, "targetVersion", versionToUpdate)
-	r.updateStatus(ctx, akamaiProperty, PhaseUpdating, "UpdatingPropertyRules", "")
+	etag := currentRules.Etag
 
 	// Convert desired rules to Akamai expected format
-	rulesInterface, err := r.convertRulesToAkamaiFormat(akamaiProperty.Spec.Rules)
+	rulesInterface, err := convertRulesToAkamaiFormat(desiredRules)
 	if err != nil {
 		return false, fmt.Errorf("failed to convert rules to Akamai format: %w", err)
 	}
 
-	// Perform the update against the chosen version
-	updatedRules, err := r.AkamaiClient.UpdatePropertyRules(ctx,
-		akamaiProperty.Status.PropertyID,
-		versionToUpdate,
-		akamaiProperty.Spec.ContractID,
-		akamaiProperty.Spec.GroupID,
-		rulesInterface,
-		currentRules.Etag)
-	if err != nil {
-		return false, fmt.Errorf("failed to update property rules: %w", err)
+	// Perform the update against the chosen version, re-reading the rules when someone edited
+	// the version concurrently
+	var updatedRules *akamai.PropertyRules
+	for attempt := 1; ; attempt++ {
+		updatedRules, err = r.AkamaiClient.UpdatePropertyRules(ctx,
+			akamaiProperty.Status.PropertyID,
+			versionToUpdate,
+			akamaiProperty.Spec.ContractID,
+			akamaiProperty.Spec.GroupID,
+			rulesInterface,
+			etag,
+			versionNotes)
+		if err == nil {
+			break
+		}
+		if !errors.Is(err, akamai.ErrEtagConflict) || attempt >= maxEtagConflictRetries {
+			return false, fmt.Errorf("failed to update property rules: %w", err)
+		}
+
+		logger.Info("Property rules were modified concurrently; re-reading and retrying",
+			"version", versionToUpdate,
+			"attempt", attempt)
+		currentRules, desiredRules, err = r.rereadRules(ctx, akamaiProperty, versionToUpdate, specRules, preservedNames)
+		if err != nil {
+			return false, err
+		}
+		needsUpdate, err := r.versionNeedsUpdate(ctx, desiredRules, versionNotes, currentRules, sources.secretOptions)
+		if err != nil {
+			return false, err
+		}
+		if !needsUpdate {
+			// The concurrent edit already produced the desired state
+			logger.Info("Property rules match after concurrent edit; nothing to update", "version", versionToUpdate)
+			r.recordRulesDigest(akamaiProperty, sources)
+			r.recordLastApplied(ctx, akamaiProperty, specRules, sources.secretOptions)
+			if err := r.updateStatusWithRetry(ctx, akamaiProperty); err != nil {
+				return false, fmt.Errorf("failed to record preserved behaviors: %w", err)
+			}
+			return false, nil
+		}
+		rulesInterface, err = convertRulesToAkamaiFormat(desiredRules)
+		if err != nil {
+			return false, fmt.Errorf("failed to convert rules to Akamai format: %w", err)
+		}
+		etag = currentRules.Etag
 	}
 
 	logger.Info("Successfully updated property rules",
 		"propertyID", akamaiProperty.Status.PropertyID,
 		"version", versionToUpdate,
-		"newEtag", updatedRules.Etag)
+		"newEtag", updatedRules.Etag,
+		"warnings", len(updatedRules.Warnings))
+
+	akamaiProperty.Status.Validation = validationStatus(updatedRules.Warnings)
+	r.recordRulesDigest(akamaiProperty, sources)
+	r.recordLastApplied(ctx, akamaiProperty, specRules, sources.secretOptions)
+	completeStep(akamaiProperty, CheckpointUpdateRules, versionToUpdate)
+	if err := r.updateStatusWithRetry(ctx, akamaiProperty); err != nil {
+		return true, fmt.Errorf("failed to record validation warnings: %w", err)
+	}
 	return true, nil
 }
 
-// rulesNeedUpdate compares desired rules with current rules to determine if an update is needed
-func (r *AkamaiPropertyReconciler) rulesNeedUpdate(desired *akamaiV1alpha1.PropertyRules, current interface{}) (bool, error) {
+// rereadRules reads the rules of the version to write to and returns them with the spec rules
+// merged with the behaviors preserved in them
+func (r *AkamaiPropertyReconciler) rereadRules(ctx context.Context, akamaiProperty *akamaiV1alpha1.AkamaiProperty, version int, specRules *akamaiV1alpha1.PropertyRules, preservedNames map[string]bool) (*akamai.PropertyRules, *akamaiV1alpha1.PropertyRules, error) {
+	currentRules, err := r.AkamaiClient.GetPropertyRules(ctx,
+		akamaiProperty.Status.PropertyID,
+		version,
+		akamaiProperty.Spec.ContractID,
+		akamaiProperty.Spec.GroupID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to re-read property rules for version %d: %w", version, err)
+	}
+	desiredRules, err := r.mergeLastApplied(ctx, akamaiProperty, specRules, currentRules.Rules)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to merge with the last applied rules: %w", err)
+	}
+	desiredRules, preserved, err := preserveBehaviors(desiredRules, currentRules.Rules, preservedNames)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to preserve behaviors: %w", err)
+	}
+	r.recordPreservedBehaviors(ctx, akamaiProperty, preserved)
+	return currentRules, desiredRules, nil
+}
+
+// validationStatus converts the warnings of a rules update into status.validation
+func validationStatus(warnings []akamai.RuleWarning) *akamaiV1alpha1.ValidationStatus {
+	if len(warnings) == 0 {
+		return nil
+	}
+	status := &akamaiV1alpha1.ValidationStatus{
+		Warnings: make([]akamaiV1alpha1.ValidationWarning, 0, len(warnings)),
+	}
+	for _, w := range warnings {
+		status.Warnings = append(status.Warnings, akamaiV1alpha1.ValidationWarning{
+			Type:     w.Type,
+			Title:    w.Title,
+			Detail:   w.Detail,
+			Location: w.ErrorLocation,
+		})
+	}
+	return status
+}
+
+// versionNeedsUpdate reports whether the rules or the version notes of a version differ from the desired state
+func (r *AkamaiPropertyReconciler) versionNeedsUpdate(ctx context.Context, desiredRules *akamaiV1alpha1.PropertyRules, versionNotes string, currentRules *akamai.PropertyRules, masked maskedOptions) (bool, error) {
+	needsUpdate, err := r.rulesNeedUpdate(desiredRules, currentRules.Rules, masked)
+	if err != nil {
+		return false, fmt.Errorf("failed to compare rules: %w", err)
+	}
+	// Labels synchronized into the version notes also require an update when they drift
+	if !needsUpdate && versionNotes != "" && versionNotes != currentRules.Comments {
+		log.FromContext(ctx).Info("Property version notes differ from synchronized labels", "current", currentRules.Comments, "desired", versionNotes)
+		needsUpdate = true
+	}
+	return needsUpdate, nil
+}
+
+// rulesNeedUpdate compares desired rules with current rules to determine if an update is needed.
+// The masked option values are not logged.
+func (r *AkamaiPropertyReconciler) rulesNeedUpdate(desired *akamaiV1alpha1.PropertyRules, current interface{}, masked maskedOptions) (bool, error) {
 	if desired == nil {
 		return false, nil
 	}
@@ -111,11 +269,11 @@ func (r *AkamaiPropertyReconciler) rulesNeedUpdate(desired *akamaiV1alpha1.Prope
 	}
 
 	// Compare the meaningful parts of the rules
-	return r.compareRulesDeep(desired, currentRules), nil
+	return r.compareRulesDeep(desired, currentRules, masked), nil
 }
 
 // convertRulesToAkamaiFormat converts our PropertyRules to the format expected by Akamai API
-func (r *AkamaiPropertyReconciler) convertRulesToAkamaiFormat(rules *akamaiV1alpha1.PropertyRules) (interface{}, error) {
+func convertRulesToAkamaiFormat(rules *akamaiV1alpha1.PropertyRules) (interface{}, error) {
 	if rules == nil {
 		return nil, fmt.Errorf("rules cannot be nil")
 	}
@@ -0,0 +1,34 @@
+package controllers
+
+import (
+	"strings"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+)
+
+// managedCommentsMarker opens the comment block injected into the top-level rule
+const managedCommentsMarker = "[managed-by akamai-operator]"
+
+// appendManagedComments appends the managed-by block to the user-provided comments,
+// replacing any block left over from a previous injection
+func appendManagedComments(comments string, akamaiProperty *akamaiV1alpha1.AkamaiProperty) string {
+	if idx := strings.Index(comments, managedCommentsMarker); idx != -1 {
+		comments = comments[:idx]
+	}
+	comments = strings.TrimRight(comments, "\n ")
+
+	lines := []string{
+		managedCommentsMarker,
+		"resource: " + akamaiProperty.Name,
+		"uid: " + string(akamaiProperty.UID),
+	}
+	if revision := akamaiProperty.Annotations[AnnotationGitRevision]; revision != "" {
+		lines = append(lines, "git-revision: "+revision)
+	}
+
+	block := strings.Join(lines, "\n")
+	if comments == "" {
+		return block
+	}
+	return comments + "\n\n" + block
+}
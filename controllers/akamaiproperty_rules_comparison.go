@@ -39,7 +39,7 @@ func (r *AkamaiPropertyReconciler) cleanRulesForComparison(rules *akamaiV1alpha1
 	// Normalize empty/default values for top-level fields
 	// Empty criteriaMustSatisfy should be treated same as "all" (Akamai default)
 	if rules.CriteriaMustSatisfy == "" {
-		rules.CriteriaMustSatisfy = "all"
+		rules.CriteriaMustSatisfy = akamaiV1alpha1.CriteriaMustSatisfyAll
 	}
 
 	// Normalize options field - empty object or null should be treated the same
@@ -153,7 +153,7 @@ func (r *AkamaiPropertyReconciler) normalizeOptionsMap(options map[string]interf
 }
 
 // compareRulesDeep performs a deep comparison of two PropertyRules structures
-func (r *AkamaiPropertyReconciler) compareRulesDeep(desired, current *akamaiV1alpha1.PropertyRules) bool {
+func (r *AkamaiPropertyReconciler) compareRulesDeep(desired, current *akamaiV1alpha1.PropertyRules, masked maskedOptions) bool {
 	// Create clean copies for comparison
 	desiredClean := r.copyAndCleanRules(desired)
 	currentClean := r.copyAndCleanRules(current)
@@ -190,10 +190,18 @@ func (r *AkamaiPropertyReconciler) compareRulesDeep(desired, current *akamaiV1al
 	different := string(desiredFinal) != string(currentFinal)
 
 	if different {
+		// Never log the values of sensitive variables or values resolved from Secrets
+		maskSensitiveVariables(desiredNormalized)
+		maskSensitiveVariables(currentNormalized)
+		masked.mask(desiredNormalized)
+		masked.mask(currentNormalized)
+		desiredMasked, _ := json.Marshal(desiredNormalized)
+		currentMasked, _ := json.Marshal(currentNormalized)
+
 		logger := log.FromContext(context.Background())
 		logger.V(1).Info("Rules differ",
-			"desired", string(desiredFinal),
-			"current", string(currentFinal))
+			"desired", string(desiredMasked),
+			"current", string(currentMasked))
 	}
 
 	return different
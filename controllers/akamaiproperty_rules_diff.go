@@ -0,0 +1,210 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+)
+
+// ruleListItems names the items of the lists of a rule compared by name
+var ruleListItems = map[string]string{
+	"behaviors": "behavior",
+	"criteria":  "criterion",
+	"variables": "variable",
+}
+
+const (
+	// maxRulesDiff is the number of changes of a rules update kept in status.lastRulesDiff
+	maxRulesDiff = 20
+
+	// maxRulesDiffEvent is the number of changes of a rules update listed in its event
+	maxRulesDiffEvent = 5
+)
+
+// recordRulesDiff publishes the changes of a rules update in status.lastRulesDiff and a
+// RulesChanged event. The status is persisted with the update.
+func (r *AkamaiPropertyReconciler) recordRulesDiff(ctx context.Context, akamaiProperty *akamaiV1alpha1.AkamaiProperty, desired *akamaiV1alpha1.PropertyRules, current interface{}, version int) {
+	logger := log.FromContext(ctx)
+	changes, err := r.rulesDiff(desired, current)
+	if err != nil {
+		logger.Error(err, "Failed to describe the rules changes")
+		return
+	}
+	logger.Info("Property rules differ", "version", version, "changes", changes)
+	akamaiProperty.Status.LastRulesDiff = truncateChanges(changes, maxRulesDiff)
+	if len(changes) > 0 {
+		r.recordEvent(akamaiProperty, corev1.EventTypeNormal, ReasonRulesChanged, "Update",
+			"Updating the rules of version %d: %s", version, strings.Join(truncateChanges(changes, maxRulesDiffEvent), "; "))
+	}
+}
+
+// truncateChanges keeps the first changes of a list and counts the others
+func truncateChanges(changes []string, limit int) []string {
+	if len(changes) <= limit {
+		return changes
+	}
+	return append(changes[:limit:limit], fmt.Sprintf("... and %d more changes", len(changes)-limit))
+}
+
+// rulesDiff describes the differences between the desired and the current rule tree as
+// human-readable changes, e.g. `Change behavior "caching" of rule "default/Images"`. Rules are
+// matched by their path of names; values are never included, so sensitive variables don't leak.
+func (r *AkamaiPropertyReconciler) rulesDiff(desired *akamaiV1alpha1.PropertyRules, current interface{}) ([]string, error) {
+	currentRules, err := r.normalizeCurrentRules(current)
+	if err != nil {
+		return nil, fmt.Errorf("failed to normalize current rules: %w", err)
+	}
+	desiredTree, err := r.comparableRules(desired)
+	if err != nil {
+		return nil, err
+	}
+	currentTree, err := r.comparableRules(currentRules)
+	if err != nil {
+		return nil, err
+	}
+	name, _ := desiredTree["name"].(string)
+	return diffRule(name, desiredTree, currentTree), nil
+}
+
+// comparableRules returns the rule tree cleaned and normalized the way compareRulesDeep compares it
+func (r *AkamaiPropertyReconciler) comparableRules(rules *akamaiV1alpha1.PropertyRules) (map[string]interface{}, error) {
+	raw, err := json.Marshal(r.copyAndCleanRules(rules))
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal rules: %w", err)
+	}
+	tree := map[string]interface{}{}
+	if err := json.Unmarshal(raw, &tree); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal rules: %w", err)
+	}
+	r.normalizeMapForComparison(tree)
+	return tree, nil
+}
+
+// diffRule describes the differences of a rule and its children
+func diffRule(path string, desired, current map[string]interface{}) []string {
+	keys := make(map[string]bool)
+	for key := range desired {
+		keys[key] = true
+	}
+	for key := range current {
+		keys[key] = true
+	}
+	sorted := make([]string, 0, len(keys))
+	for key := range keys {
+		sorted = append(sorted, key)
+	}
+	sort.Strings(sorted)
+
+	var changes []string
+	for _, key := range sorted {
+		switch {
+		case key == "name":
+		case key == "children":
+			changes = append(changes, diffChildren(path, desired[key], current[key])...)
+		case ruleListItems[key] != "":
+			changes = append(changes, diffNamedList(path, key, desired[key], current[key])...)
+		case !reflect.DeepEqual(desired[key], current[key]):
+			changes = append(changes, fmt.Sprintf("Change %s of rule %q", key, path))
+		}
+	}
+	return changes
+}
+
+// diffChildren describes added, removed, reordered and changed child rules
+func diffChildren(path string, desired, current interface{}) []string {
+	desiredChildren, desiredOrder := namedItems(desired)
+	currentChildren, currentOrder := namedItems(current)
+
+	var changes []string
+	for _, name := range desiredOrder {
+		childPath := path + "/" + name
+		if currentChild, ok := currentChildren[name]; ok {
+			changes = append(changes, diffRule(childPath, desiredChildren[name], currentChild)...)
+		} else {
+			changes = append(changes, fmt.Sprintf("Add rule %q", childPath))
+		}
+	}
+	for _, name := range currentOrder {
+		if _, ok := desiredChildren[name]; !ok {
+			changes = append(changes, fmt.Sprintf("Remove rule %q", path+"/"+name))
+		}
+	}
+	if reordered(desiredOrder, currentOrder, desiredChildren, currentChildren) {
+		changes = append(changes, fmt.Sprintf("Reorder the child rules of rule %q", path))
+	}
+	return changes
+}
+
+// diffNamedList describes added, removed, reordered and changed behaviors, criteria or variables
+func diffNamedList(path, key string, desired, current interface{}) []string {
+	item := ruleListItems[key]
+	desiredItems, desiredOrder := namedItems(desired)
+	currentItems, currentOrder := namedItems(current)
+
+	var changes []string
+	for _, name := range desiredOrder {
+		currentItem, ok := currentItems[name]
+		switch {
+		case !ok:
+			changes = append(changes, fmt.Sprintf("Add %s %q to rule %q", item, name, path))
+		case !reflect.DeepEqual(desiredItems[name], currentItem):
+			changes = append(changes, fmt.Sprintf("Change %s %q of rule %q", item, name, path))
+		}
+	}
+	for _, name := range currentOrder {
+		if _, ok := desiredItems[name]; !ok {
+			changes = append(changes, fmt.Sprintf("Remove %s %q from rule %q", item, name, path))
+		}
+	}
+	if reordered(desiredOrder, currentOrder, desiredItems, currentItems) {
+		changes = append(changes, fmt.Sprintf("Reorder the %s of rule %q", key, path))
+	}
+	return changes
+}
+
+// namedItems indexes a list of objects by name, numbering repeated names as "name #2"
+func namedItems(list interface{}) (map[string]map[string]interface{}, []string) {
+	items, _ := list.([]interface{})
+	byName := make(map[string]map[string]interface{}, len(items))
+	order := make([]string, 0, len(items))
+	for i, entry := range items {
+		object, ok := entry.(map[string]interface{})
+		if !ok {
+			object = map[string]interface{}{}
+		}
+		name, _ := object["name"].(string)
+		if name == "" {
+			name = fmt.Sprintf("#%d", i+1)
+		}
+		for n, base := 2, name; byName[name] != nil; n++ {
+			name = fmt.Sprintf("%s #%d", base, n)
+		}
+		byName[name] = object
+		order = append(order, name)
+	}
+	return byName, order
+}
+
+// reordered reports whether the items both lists have in common are in a different order
+func reordered(desiredOrder, currentOrder []string, desired, current map[string]map[string]interface{}) bool {
+	var desiredCommon, currentCommon []string
+	for _, name := range desiredOrder {
+		if current[name] != nil {
+			desiredCommon = append(desiredCommon, name)
+		}
+	}
+	for _, name := range currentOrder {
+		if desired[name] != nil {
+			currentCommon = append(currentCommon, name)
+		}
+	}
+	return !reflect.DeepEqual(desiredCommon, currentCommon)
+}
@@ -0,0 +1,57 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+	"github.com/mmz-srf/akamai-operator/pkg/lint"
+)
+
+// lintPropertyRules runs the configured lint rules against the rule tree of the property and
+// reports the findings in the RulesLinted condition. An error is returned when a blocking
+// finding is present.
+func (r *AkamaiPropertyReconciler) lintPropertyRules(ctx context.Context, akamaiProperty *akamaiV1alpha1.AkamaiProperty, rules *akamaiV1alpha1.PropertyRules) error {
+	if r.Linter == nil || rules == nil {
+		return nil
+	}
+	logger := log.FromContext(ctx)
+
+	findings := r.Linter.Lint(rules)
+
+	condition := metav1.Condition{
+		Type:               ConditionTypeRulesLinted,
+		Status:             metav1.ConditionTrue,
+		Reason:             "NoFindings",
+		ObservedGeneration: akamaiProperty.Generation,
+	}
+	if len(findings) > 0 {
+		messages := make([]string, 0, len(findings))
+		for _, finding := range findings {
+			messages = append(messages, finding.String())
+			logger.Info("Rules lint finding", "rule", finding.Rule, "severity", finding.Severity, "path", finding.Path, "message", finding.Message)
+		}
+		condition.Reason = "Warnings"
+		condition.Message = strings.Join(messages, "; ")
+		if lint.HasBlocking(findings) {
+			condition.Status = metav1.ConditionFalse
+			condition.Reason = "BlockingFindings"
+		}
+	}
+
+	if meta.SetStatusCondition(&akamaiProperty.Status.Conditions, condition) {
+		if err := r.updateStatusWithRetry(ctx, akamaiProperty); err != nil {
+			return fmt.Errorf("failed to record lint findings: %w", err)
+		}
+	}
+
+	if condition.Status == metav1.ConditionFalse {
+		return fmt.Errorf("rule lint failed: %s", condition.Message)
+	}
+	return nil
+}
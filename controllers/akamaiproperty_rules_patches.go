@@ -0,0 +1,200 @@
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+
+	jsonpatch "github.com/evanphx/json-patch/v5"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+)
+
+// patchDirective is the key of a Merge patch item that removes the item of the same name
+const patchDirective = "$patch"
+
+// ruleListKeys are the lists of a rule whose items a Merge patch matches by name
+var ruleListKeys = map[string]bool{"behaviors": true, "criteria": true, "children": true, "variables": true}
+
+// rulesPatchType returns the type of a rules patch, defaulting to JSONPatch
+func rulesPatchType(patch akamaiV1alpha1.RulesPatch) akamaiV1alpha1.RulesPatchType {
+	if patch.Type == "" {
+		return akamaiV1alpha1.RulesPatchTypeJSONPatch
+	}
+	return patch.Type
+}
+
+// validateRulesPatches checks that spec.rulesPatches patch a base rule tree and can be decoded
+func validateRulesPatches(akamaiProperty *akamaiV1alpha1.AkamaiProperty) error {
+	if len(akamaiProperty.Spec.RulesPatches) == 0 {
+		return nil
+	}
+	if !rendersRules(akamaiProperty) {
+		return fmt.Errorf("rulesPatches require rulesFrom or a renderer; change spec.rules directly instead")
+	}
+	for i, patch := range akamaiProperty.Spec.RulesPatches {
+		if len(patch.Patch.Raw) == 0 {
+			return fmt.Errorf("rulesPatches[%d]: patch is required", i)
+		}
+		switch rulesPatchType(patch) {
+		case akamaiV1alpha1.RulesPatchTypeJSONPatch:
+			if _, err := jsonpatch.DecodePatch(patch.Patch.Raw); err != nil {
+				return fmt.Errorf("rulesPatches[%d]: invalid JSON patch: %w", i, err)
+			}
+		case akamaiV1alpha1.RulesPatchTypeMerge:
+			var partial map[string]interface{}
+			if err := json.Unmarshal(patch.Patch.Raw, &partial); err != nil {
+				return fmt.Errorf("rulesPatches[%d]: a merge patch must be a rule: %w", i, err)
+			}
+		default:
+			return fmt.Errorf("rulesPatches[%d]: unknown patch type %q", i, patch.Type)
+		}
+	}
+	return nil
+}
+
+// applyRulesPatches applies spec.rulesPatches in order to the base rule tree
+func applyRulesPatches(rules *akamaiV1alpha1.PropertyRules, patches []akamaiV1alpha1.RulesPatch) (*akamaiV1alpha1.PropertyRules, error) {
+	if rules == nil || len(patches) == 0 {
+		return rules, nil
+	}
+	doc, err := json.Marshal(rules)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal rules: %w", err)
+	}
+	for i, patch := range patches {
+		if doc, err = applyRulesPatch(doc, patch); err != nil {
+			return nil, fmt.Errorf("rulesPatches[%d]: %w", i, err)
+		}
+	}
+	var patched akamaiV1alpha1.PropertyRules
+	if err := json.Unmarshal(doc, &patched); err != nil {
+		return nil, fmt.Errorf("patched rules are not a rule tree: %w", err)
+	}
+	return &patched, nil
+}
+
+// applyRulesPatch applies one patch to the JSON of a rule tree
+func applyRulesPatch(doc []byte, patch akamaiV1alpha1.RulesPatch) ([]byte, error) {
+	if rulesPatchType(patch) == akamaiV1alpha1.RulesPatchTypeJSONPatch {
+		operations, err := jsonpatch.DecodePatch(patch.Patch.Raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid JSON patch: %w", err)
+		}
+		return operations.Apply(doc)
+	}
+
+	var rule, partial map[string]interface{}
+	if err := json.Unmarshal(doc, &rule); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(patch.Patch.Raw, &partial); err != nil {
+		return nil, fmt.Errorf("a merge patch must be a rule: %w", err)
+	}
+	if err := mergeRule(rule, partial); err != nil {
+		return nil, err
+	}
+	return json.Marshal(rule)
+}
+
+// mergeRule merges a partial rule into a rule. Child rules, behaviors, criteria and variables are
+// matched by name, objects like options are merged as by an RFC 7386 merge patch and all other
+// values are replaced.
+func mergeRule(rule, partial map[string]interface{}) error {
+	for key, value := range partial {
+		if key == patchDirective {
+			continue
+		}
+		if value == nil {
+			delete(rule, key)
+			continue
+		}
+		if !ruleListKeys[key] {
+			rule[key] = mergeValue(rule[key], value)
+			continue
+		}
+		items, ok := value.([]interface{})
+		if !ok {
+			return fmt.Errorf("%s must be a list", key)
+		}
+		existing, _ := rule[key].([]interface{})
+		merged, err := mergeNamedItems(existing, items, key == "children")
+		if err != nil {
+			return fmt.Errorf("%s: %w", key, err)
+		}
+		rule[key] = merged
+	}
+	return nil
+}
+
+// mergeNamedItems merges the items of a patch into the items of the same name, appends items
+// without a match and removes the items marked for deletion
+func mergeNamedItems(items, patches []interface{}, rules bool) ([]interface{}, error) {
+	for _, value := range patches {
+		patch, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("items must be objects")
+		}
+		name, _ := patch["name"].(string)
+		if name == "" {
+			return nil, fmt.Errorf("items must have a name")
+		}
+		index := -1
+		for i, item := range items {
+			if object, ok := item.(map[string]interface{}); ok && object["name"] == name {
+				if index != -1 {
+					return nil, fmt.Errorf("%q matches several items; use a JSONPatch", name)
+				}
+				index = i
+			}
+		}
+
+		switch directive := patch[patchDirective]; directive {
+		case nil:
+		case "delete":
+			if index == -1 {
+				return nil, fmt.Errorf("%q to delete not found", name)
+			}
+			items = append(items[:index:index], items[index+1:]...)
+			continue
+		default:
+			return nil, fmt.Errorf("unknown %s directive %v of %q", patchDirective, directive, name)
+		}
+
+		switch {
+		case index == -1:
+			items = append(items, patch)
+		case rules:
+			child, ok := items[index].(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("child rule %q is not an object", name)
+			}
+			if err := mergeRule(child, patch); err != nil {
+				return nil, fmt.Errorf("%q: %w", name, err)
+			}
+		default:
+			items[index] = mergeValue(items[index], patch)
+		}
+	}
+	return items, nil
+}
+
+// mergeValue merges a patch into a value like an RFC 7386 merge patch: objects are merged key by
+// key, null removes a key and any other value replaces the value
+func mergeValue(value, patch interface{}) interface{} {
+	patchObject, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+	object, ok := value.(map[string]interface{})
+	if !ok {
+		object = map[string]interface{}{}
+	}
+	for key, patchValue := range patchObject {
+		if patchValue == nil {
+			delete(object, key)
+			continue
+		}
+		object[key] = mergeValue(object[key], patchValue)
+	}
+	return object
+}
@@ -0,0 +1,63 @@
+package controllers
+
+import (
+	"context"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+)
+
+// desiredRules returns the rule tree that should be applied to Akamai. The rule tree is produced
+// by the renderer of the property and patched with spec.rulesPatches. Spec variables and the typed
+// behaviors are rendered into the top-level rule, spec origins and the HTTPS redirect into its
+// first child rules and, when comment injection is enabled, a managed-by block is appended to the
+// top-level rule comments. Include references and placeholders in rule options are resolved last.
+// The sources the rule tree was built from are returned with it. The spec itself is never
+// modified.
+func (r *AkamaiPropertyReconciler) desiredRules(ctx context.Context, akamaiProperty *akamaiV1alpha1.AkamaiProperty) (*akamaiV1alpha1.PropertyRules, rulesSources, error) {
+	rules, digest, err := r.renderRules(ctx, akamaiProperty)
+	if err != nil {
+		return nil, rulesSources{}, err
+	}
+	sources := rulesSources{digest: digest}
+	if rules, err = applyRulesPatches(rules, akamaiProperty.Spec.RulesPatches); err != nil {
+		return nil, rulesSources{}, err
+	}
+	if rules == nil && hasTypedBehaviors(akamaiProperty) {
+		rules = &akamaiV1alpha1.PropertyRules{Name: "default"}
+	}
+	if rules == nil {
+		return nil, sources, nil
+	}
+
+	rulesCopy := rules.DeepCopy()
+	if len(akamaiProperty.Spec.Variables) > 0 {
+		variables, err := r.renderVariables(ctx, akamaiProperty.Spec.Variables)
+		if err != nil {
+			return nil, rulesSources{}, err
+		}
+		rulesCopy.Variables = mergeVariables(rulesCopy.Variables, variables)
+	}
+	if err := applyTypedBehaviors(rulesCopy, akamaiProperty); err != nil {
+		return nil, rulesSources{}, err
+	}
+	if akamaiProperty.Spec.Origins != nil {
+		if err := applyOrigins(rulesCopy, akamaiProperty.Spec.Origins); err != nil {
+			return nil, rulesSources{}, err
+		}
+	}
+	r.includeUsers.record(akamaiProperty.Name, includeRefs(rulesCopy))
+	if rulesCopy, err = r.resolveIncludeRefs(ctx, rulesCopy); err != nil {
+		return nil, rulesSources{}, err
+	}
+	if rulesCopy, err = r.resolveCloudletPolicyRefs(ctx, rulesCopy); err != nil {
+		return nil, rulesSources{}, err
+	}
+	if rulesCopy, err = r.resolvePlaceholders(ctx, rulesCopy, &sources); err != nil {
+		return nil, rulesSources{}, err
+	}
+	if r.InjectRuleComments {
+		rulesCopy.Comments = appendManagedComments(rules.Comments, akamaiProperty)
+	}
+	sources.cpCodes = cpCodesFromRules(rulesCopy)
+	return rulesCopy, sources, nil
+}
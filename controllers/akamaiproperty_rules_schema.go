@@ -0,0 +1,80 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+	"github.com/mmz-srf/akamai-operator/pkg/akamai"
+)
+
+// maxReportedSchemaViolations bounds the violations listed in the RulesSchemaValid condition
+const maxReportedSchemaViolations = 10
+
+// errSchemaViolations marks rules that do not match the rule format schema
+var errSchemaViolations = errors.New("rules do not match the rule format schema")
+
+// checkRulesSchema validates the behaviors and criteria of the rules against the PAPI schema of
+// the property's product and rule format and reports the violations in the RulesSchemaValid
+// condition. Errors wrapping errSchemaViolations or errIncompatibleRuleFormat are spec errors;
+// any other error, e.g. failing to fetch the schema, is transient.
+func (r *AkamaiPropertyReconciler) checkRulesSchema(ctx context.Context, akamaiProperty *akamaiV1alpha1.AkamaiProperty, rules *akamaiV1alpha1.PropertyRules) error {
+	if !r.ValidateRuleSchemas || rules == nil || akamaiProperty.Spec.ProductID == "" {
+		return nil
+	}
+
+	ruleFormat := akamai.RuleFormatOrDefault(akamaiProperty.Spec.RuleFormat)
+	schema, err := r.AkamaiClient.GetRuleSchema(ctx, akamaiProperty.Spec.ProductID, ruleFormat)
+	if akamai.IsUnsupportedRuleFormat(err) {
+		return fmt.Errorf("%w: rule format %s is not supported for product %s", errIncompatibleRuleFormat, ruleFormat, akamaiProperty.Spec.ProductID)
+	} else if err != nil {
+		return err
+	}
+	violations, err := schema.ValidateRules(rules)
+	if err != nil {
+		return err
+	}
+
+	condition := rulesSchemaCondition(akamaiProperty.Generation, ruleFormat, violations)
+	if meta.SetStatusCondition(&akamaiProperty.Status.Conditions, condition) {
+		if err := r.updateStatusWithRetry(ctx, akamaiProperty); err != nil {
+			return fmt.Errorf("failed to record rule schema violations: %w", err)
+		}
+	}
+	if len(violations) > 0 {
+		return fmt.Errorf("%w %s: %s", errSchemaViolations, ruleFormat, condition.Message)
+	}
+	return nil
+}
+
+// rulesSchemaCondition builds the RulesSchemaValid condition listing the first violations
+func rulesSchemaCondition(generation int64, ruleFormat string, violations []akamai.SchemaViolation) metav1.Condition {
+	condition := metav1.Condition{
+		Type:               ConditionTypeRulesSchemaValid,
+		Status:             metav1.ConditionTrue,
+		Reason:             "SchemaValid",
+		Message:            fmt.Sprintf("Rules match the schema of rule format %s", ruleFormat),
+		ObservedGeneration: generation,
+	}
+	if len(violations) == 0 {
+		return condition
+	}
+
+	messages := make([]string, 0, min(len(violations), maxReportedSchemaViolations)+1)
+	for i, violation := range violations {
+		if i == maxReportedSchemaViolations {
+			messages = append(messages, fmt.Sprintf("and %d more", len(violations)-maxReportedSchemaViolations))
+			break
+		}
+		messages = append(messages, violation.String())
+	}
+	condition.Status = metav1.ConditionFalse
+	condition.Reason = "SchemaViolations"
+	condition.Message = strings.Join(messages, "; ")
+	return condition
+}
@@ -24,6 +24,21 @@ func (r *AkamaiPropertyReconciler) validatePropertyRules(rules *akamaiV1alpha1.P
 		return fmt.Errorf("top-level rule name should be 'default', got '%s'", rules.Name)
 	}
 
+	if err := validatePlaceholders(rules); err != nil {
+		return err
+	}
+	return r.validateRule(rules)
+}
+
+// validateRule validates a rule and, recursively, its child rules
+func (r *AkamaiPropertyReconciler) validateRule(rules *akamaiV1alpha1.PropertyRules) error {
+	// Validate how criteria are combined; child rules bypass the CRD schema as raw JSON
+	switch rules.CriteriaMustSatisfy {
+	case "", akamaiV1alpha1.CriteriaMustSatisfyAll, akamaiV1alpha1.CriteriaMustSatisfyAny:
+	default:
+		return fmt.Errorf("criteriaMustSatisfy must be 'all' or 'any', got '%s'", rules.CriteriaMustSatisfy)
+	}
+
 	// Validate behaviors
 	for i, behavior := range rules.Behaviors {
 		if err := r.validateRuleBehavior(&behavior, fmt.Sprintf("behavior[%d]", i)); err != nil {
@@ -60,8 +75,11 @@ func (r *AkamaiPropertyReconciler) validatePropertyRules(rules *akamaiV1alpha1.P
 			return fmt.Errorf("invalid child rule at index %d: failed to parse child rule: %w", i, err)
 		}
 
-		if err := r.validatePropertyRules(&child); err != nil {
-			return fmt.Errorf("invalid child rule at index %d: %w", i, err)
+		if child.Name == "" {
+			return fmt.Errorf("invalid child rule at index %d: rule must have a name", i)
+		}
+		if err := r.validateRule(&child); err != nil {
+			return fmt.Errorf("invalid child rule %q at index %d: %w", child.Name, i, err)
 		}
 	}
 
@@ -146,3 +164,28 @@ func (r *AkamaiPropertyReconciler) validateRuleVariable(variable *akamaiV1alpha1
 
 	return nil
 }
+
+// validatePropertyVariables validates the variables declared in spec.variables
+func (r *AkamaiPropertyReconciler) validatePropertyVariables(variables []akamaiV1alpha1.PropertyVariable) error {
+	variableNames := make(map[string]bool)
+	for i, variable := range variables {
+		ruleVariable := akamaiV1alpha1.RuleVariable{Name: variable.Name}
+		if err := r.validateRuleVariable(&ruleVariable, fmt.Sprintf("variables[%d]", i)); err != nil {
+			return err
+		}
+
+		if variableNames[variable.Name] {
+			return fmt.Errorf("duplicate variable name '%s' at index %d", variable.Name, i)
+		}
+		variableNames[variable.Name] = true
+
+		if variable.ValueFrom != nil && variable.ValueFrom.SecretKeyRef != nil {
+			ref := variable.ValueFrom.SecretKeyRef
+			if ref.Namespace == "" || ref.Name == "" || ref.Key == "" {
+				return fmt.Errorf("variables[%d]: secretKeyRef requires namespace, name and key", i)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,49 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+	"github.com/mmz-srf/akamai-operator/pkg/akamai"
+)
+
+// servingSummary counts the spec hostnames present on the production version with a ready
+// certificate and renders them as "<serving>/<desired>"
+func servingSummary(desired []akamaiV1alpha1.Hostname, production []akamai.Hostname) string {
+	productionMap := make(map[string]akamai.Hostname, len(production))
+	for _, h := range production {
+		productionMap[h.CNAMEFrom] = h
+	}
+
+	serving := 0
+	for _, h := range desired {
+		if ph, ok := productionMap[h.CNAMEFrom]; ok && ph.CNAMETo == h.CNAMETo && ph.CertReady() {
+			serving++
+		}
+	}
+	return fmt.Sprintf("%d/%d", serving, len(desired))
+}
+
+// updateServing refreshes status.serving from the hostnames of the production version
+func (r *AkamaiPropertyReconciler) updateServing(ctx context.Context, akamaiProperty *akamaiV1alpha1.AkamaiProperty) error {
+	var production []akamai.Hostname
+	if akamaiProperty.Status.ProductionVersion > 0 {
+		hostnames, err := r.AkamaiClient.GetPropertyHostnames(ctx,
+			akamaiProperty.Status.PropertyID,
+			akamaiProperty.Spec.ContractID,
+			akamaiProperty.Spec.GroupID,
+			akamaiProperty.Status.ProductionVersion)
+		if err != nil {
+			return fmt.Errorf("failed to get production hostnames: %w", err)
+		}
+		production = hostnames
+	}
+
+	serving := servingSummary(akamaiProperty.Spec.Hostnames, production)
+	if serving == akamaiProperty.Status.Serving {
+		return nil
+	}
+	akamaiProperty.Status.Serving = serving
+	return r.updateStatusWithRetry(ctx, akamaiProperty)
+}
@@ -0,0 +1,106 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+)
+
+// checkSpecValidity validates and lints the spec and checks its rule format and schema once per
+// generation. Generations that failed are marked with a terminal InvalidSpec condition and are not
+// retried until the spec changes; generations that passed are recorded in
+// status.validatedGeneration.
+func (r *AkamaiPropertyReconciler) checkSpecValidity(ctx context.Context, akamaiProperty *akamaiV1alpha1.AkamaiProperty) (bool, error) {
+	logger := log.FromContext(ctx)
+	generation := akamaiProperty.Generation
+
+	// Known invalid generation: nothing to do until the spec is changed
+	if condition := meta.FindStatusCondition(akamaiProperty.Status.Conditions, ConditionTypeInvalidSpec); condition != nil &&
+		condition.Status == metav1.ConditionTrue && condition.ObservedGeneration == generation {
+		logger.V(1).Info("Skipping reconcile of invalid generation", "generation", generation, "reason", condition.Message)
+		return false, nil
+	}
+
+	// Known valid generation
+	if akamaiProperty.Status.ValidatedGeneration == generation {
+		return true, nil
+	}
+
+	var rules *akamaiV1alpha1.PropertyRules
+	validationErr := r.validatePropertyVariables(akamaiProperty.Spec.Variables)
+	if validationErr != nil {
+		validationErr = fmt.Errorf("variable validation failed: %w", validationErr)
+	} else if validationErr = validateActivationNotifications(akamaiProperty); validationErr != nil {
+		validationErr = fmt.Errorf("activation validation failed: %w", validationErr)
+	} else if validationErr = r.validateChinaCDN(akamaiProperty); validationErr != nil {
+		validationErr = fmt.Errorf("China CDN validation failed: %w", validationErr)
+	} else if validationErr = validateOrigins(akamaiProperty); validationErr != nil {
+		validationErr = fmt.Errorf("origins validation failed: %w", validationErr)
+	} else if validationErr = r.validateRenderer(akamaiProperty); validationErr != nil {
+		validationErr = fmt.Errorf("renderer validation failed: %w", validationErr)
+	} else if validationErr = validateRulesPatches(akamaiProperty); validationErr != nil {
+		validationErr = fmt.Errorf("rules patches validation failed: %w", validationErr)
+	} else if validationErr = validateTypedBehaviors(akamaiProperty); validationErr != nil {
+		validationErr = fmt.Errorf("behavior validation failed: %w", validationErr)
+	} else if rules, validationErr = specRules(akamaiProperty); validationErr != nil {
+		validationErr = fmt.Errorf("behavior validation failed: %w", validationErr)
+	} else if validationErr = r.validatePropertyRules(rules); validationErr != nil {
+		validationErr = fmt.Errorf("rule validation failed: %w", validationErr)
+	} else if validationErr = r.lintPropertyRules(ctx, akamaiProperty, rules); validationErr == nil {
+		// Failing to reach the catalog APIs must not mark the generation invalid
+		if err := r.checkRuleFormat(ctx, akamaiProperty); errors.Is(err, errIncompatibleRuleFormat) {
+			validationErr = fmt.Errorf("rule format validation failed: %w", err)
+		} else if err != nil {
+			return false, fmt.Errorf("failed to check rule format: %w", err)
+		}
+		if validationErr == nil {
+			if err := r.checkRulesSchema(ctx, akamaiProperty, rules); errors.Is(err, errSchemaViolations) || errors.Is(err, errIncompatibleRuleFormat) {
+				validationErr = fmt.Errorf("rule schema validation failed: %w", err)
+			} else if err != nil {
+				return false, fmt.Errorf("failed to check rules against the rule format schema: %w", err)
+			}
+		}
+		if validationErr == nil {
+			if err := r.checkIncludeVariables(ctx, akamaiProperty); errors.Is(err, errUndefinedVariables) {
+				validationErr = fmt.Errorf("variable validation failed: %w", err)
+			} else if err != nil {
+				return false, fmt.Errorf("failed to check include variables: %w", err)
+			}
+		}
+	}
+
+	if validationErr != nil {
+		logger.Info("Spec is invalid, waiting for a new generation", "generation", generation, "error", validationErr.Error())
+		meta.SetStatusCondition(&akamaiProperty.Status.Conditions, metav1.Condition{
+			Type:               ConditionTypeInvalidSpec,
+			Status:             metav1.ConditionTrue,
+			Reason:             "ValidationFailed",
+			Message:            validationErr.Error(),
+			ObservedGeneration: generation,
+		})
+		akamaiProperty.Status.ObservedGeneration = generation
+		if err := r.updateStatusWithRetry(ctx, akamaiProperty); err != nil {
+			return false, err
+		}
+		r.updateStatus(ctx, akamaiProperty, PhaseError, "InvalidSpec", validationErr.Error())
+		return false, nil
+	}
+
+	meta.SetStatusCondition(&akamaiProperty.Status.Conditions, metav1.Condition{
+		Type:               ConditionTypeInvalidSpec,
+		Status:             metav1.ConditionFalse,
+		Reason:             "ValidationPassed",
+		ObservedGeneration: generation,
+	})
+	akamaiProperty.Status.ValidatedGeneration = generation
+	if err := r.updateStatusWithRetry(ctx, akamaiProperty); err != nil {
+		return false, err
+	}
+	return true, nil
+}
@@ -34,9 +34,39 @@ func (r *AkamaiPropertyReconciler) updateStatusWithRetry(ctx context.Context, ak
 		latest.Status.ProductionActivationID = akamaiProperty.Status.ProductionActivationID
 		latest.Status.StagingActivationStatus = akamaiProperty.Status.StagingActivationStatus
 		latest.Status.ProductionActivationStatus = akamaiProperty.Status.ProductionActivationStatus
+		latest.Status.StagingActivationNote = akamaiProperty.Status.StagingActivationNote
+		latest.Status.ProductionActivationNote = akamaiProperty.Status.ProductionActivationNote
+		latest.Status.ManagedHostnames = akamaiProperty.Status.ManagedHostnames
+		latest.Status.OwnedEdgeHostnames = akamaiProperty.Status.OwnedEdgeHostnames
+		latest.Status.PendingWarnings = akamaiProperty.Status.PendingWarnings
+		latest.Status.PendingGuardrails = akamaiProperty.Status.PendingGuardrails
+		latest.Status.LastAPIError = akamaiProperty.Status.LastAPIError
+		latest.Status.Checkpoint = akamaiProperty.Status.Checkpoint
+		latest.Status.PreservedBehaviors = akamaiProperty.Status.PreservedBehaviors
+		latest.Status.Drift = akamaiProperty.Status.Drift
+		latest.Status.PlannedActivations = akamaiProperty.Status.PlannedActivations
+		latest.Status.RulesDigest = akamaiProperty.Status.RulesDigest
+		latest.Status.ValueSources = akamaiProperty.Status.ValueSources
+		latest.Status.CPCodes = akamaiProperty.Status.CPCodes
+		latest.Status.ValuesDigest = akamaiProperty.Status.ValuesDigest
+		latest.Status.LastAppliedRules = akamaiProperty.Status.LastAppliedRules
+		latest.Status.LastRulesDiff = akamaiProperty.Status.LastRulesDiff
+		latest.Status.PendingChanges = akamaiProperty.Status.PendingChanges
+		latest.Status.Promotion = akamaiProperty.Status.Promotion
+		latest.Status.Serving = akamaiProperty.Status.Serving
+		latest.Status.HostnameCertificates = akamaiProperty.Status.HostnameCertificates
+		latest.Status.DNSEndpoint = akamaiProperty.Status.DNSEndpoint
+		latest.Status.EdgeEndpoints = akamaiProperty.Status.EdgeEndpoints
+		latest.Status.Validation = akamaiProperty.Status.Validation
+		latest.Status.ValidatedGeneration = akamaiProperty.Status.ValidatedGeneration
+		latest.Status.VersionGeneration = akamaiProperty.Status.VersionGeneration
+		latest.Status.ObservedGeneration = akamaiProperty.Status.ObservedGeneration
+		latest.Status.ExpiresAt = akamaiProperty.Status.ExpiresAt
+		latest.Status.LastHandledReconcileAt = akamaiProperty.Status.LastHandledReconcileAt
 		latest.Status.Phase = akamaiProperty.Status.Phase
 		latest.Status.LastUpdated = akamaiProperty.Status.LastUpdated
 		latest.Status.Conditions = akamaiProperty.Status.Conditions
+		setDerivedConditions(&latest)
 
 		// Try to update the status
 		if err := r.Status().Update(ctx, &latest); err != nil {
@@ -114,6 +144,9 @@ func (r *AkamaiPropertyReconciler) updateStatus(ctx context.Context, akamaiPrope
 		if latest.Status.ProductionActivationStatus == "" && akamaiProperty.Status.ProductionActivationStatus != "" {
 			latest.Status.ProductionActivationStatus = akamaiProperty.Status.ProductionActivationStatus
 		}
+		if akamaiProperty.Status.LastAPIError != nil {
+			latest.Status.LastAPIError = akamaiProperty.Status.LastAPIError
+		}
 
 		// Update conditions
 		condition := metav1.Condition{
@@ -122,6 +155,7 @@ func (r *AkamaiPropertyReconciler) updateStatus(ctx context.Context, akamaiPrope
 			LastTransitionTime: now,
 			Reason:             reason,
 			Message:            message,
+			ObservedGeneration: latest.Generation,
 		}
 
 		if phase == PhaseReady {
@@ -136,7 +170,8 @@ func (r *AkamaiPropertyReconciler) updateStatus(ctx context.Context, akamaiPrope
 				// Check if condition actually changed
 				if existingCondition.Status != condition.Status ||
 					existingCondition.Reason != condition.Reason ||
-					existingCondition.Message != condition.Message {
+					existingCondition.Message != condition.Message ||
+					existingCondition.ObservedGeneration != condition.ObservedGeneration {
 					conditionChanged = true
 					condition.LastTransitionTime = now
 				} else {
@@ -152,6 +187,9 @@ func (r *AkamaiPropertyReconciler) updateStatus(ctx context.Context, akamaiPrope
 			latest.Status.Conditions = append(latest.Status.Conditions, condition)
 			conditionChanged = true
 		}
+		if setDerivedConditions(&latest) {
+			conditionChanged = true
+		}
 
 		// If nothing changed, skip the update
 		if !statusChanged && !conditionChanged {
@@ -0,0 +1,141 @@
+package controllers
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+)
+
+// Severities of summary issues, most blocking first
+const (
+	summarySeverityError = iota
+	summarySeverityWarning
+	summarySeverityInfo
+)
+
+// summaryIssue is one aspect of the property that is not in its desired state
+type summaryIssue struct {
+	severity int
+	reason   string
+	message  string
+}
+
+// summaryIssues collects the aspects of the status that keep the property from being fully
+// synced: spec validity, permissions, rules sync, hostnames sync, certificate readiness and
+// activation state. Issues of the same severity keep this order.
+func summaryIssues(akamaiProperty *akamaiV1alpha1.AkamaiProperty) []summaryIssue {
+	var issues []summaryIssue
+	conditions := akamaiProperty.Status.Conditions
+
+	if condition := meta.FindStatusCondition(conditions, ConditionTypeInvalidSpec); condition != nil && condition.Status == metav1.ConditionTrue {
+		issues = append(issues, summaryIssue{summarySeverityError, "InvalidSpec", condition.Message})
+	}
+	if condition := meta.FindStatusCondition(conditions, ConditionTypePermissionsInsufficient); condition != nil && condition.Status == metav1.ConditionTrue {
+		issues = append(issues, summaryIssue{summarySeverityError, ConditionTypePermissionsInsufficient, condition.Message})
+	}
+	if condition := progressDeadlineExceeded(akamaiProperty); condition != nil {
+		issues = append(issues, summaryIssue{summarySeverityError, condition.Reason, condition.Message})
+	}
+
+	// Rules sync: the Ready condition carries the reason of the last reconcile step
+	if ready := meta.FindStatusCondition(conditions, ConditionTypeReady); ready != nil && ready.Status != metav1.ConditionTrue &&
+		ready.Reason != "InvalidSpec" && ready.Reason != ConditionTypePermissionsInsufficient {
+		severity := summarySeverityInfo
+		if akamaiProperty.Status.Phase == PhaseError {
+			severity = summarySeverityError
+		}
+		message := ready.Message
+		if message == "" {
+			message = fmt.Sprintf("Property is %s", strings.ToLower(akamaiProperty.Status.Phase))
+		}
+		issues = append(issues, summaryIssue{severity, ready.Reason, message})
+	}
+
+	if meta.IsStatusConditionTrue(conditions, ConditionTypePendingAcknowledgement) {
+		if guardrails := len(akamaiProperty.Status.PendingGuardrails); guardrails > 0 {
+			issues = append(issues, summaryIssue{summarySeverityWarning, "GuardrailsNotAcknowledged",
+				fmt.Sprintf("%d guardrail finding(s) need to be acknowledged", guardrails)})
+		}
+		if len(akamaiProperty.Status.PendingWarnings) > 0 || len(akamaiProperty.Status.PendingGuardrails) == 0 {
+			issues = append(issues, summaryIssue{summarySeverityWarning, "WarningsNotAcknowledged",
+				fmt.Sprintf("%d activation warning(s) need to be acknowledged", len(akamaiProperty.Status.PendingWarnings))})
+		}
+	}
+
+	for _, activation := range []struct{ network, status string }{
+		{"Staging", akamaiProperty.Status.StagingActivationStatus},
+		{"Production", akamaiProperty.Status.ProductionActivationStatus},
+	} {
+		switch activation.status {
+		case "", "ACTIVE", "INACTIVE", "DEACTIVATED":
+		case "FAILED", "ABORTED":
+			issues = append(issues, summaryIssue{summarySeverityError, activation.network + "ActivationFailed",
+				fmt.Sprintf("%s activation finished with status %s", activation.network, activation.status)})
+		default:
+			issues = append(issues, summaryIssue{summarySeverityInfo, activation.network + "ActivationInProgress",
+				fmt.Sprintf("%s activation is %s", activation.network, activation.status)})
+		}
+	}
+
+	if akamaiProperty.Status.PropertyID != "" && !hostnamesSynced(akamaiProperty) {
+		issues = append(issues, summaryIssue{summarySeverityInfo, "HostnamesNotSynced",
+			"Property hostnames do not match spec.hostnames yet"})
+	}
+
+	var serving, desired int
+	if _, err := fmt.Sscanf(akamaiProperty.Status.Serving, "%d/%d", &serving, &desired); err == nil && serving < desired {
+		issues = append(issues, summaryIssue{summarySeverityInfo, "CertificatesNotReady",
+			fmt.Sprintf("%d of %d hostnames are served on production with a ready certificate", serving, desired)})
+	}
+
+	sort.SliceStable(issues, func(i, j int) bool {
+		return issues[i].severity < issues[j].severity
+	})
+	return issues
+}
+
+// hostnamesSynced reports whether the hostnames applied by the operator match spec.hostnames
+func hostnamesSynced(akamaiProperty *akamaiV1alpha1.AkamaiProperty) bool {
+	desired := hostnameNames(akamaiProperty.Spec.Hostnames)
+	if len(desired) != len(akamaiProperty.Status.ManagedHostnames) {
+		return false
+	}
+	managed := make(map[string]bool, len(akamaiProperty.Status.ManagedHostnames))
+	for _, hostname := range akamaiProperty.Status.ManagedHostnames {
+		managed[hostname] = true
+	}
+	for _, hostname := range desired {
+		if !managed[hostname] {
+			return false
+		}
+	}
+	return true
+}
+
+// setSummaryCondition aggregates the status into the Summary condition, so consumers that only
+// look at a single condition (e.g. Argo CD health checks) see the most blocking reason. It
+// reports whether the condition changed.
+func setSummaryCondition(akamaiProperty *akamaiV1alpha1.AkamaiProperty) bool {
+	condition := metav1.Condition{
+		Type:               ConditionTypeSummary,
+		Status:             metav1.ConditionTrue,
+		Reason:             "Synced",
+		Message:            "Property is in sync",
+		ObservedGeneration: akamaiProperty.Generation,
+	}
+	if issues := summaryIssues(akamaiProperty); len(issues) > 0 {
+		messages := make([]string, 0, len(issues))
+		for _, issue := range issues {
+			messages = append(messages, issue.message)
+		}
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = issues[0].reason
+		condition.Message = strings.Join(messages, "; ")
+	}
+	return meta.SetStatusCondition(&akamaiProperty.Status.Conditions, condition)
+}
@@ -0,0 +1,215 @@
+package controllers
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+)
+
+// maxAppliedRulesSnapshot is the largest compressed snapshot kept in the status; larger rule
+// trees are applied with the Replace strategy
+const maxAppliedRulesSnapshot = 256 * 1024
+
+// mergesThreeWay reports whether the rule tree of the property is merged with the rules in Akamai
+func mergesThreeWay(akamaiProperty *akamaiV1alpha1.AkamaiProperty) bool {
+	return akamaiProperty.Spec.RulesMergeStrategy == akamaiV1alpha1.RulesMergeStrategyThreeWay
+}
+
+// mergeLastApplied merges the desired rule tree into the current rules like kubectl apply, using
+// the snapshot of the rule tree last applied: parts of the current rules that were neither
+// applied before nor are desired were added outside the operator and are kept. Without the
+// ThreeWay strategy or a snapshot the desired rules are returned as they are.
+func (r *AkamaiPropertyReconciler) mergeLastApplied(ctx context.Context, akamaiProperty *akamaiV1alpha1.AkamaiProperty, desired *akamaiV1alpha1.PropertyRules, current interface{}) (*akamaiV1alpha1.PropertyRules, error) {
+	if desired == nil || !mergesThreeWay(akamaiProperty) || akamaiProperty.Status.LastAppliedRules == nil {
+		return desired, nil
+	}
+	lastApplied, err := decodeAppliedRules(akamaiProperty.Status.LastAppliedRules)
+	if err != nil {
+		// The rules are replaced once and a new snapshot is recorded with them
+		log.FromContext(ctx).Info("Ignoring the last applied rules", "reason", err.Error())
+		return desired, nil
+	}
+
+	desiredTree, err := ruleTree(desired)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := json.Marshal(current)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal current rules: %w", err)
+	}
+	var currentTree map[string]interface{}
+	if err := json.Unmarshal(raw, &currentTree); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal current rules: %w", err)
+	}
+
+	raw, err = json.Marshal(mergeThreeWay(desiredTree, lastApplied, currentTree, true))
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal merged rules: %w", err)
+	}
+	var merged akamaiV1alpha1.PropertyRules
+	if err := json.Unmarshal(raw, &merged); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal merged rules: %w", err)
+	}
+	return &merged, nil
+}
+
+// mergeThreeWay merges a desired object into the current one. Desired values win, values of the
+// current object the last applied one didn't have are kept and objects present in both are
+// merged recursively. In rules, child rules, behaviors, criteria and variables are matched by
+// name.
+func mergeThreeWay(desired, lastApplied, current map[string]interface{}, rule bool) map[string]interface{} {
+	merged := make(map[string]interface{}, len(desired))
+	for key, value := range desired {
+		if rule && ruleListKeys[key] {
+			continue
+		}
+		desiredObject, desiredIsObject := value.(map[string]interface{})
+		currentObject, currentIsObject := current[key].(map[string]interface{})
+		if desiredIsObject && currentIsObject {
+			lastObject, _ := lastApplied[key].(map[string]interface{})
+			merged[key] = mergeThreeWay(desiredObject, lastObject, currentObject, false)
+			continue
+		}
+		merged[key] = value
+	}
+	for key, value := range current {
+		_, desired := desired[key]
+		_, applied := lastApplied[key]
+		if !desired && !applied && !(rule && ruleListKeys[key]) {
+			merged[key] = value
+		}
+	}
+	if rule {
+		for key := range ruleListKeys {
+			if items := mergeThreeWayItems(desired[key], lastApplied[key], current[key], key == "children"); len(items) > 0 {
+				merged[key] = items
+			}
+		}
+	}
+	return merged
+}
+
+// mergeThreeWayItems merges a list of named items. Desired items are merged with the current
+// item of the same name in the desired order; items only the current list has and that weren't
+// applied before keep their place after the closest item preceding them that is kept.
+func mergeThreeWayItems(desired, lastApplied, current interface{}, rules bool) []interface{} {
+	desiredItems, desiredOrder := namedItems(desired)
+	lastItems, _ := namedItems(lastApplied)
+	currentItems, currentOrder := namedItems(current)
+
+	order := slices.Clone(desiredOrder)
+	items := make(map[string]interface{}, len(desiredItems)+len(currentItems))
+	for _, name := range desiredOrder {
+		if currentItem, ok := currentItems[name]; ok {
+			items[name] = mergeThreeWay(desiredItems[name], lastItems[name], currentItem, rules)
+		} else {
+			items[name] = desiredItems[name]
+		}
+	}
+	for i, name := range currentOrder {
+		if desiredItems[name] != nil || lastItems[name] != nil {
+			continue
+		}
+		position := 0
+		for j := i - 1; j >= 0; j-- {
+			if index := slices.Index(order, currentOrder[j]); index != -1 {
+				position = index + 1
+				break
+			}
+		}
+		order = slices.Insert(order, position, name)
+		items[name] = currentItems[name]
+	}
+
+	merged := make([]interface{}, 0, len(order))
+	for _, name := range order {
+		merged = append(merged, items[name])
+	}
+	return merged
+}
+
+// recordLastApplied records the snapshot of the rule tree applied with the ThreeWay strategy and
+// reports whether it changed. The masked option values are not recorded. The status is persisted
+// by the caller.
+func (r *AkamaiPropertyReconciler) recordLastApplied(ctx context.Context, akamaiProperty *akamaiV1alpha1.AkamaiProperty, applied *akamaiV1alpha1.PropertyRules, masked maskedOptions) bool {
+	var snapshot *akamaiV1alpha1.AppliedRules
+	if mergesThreeWay(akamaiProperty) && applied != nil {
+		var err error
+		if snapshot, err = encodeAppliedRules(applied, masked); err != nil {
+			log.FromContext(ctx).Info("Not recording the applied rules; the next change replaces the rules", "reason", err.Error())
+		}
+	}
+	previous := akamaiProperty.Status.LastAppliedRules
+	if (previous == nil && snapshot == nil) || (previous != nil && snapshot != nil && previous.Digest == snapshot.Digest) {
+		return false
+	}
+	akamaiProperty.Status.LastAppliedRules = snapshot
+	return true
+}
+
+// encodeAppliedRules compresses a rule tree into a snapshot, masking sensitive values
+func encodeAppliedRules(rules *akamaiV1alpha1.PropertyRules, masked maskedOptions) (*akamaiV1alpha1.AppliedRules, error) {
+	tree, err := ruleTree(rules)
+	if err != nil {
+		return nil, err
+	}
+	maskSensitiveVariables(tree)
+	masked.mask(tree)
+	raw, err := json.Marshal(tree)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal rules: %w", err)
+	}
+
+	var compressed bytes.Buffer
+	writer := gzip.NewWriter(&compressed)
+	if _, err := writer.Write(raw); err != nil {
+		return nil, fmt.Errorf("failed to compress rules: %w", err)
+	}
+	if err := writer.Close(); err != nil {
+		return nil, fmt.Errorf("failed to compress rules: %w", err)
+	}
+	if compressed.Len() > maxAppliedRulesSnapshot {
+		return nil, fmt.Errorf("compressed rules of %d bytes exceed the snapshot limit of %d bytes", compressed.Len(), maxAppliedRulesSnapshot)
+	}
+	digest := sha256.Sum256(raw)
+	return &akamaiV1alpha1.AppliedRules{
+		Digest:   "sha256:" + hex.EncodeToString(digest[:]),
+		Snapshot: base64.StdEncoding.EncodeToString(compressed.Bytes()),
+	}, nil
+}
+
+// decodeAppliedRules restores the rule tree of a snapshot and verifies its digest
+func decodeAppliedRules(applied *akamaiV1alpha1.AppliedRules) (map[string]interface{}, error) {
+	compressed, err := base64.StdEncoding.DecodeString(applied.Snapshot)
+	if err != nil {
+		return nil, fmt.Errorf("invalid snapshot encoding: %w", err)
+	}
+	reader, err := gzip.NewReader(bytes.NewReader(compressed))
+	if err != nil {
+		return nil, fmt.Errorf("invalid snapshot compression: %w", err)
+	}
+	raw, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("invalid snapshot compression: %w", err)
+	}
+	if digest := sha256.Sum256(raw); "sha256:"+hex.EncodeToString(digest[:]) != applied.Digest {
+		return nil, fmt.Errorf("snapshot does not match digest %s", applied.Digest)
+	}
+	var tree map[string]interface{}
+	if err := json.Unmarshal(raw, &tree); err != nil {
+		return nil, fmt.Errorf("invalid snapshot: %w", err)
+	}
+	return tree, nil
+}
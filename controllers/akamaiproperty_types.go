@@ -4,10 +4,94 @@ const (
 	// FinalizerName is the finalizer added to AkamaiProperty resources
 	FinalizerName = "akamai.com/finalizer"
 
+	// EdgeHostnameFinalizerName is the finalizer added to AkamaiEdgeHostname resources
+	EdgeHostnameFinalizerName = "akamai.com/edge-hostname-finalizer"
+
+	// DNSZoneFinalizerName is the finalizer added to AkamaiDnsZone resources
+	DNSZoneFinalizerName = "akamai.com/dns-zone-finalizer"
+
+	// DNSRecordFinalizerName is the finalizer added to AkamaiDnsRecord resources
+	DNSRecordFinalizerName = "akamai.com/dns-record-finalizer"
+
+	// NetworkListFinalizerName is the finalizer added to AkamaiNetworkList resources
+	NetworkListFinalizerName = "akamai.com/network-list-finalizer"
+
+	// AppSecConfigFinalizerName is the finalizer added to AkamaiAppSecConfig resources
+	AppSecConfigFinalizerName = "akamai.com/appsec-config-finalizer"
+
+	// GtmDomainFinalizerName is the finalizer added to AkamaiGtmDomain resources
+	GtmDomainFinalizerName = "akamai.com/gtm-domain-finalizer"
+
+	// CloudletPolicyFinalizerName is the finalizer added to AkamaiCloudletPolicy resources
+	CloudletPolicyFinalizerName = "akamai.com/cloudlet-policy-finalizer"
+
+	// CertificateFinalizerName is the finalizer added to AkamaiCertificate resources
+	CertificateFinalizerName = "akamai.com/certificate-finalizer"
+
+	// DataStreamFinalizerName is the finalizer added to AkamaiDataStream resources
+	DataStreamFinalizerName = "akamai.com/datastream-finalizer"
+
+	// ClientListFinalizerName is the finalizer added to AkamaiClientList resources
+	ClientListFinalizerName = "akamai.com/client-list-finalizer"
+
+	// AnnotationGitRevision records the git revision the resource was rendered from
+	AnnotationGitRevision = "akamai.com/git-revision"
+
+	// AnnotationPromoteVersion requests the promotion of a version tested on staging to production
+	AnnotationPromoteVersion = "akamai.com/promote-version"
+
+	// AnnotationSuspend set to "true" suspends the reconciliation of the property
+	AnnotationSuspend = "akamai.com/suspend"
+
+	// AnnotationPaused set to "true" suspends the reconciliation of the property like AnnotationSuspend
+	AnnotationPaused = "akamai.com/paused"
+
+	// AnnotationFreezeActivations set to "true" holds back the activations and promotions of the
+	// property while changes are still written to its versions
+	AnnotationFreezeActivations = "akamai.com/freeze-activations"
+
+	// AnnotationAbortActivation requests the cancellation of an activation while it is still
+	// PENDING: on an AkamaiProperty its value is the ID of the activation, on an
+	// AkamaiPropertyActivation any value
+	AnnotationAbortActivation = "akamai.com/abort-activation"
+
+	// AnnotationReconcileRequestedAt requests an immediate full sync, following the Flux convention
+	AnnotationReconcileRequestedAt = "reconcile.fluxcd.io/requestedAt"
+
+	// AnnotationAppliedChecksum records the checksum of the spec last applied to Akamai
+	AnnotationAppliedChecksum = "akamai.com/applied-checksum"
+
+	// AnnotationPropertyID names an existing Akamai property the operator adopts instead of
+	// creating one, e.g. a property re-created by a restore
+	AnnotationPropertyID = "akamai.com/property-id"
+
+	// LabelCNAMEProperty names the AkamaiProperty an AkamaiDnsRecord was created for by
+	// spec.dns.autoCreateCNAMEs
+	LabelCNAMEProperty = "akamai.com/cname-property"
+
+	// LabelPreview set to "true" marks the resource as a preview of the property named in spec.preview.baseRef
+	LabelPreview = "akamai.com/preview"
+
+	// LabelRuleValues set to "true" allows properties to read a Secret into their rules through
+	// placeholders and spec.variables
+	LabelRuleValues = "akamai.com/rule-values"
+
 	// Condition types
-	ConditionTypeReady       = "Ready"
-	ConditionTypeAvailable   = "Available"
-	ConditionTypeProgressing = "Progressing"
+	ConditionTypeReady                   = "Ready"
+	ConditionTypeSummary                 = "Summary"
+	ConditionTypeReconciling             = "Reconciling"
+	ConditionTypeStalled                 = "Stalled"
+	ConditionTypeAvailable               = "Available"
+	ConditionTypeProgressing             = "Progressing"
+	ConditionTypeRulesLinted             = "RulesLinted"
+	ConditionTypeRulesSchemaValid        = "RulesSchemaValid"
+	ConditionTypeInvalidSpec             = "InvalidSpec"
+	ConditionTypePendingAcknowledgement  = "PendingAcknowledgement"
+	ConditionTypeWaitingForActivation    = "WaitingForActivation"
+	ConditionTypePermissionsInsufficient = "PermissionsInsufficient"
+	ConditionTypeExpiring                = "Expiring"
+	ConditionTypeDriftDetected           = "DriftDetected"
+	ConditionTypeDryRun                  = "DryRun"
 
 	// Phase constants
 	PhaseCreating   = "Creating"
@@ -16,4 +100,54 @@ const (
 	PhaseActivating = "Activating"
 	PhaseError      = "Error"
 	PhaseDeleting   = "Deleting"
+	PhaseSuspended  = "Suspended"
+	PhaseObserving  = "Observing"
+	PhaseDryRun     = "DryRun"
+
+	// ReasonObserveOnly is the reason of the Ready condition while the operator only observes
+	ReasonObserveOnly = "ObserveOnly"
+
+	// ReasonNewGeneration is the reason of the Ready condition while a spec edit is reconciled
+	ReasonNewGeneration = "NewGeneration"
+
+	// Reasons of the Progressing condition
+	ReasonGenerationProgressing    = "GenerationProgressing"
+	ReasonGenerationReady          = "GenerationReady"
+	ReasonProgressDeadlineExceeded = "ProgressDeadlineExceeded"
+
+	// Reasons of the Expiring condition and the events of temporary properties
+	ReasonExpiringSoon = "ExpiringSoon"
+	ReasonExpired      = "Expired"
+
+	// Actions of planned activations and the reason of the deactivations planned for the expiry
+	// of a temporary property
+	PlannedActionActivate   = "Activate"
+	PlannedActionDeactivate = "Deactivate"
+	ReasonPropertyExpires   = "PropertyExpires"
+
+	// Reasons of the DryRun condition; ReasonDryRun is the reason of the Ready condition of a dry run
+	ReasonDryRun         = "DryRun"
+	ReasonChangesPending = "ChangesPending"
+	ReasonNoChanges      = "NoChanges"
+
+	// Reasons of the DriftDetected condition and the drift events
+	ReasonDriftDetected  = "DriftDetected"
+	ReasonDriftCorrected = "DriftCorrected"
+
+	// ReasonActivationsFrozen is the reason of the Ready condition while activations are frozen
+	ReasonActivationsFrozen = "ActivationsFrozen"
+
+	// ReasonRulesSourceChanged is the reason of the event of new content of spec.rulesFrom
+	ReasonRulesSourceChanged = "RulesSourceChanged"
+
+	// ReasonRulesChanged is the reason of the event listing the changes of a rules update
+	ReasonRulesChanged = "RulesChanged"
+
+	// ReasonEdgeHostnameDeleted is the reason of the event of an unused edge hostname the
+	// property created being deleted
+	ReasonEdgeHostnameDeleted = "EdgeHostnameDeleted"
+
+	// ReasonCNAMEsFailed is the reason of the event of CNAMEs that couldn't be created for the
+	// hostnames served on production
+	ReasonCNAMEsFailed = "CNAMEsFailed"
 )
@@ -0,0 +1,90 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/types"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+)
+
+// maskedValue replaces sensitive variable values in logs and diffs
+const maskedValue = "***"
+
+// renderVariables resolves spec.variables (including Secret references) into rule variables
+func (r *AkamaiPropertyReconciler) renderVariables(ctx context.Context, variables []akamaiV1alpha1.PropertyVariable) ([]akamaiV1alpha1.RuleVariable, error) {
+	rendered := make([]akamaiV1alpha1.RuleVariable, 0, len(variables))
+	for _, variable := range variables {
+		value := variable.Value
+		if variable.ValueFrom != nil && variable.ValueFrom.SecretKeyRef != nil {
+			secretValue, err := r.readSecretKey(ctx, variable.ValueFrom.SecretKeyRef)
+			if err != nil {
+				return nil, fmt.Errorf("failed to resolve value of variable %s: %w", variable.Name, err)
+			}
+			value = secretValue
+		}
+
+		rendered = append(rendered, akamaiV1alpha1.RuleVariable{
+			Name:        variable.Name,
+			Value:       value,
+			Description: variable.Description,
+			Hidden:      variable.Hidden,
+			Sensitive:   variable.Sensitive,
+		})
+	}
+	return rendered, nil
+}
+
+// readSecretKey reads a single key of a Secret
+func (r *AkamaiPropertyReconciler) readSecretKey(ctx context.Context, ref *akamaiV1alpha1.SecretKeyReference) (string, error) {
+	secret, err := r.getRuleValueSecret(ctx, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name})
+	if err != nil {
+		return "", err
+	}
+	value, ok := secret.Data[ref.Key]
+	if !ok {
+		return "", fmt.Errorf("key %s not found in secret %s/%s", ref.Key, ref.Namespace, ref.Name)
+	}
+	return string(value), nil
+}
+
+// mergeVariables merges rendered spec variables into the rule variables;
+// spec variables replace rule variables with the same name
+func mergeVariables(ruleVariables, specVariables []akamaiV1alpha1.RuleVariable) []akamaiV1alpha1.RuleVariable {
+	if len(specVariables) == 0 {
+		return ruleVariables
+	}
+
+	overridden := make(map[string]bool, len(specVariables))
+	for _, variable := range specVariables {
+		overridden[variable.Name] = true
+	}
+
+	merged := make([]akamaiV1alpha1.RuleVariable, 0, len(ruleVariables)+len(specVariables))
+	for _, variable := range ruleVariables {
+		if !overridden[variable.Name] {
+			merged = append(merged, variable)
+		}
+	}
+	return append(merged, specVariables...)
+}
+
+// maskSensitiveVariables masks the values of sensitive variables in a normalized rule map
+func maskSensitiveVariables(rules map[string]interface{}) {
+	variables, ok := rules["variables"].([]interface{})
+	if !ok {
+		return
+	}
+	for _, item := range variables {
+		variable, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if sensitive, _ := variable["sensitive"].(bool); sensitive {
+			if _, hasValue := variable["value"]; hasValue {
+				variable["value"] = maskedValue
+			}
+		}
+	}
+}
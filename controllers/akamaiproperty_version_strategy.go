@@ -0,0 +1,194 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+)
+
+// errManualVersionRequired signals that the Manual version strategy is waiting for an editable
+// version to be created outside the operator
+var errManualVersionRequired = errors.New("an editable property version must be created manually")
+
+// versionStrategy returns the configured version strategy, defaulting to ReuseUnpublished
+func versionStrategy(akamaiProperty *akamaiV1alpha1.AkamaiProperty) akamaiV1alpha1.VersionStrategy {
+	if akamaiProperty.Spec.VersionStrategy == "" {
+		return akamaiV1alpha1.VersionStrategyReuseUnpublished
+	}
+	return akamaiProperty.Spec.VersionStrategy
+}
+
+// needsNewVersion decides whether a new version has to be created before writing changes
+// to the latest version, given whether the latest version is published
+func needsNewVersion(akamaiProperty *akamaiV1alpha1.AkamaiProperty, published bool) (bool, error) {
+	switch versionStrategy(akamaiProperty) {
+	case akamaiV1alpha1.VersionStrategyAlwaysNew:
+		// Changes of one generation share a single version, every new generation gets its own
+		return published || akamaiProperty.Status.VersionGeneration != akamaiProperty.Generation, nil
+	case akamaiV1alpha1.VersionStrategyManual:
+		if published {
+			return false, fmt.Errorf("%w: version %d is published", errManualVersionRequired, akamaiProperty.Status.LatestVersion)
+		}
+		return false, nil
+	default:
+		return published, nil
+	}
+}
+
+// editableVersion returns the property version changes should be written to, creating a new
+// version when the version strategy requires it
+func (r *AkamaiPropertyReconciler) editableVersion(ctx context.Context, akamaiProperty *akamaiV1alpha1.AkamaiProperty) (int, error) {
+	logger := log.FromContext(ctx)
+	latestVersion := akamaiProperty.Status.LatestVersion
+
+	// The single version endpoint is polled instead of the full property to keep this cheap
+	versionState, err := r.AkamaiClient.GetPropertyVersion(ctx,
+		akamaiProperty.Status.PropertyID,
+		latestVersion,
+		akamaiProperty.Spec.ContractID,
+		akamaiProperty.Spec.GroupID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to check if version %d is published: %w", latestVersion, err)
+	}
+	if versionState.IsPending() {
+		// The version is being activated (possibly outside the operator); wait for it to settle
+		logger.Info("Latest version has a pending activation; waiting before writing changes",
+			"version", latestVersion,
+			"stagingStatus", versionState.StagingStatus,
+			"productionStatus", versionState.ProductionStatus)
+		return 0, fmt.Errorf("%w: version %d has a pending activation", errVersionNotEditable, latestVersion)
+	}
+	if err := r.setWaitingForActivation(ctx, akamaiProperty, nil); err != nil {
+		return 0, err
+	}
+
+	// Write to the version an interrupted sequence created for this generation instead of
+	// creating another one
+	if version, ok := resumedVersion(akamaiProperty, versionState); ok && !versionState.IsPublished() {
+		logger.Info("Resuming writes to the version created for this generation", "version", version)
+		if slices.Contains(akamaiProperty.Status.Checkpoint.Completed, CheckpointCreateVersion) {
+			return version, nil
+		}
+		return r.claimVersion(ctx, akamaiProperty, version)
+	}
+
+	createVersion, err := needsNewVersion(akamaiProperty, versionState.IsPublished())
+	if err != nil {
+		return 0, err
+	}
+	if !createVersion && isForeignVersion(akamaiProperty, versionState) {
+		if foreignVersionPolicy(akamaiProperty) == akamaiV1alpha1.ForeignVersionPolicyRefuse {
+			return 0, fmt.Errorf("%w: version %d was last updated by %q", errForeignVersion, latestVersion, versionState.UpdatedByUser)
+		}
+		logger.Info("Latest version was created outside the operator; writing changes to a new version",
+			"version", latestVersion,
+			"updatedByUser", versionState.UpdatedByUser)
+		createVersion = true
+	}
+	if !createVersion {
+		return latestVersion, nil
+	}
+
+	if err := r.setCheckpoint(ctx, akamaiProperty, CheckpointCreateVersion, latestVersion); err != nil {
+		return 0, fmt.Errorf("failed to record checkpoint: %w", err)
+	}
+	newVersion, err := r.AkamaiClient.CreatePropertyVersion(ctx,
+		akamaiProperty.Status.PropertyID,
+		akamaiProperty.Spec.ContractID,
+		akamaiProperty.Spec.GroupID,
+		latestVersion)
+	if err != nil {
+		return 0, err
+	}
+
+	logger.Info("Created new property version",
+		"strategy", versionStrategy(akamaiProperty),
+		"fromVersion", latestVersion,
+		"newVersion", newVersion,
+		"stagingStatus", versionState.StagingStatus,
+		"productionStatus", versionState.ProductionStatus)
+	return r.claimVersion(ctx, akamaiProperty, newVersion)
+}
+
+// claimVersion records a version created for the current generation as the one to write to
+func (r *AkamaiPropertyReconciler) claimVersion(ctx context.Context, akamaiProperty *akamaiV1alpha1.AkamaiProperty, version int) (int, error) {
+	akamaiProperty.Status.LatestVersion = version
+	akamaiProperty.Status.VersionGeneration = akamaiProperty.Generation
+	completeStep(akamaiProperty, CheckpointCreateVersion, version)
+	if err := r.updateStatusWithRetry(ctx, akamaiProperty); err != nil {
+		return 0, fmt.Errorf("failed to update status with new version: %w", err)
+	}
+
+	// A new version inherits the notes of the version it was created from; claim it right away
+	// so it is not mistaken for a foreign version before the rules are written
+	if foreignVersionGuardEnabled(akamaiProperty) {
+		err := r.AkamaiClient.SetVersionNotes(ctx, akamaiProperty.Status.PropertyID,
+			version,
+			akamaiProperty.Spec.ContractID,
+			akamaiProperty.Spec.GroupID,
+			renderVersionNotes(akamaiProperty))
+		if err != nil {
+			return 0, fmt.Errorf("failed to mark version %d as managed by the operator: %w", version, err)
+		}
+	}
+	return version, nil
+}
+
+// waitForEditableVersion reports whether err means the reconcile has to wait for an editable
+// version, and if so records the reason and returns the result polling for it
+func (r *AkamaiPropertyReconciler) waitForEditableVersion(ctx context.Context, akamaiProperty *akamaiV1alpha1.AkamaiProperty, err error) (ctrl.Result, bool) {
+	switch {
+	case errors.Is(err, errVersionNotEditable):
+		// The changes supersede the pending activation with spec.activation.cancelSuperseded
+		if cancelling, cancelErr := r.cancelSupersededActivation(ctx, akamaiProperty); cancelErr != nil {
+			log.FromContext(ctx).Error(cancelErr, "Failed to cancel superseded activation")
+		} else if cancelling {
+			r.updateStatus(ctx, akamaiProperty, PhaseUpdating, "CancellingSupersededActivation",
+				fmt.Sprintf("Cancelling the pending activation of version %d to write the changes", akamaiProperty.Status.LatestVersion))
+			return ctrl.Result{RequeueAfter: r.versionPollInterval()}, true
+		}
+		if statusErr := r.setWaitingForActivation(ctx, akamaiProperty, err); statusErr != nil {
+			log.FromContext(ctx).Error(statusErr, "Failed to record WaitingForActivation condition")
+		}
+		r.updateStatus(ctx, akamaiProperty, PhaseUpdating, "WaitingForEditableVersion", err.Error())
+	case errors.Is(err, errManualVersionRequired):
+		r.updateStatus(ctx, akamaiProperty, PhaseUpdating, "WaitingForManualVersion", err.Error())
+	case errors.Is(err, errForeignVersion):
+		r.updateStatus(ctx, akamaiProperty, PhaseUpdating, "RefusedForeignVersion", err.Error())
+	default:
+		return ctrl.Result{}, false
+	}
+	return ctrl.Result{RequeueAfter: r.versionPollInterval()}, true
+}
+
+// setWaitingForActivation records whether writes are deferred because the target version is being
+// activated. A nil reason clears the condition; nothing is written if it was never set.
+func (r *AkamaiPropertyReconciler) setWaitingForActivation(ctx context.Context, akamaiProperty *akamaiV1alpha1.AkamaiProperty, reason error) error {
+	condition := metav1.Condition{
+		Type:               ConditionTypeWaitingForActivation,
+		Status:             metav1.ConditionFalse,
+		Reason:             "VersionEditable",
+		Message:            "The target property version is not being activated",
+		ObservedGeneration: akamaiProperty.Generation,
+	}
+	if reason != nil {
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = "ActivationPending"
+		condition.Message = reason.Error()
+	} else if meta.FindStatusCondition(akamaiProperty.Status.Conditions, ConditionTypeWaitingForActivation) == nil {
+		return nil
+	}
+
+	if !meta.SetStatusCondition(&akamaiProperty.Status.Conditions, condition) {
+		return nil
+	}
+	return r.updateStatusWithRetry(ctx, akamaiProperty)
+}
@@ -0,0 +1,410 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+	"github.com/mmz-srf/akamai-operator/pkg/akamai"
+)
+
+// AkamaiPropertyActivationReconciler submits the activations of AkamaiPropertyActivation
+// resources and follows them until they finish. Each resource activates once; deleting it
+// doesn't deactivate the property.
+type AkamaiPropertyActivationReconciler struct {
+	client.Client
+	Scheme       *runtime.Scheme
+	AkamaiClient *akamai.Client
+
+	// Credentials selects the credential source the Akamai client is created with
+	Credentials akamai.Credentials
+
+	// ClientCache holds the Akamai clients of properties with a credentialsRef
+	ClientCache *AkamaiClientCache
+
+	// ActivationScheduler is shared with the property controller to keep the activations in
+	// flight within the account limit
+	ActivationScheduler *ActivationScheduler
+
+	// Shard is the part of the fleet this instance manages; activations follow their property
+	Shard *Shard
+
+	// Guardrails are the operator-wide guardrails checked before production activations, like
+	// those of the property controller
+	Guardrails []string
+
+	// ObserveOnly holds the activations back and follows those already submitted without
+	// cancelling them
+	ObserveOnly bool
+
+	// slotKeys maps activations to the scheduler slot they hold or wait for, so the slot is freed
+	// when an activation is deleted while in flight
+	slotMu   sync.Mutex
+	slotKeys map[types.NamespacedName]activationSlot
+}
+
+//+kubebuilder:rbac:groups=akamai.com,resources=akamaipropertyactivations,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=akamai.com,resources=akamaipropertyactivations/status,verbs=get;update;patch
+
+// Reconcile submits the activation of an AkamaiPropertyActivation and records its progress
+func (r *AkamaiPropertyActivationReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	var activation akamaiV1alpha1.AkamaiPropertyActivation
+	if err := r.Get(ctx, req.NamespacedName, &activation); err != nil {
+		if apierrors.IsNotFound(err) {
+			// Free the slot of an activation deleted while in flight
+			if key, ok := r.forgetSlot(req.NamespacedName); ok {
+				r.ActivationScheduler.Release(key)
+			}
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	// A finished activation is history; a new one needs a new resource
+	if activation.Status.ActivationID != "" && akamai.ActivationFinished(activation.Status.Status) {
+		return ctrl.Result{}, nil
+	}
+
+	var property akamaiV1alpha1.AkamaiProperty
+	if err := r.Get(ctx, types.NamespacedName{Name: activation.Spec.PropertyRef}, &property); err != nil {
+		if apierrors.IsNotFound(err) {
+			r.setActivationCondition(&activation, PhaseError, metav1.ConditionFalse, "PropertyNotFound",
+				fmt.Sprintf("AkamaiProperty %q not found", activation.Spec.PropertyRef))
+			return ctrl.Result{RequeueAfter: time.Minute * 2}, r.Status().Update(ctx, &activation)
+		}
+		return ctrl.Result{}, err
+	}
+	if inShard, err := r.Shard.containsProperty(ctx, r.Client, &property); err != nil || !inShard {
+		return ctrl.Result{}, err
+	}
+	if property.Status.PropertyID == "" || property.Status.LatestVersion == 0 {
+		r.setActivationCondition(&activation, PhaseCreating, metav1.ConditionFalse, "WaitingForProperty",
+			fmt.Sprintf("AkamaiProperty %q has not been created in Akamai yet", activation.Spec.PropertyRef))
+		return ctrl.Result{RequeueAfter: time.Minute * 2}, r.Status().Update(ctx, &activation)
+	}
+
+	// Activate with the credentials of the property's Akamai account
+	akamaiClient, err := r.akamaiClientFor(ctx, &property)
+	if err != nil {
+		logger.Error(err, "Failed to create Akamai client")
+		r.setActivationCondition(&activation, PhaseError, metav1.ConditionFalse, "FailedToCreateClient", akamai.ErrorMessage(err))
+		return ctrl.Result{RequeueAfter: time.Minute * 2}, r.Status().Update(ctx, &activation)
+	}
+
+	if activation.Status.ActivationID == "" {
+		if activation.Annotations[AnnotationAbortActivation] != "" {
+			// Aborted before it was submitted; there is nothing to cancel in Akamai
+			recordActivationStatus(&activation, "ABORTED")
+			r.setActivationCondition(&activation, PhaseError, metav1.ConditionFalse, "ActivationAborted", "Aborted before the activation was submitted")
+			return ctrl.Result{}, r.Status().Update(ctx, &activation)
+		}
+		return r.submitActivation(ctx, akamaiClient, &activation, &property)
+	}
+	return r.followActivation(ctx, akamaiClient, &activation, &property)
+}
+
+// submitActivation starts the activation in Akamai, or adopts one already pending for the version
+func (r *AkamaiPropertyActivationReconciler) submitActivation(ctx context.Context, akamaiClient *akamai.Client, activation *akamaiV1alpha1.AkamaiPropertyActivation, property *akamaiV1alpha1.AkamaiProperty) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+	network := activation.Spec.Network
+	version := activation.Spec.Version
+	if version == 0 {
+		version = property.Status.LatestVersion
+	}
+	schedulerKey := activationKey(akamaiClient, property, network)
+
+	// Activation resources are held back by the same gates as the activations of the property
+	if reason, message := activationHold(property); reason != "" {
+		logger.Info("Activation held back by the property", "network", network, "reason", reason)
+		r.setActivationCondition(activation, PhaseCreating, metav1.ConditionFalse, reason, message)
+		return ctrl.Result{RequeueAfter: time.Minute * 2}, r.Status().Update(ctx, activation)
+	}
+	if r.ObserveOnly {
+		r.setActivationCondition(activation, PhaseObserving, metav1.ConditionFalse, ReasonObserveOnly,
+			fmt.Sprintf("The activation of version %d on %s isn't submitted in observe-only mode", version, network))
+		return ctrl.Result{RequeueAfter: time.Minute * 30}, r.Status().Update(ctx, activation)
+	}
+
+	pending, err := akamaiClient.GetPendingActivationForVersion(ctx, property.Status.PropertyID, version, network)
+	if err != nil {
+		logger.Error(err, "Failed to check for pending activation")
+		r.setActivationCondition(activation, PhaseError, metav1.ConditionFalse, "FailedToCheckActivations", akamai.ErrorMessage(err))
+		return ctrl.Result{RequeueAfter: time.Minute * 2}, r.Status().Update(ctx, activation)
+	}
+	if pending != nil {
+		logger.Info("Adopting pending activation", "network", network, "version", version, "activationID", pending.ActivationID)
+		r.trackSlot(activation, schedulerKey)
+		recordSubmittedActivation(activation, property.Status.PropertyID, version, pending.ActivationID, pending.Status)
+		r.setActivationCondition(activation, PhaseActivating, metav1.ConditionFalse, "ActivationInProgress",
+			fmt.Sprintf("Following the pending activation of version %d on %s", version, network))
+		return ctrl.Result{RequeueAfter: time.Minute * 2}, r.Status().Update(ctx, activation)
+	}
+
+	if network == "PRODUCTION" {
+		guardrails, err := unacknowledgedGuardrails(ctx, akamaiClient, guardrailNames(r.Guardrails, property), property, version)
+		if err != nil {
+			logger.Error(err, "Failed to check guardrails")
+			r.setActivationCondition(activation, PhaseError, metav1.ConditionFalse, "FailedToCheckGuardrails", akamai.ErrorMessage(err))
+			return ctrl.Result{RequeueAfter: time.Minute * 2}, r.Status().Update(ctx, activation)
+		}
+		if len(guardrails) > 0 {
+			// Acknowledging the findings changes the property, not this resource, so keep polling
+			logger.Info("Activation blocked by guardrails", "version", version)
+			r.setActivationCondition(activation, PhaseCreating, metav1.ConditionFalse, "GuardrailsNotAcknowledged",
+				fmt.Sprintf("Guardrails block the production activation of version %d; add to activation.acknowledgeGuardrails of AkamaiProperty %q: %s",
+					version, property.Name, strings.Join(guardrailIDs(guardrails), ", ")))
+			return ctrl.Result{RequeueAfter: time.Minute * 2}, r.Status().Update(ctx, activation)
+		}
+	}
+
+	// Wait for a slot so activations across all properties stay within the account limit
+	if granted, position := r.acquireSlot(activation, schedulerKey); !granted {
+		logger.Info("Activation queued, waiting for a free activation slot", "network", network, "version", version, "position", position)
+		r.setActivationCondition(activation, PhaseActivating, metav1.ConditionFalse, "ActivationQueued",
+			fmt.Sprintf("Waiting for a free activation slot to activate version %d on %s (position %d)", version, network, position))
+		return ctrl.Result{RequeueAfter: time.Second * 30}, r.Status().Update(ctx, activation)
+	}
+
+	requestSpec, err := propertyActivationRequest(property, activation, version)
+	if err == nil && len(requestSpec.NotifyEmails) == 0 {
+		err = fmt.Errorf("no notify emails: set spec.notifyEmails or the notify emails of the property's spec.activation")
+	}
+	if err != nil {
+		// Retrying cannot help until the spec changes
+		r.releaseSlot(activation, schedulerKey)
+		r.setActivationCondition(activation, PhaseError, metav1.ConditionFalse, "FailedToPrepareActivation", err.Error())
+		return ctrl.Result{}, r.Status().Update(ctx, activation)
+	}
+
+	logger.Info("Starting property activation", "property", property.Name, "network", network, "version", version)
+	activationID, err := akamaiClient.ActivateProperty(ctx, property.Status.PropertyID, version, requestSpec, property.Spec.ContractID, property.Spec.GroupID)
+	if err != nil {
+		r.releaseSlot(activation, schedulerKey)
+	}
+	var warningsErr *akamai.WarningsNotAcknowledgedError
+	if errors.As(err, &warningsErr) {
+		// Retrying cannot help until the warnings are acknowledged, which changes the generation
+		logger.Info("Activation blocked by unacknowledged warnings", "network", network, "warnings", len(warningsErr.Warnings))
+		activation.Status.Version = version
+		activation.Status.PendingWarnings = pendingWarnings(warningsErr.Warnings)
+		r.setActivationCondition(activation, PhaseError, metav1.ConditionFalse, "WarningsNotAcknowledged", warningsErr.Error())
+		return ctrl.Result{}, r.Status().Update(ctx, activation)
+	}
+	if err != nil {
+		logger.Error(err, "Failed to activate property", "network", network, "version", version)
+		r.setActivationCondition(activation, PhaseError, metav1.ConditionFalse, "FailedToActivate", akamai.ErrorMessage(err))
+		return ctrl.Result{RequeueAfter: time.Minute * 2}, r.Status().Update(ctx, activation)
+	}
+
+	logger.Info("Successfully started activation", "activationID", activationID, "network", network)
+	recordSubmittedActivation(activation, property.Status.PropertyID, version, activationID, "PENDING")
+	r.setActivationCondition(activation, PhaseActivating, metav1.ConditionFalse, "ActivationInProgress",
+		fmt.Sprintf("Activating version %d on %s", version, network))
+	return ctrl.Result{RequeueAfter: time.Minute * 2}, r.Status().Update(ctx, activation)
+}
+
+// activationHold returns the reason and message of the gate of the property that holds back its
+// activations, or an empty reason when activations may be submitted
+func activationHold(property *akamaiV1alpha1.AkamaiProperty) (string, string) {
+	switch {
+	case suspendedBy(property) != "":
+		return "PropertySuspended", fmt.Sprintf("AkamaiProperty %q is suspended by %s", property.Name, suspendedBy(property))
+	case activationsFrozen(property):
+		return "ActivationsFrozen", fmt.Sprintf("Activations of AkamaiProperty %q are frozen by the %s annotation", property.Name, AnnotationFreezeActivations)
+	case property.Spec.DryRun:
+		return "PropertyDryRun", fmt.Sprintf("AkamaiProperty %q is a dry run", property.Name)
+	case !managesActivation(property):
+		return "ActivationNotManaged", fmt.Sprintf("Activations of AkamaiProperty %q are left to other tooling with spec.manage.activation false", property.Name)
+	}
+	return "", ""
+}
+
+// followActivation polls a submitted activation until it finishes
+func (r *AkamaiPropertyActivationReconciler) followActivation(ctx context.Context, akamaiClient *akamai.Client, activation *akamaiV1alpha1.AkamaiPropertyActivation, property *akamaiV1alpha1.AkamaiProperty) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+	schedulerKey := activationKey(akamaiClient, property, activation.Spec.Network)
+
+	current, err := akamaiClient.GetActivation(ctx, activation.Status.PropertyID, activation.Status.ActivationID)
+	if err != nil {
+		logger.Error(err, "Failed to get activation status")
+		return ctrl.Result{RequeueAfter: time.Minute * 2}, nil
+	}
+	status := current.Status
+
+	// Cancel on request while Akamai still allows it; observe-only mode cancels nothing
+	abortRequested := activation.Annotations[AnnotationAbortActivation] != "" && !r.ObserveOnly
+	if abortRequested && akamai.ActivationCancellable(status) {
+		status, err = akamaiClient.CancelActivation(ctx, activation.Status.PropertyID, activation.Status.ActivationID,
+			property.Spec.ContractID, property.Spec.GroupID)
+		if err != nil {
+			logger.Error(err, "Failed to cancel activation")
+			r.setActivationCondition(activation, PhaseActivating, metav1.ConditionFalse, "FailedToCancelActivation", akamai.ErrorMessage(err))
+			return ctrl.Result{RequeueAfter: time.Minute * 2}, r.Status().Update(ctx, activation)
+		}
+		logger.Info("Cancelled activation", "activationID", activation.Status.ActivationID, "network", activation.Spec.Network)
+	}
+	recordActivationStatus(activation, status)
+
+	// Keep the scheduler in sync, also for activations submitted before a restart
+	if !akamai.ActivationFinished(status) {
+		r.trackSlot(activation, schedulerKey)
+		message := fmt.Sprintf("Status: %s", status)
+		if abortRequested && !akamai.ActivationAborted(status) {
+			message += "; the activation can no longer be cancelled"
+		}
+		r.setActivationCondition(activation, PhaseActivating, metav1.ConditionFalse, "ActivationInProgress", message)
+		return ctrl.Result{RequeueAfter: time.Minute * 2}, r.Status().Update(ctx, activation)
+	}
+	r.releaseSlot(activation, schedulerKey)
+
+	switch {
+	case status == "ACTIVE":
+		logger.Info("Activation completed successfully", "network", activation.Spec.Network, "version", activation.Status.Version)
+		r.setActivationCondition(activation, PhaseReady, metav1.ConditionTrue, "Activated",
+			fmt.Sprintf("Version %d is active on %s", activation.Status.Version, activation.Spec.Network))
+	case akamai.ActivationAborted(status):
+		r.setActivationCondition(activation, PhaseError, metav1.ConditionFalse, "ActivationAborted",
+			fmt.Sprintf("Activation of version %d on %s was cancelled", activation.Status.Version, activation.Spec.Network))
+	default:
+		logger.Info("Activation did not complete", "network", activation.Spec.Network, "status", status)
+		r.setActivationCondition(activation, PhaseError, metav1.ConditionFalse, "ActivationFailed",
+			fmt.Sprintf("Activation of version %d on %s finished with status %s", activation.Status.Version, activation.Spec.Network, status))
+	}
+	return ctrl.Result{}, r.Status().Update(ctx, activation)
+}
+
+// acquireSlot asks the scheduler for the slot of an activation and remembers the key it waits for
+func (r *AkamaiPropertyActivationReconciler) acquireSlot(activation *akamaiV1alpha1.AkamaiPropertyActivation, key activationSlot) (bool, int) {
+	r.rememberSlot(activation, key)
+	return r.ActivationScheduler.Acquire(key)
+}
+
+// trackSlot records an activation in flight with the scheduler and remembers the key it holds
+func (r *AkamaiPropertyActivationReconciler) trackSlot(activation *akamaiV1alpha1.AkamaiPropertyActivation, key activationSlot) {
+	r.rememberSlot(activation, key)
+	r.ActivationScheduler.Track(key)
+}
+
+func (r *AkamaiPropertyActivationReconciler) rememberSlot(activation *akamaiV1alpha1.AkamaiPropertyActivation, key activationSlot) {
+	r.slotMu.Lock()
+	defer r.slotMu.Unlock()
+	if r.slotKeys == nil {
+		r.slotKeys = make(map[types.NamespacedName]activationSlot)
+	}
+	r.slotKeys[client.ObjectKeyFromObject(activation)] = key
+}
+
+// releaseSlot frees the scheduler slot of an activation
+func (r *AkamaiPropertyActivationReconciler) releaseSlot(activation *akamaiV1alpha1.AkamaiPropertyActivation, key activationSlot) {
+	r.forgetSlot(client.ObjectKeyFromObject(activation))
+	r.ActivationScheduler.Release(key)
+}
+
+// forgetSlot drops and returns the scheduler key remembered for an activation
+func (r *AkamaiPropertyActivationReconciler) forgetSlot(name types.NamespacedName) (activationSlot, bool) {
+	r.slotMu.Lock()
+	defer r.slotMu.Unlock()
+	key, ok := r.slotKeys[name]
+	delete(r.slotKeys, name)
+	return key, ok
+}
+
+// akamaiClientFor returns the Akamai client of the property's account, applying the contract
+// and group defaults of its provider config
+func (r *AkamaiPropertyActivationReconciler) akamaiClientFor(ctx context.Context, property *akamaiV1alpha1.AkamaiProperty) (*akamai.Client, error) {
+	akamaiClient, err := resolveAccount(ctx, r.Client, r.ClientCache, r.Credentials, propertyAccount(property))
+	if err != nil || akamaiClient != nil {
+		return akamaiClient, err
+	}
+	if r.AkamaiClient == nil {
+		akamaiClient, err := akamai.NewClient(r.Credentials)
+		if err != nil {
+			return nil, err
+		}
+		r.AkamaiClient = akamaiClient
+	}
+	return r.AkamaiClient, nil
+}
+
+// propertyActivationRequest returns the activation request of an AkamaiPropertyActivation. The
+// settings of the property's spec.activation apply, with the note, notify emails and
+// acknowledgements of the activation resource on top.
+func propertyActivationRequest(property *akamaiV1alpha1.AkamaiProperty, activation *akamaiV1alpha1.AkamaiPropertyActivation, version int) (*akamaiV1alpha1.ActivationSpec, error) {
+	spec := akamaiV1alpha1.ActivationSpec{}
+	if property.Spec.Activation != nil {
+		spec = *property.Spec.Activation
+	}
+	spec.Note = activation.Spec.Note
+	withNote := property.DeepCopy()
+	withNote.Spec.Activation = &spec
+
+	requestSpec, err := activationRequestSpec(withNote, activation.Spec.Network, version)
+	if err != nil {
+		return nil, err
+	}
+	if len(activation.Spec.NotifyEmails) > 0 {
+		requestSpec.NotifyEmails = activation.Spec.NotifyEmails
+	}
+	requestSpec.AcknowledgeWarnings = append(slices.Clone(requestSpec.AcknowledgeWarnings), activation.Spec.AcknowledgeWarnings...)
+	requestSpec.AcknowledgeAllWarnings = requestSpec.AcknowledgeAllWarnings || activation.Spec.AcknowledgeAllWarnings
+	requestSpec.UseFastFallback = requestSpec.UseFastFallback || activation.Spec.UseFastFallback
+	return requestSpec, nil
+}
+
+// recordSubmittedActivation records the activation Akamai accepted in the status
+func recordSubmittedActivation(activation *akamaiV1alpha1.AkamaiPropertyActivation, propertyID string, version int, activationID, status string) {
+	now := metav1.Now()
+	activation.Status.PropertyID = propertyID
+	activation.Status.Version = version
+	activation.Status.ActivationID = activationID
+	activation.Status.SubmittedAt = &now
+	activation.Status.PendingWarnings = nil
+	recordActivationStatus(activation, status)
+}
+
+// recordActivationStatus sets the Akamai status of the activation, adding it to the history when
+// it changed
+func recordActivationStatus(activation *akamaiV1alpha1.AkamaiPropertyActivation, status string) {
+	if activation.Status.Status == status {
+		return
+	}
+	activation.Status.Status = status
+	activation.Status.History = append(activation.Status.History, akamaiV1alpha1.ActivationHistoryEntry{Status: status, Time: metav1.Now()})
+}
+
+// setActivationCondition sets the phase and the Ready condition of the activation
+func (r *AkamaiPropertyActivationReconciler) setActivationCondition(activation *akamaiV1alpha1.AkamaiPropertyActivation, phase string, status metav1.ConditionStatus, reason, message string) {
+	setReadyCondition(activation.Generation, readyStatus{
+		Phase:              &activation.Status.Phase,
+		ObservedGeneration: &activation.Status.ObservedGeneration,
+		LastUpdated:        &activation.Status.LastUpdated,
+		Conditions:         &activation.Status.Conditions,
+	}, phase, status, reason, message)
+}
+
+// SetupWithManager sets up the controller with the Manager. Status updates don't trigger
+// reconciles; activations are requeued while they are in flight, and an abort annotation is
+// acted on right away.
+func (r *AkamaiPropertyActivationReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&akamaiV1alpha1.AkamaiPropertyActivation{}, builder.WithPredicates(
+			predicate.Or(predicate.GenerationChangedPredicate{}, predicate.AnnotationChangedPredicate{}))).
+		Complete(r)
+}
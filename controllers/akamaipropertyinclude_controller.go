@@ -0,0 +1,390 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+	"github.com/mmz-srf/akamai-operator/pkg/akamai"
+)
+
+const (
+	// includeResyncInterval is how often an include is compared with Akamai, so changes made
+	// outside the operator are corrected
+	includeResyncInterval = 10 * time.Minute
+
+	// includeActivationPollInterval is how often pending include activations are polled
+	includeActivationPollInterval = time.Minute
+
+	// includeErrorRetryInterval is how long a failed include reconcile waits before it is retried
+	includeErrorRetryInterval = 2 * time.Minute
+
+	// defaultIncludeType is the type includes are created with unless spec.includeType sets one
+	defaultIncludeType = "COMMON_SETTINGS"
+)
+
+// AkamaiPropertyIncludeReconciler creates PAPI includes, keeps the rule tree of their latest
+// version in sync with the spec and activates it on the networks of spec.activation
+type AkamaiPropertyIncludeReconciler struct {
+	client.Client
+	Scheme       *runtime.Scheme
+	AkamaiClient *akamai.Client
+
+	// Credentials selects the credential source the Akamai client is created with
+	Credentials akamai.Credentials
+
+	// ClientCache holds the clients of the accounts referenced by spec.credentialsRef and
+	// spec.providerConfigRef
+	ClientCache *AkamaiClientCache
+
+	// Shard is the part of the fleet this instance manages; nil manages all includes
+	Shard *Shard
+
+	// ObserveOnly compares the includes with Akamai and reports differences without changing them
+	ObserveOnly bool
+}
+
+//+kubebuilder:rbac:groups=akamai.com,resources=akamaipropertyincludes,verbs=get;list;watch
+//+kubebuilder:rbac:groups=akamai.com,resources=akamaipropertyincludes/status,verbs=get;update;patch
+
+// Reconcile brings an include in Akamai to the state of its AkamaiPropertyInclude
+func (r *AkamaiPropertyIncludeReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	var include akamaiV1alpha1.AkamaiPropertyInclude
+	if err := r.Get(ctx, req.NamespacedName, &include); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	// Includes of other shards are left to the instances managing them
+	contains, err := r.Shard.containsAccount(ctx, r.Client, include.Labels, includeAccount(&include))
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if !contains {
+		logger.V(1).Info("Include belongs to another shard", "shard", r.Shard.Name)
+		return ctrl.Result{}, nil
+	}
+
+	// An invalid spec is not retried until it changes
+	if err := validateIncludeRules(&include.Spec.Rules); err != nil {
+		r.setIncludeCondition(&include, PhaseError, metav1.ConditionFalse, "InvalidSpec", err.Error())
+		return ctrl.Result{}, r.Status().Update(ctx, &include)
+	}
+
+	scoped, err := r.forAccount(ctx, &include)
+	if err != nil {
+		logger.Error(err, "Failed to create Akamai client")
+		r.setIncludeCondition(&include, PhaseError, metav1.ConditionFalse, "FailedToCreateClient", akamai.ErrorMessage(err))
+		if updateErr := r.Status().Update(ctx, &include); updateErr != nil {
+			return ctrl.Result{}, updateErr
+		}
+		return ctrl.Result{RequeueAfter: includeErrorRetryInterval}, nil
+	}
+
+	pending, err := scoped.syncInclude(ctx, &include)
+	if message, observed := observeOnlyMessage(r.ObserveOnly, err); observed && !pending {
+		r.setIncludeCondition(&include, PhaseObserving, metav1.ConditionFalse, ReasonObserveOnly, message)
+		if err := r.Status().Update(ctx, &include); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{RequeueAfter: includeResyncInterval}, nil
+	}
+	if err != nil {
+		logger.Error(err, "Failed to reconcile include", "include", include.Spec.IncludeName)
+		r.setIncludeCondition(&include, PhaseError, metav1.ConditionFalse, "ReconcileError", akamai.ErrorMessage(err))
+		if updateErr := r.Status().Update(ctx, &include); updateErr != nil {
+			return ctrl.Result{}, updateErr
+		}
+		return ctrl.Result{RequeueAfter: includeErrorRetryInterval}, nil
+	}
+
+	if failed := failedIncludeActivations(&include); failed != "" {
+		r.setIncludeCondition(&include, PhaseError, metav1.ConditionFalse, "ActivationFailed", failed)
+	} else if pending {
+		r.setIncludeCondition(&include, PhaseActivating, metav1.ConditionFalse, "ActivationPending",
+			fmt.Sprintf("Activating version %d", include.Status.LatestVersion))
+	} else {
+		r.setIncludeCondition(&include, PhaseReady, metav1.ConditionTrue, "IncludeReady",
+			fmt.Sprintf("Version %d is up to date", include.Status.LatestVersion))
+	}
+	if err := r.Status().Update(ctx, &include); err != nil {
+		return ctrl.Result{}, err
+	}
+	if pending {
+		return ctrl.Result{RequeueAfter: includeActivationPollInterval}, nil
+	}
+	return ctrl.Result{RequeueAfter: includeResyncInterval}, nil
+}
+
+// forAccount returns the reconciler to reconcile an include with: r itself using the operator's
+// Akamai client, or a copy using the client of the include's account
+func (r *AkamaiPropertyIncludeReconciler) forAccount(ctx context.Context, include *akamaiV1alpha1.AkamaiPropertyInclude) (*AkamaiPropertyIncludeReconciler, error) {
+	akamaiClient, err := resolveAccount(ctx, r.Client, r.ClientCache, r.Credentials, includeAccount(include))
+	if err != nil {
+		return nil, err
+	}
+
+	operatorClientMu.Lock()
+	defer operatorClientMu.Unlock()
+	if akamaiClient != nil {
+		scoped := *r
+		scoped.AkamaiClient = akamaiClient
+		return &scoped, nil
+	}
+	if r.AkamaiClient == nil {
+		akamaiClient, err := akamai.NewClient(r.Credentials)
+		if err != nil {
+			return nil, err
+		}
+		r.AkamaiClient = akamaiClient
+	}
+	return r, nil
+}
+
+// syncInclude creates the include if needed, writes the spec rules to its latest version and
+// activates it, recording the state in the status. It reports whether activations are pending.
+func (r *AkamaiPropertyIncludeReconciler) syncInclude(ctx context.Context, include *akamaiV1alpha1.AkamaiPropertyInclude) (bool, error) {
+	spec := include.Spec
+	if include.Status.IncludeID == "" {
+		includeID, err := r.ensureInclude(ctx, include)
+		if err != nil {
+			return false, err
+		}
+		include.Status.IncludeID = includeID
+	}
+	includeID := include.Status.IncludeID
+
+	current, err := r.AkamaiClient.GetInclude(ctx, includeID, spec.ContractID, spec.GroupID)
+	if err != nil {
+		return false, err
+	}
+	latestVersion, err := r.updateIncludeRules(ctx, include, current.LatestVersion)
+	if err != nil {
+		return false, err
+	}
+	include.Status.LatestVersion = latestVersion
+	includeNetworkStatus(include, "STAGING").Version = current.StagingVersion
+	includeNetworkStatus(include, "PRODUCTION").Version = current.ProductionVersion
+
+	if spec.Activation == nil {
+		return false, nil
+	}
+	pending := false
+	for _, network := range spec.Activation.Networks {
+		networkPending, err := r.activateInclude(ctx, include, network)
+		if err != nil {
+			return false, err
+		}
+		pending = pending || networkPending
+	}
+	return pending, nil
+}
+
+// ensureInclude returns the ID of the include of spec.includeName, creating it if it doesn't exist
+func (r *AkamaiPropertyIncludeReconciler) ensureInclude(ctx context.Context, include *akamaiV1alpha1.AkamaiPropertyInclude) (string, error) {
+	logger := log.FromContext(ctx)
+	spec := include.Spec
+
+	existing, err := r.AkamaiClient.FindIncludeByName(ctx, spec.IncludeName, spec.ContractID, spec.GroupID)
+	if err != nil {
+		return "", err
+	}
+	if existing != nil {
+		logger.Info("Adopting existing include", "include", spec.IncludeName, "includeID", existing.IncludeID)
+		return existing.IncludeID, nil
+	}
+	if r.ObserveOnly {
+		return "", observedDifference(fmt.Sprintf("Include %s doesn't exist in Akamai", spec.IncludeName))
+	}
+
+	includeType := spec.IncludeType
+	if includeType == "" {
+		includeType = defaultIncludeType
+	}
+	includeID, err := r.AkamaiClient.CreateInclude(ctx, spec.IncludeName, includeType, spec.ProductID, spec.RuleFormat, spec.ContractID, spec.GroupID)
+	if err != nil {
+		return "", err
+	}
+	logger.Info("Created include", "include", spec.IncludeName, "includeID", includeID)
+	return includeID, nil
+}
+
+// updateIncludeRules writes the spec rules to the latest version of the include when they differ,
+// creating a new version when the latest one was activated, and returns the latest version
+func (r *AkamaiPropertyIncludeReconciler) updateIncludeRules(ctx context.Context, include *akamaiV1alpha1.AkamaiPropertyInclude, latestVersion int) (int, error) {
+	logger := log.FromContext(ctx)
+	spec := include.Spec
+	includeID := include.Status.IncludeID
+
+	currentRules, err := r.AkamaiClient.GetIncludeVersionRules(ctx, includeID, latestVersion, spec.ContractID, spec.GroupID)
+	if err != nil {
+		return 0, err
+	}
+	// The rule comparison of properties doesn't depend on the state of a property reconciler
+	comparer := &AkamaiPropertyReconciler{}
+	differ, err := comparer.rulesNeedUpdate(&spec.Rules, currentRules.Rules, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to compare rules: %w", err)
+	}
+	if !differ {
+		return latestVersion, nil
+	}
+	if r.ObserveOnly {
+		return 0, observedDifference(fmt.Sprintf("The rules of include version %d differ from the spec", latestVersion))
+	}
+
+	version := latestVersion
+	editable, err := r.AkamaiClient.IsIncludeVersionEditable(ctx, includeID, latestVersion, spec.ContractID, spec.GroupID)
+	if err != nil {
+		return 0, err
+	}
+	if !editable {
+		if version, err = r.AkamaiClient.CreateIncludeVersion(ctx, includeID, latestVersion); err != nil {
+			return 0, err
+		}
+		// The new version has its own etag
+		if currentRules, err = r.AkamaiClient.GetIncludeVersionRules(ctx, includeID, version, spec.ContractID, spec.GroupID); err != nil {
+			return 0, err
+		}
+	}
+
+	rules, err := convertRulesToAkamaiFormat(&spec.Rules)
+	if err != nil {
+		return 0, fmt.Errorf("failed to convert rules to Akamai format: %w", err)
+	}
+	updated, err := r.AkamaiClient.UpdateIncludeRules(ctx, includeID, version, spec.ContractID, spec.GroupID, rules, currentRules.Etag)
+	if err != nil {
+		return 0, err
+	}
+	include.Status.Validation = validationStatus(updated.Warnings)
+	logger.Info("Updated include rules", "include", spec.IncludeName, "version", version, "warnings", len(updated.Warnings))
+	return version, nil
+}
+
+// activateInclude activates the latest version of the include on a network unless it is active
+// there, and reports whether an activation is pending. A failed activation of the latest version
+// is not retried; the next version is activated again.
+func (r *AkamaiPropertyIncludeReconciler) activateInclude(ctx context.Context, include *akamaiV1alpha1.AkamaiPropertyInclude, network string) (bool, error) {
+	logger := log.FromContext(ctx)
+	activation := include.Spec.Activation
+	latestVersion := include.Status.LatestVersion
+	status := includeNetworkStatus(include, network)
+	if status.Version == latestVersion {
+		return false, nil
+	}
+
+	if status.ActivationID != "" && status.ActivationVersion == latestVersion {
+		if isTerminalIncludeActivation(status.ActivationStatus) {
+			return false, nil
+		}
+		activationStatus, err := r.AkamaiClient.GetIncludeActivationStatus(ctx, include.Status.IncludeID, status.ActivationID)
+		if err != nil {
+			return false, err
+		}
+		status.ActivationStatus = activationStatus
+		if activationStatus == "ACTIVE" {
+			status.Version = latestVersion
+		}
+		return !isTerminalIncludeActivation(activationStatus), nil
+	}
+
+	if r.ObserveOnly {
+		return false, observedDifference(fmt.Sprintf("Include version %d isn't active on %s", latestVersion, network))
+	}
+	activationID, err := r.AkamaiClient.ActivateInclude(ctx, include.Status.IncludeID, latestVersion, network,
+		activation.Note, activation.NotifyEmails, activation.AcknowledgeAllWarnings)
+	if err != nil {
+		return false, err
+	}
+	logger.Info("Activating include", "include", include.Spec.IncludeName, "version", latestVersion, "network", network, "activationID", activationID)
+	status.ActivationID = activationID
+	status.ActivationVersion = latestVersion
+	status.ActivationStatus = "PENDING"
+	return true, nil
+}
+
+// includeNetworkStatus returns the status of the include on a network, creating it if needed
+func includeNetworkStatus(include *akamaiV1alpha1.AkamaiPropertyInclude, network string) *akamaiV1alpha1.IncludeNetworkStatus {
+	status := &include.Status.Staging
+	if network == "PRODUCTION" {
+		status = &include.Status.Production
+	}
+	if *status == nil {
+		*status = &akamaiV1alpha1.IncludeNetworkStatus{}
+	}
+	return *status
+}
+
+// isTerminalIncludeActivation reports whether an include activation no longer progresses
+func isTerminalIncludeActivation(status string) bool {
+	switch status {
+	case "ACTIVE", "FAILED", "ABORTED", "INACTIVE", "DEACTIVATED":
+		return true
+	}
+	return false
+}
+
+// failedIncludeActivations describes the activations of the latest version that failed
+func failedIncludeActivations(include *akamaiV1alpha1.AkamaiPropertyInclude) string {
+	var failed []string
+	for _, network := range []string{"STAGING", "PRODUCTION"} {
+		status := includeNetworkStatus(include, network)
+		if status.ActivationVersion == include.Status.LatestVersion &&
+			(status.ActivationStatus == "FAILED" || status.ActivationStatus == "ABORTED") {
+			failed = append(failed, fmt.Sprintf("activation %s of version %d on %s is %s", status.ActivationID, status.ActivationVersion, network, status.ActivationStatus))
+		}
+	}
+	return strings.Join(failed, "; ")
+}
+
+// validateIncludeRules checks the rule tree of an include like the rules of a property.
+// Placeholders and include references are only resolved in the rules of properties.
+func validateIncludeRules(rules *akamaiV1alpha1.PropertyRules) error {
+	if err := (&AkamaiPropertyReconciler{}).validatePropertyRules(rules); err != nil {
+		return err
+	}
+	tree, err := ruleTree(rules)
+	if err != nil {
+		return err
+	}
+	_, err = walkOptionStrings(tree, func(value string) (string, error) {
+		if placeholderStart.MatchString(value) || includeRefPattern.MatchString(value) {
+			return value, fmt.Errorf("placeholders are not resolved in includes, got %q", value)
+		}
+		return value, nil
+	})
+	return err
+}
+
+// setIncludeCondition sets the phase and the Ready condition of the include
+func (r *AkamaiPropertyIncludeReconciler) setIncludeCondition(include *akamaiV1alpha1.AkamaiPropertyInclude, phase string, status metav1.ConditionStatus, reason, message string) {
+	setReadyCondition(include.Generation, readyStatus{
+		Phase:              &include.Status.Phase,
+		ObservedGeneration: &include.Status.ObservedGeneration,
+		LastUpdated:        &include.Status.LastUpdated,
+		Conditions:         &include.Status.Conditions,
+	}, phase, status, reason, message)
+}
+
+// SetupWithManager sets up the controller with the Manager. Status updates don't trigger
+// reconciles; includes are requeued to poll activations and to correct changes made in Akamai.
+func (r *AkamaiPropertyIncludeReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&akamaiV1alpha1.AkamaiPropertyInclude{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Complete(r)
+}
@@ -0,0 +1,197 @@
+package controllers
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+	"github.com/mmz-srf/akamai-operator/pkg/akamai"
+)
+
+// Purge phases
+const (
+	PurgeInProgress = "InProgress"
+	PurgeCompleted  = "Completed"
+	PurgeFailed     = "Failed"
+)
+
+// purgeErrorRetryInterval is how long a purge that could not be submitted waits before it is retried
+const purgeErrorRetryInterval = 2 * time.Minute
+
+// AkamaiPurgeReconciler submits the Fast Purge requests of AkamaiPurge resources, once per
+// generation
+type AkamaiPurgeReconciler struct {
+	client.Client
+	Scheme       *runtime.Scheme
+	AkamaiClient *akamai.Client
+
+	// Credentials selects the credential source the Akamai client is created with
+	Credentials akamai.Credentials
+
+	// Shard is the part of the fleet this instance manages; nil manages all purges
+	Shard *Shard
+
+	// ObserveOnly holds the purges back; they are submitted once the operator runs without it
+	ObserveOnly bool
+
+	// now returns the current time; defaults to time.Now
+	now func() time.Time
+}
+
+//+kubebuilder:rbac:groups=akamai.com,resources=akamaipurges,verbs=get;list;watch
+//+kubebuilder:rbac:groups=akamai.com,resources=akamaipurges/status,verbs=get;update;patch
+
+// Reconcile purges the objects of an AkamaiPurge and records the progress in its status
+func (r *AkamaiPurgeReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	var purge akamaiV1alpha1.AkamaiPurge
+	if err := r.Get(ctx, req.NamespacedName, &purge); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	// Purges of other shards are left to the instances managing them
+	if !r.Shard.containsAccountWide(purge.Labels) {
+		logger.V(1).Info("Purge belongs to another shard", "shard", r.Shard.Name)
+		return ctrl.Result{}, nil
+	}
+
+	// A generation is purged once; only purges that could not be submitted are retried
+	if purge.Status.ObservedGeneration == purge.Generation {
+		switch purge.Status.Phase {
+		case PurgeCompleted, PurgeFailed:
+			return ctrl.Result{}, nil
+		case PurgeInProgress:
+			return r.checkCompletion(ctx, &purge)
+		}
+	}
+
+	objectType, objects := purgeObjects(&purge)
+	action, network := purgeAction(&purge), purgeNetwork(&purge)
+	if r.ObserveOnly {
+		if purge.Status.ObservedGeneration == purge.Generation && purge.Status.Phase == PhaseObserving {
+			return ctrl.Result{}, nil
+		}
+		logger.Info("Not purging in observe-only mode", "action", action, "network", network, "objects", len(objects))
+		purge.Status = akamaiV1alpha1.AkamaiPurgeStatus{ObservedGeneration: purge.Generation, Objects: len(objects), Phase: PhaseObserving,
+			Message: "The purge isn't submitted in observe-only mode; it is once the operator runs without it"}
+		return ctrl.Result{}, r.Status().Update(ctx, &purge)
+	}
+
+	if r.AkamaiClient == nil {
+		akamaiClient, err := akamai.NewClient(r.Credentials)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		r.AkamaiClient = akamaiClient
+	}
+
+	result, err := r.AkamaiClient.Purge(ctx, action, objectType, network, objects)
+	now := metav1.NewTime(r.clock())
+	purge.Status = akamaiV1alpha1.AkamaiPurgeStatus{ObservedGeneration: purge.Generation, Objects: len(objects)}
+	if err != nil {
+		logger.Error(err, "Failed to purge", "action", action, "network", network, "objects", len(objects))
+		purge.Status.Message = akamai.ErrorMessage(err)
+		// Requests Akamai refused fail the same way when they are sent again
+		if apiErr, ok := akamai.AsAPIError(err); ok && apiErr.StatusCode >= http.StatusBadRequest &&
+			apiErr.StatusCode < http.StatusInternalServerError && apiErr.StatusCode != http.StatusTooManyRequests {
+			purge.Status.Phase = PurgeFailed
+			purge.Status.CompletedAt = &now
+			return ctrl.Result{}, r.Status().Update(ctx, &purge)
+		}
+		purge.Status.Phase = PhaseError
+		if updateErr := r.Status().Update(ctx, &purge); updateErr != nil {
+			return ctrl.Result{}, updateErr
+		}
+		return ctrl.Result{RequeueAfter: purgeErrorRetryInterval}, nil
+	}
+
+	logger.Info("Submitted purge", "action", action, "network", network, "objects", len(objects),
+		"purgeIds", result.PurgeIDs, "estimatedSeconds", result.EstimatedSeconds)
+	purge.Status.Phase = PurgeInProgress
+	purge.Status.PurgeIDs = result.PurgeIDs
+	purge.Status.EstimatedSeconds = result.EstimatedSeconds
+	purge.Status.SubmittedAt = &now
+	if err := r.Status().Update(ctx, &purge); err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{RequeueAfter: time.Duration(result.EstimatedSeconds) * time.Second}, nil
+}
+
+// checkCompletion completes a submitted purge once the time Akamai estimated for it has passed.
+// The Fast Purge API has no endpoint reporting the progress of a purge.
+func (r *AkamaiPurgeReconciler) checkCompletion(ctx context.Context, purge *akamaiV1alpha1.AkamaiPurge) (ctrl.Result, error) {
+	now := r.clock()
+	if purge.Status.SubmittedAt != nil {
+		done := purge.Status.SubmittedAt.Add(time.Duration(purge.Status.EstimatedSeconds) * time.Second)
+		if remaining := done.Sub(now); remaining > 0 {
+			return ctrl.Result{RequeueAfter: remaining}, nil
+		}
+	}
+	completedAt := metav1.NewTime(now)
+	purge.Status.Phase = PurgeCompleted
+	purge.Status.CompletedAt = &completedAt
+	log.FromContext(ctx).Info("Purge completed", "purgeIds", purge.Status.PurgeIDs)
+	return ctrl.Result{}, r.Status().Update(ctx, purge)
+}
+
+func (r *AkamaiPurgeReconciler) clock() time.Time {
+	if r.now != nil {
+		return r.now()
+	}
+	return time.Now()
+}
+
+// purgeObjects returns the Fast Purge object type and the objects of the spec
+func purgeObjects(purge *akamaiV1alpha1.AkamaiPurge) (string, []string) {
+	switch {
+	case len(purge.Spec.CPCodes) > 0:
+		cpCodes := make([]string, 0, len(purge.Spec.CPCodes))
+		for _, cpCode := range purge.Spec.CPCodes {
+			cpCodes = append(cpCodes, strconv.FormatInt(cpCode, 10))
+		}
+		return akamai.PurgeTypeCPCode, cpCodes
+	case len(purge.Spec.Tags) > 0:
+		return akamai.PurgeTypeTag, purge.Spec.Tags
+	default:
+		return akamai.PurgeTypeURL, purge.Spec.URLs
+	}
+}
+
+// purgeAction returns the action of the spec, invalidate unless set
+func purgeAction(purge *akamaiV1alpha1.AkamaiPurge) string {
+	if purge.Spec.Action == "" {
+		return akamai.PurgeActionInvalidate
+	}
+	return purge.Spec.Action
+}
+
+// purgeNetwork returns the network of the spec, production unless set
+func purgeNetwork(purge *akamaiV1alpha1.AkamaiPurge) string {
+	if purge.Spec.Network == "" {
+		return "production"
+	}
+	return purge.Spec.Network
+}
+
+// SetupWithManager sets up the controller with the Manager. Status updates don't trigger
+// reconciles; submitted purges are requeued until their estimated time has passed.
+func (r *AkamaiPurgeReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&akamaiV1alpha1.AkamaiPurge{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Complete(r)
+}
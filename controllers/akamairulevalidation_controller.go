@@ -0,0 +1,176 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+	"github.com/mmz-srf/akamai-operator/pkg/akamai"
+)
+
+// Rule validation results
+const (
+	RuleValidationValid   = "Valid"
+	RuleValidationInvalid = "Invalid"
+	RuleValidationError   = "Error"
+)
+
+// AkamaiRuleValidationReconciler validates the candidate rule trees of AkamaiRuleValidation
+// resources with a PAPI dry run, once per generation
+type AkamaiRuleValidationReconciler struct {
+	client.Client
+	Scheme       *runtime.Scheme
+	AkamaiClient *akamai.Client
+
+	// Credentials selects the credential source the Akamai client is created with
+	Credentials akamai.Credentials
+
+	// ClientCache holds the Akamai clients of properties with a credentialsRef
+	ClientCache *AkamaiClientCache
+
+	// Shard is the part of the fleet this instance manages; validations follow their property
+	Shard *Shard
+}
+
+//+kubebuilder:rbac:groups=akamai.com,resources=akamairulevalidations,verbs=get;list;watch
+//+kubebuilder:rbac:groups=akamai.com,resources=akamairulevalidations/status,verbs=get;update;patch
+
+// Reconcile validates the rule tree of an AkamaiRuleValidation and records the outcome in its status
+func (r *AkamaiRuleValidationReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	var validation akamaiV1alpha1.AkamaiRuleValidation
+	if err := r.Get(ctx, req.NamespacedName, &validation); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	// A generation is validated once; only failures to validate are retried
+	if validation.Status.ObservedGeneration == validation.Generation &&
+		(validation.Status.Result == RuleValidationValid || validation.Status.Result == RuleValidationInvalid) {
+		return ctrl.Result{}, nil
+	}
+
+	var property akamaiV1alpha1.AkamaiProperty
+	if err := r.Get(ctx, types.NamespacedName{Name: validation.Spec.PropertyRef}, &property); err != nil {
+		if apierrors.IsNotFound(err) {
+			return r.setValidationError(ctx, &validation, 0, fmt.Errorf("AkamaiProperty %q not found", validation.Spec.PropertyRef))
+		}
+		return ctrl.Result{}, err
+	}
+	if inShard, err := r.Shard.containsProperty(ctx, r.Client, &property); err != nil || !inShard {
+		return ctrl.Result{}, err
+	}
+	if property.Status.PropertyID == "" {
+		return r.setValidationError(ctx, &validation, 0, fmt.Errorf("AkamaiProperty %q has not been created in Akamai yet", validation.Spec.PropertyRef))
+	}
+
+	// Validate with the credentials of the property's Akamai account
+	akamaiClient, err := r.akamaiClientFor(ctx, &property)
+	if err != nil {
+		logger.Error(err, "Failed to create Akamai client")
+		return r.setValidationError(ctx, &validation, 0, err)
+	}
+
+	version := validation.Spec.Version
+	if version == 0 {
+		version = property.Status.LatestVersion
+	}
+
+	rules, err := convertRulesToAkamaiFormat(&validation.Spec.Rules)
+	if err != nil {
+		return r.setValidationError(ctx, &validation, version, err)
+	}
+	result, err := akamaiClient.ValidatePropertyRules(ctx,
+		property.Status.PropertyID,
+		version,
+		property.Spec.ContractID,
+		property.Spec.GroupID,
+		rules)
+	if err != nil {
+		logger.Error(err, "Failed to validate rules", "property", validation.Spec.PropertyRef, "version", version)
+		return r.setValidationError(ctx, &validation, version, err)
+	}
+
+	validation.Status = ruleValidationStatus(result, validation.Generation)
+	logger.Info("Validated rules", "property", validation.Spec.PropertyRef, "version", version,
+		"result", validation.Status.Result, "errors", len(result.Errors), "warnings", len(result.Warnings))
+	return ctrl.Result{}, r.Status().Update(ctx, &validation)
+}
+
+// akamaiClientFor returns the Akamai client of the property's account, applying the contract
+// and group defaults of its provider config
+func (r *AkamaiRuleValidationReconciler) akamaiClientFor(ctx context.Context, property *akamaiV1alpha1.AkamaiProperty) (*akamai.Client, error) {
+	akamaiClient, err := resolveAccount(ctx, r.Client, r.ClientCache, r.Credentials, propertyAccount(property))
+	if err != nil || akamaiClient != nil {
+		return akamaiClient, err
+	}
+	if r.AkamaiClient == nil {
+		akamaiClient, err := akamai.NewClient(r.Credentials)
+		if err != nil {
+			return nil, err
+		}
+		r.AkamaiClient = akamaiClient
+	}
+	return r.AkamaiClient, nil
+}
+
+// ruleValidationStatus converts a PAPI validation result into the status of a generation
+func ruleValidationStatus(result *akamai.RuleValidationResult, generation int64) akamaiV1alpha1.AkamaiRuleValidationStatus {
+	now := metav1.Now()
+	status := akamaiV1alpha1.AkamaiRuleValidationStatus{
+		Result:             RuleValidationValid,
+		ObservedGeneration: generation,
+		PropertyVersion:    result.PropertyVersion,
+		LastValidated:      &now,
+	}
+	if len(result.Errors) > 0 {
+		status.Result = RuleValidationInvalid
+	}
+	for _, ruleError := range result.Errors {
+		status.Errors = append(status.Errors, akamaiV1alpha1.ValidationWarning{
+			Type:     ruleError.Type,
+			Title:    ruleError.Title,
+			Detail:   ruleError.Detail,
+			Location: ruleError.ErrorLocation,
+		})
+	}
+	if warnings := validationStatus(result.Warnings); warnings != nil {
+		status.Warnings = warnings.Warnings
+	}
+	return status
+}
+
+// setValidationError records that the rules could not be validated and retries later
+func (r *AkamaiRuleValidationReconciler) setValidationError(ctx context.Context, validation *akamaiV1alpha1.AkamaiRuleValidation, version int, err error) (ctrl.Result, error) {
+	now := metav1.Now()
+	validation.Status = akamaiV1alpha1.AkamaiRuleValidationStatus{
+		Result:             RuleValidationError,
+		ObservedGeneration: validation.Generation,
+		PropertyVersion:    version,
+		Message:            akamai.ErrorMessage(err),
+		LastValidated:      &now,
+	}
+	if err := r.Status().Update(ctx, validation); err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{RequeueAfter: time.Minute * 2}, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *AkamaiRuleValidationReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&akamaiV1alpha1.AkamaiRuleValidation{}).
+		Complete(r)
+}
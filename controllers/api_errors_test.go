@@ -0,0 +1,47 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v8/pkg/papi"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+)
+
+func TestRecordAPIError(t *testing.T) {
+	property := &akamaiV1alpha1.AkamaiProperty{}
+
+	recordAPIError(property, "update rules", errors.New("connection refused"))
+	if property.Status.LastAPIError != nil {
+		t.Fatalf("recorded a non-API error: %+v", property.Status.LastAPIError)
+	}
+
+	err := fmt.Errorf("failed to update property rules: %w", &papi.Error{
+		StatusCode: http.StatusBadRequest,
+		Title:      "Invalid rule tree",
+		Detail:     strings.Repeat("x", 2000),
+		Instance:   "https://akaa-xyz.luna.akamaiapis.net/papi/v1/properties/prp_1#8a2b",
+		Errors:     json.RawMessage(`[` + strings.Repeat(`{"behaviorName": "origin", "errorLocation": "#/rules/behaviors/0", "detail": "hostname is required"},`, 11) + `{}]`),
+	})
+	recordAPIError(property, "update rules", err)
+
+	recorded := property.Status.LastAPIError
+	if recorded == nil {
+		t.Fatal("API error was not recorded")
+	}
+	if recorded.Operation != "update rules" || recorded.StatusCode != http.StatusBadRequest ||
+		recorded.Instance != "https://akaa-xyz.luna.akamaiapis.net/papi/v1/properties/prp_1#8a2b" || recorded.Time.IsZero() {
+		t.Errorf("unexpected recorded error %+v", recorded)
+	}
+	if len(recorded.Detail) != maxAPIErrorDetail || !strings.HasSuffix(recorded.Detail, "...") {
+		t.Errorf("detail was not truncated: %d characters", len(recorded.Detail))
+	}
+	if len(recorded.Errors) != maxAPIErrorProblems || recorded.Errors[0] != `behavior "origin" at #/rules/behaviors/0: hostname is required` {
+		t.Errorf("unexpected problems %q", recorded.Errors)
+	}
+}
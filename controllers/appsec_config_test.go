@@ -0,0 +1,576 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"testing"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v8/pkg/appsec"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+	"github.com/mmz-srf/akamai-operator/pkg/akamai"
+)
+
+// appSecVersion is a version of a security configuration in the appSecAPI stub
+type appSecVersion struct {
+	hostnames []string
+	policies  map[string]string
+	wafModes  map[string]string
+	rules     map[string]map[int]string
+	targets   []map[string]interface{}
+
+	ratePolicies []map[string]interface{}
+	rateActions  map[string]map[int]string
+}
+
+// clone returns a deep copy of the version
+func (v *appSecVersion) clone() *appSecVersion {
+	cloned := &appSecVersion{
+		hostnames: slices.Clone(v.hostnames),
+		policies:  maps.Clone(v.policies),
+		wafModes:  maps.Clone(v.wafModes),
+		rules:     map[string]map[int]string{},
+		targets:   slices.Clone(v.targets),
+
+		ratePolicies: slices.Clone(v.ratePolicies),
+		rateActions:  map[string]map[int]string{},
+	}
+	for policyID, actions := range v.rules {
+		cloned.rules[policyID] = maps.Clone(actions)
+	}
+	for policyID, actions := range v.rateActions {
+		cloned.rateActions[policyID] = maps.Clone(actions)
+	}
+	return cloned
+}
+
+// appSecConfigState is a security configuration in the appSecAPI stub
+type appSecConfigState struct {
+	name                        string
+	latest, staging, production int
+	versions                    map[int]*appSecVersion
+}
+
+// appSecAPI stubs the Application Security endpoints, recording the calls that change
+// configurations. Activations stay pending until activate is called.
+type appSecAPI struct {
+	appsec.APPSEC
+	configs     map[int]*appSecConfigState
+	activations map[int]*appsec.CreateActivationsRequest
+	statuses    map[int]appsec.StatusValue
+	calls       []string
+}
+
+// decodeAppSec fills an API response, whose fields are often anonymous structs, from JSON
+func decodeAppSec[T any](value interface{}) *T {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		panic(err)
+	}
+	var resp T
+	if err := json.Unmarshal(encoded, &resp); err != nil {
+		panic(err)
+	}
+	return &resp
+}
+
+func (s *appSecAPI) version(configID, version int) *appSecVersion {
+	return s.configs[configID].versions[version]
+}
+
+func (s *appSecAPI) GetConfigurations(_ context.Context, _ appsec.GetConfigurationsRequest) (*appsec.GetConfigurationsResponse, error) {
+	var configs []map[string]interface{}
+	for id, config := range s.configs {
+		configs = append(configs, map[string]interface{}{"id": id, "name": config.name, "latestVersion": config.latest})
+	}
+	return decodeAppSec[appsec.GetConfigurationsResponse](map[string]interface{}{"configurations": configs}), nil
+}
+
+func (s *appSecAPI) GetConfiguration(_ context.Context, params appsec.GetConfigurationRequest) (*appsec.GetConfigurationResponse, error) {
+	config, ok := s.configs[params.ConfigID]
+	if !ok {
+		return nil, &appsec.Error{StatusCode: http.StatusNotFound, Title: "Not Found"}
+	}
+	return &appsec.GetConfigurationResponse{ID: params.ConfigID, Name: config.name, LatestVersion: config.latest,
+		StagingVersion: config.staging, ProductionVersion: config.production}, nil
+}
+
+func (s *appSecAPI) CreateConfiguration(_ context.Context, params appsec.CreateConfigurationRequest) (*appsec.CreateConfigurationResponse, error) {
+	s.calls = append(s.calls, "create "+params.Name)
+	configID := 100 + len(s.configs)
+	s.configs[configID] = &appSecConfigState{name: params.Name, latest: 1, versions: map[int]*appSecVersion{
+		1: {hostnames: params.Hostnames, policies: map[string]string{}, wafModes: map[string]string{}, rules: map[string]map[int]string{}},
+	}}
+	return &appsec.CreateConfigurationResponse{ConfigID: configID, Version: 1}, nil
+}
+
+func (s *appSecAPI) RemoveConfiguration(_ context.Context, params appsec.RemoveConfigurationRequest) (*appsec.RemoveConfigurationResponse, error) {
+	s.calls = append(s.calls, fmt.Sprintf("delete %d", params.ConfigID))
+	delete(s.configs, params.ConfigID)
+	return &appsec.RemoveConfigurationResponse{}, nil
+}
+
+func (s *appSecAPI) CreateConfigurationVersionClone(_ context.Context, params appsec.CreateConfigurationVersionCloneRequest) (*appsec.CreateConfigurationVersionCloneResponse, error) {
+	s.calls = append(s.calls, fmt.Sprintf("clone %d", params.CreateFromVersion))
+	config := s.configs[params.ConfigID]
+	config.latest++
+	config.versions[config.latest] = config.versions[params.CreateFromVersion].clone()
+	return &appsec.CreateConfigurationVersionCloneResponse{ConfigID: params.ConfigID, Version: config.latest}, nil
+}
+
+func (s *appSecAPI) GetSecurityPolicies(_ context.Context, params appsec.GetSecurityPoliciesRequest) (*appsec.GetSecurityPoliciesResponse, error) {
+	var policies []map[string]string
+	for name, id := range s.version(params.ConfigID, params.Version).policies {
+		policies = append(policies, map[string]string{"policyId": id, "policyName": name})
+	}
+	return decodeAppSec[appsec.GetSecurityPoliciesResponse](map[string]interface{}{"policies": policies}), nil
+}
+
+func (s *appSecAPI) CreateSecurityPolicy(_ context.Context, params appsec.CreateSecurityPolicyRequest) (*appsec.CreateSecurityPolicyResponse, error) {
+	s.calls = append(s.calls, fmt.Sprintf("create policy %s in %d", params.PolicyName, params.Version))
+	version := s.version(params.ConfigID, params.Version)
+	policyID := fmt.Sprintf("%s_%d", params.PolicyPrefix, 100000+len(version.policies))
+	version.policies[params.PolicyName] = policyID
+	version.wafModes[policyID] = "AAG"
+	version.rules[policyID] = map[int]string{950002: "alert", 950006: "alert"}
+	return &appsec.CreateSecurityPolicyResponse{PolicyID: policyID, PolicyName: params.PolicyName}, nil
+}
+
+func (s *appSecAPI) GetWAFMode(_ context.Context, params appsec.GetWAFModeRequest) (*appsec.GetWAFModeResponse, error) {
+	return &appsec.GetWAFModeResponse{Mode: s.version(params.ConfigID, params.Version).wafModes[params.PolicyID]}, nil
+}
+
+func (s *appSecAPI) UpdateWAFMode(_ context.Context, params appsec.UpdateWAFModeRequest) (*appsec.UpdateWAFModeResponse, error) {
+	s.calls = append(s.calls, fmt.Sprintf("set mode %s %s in %d", params.PolicyID, params.Mode, params.Version))
+	s.version(params.ConfigID, params.Version).wafModes[params.PolicyID] = params.Mode
+	return &appsec.UpdateWAFModeResponse{Mode: params.Mode}, nil
+}
+
+func (s *appSecAPI) GetRules(_ context.Context, params appsec.GetRulesRequest) (*appsec.GetRulesResponse, error) {
+	var rules []map[string]interface{}
+	for id, action := range s.version(params.ConfigID, params.Version).rules[params.PolicyID] {
+		rules = append(rules, map[string]interface{}{"id": id, "action": action})
+	}
+	return decodeAppSec[appsec.GetRulesResponse](map[string]interface{}{"ruleActions": rules}), nil
+}
+
+func (s *appSecAPI) UpdateRule(_ context.Context, params appsec.UpdateRuleRequest) (*appsec.UpdateRuleResponse, error) {
+	s.calls = append(s.calls, fmt.Sprintf("set rule %s %d %s in %d", params.PolicyID, params.RuleID, params.Action, params.Version))
+	s.version(params.ConfigID, params.Version).rules[params.PolicyID][params.RuleID] = params.Action
+	return &appsec.UpdateRuleResponse{Action: params.Action}, nil
+}
+
+func (s *appSecAPI) GetSelectedHostnames(_ context.Context, params appsec.GetSelectedHostnamesRequest) (*appsec.GetSelectedHostnamesResponse, error) {
+	resp := &appsec.GetSelectedHostnamesResponse{}
+	for _, hostname := range s.version(params.ConfigID, params.Version).hostnames {
+		resp.HostnameList = append(resp.HostnameList, appsec.Hostname{Hostname: hostname})
+	}
+	return resp, nil
+}
+
+func (s *appSecAPI) UpdateSelectedHostnames(_ context.Context, params appsec.UpdateSelectedHostnamesRequest) (*appsec.UpdateSelectedHostnamesResponse, error) {
+	s.calls = append(s.calls, fmt.Sprintf("set hostnames in %d", params.Version))
+	version := s.version(params.ConfigID, params.Version)
+	version.hostnames = nil
+	for _, hostname := range params.HostnameList {
+		version.hostnames = append(version.hostnames, hostname.Hostname)
+	}
+	return &appsec.UpdateSelectedHostnamesResponse{HostnameList: params.HostnameList}, nil
+}
+
+func (s *appSecAPI) GetMatchTargets(_ context.Context, params appsec.GetMatchTargetsRequest) (*appsec.GetMatchTargetsResponse, error) {
+	targets := s.version(params.ConfigID, params.ConfigVersion).targets
+	return decodeAppSec[appsec.GetMatchTargetsResponse](map[string]interface{}{"matchTargets": map[string]interface{}{"websiteTargets": targets}}), nil
+}
+
+func (s *appSecAPI) CreateMatchTarget(_ context.Context, params appsec.CreateMatchTargetRequest) (*appsec.CreateMatchTargetResponse, error) {
+	s.calls = append(s.calls, fmt.Sprintf("create target in %d", params.ConfigVersion))
+	var target map[string]interface{}
+	if err := json.Unmarshal(params.JsonPayloadRaw, &target); err != nil {
+		return nil, err
+	}
+	version := s.version(params.ConfigID, params.ConfigVersion)
+	target["targetId"] = 1000 + len(s.calls)
+	version.targets = append(version.targets, target)
+	return &appsec.CreateMatchTargetResponse{}, nil
+}
+
+func (s *appSecAPI) RemoveMatchTarget(_ context.Context, params appsec.RemoveMatchTargetRequest) (*appsec.RemoveMatchTargetResponse, error) {
+	s.calls = append(s.calls, fmt.Sprintf("remove target %d in %d", params.TargetID, params.ConfigVersion))
+	version := s.version(params.ConfigID, params.ConfigVersion)
+	version.targets = slices.DeleteFunc(version.targets, func(target map[string]interface{}) bool {
+		return target["targetId"] == params.TargetID
+	})
+	return &appsec.RemoveMatchTargetResponse{}, nil
+}
+
+func (s *appSecAPI) GetRatePolicies(_ context.Context, params appsec.GetRatePoliciesRequest) (*appsec.GetRatePoliciesResponse, error) {
+	policies := s.version(params.ConfigID, params.ConfigVersion).ratePolicies
+	return decodeAppSec[appsec.GetRatePoliciesResponse](map[string]interface{}{"ratePolicies": policies}), nil
+}
+
+func (s *appSecAPI) CreateRatePolicy(_ context.Context, params appsec.CreateRatePolicyRequest) (*appsec.CreateRatePolicyResponse, error) {
+	var policy map[string]interface{}
+	if err := json.Unmarshal(params.JsonPayloadRaw, &policy); err != nil {
+		return nil, err
+	}
+	s.calls = append(s.calls, fmt.Sprintf("create rate policy %s in %d", policy["name"], params.ConfigVersion))
+	version := s.version(params.ConfigID, params.ConfigVersion)
+	policy["id"] = 500 + len(version.ratePolicies)
+	version.ratePolicies = append(version.ratePolicies, policy)
+	return &appsec.CreateRatePolicyResponse{ID: policy["id"].(int)}, nil
+}
+
+func (s *appSecAPI) UpdateRatePolicy(_ context.Context, params appsec.UpdateRatePolicyRequest) (*appsec.UpdateRatePolicyResponse, error) {
+	s.calls = append(s.calls, fmt.Sprintf("update rate policy %d in %d", params.RatePolicyID, params.ConfigVersion))
+	var policy map[string]interface{}
+	if err := json.Unmarshal(params.JsonPayloadRaw, &policy); err != nil {
+		return nil, err
+	}
+	policy["id"] = params.RatePolicyID
+	version := s.version(params.ConfigID, params.ConfigVersion)
+	for i, current := range version.ratePolicies {
+		if fmt.Sprint(current["id"]) == fmt.Sprint(params.RatePolicyID) {
+			version.ratePolicies[i] = policy
+		}
+	}
+	return &appsec.UpdateRatePolicyResponse{ID: params.RatePolicyID}, nil
+}
+
+func (s *appSecAPI) GetRatePolicyActions(_ context.Context, params appsec.GetRatePolicyActionsRequest) (*appsec.GetRatePolicyActionsResponse, error) {
+	var actions []map[string]interface{}
+	for id, action := range s.version(params.ConfigID, params.Version).rateActions[params.PolicyID] {
+		actions = append(actions, map[string]interface{}{"id": id, "ipv4Action": action, "ipv6Action": action})
+	}
+	return decodeAppSec[appsec.GetRatePolicyActionsResponse](map[string]interface{}{"ratePolicyActions": actions}), nil
+}
+
+func (s *appSecAPI) UpdateRatePolicyAction(_ context.Context, params appsec.UpdateRatePolicyActionRequest) (*appsec.UpdateRatePolicyActionResponse, error) {
+	s.calls = append(s.calls, fmt.Sprintf("set rate action %s %d %s in %d", params.PolicyID, params.RatePolicyID, params.Ipv4Action, params.Version))
+	version := s.version(params.ConfigID, params.Version)
+	if version.rateActions == nil {
+		version.rateActions = map[string]map[int]string{}
+	}
+	if version.rateActions[params.PolicyID] == nil {
+		version.rateActions[params.PolicyID] = map[int]string{}
+	}
+	version.rateActions[params.PolicyID][params.RatePolicyID] = params.Ipv4Action
+	return &appsec.UpdateRatePolicyActionResponse{ID: params.RatePolicyID, Ipv4Action: params.Ipv4Action, Ipv6Action: params.Ipv6Action}, nil
+}
+
+func (s *appSecAPI) CreateActivations(_ context.Context, params appsec.CreateActivationsRequest, _ bool) (*appsec.CreateActivationsResponse, error) {
+	config := params.ActivationConfigs[0]
+	s.calls = append(s.calls, fmt.Sprintf("activate %d on %s", config.ConfigVersion, params.Network))
+	activationID := len(s.activations) + 1
+	s.activations[activationID] = &params
+	s.statuses[activationID] = appsec.StatusPending
+	return &appsec.CreateActivationsResponse{ActivationID: activationID, Status: appsec.StatusPending}, nil
+}
+
+func (s *appSecAPI) GetActivations(_ context.Context, params appsec.GetActivationsRequest) (*appsec.GetActivationsResponse, error) {
+	return &appsec.GetActivationsResponse{ActivationID: params.ActivationID, Status: s.statuses[params.ActivationID]}, nil
+}
+
+// activate completes the pending activations
+func (s *appSecAPI) activate() {
+	for activationID, status := range s.statuses {
+		if status != appsec.StatusPending {
+			continue
+		}
+		s.statuses[activationID] = appsec.StatusActive
+		request := s.activations[activationID]
+		config := s.configs[request.ActivationConfigs[0].ConfigID]
+		if request.Network == string(appsec.NetworkStaging) {
+			config.staging = request.ActivationConfigs[0].ConfigVersion
+		} else {
+			config.production = request.ActivationConfigs[0].ConfigVersion
+		}
+	}
+}
+
+func newAppSecConfigReconciler(t *testing.T, stub *appSecAPI, objects ...client.Object) *AkamaiAppSecConfigReconciler {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	if err := akamaiV1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to build scheme: %v", err)
+	}
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(objects...).
+		WithStatusSubresource(&akamaiV1alpha1.AkamaiAppSecConfig{}).
+		Build()
+	return &AkamaiAppSecConfigReconciler{Client: fakeClient, Scheme: scheme, AkamaiClient: akamai.NewClientWithAppSec(stub)}
+}
+
+func newAppSecAPI() *appSecAPI {
+	return &appSecAPI{
+		configs:     map[int]*appSecConfigState{},
+		activations: map[int]*appsec.CreateActivationsRequest{},
+		statuses:    map[int]appsec.StatusValue{},
+	}
+}
+
+func TestAppSecConfigReconcile(t *testing.T) {
+	ctx := context.Background()
+	config := &akamaiV1alpha1.AkamaiAppSecConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "www", Generation: 1},
+		Spec: akamaiV1alpha1.AkamaiAppSecConfigSpec{
+			ContractID: "ctr_C-123",
+			GroupID:    "grp_456",
+			Hostnames:  []string{"www.example.com"},
+			Policies: []akamaiV1alpha1.AppSecPolicySpec{{
+				Name:        "Website",
+				Prefix:      "web1",
+				WAFMode:     "KRS",
+				RuleActions: []akamaiV1alpha1.AppSecRuleAction{{RuleID: 950002, Action: "deny"}},
+			}},
+			MatchTargets:   []akamaiV1alpha1.AppSecMatchTargetSpec{{Policy: "Website"}},
+			Activation:     &akamaiV1alpha1.AppSecActivationSpec{Staging: true, Production: true},
+			DeletionPolicy: akamaiV1alpha1.DeletionPolicyDelete,
+		},
+	}
+	stub := newAppSecAPI()
+	r := newAppSecConfigReconciler(t, stub, config)
+	key := types.NamespacedName{Name: config.Name}
+	reconcile := func() (*akamaiV1alpha1.AkamaiAppSecConfig, ctrl.Result) {
+		t.Helper()
+		stub.calls = nil
+		result, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+		if err != nil {
+			t.Fatalf("Reconcile() error = %v", err)
+		}
+		var got akamaiV1alpha1.AkamaiAppSecConfig
+		if err := r.Get(ctx, key, &got); err != nil {
+			t.Fatalf("failed to get security configuration: %v", err)
+		}
+		return &got, result
+	}
+
+	// The configuration is created, its first version edited in place and activated on staging
+	got, result := reconcile()
+	expected := []string{
+		"create www",
+		"create policy Website in 1",
+		"set mode web1_100000 KRS in 1",
+		"set rule web1_100000 950002 deny in 1",
+		"create target in 1",
+		"activate 1 on STAGING",
+	}
+	if !slices.Equal(stub.calls, expected) {
+		t.Errorf("calls = %q, expected %q", stub.calls, expected)
+	}
+	if got.Status.ConfigID != 100 || got.Status.Policies["Website"] != "web1_100000" || got.Status.Phase != PhaseActivating ||
+		result.RequeueAfter != appSecActivationPollInterval || got.Status.Production != nil || len(got.Status.LastChanges) != 4 {
+		t.Errorf("status = %+v, result = %+v, expected the configuration to be activating on staging", got.Status, result)
+	}
+
+	// Once active on staging the version is activated on production
+	stub.activate()
+	got, _ = reconcile()
+	if !slices.Equal(stub.calls, []string{"activate 1 on PRODUCTION"}) || got.Status.Staging.Status != string(appsec.StatusActive) {
+		t.Errorf("calls = %q, status = %+v, expected the activation on production", stub.calls, got.Status)
+	}
+	stub.activate()
+	got, result = reconcile()
+	if len(stub.calls) != 0 || got.Status.Phase != PhaseReady || got.Status.ProductionVersion != 1 || result.RequeueAfter != appSecResyncInterval {
+		t.Errorf("calls = %q, status = %+v, expected the active configuration to be ready", stub.calls, got.Status)
+	}
+
+	// A change made outside the operator goes to a new version, since version 1 is active
+	stub.version(100, 1).rules["web1_100000"][950002] = "alert"
+	got, _ = reconcile()
+	expected = []string{"clone 1", "set rule web1_100000 950002 deny in 2", "activate 2 on STAGING"}
+	if !slices.Equal(stub.calls, expected) {
+		t.Errorf("calls = %q, expected %q", stub.calls, expected)
+	}
+	if got.Status.LatestVersion != 2 || !slices.Equal(got.Status.LastChanges, []string{"set action of rule 950002 of policy Website to deny"}) {
+		t.Errorf("status = %+v, expected the change in version 2", got.Status)
+	}
+
+	// Version 2 is being activated, so new hostnames go to version 3 and replace the match targets
+	got.Spec.Hostnames = append(got.Spec.Hostnames, "api.example.com")
+	if err := r.Update(ctx, got); err != nil {
+		t.Fatalf("failed to update security configuration: %v", err)
+	}
+	got, _ = reconcile()
+	expected = []string{"clone 2", "set hostnames in 3", "remove target 1005 in 3", "create target in 3", "activate 3 on STAGING"}
+	if !slices.Equal(stub.calls, expected) {
+		t.Errorf("calls = %q, expected %q", stub.calls, expected)
+	}
+	if got.Status.LatestVersion != 3 || got.Status.Staging.Version != 3 {
+		t.Errorf("status = %+v, expected the activation of version 3", got.Status)
+	}
+
+	// Deleting the resource deletes the configuration with the Delete deletion policy
+	if err := r.Delete(ctx, got); err != nil {
+		t.Fatalf("failed to delete security configuration: %v", err)
+	}
+	stub.calls = nil
+	if _, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: key}); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	if !slices.Equal(stub.calls, []string{"delete 100"}) || len(stub.configs) != 0 {
+		t.Errorf("calls = %q, expected the configuration to be deleted", stub.calls)
+	}
+}
+
+func TestAppSecConfigAdoption(t *testing.T) {
+	ctx := context.Background()
+	config := &akamaiV1alpha1.AkamaiAppSecConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "shop", Generation: 1},
+		Spec: akamaiV1alpha1.AkamaiAppSecConfigSpec{
+			Name:      "Shop",
+			Hostnames: []string{"shop.example.com"},
+			Policies:  []akamaiV1alpha1.AppSecPolicySpec{{Name: "Shop", Prefix: "shp1", WAFMode: "ASE_AUTO"}},
+		},
+	}
+	stub := newAppSecAPI()
+	stub.configs[42] = &appSecConfigState{name: "Shop", latest: 3, production: 3, versions: map[int]*appSecVersion{
+		3: {
+			hostnames: []string{"SHOP.example.com"},
+			policies:  map[string]string{"Shop": "shp1_1", "Legacy": "lgc1_2"},
+			wafModes:  map[string]string{"shp1_1": "ASE_AUTO", "lgc1_2": "KRS"},
+			rules:     map[string]map[int]string{},
+		},
+	}}
+	r := newAppSecConfigReconciler(t, stub, config)
+	if _, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: config.Name}}); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	var got akamaiV1alpha1.AkamaiAppSecConfig
+	if err := r.Get(ctx, types.NamespacedName{Name: config.Name}, &got); err != nil {
+		t.Fatalf("failed to get security configuration: %v", err)
+	}
+	if len(stub.calls) != 0 || got.Status.ConfigID != 42 || got.Status.LatestVersion != 3 || got.Status.Phase != PhaseReady ||
+		!maps.Equal(got.Status.Policies, map[string]string{"Shop": "shp1_1"}) {
+		t.Errorf("calls = %q, status = %+v, expected the configuration to be adopted unchanged", stub.calls, got.Status)
+	}
+}
+
+func TestAppSecRatePolicies(t *testing.T) {
+	ctx := context.Background()
+	config := &akamaiV1alpha1.AkamaiAppSecConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "shop", Generation: 1},
+		Spec: akamaiV1alpha1.AkamaiAppSecConfigSpec{
+			Hostnames: []string{"shop.example.com"},
+			Policies:  []akamaiV1alpha1.AppSecPolicySpec{{Name: "Shop", Prefix: "shp1"}},
+			RatePolicies: []akamaiV1alpha1.AppSecRatePolicySpec{{
+				Name:             "Login",
+				AverageThreshold: 5,
+				BurstThreshold:   10,
+				Paths:            []string{"/login"},
+				Actions:          []akamaiV1alpha1.AppSecRatePolicyAction{{Policy: "Shop", Action: "deny"}},
+			}},
+		},
+	}
+	stub := newAppSecAPI()
+	stub.configs[42] = &appSecConfigState{name: "shop", latest: 2, production: 2, versions: map[int]*appSecVersion{
+		2: {
+			hostnames: []string{"shop.example.com"},
+			policies:  map[string]string{"Shop": "shp1_1"},
+			wafModes:  map[string]string{},
+			rules:     map[string]map[int]string{},
+		},
+	}}
+	r := newAppSecConfigReconciler(t, stub, config)
+	key := types.NamespacedName{Name: config.Name}
+	reconcile := func() *akamaiV1alpha1.AkamaiAppSecConfig {
+		t.Helper()
+		stub.calls = nil
+		if _, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: key}); err != nil {
+			t.Fatalf("Reconcile() error = %v", err)
+		}
+		var got akamaiV1alpha1.AkamaiAppSecConfig
+		if err := r.Get(ctx, key, &got); err != nil {
+			t.Fatalf("failed to get security configuration: %v", err)
+		}
+		return &got
+	}
+
+	// The rate policy is created in a new version and the security policy denies clients exceeding it
+	got := reconcile()
+	expected := []string{"clone 2", "create rate policy Login in 3", "set rate action shp1_1 500 deny in 3"}
+	if !slices.Equal(stub.calls, expected) {
+		t.Errorf("calls = %q, expected %q", stub.calls, expected)
+	}
+	if got.Status.RatePolicies["Login"] != 500 {
+		t.Errorf("status = %+v, expected the ID of the rate policy", got.Status)
+	}
+	created := stub.version(42, 3).ratePolicies[0]
+	if created["clientIdentifier"] != "ip" || created["requestType"] != "ClientRequest" || created["pathMatchType"] != "Custom" {
+		t.Errorf("rate policy = %v, expected the defaults and the custom paths", created)
+	}
+
+	// Nothing changes while the rate policy matches the spec
+	reconcile()
+	if len(stub.calls) != 0 {
+		t.Errorf("calls = %q, expected no changes", stub.calls)
+	}
+
+	// Thresholds and actions changed outside the operator are restored
+	stub.version(42, 3).ratePolicies[0]["burstThreshold"] = 50
+	stub.version(42, 3).rateActions["shp1_1"][500] = "alert"
+	got = reconcile()
+	expected = []string{"update rate policy 500 in 3", "set rate action shp1_1 500 deny in 3"}
+	if !slices.Equal(stub.calls, expected) {
+		t.Errorf("calls = %q, expected %q", stub.calls, expected)
+	}
+	if !slices.Equal(got.Status.LastChanges, []string{"update rate policy Login", "set action of rate policy Login in policy Shop to deny"}) {
+		t.Errorf("lastChanges = %q, expected the restored rate policy", got.Status.LastChanges)
+	}
+}
+
+func TestSameMatchTargets(t *testing.T) {
+	tests := []struct {
+		name     string
+		current  []akamai.AppSecMatchTarget
+		desired  []akamai.AppSecMatchTarget
+		expected bool
+	}{
+		{
+			name:     "hostnames in another order and case",
+			current:  []akamai.AppSecMatchTarget{{ID: 1, PolicyID: "web1_1", Hostnames: []string{"b.example.com", "A.example.com"}, Paths: []string{"/*"}}},
+			desired:  []akamai.AppSecMatchTarget{{PolicyID: "web1_1", Hostnames: []string{"a.example.com", "b.example.com"}, Paths: []string{"/*"}}},
+			expected: true,
+		},
+		{
+			name: "targets in another order",
+			current: []akamai.AppSecMatchTarget{
+				{ID: 1, PolicyID: "web1_1", Hostnames: []string{"a.example.com"}, Paths: []string{"/*"}},
+				{ID: 2, PolicyID: "api1_2", Hostnames: []string{"a.example.com"}, Paths: []string{"/api/*"}},
+			},
+			desired: []akamai.AppSecMatchTarget{
+				{PolicyID: "api1_2", Hostnames: []string{"a.example.com"}, Paths: []string{"/api/*"}},
+				{PolicyID: "web1_1", Hostnames: []string{"a.example.com"}, Paths: []string{"/*"}},
+			},
+		},
+		{
+			name:    "other policy",
+			current: []akamai.AppSecMatchTarget{{ID: 1, PolicyID: "web1_1", Hostnames: []string{"a.example.com"}, Paths: []string{"/*"}}},
+			desired: []akamai.AppSecMatchTarget{{PolicyID: "api1_2", Hostnames: []string{"a.example.com"}, Paths: []string{"/*"}}},
+		},
+		{
+			name:    "target added",
+			current: []akamai.AppSecMatchTarget{},
+			desired: []akamai.AppSecMatchTarget{{PolicyID: "web1_1", Hostnames: []string{"a.example.com"}, Paths: []string{"/*"}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sameMatchTargets(tt.current, tt.desired); got != tt.expected {
+				t.Errorf("sameMatchTargets() = %v, expected %v", got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package controllers
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/runtime"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+	"github.com/mmz-srf/akamai-operator/pkg/lint"
+)
+
+func TestValidateTypedBehaviors(t *testing.T) {
+	origin := &akamaiV1alpha1.OriginServer{Hostname: "origin.example.com"}
+	tests := []struct {
+		name string
+		spec akamaiV1alpha1.AkamaiPropertySpec
+		err  string
+	}{
+		{name: "no typed behaviors"},
+		{name: "complete", spec: akamaiV1alpha1.AkamaiPropertySpec{
+			Origin:  origin,
+			Caching: &akamaiV1alpha1.CachingSpec{Behavior: "MAX_AGE", TTL: "1d"},
+			CPCode:  &akamaiV1alpha1.CPCodeSpec{ID: 12345},
+		}},
+		{name: "origin with origins", spec: akamaiV1alpha1.AkamaiPropertySpec{Origin: origin, Origins: &akamaiV1alpha1.OriginsSpec{}}, err: "mutually exclusive"},
+		{name: "origin without hostname", spec: akamaiV1alpha1.AkamaiPropertySpec{Origin: &akamaiV1alpha1.OriginServer{}}, err: "no hostname"},
+		{name: "max age without ttl", spec: akamaiV1alpha1.AkamaiPropertySpec{Caching: &akamaiV1alpha1.CachingSpec{Behavior: "MAX_AGE"}}, err: "requires a ttl"},
+		{name: "no store with ttl", spec: akamaiV1alpha1.AkamaiPropertySpec{Caching: &akamaiV1alpha1.CachingSpec{Behavior: "NO_STORE", TTL: "1d"}}, err: "only used with behavior MAX_AGE"},
+		{name: "cp code without id", spec: akamaiV1alpha1.AkamaiPropertySpec{CPCode: &akamaiV1alpha1.CPCodeSpec{}}, err: "must be positive"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateTypedBehaviors(&akamaiV1alpha1.AkamaiProperty{Spec: tt.spec})
+			if tt.err == "" && err != nil {
+				t.Errorf("validateTypedBehaviors() unexpected error: %v", err)
+			}
+			if tt.err != "" && (err == nil || !strings.Contains(err.Error(), tt.err)) {
+				t.Errorf("validateTypedBehaviors() = %v, expected an error containing %q", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestSpecRulesTypedBehaviors(t *testing.T) {
+	behaviorOptions := func(rules *akamaiV1alpha1.PropertyRules) map[string]string {
+		options := make(map[string]string)
+		for _, behavior := range rules.Behaviors {
+			options[behavior.Name] = string(behavior.Options.Raw)
+		}
+		return options
+	}
+	property := &akamaiV1alpha1.AkamaiProperty{Spec: akamaiV1alpha1.AkamaiPropertySpec{
+		Origin:   &akamaiV1alpha1.OriginServer{Hostname: "origin.example.com"},
+		Caching:  &akamaiV1alpha1.CachingSpec{Behavior: "MAX_AGE", TTL: "1d"},
+		CPCode:   &akamaiV1alpha1.CPCodeSpec{ID: 12345},
+		Redirect: &akamaiV1alpha1.RedirectSpec{HTTPToHTTPS: true},
+	}}
+
+	// Typed behaviors alone make up the top-level rule
+	if !managesRules(property) {
+		t.Fatal("expected typed behaviors to manage the rules")
+	}
+	rules, err := specRules(property)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rules.Name != "default" || len(rules.Behaviors) != 3 || len(rules.Children) != 1 {
+		t.Fatalf("unexpected rules %+v", rules)
+	}
+	options := behaviorOptions(rules)
+	if options["caching"] != `{"behavior":"MAX_AGE","mustRevalidate":false,"ttl":"1d"}` || options["cpCode"] != `{"value":{"id":12345}}` ||
+		!strings.Contains(options["origin"], `"hostname":"origin.example.com"`) {
+		t.Errorf("unexpected behavior options %v", options)
+	}
+	var redirect struct {
+		Name      string                        `json:"name"`
+		Behaviors []akamaiV1alpha1.RuleBehavior `json:"behaviors"`
+	}
+	if err := json.Unmarshal(rules.Children[0].Raw, &redirect); err != nil || redirect.Name != redirectRuleName ||
+		!strings.Contains(string(redirect.Behaviors[0].Options.Raw), `"responseCode":301`) {
+		t.Errorf("unexpected redirect rule %s", rules.Children[0].Raw)
+	}
+	if findings := lint.NewLinter(nil).Lint(rules); len(findings) != 0 {
+		t.Errorf("expected the rendered rules to pass linting, got %v", findings)
+	}
+
+	// Typed behaviors replace the behaviors of the same name in spec.rules, which stays unchanged
+	property.Spec.Caching = &akamaiV1alpha1.CachingSpec{Behavior: "NO_STORE"}
+	property.Spec.Rules = &akamaiV1alpha1.PropertyRules{
+		Name: "default",
+		Behaviors: []akamaiV1alpha1.RuleBehavior{
+			{Name: "caching", UUID: "abc", Options: runtime.RawExtension{Raw: []byte(`{"behavior":"MAX_AGE","ttl":"7d"}`)}},
+			{Name: "gzipResponse", Options: runtime.RawExtension{Raw: []byte(`{"behavior":"ALWAYS"}`)}},
+		},
+		Children: []runtime.RawExtension{{Raw: []byte(`{"name":"` + redirectRuleName + `"}`)}, {Raw: []byte(`{"name":"Static"}`)}},
+	}
+	rules, err = specRules(property)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rules.Behaviors[0].Name != "caching" || rules.Behaviors[0].UUID != "abc" || rules.Behaviors[1].Name != "gzipResponse" || len(rules.Behaviors) != 4 {
+		t.Errorf("unexpected behaviors %v", behaviorOptions(rules))
+	}
+	if options := behaviorOptions(rules); options["caching"] != `{"behavior":"NO_STORE","mustRevalidate":false}` {
+		t.Errorf("unexpected caching options %s", options["caching"])
+	}
+	if len(rules.Children) != 2 || !strings.Contains(string(rules.Children[1].Raw), "Static") {
+		t.Errorf("expected the redirect rule to be replaced, got %d children", len(rules.Children))
+	}
+	if string(property.Spec.Rules.Behaviors[0].Options.Raw) != `{"behavior":"MAX_AGE","ttl":"7d"}` {
+		t.Error("expected spec.rules to be left unchanged")
+	}
+}
@@ -0,0 +1,265 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v8/pkg/cps"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+	"github.com/mmz-srf/akamai-operator/pkg/akamai"
+)
+
+// cpsAPI stubs the CPS enrollments of a contract, recording the created and updated enrollments
+type cpsAPI struct {
+	cps.CPS
+	enrollments map[int]*cps.Enrollment
+	change      *cps.Change
+	production  *cps.Deployment
+	created     []cps.EnrollmentRequestBody
+	updated     []cps.EnrollmentRequestBody
+}
+
+func (s *cpsAPI) ListEnrollments(_ context.Context, _ cps.ListEnrollmentsRequest) (*cps.ListEnrollmentsResponse, error) {
+	resp := &cps.ListEnrollmentsResponse{}
+	for id, enrollment := range s.enrollments {
+		listed := *enrollment
+		listed.ID = 0
+		listed.Location = fmt.Sprintf("/cps/v2/enrollments/%d", id)
+		resp.Enrollments = append(resp.Enrollments, listed)
+	}
+	return resp, nil
+}
+
+func (s *cpsAPI) GetEnrollment(_ context.Context, req cps.GetEnrollmentRequest) (*cps.GetEnrollmentResponse, error) {
+	enrollment, ok := s.enrollments[req.EnrollmentID]
+	if !ok {
+		return nil, &cps.Error{StatusCode: http.StatusNotFound}
+	}
+	resp := cps.GetEnrollmentResponse(*enrollment)
+	return &resp, nil
+}
+
+func (s *cpsAPI) CreateEnrollment(_ context.Context, req cps.CreateEnrollmentRequest) (*cps.CreateEnrollmentResponse, error) {
+	s.created = append(s.created, req.EnrollmentRequestBody)
+	s.enrollments[100] = s.enrollment(req.EnrollmentRequestBody, "new-certificate")
+	return &cps.CreateEnrollmentResponse{ID: 100}, nil
+}
+
+func (s *cpsAPI) UpdateEnrollment(_ context.Context, req cps.UpdateEnrollmentRequest) (*cps.UpdateEnrollmentResponse, error) {
+	s.updated = append(s.updated, req.EnrollmentRequestBody)
+	s.enrollments[req.EnrollmentID] = s.enrollment(req.EnrollmentRequestBody, "renew")
+	return &cps.UpdateEnrollmentResponse{ID: req.EnrollmentID}, nil
+}
+
+func (s *cpsAPI) GetChangeStatus(_ context.Context, _ cps.GetChangeStatusRequest) (*cps.Change, error) {
+	return s.change, nil
+}
+
+func (s *cpsAPI) GetStagingDeployment(_ context.Context, _ cps.GetDeploymentRequest) (*cps.GetStagingDeploymentResponse, error) {
+	return nil, &cps.Error{StatusCode: http.StatusNotFound}
+}
+
+func (s *cpsAPI) GetProductionDeployment(_ context.Context, _ cps.GetDeploymentRequest) (*cps.GetProductionDeploymentResponse, error) {
+	if s.production == nil {
+		return nil, &cps.Error{StatusCode: http.StatusNotFound}
+	}
+	resp := cps.GetProductionDeploymentResponse(*s.production)
+	return &resp, nil
+}
+
+// enrollment returns the enrollment of a request body with a pending change of the given type
+func (s *cpsAPI) enrollment(body cps.EnrollmentRequestBody, changeType string) *cps.Enrollment {
+	return &cps.Enrollment{
+		CSR:                  body.CSR,
+		NetworkConfiguration: body.NetworkConfiguration,
+		ChangeManagement:     body.ChangeManagement,
+		PendingChanges:       []cps.PendingChange{{ChangeType: changeType, Location: "/cps/v2/enrollments/100/changes/7"}},
+	}
+}
+
+func TestCertificateReconcile(t *testing.T) {
+	ctx := context.Background()
+	spec := akamaiV1alpha1.AkamaiCertificateSpec{
+		ContractID:     "ctr_1",
+		ValidationType: "dv",
+		CommonName:     "www.example.com",
+		SANs:           []string{"static.example.com", "example.com", "www.example.com"},
+		Organization:   akamaiV1alpha1.CertificateOrganization{Name: "Example AG", Country: "CH"},
+	}
+	sans := []string{"example.com", "static.example.com", "www.example.com"}
+	deployed := func(sans ...string) *cps.Enrollment {
+		return &cps.Enrollment{
+			CSR:                  &cps.CSR{CN: "www.example.com", SANS: sans},
+			NetworkConfiguration: &cps.NetworkConfiguration{Geography: "core", SNIOnly: true},
+		}
+	}
+
+	tests := []struct {
+		name                 string
+		enrollments          map[int]*cps.Enrollment
+		enrollmentID         int
+		change               *cps.Change
+		unreleased           bool
+		expectedCreated      bool
+		expectedUpdatedSANs  []string
+		expectedPhase        string
+		expectedPendingInput []string
+		expectedRequeue      time.Duration
+	}{
+		{
+			name:        "created",
+			enrollments: map[int]*cps.Enrollment{},
+			change: &cps.Change{
+				StatusInfo:   &cps.StatusInfo{Status: "coordinate-domain-validation"},
+				AllowedInput: []cps.AllowedInput{{Type: "lets-encrypt-challenges", RequiredToProceed: true}},
+			},
+			unreleased:           true,
+			expectedCreated:      true,
+			expectedPhase:        PhaseCreating,
+			expectedPendingInput: []string{"lets-encrypt-challenges"},
+			expectedRequeue:      certificateChangePollInterval,
+		},
+		{
+			name:            "adopted",
+			enrollments:     map[int]*cps.Enrollment{42: deployed(sans...)},
+			expectedPhase:   PhaseReady,
+			expectedRequeue: certificateResyncInterval,
+		},
+		{
+			name:                "sans added",
+			enrollments:         map[int]*cps.Enrollment{42: deployed("www.example.com")},
+			enrollmentID:        42,
+			change:              &cps.Change{StatusInfo: &cps.StatusInfo{Status: "wait-review-cert-warning"}},
+			expectedUpdatedSANs: sans,
+			expectedPhase:       PhaseUpdating,
+			expectedRequeue:     certificateChangePollInterval,
+		},
+		{
+			name: "change failed",
+			enrollments: map[int]*cps.Enrollment{42: {
+				CSR:            &cps.CSR{CN: "www.example.com", SANS: sans},
+				PendingChanges: []cps.PendingChange{{ChangeType: "renew", Location: "/cps/v2/enrollments/42/changes/7"}},
+			}},
+			enrollmentID:    42,
+			change:          &cps.Change{StatusInfo: &cps.StatusInfo{Status: "error", Error: &cps.StatusInfoError{Code: "validation", Description: "domain not validated"}}},
+			expectedPhase:   PhaseError,
+			expectedRequeue: certificateErrorRetryInterval,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			certificate := &akamaiV1alpha1.AkamaiCertificate{
+				ObjectMeta: metav1.ObjectMeta{Name: "www-example-com", Generation: 1},
+				Spec:       spec,
+				Status:     akamaiV1alpha1.AkamaiCertificateStatus{EnrollmentID: tt.enrollmentID},
+			}
+			scheme := runtime.NewScheme()
+			if err := akamaiV1alpha1.AddToScheme(scheme); err != nil {
+				t.Fatalf("failed to build scheme: %v", err)
+			}
+			fakeClient := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(certificate).
+				WithStatusSubresource(&akamaiV1alpha1.AkamaiCertificate{}).
+				Build()
+			stub := &cpsAPI{enrollments: tt.enrollments, change: tt.change}
+			if !tt.unreleased {
+				stub.production = &cps.Deployment{PrimaryCertificate: cps.DeploymentCertificate{Expiry: "2027-01-01T00:00:00Z"}}
+			}
+			r := &AkamaiCertificateReconciler{Client: fakeClient, Scheme: scheme, AkamaiClient: akamai.NewClientWithCPS(stub)}
+			key := types.NamespacedName{Name: certificate.Name}
+
+			result, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+			if err != nil {
+				t.Fatalf("Reconcile() error = %v", err)
+			}
+			var got akamaiV1alpha1.AkamaiCertificate
+			if err := r.Get(ctx, key, &got); err != nil {
+				t.Fatalf("failed to get certificate: %v", err)
+			}
+
+			if created := len(stub.created) > 0; created != tt.expectedCreated {
+				t.Errorf("created = %+v, expected created %v", stub.created, tt.expectedCreated)
+			} else if created {
+				body := stub.created[0]
+				if body.RA != "lets-encrypt" || body.CertificateType != "san" || !slices.Equal(body.CSR.SANS, sans) || body.CSR.O != "Example AG" {
+					t.Errorf("created enrollment = %+v, csr = %+v", body, body.CSR)
+				}
+			}
+			var updatedSANs []string
+			if len(stub.updated) > 0 {
+				updatedSANs = stub.updated[0].CSR.SANS
+			}
+			if !slices.Equal(updatedSANs, tt.expectedUpdatedSANs) {
+				t.Errorf("updated SANs = %v, expected %v", updatedSANs, tt.expectedUpdatedSANs)
+			}
+			if got.Status.Phase != tt.expectedPhase || result.RequeueAfter != tt.expectedRequeue {
+				t.Errorf("status = %+v, requeue = %v, expected phase %s and requeue %v", got.Status, result.RequeueAfter, tt.expectedPhase, tt.expectedRequeue)
+			}
+			if got.Status.EnrollmentID == 0 {
+				t.Errorf("status.enrollmentId is not set")
+			}
+			var pendingInput []string
+			if got.Status.PendingChange != nil {
+				pendingInput = got.Status.PendingChange.RequiredInput
+			}
+			if !slices.Equal(pendingInput, tt.expectedPendingInput) {
+				t.Errorf("pending change = %+v, expected required input %v", got.Status.PendingChange, tt.expectedPendingInput)
+			}
+			if tt.expectedPhase == PhaseReady && (got.Status.Production == nil || got.Status.Production.Expiry == "") {
+				t.Errorf("status.production = %+v, expected the deployed certificate", got.Status.Production)
+			}
+		})
+	}
+}
+
+func TestCertificateEnrollmentID(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := akamaiV1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to build scheme: %v", err)
+	}
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(
+			&akamaiV1alpha1.AkamaiCertificate{ObjectMeta: metav1.ObjectMeta{Name: "ready"}, Status: akamaiV1alpha1.AkamaiCertificateStatus{EnrollmentID: 42}},
+			&akamaiV1alpha1.AkamaiCertificate{ObjectMeta: metav1.ObjectMeta{Name: "pending"}},
+		).
+		Build()
+
+	tests := []struct {
+		name          string
+		expected      int
+		expectedError string
+	}{
+		{name: "ready", expected: 42},
+		{name: "pending", expectedError: "has not been created in Akamai yet"},
+		{name: "missing", expectedError: "not found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := certificateEnrollmentID(context.Background(), fakeClient, tt.name)
+			if tt.expectedError != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.expectedError) {
+					t.Errorf("certificateEnrollmentID() error = %v, expected %q", err, tt.expectedError)
+				}
+				return
+			}
+			if err != nil || got != tt.expected {
+				t.Errorf("certificateEnrollmentID() = %d, %v, expected %d", got, err, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,132 @@
+package controllers
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+	"github.com/mmz-srf/akamai-operator/pkg/akamai"
+)
+
+func TestResumedVersion(t *testing.T) {
+	startedAt := metav1.NewTime(time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC))
+	interrupted := &akamaiV1alpha1.WriteCheckpoint{Step: CheckpointCreateVersion, Version: 6, Generation: 3, StartedAt: startedAt}
+	created := &akamai.PropertyVersion{PropertyVersion: 7, UpdatedDate: "2026-03-01T12:00:02Z"}
+
+	tests := []struct {
+		name          string
+		latestVersion int
+		latest        *akamai.PropertyVersion
+		checkpoint    *akamaiV1alpha1.WriteCheckpoint
+		expected      int
+	}{
+		{name: "no checkpoint", latestVersion: 7},
+		{
+			name:          "version recorded as created",
+			latestVersion: 7,
+			checkpoint:    &akamaiV1alpha1.WriteCheckpoint{Version: 7, Generation: 3, Completed: []string{CheckpointCreateVersion}},
+			expected:      7,
+		},
+		{
+			name:          "creation interrupted after Akamai created the version",
+			latestVersion: 7,
+			latest:        created,
+			checkpoint:    interrupted,
+			expected:      7,
+		},
+		{
+			name:          "creation interrupted before Akamai created the version",
+			latestVersion: 6,
+			latest:        &akamai.PropertyVersion{PropertyVersion: 6, UpdatedDate: "2026-03-01T12:00:02Z"},
+			checkpoint:    interrupted,
+		},
+		{
+			name:          "newer version created before the interrupted creation",
+			latestVersion: 7,
+			latest:        &akamai.PropertyVersion{PropertyVersion: 7, UpdatedDate: "2026-03-01T11:00:00Z"},
+			checkpoint:    interrupted,
+		},
+		{
+			name:          "older version carrying the managed-by marker",
+			latestVersion: 7,
+			latest:        &akamai.PropertyVersion{PropertyVersion: 7, Note: versionNotesManagedByMarker, UpdatedDate: "2026-03-01T11:00:00Z"},
+			checkpoint:    interrupted,
+			expected:      7,
+		},
+		{
+			name:          "version created in the console after the interrupted creation",
+			latestVersion: 8,
+			latest:        &akamai.PropertyVersion{PropertyVersion: 8, Note: versionNotesManagedByMarker, UpdatedDate: "2026-03-01T12:00:05Z"},
+			checkpoint:    interrupted,
+		},
+		{
+			name:          "checkpoint of an older generation",
+			latestVersion: 7,
+			checkpoint:    &akamaiV1alpha1.WriteCheckpoint{Version: 7, Generation: 2, Completed: []string{CheckpointCreateVersion}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			property := &akamaiV1alpha1.AkamaiProperty{
+				ObjectMeta: metav1.ObjectMeta{Generation: 3},
+				Status:     akamaiV1alpha1.AkamaiPropertyStatus{LatestVersion: tt.latestVersion, Checkpoint: tt.checkpoint},
+			}
+			version, ok := resumedVersion(property, tt.latest)
+			if version != tt.expected || ok != (tt.expected != 0) {
+				t.Errorf("resumedVersion() = (%d, %v), expected %d", version, ok, tt.expected)
+			}
+		})
+	}
+}
+
+func TestCheckpointSteps(t *testing.T) {
+	ctx := context.Background()
+	property := &akamaiV1alpha1.AkamaiProperty{
+		ObjectMeta: metav1.ObjectMeta{Name: "example", Generation: 3},
+		Status:     akamaiV1alpha1.AkamaiPropertyStatus{LatestVersion: 6},
+	}
+	r := newFakeReconciler(t, property)
+
+	if err := r.setCheckpoint(ctx, property, CheckpointCreateVersion, 6); err != nil {
+		t.Fatalf("setCheckpoint() unexpected error: %v", err)
+	}
+	completeStep(property, CheckpointCreateVersion, 7)
+	if err := r.setCheckpoint(ctx, property, CheckpointUpdateProperty, 7); err != nil {
+		t.Fatalf("setCheckpoint() unexpected error: %v", err)
+	}
+	completeStep(property, CheckpointUpdateProperty, 7)
+	if err := r.setCheckpoint(ctx, property, CheckpointUpdateRules, 7); err != nil {
+		t.Fatalf("setCheckpoint() unexpected error: %v", err)
+	}
+
+	var stored akamaiV1alpha1.AkamaiProperty
+	if err := r.Get(ctx, types.NamespacedName{Name: "example"}, &stored); err != nil {
+		t.Fatalf("failed to get property: %v", err)
+	}
+	checkpoint := stored.Status.Checkpoint
+	if checkpoint == nil || checkpoint.Step != CheckpointUpdateRules || checkpoint.Version != 7 || checkpoint.Generation != 3 {
+		t.Fatalf("unexpected checkpoint %+v", checkpoint)
+	}
+	if expected := []string{CheckpointCreateVersion, CheckpointUpdateProperty}; !reflect.DeepEqual(checkpoint.Completed, expected) {
+		t.Errorf("completed = %v, expected %v", checkpoint.Completed, expected)
+	}
+	if !resumesStep(ctx, &stored, CheckpointUpdateRules) || resumesStep(ctx, &stored, CheckpointUpdateProperty) {
+		t.Error("expected only the rules step to be resumed")
+	}
+
+	if err := r.clearCheckpoint(ctx, property); err != nil {
+		t.Fatalf("clearCheckpoint() unexpected error: %v", err)
+	}
+	if err := r.Get(ctx, types.NamespacedName{Name: "example"}, &stored); err != nil {
+		t.Fatalf("failed to get property: %v", err)
+	}
+	if stored.Status.Checkpoint != nil {
+		t.Errorf("checkpoint was not cleared: %+v", stored.Status.Checkpoint)
+	}
+}
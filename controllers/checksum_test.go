@@ -0,0 +1,74 @@
+package controllers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+)
+
+func TestSpecChecksum(t *testing.T) {
+	spec := &akamaiV1alpha1.AkamaiPropertySpec{
+		PropertyName: "www.example.com",
+		ContractID:   "ctr_1",
+		GroupID:      "grp_1",
+		ProductID:    "prd_Fresca",
+	}
+
+	// The output of `jq -cS .spec` for the same spec
+	canonical := `{"contractId":"ctr_1","groupId":"grp_1","productId":"prd_Fresca","propertyName":"www.example.com"}` + "\n"
+	sum := sha256.Sum256([]byte(canonical))
+	expected := "sha256:" + hex.EncodeToString(sum[:])
+
+	got, err := specChecksum(spec)
+	if err != nil {
+		t.Fatalf("specChecksum() error = %v", err)
+	}
+	if got != expected {
+		t.Errorf("specChecksum() = %s, expected %s", got, expected)
+	}
+
+	changed := spec.DeepCopy()
+	changed.GroupID = "grp_2"
+	if other, _ := specChecksum(changed); other == got {
+		t.Errorf("specChecksum() did not change with the spec")
+	}
+}
+
+func TestRecordAppliedChecksum(t *testing.T) {
+	ctx := context.Background()
+	stored := &akamaiV1alpha1.AkamaiProperty{
+		ObjectMeta: metav1.ObjectMeta{Name: "example"},
+		Spec:       akamaiV1alpha1.AkamaiPropertySpec{PropertyName: "www.example.com", ProductID: "prd_Fresca", ProviderConfigRef: "tenant-a"},
+	}
+	r := newFakeReconciler(t, stored)
+
+	var property akamaiV1alpha1.AkamaiProperty
+	if err := r.Get(ctx, types.NamespacedName{Name: "example"}, &property); err != nil {
+		t.Fatalf("failed to get property: %v", err)
+	}
+	// Account defaults applied in memory must not be persisted
+	property.Spec.ContractID = "ctr_default"
+	property.Spec.GroupID = "grp_default"
+	expected, _ := specChecksum(&property.Spec)
+
+	if err := r.recordAppliedChecksum(ctx, &property); err != nil {
+		t.Fatalf("recordAppliedChecksum() error = %v", err)
+	}
+
+	var updated akamaiV1alpha1.AkamaiProperty
+	if err := r.Get(ctx, types.NamespacedName{Name: "example"}, &updated); err != nil {
+		t.Fatalf("failed to get property: %v", err)
+	}
+	if got := updated.Annotations[AnnotationAppliedChecksum]; got != expected {
+		t.Errorf("annotation = %q, expected %q", got, expected)
+	}
+	if updated.Spec.ContractID != "" || updated.Spec.GroupID != "" {
+		t.Errorf("account defaults were persisted: %s/%s", updated.Spec.ContractID, updated.Spec.GroupID)
+	}
+}
@@ -0,0 +1,82 @@
+package controllers
+
+import (
+	"strings"
+	"testing"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+)
+
+func TestValidateChinaCDN(t *testing.T) {
+	chinaEdgeHostname := &akamaiV1alpha1.EdgeHostnameSpec{DomainPrefix: "www.example.cn", DomainSuffix: "edgekey.net", ChinaCDN: true}
+
+	tests := []struct {
+		name          string
+		template      string
+		edgeHostname  *akamaiV1alpha1.EdgeHostnameSpec
+		hostnames     []akamaiV1alpha1.Hostname
+		expectedError string
+	}{
+		{
+			name:      "no China CDN",
+			hostnames: []akamaiV1alpha1.Hostname{{CNAMEFrom: "www.example.com", CNAMETo: "www.example.com.edgesuite.net"}},
+		},
+		{
+			name:         "licensed hostnames",
+			edgeHostname: chinaEdgeHostname,
+			hostnames: []akamaiV1alpha1.Hostname{
+				{CNAMEFrom: "www.example.cn", CNAMETo: "www.example.cn.edgekey.net", ICPLicense: "京ICP备12345678号-1"},
+				{CNAMEFrom: "static.example.cn", CNAMETo: "www.example.cn.edgekey.net", ICPLicense: "沪ICP证123456号"},
+				{CNAMEFrom: "www.example.com", CNAMETo: "www.example.com.edgesuite.net"},
+			},
+		},
+		{
+			name:         "unlicensed hostname",
+			edgeHostname: chinaEdgeHostname,
+			hostnames: []akamaiV1alpha1.Hostname{
+				{CNAMEFrom: "www.example.cn", CNAMETo: "www.example.cn.edgekey.net", ICPLicense: "京ICP备12345678号-1"},
+				{CNAMEFrom: "static.example.cn", CNAMETo: "www.example.cn.edgekey.net"},
+			},
+			expectedError: "need an icpLicense: static.example.cn",
+		},
+		{
+			name:          "unlicensed hostname of a templated edge hostname",
+			template:      "{property}",
+			edgeHostname:  &akamaiV1alpha1.EdgeHostnameSpec{DomainSuffix: "edgekey.net", ChinaCDN: true},
+			hostnames:     []akamaiV1alpha1.Hostname{{CNAMEFrom: "www.example.cn", CNAMETo: "example.edgekey.net"}},
+			expectedError: "need an icpLicense: www.example.cn",
+		},
+		{
+			name:          "invalid license",
+			hostnames:     []akamaiV1alpha1.Hostname{{CNAMEFrom: "www.example.cn", CNAMETo: "www.example.cn.edgekey.net", ICPLicense: "ICP-12345"}},
+			expectedError: `invalid icpLicense "ICP-12345"`,
+		},
+		{
+			name: "duplicate use case",
+			edgeHostname: &akamaiV1alpha1.EdgeHostnameSpec{DomainPrefix: "www.example.com", DomainSuffix: "edgesuite.net", UseCases: []akamaiV1alpha1.EdgeHostnameUseCase{
+				{UseCase: "Download_Mode", Option: "BACKGROUND"},
+				{UseCase: "Download_Mode", Option: "FOREGROUND"},
+			}},
+			expectedError: `duplicate use case "Download_Mode"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &AkamaiPropertyReconciler{EdgeHostnameTemplate: tt.template}
+			property := &akamaiV1alpha1.AkamaiProperty{
+				Spec: akamaiV1alpha1.AkamaiPropertySpec{PropertyName: "example", EdgeHostname: tt.edgeHostname, Hostnames: tt.hostnames},
+			}
+			err := r.validateChinaCDN(property)
+			if tt.expectedError == "" {
+				if err != nil {
+					t.Errorf("validateChinaCDN() unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.expectedError) {
+				t.Errorf("validateChinaCDN() = %v, expected an error containing %q", err, tt.expectedError)
+			}
+		})
+	}
+}
@@ -0,0 +1,244 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v8/pkg/clientlists"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+	"github.com/mmz-srf/akamai-operator/pkg/akamai"
+)
+
+// clientListsAPI stubs the client lists of an account, recording the calls. Each change creates a
+// new version; activations stay pending.
+type clientListsAPI struct {
+	clientlists.ClientLists
+	lists       map[string]*clientlists.GetClientListResponse
+	activations map[clientlists.ActivationNetwork]*clientlists.GetActivationStatusResponse
+	calls       []string
+}
+
+func (s *clientListsAPI) GetClientLists(_ context.Context, req clientlists.GetClientListsRequest) (*clientlists.GetClientListsResponse, error) {
+	resp := &clientlists.GetClientListsResponse{}
+	for _, list := range s.lists {
+		if strings.Contains(list.Name, req.Name) {
+			resp.Content = append(resp.Content, clientlists.ClientList{ListContent: list.ListContent})
+		}
+	}
+	return resp, nil
+}
+
+func (s *clientListsAPI) GetClientList(_ context.Context, req clientlists.GetClientListRequest) (*clientlists.GetClientListResponse, error) {
+	list, ok := s.lists[req.ListID]
+	if !ok {
+		return nil, &clientlists.Error{StatusCode: http.StatusNotFound}
+	}
+	return list, nil
+}
+
+func (s *clientListsAPI) CreateClientList(_ context.Context, req clientlists.CreateClientListRequest) (*clientlists.CreateClientListResponse, error) {
+	s.calls = append(s.calls, fmt.Sprintf("create %s %s %s", req.Name, req.ContractID, clientListItemValues(req.Items)))
+	list := &clientlists.GetClientListResponse{ListContent: clientlists.ListContent{ListID: "7_BLOCKED", Name: req.Name, Type: req.Type, Notes: req.Notes, Tags: req.Tags, Version: 1}}
+	for _, item := range req.Items {
+		list.Items = append(list.Items, clientlists.ListItemContent{Value: item.Value, Tags: item.Tags, Description: item.Description, ExpirationDate: item.ExpirationDate})
+	}
+	list.ItemsCount = int64(len(list.Items))
+	s.lists[list.ListID] = list
+	resp := clientlists.CreateClientListResponse(*list)
+	return &resp, nil
+}
+
+func (s *clientListsAPI) UpdateClientList(_ context.Context, req clientlists.UpdateClientListRequest) (*clientlists.UpdateClientListResponse, error) {
+	s.calls = append(s.calls, fmt.Sprintf("update %s %s %v", req.ListID, req.Notes, req.Tags))
+	list := s.lists[req.ListID]
+	list.Name, list.Notes, list.Tags = req.Name, req.Notes, req.Tags
+	list.Version++
+	return &clientlists.UpdateClientListResponse{ListContent: list.ListContent}, nil
+}
+
+func (s *clientListsAPI) UpdateClientListItems(_ context.Context, req clientlists.UpdateClientListItemsRequest) (*clientlists.UpdateClientListItemsResponse, error) {
+	s.calls = append(s.calls, fmt.Sprintf("items %s append %s update %s delete %s", req.ListID,
+		clientListItemValues(req.Append), clientListItemValues(req.Update), clientListItemValues(req.Delete)))
+	s.lists[req.ListID].Version++
+	return &clientlists.UpdateClientListItemsResponse{}, nil
+}
+
+func (s *clientListsAPI) GetActivationStatus(_ context.Context, req clientlists.GetActivationStatusRequest) (*clientlists.GetActivationStatusResponse, error) {
+	activation, ok := s.activations[req.Network]
+	if !ok {
+		return nil, &clientlists.Error{StatusCode: http.StatusNotFound}
+	}
+	return activation, nil
+}
+
+func (s *clientListsAPI) CreateActivation(_ context.Context, req clientlists.CreateActivationRequest) (*clientlists.CreateActivationResponse, error) {
+	s.calls = append(s.calls, fmt.Sprintf("activate %s %s", req.ListID, req.Network))
+	return &clientlists.CreateActivationResponse{ActivationID: 99, ActivationStatus: clientlists.PendingActivation, Version: s.lists[req.ListID].Version}, nil
+}
+
+func clientListItemValues(items []clientlists.ListItemPayload) string {
+	values := make([]string, 0, len(items))
+	for _, item := range items {
+		values = append(values, item.Value)
+	}
+	return "[" + strings.Join(values, " ") + "]"
+}
+
+func TestClientListReconcile(t *testing.T) {
+	ctx := context.Background()
+	spec := akamaiV1alpha1.AkamaiClientListSpec{
+		Name:       "Blocked clients",
+		Type:       "IP",
+		Notes:      "blocked",
+		ContractID: "ctr_C-1",
+		GroupID:    "grp_2",
+		Items: []akamaiV1alpha1.ClientListItem{
+			{Value: "198.51.100.17", Description: "stuffing", ExpirationDate: "2099-01-01T00:00:00Z"},
+			{Value: "192.0.2.0/24", Description: "scraper", Tags: []string{"b", "a"}},
+			{Value: "203.0.113.1", ExpirationDate: "2020-01-01T00:00:00Z"},
+			{Value: "2001:DB8::/32"},
+		},
+	}
+	// existing returns the list in Akamai with the items of the spec except the expired one
+	existing := func() *clientlists.GetClientListResponse {
+		return &clientlists.GetClientListResponse{
+			ListContent: clientlists.ListContent{ListID: "7_BLOCKED", Name: "Blocked clients", Type: clientlists.IP, Notes: "blocked", Version: 3, ItemsCount: 3},
+			Items: []clientlists.ListItemContent{
+				{Value: "192.0.2.0/24", Description: "scraper", Tags: []string{"a", "b"}},
+				{Value: "198.51.100.17", Description: "stuffing", ExpirationDate: "2099-01-01T00:00:00.000+00:00"},
+				{Value: "2001:db8::/32"},
+			},
+		}
+	}
+	changed := existing()
+	changed.Items = []clientlists.ListItemContent{
+		{Value: "192.0.2.0/24", Description: "old"},
+		{Value: "203.0.113.1"},
+		{Value: "233.252.0.1"},
+	}
+	active := func(version int64) map[clientlists.ActivationNetwork]*clientlists.GetActivationStatusResponse {
+		return map[clientlists.ActivationNetwork]*clientlists.GetActivationStatusResponse{
+			clientlists.Staging: {ActivationID: 5, ActivationStatus: clientlists.Active, Version: version},
+		}
+	}
+
+	tests := []struct {
+		name            string
+		lists           map[string]*clientlists.GetClientListResponse
+		activations     map[clientlists.ActivationNetwork]*clientlists.GetActivationStatusResponse
+		notes           string
+		expectedCalls   []string
+		expectedVersion int64
+		expectedPhase   string
+		expectedRequeue time.Duration
+	}{
+		{
+			name:  "created",
+			lists: map[string]*clientlists.GetClientListResponse{},
+			expectedCalls: []string{
+				"create Blocked clients C-1 [192.0.2.0/24 198.51.100.17 2001:db8::/32]",
+				"activate 7_BLOCKED STAGING",
+			},
+			expectedVersion: 1,
+			expectedPhase:   PhaseActivating,
+			expectedRequeue: clientListActivationPollInterval,
+		},
+		{
+			name:            "unchanged",
+			lists:           map[string]*clientlists.GetClientListResponse{"7_BLOCKED": existing()},
+			activations:     active(3),
+			expectedVersion: 3,
+			expectedPhase:   PhaseReady,
+			expectedRequeue: clientListResyncInterval,
+		},
+		{
+			name:        "items changed",
+			lists:       map[string]*clientlists.GetClientListResponse{"7_BLOCKED": changed},
+			activations: active(3),
+			expectedCalls: []string{
+				"items 7_BLOCKED append [198.51.100.17 2001:db8::/32] update [192.0.2.0/24] delete [203.0.113.1 233.252.0.1]",
+				"activate 7_BLOCKED STAGING",
+			},
+			expectedVersion: 4,
+			expectedPhase:   PhaseActivating,
+			expectedRequeue: clientListActivationPollInterval,
+		},
+		{
+			name:        "notes changed",
+			lists:       map[string]*clientlists.GetClientListResponse{"7_BLOCKED": existing()},
+			activations: active(3),
+			notes:       "blocked by the WAF team",
+			expectedCalls: []string{
+				"update 7_BLOCKED blocked by the WAF team []",
+				"activate 7_BLOCKED STAGING",
+			},
+			expectedVersion: 4,
+			expectedPhase:   PhaseActivating,
+			expectedRequeue: clientListActivationPollInterval,
+		},
+		{
+			name:  "activation failed",
+			lists: map[string]*clientlists.GetClientListResponse{"7_BLOCKED": existing()},
+			activations: map[clientlists.ActivationNetwork]*clientlists.GetActivationStatusResponse{
+				clientlists.Staging: {ActivationID: 5, ActivationStatus: clientlists.Failed, Version: 3},
+			},
+			expectedVersion: 3,
+			expectedPhase:   PhaseError,
+			expectedRequeue: clientListErrorRetryInterval,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			listSpec := spec
+			listSpec.Activation = &akamaiV1alpha1.ClientListActivationSpec{Staging: true}
+			if tt.notes != "" {
+				listSpec.Notes = tt.notes
+			}
+			list := &akamaiV1alpha1.AkamaiClientList{
+				ObjectMeta: metav1.ObjectMeta{Name: "blocked-clients", Generation: 1},
+				Spec:       listSpec,
+			}
+			scheme := runtime.NewScheme()
+			if err := akamaiV1alpha1.AddToScheme(scheme); err != nil {
+				t.Fatalf("failed to build scheme: %v", err)
+			}
+			fakeClient := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(list).
+				WithStatusSubresource(&akamaiV1alpha1.AkamaiClientList{}).
+				Build()
+			stub := &clientListsAPI{lists: tt.lists, activations: tt.activations}
+			r := &AkamaiClientListReconciler{Client: fakeClient, Scheme: scheme, AkamaiClient: akamai.NewClientWithClientLists(stub)}
+			key := types.NamespacedName{Name: list.Name}
+
+			result, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+			if err != nil {
+				t.Fatalf("Reconcile() error = %v", err)
+			}
+			var got akamaiV1alpha1.AkamaiClientList
+			if err := r.Get(ctx, key, &got); err != nil {
+				t.Fatalf("failed to get client list: %v", err)
+			}
+			if strings.Join(stub.calls, "\n") != strings.Join(tt.expectedCalls, "\n") {
+				t.Errorf("calls = %q, expected %q", stub.calls, tt.expectedCalls)
+			}
+			if got.Status.Phase != tt.expectedPhase || result.RequeueAfter != tt.expectedRequeue {
+				t.Errorf("status = %+v, requeue = %v, expected phase %s and requeue %v", got.Status, result.RequeueAfter, tt.expectedPhase, tt.expectedRequeue)
+			}
+			if got.Status.ListID != "7_BLOCKED" || got.Status.Version != tt.expectedVersion || got.Status.Staging == nil {
+				t.Errorf("status = %+v, expected list 7_BLOCKED version %d with its staging activation", got.Status, tt.expectedVersion)
+			}
+		})
+	}
+}
@@ -0,0 +1,299 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+
+	cloudlets "github.com/akamai/AkamaiOPEN-edgegrid-golang/v8/pkg/cloudlets/v3"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+	"github.com/mmz-srf/akamai-operator/pkg/akamai"
+)
+
+// cloudletsAPI stubs the Cloudlets shared policy endpoints, recording the calls that change
+// policies. Activations stay IN_PROGRESS until the test completes them.
+type cloudletsAPI struct {
+	cloudlets.Cloudlets
+	policies map[int64]*cloudlets.Policy
+	versions map[int64][]cloudlets.PolicyVersion
+	calls    []string
+}
+
+func (s *cloudletsAPI) GetPolicy(_ context.Context, req cloudlets.GetPolicyRequest) (*cloudlets.Policy, error) {
+	policy, ok := s.policies[req.PolicyID]
+	if !ok {
+		return nil, &cloudlets.Error{Status: http.StatusNotFound, Title: "Not Found"}
+	}
+	result := *policy
+	return &result, nil
+}
+
+func (s *cloudletsAPI) ListPolicies(_ context.Context, _ cloudlets.ListPoliciesRequest) (*cloudlets.ListPoliciesResponse, error) {
+	resp := &cloudlets.ListPoliciesResponse{Page: cloudlets.Page{TotalPages: 1}}
+	for _, policy := range s.policies {
+		resp.Content = append(resp.Content, *policy)
+	}
+	return resp, nil
+}
+
+func (s *cloudletsAPI) CreatePolicy(_ context.Context, req cloudlets.CreatePolicyRequest) (*cloudlets.Policy, error) {
+	s.calls = append(s.calls, fmt.Sprintf("create %s type=%s group=%d", req.Name, req.CloudletType, req.GroupID))
+	policy := &cloudlets.Policy{ID: int64(100 + len(s.policies)), Name: req.Name, CloudletType: req.CloudletType, GroupID: req.GroupID, Description: req.Description, PolicyType: req.PolicyType}
+	s.policies[policy.ID] = policy
+	return policy, nil
+}
+
+func (s *cloudletsAPI) UpdatePolicy(_ context.Context, req cloudlets.UpdatePolicyRequest) (*cloudlets.Policy, error) {
+	s.calls = append(s.calls, fmt.Sprintf("update %d group=%d", req.PolicyID, req.BodyParams.GroupID))
+	policy := s.policies[req.PolicyID]
+	policy.GroupID, policy.Description = req.BodyParams.GroupID, req.BodyParams.Description
+	return policy, nil
+}
+
+func (s *cloudletsAPI) DeletePolicy(_ context.Context, req cloudlets.DeletePolicyRequest) error {
+	s.calls = append(s.calls, fmt.Sprintf("delete %d", req.PolicyID))
+	delete(s.policies, req.PolicyID)
+	return nil
+}
+
+func (s *cloudletsAPI) ListPolicyVersions(_ context.Context, req cloudlets.ListPolicyVersionsRequest) (*cloudlets.ListPolicyVersions, error) {
+	resp := &cloudlets.ListPolicyVersions{}
+	for _, version := range s.versions[req.PolicyID] {
+		resp.PolicyVersions = append(resp.PolicyVersions, cloudlets.ListPolicyVersionsItem{PolicyVersion: version.PolicyVersion, Immutable: version.Immutable})
+	}
+	return resp, nil
+}
+
+func (s *cloudletsAPI) GetPolicyVersion(_ context.Context, req cloudlets.GetPolicyVersionRequest) (*cloudlets.PolicyVersion, error) {
+	version := s.versions[req.PolicyID][req.PolicyVersion-1]
+	return &version, nil
+}
+
+func (s *cloudletsAPI) CreatePolicyVersion(_ context.Context, req cloudlets.CreatePolicyVersionRequest) (*cloudlets.PolicyVersion, error) {
+	number := int64(len(s.versions[req.PolicyID]) + 1)
+	s.calls = append(s.calls, fmt.Sprintf("create version %d/%d", req.PolicyID, number))
+	version := cloudlets.PolicyVersion{PolicyID: req.PolicyID, PolicyVersion: number, MatchRules: req.MatchRules}
+	s.versions[req.PolicyID] = append(s.versions[req.PolicyID], version)
+	return &version, nil
+}
+
+func (s *cloudletsAPI) UpdatePolicyVersion(_ context.Context, req cloudlets.UpdatePolicyVersionRequest) (*cloudlets.PolicyVersion, error) {
+	s.calls = append(s.calls, fmt.Sprintf("update version %d/%d", req.PolicyID, req.PolicyVersion))
+	version := &s.versions[req.PolicyID][req.PolicyVersion-1]
+	version.MatchRules = req.MatchRules
+	return version, nil
+}
+
+func (s *cloudletsAPI) ActivatePolicy(_ context.Context, req cloudlets.ActivatePolicyRequest) (*cloudlets.PolicyActivation, error) {
+	s.calls = append(s.calls, fmt.Sprintf("activate %d/%d %s", req.PolicyID, req.PolicyVersion, req.Network))
+	s.versions[req.PolicyID][req.PolicyVersion-1].Immutable = true
+	return s.setActivation(req.PolicyID, req.PolicyVersion, req.Network, cloudlets.OperationActivation), nil
+}
+
+func (s *cloudletsAPI) DeactivatePolicy(_ context.Context, req cloudlets.DeactivatePolicyRequest) (*cloudlets.PolicyActivation, error) {
+	s.calls = append(s.calls, fmt.Sprintf("deactivate %d/%d %s", req.PolicyID, req.PolicyVersion, req.Network))
+	return s.setActivation(req.PolicyID, req.PolicyVersion, req.Network, cloudlets.OperationDeactivation), nil
+}
+
+func (s *cloudletsAPI) setActivation(policyID, version int64, network cloudlets.Network, operation cloudlets.PolicyActivationOperation) *cloudlets.PolicyActivation {
+	activation := &cloudlets.PolicyActivation{ID: int64(len(s.calls)), PolicyID: policyID, PolicyVersion: version, Network: network, Operation: operation, Status: cloudlets.ActivationStatusInProgress}
+	info := &s.policies[policyID].CurrentActivations.Staging
+	if network == cloudlets.ProductionNetwork {
+		info = &s.policies[policyID].CurrentActivations.Production
+	}
+	info.Latest = activation
+	return activation
+}
+
+// completeActivations finishes the running activations of a policy
+func (s *cloudletsAPI) completeActivations(policyID int64) {
+	for _, info := range []*cloudlets.ActivationInfo{&s.policies[policyID].CurrentActivations.Staging, &s.policies[policyID].CurrentActivations.Production} {
+		if info.Latest == nil || info.Latest.Status != cloudlets.ActivationStatusInProgress {
+			continue
+		}
+		info.Latest.Status = cloudlets.ActivationStatusSuccess
+		info.Effective = info.Latest
+		if info.Latest.Operation == cloudlets.OperationDeactivation {
+			info.Effective = nil
+		}
+	}
+}
+
+func newCloudletPolicyReconciler(t *testing.T, stub *cloudletsAPI, objects ...client.Object) *AkamaiCloudletPolicyReconciler {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	if err := akamaiV1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to build scheme: %v", err)
+	}
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(objects...).
+		WithStatusSubresource(&akamaiV1alpha1.AkamaiCloudletPolicy{}).
+		Build()
+	return &AkamaiCloudletPolicyReconciler{Client: fakeClient, Scheme: scheme, AkamaiClient: akamai.NewClientWithCloudlets(stub)}
+}
+
+func TestCloudletPolicyReconcile(t *testing.T) {
+	ctx := context.Background()
+	policy := &akamaiV1alpha1.AkamaiCloudletPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "www-redirects", Generation: 1},
+		Spec: akamaiV1alpha1.AkamaiCloudletPolicySpec{
+			CloudletType:   "ER",
+			GroupID:        "grp_42",
+			MatchRules:     []runtime.RawExtension{{Raw: []byte(`{"name":"shop","matchURL":"/shop/*","redirectURL":"/store/","statusCode":301}`)}},
+			Activation:     &akamaiV1alpha1.CloudletPolicyActivationSpec{Staging: true},
+			DeletionPolicy: akamaiV1alpha1.DeletionPolicyDelete,
+		},
+	}
+	stub := &cloudletsAPI{policies: map[int64]*cloudlets.Policy{}, versions: map[int64][]cloudlets.PolicyVersion{}}
+	r := newCloudletPolicyReconciler(t, stub, policy)
+	key := types.NamespacedName{Name: policy.Name}
+	reconcile := func() (*akamaiV1alpha1.AkamaiCloudletPolicy, ctrl.Result) {
+		t.Helper()
+		stub.calls = nil
+		result, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+		if err != nil {
+			t.Fatalf("Reconcile() error = %v", err)
+		}
+		var got akamaiV1alpha1.AkamaiCloudletPolicy
+		if err := r.Get(ctx, key, &got); err != nil {
+			t.Fatalf("failed to get cloudlet policy: %v", err)
+		}
+		return &got, result
+	}
+
+	// The policy is created with a first version, which is activated on staging
+	got, result := reconcile()
+	expected := []string{"create www_redirects type=ER group=42", "create version 100/1", "activate 100/1 STAGING"}
+	if !slices.Equal(stub.calls, expected) {
+		t.Errorf("calls = %q, expected %q", stub.calls, expected)
+	}
+	if got.Status.PolicyID != 100 || got.Status.Version != 1 || got.Status.Phase != PhaseActivating ||
+		got.Status.Staging == nil || got.Status.Production != nil || result.RequeueAfter != cloudletPolicyActivationPollInterval {
+		t.Errorf("status = %+v, expected the activation to run", got.Status)
+	}
+	if rules := stub.versions[100][0].MatchRules; len(rules) != 1 || rules[0].(*cloudlets.MatchRuleER).RedirectURL != "/store/" {
+		t.Errorf("match rules = %+v, expected the Edge Redirector rule of the spec", rules)
+	}
+
+	// Unchanged match rules are left alone once the activation succeeded
+	stub.completeActivations(100)
+	if got, result = reconcile(); len(stub.calls) != 0 || got.Status.Phase != PhaseReady ||
+		got.Status.Staging.Status != "SUCCESS" || result.RequeueAfter != cloudletPolicyResyncInterval {
+		t.Errorf("calls = %q, status = %+v, expected the policy to be ready", stub.calls, got.Status)
+	}
+
+	// Changed match rules go to a new version since the activated one is immutable
+	got.Spec.MatchRules = []runtime.RawExtension{{Raw: []byte(`{"type":"erMatchRule","name":"shop","matchURL":"/shop/*","redirectURL":"/store/","statusCode":302}`)}}
+	if err := r.Update(ctx, got); err != nil {
+		t.Fatalf("failed to update cloudlet policy: %v", err)
+	}
+	got, _ = reconcile()
+	expected = []string{"create version 100/2", "activate 100/2 STAGING"}
+	if !slices.Equal(stub.calls, expected) || got.Status.Version != 2 {
+		t.Errorf("calls = %q, version = %d, expected a second version", stub.calls, got.Status.Version)
+	}
+
+	// Deleting deactivates the policy before it is removed
+	stub.completeActivations(100)
+	if err := r.Delete(ctx, got); err != nil {
+		t.Fatalf("failed to delete cloudlet policy: %v", err)
+	}
+	if _, result = reconcile(); !slices.Equal(stub.calls, []string{"deactivate 100/2 STAGING"}) || result.RequeueAfter != cloudletPolicyActivationPollInterval {
+		t.Errorf("calls = %q, expected the policy to be deactivated first", stub.calls)
+	}
+	stub.completeActivations(100)
+	stub.calls = nil
+	if _, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: key}); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	if !slices.Equal(stub.calls, []string{"delete 100"}) {
+		t.Errorf("calls = %q, expected the policy to be deleted", stub.calls)
+	}
+}
+
+func TestCloudletPolicyAdoptsExistingPolicy(t *testing.T) {
+	policy := &akamaiV1alpha1.AkamaiCloudletPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "rollout", Generation: 1},
+		Spec: akamaiV1alpha1.AkamaiCloudletPolicySpec{
+			Name:         "rollout",
+			CloudletType: "CD",
+			GroupID:      "grp_7",
+			MatchRules:   []runtime.RawExtension{{Raw: []byte(`{"name":"beta","matchURL":"/beta/*","forwardSettings":{"originId":"beta","percent":10}}`)}},
+		},
+	}
+	stub := &cloudletsAPI{
+		policies: map[int64]*cloudlets.Policy{7: {ID: 7, Name: "rollout", CloudletType: cloudlets.CloudletTypeCD, GroupID: 3}},
+		versions: map[int64][]cloudlets.PolicyVersion{7: {{PolicyID: 7, PolicyVersion: 1}}},
+	}
+	r := newCloudletPolicyReconciler(t, stub, policy)
+
+	if _, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: types.NamespacedName{Name: policy.Name}}); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	expected := []string{"update 7 group=7", "update version 7/1"}
+	if !slices.Equal(stub.calls, expected) {
+		t.Errorf("calls = %q, expected %q", stub.calls, expected)
+	}
+	if _, ok := stub.versions[7][0].MatchRules[0].(*cloudlets.MatchRulePR); !ok {
+		t.Errorf("match rules = %+v, expected a Phased Release rule", stub.versions[7][0].MatchRules)
+	}
+}
+
+func TestCloudletMatchRulesTypeMismatch(t *testing.T) {
+	policy := &akamaiV1alpha1.AkamaiCloudletPolicy{Spec: akamaiV1alpha1.AkamaiCloudletPolicySpec{
+		CloudletType: "AS",
+		MatchRules:   []runtime.RawExtension{{Raw: []byte(`{"type":"erMatchRule","name":"shop"}`)}},
+	}}
+	if _, err := cloudletMatchRules(policy); err == nil || !strings.Contains(err.Error(), "expected asMatchRule") {
+		t.Errorf("cloudletMatchRules() error = %v, expected a type mismatch", err)
+	}
+}
+
+func TestDesiredRulesResolvesCloudletPolicyRefs(t *testing.T) {
+	ctx := context.Background()
+	policy := &akamaiV1alpha1.AkamaiCloudletPolicy{ObjectMeta: metav1.ObjectMeta{Name: "www-redirects"}}
+	property := &akamaiV1alpha1.AkamaiProperty{
+		ObjectMeta: metav1.ObjectMeta{Name: "shop"},
+		Spec: akamaiV1alpha1.AkamaiPropertySpec{Rules: &akamaiV1alpha1.PropertyRules{
+			Name: "default",
+			Behaviors: []akamaiV1alpha1.RuleBehavior{{
+				Name:    "edgeRedirector",
+				Options: runtime.RawExtension{Raw: []byte(`{"enabled":true,"isSharedPolicy":true,"cloudletSharedPolicy":"${cloudletPolicy:www-redirects}","label":"policy ${cloudletPolicy:www-redirects}"}`)},
+			}},
+		}},
+	}
+	other := &akamaiV1alpha1.AkamaiProperty{ObjectMeta: metav1.ObjectMeta{Name: "blog"}}
+	r := newFakeReconciler(t, policy, property, other)
+
+	if _, _, err := r.desiredRules(ctx, property); err == nil || !strings.Contains(err.Error(), `AkamaiCloudletPolicy "www-redirects" has not been created in Akamai yet`) {
+		t.Errorf("desiredRules() error = %v, expected the policy to be missing", err)
+	}
+
+	policy.Status.PolicyID = 4711
+	if err := r.Update(ctx, policy); err != nil {
+		t.Fatalf("failed to update cloudlet policy: %v", err)
+	}
+	desired, _, err := r.desiredRules(ctx, property)
+	if err != nil {
+		t.Fatalf("desiredRules() error = %v", err)
+	}
+	if options := string(desired.Behaviors[0].Options.Raw); !strings.Contains(options, `"cloudletSharedPolicy":4711`) || !strings.Contains(options, `"label":"policy 4711"`) {
+		t.Errorf("options = %s, expected the policy ID", options)
+	}
+
+	requests := r.propertiesReferencingCloudletPolicy(ctx, policy)
+	if len(requests) != 1 || requests[0].Name != "shop" {
+		t.Errorf("propertiesReferencingCloudletPolicy() = %v, expected only the referencing property", requests)
+	}
+}
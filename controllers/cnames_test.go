@@ -0,0 +1,156 @@
+package controllers
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v8/pkg/papi"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+	"github.com/mmz-srf/akamai-operator/pkg/akamai"
+)
+
+func cnameProperty(dnsSpec *akamaiV1alpha1.PropertyDNSSpec) *akamaiV1alpha1.AkamaiProperty {
+	return &akamaiV1alpha1.AkamaiProperty{
+		ObjectMeta: metav1.ObjectMeta{Name: "shop", Labels: map[string]string{"akamai.com/shard": "news"}},
+		Spec: akamaiV1alpha1.AkamaiPropertySpec{
+			DNS: dnsSpec,
+			Hostnames: []akamaiV1alpha1.Hostname{
+				{CNAMEFrom: "www.example.com", CNAMETo: "www.example.com.edgekey.net"},
+				{CNAMEFrom: "api.shop.example.com", CNAMETo: "api.example.com.edgekey.net"},
+				{CNAMEFrom: "new.example.com", CNAMETo: "new.example.com.edgekey.net"},
+			},
+		},
+		Status: akamaiV1alpha1.AkamaiPropertyStatus{PropertyID: "prp_1", LatestVersion: 3, ProductionVersion: 2},
+	}
+}
+
+// cnamesPAPI serves the hostnames of the production version; new.example.com isn't activated yet
+func cnamesPAPI() *edgeHostnamesPAPI {
+	return &edgeHostnamesPAPI{hostnames: []papi.Hostname{
+		{CnameFrom: "www.example.com", CnameTo: "www.example.com.edgekey.net"},
+		{CnameFrom: "api.shop.example.com", CnameTo: "api.example.com.edgekey.net"},
+	}}
+}
+
+func TestSyncCNAMERecords(t *testing.T) {
+	ctx := context.Background()
+	property := cnameProperty(&akamaiV1alpha1.PropertyDNSSpec{AutoCreateCNAMEs: true})
+	zones := []client.Object{
+		&akamaiV1alpha1.AkamaiDnsZone{ObjectMeta: metav1.ObjectMeta{Name: "example.com"}},
+		&akamaiV1alpha1.AkamaiDnsZone{ObjectMeta: metav1.ObjectMeta{Name: "shop-zone"}, Spec: akamaiV1alpha1.AkamaiDnsZoneSpec{Zone: "shop.example.com"}},
+	}
+	stale := &akamaiV1alpha1.AkamaiDnsRecord{
+		ObjectMeta: metav1.ObjectMeta{Name: "old.example.com", Labels: map[string]string{LabelCNAMEProperty: "shop"}},
+		Spec:       akamaiV1alpha1.AkamaiDnsRecordSpec{Zone: "example.com", Type: "CNAME", Targets: []string{"old.example.com.edgekey.net"}},
+	}
+	r := newFakeReconciler(t, append(zones, property, stale)...)
+	r.AkamaiClient = akamai.NewClientWithPAPI(cnamesPAPI())
+
+	if err := r.syncCNAMEs(ctx, property); err != nil {
+		t.Fatalf("syncCNAMEs() error = %v", err)
+	}
+	var records akamaiV1alpha1.AkamaiDnsRecordList
+	if err := r.List(ctx, &records); err != nil {
+		t.Fatalf("failed to list records: %v", err)
+	}
+	got := map[string]akamaiV1alpha1.AkamaiDnsRecordSpec{}
+	for _, record := range records.Items {
+		got[record.Name] = record.Spec
+		if record.Labels["akamai.com/shard"] != "news" || record.Labels[LabelCNAMEProperty] != "shop" {
+			t.Errorf("labels of %s = %v, expected those of the property", record.Name, record.Labels)
+		}
+	}
+	expected := map[string]akamaiV1alpha1.AkamaiDnsRecordSpec{
+		"www.example.com": {Zone: "example.com", Name: "www.example.com", Type: "CNAME", TTL: 300,
+			Targets: []string{"www.example.com.edgekey.net"}},
+		"api.shop.example.com": {Zone: "shop.example.com", Name: "api.shop.example.com", Type: "CNAME", TTL: 300,
+			Targets: []string{"api.example.com.edgekey.net"}},
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("records = %+v, expected %+v", got, expected)
+	}
+
+	// Disabling the option removes the records
+	property.Spec.DNS.AutoCreateCNAMEs = false
+	if err := r.syncCNAMEs(ctx, property); err != nil {
+		t.Fatalf("syncCNAMEs() error = %v", err)
+	}
+	if err := r.List(ctx, &records); err != nil {
+		t.Fatalf("failed to list records: %v", err)
+	}
+	if len(records.Items) != 0 {
+		t.Errorf("records = %d, expected the CNAMEs to be removed", len(records.Items))
+	}
+}
+
+func TestSyncCNAMERecordsProblems(t *testing.T) {
+	ctx := context.Background()
+	property := cnameProperty(&akamaiV1alpha1.PropertyDNSSpec{AutoCreateCNAMEs: true})
+	foreign := &akamaiV1alpha1.AkamaiDnsRecord{
+		ObjectMeta: metav1.ObjectMeta{Name: "www.example.com"},
+		Spec:       akamaiV1alpha1.AkamaiDnsRecordSpec{Zone: "example.com", Type: "CNAME", Targets: []string{"origin.example.com"}},
+	}
+	zone := &akamaiV1alpha1.AkamaiDnsZone{ObjectMeta: metav1.ObjectMeta{Name: "example.com"}}
+	r := newFakeReconciler(t, property, foreign, zone)
+	r.AkamaiClient = akamai.NewClientWithPAPI(&edgeHostnamesPAPI{hostnames: []papi.Hostname{
+		{CnameFrom: "www.example.com", CnameTo: "www.example.com.edgekey.net"},
+		{CnameFrom: "api.shop.example.com", CnameTo: "api.example.com.edgekey.net"},
+	}})
+	property.Spec.Hostnames = append(property.Spec.Hostnames, akamaiV1alpha1.Hostname{CNAMEFrom: "www.example.org", CNAMETo: "www.example.org.edgekey.net"})
+
+	err := r.syncCNAMEs(ctx, property)
+	if err == nil || !strings.Contains(err.Error(), "AkamaiDnsRecord www.example.com already exists") {
+		t.Errorf("syncCNAMEs() error = %v, expected the foreign record to be reported", err)
+	}
+	var record akamaiV1alpha1.AkamaiDnsRecord
+	if err := r.Get(ctx, client.ObjectKey{Name: "www.example.com"}, &record); err != nil || record.Spec.Targets[0] != "origin.example.com" {
+		t.Errorf("record = %+v, expected the foreign record to be left alone", record.Spec)
+	}
+	if err := r.Get(ctx, client.ObjectKey{Name: "api.shop.example.com"}, &record); err != nil {
+		t.Errorf("expected the other hostnames to get their CNAME: %v", err)
+	}
+}
+
+func TestSyncDNSEndpoint(t *testing.T) {
+	ctx := context.Background()
+	property := cnameProperty(&akamaiV1alpha1.PropertyDNSSpec{
+		AutoCreateCNAMEs: true, Provider: akamaiV1alpha1.DNSProviderExternalDNS, Namespace: "dns", TTL: 60,
+	})
+	r := newFakeReconciler(t, property)
+	r.AkamaiClient = akamai.NewClientWithPAPI(cnamesPAPI())
+
+	if err := r.syncCNAMEs(ctx, property); err != nil {
+		t.Fatalf("syncCNAMEs() error = %v", err)
+	}
+	if property.Status.DNSEndpoint != "dns/shop" {
+		t.Errorf("status.dnsEndpoint = %q, expected dns/shop", property.Status.DNSEndpoint)
+	}
+	endpoint := &unstructured.Unstructured{}
+	endpoint.SetGroupVersionKind(dnsEndpointGVK)
+	if err := r.Get(ctx, types.NamespacedName{Namespace: "dns", Name: "shop"}, endpoint); err != nil {
+		t.Fatalf("failed to get DNSEndpoint: %v", err)
+	}
+	endpoints, _, _ := unstructured.NestedSlice(endpoint.Object, "spec", "endpoints")
+	expected := []interface{}{
+		map[string]interface{}{"dnsName": "www.example.com", "recordType": "CNAME", "recordTTL": int64(60), "targets": []interface{}{"www.example.com.edgekey.net"}},
+		map[string]interface{}{"dnsName": "api.shop.example.com", "recordType": "CNAME", "recordTTL": int64(60), "targets": []interface{}{"api.example.com.edgekey.net"}},
+	}
+	if !reflect.DeepEqual(endpoints, expected) {
+		t.Errorf("endpoints = %v, expected %v", endpoints, expected)
+	}
+
+	// Deleting the property removes the DNSEndpoint
+	if err := r.removeCNAMEs(ctx, property); err != nil {
+		t.Fatalf("removeCNAMEs() error = %v", err)
+	}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: "dns", Name: "shop"}, endpoint); err == nil || property.Status.DNSEndpoint != "" {
+		t.Errorf("status.dnsEndpoint = %q, expected the DNSEndpoint to be deleted", property.Status.DNSEndpoint)
+	}
+}
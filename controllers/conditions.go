@@ -0,0 +1,30 @@
+package controllers
+
+import (
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// readyStatus points at the status fields a resource reports its phase and Ready condition in
+type readyStatus struct {
+	Phase              *string
+	ObservedGeneration *int64
+	LastUpdated        **metav1.Time
+	Conditions         *[]metav1.Condition
+}
+
+// setReadyCondition sets the phase, the observed generation, the update time and the Ready
+// condition of a resource at the given generation
+func setReadyCondition(generation int64, fields readyStatus, phase string, status metav1.ConditionStatus, reason, message string) {
+	now := metav1.Now()
+	*fields.Phase = phase
+	*fields.ObservedGeneration = generation
+	*fields.LastUpdated = &now
+	meta.SetStatusCondition(fields.Conditions, metav1.Condition{
+		Type:               ConditionTypeReady,
+		Status:             status,
+		ObservedGeneration: generation,
+		Reason:             reason,
+		Message:            message,
+	})
+}
@@ -0,0 +1,247 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v8/pkg/datastream"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+	"github.com/mmz-srf/akamai-operator/pkg/akamai"
+)
+
+// dataStreamAPI stubs the DataStream streams of a group, recording the calls. Activations complete
+// at once, deactivations stay in progress.
+type dataStreamAPI struct {
+	datastream.DS
+	streams map[int64]*datastream.DetailedStreamVersion
+	calls   []string
+}
+
+func (s *dataStreamAPI) ListStreams(_ context.Context, _ datastream.ListStreamsRequest) ([]datastream.StreamDetails, error) {
+	var streams []datastream.StreamDetails
+	for id, stream := range s.streams {
+		streams = append(streams, datastream.StreamDetails{StreamID: id, StreamName: stream.StreamName})
+	}
+	return streams, nil
+}
+
+func (s *dataStreamAPI) GetStream(_ context.Context, req datastream.GetStreamRequest) (*datastream.DetailedStreamVersion, error) {
+	stream, ok := s.streams[req.StreamID]
+	if !ok {
+		return nil, &datastream.Error{StatusCode: 404}
+	}
+	return stream, nil
+}
+
+func (s *dataStreamAPI) CreateStream(_ context.Context, req datastream.CreateStreamRequest) (*datastream.DetailedStreamVersion, error) {
+	destination, _ := json.Marshal(req.StreamConfiguration.Destination)
+	s.calls = append(s.calls, fmt.Sprintf("create %s %v %s", req.StreamConfiguration.StreamName, req.StreamConfiguration.Properties, destination))
+	s.streams[7] = &datastream.DetailedStreamVersion{StreamID: 7, StreamName: req.StreamConfiguration.StreamName, StreamVersion: 1, StreamStatus: dataStreamStatus(req.Activate)}
+	return s.streams[7], nil
+}
+
+func (s *dataStreamAPI) UpdateStream(_ context.Context, req datastream.UpdateStreamRequest) (*datastream.DetailedStreamVersion, error) {
+	s.calls = append(s.calls, fmt.Sprintf("update %d", req.StreamID))
+	stream := s.streams[req.StreamID]
+	stream.StreamVersion++
+	stream.StreamStatus = dataStreamStatus(req.Activate)
+	return stream, nil
+}
+
+func (s *dataStreamAPI) ActivateStream(_ context.Context, req datastream.ActivateStreamRequest) (*datastream.DetailedStreamVersion, error) {
+	s.calls = append(s.calls, fmt.Sprintf("activate %d", req.StreamID))
+	s.streams[req.StreamID].StreamStatus = datastream.StreamStatusActivated
+	return s.streams[req.StreamID], nil
+}
+
+func (s *dataStreamAPI) DeactivateStream(_ context.Context, req datastream.DeactivateStreamRequest) (*datastream.DetailedStreamVersion, error) {
+	s.calls = append(s.calls, fmt.Sprintf("deactivate %d", req.StreamID))
+	s.streams[req.StreamID].StreamStatus = datastream.StreamStatusDeactivating
+	return s.streams[req.StreamID], nil
+}
+
+func dataStreamStatus(activate bool) datastream.StreamStatus {
+	if activate {
+		return datastream.StreamStatusActivating
+	}
+	return datastream.StreamStatusInactive
+}
+
+func TestDataStreamReconcile(t *testing.T) {
+	ctx := context.Background()
+	inactive := false
+	spec := akamaiV1alpha1.AkamaiDataStreamSpec{
+		ContractID:      "ctr_1",
+		GroupID:         "grp_1",
+		PropertyRefs:    []string{"www"},
+		PropertyIDs:     []string{"prp_9"},
+		DatasetFieldIDs: []int{1000, 1002},
+		Destination: akamaiV1alpha1.DataStreamDestination{
+			Type:      "S3",
+			Bucket:    "logs",
+			Region:    "eu-central-1",
+			SecretRef: &akamaiV1alpha1.SecretReference{Namespace: "default", Name: "s3"},
+		},
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "s3"},
+		Data:       map[string][]byte{"accessKey": []byte("AKIA"), "secretAccessKey": []byte("secret")},
+	}
+	stream := func(status datastream.StreamStatus) *datastream.DetailedStreamVersion {
+		return &datastream.DetailedStreamVersion{StreamID: 7, StreamName: "www-logs", StreamVersion: 3, StreamStatus: status}
+	}
+	// appliedHash is the hash of the configuration of spec with the Secret
+	appliedHash := func(spec akamaiV1alpha1.AkamaiDataStreamSpec) string {
+		config, err := dataStreamConfiguration(&akamaiV1alpha1.AkamaiDataStream{ObjectMeta: metav1.ObjectMeta{Name: "www-logs"}, Spec: spec},
+			1, []int{9, 42}, map[string]string{"accessKey": "AKIA", "secretAccessKey": "secret"})
+		if err != nil {
+			t.Fatalf("dataStreamConfiguration() error = %v", err)
+		}
+		hash, err := dataStreamConfigurationHash(config)
+		if err != nil {
+			t.Fatalf("dataStreamConfigurationHash() error = %v", err)
+		}
+		return hash
+	}
+
+	tests := []struct {
+		name            string
+		active          *bool
+		streams         map[int64]*datastream.DetailedStreamVersion
+		status          akamaiV1alpha1.AkamaiDataStreamStatus
+		secretKeys      []string
+		staleHash       bool
+		expectedCalls   []string
+		expectedPhase   string
+		expectedRequeue time.Duration
+	}{
+		{
+			name:    "created",
+			streams: map[int64]*datastream.DetailedStreamVersion{},
+			expectedCalls: []string{
+				`create www-logs [{9} {42}] {"destinationType":"S3","accessKey":"AKIA","bucket":"logs","displayName":"www-logs","path":"","region":"eu-central-1","secretAccessKey":"secret"}`,
+			},
+			expectedPhase:   PhaseActivating,
+			expectedRequeue: dataStreamActivationPollInterval,
+		},
+		{
+			name:            "adopted",
+			streams:         map[int64]*datastream.DetailedStreamVersion{7: stream(datastream.StreamStatusActivated)},
+			expectedCalls:   []string{"update 7"},
+			expectedPhase:   PhaseActivating,
+			expectedRequeue: dataStreamActivationPollInterval,
+		},
+		{
+			name:            "unchanged",
+			streams:         map[int64]*datastream.DetailedStreamVersion{7: stream(datastream.StreamStatusActivated)},
+			status:          akamaiV1alpha1.AkamaiDataStreamStatus{StreamID: 7, ConfigurationHash: appliedHash(spec)},
+			expectedPhase:   PhaseReady,
+			expectedRequeue: dataStreamResyncInterval,
+		},
+		{
+			name:            "activated",
+			streams:         map[int64]*datastream.DetailedStreamVersion{7: stream(datastream.StreamStatusDeactivated)},
+			status:          akamaiV1alpha1.AkamaiDataStreamStatus{StreamID: 7, ConfigurationHash: appliedHash(spec)},
+			expectedCalls:   []string{"activate 7"},
+			expectedPhase:   PhaseReady,
+			expectedRequeue: dataStreamResyncInterval,
+		},
+		{
+			name:            "deactivated",
+			active:          &inactive,
+			streams:         map[int64]*datastream.DetailedStreamVersion{7: stream(datastream.StreamStatusActivated)},
+			status:          akamaiV1alpha1.AkamaiDataStreamStatus{StreamID: 7, ConfigurationHash: appliedHash(spec)},
+			expectedCalls:   []string{"deactivate 7"},
+			expectedPhase:   PhaseActivating,
+			expectedRequeue: dataStreamActivationPollInterval,
+		},
+		{
+			name:            "busy",
+			streams:         map[int64]*datastream.DetailedStreamVersion{7: stream(datastream.StreamStatusActivating)},
+			status:          akamaiV1alpha1.AkamaiDataStreamStatus{StreamID: 7, ConfigurationHash: "sha256:old"},
+			staleHash:       true,
+			expectedPhase:   PhaseActivating,
+			expectedRequeue: dataStreamActivationPollInterval,
+		},
+		{
+			name:            "missing credentials",
+			streams:         map[int64]*datastream.DetailedStreamVersion{},
+			secretKeys:      []string{"accessKey"},
+			expectedPhase:   PhaseError,
+			expectedRequeue: dataStreamErrorRetryInterval,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			streamSpec := spec
+			streamSpec.Active = tt.active
+			resource := &akamaiV1alpha1.AkamaiDataStream{
+				ObjectMeta: metav1.ObjectMeta{Name: "www-logs", Generation: 1},
+				Spec:       streamSpec,
+				Status:     tt.status,
+			}
+			property := &akamaiV1alpha1.AkamaiProperty{
+				ObjectMeta: metav1.ObjectMeta{Name: "www"},
+				Status:     akamaiV1alpha1.AkamaiPropertyStatus{PropertyID: "prp_42"},
+			}
+			streamSecret := secret.DeepCopy()
+			if tt.secretKeys != nil {
+				for key := range streamSecret.Data {
+					if !slices.Contains(tt.secretKeys, key) {
+						delete(streamSecret.Data, key)
+					}
+				}
+			}
+			scheme := runtime.NewScheme()
+			if err := akamaiV1alpha1.AddToScheme(scheme); err != nil {
+				t.Fatalf("failed to build scheme: %v", err)
+			}
+			if err := corev1.AddToScheme(scheme); err != nil {
+				t.Fatalf("failed to build scheme: %v", err)
+			}
+			fakeClient := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(resource, property, streamSecret).
+				WithStatusSubresource(&akamaiV1alpha1.AkamaiDataStream{}).
+				Build()
+			stub := &dataStreamAPI{streams: tt.streams}
+			r := &AkamaiDataStreamReconciler{Client: fakeClient, Scheme: scheme, AkamaiClient: akamai.NewClientWithDataStream(stub)}
+			key := types.NamespacedName{Name: resource.Name}
+
+			result, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+			if err != nil {
+				t.Fatalf("Reconcile() error = %v", err)
+			}
+			var got akamaiV1alpha1.AkamaiDataStream
+			if err := r.Get(ctx, key, &got); err != nil {
+				t.Fatalf("failed to get stream: %v", err)
+			}
+			if strings.Join(stub.calls, "\n") != strings.Join(tt.expectedCalls, "\n") {
+				t.Errorf("calls = %q, expected %q", stub.calls, tt.expectedCalls)
+			}
+			if got.Status.Phase != tt.expectedPhase || result.RequeueAfter != tt.expectedRequeue {
+				t.Errorf("status = %+v, requeue = %v, expected phase %s and requeue %v", got.Status, result.RequeueAfter, tt.expectedPhase, tt.expectedRequeue)
+			}
+			if tt.expectedPhase != PhaseError {
+				// Streams being activated or deactivated are updated once they are done
+				applied := got.Status.ConfigurationHash == appliedHash(spec)
+				if got.Status.StreamID != 7 || applied == tt.staleHash || !slices.Equal(got.Status.PropertyIDs, []string{"prp_9", "prp_42"}) {
+					t.Errorf("status = %+v, expected stream 7 with the applied configuration %v", got.Status, !tt.staleHash)
+				}
+			}
+		})
+	}
+}
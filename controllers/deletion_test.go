@@ -0,0 +1,83 @@
+package controllers
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+	"github.com/mmz-srf/akamai-operator/pkg/akamai"
+)
+
+func TestDeletionPolicy(t *testing.T) {
+	tests := []struct {
+		policy   akamaiV1alpha1.DeletionPolicy
+		expected akamaiV1alpha1.DeletionPolicy
+	}{
+		{policy: "", expected: akamaiV1alpha1.DeletionPolicyDelete},
+		{policy: akamaiV1alpha1.DeletionPolicyRetain, expected: akamaiV1alpha1.DeletionPolicyRetain},
+		{policy: akamaiV1alpha1.DeletionPolicyDeactivate, expected: akamaiV1alpha1.DeletionPolicyDeactivate},
+	}
+
+	for _, tt := range tests {
+		property := &akamaiV1alpha1.AkamaiProperty{Spec: akamaiV1alpha1.AkamaiPropertySpec{DeletionPolicy: tt.policy}}
+		if got := deletionPolicy(property); got != tt.expected {
+			t.Errorf("deletionPolicy(%q) = %q, expected %q", tt.policy, got, tt.expected)
+		}
+	}
+}
+
+func TestHandleDeletionRetainsProperty(t *testing.T) {
+	ctx := context.Background()
+	now := metav1.Now()
+	property := &akamaiV1alpha1.AkamaiProperty{
+		ObjectMeta: metav1.ObjectMeta{Name: "example", Finalizers: []string{FinalizerName}, DeletionTimestamp: &now},
+		Spec:       akamaiV1alpha1.AkamaiPropertySpec{DeletionPolicy: akamaiV1alpha1.DeletionPolicyRetain},
+		Status: akamaiV1alpha1.AkamaiPropertyStatus{
+			PropertyID:         "prp_1",
+			OwnedEdgeHostnames: []string{"www.example.com.edgekey.net"},
+		},
+	}
+	// No Akamai client: retaining must not call Akamai
+	r := newFakeReconciler(t, property)
+
+	if _, err := r.handleDeletion(ctx, property); err != nil {
+		t.Fatalf("handleDeletion() error = %v", err)
+	}
+
+	var deleted akamaiV1alpha1.AkamaiProperty
+	if err := r.Get(ctx, types.NamespacedName{Name: "example"}, &deleted); !apierrors.IsNotFound(err) {
+		t.Errorf("expected the resource to be gone once its finalizer was removed, got %v", err)
+	}
+}
+
+func TestDeactivationNotifyEmails(t *testing.T) {
+	activations := []akamai.Activation{
+		{Network: "STAGING", PropertyVersion: 3, NotifyEmails: []string{"old@example.com"}},
+		{Network: "PRODUCTION", PropertyVersion: 4, NotifyEmails: []string{"console@example.com"}},
+	}
+	tests := []struct {
+		name       string
+		activation *akamaiV1alpha1.ActivationSpec
+		network    string
+		version    int
+		expected   []string
+	}{
+		{name: "spec emails", activation: &akamaiV1alpha1.ActivationSpec{NotifyEmails: []string{"team@example.com"}}, network: "PRODUCTION", version: 4, expected: []string{"team@example.com"}},
+		{name: "emails of the active version", network: "PRODUCTION", version: 4, expected: []string{"console@example.com"}},
+		{name: "no emails", network: "STAGING", version: 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			property := &akamaiV1alpha1.AkamaiProperty{Spec: akamaiV1alpha1.AkamaiPropertySpec{Activation: tt.activation}}
+			if got := deactivationNotifyEmails(property, activations, tt.network, tt.version); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("deactivationNotifyEmails() = %v, expected %v", got, tt.expected)
+			}
+		})
+	}
+}
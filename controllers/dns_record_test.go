@@ -0,0 +1,260 @@
+package controllers
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v8/pkg/dns"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+	"github.com/mmz-srf/akamai-operator/pkg/akamai"
+)
+
+// dnsRecordsAPI stubs the Edge DNS record set endpoints, recording the calls
+type dnsRecordsAPI struct {
+	dns.DNS
+	records map[string]*dns.RecordBody
+	calls   []string
+}
+
+func (s *dnsRecordsAPI) GetRecord(_ context.Context, zone, name, recordType string) (*dns.RecordBody, error) {
+	if record, ok := s.records[zone+"/"+name+"/"+recordType]; ok {
+		return record, nil
+	}
+	return nil, &dns.Error{StatusCode: http.StatusNotFound, Title: "Not Found"}
+}
+
+func (s *dnsRecordsAPI) GetRecordSets(_ context.Context, zone string, args ...dns.RecordSetQueryArgs) (*dns.RecordSetResponse, error) {
+	response := &dns.RecordSetResponse{}
+	for key, record := range s.records {
+		if strings.HasPrefix(key, zone+"/") && (len(args) == 0 || slices.Contains(strings.Split(args[0].Types, ","), record.RecordType)) {
+			response.RecordSets = append(response.RecordSets, dns.RecordSet{Name: record.Name, Type: record.RecordType, TTL: record.TTL, Rdata: record.Target})
+		}
+	}
+	slices.SortFunc(response.RecordSets, func(a, b dns.RecordSet) int {
+		return strings.Compare(a.Name+a.Type, b.Name+b.Type)
+	})
+	return response, nil
+}
+
+func (s *dnsRecordsAPI) CreateRecord(_ context.Context, record *dns.RecordBody, zone string, _ ...bool) error {
+	s.calls = append(s.calls, "create "+record.Name+" "+record.RecordType)
+	copied := *record
+	s.records[zone+"/"+record.Name+"/"+record.RecordType] = &copied
+	return nil
+}
+
+func (s *dnsRecordsAPI) UpdateRecord(_ context.Context, record *dns.RecordBody, zone string, _ ...bool) error {
+	s.calls = append(s.calls, "update "+record.Name+" "+record.RecordType)
+	copied := *record
+	s.records[zone+"/"+record.Name+"/"+record.RecordType] = &copied
+	return nil
+}
+
+func (s *dnsRecordsAPI) DeleteRecord(_ context.Context, record *dns.RecordBody, zone string, _ ...bool) error {
+	s.calls = append(s.calls, "delete "+record.Name+" "+record.RecordType)
+	delete(s.records, zone+"/"+record.Name+"/"+record.RecordType)
+	return nil
+}
+
+func newDNSRecordReconciler(t *testing.T, stub *dnsRecordsAPI, objects ...client.Object) *AkamaiDnsRecordReconciler {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	if err := akamaiV1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to build scheme: %v", err)
+	}
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(objects...).
+		WithStatusSubresource(&akamaiV1alpha1.AkamaiDnsRecord{}).
+		Build()
+	return &AkamaiDnsRecordReconciler{Client: fakeClient, Scheme: scheme, AkamaiClient: akamai.NewClientWithDNS(stub)}
+}
+
+func TestDNSRecordReconcile(t *testing.T) {
+	ctx := context.Background()
+	edgeHostname := &akamaiV1alpha1.AkamaiEdgeHostname{
+		ObjectMeta: metav1.ObjectMeta{Name: "www.example.com"},
+		Status:     akamaiV1alpha1.AkamaiEdgeHostnameStatus{Domain: "www.example.com.edgekey.net"},
+	}
+	record := &akamaiV1alpha1.AkamaiDnsRecord{
+		ObjectMeta: metav1.ObjectMeta{Name: "www.example.com", Generation: 1},
+		Spec: akamaiV1alpha1.AkamaiDnsRecordSpec{
+			Zone:            "example.com",
+			Type:            "CNAME",
+			EdgeHostnameRef: "www.example.com",
+		},
+	}
+	stub := &dnsRecordsAPI{records: map[string]*dns.RecordBody{}}
+	r := newDNSRecordReconciler(t, stub, edgeHostname, record)
+	key := types.NamespacedName{Name: record.Name}
+	reconcile := func() *akamaiV1alpha1.AkamaiDnsRecord {
+		t.Helper()
+		if _, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: key}); err != nil {
+			t.Fatalf("Reconcile() error = %v", err)
+		}
+		var got akamaiV1alpha1.AkamaiDnsRecord
+		if err := r.Get(ctx, key, &got); err != nil {
+			t.Fatalf("failed to get DNS record: %v", err)
+		}
+		return &got
+	}
+	const recordKey = "example.com/www.example.com/CNAME"
+
+	// The CNAME is created with the domain of the edge hostname and the default TTL
+	got := reconcile()
+	if !slices.Equal(stub.calls, []string{"create www.example.com CNAME"}) {
+		t.Errorf("calls = %q, expected the record to be created", stub.calls)
+	}
+	if created := stub.records[recordKey]; created == nil || created.TTL != 300 || !slices.Equal(created.Target, []string{"www.example.com.edgekey.net"}) {
+		t.Errorf("record = %+v, expected a CNAME to the edge hostname", created)
+	}
+	if got.Status.Phase != PhaseReady || !slices.Equal(got.Status.Targets, []string{"www.example.com.edgekey.net"}) {
+		t.Errorf("status = %+v, expected the ready record", got.Status)
+	}
+
+	// Edge DNS returning the target as fully qualified name is not a change
+	stub.records[recordKey].Target = []string{"www.example.com.edgekey.net."}
+	stub.calls = nil
+	got = reconcile()
+	if len(stub.calls) != 0 || got.Status.LastDriftCorrected != nil {
+		t.Errorf("calls = %q, expected the record to be in sync", stub.calls)
+	}
+
+	// A record changed outside the operator is restored
+	stub.records[recordKey].Target = []string{"origin.example.com."}
+	got = reconcile()
+	if !slices.Equal(stub.calls, []string{"update www.example.com CNAME"}) || got.Status.LastDriftCorrected == nil {
+		t.Errorf("calls = %q, status = %+v, expected the drift to be corrected", stub.calls, got.Status)
+	}
+	if condition := meta.FindStatusCondition(got.Status.Conditions, ConditionTypeReady); condition == nil || condition.Reason != "DriftCorrected" {
+		t.Errorf("conditions = %+v, expected a DriftCorrected Ready condition", got.Status.Conditions)
+	}
+
+	// A renamed record is deleted at its old name
+	got.Spec.Name = "shop.example.com"
+	if err := r.Update(ctx, got); err != nil {
+		t.Fatalf("failed to update DNS record: %v", err)
+	}
+	stub.calls = nil
+	got = reconcile()
+	if !slices.Equal(stub.calls, []string{"delete www.example.com CNAME", "create shop.example.com CNAME"}) {
+		t.Errorf("calls = %q, expected the record to be moved", stub.calls)
+	}
+	if got.Status.Name != "shop.example.com" || got.Status.LastDriftCorrected == nil {
+		t.Errorf("status = %+v, expected the moved record", got.Status)
+	}
+
+	// Deleting the resource deletes the record with the default deletion policy
+	if err := r.Delete(ctx, got); err != nil {
+		t.Fatalf("failed to delete DNS record: %v", err)
+	}
+	stub.calls = nil
+	if _, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: key}); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	if !slices.Equal(stub.calls, []string{"delete shop.example.com CNAME"}) || len(stub.records) != 0 {
+		t.Errorf("calls = %q, expected the record to be deleted", stub.calls)
+	}
+}
+
+func TestDNSRecordUnresolvedEdgeHostname(t *testing.T) {
+	ctx := context.Background()
+	record := &akamaiV1alpha1.AkamaiDnsRecord{
+		ObjectMeta: metav1.ObjectMeta{Name: "www.example.com", Generation: 1},
+		Spec:       akamaiV1alpha1.AkamaiDnsRecordSpec{Zone: "example.com", Type: "CNAME", EdgeHostnameRef: "missing"},
+	}
+	stub := &dnsRecordsAPI{records: map[string]*dns.RecordBody{}}
+	r := newDNSRecordReconciler(t, stub, record)
+	key := types.NamespacedName{Name: record.Name}
+	result, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+	if err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	var got akamaiV1alpha1.AkamaiDnsRecord
+	if err := r.Get(ctx, key, &got); err != nil {
+		t.Fatalf("failed to get DNS record: %v", err)
+	}
+	if got.Status.Phase != PhaseError || result.RequeueAfter != dnsRecordErrorRetryInterval || len(stub.calls) != 0 {
+		t.Errorf("status = %+v, calls = %q, expected the record to wait for the edge hostname", got.Status, stub.calls)
+	}
+}
+
+func TestDNSRecordInSync(t *testing.T) {
+	tests := []struct {
+		name     string
+		desired  dns.RecordBody
+		current  dns.RecordBody
+		expected bool
+	}{
+		{
+			name:     "IPv4 addresses in another order",
+			desired:  dns.RecordBody{RecordType: "A", TTL: 300, Target: []string{"192.0.2.1", "192.0.2.2"}},
+			current:  dns.RecordBody{RecordType: "A", TTL: 300, Target: []string{"192.0.2.2", "192.0.2.1"}},
+			expected: true,
+		},
+		{
+			name:     "IPv6 address in its long form",
+			desired:  dns.RecordBody{RecordType: "AAAA", TTL: 300, Target: []string{"2001:db8::1"}},
+			current:  dns.RecordBody{RecordType: "AAAA", TTL: 300, Target: []string{"2001:0db8:0000:0000:0000:0000:0000:0001"}},
+			expected: true,
+		},
+		{
+			name:    "TTL changed",
+			desired: dns.RecordBody{RecordType: "A", TTL: 300, Target: []string{"192.0.2.1"}},
+			current: dns.RecordBody{RecordType: "A", TTL: 60, Target: []string{"192.0.2.1"}},
+		},
+		{
+			name:     "CNAME target fully qualified",
+			desired:  dns.RecordBody{RecordType: "CNAME", TTL: 300, Target: []string{"www.example.com.edgekey.net"}},
+			current:  dns.RecordBody{RecordType: "CNAME", TTL: 300, Target: []string{"WWW.example.com.edgekey.net."}},
+			expected: true,
+		},
+		{
+			name:     "long TXT split into strings",
+			desired:  dns.RecordBody{RecordType: "TXT", TTL: 300, Target: []string{`"v=spf1 include:_spf.example.com ~all"`}},
+			current:  dns.RecordBody{RecordType: "TXT", TTL: 300, Target: []string{`"v=spf1 include:" "_spf.example.com ~all"`}},
+			expected: true,
+		},
+		{
+			name:    "TXT changed",
+			desired: dns.RecordBody{RecordType: "TXT", TTL: 300, Target: []string{`"verification=1"`}},
+			current: dns.RecordBody{RecordType: "TXT", TTL: 300, Target: []string{`"verification=2"`}},
+		},
+		{
+			name:    "target added",
+			desired: dns.RecordBody{RecordType: "A", TTL: 300, Target: []string{"192.0.2.1", "192.0.2.2"}},
+			current: dns.RecordBody{RecordType: "A", TTL: 300, Target: []string{"192.0.2.1"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := dnsRecordInSync(&tt.desired, &tt.current); got != tt.expected {
+				t.Errorf("dnsRecordInSync() = %v, expected %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestDNSRecordBodyQuotesTexts(t *testing.T) {
+	record := &akamaiV1alpha1.AkamaiDnsRecord{
+		ObjectMeta: metav1.ObjectMeta{Name: "example.com"},
+		Spec:       akamaiV1alpha1.AkamaiDnsRecordSpec{Zone: "example.com", Type: "TXT", TTL: 60},
+	}
+	got := dnsRecordBody(record, []string{`say "hi"`, `"quoted"`})
+	expected := []string{`"say \"hi\""`, `"quoted"`}
+	if got.Name != "example.com" || got.TTL != 60 || !slices.Equal(got.Target, expected) {
+		t.Errorf("dnsRecordBody() = %+v, expected the targets %q", got, expected)
+	}
+}
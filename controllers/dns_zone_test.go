@@ -0,0 +1,312 @@
+package controllers
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v8/pkg/dns"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+	"github.com/mmz-srf/akamai-operator/pkg/akamai"
+)
+
+// dnsZonesAPI stubs the Edge DNS zone endpoints, recording the calls
+type dnsZonesAPI struct {
+	dns.DNS
+	zones map[string]*dns.ZoneResponse
+	calls []string
+}
+
+func (s *dnsZonesAPI) GetZone(_ context.Context, zone string) (*dns.ZoneResponse, error) {
+	if current, ok := s.zones[zone]; ok {
+		return current, nil
+	}
+	return nil, &dns.Error{StatusCode: http.StatusNotFound, Title: "Not Found"}
+}
+
+func (s *dnsZonesAPI) CreateZone(_ context.Context, zone *dns.ZoneCreate, _ dns.ZoneQueryString, _ ...bool) error {
+	s.calls = append(s.calls, "create "+zone.Zone)
+	s.zones[zone.Zone] = &dns.ZoneResponse{Zone: zone.Zone, Type: zone.Type, Comment: zone.Comment,
+		SignAndServe: zone.SignAndServe, ActivationState: "NEW", VersionID: "v1"}
+	return nil
+}
+
+func (s *dnsZonesAPI) SaveChangelist(_ context.Context, zone *dns.ZoneCreate) error {
+	s.calls = append(s.calls, "save "+zone.Zone)
+	return nil
+}
+
+func (s *dnsZonesAPI) SubmitChangelist(_ context.Context, zone *dns.ZoneCreate) error {
+	s.calls = append(s.calls, "submit "+zone.Zone)
+	s.zones[zone.Zone].ActivationState = "PENDING"
+	return nil
+}
+
+func (s *dnsZonesAPI) UpdateZone(_ context.Context, zone *dns.ZoneCreate, _ dns.ZoneQueryString) error {
+	s.calls = append(s.calls, "update "+zone.Zone)
+	current := s.zones[zone.Zone]
+	current.Comment, current.SignAndServe, current.SignAndServeAlgorithm = zone.Comment, zone.SignAndServe, zone.SignAndServeAlgorithm
+	return nil
+}
+
+func (s *dnsZonesAPI) GetZonesDNSSecStatus(_ context.Context, request dns.GetZonesDNSSecStatusRequest) (*dns.GetZonesDNSSecStatusResponse, error) {
+	return &dns.GetZonesDNSSecStatusResponse{DNSSecStatuses: []dns.SecStatus{
+		{Zone: request.Zones[0], CurrentRecords: dns.SecRecords{DSRecord: request.Zones[0] + ". 86400 IN DS 12345 13 2 ABCDEF"}},
+	}}, nil
+}
+
+func (s *dnsZonesAPI) DeleteBulkZones(_ context.Context, zones *dns.ZoneNameListResponse, _ ...bool) (*dns.BulkZonesResponse, error) {
+	s.calls = append(s.calls, "delete "+zones.Zones[0])
+	return &dns.BulkZonesResponse{RequestID: "req_1"}, nil
+}
+
+func newDNSZoneReconciler(t *testing.T, stub *dnsZonesAPI, objects ...client.Object) *AkamaiDnsZoneReconciler {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	if err := akamaiV1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to build scheme: %v", err)
+	}
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(objects...).
+		WithStatusSubresource(&akamaiV1alpha1.AkamaiDnsZone{}).
+		Build()
+	return &AkamaiDnsZoneReconciler{Client: fakeClient, Scheme: scheme, AkamaiClient: akamai.NewClientWithDNS(stub)}
+}
+
+func TestDNSZoneReconcile(t *testing.T) {
+	ctx := context.Background()
+	zone := &akamaiV1alpha1.AkamaiDnsZone{
+		ObjectMeta: metav1.ObjectMeta{Name: "example.com", Generation: 1},
+		Spec: akamaiV1alpha1.AkamaiDnsZoneSpec{
+			ContractID:     "ctr_1",
+			Comment:        "managed",
+			DeletionPolicy: akamaiV1alpha1.DeletionPolicyDelete,
+		},
+	}
+	stub := &dnsZonesAPI{zones: map[string]*dns.ZoneResponse{}}
+	r := newDNSZoneReconciler(t, stub, zone)
+	key := types.NamespacedName{Name: zone.Name}
+	reconcile := func() *akamaiV1alpha1.AkamaiDnsZone {
+		t.Helper()
+		if _, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: key}); err != nil {
+			t.Fatalf("Reconcile() error = %v", err)
+		}
+		var got akamaiV1alpha1.AkamaiDnsZone
+		if err := r.Get(ctx, key, &got); err != nil {
+			t.Fatalf("failed to get DNS zone: %v", err)
+		}
+		return &got
+	}
+
+	// The primary zone is created with the name as zone and its default records are submitted
+	got := reconcile()
+	if !slices.Equal(stub.calls, []string{"create example.com", "save example.com", "submit example.com"}) {
+		t.Errorf("calls = %q, expected the zone to be created with its default records", stub.calls)
+	}
+	if got.Status.Zone != "example.com" || got.Status.ActivationState != "PENDING" || got.Status.Phase != PhaseReady {
+		t.Errorf("status = %+v, expected the pending zone example.com", got.Status)
+	}
+
+	// Changed settings update the zone, and sign and serve publishes the DS record
+	got.Spec.Comment = "signed"
+	got.Spec.SignAndServe = true
+	if err := r.Update(ctx, got); err != nil {
+		t.Fatalf("failed to update DNS zone: %v", err)
+	}
+	stub.calls = nil
+	got = reconcile()
+	if !slices.Equal(stub.calls, []string{"update example.com"}) {
+		t.Errorf("calls = %q, expected the zone to be updated", stub.calls)
+	}
+	if got.Status.DSRecord == "" || stub.zones["example.com"].Comment != "signed" {
+		t.Errorf("status = %+v, expected the DS record of the signed zone", got.Status)
+	}
+
+	// An unchanged zone is left alone
+	stub.calls = nil
+	got = reconcile()
+	if len(stub.calls) != 0 {
+		t.Errorf("calls = %q, expected no changes", stub.calls)
+	}
+
+	// A renamed zone is refused
+	got.Spec.Zone = "example.org"
+	if err := r.Update(ctx, got); err != nil {
+		t.Fatalf("failed to update DNS zone: %v", err)
+	}
+	got = reconcile()
+	if got.Status.Phase != PhaseError || got.Status.Zone != "example.com" || len(stub.calls) != 0 {
+		t.Errorf("status = %+v, calls = %q, expected the rename to be refused", got.Status, stub.calls)
+	}
+
+	// Deleting the resource deletes the zone with the Delete deletion policy
+	if err := r.Delete(ctx, got); err != nil {
+		t.Fatalf("failed to delete DNS zone: %v", err)
+	}
+	if _, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: key}); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	if !slices.Equal(stub.calls, []string{"delete example.com"}) {
+		t.Errorf("calls = %q, expected the zone to be deleted", stub.calls)
+	}
+	if err := r.Get(ctx, key, got); err == nil {
+		t.Errorf("expected the resource to be gone once the finalizer is removed")
+	}
+}
+
+func TestDNSZoneObserveOnly(t *testing.T) {
+	ctx := context.Background()
+	zone := &akamaiV1alpha1.AkamaiDnsZone{
+		ObjectMeta: metav1.ObjectMeta{Name: "example.com", Generation: 1},
+		Spec: akamaiV1alpha1.AkamaiDnsZoneSpec{
+			ContractID:     "ctr_1",
+			Comment:        "managed",
+			DeletionPolicy: akamaiV1alpha1.DeletionPolicyDelete,
+		},
+	}
+	stub := &dnsZonesAPI{zones: map[string]*dns.ZoneResponse{}}
+	r := newDNSZoneReconciler(t, stub, zone)
+	r.ObserveOnly = true
+	key := types.NamespacedName{Name: zone.Name}
+	reconcile := func() *akamaiV1alpha1.AkamaiDnsZone {
+		t.Helper()
+		if _, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: key}); err != nil {
+			t.Fatalf("Reconcile() error = %v", err)
+		}
+		var got akamaiV1alpha1.AkamaiDnsZone
+		if err := r.Get(ctx, key, &got); err != nil {
+			t.Fatalf("failed to get DNS zone: %v", err)
+		}
+		return &got
+	}
+	ready := func(got *akamaiV1alpha1.AkamaiDnsZone) *metav1.Condition {
+		return meta.FindStatusCondition(got.Status.Conditions, "Ready")
+	}
+
+	// A missing zone is reported, not created
+	got := reconcile()
+	condition := ready(got)
+	if got.Status.Phase != PhaseObserving || condition == nil || condition.Reason != ReasonObserveOnly ||
+		!strings.Contains(condition.Message, "doesn't exist") || len(stub.calls) != 0 {
+		t.Errorf("status = %+v, calls = %q, expected the missing zone to be reported", got.Status, stub.calls)
+	}
+
+	// Differing settings are reported, not updated
+	stub.zones["example.com"] = &dns.ZoneResponse{Zone: "example.com", Type: "PRIMARY", Comment: "other", ActivationState: "ACTIVE"}
+	got = reconcile()
+	if condition = ready(got); got.Status.Phase != PhaseObserving || !strings.Contains(condition.Message, "differ") || len(stub.calls) != 0 {
+		t.Errorf("status = %+v, calls = %q, expected the differing settings to be reported", got.Status, stub.calls)
+	}
+
+	// A matching zone is observed
+	stub.zones["example.com"].Comment = "managed"
+	got = reconcile()
+	if condition = ready(got); got.Status.Phase != PhaseObserving || !strings.Contains(condition.Message, "matches") {
+		t.Errorf("status = %+v, expected the zone to match", got.Status)
+	}
+
+	// Deleting the resource leaves the zone in Edge DNS, whatever the deletion policy
+	if err := r.Delete(ctx, got); err != nil {
+		t.Fatalf("failed to delete DNS zone: %v", err)
+	}
+	if _, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: key}); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	if len(stub.calls) != 0 || r.Get(ctx, key, got) == nil {
+		t.Errorf("calls = %q, expected the resource to be gone and the zone retained", stub.calls)
+	}
+}
+
+func TestDNSZoneAdoptionAndTypeChange(t *testing.T) {
+	ctx := context.Background()
+	zone := &akamaiV1alpha1.AkamaiDnsZone{
+		ObjectMeta: metav1.ObjectMeta{Name: "example.net", Generation: 1, Finalizers: []string{DNSZoneFinalizerName}},
+		Spec:       akamaiV1alpha1.AkamaiDnsZoneSpec{ContractID: "ctr_1", Type: "SECONDARY", Masters: []string{"192.0.2.53"}},
+	}
+	stub := &dnsZonesAPI{zones: map[string]*dns.ZoneResponse{
+		"example.net": {Zone: "example.net", Type: "PRIMARY", ActivationState: "ACTIVE", LastActivationDate: "2026-01-01T00:00:00Z"},
+	}}
+	r := newDNSZoneReconciler(t, stub, zone)
+	key := types.NamespacedName{Name: zone.Name}
+	if _, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: key}); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	var got akamaiV1alpha1.AkamaiDnsZone
+	if err := r.Get(ctx, key, &got); err != nil {
+		t.Fatalf("failed to get DNS zone: %v", err)
+	}
+	if got.Status.Phase != PhaseError || got.Status.Zone != "" || len(stub.calls) != 0 {
+		t.Errorf("status = %+v, calls = %q, expected the primary zone not to be turned into a secondary one", got.Status, stub.calls)
+	}
+
+	// Once the spec matches, the existing zone is adopted without changes
+	got.Spec.Type = "PRIMARY"
+	got.Spec.Masters = nil
+	if err := r.Update(ctx, &got); err != nil {
+		t.Fatalf("failed to update DNS zone: %v", err)
+	}
+	if _, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: key}); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	if err := r.Get(ctx, key, &got); err != nil {
+		t.Fatalf("failed to get DNS zone: %v", err)
+	}
+	if got.Status.Phase != PhaseReady || got.Status.Zone != "example.net" || got.Status.ActivationState != "ACTIVE" || len(stub.calls) != 0 {
+		t.Errorf("status = %+v, calls = %q, expected the zone to be adopted", got.Status, stub.calls)
+	}
+}
+
+func TestDNSZoneNeedsUpdate(t *testing.T) {
+	current := &dns.ZoneResponse{Zone: "example.com", Type: "SECONDARY", Masters: []string{"192.0.2.1", "192.0.2.2"},
+		Comment: "zone", SignAndServe: true, SignAndServeAlgorithm: "RSA_SHA256"}
+	tests := []struct {
+		name     string
+		desired  dns.ZoneCreate
+		expected bool
+	}{
+		{
+			name:    "unchanged with masters in another order",
+			desired: dns.ZoneCreate{Masters: []string{"192.0.2.2", "192.0.2.1"}, Comment: "zone", SignAndServe: true},
+		},
+		{
+			name:     "comment changed",
+			desired:  dns.ZoneCreate{Masters: []string{"192.0.2.1", "192.0.2.2"}, Comment: "other", SignAndServe: true},
+			expected: true,
+		},
+		{
+			name:     "master removed",
+			desired:  dns.ZoneCreate{Masters: []string{"192.0.2.1"}, Comment: "zone", SignAndServe: true},
+			expected: true,
+		},
+		{
+			name: "algorithm changed",
+			desired: dns.ZoneCreate{Masters: []string{"192.0.2.1", "192.0.2.2"}, Comment: "zone", SignAndServe: true,
+				SignAndServeAlgorithm: "ECDSA_P256_SHA256"},
+			expected: true,
+		},
+		{
+			name:     "sign and serve disabled",
+			desired:  dns.ZoneCreate{Masters: []string{"192.0.2.1", "192.0.2.2"}, Comment: "zone"},
+			expected: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := dnsZoneNeedsUpdate(&tt.desired, current); got != tt.expected {
+				t.Errorf("dnsZoneNeedsUpdate() = %v, expected %v", got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/events"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+)
+
+func TestHandleDrift(t *testing.T) {
+	tests := []struct {
+		name      string
+		policy    akamaiV1alpha1.DriftPolicy
+		applied   bool
+		observe   bool
+		overwrite bool
+		drift     []string
+		event     string
+	}{
+		{name: "spec change with warn", policy: akamaiV1alpha1.DriftPolicyWarn, overwrite: true},
+		{name: "correct by default", applied: true, overwrite: true, event: ReasonDriftCorrected},
+		{name: "warn", policy: akamaiV1alpha1.DriftPolicyWarn, applied: true, drift: []string{driftRules}, event: ReasonDriftDetected},
+		{name: "ignore", policy: akamaiV1alpha1.DriftPolicyIgnore, applied: true},
+		{name: "observe-only spec change", observe: true, drift: []string{driftRules}, event: ReasonDriftDetected},
+		{name: "observe-only ignore", policy: akamaiV1alpha1.DriftPolicyIgnore, applied: true, observe: true, drift: []string{driftRules}, event: ReasonDriftDetected},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			property := &akamaiV1alpha1.AkamaiProperty{
+				ObjectMeta: metav1.ObjectMeta{Name: "example", Generation: 1},
+				Spec:       akamaiV1alpha1.AkamaiPropertySpec{PropertyName: "example.com", DriftPolicy: tt.policy},
+			}
+			if tt.applied {
+				checksum, err := specChecksum(&property.Spec)
+				if err != nil {
+					t.Fatalf("specChecksum() unexpected error: %v", err)
+				}
+				property.Annotations = map[string]string{AnnotationAppliedChecksum: checksum}
+			}
+			r := newFakeReconciler(t, property)
+			recorder := events.NewFakeRecorder(10)
+			r.Recorder = recorder
+			r.ObserveOnly = tt.observe
+
+			if got := r.handleDrift(context.Background(), property, driftRules); got != tt.overwrite {
+				t.Errorf("handleDrift() = %v, expected %v", got, tt.overwrite)
+			}
+			if len(property.Status.Drift) != len(tt.drift) {
+				t.Errorf("status.drift = %v, expected %v", property.Status.Drift, tt.drift)
+			}
+			if got := meta.IsStatusConditionTrue(property.Status.Conditions, ConditionTypeDriftDetected); got != (len(tt.drift) > 0) {
+				t.Errorf("DriftDetected condition = %v", got)
+			}
+			select {
+			case event := <-recorder.Events:
+				if tt.event == "" {
+					t.Errorf("unexpected event %q", event)
+				}
+			default:
+				if tt.event != "" {
+					t.Errorf("expected a %s event", tt.event)
+				}
+			}
+
+			// A drift that is still there is reported only once
+			if len(tt.drift) > 0 {
+				r.handleDrift(context.Background(), property, driftRules)
+				if len(recorder.Events) != 0 {
+					t.Errorf("expected no second event, got %q", <-recorder.Events)
+				}
+			}
+		})
+	}
+}
+
+func TestSetDrift(t *testing.T) {
+	property := &akamaiV1alpha1.AkamaiProperty{Spec: akamaiV1alpha1.AkamaiPropertySpec{DriftPolicy: akamaiV1alpha1.DriftPolicyWarn}}
+
+	uncorrected := "with drift policy Warn"
+	if !setDrift(property, driftRules, true, uncorrected) || !setDrift(property, driftHostnames, true, uncorrected) {
+		t.Fatal("setDrift() expected new drift to change the status")
+	}
+	if setDrift(property, driftRules, true, uncorrected) {
+		t.Error("setDrift() expected known drift not to change the status")
+	}
+	condition := meta.FindStatusCondition(property.Status.Conditions, ConditionTypeDriftDetected)
+	if condition == nil || condition.Message != "Differs from the spec and not corrected with drift policy Warn: hostnames, rules" {
+		t.Errorf("unexpected condition %+v", condition)
+	}
+
+	setDrift(property, driftRules, false, uncorrected)
+	setDrift(property, driftHostnames, false, uncorrected)
+	if property.Status.Drift != nil || meta.FindStatusCondition(property.Status.Conditions, ConditionTypeDriftDetected) != nil {
+		t.Errorf("expected resolved drift to be cleared, got %v", property.Status.Drift)
+	}
+}
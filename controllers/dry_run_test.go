@@ -0,0 +1,113 @@
+package controllers
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+)
+
+func TestRulesDiff(t *testing.T) {
+	behavior := func(name, options string) akamaiV1alpha1.RuleBehavior {
+		return akamaiV1alpha1.RuleBehavior{Name: name, Options: runtime.RawExtension{Raw: []byte(options)}}
+	}
+	child := func(name string, behaviors ...string) runtime.RawExtension {
+		return runtime.RawExtension{Raw: []byte(`{"name":"` + name + `","behaviors":[` + strings.Join(behaviors, ",") + `]}`)}
+	}
+	desired := &akamaiV1alpha1.PropertyRules{
+		Name:      "default",
+		Behaviors: []akamaiV1alpha1.RuleBehavior{behavior("origin", `{"hostname":"new.example.com"}`), behavior("http2", `{}`)},
+		Children: []runtime.RawExtension{
+			child("Images", `{"name":"caching","options":{"ttl":"7d"}}`),
+			child("Static", `{"name":"caching","options":{"ttl":"1d"}}`),
+			child("New"),
+		},
+		Variables: []akamaiV1alpha1.RuleVariable{{Name: "PMUSER_TOKEN", Value: "secret", Sensitive: true}},
+	}
+	current := map[string]interface{}{
+		"name": "default",
+		"behaviors": []interface{}{
+			map[string]interface{}{"name": "origin", "options": map[string]interface{}{"hostname": "old.example.com"}},
+			map[string]interface{}{"name": "gzipResponse", "options": map[string]interface{}{"behavior": "ALWAYS"}},
+		},
+		"children": []interface{}{
+			map[string]interface{}{"name": "Static", "behaviors": []interface{}{map[string]interface{}{"name": "caching", "options": map[string]interface{}{"ttl": "1d"}}}},
+			map[string]interface{}{"name": "Images", "behaviors": []interface{}{map[string]interface{}{"name": "caching", "options": map[string]interface{}{"ttl": "1d"}}}},
+			map[string]interface{}{"name": "Old"},
+		},
+		"variables": []interface{}{
+			map[string]interface{}{"name": "PMUSER_TOKEN", "value": "old-secret", "sensitive": true},
+		},
+	}
+
+	changes, err := (&AkamaiPropertyReconciler{}).rulesDiff(desired, current)
+	if err != nil {
+		t.Fatalf("rulesDiff() unexpected error: %v", err)
+	}
+	expected := []string{
+		`Change behavior "origin" of rule "default"`,
+		`Add behavior "http2" to rule "default"`,
+		`Remove behavior "gzipResponse" from rule "default"`,
+		`Change behavior "caching" of rule "default/Images"`,
+		`Add rule "default/New"`,
+		`Remove rule "default/Old"`,
+		`Reorder the child rules of rule "default"`,
+		`Change variable "PMUSER_TOKEN" of rule "default"`,
+	}
+	if strings.Join(changes, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("rulesDiff() =\n%s\nexpected\n%s", strings.Join(changes, "\n"), strings.Join(expected, "\n"))
+	}
+	for _, change := range changes {
+		if strings.Contains(change, "secret") {
+			t.Errorf("change %q leaks a variable value", change)
+		}
+	}
+}
+
+func TestDryRunCreation(t *testing.T) {
+	property := &akamaiV1alpha1.AkamaiProperty{
+		ObjectMeta: metav1.ObjectMeta{Name: "example", Generation: 2},
+		Spec: akamaiV1alpha1.AkamaiPropertySpec{
+			PropertyName: "example.com",
+			ProductID:    "prd_Fresca",
+			ContractID:   "ctr_1",
+			GroupID:      "grp_1",
+			DryRun:       true,
+			Hostnames:    []akamaiV1alpha1.Hostname{{CNAMEFrom: "www.example.com", CNAMETo: "www.example.com.edgekey.net"}},
+			Activation:   &akamaiV1alpha1.ActivationSpec{Network: "STAGING", NotifyEmails: []string{"ops@example.com"}},
+		},
+	}
+	r := newFakeReconciler(t, property)
+
+	// Without an Akamai client any attempt to create the property would fail
+	if _, err := r.reconcileProperty(context.Background(), property); err != nil {
+		t.Fatalf("reconcileProperty() unexpected error: %v", err)
+	}
+	expected := []string{
+		`Create property "example.com" with product prd_Fresca in contract ctr_1 and group grp_1`,
+		"Add hostname www.example.com -> www.example.com.edgekey.net",
+		"Activate version 1 on STAGING",
+	}
+	if strings.Join(property.Status.PendingChanges, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("pendingChanges = %q, expected %q", property.Status.PendingChanges, expected)
+	}
+	if property.Status.Phase != PhaseDryRun || property.Status.PropertyID != "" || property.Status.ObservedGeneration != 2 {
+		t.Errorf("phase = %q, propertyID = %q, observedGeneration = %d", property.Status.Phase, property.Status.PropertyID, property.Status.ObservedGeneration)
+	}
+	condition := meta.FindStatusCondition(property.Status.Conditions, ConditionTypeDryRun)
+	if condition == nil || condition.Reason != ReasonChangesPending || !strings.Contains(condition.Message, "Add hostname www.example.com") {
+		t.Errorf("unexpected DryRun condition %+v", condition)
+	}
+
+	if !clearPendingChanges(property) || property.Status.PendingChanges != nil || meta.FindStatusCondition(property.Status.Conditions, ConditionTypeDryRun) != nil {
+		t.Error("clearPendingChanges() expected to remove the dry run results")
+	}
+	if clearPendingChanges(property) {
+		t.Error("clearPendingChanges() expected no change without dry run results")
+	}
+}
@@ -0,0 +1,144 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v8/pkg/papi"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+	"github.com/mmz-srf/akamai-operator/pkg/akamai"
+)
+
+func TestBuildEdgeEndpointsEntry(t *testing.T) {
+	property := &akamaiV1alpha1.AkamaiProperty{
+		ObjectMeta: metav1.ObjectMeta{Name: "example"},
+		Spec:       akamaiV1alpha1.AkamaiPropertySpec{PropertyName: "example.com"},
+		Status: akamaiV1alpha1.AkamaiPropertyStatus{
+			PropertyID:        "prp_123",
+			LatestVersion:     3,
+			StagingVersion:    3,
+			ProductionVersion: 2,
+		},
+	}
+	hostnames := []akamai.Hostname{
+		{CNAMEFrom: "www.example.com", CNAMETo: "example.com.edgesuite.net"},
+		{CNAMEFrom: "api.example.com", CNAMETo: "example.com.edgekey.net"},
+	}
+
+	entry := buildEdgeEndpointsEntry(property, hostnames)
+
+	if entry.PropertyID != "prp_123" || entry.PropertyName != "example.com" {
+		t.Errorf("unexpected property identity: %+v", entry)
+	}
+	if entry.StagingVersion != 3 || entry.ProductionVersion != 2 {
+		t.Errorf("unexpected versions: staging=%d production=%d", entry.StagingVersion, entry.ProductionVersion)
+	}
+	if len(entry.Endpoints) != 2 {
+		t.Fatalf("expected 2 endpoints, got %d", len(entry.Endpoints))
+	}
+	if entry.Endpoints[1].Hostname != "api.example.com" || entry.Endpoints[1].EdgeHostname != "example.com.edgekey.net" {
+		t.Errorf("unexpected endpoint: %+v", entry.Endpoints[1])
+	}
+	if key := edgeEndpointsDataKey(property); key != "example.json" {
+		t.Errorf("unexpected data key: %s", key)
+	}
+	if name := edgeEndpointsConfigMapKey(&akamaiV1alpha1.EdgeEndpointsSpec{Namespace: "dns"}).Name; name != DefaultEdgeEndpointsConfigMapName {
+		t.Errorf("unexpected default ConfigMap name: %s", name)
+	}
+}
+
+func TestPublishEdgeEndpoints(t *testing.T) {
+	ctx := context.Background()
+	property := &akamaiV1alpha1.AkamaiProperty{
+		ObjectMeta: metav1.ObjectMeta{Name: "example"},
+		Spec: akamaiV1alpha1.AkamaiPropertySpec{
+			PropertyName: "example.com",
+			// The spec already lists a hostname the active version doesn't serve yet
+			Hostnames: []akamaiV1alpha1.Hostname{
+				{CNAMEFrom: "www.example.com", CNAMETo: "example.com.edgesuite.net"},
+				{CNAMEFrom: "new.example.com", CNAMETo: "example.com.edgesuite.net"},
+			},
+			EdgeEndpoints: &akamaiV1alpha1.EdgeEndpointsSpec{Namespace: "dns"},
+		},
+		Status: akamaiV1alpha1.AkamaiPropertyStatus{PropertyID: "prp_123", LatestVersion: 3, StagingVersion: 3, ProductionVersion: 2},
+	}
+	stub := &edgeHostnamesPAPI{versionHostnames: map[int][]papi.Hostname{
+		2: {{CnameFrom: "www.example.com", CnameTo: "example.com.edgesuite.net"}},
+		3: {{CnameFrom: "www.example.com", CnameTo: "example.com.edgesuite.net"}, {CnameFrom: "new.example.com", CnameTo: "example.com.edgesuite.net"}},
+	}}
+	r := newFakeReconciler(t, property)
+	if err := clientgoscheme.AddToScheme(r.Scheme); err != nil {
+		t.Fatalf("failed to build scheme: %v", err)
+	}
+	r.Client = fake.NewClientBuilder().WithScheme(r.Scheme).WithObjects(property).WithStatusSubresource(property).Build()
+	r.AkamaiClient = akamai.NewClientWithPAPI(stub)
+
+	entry := func(namespace, name string) *EdgeEndpointsEntry {
+		t.Helper()
+		var configMap corev1.ConfigMap
+		if err := r.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, &configMap); err != nil {
+			t.Fatalf("failed to get ConfigMap %s/%s: %v", namespace, name, err)
+		}
+		data, ok := configMap.Data["example.json"]
+		if !ok {
+			return nil
+		}
+		var entry EdgeEndpointsEntry
+		if err := json.Unmarshal([]byte(data), &entry); err != nil {
+			t.Fatalf("failed to unmarshal entry: %v", err)
+		}
+		return &entry
+	}
+
+	// The hostnames of the version active on production are published
+	if err := r.publishEdgeEndpoints(ctx, property); err != nil {
+		t.Fatalf("publishEdgeEndpoints() error = %v", err)
+	}
+	if published := entry("dns", DefaultEdgeEndpointsConfigMapName); published == nil || len(published.Endpoints) != 1 || published.Endpoints[0].Hostname != "www.example.com" {
+		t.Errorf("entry = %+v, expected the hostnames of version 2", published)
+	}
+	if property.Status.EdgeEndpoints != "dns/"+DefaultEdgeEndpointsConfigMapName {
+		t.Errorf("status.edgeEndpoints = %q, expected the published ConfigMap", property.Status.EdgeEndpoints)
+	}
+
+	// Moving the ConfigMap removes the entry from the previous one
+	property.Spec.EdgeEndpoints = &akamaiV1alpha1.EdgeEndpointsSpec{Namespace: "edge", ConfigMapName: "endpoints"}
+	if err := r.publishEdgeEndpoints(ctx, property); err != nil {
+		t.Fatalf("publishEdgeEndpoints() error = %v", err)
+	}
+	if previous := entry("dns", DefaultEdgeEndpointsConfigMapName); previous != nil {
+		t.Errorf("entry = %+v, expected it removed from the previous ConfigMap", previous)
+	}
+	if entry("edge", "endpoints") == nil || property.Status.EdgeEndpoints != "edge/endpoints" {
+		t.Errorf("status.edgeEndpoints = %q, expected the entry in edge/endpoints", property.Status.EdgeEndpoints)
+	}
+
+	// Removing spec.edgeEndpoints removes the entry
+	property.Spec.EdgeEndpoints = nil
+	if err := r.publishEdgeEndpoints(ctx, property); err != nil {
+		t.Fatalf("publishEdgeEndpoints() error = %v", err)
+	}
+	if published := entry("edge", "endpoints"); published != nil || property.Status.EdgeEndpoints != "" {
+		t.Errorf("entry = %+v, status.edgeEndpoints = %q, expected the entry removed", published, property.Status.EdgeEndpoints)
+	}
+
+	// Deleting the property removes the entry it was published with
+	property.Spec.EdgeEndpoints = &akamaiV1alpha1.EdgeEndpointsSpec{Namespace: "dns"}
+	if err := r.publishEdgeEndpoints(ctx, property); err != nil {
+		t.Fatalf("publishEdgeEndpoints() error = %v", err)
+	}
+	property.Spec.EdgeEndpoints = nil
+	if err := r.removeEdgeEndpoints(ctx, property); err != nil {
+		t.Fatalf("removeEdgeEndpoints() error = %v", err)
+	}
+	if published := entry("dns", DefaultEdgeEndpointsConfigMapName); published != nil {
+		t.Errorf("entry = %+v, expected it removed on delete", published)
+	}
+}
@@ -0,0 +1,225 @@
+package controllers
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v8/pkg/papi"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+	"github.com/mmz-srf/akamai-operator/pkg/akamai"
+)
+
+// edgeHostnamesPAPI stubs the PAPI edge hostname endpoints and the hostnames of one property
+// version, recording the calls
+type edgeHostnamesPAPI struct {
+	papi.PAPI
+	edgeHostnames []papi.EdgeHostnameGetItem
+	hostnames     []papi.Hostname
+	// versionHostnames are the hostnames of single property versions, overriding hostnames
+	versionHostnames map[int][]papi.Hostname
+	calls            []string
+}
+
+func (s *edgeHostnamesPAPI) GetEdgeHostnames(_ context.Context, _ papi.GetEdgeHostnamesRequest) (*papi.GetEdgeHostnamesResponse, error) {
+	return &papi.GetEdgeHostnamesResponse{EdgeHostnames: papi.EdgeHostnameItems{Items: s.edgeHostnames}}, nil
+}
+
+func (s *edgeHostnamesPAPI) CreateEdgeHostname(_ context.Context, request papi.CreateEdgeHostnameRequest) (*papi.CreateEdgeHostnameResponse, error) {
+	domain := request.EdgeHostname.DomainPrefix + "." + request.EdgeHostname.DomainSuffix
+	s.calls = append(s.calls, "create "+domain)
+	s.edgeHostnames = append(s.edgeHostnames, papi.EdgeHostnameGetItem{ID: "ehn_1", Domain: domain, Status: "PENDING"})
+	return &papi.CreateEdgeHostnameResponse{EdgeHostnameID: "ehn_1"}, nil
+}
+
+func (s *edgeHostnamesPAPI) GetEdgeHostname(_ context.Context, request papi.GetEdgeHostnameRequest) (*papi.GetEdgeHostnamesResponse, error) {
+	for _, item := range s.edgeHostnames {
+		if item.ID == request.EdgeHostnameID {
+			return &papi.GetEdgeHostnamesResponse{EdgeHostnames: papi.EdgeHostnameItems{Items: []papi.EdgeHostnameGetItem{item}}}, nil
+		}
+	}
+	return &papi.GetEdgeHostnamesResponse{}, nil
+}
+
+func (s *edgeHostnamesPAPI) GetPropertyVersionHostnames(_ context.Context, request papi.GetPropertyVersionHostnamesRequest) (*papi.GetPropertyVersionHostnamesResponse, error) {
+	if hostnames, ok := s.versionHostnames[request.PropertyVersion]; ok {
+		return &papi.GetPropertyVersionHostnamesResponse{Hostnames: papi.HostnameResponseItems{Items: hostnames}}, nil
+	}
+	return &papi.GetPropertyVersionHostnamesResponse{Hostnames: papi.HostnameResponseItems{Items: s.hostnames}}, nil
+}
+
+func newEdgeHostnameReconciler(t *testing.T, stub *edgeHostnamesPAPI, objects ...client.Object) *AkamaiEdgeHostnameReconciler {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	if err := akamaiV1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to build scheme: %v", err)
+	}
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(objects...).
+		WithStatusSubresource(&akamaiV1alpha1.AkamaiEdgeHostname{}).
+		Build()
+	return &AkamaiEdgeHostnameReconciler{Client: fakeClient, Scheme: scheme, AkamaiClient: akamai.NewClientWithPAPI(stub)}
+}
+
+func certHostname(cnameFrom, cnameTo, productionStatus string) papi.Hostname {
+	return papi.Hostname{CnameFrom: cnameFrom, CnameTo: cnameTo, CertStatus: papi.CertStatusItem{
+		Production: []papi.StatusItem{{Status: productionStatus}},
+	}}
+}
+
+func TestEdgeHostnameReconcile(t *testing.T) {
+	ctx := context.Background()
+	edgeHostname := &akamaiV1alpha1.AkamaiEdgeHostname{
+		ObjectMeta: metav1.ObjectMeta{Name: "www.example.com", Generation: 1},
+		Spec: akamaiV1alpha1.AkamaiEdgeHostnameSpec{
+			ContractID:   "ctr_1",
+			GroupID:      "grp_1",
+			ProductID:    "prd_Fresca",
+			DomainSuffix: "edgekey.net",
+		},
+	}
+	property := &akamaiV1alpha1.AkamaiProperty{
+		ObjectMeta: metav1.ObjectMeta{Name: "shop"},
+		Spec: akamaiV1alpha1.AkamaiPropertySpec{ContractID: "ctr_1", GroupID: "grp_1", Hostnames: []akamaiV1alpha1.Hostname{
+			{CNAMEFrom: "www.example.com", EdgeHostnameRef: "www.example.com"},
+			{CNAMEFrom: "api.example.com", CNAMETo: "api.example.com.edgekey.net"},
+		}},
+		Status: akamaiV1alpha1.AkamaiPropertyStatus{PropertyID: "prp_1", LatestVersion: 2},
+	}
+	stub := &edgeHostnamesPAPI{hostnames: []papi.Hostname{
+		certHostname("www.example.com", "www.example.com.edgekey.net", "PENDING"),
+		certHostname("api.example.com", "api.example.com.edgekey.net", "DEPLOYED"),
+	}}
+	r := newEdgeHostnameReconciler(t, stub, edgeHostname, property)
+	key := types.NamespacedName{Name: edgeHostname.Name}
+	reconcile := func() (ctrl.Result, *akamaiV1alpha1.AkamaiEdgeHostname) {
+		t.Helper()
+		result, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+		if err != nil {
+			t.Fatalf("Reconcile() error = %v", err)
+		}
+		var got akamaiV1alpha1.AkamaiEdgeHostname
+		if err := r.Get(ctx, key, &got); err != nil {
+			t.Fatalf("failed to get edge hostname: %v", err)
+		}
+		return result, &got
+	}
+
+	// The edge hostname is created with the name as prefix and the pending certificate is polled
+	result, got := reconcile()
+	if !slices.Equal(stub.calls, []string{"create www.example.com.edgekey.net"}) {
+		t.Errorf("calls = %q, expected the edge hostname to be created", stub.calls)
+	}
+	if got.Status.EdgeHostnameID != "ehn_1" || got.Status.Domain != "www.example.com.edgekey.net" || got.Status.Phase != PhaseReady {
+		t.Errorf("status = %+v, expected edge hostname ehn_1", got.Status)
+	}
+	if !slices.Equal(got.Status.ReferencedBy, []string{"shop"}) || len(got.Status.Certificates) != 1 || got.Status.CertStatus != "PENDING" {
+		t.Errorf("status = %+v, expected the pending certificate of www.example.com", got.Status)
+	}
+	if result.RequeueAfter != edgeHostnameCertPollInterval || !slices.Contains(got.Finalizers, EdgeHostnameFinalizerName) {
+		t.Errorf("requeue = %v, finalizers = %v", result.RequeueAfter, got.Finalizers)
+	}
+
+	// Once deployed, the edge hostname is only refreshed
+	stub.calls = nil
+	stub.hostnames[0] = certHostname("www.example.com", "www.example.com.edgekey.net", "DEPLOYED")
+	result, got = reconcile()
+	if len(stub.calls) != 0 || got.Status.CertStatus != akamai.CertStatusDeployed || result.RequeueAfter != edgeHostnameResyncInterval {
+		t.Errorf("calls = %q, status = %+v, requeue = %v", stub.calls, got.Status, result.RequeueAfter)
+	}
+
+	// The deletion waits for the property referencing the edge hostname
+	if err := r.Delete(ctx, got); err != nil {
+		t.Fatalf("failed to delete edge hostname: %v", err)
+	}
+	_, got = reconcile()
+	if got.Status.Phase != PhaseDeleting || !strings.Contains(got.Status.Conditions[0].Message, "shop") {
+		t.Errorf("status = %+v, expected the deletion to wait for shop", got.Status)
+	}
+	if err := r.Delete(ctx, property); err != nil {
+		t.Fatalf("failed to delete property: %v", err)
+	}
+	if _, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: key}); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	if err := r.Get(ctx, key, got); err == nil {
+		t.Errorf("expected the retained edge hostname to be released, finalizers %v", got.Finalizers)
+	}
+}
+
+func TestResolveEdgeHostnameRefs(t *testing.T) {
+	created := &akamaiV1alpha1.AkamaiEdgeHostname{
+		ObjectMeta: metav1.ObjectMeta{Name: "www.example.com"},
+		Status:     akamaiV1alpha1.AkamaiEdgeHostnameStatus{EdgeHostnameID: "ehn_1", Domain: "www.example.com.edgekey.net"},
+	}
+	pending := &akamaiV1alpha1.AkamaiEdgeHostname{ObjectMeta: metav1.ObjectMeta{Name: "api.example.com"}}
+
+	tests := []struct {
+		name     string
+		hostname akamaiV1alpha1.Hostname
+		want     string
+		err      string
+	}{
+		{name: "reference", hostname: akamaiV1alpha1.Hostname{CNAMEFrom: "www.example.com", EdgeHostnameRef: "www.example.com"}, want: "www.example.com.edgekey.net"},
+		{name: "cnameTo", hostname: akamaiV1alpha1.Hostname{CNAMEFrom: "www.example.com", CNAMETo: "example.edgesuite.net"}, want: "example.edgesuite.net"},
+		{name: "edge hostname not created yet", hostname: akamaiV1alpha1.Hostname{CNAMEFrom: "api.example.com", EdgeHostnameRef: "api.example.com"}, err: "not been created"},
+		{name: "missing edge hostname", hostname: akamaiV1alpha1.Hostname{CNAMEFrom: "x.example.com", EdgeHostnameRef: "x.example.com"}, err: "not found"},
+		{name: "both", hostname: akamaiV1alpha1.Hostname{CNAMEFrom: "www.example.com", CNAMETo: "a.edgesuite.net", EdgeHostnameRef: "www.example.com"}, err: "sets both"},
+		{name: "neither", hostname: akamaiV1alpha1.Hostname{CNAMEFrom: "www.example.com"}, err: "needs cnameTo or edgeHostnameRef"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			property := &akamaiV1alpha1.AkamaiProperty{
+				ObjectMeta: metav1.ObjectMeta{Name: "shop"},
+				Spec:       akamaiV1alpha1.AkamaiPropertySpec{Hostnames: []akamaiV1alpha1.Hostname{tt.hostname}},
+			}
+			r := newFakeReconciler(t, created, pending)
+			err := r.resolveEdgeHostnameRefs(context.Background(), property)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("expected error containing %q, got %v", tt.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("resolveEdgeHostnameRefs() unexpected error: %v", err)
+			}
+			if got := property.Spec.Hostnames[0].CNAMETo; got != tt.want {
+				t.Errorf("cnameTo = %q, expected %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCertStatusSummary(t *testing.T) {
+	tests := []struct {
+		name     string
+		statuses []string
+		want     string
+	}{
+		{name: "no certificates"},
+		{name: "certificates not managed by PAPI", statuses: []string{"", ""}},
+		{name: "all deployed", statuses: []string{"DEPLOYED", "", "DEPLOYED"}, want: "DEPLOYED"},
+		{name: "one pending", statuses: []string{"DEPLOYED", "PENDING"}, want: "PENDING"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var certificates []akamaiV1alpha1.EdgeHostnameCertificate
+			for _, status := range tt.statuses {
+				certificates = append(certificates, akamaiV1alpha1.EdgeHostnameCertificate{ProductionCertStatus: status})
+			}
+			if got := certStatusSummary(certificates); got != tt.want {
+				t.Errorf("certStatusSummary() = %q, expected %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,142 @@
+package controllers
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v8/pkg/papi"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+	"github.com/mmz-srf/akamai-operator/pkg/akamai"
+)
+
+func TestPlanEdgeHostnameRelease(t *testing.T) {
+	owned := []string{"shared.example.com.edgesuite.net", "own.example.com.edgekey.net"}
+	references := map[string][]string{
+		"shared.example.com.edgesuite.net": {"team-a", "team-b"},
+		"other.example.com.edgesuite.net":  {"team-c"},
+	}
+
+	transfers, deletions := planEdgeHostnameRelease(owned, references)
+
+	expectedTransfers := map[string]string{"shared.example.com.edgesuite.net": "team-a"}
+	if !reflect.DeepEqual(transfers, expectedTransfers) {
+		t.Errorf("transfers = %v, expected %v", transfers, expectedTransfers)
+	}
+	expectedDeletions := []string{"own.example.com.edgekey.net"}
+	if !reflect.DeepEqual(deletions, expectedDeletions) {
+		t.Errorf("deletions = %v, expected %v", deletions, expectedDeletions)
+	}
+}
+
+func TestReleaseEdgeHostnamesTransfersOwnership(t *testing.T) {
+	ctx := context.Background()
+	shared := "shared.example.com.edgesuite.net"
+	hostnames := []akamaiV1alpha1.Hostname{{CNAMEFrom: "www.example.com", CNAMETo: shared}}
+
+	deleting := &akamaiV1alpha1.AkamaiProperty{
+		ObjectMeta: metav1.ObjectMeta{Name: "owner", UID: types.UID("owner")},
+		Spec:       akamaiV1alpha1.AkamaiPropertySpec{Hostnames: hostnames},
+		Status:     akamaiV1alpha1.AkamaiPropertyStatus{OwnedEdgeHostnames: []string{shared}},
+	}
+	remaining := &akamaiV1alpha1.AkamaiProperty{
+		ObjectMeta: metav1.ObjectMeta{Name: "team-b", UID: types.UID("team-b")},
+		Spec:       akamaiV1alpha1.AkamaiPropertySpec{Hostnames: hostnames},
+	}
+	r := newFakeReconciler(t, deleting, remaining)
+
+	references, err := r.edgeHostnameReferences(ctx, deleting)
+	if err != nil {
+		t.Fatalf("edgeHostnameReferences() error = %v", err)
+	}
+	if !reflect.DeepEqual(references[shared], []string{"team-b"}) {
+		t.Fatalf("references[%s] = %v, expected [team-b]", shared, references[shared])
+	}
+
+	if err := r.releaseEdgeHostnames(ctx, deleting); err != nil {
+		t.Fatalf("releaseEdgeHostnames() error = %v", err)
+	}
+	if deleting.Status.OwnedEdgeHostnames != nil {
+		t.Errorf("released property still owns %v", deleting.Status.OwnedEdgeHostnames)
+	}
+
+	var holder akamaiV1alpha1.AkamaiProperty
+	if err := r.Get(ctx, client.ObjectKey{Name: "team-b"}, &holder); err != nil {
+		t.Fatalf("failed to get holder: %v", err)
+	}
+	if !reflect.DeepEqual(holder.Status.OwnedEdgeHostnames, []string{shared}) {
+		t.Errorf("holder owns %v, expected [%s]", holder.Status.OwnedEdgeHostnames, shared)
+	}
+}
+
+func TestCollectEdgeHostnames(t *testing.T) {
+	ctx := context.Background()
+	active := "old.example.com.edgesuite.net"
+	rollback := "rollback.example.com.edgesuite.net"
+	shared := "shared.example.com.edgesuite.net"
+
+	owner := &akamaiV1alpha1.AkamaiProperty{
+		ObjectMeta: metav1.ObjectMeta{Name: "owner", UID: types.UID("owner")},
+		Spec: akamaiV1alpha1.AkamaiPropertySpec{Hostnames: []akamaiV1alpha1.Hostname{
+			{CNAMEFrom: "www.example.com", CNAMETo: "new.example.com.edgesuite.net"},
+		}},
+		Status: akamaiV1alpha1.AkamaiPropertyStatus{
+			PropertyID:         "prp_1",
+			LatestVersion:      3,
+			ProductionVersion:  2,
+			OwnedEdgeHostnames: []string{"new.example.com.edgesuite.net", active, rollback, shared},
+		},
+	}
+	other := &akamaiV1alpha1.AkamaiProperty{
+		ObjectMeta: metav1.ObjectMeta{Name: "team-b", UID: types.UID("team-b")},
+		Spec:       akamaiV1alpha1.AkamaiPropertySpec{Hostnames: []akamaiV1alpha1.Hostname{{CNAMEFrom: "shared.example.com", CNAMETo: shared}}},
+	}
+	r := newFakeReconciler(t, owner, other)
+	// The production version still serves www.example.com from the old edge hostname, and the
+	// inactive version 1 a rollback could return to from another one
+	r.AkamaiClient = akamai.NewClientWithPAPI(&edgeHostnamesPAPI{
+		hostnames:        []papi.Hostname{{CnameFrom: "www.example.com", CnameTo: active}},
+		versionHostnames: map[int][]papi.Hostname{1: {{CnameFrom: "www.example.com", CnameTo: rollback}}},
+	})
+
+	if err := r.collectEdgeHostnames(ctx, owner); err != nil {
+		t.Fatalf("collectEdgeHostnames() error = %v", err)
+	}
+	if len(owner.Status.OwnedEdgeHostnames) != 4 {
+		t.Fatalf("owned = %v, expected nothing to be collected without --collect-edge-hostnames", owner.Status.OwnedEdgeHostnames)
+	}
+
+	r.CollectEdgeHostnames = true
+	if err := r.collectEdgeHostnames(ctx, owner); err != nil {
+		t.Fatalf("collectEdgeHostnames() error = %v", err)
+	}
+	expected := []string{"new.example.com.edgesuite.net", active, rollback}
+	if !reflect.DeepEqual(owner.Status.OwnedEdgeHostnames, expected) {
+		t.Errorf("owned = %v, expected %v", owner.Status.OwnedEdgeHostnames, expected)
+	}
+	var holder akamaiV1alpha1.AkamaiProperty
+	if err := r.Get(ctx, client.ObjectKey{Name: "team-b"}, &holder); err != nil {
+		t.Fatalf("failed to get holder: %v", err)
+	}
+	if !reflect.DeepEqual(holder.Status.OwnedEdgeHostnames, []string{shared}) {
+		t.Errorf("holder owns %v, expected [%s]", holder.Status.OwnedEdgeHostnames, shared)
+	}
+}
+
+func TestUnusedEdgeHostnames(t *testing.T) {
+	hostnames := []akamaiV1alpha1.Hostname{
+		{CNAMEFrom: "www.example.com", CNAMETo: "www.example.com.edgekey.net"},
+		{CNAMEFrom: "api.example.com", CNAMETo: "www.example.com.edgekey.net"},
+	}
+	got := unusedEdgeHostnames([]string{"www.example.com.edgekey.net", "old.example.com.edgesuite.net"}, hostnames)
+	if !reflect.DeepEqual(got, []string{"old.example.com.edgesuite.net"}) {
+		t.Errorf("unusedEdgeHostnames() = %v", got)
+	}
+	if got := unusedEdgeHostnames(nil, hostnames); got != nil {
+		t.Errorf("unusedEdgeHostnames() = %v, expected nil", got)
+	}
+}
@@ -0,0 +1,259 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"testing"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v8/pkg/edgeworkers"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+	"github.com/mmz-srf/akamai-operator/pkg/akamai"
+)
+
+// edgeKVAPI stubs the EdgeKV endpoints, recording the calls that change namespaces and items.
+// The account is initialized by the first InitializeEdgeKV call.
+type edgeKVAPI struct {
+	edgeworkers.Edgeworkers
+	accountStatus string
+	namespaces    map[string]edgeworkers.Namespace
+	items         map[string]string
+	calls         []string
+}
+
+func (s *edgeKVAPI) GetEdgeKVInitializationStatus(_ context.Context) (*edgeworkers.EdgeKVInitializationStatus, error) {
+	return &edgeworkers.EdgeKVInitializationStatus{AccountStatus: s.accountStatus}, nil
+}
+
+func (s *edgeKVAPI) InitializeEdgeKV(_ context.Context) (*edgeworkers.EdgeKVInitializationStatus, error) {
+	s.calls = append(s.calls, "initialize")
+	s.accountStatus = "PENDING"
+	return &edgeworkers.EdgeKVInitializationStatus{AccountStatus: s.accountStatus}, nil
+}
+
+func (s *edgeKVAPI) GetEdgeKVNamespace(_ context.Context, req edgeworkers.GetEdgeKVNamespaceRequest) (*edgeworkers.Namespace, error) {
+	namespace, ok := s.namespaces[string(req.Network)+"/"+req.Name]
+	if !ok {
+		return nil, &edgeworkers.Error{Status: http.StatusNotFound, Title: "Not Found"}
+	}
+	return &namespace, nil
+}
+
+func (s *edgeKVAPI) CreateEdgeKVNamespace(_ context.Context, req edgeworkers.CreateEdgeKVNamespaceRequest) (*edgeworkers.Namespace, error) {
+	s.calls = append(s.calls, fmt.Sprintf("create %s/%s geo=%q", req.Network, req.Name, req.GeoLocation))
+	namespace := req.Namespace
+	if namespace.GeoLocation == "" {
+		namespace.GeoLocation = "US"
+	}
+	s.namespaces[string(req.Network)+"/"+req.Name] = namespace
+	return &namespace, nil
+}
+
+func (s *edgeKVAPI) UpdateEdgeKVNamespace(_ context.Context, req edgeworkers.UpdateEdgeKVNamespaceRequest) (*edgeworkers.Namespace, error) {
+	s.calls = append(s.calls, fmt.Sprintf("update %s/%s retention=%d", req.Network, req.Name, *req.Retention))
+	namespace := s.namespaces[string(req.Network)+"/"+req.Name]
+	namespace.Retention, namespace.GroupID = req.Retention, req.GroupID
+	s.namespaces[string(req.Network)+"/"+req.Name] = namespace
+	return &namespace, nil
+}
+
+func (s *edgeKVAPI) itemKey(params edgeworkers.ItemsRequestParams, key string) string {
+	return fmt.Sprintf("%s/%s/%s/%s", params.Network, params.NamespaceID, params.GroupID, key)
+}
+
+func (s *edgeKVAPI) ListItems(_ context.Context, req edgeworkers.ListItemsRequest) (*edgeworkers.ListItemsResponse, error) {
+	var keys edgeworkers.ListItemsResponse
+	for key := range s.items {
+		prefix := s.itemKey(req.ItemsRequestParams, "")
+		if len(key) > len(prefix) && key[:len(prefix)] == prefix {
+			keys = append(keys, key[len(prefix):])
+		}
+	}
+	if len(keys) == 0 {
+		return nil, &edgeworkers.Error{Status: http.StatusNotFound, Title: "Not Found"}
+	}
+	return &keys, nil
+}
+
+func (s *edgeKVAPI) GetItem(_ context.Context, req edgeworkers.GetItemRequest) (*edgeworkers.Item, error) {
+	value, ok := s.items[s.itemKey(req.ItemsRequestParams, req.ItemID)]
+	if !ok {
+		return nil, &edgeworkers.Error{Status: http.StatusNotFound, Title: "Not Found"}
+	}
+	item := edgeworkers.Item(value)
+	return &item, nil
+}
+
+func (s *edgeKVAPI) UpsertItem(_ context.Context, req edgeworkers.UpsertItemRequest) (*string, error) {
+	key := s.itemKey(req.ItemsRequestParams, req.ItemID)
+	s.calls = append(s.calls, "put "+key)
+	s.items[key] = string(req.ItemData)
+	result := "Item was upserted in KV store"
+	return &result, nil
+}
+
+func newEdgeKVNamespaceReconciler(t *testing.T, stub *edgeKVAPI, objects ...client.Object) *AkamaiEdgeKVNamespaceReconciler {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	if err := akamaiV1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to build scheme: %v", err)
+	}
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to build scheme: %v", err)
+	}
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(objects...).
+		WithStatusSubresource(&akamaiV1alpha1.AkamaiEdgeKVNamespace{}).
+		Build()
+	return &AkamaiEdgeKVNamespaceReconciler{Client: fakeClient, Scheme: scheme, AkamaiClient: akamai.NewClientWithEdgeWorkers(stub)}
+}
+
+func TestEdgeKVNamespaceReconcile(t *testing.T) {
+	ctx := context.Background()
+	kv := &akamaiV1alpha1.AkamaiEdgeKVNamespace{
+		ObjectMeta: metav1.ObjectMeta{Name: "redirects", Generation: 1},
+		Spec: akamaiV1alpha1.AkamaiEdgeKVNamespaceSpec{
+			Networks:    []string{"production", "staging"},
+			GeoLocation: "EU",
+			Seed: &akamaiV1alpha1.EdgeKVSeedSpec{
+				Group:        "paths",
+				ConfigMapRef: &akamaiV1alpha1.ConfigMapReference{Namespace: "web", Name: "redirects"},
+			},
+		},
+	}
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "web", Name: "redirects"},
+		Data:       map[string]string{"old": "/new", "legacy": "/"},
+	}
+	stub := &edgeKVAPI{accountStatus: akamai.EdgeKVUninitialized, namespaces: map[string]edgeworkers.Namespace{}, items: map[string]string{}}
+	r := newEdgeKVNamespaceReconciler(t, stub, kv, configMap)
+	key := types.NamespacedName{Name: kv.Name}
+	reconcile := func() (*akamaiV1alpha1.AkamaiEdgeKVNamespace, ctrl.Result) {
+		t.Helper()
+		stub.calls = nil
+		result, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+		if err != nil {
+			t.Fatalf("Reconcile() error = %v", err)
+		}
+		var got akamaiV1alpha1.AkamaiEdgeKVNamespace
+		if err := r.Get(ctx, key, &got); err != nil {
+			t.Fatalf("failed to get EdgeKV namespace: %v", err)
+		}
+		return &got, result
+	}
+
+	// EdgeKV is initialized for the account before any namespace is created
+	got, result := reconcile()
+	if !slices.Equal(stub.calls, []string{"initialize"}) || got.Status.Phase != PhaseCreating ||
+		result.RequeueAfter != edgeKVInitializationPollInterval {
+		t.Errorf("calls = %q, status = %+v, expected EdgeKV to be initialized", stub.calls, got.Status)
+	}
+
+	// Once initialized, the namespaces are created staging first and seeded
+	stub.accountStatus = akamai.EdgeKVInitialized
+	got, result = reconcile()
+	expected := []string{
+		`create staging/redirects geo=""`,
+		"put staging/redirects/paths/legacy",
+		"put staging/redirects/paths/old",
+		`create production/redirects geo="EU"`,
+		"put production/redirects/paths/legacy",
+		"put production/redirects/paths/old",
+	}
+	if !slices.Equal(stub.calls, expected) {
+		t.Errorf("calls = %q, expected %q", stub.calls, expected)
+	}
+	if got.Status.Phase != PhaseReady || result.RequeueAfter != edgeKVNamespaceResyncInterval ||
+		got.Status.Production.GeoLocation != "EU" || got.Status.Staging.SeededItems != 2 {
+		t.Errorf("status = %+v, expected the seeded namespaces to be ready", got.Status)
+	}
+
+	// Items changed at runtime are kept unless the seed overwrites them
+	stub.items["production/redirects/paths/old"] = "/changed"
+	if got, _ = reconcile(); len(stub.calls) != 0 {
+		t.Errorf("calls = %q, expected the changed item to be kept", stub.calls)
+	}
+	got.Spec.Seed.Overwrite = true
+	got.Spec.RetentionSeconds = 86400
+	if err := r.Update(ctx, got); err != nil {
+		t.Fatalf("failed to update EdgeKV namespace: %v", err)
+	}
+	got, _ = reconcile()
+	expected = []string{
+		"update staging/redirects retention=86400",
+		"update production/redirects retention=86400",
+		"put production/redirects/paths/old",
+	}
+	if !slices.Equal(stub.calls, expected) || stub.items["production/redirects/paths/old"] != "/new" {
+		t.Errorf("calls = %q, expected %q", stub.calls, expected)
+	}
+	if len(got.Status.LastChanges) != 3 || got.Status.Staging.RetentionSeconds != 86400 {
+		t.Errorf("status = %+v, expected the retention and item updates", got.Status)
+	}
+}
+
+func TestEdgeKVNamespaceGeoLocationMismatch(t *testing.T) {
+	ctx := context.Background()
+	kv := &akamaiV1alpha1.AkamaiEdgeKVNamespace{
+		ObjectMeta: metav1.ObjectMeta{Name: "config", Generation: 1},
+		Spec:       akamaiV1alpha1.AkamaiEdgeKVNamespaceSpec{Networks: []string{"production"}, GeoLocation: "EU"},
+	}
+	stub := &edgeKVAPI{
+		accountStatus: akamai.EdgeKVInitialized,
+		namespaces:    map[string]edgeworkers.Namespace{"production/config": {Name: "config", GeoLocation: "JP"}},
+		items:         map[string]string{},
+	}
+	r := newEdgeKVNamespaceReconciler(t, stub, kv)
+	result, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: kv.Name}})
+	if err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	var got akamaiV1alpha1.AkamaiEdgeKVNamespace
+	if err := r.Get(ctx, types.NamespacedName{Name: kv.Name}, &got); err != nil {
+		t.Fatalf("failed to get EdgeKV namespace: %v", err)
+	}
+	if len(stub.calls) != 0 || got.Status.Phase != PhaseError || result.RequeueAfter != edgeKVNamespaceErrorRetryInterval {
+		t.Errorf("calls = %q, status = %+v, expected the geo location mismatch to be an error", stub.calls, got.Status)
+	}
+}
+
+func TestShardContainsAccountWide(t *testing.T) {
+	selectorShard, err := NewShard("news", "akamai.com/shard=news", nil)
+	if err != nil {
+		t.Fatalf("NewShard() error = %v", err)
+	}
+	contractShard, err := NewShard("sport", "", []string{"ctr_1-ABC"})
+	if err != nil {
+		t.Fatalf("NewShard() error = %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		shard    *Shard
+		labels   map[string]string
+		expected bool
+	}{
+		{name: "no shard", expected: true},
+		{name: "selector matches", shard: selectorShard, labels: map[string]string{"akamai.com/shard": "news"}, expected: true},
+		{name: "selector doesn't match", shard: selectorShard, labels: map[string]string{"akamai.com/shard": "sport"}},
+		{name: "contracts only", shard: contractShard, labels: map[string]string{"akamai.com/shard": "sport"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.shard.containsAccountWide(tt.labels); got != tt.expected {
+				t.Errorf("containsAccountWide() = %v, expected %v", got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/util/workqueue"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+	"github.com/mmz-srf/akamai-operator/pkg/akamai"
+)
+
+// defaultErrorRequeue is the retry delay of failed reconciles when no backoff is configured
+const defaultErrorRequeue = 2 * time.Minute
+
+// errorBackoffJitter is the maximum fraction added to each delay, so properties failing at the
+// same time don't retry in lockstep
+const errorBackoffJitter = 0.2
+
+// errorClass groups errors by how soon a retry can succeed
+type errorClass string
+
+const (
+	// errorClassTransient covers network errors, throttling, server errors and authentication
+	// failures, which a credentials or permission fix outside the spec resolves
+	errorClassTransient errorClass = "transient"
+
+	// errorClassConflict covers concurrent edits, which usually resolve within seconds
+	errorClassConflict errorClass = "conflict"
+
+	// errorClassRejected covers requests Akamai rejects as invalid; they fail the same way until
+	// the spec changes
+	errorClassRejected errorClass = "rejected"
+)
+
+// errorBackoffs are the first and the maximum retry delay of each error class
+var errorBackoffs = map[errorClass]struct{ base, max time.Duration }{
+	errorClassTransient: {base: 30 * time.Second, max: 30 * time.Minute},
+	errorClassConflict:  {base: 5 * time.Second, max: 2 * time.Minute},
+	errorClassRejected:  {base: 2 * time.Minute, max: 6 * time.Hour},
+}
+
+// classifyError returns the class of an error returned while reconciling. Errors that are not
+// Akamai API responses, e.g. network errors, are transient. Hostname conflicts need a spec change
+// or the hostname to be moved off the other property, so they back off like rejected requests.
+func classifyError(err error) errorClass {
+	if errors.Is(err, akamai.ErrHostnameConflict) {
+		return errorClassRejected
+	}
+	apiErr, ok := akamai.AsAPIError(err)
+	if !ok {
+		return errorClassTransient
+	}
+	switch {
+	case apiErr.StatusCode == http.StatusConflict || apiErr.StatusCode == http.StatusPreconditionFailed:
+		return errorClassConflict
+	case apiErr.StatusCode == http.StatusTooManyRequests || apiErr.StatusCode >= http.StatusInternalServerError,
+		apiErr.StatusCode == http.StatusUnauthorized || apiErr.StatusCode == http.StatusForbidden:
+		return errorClassTransient
+	case apiErr.StatusCode >= http.StatusBadRequest:
+		return errorClassRejected
+	default:
+		return errorClassTransient
+	}
+}
+
+// ErrorBackoff computes the retry delay of failed reconciles. The delay grows exponentially with
+// the consecutive failures of a property in the same error class, using the workqueue rate
+// limiters, and gets jitter added. A successful reconcile or a new generation resets it.
+type ErrorBackoff struct {
+	limiters map[errorClass]workqueue.TypedRateLimiter[string]
+
+	mu          sync.Mutex
+	generations map[string]int64
+}
+
+// NewErrorBackoff creates a backoff with the delays of errorBackoffs
+func NewErrorBackoff() *ErrorBackoff {
+	limiters := make(map[errorClass]workqueue.TypedRateLimiter[string], len(errorBackoffs))
+	for class, delays := range errorBackoffs {
+		limiters[class] = workqueue.NewTypedItemExponentialFailureRateLimiter[string](delays.base, delays.max)
+	}
+	return &ErrorBackoff{limiters: limiters, generations: make(map[string]int64)}
+}
+
+// Next records a failure of the item at the given generation and returns the delay before
+// retrying it. The first failure of a new generation starts over at the base delay, since the
+// spec change may have fixed the cause. A nil backoff always returns defaultErrorRequeue.
+func (b *ErrorBackoff) Next(item string, generation int64, err error) time.Duration {
+	if b == nil {
+		return defaultErrorRequeue
+	}
+	b.mu.Lock()
+	if last, ok := b.generations[item]; ok && last != generation {
+		b.forget(item)
+	}
+	b.generations[item] = generation
+	b.mu.Unlock()
+	return wait.Jitter(b.limiters[classifyError(err)].When(item), errorBackoffJitter)
+}
+
+// Forget resets the backoff of the item after a successful reconcile
+func (b *ErrorBackoff) Forget(item string) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.forget(item)
+	delete(b.generations, item)
+}
+
+func (b *ErrorBackoff) forget(item string) {
+	for _, limiter := range b.limiters {
+		limiter.Forget(item)
+	}
+}
+
+// retryAfterError returns the result requeueing the property after a failed step. Throttled
+// requests are retried exactly when the account's rate limit accepts requests again, without
+// growing the backoff.
+func (r *AkamaiPropertyReconciler) retryAfterError(akamaiProperty *akamaiV1alpha1.AkamaiProperty, err error) ctrl.Result {
+	if limited, ok := r.AkamaiClient.AsRateLimited(err); ok {
+		return ctrl.Result{RequeueAfter: limited.RetryAfter}
+	}
+	return ctrl.Result{RequeueAfter: r.Backoff.Next(akamaiProperty.Name, akamaiProperty.Generation, err)}
+}
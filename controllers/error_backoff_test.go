@@ -0,0 +1,101 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v8/pkg/papi"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+	"github.com/mmz-srf/akamai-operator/pkg/akamai"
+)
+
+func TestClassifyError(t *testing.T) {
+	apiError := func(status int) error {
+		return fmt.Errorf("failed to update property rules: %w", &papi.Error{StatusCode: status})
+	}
+
+	tests := []struct {
+		name     string
+		err      error
+		expected errorClass
+	}{
+		{name: "network error", err: errors.New("dial tcp: connection refused"), expected: errorClassTransient},
+		{name: "server error", err: apiError(http.StatusBadGateway), expected: errorClassTransient},
+		{name: "throttled", err: apiError(http.StatusTooManyRequests), expected: errorClassTransient},
+		{name: "concurrent edit", err: apiError(http.StatusConflict), expected: errorClassConflict},
+		{name: "stale etag", err: apiError(http.StatusPreconditionFailed), expected: errorClassConflict},
+		{name: "invalid rules", err: apiError(http.StatusBadRequest), expected: errorClassRejected},
+		{name: "unknown property", err: apiError(http.StatusNotFound), expected: errorClassRejected},
+		{name: "expired credentials", err: apiError(http.StatusUnauthorized), expected: errorClassTransient},
+		{name: "missing permission", err: apiError(http.StatusForbidden), expected: errorClassTransient},
+		{name: "hostname conflict", err: fmt.Errorf("%w: www.example.com is active on other (prp_2)", akamai.ErrHostnameConflict), expected: errorClassRejected},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := classifyError(tt.err); got != tt.expected {
+				t.Errorf("classifyError() = %q, expected %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestErrorBackoff(t *testing.T) {
+	rejected := &papi.Error{StatusCode: http.StatusBadRequest}
+	inRange := func(got, expected time.Duration) bool {
+		return got >= expected && got <= time.Duration(float64(expected)*(1+errorBackoffJitter))
+	}
+
+	backoff := NewErrorBackoff()
+	expected := errorBackoffs[errorClassRejected].base
+	for i := 0; i < 3; i++ {
+		if got := backoff.Next("example", 1, rejected); !inRange(got, expected) {
+			t.Errorf("failure %d: Next() = %s, expected %s plus jitter", i+1, got, expected)
+		}
+		expected *= 2
+	}
+
+	// Each class and each property backs off on its own
+	if got := backoff.Next("example", 1, errors.New("timeout")); !inRange(got, errorBackoffs[errorClassTransient].base) {
+		t.Errorf("first transient failure: Next() = %s", got)
+	}
+	if got := backoff.Next("other", 1, rejected); !inRange(got, errorBackoffs[errorClassRejected].base) {
+		t.Errorf("first failure of another property: Next() = %s", got)
+	}
+
+	for i := 0; i < 20; i++ {
+		backoff.Next("example", 1, rejected)
+	}
+	if got := backoff.Next("example", 1, rejected); !inRange(got, errorBackoffs[errorClassRejected].max) {
+		t.Errorf("Next() = %s, expected the maximum of %s plus jitter", got, errorBackoffs[errorClassRejected].max)
+	}
+
+	backoff.Forget("example")
+	if got := backoff.Next("example", 1, rejected); !inRange(got, errorBackoffs[errorClassRejected].base) {
+		t.Errorf("after Forget: Next() = %s, expected %s plus jitter", got, errorBackoffs[errorClassRejected].base)
+	}
+
+	// A new generation starts over at the base delay
+	backoff.Next("example", 1, rejected)
+	if got := backoff.Next("example", 2, rejected); !inRange(got, errorBackoffs[errorClassRejected].base) {
+		t.Errorf("new generation: Next() = %s, expected %s plus jitter", got, errorBackoffs[errorClassRejected].base)
+	}
+
+	// Throttled requests are retried when the rate limit allows, without growing the backoff
+	r := &AkamaiPropertyReconciler{Backoff: NewErrorBackoff()}
+	property := &akamaiV1alpha1.AkamaiProperty{ObjectMeta: metav1.ObjectMeta{Name: "example"}}
+	throttled := fmt.Errorf("failed to update property rules: %w", &akamai.RateLimitedError{RetryAfter: 17 * time.Second})
+	if got := r.retryAfterError(property, throttled); got.RequeueAfter != 17*time.Second {
+		t.Errorf("throttled: retryAfterError() = %s, expected 17s", got.RequeueAfter)
+	}
+
+	var unset *ErrorBackoff
+	if got := unset.Next("example", 1, rejected); got != defaultErrorRequeue {
+		t.Errorf("nil backoff: Next() = %s, expected %s", got, defaultErrorRequeue)
+	}
+}
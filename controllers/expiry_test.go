@@ -0,0 +1,135 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/events"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+)
+
+func TestHandleExpiry(t *testing.T) {
+	ctx := context.Background()
+	created := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	now := created.Add(10 * 24 * time.Hour)
+	at := func(t time.Time) *metav1.Time {
+		value := metav1.NewTime(t)
+		return &value
+	}
+
+	tests := []struct {
+		name      string
+		expiresAt *metav1.Time
+		ttl       *metav1.Duration
+		dryRun    bool
+		expired   bool
+		expiring  bool
+		event     string
+	}{
+		{name: "permanent property"},
+		{name: "expires later", expiresAt: at(now.Add(72 * time.Hour))},
+		{name: "expires soon", expiresAt: at(now.Add(time.Hour)), expiring: true, event: ReasonExpiringSoon},
+		{name: "ttl running out", ttl: &metav1.Duration{Duration: 10*24*time.Hour + time.Hour}, expiring: true, event: ReasonExpiringSoon},
+		{name: "expired", expiresAt: at(now.Add(-time.Minute)), expired: true, event: ReasonExpired},
+		{name: "ttl expired", ttl: &metav1.Duration{Duration: 7 * 24 * time.Hour}, expired: true, event: ReasonExpired},
+		{name: "expired dry run", expiresAt: at(now.Add(-time.Minute)), dryRun: true, expiring: true, event: ReasonExpired},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			property := &akamaiV1alpha1.AkamaiProperty{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:              "campaign",
+					Generation:        1,
+					CreationTimestamp: metav1.NewTime(created),
+					Finalizers:        []string{FinalizerName},
+				},
+				Spec: akamaiV1alpha1.AkamaiPropertySpec{PropertyName: "campaign.example.com", ExpiresAt: tt.expiresAt, TTL: tt.ttl, DryRun: tt.dryRun},
+			}
+			r := newFakeReconciler(t, property)
+			recorder := events.NewFakeRecorder(10)
+			r.Recorder = recorder
+
+			expired, err := r.handleExpiry(ctx, property, now)
+			if err != nil {
+				t.Fatalf("handleExpiry() unexpected error: %v", err)
+			}
+			if expired != tt.expired {
+				t.Errorf("handleExpiry() = %v, expected %v", expired, tt.expired)
+			}
+
+			var stored akamaiV1alpha1.AkamaiProperty
+			if err := r.Get(ctx, client.ObjectKeyFromObject(property), &stored); err != nil {
+				t.Fatalf("failed to get property: %v", err)
+			}
+			if deleting := stored.DeletionTimestamp != nil; deleting != tt.expired {
+				t.Errorf("deletion requested = %v, expected %v", deleting, tt.expired)
+			}
+			if !tt.expired {
+				if got := meta.IsStatusConditionTrue(stored.Status.Conditions, ConditionTypeExpiring); got != tt.expiring {
+					t.Errorf("Expiring condition = %v, expected %v", got, tt.expiring)
+				}
+				if (stored.Status.ExpiresAt != nil) != (tt.expiresAt != nil || tt.ttl != nil) {
+					t.Errorf("status.expiresAt = %v", stored.Status.ExpiresAt)
+				}
+			}
+
+			select {
+			case event := <-recorder.Events:
+				if tt.event == "" {
+					t.Errorf("unexpected event %q", event)
+				}
+			default:
+				if tt.event != "" {
+					t.Errorf("expected a %s event", tt.event)
+				}
+			}
+
+			// The warning is emitted once
+			if tt.expiring {
+				if _, err := r.handleExpiry(ctx, property, now.Add(time.Minute)); err != nil {
+					t.Fatalf("handleExpiry() unexpected error: %v", err)
+				}
+				if len(recorder.Events) != 0 {
+					t.Errorf("expected no second warning, got %q", <-recorder.Events)
+				}
+			}
+		})
+	}
+}
+
+func TestExpiryRequeue(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	property := func(expiresIn time.Duration) *akamaiV1alpha1.AkamaiProperty {
+		expiresAt := metav1.NewTime(now.Add(expiresIn))
+		return &akamaiV1alpha1.AkamaiProperty{Spec: akamaiV1alpha1.AkamaiPropertySpec{ExpiresAt: &expiresAt}}
+	}
+	r := &AkamaiPropertyReconciler{ExpiryWarning: time.Hour}
+
+	tests := []struct {
+		name     string
+		property *akamaiV1alpha1.AkamaiProperty
+		result   ctrl.Result
+		expected time.Duration
+	}{
+		{name: "permanent property", property: &akamaiV1alpha1.AkamaiProperty{}, result: ctrl.Result{RequeueAfter: time.Minute}, expected: time.Minute},
+		{name: "warning due first", property: property(3 * time.Hour), expected: 2*time.Hour + time.Second},
+		{name: "expiry due first", property: property(30 * time.Minute), expected: 30*time.Minute + time.Second},
+		{name: "earlier requeue kept", property: property(30 * time.Minute), result: ctrl.Result{RequeueAfter: 30 * time.Second}, expected: 30 * time.Second},
+		{name: "expired in read-only mode", property: property(-time.Hour), result: ctrl.Result{RequeueAfter: 5 * time.Minute}, expected: 5 * time.Minute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := r.expiryRequeue(tt.property, tt.result, now); got.RequeueAfter != tt.expected {
+				t.Errorf("expiryRequeue() = %s, expected %s", got.RequeueAfter, tt.expected)
+			}
+		})
+	}
+}
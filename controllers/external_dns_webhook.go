@@ -0,0 +1,289 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v8/pkg/dns"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/mmz-srf/akamai-operator/pkg/akamai"
+)
+
+// externalDNSMediaType is the media type of the external-dns webhook provider protocol
+const externalDNSMediaType = "application/external.dns.webhook+json;version=1"
+
+// externalDNSRecordTypes are the record types the webhook provider manages
+var externalDNSRecordTypes = []string{"A", "AAAA", "CNAME", "TXT"}
+
+// ExternalDNSWebhook serves the external-dns webhook provider protocol over HTTP, so a running
+// external-dns can manage the records of Edge DNS zones, e.g. the CNAMEs of property hostnames
+// to their edge hostnames. external-dns doesn't authenticate to its webhook, so the server only
+// listens on loopback addresses, where an external-dns sidecar in the operator pod reaches it.
+type ExternalDNSWebhook struct {
+	AkamaiClient *akamai.Client
+
+	// Credentials selects the credential source the Akamai client is created with
+	Credentials akamai.Credentials
+
+	// BindAddress is the address the server listens on, e.g. ":8888". An empty host binds to
+	// 127.0.0.1; other hosts must be loopback addresses.
+	BindAddress string
+
+	// Zones are the Edge DNS zones external-dns may manage records in
+	Zones []string
+
+	// ObserveOnly serves the records and logs the planned changes without applying them
+	ObserveOnly bool
+}
+
+// externalDNSEndpoint is a record set as external-dns describes it
+type externalDNSEndpoint struct {
+	DNSName       string   `json:"dnsName"`
+	Targets       []string `json:"targets"`
+	RecordType    string   `json:"recordType"`
+	SetIdentifier string   `json:"setIdentifier,omitempty"`
+	RecordTTL     int64    `json:"recordTTL,omitempty"`
+}
+
+// externalDNSChanges are the record set changes external-dns plans
+type externalDNSChanges struct {
+	Create    []*externalDNSEndpoint `json:"create,omitempty"`
+	UpdateOld []*externalDNSEndpoint `json:"updateOld,omitempty"`
+	UpdateNew []*externalDNSEndpoint `json:"updateNew,omitempty"`
+	Delete    []*externalDNSEndpoint `json:"delete,omitempty"`
+}
+
+// externalDNSDomainFilter tells external-dns the domains the provider is responsible for
+type externalDNSDomainFilter struct {
+	Include []string `json:"include"`
+}
+
+// NeedLeaderElection reports that the webhook is served by every replica; external-dns itself
+// makes sure only one instance applies changes
+func (s *ExternalDNSWebhook) NeedLeaderElection() bool {
+	return false
+}
+
+// Start serves the webhook provider until the context is cancelled
+func (s *ExternalDNSWebhook) Start(ctx context.Context) error {
+	logger := log.FromContext(ctx).WithName("external-dns-webhook")
+	if s.AkamaiClient == nil {
+		akamaiClient, err := akamai.NewClient(s.Credentials)
+		if err != nil {
+			return fmt.Errorf("failed to create Akamai client: %w", err)
+		}
+		s.AkamaiClient = akamaiClient
+	}
+	address, err := loopbackBindAddress(s.BindAddress)
+	if err != nil {
+		return err
+	}
+	server := &http.Server{
+		Addr:              address,
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+		BaseContext:       func(_ net.Listener) context.Context { return log.IntoContext(ctx, logger) },
+	}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			logger.Error(err, "Failed to shut down external-dns webhook")
+		}
+	}()
+
+	logger.Info("Serving external-dns webhook provider", "address", address, "zones", s.Zones)
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// loopbackBindAddress returns the bind address with an empty host replaced by 127.0.0.1, and
+// refuses hosts that aren't loopback addresses, since the webhook has no authentication
+func loopbackBindAddress(bindAddress string) (string, error) {
+	host, port, err := net.SplitHostPort(bindAddress)
+	if err != nil {
+		return "", fmt.Errorf("invalid external-dns webhook bind address %q: %w", bindAddress, err)
+	}
+	if host == "" {
+		return net.JoinHostPort("127.0.0.1", port), nil
+	}
+	if host != "localhost" {
+		if ip := net.ParseIP(host); ip == nil || !ip.IsLoopback() {
+			return "", fmt.Errorf("external-dns webhook bind address %q is not a loopback address; the webhook is unauthenticated", bindAddress)
+		}
+	}
+	return bindAddress, nil
+}
+
+// Handler returns the HTTP handler of the webhook provider:
+//
+//	GET  /                 the zones as domain filter
+//	GET  /records          the A, AAAA, CNAME and TXT record sets of the zones
+//	POST /records          apply the planned changes
+//	POST /adjustendpoints  the desired record sets in the form the provider returns them
+//	GET  /healthz          liveness
+func (s *ExternalDNSWebhook) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{$}", s.negotiate)
+	mux.HandleFunc("GET /records", s.records)
+	mux.HandleFunc("POST /records", s.applyChanges)
+	mux.HandleFunc("POST /adjustendpoints", s.adjustEndpoints)
+	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	return mux
+}
+
+// negotiate answers the handshake of external-dns with the domains of the zones
+func (s *ExternalDNSWebhook) negotiate(w http.ResponseWriter, req *http.Request) {
+	writeExternalDNS(req.Context(), w, externalDNSDomainFilter{Include: s.Zones})
+}
+
+// records serves the record sets of all zones
+func (s *ExternalDNSWebhook) records(w http.ResponseWriter, req *http.Request) {
+	ctx := req.Context()
+	endpoints := []*externalDNSEndpoint{}
+	for _, zone := range s.Zones {
+		recordSets, err := s.AkamaiClient.ListDNSRecords(ctx, zone, externalDNSRecordTypes)
+		if err != nil {
+			log.FromContext(ctx).Error(err, "Failed to list records", "zone", zone)
+			http.Error(w, fmt.Sprintf("failed to list records of zone %s", zone), http.StatusInternalServerError)
+			return
+		}
+		for _, recordSet := range recordSets {
+			endpoints = append(endpoints, externalDNSEndpointOf(recordSet))
+		}
+	}
+	writeExternalDNS(ctx, w, endpoints)
+}
+
+// applyChanges applies the changes external-dns planned: deletions first, so a record set can
+// be recreated with another type, then creations and updates. All changes are attempted; the
+// failed ones are reported so external-dns retries them.
+func (s *ExternalDNSWebhook) applyChanges(w http.ResponseWriter, req *http.Request) {
+	ctx := req.Context()
+	var changes externalDNSChanges
+	if err := json.NewDecoder(req.Body).Decode(&changes); err != nil {
+		http.Error(w, fmt.Sprintf("invalid changes: %v", err), http.StatusBadRequest)
+		return
+	}
+	if s.ObserveOnly {
+		// external-dns keeps reporting the changes as pending instead of assuming they were made
+		log.FromContext(ctx).Info("Not applying external-dns changes in observe-only mode",
+			"create", len(changes.Create), "update", len(changes.UpdateNew), "delete", len(changes.Delete))
+		http.Error(w, "observe-only mode applies no changes to Edge DNS", http.StatusServiceUnavailable)
+		return
+	}
+
+	var problems []string
+	apply := func(action string, endpoints []*externalDNSEndpoint, change func(context.Context, string, *dns.RecordBody) error) {
+		for _, endpoint := range endpoints {
+			record := externalDNSRecordBody(endpoint)
+			zone := longestZoneSuffix(s.Zones, record.Name)
+			if zone == "" {
+				problems = append(problems, fmt.Sprintf("%s is in none of the zones", record.Name))
+				continue
+			}
+			if err := change(ctx, zone, record); err != nil {
+				problems = append(problems, err.Error())
+				continue
+			}
+			log.FromContext(ctx).Info("Applied external-dns change", "action", action, "zone", zone,
+				"name", record.Name, "type", record.RecordType)
+		}
+	}
+	apply("delete", changes.Delete, s.AkamaiClient.DeleteDNSRecord)
+	apply("create", changes.Create, s.AkamaiClient.CreateDNSRecord)
+	apply("update", changes.UpdateNew, s.AkamaiClient.UpdateDNSRecord)
+
+	if len(problems) > 0 {
+		log.FromContext(ctx).Info("Failed to apply external-dns changes", "problems", problems)
+		http.Error(w, strings.Join(problems, "; "), http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// adjustEndpoints returns the desired record sets the way records serves them, so external-dns
+// doesn't plan changes for differences Edge DNS doesn't keep: names and hostnames are lowercased
+// without the trailing dot and a missing TTL becomes the default TTL. Record sets of other types,
+// outside the zones or with a routing policy are dropped.
+func (s *ExternalDNSWebhook) adjustEndpoints(w http.ResponseWriter, req *http.Request) {
+	var endpoints []*externalDNSEndpoint
+	if err := json.NewDecoder(req.Body).Decode(&endpoints); err != nil {
+		http.Error(w, fmt.Sprintf("invalid endpoints: %v", err), http.StatusBadRequest)
+		return
+	}
+	adjusted := []*externalDNSEndpoint{}
+	for _, endpoint := range endpoints {
+		endpoint.DNSName = strings.TrimSuffix(strings.ToLower(endpoint.DNSName), ".")
+		if !slices.Contains(externalDNSRecordTypes, endpoint.RecordType) || endpoint.SetIdentifier != "" ||
+			longestZoneSuffix(s.Zones, endpoint.DNSName) == "" {
+			continue
+		}
+		if endpoint.RecordTTL <= 0 {
+			endpoint.RecordTTL = defaultDNSRecordTTL
+		}
+		for i, target := range endpoint.Targets {
+			endpoint.Targets[i] = externalDNSTarget(endpoint.RecordType, target)
+		}
+		adjusted = append(adjusted, endpoint)
+	}
+	writeExternalDNS(req.Context(), w, adjusted)
+}
+
+// externalDNSEndpointOf converts an Edge DNS record set into an endpoint
+func externalDNSEndpointOf(recordSet dns.RecordSet) *externalDNSEndpoint {
+	endpoint := &externalDNSEndpoint{DNSName: recordSet.Name, RecordType: recordSet.Type, RecordTTL: int64(recordSet.TTL)}
+	for _, target := range recordSet.Rdata {
+		endpoint.Targets = append(endpoint.Targets, externalDNSTarget(recordSet.Type, target))
+	}
+	return endpoint
+}
+
+// externalDNSRecordBody converts an endpoint into an Edge DNS record set
+func externalDNSRecordBody(endpoint *externalDNSEndpoint) *dns.RecordBody {
+	record := &dns.RecordBody{
+		Name:       strings.TrimSuffix(strings.ToLower(endpoint.DNSName), "."),
+		RecordType: endpoint.RecordType,
+		TTL:        int(endpoint.RecordTTL),
+	}
+	if record.TTL <= 0 {
+		record.TTL = defaultDNSRecordTTL
+	}
+	for _, target := range endpoint.Targets {
+		record.Target = append(record.Target, externalDNSTarget(endpoint.RecordType, target))
+	}
+	return record
+}
+
+// externalDNSTarget returns the canonical form of a target with texts quoted, the form
+// external-dns writes the texts of its TXT registry in
+func externalDNSTarget(recordType, target string) string {
+	target = normalizeDNSTarget(recordType, target)
+	if recordType == "TXT" {
+		return `"` + strings.ReplaceAll(target, `"`, `\"`) + `"`
+	}
+	return target
+}
+
+// writeExternalDNS writes a response in the media type of the webhook provider protocol
+func writeExternalDNS(ctx context.Context, w http.ResponseWriter, response interface{}) {
+	w.Header().Set("Content-Type", externalDNSMediaType)
+	w.Header().Set("Vary", "Content-Type")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to write external-dns response")
+	}
+}
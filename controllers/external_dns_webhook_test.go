@@ -0,0 +1,158 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v8/pkg/dns"
+
+	"github.com/mmz-srf/akamai-operator/pkg/akamai"
+)
+
+func TestExternalDNSWebhook(t *testing.T) {
+	stub := &dnsRecordsAPI{records: map[string]*dns.RecordBody{
+		"example.com/example.com/SOA":      {Name: "example.com", RecordType: "SOA", TTL: 86400, Target: []string{"a1.akam.net."}},
+		"example.com/www.example.com/A":    {Name: "www.example.com", RecordType: "A", TTL: 300, Target: []string{"192.0.2.1"}},
+		"example.com/txt.example.com/TXT":  {Name: "txt.example.com", RecordType: "TXT", TTL: 300, Target: []string{`"heritage=external-dns," "external-dns/owner=default"`}},
+		"example.org/shop.example.org/A":   {Name: "shop.example.org", RecordType: "A", TTL: 300, Target: []string{"192.0.2.9"}},
+		"example.com/old.example.com/AAAA": {Name: "old.example.com", RecordType: "AAAA", TTL: 60, Target: []string{"2001:0db8:0000:0000:0000:0000:0000:0001"}},
+	}}
+	webhook := &ExternalDNSWebhook{AkamaiClient: akamai.NewClientWithDNS(stub), Zones: []string{"example.com"}}
+	server := httptest.NewServer(webhook.Handler())
+	defer server.Close()
+
+	request := func(method, path, body string) *http.Response {
+		t.Helper()
+		req, err := http.NewRequest(method, server.URL+path, strings.NewReader(body))
+		if err != nil {
+			t.Fatalf("failed to build request: %v", err)
+		}
+		req.Header.Set("Accept", externalDNSMediaType)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("%s %s failed: %v", method, path, err)
+		}
+		t.Cleanup(func() { _ = resp.Body.Close() })
+		return resp
+	}
+
+	// The handshake announces the zones as domain filter
+	resp := request(http.MethodGet, "/", "")
+	var filter externalDNSDomainFilter
+	if err := json.NewDecoder(resp.Body).Decode(&filter); err != nil || !slices.Equal(filter.Include, []string{"example.com"}) {
+		t.Errorf("domain filter = %+v (%v), expected the zones", filter, err)
+	}
+	if resp.Header.Get("Content-Type") != externalDNSMediaType {
+		t.Errorf("Content-Type = %q, expected %q", resp.Header.Get("Content-Type"), externalDNSMediaType)
+	}
+
+	// Records lists the managed types of the zones, with texts joined and addresses shortened
+	var records []*externalDNSEndpoint
+	if err := json.NewDecoder(request(http.MethodGet, "/records", "").Body).Decode(&records); err != nil {
+		t.Fatalf("failed to decode records: %v", err)
+	}
+	expected := []*externalDNSEndpoint{
+		{DNSName: "old.example.com", RecordType: "AAAA", RecordTTL: 60, Targets: []string{"2001:db8::1"}},
+		{DNSName: "txt.example.com", RecordType: "TXT", RecordTTL: 300, Targets: []string{`"heritage=external-dns,external-dns/owner=default"`}},
+		{DNSName: "www.example.com", RecordType: "A", RecordTTL: 300, Targets: []string{"192.0.2.1"}},
+	}
+	if !reflect.DeepEqual(records, expected) {
+		t.Errorf("records = %+v, expected %+v", records, expected)
+	}
+
+	// Endpoints are adjusted to the form of the records and those of other zones dropped
+	var adjusted []*externalDNSEndpoint
+	resp = request(http.MethodPost, "/adjustendpoints", `[
+		{"dnsName": "Shop.Example.com.", "recordType": "CNAME", "targets": ["shop.example.com.edgekey.net."]},
+		{"dnsName": "shop.example.org", "recordType": "CNAME", "targets": ["shop.example.org.edgekey.net"]},
+		{"dnsName": "mail.example.com", "recordType": "MX", "targets": ["10 mx.example.com"]}
+	]`)
+	if err := json.NewDecoder(resp.Body).Decode(&adjusted); err != nil {
+		t.Fatalf("failed to decode adjusted endpoints: %v", err)
+	}
+	expected = []*externalDNSEndpoint{
+		{DNSName: "shop.example.com", RecordType: "CNAME", RecordTTL: 300, Targets: []string{"shop.example.com.edgekey.net"}},
+	}
+	if !reflect.DeepEqual(adjusted, expected) {
+		t.Errorf("adjusted endpoints = %+v, expected %+v", adjusted, expected)
+	}
+
+	// Changes are applied with deletions first
+	resp = request(http.MethodPost, "/records", `{
+		"create": [{"dnsName": "shop.example.com", "recordType": "CNAME", "recordTTL": 300, "targets": ["shop.example.com.edgekey.net"]}],
+		"updateOld": [{"dnsName": "www.example.com", "recordType": "A", "recordTTL": 300, "targets": ["192.0.2.1"]}],
+		"updateNew": [{"dnsName": "www.example.com", "recordType": "A", "recordTTL": 300, "targets": ["192.0.2.2"]}],
+		"delete": [{"dnsName": "old.example.com", "recordType": "AAAA", "recordTTL": 60, "targets": ["2001:db8::1"]}]
+	}`)
+	if resp.StatusCode != http.StatusNoContent {
+		t.Errorf("status = %d, expected %d", resp.StatusCode, http.StatusNoContent)
+	}
+	expectedCalls := []string{"delete old.example.com AAAA", "create shop.example.com CNAME", "update www.example.com A"}
+	if !slices.Equal(stub.calls, expectedCalls) {
+		t.Errorf("calls = %q, expected %q", stub.calls, expectedCalls)
+	}
+	if target := stub.records["example.com/www.example.com/A"].Target; !slices.Equal(target, []string{"192.0.2.2"}) {
+		t.Errorf("targets = %q, expected the updated address", target)
+	}
+
+	// Changes outside the zones fail without blocking the others
+	stub.calls = nil
+	resp = request(http.MethodPost, "/records", `{
+		"Create": [
+			{"dnsName": "shop.example.org", "recordType": "A", "targets": ["192.0.2.10"]},
+			{"dnsName": "api.example.com", "recordType": "TXT", "targets": ["verification=1"]}
+		]
+	}`)
+	if resp.StatusCode != http.StatusInternalServerError {
+		t.Errorf("status = %d, expected %d", resp.StatusCode, http.StatusInternalServerError)
+	}
+	if created := stub.records["example.com/api.example.com/TXT"]; created == nil || created.TTL != 300 || created.Target[0] != `"verification=1"` {
+		t.Errorf("record = %+v, expected a quoted text with the default TTL", created)
+	}
+
+	// Observe-only mode serves the records but applies no changes
+	stub.calls = nil
+	webhook.ObserveOnly = true
+	resp = request(http.MethodPost, "/records", `{
+		"delete": [{"dnsName": "www.example.com", "recordType": "A", "recordTTL": 300, "targets": ["192.0.2.2"]}]
+	}`)
+	if resp.StatusCode != http.StatusServiceUnavailable || len(stub.calls) != 0 {
+		t.Errorf("status = %d, calls = %q, expected no changes in observe-only mode", resp.StatusCode, stub.calls)
+	}
+	if resp = request(http.MethodGet, "/records", ""); resp.StatusCode != http.StatusOK {
+		t.Errorf("status = %d, expected the records served in observe-only mode", resp.StatusCode)
+	}
+}
+
+func TestLoopbackBindAddress(t *testing.T) {
+	tests := []struct {
+		bindAddress string
+		expected    string
+		wantErr     bool
+	}{
+		{bindAddress: ":8888", expected: "127.0.0.1:8888"},
+		{bindAddress: "127.0.0.1:8888", expected: "127.0.0.1:8888"},
+		{bindAddress: "[::1]:8888", expected: "[::1]:8888"},
+		{bindAddress: "localhost:8888", expected: "localhost:8888"},
+		{bindAddress: "0.0.0.0:8888", wantErr: true},
+		{bindAddress: "10.0.0.5:8888", wantErr: true},
+		{bindAddress: "8888", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.bindAddress, func(t *testing.T) {
+			address, err := loopbackBindAddress(tt.bindAddress)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("loopbackBindAddress() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if address != tt.expected {
+				t.Errorf("loopbackBindAddress() = %q, expected %q", address, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package controllers
+
+import (
+	"strings"
+	"testing"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+	"github.com/mmz-srf/akamai-operator/pkg/akamai"
+)
+
+func TestIsForeignVersion(t *testing.T) {
+	tests := []struct {
+		name     string
+		policy   akamaiV1alpha1.ForeignVersionPolicy
+		strategy akamaiV1alpha1.VersionStrategy
+		note     string
+		staging  string
+		expected bool
+	}{
+		{name: "guard disabled by default", note: "Managed by Terraform", expected: false},
+		{name: "overwrite ignores foreign notes", policy: akamaiV1alpha1.ForeignVersionPolicyOverwrite, note: "Managed by Terraform", expected: false},
+		{name: "refuse detects missing marker", policy: akamaiV1alpha1.ForeignVersionPolicyRefuse, note: "Managed by Terraform", expected: true},
+		{name: "create version detects empty notes", policy: akamaiV1alpha1.ForeignVersionPolicyCreateVersion, expected: true},
+		{name: "marker on its own line", policy: akamaiV1alpha1.ForeignVersionPolicyRefuse, note: "Shop frontend\n\n" + versionNotesManagedByMarker, expected: false},
+		{name: "marker inside other text", policy: akamaiV1alpha1.ForeignVersionPolicyRefuse, note: "not " + versionNotesManagedByMarker, expected: true},
+		{name: "published versions are never foreign", policy: akamaiV1alpha1.ForeignVersionPolicyRefuse, staging: "ACTIVE", expected: false},
+		{name: "manual strategy disables the guard", policy: akamaiV1alpha1.ForeignVersionPolicyRefuse, strategy: akamaiV1alpha1.VersionStrategyManual, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			property := &akamaiV1alpha1.AkamaiProperty{
+				Spec: akamaiV1alpha1.AkamaiPropertySpec{ForeignVersionPolicy: tt.policy, VersionStrategy: tt.strategy},
+			}
+			version := &akamai.PropertyVersion{Note: tt.note, StagingStatus: tt.staging, ProductionStatus: "INACTIVE"}
+			if got := isForeignVersion(property, version); got != tt.expected {
+				t.Errorf("isForeignVersion() = %v, expected %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRenderVersionNotesManagedByMarker(t *testing.T) {
+	property := &akamaiV1alpha1.AkamaiProperty{
+		Spec: akamaiV1alpha1.AkamaiPropertySpec{Description: "Shop frontend"},
+	}
+	if notes := renderVersionNotes(property); strings.Contains(notes, versionNotesManagedByMarker) {
+		t.Errorf("renderVersionNotes() = %q, expected no marker without a foreign version policy", notes)
+	}
+
+	property.Spec.ForeignVersionPolicy = akamaiV1alpha1.ForeignVersionPolicyRefuse
+	expected := "Shop frontend\n\n" + versionNotesManagedByMarker
+	if notes := renderVersionNotes(property); notes != expected {
+		t.Errorf("renderVersionNotes() = %q, expected %q", notes, expected)
+	}
+	if !hasManagedByMarker(renderVersionNotes(property)) {
+		t.Error("rendered notes are not recognized as managed by the operator")
+	}
+}
@@ -0,0 +1,947 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/events"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+)
+
+const (
+	// GatewayFinalizerName is the finalizer added to the Gateways an AkamaiProperty is synthesized
+	// for, so the property is deleted with the Gateway
+	GatewayFinalizerName = "akamai.com/gateway-finalizer"
+
+	// GatewayNamespaceLabel and GatewayNameLabel record the Gateway an AkamaiProperty is
+	// synthesized for
+	GatewayNamespaceLabel = "akamai.com/gateway-namespace"
+	GatewayNameLabel      = "akamai.com/gateway-name"
+
+	// gatewayAPIGroup is the API group of the Gateway API
+	gatewayAPIGroup = "gateway.networking.k8s.io"
+)
+
+var (
+	gatewayClassGVK = schema.GroupVersionKind{Group: gatewayAPIGroup, Version: "v1", Kind: "GatewayClass"}
+	gatewayGVK      = schema.GroupVersionKind{Group: gatewayAPIGroup, Version: "v1", Kind: "Gateway"}
+	httpRouteGVK    = schema.GroupVersionKind{Group: gatewayAPIGroup, Version: "v1", Kind: "HTTPRoute"}
+)
+
+// GatewayReconciler implements the Gateway API for the GatewayClasses of its controller name:
+// each Gateway becomes an AkamaiProperty with an edge hostname, and the HTTPRoutes attached to
+// it compile to child rules of the property's rule tree. Akamai serves the traffic; the backends
+// of the routes are its origins, so they need an address reachable from the internet.
+type GatewayReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+
+	// ControllerName is the spec.controllerName of the GatewayClasses the operator implements
+	ControllerName string
+
+	// EdgeHostnameTemplate renders the domain prefix of the edge hostnames, like
+	// --edge-hostname-template; empty uses the name of the property
+	EdgeHostnameTemplate string
+
+	// AccountNamespaces are the namespaces whose Gateways may choose the contract, group,
+	// product and CPS certificate of their property with annotations; the Gateways of other
+	// namespaces get the ones of their GatewayClass
+	AccountNamespaces []string
+
+	// PropagatedLabels are the keys of the Gateway labels copied onto the synthesized properties
+	PropagatedLabels []string
+
+	// Recorder emits events about the Gateways; nil discards them
+	Recorder events.EventRecorder
+
+	// Shard is the part of the fleet this instance manages; nil manages all Gateways
+	Shard *Shard
+}
+
+// gatewayRoute is an HTTPRoute with the outcome of attaching it to a Gateway
+type gatewayRoute struct {
+	route *unstructured.Unstructured
+	spec  httpRouteSpec
+
+	// parents are the parentRefs of the route pointing at the Gateway, with their conditions
+	parents []gatewayRouteParent
+
+	// hostnames are the hostnames the route is attached with; nil matches all
+	hostnames []string
+}
+
+// gatewayRouteParent is a parentRef of a route with its Accepted and ResolvedRefs conditions
+type gatewayRouteParent struct {
+	ref          gatewayParentReference
+	accepted     metav1.Condition
+	resolvedRefs metav1.Condition
+}
+
+// attached reports whether any parentRef of the route was accepted
+func (route *gatewayRoute) attached() bool {
+	for _, parent := range route.parents {
+		if parent.accepted.Status == metav1.ConditionTrue {
+			return true
+		}
+	}
+	return false
+}
+
+//+kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=gateways;httproutes,verbs=get;list;watch;update;patch
+//+kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=gateways/status;httproutes/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=gateways/finalizers,verbs=update
+//+kubebuilder:rbac:groups="",resources=services;namespaces,verbs=get;list;watch
+//+kubebuilder:rbac:groups=akamai.com,resources=akamaiproperties,verbs=get;list;watch;create;update;patch;delete
+
+// Reconcile creates, updates or deletes the AkamaiProperty of a Gateway and reports the outcome in
+// the status of the Gateway and its HTTPRoutes
+func (r *GatewayReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	gateway := &unstructured.Unstructured{}
+	gateway.SetGroupVersionKind(gatewayGVK)
+	if err := r.Get(ctx, req.NamespacedName, gateway); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+	var spec gatewaySpec
+	if err := decodeUnstructured(gateway, &spec, "spec"); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to decode Gateway %s: %w", req.NamespacedName, err)
+	}
+	class, err := r.gatewayClass(ctx, spec.GatewayClassName)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// Deleted Gateways and Gateways moved to another class give up their property and routes
+	if gateway.GetDeletionTimestamp() != nil || class == nil {
+		if !controllerutil.ContainsFinalizer(gateway, GatewayFinalizerName) {
+			return ctrl.Result{}, nil
+		}
+		if err := r.deleteProperty(ctx, gateway); err != nil {
+			return ctrl.Result{}, err
+		}
+		if err := r.releaseRoutes(ctx, gateway); err != nil {
+			return ctrl.Result{}, err
+		}
+		controllerutil.RemoveFinalizer(gateway, GatewayFinalizerName)
+		return ctrl.Result{}, r.Update(ctx, gateway)
+	}
+
+	// Gateways of other shards are left to the instances managing them
+	if !r.Shard.Contains(gateway.GetLabels(), gatewayAnnotation(gateway, class, AnnotationIngressContractID)) {
+		logger.V(1).Info("Gateway belongs to another shard", "shard", r.Shard.Name)
+		return ctrl.Result{}, nil
+	}
+
+	routes, err := r.attachRoutes(ctx, gateway, &spec)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	desired, err := r.gatewayProperty(ctx, gateway, class, &spec, routes)
+	if err != nil {
+		// Fixing the Gateway or its routes triggers the next reconcile
+		logger.Info("Can't synthesize an AkamaiProperty for the Gateway", "reason", err.Error())
+		r.event(gateway, "Warning", "InvalidGateway", "Synthesize", "%s", err)
+		programmed := metav1.Condition{Type: "Programmed", Status: metav1.ConditionFalse, Reason: "Invalid", Message: err.Error()}
+		if err := r.updateRouteStatuses(ctx, gateway, routes); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, r.updateGatewayStatus(ctx, gateway, &spec, routes, "", programmed)
+	}
+
+	// The finalizer is added before the property is created, so it is never left behind
+	if !controllerutil.ContainsFinalizer(gateway, GatewayFinalizerName) {
+		controllerutil.AddFinalizer(gateway, GatewayFinalizerName)
+		if err := r.Update(ctx, gateway); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	current, err := r.applyProperty(ctx, gateway, desired)
+	if err != nil {
+		programmed := metav1.Condition{Type: "Programmed", Status: metav1.ConditionFalse, Reason: "Invalid", Message: err.Error()}
+		if statusErr := r.updateGatewayStatus(ctx, gateway, &spec, routes, "", programmed); statusErr != nil {
+			logger.Error(statusErr, "Failed to update the status of the Gateway")
+		}
+		return ctrl.Result{}, err
+	}
+	if err := r.updateRouteStatuses(ctx, gateway, routes); err != nil {
+		return ctrl.Result{}, err
+	}
+	edgeHostname := desired.Spec.EdgeHostname.DomainPrefix + "." + desired.Spec.EdgeHostname.DomainSuffix
+	return ctrl.Result{}, r.updateGatewayStatus(ctx, gateway, &spec, routes, edgeHostname, propertyProgrammed(current))
+}
+
+// gatewayClass returns the GatewayClass, or nil when it doesn't exist or belongs to another
+// controller
+func (r *GatewayReconciler) gatewayClass(ctx context.Context, name string) (*unstructured.Unstructured, error) {
+	class := &unstructured.Unstructured{}
+	class.SetGroupVersionKind(gatewayClassGVK)
+	if err := r.Get(ctx, types.NamespacedName{Name: name}, class); err != nil {
+		return nil, client.IgnoreNotFound(err)
+	}
+	if controllerName, _, _ := unstructured.NestedString(class.Object, "spec", "controllerName"); controllerName != r.ControllerName {
+		return nil, nil
+	}
+	return class, nil
+}
+
+// attachRoutes returns the HTTPRoutes with a parentRef pointing at the Gateway, oldest first, and
+// decides for each parentRef whether a listener of the Gateway accepts it. Routes no longer
+// pointing at the Gateway are returned without parentRefs while their status still lists it.
+func (r *GatewayReconciler) attachRoutes(ctx context.Context, gateway *unstructured.Unstructured, spec *gatewaySpec) ([]*gatewayRoute, error) {
+	list := &unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(httpRouteGVK.GroupVersion().WithKind(httpRouteGVK.Kind + "List"))
+	if err := r.List(ctx, list); err != nil {
+		return nil, fmt.Errorf("failed to list HTTPRoutes: %w", err)
+	}
+	sort.SliceStable(list.Items, func(i, j int) bool {
+		a, b := list.Items[i].GetCreationTimestamp(), list.Items[j].GetCreationTimestamp()
+		if !a.Equal(&b) {
+			return a.Before(&b)
+		}
+		return list.Items[i].GetNamespace()+"/"+list.Items[i].GetName() < list.Items[j].GetNamespace()+"/"+list.Items[j].GetName()
+	})
+
+	namespaceLabels := map[string]labels.Set{}
+	var routes []*gatewayRoute
+	for i := range list.Items {
+		route := &gatewayRoute{route: &list.Items[i]}
+		if err := decodeUnstructured(route.route, &route.spec, "spec"); err != nil {
+			return nil, fmt.Errorf("failed to decode HTTPRoute %s/%s: %w", route.route.GetNamespace(), route.route.GetName(), err)
+		}
+		anyHostname := false
+		for _, ref := range route.spec.ParentRefs {
+			if !refersTo(ref, route.route.GetNamespace(), gateway) {
+				continue
+			}
+			parent := gatewayRouteParent{
+				ref:          ref,
+				accepted:     metav1.Condition{Type: "Accepted", Status: metav1.ConditionFalse, Reason: "NoMatchingParent", Message: "No listener of the Gateway matches the parentRef"},
+				resolvedRefs: metav1.Condition{Type: "ResolvedRefs", Status: metav1.ConditionTrue, Reason: "ResolvedRefs", Message: "All references are resolved"},
+			}
+			for _, listener := range spec.Listeners {
+				if (ref.SectionName != nil && *ref.SectionName != listener.Name) || (ref.Port != nil && *ref.Port != listener.Port) ||
+					(listener.Protocol != "HTTP" && listener.Protocol != "HTTPS") {
+					continue
+				}
+				allowed, err := r.allowsNamespace(ctx, gateway, listener, route.route.GetNamespace(), namespaceLabels)
+				if err != nil {
+					return nil, err
+				}
+				if !allowed {
+					if parent.accepted.Reason == "NoMatchingParent" {
+						parent.accepted.Reason, parent.accepted.Message = "NotAllowedByListeners", "The listeners of the Gateway don't allow routes from this namespace"
+					}
+					continue
+				}
+				hostnames, ok := listenerHostnames(listener.Hostname, route.spec.Hostnames)
+				if !ok {
+					if parent.accepted.Status != metav1.ConditionTrue {
+						parent.accepted.Reason, parent.accepted.Message = "NoMatchingListenerHostname", "No listener of the Gateway matches the hostnames of the route"
+					}
+					continue
+				}
+				parent.accepted = metav1.Condition{Type: "Accepted", Status: metav1.ConditionTrue, Reason: "Accepted", Message: "The route is attached to the Gateway"}
+				if hostnames == nil {
+					anyHostname = true
+				}
+				for _, hostname := range hostnames {
+					route.hostnames = append(route.hostnames, strings.ToLower(hostname))
+				}
+			}
+			route.parents = append(route.parents, parent)
+		}
+		if len(route.parents) == 0 && !r.hasOwnRouteParent(route.route, gateway) {
+			continue
+		}
+		if anyHostname {
+			route.hostnames = nil
+		} else {
+			slices.Sort(route.hostnames)
+			route.hostnames = slices.Compact(route.hostnames)
+		}
+		routes = append(routes, route)
+	}
+	return routes, nil
+}
+
+// allowsNamespace reports whether the listener accepts routes from the namespace. Listeners
+// accept routes from the namespace of the Gateway unless allowedRoutes says otherwise.
+func (r *GatewayReconciler) allowsNamespace(ctx context.Context, gateway *unstructured.Unstructured, listener gatewayListener, namespace string, cache map[string]labels.Set) (bool, error) {
+	from := "Same"
+	var selector *metav1.LabelSelector
+	if listener.AllowedRoutes != nil && listener.AllowedRoutes.Namespaces != nil {
+		if listener.AllowedRoutes.Namespaces.From != "" {
+			from = listener.AllowedRoutes.Namespaces.From
+		}
+		selector = listener.AllowedRoutes.Namespaces.Selector
+	}
+	switch from {
+	case "All":
+		return true, nil
+	case "Selector":
+		matcher, err := metav1.LabelSelectorAsSelector(selector)
+		if err != nil || selector == nil {
+			return false, nil
+		}
+		set, ok := cache[namespace]
+		if !ok {
+			var ns corev1.Namespace
+			if err := r.Get(ctx, types.NamespacedName{Name: namespace}, &ns); err != nil {
+				return false, client.IgnoreNotFound(err)
+			}
+			set = labels.Set(ns.Labels)
+			cache[namespace] = set
+		}
+		return matcher.Matches(set), nil
+	default:
+		return namespace == gateway.GetNamespace(), nil
+	}
+}
+
+// gatewayProperty returns the AkamaiProperty synthesized for the Gateway. The property delivers
+// the hostnames of the attached routes, and gets an Enhanced TLS edge hostname with Default DV
+// certificates when the Gateway has an HTTPS listener. The certificateRefs of the listeners are
+// not used, as Akamai terminates TLS.
+func (r *GatewayReconciler) gatewayProperty(ctx context.Context, gateway, class *unstructured.Unstructured, spec *gatewaySpec, routes []*gatewayRoute) (*akamaiV1alpha1.AkamaiProperty, error) {
+	if err := checkAccountAnnotations(gateway, r.AccountNamespaces); err != nil {
+		return nil, err
+	}
+	name := gateway.GetNamespace() + "." + gateway.GetName()
+	property := &akamaiV1alpha1.AkamaiProperty{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Labels: propagatedLabels(gateway.GetLabels(), r.PropagatedLabels)},
+		Spec: akamaiV1alpha1.AkamaiPropertySpec{
+			PropertyName: name,
+			ContractID:   gatewayAnnotation(gateway, class, AnnotationIngressContractID),
+			GroupID:      gatewayAnnotation(gateway, class, AnnotationIngressGroupID),
+			ProductID:    gatewayAnnotation(gateway, class, AnnotationIngressProductID),
+		},
+	}
+	property.Labels[ManagedByLabel] = ManagedByValue
+	property.Labels[GatewayNamespaceLabel] = gateway.GetNamespace()
+	property.Labels[GatewayNameLabel] = gateway.GetName()
+
+	if property.Spec.ContractID == "" || property.Spec.GroupID == "" || property.Spec.ProductID == "" {
+		return nil, fmt.Errorf("the contract, group and product of the property are required; set the %s, %s and %s annotations on the Gateway or its GatewayClass",
+			AnnotationIngressContractID, AnnotationIngressGroupID, AnnotationIngressProductID)
+	}
+
+	// The routes compile to child rules; routes using features Akamai can't express are rejected
+	var compiled []compiledRule
+	var hostnames []string
+	origin := gatewayAnnotation(gateway, class, AnnotationIngressOriginHostname)
+	resolve := func(namespace string, ref httpBackendRef) (*backendOrigin, error) {
+		return r.resolveBackend(ctx, namespace, ref)
+	}
+	for _, route := range routes {
+		if !route.attached() {
+			continue
+		}
+		rules, unresolved, err := compileHTTPRoute(route.route, &route.spec, route.hostnames, len(compiled), resolve)
+		if err != nil {
+			for i := range route.parents {
+				if route.parents[i].accepted.Status == metav1.ConditionTrue {
+					route.parents[i].accepted = metav1.Condition{Type: "Accepted", Status: metav1.ConditionFalse, Reason: "UnsupportedValue", Message: err.Error()}
+				}
+			}
+			continue
+		}
+		if unresolved != nil {
+			for i := range route.parents {
+				route.parents[i].resolvedRefs = metav1.Condition{Type: "ResolvedRefs", Status: metav1.ConditionFalse, Reason: unresolved.reason, Message: unresolved.message}
+			}
+		}
+		compiled = append(compiled, rules...)
+		for _, hostname := range route.hostnames {
+			// Wildcards match in the rules but can't be CNAMEd to the edge hostname
+			if !strings.HasPrefix(hostname, "*") {
+				hostnames = append(hostnames, hostname)
+			}
+		}
+	}
+	slices.Sort(hostnames)
+	hostnames = slices.Compact(hostnames)
+	sortCompiledRules(compiled)
+	if origin == "" {
+		origin = firstRouteOrigin(compiled)
+	}
+	if len(hostnames) == 0 {
+		return nil, fmt.Errorf("no HTTPRoute with a hostname is attached to the Gateway")
+	}
+	if origin == "" {
+		return nil, fmt.Errorf("no backend of the attached HTTPRoutes has an address and the %s annotation isn't set", AnnotationIngressOriginHostname)
+	}
+	property.Spec.Origin = &akamaiV1alpha1.OriginServer{Hostname: origin, ForwardHostHeader: "REQUEST_HOST_HEADER"}
+
+	property.Spec.Rules = &akamaiV1alpha1.PropertyRules{Name: "default"}
+	for _, c := range compiled {
+		raw, err := json.Marshal(c.rule)
+		if err != nil {
+			return nil, fmt.Errorf("failed to render %s: %w", c.rule["name"], err)
+		}
+		property.Spec.Rules.Children = append(property.Spec.Rules.Children, runtime.RawExtension{Raw: raw})
+	}
+
+	if cpCode := gatewayAnnotation(gateway, class, AnnotationIngressCPCode); cpCode != "" {
+		id, err := strconv.ParseInt(strings.TrimPrefix(cpCode, "cpc_"), 10, 64)
+		if err != nil || id <= 0 {
+			return nil, fmt.Errorf("invalid CP code %q in the %s annotation", cpCode, AnnotationIngressCPCode)
+		}
+		property.Spec.CPCode = &akamaiV1alpha1.CPCodeSpec{ID: id}
+	}
+
+	edgeHostname := &akamaiV1alpha1.EdgeHostnameSpec{DomainPrefix: name, DomainSuffix: "edgesuite.net"}
+	if r.EdgeHostnameTemplate != "" {
+		prefix, err := renderNameTemplate(r.EdgeHostnameTemplate, property)
+		if err != nil {
+			return nil, err
+		}
+		edgeHostname.DomainPrefix = prefix
+	}
+	certProvisioningType := ""
+	if certificateRef := gatewayAnnotation(gateway, class, AnnotationIngressCertificateRef); certificateRef != "" {
+		edgeHostname.DomainSuffix, edgeHostname.SecureNetwork, edgeHostname.CertificateRef = "edgekey.net", "ENHANCED_TLS", certificateRef
+		certProvisioningType = "CPS_MANAGED"
+	} else if slices.ContainsFunc(spec.Listeners, func(listener gatewayListener) bool { return listener.Protocol == "HTTPS" }) {
+		edgeHostname.DomainSuffix, edgeHostname.SecureNetwork = "edgekey.net", "ENHANCED_TLS"
+		certProvisioningType = certProvisioningDefault
+	}
+	if certProvisioningType != "" {
+		property.Spec.Redirect = &akamaiV1alpha1.RedirectSpec{HTTPToHTTPS: true}
+	}
+	property.Spec.EdgeHostname = edgeHostname
+	for _, hostname := range hostnames {
+		property.Spec.Hostnames = append(property.Spec.Hostnames, akamaiV1alpha1.Hostname{
+			CNAMEFrom:            hostname,
+			CNAMETo:              edgeHostname.DomainPrefix + "." + edgeHostname.DomainSuffix,
+			CertProvisioningType: certProvisioningType,
+		})
+	}
+
+	if notify := gatewayAnnotation(gateway, class, AnnotationIngressNotifyEmails); notify != "" {
+		network := strings.ToUpper(gatewayAnnotation(gateway, class, AnnotationIngressActivationNetwork))
+		if network == "" {
+			network = "STAGING"
+		}
+		if network != "STAGING" && network != "PRODUCTION" {
+			return nil, fmt.Errorf("invalid activation network %q in the %s annotation", network, AnnotationIngressActivationNetwork)
+		}
+		property.Spec.Activation = &akamaiV1alpha1.ActivationSpec{Network: network, NotifyEmails: strings.FieldsFunc(notify, func(c rune) bool { return c == ',' || unicode.IsSpace(c) })}
+	}
+	return property, nil
+}
+
+// resolveBackend returns the origin of a backend: the external name of an ExternalName Service
+// or the load balancer address of a LoadBalancer Service. Services of other namespaces need a
+// ReferenceGrant, which the operator doesn't support.
+func (r *GatewayReconciler) resolveBackend(ctx context.Context, namespace string, ref httpBackendRef) (*backendOrigin, error) {
+	if (ref.Group != nil && *ref.Group != "") || (ref.Kind != nil && *ref.Kind != "Service") {
+		return nil, &backendError{reason: "InvalidKind", message: fmt.Sprintf("backend %s is not a Service", ref.Name)}
+	}
+	if ref.Namespace != nil && *ref.Namespace != namespace {
+		return nil, &backendError{reason: "RefNotPermitted", message: fmt.Sprintf("backend %s/%s is in another namespace", *ref.Namespace, ref.Name)}
+	}
+	var service corev1.Service
+	if err := r.Get(ctx, types.NamespacedName{Namespace: namespace, Name: ref.Name}, &service); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, &backendError{reason: "BackendNotFound", message: fmt.Sprintf("Service %s/%s not found", namespace, ref.Name)}
+		}
+		return nil, err
+	}
+	origin := &backendOrigin{}
+	if ref.Port != nil {
+		origin.Port = *ref.Port
+	}
+	if service.Spec.Type == corev1.ServiceTypeExternalName {
+		origin.Hostname = service.Spec.ExternalName
+		return origin, nil
+	}
+	for _, lb := range service.Status.LoadBalancer.Ingress {
+		if lb.Hostname != "" {
+			origin.Hostname = lb.Hostname
+			return origin, nil
+		}
+		if lb.IP != "" {
+			origin.Hostname = lb.IP
+			return origin, nil
+		}
+	}
+	return nil, &backendError{reason: "BackendNotFound", message: fmt.Sprintf("Service %s/%s has no external address", namespace, ref.Name)}
+}
+
+// applyProperty creates or updates the AkamaiProperty of the Gateway and returns it
+func (r *GatewayReconciler) applyProperty(ctx context.Context, gateway *unstructured.Unstructured, desired *akamaiV1alpha1.AkamaiProperty) (*akamaiV1alpha1.AkamaiProperty, error) {
+	logger := log.FromContext(ctx)
+	var current akamaiV1alpha1.AkamaiProperty
+	if err := r.Get(ctx, types.NamespacedName{Name: desired.Name}, &current); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return nil, err
+		}
+		if err := r.Create(ctx, desired); err != nil {
+			return nil, fmt.Errorf("failed to create AkamaiProperty %s: %w", desired.Name, err)
+		}
+		logger.Info("Created AkamaiProperty for the Gateway", "property", desired.Name)
+		r.event(gateway, "Normal", "PropertyCreated", "Synthesize", "Created AkamaiProperty %s", desired.Name)
+		return desired, nil
+	}
+	if !synthesizedForGateway(&current, gateway) {
+		err := fmt.Errorf("AkamaiProperty %s exists and was not synthesized for this Gateway", current.Name)
+		r.event(gateway, "Warning", "PropertyConflict", "Synthesize", "%s", err)
+		return nil, err
+	}
+	if equality.Semantic.DeepEqual(current.Spec, desired.Spec) && equality.Semantic.DeepEqual(current.Labels, desired.Labels) {
+		return &current, nil
+	}
+	current.Spec = desired.Spec
+	current.Labels = desired.Labels
+	if err := r.Update(ctx, &current); err != nil {
+		return nil, fmt.Errorf("failed to update AkamaiProperty %s: %w", current.Name, err)
+	}
+	logger.Info("Updated AkamaiProperty of the Gateway", "property", current.Name)
+	return &current, nil
+}
+
+// deleteProperty deletes the AkamaiProperty synthesized for the Gateway, which removes the
+// property from Akamai according to its deletion policy
+func (r *GatewayReconciler) deleteProperty(ctx context.Context, gateway *unstructured.Unstructured) error {
+	var property akamaiV1alpha1.AkamaiProperty
+	if err := r.Get(ctx, types.NamespacedName{Name: gateway.GetNamespace() + "." + gateway.GetName()}, &property); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	if !synthesizedForGateway(&property, gateway) {
+		return nil
+	}
+	if err := r.Delete(ctx, &property); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	log.FromContext(ctx).Info("Deleted AkamaiProperty of the Gateway", "property", property.Name)
+	return nil
+}
+
+// updateRouteStatuses writes the conditions of the parentRefs pointing at the Gateway into the
+// status of the routes, leaving the entries of other Gateways and controllers alone
+func (r *GatewayReconciler) updateRouteStatuses(ctx context.Context, gateway *unstructured.Unstructured, routes []*gatewayRoute) error {
+	for _, route := range routes {
+		var parents []interface{}
+		existing, _, _ := unstructured.NestedSlice(route.route.Object, "status", "parents")
+		for _, parent := range existing {
+			if !r.isOwnRouteParent(parent, route.route.GetNamespace(), gateway) {
+				parents = append(parents, parent)
+			}
+		}
+		for _, parent := range route.parents {
+			ref, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&parent.ref)
+			if err != nil {
+				return fmt.Errorf("failed to render the status of HTTPRoute %s/%s: %w", route.route.GetNamespace(), route.route.GetName(), err)
+			}
+			var conditions []metav1.Condition
+			for _, previous := range existing {
+				if r.isOwnRouteParent(previous, route.route.GetNamespace(), gateway) && equality.Semantic.DeepEqual(previous.(map[string]interface{})["parentRef"], ref) {
+					conditions = unstructuredConditions(previous.(map[string]interface{})["conditions"])
+				}
+			}
+			generation := route.route.GetGeneration()
+			parent.accepted.ObservedGeneration, parent.resolvedRefs.ObservedGeneration = generation, generation
+			meta.SetStatusCondition(&conditions, parent.accepted)
+			meta.SetStatusCondition(&conditions, parent.resolvedRefs)
+			parents = append(parents, map[string]interface{}{
+				"parentRef":      ref,
+				"controllerName": r.ControllerName,
+				"conditions":     conditionValues(conditions),
+			})
+		}
+		if err := r.writeRouteParents(ctx, route.route, existing, parents); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// releaseRoutes removes the entries of the Gateway from the status of the routes
+func (r *GatewayReconciler) releaseRoutes(ctx context.Context, gateway *unstructured.Unstructured) error {
+	list := &unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(httpRouteGVK.GroupVersion().WithKind(httpRouteGVK.Kind + "List"))
+	if err := r.List(ctx, list); err != nil {
+		return fmt.Errorf("failed to list HTTPRoutes: %w", err)
+	}
+	for i := range list.Items {
+		route := &list.Items[i]
+		existing, _, _ := unstructured.NestedSlice(route.Object, "status", "parents")
+		var parents []interface{}
+		for _, parent := range existing {
+			if !r.isOwnRouteParent(parent, route.GetNamespace(), gateway) {
+				parents = append(parents, parent)
+			}
+		}
+		if err := r.writeRouteParents(ctx, route, existing, parents); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeRouteParents updates status.parents of a route when it changed
+func (r *GatewayReconciler) writeRouteParents(ctx context.Context, route *unstructured.Unstructured, existing, parents []interface{}) error {
+	if parents == nil {
+		parents = []interface{}{}
+	}
+	if (len(existing) == 0 && len(parents) == 0) || equality.Semantic.DeepEqual(existing, parents) {
+		return nil
+	}
+	if err := unstructured.SetNestedSlice(route.Object, parents, "status", "parents"); err != nil {
+		return fmt.Errorf("failed to render the status of HTTPRoute %s/%s: %w", route.GetNamespace(), route.GetName(), err)
+	}
+	if err := r.Status().Update(ctx, route); err != nil {
+		return fmt.Errorf("failed to update the status of HTTPRoute %s/%s: %w", route.GetNamespace(), route.GetName(), err)
+	}
+	return nil
+}
+
+// isOwnRouteParent reports whether an entry of status.parents of a route was written by the
+// operator for the Gateway
+func (r *GatewayReconciler) isOwnRouteParent(parent interface{}, namespace string, gateway *unstructured.Unstructured) bool {
+	entry, ok := parent.(map[string]interface{})
+	if !ok || entry["controllerName"] != r.ControllerName {
+		return false
+	}
+	refValue, ok := entry["parentRef"].(map[string]interface{})
+	if !ok {
+		return false
+	}
+	var ref gatewayParentReference
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(refValue, &ref); err != nil {
+		return false
+	}
+	return refersTo(ref, namespace, gateway)
+}
+
+// hasOwnRouteParent reports whether the status of the route lists the Gateway
+func (r *GatewayReconciler) hasOwnRouteParent(route, gateway *unstructured.Unstructured) bool {
+	parents, _, _ := unstructured.NestedSlice(route.Object, "status", "parents")
+	return slices.ContainsFunc(parents, func(parent interface{}) bool {
+		return r.isOwnRouteParent(parent, route.GetNamespace(), gateway)
+	})
+}
+
+// updateGatewayStatus writes the Accepted and Programmed conditions, the edge hostname as address
+// and the attached routes of each listener into the status of the Gateway
+func (r *GatewayReconciler) updateGatewayStatus(ctx context.Context, gateway *unstructured.Unstructured, spec *gatewaySpec, routes []*gatewayRoute, edgeHostname string, programmed metav1.Condition) error {
+	generation := gateway.GetGeneration()
+	existing, _, _ := unstructured.NestedMap(gateway.Object, "status")
+	conditions := unstructuredConditions(existing["conditions"])
+	programmed.ObservedGeneration = generation
+	meta.SetStatusCondition(&conditions, metav1.Condition{Type: "Accepted", Status: metav1.ConditionTrue, Reason: "Accepted",
+		Message: "The Gateway is implemented by akamai-operator", ObservedGeneration: generation})
+	meta.SetStatusCondition(&conditions, programmed)
+
+	previousListeners := map[string][]metav1.Condition{}
+	if listeners, ok := existing["listeners"].([]interface{}); ok {
+		for _, listener := range listeners {
+			if entry, ok := listener.(map[string]interface{}); ok {
+				name, _ := entry["name"].(string)
+				previousListeners[name] = unstructuredConditions(entry["conditions"])
+			}
+		}
+	}
+	listeners := []interface{}{}
+	for _, listener := range spec.Listeners {
+		listenerConditions := previousListeners[listener.Name]
+		supportedKinds := []interface{}{map[string]interface{}{"group": gatewayAPIGroup, "kind": httpRouteGVK.Kind}}
+		if listener.Protocol == "HTTP" || listener.Protocol == "HTTPS" {
+			meta.SetStatusCondition(&listenerConditions, metav1.Condition{Type: "Accepted", Status: metav1.ConditionTrue, Reason: "Accepted",
+				Message: "The listener is served by Akamai", ObservedGeneration: generation})
+			meta.SetStatusCondition(&listenerConditions, programmed)
+		} else {
+			supportedKinds = []interface{}{}
+			meta.SetStatusCondition(&listenerConditions, metav1.Condition{Type: "Accepted", Status: metav1.ConditionFalse, Reason: "UnsupportedProtocol",
+				Message: fmt.Sprintf("Protocol %s is not supported", listener.Protocol), ObservedGeneration: generation})
+			meta.SetStatusCondition(&listenerConditions, metav1.Condition{Type: "Programmed", Status: metav1.ConditionFalse, Reason: "Invalid",
+				Message: fmt.Sprintf("Protocol %s is not supported", listener.Protocol), ObservedGeneration: generation})
+		}
+		meta.SetStatusCondition(&listenerConditions, metav1.Condition{Type: "ResolvedRefs", Status: metav1.ConditionTrue, Reason: "ResolvedRefs",
+			Message: "All references are resolved", ObservedGeneration: generation})
+		listeners = append(listeners, map[string]interface{}{
+			"name":           listener.Name,
+			"supportedKinds": supportedKinds,
+			"attachedRoutes": attachedRoutes(listener, routes),
+			"conditions":     conditionValues(listenerConditions),
+		})
+	}
+
+	status := map[string]interface{}{
+		"conditions": conditionValues(conditions),
+		"listeners":  listeners,
+	}
+	if edgeHostname != "" {
+		status["addresses"] = []interface{}{map[string]interface{}{"type": "Hostname", "value": edgeHostname}}
+	} else if addresses, ok := existing["addresses"]; ok {
+		status["addresses"] = addresses
+	}
+	if equality.Semantic.DeepEqual(existing, status) {
+		return nil
+	}
+	gateway.Object["status"] = status
+	if err := r.Status().Update(ctx, gateway); err != nil {
+		return fmt.Errorf("failed to update the status of Gateway %s/%s: %w", gateway.GetNamespace(), gateway.GetName(), err)
+	}
+	return nil
+}
+
+// event records an event about the Gateway
+func (r *GatewayReconciler) event(gateway *unstructured.Unstructured, eventType, reason, action, note string, args ...interface{}) {
+	if r.Recorder == nil {
+		return
+	}
+	r.Recorder.Eventf(gateway, nil, eventType, reason, action, note, args...)
+}
+
+// propertyProgrammed returns the Programmed condition of a Gateway from the phase of its property
+func propertyProgrammed(property *akamaiV1alpha1.AkamaiProperty) metav1.Condition {
+	switch property.Status.Phase {
+	case PhaseReady:
+		return metav1.Condition{Type: "Programmed", Status: metav1.ConditionTrue, Reason: "Programmed",
+			Message: fmt.Sprintf("AkamaiProperty %s is ready", property.Name)}
+	case PhaseError:
+		message := fmt.Sprintf("AkamaiProperty %s failed", property.Name)
+		if ready := meta.FindStatusCondition(property.Status.Conditions, ConditionTypeReady); ready != nil && ready.Message != "" {
+			message += ": " + ready.Message
+		}
+		return metav1.Condition{Type: "Programmed", Status: metav1.ConditionFalse, Reason: "Invalid", Message: message}
+	default:
+		phase := property.Status.Phase
+		if phase == "" {
+			phase = "pending"
+		}
+		return metav1.Condition{Type: "Programmed", Status: metav1.ConditionFalse, Reason: "Pending",
+			Message: fmt.Sprintf("AkamaiProperty %s is %s", property.Name, strings.ToLower(phase))}
+	}
+}
+
+// attachedRoutes counts the routes attached to a listener
+func attachedRoutes(listener gatewayListener, routes []*gatewayRoute) int64 {
+	var count int64
+	for _, route := range routes {
+		for _, parent := range route.parents {
+			if parent.accepted.Status == metav1.ConditionTrue && (parent.ref.SectionName == nil || *parent.ref.SectionName == listener.Name) &&
+				(parent.ref.Port == nil || *parent.ref.Port == listener.Port) {
+				if _, ok := listenerHostnames(listener.Hostname, route.spec.Hostnames); ok {
+					count++
+					break
+				}
+			}
+		}
+	}
+	return count
+}
+
+// firstRouteOrigin returns the origin of the least specific compiled rule with a backend, the
+// default origin of the property
+func firstRouteOrigin(rules []compiledRule) string {
+	for _, c := range rules {
+		for _, behavior := range c.rule["behaviors"].([]interface{}) {
+			if b := behavior.(map[string]interface{}); b["name"] == "origin" {
+				return b["options"].(map[string]interface{})["hostname"].(string)
+			}
+		}
+	}
+	return ""
+}
+
+// refersTo reports whether a parentRef of a route in the namespace points at the Gateway
+func refersTo(ref gatewayParentReference, namespace string, gateway *unstructured.Unstructured) bool {
+	if ref.Group != nil && *ref.Group != gatewayAPIGroup || ref.Kind != nil && *ref.Kind != gatewayGVK.Kind {
+		return false
+	}
+	if ref.Namespace != nil {
+		namespace = *ref.Namespace
+	}
+	return namespace == gateway.GetNamespace() && ref.Name == gateway.GetName()
+}
+
+// synthesizedForGateway reports whether the AkamaiProperty was synthesized for the Gateway
+func synthesizedForGateway(property *akamaiV1alpha1.AkamaiProperty, gateway *unstructured.Unstructured) bool {
+	return property.Labels[GatewayNamespaceLabel] == gateway.GetNamespace() && property.Labels[GatewayNameLabel] == gateway.GetName()
+}
+
+// gatewayAnnotation returns the annotation of the Gateway, or of its GatewayClass when the Gateway
+// doesn't set it, so the class carries the defaults of its Gateways
+func gatewayAnnotation(gateway, class *unstructured.Unstructured, key string) string {
+	if value := strings.TrimSpace(gateway.GetAnnotations()[key]); value != "" {
+		return value
+	}
+	return strings.TrimSpace(class.GetAnnotations()[key])
+}
+
+// unstructuredConditions decodes the conditions of an unstructured status
+func unstructuredConditions(value interface{}) []metav1.Condition {
+	var conditions []metav1.Condition
+	values, _ := value.([]interface{})
+	for _, v := range values {
+		entry, ok := v.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		var condition metav1.Condition
+		if runtime.DefaultUnstructuredConverter.FromUnstructured(entry, &condition) == nil {
+			conditions = append(conditions, condition)
+		}
+	}
+	return conditions
+}
+
+// conditionValues encodes conditions for an unstructured status
+func conditionValues(conditions []metav1.Condition) []interface{} {
+	values := make([]interface{}, 0, len(conditions))
+	for i := range conditions {
+		value, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&conditions[i])
+		if err == nil {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// gatewaysOfClass maps a GatewayClass to its Gateways
+func (r *GatewayReconciler) gatewaysOfClass(ctx context.Context, obj client.Object) []reconcile.Request {
+	list := &unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(gatewayGVK.GroupVersion().WithKind(gatewayGVK.Kind + "List"))
+	if err := r.List(ctx, list); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to list Gateways")
+		return nil
+	}
+	var requests []reconcile.Request
+	for _, gateway := range list.Items {
+		if className, _, _ := unstructured.NestedString(gateway.Object, "spec", "gatewayClassName"); className == obj.GetName() {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: gateway.GetNamespace(), Name: gateway.GetName()}})
+		}
+	}
+	return requests
+}
+
+// gatewaysOfRoute maps an HTTPRoute to the Gateways it references and the ones it was attached to
+// before, so detached routes are released
+func (r *GatewayReconciler) gatewaysOfRoute(_ context.Context, obj client.Object) []reconcile.Request {
+	route, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return nil
+	}
+	var spec httpRouteSpec
+	if decodeUnstructured(route, &spec, "spec") != nil {
+		return nil
+	}
+	refs := spec.ParentRefs
+	parents, _, _ := unstructured.NestedSlice(route.Object, "status", "parents")
+	for _, parent := range parents {
+		entry, ok := parent.(map[string]interface{})
+		if !ok || entry["controllerName"] != r.ControllerName {
+			continue
+		}
+		var ref gatewayParentReference
+		if value, ok := entry["parentRef"].(map[string]interface{}); ok && runtime.DefaultUnstructuredConverter.FromUnstructured(value, &ref) == nil {
+			refs = append(refs, ref)
+		}
+	}
+	var requests []reconcile.Request
+	for _, ref := range refs {
+		if ref.Group != nil && *ref.Group != gatewayAPIGroup || ref.Kind != nil && *ref.Kind != gatewayGVK.Kind {
+			continue
+		}
+		namespace := route.GetNamespace()
+		if ref.Namespace != nil {
+			namespace = *ref.Namespace
+		}
+		request := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: namespace, Name: ref.Name}}
+		if !slices.Contains(requests, request) {
+			requests = append(requests, request)
+		}
+	}
+	return requests
+}
+
+// gatewaysOfService maps a Service to the Gateways of the routes in its namespace, as their
+// origins follow the address of the Service
+func (r *GatewayReconciler) gatewaysOfService(ctx context.Context, obj client.Object) []reconcile.Request {
+	list := &unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(httpRouteGVK.GroupVersion().WithKind(httpRouteGVK.Kind + "List"))
+	if err := r.List(ctx, list, client.InNamespace(obj.GetNamespace())); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to list HTTPRoutes")
+		return nil
+	}
+	var requests []reconcile.Request
+	for i := range list.Items {
+		var spec httpRouteSpec
+		if decodeUnstructured(&list.Items[i], &spec, "spec") != nil {
+			continue
+		}
+		references := false
+		for _, rule := range spec.Rules {
+			for _, ref := range rule.BackendRefs {
+				references = references || ref.Name == obj.GetName()
+			}
+		}
+		if !references {
+			continue
+		}
+		for _, request := range r.gatewaysOfRoute(ctx, &list.Items[i]) {
+			if !slices.Contains(requests, request) {
+				requests = append(requests, request)
+			}
+		}
+	}
+	return requests
+}
+
+// gatewayOfProperty maps an AkamaiProperty to the Gateway it was synthesized for, so its phase is
+// reported in the status of the Gateway and changes made to the property directly are reverted
+func gatewayOfProperty(_ context.Context, obj client.Object) []reconcile.Request {
+	labels := obj.GetLabels()
+	if labels[GatewayNamespaceLabel] == "" || labels[GatewayNameLabel] == "" {
+		return nil
+	}
+	return []reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: labels[GatewayNamespaceLabel], Name: labels[GatewayNameLabel]}}}
+}
+
+// SetupWithManager sets up the controller with the Manager. The Gateway API CRDs must be
+// installed in the cluster.
+func (r *GatewayReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	gateway := &unstructured.Unstructured{}
+	gateway.SetGroupVersionKind(gatewayGVK)
+	class := &unstructured.Unstructured{}
+	class.SetGroupVersionKind(gatewayClassGVK)
+	route := &unstructured.Unstructured{}
+	route.SetGroupVersionKind(httpRouteGVK)
+	return ctrl.NewControllerManagedBy(mgr).
+		For(gateway).
+		Watches(class, handler.EnqueueRequestsFromMapFunc(r.gatewaysOfClass)).
+		Watches(route, handler.EnqueueRequestsFromMapFunc(r.gatewaysOfRoute)).
+		Watches(&corev1.Service{}, handler.EnqueueRequestsFromMapFunc(r.gatewaysOfService)).
+		Watches(&akamaiV1alpha1.AkamaiProperty{}, handler.EnqueueRequestsFromMapFunc(gatewayOfProperty)).
+		Complete(r)
+}
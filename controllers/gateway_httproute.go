@@ -0,0 +1,528 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+)
+
+// The parts of the Gateway API the operator reads. The objects are handled as unstructured, like
+// DNSEndpoints, so the operator doesn't depend on the Gateway API module; these types decode the
+// fields it uses.
+
+// gatewaySpec is the spec of a Gateway
+type gatewaySpec struct {
+	GatewayClassName string            `json:"gatewayClassName"`
+	Listeners        []gatewayListener `json:"listeners,omitempty"`
+}
+
+// gatewayListener is a listener of a Gateway
+type gatewayListener struct {
+	Name          string                `json:"name"`
+	Hostname      string                `json:"hostname,omitempty"`
+	Port          int32                 `json:"port"`
+	Protocol      string                `json:"protocol"`
+	AllowedRoutes *gatewayAllowedRoutes `json:"allowedRoutes,omitempty"`
+}
+
+// gatewayAllowedRoutes restricts the namespaces of the routes attached to a listener
+type gatewayAllowedRoutes struct {
+	Namespaces *struct {
+		From     string                `json:"from,omitempty"`
+		Selector *metav1.LabelSelector `json:"selector,omitempty"`
+	} `json:"namespaces,omitempty"`
+}
+
+// httpRouteSpec is the spec of an HTTPRoute
+type httpRouteSpec struct {
+	ParentRefs []gatewayParentReference `json:"parentRefs,omitempty"`
+	Hostnames  []string                 `json:"hostnames,omitempty"`
+	Rules      []httpRouteRule          `json:"rules,omitempty"`
+}
+
+// gatewayParentReference references the Gateway, and optionally the listener, a route attaches to.
+// It is echoed in the route status, so unset fields stay unset.
+type gatewayParentReference struct {
+	Group       *string `json:"group,omitempty"`
+	Kind        *string `json:"kind,omitempty"`
+	Namespace   *string `json:"namespace,omitempty"`
+	Name        string  `json:"name"`
+	SectionName *string `json:"sectionName,omitempty"`
+	Port        *int32  `json:"port,omitempty"`
+}
+
+// httpRouteRule is a rule of an HTTPRoute
+type httpRouteRule struct {
+	Matches     []httpRouteMatch  `json:"matches,omitempty"`
+	Filters     []httpRouteFilter `json:"filters,omitempty"`
+	BackendRefs []httpBackendRef  `json:"backendRefs,omitempty"`
+}
+
+// httpRouteMatch matches the requests of a rule
+type httpRouteMatch struct {
+	Path *struct {
+		Type  string `json:"type,omitempty"`
+		Value string `json:"value,omitempty"`
+	} `json:"path,omitempty"`
+	Headers     []httpValueMatch `json:"headers,omitempty"`
+	QueryParams []httpValueMatch `json:"queryParams,omitempty"`
+	Method      string           `json:"method,omitempty"`
+}
+
+// httpValueMatch matches a header or query parameter
+type httpValueMatch struct {
+	Type  string `json:"type,omitempty"`
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// httpRouteFilter modifies the requests or responses of a rule
+type httpRouteFilter struct {
+	Type                   string               `json:"type"`
+	RequestHeaderModifier  *httpHeaderModifier  `json:"requestHeaderModifier,omitempty"`
+	ResponseHeaderModifier *httpHeaderModifier  `json:"responseHeaderModifier,omitempty"`
+	RequestRedirect        *httpRequestRedirect `json:"requestRedirect,omitempty"`
+}
+
+// httpHeaderModifier sets, adds and removes headers
+type httpHeaderModifier struct {
+	Set []struct {
+		Name  string `json:"name"`
+		Value string `json:"value"`
+	} `json:"set,omitempty"`
+	Add []struct {
+		Name  string `json:"name"`
+		Value string `json:"value"`
+	} `json:"add,omitempty"`
+	Remove []string `json:"remove,omitempty"`
+}
+
+// httpRequestRedirect redirects the requests of a rule
+type httpRequestRedirect struct {
+	Scheme   *string `json:"scheme,omitempty"`
+	Hostname *string `json:"hostname,omitempty"`
+	Path     *struct {
+		Type               string  `json:"type"`
+		ReplaceFullPath    *string `json:"replaceFullPath,omitempty"`
+		ReplacePrefixMatch *string `json:"replacePrefixMatch,omitempty"`
+	} `json:"path,omitempty"`
+	Port       *int32 `json:"port,omitempty"`
+	StatusCode *int64 `json:"statusCode,omitempty"`
+}
+
+// httpBackendRef references the backend of a rule
+type httpBackendRef struct {
+	Group     *string           `json:"group,omitempty"`
+	Kind      *string           `json:"kind,omitempty"`
+	Name      string            `json:"name"`
+	Namespace *string           `json:"namespace,omitempty"`
+	Port      *int32            `json:"port,omitempty"`
+	Weight    *int32            `json:"weight,omitempty"`
+	Filters   []httpRouteFilter `json:"filters,omitempty"`
+}
+
+// backendOrigin is the origin a backend of a route is reached at
+type backendOrigin struct {
+	Hostname string
+	Port     int32
+}
+
+// backendError is why a backend of a route can't be resolved, with the reason of the route's
+// ResolvedRefs condition
+type backendError struct {
+	reason  string
+	message string
+}
+
+func (e *backendError) Error() string {
+	return e.message
+}
+
+// backendResolver resolves a backend of a route in the namespace of the route
+type backendResolver func(namespace string, ref httpBackendRef) (*backendOrigin, error)
+
+// compiledRule is a child rule compiled from a match of an HTTPRoute rule, with the precedence
+// of the match
+type compiledRule struct {
+	rule        map[string]interface{}
+	exact       bool
+	prefix      int
+	method      bool
+	headers     int
+	queryParams int
+	order       int
+}
+
+// decodeUnstructured decodes a field of an unstructured object into one of the types above
+func decodeUnstructured(obj *unstructured.Unstructured, into interface{}, fields ...string) error {
+	value, found, err := unstructured.NestedMap(obj.Object, fields...)
+	if err != nil || !found {
+		return err
+	}
+	return runtime.DefaultUnstructuredConverter.FromUnstructured(value, into)
+}
+
+// compileHTTPRoute compiles the rules of an HTTPRoute into child rules of the property's rule
+// tree, one for each match. Hostnames restricts the rules to the hostnames the route is attached
+// with; nil matches all hostnames. The first backend that can't be resolved is returned with
+// the rules, whose requests then get a 500 response. Features Akamai can't express make the
+// route fail as a whole.
+func compileHTTPRoute(route *unstructured.Unstructured, spec *httpRouteSpec, hostnames []string, order int, resolve backendResolver) ([]compiledRule, *backendError, error) {
+	var compiled []compiledRule
+	var unresolved *backendError
+	for i, rule := range spec.Rules {
+		behaviors, err := compileFilters(rule.Filters)
+		if err != nil {
+			return nil, nil, fmt.Errorf("rule %d: %w", i+1, err)
+		}
+		if !hasRedirect(rule.Filters) {
+			origin, err := ruleBackend(route.GetNamespace(), rule, resolve)
+			var backendErr *backendError
+			if err != nil && !errors.As(err, &backendErr) {
+				return nil, nil, fmt.Errorf("rule %d: %w", i+1, err)
+			}
+			if origin != nil {
+				behaviors = append([]interface{}{routeOriginBehavior(origin)}, behaviors...)
+			} else {
+				// Requests to rules without a usable backend fail, as the Gateway API requires
+				if backendErr != nil && unresolved == nil {
+					unresolved = backendErr
+				}
+				behaviors = append([]interface{}{map[string]interface{}{
+					"name": "constructResponse",
+					"options": map[string]interface{}{
+						"enabled":       true,
+						"responseCode":  500,
+						"body":          "Internal Server Error",
+						"forceEviction": false,
+					},
+				}}, behaviors...)
+			}
+		}
+
+		matches := rule.Matches
+		if len(matches) == 0 {
+			matches = []httpRouteMatch{{}}
+		}
+		for j, match := range matches {
+			name := fmt.Sprintf("HTTPRoute %s/%s rule %d", route.GetNamespace(), route.GetName(), i+1)
+			if len(matches) > 1 {
+				name += fmt.Sprintf(" match %d", j+1)
+			}
+			c, err := compileMatch(match, hostnames)
+			if err != nil {
+				return nil, nil, fmt.Errorf("rule %d: %w", i+1, err)
+			}
+			c.order = order
+			c.rule["name"] = name
+			c.rule["comments"] = "Compiled from an HTTPRoute by akamai-operator; changes made here are overwritten"
+			c.rule["behaviors"] = behaviors
+			c.rule["children"] = []interface{}{}
+			compiled = append(compiled, *c)
+			order++
+		}
+	}
+	return compiled, unresolved, nil
+}
+
+// compileMatch renders the criteria of a match. Path prefixes match the path itself and
+// everything below it.
+func compileMatch(match httpRouteMatch, hostnames []string) (*compiledRule, error) {
+	c := &compiledRule{}
+	var criteria []interface{}
+	if len(hostnames) > 0 {
+		criteria = append(criteria, map[string]interface{}{
+			"name":    "hostname",
+			"options": map[string]interface{}{"matchOperator": "IS_ONE_OF", "values": stringValues(hostnames)},
+		})
+	}
+	if match.Path != nil {
+		value := match.Path.Value
+		if value == "" {
+			value = "/"
+		}
+		var values []string
+		switch match.Path.Type {
+		case "", "PathPrefix":
+			if prefix := strings.TrimSuffix(value, "/"); prefix != "" {
+				values = []string{prefix, prefix + "/*"}
+				c.prefix = len(prefix)
+			}
+		case "Exact":
+			values = []string{value}
+			c.exact = true
+		default:
+			return nil, fmt.Errorf("path match type %s is not supported", match.Path.Type)
+		}
+		if len(values) > 0 {
+			criteria = append(criteria, map[string]interface{}{
+				"name": "path",
+				"options": map[string]interface{}{
+					"matchOperator":      "MATCHES_ONE_OF",
+					"values":             stringValues(values),
+					"matchCaseSensitive": true,
+					"normalize":          false,
+				},
+			})
+		}
+	}
+	if match.Method != "" {
+		criteria = append(criteria, map[string]interface{}{
+			"name":    "requestMethod",
+			"options": map[string]interface{}{"matchOperator": "IS", "value": strings.ToUpper(match.Method)},
+		})
+		c.method = true
+	}
+	for _, header := range match.Headers {
+		if header.Type != "" && header.Type != "Exact" {
+			return nil, fmt.Errorf("header match type %s is not supported", header.Type)
+		}
+		criteria = append(criteria, map[string]interface{}{
+			"name": "requestHeader",
+			"options": map[string]interface{}{
+				"headerName":              header.Name,
+				"matchOperator":           "IS_ONE_OF",
+				"values":                  []interface{}{header.Value},
+				"matchWildcardName":       false,
+				"matchWildcardValue":      false,
+				"matchCaseSensitiveValue": true,
+			},
+		})
+	}
+	c.headers = len(match.Headers)
+	for _, param := range match.QueryParams {
+		if param.Type != "" && param.Type != "Exact" {
+			return nil, fmt.Errorf("query parameter match type %s is not supported", param.Type)
+		}
+		criteria = append(criteria, map[string]interface{}{
+			"name": "queryStringParameter",
+			"options": map[string]interface{}{
+				"parameterName":          param.Name,
+				"matchOperator":          "IS_ONE_OF",
+				"values":                 []interface{}{param.Value},
+				"matchWildcardName":      false,
+				"matchCaseSensitiveName": true,
+				"matchWildcardValue":     false,
+				"escapeValue":            false,
+			},
+		})
+	}
+	c.queryParams = len(match.QueryParams)
+	if criteria == nil {
+		criteria = []interface{}{}
+	}
+	c.rule = map[string]interface{}{"criteria": criteria, "criteriaMustSatisfy": "all"}
+	return c, nil
+}
+
+// compileFilters renders the header modifiers and redirects of a rule as behaviors
+func compileFilters(filters []httpRouteFilter) ([]interface{}, error) {
+	var behaviors []interface{}
+	for _, filter := range filters {
+		switch {
+		case filter.Type == "RequestHeaderModifier" && filter.RequestHeaderModifier != nil:
+			behaviors = append(behaviors, headerBehaviors("modifyOutgoingRequestHeader", filter.RequestHeaderModifier)...)
+		case filter.Type == "ResponseHeaderModifier" && filter.ResponseHeaderModifier != nil:
+			behaviors = append(behaviors, headerBehaviors("modifyOutgoingResponseHeader", filter.ResponseHeaderModifier)...)
+		case filter.Type == "RequestRedirect" && filter.RequestRedirect != nil:
+			behavior, err := redirectBehavior(filter.RequestRedirect)
+			if err != nil {
+				return nil, err
+			}
+			behaviors = append(behaviors, behavior)
+		default:
+			return nil, fmt.Errorf("filter %s is not supported", filter.Type)
+		}
+	}
+	return behaviors, nil
+}
+
+// headerBehaviors renders a header modifier. Akamai only modifies headers that exist, so a set
+// header is removed and added again.
+func headerBehaviors(name string, modifier *httpHeaderModifier) []interface{} {
+	remove := func(header string) interface{} {
+		return map[string]interface{}{
+			"name": name,
+			"options": map[string]interface{}{
+				"action":                   "DELETE",
+				"standardDeleteHeaderName": "OTHER",
+				"customHeaderName":         header,
+			},
+		}
+	}
+	add := func(header, value string) interface{} {
+		return map[string]interface{}{
+			"name": name,
+			"options": map[string]interface{}{
+				"action":                "ADD",
+				"standardAddHeaderName": "OTHER",
+				"customHeaderName":      header,
+				"headerValue":           value,
+			},
+		}
+	}
+	var behaviors []interface{}
+	for _, header := range modifier.Set {
+		behaviors = append(behaviors, remove(header.Name), add(header.Name, header.Value))
+	}
+	for _, header := range modifier.Add {
+		behaviors = append(behaviors, add(header.Name, header.Value))
+	}
+	for _, header := range modifier.Remove {
+		behaviors = append(behaviors, remove(header))
+	}
+	return behaviors
+}
+
+// redirectBehavior renders a request redirect. Akamai keeps the port of the request and can't
+// replace path prefixes.
+func redirectBehavior(redirect *httpRequestRedirect) (interface{}, error) {
+	options := map[string]interface{}{
+		"mobileDefaultChoice": "DEFAULT",
+		"destinationProtocol": "SAME_AS_REQUEST",
+		"destinationHostname": "SAME_AS_REQUEST",
+		"destinationPath":     "SAME_AS_REQUEST",
+		"queryString":         "APPEND",
+		"responseCode":        int64(302),
+	}
+	if redirect.Scheme != nil {
+		options["destinationProtocol"] = strings.ToUpper(*redirect.Scheme)
+	}
+	if redirect.Hostname != nil {
+		options["destinationHostname"] = "OTHER"
+		options["destinationHostnameOther"] = *redirect.Hostname
+	}
+	if redirect.Path != nil {
+		if redirect.Path.Type != "ReplaceFullPath" || redirect.Path.ReplaceFullPath == nil {
+			return nil, fmt.Errorf("redirect path modifier %s is not supported", redirect.Path.Type)
+		}
+		options["destinationPath"] = "OTHER"
+		options["destinationPathOther"] = *redirect.Path.ReplaceFullPath
+	}
+	if redirect.Port != nil {
+		return nil, fmt.Errorf("redirect ports are not supported")
+	}
+	if redirect.StatusCode != nil {
+		switch *redirect.StatusCode {
+		case 301, 302, 303, 307:
+			options["responseCode"] = *redirect.StatusCode
+		default:
+			return nil, fmt.Errorf("redirect status code %d is not supported", *redirect.StatusCode)
+		}
+	}
+	return map[string]interface{}{"name": "redirect", "options": options}, nil
+}
+
+// hasRedirect reports whether the filters redirect the requests, which then need no backend
+func hasRedirect(filters []httpRouteFilter) bool {
+	for _, filter := range filters {
+		if filter.Type == "RequestRedirect" {
+			return true
+		}
+	}
+	return false
+}
+
+// ruleBackend resolves the backend of a rule. Akamai can't split traffic by weight, so a rule has
+// at most one backend with a weight; nil means the rule has none.
+func ruleBackend(namespace string, rule httpRouteRule, resolve backendResolver) (*backendOrigin, error) {
+	var weighted []httpBackendRef
+	for _, ref := range rule.BackendRefs {
+		if len(ref.Filters) > 0 {
+			return nil, fmt.Errorf("backend filters are not supported")
+		}
+		if ref.Weight == nil || *ref.Weight > 0 {
+			weighted = append(weighted, ref)
+		}
+	}
+	switch len(weighted) {
+	case 0:
+		return nil, nil
+	case 1:
+		return resolve(namespace, weighted[0])
+	default:
+		return nil, fmt.Errorf("traffic splitting between backends is not supported")
+	}
+}
+
+// routeOriginBehavior renders the origin behavior of a backend. Backends on other ports than 80
+// and 443 are reached on their port for both HTTP and HTTPS.
+func routeOriginBehavior(origin *backendOrigin) interface{} {
+	behavior := originBehavior(akamaiV1alpha1.OriginServer{Hostname: origin.Hostname})
+	if origin.Port != 0 && origin.Port != 80 && origin.Port != 443 {
+		options := behavior["options"].(map[string]interface{})
+		options["httpPort"] = origin.Port
+		options["httpsPort"] = origin.Port
+	}
+	return behavior
+}
+
+// sortCompiledRules orders the compiled rules so more specific matches come later and override
+// less specific ones, as Akamai applies child rules in order: exact paths before prefixes, then
+// longer prefixes, methods, more headers and more query parameters. Among equally specific
+// matches, the ones of older routes and earlier rules win.
+func sortCompiledRules(rules []compiledRule) {
+	sort.SliceStable(rules, func(i, j int) bool {
+		a, b := rules[i], rules[j]
+		switch {
+		case a.exact != b.exact:
+			return b.exact
+		case a.prefix != b.prefix:
+			return a.prefix < b.prefix
+		case a.method != b.method:
+			return b.method
+		case a.headers != b.headers:
+			return a.headers < b.headers
+		case a.queryParams != b.queryParams:
+			return a.queryParams < b.queryParams
+		}
+		return a.order > b.order
+	})
+}
+
+// listenerHostnames intersects the hostname of a listener with the hostnames of a route. ok is
+// false when they don't intersect; nil hostnames match all.
+func listenerHostnames(listener string, route []string) (hostnames []string, ok bool) {
+	if listener == "" {
+		return route, true
+	}
+	if len(route) == 0 {
+		return []string{listener}, true
+	}
+	for _, hostname := range route {
+		switch {
+		case hostnameMatches(listener, hostname):
+			hostnames = append(hostnames, hostname)
+		case hostnameMatches(hostname, listener):
+			hostnames = append(hostnames, listener)
+		}
+	}
+	return hostnames, len(hostnames) > 0
+}
+
+// hostnameMatches reports whether the hostname matches the pattern, which may be a wildcard like
+// *.example.com matching one or more labels
+func hostnameMatches(pattern, hostname string) bool {
+	pattern, hostname = strings.ToLower(pattern), strings.ToLower(hostname)
+	if pattern == hostname {
+		return true
+	}
+	suffix, ok := strings.CutPrefix(pattern, "*")
+	return ok && strings.HasSuffix(hostname, suffix) && len(hostname) > len(suffix) && !strings.HasPrefix(hostname, "*")
+}
+
+// stringValues converts strings to the values of an unstructured object
+func stringValues(values []string) []interface{} {
+	result := make([]interface{}, 0, len(values))
+	for _, value := range values {
+		result = append(result, value)
+	}
+	return result
+}
@@ -0,0 +1,349 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+)
+
+// gatewayAPIObject builds an unstructured Gateway API object
+func gatewayAPIObject(gvk schema.GroupVersionKind, namespace, name string, spec map[string]interface{}) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{"spec": spec}}
+	obj.SetGroupVersionKind(gvk)
+	obj.SetNamespace(namespace)
+	obj.SetName(name)
+	obj.SetGeneration(1)
+	return obj
+}
+
+// httpRouteObject builds an HTTPRoute attached to the web Gateway in the shop namespace
+func httpRouteObject(namespace string, rules ...interface{}) *unstructured.Unstructured {
+	return gatewayAPIObject(httpRouteGVK, namespace, "web", map[string]interface{}{
+		"parentRefs": []interface{}{map[string]interface{}{"name": "web", "namespace": "shop"}},
+		"hostnames":  []interface{}{"WWW.example.com", "*.example.org"},
+		"rules":      rules,
+	})
+}
+
+// routeParentCondition returns a condition the operator reported for the web Gateway in the
+// status of the route
+func routeParentCondition(route *unstructured.Unstructured, conditionType string) *metav1.Condition {
+	parents, _, _ := unstructured.NestedSlice(route.Object, "status", "parents")
+	for _, parent := range parents {
+		entry := parent.(map[string]interface{})
+		if entry["controllerName"] == "akamai.com/gateway-controller" {
+			return meta.FindStatusCondition(unstructuredConditions(entry["conditions"]), conditionType)
+		}
+	}
+	return nil
+}
+
+func TestGatewayReconcile(t *testing.T) {
+	ctx := context.Background()
+	apiRule := map[string]interface{}{
+		"matches": []interface{}{map[string]interface{}{"path": map[string]interface{}{"type": "PathPrefix", "value": "/api"}}},
+		"filters": []interface{}{map[string]interface{}{
+			"type":                  "RequestHeaderModifier",
+			"requestHeaderModifier": map[string]interface{}{"set": []interface{}{map[string]interface{}{"name": "X-Env", "value": "prod"}}},
+		}},
+		"backendRefs": []interface{}{map[string]interface{}{"name": "api", "port": int64(8080)}},
+	}
+	webRule := map[string]interface{}{"backendRefs": []interface{}{map[string]interface{}{"name": "web", "port": int64(80)}}}
+	services := []client.Object{
+		&corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "shop", Name: "api"},
+			Spec:       corev1.ServiceSpec{Type: corev1.ServiceTypeLoadBalancer},
+			Status: corev1.ServiceStatus{LoadBalancer: corev1.LoadBalancerStatus{
+				Ingress: []corev1.LoadBalancerIngress{{Hostname: "lb-api.example.net"}},
+			}},
+		},
+		&corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "shop", Name: "web"},
+			Spec:       corev1.ServiceSpec{Type: corev1.ServiceTypeExternalName, ExternalName: "web.example.net"},
+		},
+	}
+
+	tests := []struct {
+		name                 string
+		controllerName       string
+		route                *unstructured.Unstructured
+		withoutServices      bool
+		expectedRules        []string
+		expectedOrigin       string
+		expectedAccepted     string
+		expectedResolvedRefs string
+		expectedProgrammed   string
+	}{
+		{
+			name:                 "created",
+			controllerName:       "akamai.com/gateway-controller",
+			route:                httpRouteObject("shop", apiRule, webRule),
+			expectedRules:        []string{"HTTPRoute shop/web rule 2", "HTTPRoute shop/web rule 1"},
+			expectedOrigin:       "web.example.net",
+			expectedAccepted:     "Accepted",
+			expectedResolvedRefs: "ResolvedRefs",
+			expectedProgrammed:   "Pending",
+		},
+		{
+			name:                 "backend not found",
+			controllerName:       "akamai.com/gateway-controller",
+			route:                httpRouteObject("shop", webRule),
+			withoutServices:      true,
+			expectedRules:        []string{"HTTPRoute shop/web rule 1"},
+			expectedOrigin:       "origin.example.com",
+			expectedAccepted:     "Accepted",
+			expectedResolvedRefs: "BackendNotFound",
+			expectedProgrammed:   "Pending",
+		},
+		{
+			name:           "unsupported filter",
+			controllerName: "akamai.com/gateway-controller",
+			route: httpRouteObject("shop", map[string]interface{}{
+				"filters": []interface{}{map[string]interface{}{"type": "URLRewrite"}},
+			}),
+			expectedAccepted:     "UnsupportedValue",
+			expectedResolvedRefs: "ResolvedRefs",
+			expectedProgrammed:   "Invalid",
+		},
+		{
+			name:                 "namespace not allowed",
+			controllerName:       "akamai.com/gateway-controller",
+			route:                httpRouteObject("blog", webRule),
+			expectedAccepted:     "NotAllowedByListeners",
+			expectedResolvedRefs: "ResolvedRefs",
+			expectedProgrammed:   "Invalid",
+		},
+		{
+			name:           "other controller",
+			controllerName: "example.com/gateway-controller",
+			route:          httpRouteObject("shop", webRule),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			class := gatewayAPIObject(gatewayClassGVK, "", "akamai", map[string]interface{}{"controllerName": tt.controllerName})
+			class.SetAnnotations(map[string]string{
+				AnnotationIngressContractID: "ctr_1",
+				AnnotationIngressGroupID:    "grp_2",
+				AnnotationIngressProductID:  "prd_Fresca",
+			})
+			gateway := gatewayAPIObject(gatewayGVK, "shop", "web", map[string]interface{}{
+				"gatewayClassName": "akamai",
+				"listeners": []interface{}{
+					map[string]interface{}{"name": "http", "port": int64(80), "protocol": "HTTP"},
+					map[string]interface{}{"name": "https", "port": int64(443), "protocol": "HTTPS", "hostname": "*.example.com"},
+				},
+			})
+			if tt.withoutServices {
+				gateway.SetAnnotations(map[string]string{AnnotationIngressOriginHostname: "origin.example.com"})
+			}
+			objects := []client.Object{class, gateway, tt.route}
+			if !tt.withoutServices {
+				objects = append(objects, services...)
+			}
+			scheme := runtime.NewScheme()
+			if err := akamaiV1alpha1.AddToScheme(scheme); err != nil {
+				t.Fatalf("failed to build scheme: %v", err)
+			}
+			if err := clientgoscheme.AddToScheme(scheme); err != nil {
+				t.Fatalf("failed to build scheme: %v", err)
+			}
+			fakeClient := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(objects...).
+				WithStatusSubresource(class, gateway, tt.route).
+				Build()
+			r := &GatewayReconciler{Client: fakeClient, Scheme: scheme, ControllerName: "akamai.com/gateway-controller"}
+			key := types.NamespacedName{Namespace: "shop", Name: "web"}
+
+			if _, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: key}); err != nil {
+				t.Fatalf("Reconcile() error = %v", err)
+			}
+			if err := r.Get(ctx, key, gateway); err != nil {
+				t.Fatalf("failed to get gateway: %v", err)
+			}
+			if err := r.Get(ctx, client.ObjectKeyFromObject(tt.route), tt.route); err != nil {
+				t.Fatalf("failed to get route: %v", err)
+			}
+
+			accepted, resolvedRefs := routeParentCondition(tt.route, "Accepted"), routeParentCondition(tt.route, "ResolvedRefs")
+			if tt.expectedAccepted == "" {
+				if accepted != nil || slices.Contains(gateway.GetFinalizers(), GatewayFinalizerName) {
+					t.Errorf("route status = %v, finalizers = %v, expected the Gateway to be ignored", tt.route.Object["status"], gateway.GetFinalizers())
+				}
+				return
+			}
+			if accepted == nil || accepted.Reason != tt.expectedAccepted || resolvedRefs == nil || resolvedRefs.Reason != tt.expectedResolvedRefs {
+				t.Errorf("route conditions = %+v, %+v, expected %s and %s", accepted, resolvedRefs, tt.expectedAccepted, tt.expectedResolvedRefs)
+			}
+			conditions, _, _ := unstructured.NestedSlice(gateway.Object, "status", "conditions")
+			if programmed := meta.FindStatusCondition(unstructuredConditions(conditions), "Programmed"); programmed == nil || programmed.Reason != tt.expectedProgrammed {
+				t.Errorf("gateway conditions = %+v, expected Programmed %s", conditions, tt.expectedProgrammed)
+			}
+
+			var property akamaiV1alpha1.AkamaiProperty
+			err := r.Get(ctx, types.NamespacedName{Name: "shop.web"}, &property)
+			if tt.expectedRules == nil {
+				if !apierrors.IsNotFound(err) {
+					t.Errorf("property = %+v, error = %v, expected none", property.Spec, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("failed to get property: %v", err)
+			}
+			var rules []string
+			for _, child := range property.Spec.Rules.Children {
+				var rule struct {
+					Name string `json:"name"`
+				}
+				if err := json.Unmarshal(child.Raw, &rule); err != nil {
+					t.Fatalf("failed to decode child rule: %v", err)
+				}
+				rules = append(rules, rule.Name)
+			}
+			if !slices.Equal(rules, tt.expectedRules) {
+				t.Errorf("child rules = %v, expected %v", rules, tt.expectedRules)
+			}
+			spec := property.Spec
+			if spec.Origin == nil || spec.Origin.Hostname != tt.expectedOrigin {
+				t.Errorf("origin = %+v, expected %s", spec.Origin, tt.expectedOrigin)
+			}
+			expectedHostnames := []akamaiV1alpha1.Hostname{{CNAMEFrom: "www.example.com", CNAMETo: "shop.web.edgekey.net", CertProvisioningType: "DEFAULT"}}
+			if !slices.Equal(spec.Hostnames, expectedHostnames) || spec.ContractID != "ctr_1" || spec.Redirect == nil {
+				t.Errorf("spec = %+v, expected the class defaults with an Enhanced TLS edge hostname for www.example.com", spec)
+			}
+			if !slices.Contains(gateway.GetFinalizers(), GatewayFinalizerName) {
+				t.Errorf("finalizers = %v, expected the gateway finalizer", gateway.GetFinalizers())
+			}
+			addresses, _, _ := unstructured.NestedSlice(gateway.Object, "status", "addresses")
+			listeners, _, _ := unstructured.NestedSlice(gateway.Object, "status", "listeners")
+			if len(addresses) != 1 || addresses[0].(map[string]interface{})["value"] != "shop.web.edgekey.net" ||
+				len(listeners) != 2 || listeners[1].(map[string]interface{})["attachedRoutes"] != int64(1) {
+				t.Errorf("gateway status = %v, expected the edge hostname and an attached route per listener", gateway.Object["status"])
+			}
+		})
+	}
+}
+
+func TestCompileHTTPRoute(t *testing.T) {
+	tests := []struct {
+		name        string
+		rule        string
+		expectError string
+		expected    []string
+	}{
+		{
+			name:     "path prefix",
+			rule:     `{"matches": [{"path": {"type": "PathPrefix", "value": "/api/"}}], "backendRefs": [{"name": "api", "port": 8080}]}`,
+			expected: []string{`"values":["/api","/api/*"]`, `"hostname":"api.example.net"`, `"httpPort":8080`},
+		},
+		{
+			name:     "exact path, method and header",
+			rule:     `{"matches": [{"path": {"type": "Exact", "value": "/login"}, "method": "post", "headers": [{"name": "X-Beta", "value": "1"}]}], "backendRefs": [{"name": "api"}]}`,
+			expected: []string{`"values":["/login"]`, `"name":"requestMethod","options":{"matchOperator":"IS","value":"POST"}`, `"headerName":"X-Beta"`},
+		},
+		{
+			name: "redirect",
+			rule: `{"filters": [{"type": "RequestRedirect", "requestRedirect": {"scheme": "https", "hostname": "www.example.com", "statusCode": 301}}]}`,
+			expected: []string{`"name":"redirect"`, `"destinationHostnameOther":"www.example.com"`, `"destinationProtocol":"HTTPS"`,
+				`"responseCode":301`},
+		},
+		{
+			name: "header modifiers",
+			rule: `{"filters": [{"type": "ResponseHeaderModifier", "responseHeaderModifier": {"add": [{"name": "X-Served-By", "value": "akamai"}], "remove": ["Server"]}}], "backendRefs": [{"name": "api"}]}`,
+			expected: []string{`"name":"modifyOutgoingResponseHeader","options":{"action":"ADD"`, `"customHeaderName":"X-Served-By"`,
+				`"action":"DELETE","customHeaderName":"Server"`},
+		},
+		{
+			name:     "no backend",
+			rule:     `{}`,
+			expected: []string{`"name":"constructResponse"`, `"responseCode":500`},
+		},
+		{
+			name:        "regular expression",
+			rule:        `{"matches": [{"path": {"type": "RegularExpression", "value": "/v[0-9]+"}}]}`,
+			expectError: "path match type RegularExpression is not supported",
+		},
+		{
+			name:        "traffic splitting",
+			rule:        `{"backendRefs": [{"name": "api", "weight": 90}, {"name": "canary", "weight": 10}]}`,
+			expectError: "traffic splitting between backends is not supported",
+		},
+	}
+
+	resolve := func(_ string, ref httpBackendRef) (*backendOrigin, error) {
+		origin := &backendOrigin{Hostname: ref.Name + ".example.net"}
+		if ref.Port != nil {
+			origin.Port = *ref.Port
+		}
+		return origin, nil
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var rule httpRouteRule
+			if err := json.Unmarshal([]byte(tt.rule), &rule); err != nil {
+				t.Fatalf("failed to decode rule: %v", err)
+			}
+			route := gatewayAPIObject(httpRouteGVK, "shop", "web", nil)
+			compiled, _, err := compileHTTPRoute(route, &httpRouteSpec{Rules: []httpRouteRule{rule}}, []string{"www.example.com"}, 0, resolve)
+			if tt.expectError != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.expectError) {
+					t.Errorf("compileHTTPRoute() error = %v, expected %q", err, tt.expectError)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("compileHTTPRoute() error = %v", err)
+			}
+			raw, err := json.Marshal(compiled[0].rule)
+			if err != nil {
+				t.Fatalf("failed to render rule: %v", err)
+			}
+			for _, expected := range append(tt.expected, `"name":"hostname","options":{"matchOperator":"IS_ONE_OF","values":["www.example.com"]}`) {
+				if !strings.Contains(string(raw), expected) {
+					t.Errorf("rule = %s, expected it to contain %s", raw, expected)
+				}
+			}
+		})
+	}
+}
+
+func TestListenerHostnames(t *testing.T) {
+	tests := []struct {
+		listener     string
+		route        []string
+		expected     []string
+		expectedOkay bool
+	}{
+		{listener: "", route: nil, expected: nil, expectedOkay: true},
+		{listener: "www.example.com", route: nil, expected: []string{"www.example.com"}, expectedOkay: true},
+		{listener: "*.example.com", route: []string{"www.example.com", "example.com", "www.example.org"}, expected: []string{"www.example.com"}, expectedOkay: true},
+		{listener: "www.example.com", route: []string{"*.example.com"}, expected: []string{"www.example.com"}, expectedOkay: true},
+		{listener: "www.example.com", route: []string{"api.example.com"}, expected: nil, expectedOkay: false},
+	}
+	for _, tt := range tests {
+		got, ok := listenerHostnames(tt.listener, tt.route)
+		if !slices.Equal(got, tt.expected) || ok != tt.expectedOkay {
+			t.Errorf("listenerHostnames(%q, %v) = %v, %v, expected %v, %v", tt.listener, tt.route, got, ok, tt.expected, tt.expectedOkay)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+// GatewayClassReconciler accepts the GatewayClasses of its controller name, so their Gateways can
+// be implemented by the GatewayReconciler
+type GatewayClassReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+
+	// ControllerName is the spec.controllerName of the GatewayClasses the operator implements
+	ControllerName string
+}
+
+//+kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=gatewayclasses,verbs=get;list;watch
+//+kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=gatewayclasses/status,verbs=get;update;patch
+
+// Reconcile sets the Accepted condition of a GatewayClass of the operator
+func (r *GatewayClassReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	class := &unstructured.Unstructured{}
+	class.SetGroupVersionKind(gatewayClassGVK)
+	if err := r.Get(ctx, req.NamespacedName, class); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+	if controllerName, _, _ := unstructured.NestedString(class.Object, "spec", "controllerName"); controllerName != r.ControllerName {
+		return ctrl.Result{}, nil
+	}
+
+	existing, _, _ := unstructured.NestedSlice(class.Object, "status", "conditions")
+	conditions := unstructuredConditions(existing)
+	meta.SetStatusCondition(&conditions, metav1.Condition{Type: "Accepted", Status: metav1.ConditionTrue, Reason: "Accepted",
+		Message: "Gateways of the class are implemented by akamai-operator", ObservedGeneration: class.GetGeneration()})
+	values := conditionValues(conditions)
+	if equality.Semantic.DeepEqual(existing, values) {
+		return ctrl.Result{}, nil
+	}
+	if err := unstructured.SetNestedSlice(class.Object, values, "status", "conditions"); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to render the status of GatewayClass %s: %w", class.GetName(), err)
+	}
+	if err := r.Status().Update(ctx, class); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to update the status of GatewayClass %s: %w", class.GetName(), err)
+	}
+	return ctrl.Result{}, nil
+}
+
+// SetupWithManager sets up the controller with the Manager
+func (r *GatewayClassReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	class := &unstructured.Unstructured{}
+	class.SetGroupVersionKind(gatewayClassGVK)
+	return ctrl.NewControllerManagedBy(mgr).
+		For(class, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Complete(r)
+}
@@ -0,0 +1,190 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+)
+
+func TestRequestedReconcile(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		lastHandled string
+		expected    string
+		requested   bool
+	}{
+		{name: "no annotation"},
+		{name: "new request", annotations: map[string]string{AnnotationReconcileRequestedAt: "2026-10-18T10:00:00Z"}, expected: "2026-10-18T10:00:00Z", requested: true},
+		{name: "already handled", annotations: map[string]string{AnnotationReconcileRequestedAt: "2026-10-18T10:00:00Z"}, lastHandled: "2026-10-18T10:00:00Z"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			property := &akamaiV1alpha1.AkamaiProperty{
+				ObjectMeta: metav1.ObjectMeta{Annotations: tt.annotations},
+				Status:     akamaiV1alpha1.AkamaiPropertyStatus{LastHandledReconcileAt: tt.lastHandled},
+			}
+			got, requested := requestedReconcile(property)
+			if got != tt.expected || requested != tt.requested {
+				t.Errorf("requestedReconcile() = (%q, %v), expected (%q, %v)", got, requested, tt.expected, tt.requested)
+			}
+		})
+	}
+}
+
+func TestSetHealthConditions(t *testing.T) {
+	tests := []struct {
+		name                string
+		phase               string
+		conditions          []metav1.Condition
+		expectedReconciling metav1.ConditionStatus
+		expectedStalled     metav1.ConditionStatus
+		expectedReason      string
+	}{
+		{name: "ready", phase: PhaseReady, expectedReconciling: metav1.ConditionFalse, expectedStalled: metav1.ConditionFalse, expectedReason: "ReconcileSucceeded"},
+		{
+			name:                "updating",
+			phase:               PhaseUpdating,
+			conditions:          []metav1.Condition{{Type: ConditionTypeReady, Status: metav1.ConditionFalse, Reason: "UpdatingAkamaiProperty"}},
+			expectedReconciling: metav1.ConditionTrue,
+			expectedStalled:     metav1.ConditionFalse,
+			expectedReason:      "UpdatingAkamaiProperty",
+		},
+		{
+			name:                "transient error is retried",
+			phase:               PhaseError,
+			conditions:          []metav1.Condition{{Type: ConditionTypeReady, Status: metav1.ConditionFalse, Reason: "FailedToUpdateRules"}},
+			expectedReconciling: metav1.ConditionTrue,
+			expectedStalled:     metav1.ConditionFalse,
+			expectedReason:      "FailedToUpdateRules",
+		},
+		{
+			name:  "invalid spec is stalled",
+			phase: PhaseError,
+			conditions: []metav1.Condition{
+				{Type: ConditionTypeReady, Status: metav1.ConditionFalse, Reason: "InvalidSpec"},
+				{Type: ConditionTypeInvalidSpec, Status: metav1.ConditionTrue, Reason: "ValidationFailed", Message: "bad rules"},
+			},
+			expectedReconciling: metav1.ConditionFalse,
+			expectedStalled:     metav1.ConditionTrue,
+			expectedReason:      "Stalled",
+		},
+		{name: "suspended", phase: PhaseSuspended, expectedReconciling: metav1.ConditionFalse, expectedStalled: metav1.ConditionFalse, expectedReason: PhaseSuspended},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			property := &akamaiV1alpha1.AkamaiProperty{
+				Status: akamaiV1alpha1.AkamaiPropertyStatus{Phase: tt.phase, Conditions: tt.conditions},
+			}
+			setHealthConditions(property)
+			reconciling := meta.FindStatusCondition(property.Status.Conditions, ConditionTypeReconciling)
+			stalled := meta.FindStatusCondition(property.Status.Conditions, ConditionTypeStalled)
+			if reconciling.Status != tt.expectedReconciling || reconciling.Reason != tt.expectedReason {
+				t.Errorf("Reconciling = %s/%s, expected %s/%s", reconciling.Status, reconciling.Reason, tt.expectedReconciling, tt.expectedReason)
+			}
+			if stalled.Status != tt.expectedStalled {
+				t.Errorf("Stalled = %s, expected %s", stalled.Status, tt.expectedStalled)
+			}
+		})
+	}
+}
+
+func TestSetReadyGeneration(t *testing.T) {
+	ready := metav1.Condition{Type: ConditionTypeReady, Status: metav1.ConditionTrue, Reason: "PropertyIsReady", ObservedGeneration: 1}
+	notReady := metav1.Condition{Type: ConditionTypeReady, Status: metav1.ConditionFalse, Reason: "FailedToUpdateRules", ObservedGeneration: 1}
+
+	tests := []struct {
+		name               string
+		observedGeneration int64
+		ready              metav1.Condition
+		expectedStatus     metav1.ConditionStatus
+		expectedReason     string
+	}{
+		{name: "generation reconciled", observedGeneration: 2, ready: ready, expectedStatus: metav1.ConditionTrue, expectedReason: "PropertyIsReady"},
+		{name: "spec edited", observedGeneration: 1, ready: ready, expectedStatus: metav1.ConditionFalse, expectedReason: ReasonNewGeneration},
+		{name: "not ready keeps its reason", observedGeneration: 1, ready: notReady, expectedStatus: metav1.ConditionFalse, expectedReason: "FailedToUpdateRules"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			property := &akamaiV1alpha1.AkamaiProperty{
+				ObjectMeta: metav1.ObjectMeta{Generation: 2},
+				Status: akamaiV1alpha1.AkamaiPropertyStatus{
+					ObservedGeneration: tt.observedGeneration,
+					Conditions:         []metav1.Condition{tt.ready},
+				},
+			}
+			changed := setReadyGeneration(property)
+			condition := meta.FindStatusCondition(property.Status.Conditions, ConditionTypeReady)
+			if condition.Status != tt.expectedStatus || condition.Reason != tt.expectedReason {
+				t.Errorf("Ready = %s/%s, expected %s/%s", condition.Status, condition.Reason, tt.expectedStatus, tt.expectedReason)
+			}
+			if changed != (tt.expectedReason == ReasonNewGeneration) {
+				t.Errorf("setReadyGeneration() = %v", changed)
+			}
+			if changed && condition.ObservedGeneration != 2 {
+				t.Errorf("Ready observedGeneration = %d, expected 2", condition.ObservedGeneration)
+			}
+		})
+	}
+}
+
+func TestReconcileSuspended(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		suspend     bool
+		message     string
+	}{
+		{name: "suspend annotation", annotations: map[string]string{AnnotationSuspend: "true"}, message: "Reconciliation is suspended by the akamai.com/suspend annotation"},
+		{name: "paused annotation", annotations: map[string]string{AnnotationPaused: "True"}, message: "Reconciliation is suspended by the akamai.com/paused annotation"},
+		{name: "spec", suspend: true, message: "Reconciliation is suspended by spec.suspend"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			property := &akamaiV1alpha1.AkamaiProperty{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "example",
+					Generation:  1,
+					Finalizers:  []string{FinalizerName},
+					Annotations: tt.annotations,
+				},
+				Spec: akamaiV1alpha1.AkamaiPropertySpec{PropertyName: "example.com", Suspend: tt.suspend},
+			}
+			// No Akamai credentials are configured: a suspended property must not need a client
+			r := newFakeReconciler(t, property)
+
+			result, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: "example"}})
+			if err != nil {
+				t.Fatalf("Reconcile() unexpected error: %v", err)
+			}
+			if !result.IsZero() {
+				t.Errorf("Reconcile() = %+v, expected no requeue", result)
+			}
+
+			var updated akamaiV1alpha1.AkamaiProperty
+			if err := r.Get(ctx, types.NamespacedName{Name: "example"}, &updated); err != nil {
+				t.Fatalf("failed to get property: %v", err)
+			}
+			if updated.Status.Phase != PhaseSuspended {
+				t.Errorf("phase = %q, expected %q", updated.Status.Phase, PhaseSuspended)
+			}
+			if ready := meta.FindStatusCondition(updated.Status.Conditions, ConditionTypeReady); ready == nil || ready.Reason != "Suspended" || ready.Message != tt.message {
+				t.Errorf("expected a Ready condition with reason Suspended and message %q, got %+v", tt.message, ready)
+			}
+			if r.AkamaiClient != nil {
+				t.Error("expected no Akamai client to be created for a suspended property")
+			}
+		})
+	}
+}
@@ -0,0 +1,300 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"testing"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v8/pkg/gtm"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+	"github.com/mmz-srf/akamai-operator/pkg/akamai"
+)
+
+// gtmAPI stubs the Global Traffic Management endpoints of one domain, recording the calls that
+// change it. Changes stay pending until propagate is called.
+type gtmAPI struct {
+	gtm.GTM
+	domain      *gtm.Domain
+	datacenters []*gtm.Datacenter
+	properties  []*gtm.Property
+	propagation string
+	calls       []string
+}
+
+func (s *gtmAPI) change(call string) {
+	s.calls = append(s.calls, call)
+	s.propagation = akamai.GTMPropagationPending
+}
+
+// propagate completes the propagation of the changes
+func (s *gtmAPI) propagate() {
+	s.propagation = akamai.GTMPropagationComplete
+}
+
+func (s *gtmAPI) GetDomain(_ context.Context, name string) (*gtm.Domain, error) {
+	if s.domain == nil || s.domain.Name != name {
+		return nil, &gtm.Error{StatusCode: http.StatusNotFound, Title: "Not Found"}
+	}
+	copied := *s.domain
+	return &copied, nil
+}
+
+func (s *gtmAPI) CreateDomain(_ context.Context, domain *gtm.Domain, queryArgs map[string]string) (*gtm.DomainResponse, error) {
+	s.change(fmt.Sprintf("create domain %s in %s", domain.Name, queryArgs["contractId"]))
+	copied := *domain
+	s.domain = &copied
+	return &gtm.DomainResponse{Resource: domain}, nil
+}
+
+func (s *gtmAPI) UpdateDomain(_ context.Context, domain *gtm.Domain, _ map[string]string) (*gtm.ResponseStatus, error) {
+	s.change("update domain " + domain.Type)
+	copied := *domain
+	s.domain = &copied
+	return &gtm.ResponseStatus{}, nil
+}
+
+func (s *gtmAPI) DeleteDomain(_ context.Context, domain *gtm.Domain) (*gtm.ResponseStatus, error) {
+	s.calls = append(s.calls, "delete domain "+domain.Name)
+	s.domain = nil
+	return &gtm.ResponseStatus{}, nil
+}
+
+func (s *gtmAPI) GetDomainStatus(_ context.Context, _ string) (*gtm.ResponseStatus, error) {
+	return &gtm.ResponseStatus{ChangeID: fmt.Sprintf("change-%d", len(s.calls)), PropagationStatus: s.propagation}, nil
+}
+
+func (s *gtmAPI) ListDatacenters(_ context.Context, _ string) ([]*gtm.Datacenter, error) {
+	var datacenters []*gtm.Datacenter
+	for _, datacenter := range s.datacenters {
+		copied := *datacenter
+		datacenters = append(datacenters, &copied)
+	}
+	return datacenters, nil
+}
+
+func (s *gtmAPI) CreateDatacenter(_ context.Context, datacenter *gtm.Datacenter, _ string) (*gtm.DatacenterResponse, error) {
+	s.change("create datacenter " + datacenter.Nickname)
+	copied := *datacenter
+	copied.DatacenterID = 3131 + len(s.datacenters)
+	s.datacenters = append(s.datacenters, &copied)
+	return &gtm.DatacenterResponse{Resource: &copied}, nil
+}
+
+func (s *gtmAPI) UpdateDatacenter(_ context.Context, datacenter *gtm.Datacenter, _ string) (*gtm.ResponseStatus, error) {
+	s.change("update datacenter " + datacenter.Nickname)
+	for i, current := range s.datacenters {
+		if current.DatacenterID == datacenter.DatacenterID {
+			copied := *datacenter
+			s.datacenters[i] = &copied
+		}
+	}
+	return &gtm.ResponseStatus{}, nil
+}
+
+func (s *gtmAPI) ListProperties(_ context.Context, _ string) ([]*gtm.Property, error) {
+	var properties []*gtm.Property
+	for _, property := range s.properties {
+		copied := *property
+		properties = append(properties, &copied)
+	}
+	return properties, nil
+}
+
+func (s *gtmAPI) UpdateProperty(_ context.Context, property *gtm.Property, _ string) (*gtm.ResponseStatus, error) {
+	s.change("put property " + property.Name)
+	copied := *property
+	s.properties = slices.DeleteFunc(s.properties, func(current *gtm.Property) bool { return current.Name == property.Name })
+	s.properties = append(s.properties, &copied)
+	return &gtm.ResponseStatus{}, nil
+}
+
+func newGtmDomainReconciler(t *testing.T, stub *gtmAPI, objects ...client.Object) *AkamaiGtmDomainReconciler {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	if err := akamaiV1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to build scheme: %v", err)
+	}
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(objects...).
+		WithStatusSubresource(&akamaiV1alpha1.AkamaiGtmDomain{}).
+		Build()
+	return &AkamaiGtmDomainReconciler{Client: fakeClient, Scheme: scheme, AkamaiClient: akamai.NewClientWithGTM(stub)}
+}
+
+func TestGtmDomainReconcile(t *testing.T) {
+	ctx := context.Background()
+	domain := &akamaiV1alpha1.AkamaiGtmDomain{
+		ObjectMeta: metav1.ObjectMeta{Name: "example.akadns.net", Generation: 1},
+		Spec: akamaiV1alpha1.AkamaiGtmDomainSpec{
+			Type:       "weighted",
+			ContractID: "ctr_C-123",
+			Datacenters: []akamaiV1alpha1.GtmDatacenterSpec{
+				{Nickname: "zurich", Country: "CH"},
+				{Nickname: "frankfurt", Country: "DE"},
+			},
+			Properties: []akamaiV1alpha1.GtmPropertySpec{{
+				Name: "origin",
+				Type: "weighted-round-robin",
+				TrafficTargets: []akamaiV1alpha1.GtmTrafficTargetSpec{
+					{Datacenter: "zurich", Weight: 80, Servers: []string{"192.0.2.10"}},
+					{Datacenter: "frankfurt", Weight: 20, Servers: []string{"198.51.100.10"}},
+				},
+				LivenessTest: &akamaiV1alpha1.GtmLivenessTestSpec{Protocol: "HTTPS", Path: "/healthz"},
+			}},
+			DeletionPolicy: akamaiV1alpha1.DeletionPolicyDelete,
+		},
+	}
+	stub := &gtmAPI{}
+	r := newGtmDomainReconciler(t, stub, domain)
+	key := types.NamespacedName{Name: domain.Name}
+	reconcile := func() (*akamaiV1alpha1.AkamaiGtmDomain, ctrl.Result) {
+		t.Helper()
+		stub.calls = nil
+		result, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+		if err != nil {
+			t.Fatalf("Reconcile() error = %v", err)
+		}
+		var got akamaiV1alpha1.AkamaiGtmDomain
+		if err := r.Get(ctx, key, &got); err != nil {
+			t.Fatalf("failed to get GTM domain: %v", err)
+		}
+		return &got, result
+	}
+
+	// The domain, its datacenters and the property are created and propagate
+	got, result := reconcile()
+	expected := []string{
+		"create domain example.akadns.net in ctr_C-123",
+		"create datacenter zurich",
+		"create datacenter frankfurt",
+		"put property origin",
+	}
+	if !slices.Equal(stub.calls, expected) {
+		t.Errorf("calls = %q, expected %q", stub.calls, expected)
+	}
+	if got.Status.Phase != PhaseActivating || got.Status.PropagationStatus != akamai.GTMPropagationPending ||
+		result.RequeueAfter != gtmPropagationPollInterval || got.Status.Datacenters["frankfurt"] != 3132 {
+		t.Errorf("status = %+v, result = %+v, expected the domain to be propagating", got.Status, result)
+	}
+	property := stub.properties[0]
+	if property.HandoutMode != "normal" || property.DynamicTTL != defaultGTMDynamicTTL || property.TrafficTargets[0].Weight != 80 ||
+		property.LivenessTests[0].TestObjectPort != 443 {
+		t.Errorf("property = %+v, expected the defaults and the weights of the spec", property)
+	}
+
+	// Once propagated the domain is ready and nothing changes
+	stub.propagate()
+	got, result = reconcile()
+	if len(stub.calls) != 0 || got.Status.Phase != PhaseReady || result.RequeueAfter != gtmDomainResyncInterval {
+		t.Errorf("calls = %q, status = %+v, expected the propagated domain to be ready", stub.calls, got.Status)
+	}
+
+	// A weight changed outside the operator is restored, keeping the settings the spec doesn't cover
+	stub.properties[0].TrafficTargets = []*gtm.TrafficTarget{
+		{DatacenterID: 3132, Enabled: true, Weight: 50, Servers: []string{"198.51.100.10"}},
+		{DatacenterID: 3131, Enabled: true, Weight: 50, Servers: []string{"192.0.2.10"}},
+	}
+	stub.properties[0].HandoutLimit = 1
+	got, _ = reconcile()
+	if !slices.Equal(stub.calls, []string{"put property origin"}) || stub.properties[0].HandoutLimit != 1 ||
+		stub.properties[0].TrafficTargets[0].Weight != 80 {
+		t.Errorf("calls = %q, property = %+v, expected the weights to be restored", stub.calls, stub.properties[0])
+	}
+	if !slices.Equal(got.Status.LastChanges, []string{"update property origin"}) {
+		t.Errorf("lastChanges = %q, expected the property update", got.Status.LastChanges)
+	}
+
+	// Deleting the resource deletes the domain with the Delete deletion policy
+	if err := r.Delete(ctx, got); err != nil {
+		t.Fatalf("failed to delete GTM domain: %v", err)
+	}
+	stub.calls = nil
+	if _, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: key}); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	if !slices.Equal(stub.calls, []string{"delete domain example.akadns.net"}) || stub.domain != nil {
+		t.Errorf("calls = %q, expected the domain to be deleted", stub.calls)
+	}
+}
+
+func TestGtmDomainDeniedChange(t *testing.T) {
+	ctx := context.Background()
+	domain := &akamaiV1alpha1.AkamaiGtmDomain{
+		ObjectMeta: metav1.ObjectMeta{Name: "example.akadns.net", Generation: 1},
+		Spec:       akamaiV1alpha1.AkamaiGtmDomainSpec{Type: "full", ContractID: "ctr_C-123"},
+	}
+	stub := &gtmAPI{domain: &gtm.Domain{Name: "example.akadns.net", Type: "basic"}, propagation: akamai.GTMPropagationComplete}
+	r := newGtmDomainReconciler(t, stub, domain)
+	r.AkamaiClient = akamai.NewClientWithGTM(&deniedGtmAPI{gtmAPI: stub})
+	result, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: domain.Name}})
+	if err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	var got akamaiV1alpha1.AkamaiGtmDomain
+	if err := r.Get(ctx, types.NamespacedName{Name: domain.Name}, &got); err != nil {
+		t.Fatalf("failed to get GTM domain: %v", err)
+	}
+	if !slices.Equal(stub.calls, []string{"update domain full"}) || got.Status.Phase != PhaseError ||
+		result.RequeueAfter != gtmDomainErrorRetryInterval {
+		t.Errorf("calls = %q, status = %+v, expected the denied change to be an error", stub.calls, got.Status)
+	}
+}
+
+// deniedGtmAPI denies every change of the domain
+type deniedGtmAPI struct {
+	*gtmAPI
+}
+
+func (s *deniedGtmAPI) GetDomainStatus(_ context.Context, _ string) (*gtm.ResponseStatus, error) {
+	return &gtm.ResponseStatus{ChangeID: "change-1", PropagationStatus: akamai.GTMPropagationDenied, Message: "invalid domain type"}, nil
+}
+
+func TestGtmPropertyFailoverWeights(t *testing.T) {
+	tests := []struct {
+		name     string
+		targets  []akamaiV1alpha1.GtmTrafficTargetSpec
+		expected []float64
+	}{
+		{
+			name: "first target is the primary",
+			targets: []akamaiV1alpha1.GtmTrafficTargetSpec{
+				{Datacenter: "zurich", Weight: 10}, {Datacenter: "frankfurt", Weight: 90},
+			},
+			expected: []float64{1, 0},
+		},
+		{
+			name: "disabled targets are skipped",
+			targets: []akamaiV1alpha1.GtmTrafficTargetSpec{
+				{Datacenter: "zurich", Disabled: true}, {Datacenter: "frankfurt"},
+			},
+			expected: []float64{0, 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			property := &gtm.Property{}
+			spec := akamaiV1alpha1.GtmPropertySpec{Name: "api", Type: "failover", TrafficTargets: tt.targets}
+			if err := setGTMProperty(property, spec, map[string]int{"zurich": 1, "frankfurt": 2}); err != nil {
+				t.Fatalf("setGTMProperty() error = %v", err)
+			}
+			var weights []float64
+			for _, target := range property.TrafficTargets {
+				weights = append(weights, target.Weight)
+			}
+			if !slices.Equal(weights, tt.expected) {
+				t.Errorf("weights = %v, expected %v", weights, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package controllers
+
+import (
+	"reflect"
+	"testing"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+	"github.com/mmz-srf/akamai-operator/pkg/lint"
+)
+
+func TestPendingGuardrails(t *testing.T) {
+	findings := []lint.GuardFinding{
+		{Guardrail: lint.GuardrailCachingRemoved, Path: "default/Static", Message: "removed"},
+		{Guardrail: lint.GuardrailZeroTTLAtDefault, Path: "default", Message: "zero"},
+	}
+
+	tests := []struct {
+		name         string
+		acknowledged []string
+		version      int
+		expected     []string
+	}{
+		{name: "nothing acknowledged", version: 12, expected: []string{"caching-removed/v12", "zero-ttl-at-default/v12"}},
+		{name: "one acknowledged", acknowledged: []string{"caching-removed/v12"}, version: 12, expected: []string{"zero-ttl-at-default/v12"}},
+		{name: "acknowledgement of another version", acknowledged: []string{"caching-removed/v11", "zero-ttl-at-default/v11"}, version: 12,
+			expected: []string{"caching-removed/v12", "zero-ttl-at-default/v12"}},
+		{name: "all acknowledged", acknowledged: []string{"caching-removed/v12", "zero-ttl-at-default/v12"}, version: 12},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			property := &akamaiV1alpha1.AkamaiProperty{Spec: akamaiV1alpha1.AkamaiPropertySpec{
+				Activation: &akamaiV1alpha1.ActivationSpec{Network: "PRODUCTION", AcknowledgeGuardrails: tt.acknowledged},
+			}}
+			var ids []string
+			for _, pending := range pendingGuardrails(property, findings, tt.version) {
+				ids = append(ids, pending.ID)
+			}
+			if !reflect.DeepEqual(ids, tt.expected) {
+				t.Errorf("pendingGuardrails() = %v, expected %v", ids, tt.expected)
+			}
+		})
+	}
+}
+
+func TestGuardrailNames(t *testing.T) {
+	operatorGuardrails := []string{lint.GuardrailZeroTTLAtDefault, lint.GuardrailCachingRemoved}
+	property := &akamaiV1alpha1.AkamaiProperty{Spec: akamaiV1alpha1.AkamaiPropertySpec{
+		Activation: &akamaiV1alpha1.ActivationSpec{Guardrails: []string{lint.GuardrailCachingRemoved, lint.GuardrailNoStoreAtDefault}},
+	}}
+
+	expected := []string{lint.GuardrailCachingRemoved, lint.GuardrailNoStoreAtDefault, lint.GuardrailZeroTTLAtDefault}
+	if got := guardrailNames(operatorGuardrails, property); !reflect.DeepEqual(got, expected) {
+		t.Errorf("guardrailNames() = %v, expected %v", got, expected)
+	}
+}
@@ -0,0 +1,92 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/runtime"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+)
+
+func TestVariableUsageMissing(t *testing.T) {
+	parent := map[string]interface{}{
+		"name":      "default",
+		"variables": []interface{}{map[string]interface{}{"name": "PMUSER_ORIGIN"}},
+		"behaviors": []interface{}{
+			map[string]interface{}{"name": "origin", "options": map[string]interface{}{"hostname": "{{user.PMUSER_ORIGIN}}"}},
+			map[string]interface{}{"name": "include", "options": map[string]interface{}{"id": "inc_123"}},
+		},
+		"children": []interface{}{
+			map[string]interface{}{
+				"name":      "Tokens",
+				"criteria":  []interface{}{map[string]interface{}{"name": "matchVariable", "options": map[string]interface{}{"variableName": "PMUSER_TOKEN"}}},
+				"behaviors": []interface{}{map[string]interface{}{"name": "modifyOutgoingRequestHeader", "options": map[string]interface{}{"newHeaderValue": "{{ user.PMUSER_TOKEN }}-{{builtin.AK_HOST}}"}}},
+			},
+		},
+	}
+	include := map[string]interface{}{
+		"name": "default",
+		"behaviors": []interface{}{
+			map[string]interface{}{"name": "setVariable", "options": map[string]interface{}{"variableName": "PMUSER_REGION"}},
+			map[string]interface{}{"name": "caching", "options": map[string]interface{}{"ttl": "{{user.PMUSER_TOKEN}}"}},
+		},
+	}
+
+	usage := newVariableUsage()
+	if err := usage.addTree(parent, "rules"); err != nil {
+		t.Fatalf("addTree() error = %v", err)
+	}
+	if !reflect.DeepEqual(usage.includes, []string{"inc_123"}) {
+		t.Errorf("includes = %v, expected [inc_123]", usage.includes)
+	}
+	if err := usage.addTree(include, "include inc_123"); err != nil {
+		t.Fatalf("addTree() error = %v", err)
+	}
+
+	expected := []string{
+		"PMUSER_REGION (used in include inc_123)",
+		"PMUSER_TOKEN (used in rules, include inc_123)",
+	}
+	if got := usage.missing(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("missing() = %v, expected %v", got, expected)
+	}
+}
+
+func TestCheckIncludeVariables(t *testing.T) {
+	tests := []struct {
+		name        string
+		variables   []akamaiV1alpha1.PropertyVariable
+		expectedErr error
+	}{
+		{name: "undeclared variable", expectedErr: errUndefinedVariables},
+		{name: "declared in spec.variables", variables: []akamaiV1alpha1.PropertyVariable{{Name: "PMUSER_ORIGIN", Value: "origin.example.com"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			property := &akamaiV1alpha1.AkamaiProperty{
+				Spec: akamaiV1alpha1.AkamaiPropertySpec{
+					Variables: tt.variables,
+					Rules: &akamaiV1alpha1.PropertyRules{
+						Name: "default",
+						Children: []runtime.RawExtension{
+							{Raw: []byte(`{"name":"Origin","behaviors":[{"name":"origin","options":{"hostname":"{{user.PMUSER_ORIGIN}}"}}]}`)},
+						},
+					},
+				},
+			}
+			r := newFakeReconciler(t)
+			err := r.checkIncludeVariables(context.Background(), property)
+			if !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("checkIncludeVariables() error = %v, expected %v", err, tt.expectedErr)
+			}
+			if err != nil && !strings.Contains(err.Error(), "PMUSER_ORIGIN (used in rules)") {
+				t.Errorf("checkIncludeVariables() error = %q, expected it to name PMUSER_ORIGIN", err)
+			}
+		})
+	}
+}
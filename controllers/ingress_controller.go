@@ -0,0 +1,392 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/events"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+)
+
+const (
+	// IngressFinalizerName is the finalizer added to the Ingresses an AkamaiProperty is synthesized
+	// for, so the property is deleted with the Ingress
+	IngressFinalizerName = "akamai.com/ingress-finalizer"
+
+	// IngressNamespaceLabel and IngressNameLabel record the Ingress an AkamaiProperty is
+	// synthesized for
+	IngressNamespaceLabel = "akamai.com/ingress-namespace"
+	IngressNameLabel      = "akamai.com/ingress-name"
+
+	// Annotations of an Ingress overriding the defaults of the synthesized AkamaiProperty
+	AnnotationIngressContractID        = "akamai.com/contract-id"
+	AnnotationIngressGroupID           = "akamai.com/group-id"
+	AnnotationIngressProductID         = "akamai.com/product-id"
+	AnnotationIngressCPCode            = "akamai.com/cp-code"
+	AnnotationIngressOriginHostname    = "akamai.com/origin-hostname"
+	AnnotationIngressActivationNetwork = "akamai.com/activation-network"
+	AnnotationIngressNotifyEmails      = "akamai.com/notify-emails"
+	AnnotationIngressCertificateRef    = "akamai.com/certificate-ref"
+)
+
+// IngressReconciler synthesizes an AkamaiProperty for each Ingress of its IngressClass: the
+// hostnames come from the rules of the Ingress and the origin from the address of the load
+// balancer in the status of the Ingress, so app teams get CDN delivery without writing property
+// specs. The traffic itself is served by the cluster's ingress controller.
+type IngressReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+
+	// IngressClassName is the ingressClassName of the Ingresses an AkamaiProperty is synthesized for
+	IngressClassName string
+
+	// ContractID, GroupID and ProductID are the defaults of the synthesized properties
+	ContractID string
+	GroupID    string
+	ProductID  string
+
+	// CPCodeID is the default CP code of the synthesized properties; 0 leaves the cpCode behavior
+	// of the property's rule tree in place
+	CPCodeID int64
+
+	// OriginHostname is the origin of Ingresses whose status has no load balancer address yet,
+	// e.g. the DNS name of the cluster's shared load balancer
+	OriginHostname string
+
+	// ActivationNetwork and NotifyEmails activate the versions of the synthesized properties;
+	// without notification emails the versions are not activated
+	ActivationNetwork string
+	NotifyEmails      []string
+
+	// EdgeHostnameTemplate renders the domain prefix of the edge hostnames, like
+	// --edge-hostname-template; empty uses the name of the property
+	EdgeHostnameTemplate string
+
+	// AccountNamespaces are the namespaces whose Ingresses may choose the contract, group,
+	// product and CPS certificate of their property with annotations; the Ingresses of other
+	// namespaces get the defaults
+	AccountNamespaces []string
+
+	// PropagatedLabels are the keys of the Ingress labels copied onto the synthesized properties
+	PropagatedLabels []string
+
+	// Recorder emits events about the Ingresses; nil discards them
+	Recorder events.EventRecorder
+
+	// Shard is the part of the fleet this instance manages; nil manages all Ingresses
+	Shard *Shard
+}
+
+//+kubebuilder:rbac:groups=networking.k8s.io,resources=ingresses,verbs=get;list;watch;update;patch
+//+kubebuilder:rbac:groups=networking.k8s.io,resources=ingresses/finalizers,verbs=update
+//+kubebuilder:rbac:groups=akamai.com,resources=akamaiproperties,verbs=get;list;watch;create;update;patch;delete
+
+// Reconcile creates, updates or deletes the AkamaiProperty of an Ingress
+func (r *IngressReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	var ingress networkingv1.Ingress
+	if err := r.Get(ctx, req.NamespacedName, &ingress); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	// Deleted Ingresses and Ingresses moved to another class give up their property
+	if ingress.DeletionTimestamp != nil || !r.isManaged(&ingress) {
+		if !controllerutil.ContainsFinalizer(&ingress, IngressFinalizerName) {
+			return ctrl.Result{}, nil
+		}
+		if err := r.deleteProperty(ctx, &ingress); err != nil {
+			return ctrl.Result{}, err
+		}
+		controllerutil.RemoveFinalizer(&ingress, IngressFinalizerName)
+		return ctrl.Result{}, r.Update(ctx, &ingress)
+	}
+
+	// Ingresses of other shards are left to the instances managing them
+	if !r.Shard.Contains(ingress.Labels, ingressAnnotation(&ingress, AnnotationIngressContractID, r.ContractID)) {
+		logger.V(1).Info("Ingress belongs to another shard", "shard", r.Shard.Name)
+		return ctrl.Result{}, nil
+	}
+
+	desired, err := r.ingressProperty(&ingress)
+	if err != nil {
+		// Fixing the Ingress or its load balancer triggers the next reconcile
+		logger.Info("Can't synthesize an AkamaiProperty for the Ingress", "reason", err.Error())
+		r.event(&ingress, "Warning", "InvalidIngress", "Synthesize", "%s", err)
+		return ctrl.Result{}, nil
+	}
+
+	// The finalizer is added before the property is created, so it is never left behind
+	if !controllerutil.ContainsFinalizer(&ingress, IngressFinalizerName) {
+		controllerutil.AddFinalizer(&ingress, IngressFinalizerName)
+		if err := r.Update(ctx, &ingress); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	var current akamaiV1alpha1.AkamaiProperty
+	if err := r.Get(ctx, types.NamespacedName{Name: desired.Name}, &current); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return ctrl.Result{}, err
+		}
+		if err := r.Create(ctx, desired); err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to create AkamaiProperty %s: %w", desired.Name, err)
+		}
+		logger.Info("Created AkamaiProperty for the Ingress", "property", desired.Name)
+		r.event(&ingress, "Normal", "PropertyCreated", "Synthesize", "Created AkamaiProperty %s", desired.Name)
+		return ctrl.Result{}, nil
+	}
+	if !synthesizedFrom(&current, &ingress) {
+		err := fmt.Errorf("AkamaiProperty %s exists and was not synthesized for this Ingress", current.Name)
+		r.event(&ingress, "Warning", "PropertyConflict", "Synthesize", "%s", err)
+		return ctrl.Result{}, err
+	}
+	if equality.Semantic.DeepEqual(current.Spec, desired.Spec) && equality.Semantic.DeepEqual(current.Labels, desired.Labels) {
+		return ctrl.Result{}, nil
+	}
+	current.Spec = desired.Spec
+	current.Labels = desired.Labels
+	if err := r.Update(ctx, &current); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to update AkamaiProperty %s: %w", current.Name, err)
+	}
+	logger.Info("Updated AkamaiProperty of the Ingress", "property", current.Name)
+	return ctrl.Result{}, nil
+}
+
+// isManaged reports whether the Ingress belongs to the operator's IngressClass
+func (r *IngressReconciler) isManaged(ingress *networkingv1.Ingress) bool {
+	return ingress.Spec.IngressClassName != nil && *ingress.Spec.IngressClassName == r.IngressClassName
+}
+
+// ingressProperty returns the AkamaiProperty synthesized for the Ingress. The property delivers
+// the hosts of the rules from the origin and gets an Enhanced TLS edge hostname with Default DV
+// certificates when the Ingress terminates TLS for any of them.
+func (r *IngressReconciler) ingressProperty(ingress *networkingv1.Ingress) (*akamaiV1alpha1.AkamaiProperty, error) {
+	if err := checkAccountAnnotations(ingress, r.AccountNamespaces); err != nil {
+		return nil, err
+	}
+	name := ingressPropertyName(ingress)
+	property := &akamaiV1alpha1.AkamaiProperty{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Labels: propagatedLabels(ingress.Labels, r.PropagatedLabels)},
+		Spec: akamaiV1alpha1.AkamaiPropertySpec{
+			PropertyName: name,
+			ContractID:   ingressAnnotation(ingress, AnnotationIngressContractID, r.ContractID),
+			GroupID:      ingressAnnotation(ingress, AnnotationIngressGroupID, r.GroupID),
+			ProductID:    ingressAnnotation(ingress, AnnotationIngressProductID, r.ProductID),
+		},
+	}
+	property.Labels[ManagedByLabel] = ManagedByValue
+	property.Labels[IngressNamespaceLabel] = ingress.Namespace
+	property.Labels[IngressNameLabel] = ingress.Name
+
+	if property.Spec.ContractID == "" || property.Spec.GroupID == "" || property.Spec.ProductID == "" {
+		return nil, fmt.Errorf("the contract, group and product of the property are required; set --ingress-contract-id, --ingress-group-id and --ingress-product-id")
+	}
+
+	hosts := ingressHosts(ingress)
+	if len(hosts) == 0 {
+		return nil, fmt.Errorf("the Ingress has no rules with a host")
+	}
+	origin := ingressOrigin(ingress, r.OriginHostname)
+	if origin == "" {
+		return nil, fmt.Errorf("the load balancer of the Ingress has no address yet and no origin hostname is configured")
+	}
+	property.Spec.Origin = &akamaiV1alpha1.OriginServer{Hostname: origin, ForwardHostHeader: "REQUEST_HOST_HEADER"}
+
+	if cpCode := ingressAnnotation(ingress, AnnotationIngressCPCode, ""); cpCode != "" {
+		id, err := strconv.ParseInt(strings.TrimPrefix(cpCode, "cpc_"), 10, 64)
+		if err != nil || id <= 0 {
+			return nil, fmt.Errorf("invalid CP code %q in the %s annotation", cpCode, AnnotationIngressCPCode)
+		}
+		property.Spec.CPCode = &akamaiV1alpha1.CPCodeSpec{ID: id}
+	} else if r.CPCodeID > 0 {
+		property.Spec.CPCode = &akamaiV1alpha1.CPCodeSpec{ID: r.CPCodeID}
+	}
+
+	edgeHostname := &akamaiV1alpha1.EdgeHostnameSpec{DomainPrefix: name, DomainSuffix: "edgesuite.net"}
+	if r.EdgeHostnameTemplate != "" {
+		prefix, err := renderNameTemplate(r.EdgeHostnameTemplate, property)
+		if err != nil {
+			return nil, err
+		}
+		edgeHostname.DomainPrefix = prefix
+	}
+	certProvisioningType := ""
+	if certificateRef := ingressAnnotation(ingress, AnnotationIngressCertificateRef, ""); certificateRef != "" {
+		edgeHostname.DomainSuffix, edgeHostname.SecureNetwork, edgeHostname.CertificateRef = "edgekey.net", "ENHANCED_TLS", certificateRef
+		certProvisioningType = "CPS_MANAGED"
+	} else if len(ingress.Spec.TLS) > 0 {
+		edgeHostname.DomainSuffix, edgeHostname.SecureNetwork = "edgekey.net", "ENHANCED_TLS"
+		certProvisioningType = certProvisioningDefault
+	}
+	if certProvisioningType != "" {
+		property.Spec.Redirect = &akamaiV1alpha1.RedirectSpec{HTTPToHTTPS: true}
+	}
+	property.Spec.EdgeHostname = edgeHostname
+	for _, host := range hosts {
+		property.Spec.Hostnames = append(property.Spec.Hostnames, akamaiV1alpha1.Hostname{
+			CNAMEFrom:            host,
+			CNAMETo:              edgeHostname.DomainPrefix + "." + edgeHostname.DomainSuffix,
+			CertProvisioningType: certProvisioningType,
+		})
+	}
+
+	if notify := ingressAnnotation(ingress, AnnotationIngressNotifyEmails, strings.Join(r.NotifyEmails, ",")); notify != "" {
+		network := strings.ToUpper(ingressAnnotation(ingress, AnnotationIngressActivationNetwork, r.ActivationNetwork))
+		if network == "" {
+			network = "STAGING"
+		}
+		if network != "STAGING" && network != "PRODUCTION" {
+			return nil, fmt.Errorf("invalid activation network %q in the %s annotation", network, AnnotationIngressActivationNetwork)
+		}
+		property.Spec.Activation = &akamaiV1alpha1.ActivationSpec{Network: network, NotifyEmails: strings.FieldsFunc(notify, func(c rune) bool { return c == ',' || unicode.IsSpace(c) })}
+	}
+	return property, nil
+}
+
+// deleteProperty deletes the AkamaiProperty synthesized for the Ingress, which removes the
+// property from Akamai according to its deletion policy
+func (r *IngressReconciler) deleteProperty(ctx context.Context, ingress *networkingv1.Ingress) error {
+	var property akamaiV1alpha1.AkamaiProperty
+	if err := r.Get(ctx, types.NamespacedName{Name: ingressPropertyName(ingress)}, &property); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	if !synthesizedFrom(&property, ingress) {
+		return nil
+	}
+	if err := r.Delete(ctx, &property); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	log.FromContext(ctx).Info("Deleted AkamaiProperty of the Ingress", "property", property.Name)
+	return nil
+}
+
+// event records an event about the Ingress
+func (r *IngressReconciler) event(ingress *networkingv1.Ingress, eventType, reason, action, note string, args ...interface{}) {
+	if r.Recorder == nil {
+		return
+	}
+	r.Recorder.Eventf(ingress, nil, eventType, reason, action, note, args...)
+}
+
+// ingressPropertyName returns the name of the AkamaiProperty synthesized for an Ingress, which is
+// also the name of the property in Akamai. Namespaces have no dots, so the name is unique.
+func ingressPropertyName(ingress *networkingv1.Ingress) string {
+	return ingress.Namespace + "." + ingress.Name
+}
+
+// synthesizedFrom reports whether the AkamaiProperty was synthesized for the Ingress
+func synthesizedFrom(property *akamaiV1alpha1.AkamaiProperty, ingress *networkingv1.Ingress) bool {
+	return property.Labels[IngressNamespaceLabel] == ingress.Namespace && property.Labels[IngressNameLabel] == ingress.Name
+}
+
+// ingressAnnotation returns the annotation of the Ingress, or the default when it isn't set
+func ingressAnnotation(ingress *networkingv1.Ingress, key, defaultValue string) string {
+	if value := strings.TrimSpace(ingress.Annotations[key]); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+// accountAnnotations choose the account resources a synthesized property is billed to and
+// serves certificates from
+var accountAnnotations = []string{AnnotationIngressContractID, AnnotationIngressGroupID, AnnotationIngressProductID, AnnotationIngressCertificateRef}
+
+// checkAccountAnnotations rejects an Ingress or Gateway outside namespaces that sets any of the
+// accountAnnotations, as anyone allowed to edit it could otherwise bill another contract or
+// serve another team's certificate
+func checkAccountAnnotations(object metav1.Object, namespaces []string) error {
+	if slices.Contains(namespaces, object.GetNamespace()) {
+		return nil
+	}
+	for _, key := range accountAnnotations {
+		if strings.TrimSpace(object.GetAnnotations()[key]) != "" {
+			return fmt.Errorf("the %s annotation is not allowed in namespace %s; add it to --account-annotation-namespaces", key, object.GetNamespace())
+		}
+	}
+	return nil
+}
+
+// propagatedLabels returns the labels of keys, which select shards and fill naming templates.
+// Other labels aren't copied onto the cluster-scoped property, where they could mark it as a
+// preview or move it to another shard.
+func propagatedLabels(objectLabels map[string]string, keys []string) map[string]string {
+	propagated := map[string]string{}
+	for _, key := range keys {
+		if value, ok := objectLabels[key]; ok {
+			propagated[key] = value
+		}
+	}
+	return propagated
+}
+
+// ingressHosts returns the sorted hosts of the rules of the Ingress, without duplicates
+func ingressHosts(ingress *networkingv1.Ingress) []string {
+	var hosts []string
+	for _, rule := range ingress.Spec.Rules {
+		if rule.Host != "" {
+			hosts = append(hosts, strings.ToLower(rule.Host))
+		}
+	}
+	slices.Sort(hosts)
+	return slices.Compact(hosts)
+}
+
+// ingressOrigin returns the origin of the Ingress: the origin-hostname annotation, the first
+// address of its load balancer or the default origin
+func ingressOrigin(ingress *networkingv1.Ingress, defaultOrigin string) string {
+	if origin := ingressAnnotation(ingress, AnnotationIngressOriginHostname, ""); origin != "" {
+		return origin
+	}
+	for _, lb := range ingress.Status.LoadBalancer.Ingress {
+		if lb.Hostname != "" {
+			return lb.Hostname
+		}
+		if lb.IP != "" {
+			return lb.IP
+		}
+	}
+	return defaultOrigin
+}
+
+// ingressOfProperty maps an AkamaiProperty to the Ingress it was synthesized for, so changes
+// made to the property directly are reverted
+func ingressOfProperty(_ context.Context, obj client.Object) []reconcile.Request {
+	labels := obj.GetLabels()
+	if labels[IngressNamespaceLabel] == "" || labels[IngressNameLabel] == "" {
+		return nil
+	}
+	return []reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: labels[IngressNamespaceLabel], Name: labels[IngressNameLabel]}}}
+}
+
+// SetupWithManager sets up the controller with the Manager. Status updates of Ingresses trigger
+// reconciles too, as they carry the address of the load balancer the origin is taken from.
+func (r *IngressReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&networkingv1.Ingress{}).
+		Watches(&akamaiV1alpha1.AkamaiProperty{}, handler.EnqueueRequestsFromMapFunc(ingressOfProperty),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Complete(r)
+}
@@ -0,0 +1,279 @@
+package controllers
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	networkingv1 "k8s.io/api/networking/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+)
+
+func TestIngressReconcile(t *testing.T) {
+	ctx := context.Background()
+	akamaiClass, otherClass := "akamai", "nginx"
+	ingress := func(class *string, loadBalancer string, annotations map[string]string) *networkingv1.Ingress {
+		ingress := &networkingv1.Ingress{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:   "shop",
+				Name:        "web",
+				Labels:      map[string]string{"team": "shop", LabelPreview: "true"},
+				Annotations: annotations,
+			},
+			Spec: networkingv1.IngressSpec{
+				IngressClassName: class,
+				Rules:            []networkingv1.IngressRule{{Host: "WWW.example.com"}, {Host: "api.example.com"}, {Host: "www.example.com"}},
+				TLS:              []networkingv1.IngressTLS{{Hosts: []string{"www.example.com"}}},
+			},
+		}
+		if loadBalancer != "" {
+			ingress.Status.LoadBalancer.Ingress = []networkingv1.IngressLoadBalancerIngress{{Hostname: loadBalancer}}
+		}
+		return ingress
+	}
+	synthesized := func(origin string) *akamaiV1alpha1.AkamaiProperty {
+		return &akamaiV1alpha1.AkamaiProperty{
+			ObjectMeta: metav1.ObjectMeta{Name: "shop.web", Labels: map[string]string{IngressNamespaceLabel: "shop", IngressNameLabel: "web"}},
+			Spec: akamaiV1alpha1.AkamaiPropertySpec{
+				PropertyName: "shop.web",
+				Origin:       &akamaiV1alpha1.OriginServer{Hostname: origin},
+			},
+		}
+	}
+
+	tests := []struct {
+		name              string
+		ingress           *networkingv1.Ingress
+		property          *akamaiV1alpha1.AkamaiProperty
+		expectError       bool
+		expectedOrigin    string
+		expectedFinalizer bool
+	}{
+		{
+			name:              "created",
+			ingress:           ingress(&akamaiClass, "lb.example.net", nil),
+			expectedOrigin:    "lb.example.net",
+			expectedFinalizer: true,
+		},
+		{
+			name:              "origin annotation",
+			ingress:           ingress(&akamaiClass, "lb.example.net", map[string]string{AnnotationIngressOriginHostname: "origin.example.com"}),
+			expectedOrigin:    "origin.example.com",
+			expectedFinalizer: true,
+		},
+		{
+			name:    "waiting for the load balancer",
+			ingress: ingress(&akamaiClass, "", nil),
+		},
+		{
+			name:    "other class",
+			ingress: ingress(&otherClass, "lb.example.net", nil),
+		},
+		{
+			name:              "updated",
+			ingress:           ingress(&akamaiClass, "lb.example.net", nil),
+			property:          synthesized("old-lb.example.net"),
+			expectedOrigin:    "lb.example.net",
+			expectedFinalizer: true,
+		},
+		{
+			name: "moved to another class",
+			ingress: func() *networkingv1.Ingress {
+				ingress := ingress(&otherClass, "lb.example.net", nil)
+				ingress.Finalizers = []string{IngressFinalizerName}
+				return ingress
+			}(),
+			property: synthesized("lb.example.net"),
+		},
+		{
+			name:              "conflict",
+			ingress:           ingress(&akamaiClass, "lb.example.net", nil),
+			property:          &akamaiV1alpha1.AkamaiProperty{ObjectMeta: metav1.ObjectMeta{Name: "shop.web"}},
+			expectError:       true,
+			expectedOrigin:    "-",
+			expectedFinalizer: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scheme := runtime.NewScheme()
+			if err := akamaiV1alpha1.AddToScheme(scheme); err != nil {
+				t.Fatalf("failed to build scheme: %v", err)
+			}
+			if err := clientgoscheme.AddToScheme(scheme); err != nil {
+				t.Fatalf("failed to build scheme: %v", err)
+			}
+			objects := []client.Object{tt.ingress}
+			if tt.property != nil {
+				objects = append(objects, tt.property)
+			}
+			fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build()
+			r := &IngressReconciler{
+				Client:            fakeClient,
+				Scheme:            scheme,
+				IngressClassName:  "akamai",
+				ContractID:        "ctr_1",
+				GroupID:           "grp_2",
+				ProductID:         "prd_Fresca",
+				ActivationNetwork: "STAGING",
+				NotifyEmails:      []string{"cdn@example.com"},
+				PropagatedLabels:  []string{"team"},
+			}
+			key := types.NamespacedName{Namespace: "shop", Name: "web"}
+
+			_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+			if (err != nil) != tt.expectError {
+				t.Fatalf("Reconcile() error = %v, expected error %v", err, tt.expectError)
+			}
+			var got networkingv1.Ingress
+			if err := r.Get(ctx, key, &got); err != nil {
+				t.Fatalf("failed to get ingress: %v", err)
+			}
+			if slices.Contains(got.Finalizers, IngressFinalizerName) != tt.expectedFinalizer {
+				t.Errorf("finalizers = %v, expected finalizer %v", got.Finalizers, tt.expectedFinalizer)
+			}
+
+			var property akamaiV1alpha1.AkamaiProperty
+			err = r.Get(ctx, types.NamespacedName{Name: "shop.web"}, &property)
+			if tt.expectedOrigin == "" {
+				if !apierrors.IsNotFound(err) {
+					t.Errorf("property = %+v, error = %v, expected none", property.Spec, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("failed to get property: %v", err)
+			}
+			if tt.expectError {
+				if property.Spec.Origin != nil {
+					t.Errorf("property of another owner was changed: %+v", property.Spec)
+				}
+				return
+			}
+			spec := property.Spec
+			if spec.Origin == nil || spec.Origin.Hostname != tt.expectedOrigin || spec.Origin.ForwardHostHeader != "REQUEST_HOST_HEADER" {
+				t.Errorf("origin = %+v, expected %s forwarding the request host", spec.Origin, tt.expectedOrigin)
+			}
+			expectedHostnames := []akamaiV1alpha1.Hostname{
+				{CNAMEFrom: "api.example.com", CNAMETo: "shop.web.edgekey.net", CertProvisioningType: "DEFAULT"},
+				{CNAMEFrom: "www.example.com", CNAMETo: "shop.web.edgekey.net", CertProvisioningType: "DEFAULT"},
+			}
+			if !slices.Equal(spec.Hostnames, expectedHostnames) {
+				t.Errorf("hostnames = %+v, expected %+v", spec.Hostnames, expectedHostnames)
+			}
+			if spec.ContractID != "ctr_1" || spec.ProductID != "prd_Fresca" || spec.EdgeHostname == nil || spec.EdgeHostname.SecureNetwork != "ENHANCED_TLS" ||
+				spec.Redirect == nil || spec.Activation == nil || spec.Activation.Network != "STAGING" {
+				t.Errorf("spec = %+v, expected the defaults with an Enhanced TLS edge hostname, redirect and activation", spec)
+			}
+			if property.Labels["team"] != "shop" || property.Labels[ManagedByLabel] != ManagedByValue || property.Labels[LabelPreview] != "" {
+				t.Errorf("labels = %v, expected the propagated labels of the ingress", property.Labels)
+			}
+		})
+	}
+}
+
+func TestIngressPropertyAnnotations(t *testing.T) {
+	r := &IngressReconciler{ContractID: "ctr_1", GroupID: "grp_2", ProductID: "prd_Fresca", OriginHostname: "lb.example.net", AccountNamespaces: []string{"shop"}}
+	tests := []struct {
+		name          string
+		namespace     string
+		annotations   map[string]string
+		expectError   bool
+		expectedCheck func(*akamaiV1alpha1.AkamaiPropertySpec) bool
+	}{
+		{
+			name:          "no activation without notification emails",
+			expectedCheck: func(spec *akamaiV1alpha1.AkamaiPropertySpec) bool { return spec.Activation == nil },
+		},
+		{
+			name:        "activation on production",
+			annotations: map[string]string{AnnotationIngressNotifyEmails: "a@example.com, b@example.com", AnnotationIngressActivationNetwork: "production"},
+			expectedCheck: func(spec *akamaiV1alpha1.AkamaiPropertySpec) bool {
+				return spec.Activation != nil && spec.Activation.Network == "PRODUCTION" && slices.Equal(spec.Activation.NotifyEmails, []string{"a@example.com", "b@example.com"})
+			},
+		},
+		{
+			name:        "certificate",
+			annotations: map[string]string{AnnotationIngressCertificateRef: "www-example-com"},
+			expectedCheck: func(spec *akamaiV1alpha1.AkamaiPropertySpec) bool {
+				return spec.EdgeHostname.CertificateRef == "www-example-com" && spec.Hostnames[0].CertProvisioningType == "CPS_MANAGED"
+			},
+		},
+		{
+			name:        "cp code",
+			annotations: map[string]string{AnnotationIngressCPCode: "cpc_12345", AnnotationIngressContractID: "ctr_2"},
+			expectedCheck: func(spec *akamaiV1alpha1.AkamaiPropertySpec) bool {
+				return spec.CPCode.ID == 12345 && spec.ContractID == "ctr_2"
+			},
+		},
+		{
+			name:        "contract outside the account namespaces",
+			namespace:   "blog",
+			annotations: map[string]string{AnnotationIngressContractID: "ctr_2"},
+			expectError: true,
+		},
+		{
+			name:        "certificate outside the account namespaces",
+			namespace:   "blog",
+			annotations: map[string]string{AnnotationIngressCertificateRef: "www-example-com"},
+			expectError: true,
+		},
+		{
+			name:        "cp code outside the account namespaces",
+			namespace:   "blog",
+			annotations: map[string]string{AnnotationIngressCPCode: "cpc_12345"},
+			expectedCheck: func(spec *akamaiV1alpha1.AkamaiPropertySpec) bool {
+				return spec.CPCode.ID == 12345 && spec.ContractID == "ctr_1"
+			},
+		},
+		{
+			name:        "invalid cp code",
+			annotations: map[string]string{AnnotationIngressCPCode: "abc"},
+			expectError: true,
+		},
+		{
+			name:        "blank annotation",
+			annotations: map[string]string{AnnotationIngressProductID: " "},
+			expectedCheck: func(spec *akamaiV1alpha1.AkamaiPropertySpec) bool {
+				// A blank annotation keeps the default
+				return spec.ProductID == "prd_Fresca"
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			namespace := tt.namespace
+			if namespace == "" {
+				namespace = "shop"
+			}
+			ingress := &networkingv1.Ingress{
+				ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "web", Annotations: tt.annotations},
+				Spec:       networkingv1.IngressSpec{Rules: []networkingv1.IngressRule{{Host: "www.example.com"}}},
+			}
+			property, err := r.ingressProperty(ingress)
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("ingressProperty() = %+v, expected an error", property.Spec)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ingressProperty() error = %v", err)
+			}
+			if !tt.expectedCheck(&property.Spec) {
+				t.Errorf("spec = %+v", property.Spec)
+			}
+		})
+	}
+}
@@ -0,0 +1,140 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+)
+
+// icsTimeFormat is the UTC date-time format of iCalendar
+const icsTimeFormat = "20060102T150405Z"
+
+// inventoryPlannedActivation is an entry of the activation calendar
+type inventoryPlannedActivation struct {
+	Property     string `json:"property"`
+	PropertyName string `json:"propertyName"`
+	PropertyID   string `json:"propertyId,omitempty"`
+	akamaiV1alpha1.PlannedActivation
+}
+
+// start returns when the planned activation runs, or when it was planned if it isn't bound to a time
+func (a *inventoryPlannedActivation) start() time.Time {
+	switch {
+	case a.ScheduledAt != nil:
+		return a.ScheduledAt.Time
+	case a.Since != nil:
+		return a.Since.Time
+	default:
+		return time.Time{}
+	}
+}
+
+// listCalendar serves the planned activations of all properties
+func (s *InventoryServer) listCalendar(w http.ResponseWriter, req *http.Request) {
+	properties, ok := s.properties(w, req)
+	if !ok {
+		return
+	}
+	writeInventory(req.Context(), w, calendarEntries(properties))
+}
+
+// listCalendarICS serves the planned activations of all properties as an iCalendar feed
+func (s *InventoryServer) listCalendarICS(w http.ResponseWriter, req *http.Request) {
+	properties, ok := s.properties(w, req)
+	if !ok {
+		return
+	}
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	if _, err := w.Write([]byte(renderCalendar(calendarEntries(properties), time.Now()))); err != nil {
+		log.FromContext(req.Context()).Error(err, "Failed to write calendar")
+	}
+}
+
+// calendarEntries lists the planned activations of the properties in the order they run
+func calendarEntries(properties []akamaiV1alpha1.AkamaiProperty) []inventoryPlannedActivation {
+	entries := []inventoryPlannedActivation{}
+	for i := range properties {
+		for _, planned := range properties[i].Status.PlannedActivations {
+			entries = append(entries, inventoryPlannedActivation{
+				Property:          properties[i].Name,
+				PropertyName:      properties[i].Spec.PropertyName,
+				PropertyID:        properties[i].Status.PropertyID,
+				PlannedActivation: planned,
+			})
+		}
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].start().Before(entries[j].start())
+	})
+	return entries
+}
+
+// renderCalendar renders the planned activations as an iCalendar (RFC 5545) feed. Activations
+// that run at a known time or have started are confirmed, those waiting for a gate tentative.
+func renderCalendar(entries []inventoryPlannedActivation, now time.Time) string {
+	var lines []string
+	lines = append(lines,
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//akamai-operator//Activation Calendar//EN",
+		"CALSCALE:GREGORIAN",
+		"X-WR-CALNAME:Akamai activations",
+	)
+	for _, entry := range entries {
+		status := "TENTATIVE"
+		if entry.ScheduledAt != nil || entry.Reason == "StartingActivation" || entry.Reason == "ActivationInProgress" {
+			status = "CONFIRMED"
+		}
+		description := entry.Reason
+		if entry.Message != "" {
+			description += ": " + entry.Message
+		}
+		lines = append(lines,
+			"BEGIN:VEVENT",
+			fmt.Sprintf("UID:%s-%s-%s-v%d@akamai-operator", entry.Property, strings.ToLower(entry.Network), strings.ToLower(entry.Action), entry.Version),
+			"DTSTAMP:"+now.UTC().Format(icsTimeFormat),
+			"DTSTART:"+entry.start().UTC().Format(icsTimeFormat),
+			"SUMMARY:"+icsText(fmt.Sprintf("%s %s v%d on %s", entry.Action, entry.PropertyName, entry.Version, entry.Network)),
+			"DESCRIPTION:"+icsText(description),
+			"STATUS:"+status,
+			"END:VEVENT",
+		)
+	}
+	lines = append(lines, "END:VCALENDAR")
+
+	var calendar strings.Builder
+	for _, line := range lines {
+		calendar.WriteString(foldICSLine(line))
+		calendar.WriteString("\r\n")
+	}
+	return calendar.String()
+}
+
+// icsText escapes a value of an iCalendar text property
+func icsText(value string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`).Replace(value)
+}
+
+// foldICSLine splits a content line longer than 75 octets into continuation lines starting with
+// a space, without splitting UTF-8 characters
+func foldICSLine(line string) string {
+	const maxOctets = 75
+	var folded strings.Builder
+	width := 0
+	for _, r := range line {
+		size := len(string(r))
+		if width+size > maxOctets {
+			folded.WriteString("\r\n ")
+			width = 1
+		}
+		folded.WriteRune(r)
+		width += size
+	}
+	return folded.String()
+}
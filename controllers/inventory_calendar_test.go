@@ -0,0 +1,130 @@
+package controllers
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+)
+
+func TestPlannedActivations(t *testing.T) {
+	queuedAt := metav1.NewTime(time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC))
+	expiresAt := metav1.NewTime(time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC))
+	ready := func(reason string) []metav1.Condition {
+		return []metav1.Condition{{Type: ConditionTypeReady, Status: metav1.ConditionFalse, Reason: reason, Message: "waiting", LastTransitionTime: queuedAt}}
+	}
+
+	tests := []struct {
+		name       string
+		spec       akamaiV1alpha1.AkamaiPropertySpec
+		conditions []metav1.Condition
+		observe    bool
+		expected   []string
+	}{
+		{name: "nothing planned", spec: akamaiV1alpha1.AkamaiPropertySpec{Activation: &akamaiV1alpha1.ActivationSpec{Network: "PRODUCTION"}}, conditions: ready("PropertyIsReady")},
+		{
+			name:       "queued activation",
+			spec:       akamaiV1alpha1.AkamaiPropertySpec{Activation: &akamaiV1alpha1.ActivationSpec{Network: "PRODUCTION"}},
+			conditions: ready("ActivationQueued"),
+			expected:   []string{"Activate PRODUCTION v5 ActivationQueued"},
+		},
+		{
+			name:       "guardrails gate",
+			spec:       akamaiV1alpha1.AkamaiPropertySpec{Activation: &akamaiV1alpha1.ActivationSpec{Network: "PRODUCTION"}},
+			conditions: ready("GuardrailsNotAcknowledged"),
+			expected:   []string{"Activate PRODUCTION v5 GuardrailsNotAcknowledged"},
+		},
+		{name: "expiry", spec: akamaiV1alpha1.AkamaiPropertySpec{ExpiresAt: &expiresAt}, expected: []string{"Deactivate STAGING v4 PropertyExpires", "Deactivate PRODUCTION v3 PropertyExpires"}},
+		{name: "expiry retained", spec: akamaiV1alpha1.AkamaiPropertySpec{ExpiresAt: &expiresAt, DeletionPolicy: akamaiV1alpha1.DeletionPolicyRetain}},
+		{name: "expiry observed", spec: akamaiV1alpha1.AkamaiPropertySpec{ExpiresAt: &expiresAt}, observe: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			property := &akamaiV1alpha1.AkamaiProperty{
+				Spec: tt.spec,
+				Status: akamaiV1alpha1.AkamaiPropertyStatus{
+					LatestVersion:     5,
+					StagingVersion:    4,
+					ProductionVersion: 3,
+					Conditions:        tt.conditions,
+				},
+			}
+			r := &AkamaiPropertyReconciler{ObserveOnly: tt.observe}
+
+			var got []string
+			for _, planned := range r.plannedActivations(property) {
+				got = append(got, fmt.Sprintf("%s %s v%d %s", planned.Action, planned.Network, planned.Version, planned.Reason))
+				if planned.Action == PlannedActionActivate && (planned.Since == nil || !planned.Since.Equal(&queuedAt)) {
+					t.Errorf("since = %v, expected %v", planned.Since, queuedAt)
+				}
+				if planned.Action == PlannedActionDeactivate && (planned.ScheduledAt == nil || !planned.ScheduledAt.Equal(&expiresAt)) {
+					t.Errorf("scheduledAt = %v, expected %v", planned.ScheduledAt, expiresAt)
+				}
+			}
+			if strings.Join(got, ", ") != strings.Join(tt.expected, ", ") {
+				t.Errorf("plannedActivations() = %v, expected %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRenderCalendar(t *testing.T) {
+	since := metav1.NewTime(time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC))
+	scheduled := metav1.NewTime(time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC))
+	properties := []akamaiV1alpha1.AkamaiProperty{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "campaign"},
+			Spec:       akamaiV1alpha1.AkamaiPropertySpec{PropertyName: "campaign.example.com"},
+			Status: akamaiV1alpha1.AkamaiPropertyStatus{PlannedActivations: []akamaiV1alpha1.PlannedActivation{
+				{Network: "PRODUCTION", Action: PlannedActionDeactivate, Version: 2, Reason: ReasonPropertyExpires, ScheduledAt: &scheduled},
+			}},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "www"},
+			Spec:       akamaiV1alpha1.AkamaiPropertySpec{PropertyName: "www.example.com"},
+			Status: akamaiV1alpha1.AkamaiPropertyStatus{PlannedActivations: []akamaiV1alpha1.PlannedActivation{
+				{Network: "PRODUCTION", Action: PlannedActionActivate, Version: 7, Reason: "ActivationQueued", Message: "Waiting for a slot, position 2", Since: &since},
+			}},
+		},
+	}
+
+	entries := calendarEntries(properties)
+	if len(entries) != 2 || entries[0].Property != "www" || entries[1].Property != "campaign" {
+		t.Fatalf("calendarEntries() = %+v, expected www before campaign", entries)
+	}
+
+	calendar := renderCalendar(entries, time.Date(2026, 10, 18, 10, 0, 0, 0, time.UTC))
+	for _, expected := range []string{
+		"BEGIN:VCALENDAR\r\n",
+		"UID:www-production-activate-v7@akamai-operator\r\n",
+		"DTSTART:20261018T090000Z\r\n",
+		"SUMMARY:Activate www.example.com v7 on PRODUCTION\r\n",
+		"DESCRIPTION:ActivationQueued: Waiting for a slot\\, position 2\r\n",
+		"STATUS:TENTATIVE\r\n",
+		"DTSTART:20261101T000000Z\r\n",
+		"STATUS:CONFIRMED\r\n",
+		"END:VCALENDAR\r\n",
+	} {
+		if !strings.Contains(calendar, expected) {
+			t.Errorf("calendar misses %q:\n%s", expected, calendar)
+		}
+	}
+}
+
+func TestFoldICSLine(t *testing.T) {
+	line := "DESCRIPTION:" + strings.Repeat("ä", 40)
+	folded := foldICSLine(line)
+	for _, part := range strings.Split(folded, "\r\n") {
+		if len(part) > 75 {
+			t.Errorf("line of %d octets: %q", len(part), part)
+		}
+	}
+	if strings.ReplaceAll(folded, "\r\n ", "") != line {
+		t.Errorf("unfolding %q does not restore the line", folded)
+	}
+}
@@ -0,0 +1,243 @@
+package controllers
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"sort"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+)
+
+// InventoryServer serves read-only JSON inventories of the AkamaiProperty resources (properties,
+// versions, hostnames, activation states, planned activations and managed rule trees) over HTTP,
+// for CMDB and reporting systems that can't talk to the Kubernetes API. Every request must
+// present Token as a bearer token.
+type InventoryServer struct {
+	client.Client
+
+	// BindAddress is the address the server listens on, e.g. ":8082"
+	BindAddress string
+
+	// Token is the bearer token clients authenticate with
+	Token string
+}
+
+// inventoryProperty is the inventory entry of an AkamaiProperty
+type inventoryProperty struct {
+	Name              string                        `json:"name"`
+	PropertyName      string                        `json:"propertyName"`
+	PropertyID        string                        `json:"propertyId,omitempty"`
+	ContractID        string                        `json:"contractId,omitempty"`
+	GroupID           string                        `json:"groupId,omitempty"`
+	ProductID         string                        `json:"productId"`
+	Phase             string                        `json:"phase,omitempty"`
+	Ready             bool                          `json:"ready"`
+	LatestVersion     int                           `json:"latestVersion,omitempty"`
+	StagingVersion    int                           `json:"stagingVersion,omitempty"`
+	ProductionVersion int                           `json:"productionVersion,omitempty"`
+	Staging           *inventoryActivation          `json:"staging,omitempty"`
+	Production        *inventoryActivation          `json:"production,omitempty"`
+	Hostnames         []inventoryHostname           `json:"hostnames"`
+	Rules             *akamaiV1alpha1.PropertyRules `json:"rules,omitempty"`
+}
+
+// inventoryActivation is the last activation of a property on a network
+type inventoryActivation struct {
+	ActivationID string `json:"activationId"`
+	Status       string `json:"status,omitempty"`
+}
+
+// inventoryHostname is a hostname served by a property
+type inventoryHostname struct {
+	Hostname             string `json:"hostname"`
+	EdgeHostname         string `json:"edgeHostname"`
+	CertProvisioningType string `json:"certProvisioningType,omitempty"`
+	Property             string `json:"property,omitempty"`
+	PropertyID           string `json:"propertyId,omitempty"`
+}
+
+//+kubebuilder:rbac:groups=akamai.com,resources=akamaiproperties,verbs=get;list;watch
+
+// NeedLeaderElection reports that the inventory is served by every replica
+func (s *InventoryServer) NeedLeaderElection() bool {
+	return false
+}
+
+// Start serves the inventory until the context is cancelled
+func (s *InventoryServer) Start(ctx context.Context) error {
+	logger := log.FromContext(ctx).WithName("inventory-server")
+	server := &http.Server{
+		Addr:              s.BindAddress,
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+		BaseContext:       func(_ net.Listener) context.Context { return log.IntoContext(ctx, logger) },
+	}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			logger.Error(err, "Failed to shut down inventory server")
+		}
+	}()
+
+	logger.Info("Serving inventory", "address", s.BindAddress)
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// Handler returns the authenticated HTTP handler of the inventory:
+//
+//	GET /inventory/properties         all properties, without rule trees
+//	GET /inventory/properties/{name}  one property including its managed rule tree
+//	GET /inventory/hostnames          all hostnames with the property serving them
+//	GET /inventory/calendar           planned activations of all properties in the order they run
+//	GET /inventory/calendar.ics       the same as an iCalendar feed
+func (s *InventoryServer) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /inventory/properties", s.listProperties)
+	mux.HandleFunc("GET /inventory/properties/{name}", s.getProperty)
+	mux.HandleFunc("GET /inventory/hostnames", s.listHostnames)
+	mux.HandleFunc("GET /inventory/calendar", s.listCalendar)
+	mux.HandleFunc("GET /inventory/calendar.ics", s.listCalendarICS)
+	return bearerAuth(s.Token, mux)
+}
+
+// bearerAuth rejects requests without the bearer token
+func bearerAuth(token string, next http.Handler) http.Handler {
+	expected := []byte("Bearer " + token)
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if token == "" || subtle.ConstantTimeCompare([]byte(req.Header.Get("Authorization")), expected) != 1 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="akamai-operator"`)
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, req)
+	})
+}
+
+// listProperties serves all properties sorted by name
+func (s *InventoryServer) listProperties(w http.ResponseWriter, req *http.Request) {
+	properties, ok := s.properties(w, req)
+	if !ok {
+		return
+	}
+	inventory := make([]inventoryProperty, 0, len(properties))
+	for i := range properties {
+		inventory = append(inventory, newInventoryProperty(&properties[i], false))
+	}
+	writeInventory(req.Context(), w, inventory)
+}
+
+// getProperty serves a single property including its rule tree
+func (s *InventoryServer) getProperty(w http.ResponseWriter, req *http.Request) {
+	var akamaiProperty akamaiV1alpha1.AkamaiProperty
+	if err := s.Get(req.Context(), types.NamespacedName{Name: req.PathValue("name")}, &akamaiProperty); err != nil {
+		if apierrors.IsNotFound(err) {
+			http.Error(w, "property not found", http.StatusNotFound)
+			return
+		}
+		log.FromContext(req.Context()).Error(err, "Failed to get AkamaiProperty")
+		http.Error(w, "failed to get property", http.StatusInternalServerError)
+		return
+	}
+	writeInventory(req.Context(), w, newInventoryProperty(&akamaiProperty, true))
+}
+
+// listHostnames serves the hostnames of all properties sorted by hostname
+func (s *InventoryServer) listHostnames(w http.ResponseWriter, req *http.Request) {
+	properties, ok := s.properties(w, req)
+	if !ok {
+		return
+	}
+	inventory := []inventoryHostname{}
+	for i := range properties {
+		for _, hostname := range inventoryHostnames(&properties[i]) {
+			hostname.Property = properties[i].Name
+			hostname.PropertyID = properties[i].Status.PropertyID
+			inventory = append(inventory, hostname)
+		}
+	}
+	sort.SliceStable(inventory, func(i, j int) bool {
+		return inventory[i].Hostname < inventory[j].Hostname
+	})
+	writeInventory(req.Context(), w, inventory)
+}
+
+// properties lists all AkamaiProperty resources sorted by name, answering the request on failure
+func (s *InventoryServer) properties(w http.ResponseWriter, req *http.Request) ([]akamaiV1alpha1.AkamaiProperty, bool) {
+	var list akamaiV1alpha1.AkamaiPropertyList
+	if err := s.List(req.Context(), &list); err != nil {
+		log.FromContext(req.Context()).Error(err, "Failed to list AkamaiProperties")
+		http.Error(w, "failed to list properties", http.StatusInternalServerError)
+		return nil, false
+	}
+	sort.Slice(list.Items, func(i, j int) bool {
+		return list.Items[i].Name < list.Items[j].Name
+	})
+	return list.Items, true
+}
+
+// newInventoryProperty converts an AkamaiProperty into its inventory entry
+func newInventoryProperty(akamaiProperty *akamaiV1alpha1.AkamaiProperty, withRules bool) inventoryProperty {
+	status := akamaiProperty.Status
+	entry := inventoryProperty{
+		Name:              akamaiProperty.Name,
+		PropertyName:      akamaiProperty.Spec.PropertyName,
+		PropertyID:        status.PropertyID,
+		ContractID:        akamaiProperty.Spec.ContractID,
+		GroupID:           akamaiProperty.Spec.GroupID,
+		ProductID:         akamaiProperty.Spec.ProductID,
+		Phase:             status.Phase,
+		Ready:             meta.IsStatusConditionTrue(status.Conditions, ConditionTypeReady),
+		LatestVersion:     status.LatestVersion,
+		StagingVersion:    status.StagingVersion,
+		ProductionVersion: status.ProductionVersion,
+		Hostnames:         inventoryHostnames(akamaiProperty),
+	}
+	if status.StagingActivationID != "" {
+		entry.Staging = &inventoryActivation{ActivationID: status.StagingActivationID, Status: status.StagingActivationStatus}
+	}
+	if status.ProductionActivationID != "" {
+		entry.Production = &inventoryActivation{ActivationID: status.ProductionActivationID, Status: status.ProductionActivationStatus}
+	}
+	if withRules {
+		entry.Rules = akamaiProperty.Spec.Rules
+	}
+	return entry
+}
+
+// inventoryHostnames lists the hostnames of the spec
+func inventoryHostnames(akamaiProperty *akamaiV1alpha1.AkamaiProperty) []inventoryHostname {
+	hostnames := make([]inventoryHostname, 0, len(akamaiProperty.Spec.Hostnames))
+	for _, hostname := range akamaiProperty.Spec.Hostnames {
+		hostnames = append(hostnames, inventoryHostname{
+			Hostname:             hostname.CNAMEFrom,
+			EdgeHostname:         hostname.CNAMETo,
+			CertProvisioningType: hostname.CertProvisioningType,
+		})
+	}
+	return hostnames
+}
+
+// writeInventory answers with the JSON encoding of an inventory
+func writeInventory(ctx context.Context, w http.ResponseWriter, inventory interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(inventory); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to write inventory")
+	}
+}
@@ -0,0 +1,111 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+)
+
+func TestInventoryServer(t *testing.T) {
+	properties := []*akamaiV1alpha1.AkamaiProperty{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "www"},
+			Spec: akamaiV1alpha1.AkamaiPropertySpec{
+				PropertyName: "www.example.com",
+				ProductID:    "prd_Fresca",
+				Hostnames:    []akamaiV1alpha1.Hostname{{CNAMEFrom: "www.example.com", CNAMETo: "www.example.com.edgekey.net"}},
+				Rules:        &akamaiV1alpha1.PropertyRules{Name: "default"},
+			},
+			Status: akamaiV1alpha1.AkamaiPropertyStatus{
+				PropertyID:                 "prp_1",
+				ProductionVersion:          3,
+				ProductionActivationID:     "atv_1",
+				ProductionActivationStatus: "ACTIVE",
+				Conditions:                 []metav1.Condition{{Type: ConditionTypeReady, Status: metav1.ConditionTrue, Reason: "PropertyIsReady"}},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "api"},
+			Spec: akamaiV1alpha1.AkamaiPropertySpec{
+				PropertyName: "api.example.com",
+				ProductID:    "prd_Fresca",
+				Hostnames:    []akamaiV1alpha1.Hostname{{CNAMEFrom: "api.example.com", CNAMETo: "api.example.com.edgekey.net"}},
+			},
+		},
+	}
+	r := newFakeReconciler(t, properties[0], properties[1])
+	server := httptest.NewServer((&InventoryServer{Client: r.Client, Token: "secret"}).Handler())
+	defer server.Close()
+
+	get := func(t *testing.T, path, token string, into interface{}) int {
+		t.Helper()
+		req, err := http.NewRequest(http.MethodGet, server.URL+path, nil)
+		if err != nil {
+			t.Fatalf("failed to build request: %v", err)
+		}
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("request failed: %v", err)
+		}
+		defer resp.Body.Close()
+		if into != nil && resp.StatusCode == http.StatusOK {
+			if err := json.NewDecoder(resp.Body).Decode(into); err != nil {
+				t.Fatalf("invalid JSON: %v", err)
+			}
+		}
+		return resp.StatusCode
+	}
+
+	t.Run("rejects missing and wrong tokens", func(t *testing.T) {
+		for _, token := range []string{"", "wrong"} {
+			if status := get(t, "/inventory/properties", token, nil); status != http.StatusUnauthorized {
+				t.Errorf("token %q: status = %d, expected %d", token, status, http.StatusUnauthorized)
+			}
+		}
+	})
+
+	t.Run("lists properties without rules", func(t *testing.T) {
+		var inventory []inventoryProperty
+		if status := get(t, "/inventory/properties", "secret", &inventory); status != http.StatusOK {
+			t.Fatalf("status = %d", status)
+		}
+		if len(inventory) != 2 || inventory[0].Name != "api" || inventory[1].Name != "www" {
+			t.Fatalf("inventory = %+v, expected api and www", inventory)
+		}
+		www := inventory[1]
+		if !www.Ready || www.ProductionVersion != 3 || www.Production == nil || www.Production.Status != "ACTIVE" || www.Rules != nil {
+			t.Errorf("www = %+v", www)
+		}
+	})
+
+	t.Run("serves one property with its rules", func(t *testing.T) {
+		var property inventoryProperty
+		if status := get(t, "/inventory/properties/www", "secret", &property); status != http.StatusOK {
+			t.Fatalf("status = %d", status)
+		}
+		if property.Rules == nil || property.Rules.Name != "default" {
+			t.Errorf("rules = %+v, expected the managed rule tree", property.Rules)
+		}
+		if status := get(t, "/inventory/properties/missing", "secret", nil); status != http.StatusNotFound {
+			t.Errorf("missing property: status = %d, expected %d", status, http.StatusNotFound)
+		}
+	})
+
+	t.Run("lists hostnames", func(t *testing.T) {
+		var hostnames []inventoryHostname
+		if status := get(t, "/inventory/hostnames", "secret", &hostnames); status != http.StatusOK {
+			t.Fatalf("status = %d", status)
+		}
+		if len(hostnames) != 2 || hostnames[0].Hostname != "api.example.com" || hostnames[1].Property != "www" || hostnames[1].PropertyID != "prp_1" {
+			t.Errorf("hostnames = %+v", hostnames)
+		}
+	})
+}
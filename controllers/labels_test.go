@@ -0,0 +1,67 @@
+package controllers
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+)
+
+func TestRenderVersionNotes(t *testing.T) {
+	tests := []struct {
+		name        string
+		labels      map[string]string
+		syncLabels  []string
+		description string
+		metadata    map[string]string
+		expected    string
+	}{
+		{
+			name:     "no labels selected",
+			labels:   map[string]string{"team": "edge"},
+			expected: "",
+		},
+		{
+			name:       "selected label missing",
+			labels:     map[string]string{"team": "edge"},
+			syncLabels: []string{"cost-center"},
+			expected:   "",
+		},
+		{
+			name:       "sorted and deduplicated",
+			labels:     map[string]string{"team": "edge", "cost-center": "1234", "ignored": "x"},
+			syncLabels: []string{"team", "cost-center", "team"},
+			expected:   "labels: cost-center=1234; team=edge",
+		},
+		{
+			name:        "description only",
+			description: "  Public website  ",
+			expected:    "Public website",
+		},
+		{
+			name:        "description, metadata and labels",
+			labels:      map[string]string{"team": "edge"},
+			syncLabels:  []string{"team"},
+			description: "Public website",
+			metadata:    map[string]string{"repository": "https://git.example.com/web", "owner": "edge-team"},
+			expected:    "Public website\n\nowner: edge-team\nrepository: https://git.example.com/web\n\nlabels: team=edge",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			property := &akamaiV1alpha1.AkamaiProperty{
+				ObjectMeta: metav1.ObjectMeta{Labels: tt.labels},
+				Spec: akamaiV1alpha1.AkamaiPropertySpec{
+					SyncLabels:  tt.syncLabels,
+					Description: tt.description,
+					Metadata:    tt.metadata,
+				},
+			}
+			if got := renderVersionNotes(property); got != tt.expected {
+				t.Errorf("renderVersionNotes() = %q, expected %q", got, tt.expected)
+			}
+		})
+	}
+}
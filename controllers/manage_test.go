@@ -0,0 +1,98 @@
+package controllers
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+)
+
+func TestManagedParts(t *testing.T) {
+	off, on := false, true
+	tests := []struct {
+		name       string
+		manage     *akamaiV1alpha1.ManageSpec
+		rules      bool
+		hostnames  bool
+		activation bool
+	}{
+		{name: "everything by default", rules: true, hostnames: true, activation: true},
+		{name: "empty manage", manage: &akamaiV1alpha1.ManageSpec{}, rules: true, hostnames: true, activation: true},
+		{name: "rules in other tooling", manage: &akamaiV1alpha1.ManageSpec{Rules: &off, Hostnames: &on}, hostnames: true, activation: true},
+		{name: "hostnames in other tooling", manage: &akamaiV1alpha1.ManageSpec{Hostnames: &off}, rules: true, activation: true},
+		{name: "activations in other tooling", manage: &akamaiV1alpha1.ManageSpec{Activation: &off}, rules: true, hostnames: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			property := &akamaiV1alpha1.AkamaiProperty{Spec: akamaiV1alpha1.AkamaiPropertySpec{
+				Rules:  &akamaiV1alpha1.PropertyRules{Name: "default"},
+				Manage: tt.manage,
+			}}
+			if got := managesRules(property); got != tt.rules {
+				t.Errorf("managesRules() = %v, expected %v", got, tt.rules)
+			}
+			if got := managesHostnames(property); got != tt.hostnames {
+				t.Errorf("managesHostnames() = %v, expected %v", got, tt.hostnames)
+			}
+			if got := managesActivation(property); got != tt.activation {
+				t.Errorf("managesActivation() = %v, expected %v", got, tt.activation)
+			}
+		})
+	}
+}
+
+func TestDryRunCreationOfManagedParts(t *testing.T) {
+	off := false
+	property := &akamaiV1alpha1.AkamaiProperty{
+		ObjectMeta: metav1.ObjectMeta{Name: "example"},
+		Spec: akamaiV1alpha1.AkamaiPropertySpec{
+			PropertyName: "example.com",
+			ProductID:    "prd_Fresca",
+			ContractID:   "ctr_1",
+			GroupID:      "grp_1",
+			DryRun:       true,
+			Hostnames:    []akamaiV1alpha1.Hostname{{CNAMEFrom: "www.example.com", CNAMETo: "www.example.com.edgekey.net"}},
+			Rules:        &akamaiV1alpha1.PropertyRules{Name: "default"},
+			Activation:   &akamaiV1alpha1.ActivationSpec{Network: "STAGING", NotifyEmails: []string{"ops@example.com"}},
+			Manage:       &akamaiV1alpha1.ManageSpec{Hostnames: &off, Activation: &off},
+		},
+	}
+	r := newFakeReconciler(t, property)
+
+	if _, err := r.reconcileProperty(context.Background(), property); err != nil {
+		t.Fatalf("reconcileProperty() unexpected error: %v", err)
+	}
+	expected := []string{
+		`Create property "example.com" with product prd_Fresca in contract ctr_1 and group grp_1`,
+		"Set the rules of version 1",
+	}
+	if strings.Join(property.Status.PendingChanges, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("pendingChanges = %q, expected %q", property.Status.PendingChanges, expected)
+	}
+}
+
+func TestHandlePromotionActivationNotManaged(t *testing.T) {
+	off := false
+	property := &akamaiV1alpha1.AkamaiProperty{
+		ObjectMeta: metav1.ObjectMeta{Name: "example", Annotations: map[string]string{AnnotationPromoteVersion: "3"}},
+		Spec: akamaiV1alpha1.AkamaiPropertySpec{
+			Activation: &akamaiV1alpha1.ActivationSpec{Network: "STAGING"},
+			Manage:     &akamaiV1alpha1.ManageSpec{Activation: &off},
+		},
+		Status: akamaiV1alpha1.AkamaiPropertyStatus{LatestVersion: 4},
+	}
+	r := newFakeReconciler(t, property)
+
+	// Rejected without asking Akamai
+	if _, err := r.handlePromotion(context.Background(), property); err != nil {
+		t.Fatalf("handlePromotion() error = %v", err)
+	}
+	promotion := property.Status.Promotion
+	if promotion == nil || promotion.State != akamaiV1alpha1.PromotionStateRejected || !strings.Contains(promotion.Message, "spec.manage.activation") {
+		t.Errorf("status.promotion = %+v, expected version 3 to be rejected", promotion)
+	}
+}
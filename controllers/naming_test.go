@@ -0,0 +1,83 @@
+package controllers
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+)
+
+func TestRenderNameTemplate(t *testing.T) {
+	property := &akamaiV1alpha1.AkamaiProperty{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "shop-frontend",
+			Labels: map[string]string{"team": "Web Platform", "env": "prod"},
+		},
+		Spec: akamaiV1alpha1.AkamaiPropertySpec{PropertyName: "www.example.com"},
+	}
+
+	tests := []struct {
+		name      string
+		template  string
+		expected  string
+		expectErr bool
+	}{
+		{name: "labels and property", template: "{team}-{env}-{property}", expected: "web-platform-prod-www.example.com"},
+		{name: "resource name", template: "{name}.{env}", expected: "shop-frontend.prod"},
+		{name: "literal text", template: "akamai-{ env }", expected: "akamai-prod"},
+		{name: "invalid characters trimmed", template: "_{env}_", expected: "prod"},
+		{name: "missing label", template: "{team}-{region}", expectErr: true},
+		{name: "empty result", template: "--", expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := renderNameTemplate(tt.template, property)
+			if (err != nil) != tt.expectErr {
+				t.Fatalf("renderNameTemplate() error = %v, expectErr %v", err, tt.expectErr)
+			}
+			if got != tt.expected {
+				t.Errorf("renderNameTemplate() = %q, expected %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestEdgeHostnameSpec(t *testing.T) {
+	tests := []struct {
+		name           string
+		template       string
+		edgeHostname   *akamaiV1alpha1.EdgeHostnameSpec
+		expectedPrefix string
+		expectErr      bool
+	}{
+		{name: "no edge hostname spec", template: "{env}"},
+		{name: "explicit prefix wins", template: "{env}", edgeHostname: &akamaiV1alpha1.EdgeHostnameSpec{DomainPrefix: "custom", DomainSuffix: "edgesuite.net"}, expectedPrefix: "custom"},
+		{name: "prefix from template", template: "{env}-{property}", edgeHostname: &akamaiV1alpha1.EdgeHostnameSpec{DomainSuffix: "edgesuite.net"}, expectedPrefix: "prod-www.example.com"},
+		{name: "no prefix and no template", edgeHostname: &akamaiV1alpha1.EdgeHostnameSpec{DomainSuffix: "edgesuite.net"}, expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			property := &akamaiV1alpha1.AkamaiProperty{
+				ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"env": "prod"}},
+				Spec:       akamaiV1alpha1.AkamaiPropertySpec{PropertyName: "www.example.com", EdgeHostname: tt.edgeHostname},
+			}
+			r := &AkamaiPropertyReconciler{EdgeHostnameTemplate: tt.template}
+			got, err := r.edgeHostnameSpec(property)
+			if (err != nil) != tt.expectErr {
+				t.Fatalf("edgeHostnameSpec() error = %v, expectErr %v", err, tt.expectErr)
+			}
+			if tt.expectErr || tt.edgeHostname == nil {
+				return
+			}
+			if got.DomainPrefix != tt.expectedPrefix || got.DomainSuffix != "edgesuite.net" {
+				t.Errorf("edgeHostnameSpec() = %+v, expected prefix %q", got, tt.expectedPrefix)
+			}
+			if tt.edgeHostname.DomainPrefix == "" && property.Spec.EdgeHostname.DomainPrefix != "" {
+				t.Error("edgeHostnameSpec() modified the spec")
+			}
+		})
+	}
+}
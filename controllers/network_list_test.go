@@ -0,0 +1,245 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"testing"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v8/pkg/networklists"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+	"github.com/mmz-srf/akamai-operator/pkg/akamai"
+)
+
+// networkListsAPI stubs the Network Lists endpoints, recording the calls. Activations stay
+// pending until activate is called.
+type networkListsAPI struct {
+	networklists.NTWRKLISTS
+	lists       map[string]*networklists.GetNetworkListResponse
+	activations map[string]*networklists.GetActivationsResponse
+	calls       []string
+}
+
+func (s *networkListsAPI) GetNetworkList(_ context.Context, params networklists.GetNetworkListRequest) (*networklists.GetNetworkListResponse, error) {
+	if list, ok := s.lists[params.UniqueID]; ok {
+		copied := *list
+		return &copied, nil
+	}
+	return nil, &networklists.Error{StatusCode: http.StatusNotFound, Title: "Not Found"}
+}
+
+func (s *networkListsAPI) GetNetworkLists(_ context.Context, params networklists.GetNetworkListsRequest) (*networklists.GetNetworkListsResponse, error) {
+	resp := &networklists.GetNetworkListsResponse{}
+	for _, list := range s.lists {
+		resp.NetworkLists = append(resp.NetworkLists, networklists.GetNetworkListsResponseListElement{Name: list.Name, Type: list.Type, UniqueID: list.UniqueID})
+	}
+	return resp, nil
+}
+
+func (s *networkListsAPI) CreateNetworkList(_ context.Context, params networklists.CreateNetworkListRequest) (*networklists.CreateNetworkListResponse, error) {
+	s.calls = append(s.calls, "create "+params.Name)
+	uniqueID := fmt.Sprintf("%d_LIST", len(s.lists)+1)
+	s.lists[uniqueID] = &networklists.GetNetworkListResponse{UniqueID: uniqueID, Name: params.Name, Type: params.Type,
+		Description: params.Description, List: params.List, ElementCount: len(params.List), SyncPoint: 0}
+	return &networklists.CreateNetworkListResponse{UniqueID: uniqueID}, nil
+}
+
+func (s *networkListsAPI) UpdateNetworkList(_ context.Context, params networklists.UpdateNetworkListRequest) (*networklists.UpdateNetworkListResponse, error) {
+	s.calls = append(s.calls, "update "+params.UniqueID)
+	list := s.lists[params.UniqueID]
+	if params.SyncPoint != list.SyncPoint {
+		return nil, &networklists.Error{StatusCode: http.StatusConflict, Title: "Conflict"}
+	}
+	list.Description, list.List, list.ElementCount, list.SyncPoint = params.Description, params.List, len(params.List), list.SyncPoint+1
+	return &networklists.UpdateNetworkListResponse{}, nil
+}
+
+func (s *networkListsAPI) RemoveNetworkList(_ context.Context, params networklists.RemoveNetworkListRequest) (*networklists.RemoveNetworkListResponse, error) {
+	s.calls = append(s.calls, "delete "+params.UniqueID)
+	delete(s.lists, params.UniqueID)
+	return &networklists.RemoveNetworkListResponse{}, nil
+}
+
+func (s *networkListsAPI) GetActivations(_ context.Context, params networklists.GetActivationsRequest) (*networklists.GetActivationsResponse, error) {
+	if activation, ok := s.activations[params.UniqueID+"/"+params.Network]; ok {
+		return activation, nil
+	}
+	return nil, &networklists.Error{StatusCode: http.StatusNotFound, Title: "Not Found"}
+}
+
+func (s *networkListsAPI) CreateActivations(_ context.Context, params networklists.CreateActivationsRequest) (*networklists.CreateActivationsResponse, error) {
+	s.calls = append(s.calls, "activate "+params.UniqueID+" "+params.Network)
+	activation := &networklists.GetActivationsResponse{ActivationID: len(s.calls), UniqueID: params.UniqueID,
+		ActivationStatus: akamai.NetworkListStatusPendingActivation, SyncPoint: s.lists[params.UniqueID].SyncPoint}
+	s.activations[params.UniqueID+"/"+params.Network] = activation
+	return &networklists.CreateActivationsResponse{ActivationID: activation.ActivationID, UniqueID: params.UniqueID,
+		ActivationStatus: activation.ActivationStatus, SyncPoint: activation.SyncPoint}, nil
+}
+
+// activate completes the pending activations
+func (s *networkListsAPI) activate() {
+	for _, activation := range s.activations {
+		activation.ActivationStatus = akamai.NetworkListStatusActive
+	}
+}
+
+func newNetworkListReconciler(t *testing.T, stub *networkListsAPI, objects ...client.Object) *AkamaiNetworkListReconciler {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	if err := akamaiV1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to build scheme: %v", err)
+	}
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(objects...).
+		WithStatusSubresource(&akamaiV1alpha1.AkamaiNetworkList{}).
+		Build()
+	return &AkamaiNetworkListReconciler{Client: fakeClient, Scheme: scheme, AkamaiClient: akamai.NewClientWithNetworkLists(stub)}
+}
+
+func TestNetworkListReconcile(t *testing.T) {
+	ctx := context.Background()
+	list := &akamaiV1alpha1.AkamaiNetworkList{
+		ObjectMeta: metav1.ObjectMeta{Name: "office-networks", Generation: 1},
+		Spec: akamaiV1alpha1.AkamaiNetworkListSpec{
+			Type:           "IP",
+			GroupID:        "grp_123",
+			Elements:       []string{"198.51.100.17", "192.0.2.0/24"},
+			Activation:     &akamaiV1alpha1.NetworkListActivationSpec{Production: true},
+			DeletionPolicy: akamaiV1alpha1.DeletionPolicyDelete,
+		},
+	}
+	stub := &networkListsAPI{lists: map[string]*networklists.GetNetworkListResponse{}, activations: map[string]*networklists.GetActivationsResponse{}}
+	r := newNetworkListReconciler(t, stub, list)
+	key := types.NamespacedName{Name: list.Name}
+	reconcile := func() (*akamaiV1alpha1.AkamaiNetworkList, ctrl.Result) {
+		t.Helper()
+		result, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+		if err != nil {
+			t.Fatalf("Reconcile() error = %v", err)
+		}
+		var got akamaiV1alpha1.AkamaiNetworkList
+		if err := r.Get(ctx, key, &got); err != nil {
+			t.Fatalf("failed to get network list: %v", err)
+		}
+		return &got, result
+	}
+
+	// The list is created and activated on production only
+	got, result := reconcile()
+	if !slices.Equal(stub.calls, []string{"create office-networks", "activate 1_LIST PRODUCTION"}) {
+		t.Errorf("calls = %q, expected the list to be created and activated", stub.calls)
+	}
+	if got.Status.UniqueID != "1_LIST" || got.Status.ElementCount != 2 || got.Status.Phase != PhaseActivating ||
+		result.RequeueAfter != networkListActivationPollInterval || got.Status.Staging != nil {
+		t.Errorf("status = %+v, result = %+v, expected the list to be activating", got.Status, result)
+	}
+
+	// Once the activation completed the list is ready and nothing is activated again
+	stub.activate()
+	stub.calls = nil
+	got, result = reconcile()
+	if len(stub.calls) != 0 || got.Status.Phase != PhaseReady || got.Status.Production.Status != akamai.NetworkListStatusActive ||
+		result.RequeueAfter != networkListResyncInterval {
+		t.Errorf("calls = %q, status = %+v, expected the active list to be ready", stub.calls, got.Status)
+	}
+
+	// Changed elements are written with the current sync point and activated again
+	got.Spec.Elements = append(got.Spec.Elements, "203.0.113.0/24")
+	if err := r.Update(ctx, got); err != nil {
+		t.Fatalf("failed to update network list: %v", err)
+	}
+	got, _ = reconcile()
+	if !slices.Equal(stub.calls, []string{"update 1_LIST", "activate 1_LIST PRODUCTION"}) {
+		t.Errorf("calls = %q, expected the list to be updated and activated", stub.calls)
+	}
+	if got.Status.SyncPoint != 1 || got.Status.Production.SyncPoint != 1 || got.Status.ElementCount != 3 {
+		t.Errorf("status = %+v, expected the activation of the new sync point", got.Status)
+	}
+
+	// Deleting the resource deletes the list with the Delete deletion policy
+	if err := r.Delete(ctx, got); err != nil {
+		t.Fatalf("failed to delete network list: %v", err)
+	}
+	stub.calls = nil
+	if _, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: key}); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	if !slices.Equal(stub.calls, []string{"delete 1_LIST"}) || len(stub.lists) != 0 {
+		t.Errorf("calls = %q, expected the list to be deleted", stub.calls)
+	}
+}
+
+func TestNetworkListAdoption(t *testing.T) {
+	ctx := context.Background()
+	list := &akamaiV1alpha1.AkamaiNetworkList{
+		ObjectMeta: metav1.ObjectMeta{Name: "blocked-countries", Generation: 1},
+		Spec:       akamaiV1alpha1.AkamaiNetworkListSpec{Name: "Blocked countries", Type: "GEO", Elements: []string{"kp", "IR"}},
+	}
+	stub := &networkListsAPI{
+		lists: map[string]*networklists.GetNetworkListResponse{
+			"7_GEO": {UniqueID: "7_GEO", Name: "Blocked countries", Type: "GEO", List: []string{"IR", "KP"}, ElementCount: 2, SyncPoint: 4},
+		},
+		activations: map[string]*networklists.GetActivationsResponse{},
+	}
+	r := newNetworkListReconciler(t, stub, list)
+	if _, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: list.Name}}); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	var got akamaiV1alpha1.AkamaiNetworkList
+	if err := r.Get(ctx, types.NamespacedName{Name: list.Name}, &got); err != nil {
+		t.Fatalf("failed to get network list: %v", err)
+	}
+	if len(stub.calls) != 0 || got.Status.UniqueID != "7_GEO" || got.Status.SyncPoint != 4 || got.Status.Phase != PhaseReady {
+		t.Errorf("calls = %q, status = %+v, expected the list to be adopted unchanged", stub.calls, got.Status)
+	}
+}
+
+func TestNetworkListNeedsUpdate(t *testing.T) {
+	tests := []struct {
+		name     string
+		spec     akamaiV1alpha1.AkamaiNetworkListSpec
+		current  networklists.GetNetworkListResponse
+		expected bool
+	}{
+		{
+			name:    "elements in another order",
+			spec:    akamaiV1alpha1.AkamaiNetworkListSpec{Type: "IP", Elements: []string{"192.0.2.1", "192.0.2.0/24"}},
+			current: networklists.GetNetworkListResponse{List: []string{"192.0.2.0/24", "192.0.2.1"}},
+		},
+		{
+			name:    "country codes in lower case and duplicated",
+			spec:    akamaiV1alpha1.AkamaiNetworkListSpec{Type: "GEO", Elements: []string{"ch", "CH", "li"}},
+			current: networklists.GetNetworkListResponse{List: []string{"CH", "LI"}},
+		},
+		{
+			name:     "element removed",
+			spec:     akamaiV1alpha1.AkamaiNetworkListSpec{Type: "IP", Elements: []string{"192.0.2.1"}},
+			current:  networklists.GetNetworkListResponse{List: []string{"192.0.2.1", "192.0.2.2"}},
+			expected: true,
+		},
+		{
+			name:     "description changed",
+			spec:     akamaiV1alpha1.AkamaiNetworkListSpec{Type: "IP", Description: "Offices", Elements: []string{"192.0.2.1"}},
+			current:  networklists.GetNetworkListResponse{List: []string{"192.0.2.1"}},
+			expected: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			list := &akamaiV1alpha1.AkamaiNetworkList{Spec: tt.spec}
+			if got := networkListNeedsUpdate(list, &tt.current); got != tt.expected {
+				t.Errorf("networkListNeedsUpdate() = %v, expected %v", got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package controllers
+
+import (
+	"errors"
+)
+
+// observedDifference is a difference between a resource and Akamai that observe-only mode leaves
+// in place. The syncs of the resources return it instead of making the change.
+type observedDifference string
+
+func (d observedDifference) Error() string {
+	return string(d)
+}
+
+// observeOnlyMessage returns the message of the Ready condition of a resource compared with Akamai
+// in observe-only mode, given the outcome of its sync: nil when Akamai matches the spec, or the
+// observedDifference found. It reports false outside observe-only mode and for other errors,
+// which are handled like in normal operation.
+func observeOnlyMessage(observeOnly bool, err error) (string, bool) {
+	if !observeOnly {
+		return "", false
+	}
+	var difference observedDifference
+	switch {
+	case err == nil:
+		return "Akamai matches the spec; observe-only mode makes no changes", true
+	case errors.As(err, &difference):
+		return difference.Error() + "; observe-only mode makes no changes", true
+	}
+	return "", false
+}
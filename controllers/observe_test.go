@@ -0,0 +1,47 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+)
+
+func TestObserveOnlyDoesNotCreate(t *testing.T) {
+	property := &akamaiV1alpha1.AkamaiProperty{
+		ObjectMeta: metav1.ObjectMeta{Name: "example", Generation: 1},
+		Spec:       akamaiV1alpha1.AkamaiPropertySpec{PropertyName: "example.com"},
+	}
+	r := newFakeReconciler(t, property)
+	r.ObserveOnly = true
+
+	// Without an Akamai client any attempt to create the property would fail
+	result, err := r.reconcileProperty(context.Background(), property)
+	if err != nil {
+		t.Fatalf("reconcileProperty() unexpected error: %v", err)
+	}
+	if result.RequeueAfter == 0 {
+		t.Error("expected the property to be observed again later")
+	}
+	if property.Status.Phase != PhaseObserving || property.Status.PropertyID != "" {
+		t.Errorf("phase = %q, propertyID = %q", property.Status.Phase, property.Status.PropertyID)
+	}
+	ready := meta.FindStatusCondition(property.Status.Conditions, ConditionTypeReady)
+	if ready == nil || ready.Status != metav1.ConditionFalse || ready.Reason != ReasonObserveOnly {
+		t.Errorf("unexpected Ready condition %+v", ready)
+	}
+}
+
+func TestObservedMessage(t *testing.T) {
+	property := &akamaiV1alpha1.AkamaiProperty{}
+	if got := observedMessage(property); got != "Akamai matches the spec; observe-only mode makes no changes" {
+		t.Errorf("observedMessage() = %q", got)
+	}
+	property.Status.Drift = []string{driftHostnames, driftRules}
+	if got := observedMessage(property); got != "The hostnames and rules in Akamai differ from the spec; observe-only mode makes no changes" {
+		t.Errorf("observedMessage() = %q", got)
+	}
+}
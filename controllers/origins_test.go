@@ -0,0 +1,131 @@
+package controllers
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/runtime"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+)
+
+func TestValidateOrigins(t *testing.T) {
+	origin := func(name string, weight int32) akamaiV1alpha1.WeightedOrigin {
+		return akamaiV1alpha1.WeightedOrigin{Name: name, Weight: weight, OriginServer: akamaiV1alpha1.OriginServer{Hostname: name + ".example.com"}}
+	}
+
+	tests := []struct {
+		name    string
+		origins *akamaiV1alpha1.OriginsSpec
+		err     string
+	}{
+		{name: "no origins block"},
+		{name: "weighted", origins: &akamaiV1alpha1.OriginsSpec{Origins: []akamaiV1alpha1.WeightedOrigin{origin("a", 80), origin("b", 20)}}},
+		{name: "drained origin", origins: &akamaiV1alpha1.OriginsSpec{Origins: []akamaiV1alpha1.WeightedOrigin{origin("a", 1), origin("b", 0)}}},
+		{name: "empty", origins: &akamaiV1alpha1.OriginsSpec{}, err: "at least one origin"},
+		{name: "duplicate", origins: &akamaiV1alpha1.OriginsSpec{Origins: []akamaiV1alpha1.WeightedOrigin{origin("a", 1), origin("a", 1)}}, err: "duplicate origin"},
+		{name: "all drained", origins: &akamaiV1alpha1.OriginsSpec{Origins: []akamaiV1alpha1.WeightedOrigin{origin("a", 0)}}, err: "weight 0"},
+		{
+			name:    "failover without hostname",
+			origins: &akamaiV1alpha1.OriginsSpec{Origins: []akamaiV1alpha1.WeightedOrigin{origin("a", 1)}, Failover: &akamaiV1alpha1.OriginServer{}},
+			err:     "failover has no hostname",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			property := &akamaiV1alpha1.AkamaiProperty{Spec: akamaiV1alpha1.AkamaiPropertySpec{Origins: tt.origins}}
+			err := validateOrigins(property)
+			if tt.err == "" && err != nil {
+				t.Errorf("validateOrigins() unexpected error: %v", err)
+			}
+			if tt.err != "" && (err == nil || !strings.Contains(err.Error(), tt.err)) {
+				t.Errorf("validateOrigins() = %v, expected an error containing %q", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestApplyOrigins(t *testing.T) {
+	type rule struct {
+		Name      string `json:"name"`
+		Criteria  []akamaiV1alpha1.RuleCriteria
+		Behaviors []akamaiV1alpha1.RuleBehavior
+		Children  []json.RawMessage
+	}
+	options := func(raw runtime.RawExtension) map[string]interface{} {
+		var values map[string]interface{}
+		_ = json.Unmarshal(raw.Raw, &values)
+		return values
+	}
+	existing, _ := json.Marshal(map[string]interface{}{"name": "Static content"})
+	stale, _ := json.Marshal(map[string]interface{}{"name": originsRuleName})
+
+	rules := &akamaiV1alpha1.PropertyRules{
+		Name:     "default",
+		Children: []runtime.RawExtension{{Raw: stale}, {Raw: existing}},
+	}
+	spec := &akamaiV1alpha1.OriginsSpec{
+		Origins: []akamaiV1alpha1.WeightedOrigin{
+			{Name: "eu", Weight: 80, OriginServer: akamaiV1alpha1.OriginServer{Hostname: "eu.origin.example.com"}},
+			{Name: "drained", Weight: 0, OriginServer: akamaiV1alpha1.OriginServer{Hostname: "old.origin.example.com"}},
+			{Name: "us", Weight: 20, OriginServer: akamaiV1alpha1.OriginServer{Hostname: "us.origin.example.com", ForwardHostHeader: "ORIGIN_HOSTNAME"}},
+		},
+		Failover: &akamaiV1alpha1.OriginServer{Hostname: "backup.example.com"},
+	}
+	if err := applyOrigins(rules, spec); err != nil {
+		t.Fatalf("applyOrigins() unexpected error: %v", err)
+	}
+
+	if len(rules.Children) != 2 {
+		t.Fatalf("expected the origins rule to replace the stale one, got %d children", len(rules.Children))
+	}
+	var managed rule
+	if err := json.Unmarshal(rules.Children[0].Raw, &managed); err != nil || managed.Name != originsRuleName {
+		t.Fatalf("first child = %s, expected the origins rule", rules.Children[0].Raw)
+	}
+	if len(managed.Behaviors) != 2 || managed.Behaviors[0].Name != "setVariable" || managed.Behaviors[1].Name != "failAction" {
+		t.Fatalf("unexpected behaviors %+v", managed.Behaviors)
+	}
+	if got := options(managed.Behaviors[0].Options)["maxRandomNumber"]; got != "100" {
+		t.Errorf("maxRandomNumber = %v, expected 100", got)
+	}
+	if got := options(managed.Behaviors[1].Options)["cexHostname"]; got != "backup.example.com" {
+		t.Errorf("failover hostname = %v", got)
+	}
+
+	expected := []struct{ name, lower, upper, hostname, forwardHostHeader string }{
+		{name: "Origin eu", lower: "1", upper: "80", hostname: "eu.origin.example.com", forwardHostHeader: "REQUEST_HOST_HEADER"},
+		{name: "Origin us", lower: "81", upper: "100", hostname: "us.origin.example.com", forwardHostHeader: "ORIGIN_HOSTNAME"},
+	}
+	if len(managed.Children) != len(expected) {
+		t.Fatalf("expected %d origin rules, got %d", len(expected), len(managed.Children))
+	}
+	for i, want := range expected {
+		var child rule
+		if err := json.Unmarshal(managed.Children[i], &child); err != nil {
+			t.Fatalf("failed to parse origin rule: %v", err)
+		}
+		bounds := options(child.Criteria[0].Options)
+		origin := options(child.Behaviors[0].Options)
+		if child.Name != want.name || bounds["lowerBound"] != want.lower || bounds["upperBound"] != want.upper ||
+			origin["hostname"] != want.hostname || origin["forwardHostHeader"] != want.forwardHostHeader {
+			t.Errorf("origin rule %d = %s", i, managed.Children[i])
+		}
+	}
+	if len(rules.Variables) != 1 || rules.Variables[0].Name != originBucketVariable {
+		t.Errorf("expected %s to be declared, got %+v", originBucketVariable, rules.Variables)
+	}
+
+	// A single origin needs no random selection
+	single := &akamaiV1alpha1.PropertyRules{Name: "default"}
+	if err := applyOrigins(single, &akamaiV1alpha1.OriginsSpec{Origins: spec.Origins[:1]}); err != nil {
+		t.Fatalf("applyOrigins() unexpected error: %v", err)
+	}
+	var singleRule rule
+	_ = json.Unmarshal(single.Children[0].Raw, &singleRule)
+	if len(singleRule.Behaviors) != 1 || singleRule.Behaviors[0].Name != "origin" || len(singleRule.Children) != 0 || len(single.Variables) != 0 {
+		t.Errorf("single origin rendered as %s", single.Children[0].Raw)
+	}
+}
@@ -0,0 +1,99 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v8/pkg/papi"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+	"github.com/mmz-srf/akamai-operator/pkg/akamai"
+)
+
+func TestPermissionsInsufficient(t *testing.T) {
+	ctx := context.Background()
+	property := &akamaiV1alpha1.AkamaiProperty{
+		ObjectMeta: metav1.ObjectMeta{Name: "example", Generation: 1},
+		Spec:       akamaiV1alpha1.AkamaiPropertySpec{PropertyName: "example.com", ContractID: "ctr_1", GroupID: "grp_1"},
+	}
+	r := newFakeReconciler(t, property)
+
+	if _, denied := r.permissionsInsufficient(ctx, property, accessWrite, "update rules", errors.New("timeout")); denied {
+		t.Fatal("expected a transient error not to be classified as a permission error")
+	}
+
+	forbidden := fmt.Errorf("failed to create version: %w", &papi.Error{StatusCode: http.StatusForbidden, Detail: "read-only grant"})
+	result, denied := r.permissionsInsufficient(ctx, property, accessWrite, "create property version", forbidden)
+	if !denied {
+		t.Fatal("expected a 403 to be classified as a permission error")
+	}
+	if result.RequeueAfter != permissionRetryInterval {
+		t.Errorf("RequeueAfter = %v, expected %v", result.RequeueAfter, permissionRetryInterval)
+	}
+	condition := meta.FindStatusCondition(property.Status.Conditions, ConditionTypePermissionsInsufficient)
+	if condition == nil || condition.Status != metav1.ConditionTrue || condition.Reason != "MissingWriteAccess" {
+		t.Fatalf("expected a true MissingWriteAccess condition, got %+v", condition)
+	}
+	expected := "API client lacks write access to contract ctr_1, group grp_1 (create property version): read-only grant"
+	if condition.Message != expected {
+		t.Errorf("condition message = %q, expected %q", condition.Message, expected)
+	}
+	if property.Status.Phase != PhaseError {
+		t.Errorf("phase = %q, expected %q", property.Status.Phase, PhaseError)
+	}
+
+	if !clearPermissionsInsufficient(property) {
+		t.Fatal("expected the condition to be cleared")
+	}
+	if meta.IsStatusConditionTrue(property.Status.Conditions, ConditionTypePermissionsInsufficient) {
+		t.Error("expected the condition to be false after clearing")
+	}
+	if clearPermissionsInsufficient(property) {
+		t.Error("expected clearing a resolved condition to be a no-op")
+	}
+}
+
+// forbiddenNotesPAPI creates properties but refuses to read their rules, like an API client
+// without access to the new property's group
+type forbiddenNotesPAPI struct {
+	papi.PAPI
+}
+
+func (s *forbiddenNotesPAPI) CreateProperty(_ context.Context, _ papi.CreatePropertyRequest) (*papi.CreatePropertyResponse, error) {
+	return &papi.CreatePropertyResponse{PropertyID: "prp_1", PropertyLink: "/papi/v1/properties/prp_1?contractId=ctr_1&groupId=grp_1"}, nil
+}
+
+func (s *forbiddenNotesPAPI) GetRuleTree(_ context.Context, _ papi.GetRuleTreeRequest) (*papi.GetRuleTreeResponse, error) {
+	return nil, &papi.Error{StatusCode: http.StatusForbidden, Detail: "no access to group"}
+}
+
+func TestInitialVersionNotesPermissionsInsufficient(t *testing.T) {
+	ctx := context.Background()
+	property := &akamaiV1alpha1.AkamaiProperty{
+		ObjectMeta: metav1.ObjectMeta{Name: "example", Generation: 1},
+		Spec: akamaiV1alpha1.AkamaiPropertySpec{PropertyName: "example.com", ContractID: "ctr_1", GroupID: "grp_1",
+			ProductID: "prd_Fresca", Description: "Example site"},
+	}
+	r := newFakeReconciler(t, property)
+	r.AkamaiClient = akamai.NewClientWithPAPI(&forbiddenNotesPAPI{})
+
+	result, err := r.reconcileProperty(ctx, property)
+	if err != nil {
+		t.Fatalf("reconcileProperty() error = %v", err)
+	}
+	if result.RequeueAfter != permissionRetryInterval {
+		t.Errorf("RequeueAfter = %v, expected the permission back-off %v", result.RequeueAfter, permissionRetryInterval)
+	}
+	condition := meta.FindStatusCondition(property.Status.Conditions, ConditionTypePermissionsInsufficient)
+	if condition == nil || condition.Reason != "MissingWriteAccess" {
+		t.Errorf("condition = %+v, expected the missing write access to be reported", condition)
+	}
+	if property.Status.PropertyID != "prp_1" {
+		t.Errorf("propertyId = %q, expected the created property to be recorded", property.Status.PropertyID)
+	}
+}
@@ -0,0 +1,250 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+)
+
+func placeholderReconciler(t *testing.T, objects ...client.Object) *AkamaiPropertyReconciler {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+	_ = akamaiV1alpha1.AddToScheme(scheme)
+	return &AkamaiPropertyReconciler{Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build()}
+}
+
+func behaviorRules(behaviors ...string) *akamaiV1alpha1.PropertyRules {
+	rules := &akamaiV1alpha1.PropertyRules{Name: "default"}
+	for _, behavior := range behaviors {
+		var parsed akamaiV1alpha1.RuleBehavior
+		if err := json.Unmarshal([]byte(behavior), &parsed); err != nil {
+			panic(err)
+		}
+		rules.Behaviors = append(rules.Behaviors, parsed)
+	}
+	return rules
+}
+
+func TestParsePlaceholders(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		refs  []valueRef
+		err   bool
+	}{
+		{name: "plain value", value: "origin.example.com"},
+		{name: "papi variable", value: "{{user.PMUSER_ORIGIN}}"},
+		{name: "secret", value: "${secret:edge/origin-auth/token}",
+			refs: []valueRef{{kind: "secret", namespace: "edge", name: "origin-auth", key: "token"}}},
+		{name: "embedded configmap and secret", value: "${configmap:edge/origin/host}:${secret:edge/origin-auth/port}",
+			refs: []valueRef{{kind: "configmap", namespace: "edge", name: "origin", key: "host"}, {kind: "secret", namespace: "edge", name: "origin-auth", key: "port"}}},
+		{name: "missing key", value: "${secret:edge/origin-auth}", err: true},
+		{name: "unterminated", value: "Bearer ${secret:edge/origin-auth/token", err: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			refs, err := parsePlaceholders(tt.value)
+			if (err != nil) != tt.err {
+				t.Fatalf("parsePlaceholders() error = %v, want error %v", err, tt.err)
+			}
+			if len(refs) != len(tt.refs) {
+				t.Fatalf("parsePlaceholders() = %+v, want %+v", refs, tt.refs)
+			}
+			for i := range refs {
+				if refs[i] != tt.refs[i] {
+					t.Errorf("parsePlaceholders()[%d] = %+v, want %+v", i, refs[i], tt.refs[i])
+				}
+			}
+		})
+	}
+}
+
+func TestValidatePropertyRulesPlaceholders(t *testing.T) {
+	r := &AkamaiPropertyReconciler{}
+	rules := behaviorRules(`{"name":"origin","options":{"hostname":"${configmap:edge/origin}"}}`)
+	if err := r.validatePropertyRules(rules); err == nil || !strings.Contains(err.Error(), `options of "origin": malformed placeholder`) {
+		t.Errorf("validatePropertyRules() error = %v, want malformed placeholder", err)
+	}
+	rules.Children = []runtime.RawExtension{{Raw: []byte(`{"name":"API","criteria":[{"name":"path","options":{"values":["${secret:edge/"]}}]}`)}}
+	rules.Behaviors = nil
+	if err := r.validatePropertyRules(rules); err == nil || !strings.Contains(err.Error(), `options of "path"`) {
+		t.Errorf("validatePropertyRules() error = %v, want malformed placeholder in child criterion", err)
+	}
+}
+
+func TestDesiredRulesResolvesPlaceholders(t *testing.T) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "origin-auth", Namespace: "edge", Labels: map[string]string{LabelRuleValues: "true"}},
+		Data:       map[string][]byte{"token": []byte("t0k3n")},
+	}
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "origin", Namespace: "edge"},
+		Data:       map[string]string{"host": "origin.example.com"},
+	}
+	r := placeholderReconciler(t, secret, configMap)
+
+	rules := behaviorRules(
+		`{"name":"origin","options":{"hostname":"${configmap:edge/origin/host}","httpPort":80}}`,
+		`{"name":"modifyOutgoingRequestHeader","options":{"customHeaderName":"Authorization","newHeaderValue":"Bearer ${secret:edge/origin-auth/token}"}}`,
+	)
+	rules.Children = []runtime.RawExtension{{Raw: []byte(`{"name":"API","behaviors":[{"name":"setVariable","options":{"variableValue":"${secret:edge/origin-auth/token}"}}]}`)}}
+	rules.Comments = "${secret:edge/origin-auth/token} stays in comments"
+	property := &akamaiV1alpha1.AkamaiProperty{Spec: akamaiV1alpha1.AkamaiPropertySpec{Rules: rules}}
+
+	desired, sources, err := r.desiredRules(context.Background(), property)
+	if err != nil {
+		t.Fatalf("desiredRules() unexpected error: %v", err)
+	}
+	raw, _ := json.Marshal(desired)
+	for _, want := range []string{`"hostname":"origin.example.com"`, `"newHeaderValue":"Bearer t0k3n"`, `"variableValue":"t0k3n"`, `"httpPort":80`, `"comments":"${secret:edge/origin-auth/token} stays in comments"`} {
+		if !strings.Contains(string(raw), want) {
+			t.Errorf("desired rules %s do not contain %s", raw, want)
+		}
+	}
+	if strings.Contains(string(property.Spec.Rules.Behaviors[1].Options.Raw), "t0k3n") {
+		t.Errorf("spec rules must not be modified")
+	}
+	if got := strings.Join(sources.valueSources, ","); got != "configmap:edge/origin,secret:edge/origin-auth" {
+		t.Errorf("value sources = %s", got)
+	}
+	if sources.valuesDigest == "" {
+		t.Fatalf("expected a values digest")
+	}
+
+	// A rotated secret changes the digest
+	secret.Data["token"] = []byte("r0t4t3d")
+	if err := r.Update(context.Background(), secret); err != nil {
+		t.Fatalf("failed to update secret: %v", err)
+	}
+	_, rotated, err := r.desiredRules(context.Background(), property)
+	if err != nil {
+		t.Fatalf("desiredRules() unexpected error: %v", err)
+	}
+	if rotated.valuesDigest == sources.valuesDigest {
+		t.Errorf("values digest did not change after the secret was rotated")
+	}
+
+	// Missing keys fail the render
+	property.Spec.Rules = behaviorRules(`{"name":"origin","options":{"hostname":"${configmap:edge/origin/missing}"}}`)
+	if _, _, err := r.desiredRules(context.Background(), property); err == nil || !strings.Contains(err.Error(), "key missing not found in configmap edge/origin") {
+		t.Errorf("desiredRules() error = %v, want missing key", err)
+	}
+
+	// Rules without placeholders have no value sources
+	property.Spec.Rules = behaviorRules(`{"name":"origin","options":{"hostname":"origin.example.com"}}`)
+	if _, plain, err := r.desiredRules(context.Background(), property); err != nil || plain.valuesDigest != "" || plain.valueSources != nil {
+		t.Errorf("desiredRules() = %+v, %v, want no value sources", plain, err)
+	}
+}
+
+func TestMaskResolvedSecrets(t *testing.T) {
+	r := placeholderReconciler(t, &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "masked", Namespace: "edge", Labels: map[string]string{LabelRuleValues: "true"}},
+		Data:       map[string][]byte{"token": []byte("m4sk-m3")},
+	})
+	rules := behaviorRules(`{"name":"modifyOutgoingRequestHeader","options":{"newHeaderValue":"Bearer ${secret:edge/masked/token}"}}`)
+	var sources rulesSources
+	if _, err := r.resolvePlaceholders(context.Background(), rules, &sources); err != nil {
+		t.Fatalf("resolvePlaceholders() unexpected error: %v", err)
+	}
+
+	normalized := map[string]interface{}{
+		"behaviors": []interface{}{
+			map[string]interface{}{
+				"name":    "modifyOutgoingRequestHeader",
+				"options": map[string]interface{}{"newHeaderValue": "Bearer m4sk-m3"},
+			},
+			map[string]interface{}{
+				"name":    "setVariable",
+				"options": map[string]interface{}{"variableValue": "m4sk-m3-unrelated"},
+			},
+		},
+	}
+	sources.secretOptions.mask(normalized)
+	raw, _ := json.Marshal(normalized)
+	if strings.Contains(string(raw), "Bearer m4sk-m3") || !strings.Contains(string(raw), `"newHeaderValue":"`+maskedValue+`"`) {
+		t.Errorf("secret value not masked: %s", raw)
+	}
+	if !strings.Contains(string(raw), "m4sk-m3-unrelated") {
+		t.Errorf("unrelated value masked: %s", raw)
+	}
+
+	// Another property's render doesn't mask values of this one
+	var other rulesSources
+	if _, err := r.resolvePlaceholders(context.Background(), behaviorRules(`{"name":"origin","options":{"hostname":"origin.example.com"}}`), &other); err != nil {
+		t.Fatalf("resolvePlaceholders() unexpected error: %v", err)
+	}
+	if len(other.secretOptions) != 0 {
+		t.Errorf("secretOptions = %v, expected none for rules without placeholders", other.secretOptions)
+	}
+}
+
+func TestReadValueRefRequiresLabel(t *testing.T) {
+	r := placeholderReconciler(t, &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "unlabelled", Namespace: "kube-system"},
+		Data:       map[string][]byte{"token": []byte("t0k3n")},
+	})
+	var sources rulesSources
+	rules := behaviorRules(`{"name":"modifyOutgoingRequestHeader","options":{"newHeaderValue":"${secret:kube-system/unlabelled/token}"}}`)
+	if _, err := r.resolvePlaceholders(context.Background(), rules, &sources); err == nil || !strings.Contains(err.Error(), LabelRuleValues) {
+		t.Errorf("resolvePlaceholders() error = %v, expected the missing %s label", err, LabelRuleValues)
+	}
+}
+
+func TestPropertiesReadingFrom(t *testing.T) {
+	fromRules := &akamaiV1alpha1.AkamaiProperty{
+		ObjectMeta: metav1.ObjectMeta{Name: "from-rules"},
+		Spec: akamaiV1alpha1.AkamaiPropertySpec{
+			Rules: behaviorRules(`{"name":"origin","options":{"hostname":"${secret:edge/origin-auth/host}"}}`),
+		},
+	}
+	fromVariables := &akamaiV1alpha1.AkamaiProperty{
+		ObjectMeta: metav1.ObjectMeta{Name: "from-variables"},
+		Spec: akamaiV1alpha1.AkamaiPropertySpec{Variables: []akamaiV1alpha1.PropertyVariable{{
+			Name:      "PMUSER_TOKEN",
+			ValueFrom: &akamaiV1alpha1.VariableValueSource{SecretKeyRef: &akamaiV1alpha1.SecretKeyReference{Namespace: "edge", Name: "origin-auth", Key: "token"}},
+		}}},
+	}
+	fromRendered := &akamaiV1alpha1.AkamaiProperty{
+		ObjectMeta: metav1.ObjectMeta{Name: "from-rendered"},
+		Status:     akamaiV1alpha1.AkamaiPropertyStatus{ValueSources: []string{"configmap:edge/origin-auth"}},
+	}
+	r := placeholderReconciler(t, fromRules, fromVariables, fromRendered)
+
+	tests := []struct {
+		name string
+		kind string
+		obj  client.Object
+		want []string
+	}{
+		{name: "secret", kind: placeholderSecret, obj: &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "origin-auth", Namespace: "edge"}},
+			want: []string{"from-rules", "from-variables"}},
+		{name: "configmap of the same name", kind: placeholderConfigMap, obj: &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "origin-auth", Namespace: "edge"}},
+			want: []string{"from-rendered"}},
+		{name: "other namespace", kind: placeholderSecret, obj: &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "origin-auth", Namespace: "other"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests := r.propertiesReadingFrom(tt.kind)(context.Background(), tt.obj)
+			var names []string
+			for _, request := range requests {
+				names = append(names, request.Name)
+			}
+			if strings.Join(names, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("enqueued %v, want %v", names, tt.want)
+			}
+		})
+	}
+}
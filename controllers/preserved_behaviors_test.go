@@ -0,0 +1,109 @@
+package controllers
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+)
+
+func TestPreserveBehaviors(t *testing.T) {
+	names := map[string]bool{"siteShield": true, "webApplicationFirewall": true}
+	current := map[string]interface{}{
+		"name": "default",
+		"behaviors": []interface{}{
+			map[string]interface{}{"name": "origin", "options": map[string]interface{}{"hostname": "old.example.com"}},
+			map[string]interface{}{"name": "siteShield", "options": map[string]interface{}{"ssmap": map[string]interface{}{"value": "s1.akamaiedge.net"}}},
+		},
+		"children": []interface{}{
+			map[string]interface{}{
+				"name":      "Performance",
+				"behaviors": []interface{}{map[string]interface{}{"name": "http2"}},
+			},
+			map[string]interface{}{
+				"name":      "Security",
+				"behaviors": []interface{}{map[string]interface{}{"name": "webApplicationFirewall"}},
+			},
+			map[string]interface{}{
+				"name":      "Legacy",
+				"behaviors": []interface{}{map[string]interface{}{"name": "caching"}},
+			},
+		},
+	}
+
+	tests := []struct {
+		name              string
+		desired           string
+		names             map[string]bool
+		expectedPreserved []string
+		expectedBehaviors []string
+		expectedChildren  []string
+	}{
+		{
+			name:              "keeps remote behaviors and rules",
+			desired:           `{"name":"default","behaviors":[{"name":"origin","options":{"hostname":"new.example.com"}}],"children":[{"name":"Performance","behaviors":[{"name":"http2"}]}]}`,
+			names:             names,
+			expectedPreserved: []string{"default/siteShield", "default/Security/webApplicationFirewall"},
+			expectedBehaviors: []string{"origin", "siteShield"},
+			expectedChildren:  []string{"Performance", "Security"},
+		},
+		{
+			name:              "behavior already in spec",
+			desired:           `{"name":"default","behaviors":[{"name":"siteShield"}],"children":[{"name":"Security","behaviors":[]}]}`,
+			names:             names,
+			expectedPreserved: []string{"default/Security/webApplicationFirewall"},
+			expectedBehaviors: []string{"siteShield"},
+			expectedChildren:  []string{"Security"},
+		},
+		{
+			name:              "nothing to preserve",
+			desired:           `{"name":"default","behaviors":[{"name":"origin"}]}`,
+			expectedBehaviors: []string{"origin"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var desired akamaiV1alpha1.PropertyRules
+			if err := json.Unmarshal([]byte(tt.desired), &desired); err != nil {
+				t.Fatalf("invalid desired rules: %v", err)
+			}
+			original := desired.DeepCopy()
+
+			enforced, preserved, err := preserveBehaviors(&desired, current, tt.names)
+			if err != nil {
+				t.Fatalf("preserveBehaviors() error = %v", err)
+			}
+			if !reflect.DeepEqual(preserved, tt.expectedPreserved) {
+				t.Errorf("preserved = %v, expected %v", preserved, tt.expectedPreserved)
+			}
+			if !reflect.DeepEqual(&desired, original) {
+				t.Errorf("preserveBehaviors() modified the desired rules")
+			}
+
+			var behaviors []string
+			for _, behavior := range enforced.Behaviors {
+				behaviors = append(behaviors, behavior.Name)
+			}
+			if !reflect.DeepEqual(behaviors, tt.expectedBehaviors) {
+				t.Errorf("behaviors = %v, expected %v", behaviors, tt.expectedBehaviors)
+			}
+
+			var children []string
+			for _, childRaw := range enforced.Children {
+				var child akamaiV1alpha1.PropertyRules
+				if err := json.Unmarshal(childRaw.Raw, &child); err != nil {
+					t.Fatalf("invalid child rule: %v", err)
+				}
+				children = append(children, child.Name)
+				if child.Name == "Security" && (len(child.Behaviors) != 1 || child.Behaviors[0].Name != "webApplicationFirewall") {
+					t.Errorf("Security behaviors = %+v, expected webApplicationFirewall", child.Behaviors)
+				}
+			}
+			if !reflect.DeepEqual(children, tt.expectedChildren) {
+				t.Errorf("children = %v, expected %v", children, tt.expectedChildren)
+			}
+		})
+	}
+}
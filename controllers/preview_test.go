@@ -0,0 +1,139 @@
+package controllers
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+)
+
+func TestApplyPreview(t *testing.T) {
+	base := &akamaiV1alpha1.AkamaiProperty{
+		ObjectMeta: metav1.ObjectMeta{Name: "www"},
+		Spec: akamaiV1alpha1.AkamaiPropertySpec{
+			ContractID:        "ctr_1",
+			GroupID:           "grp_1",
+			ProviderConfigRef: "tenant-a",
+			Hostnames: []akamaiV1alpha1.Hostname{
+				{CNAMEFrom: "www.example.com", CNAMETo: "www.example.com.edgekey.net"},
+				{CNAMEFrom: "example.com", CNAMETo: "www.example.com.edgekey.net"},
+			},
+			Activation: &akamaiV1alpha1.ActivationSpec{Network: "PRODUCTION", NotifyEmails: []string{"team@example.com"}},
+		},
+	}
+
+	tests := []struct {
+		name              string
+		labels            map[string]string
+		spec              akamaiV1alpha1.AkamaiPropertySpec
+		expectedHostnames []string
+		expectedContract  string
+		expectedEmails    []string
+		expectErr         string
+	}{
+		{
+			name:              "inherits from the base",
+			spec:              akamaiV1alpha1.AkamaiPropertySpec{Preview: &akamaiV1alpha1.PreviewSpec{BaseRef: "www"}},
+			expectedHostnames: []string{"www-pr-42.www.example.com", "www-pr-42.example.com"},
+			expectedContract:  "ctr_1",
+			expectedEmails:    []string{"team@example.com"},
+		},
+		{
+			name:   "hostname template with labels",
+			labels: map[string]string{"pull-request": "42"},
+			spec: akamaiV1alpha1.AkamaiPropertySpec{
+				ContractID: "ctr_2",
+				Hostnames:  []akamaiV1alpha1.Hostname{{CNAMEFrom: "www.example.com", CNAMETo: "www.example.com.edgekey.net"}},
+				Activation: &akamaiV1alpha1.ActivationSpec{Network: "PRODUCTION", NotifyEmails: []string{"pr@example.com"}},
+				Preview:    &akamaiV1alpha1.PreviewSpec{BaseRef: "www", HostnameTemplate: "pr-{pull-request}-{hostname}"},
+			},
+			expectedHostnames: []string{"pr-42-www.example.com"},
+			expectedContract:  "ctr_2",
+			expectedEmails:    []string{"pr@example.com"},
+		},
+		{
+			name:      "missing template label",
+			spec:      akamaiV1alpha1.AkamaiPropertySpec{Preview: &akamaiV1alpha1.PreviewSpec{BaseRef: "www", HostnameTemplate: "pr-{pull-request}.{hostname}"}},
+			expectErr: "needs the labels pull-request",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			property := &akamaiV1alpha1.AkamaiProperty{
+				ObjectMeta: metav1.ObjectMeta{Name: "www-pr-42", Labels: tt.labels},
+				Spec:       tt.spec,
+			}
+			err := applyPreview(property, base)
+			if tt.expectErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.expectErr) {
+					t.Fatalf("applyPreview() error = %v, expected to contain %q", err, tt.expectErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("applyPreview() error = %v", err)
+			}
+
+			if got := hostnameNames(property.Spec.Hostnames); !reflect.DeepEqual(got, tt.expectedHostnames) {
+				t.Errorf("hostnames = %v, expected %v", got, tt.expectedHostnames)
+			}
+			if property.Spec.ContractID != tt.expectedContract {
+				t.Errorf("contractId = %s, expected %s", property.Spec.ContractID, tt.expectedContract)
+			}
+			if property.Spec.ProviderConfigRef != "tenant-a" {
+				t.Errorf("providerConfigRef = %q, expected the base's", property.Spec.ProviderConfigRef)
+			}
+			activation := property.Spec.Activation
+			if activation == nil || activation.Network != previewNetwork || !reflect.DeepEqual(activation.NotifyEmails, tt.expectedEmails) {
+				t.Errorf("activation = %+v, expected staging with %v", activation, tt.expectedEmails)
+			}
+		})
+	}
+
+	// The base must not be changed through shared pointers
+	if base.Spec.Activation.Network != "PRODUCTION" || base.Spec.Hostnames[0].CNAMEFrom != "www.example.com" {
+		t.Errorf("applyPreview() modified the base: %+v", base.Spec)
+	}
+}
+
+func TestPreviewBase(t *testing.T) {
+	ctx := context.Background()
+	base := &akamaiV1alpha1.AkamaiProperty{ObjectMeta: metav1.ObjectMeta{Name: "www"}}
+	otherPreview := &akamaiV1alpha1.AkamaiProperty{ObjectMeta: metav1.ObjectMeta{Name: "www-pr-1", Labels: map[string]string{LabelPreview: "true"}}}
+	r := newFakeReconciler(t, base, otherPreview)
+
+	tests := []struct {
+		name      string
+		preview   *akamaiV1alpha1.PreviewSpec
+		expectErr string
+	}{
+		{name: "base found", preview: &akamaiV1alpha1.PreviewSpec{BaseRef: "www"}},
+		{name: "no preview spec", expectErr: "need spec.preview.baseRef"},
+		{name: "missing base", preview: &akamaiV1alpha1.PreviewSpec{BaseRef: "missing"}, expectErr: "failed to get base property missing"},
+		{name: "preview of a preview", preview: &akamaiV1alpha1.PreviewSpec{BaseRef: "www-pr-1"}, expectErr: "is a preview itself"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			property := &akamaiV1alpha1.AkamaiProperty{
+				ObjectMeta: metav1.ObjectMeta{Name: "www-pr-2", Labels: map[string]string{LabelPreview: "true"}},
+				Spec:       akamaiV1alpha1.AkamaiPropertySpec{Preview: tt.preview},
+			}
+			got, err := previewBase(ctx, r.Client, property)
+			if tt.expectErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.expectErr) {
+					t.Errorf("previewBase() error = %v, expected to contain %q", err, tt.expectErr)
+				}
+				return
+			}
+			if err != nil || got.Name != "www" {
+				t.Errorf("previewBase() = %v, %v, expected www", got, err)
+			}
+		})
+	}
+}
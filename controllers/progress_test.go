@@ -0,0 +1,138 @@
+package controllers
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+)
+
+func TestSetProgressCondition(t *testing.T) {
+	start := time.Date(2026, 10, 18, 10, 0, 0, 0, time.UTC)
+	progressing := func(generation int64, reason string, status metav1.ConditionStatus) metav1.Condition {
+		return metav1.Condition{
+			Type:               ConditionTypeProgressing,
+			Status:             status,
+			Reason:             reason,
+			ObservedGeneration: generation,
+			LastTransitionTime: metav1.NewTime(start),
+		}
+	}
+	blocked := metav1.Condition{Type: ConditionTypeReady, Status: metav1.ConditionFalse, Reason: "ActivationInProgress", Message: "Staging activation is PENDING"}
+
+	tests := []struct {
+		name               string
+		phase              string
+		observedGeneration int64
+		deadline           *int32
+		conditions         []metav1.Condition
+		now                time.Time
+		expectedReason     string
+		expectedStart      time.Time
+		expectStalled      bool
+	}{
+		{
+			name:               "ready",
+			phase:              PhaseReady,
+			observedGeneration: 2,
+			conditions:         []metav1.Condition{progressing(2, ReasonGenerationProgressing, metav1.ConditionTrue)},
+			now:                start.Add(time.Minute),
+			expectedReason:     ReasonGenerationReady,
+			expectedStart:      start,
+		},
+		{
+			name:               "new generation starts the clock",
+			phase:              PhaseReady,
+			observedGeneration: 1,
+			deadline:           ptr.To[int32](600),
+			conditions:         []metav1.Condition{progressing(1, ReasonGenerationReady, metav1.ConditionTrue)},
+			now:                start.Add(time.Hour),
+			expectedReason:     ReasonGenerationProgressing,
+			expectedStart:      start.Add(time.Hour),
+		},
+		{
+			name:               "within the deadline",
+			phase:              PhaseActivating,
+			observedGeneration: 1,
+			deadline:           ptr.To[int32](600),
+			conditions:         []metav1.Condition{progressing(2, ReasonGenerationProgressing, metav1.ConditionTrue), blocked},
+			now:                start.Add(10 * time.Minute),
+			expectedReason:     ReasonGenerationProgressing,
+			expectedStart:      start,
+		},
+		{
+			name:               "deadline exceeded",
+			phase:              PhaseActivating,
+			observedGeneration: 1,
+			deadline:           ptr.To[int32](600),
+			conditions:         []metav1.Condition{progressing(2, ReasonGenerationProgressing, metav1.ConditionTrue), blocked},
+			now:                start.Add(11 * time.Minute),
+			expectedReason:     ReasonProgressDeadlineExceeded,
+			expectedStart:      start.Add(11 * time.Minute),
+			expectStalled:      true,
+		},
+		{
+			name:               "no deadline",
+			phase:              PhaseError,
+			observedGeneration: 1,
+			conditions:         []metav1.Condition{progressing(2, ReasonGenerationProgressing, metav1.ConditionTrue), blocked},
+			now:                start.Add(24 * time.Hour),
+			expectedReason:     ReasonGenerationProgressing,
+			expectedStart:      start,
+		},
+		{
+			name:               "suspended keeps the condition",
+			phase:              PhaseSuspended,
+			observedGeneration: 1,
+			deadline:           ptr.To[int32](600),
+			conditions:         []metav1.Condition{progressing(2, ReasonGenerationProgressing, metav1.ConditionTrue)},
+			now:                start.Add(time.Hour),
+			expectedReason:     ReasonGenerationProgressing,
+			expectedStart:      start,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			property := &akamaiV1alpha1.AkamaiProperty{
+				ObjectMeta: metav1.ObjectMeta{Generation: 2},
+				Spec:       akamaiV1alpha1.AkamaiPropertySpec{ProgressDeadlineSeconds: tt.deadline},
+				Status: akamaiV1alpha1.AkamaiPropertyStatus{
+					Phase:              tt.phase,
+					ObservedGeneration: tt.observedGeneration,
+					Conditions:         tt.conditions,
+				},
+			}
+			setProgressCondition(property, tt.now)
+
+			condition := meta.FindStatusCondition(property.Status.Conditions, ConditionTypeProgressing)
+			if condition == nil {
+				t.Fatalf("Progressing condition not set")
+			}
+			if condition.Reason != tt.expectedReason {
+				t.Errorf("reason = %s, expected %s", condition.Reason, tt.expectedReason)
+			}
+			if !condition.LastTransitionTime.Time.Equal(tt.expectedStart) {
+				t.Errorf("lastTransitionTime = %s, expected %s", condition.LastTransitionTime.Time, tt.expectedStart)
+			}
+
+			reason, message := stalledReason(property)
+			if stalled := reason == ReasonProgressDeadlineExceeded; stalled != tt.expectStalled {
+				t.Errorf("stalled = %v, expected %v", stalled, tt.expectStalled)
+			}
+			if tt.expectStalled {
+				if !strings.Contains(message, "ActivationInProgress") {
+					t.Errorf("message %q does not name the blocking reason", message)
+				}
+				if property.Status.ObservedGeneration != property.Generation {
+					t.Errorf("observedGeneration = %d, expected %d", property.Status.ObservedGeneration, property.Generation)
+				}
+			}
+		})
+	}
+}
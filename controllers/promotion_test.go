@@ -0,0 +1,113 @@
+package controllers
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+	"github.com/mmz-srf/akamai-operator/pkg/akamai"
+)
+
+func TestRequestedPromotion(t *testing.T) {
+	tests := []struct {
+		name              string
+		annotations       map[string]string
+		expectedVersion   int
+		expectedRequested bool
+		expectErr         bool
+	}{
+		{name: "no annotation", annotations: map[string]string{"other": "1"}},
+		{name: "version", annotations: map[string]string{AnnotationPromoteVersion: "12"}, expectedVersion: 12, expectedRequested: true},
+		{name: "surrounding whitespace", annotations: map[string]string{AnnotationPromoteVersion: " 3\n"}, expectedVersion: 3, expectedRequested: true},
+		{name: "not a number", annotations: map[string]string{AnnotationPromoteVersion: "latest"}, expectedRequested: true, expectErr: true},
+		{name: "zero", annotations: map[string]string{AnnotationPromoteVersion: "0"}, expectedRequested: true, expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			property := &akamaiV1alpha1.AkamaiProperty{ObjectMeta: metav1.ObjectMeta{Annotations: tt.annotations}}
+			version, requested, err := requestedPromotion(property)
+			if (err != nil) != tt.expectErr {
+				t.Fatalf("requestedPromotion() error = %v, expectErr %v", err, tt.expectErr)
+			}
+			if version != tt.expectedVersion || requested != tt.expectedRequested {
+				t.Errorf("requestedPromotion() = (%d, %v), expected (%d, %v)", version, requested, tt.expectedVersion, tt.expectedRequested)
+			}
+		})
+	}
+}
+
+func TestPromotionRejection(t *testing.T) {
+	stagingActive := &akamai.PropertyVersion{StagingStatus: "ACTIVE", ProductionStatus: "INACTIVE"}
+	tests := []struct {
+		name         string
+		labels       map[string]string
+		activation   *akamaiV1alpha1.ActivationSpec
+		versionState *akamai.PropertyVersion
+		expected     string
+	}{
+		{name: "tested on staging", activation: &akamaiV1alpha1.ActivationSpec{Network: "STAGING"}, versionState: stagingActive},
+		{name: "manual production activation", activation: &akamaiV1alpha1.ActivationSpec{Network: "PRODUCTION", Trigger: akamaiV1alpha1.ActivationTriggerManual}, versionState: stagingActive},
+		{name: "no activation spec", versionState: stagingActive, expected: "spec.activation is required"},
+		{name: "production managed by spec", activation: &akamaiV1alpha1.ActivationSpec{Network: "PRODUCTION"}, versionState: stagingActive, expected: "already managed"},
+		{name: "unknown version", activation: &akamaiV1alpha1.ActivationSpec{Network: "STAGING"}, expected: "does not exist"},
+		{
+			name:         "not tested on staging",
+			activation:   &akamaiV1alpha1.ActivationSpec{Network: "STAGING"},
+			versionState: &akamai.PropertyVersion{StagingStatus: "INACTIVE"},
+			expected:     "not active on staging",
+		},
+		{
+			name:         "preview",
+			labels:       map[string]string{LabelPreview: "true"},
+			activation:   &akamaiV1alpha1.ActivationSpec{Network: "STAGING"},
+			versionState: stagingActive,
+			expected:     "only activated on staging",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			property := &akamaiV1alpha1.AkamaiProperty{
+				ObjectMeta: metav1.ObjectMeta{Labels: tt.labels},
+				Spec:       akamaiV1alpha1.AkamaiPropertySpec{Activation: tt.activation},
+				Status:     akamaiV1alpha1.AkamaiPropertyStatus{LatestVersion: 4},
+			}
+			got := promotionRejection(property, 5, tt.versionState)
+			if tt.expected == "" && got != "" || !strings.Contains(got, tt.expected) {
+				t.Errorf("promotionRejection() = %q, expected to contain %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestHandlePromotionRejectsUnknownVersion(t *testing.T) {
+	ctx := context.Background()
+	property := &akamaiV1alpha1.AkamaiProperty{
+		ObjectMeta: metav1.ObjectMeta{Name: "example", Annotations: map[string]string{AnnotationPromoteVersion: "9"}},
+		Spec:       akamaiV1alpha1.AkamaiPropertySpec{Activation: &akamaiV1alpha1.ActivationSpec{Network: "STAGING"}},
+	}
+	r := newFakeReconciler(t, property)
+	property.Status.LatestVersion = 4
+
+	// Versions beyond the latest version are rejected without asking Akamai
+	result, err := r.handlePromotion(ctx, property)
+	if err != nil {
+		t.Fatalf("handlePromotion() error = %v", err)
+	}
+	if !result.IsZero() {
+		t.Errorf("handlePromotion() = %+v, expected no requeue", result)
+	}
+	promotion := property.Status.Promotion
+	if promotion == nil || promotion.Version != 9 || promotion.State != akamaiV1alpha1.PromotionStateRejected {
+		t.Fatalf("status.promotion = %+v, expected version 9 to be rejected", promotion)
+	}
+
+	// A finished promotion is not attempted again for the same annotation value
+	if _, err := r.handlePromotion(ctx, property); err != nil {
+		t.Fatalf("handlePromotion() error = %v", err)
+	}
+}
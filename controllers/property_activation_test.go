@@ -0,0 +1,523 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v8/pkg/papi"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+	"github.com/mmz-srf/akamai-operator/pkg/akamai"
+	"github.com/mmz-srf/akamai-operator/pkg/lint"
+)
+
+// propertyActivationPAPI serves the activations of a single property
+type propertyActivationPAPI struct {
+	papi.PAPI
+	pending   []*papi.Activation
+	status    papi.ActivationStatus
+	version   int
+	createErr error
+	created   *papi.CreateActivationRequest
+	cancelled *papi.CancelActivationRequest
+	rules     papi.Rules
+}
+
+func (s *propertyActivationPAPI) GetRuleTree(_ context.Context, request papi.GetRuleTreeRequest) (*papi.GetRuleTreeResponse, error) {
+	return &papi.GetRuleTreeResponse{PropertyVersion: request.PropertyVersion, Rules: s.rules}, nil
+}
+
+func (s *propertyActivationPAPI) GetActivations(context.Context, papi.GetActivationsRequest) (*papi.GetActivationsResponse, error) {
+	return &papi.GetActivationsResponse{Activations: papi.ActivationsItems{Items: s.pending}}, nil
+}
+
+func (s *propertyActivationPAPI) CreateActivation(_ context.Context, request papi.CreateActivationRequest) (*papi.CreateActivationResponse, error) {
+	if s.createErr != nil {
+		return nil, s.createErr
+	}
+	s.created = &request
+	return &papi.CreateActivationResponse{ActivationLink: "/papi/v1/properties/prp_1/activations/atv_new"}, nil
+}
+
+func (s *propertyActivationPAPI) GetActivation(_ context.Context, request papi.GetActivationRequest) (*papi.GetActivationResponse, error) {
+	return &papi.GetActivationResponse{GetActivationsResponse: papi.GetActivationsResponse{
+		Activations: papi.ActivationsItems{Items: []*papi.Activation{{
+			ActivationID:    request.ActivationID,
+			PropertyID:      request.PropertyID,
+			PropertyVersion: s.version,
+			Status:          s.status,
+		}}},
+	}}, nil
+}
+
+func (s *propertyActivationPAPI) CancelActivation(_ context.Context, request papi.CancelActivationRequest) (*papi.CancelActivationResponse, error) {
+	s.cancelled = &request
+	return &papi.CancelActivationResponse{Activations: papi.ActivationsItems{Items: []*papi.Activation{{
+		ActivationID: request.ActivationID,
+		Status:       papi.ActivationStatusAborted,
+	}}}}, nil
+}
+
+func propertyActivationScheme(t *testing.T) *runtime.Scheme {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	if err := akamaiV1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to build scheme: %v", err)
+	}
+	return scheme
+}
+
+func TestPropertyActivationReconcile(t *testing.T) {
+	ctx := context.Background()
+	property := func(propertyID string) *akamaiV1alpha1.AkamaiProperty {
+		return &akamaiV1alpha1.AkamaiProperty{
+			ObjectMeta: metav1.ObjectMeta{Name: "example"},
+			Spec: akamaiV1alpha1.AkamaiPropertySpec{
+				PropertyName: "www.example.com",
+				ContractID:   "ctr_1",
+				GroupID:      "grp_1",
+				Activation: &akamaiV1alpha1.ActivationSpec{
+					Network:      "STAGING",
+					NotifyEmails: []string{"cdn@example.com"},
+					Production:   &akamaiV1alpha1.ActivationNotification{NoteTemplate: "v{{.Version}}: {{.Note}}"},
+				},
+			},
+			Status: akamaiV1alpha1.AkamaiPropertyStatus{PropertyID: propertyID, LatestVersion: 3},
+		}
+	}
+	activation := func(status akamaiV1alpha1.AkamaiPropertyActivationStatus) *akamaiV1alpha1.AkamaiPropertyActivation {
+		return &akamaiV1alpha1.AkamaiPropertyActivation{
+			ObjectMeta: metav1.ObjectMeta{Name: "release"},
+			Spec:       akamaiV1alpha1.AkamaiPropertyActivationSpec{PropertyRef: "example", Network: "PRODUCTION", Note: "release"},
+			Status:     status,
+		}
+	}
+	gated := func(gate func(*akamaiV1alpha1.AkamaiProperty)) *akamaiV1alpha1.AkamaiProperty {
+		gatedProperty := property("prp_1")
+		gate(gatedProperty)
+		return gatedProperty
+	}
+	noStore := papi.Rules{Name: "default", Behaviors: []papi.RuleBehavior{{Name: "caching", Options: papi.RuleOptionsMap{"behavior": "NO_STORE"}}}}
+	submitted := akamaiV1alpha1.AkamaiPropertyActivationStatus{
+		PropertyID:   "prp_1",
+		Version:      3,
+		ActivationID: "atv_1",
+		Status:       "PENDING",
+		History:      []akamaiV1alpha1.ActivationHistoryEntry{{Status: "PENDING"}},
+	}
+
+	tests := []struct {
+		name               string
+		property           *akamaiV1alpha1.AkamaiProperty
+		activation         *akamaiV1alpha1.AkamaiPropertyActivation
+		stub               *propertyActivationPAPI
+		guardrails         []string
+		expectedPhase      string
+		expectedReason     string
+		expectedID         string
+		expectedHistory    []string
+		expectedNote       string
+		expectedWarnings   int
+		expectedRequeueing bool
+	}{
+		{
+			name:               "submitted",
+			property:           property("prp_1"),
+			activation:         activation(akamaiV1alpha1.AkamaiPropertyActivationStatus{}),
+			stub:               &propertyActivationPAPI{},
+			expectedPhase:      PhaseActivating,
+			expectedID:         "atv_new",
+			expectedHistory:    []string{"PENDING"},
+			expectedNote:       "v3: release",
+			expectedRequeueing: true,
+		},
+		{
+			name:       "pending activation adopted",
+			property:   property("prp_1"),
+			activation: activation(akamaiV1alpha1.AkamaiPropertyActivationStatus{}),
+			stub: &propertyActivationPAPI{pending: []*papi.Activation{{
+				ActivationID: "atv_9", PropertyVersion: 3, Network: papi.ActivationNetworkProduction, Status: papi.ActivationStatusPending,
+			}}},
+			expectedPhase:      PhaseActivating,
+			expectedID:         "atv_9",
+			expectedHistory:    []string{"PENDING"},
+			expectedRequeueing: true,
+		},
+		{
+			name:       "warnings not acknowledged",
+			property:   property("prp_1"),
+			activation: activation(akamaiV1alpha1.AkamaiPropertyActivationStatus{}),
+			stub: &propertyActivationPAPI{createErr: &papi.Error{
+				StatusCode: 400,
+				Warnings:   json.RawMessage(`[{"messageId": "msg_1", "title": "Origin unreachable"}]`),
+			}},
+			expectedPhase:    PhaseError,
+			expectedWarnings: 1,
+		},
+		{
+			name:               "still activating",
+			property:           property("prp_1"),
+			activation:         activation(submitted),
+			stub:               &propertyActivationPAPI{status: papi.ActivationStatusZone1},
+			expectedPhase:      PhaseActivating,
+			expectedID:         "atv_1",
+			expectedHistory:    []string{"PENDING", "ZONE_1"},
+			expectedRequeueing: true,
+		},
+		{
+			name:            "active",
+			property:        property("prp_1"),
+			activation:      activation(submitted),
+			stub:            &propertyActivationPAPI{status: papi.ActivationStatusActive},
+			expectedPhase:   PhaseReady,
+			expectedID:      "atv_1",
+			expectedHistory: []string{"PENDING", "ACTIVE"},
+		},
+		{
+			name:            "failed",
+			property:        property("prp_1"),
+			activation:      activation(submitted),
+			stub:            &propertyActivationPAPI{status: papi.ActivationStatusFailed},
+			expectedPhase:   PhaseError,
+			expectedID:      "atv_1",
+			expectedHistory: []string{"PENDING", "FAILED"},
+		},
+		{
+			name:               "property suspended",
+			property:           gated(func(p *akamaiV1alpha1.AkamaiProperty) { p.Spec.Suspend = true }),
+			activation:         activation(akamaiV1alpha1.AkamaiPropertyActivationStatus{}),
+			stub:               &propertyActivationPAPI{},
+			expectedPhase:      PhaseCreating,
+			expectedReason:     "PropertySuspended",
+			expectedRequeueing: true,
+		},
+		{
+			name: "activations frozen",
+			property: gated(func(p *akamaiV1alpha1.AkamaiProperty) {
+				p.Annotations = map[string]string{AnnotationFreezeActivations: "true"}
+			}),
+			activation:         activation(akamaiV1alpha1.AkamaiPropertyActivationStatus{}),
+			stub:               &propertyActivationPAPI{},
+			expectedPhase:      PhaseCreating,
+			expectedReason:     "ActivationsFrozen",
+			expectedRequeueing: true,
+		},
+		{
+			name:               "property dry run",
+			property:           gated(func(p *akamaiV1alpha1.AkamaiProperty) { p.Spec.DryRun = true }),
+			activation:         activation(akamaiV1alpha1.AkamaiPropertyActivationStatus{}),
+			stub:               &propertyActivationPAPI{},
+			expectedPhase:      PhaseCreating,
+			expectedReason:     "PropertyDryRun",
+			expectedRequeueing: true,
+		},
+		{
+			name: "activation not managed",
+			property: gated(func(p *akamaiV1alpha1.AkamaiProperty) {
+				p.Spec.Manage = &akamaiV1alpha1.ManageSpec{Activation: ptr.To(false)}
+			}),
+			activation:         activation(akamaiV1alpha1.AkamaiPropertyActivationStatus{}),
+			stub:               &propertyActivationPAPI{},
+			expectedPhase:      PhaseCreating,
+			expectedReason:     "ActivationNotManaged",
+			expectedRequeueing: true,
+		},
+		{
+			name:               "guardrails not acknowledged",
+			property:           property("prp_1"),
+			activation:         activation(akamaiV1alpha1.AkamaiPropertyActivationStatus{}),
+			stub:               &propertyActivationPAPI{rules: noStore},
+			guardrails:         []string{lint.GuardrailNoStoreAtDefault},
+			expectedPhase:      PhaseCreating,
+			expectedReason:     "GuardrailsNotAcknowledged",
+			expectedRequeueing: true,
+		},
+		{
+			name: "guardrails acknowledged",
+			property: gated(func(p *akamaiV1alpha1.AkamaiProperty) {
+				p.Spec.Activation.AcknowledgeGuardrails = []string{guardrailID(lint.GuardrailNoStoreAtDefault, 3)}
+			}),
+			activation:         activation(akamaiV1alpha1.AkamaiPropertyActivationStatus{}),
+			stub:               &propertyActivationPAPI{rules: noStore},
+			guardrails:         []string{lint.GuardrailNoStoreAtDefault},
+			expectedPhase:      PhaseActivating,
+			expectedID:         "atv_new",
+			expectedHistory:    []string{"PENDING"},
+			expectedRequeueing: true,
+		},
+		{
+			name:               "property not created yet",
+			property:           property(""),
+			activation:         activation(akamaiV1alpha1.AkamaiPropertyActivationStatus{}),
+			stub:               &propertyActivationPAPI{},
+			expectedPhase:      PhaseCreating,
+			expectedRequeueing: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeClient := fake.NewClientBuilder().
+				WithScheme(propertyActivationScheme(t)).
+				WithObjects(tt.property, tt.activation).
+				WithStatusSubresource(&akamaiV1alpha1.AkamaiPropertyActivation{}).
+				Build()
+			r := &AkamaiPropertyActivationReconciler{
+				Client:       fakeClient,
+				AkamaiClient: akamai.NewClientWithPAPI(tt.stub),
+				Guardrails:   tt.guardrails,
+			}
+
+			result, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: "release"}})
+			if err != nil {
+				t.Fatalf("Reconcile() error = %v", err)
+			}
+			if (result.RequeueAfter > 0) != tt.expectedRequeueing {
+				t.Errorf("result = %+v, expected requeueing %v", result, tt.expectedRequeueing)
+			}
+
+			var got akamaiV1alpha1.AkamaiPropertyActivation
+			if err := r.Get(ctx, types.NamespacedName{Name: "release"}, &got); err != nil {
+				t.Fatalf("failed to get activation: %v", err)
+			}
+			if got.Status.Phase != tt.expectedPhase || got.Status.ActivationID != tt.expectedID {
+				t.Errorf("status = %+v, expected phase %s and activation %q", got.Status, tt.expectedPhase, tt.expectedID)
+			}
+			var history []string
+			for _, entry := range got.Status.History {
+				history = append(history, entry.Status)
+			}
+			if len(history) != len(tt.expectedHistory) || (len(history) > 0 && history[len(history)-1] != tt.expectedHistory[len(tt.expectedHistory)-1]) {
+				t.Errorf("history = %v, expected %v", history, tt.expectedHistory)
+			}
+			if tt.expectedReason != "" {
+				if ready := meta.FindStatusCondition(got.Status.Conditions, ConditionTypeReady); ready == nil || ready.Reason != tt.expectedReason {
+					t.Errorf("Ready condition = %+v, expected reason %s", ready, tt.expectedReason)
+				}
+				if tt.stub.created != nil {
+					t.Errorf("activation created although the property holds it back: %+v", tt.stub.created)
+				}
+			}
+			if len(got.Status.PendingWarnings) != tt.expectedWarnings {
+				t.Errorf("pending warnings = %+v, expected %d", got.Status.PendingWarnings, tt.expectedWarnings)
+			}
+			if tt.expectedNote != "" {
+				if tt.stub.created == nil {
+					t.Fatal("expected an activation to be created")
+				}
+				created := tt.stub.created.Activation
+				if created.Note != tt.expectedNote || created.PropertyVersion != 3 || len(created.NotifyEmails) != 1 {
+					t.Errorf("created activation = %+v, expected version 3 with note %q and the property's notify emails", created, tt.expectedNote)
+				}
+			}
+		})
+	}
+}
+
+func TestPropertyActivationReleasesDeletedSlot(t *testing.T) {
+	ctx := context.Background()
+	property := &akamaiV1alpha1.AkamaiProperty{
+		ObjectMeta: metav1.ObjectMeta{Name: "example"},
+		Spec: akamaiV1alpha1.AkamaiPropertySpec{
+			PropertyName: "www.example.com",
+			ContractID:   "ctr_1",
+			GroupID:      "grp_1",
+			Activation:   &akamaiV1alpha1.ActivationSpec{Network: "STAGING", NotifyEmails: []string{"cdn@example.com"}},
+		},
+		Status: akamaiV1alpha1.AkamaiPropertyStatus{PropertyID: "prp_1", LatestVersion: 3},
+	}
+	activation := &akamaiV1alpha1.AkamaiPropertyActivation{
+		ObjectMeta: metav1.ObjectMeta{Name: "release"},
+		Spec:       akamaiV1alpha1.AkamaiPropertyActivationSpec{PropertyRef: "example", Network: "STAGING"},
+	}
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(propertyActivationScheme(t)).
+		WithObjects(property, activation).
+		WithStatusSubresource(&akamaiV1alpha1.AkamaiPropertyActivation{}).
+		Build()
+	scheduler := NewActivationScheduler(1)
+	r := &AkamaiPropertyActivationReconciler{
+		Client:              fakeClient,
+		AkamaiClient:        akamai.NewClientWithPAPI(&propertyActivationPAPI{}),
+		ActivationScheduler: scheduler,
+	}
+	request := ctrl.Request{NamespacedName: types.NamespacedName{Name: "release"}}
+
+	if _, err := r.Reconcile(ctx, request); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	if granted, _ := scheduler.Acquire(activationSlot{name: "other/STAGING"}); granted {
+		t.Fatal("expected the submitted activation to hold the only slot")
+	}
+
+	if err := fakeClient.Delete(ctx, activation); err != nil {
+		t.Fatalf("failed to delete activation: %v", err)
+	}
+	if _, err := r.Reconcile(ctx, request); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	if granted, _ := scheduler.Acquire(activationSlot{name: "other/STAGING"}); !granted {
+		t.Error("expected the slot of the deleted activation to be released")
+	}
+}
+
+func TestHandleActivationResources(t *testing.T) {
+	ctx := context.Background()
+	property := func(trigger akamaiV1alpha1.ActivationTrigger, stagingVersion int) *akamaiV1alpha1.AkamaiProperty {
+		return &akamaiV1alpha1.AkamaiProperty{
+			ObjectMeta: metav1.ObjectMeta{Name: "example", UID: "uid-1", Labels: map[string]string{"akamai.com/shard": "news"}},
+			Spec: akamaiV1alpha1.AkamaiPropertySpec{
+				PropertyName: "www.example.com",
+				Activation: &akamaiV1alpha1.ActivationSpec{
+					Network:      "STAGING",
+					NotifyEmails: []string{"cdn@example.com"},
+					Note:         "release",
+					Trigger:      trigger,
+					Mode:         akamaiV1alpha1.ActivationModeResources,
+				},
+			},
+			Status: akamaiV1alpha1.AkamaiPropertyStatus{PropertyID: "prp_1", LatestVersion: 3, StagingVersion: stagingVersion},
+		}
+	}
+	existing := func(version int, phase, status string) *akamaiV1alpha1.AkamaiPropertyActivation {
+		return &akamaiV1alpha1.AkamaiPropertyActivation{
+			ObjectMeta: metav1.ObjectMeta{Name: "existing"},
+			Spec:       akamaiV1alpha1.AkamaiPropertyActivationSpec{PropertyRef: "example", Network: "STAGING", Version: version},
+			Status:     akamaiV1alpha1.AkamaiPropertyActivationStatus{ActivationID: "atv_1", Version: version, Status: status, Phase: phase},
+		}
+	}
+
+	// Cancelled by the property controller after the version changed while it was pending
+	superseded := existing(3, PhaseError, "ABORTED")
+	superseded.Name = "example-staging-v3"
+	superseded.Annotations = map[string]string{AnnotationAbortActivation: abortSuperseded}
+	superseded.OwnerReferences = []metav1.OwnerReference{{
+		APIVersion: akamaiV1alpha1.GroupVersion.String(), Kind: "AkamaiProperty", Name: "example", UID: "uid-1", Controller: ptr.To(true),
+	}}
+
+	otherProperty := existing(3, PhaseActivating, "PENDING")
+	otherProperty.Spec.PropertyRef = "other"
+
+	tests := []struct {
+		name             string
+		property         *akamaiV1alpha1.AkamaiProperty
+		existing         *akamaiV1alpha1.AkamaiPropertyActivation
+		expectedCreated  bool
+		expectedPhase    string
+		expectedRequeue  bool
+		expectedMirrored string
+	}{
+		{
+			name:            "latest version activated",
+			property:        property(akamaiV1alpha1.ActivationTriggerNoteChange, 2),
+			expectedCreated: true,
+			expectedPhase:   PhaseActivating,
+			expectedRequeue: true,
+		},
+		{
+			name:             "older activation finished",
+			property:         property(akamaiV1alpha1.ActivationTriggerOnChange, 2),
+			existing:         existing(2, PhaseReady, "ACTIVE"),
+			expectedCreated:  true,
+			expectedPhase:    PhaseActivating,
+			expectedRequeue:  true,
+			expectedMirrored: "ACTIVE",
+		},
+		{
+			name:     "manual trigger",
+			property: property(akamaiV1alpha1.ActivationTriggerManual, 2),
+		},
+		{
+			name:     "latest version active",
+			property: property(akamaiV1alpha1.ActivationTriggerNoteChange, 3),
+		},
+		{
+			name:             "activation in flight",
+			property:         property(akamaiV1alpha1.ActivationTriggerNoteChange, 1),
+			existing:         existing(2, PhaseActivating, "PENDING"),
+			expectedPhase:    PhaseActivating,
+			expectedRequeue:  true,
+			expectedMirrored: "PENDING",
+		},
+		{
+			name:             "latest version failed",
+			property:         property(akamaiV1alpha1.ActivationTriggerNoteChange, 2),
+			existing:         existing(3, PhaseError, "FAILED"),
+			expectedPhase:    PhaseError,
+			expectedRequeue:  true,
+			expectedMirrored: "FAILED",
+		},
+		{
+			name:            "activation of another property ignored",
+			property:        property(akamaiV1alpha1.ActivationTriggerNoteChange, 2),
+			existing:        otherProperty,
+			expectedCreated: true,
+			expectedPhase:   PhaseActivating,
+			expectedRequeue: true,
+		},
+		{
+			name:             "superseded activation replaced",
+			property:         property(akamaiV1alpha1.ActivationTriggerNoteChange, 2),
+			existing:         superseded,
+			expectedCreated:  true,
+			expectedPhase:    PhaseActivating,
+			expectedRequeue:  true,
+			expectedMirrored: "ABORTED",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scheme := propertyActivationScheme(t)
+			objects := []client.Object{tt.property}
+			if tt.existing != nil {
+				objects = append(objects, tt.existing)
+			}
+			fakeClient := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(objects...).
+				WithStatusSubresource(&akamaiV1alpha1.AkamaiProperty{}).
+				WithIndex(&akamaiV1alpha1.AkamaiPropertyActivation{}, activationPropertyRefField, indexActivationPropertyRef).
+				Build()
+			r := &AkamaiPropertyReconciler{Client: fakeClient, Scheme: scheme}
+
+			result, err := r.handleActivationResources(ctx, tt.property)
+			if err != nil {
+				t.Fatalf("handleActivationResources() error = %v", err)
+			}
+			if result.RequeueAfter > 0 != tt.expectedRequeue {
+				t.Errorf("result = %+v, expected requeue %v", result, tt.expectedRequeue)
+			}
+
+			var created akamaiV1alpha1.AkamaiPropertyActivation
+			err = r.Get(ctx, types.NamespacedName{Name: "example-staging-v3"}, &created)
+			if (err == nil) != tt.expectedCreated {
+				t.Fatalf("get example-staging-v3 error = %v, expected created %v", err, tt.expectedCreated)
+			}
+			if tt.expectedCreated {
+				if created.Spec.Version != 3 || created.Spec.Note != "release" || !metav1.IsControlledBy(&created, tt.property) ||
+					created.Labels["akamai.com/shard"] != "news" {
+					t.Errorf("activation = %+v, expected version 3 with the note and labels of the property, controlled by it", created)
+				}
+			}
+
+			var got akamaiV1alpha1.AkamaiProperty
+			if err := r.Get(ctx, types.NamespacedName{Name: "example"}, &got); err != nil {
+				t.Fatalf("failed to get property: %v", err)
+			}
+			if got.Status.Phase != tt.expectedPhase || got.Status.StagingActivationStatus != tt.expectedMirrored {
+				t.Errorf("status = phase %q, staging activation %q, expected %q and %q",
+					got.Status.Phase, got.Status.StagingActivationStatus, tt.expectedPhase, tt.expectedMirrored)
+			}
+		})
+	}
+}
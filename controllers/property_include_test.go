@@ -0,0 +1,288 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v8/pkg/papi"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+	"github.com/mmz-srf/akamai-operator/pkg/akamai"
+)
+
+// includesPAPI stubs the PAPI include endpoints for a single include, recording the calls
+type includesPAPI struct {
+	papi.PAPI
+	include          *papi.Include
+	rules            map[int]papi.Rules
+	activated        map[int]bool
+	activationStatus papi.ActivationStatus
+	calls            []string
+}
+
+func (s *includesPAPI) ListIncludes(_ context.Context, _ papi.ListIncludesRequest) (*papi.ListIncludesResponse, error) {
+	response := &papi.ListIncludesResponse{}
+	if s.include != nil {
+		response.Includes.Items = []papi.Include{*s.include}
+	}
+	return response, nil
+}
+
+func (s *includesPAPI) CreateInclude(_ context.Context, request papi.CreateIncludeRequest) (*papi.CreateIncludeResponse, error) {
+	s.calls = append(s.calls, "create "+request.IncludeName+" "+string(request.IncludeType))
+	s.include = &papi.Include{IncludeID: "inc_1", IncludeName: request.IncludeName, LatestVersion: 1}
+	s.rules = map[int]papi.Rules{1: {Name: "default"}}
+	return &papi.CreateIncludeResponse{IncludeID: "inc_1"}, nil
+}
+
+func (s *includesPAPI) GetInclude(_ context.Context, _ papi.GetIncludeRequest) (*papi.GetIncludeResponse, error) {
+	return &papi.GetIncludeResponse{Includes: papi.IncludeItems{Items: []papi.Include{*s.include}}}, nil
+}
+
+func (s *includesPAPI) GetIncludeVersion(_ context.Context, request papi.GetIncludeVersionRequest) (*papi.GetIncludeVersionResponse, error) {
+	status := papi.VersionStatusInactive
+	if s.activated[request.Version] {
+		status = papi.VersionStatusActive
+	}
+	return &papi.GetIncludeVersionResponse{IncludeVersion: papi.IncludeVersion{
+		IncludeVersion:   request.Version,
+		StagingStatus:    status,
+		ProductionStatus: papi.VersionStatusInactive,
+	}}, nil
+}
+
+func (s *includesPAPI) CreateIncludeVersion(_ context.Context, request papi.CreateIncludeVersionRequest) (*papi.CreateIncludeVersionResponse, error) {
+	s.calls = append(s.calls, fmt.Sprintf("create version from %d", request.CreateFromVersion))
+	version := s.include.LatestVersion + 1
+	s.rules[version] = s.rules[request.CreateFromVersion]
+	s.include.LatestVersion = version
+	return &papi.CreateIncludeVersionResponse{Version: version}, nil
+}
+
+func (s *includesPAPI) GetIncludeRuleTree(_ context.Context, request papi.GetIncludeRuleTreeRequest) (*papi.GetIncludeRuleTreeResponse, error) {
+	return &papi.GetIncludeRuleTreeResponse{
+		IncludeVersion: request.IncludeVersion,
+		Etag:           fmt.Sprintf("etag-%d", request.IncludeVersion),
+		Rules:          s.rules[request.IncludeVersion],
+	}, nil
+}
+
+func (s *includesPAPI) UpdateIncludeRuleTree(_ context.Context, request papi.UpdateIncludeRuleTreeRequest) (*papi.UpdateIncludeRuleTreeResponse, error) {
+	s.calls = append(s.calls, fmt.Sprintf("update rules %d", request.IncludeVersion))
+	s.rules[request.IncludeVersion] = request.Rules.Rules
+	return &papi.UpdateIncludeRuleTreeResponse{IncludeVersion: request.IncludeVersion, Rules: request.Rules.Rules}, nil
+}
+
+func (s *includesPAPI) ActivateInclude(_ context.Context, request papi.ActivateIncludeRequest) (*papi.ActivationIncludeResponse, error) {
+	s.calls = append(s.calls, fmt.Sprintf("activate %d on %s", request.Version, request.Network))
+	return &papi.ActivationIncludeResponse{ActivationID: fmt.Sprintf("atv_%d", request.Version)}, nil
+}
+
+func (s *includesPAPI) GetIncludeActivation(_ context.Context, request papi.GetIncludeActivationRequest) (*papi.GetIncludeActivationResponse, error) {
+	if s.activationStatus == papi.ActivationStatusActive {
+		version := s.include.LatestVersion
+		s.include.StagingVersion = &version
+		s.activated[version] = true
+	}
+	return &papi.GetIncludeActivationResponse{Activation: papi.IncludeActivation{ActivationID: request.ActivationID, Status: s.activationStatus}}, nil
+}
+
+func newIncludeReconciler(t *testing.T, stub *includesPAPI, objects ...client.Object) *AkamaiPropertyIncludeReconciler {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	if err := akamaiV1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to build scheme: %v", err)
+	}
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(objects...).
+		WithStatusSubresource(&akamaiV1alpha1.AkamaiPropertyInclude{}).
+		Build()
+	return &AkamaiPropertyIncludeReconciler{Client: fakeClient, Scheme: scheme, AkamaiClient: akamai.NewClientWithPAPI(stub)}
+}
+
+func securityHeadersInclude(header string) *akamaiV1alpha1.AkamaiPropertyInclude {
+	return &akamaiV1alpha1.AkamaiPropertyInclude{
+		ObjectMeta: metav1.ObjectMeta{Name: "security-headers", Generation: 1},
+		Spec: akamaiV1alpha1.AkamaiPropertyIncludeSpec{
+			IncludeName: "security-headers",
+			ContractID:  "ctr_1",
+			GroupID:     "grp_1",
+			ProductID:   "prd_Fresca",
+			Rules: akamaiV1alpha1.PropertyRules{Name: "default", Behaviors: []akamaiV1alpha1.RuleBehavior{{
+				Name:    "modifyOutgoingResponseHeader",
+				Options: runtime.RawExtension{Raw: []byte(`{"customHeaderName":"` + header + `"}`)},
+			}}},
+			Activation: &akamaiV1alpha1.IncludeActivationSpec{Networks: []string{"STAGING"}, NotifyEmails: []string{"ops@example.com"}},
+		},
+	}
+}
+
+func TestIncludeReconcile(t *testing.T) {
+	ctx := context.Background()
+	stub := &includesPAPI{activated: map[int]bool{}, activationStatus: papi.ActivationStatusPending}
+	include := securityHeadersInclude("X-Frame-Options")
+	r := newIncludeReconciler(t, stub, include)
+	key := types.NamespacedName{Name: include.Name}
+	reconcile := func() (ctrl.Result, *akamaiV1alpha1.AkamaiPropertyInclude) {
+		t.Helper()
+		result, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+		if err != nil {
+			t.Fatalf("Reconcile() error = %v", err)
+		}
+		var got akamaiV1alpha1.AkamaiPropertyInclude
+		if err := r.Get(ctx, key, &got); err != nil {
+			t.Fatalf("failed to get include: %v", err)
+		}
+		return result, &got
+	}
+
+	// The include is created, its first version written and activated on staging
+	result, got := reconcile()
+	expected := []string{"create security-headers COMMON_SETTINGS", "update rules 1", "activate 1 on STAGING"}
+	if !slices.Equal(stub.calls, expected) {
+		t.Errorf("calls = %q, expected %q", stub.calls, expected)
+	}
+	if got.Status.IncludeID != "inc_1" || got.Status.Phase != PhaseActivating || result.RequeueAfter != includeActivationPollInterval {
+		t.Errorf("status = %+v, requeue %v, expected a pending activation of inc_1", got.Status, result.RequeueAfter)
+	}
+	if got.Status.Staging == nil || got.Status.Staging.ActivationID != "atv_1" || got.Status.Staging.ActivationVersion != 1 {
+		t.Errorf("staging status = %+v, expected activation atv_1 of version 1", got.Status.Staging)
+	}
+
+	// The activation completes without starting another one
+	stub.calls = nil
+	stub.activationStatus = papi.ActivationStatusActive
+	result, got = reconcile()
+	if len(stub.calls) != 0 || got.Status.Phase != PhaseReady || got.Status.Staging.Version != 1 || result.RequeueAfter != includeResyncInterval {
+		t.Errorf("calls = %q, status = %+v, expected version 1 to be active on staging", stub.calls, got.Status)
+	}
+
+	// Changed rules are written to a new version, as the active one is locked
+	got.Spec.Rules = securityHeadersInclude("Strict-Transport-Security").Spec.Rules
+	got.Generation = 2
+	if err := r.Update(ctx, got); err != nil {
+		t.Fatalf("failed to update include: %v", err)
+	}
+	stub.activationStatus = papi.ActivationStatusFailed
+	_, got = reconcile()
+	expected = []string{"create version from 1", "update rules 2", "activate 2 on STAGING"}
+	if !slices.Equal(stub.calls, expected) {
+		t.Errorf("calls = %q, expected %q", stub.calls, expected)
+	}
+	if got.Status.LatestVersion != 2 {
+		t.Errorf("latest version = %d, expected 2", got.Status.LatestVersion)
+	}
+
+	// A failed activation is reported and not retried
+	stub.calls = nil
+	_, got = reconcile()
+	if got.Status.Phase != PhaseError || !strings.Contains(got.Status.Conditions[0].Message, "activation atv_2 of version 2 on STAGING is FAILED") {
+		t.Errorf("status = %+v, expected the failed activation to be reported", got.Status)
+	}
+	stub.calls = nil
+	reconcile()
+	if len(stub.calls) != 0 {
+		t.Errorf("calls = %q, expected the failed activation not to be retried", stub.calls)
+	}
+}
+
+func TestIncludeReconcileInvalidRules(t *testing.T) {
+	include := securityHeadersInclude("X-Frame-Options")
+	include.Spec.Rules.Behaviors[0].Options.Raw = []byte(`{"customHeaderValue":"${secret:edge/headers/value}"}`)
+	stub := &includesPAPI{activated: map[int]bool{}}
+	r := newIncludeReconciler(t, stub, include)
+
+	result, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: types.NamespacedName{Name: include.Name}})
+	if err != nil || result.RequeueAfter != 0 || len(stub.calls) != 0 {
+		t.Fatalf("Reconcile() = %+v, %v, calls %q, expected the invalid spec not to be retried", result, err, stub.calls)
+	}
+	var got akamaiV1alpha1.AkamaiPropertyInclude
+	_ = r.Get(context.Background(), types.NamespacedName{Name: include.Name}, &got)
+	if got.Status.Phase != PhaseError || got.Status.Conditions[0].Reason != "InvalidSpec" {
+		t.Errorf("status = %+v, expected InvalidSpec", got.Status)
+	}
+}
+
+func TestDesiredRulesResolvesIncludeRefs(t *testing.T) {
+	include := securityHeadersInclude("X-Frame-Options")
+	property := &akamaiV1alpha1.AkamaiProperty{
+		ObjectMeta: metav1.ObjectMeta{Name: "shop"},
+		Spec: akamaiV1alpha1.AkamaiPropertySpec{Rules: &akamaiV1alpha1.PropertyRules{
+			Name:     "default",
+			Children: []runtime.RawExtension{{Raw: []byte(`{"name":"Security","behaviors":[{"name":"include","options":{"id":"${include:security-headers}"}}]}`)}},
+		}},
+	}
+	other := &akamaiV1alpha1.AkamaiProperty{ObjectMeta: metav1.ObjectMeta{Name: "blog"}}
+	// References added by a patch only show in the rendered rule tree
+	patched := &akamaiV1alpha1.AkamaiProperty{
+		ObjectMeta: metav1.ObjectMeta{Name: "news"},
+		Spec: akamaiV1alpha1.AkamaiPropertySpec{
+			Rules: &akamaiV1alpha1.PropertyRules{Name: "default"},
+			RulesPatches: []akamaiV1alpha1.RulesPatch{{Patch: runtime.RawExtension{
+				Raw: []byte(`[{"op":"add","path":"/behaviors","value":[{"name":"include","options":{"id":"${include:security-headers}"}}]}]`),
+			}}},
+		},
+	}
+	r := newFakeReconciler(t, include, property, other, patched)
+	r.includeUsers = &includeUsers{}
+	if _, _, err := r.desiredRules(context.Background(), patched); err == nil || !strings.Contains(err.Error(), `"security-headers" has not been created`) {
+		t.Errorf("desiredRules() error = %v, expected the include of the patch to be missing", err)
+	}
+
+	if _, _, err := r.desiredRules(context.Background(), property); err == nil || !strings.Contains(err.Error(), `AkamaiPropertyInclude "security-headers" has not been created in Akamai yet`) {
+		t.Errorf("desiredRules() error = %v, expected the include to be missing", err)
+	}
+
+	include.Status.IncludeID = "inc_42"
+	if err := r.Status().Update(context.Background(), include); err != nil {
+		t.Fatalf("failed to update include status: %v", err)
+	}
+	desired, _, err := r.desiredRules(context.Background(), property)
+	if err != nil {
+		t.Fatalf("desiredRules() error = %v", err)
+	}
+	if child := string(desired.Children[0].Raw); !strings.Contains(child, `"id":"inc_42"`) {
+		t.Errorf("child rule = %s, expected the include ID", child)
+	}
+
+	var names []string
+	for _, request := range r.propertiesIncluding(context.Background(), include) {
+		names = append(names, request.Name)
+	}
+	slices.Sort(names)
+	if !slices.Equal(names, []string{"news", "shop"}) {
+		t.Errorf("propertiesIncluding() = %v, expected only the referencing properties", names)
+	}
+}
+
+func TestCheckIncludeVariablesOfIncludeRefs(t *testing.T) {
+	include := securityHeadersInclude("X-Frame-Options")
+	include.Spec.Rules.Behaviors[0].Options.Raw = []byte(`{"customHeaderValue":"{{user.PMUSER_FRAME_OPTIONS}}"}`)
+	property := &akamaiV1alpha1.AkamaiProperty{
+		ObjectMeta: metav1.ObjectMeta{Name: "shop"},
+		Spec: akamaiV1alpha1.AkamaiPropertySpec{Rules: &akamaiV1alpha1.PropertyRules{
+			Name:      "default",
+			Behaviors: []akamaiV1alpha1.RuleBehavior{{Name: "include", Options: runtime.RawExtension{Raw: []byte(`{"id":"${include:security-headers}"}`)}}},
+		}},
+	}
+	r := newFakeReconciler(t, include, property)
+
+	err := r.checkIncludeVariables(context.Background(), property)
+	if err == nil || !strings.Contains(err.Error(), "PMUSER_FRAME_OPTIONS (used in include security-headers)") {
+		t.Errorf("checkIncludeVariables() error = %v, expected the variable of the include to be missing", err)
+	}
+	property.Spec.Variables = []akamaiV1alpha1.PropertyVariable{{Name: "PMUSER_FRAME_OPTIONS", Value: "DENY"}}
+	if err := r.checkIncludeVariables(context.Background(), property); err != nil {
+		t.Errorf("checkIncludeVariables() error = %v", err)
+	}
+}
@@ -0,0 +1,148 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v8/pkg/session"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+	"github.com/mmz-srf/akamai-operator/pkg/akamai"
+)
+
+// purgeAPI stubs the Fast Purge endpoints, recording the requests and answering them with status
+// and body
+type purgeAPI struct {
+	session.Session
+	status   int
+	body     string
+	requests []string
+}
+
+func (s *purgeAPI) Exec(r *http.Request, out interface{}, in ...interface{}) (*http.Response, error) {
+	body, _ := json.Marshal(in[0])
+	s.requests = append(s.requests, r.URL.Path+" "+string(body))
+	if s.status == http.StatusCreated {
+		if err := json.Unmarshal([]byte(s.body), out); err != nil {
+			return nil, err
+		}
+	}
+	return &http.Response{StatusCode: s.status, Body: io.NopCloser(strings.NewReader(s.body))}, nil
+}
+
+func TestPurgeReconcile(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name             string
+		spec             akamaiV1alpha1.AkamaiPurgeSpec
+		status           int
+		body             string
+		expectedRequests []string
+		expectedPhase    string
+		expectedRequeue  time.Duration
+	}{
+		{
+			name:             "urls",
+			spec:             akamaiV1alpha1.AkamaiPurgeSpec{URLs: []string{"https://www.example.com/"}},
+			status:           http.StatusCreated,
+			body:             `{"httpStatus":201,"estimatedSeconds":5,"purgeId":"p-1"}`,
+			expectedRequests: []string{`/ccu/v3/invalidate/url/production {"objects":["https://www.example.com/"]}`},
+			expectedPhase:    PurgeInProgress,
+			expectedRequeue:  5 * time.Second,
+		},
+		{
+			name:             "cp codes deleted on staging",
+			spec:             akamaiV1alpha1.AkamaiPurgeSpec{CPCodes: []int64{12345}, Action: "delete", Network: "staging"},
+			status:           http.StatusCreated,
+			body:             `{"httpStatus":201,"estimatedSeconds":5,"purgeId":"p-1"}`,
+			expectedRequests: []string{`/ccu/v3/delete/cpcode/staging {"objects":[12345]}`},
+			expectedPhase:    PurgeInProgress,
+			expectedRequeue:  5 * time.Second,
+		},
+		{
+			name:             "refused",
+			spec:             akamaiV1alpha1.AkamaiPurgeSpec{Tags: []string{"products"}},
+			status:           http.StatusForbidden,
+			body:             `{"status":403,"title":"unauthorized cache tag"}`,
+			expectedRequests: []string{`/ccu/v3/invalidate/tag/production {"objects":["products"]}`},
+			expectedPhase:    PurgeFailed,
+		},
+		{
+			name:             "retried",
+			spec:             akamaiV1alpha1.AkamaiPurgeSpec{Tags: []string{"products"}},
+			status:           http.StatusServiceUnavailable,
+			expectedRequests: []string{`/ccu/v3/invalidate/tag/production {"objects":["products"]}`},
+			expectedPhase:    PhaseError,
+			expectedRequeue:  purgeErrorRetryInterval,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			purge := &akamaiV1alpha1.AkamaiPurge{ObjectMeta: metav1.ObjectMeta{Name: "release", Generation: 1}, Spec: tt.spec}
+			scheme := runtime.NewScheme()
+			if err := akamaiV1alpha1.AddToScheme(scheme); err != nil {
+				t.Fatalf("failed to build scheme: %v", err)
+			}
+			fakeClient := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(purge).
+				WithStatusSubresource(&akamaiV1alpha1.AkamaiPurge{}).
+				Build()
+			stub := &purgeAPI{status: tt.status, body: tt.body}
+			r := &AkamaiPurgeReconciler{Client: fakeClient, Scheme: scheme, AkamaiClient: akamai.NewClientWithSession(stub), now: func() time.Time { return now }}
+			key := types.NamespacedName{Name: purge.Name}
+
+			result, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+			if err != nil {
+				t.Fatalf("Reconcile() error = %v", err)
+			}
+			var got akamaiV1alpha1.AkamaiPurge
+			if err := r.Get(ctx, key, &got); err != nil {
+				t.Fatalf("failed to get purge: %v", err)
+			}
+			if !slices.Equal(stub.requests, tt.expectedRequests) {
+				t.Errorf("requests = %q, expected %q", stub.requests, tt.expectedRequests)
+			}
+			if got.Status.Phase != tt.expectedPhase || result.RequeueAfter != tt.expectedRequeue {
+				t.Errorf("status = %+v, requeue = %v, expected phase %s and requeue %v", got.Status, result.RequeueAfter, tt.expectedPhase, tt.expectedRequeue)
+			}
+
+			// A purge is submitted once per generation and completes after its estimated time
+			stub.requests = nil
+			now = now.Add(5 * time.Second)
+			if _, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: key}); err != nil {
+				t.Fatalf("Reconcile() error = %v", err)
+			}
+			if err := r.Get(ctx, key, &got); err != nil {
+				t.Fatalf("failed to get purge: %v", err)
+			}
+			switch tt.expectedPhase {
+			case PurgeInProgress:
+				if len(stub.requests) != 0 || got.Status.Phase != PurgeCompleted || got.Status.CompletedAt == nil {
+					t.Errorf("requests = %q, status = %+v, expected the purge to complete", stub.requests, got.Status)
+				}
+			case PurgeFailed:
+				if len(stub.requests) != 0 || got.Status.Phase != PurgeFailed {
+					t.Errorf("requests = %q, status = %+v, expected the refused purge not to be retried", stub.requests, got.Status)
+				}
+			case PhaseError:
+				if len(stub.requests) != 1 {
+					t.Errorf("requests = %q, expected the purge to be retried", stub.requests)
+				}
+			}
+		})
+	}
+}
@@ -85,7 +85,7 @@ func TestRealWorldComparison(t *testing.T) {
 		},
 	}
 
-	needsUpdate, err := reconciler.rulesNeedUpdate(desired, current)
+	needsUpdate, err := reconciler.rulesNeedUpdate(desired, current, nil)
 	if err != nil {
 		t.Fatalf("rulesNeedUpdate() error = %v", err)
 	}
@@ -178,7 +178,7 @@ func TestEmptyArraysAndObjects(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result, err := reconciler.rulesNeedUpdate(tt.desired, tt.current)
+			result, err := reconciler.rulesNeedUpdate(tt.desired, tt.current, nil)
 			if err != nil {
 				t.Errorf("rulesNeedUpdate() error = %v", err)
 				return
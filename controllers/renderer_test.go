@@ -0,0 +1,56 @@
+package controllers
+
+import (
+	"strings"
+	"testing"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+	"github.com/mmz-srf/akamai-operator/pkg/render"
+)
+
+func TestValidateRenderer(t *testing.T) {
+	r := &AkamaiPropertyReconciler{Renderers: map[string]render.Renderer{"corp": render.Exec{Command: "/bin/render"}}}
+	configMap := &akamaiV1alpha1.ConfigMapReference{Namespace: "akamai", Name: "shop-rules"}
+	artifact := &akamaiV1alpha1.RulesSource{OCIRef: "registry.example.com/akamai/shop-rules:v1"}
+
+	tests := []struct {
+		name      string
+		renderer  *akamaiV1alpha1.RendererSpec
+		rules     *akamaiV1alpha1.PropertyRules
+		rulesFrom *akamaiV1alpha1.RulesSource
+		err       string
+	}{
+		{name: "inline", rules: &akamaiV1alpha1.PropertyRules{Name: "default"}},
+		{name: "pipeline", renderer: &akamaiV1alpha1.RendererSpec{Type: akamaiV1alpha1.RendererTypePipeline, ConfigMapRef: configMap}},
+		{name: "template without ConfigMap", renderer: &akamaiV1alpha1.RendererSpec{Type: akamaiV1alpha1.RendererTypeTemplate}, err: "requires configMapRef"},
+		{name: "registered external renderer", renderer: &akamaiV1alpha1.RendererSpec{Type: akamaiV1alpha1.RendererTypeExternal, External: "corp"}},
+		{name: "unknown external renderer", renderer: &akamaiV1alpha1.RendererSpec{Type: akamaiV1alpha1.RendererTypeExternal, External: "other"}, err: "not registered"},
+		{name: "rules with a renderer", renderer: &akamaiV1alpha1.RendererSpec{Type: akamaiV1alpha1.RendererTypePipeline, ConfigMapRef: configMap},
+			rules: &akamaiV1alpha1.PropertyRules{Name: "default"}, err: "rules must be empty"},
+		{name: "rules from URL", rulesFrom: &akamaiV1alpha1.RulesSource{URL: "https://rules.example.com/shop.json"}},
+		{name: "template from artifact", renderer: &akamaiV1alpha1.RendererSpec{Type: akamaiV1alpha1.RendererTypeTemplate}, rulesFrom: artifact},
+		{name: "rules from URL and artifact", rulesFrom: &akamaiV1alpha1.RulesSource{URL: "https://rules.example.com/shop.json", OCIRef: artifact.OCIRef},
+			err: "exactly one of url and ociRef"},
+		{name: "rules from HTTP URL", rulesFrom: &akamaiV1alpha1.RulesSource{URL: "http://rules.example.com/shop.json"}, err: "must use https"},
+		{name: "rules from invalid artifact", rulesFrom: &akamaiV1alpha1.RulesSource{OCIRef: "registry.example.com/shop@sha256:abc"}, err: "invalid digest"},
+		{name: "rules from artifact and spec", rules: &akamaiV1alpha1.PropertyRules{Name: "default"}, rulesFrom: artifact, err: "rules must be empty with rulesFrom"},
+		{name: "rules from artifact and ConfigMap", renderer: &akamaiV1alpha1.RendererSpec{Type: akamaiV1alpha1.RendererTypePipeline, ConfigMapRef: configMap},
+			rulesFrom: artifact, err: "configMapRef must be empty"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			property := &akamaiV1alpha1.AkamaiProperty{Spec: akamaiV1alpha1.AkamaiPropertySpec{Renderer: tt.renderer, Rules: tt.rules, RulesFrom: tt.rulesFrom}}
+			err := r.validateRenderer(property)
+			if tt.err == "" && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.err != "" && (err == nil || !strings.Contains(err.Error(), tt.err)) {
+				t.Fatalf("expected error containing %q, got %v", tt.err, err)
+			}
+			if managesRules(property) != (tt.rules != nil || tt.renderer != nil || tt.rulesFrom != nil) {
+				t.Errorf("managesRules() = %v", managesRules(property))
+			}
+		})
+	}
+}
@@ -0,0 +1,295 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	k8syaml "k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/yaml"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+	"github.com/mmz-srf/akamai-operator/pkg/akamai"
+)
+
+// RestoreClient is the part of the Akamai client a restore needs
+type RestoreClient interface {
+	FindPropertyByName(ctx context.Context, propertyName string) (*akamai.PropertySearchResult, error)
+	EnsureEdgeHostnamesExist(ctx context.Context, hostnames []akamaiV1alpha1.Hostname, edgeHostnameSpec *akamaiV1alpha1.EdgeHostnameSpec, productID, contractID, groupID string) ([]string, error)
+	CreateProperty(ctx context.Context, spec *akamaiV1alpha1.AkamaiPropertySpec) (string, error)
+	SetVersionNotes(ctx context.Context, propertyID string, version int, contractID, groupID, notes string) error
+	SetPropertyHostnames(ctx context.Context, propertyID, contractID, groupID string, version int, hostnames []akamaiV1alpha1.Hostname) error
+	UpdatePropertyRules(ctx context.Context, propertyID string, version int, contractID, groupID string, rules interface{}, etag, versionNotes string) (*akamai.PropertyRules, error)
+}
+
+// Restorer re-creates the Akamai properties of AkamaiProperty manifests, e.g. in a fresh contract
+// and group after losing the original account. Properties are created with their edge hostnames,
+// hostnames, version notes and inline rules but not activated; the retargeted manifests carry the
+// akamai.com/property-id annotation so the operator adopts the restored properties once they are
+// applied and takes over rules rendering and activation.
+type Restorer struct {
+	AkamaiClient RestoreClient
+
+	// ContractID and GroupID replace the contract and group of every manifest when set
+	ContractID string
+	GroupID    string
+
+	// EdgeHostnameTemplate renders empty edge hostname domain prefixes, like --edge-hostname-template
+	EdgeHostnameTemplate string
+
+	// DryRun only reports what would be restored
+	DryRun bool
+}
+
+// RestoreResult is the outcome of restoring one property
+type RestoreResult struct {
+	Name          string
+	PropertyName  string
+	PropertyID    string
+	EdgeHostnames []string
+	Hostnames     int
+	RulesApplied  bool
+	Skipped       string
+	Err           error
+}
+
+// String renders the result for the restore report
+func (r RestoreResult) String() string {
+	switch {
+	case r.Err != nil:
+		return fmt.Sprintf("%s: FAILED: %v", r.Name, r.Err)
+	case r.Skipped != "":
+		return fmt.Sprintf("%s: skipped: %s", r.Name, r.Skipped)
+	}
+	rules := "rules left to the operator"
+	if r.RulesApplied {
+		rules = "rules applied"
+	}
+	return fmt.Sprintf("%s: %s (%s), %d edge hostname(s) created, %d hostname(s), %s",
+		r.Name, r.PropertyName, r.PropertyID, len(r.EdgeHostnames), r.Hostnames, rules)
+}
+
+// Restore restores the properties in order. A failing property doesn't stop the others; the
+// returned error joins all failures.
+func (r *Restorer) Restore(ctx context.Context, properties []akamaiV1alpha1.AkamaiProperty) ([]RestoreResult, error) {
+	results := make([]RestoreResult, 0, len(properties))
+	var errs []error
+	for i := range properties {
+		result := r.restoreProperty(ctx, &properties[i])
+		if result.Err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", result.Name, result.Err))
+		}
+		results = append(results, result)
+	}
+	return results, errors.Join(errs...)
+}
+
+// restoreProperty retargets and restores a single property
+func (r *Restorer) restoreProperty(ctx context.Context, akamaiProperty *akamaiV1alpha1.AkamaiProperty) RestoreResult {
+	logger := log.FromContext(ctx).WithValues("property", akamaiProperty.Name)
+	r.retarget(akamaiProperty)
+	spec := &akamaiProperty.Spec
+	result := RestoreResult{Name: akamaiProperty.Name, PropertyName: spec.PropertyName, Hostnames: len(spec.Hostnames)}
+
+	if isPreview(akamaiProperty) {
+		result.Skipped = "preview properties are cloned from their base by the operator"
+		return result
+	}
+	if spec.ContractID == "" || spec.GroupID == "" || spec.ProductID == "" {
+		result.Err = fmt.Errorf("contractId, groupId and productId are required; defaults of provider configs are not resolved by a restore")
+		return result
+	}
+
+	existing, err := r.AkamaiClient.FindPropertyByName(ctx, spec.PropertyName)
+	if err != nil {
+		result.Err = fmt.Errorf("failed to search property: %w", err)
+		return result
+	}
+	if existing != nil {
+		// Re-running a restore must not create duplicates; the existing property is adopted as is
+		result.PropertyID = existing.PropertyID
+		result.Skipped = fmt.Sprintf("property already exists as %s", existing.PropertyID)
+		setRestoredPropertyID(akamaiProperty, existing.PropertyID)
+		return result
+	}
+	if r.DryRun {
+		result.Skipped = "dry run"
+		return result
+	}
+
+	if len(spec.Hostnames) > 0 {
+		edgeHostnameSpec, err := renderEdgeHostnameSpec(r.EdgeHostnameTemplate, akamaiProperty)
+		if err != nil {
+			result.Err = err
+			return result
+		}
+		// Enrollments belong to a contract; a restored property needs the ID of the new enrollment
+		if edgeHostnameSpec != nil && edgeHostnameSpec.CertificateRef != "" {
+			result.Err = fmt.Errorf("edgeHostname.certificateRef %q cannot be resolved in a restore, set edgeHostname.certEnrollmentId instead", edgeHostnameSpec.CertificateRef)
+			return result
+		}
+		result.EdgeHostnames, err = r.AkamaiClient.EnsureEdgeHostnamesExist(ctx, spec.Hostnames, edgeHostnameSpec, spec.ProductID, spec.ContractID, spec.GroupID)
+		if err != nil {
+			result.Err = fmt.Errorf("failed to restore edge hostnames: %w", err)
+			return result
+		}
+	}
+
+	logger.Info("Creating property", "propertyName", spec.PropertyName, "contractID", spec.ContractID, "groupID", spec.GroupID)
+	result.PropertyID, err = r.AkamaiClient.CreateProperty(ctx, spec)
+	if err != nil {
+		result.Err = fmt.Errorf("failed to create property: %w", err)
+		return result
+	}
+	setRestoredPropertyID(akamaiProperty, result.PropertyID)
+
+	notes := renderVersionNotes(akamaiProperty)
+	if notes != "" {
+		if err := r.AkamaiClient.SetVersionNotes(ctx, result.PropertyID, 1, spec.ContractID, spec.GroupID, notes); err != nil {
+			result.Err = fmt.Errorf("failed to set version notes: %w", err)
+			return result
+		}
+	}
+	if len(spec.Hostnames) > 0 {
+		if err := r.AkamaiClient.SetPropertyHostnames(ctx, result.PropertyID, spec.ContractID, spec.GroupID, 1, spec.Hostnames); err != nil {
+			result.Err = fmt.Errorf("failed to restore hostnames: %w", err)
+			return result
+		}
+	}
+
+	// Variables and renderers may read cluster resources; those rule trees are applied by the operator
+	if spec.Rules != nil && len(spec.Variables) == 0 && rendererType(akamaiProperty) == akamaiV1alpha1.RendererTypeInline {
+		rules, err := convertRulesToAkamaiFormat(spec.Rules)
+		if err != nil {
+			result.Err = err
+			return result
+		}
+		if _, err := r.AkamaiClient.UpdatePropertyRules(ctx, result.PropertyID, 1, spec.ContractID, spec.GroupID, rules, "", notes); err != nil {
+			result.Err = fmt.Errorf("failed to restore rules: %w", err)
+			return result
+		}
+		result.RulesApplied = true
+	}
+	return result
+}
+
+// retarget points the manifest at the restore contract and group and drops the state of the
+// original property
+func (r *Restorer) retarget(akamaiProperty *akamaiV1alpha1.AkamaiProperty) {
+	if r.ContractID != "" {
+		akamaiProperty.Spec.ContractID = r.ContractID
+	}
+	if r.GroupID != "" {
+		akamaiProperty.Spec.GroupID = r.GroupID
+	}
+	akamaiProperty.Status = akamaiV1alpha1.AkamaiPropertyStatus{}
+	akamaiProperty.ResourceVersion = ""
+	akamaiProperty.UID = ""
+	akamaiProperty.Generation = 0
+	akamaiProperty.CreationTimestamp.Reset()
+	akamaiProperty.ManagedFields = nil
+	akamaiProperty.Finalizers = nil
+	delete(akamaiProperty.Annotations, AnnotationAppliedChecksum)
+}
+
+// setRestoredPropertyID records the restored property in the manifest for adoption
+func setRestoredPropertyID(akamaiProperty *akamaiV1alpha1.AkamaiProperty, propertyID string) {
+	if akamaiProperty.Annotations == nil {
+		akamaiProperty.Annotations = make(map[string]string)
+	}
+	akamaiProperty.Annotations[AnnotationPropertyID] = propertyID
+}
+
+// LoadPropertyManifests reads the AkamaiProperty manifests of YAML or JSON files. Directories are
+// read recursively; other kinds are ignored. Manifests are returned sorted by name.
+func LoadPropertyManifests(paths []string) ([]akamaiV1alpha1.AkamaiProperty, error) {
+	var files []string
+	for _, path := range paths {
+		err := filepath.WalkDir(path, func(file string, entry os.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			switch strings.ToLower(filepath.Ext(file)) {
+			case ".yaml", ".yml", ".json":
+				if !entry.IsDir() {
+					files = append(files, file)
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+	}
+
+	var properties []akamaiV1alpha1.AkamaiProperty
+	seen := make(map[string]string)
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", file, err)
+		}
+		decoder := k8syaml.NewYAMLOrJSONDecoder(strings.NewReader(string(data)), 4096)
+		for {
+			var property akamaiV1alpha1.AkamaiProperty
+			if err := decoder.Decode(&property); errors.Is(err, io.EOF) {
+				break
+			} else if err != nil {
+				return nil, fmt.Errorf("failed to parse %s: %w", file, err)
+			}
+			if property.Kind != "AkamaiProperty" || property.APIVersion != akamaiV1alpha1.GroupVersion.String() {
+				continue
+			}
+			if other, ok := seen[property.Name]; ok {
+				return nil, fmt.Errorf("AkamaiProperty %s is defined in %s and %s", property.Name, other, file)
+			}
+			seen[property.Name] = file
+			properties = append(properties, property)
+		}
+	}
+
+	sort.Slice(properties, func(i, j int) bool { return properties[i].Name < properties[j].Name })
+	return properties, nil
+}
+
+// WriteRestoredManifests writes the retargeted manifests as one YAML file per property
+func WriteRestoredManifests(dir string, properties []akamaiV1alpha1.AkamaiProperty) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", dir, err)
+	}
+	for i := range properties {
+		data, err := yaml.Marshal(&properties[i])
+		if err != nil {
+			return fmt.Errorf("failed to marshal %s: %w", properties[i].Name, err)
+		}
+		file := filepath.Join(dir, properties[i].Name+".yaml")
+		if err := os.WriteFile(file, data, 0o644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", file, err)
+		}
+	}
+	return nil
+}
+
+// adoptProperty takes over the existing Akamai property with the given ID instead of creating one.
+// The property must have the name of the spec; its hostnames are treated as managed.
+func (r *AkamaiPropertyReconciler) adoptProperty(ctx context.Context, akamaiProperty *akamaiV1alpha1.AkamaiProperty, propertyID string) error {
+	property, err := r.AkamaiClient.GetProperty(ctx, propertyID)
+	if err != nil {
+		return fmt.Errorf("failed to get property %s: %w", propertyID, err)
+	}
+	if property.PropertyName != akamaiProperty.Spec.PropertyName {
+		return fmt.Errorf("property %s is named %q, not %q", propertyID, property.PropertyName, akamaiProperty.Spec.PropertyName)
+	}
+
+	log.FromContext(ctx).Info("Adopting existing Akamai property", "propertyID", propertyID, "latestVersion", property.LatestVersion)
+	akamaiProperty.Status.PropertyID = propertyID
+	akamaiProperty.Status.LatestVersion = property.LatestVersion
+	akamaiProperty.Status.ManagedHostnames = hostnameNames(akamaiProperty.Spec.Hostnames)
+	return r.updateStatusWithRetry(ctx, akamaiProperty)
+}
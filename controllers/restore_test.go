@@ -0,0 +1,141 @@
+package controllers
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+	"github.com/mmz-srf/akamai-operator/pkg/akamai"
+)
+
+// restoreStub records the calls of a restore
+type restoreStub struct {
+	existing map[string]string
+	calls    []string
+}
+
+func (s *restoreStub) FindPropertyByName(_ context.Context, name string) (*akamai.PropertySearchResult, error) {
+	if id, ok := s.existing[name]; ok {
+		return &akamai.PropertySearchResult{PropertyID: id, PropertyName: name}, nil
+	}
+	return nil, nil
+}
+
+func (s *restoreStub) EnsureEdgeHostnamesExist(_ context.Context, hostnames []akamaiV1alpha1.Hostname, _ *akamaiV1alpha1.EdgeHostnameSpec, _, contractID, groupID string) ([]string, error) {
+	s.calls = append(s.calls, "edge hostnames "+contractID+"/"+groupID)
+	return []string{hostnames[0].CNAMETo}, nil
+}
+
+func (s *restoreStub) CreateProperty(_ context.Context, spec *akamaiV1alpha1.AkamaiPropertySpec) (string, error) {
+	s.calls = append(s.calls, "create "+spec.PropertyName+" in "+spec.ContractID+"/"+spec.GroupID)
+	return "prp_2", nil
+}
+
+func (s *restoreStub) SetVersionNotes(_ context.Context, propertyID string, _ int, _, _, _ string) error {
+	s.calls = append(s.calls, "notes "+propertyID)
+	return nil
+}
+
+func (s *restoreStub) SetPropertyHostnames(_ context.Context, propertyID, _, _ string, _ int, _ []akamaiV1alpha1.Hostname) error {
+	s.calls = append(s.calls, "hostnames "+propertyID)
+	return nil
+}
+
+func (s *restoreStub) UpdatePropertyRules(_ context.Context, propertyID string, _ int, _, _ string, _ interface{}, _, _ string) (*akamai.PropertyRules, error) {
+	s.calls = append(s.calls, "rules "+propertyID)
+	return &akamai.PropertyRules{}, nil
+}
+
+func TestRestore(t *testing.T) {
+	dir := t.TempDir()
+	manifests := `apiVersion: akamai.com/v1alpha1
+kind: AkamaiProperty
+metadata:
+  name: shop
+  annotations:
+    akamai.com/applied-checksum: sha256:abc
+spec:
+  propertyName: shop.example.com
+  contractId: ctr_OLD
+  groupId: grp_OLD
+  productId: prd_Fresca
+  description: Shop
+  hostnames:
+    - cnameFrom: shop.example.com
+      cnameTo: shop.example.com.edgesuite.net
+  rules:
+    name: default
+status:
+  propertyId: prp_1
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: unrelated
+---
+apiVersion: akamai.com/v1alpha1
+kind: AkamaiProperty
+metadata:
+  name: blog
+spec:
+  propertyName: blog.example.com
+  contractId: ctr_OLD
+  groupId: grp_OLD
+  productId: prd_Fresca
+`
+	if err := os.WriteFile(filepath.Join(dir, "properties.yaml"), []byte(manifests), 0o644); err != nil {
+		t.Fatalf("failed to write manifests: %v", err)
+	}
+
+	properties, err := LoadPropertyManifests([]string{dir})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(properties) != 2 || properties[0].Name != "blog" || properties[1].Name != "shop" {
+		t.Fatalf("unexpected manifests %v", properties)
+	}
+
+	stub := &restoreStub{existing: map[string]string{"blog.example.com": "prp_9"}}
+	restorer := &Restorer{AkamaiClient: stub, ContractID: "ctr_NEW", GroupID: "grp_NEW"}
+	results, err := restorer.Restore(context.Background(), properties)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expectedCalls := []string{
+		"edge hostnames ctr_NEW/grp_NEW",
+		"create shop.example.com in ctr_NEW/grp_NEW",
+		"notes prp_2",
+		"hostnames prp_2",
+		"rules prp_2",
+	}
+	if !reflect.DeepEqual(stub.calls, expectedCalls) {
+		t.Errorf("calls = %v, expected %v", stub.calls, expectedCalls)
+	}
+	if results[0].Skipped == "" || results[1].PropertyID != "prp_2" || !results[1].RulesApplied {
+		t.Errorf("unexpected results %v", results)
+	}
+
+	shop := properties[1]
+	if shop.Annotations[AnnotationPropertyID] != "prp_2" || properties[0].Annotations[AnnotationPropertyID] != "prp_9" {
+		t.Errorf("unexpected property ID annotations %v, %v", shop.Annotations, properties[0].Annotations)
+	}
+	if _, ok := shop.Annotations[AnnotationAppliedChecksum]; ok || shop.Status.PropertyID != "" {
+		t.Errorf("state of the original property was kept: %v, %v", shop.Annotations, shop.Status)
+	}
+
+	output := filepath.Join(dir, "restored")
+	if err := WriteRestoredManifests(output, properties); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	restored, err := LoadPropertyManifests([]string{output})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(restored) != 2 || restored[1].Spec.ContractID != "ctr_NEW" || restored[1].Annotations[AnnotationPropertyID] != "prp_2" {
+		t.Errorf("unexpected restored manifests %v", restored)
+	}
+}
@@ -0,0 +1,107 @@
+package controllers
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+	"github.com/mmz-srf/akamai-operator/pkg/akamai"
+)
+
+func TestRuleValidationStatus(t *testing.T) {
+	tests := []struct {
+		name             string
+		result           *akamai.RuleValidationResult
+		expectedResult   string
+		expectedErrors   int
+		expectedWarnings int
+	}{
+		{name: "valid", result: &akamai.RuleValidationResult{PropertyVersion: 3}, expectedResult: RuleValidationValid},
+		{
+			name:             "valid with warnings",
+			result:           &akamai.RuleValidationResult{PropertyVersion: 3, Warnings: []akamai.RuleWarning{{Type: "deprecated", ErrorLocation: "#/rules"}}},
+			expectedResult:   RuleValidationValid,
+			expectedWarnings: 1,
+		},
+		{
+			name:           "invalid",
+			result:         &akamai.RuleValidationResult{PropertyVersion: 3, Errors: []akamai.RuleError{{Type: "missing_option", ErrorLocation: "#/rules/behaviors/0"}}},
+			expectedResult: RuleValidationInvalid,
+			expectedErrors: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status := ruleValidationStatus(tt.result, 2)
+			if status.Result != tt.expectedResult {
+				t.Errorf("Result = %q, expected %q", status.Result, tt.expectedResult)
+			}
+			if status.ObservedGeneration != 2 || status.PropertyVersion != 3 {
+				t.Errorf("status = %+v, expected generation 2 and version 3", status)
+			}
+			if len(status.Errors) != tt.expectedErrors || len(status.Warnings) != tt.expectedWarnings {
+				t.Errorf("got %d errors and %d warnings, expected %d and %d",
+					len(status.Errors), len(status.Warnings), tt.expectedErrors, tt.expectedWarnings)
+			}
+		})
+	}
+}
+
+func TestAkamaiRuleValidationReconcile(t *testing.T) {
+	ctx := context.Background()
+	pending := &akamaiV1alpha1.AkamaiProperty{ObjectMeta: metav1.ObjectMeta{Name: "pending"}}
+	missingRef := &akamaiV1alpha1.AkamaiRuleValidation{
+		ObjectMeta: metav1.ObjectMeta{Name: "missing-ref", Generation: 1},
+		Spec:       akamaiV1alpha1.AkamaiRuleValidationSpec{PropertyRef: "missing"},
+	}
+	notCreated := &akamaiV1alpha1.AkamaiRuleValidation{
+		ObjectMeta: metav1.ObjectMeta{Name: "not-created", Generation: 1},
+		Spec:       akamaiV1alpha1.AkamaiRuleValidationSpec{PropertyRef: "pending"},
+	}
+	done := &akamaiV1alpha1.AkamaiRuleValidation{
+		ObjectMeta: metav1.ObjectMeta{Name: "done", Generation: 1},
+		Spec:       akamaiV1alpha1.AkamaiRuleValidationSpec{PropertyRef: "missing"},
+		Status:     akamaiV1alpha1.AkamaiRuleValidationStatus{Result: RuleValidationValid, ObservedGeneration: 1},
+	}
+	fake := newFakeReconciler(t, pending, missingRef, notCreated, done)
+	// The Akamai client is never used on these paths
+	r := &AkamaiRuleValidationReconciler{Client: fake.Client, Scheme: fake.Scheme, AkamaiClient: &akamai.Client{}}
+
+	tests := []struct {
+		name            string
+		resource        string
+		expectedResult  string
+		expectedMessage string
+		expectRequeue   bool
+	}{
+		{name: "unknown property", resource: "missing-ref", expectedResult: RuleValidationError, expectedMessage: "not found", expectRequeue: true},
+		{name: "property not created yet", resource: "not-created", expectedResult: RuleValidationError, expectedMessage: "not been created", expectRequeue: true},
+		{name: "generation already validated", resource: "done", expectedResult: RuleValidationValid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: tt.resource}})
+			if err != nil {
+				t.Fatalf("Reconcile() error = %v", err)
+			}
+			if (result.RequeueAfter > 0) != tt.expectRequeue {
+				t.Errorf("Reconcile() = %+v, expectRequeue %v", result, tt.expectRequeue)
+			}
+
+			var validation akamaiV1alpha1.AkamaiRuleValidation
+			if err := r.Get(ctx, types.NamespacedName{Name: tt.resource}, &validation); err != nil {
+				t.Fatalf("failed to get validation: %v", err)
+			}
+			if validation.Status.Result != tt.expectedResult || !strings.Contains(validation.Status.Message, tt.expectedMessage) {
+				t.Errorf("status = %+v, expected result %q with message containing %q", validation.Status, tt.expectedResult, tt.expectedMessage)
+			}
+		})
+	}
+}
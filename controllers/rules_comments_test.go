@@ -0,0 +1,46 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+)
+
+func TestDesiredRulesInjectsManagedComments(t *testing.T) {
+	property := &akamaiV1alpha1.AkamaiProperty{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "example",
+			UID:         "1234-abcd",
+			Annotations: map[string]string{AnnotationGitRevision: "deadbeef"},
+		},
+		Spec: akamaiV1alpha1.AkamaiPropertySpec{
+			Rules: &akamaiV1alpha1.PropertyRules{Name: "default", Comments: "Main rule"},
+		},
+	}
+
+	disabled := &AkamaiPropertyReconciler{}
+	if rules, _, _ := disabled.desiredRules(context.Background(), property); rules.Comments != "Main rule" {
+		t.Errorf("comments should be untouched when injection is disabled, got %q", rules.Comments)
+	}
+
+	reconciler := &AkamaiPropertyReconciler{InjectRuleComments: true}
+	rules, _, err := reconciler.desiredRules(context.Background(), property)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := "Main rule\n\n[managed-by akamai-operator]\nresource: example\nuid: 1234-abcd\ngit-revision: deadbeef"
+	if rules.Comments != expected {
+		t.Errorf("unexpected comments:\n%s\nexpected:\n%s", rules.Comments, expected)
+	}
+	if property.Spec.Rules.Comments != "Main rule" {
+		t.Errorf("spec rules must not be modified, got %q", property.Spec.Rules.Comments)
+	}
+
+	// Re-injecting into already injected comments must be stable
+	if again := appendManagedComments(rules.Comments, property); again != expected {
+		t.Errorf("injection is not idempotent:\n%s", again)
+	}
+}
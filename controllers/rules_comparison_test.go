@@ -259,7 +259,7 @@ func TestRulesNeedUpdate(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result, err := reconciler.rulesNeedUpdate(tt.desired, tt.current)
+			result, err := reconciler.rulesNeedUpdate(tt.desired, tt.current, nil)
 			if err != nil {
 				t.Errorf("rulesNeedUpdate() error = %v", err)
 				return
@@ -0,0 +1,68 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/events"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+)
+
+func TestRecordRulesDiff(t *testing.T) {
+	property := &akamaiV1alpha1.AkamaiProperty{
+		ObjectMeta: metav1.ObjectMeta{Name: "example", Generation: 1},
+		Spec:       akamaiV1alpha1.AkamaiPropertySpec{PropertyName: "example.com"},
+	}
+	r := newFakeReconciler(t, property)
+	recorder := events.NewFakeRecorder(10)
+	r.Recorder = recorder
+
+	desired := &akamaiV1alpha1.PropertyRules{
+		Name:      "default",
+		Behaviors: []akamaiV1alpha1.RuleBehavior{{Name: "origin", Options: runtime.RawExtension{Raw: []byte(`{"hostname":"new.example.com"}`)}}},
+		Children:  []runtime.RawExtension{{Raw: []byte(`{"name":"Images"}`)}},
+	}
+	current := map[string]interface{}{
+		"name":      "default",
+		"behaviors": []interface{}{map[string]interface{}{"name": "origin", "options": map[string]interface{}{"hostname": "old.example.com"}}},
+	}
+
+	r.recordRulesDiff(context.Background(), property, desired, current, 3)
+	expected := []string{`Change behavior "origin" of rule "default"`, `Add rule "default/Images"`}
+	if strings.Join(property.Status.LastRulesDiff, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("status.lastRulesDiff = %v, expected %v", property.Status.LastRulesDiff, expected)
+	}
+	select {
+	case event := <-recorder.Events:
+		if !strings.Contains(event, ReasonRulesChanged) || !strings.Contains(event, "version 3") || !strings.Contains(event, expected[1]) {
+			t.Errorf("unexpected event %q", event)
+		}
+	default:
+		t.Errorf("expected a %s event", ReasonRulesChanged)
+	}
+	if strings.Contains(strings.Join(property.Status.LastRulesDiff, " "), "example.com") {
+		t.Error("the diff must not contain option values")
+	}
+}
+
+func TestTruncateChanges(t *testing.T) {
+	var changes []string
+	for i := 1; i <= 25; i++ {
+		changes = append(changes, fmt.Sprintf("Add rule %q", fmt.Sprintf("default/%d", i)))
+	}
+	truncated := truncateChanges(changes, maxRulesDiff)
+	if len(truncated) != maxRulesDiff+1 || truncated[maxRulesDiff] != "... and 5 more changes" {
+		t.Errorf("truncateChanges() = %v", truncated)
+	}
+	if len(changes) != 25 || changes[maxRulesDiff] != `Add rule "default/21"` {
+		t.Error("truncateChanges() must not modify the changes")
+	}
+	if got := truncateChanges(changes[:3], maxRulesDiff); len(got) != 3 {
+		t.Errorf("truncateChanges() = %v", got)
+	}
+}
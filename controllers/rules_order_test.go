@@ -0,0 +1,161 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v8/pkg/papi"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+)
+
+func TestValidateCriteriaMustSatisfy(t *testing.T) {
+	child := func(raw string) runtime.RawExtension { return runtime.RawExtension{Raw: []byte(raw)} }
+	tests := []struct {
+		name  string
+		rules *akamaiV1alpha1.PropertyRules
+		err   string
+	}{
+		{name: "default", rules: &akamaiV1alpha1.PropertyRules{Name: "default"}},
+		{name: "children of any level", rules: &akamaiV1alpha1.PropertyRules{Name: "default", CriteriaMustSatisfy: akamaiV1alpha1.CriteriaMustSatisfyAll,
+			Children: []runtime.RawExtension{child(`{"name":"Static","criteriaMustSatisfy":"any","children":[{"name":"Images","criteriaMustSatisfy":"all"}]}`)}}},
+		{name: "invalid top-level value", rules: &akamaiV1alpha1.PropertyRules{Name: "default", CriteriaMustSatisfy: "one"},
+			err: "criteriaMustSatisfy must be 'all' or 'any', got 'one'"},
+		{name: "invalid nested value", rules: &akamaiV1alpha1.PropertyRules{Name: "default",
+			Children: []runtime.RawExtension{child(`{"name":"Static"}`), child(`{"name":"API","children":[{"name":"Writes","criteriaMustSatisfy":"ALL"}]}`)}},
+			err: `invalid child rule "API" at index 1: invalid child rule "Writes" at index 0: criteriaMustSatisfy must be 'all' or 'any'`},
+		{name: "child without name", rules: &akamaiV1alpha1.PropertyRules{Name: "default", Children: []runtime.RawExtension{child(`{"criteriaMustSatisfy":"any"}`)}},
+			err: "rule must have a name"},
+	}
+
+	r := &AkamaiPropertyReconciler{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := r.validatePropertyRules(tt.rules)
+			if tt.err == "" && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.err != "" && (err == nil || !strings.Contains(err.Error(), tt.err)) {
+				t.Fatalf("expected error containing %q, got %v", tt.err, err)
+			}
+		})
+	}
+}
+
+// childNames returns the names of the child rules of a rule tree in JSON
+func childNames(t *testing.T, rules interface{}) []string {
+	t.Helper()
+	raw, err := json.Marshal(rules)
+	if err != nil {
+		t.Fatalf("failed to marshal rules: %v", err)
+	}
+	var tree struct {
+		Children []struct {
+			Name string `json:"name"`
+		} `json:"children"`
+	}
+	if err := json.Unmarshal(raw, &tree); err != nil {
+		t.Fatalf("failed to unmarshal rules: %v", err)
+	}
+	var names []string
+	for _, child := range tree.Children {
+		names = append(names, child.Name)
+	}
+	return names
+}
+
+func TestRuleOrderPreserved(t *testing.T) {
+	// Child rules as written in the spec, with unusual key order and spacing
+	specChildren := []string{
+		`{"name":"Static","criteriaMustSatisfy":"any","criteria":[{"name":"fileExtension","options":{"values":["css","js"]}},{"name":"path","options":{"values":["/static/*"]}}],"behaviors":[{"name":"caching","options":{"behavior":"MAX_AGE","ttl":"7d"}}]}`,
+		`{"behaviors":[],  "name":"API","children":[{"name":"Writes"},{"name":"Reads"}]}`,
+		`{"name":"Static Overrides","behaviors":[{"name":"caching","options":{"behavior":"NO_STORE"}}]}`,
+	}
+	rules := &akamaiV1alpha1.PropertyRules{Name: "default"}
+	for _, child := range specChildren {
+		rules.Children = append(rules.Children, runtime.RawExtension{Raw: []byte(child)})
+	}
+	property := &akamaiV1alpha1.AkamaiProperty{Spec: akamaiV1alpha1.AkamaiPropertySpec{
+		Rules:     rules,
+		Variables: []akamaiV1alpha1.PropertyVariable{{Name: "PMUSER_ENV", Value: "prod"}},
+		Origins: &akamaiV1alpha1.OriginsSpec{Origins: []akamaiV1alpha1.WeightedOrigin{
+			{Name: "a", Weight: 1, OriginServer: akamaiV1alpha1.OriginServer{Hostname: "a.example.com"}},
+		}},
+		Redirect: &akamaiV1alpha1.RedirectSpec{HTTPToHTTPS: true},
+	}}
+	r := newFakeReconciler(t)
+	r.InjectRuleComments = true
+
+	// Render: managed rules go first, the spec's children follow byte-for-byte in their order
+	desired, _, err := r.desiredRules(context.Background(), property)
+	if err != nil {
+		t.Fatalf("desiredRules() unexpected error: %v", err)
+	}
+	expectedOrder := []string{originsRuleName, redirectRuleName, "Static", "API", "Static Overrides"}
+	if got := childNames(t, desired); !reflect.DeepEqual(got, expectedOrder) {
+		t.Fatalf("rendered children = %v, expected %v", got, expectedOrder)
+	}
+	for i, child := range specChildren {
+		if got := string(desired.Children[i+2].Raw); got != child {
+			t.Errorf("child %d changed while rendering:\n%s\nexpected\n%s", i, got, child)
+		}
+	}
+
+	// Apply: the request sent to PAPI keeps the order and criteriaMustSatisfy of every level
+	converted, err := convertRulesToAkamaiFormat(desired)
+	if err != nil {
+		t.Fatalf("convertRulesToAkamaiFormat() unexpected error: %v", err)
+	}
+	raw, _ := json.Marshal(converted)
+	var applied papi.Rules
+	if err := json.Unmarshal(raw, &applied); err != nil {
+		t.Fatalf("failed to unmarshal the PAPI rules: %v", err)
+	}
+	if got := childNames(t, applied); !reflect.DeepEqual(got, expectedOrder) {
+		t.Fatalf("applied children = %v, expected %v", got, expectedOrder)
+	}
+	static, api := applied.Children[2], applied.Children[3]
+	if static.CriteriaMustSatisfy != papi.RuleCriteriaMustSatisfyAny || static.Criteria[0].Name != "fileExtension" || static.Criteria[1].Name != "path" {
+		t.Errorf("unexpected criteria of the static rule %+v", static)
+	}
+	if len(api.Children) != 2 || api.Children[0].Name != "Writes" || api.Children[1].Name != "Reads" {
+		t.Errorf("unexpected grandchildren %+v", api.Children)
+	}
+
+	// Compare: the applied rules match, while any reordering is an update
+	if differ, err := r.rulesNeedUpdate(desired, applied, nil); err != nil || differ {
+		t.Fatalf("expected the applied rules to match, got %v, %v", differ, err)
+	}
+	reorderedChildren := applied
+	reorderedChildren.Children = slices.Clone(applied.Children)
+	reorderedChildren.Children[2], reorderedChildren.Children[4] = reorderedChildren.Children[4], reorderedChildren.Children[2]
+	reorderedGrandchildren := applied
+	reorderedGrandchildren.Children = slices.Clone(applied.Children)
+	reorderedGrandchildren.Children[3].Children = []papi.Rules{api.Children[1], api.Children[0]}
+	reorderedCriteria := applied
+	reorderedCriteria.Children = slices.Clone(applied.Children)
+	reorderedCriteria.Children[2].Criteria = []papi.RuleBehavior{static.Criteria[1], static.Criteria[0]}
+	anyToAll := applied
+	anyToAll.Children = slices.Clone(applied.Children)
+	anyToAll.Children[2].CriteriaMustSatisfy = papi.RuleCriteriaMustSatisfyAll
+
+	for name, current := range map[string]papi.Rules{
+		"children":      reorderedChildren,
+		"grandchildren": reorderedGrandchildren,
+		"criteria":      reorderedCriteria,
+		"any to all":    anyToAll,
+	} {
+		if differ, err := r.rulesNeedUpdate(desired, current, nil); err != nil || !differ {
+			t.Errorf("%s: expected an update, got %v, %v", name, differ, err)
+		}
+	}
+	changes, err := r.rulesDiff(desired, reorderedChildren)
+	if err != nil || !slices.Contains(changes, `Reorder the child rules of rule "default"`) {
+		t.Errorf("rulesDiff() = %v, %v", changes, err)
+	}
+}
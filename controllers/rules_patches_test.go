@@ -0,0 +1,153 @@
+package controllers
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/runtime"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+)
+
+// baseTemplate is a centrally managed rule tree as loaded from rulesFrom
+const baseTemplate = `{
+	"name": "default",
+	"behaviors": [
+		{"name": "origin", "options": {"hostname": "origin.example.com", "forwardHostHeader": "REQUEST_HOST_HEADER"}},
+		{"name": "cpCode", "options": {"value": {"id": 1}}}
+	],
+	"children": [
+		{"name": "Static", "behaviors": [{"name": "caching", "options": {"behavior": "MAX_AGE", "ttl": "1d"}}]},
+		{"name": "Legacy", "behaviors": [{"name": "denyAccess", "options": {"enabled": true}}]}
+	]
+}`
+
+func rulesPatch(patchType akamaiV1alpha1.RulesPatchType, patch string) akamaiV1alpha1.RulesPatch {
+	return akamaiV1alpha1.RulesPatch{Type: patchType, Patch: runtime.RawExtension{Raw: []byte(patch)}}
+}
+
+func TestApplyRulesPatches(t *testing.T) {
+	tests := []struct {
+		name    string
+		patches []akamaiV1alpha1.RulesPatch
+		want    string
+		err     string
+	}{
+		{
+			name:    "JSON patch",
+			patches: []akamaiV1alpha1.RulesPatch{rulesPatch("", `[{"op": "replace", "path": "/behaviors/0/options/hostname", "value": "shop-origin.example.com"}]`)},
+			want:    `"hostname":"shop-origin.example.com"`,
+		},
+		{
+			name:    "JSON patch of a missing path",
+			patches: []akamaiV1alpha1.RulesPatch{rulesPatch(akamaiV1alpha1.RulesPatchTypeJSONPatch, `[{"op": "replace", "path": "/children/5/name", "value": "x"}]`)},
+			err:     "rulesPatches[0]",
+		},
+		{
+			name: "merge overriding origin and cpCode",
+			patches: []akamaiV1alpha1.RulesPatch{rulesPatch(akamaiV1alpha1.RulesPatchTypeMerge,
+				`{"behaviors": [{"name": "origin", "options": {"hostname": "shop-origin.example.com"}}, {"name": "cpCode", "options": {"value": {"id": 2}}}]}`)},
+			want: `{"name":"default","behaviors":[{"name":"origin","options":{"forwardHostHeader":"REQUEST_HOST_HEADER","hostname":"shop-origin.example.com"}},{"name":"cpCode","options":{"value":{"id":2}}}]`,
+		},
+		{
+			name: "merge into a child rule",
+			patches: []akamaiV1alpha1.RulesPatch{rulesPatch(akamaiV1alpha1.RulesPatchTypeMerge,
+				`{"children": [{"name": "Static", "behaviors": [{"name": "caching", "options": {"ttl": "7d"}}]}]}`)},
+			want: `{"behaviors":[{"name":"caching","options":{"behavior":"MAX_AGE","ttl":"7d"}}],"name":"Static"}`,
+		},
+		{
+			name: "merge deleting and appending child rules",
+			patches: []akamaiV1alpha1.RulesPatch{rulesPatch(akamaiV1alpha1.RulesPatchTypeMerge,
+				`{"children": [{"name": "Legacy", "$patch": "delete"}, {"name": "API", "behaviors": [{"name": "caching", "options": {"behavior": "NO_STORE"}}]}]}`)},
+			want: `"children":[{"behaviors":[{"name":"caching","options":{"behavior":"MAX_AGE","ttl":"1d"}}],"name":"Static"},{"behaviors":[{"name":"caching","options":{"behavior":"NO_STORE"}}],"name":"API"}]`,
+		},
+		{
+			name:    "merge deleting a missing rule",
+			patches: []akamaiV1alpha1.RulesPatch{rulesPatch(akamaiV1alpha1.RulesPatchTypeMerge, `{"children": [{"name": "Missing", "$patch": "delete"}]}`)},
+			err:     `"Missing" to delete not found`,
+		},
+		{
+			name:    "merge of an item without name",
+			patches: []akamaiV1alpha1.RulesPatch{rulesPatch(akamaiV1alpha1.RulesPatchTypeMerge, `{"behaviors": [{"options": {}}]}`)},
+			err:     "items must have a name",
+		},
+		{
+			name: "patches applied in order",
+			patches: []akamaiV1alpha1.RulesPatch{
+				rulesPatch(akamaiV1alpha1.RulesPatchTypeMerge, `{"behaviors": [{"name": "origin", "options": {"hostname": "first.example.com"}}]}`),
+				rulesPatch(akamaiV1alpha1.RulesPatchTypeJSONPatch, `[{"op": "replace", "path": "/behaviors/0/options/hostname", "value": "second.example.com"}]`),
+			},
+			want: `"hostname":"second.example.com"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var base akamaiV1alpha1.PropertyRules
+			if err := json.Unmarshal([]byte(baseTemplate), &base); err != nil {
+				t.Fatalf("failed to parse base template: %v", err)
+			}
+			patched, err := applyRulesPatches(&base, tt.patches)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("expected error containing %q, got %v", tt.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("applyRulesPatches() unexpected error: %v", err)
+			}
+			raw, err := json.Marshal(patched)
+			if err != nil {
+				t.Fatalf("failed to marshal patched rules: %v", err)
+			}
+			if !strings.Contains(string(raw), tt.want) {
+				t.Errorf("patched rules %s do not contain %s", raw, tt.want)
+			}
+		})
+	}
+}
+
+func TestMergeRejectsAmbiguousNames(t *testing.T) {
+	rules := &akamaiV1alpha1.PropertyRules{Name: "default", Behaviors: []akamaiV1alpha1.RuleBehavior{
+		{Name: "modifyOutgoingResponseHeader"}, {Name: "modifyOutgoingResponseHeader"},
+	}}
+	_, err := applyRulesPatches(rules, []akamaiV1alpha1.RulesPatch{rulesPatch(akamaiV1alpha1.RulesPatchTypeMerge,
+		`{"behaviors": [{"name": "modifyOutgoingResponseHeader", "options": {"action": "DELETE"}}]}`)})
+	if err == nil || !strings.Contains(err.Error(), "matches several items") {
+		t.Fatalf("expected an ambiguous name error, got %v", err)
+	}
+}
+
+func TestValidateRulesPatches(t *testing.T) {
+	rulesFrom := &akamaiV1alpha1.RulesSource{URL: "https://rules.example.com/base.json"}
+	tests := []struct {
+		name      string
+		rulesFrom *akamaiV1alpha1.RulesSource
+		patch     akamaiV1alpha1.RulesPatch
+		err       string
+	}{
+		{name: "JSON patch", rulesFrom: rulesFrom, patch: rulesPatch("", `[{"op": "remove", "path": "/children/1"}]`)},
+		{name: "merge", rulesFrom: rulesFrom, patch: rulesPatch(akamaiV1alpha1.RulesPatchTypeMerge, `{"comments": "shop"}`)},
+		{name: "without base template", patch: rulesPatch("", `[]`), err: "require rulesFrom or a renderer"},
+		{name: "empty patch", rulesFrom: rulesFrom, patch: akamaiV1alpha1.RulesPatch{}, err: "patch is required"},
+		{name: "invalid JSON patch", rulesFrom: rulesFrom, patch: rulesPatch("", `{"op": "remove"}`), err: "invalid JSON patch"},
+		{name: "merge of a list", rulesFrom: rulesFrom, patch: rulesPatch(akamaiV1alpha1.RulesPatchTypeMerge, `[]`), err: "must be a rule"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			property := &akamaiV1alpha1.AkamaiProperty{Spec: akamaiV1alpha1.AkamaiPropertySpec{
+				RulesFrom:    tt.rulesFrom,
+				RulesPatches: []akamaiV1alpha1.RulesPatch{tt.patch},
+			}}
+			err := validateRulesPatches(property)
+			if tt.err == "" && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.err != "" && (err == nil || !strings.Contains(err.Error(), tt.err)) {
+				t.Fatalf("expected error containing %q, got %v", tt.err, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package controllers
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+	"github.com/mmz-srf/akamai-operator/pkg/akamai"
+)
+
+func TestRulesSchemaCondition(t *testing.T) {
+	schema, err := akamai.ParseRuleSchema([]byte(`{"definitions": {"catalog": {
+		"behaviors": {"caching": {"type": "object", "properties": {
+			"name": {"type": "string"},
+			"options": {"type": "object", "additionalProperties": false, "properties": {"behavior": {"enum": ["MAX_AGE", "NO_STORE"]}}}
+		}}},
+		"criteria": {}
+	}}}`))
+	if err != nil {
+		t.Fatalf("failed to parse schema: %v", err)
+	}
+	rules := &akamaiV1alpha1.PropertyRules{
+		Name: "default",
+		Behaviors: []akamaiV1alpha1.RuleBehavior{
+			{Name: "caching", Options: runtime.RawExtension{Raw: []byte(`{"behavior":"FOREVER","ttl":"1d"}`)}},
+		},
+	}
+	violations, err := schema.ValidateRules(rules)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	condition := rulesSchemaCondition(3, "v2023-01-05", violations)
+	if condition.Status != metav1.ConditionFalse || condition.Reason != "SchemaViolations" || condition.ObservedGeneration != 3 {
+		t.Fatalf("unexpected condition %+v", condition)
+	}
+	expected := `#/rules/behaviors/0/options/behavior: "FOREVER" is not one of ["MAX_AGE", "NO_STORE"]; #/rules/behaviors/0/options/ttl: unknown option`
+	if condition.Message != expected {
+		t.Errorf("message = %q, expected %q", condition.Message, expected)
+	}
+
+	if condition := rulesSchemaCondition(3, "v2023-01-05", nil); condition.Status != metav1.ConditionTrue || condition.Reason != "SchemaValid" {
+		t.Errorf("unexpected condition of valid rules %+v", condition)
+	}
+
+	// Long lists of violations are cut short
+	many := make([]akamai.SchemaViolation, maxReportedSchemaViolations+5)
+	for i := range many {
+		many[i] = akamai.SchemaViolation{Path: fmt.Sprintf("#/rules/behaviors/%d", i), Message: `unknown behavior "gzip"`}
+	}
+	condition = rulesSchemaCondition(1, "latest", many)
+	if !strings.HasSuffix(condition.Message, "; and 5 more") || strings.Count(condition.Message, "unknown behavior") != maxReportedSchemaViolations {
+		t.Errorf("unexpected message %q", condition.Message)
+	}
+}
+
+func TestCheckRulesSchemaDisabled(t *testing.T) {
+	r := newFakeReconciler(t)
+	property := &akamaiV1alpha1.AkamaiProperty{Spec: akamaiV1alpha1.AkamaiPropertySpec{ProductID: "prd_Fresca", Rules: &akamaiV1alpha1.PropertyRules{Name: "default"}}}
+	if err := r.checkRulesSchema(t.Context(), property, property.Spec.Rules); err != nil {
+		t.Errorf("expected no schema check without ValidateRuleSchemas, got %v", err)
+	}
+}
@@ -0,0 +1,143 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v8/pkg/papi"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+	"github.com/mmz-srf/akamai-operator/pkg/akamai"
+)
+
+// versionsPAPI stubs the PAPI version and rule tree endpoints, recording the calls. Versions are
+// created from the edited rules when set, as if the base version was edited in the meantime.
+type versionsPAPI struct {
+	papi.PAPI
+	rules    map[int]papi.Rules
+	edited   *papi.Rules
+	staging  map[int]papi.VersionStatus
+	calls    []string
+	received papi.UpdateRulesRequest
+}
+
+func (s *versionsPAPI) GetPropertyVersion(_ context.Context, request papi.GetPropertyVersionRequest) (*papi.GetPropertyVersionsResponse, error) {
+	status := s.staging[request.PropertyVersion]
+	if status == "" {
+		status = papi.VersionStatusInactive
+	}
+	return &papi.GetPropertyVersionsResponse{Version: papi.PropertyVersionGetItem{
+		PropertyVersion:  request.PropertyVersion,
+		StagingStatus:    status,
+		ProductionStatus: papi.VersionStatusInactive,
+	}}, nil
+}
+
+func (s *versionsPAPI) CreatePropertyVersion(_ context.Context, request papi.CreatePropertyVersionRequest) (*papi.CreatePropertyVersionResponse, error) {
+	from := request.Version.CreateFromVersion
+	s.calls = append(s.calls, fmt.Sprintf("create version from %d", from))
+	version := len(s.rules) + 1
+	s.rules[version] = s.rules[from]
+	if s.edited != nil {
+		s.rules[version] = *s.edited
+	}
+	return &papi.CreatePropertyVersionResponse{VersionLink: fmt.Sprintf("/papi/v1/properties/prp_1/versions/%d?contractId=ctr_1&groupId=grp_1", version)}, nil
+}
+
+func (s *versionsPAPI) GetRuleTree(_ context.Context, request papi.GetRuleTreeRequest) (*papi.GetRuleTreeResponse, error) {
+	s.calls = append(s.calls, fmt.Sprintf("get rules %d", request.PropertyVersion))
+	return &papi.GetRuleTreeResponse{
+		PropertyVersion: request.PropertyVersion,
+		Etag:            fmt.Sprintf("etag-%d", request.PropertyVersion),
+		Rules:           s.rules[request.PropertyVersion],
+	}, nil
+}
+
+func (s *versionsPAPI) UpdateRuleTree(_ context.Context, request papi.UpdateRulesRequest) (*papi.UpdateRulesResponse, error) {
+	s.calls = append(s.calls, fmt.Sprintf("update rules %d", request.PropertyVersion))
+	s.received = request
+	return &papi.UpdateRulesResponse{PropertyVersion: request.PropertyVersion, Rules: request.Rules.Rules}, nil
+}
+
+func TestUpdateRulesRereadsNewVersion(t *testing.T) {
+	siteShield := func(ssmap string) papi.RuleBehavior {
+		return papi.RuleBehavior{Name: "siteShield", Options: papi.RuleOptionsMap{"ssmap": ssmap}}
+	}
+	origin := papi.RuleBehavior{Name: "origin", Options: papi.RuleOptionsMap{"hostname": "old.example.com"}}
+	// The Site Shield map of version 1 is changed in Control Center after the operator read it
+	stub := &versionsPAPI{
+		rules:   map[int]papi.Rules{1: {Name: "default", Behaviors: []papi.RuleBehavior{origin, siteShield("ss.akamai.net")}}},
+		edited:  &papi.Rules{Name: "default", Behaviors: []papi.RuleBehavior{origin, siteShield("s2.akamai.net")}},
+		staging: map[int]papi.VersionStatus{1: papi.VersionStatusActive},
+	}
+	property := &akamaiV1alpha1.AkamaiProperty{
+		ObjectMeta: metav1.ObjectMeta{Name: "example", Generation: 1},
+		Spec: akamaiV1alpha1.AkamaiPropertySpec{
+			PropertyName:         "example.com",
+			ContractID:           "ctr_1",
+			GroupID:              "grp_1",
+			ForeignVersionPolicy: akamaiV1alpha1.ForeignVersionPolicyOverwrite,
+			PreserveBehaviors:    []string{"siteShield"},
+			Rules: &akamaiV1alpha1.PropertyRules{
+				Name: "default",
+				Behaviors: []akamaiV1alpha1.RuleBehavior{
+					{Name: "origin", Options: runtime.RawExtension{Raw: []byte(`{"hostname":"new.example.com"}`)}},
+				},
+			},
+		},
+		Status: akamaiV1alpha1.AkamaiPropertyStatus{PropertyID: "prp_1", LatestVersion: 1},
+	}
+	r := newFakeReconciler(t, property)
+	r.AkamaiClient = akamai.NewClientWithPAPI(stub)
+
+	// Version 1 is active on staging, so the rules are written to a new version, which is read
+	// again for its own etag and preserved behaviors
+	updated, err := r.updateRulesIfNeeded(context.Background(), property, nil)
+	if err != nil {
+		t.Fatalf("updateRulesIfNeeded() error = %v", err)
+	}
+	if !updated || property.Status.LatestVersion != 2 {
+		t.Fatalf("updateRulesIfNeeded() = %v, latest version %d, expected the rules of a new version 2 to be updated", updated, property.Status.LatestVersion)
+	}
+	expected := []string{"get rules 1", "create version from 1", "get rules 2", "update rules 2"}
+	if !slices.Equal(stub.calls, expected) {
+		t.Errorf("calls = %q, expected %q", stub.calls, expected)
+	}
+	behaviors := stub.received.Rules.Rules.Behaviors
+	if len(behaviors) != 2 || behaviors[1].Name != "siteShield" || behaviors[1].Options["ssmap"] != "s2.akamai.net" {
+		t.Errorf("written behaviors = %+v, expected the Site Shield map of version 2 to be preserved", behaviors)
+	}
+}
+
+func TestUpdateRulesValidatesDesiredRules(t *testing.T) {
+	stub := &versionsPAPI{rules: map[int]papi.Rules{1: {Name: "default"}}}
+	// Inline rules are checked again before they are written, not only by checkSpecValidity
+	property := &akamaiV1alpha1.AkamaiProperty{
+		ObjectMeta: metav1.ObjectMeta{Name: "example", Generation: 1},
+		Spec: akamaiV1alpha1.AkamaiPropertySpec{
+			PropertyName: "example.com",
+			ContractID:   "ctr_1",
+			GroupID:      "grp_1",
+			Rules: &akamaiV1alpha1.PropertyRules{
+				Name:                "default",
+				CriteriaMustSatisfy: "some",
+			},
+		},
+		Status: akamaiV1alpha1.AkamaiPropertyStatus{PropertyID: "prp_1", LatestVersion: 1},
+	}
+	r := newFakeReconciler(t, property)
+	r.AkamaiClient = akamai.NewClientWithPAPI(stub)
+
+	_, err := r.updateRulesIfNeeded(context.Background(), property, nil)
+	if err == nil || !strings.Contains(err.Error(), "desired rules are invalid") {
+		t.Fatalf("updateRulesIfNeeded() error = %v, expected the inline rules to be validated", err)
+	}
+	if slices.Contains(stub.calls, "update rules 1") {
+		t.Errorf("calls = %q, expected the invalid rules not to be written", stub.calls)
+	}
+}
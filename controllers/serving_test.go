@@ -0,0 +1,84 @@
+package controllers
+
+import (
+	"reflect"
+	"testing"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+	"github.com/mmz-srf/akamai-operator/pkg/akamai"
+)
+
+func TestServingSummary(t *testing.T) {
+	desired := []akamaiV1alpha1.Hostname{
+		{CNAMEFrom: "www.example.com", CNAMETo: "www.example.com.edgesuite.net"},
+		{CNAMEFrom: "api.example.com", CNAMETo: "api.example.com.edgesuite.net"},
+		{CNAMEFrom: "cdn.example.com", CNAMETo: "cdn.example.com.edgesuite.net"},
+		{CNAMEFrom: "new.example.com", CNAMETo: "new.example.com.edgesuite.net"},
+	}
+
+	tests := []struct {
+		name       string
+		production []akamai.Hostname
+		expected   string
+	}{
+		{
+			name:     "nothing on production",
+			expected: "0/4",
+		},
+		{
+			name: "mixed certificate and target states",
+			production: []akamai.Hostname{
+				{CNAMEFrom: "www.example.com", CNAMETo: "www.example.com.edgesuite.net", ProductionCertStatus: akamai.CertStatusDeployed},
+				{CNAMEFrom: "api.example.com", CNAMETo: "api.example.com.edgesuite.net"},
+				{CNAMEFrom: "cdn.example.com", CNAMETo: "cdn.example.com.edgesuite.net", ProductionCertStatus: "PENDING"},
+				{CNAMEFrom: "new.example.com", CNAMETo: "old.example.com.edgesuite.net"},
+				{CNAMEFrom: "unmanaged.example.com", CNAMETo: "unmanaged.example.com.edgesuite.net"},
+			},
+			expected: "2/4",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := servingSummary(desired, tt.production); got != tt.expected {
+				t.Errorf("servingSummary() = %q, expected %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestHostnameCertificates(t *testing.T) {
+	desired := []akamaiV1alpha1.Hostname{
+		{CNAMEFrom: "www.example.com", CNAMETo: "www.example.com.edgekey.net", CertProvisioningType: "DEFAULT"},
+		{CNAMEFrom: "shop.example.com", CNAMETo: "www.example.com.edgekey.net", CertProvisioningType: "DEFAULT"},
+		{CNAMEFrom: "api.example.com", CNAMETo: "www.example.com.edgekey.net", CertProvisioningType: "CPS_MANAGED"},
+		{CNAMEFrom: "new.example.com", CNAMETo: "www.example.com.edgekey.net", CertProvisioningType: "DEFAULT"},
+	}
+	current := []akamai.Hostname{
+		{CNAMEFrom: "www.example.com", StagingCertStatus: "PENDING", ProductionCertStatus: "PENDING",
+			ValidationCNAME: "_acme-challenge.www.example.com", ValidationTarget: "www.example.com.validate-akamai.com"},
+		{CNAMEFrom: "shop.example.com", StagingCertStatus: "DEPLOYED", ProductionCertStatus: "DEPLOYED",
+			ValidationCNAME: "_acme-challenge.shop.example.com", ValidationTarget: "shop.example.com.validate-akamai.com"},
+		{CNAMEFrom: "api.example.com"},
+	}
+
+	certificates := hostnameCertificates(desired, current)
+	expected := []akamaiV1alpha1.HostnameCertificate{
+		{Hostname: "www.example.com", StagingStatus: "PENDING", ProductionStatus: "PENDING", ValidationRecords: []akamaiV1alpha1.DNSRecord{
+			{Type: "CNAME", Name: "_acme-challenge.www.example.com", Value: "www.example.com.validate-akamai.com"},
+		}},
+		{Hostname: "shop.example.com", StagingStatus: "DEPLOYED", ProductionStatus: "DEPLOYED"},
+	}
+	if !reflect.DeepEqual(certificates, expected) {
+		t.Errorf("hostnameCertificates() = %+v, expected %+v", certificates, expected)
+	}
+
+	property := &akamaiV1alpha1.AkamaiProperty{Status: akamaiV1alpha1.AkamaiPropertyStatus{HostnameCertificates: certificates}}
+	if !certificatesAwaitingValidation(property) {
+		t.Error("expected the certificate of www.example.com to await validation")
+	}
+	property.Status.HostnameCertificates = certificates[1:]
+	if certificatesAwaitingValidation(property) {
+		t.Error("expected no certificate to await validation")
+	}
+}
@@ -0,0 +1,169 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+)
+
+// Shard is the part of the property fleet an operator instance manages. Several operator
+// deployments, each with its own credentials and request rate, split a large fleet by label or
+// by contract without reconciling the same resources. A nil shard manages everything.
+type Shard struct {
+	// Name distinguishes the leader election of the shard from the other shards
+	Name string
+
+	// Selector selects the labels of the managed resources; nil selects all
+	Selector labels.Selector
+
+	// Contracts lists the managed contracts; empty manages all contracts
+	Contracts []string
+}
+
+// NewShard parses the shard flags. It returns nil when no shard is configured.
+func NewShard(name, selector string, contracts []string) (*Shard, error) {
+	if name == "" {
+		if selector != "" || len(contracts) > 0 {
+			return nil, fmt.Errorf("a shard selector or contracts need a shard name")
+		}
+		return nil, nil
+	}
+	if errs := validation.IsDNS1123Label(name); len(errs) > 0 {
+		return nil, fmt.Errorf("invalid shard name %q: %s", name, strings.Join(errs, ", "))
+	}
+	shard := &Shard{Name: name}
+	if selector != "" {
+		parsed, err := labels.Parse(selector)
+		if err != nil {
+			return nil, fmt.Errorf("invalid shard selector %q: %w", selector, err)
+		}
+		shard.Selector = parsed
+	}
+	for _, contract := range contracts {
+		shard.Contracts = append(shard.Contracts, strings.TrimPrefix(contract, "ctr_"))
+	}
+	if shard.Selector == nil && len(shard.Contracts) == 0 {
+		return nil, fmt.Errorf("shard %q needs a selector or contracts", name)
+	}
+	return shard, nil
+}
+
+// LeaderElectionID returns the leader election ID of the shard, so each shard elects its own
+// leader
+func (s *Shard) LeaderElectionID(id string) string {
+	if s == nil {
+		return id
+	}
+	return s.Name + "." + id
+}
+
+// CacheOptions restricts the informers of the managed resources to the labels of the shard, so
+// an instance neither caches nor watches the resources of the other shards
+func (s *Shard) CacheOptions() cache.Options {
+	if s == nil || s.Selector == nil {
+		return cache.Options{}
+	}
+	byObject := cache.ByObject{Label: s.Selector}
+	return cache.Options{ByObject: map[client.Object]cache.ByObject{
+		&akamaiV1alpha1.AkamaiProperty{}:           byObject,
+		&akamaiV1alpha1.AkamaiPropertyInclude{}:    byObject,
+		&akamaiV1alpha1.AkamaiEdgeHostname{}:       byObject,
+		&akamaiV1alpha1.AkamaiDnsZone{}:            byObject,
+		&akamaiV1alpha1.AkamaiDnsRecord{}:          byObject,
+		&akamaiV1alpha1.AkamaiNetworkList{}:        byObject,
+		&akamaiV1alpha1.AkamaiAppSecConfig{}:       byObject,
+		&akamaiV1alpha1.AkamaiGtmDomain{}:          byObject,
+		&akamaiV1alpha1.AkamaiEdgeKVNamespace{}:    byObject,
+		&akamaiV1alpha1.AkamaiCloudletPolicy{}:     byObject,
+		&akamaiV1alpha1.AkamaiPurge{}:              byObject,
+		&akamaiV1alpha1.AkamaiCertificate{}:        byObject,
+		&akamaiV1alpha1.AkamaiDataStream{}:         byObject,
+		&akamaiV1alpha1.AkamaiClientList{}:         byObject,
+		&akamaiV1alpha1.AkamaiRuleValidation{}:     byObject,
+		&akamaiV1alpha1.AkamaiPropertyActivation{}: byObject,
+	}}
+}
+
+// Contains reports whether a resource with the given labels and contract belongs to the shard.
+// The cache already filters by labels; the check covers objects read without it.
+func (s *Shard) Contains(objectLabels map[string]string, contractID string) bool {
+	if s == nil {
+		return true
+	}
+	if s.Selector != nil && !s.Selector.Matches(labels.Set(objectLabels)) {
+		return false
+	}
+	if len(s.Contracts) == 0 {
+		return true
+	}
+	contractID = strings.TrimPrefix(contractID, "ctr_")
+	for _, contract := range s.Contracts {
+		if contract == contractID {
+			return true
+		}
+	}
+	return false
+}
+
+// containsAccountWide reports whether a resource that belongs to no contract, like an EdgeKV
+// namespace, belongs to the shard. Only the selector can assign it; shards selecting by contract
+// alone would all claim it, so they leave it alone.
+func (s *Shard) containsAccountWide(objectLabels map[string]string) bool {
+	if s == nil {
+		return true
+	}
+	return s.Selector != nil && s.Selector.Matches(labels.Set(objectLabels))
+}
+
+// containsProperty reports whether a property belongs to the shard. The contract is the one the
+// property is reconciled with, including the default of its provider config.
+func (s *Shard) containsProperty(ctx context.Context, reader client.Reader, akamaiProperty *akamaiV1alpha1.AkamaiProperty) (bool, error) {
+	return s.containsAccount(ctx, reader, akamaiProperty.Labels, propertyAccount(akamaiProperty))
+}
+
+// containsAccount reports whether a resource of an account belongs to the shard by its labels
+// and its contract, including the default of its provider config
+func (s *Shard) containsAccount(ctx context.Context, reader client.Reader, objectLabels map[string]string, account akamaiAccount) (bool, error) {
+	if s == nil {
+		return true, nil
+	}
+	contractID := *account.contractID
+	if contractID == "" && len(s.Contracts) > 0 {
+		providerConfig, err := providerConfigFor(ctx, reader, account)
+		if err != nil {
+			return false, err
+		}
+		if providerConfig != nil {
+			contractID = providerConfig.Spec.DefaultContractID
+		}
+	}
+	return s.Contains(objectLabels, contractID), nil
+}
+
+// containsDNSRecord reports whether a record set belongs to the shard. Record sets have no
+// contract of their own; they follow the AkamaiDnsZone managing their zone.
+func (s *Shard) containsDNSRecord(ctx context.Context, reader client.Reader, record *akamaiV1alpha1.AkamaiDnsRecord) (bool, error) {
+	if s == nil {
+		return true, nil
+	}
+	contractID := ""
+	if len(s.Contracts) > 0 {
+		var zones akamaiV1alpha1.AkamaiDnsZoneList
+		if err := reader.List(ctx, &zones); err != nil {
+			return false, fmt.Errorf("failed to list AkamaiDnsZones: %w", err)
+		}
+		for _, zone := range zones.Items {
+			if dnsZoneName(&zone) == record.Spec.Zone {
+				contractID = zone.Spec.ContractID
+			}
+		}
+	}
+	return s.Contains(record.Labels, contractID), nil
+}
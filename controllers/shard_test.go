@@ -0,0 +1,123 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+)
+
+func TestNewShard(t *testing.T) {
+	tests := []struct {
+		name      string
+		shard     string
+		selector  string
+		contracts []string
+		wantNil   bool
+		wantErr   bool
+	}{
+		{name: "no shard", wantNil: true},
+		{name: "selector", shard: "news", selector: "akamai.com/shard=news"},
+		{name: "contracts", shard: "news", contracts: []string{"ctr_1-ABC"}},
+		{name: "selector without name", selector: "akamai.com/shard=news", wantErr: true},
+		{name: "name without selector or contracts", shard: "news", wantErr: true},
+		{name: "invalid name", shard: "News_1", contracts: []string{"1-ABC"}, wantErr: true},
+		{name: "invalid selector", shard: "news", selector: "a in (", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			shard, err := NewShard(tt.shard, tt.selector, tt.contracts)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewShard() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && (shard == nil) != tt.wantNil {
+				t.Errorf("NewShard() = %+v, wantNil %v", shard, tt.wantNil)
+			}
+		})
+	}
+}
+
+func TestShardContains(t *testing.T) {
+	shard, err := NewShard("news", "akamai.com/shard=news", []string{"ctr_1-ABC"})
+	if err != nil {
+		t.Fatalf("NewShard() unexpected error: %v", err)
+	}
+	tests := []struct {
+		name     string
+		labels   map[string]string
+		contract string
+		want     bool
+	}{
+		{name: "matching labels and contract", labels: map[string]string{"akamai.com/shard": "news"}, contract: "ctr_1-ABC", want: true},
+		{name: "contract without prefix", labels: map[string]string{"akamai.com/shard": "news"}, contract: "1-ABC", want: true},
+		{name: "other contract", labels: map[string]string{"akamai.com/shard": "news"}, contract: "ctr_2-DEF"},
+		{name: "other labels", labels: map[string]string{"akamai.com/shard": "sport"}, contract: "ctr_1-ABC"},
+		{name: "no labels", contract: "ctr_1-ABC"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := shard.Contains(tt.labels, tt.contract); got != tt.want {
+				t.Errorf("Contains() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	var all *Shard
+	if !all.Contains(nil, "ctr_2-DEF") {
+		t.Error("expected a nil shard to contain everything")
+	}
+	if got := all.LeaderElectionID("akamai-operator.akamai.com"); got != "akamai-operator.akamai.com" {
+		t.Errorf("LeaderElectionID() = %q", got)
+	}
+	if got := shard.LeaderElectionID("akamai-operator.akamai.com"); got != "news.akamai-operator.akamai.com" {
+		t.Errorf("LeaderElectionID() = %q", got)
+	}
+}
+
+func TestShardContainsPropertyWithDefaultContract(t *testing.T) {
+	ctx := context.Background()
+	providerConfig := &akamaiV1alpha1.AkamaiProviderConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "news"},
+		Spec:       akamaiV1alpha1.AkamaiProviderConfigSpec{DefaultContractID: "ctr_1-ABC"},
+	}
+	property := &akamaiV1alpha1.AkamaiProperty{
+		ObjectMeta: metav1.ObjectMeta{Name: "example"},
+		Spec:       akamaiV1alpha1.AkamaiPropertySpec{PropertyName: "example.com", ProviderConfigRef: "news"},
+	}
+	r := newFakeReconciler(t, providerConfig, property)
+
+	news, _ := NewShard("news", "", []string{"ctr_1-ABC"})
+	if inShard, err := news.containsProperty(ctx, r.Client, property); err != nil || !inShard {
+		t.Errorf("containsProperty() = %v, %v, want the default contract to match", inShard, err)
+	}
+	sport, _ := NewShard("sport", "", []string{"ctr_2-DEF"})
+	if inShard, err := sport.containsProperty(ctx, r.Client, property); err != nil || inShard {
+		t.Errorf("containsProperty() = %v, %v, want the property in another shard", inShard, err)
+	}
+}
+
+func TestReconcileSkipsPropertiesOfOtherShards(t *testing.T) {
+	property := &akamaiV1alpha1.AkamaiProperty{
+		ObjectMeta: metav1.ObjectMeta{Name: "example", Generation: 1},
+		Spec:       akamaiV1alpha1.AkamaiPropertySpec{PropertyName: "example.com", ContractID: "ctr_2-DEF", GroupID: "grp_1"},
+	}
+	r := newFakeReconciler(t, property)
+	r.Shard, _ = NewShard("news", "", []string{"ctr_1-ABC"})
+
+	// Without an Akamai client the reconcile would fail if it touched the property
+	result, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: types.NamespacedName{Name: "example"}})
+	if err != nil || result.RequeueAfter != 0 {
+		t.Fatalf("Reconcile() = %+v, %v", result, err)
+	}
+	var got akamaiV1alpha1.AkamaiProperty
+	if err := r.Get(context.Background(), types.NamespacedName{Name: "example"}, &got); err != nil {
+		t.Fatalf("failed to get property: %v", err)
+	}
+	if len(got.Finalizers) != 0 || got.Status.Phase != "" {
+		t.Errorf("expected the property to be left alone, got finalizers %v and phase %q", got.Finalizers, got.Status.Phase)
+	}
+}
@@ -0,0 +1,75 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+)
+
+func newFakeReconciler(t *testing.T, objects ...client.Object) *AkamaiPropertyReconciler {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	if err := akamaiV1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to build scheme: %v", err)
+	}
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(objects...).
+		WithStatusSubresource(&akamaiV1alpha1.AkamaiProperty{}, &akamaiV1alpha1.AkamaiRuleValidation{}, &akamaiV1alpha1.AkamaiPropertyInclude{}, &akamaiV1alpha1.AkamaiEdgeHostname{}).
+		Build()
+	return &AkamaiPropertyReconciler{Client: fakeClient, Scheme: scheme}
+}
+
+func TestCheckSpecValidity(t *testing.T) {
+	ctx := context.Background()
+	property := &akamaiV1alpha1.AkamaiProperty{
+		ObjectMeta: metav1.ObjectMeta{Name: "example", Generation: 1},
+		Spec: akamaiV1alpha1.AkamaiPropertySpec{
+			PropertyName: "example.com",
+			Rules:        &akamaiV1alpha1.PropertyRules{Name: "not-default"},
+		},
+	}
+	r := newFakeReconciler(t, property)
+
+	valid, err := r.checkSpecValidity(ctx, property)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if valid {
+		t.Fatal("expected invalid spec")
+	}
+	condition := meta.FindStatusCondition(property.Status.Conditions, ConditionTypeInvalidSpec)
+	if condition == nil || condition.Status != metav1.ConditionTrue || condition.ObservedGeneration != 1 {
+		t.Fatalf("expected terminal InvalidSpec condition, got %+v", condition)
+	}
+	if property.Status.Phase != PhaseError {
+		t.Errorf("expected phase %s, got %s", PhaseError, property.Status.Phase)
+	}
+
+	// The same generation is skipped without validating again
+	valid, err = r.checkSpecValidity(ctx, property)
+	if err != nil || valid {
+		t.Fatalf("expected invalid generation to be skipped, got valid=%v err=%v", valid, err)
+	}
+
+	// A fixed spec in a new generation passes and is recorded
+	property.Generation = 2
+	property.Spec.Rules.Name = "default"
+	valid, err = r.checkSpecValidity(ctx, property)
+	if err != nil || !valid {
+		t.Fatalf("expected valid spec, got valid=%v err=%v", valid, err)
+	}
+	if property.Status.ValidatedGeneration != 2 {
+		t.Errorf("expected validated generation 2, got %d", property.Status.ValidatedGeneration)
+	}
+	if condition := meta.FindStatusCondition(property.Status.Conditions, ConditionTypeInvalidSpec); condition.Status != metav1.ConditionFalse {
+		t.Errorf("expected InvalidSpec to be cleared, got %+v", condition)
+	}
+}
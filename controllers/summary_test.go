@@ -0,0 +1,121 @@
+package controllers
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+)
+
+func TestSetSummaryCondition(t *testing.T) {
+	readyCondition := func(status metav1.ConditionStatus, reason, message string) metav1.Condition {
+		return metav1.Condition{Type: ConditionTypeReady, Status: status, Reason: reason, Message: message}
+	}
+	synced := akamaiV1alpha1.AkamaiPropertyStatus{
+		PropertyID:       "prp_1",
+		Phase:            PhaseReady,
+		Serving:          "1/1",
+		ManagedHostnames: []string{"www.example.com"},
+		Conditions:       []metav1.Condition{readyCondition(metav1.ConditionTrue, "PropertyIsReady", "")},
+	}
+
+	tests := []struct {
+		name            string
+		mutate          func(status *akamaiV1alpha1.AkamaiPropertyStatus)
+		expectedStatus  metav1.ConditionStatus
+		expectedReason  string
+		expectedMessage string
+	}{
+		{
+			name:            "in sync",
+			mutate:          func(status *akamaiV1alpha1.AkamaiPropertyStatus) {},
+			expectedStatus:  metav1.ConditionTrue,
+			expectedReason:  "Synced",
+			expectedMessage: "Property is in sync",
+		},
+		{
+			name: "rules update failed",
+			mutate: func(status *akamaiV1alpha1.AkamaiPropertyStatus) {
+				status.Phase = PhaseError
+				status.Conditions = []metav1.Condition{readyCondition(metav1.ConditionFalse, "FailedToUpdateRules", "rule tree rejected")}
+			},
+			expectedStatus:  metav1.ConditionFalse,
+			expectedReason:  "FailedToUpdateRules",
+			expectedMessage: "rule tree rejected",
+		},
+		{
+			name: "failed activation outranks pending certificates",
+			mutate: func(status *akamaiV1alpha1.AkamaiPropertyStatus) {
+				status.Serving = "0/1"
+				status.ProductionActivationStatus = "FAILED"
+			},
+			expectedStatus:  metav1.ConditionFalse,
+			expectedReason:  "ProductionActivationFailed",
+			expectedMessage: "Production activation finished with status FAILED; 0 of 1 hostnames are served on production with a ready certificate",
+		},
+		{
+			name: "activation in progress",
+			mutate: func(status *akamaiV1alpha1.AkamaiPropertyStatus) {
+				status.Phase = PhaseActivating
+				status.StagingActivationStatus = "PENDING"
+				status.Conditions = []metav1.Condition{readyCondition(metav1.ConditionFalse, "ActivationInProgress", "Activating version 2 on STAGING")}
+			},
+			expectedStatus:  metav1.ConditionFalse,
+			expectedReason:  "ActivationInProgress",
+			expectedMessage: "Activating version 2 on STAGING; Staging activation is PENDING",
+		},
+		{
+			name: "hostnames not synced",
+			mutate: func(status *akamaiV1alpha1.AkamaiPropertyStatus) {
+				status.ManagedHostnames = nil
+			},
+			expectedStatus:  metav1.ConditionFalse,
+			expectedReason:  "HostnamesNotSynced",
+			expectedMessage: "Property hostnames do not match spec.hostnames yet",
+		},
+		{
+			name: "invalid spec is the most blocking reason",
+			mutate: func(status *akamaiV1alpha1.AkamaiPropertyStatus) {
+				status.Phase = PhaseError
+				status.StagingActivationStatus = "PENDING"
+				status.Conditions = []metav1.Condition{
+					readyCondition(metav1.ConditionFalse, "InvalidSpec", "rule validation failed"),
+					{Type: ConditionTypeInvalidSpec, Status: metav1.ConditionTrue, Reason: "ValidationFailed", Message: "rule validation failed"},
+				}
+			},
+			expectedStatus:  metav1.ConditionFalse,
+			expectedReason:  "InvalidSpec",
+			expectedMessage: "rule validation failed; Staging activation is PENDING",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			property := &akamaiV1alpha1.AkamaiProperty{
+				ObjectMeta: metav1.ObjectMeta{Generation: 3},
+				Spec: akamaiV1alpha1.AkamaiPropertySpec{
+					Hostnames: []akamaiV1alpha1.Hostname{{CNAMEFrom: "www.example.com", CNAMETo: "www.example.com.edgesuite.net"}},
+				},
+				Status: *synced.DeepCopy(),
+			}
+			tt.mutate(&property.Status)
+
+			if !setSummaryCondition(property) {
+				t.Fatal("expected the Summary condition to be added")
+			}
+			condition := meta.FindStatusCondition(property.Status.Conditions, ConditionTypeSummary)
+			if condition.Status != tt.expectedStatus || condition.Reason != tt.expectedReason || condition.Message != tt.expectedMessage {
+				t.Errorf("Summary = %s/%s %q, expected %s/%s %q", condition.Status, condition.Reason, condition.Message,
+					tt.expectedStatus, tt.expectedReason, tt.expectedMessage)
+			}
+			if condition.ObservedGeneration != 3 {
+				t.Errorf("ObservedGeneration = %d, expected 3", condition.ObservedGeneration)
+			}
+			if setSummaryCondition(property) {
+				t.Error("expected an unchanged status to leave the Summary condition unchanged")
+			}
+		})
+	}
+}
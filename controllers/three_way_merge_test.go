@@ -0,0 +1,135 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/runtime"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+)
+
+func mustRules(t *testing.T, raw string) *akamaiV1alpha1.PropertyRules {
+	t.Helper()
+	var rules akamaiV1alpha1.PropertyRules
+	if err := json.Unmarshal([]byte(raw), &rules); err != nil {
+		t.Fatalf("failed to parse rules: %v", err)
+	}
+	return &rules
+}
+
+func TestMergeLastApplied(t *testing.T) {
+	lastApplied := mustRules(t, `{"name": "default",
+		"behaviors": [{"name": "origin", "options": {"hostname": "old.example.com"}}, {"name": "gzipResponse", "options": {"behavior": "ALWAYS"}}],
+		"children": [{"name": "Static"}, {"name": "Legacy"}]}`)
+	desired := mustRules(t, `{"name": "default",
+		"behaviors": [{"name": "origin", "options": {"hostname": "new.example.com"}}, {"name": "http2", "options": {}}],
+		"children": [{"name": "Static"}, {"name": "Images"}]}`)
+	current := map[string]interface{}{
+		"name": "default",
+		"behaviors": []interface{}{
+			map[string]interface{}{"name": "origin", "options": map[string]interface{}{"hostname": "old.example.com", "ipVersion": "IPV4"}},
+			map[string]interface{}{"name": "gzipResponse", "options": map[string]interface{}{"behavior": "ALWAYS"}},
+			map[string]interface{}{"name": "webApplicationFirewall", "options": map[string]interface{}{"firewallConfiguration": "waf"}},
+		},
+		"children": []interface{}{
+			map[string]interface{}{"name": "Static"},
+			map[string]interface{}{"name": "Bot Manager"},
+			map[string]interface{}{"name": "Legacy"},
+		},
+		"comments": "added in Property Manager",
+	}
+
+	snapshot, err := encodeAppliedRules(lastApplied, nil)
+	if err != nil {
+		t.Fatalf("encodeAppliedRules() unexpected error: %v", err)
+	}
+	property := &akamaiV1alpha1.AkamaiProperty{Spec: akamaiV1alpha1.AkamaiPropertySpec{RulesMergeStrategy: akamaiV1alpha1.RulesMergeStrategyThreeWay}}
+	property.Status.LastAppliedRules = snapshot
+
+	r := &AkamaiPropertyReconciler{}
+	merged, err := r.mergeLastApplied(context.Background(), property, desired, current)
+	if err != nil {
+		t.Fatalf("mergeLastApplied() unexpected error: %v", err)
+	}
+	tree, err := ruleTree(merged)
+	if err != nil {
+		t.Fatalf("ruleTree() unexpected error: %v", err)
+	}
+
+	_, behaviors := namedItems(tree["behaviors"])
+	if got := strings.Join(behaviors, ","); got != "origin,webApplicationFirewall,http2" {
+		t.Errorf("behaviors = %s, expected the removed gzipResponse gone and the firewall added outside the operator kept", got)
+	}
+	options := tree["behaviors"].([]interface{})[0].(map[string]interface{})["options"].(map[string]interface{})
+	if options["hostname"] != "new.example.com" || options["ipVersion"] != "IPV4" {
+		t.Errorf("origin options = %v, expected the desired hostname and the option added outside the operator", options)
+	}
+	_, children := namedItems(tree["children"])
+	if got := strings.Join(children, ","); got != "Static,Bot Manager,Images" {
+		t.Errorf("children = %s, expected the rule added outside the operator kept in place", got)
+	}
+	if tree["comments"] != "added in Property Manager" {
+		t.Errorf("comments = %v, expected the comments added outside the operator", tree["comments"])
+	}
+
+	// Replace and a missing snapshot enforce the desired rules as they are
+	for _, p := range []*akamaiV1alpha1.AkamaiProperty{
+		{Status: property.Status},
+		{Spec: property.Spec},
+	} {
+		if got, err := r.mergeLastApplied(context.Background(), p, desired, current); err != nil || got != desired {
+			t.Errorf("mergeLastApplied() = %v, %v, expected the desired rules", got, err)
+		}
+	}
+}
+
+func TestAppliedRulesSnapshot(t *testing.T) {
+	rules := &akamaiV1alpha1.PropertyRules{
+		Name:      "default",
+		Behaviors: []akamaiV1alpha1.RuleBehavior{{Name: "caching", Options: runtime.RawExtension{Raw: []byte(`{"ttl":"1d"}`)}}},
+		Variables: []akamaiV1alpha1.RuleVariable{{Name: "PMUSER_TOKEN", Value: "secret", Sensitive: true}},
+	}
+	snapshot, err := encodeAppliedRules(rules, nil)
+	if err != nil {
+		t.Fatalf("encodeAppliedRules() unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(snapshot.Digest, "sha256:") {
+		t.Errorf("digest = %q", snapshot.Digest)
+	}
+	tree, err := decodeAppliedRules(snapshot)
+	if err != nil {
+		t.Fatalf("decodeAppliedRules() unexpected error: %v", err)
+	}
+	raw, _ := json.Marshal(tree)
+	if !strings.Contains(string(raw), `"ttl":"1d"`) || strings.Contains(string(raw), `"secret"`) {
+		t.Errorf("snapshot = %s, expected the rules with the sensitive value masked", raw)
+	}
+
+	tampered := *snapshot
+	tampered.Digest = "sha256:0000"
+	if _, err := decodeAppliedRules(&tampered); err == nil {
+		t.Error("expected a snapshot with another digest to be rejected")
+	}
+}
+
+func TestRecordLastApplied(t *testing.T) {
+	rules := &akamaiV1alpha1.PropertyRules{Name: "default"}
+	property := &akamaiV1alpha1.AkamaiProperty{Spec: akamaiV1alpha1.AkamaiPropertySpec{RulesMergeStrategy: akamaiV1alpha1.RulesMergeStrategyThreeWay}}
+	r := &AkamaiPropertyReconciler{}
+
+	if !r.recordLastApplied(context.Background(), property, rules, nil) || property.Status.LastAppliedRules == nil {
+		t.Fatal("expected the applied rules to be recorded")
+	}
+	if r.recordLastApplied(context.Background(), property, rules, nil) {
+		t.Error("expected the same rules to be recorded only once")
+	}
+
+	// Switching back to Replace drops the snapshot
+	property.Spec.RulesMergeStrategy = akamaiV1alpha1.RulesMergeStrategyReplace
+	if !r.recordLastApplied(context.Background(), property, rules, nil) || property.Status.LastAppliedRules != nil {
+		t.Error("expected the snapshot to be removed")
+	}
+}
@@ -0,0 +1,217 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+	"github.com/mmz-srf/akamai-operator/pkg/akamai"
+)
+
+// trafficReportWindow is the reporting window covered by each refresh
+const trafficReportWindow = time.Hour
+
+var (
+	trafficLabels = []string{"property", "cpcode"}
+
+	edgeBytesGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "akamai_property_edge_bytes",
+		Help: "Bytes delivered by the edge for a CP code of an AkamaiProperty over the last reporting window",
+	}, trafficLabels)
+	originBytesGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "akamai_property_origin_bytes",
+		Help: "Bytes fetched from origin for a CP code of an AkamaiProperty over the last reporting window",
+	}, trafficLabels)
+	bytesOffloadGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "akamai_property_bytes_offload_percent",
+		Help: "Byte offload percentage for a CP code of an AkamaiProperty over the last reporting window",
+	}, trafficLabels)
+	edgeHitsGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "akamai_property_edge_hits",
+		Help: "Edge hits for a CP code of an AkamaiProperty over the last reporting window",
+	}, trafficLabels)
+	originHitsGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "akamai_property_origin_hits",
+		Help: "Origin hits for a CP code of an AkamaiProperty over the last reporting window",
+	}, trafficLabels)
+	hitsOffloadGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "akamai_property_hits_offload_percent",
+		Help: "Hit offload percentage for a CP code of an AkamaiProperty over the last reporting window",
+	}, trafficLabels)
+
+	trafficGauges = []*prometheus.GaugeVec{
+		edgeBytesGauge, originBytesGauge, bytesOffloadGauge,
+		edgeHitsGauge, originHitsGauge, hitsOffloadGauge,
+	}
+)
+
+func init() {
+	for _, gauge := range trafficGauges {
+		metrics.Registry.MustRegister(gauge)
+	}
+}
+
+// TrafficReporter periodically pulls traffic and offload metrics of the CP codes used by
+// AkamaiProperty resources from the Reporting API and exports them as Prometheus metrics
+type TrafficReporter struct {
+	client.Client
+	AkamaiClient *akamai.Client
+
+	// Credentials selects the credential source the Akamai client is created with
+	Credentials akamai.Credentials
+
+	// Interval is the time between two refreshes
+	Interval time.Duration
+}
+
+//+kubebuilder:rbac:groups=akamai.com,resources=akamaiproperties,verbs=get;list;watch
+
+// Start runs the reporting loop until the context is cancelled
+func (t *TrafficReporter) Start(ctx context.Context) error {
+	logger := log.FromContext(ctx).WithName("traffic-reporter")
+	ctx = log.IntoContext(ctx, logger)
+
+	interval := t.Interval
+	if interval <= 0 {
+		interval = 5 * time.Minute
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := t.refresh(ctx); err != nil {
+			logger.Error(err, "Failed to refresh Akamai traffic metrics")
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// NeedLeaderElection ensures only the leader queries the Reporting API
+func (t *TrafficReporter) NeedLeaderElection() bool {
+	return true
+}
+
+// refresh pulls the metrics of all CP codes referenced by AkamaiProperty rules
+func (t *TrafficReporter) refresh(ctx context.Context) error {
+	logger := log.FromContext(ctx)
+
+	if t.AkamaiClient == nil {
+		akamaiClient, err := akamai.NewClient(t.Credentials)
+		if err != nil {
+			return fmt.Errorf("failed to create Akamai client: %w", err)
+		}
+		t.AkamaiClient = akamaiClient
+	}
+
+	var properties akamaiV1alpha1.AkamaiPropertyList
+	if err := t.List(ctx, &properties); err != nil {
+		return fmt.Errorf("failed to list AkamaiProperties: %w", err)
+	}
+
+	propertiesByCPCode := make(map[string][]string)
+	for _, property := range properties.Items {
+		// The Reporting API only knows the CP codes of the operator's own account
+		if property.Spec.CredentialsRef != nil || property.Spec.ProviderConfigRef != "" {
+			continue
+		}
+		// The CP codes of the rendered rule tree; properties not rendered yet fall back to the spec
+		cpCodes := property.Status.CPCodes
+		if cpCodes == nil {
+			rules, err := specRules(&property)
+			if err != nil {
+				continue
+			}
+			cpCodes = cpCodesFromRules(rules)
+		}
+		for _, cpCode := range cpCodes {
+			propertiesByCPCode[cpCode] = append(propertiesByCPCode[cpCode], property.Name)
+		}
+	}
+	cpCodes := make([]string, 0, len(propertiesByCPCode))
+	for cpCode := range propertiesByCPCode {
+		cpCodes = append(cpCodes, cpCode)
+	}
+	sort.Strings(cpCodes)
+
+	end := time.Now().Truncate(5 * time.Minute)
+	traffic, err := t.AkamaiClient.GetCPCodeTraffic(ctx, cpCodes, end.Add(-trafficReportWindow), end)
+	if err != nil {
+		return err
+	}
+
+	// Drop series of deleted properties and CP codes that are no longer referenced
+	for _, gauge := range trafficGauges {
+		gauge.Reset()
+	}
+	for _, cpCodeTraffic := range traffic {
+		for _, propertyName := range propertiesByCPCode[cpCodeTraffic.CPCode] {
+			labels := prometheus.Labels{"property": propertyName, "cpcode": cpCodeTraffic.CPCode}
+			edgeBytesGauge.With(labels).Set(cpCodeTraffic.EdgeBytes)
+			originBytesGauge.With(labels).Set(cpCodeTraffic.OriginBytes)
+			bytesOffloadGauge.With(labels).Set(cpCodeTraffic.BytesOffload)
+			edgeHitsGauge.With(labels).Set(cpCodeTraffic.EdgeHits)
+			originHitsGauge.With(labels).Set(cpCodeTraffic.OriginHits)
+			hitsOffloadGauge.With(labels).Set(cpCodeTraffic.HitsOffload)
+		}
+	}
+
+	logger.V(1).Info("Refreshed Akamai traffic metrics", "properties", len(properties.Items), "cpCodes", len(cpCodes))
+	return nil
+}
+
+// cpCodesFromRules returns the sorted, unique CP code IDs set by cpCode behaviors anywhere in the rule tree
+func cpCodesFromRules(rules *akamaiV1alpha1.PropertyRules) []string {
+	if rules == nil {
+		return nil
+	}
+
+	seen := make(map[string]bool)
+	collectCPCodes(rules, seen)
+
+	cpCodes := make([]string, 0, len(seen))
+	for cpCode := range seen {
+		cpCodes = append(cpCodes, cpCode)
+	}
+	sort.Strings(cpCodes)
+	return cpCodes
+}
+
+// collectCPCodes walks a rule and its children collecting cpCode behavior IDs
+func collectCPCodes(rules *akamaiV1alpha1.PropertyRules, seen map[string]bool) {
+	for _, behavior := range rules.Behaviors {
+		if behavior.Name != "cpCode" || behavior.Options.Raw == nil {
+			continue
+		}
+		var options struct {
+			Value struct {
+				ID json.Number `json:"id"`
+			} `json:"value"`
+		}
+		if err := json.Unmarshal(behavior.Options.Raw, &options); err != nil || options.Value.ID == "" {
+			continue
+		}
+		seen[options.Value.ID.String()] = true
+	}
+
+	for _, childRaw := range rules.Children {
+		var child akamaiV1alpha1.PropertyRules
+		if err := json.Unmarshal(childRaw.Raw, &child); err != nil {
+			continue
+		}
+		collectCPCodes(&child, seen)
+	}
+}
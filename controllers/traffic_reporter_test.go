@@ -0,0 +1,82 @@
+package controllers
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+)
+
+func TestCPCodesFromRules(t *testing.T) {
+	tests := []struct {
+		name     string
+		rules    *akamaiV1alpha1.PropertyRules
+		expected []string
+	}{
+		{
+			name:     "no rules",
+			rules:    nil,
+			expected: nil,
+		},
+		{
+			name: "default and child rules, deduplicated and sorted",
+			rules: &akamaiV1alpha1.PropertyRules{
+				Name: "default",
+				Behaviors: []akamaiV1alpha1.RuleBehavior{
+					{Name: "cpCode", Options: runtime.RawExtension{Raw: []byte(`{"value": {"id": 456789, "name": "www"}}`)}},
+					{Name: "caching", Options: runtime.RawExtension{Raw: []byte(`{"behavior": "MAX_AGE"}`)}},
+				},
+				Children: []runtime.RawExtension{
+					{Raw: []byte(`{"name": "static", "behaviors": [{"name": "cpCode", "options": {"value": {"id": 123456}}}]}`)},
+					{Raw: []byte(`{"name": "api", "behaviors": [{"name": "cpCode", "options": {"value": {"id": "456789"}}}]}`)},
+				},
+			},
+			expected: []string{"123456", "456789"},
+		},
+		{
+			name: "cpCode behavior without ID",
+			rules: &akamaiV1alpha1.PropertyRules{
+				Name:      "default",
+				Behaviors: []akamaiV1alpha1.RuleBehavior{{Name: "cpCode", Options: runtime.RawExtension{Raw: []byte(`{"value": {}}`)}}},
+			},
+			expected: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := cpCodesFromRules(tt.rules)
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("cpCodesFromRules() = %v, expected %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestDesiredRulesCPCodes(t *testing.T) {
+	property := &akamaiV1alpha1.AkamaiProperty{
+		ObjectMeta: metav1.ObjectMeta{Name: "shop"},
+		Spec: akamaiV1alpha1.AkamaiPropertySpec{
+			Rules: &akamaiV1alpha1.PropertyRules{Name: "default"},
+			RulesPatches: []akamaiV1alpha1.RulesPatch{{Patch: runtime.RawExtension{
+				Raw: []byte(`[{"op":"add","path":"/behaviors","value":[{"name":"cpCode","options":{"value":{"id":123456}}}]}]`),
+			}}},
+		},
+	}
+	r := newFakeReconciler(t, property)
+
+	_, sources, err := r.desiredRules(context.Background(), property)
+	if err != nil {
+		t.Fatalf("desiredRules() error = %v", err)
+	}
+	if !r.recordRulesDigest(property, sources) || !reflect.DeepEqual(property.Status.CPCodes, []string{"123456"}) {
+		t.Errorf("status CP codes = %v, expected the CP code of the rendered rule tree", property.Status.CPCodes)
+	}
+	if r.recordRulesDigest(property, sources) {
+		t.Error("recordRulesDigest() reported a change for the same CP codes")
+	}
+}
@@ -0,0 +1,70 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+)
+
+func TestDesiredRulesRendersVariables(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "origin-token", Namespace: "edge", Labels: map[string]string{LabelRuleValues: "true"}},
+		Data:       map[string][]byte{"token": []byte("s3cr3t")},
+	}
+	r := &AkamaiPropertyReconciler{Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(secret).Build()}
+
+	property := &akamaiV1alpha1.AkamaiProperty{
+		Spec: akamaiV1alpha1.AkamaiPropertySpec{
+			Rules: &akamaiV1alpha1.PropertyRules{
+				Name: "default",
+				Variables: []akamaiV1alpha1.RuleVariable{
+					{Name: "PMUSER_KEEP", Value: "rules"},
+					{Name: "PMUSER_TOKEN", Value: "from-rules"},
+				},
+			},
+			Variables: []akamaiV1alpha1.PropertyVariable{
+				{
+					Name:      "PMUSER_TOKEN",
+					Sensitive: true,
+					ValueFrom: &akamaiV1alpha1.VariableValueSource{
+						SecretKeyRef: &akamaiV1alpha1.SecretKeyReference{Namespace: "edge", Name: "origin-token", Key: "token"},
+					},
+				},
+			},
+		},
+	}
+
+	rules, _, err := r.desiredRules(context.Background(), property)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(rules.Variables) != 2 {
+		t.Fatalf("expected 2 variables, got %+v", rules.Variables)
+	}
+	if rules.Variables[0].Name != "PMUSER_KEEP" || rules.Variables[1].Value != "s3cr3t" || !rules.Variables[1].Sensitive {
+		t.Errorf("unexpected variables: %+v", rules.Variables)
+	}
+	if property.Spec.Rules.Variables[1].Value != "from-rules" {
+		t.Errorf("spec rules must not be modified")
+	}
+
+	normalized := map[string]interface{}{
+		"variables": []interface{}{
+			map[string]interface{}{"name": "PMUSER_KEEP", "value": "rules"},
+			map[string]interface{}{"name": "PMUSER_TOKEN", "value": "s3cr3t", "sensitive": true},
+		},
+	}
+	maskSensitiveVariables(normalized)
+	variables := normalized["variables"].([]interface{})
+	if variables[0].(map[string]interface{})["value"] != "rules" || variables[1].(map[string]interface{})["value"] != maskedValue {
+		t.Errorf("unexpected masking result: %+v", variables)
+	}
+}
@@ -0,0 +1,84 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+)
+
+func TestNeedsNewVersion(t *testing.T) {
+	tests := []struct {
+		name              string
+		strategy          akamaiV1alpha1.VersionStrategy
+		published         bool
+		versionGeneration int64
+		expected          bool
+		expectedErr       error
+	}{
+		{name: "default reuses unpublished", published: false, expected: false},
+		{name: "default creates after publish", published: true, expected: true},
+		{name: "reuse unpublished ignores generation", strategy: akamaiV1alpha1.VersionStrategyReuseUnpublished, versionGeneration: 1, expected: false},
+		{name: "always new for a new generation", strategy: akamaiV1alpha1.VersionStrategyAlwaysNew, versionGeneration: 1, expected: true},
+		{name: "always new reuses version of same generation", strategy: akamaiV1alpha1.VersionStrategyAlwaysNew, versionGeneration: 2, expected: false},
+		{name: "always new after publish", strategy: akamaiV1alpha1.VersionStrategyAlwaysNew, versionGeneration: 2, published: true, expected: true},
+		{name: "manual edits unpublished version", strategy: akamaiV1alpha1.VersionStrategyManual, expected: false},
+		{name: "manual waits after publish", strategy: akamaiV1alpha1.VersionStrategyManual, published: true, expectedErr: errManualVersionRequired},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			property := &akamaiV1alpha1.AkamaiProperty{
+				ObjectMeta: metav1.ObjectMeta{Generation: 2},
+				Spec:       akamaiV1alpha1.AkamaiPropertySpec{VersionStrategy: tt.strategy},
+				Status:     akamaiV1alpha1.AkamaiPropertyStatus{LatestVersion: 3, VersionGeneration: tt.versionGeneration},
+			}
+			got, err := needsNewVersion(property, tt.published)
+			if !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("needsNewVersion() error = %v, expected %v", err, tt.expectedErr)
+			}
+			if got != tt.expected {
+				t.Errorf("needsNewVersion() = %v, expected %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSetWaitingForActivation(t *testing.T) {
+	ctx := context.Background()
+	property := &akamaiV1alpha1.AkamaiProperty{ObjectMeta: metav1.ObjectMeta{Name: "example", Generation: 1}}
+	r := newFakeReconciler(t, property)
+
+	// Clearing a condition that was never set leaves the status alone
+	if err := r.setWaitingForActivation(ctx, property, nil); err != nil {
+		t.Fatalf("setWaitingForActivation() error = %v", err)
+	}
+	if meta.FindStatusCondition(property.Status.Conditions, ConditionTypeWaitingForActivation) != nil {
+		t.Fatalf("condition set although no activation was pending")
+	}
+
+	reason := fmt.Errorf("%w: version 3 has a pending activation", errVersionNotEditable)
+	if err := r.setWaitingForActivation(ctx, property, reason); err != nil {
+		t.Fatalf("setWaitingForActivation() error = %v", err)
+	}
+	if !meta.IsStatusConditionTrue(property.Status.Conditions, ConditionTypeWaitingForActivation) {
+		t.Fatalf("expected WaitingForActivation to be true")
+	}
+
+	if err := r.setWaitingForActivation(ctx, property, nil); err != nil {
+		t.Fatalf("setWaitingForActivation() error = %v", err)
+	}
+	var stored akamaiV1alpha1.AkamaiProperty
+	if err := r.Get(ctx, client.ObjectKeyFromObject(property), &stored); err != nil {
+		t.Fatalf("failed to get property: %v", err)
+	}
+	if !meta.IsStatusConditionFalse(stored.Status.Conditions, ConditionTypeWaitingForActivation) {
+		t.Errorf("expected stored WaitingForActivation to be false, got %v", stored.Status.Conditions)
+	}
+}
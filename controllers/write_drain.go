@@ -0,0 +1,88 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// ErrShuttingDown is returned for write sequences started after the operator began shutting down
+var ErrShuttingDown = errors.New("the operator is shutting down")
+
+// WriteDrain lets the Akamai write sequences in flight at shutdown complete, so a SIGTERM doesn't
+// leave a version half updated, e.g. with its hostnames written but not its rules. Sequences run
+// with a context that ignores the cancellation of the reconcile. Once the manager stops, new
+// sequences are refused and the running ones get Timeout to complete before their context is
+// cancelled too; status.checkpoint then tells the next reconcile where to resume.
+type WriteDrain struct {
+	// Timeout bounds how long the shutdown waits for write sequences in flight
+	Timeout time.Duration
+
+	mu       sync.Mutex
+	draining bool
+	inFlight sync.WaitGroup
+	abort    context.Context
+	cancel   context.CancelFunc
+}
+
+// NewWriteDrain creates a drain waiting at most timeout for write sequences at shutdown
+func NewWriteDrain(timeout time.Duration) *WriteDrain {
+	abort, cancel := context.WithCancel(context.Background())
+	return &WriteDrain{Timeout: timeout, abort: abort, cancel: cancel}
+}
+
+// Begin starts a write sequence. The returned context is only cancelled when the drain times
+// out, and done must be called once the sequence completes. A nil drain returns ctx unchanged.
+func (d *WriteDrain) Begin(ctx context.Context) (context.Context, func(), error) {
+	if d == nil {
+		return ctx, func() {}, nil
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.draining {
+		return ctx, func() {}, ErrShuttingDown
+	}
+
+	d.inFlight.Add(1)
+	writeCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	stop := context.AfterFunc(d.abort, cancel)
+	return writeCtx, func() {
+		stop()
+		cancel()
+		d.inFlight.Done()
+	}, nil
+}
+
+// Start waits for the manager to stop, then drains the write sequences in flight
+func (d *WriteDrain) Start(ctx context.Context) error {
+	logger := log.FromContext(ctx).WithName("write-drain")
+	<-ctx.Done()
+
+	d.mu.Lock()
+	d.draining = true
+	d.mu.Unlock()
+
+	drained := make(chan struct{})
+	go func() {
+		d.inFlight.Wait()
+		close(drained)
+	}()
+
+	logger.Info("Waiting for Akamai writes in flight", "timeout", d.Timeout)
+	select {
+	case <-drained:
+		logger.Info("Akamai writes completed")
+	case <-time.After(d.Timeout):
+		logger.Info("Akamai writes did not complete in time; aborting them, they resume from their checkpoint")
+		d.cancel()
+	}
+	return nil
+}
+
+// NeedLeaderElection runs the drain on every replica, alongside the reconciles it waits for
+func (d *WriteDrain) NeedLeaderElection() bool {
+	return false
+}
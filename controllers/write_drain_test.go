@@ -0,0 +1,83 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestWriteDrain(t *testing.T) {
+	t.Run("writes in flight complete after shutdown", func(t *testing.T) {
+		drain := NewWriteDrain(time.Minute)
+		managerCtx, stopManager := context.WithCancel(context.Background())
+		stopped := make(chan struct{})
+		go func() {
+			_ = drain.Start(managerCtx)
+			close(stopped)
+		}()
+
+		reconcileCtx, cancelReconcile := context.WithCancel(managerCtx)
+		writeCtx, done, err := drain.Begin(reconcileCtx)
+		if err != nil {
+			t.Fatalf("Begin() unexpected error: %v", err)
+		}
+		stopManager()
+		cancelReconcile()
+
+		if writeCtx.Err() != nil {
+			t.Fatal("write context was cancelled with the reconcile")
+		}
+		select {
+		case <-stopped:
+			t.Fatal("drain returned while a write was in flight")
+		case <-time.After(50 * time.Millisecond):
+		}
+
+		done()
+		select {
+		case <-stopped:
+		case <-time.After(time.Second):
+			t.Fatal("drain did not return after the write completed")
+		}
+
+		if _, _, err := drain.Begin(context.Background()); !errors.Is(err, ErrShuttingDown) {
+			t.Errorf("Begin() after shutdown = %v, expected %v", err, ErrShuttingDown)
+		}
+	})
+
+	t.Run("timeout aborts writes", func(t *testing.T) {
+		drain := NewWriteDrain(10 * time.Millisecond)
+		managerCtx, stopManager := context.WithCancel(context.Background())
+
+		writeCtx, done, err := drain.Begin(managerCtx)
+		if err != nil {
+			t.Fatalf("Begin() unexpected error: %v", err)
+		}
+		defer done()
+
+		stopManager()
+		if err := drain.Start(managerCtx); err != nil {
+			t.Fatalf("Start() unexpected error: %v", err)
+		}
+		select {
+		case <-writeCtx.Done():
+		case <-time.After(time.Second):
+			t.Fatal("write context was not cancelled after the drain timeout")
+		}
+	})
+
+	t.Run("nil drain", func(t *testing.T) {
+		var drain *WriteDrain
+		ctx, cancel := context.WithCancel(context.Background())
+		writeCtx, done, err := drain.Begin(ctx)
+		if err != nil {
+			t.Fatalf("Begin() unexpected error: %v", err)
+		}
+		done()
+		cancel()
+		if writeCtx.Err() == nil {
+			t.Error("write context of a nil drain must follow the reconcile")
+		}
+	})
+}
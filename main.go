@@ -3,6 +3,8 @@ package main
 import (
 	"flag"
 	"os"
+	"strings"
+	"time"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	// to ensure that exec-entrypoint and run can make use of them.
@@ -19,6 +21,9 @@ import (
 
 	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
 	"github.com/mmz-srf/akamai-operator/controllers"
+	"github.com/mmz-srf/akamai-operator/pkg/akamai"
+	"github.com/mmz-srf/akamai-operator/pkg/lint"
+	"github.com/mmz-srf/akamai-operator/pkg/render"
 	//+kubebuilder:scaffold:imports
 )
 
@@ -35,14 +40,168 @@ func init() {
 }
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "restore" {
+		os.Exit(runRestore(os.Args[2:]))
+	}
+
 	var metricsAddr string
 	var enableLeaderElection bool
 	var probeAddr string
+	var injectRuleComments bool
+	var checkRuleFormats bool
+	var validateRuleSchemas bool
+	var mirrorAccount bool
+	var reportTraffic bool
+	var observeOnly bool
+	var collectEdgeHostnames bool
+	var maxConcurrentActivations int
+	var maxConcurrentReconciles int
+	var contractConcurrency int
+	var lintSeverities string
+	var preserveBehaviors string
+	var guardrailNames string
+	var externalRenderers string
+	var registryTokenHosts string
+	var inventoryAddr string
+	var inventoryTokenFile string
+	var adminAddr string
+	var adminTokenFile string
+	var externalDNSWebhookAddr string
+	var externalDNSZones string
+	var edgeHostnameTemplate string
+	var edgercPath string
+	var edgercSection string
+	var shardName string
+	var shardSelector string
+	var shardContracts string
+	var ingressClass string
+	var ingressContractID string
+	var ingressGroupID string
+	var ingressProductID string
+	var ingressCPCode int64
+	var ingressOriginHostname string
+	var ingressActivationNetwork string
+	var ingressNotifyEmails string
+	var accountAnnotationNamespaces string
+	var propagatedLabels string
+	var gatewayControllerName string
+	var versionPollInterval time.Duration
+	var mirrorInterval time.Duration
+	var reportTrafficInterval time.Duration
+	var shutdownDrainTimeout time.Duration
+	var expiryWarning time.Duration
+	var requestRate float64
+	var requestBurst int
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
 	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
+	flag.BoolVar(&injectRuleComments, "inject-rule-comments", false,
+		"Append a managed-by block (resource name, UID, git revision) to the top-level rule comments.")
+	flag.BoolVar(&checkRuleFormats, "check-rule-formats", true,
+		"Validate that the rule format of each AkamaiProperty is supported for its product before creating it.")
+	flag.BoolVar(&validateRuleSchemas, "validate-rule-schemas", true,
+		"Validate the behaviors and criteria of AkamaiProperty rules against the PAPI schema of their product and rule format before pushing them.")
+	flag.BoolVar(&mirrorAccount, "mirror-account", false,
+		"Mirror available contracts, groups and products into read-only AkamaiContract and AkamaiGroup resources.")
+	flag.DurationVar(&mirrorInterval, "mirror-account-interval", time.Hour,
+		"How often the AkamaiContract and AkamaiGroup mirrors are refreshed.")
+	flag.BoolVar(&reportTraffic, "report-traffic", false,
+		"Export traffic and offload metrics of the CP codes used by AkamaiProperty rules from the Reporting API.")
+	flag.DurationVar(&reportTrafficInterval, "report-traffic-interval", 5*time.Minute,
+		"How often the traffic metrics are pulled from the Reporting API.")
+	flag.StringVar(&lintSeverities, "lint-severities", "",
+		"Comma separated rule=severity overrides for the rules linter (severity: off, warning, blocking).")
+	flag.DurationVar(&versionPollInterval, "version-poll-interval", 30*time.Second,
+		"How often a property version locked by a pending activation is polled before it is updated.")
+	flag.IntVar(&maxConcurrentActivations, "max-concurrent-activations", 0,
+		"Maximum number of activations this instance keeps in flight per Akamai account (0 means unlimited). "+
+			"Activations of other instances or tooling in the same account aren't counted.")
+	flag.StringVar(&edgeHostnameTemplate, "edge-hostname-template", "",
+		"Naming template for the domain prefix of edge hostnames created without one, e.g. {team}-{env}-{property}. "+
+			"Placeholders are {property}, {name} and resource label keys.")
+	flag.StringVar(&preserveBehaviors, "preserve-behaviors", "",
+		"Comma separated behavior names (e.g. siteShield) that are never removed from AkamaiProperty rules, only reported.")
+	flag.StringVar(&guardrailNames, "guardrails", "",
+		"Comma separated guardrails checked on the rendered rules before every production activation "+
+			"(caching-removed, zero-ttl-at-default, no-store-at-default).")
+	flag.StringVar(&externalRenderers, "external-renderers", "",
+		"Comma separated name=target external rule renderers AkamaiProperties can select with spec.renderer.external. "+
+			"A target is an http(s) URL the render input is POSTed to, or a command (optionally prefixed with exec:) reading it on stdin.")
+	flag.StringVar(&registryTokenHosts, "registry-token-hosts", "",
+		"Comma separated hosts, besides the registry itself and auth.docker.io, that registries of spec.rulesFrom may send "+
+			"their credentials to in exchange for a bearer token.")
+	flag.StringVar(&inventoryAddr, "inventory-bind-address", "",
+		"The address the read-only JSON inventory API binds to, e.g. :8082. Disabled when empty.")
+	flag.StringVar(&inventoryTokenFile, "inventory-token-file", "",
+		"File holding the bearer token clients of the inventory API authenticate with. Required with --inventory-bind-address.")
+	flag.StringVar(&adminAddr, "admin-bind-address", "",
+		"The address the admin API for operations on properties selected by labels binds to, e.g. :8083. Disabled when empty.")
+	flag.StringVar(&adminTokenFile, "admin-token-file", "",
+		"File holding the bearer token clients of the admin API authenticate with. Required with --admin-bind-address.")
+	flag.StringVar(&externalDNSWebhookAddr, "external-dns-webhook-bind-address", "",
+		"The loopback address the external-dns webhook provider for Edge DNS binds to, e.g. :8888 for 127.0.0.1:8888. "+
+			"Disabled when empty.")
+	flag.StringVar(&externalDNSZones, "external-dns-zones", "",
+		"Comma separated Edge DNS zones external-dns manages records in. Required with --external-dns-webhook-bind-address.")
+	flag.StringVar(&edgercPath, "edgerc", os.Getenv("AKAMAI_EDGERC"),
+		"Path of an .edgerc file to read the Akamai credentials from instead of the AKAMAI_* environment variables. "+
+			"Defaults to $AKAMAI_EDGERC.")
+	flag.StringVar(&edgercSection, "edgerc-section", os.Getenv("AKAMAI_EDGERC_SECTION"),
+		"Section of the .edgerc file to use (default \"default\"). Defaults to $AKAMAI_EDGERC_SECTION.")
+	flag.DurationVar(&shutdownDrainTimeout, "shutdown-drain-timeout", 30*time.Second,
+		"How long a shutdown waits for Akamai writes in flight to complete before aborting them. "+
+			"Keep it below the terminationGracePeriodSeconds of the pod.")
+	flag.Float64Var(&requestRate, "akamai-request-rate", akamai.DefaultRequestRate,
+		"Client-side budget of Akamai API requests per second of each account (0 disables it). "+
+			"Pauses requested by the X-RateLimit-* response headers are honored regardless.")
+	flag.IntVar(&requestBurst, "akamai-request-burst", akamai.DefaultRequestBurst,
+		"Number of Akamai API requests of an account allowed in a burst above --akamai-request-rate.")
+	flag.IntVar(&maxConcurrentReconciles, "max-concurrent-reconciles", 1,
+		"Number of AkamaiProperty resources reconciled in parallel.")
+	flag.IntVar(&contractConcurrency, "akamai-contract-concurrency", akamai.DefaultContractConcurrency,
+		"Maximum number of Akamai API requests in flight per contract (0 disables the limit).")
+	flag.DurationVar(&expiryWarning, "expiry-warning", 24*time.Hour,
+		"How long before spec.expiresAt or spec.ttl runs out a temporary property is warned about.")
+	flag.StringVar(&shardName, "shard-name", "",
+		"The name of the shard this instance manages, needed with --shard-selector or --shard-contracts. "+
+			"Each shard elects its own leader.")
+	flag.StringVar(&shardSelector, "shard-selector", "",
+		"A label selector restricting the AkamaiProperties, AkamaiPropertyIncludes, AkamaiEdgeHostnames, AkamaiDnsZones, "+
+			"AkamaiDnsRecords, AkamaiRuleValidations and AkamaiPropertyActivations this instance watches and reconciles, e.g. akamai.com/shard=news.")
+	flag.StringVar(&shardContracts, "shard-contracts", "",
+		"Comma separated contract IDs this instance reconciles; properties of other contracts are left to other shards.")
+	flag.BoolVar(&collectEdgeHostnames, "collect-edge-hostnames", false,
+		"Delete the edge hostnames the operator created for a property once none of its hostnames and none of its "+
+			"versions use them anymore, not only when the property is deleted.")
+	flag.BoolVar(&observeOnly, "observe-only", false,
+		"Compare all resources with Akamai and report differences without changing anything in Akamai, "+
+			"e.g. while introducing the operator into an account managed by other tooling.")
+	flag.StringVar(&ingressClass, "ingress-class", "",
+		"Synthesize an AkamaiProperty for each Ingress with this ingressClassName, e.g. akamai. Empty disables it.")
+	flag.StringVar(&ingressContractID, "ingress-contract-id", "",
+		"The contract of the properties synthesized for Ingresses without the akamai.com/contract-id annotation.")
+	flag.StringVar(&ingressGroupID, "ingress-group-id", "",
+		"The group of the properties synthesized for Ingresses without the akamai.com/group-id annotation.")
+	flag.StringVar(&ingressProductID, "ingress-product-id", "",
+		"The product of the properties synthesized for Ingresses without the akamai.com/product-id annotation.")
+	flag.Int64Var(&ingressCPCode, "ingress-cp-code", 0,
+		"The CP code of the properties synthesized for Ingresses without the akamai.com/cp-code annotation.")
+	flag.StringVar(&ingressOriginHostname, "ingress-origin-hostname", "",
+		"The origin of Ingresses whose status has no load balancer address, e.g. the DNS name of the cluster's public load balancer.")
+	flag.StringVar(&ingressActivationNetwork, "ingress-activation-network", "STAGING",
+		"The network the properties synthesized for Ingresses are activated on: STAGING or PRODUCTION.")
+	flag.StringVar(&ingressNotifyEmails, "ingress-notify-emails", "",
+		"Comma separated emails notified about the activations of the properties synthesized for Ingresses. "+
+			"Without them, or the akamai.com/notify-emails annotation, the properties are not activated.")
+	flag.StringVar(&accountAnnotationNamespaces, "account-annotation-namespaces", "",
+		"Comma separated namespaces whose Ingresses and Gateways may choose the contract, group, product and CPS certificate of their property with annotations.")
+	flag.StringVar(&propagatedLabels, "propagated-labels", "",
+		"Comma separated keys of the Ingress and Gateway labels copied onto the synthesized AkamaiProperties, e.g. the labels of the shard selectors.")
+	flag.StringVar(&gatewayControllerName, "gateway-controller-name", "",
+		"Implement the Gateway API for the GatewayClasses with this controllerName, e.g. akamai.com/gateway-controller. "+
+			"Empty disables it; otherwise the Gateway API CRDs must be installed.")
 	opts := zap.Options{
 		Development: true,
 	}
@@ -51,26 +210,363 @@ func main() {
 
 	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
 
+	severities, err := lint.ParseSeverities(lintSeverities)
+	if err != nil {
+		setupLog.Error(err, "invalid lint severities")
+		os.Exit(1)
+	}
+
+	guardrails, err := lint.ParseGuardrails(guardrailNames)
+	if err != nil {
+		setupLog.Error(err, "invalid guardrails")
+		os.Exit(1)
+	}
+
+	renderers, err := render.ParseExternal(externalRenderers)
+	if err != nil {
+		setupLog.Error(err, "invalid external renderers")
+		os.Exit(1)
+	}
+
+	shard, err := controllers.NewShard(shardName, shardSelector, splitList(shardContracts))
+	if err != nil {
+		setupLog.Error(err, "invalid shard")
+		os.Exit(1)
+	}
+	if shard != nil {
+		setupLog.Info("Managing a shard of the fleet", "shard", shard.Name,
+			"selector", shardSelector, "contracts", shard.Contracts)
+	}
+
+	akamai.SetRateLimit(requestRate, requestBurst)
+	akamai.SetContractConcurrency(contractConcurrency)
+	akamai.SetObserveOnly(observeOnly)
+	if observeOnly {
+		setupLog.Info("observe-only mode: no changes are made in Akamai")
+	}
+	credentials := akamai.Credentials{EdgercPath: edgercPath, EdgercSection: edgercSection}
+	clientCache := controllers.NewAkamaiClientCache()
+	drain := controllers.NewWriteDrain(shutdownDrainTimeout)
+	// The manager must outlive the drain to let the reconciles finish their writes
+	gracefulShutdownTimeout := shutdownDrainTimeout + 10*time.Second
+
 	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
-		Scheme:                 scheme,
-		Metrics:                metricsserver.Options{BindAddress: metricsAddr},
-		WebhookServer:          webhook.NewServer(webhook.Options{Port: 9443}),
-		HealthProbeBindAddress: probeAddr,
-		LeaderElection:         enableLeaderElection,
-		LeaderElectionID:       "akamai-operator.akamai.com",
+		Scheme:                  scheme,
+		Metrics:                 metricsserver.Options{BindAddress: metricsAddr},
+		WebhookServer:           webhook.NewServer(webhook.Options{Port: 9443}),
+		HealthProbeBindAddress:  probeAddr,
+		LeaderElection:          enableLeaderElection,
+		LeaderElectionID:        shard.LeaderElectionID("akamai-operator.akamai.com"),
+		Cache:                   shard.CacheOptions(),
+		GracefulShutdownTimeout: &gracefulShutdownTimeout,
 	})
 	if err != nil {
 		setupLog.Error(err, "unable to start manager")
 		os.Exit(1)
 	}
 
+	// Property activations share one scheduler, whether the property controller or an
+	// AkamaiPropertyActivation submits them
+	activationScheduler := controllers.NewActivationScheduler(maxConcurrentActivations)
 	if err = (&controllers.AkamaiPropertyReconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
+		Client:                  mgr.GetClient(),
+		Scheme:                  mgr.GetScheme(),
+		Linter:                  lint.NewLinter(severities),
+		InjectRuleComments:      injectRuleComments,
+		CheckRuleFormats:        checkRuleFormats,
+		ValidateRuleSchemas:     validateRuleSchemas,
+		VersionPollInterval:     versionPollInterval,
+		ActivationScheduler:     activationScheduler,
+		ActivationWatcher:       &akamai.ActivationWatcher{},
+		EdgeHostnameTemplate:    edgeHostnameTemplate,
+		PreserveBehaviors:       splitList(preserveBehaviors),
+		Guardrails:              guardrails,
+		Renderers:               renderers,
+		RulesFetcher:            &render.Fetcher{TokenHosts: splitList(registryTokenHosts)},
+		Credentials:             credentials,
+		ClientCache:             clientCache,
+		Drain:                   drain,
+		Backoff:                 controllers.NewErrorBackoff(),
+		Recorder:                mgr.GetEventRecorder("akamai-operator"),
+		ExpiryWarning:           expiryWarning,
+		MaxConcurrentReconciles: maxConcurrentReconciles,
+		ObserveOnly:             observeOnly,
+		Shard:                   shard,
+		CollectEdgeHostnames:    collectEdgeHostnames,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "AkamaiProperty")
 		os.Exit(1)
 	}
+	if err = (&controllers.AkamaiRuleValidationReconciler{
+		Client:      mgr.GetClient(),
+		Scheme:      mgr.GetScheme(),
+		Credentials: credentials,
+		ClientCache: clientCache,
+		Shard:       shard,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "AkamaiRuleValidation")
+		os.Exit(1)
+	}
+	if err = (&controllers.AkamaiPropertyActivationReconciler{
+		Client:              mgr.GetClient(),
+		Scheme:              mgr.GetScheme(),
+		Credentials:         credentials,
+		ClientCache:         clientCache,
+		ActivationScheduler: activationScheduler,
+		Shard:               shard,
+		Guardrails:          guardrails,
+		ObserveOnly:         observeOnly,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "AkamaiPropertyActivation")
+		os.Exit(1)
+	}
+	if err = (&controllers.AkamaiPropertyIncludeReconciler{
+		Client:      mgr.GetClient(),
+		Scheme:      mgr.GetScheme(),
+		Credentials: credentials,
+		ClientCache: clientCache,
+		Shard:       shard,
+		ObserveOnly: observeOnly,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "AkamaiPropertyInclude")
+		os.Exit(1)
+	}
+	if err = (&controllers.AkamaiEdgeHostnameReconciler{
+		Client:      mgr.GetClient(),
+		Scheme:      mgr.GetScheme(),
+		Credentials: credentials,
+		ClientCache: clientCache,
+		Shard:       shard,
+		ObserveOnly: observeOnly,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "AkamaiEdgeHostname")
+		os.Exit(1)
+	}
+	if err = (&controllers.AkamaiDnsZoneReconciler{
+		Client:      mgr.GetClient(),
+		Scheme:      mgr.GetScheme(),
+		Credentials: credentials,
+		Shard:       shard,
+		ObserveOnly: observeOnly,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "AkamaiDnsZone")
+		os.Exit(1)
+	}
+	if err = (&controllers.AkamaiDnsRecordReconciler{
+		Client:      mgr.GetClient(),
+		Scheme:      mgr.GetScheme(),
+		Credentials: credentials,
+		Shard:       shard,
+		ObserveOnly: observeOnly,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "AkamaiDnsRecord")
+		os.Exit(1)
+	}
+	if err = (&controllers.AkamaiNetworkListReconciler{
+		Client:      mgr.GetClient(),
+		Scheme:      mgr.GetScheme(),
+		Credentials: credentials,
+		Shard:       shard,
+		ObserveOnly: observeOnly,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "AkamaiNetworkList")
+		os.Exit(1)
+	}
+	if err = (&controllers.AkamaiAppSecConfigReconciler{
+		Client:      mgr.GetClient(),
+		Scheme:      mgr.GetScheme(),
+		Credentials: credentials,
+		Shard:       shard,
+		ObserveOnly: observeOnly,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "AkamaiAppSecConfig")
+		os.Exit(1)
+	}
+	if err = (&controllers.AkamaiGtmDomainReconciler{
+		Client:      mgr.GetClient(),
+		Scheme:      mgr.GetScheme(),
+		Credentials: credentials,
+		Shard:       shard,
+		ObserveOnly: observeOnly,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "AkamaiGtmDomain")
+		os.Exit(1)
+	}
+	if err = (&controllers.AkamaiEdgeKVNamespaceReconciler{
+		Client:      mgr.GetClient(),
+		Scheme:      mgr.GetScheme(),
+		Credentials: credentials,
+		Shard:       shard,
+		ObserveOnly: observeOnly,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "AkamaiEdgeKVNamespace")
+		os.Exit(1)
+	}
+	if err = (&controllers.AkamaiCloudletPolicyReconciler{
+		Client:      mgr.GetClient(),
+		Scheme:      mgr.GetScheme(),
+		Credentials: credentials,
+		Shard:       shard,
+		ObserveOnly: observeOnly,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "AkamaiCloudletPolicy")
+		os.Exit(1)
+	}
+	if err = (&controllers.AkamaiPurgeReconciler{
+		Client:      mgr.GetClient(),
+		Scheme:      mgr.GetScheme(),
+		Credentials: credentials,
+		Shard:       shard,
+		ObserveOnly: observeOnly,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "AkamaiPurge")
+		os.Exit(1)
+	}
+	if err = (&controllers.AkamaiCertificateReconciler{
+		Client:      mgr.GetClient(),
+		Scheme:      mgr.GetScheme(),
+		Credentials: credentials,
+		Shard:       shard,
+		ObserveOnly: observeOnly,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "AkamaiCertificate")
+		os.Exit(1)
+	}
+	if err = (&controllers.AkamaiDataStreamReconciler{
+		Client:      mgr.GetClient(),
+		Scheme:      mgr.GetScheme(),
+		Credentials: credentials,
+		Shard:       shard,
+		ObserveOnly: observeOnly,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "AkamaiDataStream")
+		os.Exit(1)
+	}
+	if err = (&controllers.AkamaiClientListReconciler{
+		Client:      mgr.GetClient(),
+		Scheme:      mgr.GetScheme(),
+		Credentials: credentials,
+		Shard:       shard,
+		ObserveOnly: observeOnly,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "AkamaiClientList")
+		os.Exit(1)
+	}
+	if ingressClass != "" {
+		if err = (&controllers.IngressReconciler{
+			Client:               mgr.GetClient(),
+			Scheme:               mgr.GetScheme(),
+			IngressClassName:     ingressClass,
+			ContractID:           ingressContractID,
+			GroupID:              ingressGroupID,
+			ProductID:            ingressProductID,
+			CPCodeID:             ingressCPCode,
+			OriginHostname:       ingressOriginHostname,
+			ActivationNetwork:    ingressActivationNetwork,
+			NotifyEmails:         splitList(ingressNotifyEmails),
+			EdgeHostnameTemplate: edgeHostnameTemplate,
+			AccountNamespaces:    splitList(accountAnnotationNamespaces),
+			PropagatedLabels:     splitList(propagatedLabels),
+			Recorder:             mgr.GetEventRecorder("akamai-operator"),
+			Shard:                shard,
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "Ingress")
+			os.Exit(1)
+		}
+	}
+	if gatewayControllerName != "" {
+		if err = (&controllers.GatewayClassReconciler{
+			Client:         mgr.GetClient(),
+			Scheme:         mgr.GetScheme(),
+			ControllerName: gatewayControllerName,
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "GatewayClass")
+			os.Exit(1)
+		}
+		if err = (&controllers.GatewayReconciler{
+			Client:               mgr.GetClient(),
+			Scheme:               mgr.GetScheme(),
+			ControllerName:       gatewayControllerName,
+			EdgeHostnameTemplate: edgeHostnameTemplate,
+			AccountNamespaces:    splitList(accountAnnotationNamespaces),
+			PropagatedLabels:     splitList(propagatedLabels),
+			Recorder:             mgr.GetEventRecorder("akamai-operator"),
+			Shard:                shard,
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "Gateway")
+			os.Exit(1)
+		}
+	}
+	if err = mgr.Add(drain); err != nil {
+		setupLog.Error(err, "unable to set up write drain")
+		os.Exit(1)
+	}
+	if mirrorAccount {
+		if err = mgr.Add(&controllers.AccountMirror{
+			Client:      mgr.GetClient(),
+			Credentials: credentials,
+			Interval:    mirrorInterval,
+		}); err != nil {
+			setupLog.Error(err, "unable to set up account mirror")
+			os.Exit(1)
+		}
+	}
+	if reportTraffic {
+		if err = mgr.Add(&controllers.TrafficReporter{
+			Client:      mgr.GetClient(),
+			Credentials: credentials,
+			Interval:    reportTrafficInterval,
+		}); err != nil {
+			setupLog.Error(err, "unable to set up traffic reporter")
+			os.Exit(1)
+		}
+	}
+	if inventoryAddr != "" {
+		token, err := os.ReadFile(inventoryTokenFile)
+		if err != nil || len(strings.TrimSpace(string(token))) == 0 {
+			setupLog.Error(err, "the inventory API needs a bearer token in --inventory-token-file")
+			os.Exit(1)
+		}
+		if err = mgr.Add(&controllers.InventoryServer{
+			Client:      mgr.GetClient(),
+			BindAddress: inventoryAddr,
+			Token:       strings.TrimSpace(string(token)),
+		}); err != nil {
+			setupLog.Error(err, "unable to set up inventory server")
+			os.Exit(1)
+		}
+	}
+	if adminAddr != "" {
+		token, err := os.ReadFile(adminTokenFile)
+		if err != nil || len(strings.TrimSpace(string(token))) == 0 {
+			setupLog.Error(err, "the admin API needs a bearer token in --admin-token-file")
+			os.Exit(1)
+		}
+		if err = mgr.Add(&controllers.AdminServer{
+			Client:      mgr.GetClient(),
+			BindAddress: adminAddr,
+			Token:       strings.TrimSpace(string(token)),
+		}); err != nil {
+			setupLog.Error(err, "unable to set up admin server")
+			os.Exit(1)
+		}
+	}
+	if externalDNSWebhookAddr != "" {
+		zones := splitList(strings.ToLower(externalDNSZones))
+		if len(zones) == 0 {
+			setupLog.Error(nil, "the external-dns webhook needs the zones it manages in --external-dns-zones")
+			os.Exit(1)
+		}
+		if err = mgr.Add(&controllers.ExternalDNSWebhook{
+			Credentials: credentials,
+			BindAddress: externalDNSWebhookAddr,
+			Zones:       zones,
+			ObserveOnly: observeOnly,
+		}); err != nil {
+			setupLog.Error(err, "unable to set up external-dns webhook")
+			os.Exit(1)
+		}
+	}
 	//+kubebuilder:scaffold:builder
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
@@ -88,3 +584,14 @@ func main() {
 		os.Exit(1)
 	}
 }
+
+// splitList splits a comma separated flag value, dropping empty entries
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,84 @@
+package akamai
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v8/pkg/papi"
+)
+
+// ListContracts retrieves all contracts available to the API client
+func (c *Client) ListContracts(ctx context.Context) ([]Contract, error) {
+	resp, err := c.papiClient.GetContracts(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list contracts: %w", err)
+	}
+
+	if resp == nil {
+		return []Contract{}, nil
+	}
+
+	contracts := make([]Contract, 0, len(resp.Contracts.Items))
+	for _, item := range resp.Contracts.Items {
+		if item == nil {
+			continue
+		}
+		contracts = append(contracts, Contract{
+			ContractID:       item.ContractID,
+			ContractTypeName: item.ContractTypeName,
+		})
+	}
+
+	return contracts, nil
+}
+
+// ListGroups retrieves all groups available to the API client
+func (c *Client) ListGroups(ctx context.Context) ([]Group, error) {
+	resp, err := c.papiClient.GetGroups(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list groups: %w", err)
+	}
+
+	if resp == nil {
+		return []Group{}, nil
+	}
+
+	groups := make([]Group, 0, len(resp.Groups.Items))
+	for _, item := range resp.Groups.Items {
+		if item == nil {
+			continue
+		}
+		groups = append(groups, Group{
+			GroupID:       item.GroupID,
+			GroupName:     item.GroupName,
+			ParentGroupID: item.ParentGroupID,
+			ContractIDs:   item.ContractIDs,
+		})
+	}
+
+	return groups, nil
+}
+
+// ListProducts retrieves the products available on a contract
+func (c *Client) ListProducts(ctx context.Context, contractID string) ([]Product, error) {
+	resp, err := c.papiClient.GetProducts(ctx, papi.GetProductsRequest{
+		ContractID: contractID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list products for contract %s: %w", contractID, err)
+	}
+
+	if resp == nil {
+		return []Product{}, nil
+	}
+
+	products := make([]Product, 0, len(resp.Products.Items))
+	for _, item := range resp.Products.Items {
+		products = append(products, Product{
+			ProductID:   item.ProductID,
+			ProductName: item.ProductName,
+		})
+	}
+
+	return products, nil
+}
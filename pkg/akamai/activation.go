@@ -2,7 +2,10 @@ package akamai
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v8/pkg/papi"
 	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
@@ -20,6 +23,7 @@ func (c *Client) ActivateProperty(ctx context.Context, propertyID string, versio
 			Network:                papi.ActivationNetwork(activationSpec.Network),
 			Note:                   activationSpec.Note,
 			NotifyEmails:           activationSpec.NotifyEmails,
+			AcknowledgeWarnings:    activationSpec.AcknowledgeWarnings,
 			AcknowledgeAllWarnings: activationSpec.AcknowledgeAllWarnings,
 			UseFastFallback:        activationSpec.UseFastFallback,
 		},
@@ -36,6 +40,9 @@ func (c *Client) ActivateProperty(ctx context.Context, propertyID string, versio
 	// Create the activation
 	activationResp, err := c.papiClient.CreateActivation(ctx, activationReq)
 	if err != nil {
+		if warnings := activationWarningsFromError(err); len(warnings) > 0 {
+			return "", &WarningsNotAcknowledgedError{Warnings: warnings}
+		}
 		return "", fmt.Errorf("failed to create activation: %w", err)
 	}
 
@@ -48,6 +55,86 @@ func (c *Client) ActivateProperty(ctx context.Context, propertyID string, versio
 	return activationID, nil
 }
 
+// DeactivateProperty deactivates the version of a property active on the specified network and
+// returns the ID of the deactivation
+func (c *Client) DeactivateProperty(ctx context.Context, propertyID string, version int, network string, notifyEmails []string, contractID, groupID string) (string, error) {
+	deactivationReq := papi.CreateActivationRequest{
+		PropertyID: propertyID,
+		ContractID: contractID,
+		GroupID:    groupID,
+		Activation: papi.Activation{
+			ActivationType:         papi.ActivationTypeDeactivate,
+			PropertyVersion:        version,
+			Network:                papi.ActivationNetwork(network),
+			NotifyEmails:           notifyEmails,
+			AcknowledgeAllWarnings: true,
+		},
+	}
+
+	deactivationResp, err := c.papiClient.CreateActivation(ctx, deactivationReq)
+	if err != nil {
+		return "", fmt.Errorf("failed to create deactivation: %w", err)
+	}
+	if deactivationResp == nil || deactivationResp.ActivationLink == "" {
+		return "", fmt.Errorf("invalid response from create activation API")
+	}
+	return extractActivationIDFromLink(deactivationResp.ActivationLink), nil
+}
+
+// CancelActivation cancels an activation that is still PENDING and returns its status afterwards,
+// e.g. ABORTED. Akamai rejects the cancellation once the activation is propagating.
+func (c *Client) CancelActivation(ctx context.Context, propertyID, activationID, contractID, groupID string) (string, error) {
+	cancelResp, err := c.papiClient.CancelActivation(ctx, papi.CancelActivationRequest{
+		PropertyID:   propertyID,
+		ActivationID: activationID,
+		ContractID:   contractID,
+		GroupID:      groupID,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to cancel activation: %w", err)
+	}
+	if cancelResp == nil || len(cancelResp.Activations.Items) == 0 {
+		return string(papi.ActivationStatusCancelling), nil
+	}
+	return string(cancelResp.Activations.Items[0].Status), nil
+}
+
+// WarningsNotAcknowledgedError is returned by ActivateProperty when Akamai rejects an activation
+// because of warnings that were neither acknowledged individually nor with acknowledgeAllWarnings
+type WarningsNotAcknowledgedError struct {
+	Warnings []ActivationWarning
+}
+
+func (e *WarningsNotAcknowledgedError) Error() string {
+	ids := make([]string, 0, len(e.Warnings))
+	for _, warning := range e.Warnings {
+		ids = append(ids, warning.MessageID)
+	}
+	return fmt.Sprintf("activation requires acknowledging %d warning(s): %s", len(e.Warnings), strings.Join(ids, ", "))
+}
+
+// activationWarningsFromError extracts the unacknowledged warnings from a CreateActivation error
+func activationWarningsFromError(err error) []ActivationWarning {
+	var apiErr *papi.Error
+	if !errors.As(err, &apiErr) || len(apiErr.Warnings) == 0 {
+		return nil
+	}
+
+	var warnings []ActivationWarning
+	if err := json.Unmarshal(apiErr.Warnings, &warnings); err != nil {
+		return nil
+	}
+
+	// Only warnings with a message ID can be acknowledged
+	unacknowledged := warnings[:0]
+	for _, warning := range warnings {
+		if warning.MessageID != "" {
+			unacknowledged = append(unacknowledged, warning)
+		}
+	}
+	return unacknowledged
+}
+
 // GetActivation retrieves the status of a property activation
 func (c *Client) GetActivation(ctx context.Context, propertyID, activationID string) (*Activation, error) {
 	// Get activation details
@@ -71,6 +158,7 @@ func (c *Client) GetActivation(ctx context.Context, propertyID, activationID str
 		PropertyID:      papiActivation.PropertyID,
 		PropertyVersion: papiActivation.PropertyVersion,
 		Network:         string(papiActivation.Network),
+		ActivationType:  string(papiActivation.ActivationType),
 		Status:          string(papiActivation.Status),
 		SubmitDate:      papiActivation.SubmitDate,
 		UpdateDate:      papiActivation.UpdateDate,
@@ -105,6 +193,7 @@ func (c *Client) ListActivations(ctx context.Context, propertyID string) ([]Acti
 			PropertyID:      papiActivation.PropertyID,
 			PropertyVersion: papiActivation.PropertyVersion,
 			Network:         string(papiActivation.Network),
+			ActivationType:  string(papiActivation.ActivationType),
 			Status:          string(papiActivation.Status),
 			SubmitDate:      papiActivation.SubmitDate,
 			UpdateDate:      papiActivation.UpdateDate,
@@ -0,0 +1,123 @@
+package akamai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v8/pkg/papi"
+)
+
+func TestActivationWarningsFromError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected []string
+	}{
+		{
+			name:     "not an API error",
+			err:      errors.New("connection refused"),
+			expected: nil,
+		},
+		{
+			name:     "API error without warnings",
+			err:      &papi.Error{Type: "bad-request", StatusCode: 400},
+			expected: nil,
+		},
+		{
+			name: "wrapped warnings, entries without message ID skipped",
+			err: fmt.Errorf("create activation: %w", &papi.Error{
+				Type:       "https://problems.luna.akamaiapis.net/papi/v0/activation-warnings-not-acknowledged",
+				StatusCode: 400,
+				Warnings: json.RawMessage(`[
+					{"type": "validation_message.ssl_custom_cert_expiring", "messageId": "msg_1", "title": "Certificate expiring", "detail": "expires in 10 days"},
+					{"type": "informational", "title": "no message ID"},
+					{"type": "validation_message.origin_unreachable", "messageId": "msg_2", "title": "Origin unreachable"}
+				]`),
+			}),
+			expected: []string{"msg_1", "msg_2"},
+		},
+		{
+			name:     "malformed warnings",
+			err:      &papi.Error{Warnings: json.RawMessage(`{"not": "a list"}`)},
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			warnings := activationWarningsFromError(tt.err)
+			if len(warnings) != len(tt.expected) {
+				t.Fatalf("activationWarningsFromError() returned %d warnings, expected %d", len(warnings), len(tt.expected))
+			}
+			for i, warning := range warnings {
+				if warning.MessageID != tt.expected[i] {
+					t.Errorf("warning %d message ID = %q, expected %q", i, warning.MessageID, tt.expected[i])
+				}
+			}
+		})
+	}
+}
+
+func TestWarningsNotAcknowledgedError(t *testing.T) {
+	err := &WarningsNotAcknowledgedError{Warnings: []ActivationWarning{{MessageID: "msg_1"}, {MessageID: "msg_2"}}}
+	expected := "activation requires acknowledging 2 warning(s): msg_1, msg_2"
+	if err.Error() != expected {
+		t.Errorf("Error() = %q, expected %q", err.Error(), expected)
+	}
+}
+
+// activationPAPI stubs the PAPI activation endpoints and records the requests
+type activationPAPI struct {
+	papi.PAPI
+	request       papi.CreateActivationRequest
+	cancelRequest papi.CancelActivationRequest
+}
+
+func (a *activationPAPI) CancelActivation(_ context.Context, request papi.CancelActivationRequest) (*papi.CancelActivationResponse, error) {
+	a.cancelRequest = request
+	return &papi.CancelActivationResponse{Activations: papi.ActivationsItems{Items: []*papi.Activation{{
+		ActivationID: request.ActivationID,
+		Status:       papi.ActivationStatusAborted,
+	}}}}, nil
+}
+
+func (a *activationPAPI) CreateActivation(_ context.Context, request papi.CreateActivationRequest) (*papi.CreateActivationResponse, error) {
+	a.request = request
+	return &papi.CreateActivationResponse{ActivationLink: "/papi/v1/properties/prp_1/activations/atv_9?contractId=ctr_1&groupId=grp_1"}, nil
+}
+
+func TestDeactivateProperty(t *testing.T) {
+	stub := &activationPAPI{}
+	c := &Client{papiClient: stub}
+
+	activationID, err := c.DeactivateProperty(context.Background(), "prp_1", 4, "PRODUCTION", []string{"team@example.com"}, "ctr_1", "grp_1")
+	if err != nil {
+		t.Fatalf("DeactivateProperty() error = %v", err)
+	}
+	if activationID != "atv_9" {
+		t.Errorf("activationID = %q, expected atv_9", activationID)
+	}
+	activation := stub.request.Activation
+	if activation.ActivationType != papi.ActivationTypeDeactivate || activation.PropertyVersion != 4 || activation.Network != papi.ActivationNetworkProduction {
+		t.Errorf("request = %+v, expected a deactivation of version 4 on production", activation)
+	}
+}
+
+func TestCancelActivation(t *testing.T) {
+	stub := &activationPAPI{}
+	c := &Client{papiClient: stub}
+
+	status, err := c.CancelActivation(context.Background(), "prp_1", "atv_9", "ctr_1", "grp_1")
+	if err != nil {
+		t.Fatalf("CancelActivation() error = %v", err)
+	}
+	if status != "ABORTED" || !ActivationAborted(status) || !ActivationFinished(status) {
+		t.Errorf("status = %q, expected a finished ABORTED activation", status)
+	}
+	if stub.cancelRequest.ActivationID != "atv_9" || stub.cancelRequest.PropertyID != "prp_1" || stub.cancelRequest.ContractID != "ctr_1" {
+		t.Errorf("request = %+v, expected the cancellation of atv_9 of prp_1", stub.cancelRequest)
+	}
+}
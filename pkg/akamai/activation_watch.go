@@ -0,0 +1,245 @@
+package akamai
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Defaults of PollOptions
+const (
+	defaultPollInterval       = 30 * time.Second
+	defaultMaxPollInterval    = 2 * time.Minute
+	defaultMaxPollErrors      = 5
+	pollIntervalBackoffFactor = 2
+)
+
+// ActivationGetter reads the current state of an activation, e.g. of a property, an include or a
+// security configuration
+type ActivationGetter func(ctx context.Context) (*Activation, error)
+
+// ActivationUpdate is a change of an activation seen by a poller. Err is set when the activation
+// could not be read; the last update of a poller that gave up carries the last error.
+type ActivationUpdate struct {
+	Activation *Activation
+	Err        error
+}
+
+// PollOptions configures how an activation is polled
+type PollOptions struct {
+	// Interval is the delay before the second poll; it doubles while the status is unchanged and
+	// after errors. Defaults to 30 seconds.
+	Interval time.Duration
+
+	// MaxInterval bounds the delay between polls. Defaults to 2 minutes.
+	MaxInterval time.Duration
+
+	// MaxErrors is the number of consecutive failed polls after which the poller gives up.
+	// Defaults to 5.
+	MaxErrors int
+}
+
+// withDefaults fills in the unset options
+func (o PollOptions) withDefaults() PollOptions {
+	if o.Interval <= 0 {
+		o.Interval = defaultPollInterval
+	}
+	if o.MaxInterval <= 0 {
+		o.MaxInterval = defaultMaxPollInterval
+	}
+	if o.MaxInterval < o.Interval {
+		o.MaxInterval = o.Interval
+	}
+	if o.MaxErrors <= 0 {
+		o.MaxErrors = defaultMaxPollErrors
+	}
+	return o
+}
+
+// ActivationFinished reports whether an activation status is final
+func ActivationFinished(status string) bool {
+	switch status {
+	case "ACTIVE", "FAILED", "ABORTED", "INACTIVE", "DEACTIVATED":
+		return true
+	default:
+		return false
+	}
+}
+
+// ActivationCancellable reports whether an activation with the status can still be cancelled
+func ActivationCancellable(status string) bool {
+	return status == "PENDING"
+}
+
+// ActivationAborted reports whether an activation status belongs to a cancelled activation
+func ActivationAborted(status string) bool {
+	return status == "ABORTED" || status == "PENDING_CANCELLATION"
+}
+
+// PollActivation polls an activation until it reaches a final status and sends every status
+// change on the returned channel. The channel is closed after the final status, when ctx is
+// canceled or after MaxErrors consecutive failed polls; failed polls are sent as well. The first
+// poll happens immediately, later ones back off while nothing changes.
+func PollActivation(ctx context.Context, get ActivationGetter, options PollOptions) <-chan ActivationUpdate {
+	options = options.withDefaults()
+	updates := make(chan ActivationUpdate, 1)
+	go func() {
+		defer close(updates)
+		interval := options.Interval
+		lastStatus := ""
+		failures := 0
+		for {
+			activation, err := get(ctx)
+			if ctx.Err() != nil {
+				return
+			}
+			send := false
+			if err != nil {
+				failures++
+				send = true
+			} else {
+				failures = 0
+				if activation.Status != lastStatus {
+					lastStatus = activation.Status
+					interval = options.Interval
+					send = true
+				}
+			}
+			if send {
+				select {
+				case updates <- ActivationUpdate{Activation: activation, Err: err}:
+				case <-ctx.Done():
+					return
+				}
+			}
+			if (err == nil && ActivationFinished(activation.Status)) || failures >= options.MaxErrors {
+				return
+			}
+
+			timer := time.NewTimer(interval)
+			select {
+			case <-timer.C:
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			}
+			if !send || err != nil {
+				interval = min(interval*pollIntervalBackoffFactor, options.MaxInterval)
+			}
+		}
+	}()
+	return updates
+}
+
+// WatchActivation polls an activation of a property with PollActivation
+func (c *Client) WatchActivation(ctx context.Context, propertyID, activationID string, options PollOptions) <-chan ActivationUpdate {
+	return PollActivation(ctx, func(ctx context.Context) (*Activation, error) {
+		return c.GetActivation(ctx, propertyID, activationID)
+	}, options)
+}
+
+// ActivationWatcher shares the pollers of activations among subscribers: every subscriber of an
+// activation receives its updates, while it is only polled once. A poller stops when its last
+// subscriber leaves or the activation reaches a final status.
+type ActivationWatcher struct {
+	// Options configures the pollers
+	Options PollOptions
+
+	mu      sync.Mutex
+	watches map[string]*activationWatch
+}
+
+// activationWatch is the poller of an activation and its subscribers
+type activationWatch struct {
+	cancel      context.CancelFunc
+	done        chan struct{}
+	subscribers map[chan ActivationUpdate]struct{}
+	last        *ActivationUpdate
+}
+
+// Subscribe returns a channel receiving the updates of the activation identified by key,
+// starting a poller with get unless the activation is already watched. A late subscriber first
+// receives the last update. The channel is closed when the poller finishes or ctx is canceled.
+func (w *ActivationWatcher) Subscribe(ctx context.Context, key string, get ActivationGetter) <-chan ActivationUpdate {
+	subscriber := make(chan ActivationUpdate, 1)
+
+	w.mu.Lock()
+	if w.watches == nil {
+		w.watches = make(map[string]*activationWatch)
+	}
+	watch, ok := w.watches[key]
+	if !ok {
+		pollCtx, cancel := context.WithCancel(context.Background())
+		watch = &activationWatch{cancel: cancel, done: make(chan struct{}), subscribers: make(map[chan ActivationUpdate]struct{})}
+		w.watches[key] = watch
+		go w.run(key, watch, PollActivation(pollCtx, get, w.Options))
+	}
+	watch.subscribers[subscriber] = struct{}{}
+	if watch.last != nil {
+		subscriber <- *watch.last
+	}
+	w.mu.Unlock()
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			w.unsubscribe(key, watch, subscriber)
+		case <-watch.done:
+		}
+	}()
+	return subscriber
+}
+
+// Watching reports whether the activation identified by key is polled
+func (w *ActivationWatcher) Watching(key string) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	_, ok := w.watches[key]
+	return ok
+}
+
+// run forwards the updates of a poller to the subscribers of the activation
+func (w *ActivationWatcher) run(key string, watch *activationWatch, updates <-chan ActivationUpdate) {
+	for update := range updates {
+		w.mu.Lock()
+		watch.last = &update
+		for subscriber := range watch.subscribers {
+			// Subscribers only need the latest state, so an unread update is replaced
+			select {
+			case <-subscriber:
+			default:
+			}
+			subscriber <- update
+		}
+		w.mu.Unlock()
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for subscriber := range watch.subscribers {
+		close(subscriber)
+	}
+	watch.subscribers = nil
+	if w.watches[key] == watch {
+		delete(w.watches, key)
+	}
+	watch.cancel()
+	close(watch.done)
+}
+
+// unsubscribe removes a subscriber, stopping the poller when it was the last one
+func (w *ActivationWatcher) unsubscribe(key string, watch *activationWatch, subscriber chan ActivationUpdate) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if _, ok := watch.subscribers[subscriber]; !ok {
+		return
+	}
+	delete(watch.subscribers, subscriber)
+	close(subscriber)
+	if len(watch.subscribers) == 0 {
+		if w.watches[key] == watch {
+			delete(w.watches, key)
+		}
+		watch.cancel()
+	}
+}
@@ -0,0 +1,164 @@
+package akamai
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// testPollOptions polls without noticeable delays
+var testPollOptions = PollOptions{Interval: time.Millisecond, MaxInterval: 2 * time.Millisecond, MaxErrors: 3}
+
+// collectUpdates reads the updates of a poller until it closes the channel
+func collectUpdates(t *testing.T, updates <-chan ActivationUpdate) []string {
+	t.Helper()
+	var seen []string
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case update, ok := <-updates:
+			if !ok {
+				return seen
+			}
+			if update.Err != nil {
+				seen = append(seen, "error")
+			} else {
+				seen = append(seen, update.Activation.Status)
+			}
+		case <-timeout:
+			t.Fatalf("poller did not finish, got %v", seen)
+		}
+	}
+}
+
+func TestPollActivation(t *testing.T) {
+	tests := []struct {
+		name     string
+		results  []string
+		expected []string
+	}{
+		{
+			name:     "status changes until active",
+			results:  []string{"PENDING", "PENDING", "ACTIVATING", "error", "ACTIVE", "ACTIVE"},
+			expected: []string{"PENDING", "ACTIVATING", "error", "ACTIVE"},
+		},
+		{
+			name:     "failed activation",
+			results:  []string{"PENDING", "FAILED"},
+			expected: []string{"PENDING", "FAILED"},
+		},
+		{
+			name:     "gives up after consecutive errors",
+			results:  []string{"PENDING", "error", "error", "error", "ACTIVE"},
+			expected: []string{"PENDING", "error", "error", "error"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var polls atomic.Int32
+			get := func(context.Context) (*Activation, error) {
+				result := tt.results[min(int(polls.Add(1)), len(tt.results))-1]
+				if result == "error" {
+					return nil, errors.New("connection reset")
+				}
+				return &Activation{ActivationID: "atv_1", Status: result}, nil
+			}
+
+			if got := collectUpdates(t, PollActivation(context.Background(), get, testPollOptions)); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("updates = %v, expected %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestPollActivationCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	get := func(context.Context) (*Activation, error) {
+		return &Activation{Status: "PENDING"}, nil
+	}
+	updates := PollActivation(ctx, get, testPollOptions)
+	if update := <-updates; update.Activation.Status != "PENDING" {
+		t.Fatalf("unexpected first update %+v", update)
+	}
+	cancel()
+	if got := collectUpdates(t, updates); len(got) != 0 {
+		t.Errorf("expected no updates after cancellation, got %v", got)
+	}
+}
+
+func TestActivationWatcher(t *testing.T) {
+	watcher := &ActivationWatcher{Options: testPollOptions}
+	statuses := make(chan string)
+	var polls atomic.Int32
+	status := "PENDING"
+	get := func(ctx context.Context) (*Activation, error) {
+		polls.Add(1)
+		select {
+		case status = <-statuses:
+		default:
+		}
+		return &Activation{Status: status}, nil
+	}
+
+	first := watcher.Subscribe(context.Background(), "prp_1/atv_1", get)
+	if update := <-first; update.Activation.Status != "PENDING" {
+		t.Fatalf("unexpected first update %+v", update)
+	}
+
+	// A late subscriber shares the poller and starts with the last update
+	second := watcher.Subscribe(context.Background(), "prp_1/atv_1", func(context.Context) (*Activation, error) {
+		t.Error("expected the activation to be polled once")
+		return nil, errors.New("unexpected poll")
+	})
+	if update := <-second; update.Activation.Status != "PENDING" {
+		t.Fatalf("unexpected update of the late subscriber %+v", update)
+	}
+
+	// A subscriber that leaves stops receiving updates without stopping the poller
+	ctx, cancel := context.WithCancel(context.Background())
+	third := watcher.Subscribe(ctx, "prp_1/atv_1", get)
+	<-third
+	cancel()
+	if got := collectUpdates(t, third); len(got) != 0 {
+		t.Errorf("expected no updates after leaving, got %v", got)
+	}
+	if !watcher.Watching("prp_1/atv_1") {
+		t.Fatal("expected the activation to be watched")
+	}
+
+	statuses <- "ACTIVE"
+	for _, subscriber := range []<-chan ActivationUpdate{first, second} {
+		if got := collectUpdates(t, subscriber); !reflect.DeepEqual(got, []string{"ACTIVE"}) {
+			t.Errorf("updates = %v, expected [ACTIVE]", got)
+		}
+	}
+	if watcher.Watching("prp_1/atv_1") {
+		t.Error("expected the finished activation not to be watched")
+	}
+}
+
+func TestActivationWatcherStopsWithoutSubscribers(t *testing.T) {
+	watcher := &ActivationWatcher{Options: testPollOptions}
+	var polls atomic.Int32
+	ctx, cancel := context.WithCancel(context.Background())
+	updates := watcher.Subscribe(ctx, "prp_1/atv_1", func(context.Context) (*Activation, error) {
+		polls.Add(1)
+		return &Activation{Status: "PENDING"}, nil
+	})
+	<-updates
+	cancel()
+	collectUpdates(t, updates)
+
+	if watcher.Watching("prp_1/atv_1") {
+		t.Fatal("expected the activation not to be watched without subscribers")
+	}
+	stopped := polls.Load()
+	time.Sleep(20 * time.Millisecond)
+	if polls.Load() > stopped+1 {
+		t.Errorf("expected polling to stop, got %d more polls", polls.Load()-stopped)
+	}
+}
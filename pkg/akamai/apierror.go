@@ -0,0 +1,164 @@
+package akamai
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v8/pkg/papi"
+)
+
+// maxErrorBodySize bounds the error response body read from a failed request
+const maxErrorBodySize = 64 << 10
+
+// maxProblemsInMessage bounds the individual problems listed in the message of an API error
+const maxProblemsInMessage = 3
+
+// APIError is a failed Akamai API response in the problem details format (RFC 7807). Instance,
+// RequestInstance and RequestID identify the failed request and are what Akamai support asks for.
+type APIError struct {
+	StatusCode      int    `json:"status,omitempty"`
+	Type            string `json:"type,omitempty"`
+	Title           string `json:"title,omitempty"`
+	Detail          string `json:"detail,omitempty"`
+	Instance        string `json:"instance,omitempty"`
+	RequestInstance string `json:"requestInstance,omitempty"`
+	RequestID       string `json:"requestId,omitempty"`
+
+	// BehaviorName and ErrorLocation point to the offending part of a rule tree
+	BehaviorName  string `json:"behaviorName,omitempty"`
+	ErrorLocation string `json:"errorLocation,omitempty"`
+
+	// Errors are the individual problems, e.g. every invalid behavior of a rule tree. Entries
+	// that are not problem details are dropped.
+	Errors []Problem `json:"-"`
+}
+
+// Problem is one of the individual problems of an API error
+type Problem struct {
+	Type          string `json:"type,omitempty"`
+	Title         string `json:"title,omitempty"`
+	Detail        string `json:"detail,omitempty"`
+	BehaviorName  string `json:"behaviorName,omitempty"`
+	ErrorLocation string `json:"errorLocation,omitempty"`
+}
+
+// String renders the explanation of the problem with the behavior and rule tree location it
+// concerns, e.g. `behavior "origin" at #/rules/behaviors/0: hostname is required`
+func (p Problem) String() string {
+	explanation := p.Detail
+	if explanation == "" {
+		explanation = p.Title
+	}
+	var subject []string
+	if p.BehaviorName != "" {
+		subject = append(subject, fmt.Sprintf("behavior %q", p.BehaviorName))
+	}
+	if p.ErrorLocation != "" {
+		subject = append(subject, "at "+p.ErrorLocation)
+	}
+	if len(subject) == 0 {
+		return explanation
+	}
+	if explanation == "" {
+		return strings.Join(subject, " ")
+	}
+	return strings.Join(subject, " ") + ": " + explanation
+}
+
+// Error renders the status, the explanation of the API and the first individual problems
+func (e *APIError) Error() string {
+	message := fmt.Sprintf("unexpected status %d", e.StatusCode)
+	explanation := Problem{Title: e.Title, Detail: e.Detail, BehaviorName: e.BehaviorName, ErrorLocation: e.ErrorLocation}.String()
+	if explanation != "" {
+		message += ": " + explanation
+	}
+	for i, problem := range e.Errors {
+		if i == maxProblemsInMessage {
+			message += fmt.Sprintf("; and %d more problems", len(e.Errors)-maxProblemsInMessage)
+			break
+		}
+		message += "; " + problem.String()
+	}
+	if e.Instance != "" {
+		message += " (instance " + e.Instance + ")"
+	}
+	return message
+}
+
+// newAPIError reads the problem details of a failed response of a request made outside the papi
+// package. Bodies that are not problem details only contribute the status code.
+func newAPIError(resp *http.Response) *APIError {
+	apiErr := &APIError{}
+	if resp.Body != nil {
+		if body, err := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize)); err == nil {
+			var problems struct {
+				Errors json.RawMessage `json:"errors"`
+			}
+			if json.Unmarshal(body, apiErr) == nil && json.Unmarshal(body, &problems) == nil {
+				apiErr.Errors = parseProblems(problems.Errors)
+			}
+		}
+	}
+	apiErr.StatusCode = resp.StatusCode
+	return apiErr
+}
+
+// parseProblems reads the errors array of problem details, keeping the entries that are objects
+func parseProblems(raw json.RawMessage) []Problem {
+	var entries []json.RawMessage
+	if len(raw) == 0 || json.Unmarshal(raw, &entries) != nil {
+		return nil
+	}
+	var problems []Problem
+	for _, entry := range entries {
+		var problem Problem
+		if json.Unmarshal(entry, &problem) == nil && problem != (Problem{}) {
+			problems = append(problems, problem)
+		}
+	}
+	return problems
+}
+
+// AsAPIError returns the Akamai API error response wrapped in err, both of the papi package and
+// of requests made outside of it
+func AsAPIError(err error) (*APIError, bool) {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr, true
+	}
+	var papiErr *papi.Error
+	if errors.As(err, &papiErr) {
+		return fromPAPIError(papiErr), true
+	}
+	return nil, false
+}
+
+// fromPAPIError converts an error of the papi package
+func fromPAPIError(papiErr *papi.Error) *APIError {
+	return &APIError{
+		StatusCode:    papiErr.StatusCode,
+		Type:          papiErr.Type,
+		Title:         papiErr.Title,
+		Detail:        papiErr.Detail,
+		Instance:      papiErr.Instance,
+		BehaviorName:  papiErr.BehaviorName,
+		ErrorLocation: papiErr.ErrorLocation,
+		Errors:        parseProblems(papiErr.Errors),
+	}
+}
+
+// ErrorMessage renders err for status messages and events. The papi package renders its errors
+// as the whole response body in indented JSON; that part of the message is replaced by the
+// explanation and the individual problems of the response.
+func ErrorMessage(err error) string {
+	message := err.Error()
+	var papiErr *papi.Error
+	if errors.As(err, &papiErr) {
+		message = strings.Replace(message, papiErr.Error(), fromPAPIError(papiErr).Error(), 1)
+	}
+	return message
+}
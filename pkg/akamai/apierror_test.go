@@ -0,0 +1,114 @@
+package akamai
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v8/pkg/papi"
+)
+
+func TestAsAPIError(t *testing.T) {
+	response := func(status int, body string) *http.Response {
+		return &http.Response{StatusCode: status, Body: io.NopCloser(strings.NewReader(body))}
+	}
+
+	tests := []struct {
+		name     string
+		err      error
+		expected *APIError
+		message  string
+	}{
+		{
+			name: "problem details of a raw request",
+			err: fmt.Errorf("failed to delete edge hostname: %w", newAPIError(response(http.StatusConflict,
+				`{"type": "/hapi/problems/conflict", "title": "Conflict", "detail": "edge hostname in use", "instance": "/hapi/error-instances/abc", "requestInstance": "req-123"}`))),
+			expected: &APIError{StatusCode: http.StatusConflict, Type: "/hapi/problems/conflict", Title: "Conflict", Detail: "edge hostname in use",
+				Instance: "/hapi/error-instances/abc", RequestInstance: "req-123"},
+			message: "failed to delete edge hostname: unexpected status 409: edge hostname in use (instance /hapi/error-instances/abc)",
+		},
+		{
+			name:     "body without problem details",
+			err:      newAPIError(response(http.StatusBadGateway, "<html>bad gateway</html>")),
+			expected: &APIError{StatusCode: http.StatusBadGateway},
+			message:  "unexpected status 502",
+		},
+		{
+			name: "papi error",
+			err: fmt.Errorf("failed to update rules: %w", &papi.Error{StatusCode: http.StatusBadRequest, Type: "https://problems.luna.akamaiapis.net/papi/v0/json-schema-invalid",
+				Title: "Invalid rule tree", Instance: "https://akaa-xyz.luna.akamaiapis.net/papi/v1/properties/prp_1#8a2b"}),
+			expected: &APIError{StatusCode: http.StatusBadRequest, Type: "https://problems.luna.akamaiapis.net/papi/v0/json-schema-invalid",
+				Title: "Invalid rule tree", Instance: "https://akaa-xyz.luna.akamaiapis.net/papi/v1/properties/prp_1#8a2b"},
+		},
+		{
+			name: "individual problems of a raw request",
+			err: newAPIError(response(http.StatusBadRequest, `{"type": "/papi/v1/errors/validation", "title": "Validation error", "requestId": "r-42",
+				"errors": [{"type": "/papi/v1/errors/missing_option", "behaviorName": "origin", "errorLocation": "#/rules/behaviors/0", "detail": "hostname is required"}, "unexpected", {}]}`)),
+			expected: &APIError{StatusCode: http.StatusBadRequest, Type: "/papi/v1/errors/validation", Title: "Validation error", RequestID: "r-42",
+				Errors: []Problem{{Type: "/papi/v1/errors/missing_option", BehaviorName: "origin", ErrorLocation: "#/rules/behaviors/0", Detail: "hostname is required"}}},
+			message: `unexpected status 400: Validation error; behavior "origin" at #/rules/behaviors/0: hostname is required`,
+		},
+		{
+			name: "papi error with individual problems",
+			err: &papi.Error{StatusCode: http.StatusBadRequest, Title: "Invalid rule tree", BehaviorName: "caching", ErrorLocation: "#/rules/children/1/behaviors/0",
+				Errors: json.RawMessage(`[{"title": "Invalid TTL", "errorLocation": "#/rules/children/1/behaviors/0/options/ttl"}]`)},
+			expected: &APIError{StatusCode: http.StatusBadRequest, Title: "Invalid rule tree", BehaviorName: "caching", ErrorLocation: "#/rules/children/1/behaviors/0",
+				Errors: []Problem{{Title: "Invalid TTL", ErrorLocation: "#/rules/children/1/behaviors/0/options/ttl"}}},
+		},
+		{
+			name: "not an API error",
+			err:  fmt.Errorf("connection refused"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := AsAPIError(tt.err)
+			if ok != (tt.expected != nil) {
+				t.Fatalf("AsAPIError() ok = %v", ok)
+			}
+			if tt.expected != nil && !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("AsAPIError() = %+v, expected %+v", got, tt.expected)
+			}
+			if tt.message != "" && tt.err.Error() != tt.message {
+				t.Errorf("Error() = %q, expected %q", tt.err.Error(), tt.message)
+			}
+		})
+	}
+}
+
+func TestErrorMessage(t *testing.T) {
+	problems := make([]string, 0, 5)
+	for i := 0; i < 5; i++ {
+		problems = append(problems, fmt.Sprintf(`{"behaviorName": "b%d", "detail": "invalid"}`, i))
+	}
+	tests := []struct {
+		name     string
+		err      error
+		expected string
+	}{
+		{
+			name: "papi error",
+			err: fmt.Errorf("failed to update rules: %w", &papi.Error{StatusCode: http.StatusBadRequest, Type: "https://problems.luna.akamaiapis.net/papi/v0/json-schema-invalid",
+				Title: "Invalid rule tree", Instance: "#8a2b", Errors: json.RawMessage("[" + strings.Join(problems, ",") + "]")}),
+			expected: `failed to update rules: unexpected status 400: Invalid rule tree; behavior "b0": invalid; behavior "b1": invalid; behavior "b2": invalid; and 2 more problems (instance #8a2b)`,
+		},
+		{
+			name:     "other error",
+			err:      fmt.Errorf("failed to render rules: %w", fmt.Errorf("template error")),
+			expected: "failed to render rules: template error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ErrorMessage(tt.err); got != tt.expected {
+				t.Errorf("ErrorMessage() = %q, expected %q", got, tt.expected)
+			}
+		})
+	}
+}
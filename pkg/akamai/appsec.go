@@ -0,0 +1,395 @@
+package akamai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v8/pkg/appsec"
+)
+
+// AppSecMatchTarget is a website match target assigning hostnames and paths to a security policy
+type AppSecMatchTarget struct {
+	ID        int
+	PolicyID  string
+	Hostnames []string
+	Paths     []string
+}
+
+// FindAppSecConfig returns the ID of the security configuration with the given name, or 0 when
+// there is none
+func (c *Client) FindAppSecConfig(ctx context.Context, name string) (int, error) {
+	resp, err := c.appsecClient.GetConfigurations(ctx, appsec.GetConfigurationsRequest{Name: name})
+	if err != nil {
+		return 0, fmt.Errorf("failed to list security configurations: %w", err)
+	}
+	for _, config := range resp.Configurations {
+		if config.Name == name {
+			return config.ID, nil
+		}
+	}
+	return 0, nil
+}
+
+// GetAppSecConfig retrieves a security configuration with its latest and active versions,
+// returning nil when it doesn't exist
+func (c *Client) GetAppSecConfig(ctx context.Context, configID int) (*appsec.GetConfigurationResponse, error) {
+	config, err := c.appsecClient.GetConfiguration(ctx, appsec.GetConfigurationRequest{ConfigID: configID})
+	if err != nil {
+		if isAppSecNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get security configuration %d: %w", configID, err)
+	}
+	return config, nil
+}
+
+// CreateAppSecConfig creates a security configuration protecting the hostnames and returns its ID
+func (c *Client) CreateAppSecConfig(ctx context.Context, config appsec.CreateConfigurationRequest) (int, error) {
+	resp, err := c.appsecClient.CreateConfiguration(ctx, config)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create security configuration %s: %w", config.Name, err)
+	}
+	return resp.ConfigID, nil
+}
+
+// DeleteAppSecConfig deletes a security configuration; a configuration that doesn't exist
+// anymore is not an error. Akamai refuses configurations that are active.
+func (c *Client) DeleteAppSecConfig(ctx context.Context, configID int) error {
+	if _, err := c.appsecClient.RemoveConfiguration(ctx, appsec.RemoveConfigurationRequest{ConfigID: configID}); err != nil && !isAppSecNotFound(err) {
+		return fmt.Errorf("failed to delete security configuration %d: %w", configID, err)
+	}
+	return nil
+}
+
+// CloneAppSecVersion creates a new version of a security configuration from an existing one and
+// returns its number
+func (c *Client) CloneAppSecVersion(ctx context.Context, configID, fromVersion int) (int, error) {
+	resp, err := c.appsecClient.CreateConfigurationVersionClone(ctx, appsec.CreateConfigurationVersionCloneRequest{
+		ConfigID:          configID,
+		CreateFromVersion: fromVersion,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to create a version of security configuration %d from version %d: %w", configID, fromVersion, err)
+	}
+	return resp.Version, nil
+}
+
+// GetAppSecPolicies returns the IDs of the security policies of a version by name
+func (c *Client) GetAppSecPolicies(ctx context.Context, configID, version int) (map[string]string, error) {
+	resp, err := c.appsecClient.GetSecurityPolicies(ctx, appsec.GetSecurityPoliciesRequest{ConfigID: configID, Version: version})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list security policies of configuration %d version %d: %w", configID, version, err)
+	}
+	policies := make(map[string]string, len(resp.Policies))
+	for _, policy := range resp.Policies {
+		policies[policy.PolicyName] = policy.PolicyID
+	}
+	return policies, nil
+}
+
+// CreateAppSecPolicy creates a security policy with the default settings and returns its ID
+func (c *Client) CreateAppSecPolicy(ctx context.Context, configID, version int, name, prefix string) (string, error) {
+	resp, err := c.appsecClient.CreateSecurityPolicy(ctx, appsec.CreateSecurityPolicyRequest{
+		ConfigID:        configID,
+		Version:         version,
+		PolicyName:      name,
+		PolicyPrefix:    prefix,
+		DefaultSettings: true,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to create security policy %s: %w", name, err)
+	}
+	return resp.PolicyID, nil
+}
+
+// GetWAFMode returns the WAF mode of a security policy
+func (c *Client) GetWAFMode(ctx context.Context, configID, version int, policyID string) (string, error) {
+	resp, err := c.appsecClient.GetWAFMode(ctx, appsec.GetWAFModeRequest{ConfigID: configID, Version: version, PolicyID: policyID})
+	if err != nil {
+		return "", fmt.Errorf("failed to get WAF mode of security policy %s: %w", policyID, err)
+	}
+	return resp.Mode, nil
+}
+
+// UpdateWAFMode sets the WAF mode of a security policy
+func (c *Client) UpdateWAFMode(ctx context.Context, configID, version int, policyID, mode string) error {
+	if _, err := c.appsecClient.UpdateWAFMode(ctx, appsec.UpdateWAFModeRequest{ConfigID: configID, Version: version, PolicyID: policyID, Mode: mode}); err != nil {
+		return fmt.Errorf("failed to set WAF mode of security policy %s: %w", policyID, err)
+	}
+	return nil
+}
+
+// GetRuleActions returns the actions of the WAF rules of a security policy by rule ID
+func (c *Client) GetRuleActions(ctx context.Context, configID, version int, policyID string) (map[int]string, error) {
+	resp, err := c.appsecClient.GetRules(ctx, appsec.GetRulesRequest{ConfigID: configID, Version: version, PolicyID: policyID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rule actions of security policy %s: %w", policyID, err)
+	}
+	actions := make(map[int]string, len(resp.Rules))
+	for _, rule := range resp.Rules {
+		actions[rule.ID] = rule.Action
+	}
+	return actions, nil
+}
+
+// UpdateRuleAction sets the action of a WAF rule of a security policy
+func (c *Client) UpdateRuleAction(ctx context.Context, configID, version int, policyID string, ruleID int, action string) error {
+	if _, err := c.appsecClient.UpdateRule(ctx, appsec.UpdateRuleRequest{
+		ConfigID: configID, Version: version, PolicyID: policyID, RuleID: ruleID, Action: action,
+	}); err != nil {
+		return fmt.Errorf("failed to set action of rule %d of security policy %s: %w", ruleID, policyID, err)
+	}
+	return nil
+}
+
+// GetAppSecHostnames returns the hostnames a version protects
+func (c *Client) GetAppSecHostnames(ctx context.Context, configID, version int) ([]string, error) {
+	resp, err := c.appsecClient.GetSelectedHostnames(ctx, appsec.GetSelectedHostnamesRequest{ConfigID: configID, Version: version})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get hostnames of security configuration %d version %d: %w", configID, version, err)
+	}
+	hostnames := make([]string, 0, len(resp.HostnameList))
+	for _, hostname := range resp.HostnameList {
+		hostnames = append(hostnames, hostname.Hostname)
+	}
+	return hostnames, nil
+}
+
+// UpdateAppSecHostnames replaces the hostnames a version protects
+func (c *Client) UpdateAppSecHostnames(ctx context.Context, configID, version int, hostnames []string) error {
+	hostnameList := make([]appsec.Hostname, 0, len(hostnames))
+	for _, hostname := range hostnames {
+		hostnameList = append(hostnameList, appsec.Hostname{Hostname: hostname})
+	}
+	if _, err := c.appsecClient.UpdateSelectedHostnames(ctx, appsec.UpdateSelectedHostnamesRequest{
+		ConfigID: configID, Version: version, HostnameList: hostnameList,
+	}); err != nil {
+		return fmt.Errorf("failed to update hostnames of security configuration %d version %d: %w", configID, version, err)
+	}
+	return nil
+}
+
+// GetWebsiteMatchTargets returns the website match targets of a version
+func (c *Client) GetWebsiteMatchTargets(ctx context.Context, configID, version int) ([]AppSecMatchTarget, error) {
+	resp, err := c.appsecClient.GetMatchTargets(ctx, appsec.GetMatchTargetsRequest{ConfigID: configID, ConfigVersion: version})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get match targets of security configuration %d version %d: %w", configID, version, err)
+	}
+	targets := make([]AppSecMatchTarget, 0, len(resp.MatchTargets.WebsiteTargets))
+	for _, target := range resp.MatchTargets.WebsiteTargets {
+		targets = append(targets, AppSecMatchTarget{
+			ID:        target.TargetID,
+			PolicyID:  target.SecurityPolicy.PolicyID,
+			Hostnames: target.Hostnames,
+			Paths:     target.FilePaths,
+		})
+	}
+	return targets, nil
+}
+
+// CreateWebsiteMatchTarget adds a website match target to a version
+func (c *Client) CreateWebsiteMatchTarget(ctx context.Context, configID, version int, target AppSecMatchTarget) error {
+	payload, err := json.Marshal(map[string]interface{}{
+		"type":                         "website",
+		"configId":                     configID,
+		"configVersion":                version,
+		"hostnames":                    target.Hostnames,
+		"filePaths":                    target.Paths,
+		"isNegativePathMatch":          false,
+		"isNegativeFileExtensionMatch": false,
+		"defaultFile":                  "NO_MATCH",
+		"securityPolicy":               map[string]string{"policyId": target.PolicyID},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode match target: %w", err)
+	}
+	if _, err := c.appsecClient.CreateMatchTarget(ctx, appsec.CreateMatchTargetRequest{
+		Type: "website", ConfigID: configID, ConfigVersion: version, JsonPayloadRaw: payload,
+	}); err != nil {
+		return fmt.Errorf("failed to create match target of security policy %s: %w", target.PolicyID, err)
+	}
+	return nil
+}
+
+// RemoveMatchTarget removes a match target from a version
+func (c *Client) RemoveMatchTarget(ctx context.Context, configID, version, targetID int) error {
+	if _, err := c.appsecClient.RemoveMatchTarget(ctx, appsec.RemoveMatchTargetRequest{
+		ConfigID: configID, ConfigVersion: version, TargetID: targetID,
+	}); err != nil {
+		return fmt.Errorf("failed to remove match target %d: %w", targetID, err)
+	}
+	return nil
+}
+
+// ActivateAppSecConfig activates a version of a security configuration on a network (STAGING or
+// PRODUCTION) and returns the activation
+func (c *Client) ActivateAppSecConfig(ctx context.Context, configID, version int, network, note string, notify []string) (*appsec.CreateActivationsResponse, error) {
+	request := appsec.CreateActivationsRequest{
+		Action:             string(appsec.ActivationTypeActivate),
+		Network:            network,
+		Note:               note,
+		NotificationEmails: notify,
+	}
+	// The API refuses a missing list of recipients
+	if request.NotificationEmails == nil {
+		request.NotificationEmails = []string{}
+	}
+	request.ActivationConfigs = append(request.ActivationConfigs, struct {
+		ConfigID      int `json:"configId"`
+		ConfigVersion int `json:"configVersion"`
+	}{ConfigID: configID, ConfigVersion: version})
+	resp, err := c.appsecClient.CreateActivations(ctx, request, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to activate security configuration %d version %d on %s: %w", configID, version, network, err)
+	}
+	return resp, nil
+}
+
+// GetAppSecActivation returns the status of an activation, e.g. RECEIVED, ACTIVATED or FAILED
+func (c *Client) GetAppSecActivation(ctx context.Context, activationID int) (string, error) {
+	resp, err := c.appsecClient.GetActivations(ctx, appsec.GetActivationsRequest{ActivationID: activationID})
+	if err != nil {
+		return "", fmt.Errorf("failed to get security configuration activation %d: %w", activationID, err)
+	}
+	return string(resp.Status), nil
+}
+
+// isAppSecNotFound reports whether an Application Security request failed because the resource
+// doesn't exist
+func isAppSecNotFound(err error) bool {
+	var appsecErr *appsec.Error
+	return errors.As(err, &appsecErr) && appsecErr.StatusCode == http.StatusNotFound
+}
+
+// AppSecRatePolicy is a rate policy of a security configuration version, limiting the requests a
+// client may send to the matched hostnames and paths
+type AppSecRatePolicy struct {
+	ID               int
+	Name             string
+	Description      string
+	AverageThreshold int
+	BurstThreshold   int
+	ClientIdentifier string
+	RequestType      string
+	Hostnames        []string
+	Paths            []string
+}
+
+// GetRatePolicies returns the rate policies of a version
+func (c *Client) GetRatePolicies(ctx context.Context, configID, version int) ([]AppSecRatePolicy, error) {
+	resp, err := c.appsecClient.GetRatePolicies(ctx, appsec.GetRatePoliciesRequest{ConfigID: configID, ConfigVersion: version})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rate policies of security configuration %d version %d: %w", configID, version, err)
+	}
+	policies := make([]AppSecRatePolicy, 0, len(resp.RatePolicies))
+	for _, policy := range resp.RatePolicies {
+		ratePolicy := AppSecRatePolicy{
+			ID:               policy.ID,
+			Name:             policy.Name,
+			Description:      policy.Description,
+			AverageThreshold: policy.AverageThreshold,
+			BurstThreshold:   policy.BurstThreshold,
+			ClientIdentifier: policy.ClientIdentifier,
+			RequestType:      policy.RequestType,
+			Hostnames:        policy.Hostnames,
+		}
+		if policy.Hosts != nil && policy.Hosts.Values != nil {
+			ratePolicy.Hostnames = *policy.Hosts.Values
+		}
+		if policy.Path != nil {
+			ratePolicy.Paths = policy.Path.Values
+		}
+		policies = append(policies, ratePolicy)
+	}
+	return policies, nil
+}
+
+// CreateRatePolicy adds a rate policy to a version and returns its ID
+func (c *Client) CreateRatePolicy(ctx context.Context, configID, version int, policy AppSecRatePolicy) (int, error) {
+	payload, err := ratePolicyPayload(configID, version, policy)
+	if err != nil {
+		return 0, err
+	}
+	resp, err := c.appsecClient.CreateRatePolicy(ctx, appsec.CreateRatePolicyRequest{
+		ConfigID: configID, ConfigVersion: version, JsonPayloadRaw: payload,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to create rate policy %s: %w", policy.Name, err)
+	}
+	return resp.ID, nil
+}
+
+// UpdateRatePolicy replaces the rate policy with policy.ID in a version
+func (c *Client) UpdateRatePolicy(ctx context.Context, configID, version int, policy AppSecRatePolicy) error {
+	payload, err := ratePolicyPayload(configID, version, policy)
+	if err != nil {
+		return err
+	}
+	if _, err := c.appsecClient.UpdateRatePolicy(ctx, appsec.UpdateRatePolicyRequest{
+		RatePolicyID: policy.ID, ConfigID: configID, ConfigVersion: version, JsonPayloadRaw: payload,
+	}); err != nil {
+		return fmt.Errorf("failed to update rate policy %s: %w", policy.Name, err)
+	}
+	return nil
+}
+
+// GetRatePolicyActions returns the actions a security policy takes on clients exceeding its rate
+// policies by rate policy ID
+func (c *Client) GetRatePolicyActions(ctx context.Context, configID, version int, policyID string) (map[int]string, error) {
+	resp, err := c.appsecClient.GetRatePolicyActions(ctx, appsec.GetRatePolicyActionsRequest{ConfigID: configID, Version: version, PolicyID: policyID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rate policy actions of security policy %s: %w", policyID, err)
+	}
+	actions := make(map[int]string, len(resp.RatePolicyActions))
+	for _, action := range resp.RatePolicyActions {
+		actions[action.ID] = action.Ipv4Action
+	}
+	return actions, nil
+}
+
+// UpdateRatePolicyAction sets the action a security policy takes on IPv4 and IPv6 clients
+// exceeding a rate policy
+func (c *Client) UpdateRatePolicyAction(ctx context.Context, configID, version int, policyID string, ratePolicyID int, action string) error {
+	if _, err := c.appsecClient.UpdateRatePolicyAction(ctx, appsec.UpdateRatePolicyActionRequest{
+		ConfigID: configID, Version: version, PolicyID: policyID, RatePolicyID: ratePolicyID, Ipv4Action: action, Ipv6Action: action,
+	}); err != nil {
+		return fmt.Errorf("failed to set action of rate policy %d of security policy %s: %w", ratePolicyID, policyID, err)
+	}
+	return nil
+}
+
+// ratePolicyPayload encodes a rate policy matching all requests, or the given paths, to the
+// hostnames of the policy or all hostnames of the configuration
+func ratePolicyPayload(configID, version int, policy AppSecRatePolicy) (json.RawMessage, error) {
+	payload := map[string]interface{}{
+		"configId":              configID,
+		"configVersion":         version,
+		"type":                  "WAF",
+		"matchType":             "path",
+		"name":                  policy.Name,
+		"description":           policy.Description,
+		"averageThreshold":      policy.AverageThreshold,
+		"burstThreshold":        policy.BurstThreshold,
+		"clientIdentifier":      policy.ClientIdentifier,
+		"requestType":           policy.RequestType,
+		"sameActionOnIpv6":      true,
+		"useXForwardForHeaders": false,
+		"pathMatchType":         "AllRequests",
+		"pathUriPositiveMatch":  true,
+	}
+	if len(policy.Paths) > 0 {
+		payload["pathMatchType"] = "Custom"
+		payload["path"] = map[string]interface{}{"positiveMatch": true, "values": policy.Paths}
+	}
+	if len(policy.Hostnames) > 0 {
+		payload["hosts"] = map[string]interface{}{"positiveMatch": true, "values": policy.Hostnames}
+	}
+	encoded, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode rate policy %s: %w", policy.Name, err)
+	}
+	return encoded, nil
+}
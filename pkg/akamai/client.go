@@ -3,9 +3,19 @@ package akamai
 import (
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
 
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v8/pkg/appsec"
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v8/pkg/clientlists"
+	cloudlets "github.com/akamai/AkamaiOPEN-edgegrid-golang/v8/pkg/cloudlets/v3"
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v8/pkg/cps"
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v8/pkg/datastream"
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v8/pkg/dns"
 	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v8/pkg/edgegrid"
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v8/pkg/edgeworkers"
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v8/pkg/gtm"
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v8/pkg/networklists"
 	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v8/pkg/papi"
 	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v8/pkg/session"
 )
@@ -13,56 +23,278 @@ import (
 // Client represents an Akamai API client using the official EdgeGrid client
 type Client struct {
 	papiClient papi.PAPI
+
+	// dnsClient manages Edge DNS zones
+	dnsClient dns.DNS
+
+	// networkListsClient manages network lists
+	networkListsClient networklists.NTWRKLISTS
+
+	// clientListsClient manages client lists, the successor of network lists
+	clientListsClient clientlists.ClientLists
+
+	// appsecClient manages Application Security configurations
+	appsecClient appsec.APPSEC
+
+	// gtmClient manages Global Traffic Management domains
+	gtmClient gtm.GTM
+
+	// edgeWorkersClient manages EdgeKV namespaces and their items
+	edgeWorkersClient edgeworkers.Edgeworkers
+
+	// cloudletsClient manages Cloudlets shared policies
+	cloudletsClient cloudlets.Cloudlets
+
+	// cpsClient manages CPS certificate enrollments
+	cpsClient cps.CPS
+
+	// dataStreamClient manages DataStream 2 log streams
+	dataStreamClient datastream.DS
+
+	// session is used for endpoints not covered by the papi package (e.g. PAPI PATCH, Reporting API, HAPI)
+	session session.Session
+
+	// search caches property search results; see DefaultSearchCacheTTL
+	search *searchCache
+
+	// limiter is the rate limiter of the client's account, shared with its other clients
+	limiter *accountLimiter
+
+	// account identifies the account the client acts in by its API host and account switch key
+	account string
 }
 
-// NewClient creates a new Akamai API client using the official EdgeGrid client
-func NewClient() (*Client, error) {
-	// Get credentials from environment variables
-	host := os.Getenv("AKAMAI_HOST")
-	clientToken := os.Getenv("AKAMAI_CLIENT_TOKEN")
-	clientSecret := os.Getenv("AKAMAI_CLIENT_SECRET")
-	accessToken := os.Getenv("AKAMAI_ACCESS_TOKEN")
+// Credentials selects where the client reads its EdgeGrid credentials from
+type Credentials struct {
+	// EdgercPath is the path of an .edgerc file. When empty, the credentials are read from the
+	// AKAMAI_HOST, AKAMAI_CLIENT_TOKEN, AKAMAI_CLIENT_SECRET and AKAMAI_ACCESS_TOKEN environment variables.
+	EdgercPath string
 
-	if host == "" || clientToken == "" || clientSecret == "" || accessToken == "" {
-		return nil, fmt.Errorf("missing Akamai credentials in environment variables")
+	// EdgercSection is the section of the .edgerc file to use, "default" if empty
+	EdgercSection string
+
+	// Host, ClientToken, ClientSecret and AccessToken are inline credentials, e.g. read from a
+	// Secret. They take precedence over the .edgerc file and the environment when Host is set.
+	Host         string
+	ClientToken  string
+	ClientSecret string
+	AccessToken  string
+
+	// AccountSwitchKey makes requests on behalf of another account the API client can manage
+	AccountSwitchKey string
+}
+
+// NewClient creates a new Akamai API client using the official EdgeGrid client
+func NewClient(credentials Credentials) (*Client, error) {
+	config, err := loadConfig(credentials)
+	if err != nil {
+		return nil, err
+	}
+	if credentials.AccountSwitchKey != "" {
+		config.AccountKey = credentials.AccountSwitchKey
 	}
 
 	// Validate credential formats
-	if len(clientToken) < 20 || len(clientSecret) < 20 || len(accessToken) < 20 {
+	if len(config.ClientToken) < 20 || len(config.ClientSecret) < 20 || len(config.AccessToken) < 20 {
 		return nil, fmt.Errorf("invalid Akamai credentials: tokens appear to be too short")
 	}
 
 	// Ensure host format is correct (remove https:// prefix if present, as EdgeGrid client expects just the hostname)
-	host = strings.TrimPrefix(host, "https://")
-	host = strings.TrimPrefix(host, "http://")
-	host = strings.TrimSuffix(host, "/")
+	config.Host = strings.TrimPrefix(config.Host, "https://")
+	config.Host = strings.TrimPrefix(config.Host, "http://")
+	config.Host = strings.TrimSuffix(config.Host, "/")
 
 	// Validate host format
-	if !strings.Contains(host, "akamaiapis.net") {
+	if !strings.Contains(config.Host, "akamaiapis.net") {
 		return nil, fmt.Errorf("invalid Akamai host: must contain 'akamaiapis.net'")
 	}
 
-	// Create EdgeGrid configuration
-	config := edgegrid.Config{
-		Host:         host,
-		ClientToken:  clientToken,
-		ClientSecret: clientSecret,
-		AccessToken:  accessToken,
-		MaxBody:      131072, // 128KB
-	}
-
 	// Create session with EdgeGrid signer
 	sess, err := session.New(
-		session.WithSigner(&config),
+		session.WithSigner(config),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create session: %w", err)
 	}
+	// Requests are signed only once the account's rate limit allows them to be sent
+	limiter := rateLimiters.forAccount(config.Host, credentials.AccountSwitchKey)
+	sess = rateLimitedSession{Session: sess, limiter: limiter}
 
 	// Create PAPI client
 	papiClient := papi.Client(sess)
 
 	return &Client{
-		papiClient: papiClient,
+		papiClient:         papiClient,
+		dnsClient:          dns.Client(sess),
+		networkListsClient: networklists.Client(sess),
+		clientListsClient:  clientlists.Client(sess),
+		appsecClient:       appsec.Client(sess),
+		gtmClient:          gtm.Client(sess),
+		edgeWorkersClient:  edgeworkers.Client(sess),
+		cloudletsClient:    cloudlets.Client(sess),
+		cpsClient:          cps.Client(sess),
+		dataStreamClient:   datastream.Client(sess),
+		session:            sess,
+		search:             newSearchCache(DefaultSearchCacheTTL),
+		limiter:            limiter,
+		account:            accountKey(config.Host, credentials.AccountSwitchKey),
 	}, nil
 }
+
+// Account identifies the Akamai account the client acts in, so limits of the account can be
+// shared by its clients: clients of the same API client and account switch key share it. Clients
+// sending their requests to stubs have an empty account.
+func (c *Client) Account() string {
+	if c == nil {
+		return ""
+	}
+	return c.account
+}
+
+// NewClientWithPAPI creates a client that sends its PAPI requests to papiClient, e.g. a stub in
+// tests, instead of an EdgeGrid session
+func NewClientWithPAPI(papiClient papi.PAPI) *Client {
+	return &Client{
+		papiClient: papiClient,
+		search:     newSearchCache(DefaultSearchCacheTTL),
+	}
+}
+
+// NewClientWithDNS creates a client that sends its Edge DNS requests to dnsClient, e.g. a stub in
+// tests, instead of an EdgeGrid session
+func NewClientWithDNS(dnsClient dns.DNS) *Client {
+	return &Client{
+		dnsClient: dnsClient,
+		search:    newSearchCache(DefaultSearchCacheTTL),
+	}
+}
+
+// NewClientWithNetworkLists creates a client that sends its Network Lists requests to
+// networkListsClient, e.g. a stub in tests, instead of an EdgeGrid session
+func NewClientWithNetworkLists(networkListsClient networklists.NTWRKLISTS) *Client {
+	return &Client{
+		networkListsClient: networkListsClient,
+		search:             newSearchCache(DefaultSearchCacheTTL),
+	}
+}
+
+// NewClientWithClientLists creates a client that sends its Client Lists requests to
+// clientListsClient, e.g. a stub in tests, instead of an EdgeGrid session
+func NewClientWithClientLists(clientListsClient clientlists.ClientLists) *Client {
+	return &Client{
+		clientListsClient: clientListsClient,
+		search:            newSearchCache(DefaultSearchCacheTTL),
+	}
+}
+
+// NewClientWithAppSec creates a client that sends its Application Security requests to
+// appsecClient, e.g. a stub in tests, instead of an EdgeGrid session
+func NewClientWithAppSec(appsecClient appsec.APPSEC) *Client {
+	return &Client{
+		appsecClient: appsecClient,
+		search:       newSearchCache(DefaultSearchCacheTTL),
+	}
+}
+
+// NewClientWithGTM creates a client that sends its Global Traffic Management requests to
+// gtmClient, e.g. a stub in tests, instead of an EdgeGrid session
+func NewClientWithGTM(gtmClient gtm.GTM) *Client {
+	return &Client{
+		gtmClient: gtmClient,
+		search:    newSearchCache(DefaultSearchCacheTTL),
+	}
+}
+
+// NewClientWithEdgeWorkers creates a client that sends its EdgeWorkers and EdgeKV requests to
+// edgeWorkersClient, e.g. a stub in tests, instead of an EdgeGrid session
+func NewClientWithEdgeWorkers(edgeWorkersClient edgeworkers.Edgeworkers) *Client {
+	return &Client{
+		edgeWorkersClient: edgeWorkersClient,
+		search:            newSearchCache(DefaultSearchCacheTTL),
+	}
+}
+
+// NewClientWithCloudlets creates a client that sends its Cloudlets requests to cloudletsClient,
+// e.g. a stub in tests, instead of an EdgeGrid session
+func NewClientWithCloudlets(cloudletsClient cloudlets.Cloudlets) *Client {
+	return &Client{
+		cloudletsClient: cloudletsClient,
+		search:          newSearchCache(DefaultSearchCacheTTL),
+	}
+}
+
+// NewClientWithCPS creates a client that sends its CPS requests to cpsClient, e.g. a stub in
+// tests, instead of an EdgeGrid session
+func NewClientWithCPS(cpsClient cps.CPS) *Client {
+	return &Client{
+		cpsClient: cpsClient,
+		search:    newSearchCache(DefaultSearchCacheTTL),
+	}
+}
+
+// NewClientWithDataStream creates a client that sends its DataStream requests to
+// dataStreamClient, e.g. a stub in tests, instead of an EdgeGrid session
+func NewClientWithDataStream(dataStreamClient datastream.DS) *Client {
+	return &Client{
+		dataStreamClient: dataStreamClient,
+		search:           newSearchCache(DefaultSearchCacheTTL),
+	}
+}
+
+// NewClientWithSession creates a client that sends the requests not covered by the EdgeGrid API
+// packages, e.g. Fast Purge, to sess instead of a signed EdgeGrid session
+func NewClientWithSession(sess session.Session) *Client {
+	return &Client{
+		session: sess,
+		search:  newSearchCache(DefaultSearchCacheTTL),
+	}
+}
+
+// loadConfig reads the EdgeGrid configuration from the inline credentials, the .edgerc file or the environment
+func loadConfig(credentials Credentials) (*edgegrid.Config, error) {
+	if credentials.Host != "" {
+		if credentials.ClientToken == "" || credentials.ClientSecret == "" || credentials.AccessToken == "" {
+			return nil, fmt.Errorf("missing Akamai credentials: client token, client secret and access token are required")
+		}
+		return &edgegrid.Config{
+			Host:         credentials.Host,
+			ClientToken:  credentials.ClientToken,
+			ClientSecret: credentials.ClientSecret,
+			AccessToken:  credentials.AccessToken,
+			MaxBody:      edgegrid.MaxBodySize,
+		}, nil
+	}
+
+	if credentials.EdgercPath != "" {
+		section := credentials.EdgercSection
+		if section == "" {
+			section = edgegrid.DefaultSection
+		}
+		config, err := edgegrid.New(edgegrid.WithFile(credentials.EdgercPath), edgegrid.WithSection(section))
+		if err != nil {
+			return nil, fmt.Errorf("failed to load section %q of %s: %w", section, credentials.EdgercPath, err)
+		}
+		return config, nil
+	}
+
+	// Get credentials from environment variables
+	config := &edgegrid.Config{
+		Host:         os.Getenv("AKAMAI_HOST"),
+		ClientToken:  os.Getenv("AKAMAI_CLIENT_TOKEN"),
+		ClientSecret: os.Getenv("AKAMAI_CLIENT_SECRET"),
+		AccessToken:  os.Getenv("AKAMAI_ACCESS_TOKEN"),
+		// The max body defaults to 128KB and has to match the API client's configuration
+		MaxBody: edgegrid.MaxBodySize,
+	}
+	if config.Host == "" || config.ClientToken == "" || config.ClientSecret == "" || config.AccessToken == "" {
+		return nil, fmt.Errorf("missing Akamai credentials in environment variables")
+	}
+	if raw := os.Getenv("AKAMAI_MAX_BODY"); raw != "" {
+		value, err := strconv.Atoi(raw)
+		if err != nil || value <= 0 {
+			return nil, fmt.Errorf("invalid AKAMAI_MAX_BODY %q: must be a positive number of bytes", raw)
+		}
+		config.MaxBody = value
+	}
+	return config, nil
+}
@@ -0,0 +1,61 @@
+package akamai
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const testEdgerc = `[default]
+host = akab-default.luna.akamaiapis.net
+client_token = akab-default-client-token-xxxx
+client_secret = default-client-secret-xxxxxxxxxxxx
+access_token = akab-default-access-token-xxxx
+
+[papi]
+host = akab-papi.luna.akamaiapis.net
+client_token = akab-papi-client-token-xxxxxxx
+client_secret = papi-client-secret-xxxxxxxxxxxxxxx
+access_token = akab-papi-access-token-xxxxxxx
+max_body = 262144
+`
+
+func TestLoadConfig(t *testing.T) {
+	edgercPath := filepath.Join(t.TempDir(), ".edgerc")
+	if err := os.WriteFile(edgercPath, []byte(testEdgerc), 0o600); err != nil {
+		t.Fatalf("failed to write .edgerc: %v", err)
+	}
+	t.Setenv("AKAMAI_HOST", "akab-env.luna.akamaiapis.net")
+	t.Setenv("AKAMAI_CLIENT_TOKEN", "akab-env-client-token-xxxxxxxx")
+	t.Setenv("AKAMAI_CLIENT_SECRET", "env-client-secret-xxxxxxxxxxxxxxxx")
+	t.Setenv("AKAMAI_ACCESS_TOKEN", "akab-env-access-token-xxxxxxxx")
+
+	tests := []struct {
+		name            string
+		credentials     Credentials
+		expectedHost    string
+		expectedMaxBody int
+		expectErr       bool
+	}{
+		{name: "environment", expectedHost: "akab-env.luna.akamaiapis.net", expectedMaxBody: 131072},
+		{name: "default section", credentials: Credentials{EdgercPath: edgercPath}, expectedHost: "akab-default.luna.akamaiapis.net", expectedMaxBody: 131072},
+		{name: "selected section", credentials: Credentials{EdgercPath: edgercPath, EdgercSection: "papi"}, expectedHost: "akab-papi.luna.akamaiapis.net", expectedMaxBody: 262144},
+		{name: "missing section", credentials: Credentials{EdgercPath: edgercPath, EdgercSection: "ccu"}, expectErr: true},
+		{name: "missing file", credentials: Credentials{EdgercPath: filepath.Join(t.TempDir(), "missing")}, expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config, err := loadConfig(tt.credentials)
+			if (err != nil) != tt.expectErr {
+				t.Fatalf("loadConfig() error = %v, expectErr %v", err, tt.expectErr)
+			}
+			if tt.expectErr {
+				return
+			}
+			if config.Host != tt.expectedHost || config.MaxBody != tt.expectedMaxBody {
+				t.Errorf("loadConfig() = host %q, max body %d, expected %q, %d", config.Host, config.MaxBody, tt.expectedHost, tt.expectedMaxBody)
+			}
+		})
+	}
+}
@@ -0,0 +1,118 @@
+package akamai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v8/pkg/clientlists"
+)
+
+// GetClientList retrieves a client list with its items, returning nil when it doesn't exist
+func (c *Client) GetClientList(ctx context.Context, listID string) (*clientlists.GetClientListResponse, error) {
+	list, err := c.clientListsClient.GetClientList(ctx, clientlists.GetClientListRequest{ListID: listID, IncludeItems: true})
+	if err != nil {
+		if isClientListNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get client list %s: %w", listID, err)
+	}
+	return list, nil
+}
+
+// FindClientList returns the ID of the client list with exactly the given name and type, or ""
+// when there is none
+func (c *Client) FindClientList(ctx context.Context, name, listType string) (string, error) {
+	resp, err := c.clientListsClient.GetClientLists(ctx, clientlists.GetClientListsRequest{
+		Name: name,
+		Type: []clientlists.ClientListType{clientlists.ClientListType(listType)},
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to search client list %s: %w", name, err)
+	}
+	// The search matches parts of the name too
+	for _, list := range resp.Content {
+		if list.Name == name && string(list.Type) == listType {
+			return list.ListID, nil
+		}
+	}
+	return "", nil
+}
+
+// CreateClientList creates a client list with its items and returns its ID
+func (c *Client) CreateClientList(ctx context.Context, list clientlists.CreateClientListRequest) (string, error) {
+	resp, err := c.clientListsClient.CreateClientList(ctx, list)
+	if err != nil {
+		return "", fmt.Errorf("failed to create client list %s: %w", list.Name, err)
+	}
+	return resp.ListID, nil
+}
+
+// UpdateClientList replaces the name, the notes and the tags of a client list
+func (c *Client) UpdateClientList(ctx context.Context, listID string, list clientlists.UpdateClientList) error {
+	if _, err := c.clientListsClient.UpdateClientList(ctx, clientlists.UpdateClientListRequest{UpdateClientList: list, ListID: listID}); err != nil {
+		return fmt.Errorf("failed to update client list %s: %w", listID, err)
+	}
+	return nil
+}
+
+// UpdateClientListItems appends, updates and deletes items of a client list in a single version
+func (c *Client) UpdateClientListItems(ctx context.Context, listID string, items clientlists.UpdateClientListItems) error {
+	if _, err := c.clientListsClient.UpdateClientListItems(ctx, clientlists.UpdateClientListItemsRequest{UpdateClientListItems: items, ListID: listID}); err != nil {
+		return fmt.Errorf("failed to update the items of client list %s: %w", listID, err)
+	}
+	return nil
+}
+
+// DeleteClientList deletes a client list; a list that doesn't exist anymore is not an error.
+// Akamai refuses lists that are active or used by a security configuration.
+func (c *Client) DeleteClientList(ctx context.Context, listID string) error {
+	if err := c.clientListsClient.DeleteClientList(ctx, clientlists.DeleteClientListRequest{ListID: listID}); err != nil && !isClientListNotFound(err) {
+		return fmt.Errorf("failed to delete client list %s: %w", listID, err)
+	}
+	return nil
+}
+
+// GetClientListActivation retrieves the activation status of a client list on a network
+// (STAGING or PRODUCTION); a list never activated there is INACTIVE
+func (c *Client) GetClientListActivation(ctx context.Context, listID, network string) (*clientlists.GetActivationStatusResponse, error) {
+	resp, err := c.clientListsClient.GetActivationStatus(ctx, clientlists.GetActivationStatusRequest{
+		ListID:  listID,
+		Network: clientlists.ActivationNetwork(network),
+	})
+	if err != nil {
+		if isClientListNotFound(err) {
+			return &clientlists.GetActivationStatusResponse{ListID: listID, ActivationStatus: clientlists.Inactive}, nil
+		}
+		return nil, fmt.Errorf("failed to get %s activation of client list %s: %w", network, listID, err)
+	}
+	return resp, nil
+}
+
+// ActivateClientList activates the current version of a client list on a network
+func (c *Client) ActivateClientList(ctx context.Context, listID, network, comments string, notify []string) (*clientlists.CreateActivationResponse, error) {
+	// The API refuses a missing list of recipients
+	if notify == nil {
+		notify = []string{}
+	}
+	resp, err := c.clientListsClient.CreateActivation(ctx, clientlists.CreateActivationRequest{
+		ListID: listID,
+		ActivationParams: clientlists.ActivationParams{
+			Action:                 clientlists.Activate,
+			Comments:               comments,
+			Network:                clientlists.ActivationNetwork(network),
+			NotificationRecipients: notify,
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to activate client list %s on %s: %w", listID, network, err)
+	}
+	return resp, nil
+}
+
+// isClientListNotFound reports whether a Client Lists request failed because the list doesn't exist
+func isClientListNotFound(err error) bool {
+	var listErr *clientlists.Error
+	return errors.As(err, &listErr) && listErr.StatusCode == http.StatusNotFound
+}
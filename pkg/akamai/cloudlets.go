@@ -0,0 +1,154 @@
+package akamai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	cloudlets "github.com/akamai/AkamaiOPEN-edgegrid-golang/v8/pkg/cloudlets/v3"
+)
+
+// cloudletPolicyPageSize is the number of shared policies listed per request
+const cloudletPolicyPageSize = 1000
+
+// GetCloudletPolicy retrieves a Cloudlets shared policy with its current activations, returning
+// nil when it doesn't exist
+func (c *Client) GetCloudletPolicy(ctx context.Context, policyID int64) (*cloudlets.Policy, error) {
+	policy, err := c.cloudletsClient.GetPolicy(ctx, cloudlets.GetPolicyRequest{PolicyID: policyID})
+	if err != nil {
+		if isCloudletsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get cloudlet policy %d: %w", policyID, err)
+	}
+	return policy, nil
+}
+
+// FindCloudletPolicy returns the shared policy with exactly the given name, or nil when there is
+// none. Policy names are unique within the account.
+func (c *Client) FindCloudletPolicy(ctx context.Context, name string) (*cloudlets.Policy, error) {
+	for page := 0; ; page++ {
+		resp, err := c.cloudletsClient.ListPolicies(ctx, cloudlets.ListPoliciesRequest{Page: page, Size: cloudletPolicyPageSize})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list cloudlet policies: %w", err)
+		}
+		for i := range resp.Content {
+			if resp.Content[i].Name == name {
+				return &resp.Content[i], nil
+			}
+		}
+		if page+1 >= resp.Page.TotalPages {
+			return nil, nil
+		}
+	}
+}
+
+// CreateCloudletPolicy creates a shared policy and returns it
+func (c *Client) CreateCloudletPolicy(ctx context.Context, policy cloudlets.CreatePolicyRequest) (*cloudlets.Policy, error) {
+	policy.PolicyType = cloudlets.PolicyTypeShared
+	created, err := c.cloudletsClient.CreatePolicy(ctx, policy)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cloudlet policy %s: %w", policy.Name, err)
+	}
+	return created, nil
+}
+
+// UpdateCloudletPolicy changes the group and the description of a shared policy
+func (c *Client) UpdateCloudletPolicy(ctx context.Context, policyID, groupID int64, description string) error {
+	if _, err := c.cloudletsClient.UpdatePolicy(ctx, cloudlets.UpdatePolicyRequest{
+		PolicyID:   policyID,
+		BodyParams: cloudlets.UpdatePolicyBodyParams{GroupID: groupID, Description: &description},
+	}); err != nil {
+		return fmt.Errorf("failed to update cloudlet policy %d: %w", policyID, err)
+	}
+	return nil
+}
+
+// DeleteCloudletPolicy deletes a shared policy; a policy that doesn't exist anymore is not an
+// error. Akamai refuses policies that are active on a network.
+func (c *Client) DeleteCloudletPolicy(ctx context.Context, policyID int64) error {
+	if err := c.cloudletsClient.DeletePolicy(ctx, cloudlets.DeletePolicyRequest{PolicyID: policyID}); err != nil && !isCloudletsNotFound(err) {
+		return fmt.Errorf("failed to delete cloudlet policy %d: %w", policyID, err)
+	}
+	return nil
+}
+
+// GetLatestCloudletPolicyVersion retrieves the latest version of a shared policy with its match
+// rules, returning nil when the policy has no versions
+func (c *Client) GetLatestCloudletPolicyVersion(ctx context.Context, policyID int64) (*cloudlets.PolicyVersion, error) {
+	versions, err := c.cloudletsClient.ListPolicyVersions(ctx, cloudlets.ListPolicyVersionsRequest{PolicyID: policyID, Size: 10})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list versions of cloudlet policy %d: %w", policyID, err)
+	}
+	var latest int64
+	for _, version := range versions.PolicyVersions {
+		latest = max(latest, version.PolicyVersion)
+	}
+	if latest == 0 {
+		return nil, nil
+	}
+	version, err := c.cloudletsClient.GetPolicyVersion(ctx, cloudlets.GetPolicyVersionRequest{PolicyID: policyID, PolicyVersion: latest})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get version %d of cloudlet policy %d: %w", latest, policyID, err)
+	}
+	return version, nil
+}
+
+// CreateCloudletPolicyVersion creates a version of a shared policy with the given match rules and
+// returns it with the warnings about its match rules
+func (c *Client) CreateCloudletPolicyVersion(ctx context.Context, policyID int64, description string, matchRules cloudlets.MatchRules) (*cloudlets.PolicyVersion, error) {
+	version, err := c.cloudletsClient.CreatePolicyVersion(ctx, cloudlets.CreatePolicyVersionRequest{
+		PolicyID:            policyID,
+		CreatePolicyVersion: cloudlets.CreatePolicyVersion{Description: &description, MatchRules: matchRules},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create version of cloudlet policy %d: %w", policyID, err)
+	}
+	return version, nil
+}
+
+// UpdateCloudletPolicyVersion replaces the match rules of a version of a shared policy that was
+// never activated and returns it with the warnings about its match rules
+func (c *Client) UpdateCloudletPolicyVersion(ctx context.Context, policyID, version int64, description string, matchRules cloudlets.MatchRules) (*cloudlets.PolicyVersion, error) {
+	updated, err := c.cloudletsClient.UpdatePolicyVersion(ctx, cloudlets.UpdatePolicyVersionRequest{
+		PolicyID:            policyID,
+		PolicyVersion:       version,
+		UpdatePolicyVersion: cloudlets.UpdatePolicyVersion{Description: &description, MatchRules: matchRules},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update version %d of cloudlet policy %d: %w", version, policyID, err)
+	}
+	return updated, nil
+}
+
+// ActivateCloudletPolicy activates a version of a shared policy on a network (STAGING or
+// PRODUCTION)
+func (c *Client) ActivateCloudletPolicy(ctx context.Context, policyID, version int64, network string) (*cloudlets.PolicyActivation, error) {
+	activation, err := c.cloudletsClient.ActivatePolicy(ctx, cloudlets.ActivatePolicyRequest{
+		PolicyID:      policyID,
+		PolicyVersion: version,
+		Network:       cloudlets.Network(network),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to activate version %d of cloudlet policy %d on %s: %w", version, policyID, network, err)
+	}
+	return activation, nil
+}
+
+// DeactivateCloudletPolicy deactivates a version of a shared policy on a network
+func (c *Client) DeactivateCloudletPolicy(ctx context.Context, policyID, version int64, network string) error {
+	if _, err := c.cloudletsClient.DeactivatePolicy(ctx, cloudlets.DeactivatePolicyRequest{
+		PolicyID:      policyID,
+		PolicyVersion: version,
+		Network:       cloudlets.Network(network),
+	}); err != nil {
+		return fmt.Errorf("failed to deactivate version %d of cloudlet policy %d on %s: %w", version, policyID, network, err)
+	}
+	return nil
+}
+
+func isCloudletsNotFound(err error) bool {
+	var cloudletsErr *cloudlets.Error
+	return errors.As(err, &cloudletsErr) && cloudletsErr.Status == http.StatusNotFound
+}
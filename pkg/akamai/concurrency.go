@@ -0,0 +1,58 @@
+package akamai
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// DefaultContractConcurrency is the default number of Akamai API requests in flight per contract
+const DefaultContractConcurrency = 4
+
+// SetContractConcurrency limits the Akamai API requests in flight per contract of an account for
+// clients created afterwards, however many properties are reconciled in parallel. Zero removes the
+// limit.
+func SetContractConcurrency(requests int) {
+	rateLimiters.configureContracts(requests)
+}
+
+// contractSlots is a semaphore per contract bounding its requests in flight
+type contractSlots struct {
+	limit int
+
+	mu    sync.Mutex
+	slots map[string]chan struct{}
+}
+
+func newContractSlots(limit int) *contractSlots {
+	return &contractSlots{limit: limit, slots: make(map[string]chan struct{})}
+}
+
+// acquire blocks until a request of the contract may be in flight or ctx is done. The returned
+// function releases the slot. Requests without a contract are not limited.
+func (c *contractSlots) acquire(ctx context.Context, contractID string) (func(), error) {
+	if c == nil || c.limit <= 0 || contractID == "" {
+		return func() {}, nil
+	}
+
+	c.mu.Lock()
+	slots, ok := c.slots[contractID]
+	if !ok {
+		slots = make(chan struct{}, c.limit)
+		c.slots[contractID] = slots
+	}
+	c.mu.Unlock()
+
+	select {
+	case slots <- struct{}{}:
+		return func() { <-slots }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// requestContract returns the contract a PAPI request is made for, without the ctr_ prefix
+func requestContract(r *http.Request) string {
+	return strings.TrimPrefix(r.URL.Query().Get("contractId"), "ctr_")
+}
@@ -0,0 +1,77 @@
+package akamai
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestContractSlots(t *testing.T) {
+	slots := newContractSlots(2)
+	ctx := context.Background()
+	blocked := func(contractID string) bool {
+		ctx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+		defer cancel()
+		release, err := slots.acquire(ctx, contractID)
+		if err == nil {
+			release()
+			return false
+		}
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("acquire() unexpected error: %v", err)
+		}
+		return true
+	}
+
+	first, err := slots.acquire(ctx, "1-ABC")
+	if err != nil {
+		t.Fatalf("acquire() unexpected error: %v", err)
+	}
+	if _, err := slots.acquire(ctx, "1-ABC"); err != nil {
+		t.Fatalf("acquire() unexpected error: %v", err)
+	}
+	if !blocked("1-ABC") {
+		t.Error("a third request of the contract must wait for a slot")
+	}
+	if blocked("1-XYZ") {
+		t.Error("another contract must have its own slots")
+	}
+	if blocked("") {
+		t.Error("requests without a contract must not be limited")
+	}
+
+	first()
+	if blocked("1-ABC") {
+		t.Error("a released slot must be available again")
+	}
+
+	var unlimited *contractSlots
+	if _, err := unlimited.acquire(ctx, "1-ABC"); err != nil {
+		t.Errorf("acquire() without slots unexpected error: %v", err)
+	}
+}
+
+func TestRequestContract(t *testing.T) {
+	tests := []struct {
+		url      string
+		expected string
+	}{
+		{url: "https://akab.luna.akamaiapis.net/papi/v1/properties?contractId=ctr_1-ABC&groupId=grp_1", expected: "1-ABC"},
+		{url: "https://akab.luna.akamaiapis.net/papi/v1/properties/prp_1?contractId=1-ABC", expected: "1-ABC"},
+		{url: "https://akab.luna.akamaiapis.net/hapi/v1/edge-hostnames/1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			r, err := http.NewRequest(http.MethodGet, tt.url, nil)
+			if err != nil {
+				t.Fatalf("failed to build request: %v", err)
+			}
+			if got := requestContract(r); got != tt.expected {
+				t.Errorf("requestContract() = %q, expected %q", got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package akamai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v8/pkg/cps"
+)
+
+// CPS networks a certificate is deployed to
+const (
+	CPSNetworkStaging    = "staging"
+	CPSNetworkProduction = "production"
+)
+
+// GetEnrollment retrieves a CPS enrollment, returning nil when it doesn't exist
+func (c *Client) GetEnrollment(ctx context.Context, enrollmentID int) (*cps.Enrollment, error) {
+	enrollment, err := c.cpsClient.GetEnrollment(ctx, cps.GetEnrollmentRequest{EnrollmentID: enrollmentID})
+	if err != nil {
+		if isCPSNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get enrollment %d: %w", enrollmentID, err)
+	}
+	result := cps.Enrollment(*enrollment)
+	if result.ID == 0 {
+		result.ID = enrollmentID
+	}
+	return &result, nil
+}
+
+// FindEnrollment returns the enrollment of the contract with the given common name, or nil when
+// there is none
+func (c *Client) FindEnrollment(ctx context.Context, contractID, commonName string) (*cps.Enrollment, error) {
+	resp, err := c.cpsClient.ListEnrollments(ctx, cps.ListEnrollmentsRequest{ContractID: contractID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list enrollments of contract %s: %w", contractID, err)
+	}
+	for i := range resp.Enrollments {
+		enrollment := resp.Enrollments[i]
+		if enrollment.CSR == nil || enrollment.CSR.CN != commonName {
+			continue
+		}
+		// Listed enrollments are identified by their location
+		if enrollment.ID == 0 {
+			if enrollment.ID, err = cps.GetIDFromLocation(enrollment.Location); err != nil {
+				return nil, fmt.Errorf("invalid location %q of enrollment %s: %w", enrollment.Location, commonName, err)
+			}
+		}
+		return &enrollment, nil
+	}
+	return nil, nil
+}
+
+// CreateEnrollment creates an enrollment in the contract and returns its ID
+func (c *Client) CreateEnrollment(ctx context.Context, contractID string, enrollment cps.EnrollmentRequestBody) (int, error) {
+	resp, err := c.cpsClient.CreateEnrollment(ctx, cps.CreateEnrollmentRequest{
+		EnrollmentRequestBody: enrollment,
+		ContractID:            contractID,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to create enrollment %s: %w", enrollment.CSR.CN, err)
+	}
+	return resp.ID, nil
+}
+
+// UpdateEnrollment replaces an enrollment, which starts a change that renews its certificate,
+// e.g. with new SANs. Pending changes of the enrollment are not cancelled.
+func (c *Client) UpdateEnrollment(ctx context.Context, enrollmentID int, enrollment cps.EnrollmentRequestBody) error {
+	allowCancel := false
+	if _, err := c.cpsClient.UpdateEnrollment(ctx, cps.UpdateEnrollmentRequest{
+		EnrollmentRequestBody:     enrollment,
+		EnrollmentID:              enrollmentID,
+		AllowCancelPendingChanges: &allowCancel,
+	}); err != nil {
+		return fmt.Errorf("failed to update enrollment %d: %w", enrollmentID, err)
+	}
+	return nil
+}
+
+// RemoveEnrollment removes an enrollment and its certificates; an enrollment that doesn't exist
+// anymore is not an error
+func (c *Client) RemoveEnrollment(ctx context.Context, enrollmentID int) error {
+	if _, err := c.cpsClient.RemoveEnrollment(ctx, cps.RemoveEnrollmentRequest{EnrollmentID: enrollmentID}); err != nil && !isCPSNotFound(err) {
+		return fmt.Errorf("failed to remove enrollment %d: %w", enrollmentID, err)
+	}
+	return nil
+}
+
+// GetEnrollmentChange retrieves the status of a pending change of an enrollment from its location
+func (c *Client) GetEnrollmentChange(ctx context.Context, enrollmentID int, location string) (*cps.Change, error) {
+	changeID, err := cps.GetIDFromLocation(location)
+	if err != nil {
+		return nil, fmt.Errorf("invalid change location %q of enrollment %d: %w", location, enrollmentID, err)
+	}
+	change, err := c.cpsClient.GetChangeStatus(ctx, cps.GetChangeStatusRequest{EnrollmentID: enrollmentID, ChangeID: changeID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get change %d of enrollment %d: %w", changeID, enrollmentID, err)
+	}
+	return change, nil
+}
+
+// GetEnrollmentDeployment retrieves the certificate of an enrollment deployed on a network
+// (staging or production), returning nil when none is deployed
+func (c *Client) GetEnrollmentDeployment(ctx context.Context, enrollmentID int, network string) (*cps.Deployment, error) {
+	req := cps.GetDeploymentRequest{EnrollmentID: enrollmentID}
+	var deployment cps.Deployment
+	if network == CPSNetworkProduction {
+		resp, err := c.cpsClient.GetProductionDeployment(ctx, req)
+		if err != nil {
+			return nil, deploymentError(err, enrollmentID, network)
+		}
+		deployment = cps.Deployment(*resp)
+	} else {
+		resp, err := c.cpsClient.GetStagingDeployment(ctx, req)
+		if err != nil {
+			return nil, deploymentError(err, enrollmentID, network)
+		}
+		deployment = cps.Deployment(*resp)
+	}
+	return &deployment, nil
+}
+
+// deploymentError wraps an error getting a deployment; nil when nothing is deployed
+func deploymentError(err error, enrollmentID int, network string) error {
+	if isCPSNotFound(err) {
+		return nil
+	}
+	return fmt.Errorf("failed to get %s deployment of enrollment %d: %w", network, enrollmentID, err)
+}
+
+func isCPSNotFound(err error) bool {
+	var cpsErr *cps.Error
+	return errors.As(err, &cpsErr) && cpsErr.StatusCode == http.StatusNotFound
+}
@@ -0,0 +1,89 @@
+package akamai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v8/pkg/datastream"
+)
+
+// GetStream retrieves the latest version of a DataStream stream, returning nil when it doesn't
+// exist
+func (c *Client) GetStream(ctx context.Context, streamID int64) (*datastream.DetailedStreamVersion, error) {
+	stream, err := c.dataStreamClient.GetStream(ctx, datastream.GetStreamRequest{StreamID: streamID})
+	if err != nil {
+		if isDataStreamNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get stream %d: %w", streamID, err)
+	}
+	return stream, nil
+}
+
+// FindStream returns the stream of the group with the given name, or nil when there is none
+func (c *Client) FindStream(ctx context.Context, groupID int, name string) (*datastream.StreamDetails, error) {
+	streams, err := c.dataStreamClient.ListStreams(ctx, datastream.ListStreamsRequest{GroupID: &groupID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list streams of group %d: %w", groupID, err)
+	}
+	for i := range streams {
+		if streams[i].StreamName == name {
+			return &streams[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// CreateStream creates a stream and returns its ID; activate activates its first version
+func (c *Client) CreateStream(ctx context.Context, config datastream.StreamConfiguration, activate bool) (int64, error) {
+	stream, err := c.dataStreamClient.CreateStream(ctx, datastream.CreateStreamRequest{StreamConfiguration: config, Activate: activate})
+	if err != nil {
+		return 0, fmt.Errorf("failed to create stream %s: %w", config.StreamName, err)
+	}
+	return stream.StreamID, nil
+}
+
+// UpdateStream creates a new version of a stream; activate activates it. The group of a stream
+// can't be changed and is left out of the update.
+func (c *Client) UpdateStream(ctx context.Context, streamID int64, config datastream.StreamConfiguration, activate bool) error {
+	config.GroupID = 0
+	if _, err := c.dataStreamClient.UpdateStream(ctx, datastream.UpdateStreamRequest{
+		StreamID:            streamID,
+		StreamConfiguration: config,
+		Activate:            activate,
+	}); err != nil {
+		return fmt.Errorf("failed to update stream %d: %w", streamID, err)
+	}
+	return nil
+}
+
+// ActivateStream activates the latest version of a stream
+func (c *Client) ActivateStream(ctx context.Context, streamID int64) error {
+	if _, err := c.dataStreamClient.ActivateStream(ctx, datastream.ActivateStreamRequest{StreamID: streamID}); err != nil {
+		return fmt.Errorf("failed to activate stream %d: %w", streamID, err)
+	}
+	return nil
+}
+
+// DeactivateStream stops the log delivery of a stream
+func (c *Client) DeactivateStream(ctx context.Context, streamID int64) error {
+	if _, err := c.dataStreamClient.DeactivateStream(ctx, datastream.DeactivateStreamRequest{StreamID: streamID}); err != nil {
+		return fmt.Errorf("failed to deactivate stream %d: %w", streamID, err)
+	}
+	return nil
+}
+
+// DeleteStream deletes a deactivated stream; a stream that doesn't exist anymore is not an error
+func (c *Client) DeleteStream(ctx context.Context, streamID int64) error {
+	if err := c.dataStreamClient.DeleteStream(ctx, datastream.DeleteStreamRequest{StreamID: streamID}); err != nil && !isDataStreamNotFound(err) {
+		return fmt.Errorf("failed to delete stream %d: %w", streamID, err)
+	}
+	return nil
+}
+
+func isDataStreamNotFound(err error) bool {
+	var dsErr *datastream.Error
+	return errors.As(err, &dsErr) && dsErr.StatusCode == http.StatusNotFound
+}
@@ -0,0 +1,63 @@
+package akamai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v8/pkg/dns"
+)
+
+// GetDNSRecord retrieves a record set of an Edge DNS zone, returning nil when it doesn't exist
+func (c *Client) GetDNSRecord(ctx context.Context, zone, name, recordType string) (*dns.RecordBody, error) {
+	record, err := c.dnsClient.GetRecord(ctx, zone, name, recordType)
+	if err != nil {
+		if isDNSNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get %s record %s: %w", recordType, name, err)
+	}
+	return record, nil
+}
+
+// ListDNSRecords lists the record sets of the given types in an Edge DNS zone
+func (c *Client) ListDNSRecords(ctx context.Context, zone string, recordTypes []string) ([]dns.RecordSet, error) {
+	response, err := c.dnsClient.GetRecordSets(ctx, zone, dns.RecordSetQueryArgs{ShowAll: true, Types: strings.Join(recordTypes, ",")})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list records of zone %s: %w", zone, err)
+	}
+	return response.RecordSets, nil
+}
+
+// CreateDNSRecord creates a record set in an Edge DNS zone
+func (c *Client) CreateDNSRecord(ctx context.Context, zone string, record *dns.RecordBody) error {
+	if err := c.dnsClient.CreateRecord(ctx, record, zone); err != nil {
+		return fmt.Errorf("failed to create %s record %s: %w", record.RecordType, record.Name, err)
+	}
+	return nil
+}
+
+// UpdateDNSRecord replaces the TTL and the targets of a record set in an Edge DNS zone
+func (c *Client) UpdateDNSRecord(ctx context.Context, zone string, record *dns.RecordBody) error {
+	if err := c.dnsClient.UpdateRecord(ctx, record, zone); err != nil {
+		return fmt.Errorf("failed to update %s record %s: %w", record.RecordType, record.Name, err)
+	}
+	return nil
+}
+
+// DeleteDNSRecord deletes a record set from an Edge DNS zone; a record set that doesn't exist
+// anymore is not an error
+func (c *Client) DeleteDNSRecord(ctx context.Context, zone string, record *dns.RecordBody) error {
+	if err := c.dnsClient.DeleteRecord(ctx, record, zone); err != nil && !isDNSNotFound(err) {
+		return fmt.Errorf("failed to delete %s record %s: %w", record.RecordType, record.Name, err)
+	}
+	return nil
+}
+
+// isDNSNotFound reports whether an Edge DNS request failed because the resource doesn't exist
+func isDNSNotFound(err error) bool {
+	var dnsErr *dns.Error
+	return errors.As(err, &dnsErr) && dnsErr.StatusCode == http.StatusNotFound
+}
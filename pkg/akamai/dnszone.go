@@ -0,0 +1,75 @@
+package akamai
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v8/pkg/dns"
+)
+
+// DNSZoneTypePrimary is the type of zones whose records are managed in Edge DNS
+const DNSZoneTypePrimary = "PRIMARY"
+
+// GetDNSZone retrieves an Edge DNS zone, returning nil when it doesn't exist
+func (c *Client) GetDNSZone(ctx context.Context, zone string) (*dns.ZoneResponse, error) {
+	resp, err := c.dnsClient.GetZone(ctx, zone)
+	if err != nil {
+		if isDNSNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get DNS zone %s: %w", zone, err)
+	}
+	return resp, nil
+}
+
+// CreateDNSZone creates an Edge DNS zone in a contract and, when set, a group. Primary zones are
+// created empty, so their default SOA and NS records are submitted right away.
+func (c *Client) CreateDNSZone(ctx context.Context, zone *dns.ZoneCreate, contractID, groupID string) error {
+	query := dns.ZoneQueryString{Contract: contractID, Group: groupID}
+	if err := c.dnsClient.CreateZone(ctx, zone, query); err != nil {
+		return fmt.Errorf("failed to create DNS zone %s: %w", zone.Zone, err)
+	}
+	if !strings.EqualFold(zone.Type, DNSZoneTypePrimary) {
+		return nil
+	}
+	if err := c.dnsClient.SaveChangelist(ctx, zone); err != nil {
+		return fmt.Errorf("failed to create the change list of DNS zone %s: %w", zone.Zone, err)
+	}
+	if err := c.dnsClient.SubmitChangelist(ctx, zone); err != nil {
+		return fmt.Errorf("failed to submit the default records of DNS zone %s: %w", zone.Zone, err)
+	}
+	return nil
+}
+
+// UpdateDNSZone updates the settings of an Edge DNS zone; its records are left untouched
+func (c *Client) UpdateDNSZone(ctx context.Context, zone *dns.ZoneCreate) error {
+	if err := c.dnsClient.UpdateZone(ctx, zone, dns.ZoneQueryString{}); err != nil {
+		return fmt.Errorf("failed to update DNS zone %s: %w", zone.Zone, err)
+	}
+	return nil
+}
+
+// DeleteDNSZone submits the deletion of an Edge DNS zone and returns the ID of the request. Edge
+// DNS deletes zones asynchronously, and refuses zones that still hold records other than SOA and NS.
+func (c *Client) DeleteDNSZone(ctx context.Context, zone string) (string, error) {
+	resp, err := c.dnsClient.DeleteBulkZones(ctx, &dns.ZoneNameListResponse{Zones: []string{zone}})
+	if err != nil {
+		return "", fmt.Errorf("failed to delete DNS zone %s: %w", zone, err)
+	}
+	return resp.RequestID, nil
+}
+
+// GetDNSSECStatus retrieves the DNSSEC records of a zone served with sign and serve
+func (c *Client) GetDNSSECStatus(ctx context.Context, zone string) (*dns.SecStatus, error) {
+	resp, err := c.dnsClient.GetZonesDNSSecStatus(ctx, dns.GetZonesDNSSecStatusRequest{Zones: []string{zone}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the DNSSEC status of DNS zone %s: %w", zone, err)
+	}
+	for _, status := range resp.DNSSecStatuses {
+		if status.Zone == zone {
+			return &status, nil
+		}
+	}
+	return nil, fmt.Errorf("no DNSSEC status returned for DNS zone %s", zone)
+}
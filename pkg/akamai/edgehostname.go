@@ -3,6 +3,7 @@ package akamai
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"strings"
 
 	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v8/pkg/papi"
@@ -15,6 +16,27 @@ func (c *Client) CreateEdgeHostname(ctx context.Context, spec *akamaiV1alpha1.Ed
 		return "", fmt.Errorf("edge hostname spec is nil")
 	}
 
+	createReq := papi.CreateEdgeHostnameRequest{
+		ContractID:   contractID,
+		GroupID:      groupID,
+		EdgeHostname: edgeHostnameCreate(spec, productID),
+	}
+
+	// Create the edge hostname
+	resp, err := c.papiClient.CreateEdgeHostname(ctx, createReq)
+	if err != nil {
+		return "", fmt.Errorf("failed to create edge hostname: %w", err)
+	}
+
+	if resp == nil || resp.EdgeHostnameID == "" {
+		return "", fmt.Errorf("invalid response from create edge hostname API")
+	}
+
+	return resp.EdgeHostnameID, nil
+}
+
+// edgeHostnameCreate builds the body of the request creating the edge hostname of a spec
+func edgeHostnameCreate(spec *akamaiV1alpha1.EdgeHostnameSpec, productID string) papi.EdgeHostnameCreate {
 	// Determine if this is a secure edge hostname
 	secure := strings.Contains(spec.DomainSuffix, "edgekey") ||
 		strings.Contains(spec.DomainSuffix, "akamaized") ||
@@ -26,33 +48,33 @@ func (c *Client) CreateEdgeHostname(ctx context.Context, spec *akamaiV1alpha1.Ed
 		ipVersionBehavior = "IPV4"
 	}
 
-	// Create edge hostname request
-	edgeHostnameCreate := papi.EdgeHostnameCreate{
+	return papi.EdgeHostnameCreate{
 		ProductID:         productID,
 		DomainPrefix:      spec.DomainPrefix,
 		DomainSuffix:      spec.DomainSuffix,
 		Secure:            secure,
 		SecureNetwork:     spec.SecureNetwork,
 		IPVersionBehavior: ipVersionBehavior,
+		CertEnrollmentID:  spec.CertEnrollmentID,
+		SlotNumber:        spec.SlotNumber,
+		UseCases:          edgeHostnameUseCases(spec.UseCases),
 	}
+}
 
-	createReq := papi.CreateEdgeHostnameRequest{
-		ContractID:   contractID,
-		GroupID:      groupID,
-		EdgeHostname: edgeHostnameCreate,
-	}
-
-	// Create the edge hostname
-	resp, err := c.papiClient.CreateEdgeHostname(ctx, createReq)
-	if err != nil {
-		return "", fmt.Errorf("failed to create edge hostname: %w", err)
+// edgeHostnameUseCases converts the use cases of an edge hostname spec, defaulting their type to GLOBAL
+func edgeHostnameUseCases(useCases []akamaiV1alpha1.EdgeHostnameUseCase) []papi.UseCase {
+	if len(useCases) == 0 {
+		return nil
 	}
-
-	if resp == nil || resp.EdgeHostnameID == "" {
-		return "", fmt.Errorf("invalid response from create edge hostname API")
+	result := make([]papi.UseCase, 0, len(useCases))
+	for _, u := range useCases {
+		useCaseType := u.Type
+		if useCaseType == "" {
+			useCaseType = "GLOBAL"
+		}
+		result = append(result, papi.UseCase{UseCase: u.UseCase, Option: u.Option, Type: useCaseType})
 	}
-
-	return resp.EdgeHostnameID, nil
+	return result
 }
 
 // GetEdgeHostname retrieves an edge hostname by ID
@@ -136,15 +158,16 @@ func (c *Client) GetOrCreateEdgeHostname(ctx context.Context, spec *akamaiV1alph
 }
 
 // EnsureEdgeHostnamesExist ensures all edge hostnames referenced in the hostname configuration exist
-func (c *Client) EnsureEdgeHostnamesExist(ctx context.Context, hostnames []akamaiV1alpha1.Hostname, edgeHostnameSpec *akamaiV1alpha1.EdgeHostnameSpec, productID, contractID, groupID string) error {
+// and returns the edge hostnames it created
+func (c *Client) EnsureEdgeHostnamesExist(ctx context.Context, hostnames []akamaiV1alpha1.Hostname, edgeHostnameSpec *akamaiV1alpha1.EdgeHostnameSpec, productID, contractID, groupID string) ([]string, error) {
 	if len(hostnames) == 0 {
-		return nil
+		return nil, nil
 	}
 
 	// Get all existing edge hostnames
 	existingEdgeHostnames, err := c.ListEdgeHostnames(ctx, contractID, groupID)
 	if err != nil {
-		return fmt.Errorf("failed to list edge hostnames: %w", err)
+		return nil, fmt.Errorf("failed to list edge hostnames: %w", err)
 	}
 
 	// Create a map of existing edge hostname domains
@@ -160,6 +183,7 @@ func (c *Client) EnsureEdgeHostnamesExist(ctx context.Context, hostnames []akama
 	}
 
 	// For each unique edge hostname, check if it exists
+	var created []string
 	for edgeHostname := range uniqueEdgeHostnames {
 		if !existingMap[edgeHostname] {
 			// Edge hostname doesn't exist
@@ -168,19 +192,60 @@ func (c *Client) EnsureEdgeHostnamesExist(ctx context.Context, hostnames []akama
 				// Verify that the edge hostname matches the spec
 				expectedEdgeHostname := edgeHostnameSpec.DomainPrefix + "." + edgeHostnameSpec.DomainSuffix
 				if edgeHostname != expectedEdgeHostname {
-					return fmt.Errorf("edge hostname %s does not match the edgeHostname spec (%s). Please ensure all hostname cnameTo values match the edgeHostname configuration", edgeHostname, expectedEdgeHostname)
+					return created, fmt.Errorf("edge hostname %s does not match the edgeHostname spec (%s). Please ensure all hostname cnameTo values match the edgeHostname configuration", edgeHostname, expectedEdgeHostname)
 				}
 
 				// Use the edgeHostnameSpec directly (don't parse from the string)
 				_, err := c.CreateEdgeHostname(ctx, edgeHostnameSpec, productID, contractID, groupID)
 				if err != nil {
-					return fmt.Errorf("failed to create edge hostname %s: %w", edgeHostname, err)
+					return created, fmt.Errorf("failed to create edge hostname %s: %w", edgeHostname, err)
 				}
+				created = append(created, edgeHostname)
 			} else {
-				return fmt.Errorf("edge hostname %s does not exist and no edge hostname spec provided to create it", edgeHostname)
+				return created, fmt.Errorf("edge hostname %s does not exist and no edge hostname spec provided to create it", edgeHostname)
 			}
 		}
 	}
 
-	return nil
+	return created, nil
+}
+
+// edgeHostnameZones are the DNS zones edge hostnames can be created in
+var edgeHostnameZones = []string{"edgesuite.net", "edgekey.net", "akamaized.net"}
+
+// splitEdgeHostnameZone splits an edge hostname into its record name and DNS zone,
+// e.g. "www.example.com.edgesuite.net" into "www.example.com" and "edgesuite.net"
+func splitEdgeHostnameZone(edgeHostname string) (string, string, error) {
+	for _, zone := range edgeHostnameZones {
+		if recordName, ok := strings.CutSuffix(edgeHostname, "."+zone); ok && recordName != "" {
+			return recordName, zone, nil
+		}
+	}
+	return "", "", fmt.Errorf("edge hostname %s is not in a known edge hostname zone", edgeHostname)
+}
+
+// DeleteEdgeHostname deletes an edge hostname using the Edge Hostnames API (HAPI). Akamai
+// rejects the deletion while any property version still uses the edge hostname.
+func (c *Client) DeleteEdgeHostname(ctx context.Context, edgeHostname string) error {
+	recordName, dnsZone, err := splitEdgeHostnameZone(edgeHostname)
+	if err != nil {
+		return err
+	}
+
+	deleteURL := fmt.Sprintf("/hapi/v1/dns-zones/%s/edge-hostnames/%s", dnsZone, recordName)
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, deleteURL, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create delete edge hostname request: %w", err)
+	}
+
+	resp, err := c.session.Exec(req, nil)
+	if err != nil {
+		return fmt.Errorf("failed to delete edge hostname %s: %w", edgeHostname, err)
+	}
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusAccepted, http.StatusNotFound:
+		return nil
+	default:
+		return fmt.Errorf("failed to delete edge hostname %s: %w", edgeHostname, newAPIError(resp))
+	}
 }
@@ -1,8 +1,10 @@
 package akamai
 
 import (
+	"reflect"
 	"testing"
 
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v8/pkg/papi"
 	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
 )
 
@@ -184,3 +186,88 @@ func TestEdgeHostnameSpecValidation(t *testing.T) {
 		})
 	}
 }
+
+func TestSplitEdgeHostnameZone(t *testing.T) {
+	tests := []struct {
+		edgeHostname       string
+		expectedRecordName string
+		expectedZone       string
+		expectErr          bool
+	}{
+		{edgeHostname: "www.example.com.edgesuite.net", expectedRecordName: "www.example.com", expectedZone: "edgesuite.net"},
+		{edgeHostname: "www.example.com.edgekey.net", expectedRecordName: "www.example.com", expectedZone: "edgekey.net"},
+		{edgeHostname: "example.akamaized.net", expectedRecordName: "example", expectedZone: "akamaized.net"},
+		{edgeHostname: "edgesuite.net", expectErr: true},
+		{edgeHostname: "www.example.com", expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.edgeHostname, func(t *testing.T) {
+			recordName, zone, err := splitEdgeHostnameZone(tt.edgeHostname)
+			if (err != nil) != tt.expectErr {
+				t.Fatalf("splitEdgeHostnameZone() error = %v, expectErr %v", err, tt.expectErr)
+			}
+			if recordName != tt.expectedRecordName || zone != tt.expectedZone {
+				t.Errorf("splitEdgeHostnameZone() = (%q, %q), expected (%q, %q)", recordName, zone, tt.expectedRecordName, tt.expectedZone)
+			}
+		})
+	}
+}
+
+func TestEdgeHostnameUseCases(t *testing.T) {
+	got := edgeHostnameUseCases([]akamaiV1alpha1.EdgeHostnameUseCase{
+		{UseCase: "Download_Mode", Option: "BACKGROUND"},
+		{UseCase: "Streaming", Option: "FOREGROUND", Type: "GLOBAL"},
+	})
+	expected := []papi.UseCase{
+		{UseCase: "Download_Mode", Option: "BACKGROUND", Type: "GLOBAL"},
+		{UseCase: "Streaming", Option: "FOREGROUND", Type: "GLOBAL"},
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("edgeHostnameUseCases() = %+v, expected %+v", got, expected)
+	}
+	if got := edgeHostnameUseCases(nil); got != nil {
+		t.Errorf("edgeHostnameUseCases(nil) = %+v, expected nil", got)
+	}
+}
+
+func TestEdgeHostnameCreate(t *testing.T) {
+	tests := []struct {
+		name     string
+		spec     akamaiV1alpha1.EdgeHostnameSpec
+		expected papi.EdgeHostnameCreate
+	}{
+		{
+			name: "standard TLS defaults",
+			spec: akamaiV1alpha1.EdgeHostnameSpec{DomainPrefix: "www.example.com", DomainSuffix: "edgesuite.net"},
+			expected: papi.EdgeHostnameCreate{
+				ProductID: "prd_Fresca", DomainPrefix: "www.example.com", DomainSuffix: "edgesuite.net",
+				IPVersionBehavior: "IPV4",
+			},
+		},
+		{
+			name: "enhanced TLS bound to a CPS enrollment",
+			spec: akamaiV1alpha1.EdgeHostnameSpec{
+				DomainPrefix: "www.example.com", DomainSuffix: "edgekey.net", SecureNetwork: "ENHANCED_TLS",
+				CertEnrollmentID: 123456, SlotNumber: 7, IPVersionBehavior: "IPV6_COMPLIANCE",
+			},
+			expected: papi.EdgeHostnameCreate{
+				ProductID: "prd_Fresca", DomainPrefix: "www.example.com", DomainSuffix: "edgekey.net",
+				Secure: true, SecureNetwork: "ENHANCED_TLS", CertEnrollmentID: 123456, SlotNumber: 7,
+				IPVersionBehavior: "IPV6_COMPLIANCE",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := edgeHostnameCreate(&tt.spec, "prd_Fresca")
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("edgeHostnameCreate() = %+v, expected %+v", got, tt.expected)
+			}
+			if err := got.Validate(); err != nil {
+				t.Errorf("edgeHostnameCreate() is not a valid request: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package akamai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v8/pkg/edgeworkers"
+)
+
+const (
+	// EdgeKVInitialized is the account status of an account whose EdgeKV database is ready
+	EdgeKVInitialized = "INITIALIZED"
+
+	// EdgeKVUninitialized is the account status of an account whose EdgeKV database was never
+	// initialized
+	EdgeKVUninitialized = "UNINITIALIZED"
+)
+
+// GetEdgeKVInitialization retrieves the initialization status of the EdgeKV database of the
+// account
+func (c *Client) GetEdgeKVInitialization(ctx context.Context) (*edgeworkers.EdgeKVInitializationStatus, error) {
+	status, err := c.edgeWorkersClient.GetEdgeKVInitializationStatus(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get EdgeKV initialization status: %w", err)
+	}
+	return status, nil
+}
+
+// InitializeEdgeKV starts the initialization of the EdgeKV database of the account
+func (c *Client) InitializeEdgeKV(ctx context.Context) (*edgeworkers.EdgeKVInitializationStatus, error) {
+	status, err := c.edgeWorkersClient.InitializeEdgeKV(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize EdgeKV: %w", err)
+	}
+	return status, nil
+}
+
+// GetEdgeKVNamespace retrieves an EdgeKV namespace on a network (staging or production),
+// returning nil when it doesn't exist
+func (c *Client) GetEdgeKVNamespace(ctx context.Context, network, name string) (*edgeworkers.Namespace, error) {
+	namespace, err := c.edgeWorkersClient.GetEdgeKVNamespace(ctx, edgeworkers.GetEdgeKVNamespaceRequest{
+		Network: edgeworkers.NamespaceNetwork(network),
+		Name:    name,
+	})
+	if err != nil {
+		if isEdgeKVNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get EdgeKV namespace %s on %s: %w", name, network, err)
+	}
+	return namespace, nil
+}
+
+// CreateEdgeKVNamespace creates an EdgeKV namespace on a network. Namespaces can't be deleted
+// through the API.
+func (c *Client) CreateEdgeKVNamespace(ctx context.Context, network string, namespace edgeworkers.Namespace) error {
+	if _, err := c.edgeWorkersClient.CreateEdgeKVNamespace(ctx, edgeworkers.CreateEdgeKVNamespaceRequest{
+		Network:   edgeworkers.NamespaceNetwork(network),
+		Namespace: namespace,
+	}); err != nil {
+		return fmt.Errorf("failed to create EdgeKV namespace %s on %s: %w", namespace.Name, network, err)
+	}
+	return nil
+}
+
+// UpdateEdgeKVNamespace changes the retention and the access group of an EdgeKV namespace; its
+// geo location can't be changed
+func (c *Client) UpdateEdgeKVNamespace(ctx context.Context, network string, namespace edgeworkers.UpdateNamespace) error {
+	if _, err := c.edgeWorkersClient.UpdateEdgeKVNamespace(ctx, edgeworkers.UpdateEdgeKVNamespaceRequest{
+		Network:         edgeworkers.NamespaceNetwork(network),
+		UpdateNamespace: namespace,
+	}); err != nil {
+		return fmt.Errorf("failed to update EdgeKV namespace %s on %s: %w", namespace.Name, network, err)
+	}
+	return nil
+}
+
+// ListEdgeKVItems returns the keys of the items of a group of an EdgeKV namespace; a group
+// without items doesn't exist and has no keys
+func (c *Client) ListEdgeKVItems(ctx context.Context, network, namespace, group string) ([]string, error) {
+	items, err := c.edgeWorkersClient.ListItems(ctx, edgeworkers.ListItemsRequest{
+		ItemsRequestParams: edgekvItemsParams(network, namespace, group),
+	})
+	if err != nil {
+		if isEdgeKVNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to list items of EdgeKV group %s/%s on %s: %w", namespace, group, network, err)
+	}
+	return *items, nil
+}
+
+// GetEdgeKVItem retrieves the value of an item of an EdgeKV namespace, returning nil when it
+// doesn't exist
+func (c *Client) GetEdgeKVItem(ctx context.Context, network, namespace, group, key string) (*string, error) {
+	item, err := c.edgeWorkersClient.GetItem(ctx, edgeworkers.GetItemRequest{
+		ItemID:             key,
+		ItemsRequestParams: edgekvItemsParams(network, namespace, group),
+	})
+	if err != nil {
+		if isEdgeKVNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get EdgeKV item %s/%s/%s on %s: %w", namespace, group, key, network, err)
+	}
+	value := string(*item)
+	return &value, nil
+}
+
+// PutEdgeKVItem creates or replaces an item of an EdgeKV namespace. Values that are JSON are
+// stored as JSON, all others as text.
+func (c *Client) PutEdgeKVItem(ctx context.Context, network, namespace, group, key, value string) error {
+	if _, err := c.edgeWorkersClient.UpsertItem(ctx, edgeworkers.UpsertItemRequest{
+		ItemID:             key,
+		ItemData:           edgeworkers.Item(value),
+		ItemsRequestParams: edgekvItemsParams(network, namespace, group),
+	}); err != nil {
+		return fmt.Errorf("failed to put EdgeKV item %s/%s/%s on %s: %w", namespace, group, key, network, err)
+	}
+	return nil
+}
+
+func edgekvItemsParams(network, namespace, group string) edgeworkers.ItemsRequestParams {
+	return edgeworkers.ItemsRequestParams{
+		Network:     edgeworkers.ItemNetwork(network),
+		NamespaceID: namespace,
+		GroupID:     group,
+	}
+}
+
+func isEdgeKVNotFound(err error) bool {
+	var edgeKVErr *edgeworkers.Error
+	return errors.As(err, &edgeKVErr) && edgeKVErr.Status == http.StatusNotFound
+}
@@ -0,0 +1,125 @@
+package akamai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v8/pkg/gtm"
+)
+
+const (
+	// GTMPropagationComplete is the propagation status of a GTM domain whose changes are live
+	GTMPropagationComplete = "COMPLETE"
+
+	// GTMPropagationPending is the propagation status of a GTM domain whose changes are being
+	// rolled out
+	GTMPropagationPending = "PENDING"
+
+	// GTMPropagationDenied is the propagation status of a GTM domain whose changes were rejected
+	GTMPropagationDenied = "DENIED"
+)
+
+// GetGTMDomain retrieves a GTM domain with its datacenters and properties, returning nil when it
+// doesn't exist
+func (c *Client) GetGTMDomain(ctx context.Context, name string) (*gtm.Domain, error) {
+	domain, err := c.gtmClient.GetDomain(ctx, name)
+	if err != nil {
+		if isGTMNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get GTM domain %s: %w", name, err)
+	}
+	return domain, nil
+}
+
+// CreateGTMDomain creates a GTM domain in a contract and group
+func (c *Client) CreateGTMDomain(ctx context.Context, domain *gtm.Domain, contractID, groupID string) error {
+	queryArgs := map[string]string{"contractId": contractID}
+	if groupID != "" {
+		queryArgs["gid"] = groupID
+	}
+	if _, err := c.gtmClient.CreateDomain(ctx, domain, queryArgs); err != nil {
+		return fmt.Errorf("failed to create GTM domain %s: %w", domain.Name, err)
+	}
+	return nil
+}
+
+// UpdateGTMDomain replaces the settings of a GTM domain
+func (c *Client) UpdateGTMDomain(ctx context.Context, domain *gtm.Domain) error {
+	if _, err := c.gtmClient.UpdateDomain(ctx, domain, nil); err != nil {
+		return fmt.Errorf("failed to update GTM domain %s: %w", domain.Name, err)
+	}
+	return nil
+}
+
+// DeleteGTMDomain deletes a GTM domain; a domain that doesn't exist anymore is not an error
+func (c *Client) DeleteGTMDomain(ctx context.Context, name string) error {
+	if _, err := c.gtmClient.DeleteDomain(ctx, &gtm.Domain{Name: name}); err != nil && !isGTMNotFound(err) {
+		return fmt.Errorf("failed to delete GTM domain %s: %w", name, err)
+	}
+	return nil
+}
+
+// GetGTMDomainStatus returns the propagation status of the last change of a GTM domain
+func (c *Client) GetGTMDomainStatus(ctx context.Context, name string) (*gtm.ResponseStatus, error) {
+	status, err := c.gtmClient.GetDomainStatus(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get status of GTM domain %s: %w", name, err)
+	}
+	return status, nil
+}
+
+// ListGTMDatacenters returns the datacenters of a GTM domain
+func (c *Client) ListGTMDatacenters(ctx context.Context, domain string) ([]*gtm.Datacenter, error) {
+	datacenters, err := c.gtmClient.ListDatacenters(ctx, domain)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list datacenters of GTM domain %s: %w", domain, err)
+	}
+	return datacenters, nil
+}
+
+// CreateGTMDatacenter creates a datacenter in a GTM domain and returns its ID
+func (c *Client) CreateGTMDatacenter(ctx context.Context, domain string, datacenter *gtm.Datacenter) (int, error) {
+	resp, err := c.gtmClient.CreateDatacenter(ctx, datacenter, domain)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create datacenter %s in GTM domain %s: %w", datacenter.Nickname, domain, err)
+	}
+	if resp.Resource == nil {
+		return 0, fmt.Errorf("datacenter %s was created in GTM domain %s without an ID", datacenter.Nickname, domain)
+	}
+	return resp.Resource.DatacenterID, nil
+}
+
+// UpdateGTMDatacenter replaces the settings of a datacenter of a GTM domain
+func (c *Client) UpdateGTMDatacenter(ctx context.Context, domain string, datacenter *gtm.Datacenter) error {
+	if _, err := c.gtmClient.UpdateDatacenter(ctx, datacenter, domain); err != nil {
+		return fmt.Errorf("failed to update datacenter %s in GTM domain %s: %w", datacenter.Nickname, domain, err)
+	}
+	return nil
+}
+
+// ListGTMProperties returns the properties of a GTM domain
+func (c *Client) ListGTMProperties(ctx context.Context, domain string) ([]*gtm.Property, error) {
+	properties, err := c.gtmClient.ListProperties(ctx, domain)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list properties of GTM domain %s: %w", domain, err)
+	}
+	return properties, nil
+}
+
+// PutGTMProperty creates a property of a GTM domain or replaces it
+func (c *Client) PutGTMProperty(ctx context.Context, domain string, property *gtm.Property) error {
+	if _, err := c.gtmClient.UpdateProperty(ctx, property, domain); err != nil {
+		return fmt.Errorf("failed to save property %s of GTM domain %s: %w", property.Name, domain, err)
+	}
+	return nil
+}
+
+// isGTMNotFound reports whether a Global Traffic Management request failed because the resource
+// doesn't exist
+func isGTMNotFound(err error) bool {
+	var gtmErr *gtm.Error
+	return errors.As(err, &gtmErr) && gtmErr.StatusCode == http.StatusNotFound
+}
@@ -2,7 +2,9 @@ package akamai
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/http"
 
 	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v8/pkg/papi"
 	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
@@ -11,10 +13,11 @@ import (
 // GetPropertyHostnames retrieves hostnames for a specific property version
 func (c *Client) GetPropertyHostnames(ctx context.Context, propertyID, contractID, groupID string, version int) ([]Hostname, error) {
 	getHostnamesReq := papi.GetPropertyVersionHostnamesRequest{
-		PropertyID:      propertyID,
-		PropertyVersion: version,
-		ContractID:      contractID,
-		GroupID:         groupID,
+		PropertyID:        propertyID,
+		PropertyVersion:   version,
+		ContractID:        contractID,
+		GroupID:           groupID,
+		IncludeCertStatus: true,
 	}
 
 	resp, err := c.papiClient.GetPropertyVersionHostnames(ctx, getHostnamesReq)
@@ -34,6 +37,14 @@ func (c *Client) GetPropertyHostnames(ctx context.Context, propertyID, contractI
 			CNAMETo:              h.CnameTo,
 			CertProvisioningType: h.CertProvisioningType,
 		}
+		if len(h.CertStatus.Staging) > 0 {
+			hostname.StagingCertStatus = h.CertStatus.Staging[0].Status
+		}
+		if len(h.CertStatus.Production) > 0 {
+			hostname.ProductionCertStatus = h.CertStatus.Production[0].Status
+		}
+		hostname.ValidationCNAME = h.CertStatus.ValidationCname.Hostname
+		hostname.ValidationTarget = h.CertStatus.ValidationCname.Target
 		hostnames = append(hostnames, hostname)
 	}
 
@@ -109,6 +120,117 @@ func (c *Client) SetPropertyHostnames(ctx context.Context, propertyID, contractI
 	return nil
 }
 
+// hostnamesPatch is the body of a PATCH request against the property version hostnames
+type hostnamesPatch struct {
+	Add    []papi.Hostname `json:"add"`
+	Remove []string        `json:"remove"`
+}
+
+// PatchPropertyHostnames adds and removes individual hostnames of a property version using
+// PATCH semantics, leaving all other hostnames untouched
+func (c *Client) PatchPropertyHostnames(ctx context.Context, propertyID, contractID, groupID string, version int, add []akamaiV1alpha1.Hostname, remove []string) error {
+	if len(add) == 0 && len(remove) == 0 {
+		return nil
+	}
+
+	body := hostnamesPatch{
+		Add:    make([]papi.Hostname, 0, len(add)),
+		Remove: remove,
+	}
+	if body.Remove == nil {
+		body.Remove = []string{}
+	}
+	for _, h := range add {
+		body.Add = append(body.Add, papi.Hostname{
+			CnameType:            papi.HostnameCnameTypeEdgeHostname,
+			CnameFrom:            h.CNAMEFrom,
+			CnameTo:              h.CNAMETo,
+			CertProvisioningType: h.CertProvisioningType,
+		})
+	}
+
+	patchURL := fmt.Sprintf("/papi/v1/properties/%s/versions/%d/hostnames?contractId=%s&groupId=%s",
+		propertyID, version, contractID, groupID)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPatch, patchURL, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create patch hostnames request: %w", err)
+	}
+
+	resp, err := c.session.Exec(req, nil, body)
+	if err != nil {
+		return fmt.Errorf("failed to patch property hostnames: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to patch property hostnames: %w", newAPIError(resp))
+	}
+
+	return nil
+}
+
+// ErrHostnameConflict is returned by UpdateProperty when a hostname to add is already active on
+// another property
+var ErrHostnameConflict = errors.New("hostname is active on another property")
+
+// checkHostnameConflicts returns ErrHostnameConflict if one of the hostnames is active on a
+// property other than propertyID
+func (c *Client) checkHostnameConflicts(ctx context.Context, propertyID string, hostnames []akamaiV1alpha1.Hostname) error {
+	for _, h := range hostnames {
+		results, err := c.FindPropertiesByHostname(ctx, h.CNAMEFrom)
+		if err != nil {
+			return err
+		}
+		for _, result := range results {
+			if result.PropertyID != propertyID {
+				return fmt.Errorf("%w: %s is active on %s (%s)", ErrHostnameConflict, h.CNAMEFrom, result.PropertyName, result.PropertyID)
+			}
+		}
+	}
+	return nil
+}
+
+// ComputeHostnameDelta computes the hostnames to add and remove to move the current hostnames
+// towards the desired ones. Only hostnames listed in managed are removed, so hostnames added
+// outside the operator are preserved; a nil managed list treats all current hostnames as managed.
+// Hostnames whose target changed are both removed and added again.
+func ComputeHostnameDelta(desired []akamaiV1alpha1.Hostname, current []Hostname, managed []string) ([]akamaiV1alpha1.Hostname, []string) {
+	currentMap := make(map[string]Hostname, len(current))
+	for _, h := range current {
+		currentMap[h.CNAMEFrom] = h
+	}
+	desiredMap := make(map[string]bool, len(desired))
+	for _, h := range desired {
+		desiredMap[h.CNAMEFrom] = true
+	}
+	managedMap := make(map[string]bool, len(managed))
+	for _, name := range managed {
+		managedMap[name] = true
+	}
+
+	var add []akamaiV1alpha1.Hostname
+	var remove []string
+	for _, dh := range desired {
+		ch, exists := currentMap[dh.CNAMEFrom]
+		if !exists {
+			add = append(add, dh)
+			continue
+		}
+		if dh.CNAMETo != ch.CNAMETo || (dh.CertProvisioningType != "" && dh.CertProvisioningType != ch.CertProvisioningType) {
+			remove = append(remove, dh.CNAMEFrom)
+			add = append(add, dh)
+		}
+	}
+	for _, ch := range current {
+		if desiredMap[ch.CNAMEFrom] {
+			continue
+		}
+		if managed == nil || managedMap[ch.CNAMEFrom] {
+			remove = append(remove, ch.CNAMEFrom)
+		}
+	}
+
+	return add, remove
+}
+
 // CompareHostnames compares two sets of hostnames and returns true if they differ
 func CompareHostnames(desired []akamaiV1alpha1.Hostname, current []Hostname) bool {
 	if len(desired) != len(current) {
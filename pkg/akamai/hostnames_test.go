@@ -232,3 +232,43 @@ func TestCompareHostnamesWithMultipleHostnames(t *testing.T) {
 		t.Error("Expected hostnames to be different, but CompareHostnames returned false (same)")
 	}
 }
+
+func TestComputeHostnameDelta(t *testing.T) {
+	current := []Hostname{
+		{CNAMEFrom: "www.example.com", CNAMETo: "example.com.edgesuite.net"},
+		{CNAMEFrom: "old.example.com", CNAMETo: "example.com.edgesuite.net"},
+		{CNAMEFrom: "include.example.com", CNAMETo: "example.com.edgesuite.net"},
+		{CNAMEFrom: "api.example.com", CNAMETo: "example.com.edgesuite.net"},
+	}
+	desired := []akamaiV1alpha1.Hostname{
+		{CNAMEFrom: "www.example.com", CNAMETo: "example.com.edgesuite.net"},
+		{CNAMEFrom: "api.example.com", CNAMETo: "example.com.edgekey.net"},
+		{CNAMEFrom: "new.example.com", CNAMETo: "example.com.edgesuite.net"},
+	}
+
+	t.Run("only managed hostnames are removed", func(t *testing.T) {
+		managed := []string{"www.example.com", "old.example.com", "api.example.com"}
+		add, remove := ComputeHostnameDelta(desired, current, managed)
+
+		if len(add) != 2 || add[0].CNAMEFrom != "api.example.com" || add[1].CNAMEFrom != "new.example.com" {
+			t.Errorf("unexpected add: %+v", add)
+		}
+		if len(remove) != 2 || remove[0] != "api.example.com" || remove[1] != "old.example.com" {
+			t.Errorf("unexpected remove: %v", remove)
+		}
+	})
+
+	t.Run("nil managed list removes all undesired hostnames", func(t *testing.T) {
+		_, remove := ComputeHostnameDelta(desired, current, nil)
+		if len(remove) != 3 {
+			t.Errorf("expected 3 removals, got %v", remove)
+		}
+	})
+
+	t.Run("no changes", func(t *testing.T) {
+		add, remove := ComputeHostnameDelta(desired[:1], current[:1], []string{"www.example.com"})
+		if len(add) != 0 || len(remove) != 0 {
+			t.Errorf("expected empty delta, got add=%+v remove=%v", add, remove)
+		}
+	})
+}
@@ -0,0 +1,262 @@
+package akamai
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v8/pkg/papi"
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v8/pkg/session"
+)
+
+// GetIncludeRules retrieves the rule tree of the latest version of an include
+func (c *Client) GetIncludeRules(ctx context.Context, includeID, contractID, groupID string) (*IncludeRules, error) {
+	include, err := c.GetInclude(ctx, includeID, contractID, groupID)
+	if err != nil {
+		return nil, err
+	}
+	return c.GetIncludeVersionRules(ctx, includeID, include.LatestVersion, contractID, groupID)
+}
+
+// GetInclude retrieves an include with its latest and active versions
+func (c *Client) GetInclude(ctx context.Context, includeID, contractID, groupID string) (*Include, error) {
+	includeResp, err := c.papiClient.GetInclude(ctx, papi.GetIncludeRequest{
+		IncludeID:  includeID,
+		ContractID: contractID,
+		GroupID:    groupID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get include %s: %w", includeID, err)
+	}
+	if includeResp == nil || len(includeResp.Includes.Items) == 0 {
+		return nil, fmt.Errorf("include %s not found", includeID)
+	}
+	return toInclude(includeResp.Includes.Items[0]), nil
+}
+
+// FindIncludeByName returns the include of the given name in a contract and group, or nil if
+// there is none
+func (c *Client) FindIncludeByName(ctx context.Context, includeName, contractID, groupID string) (*Include, error) {
+	includesResp, err := c.papiClient.ListIncludes(ctx, papi.ListIncludesRequest{
+		ContractID: contractID,
+		GroupID:    groupID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list includes: %w", err)
+	}
+	if includesResp == nil {
+		return nil, fmt.Errorf("empty response from list includes API")
+	}
+	for _, include := range includesResp.Includes.Items {
+		if include.IncludeName == includeName {
+			return toInclude(include), nil
+		}
+	}
+	return nil, nil
+}
+
+// CreateInclude creates an include of the given type and returns its ID. An empty rule format
+// creates the include with the latest rule format.
+func (c *Client) CreateInclude(ctx context.Context, includeName, includeType, productID, ruleFormat, contractID, groupID string) (string, error) {
+	createResp, err := c.papiClient.CreateInclude(ctx, papi.CreateIncludeRequest{
+		ContractID:  contractID,
+		GroupID:     groupID,
+		IncludeName: includeName,
+		IncludeType: papi.IncludeType(includeType),
+		ProductID:   productID,
+		RuleFormat:  ruleFormat,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to create include %s: %w", includeName, err)
+	}
+	if createResp == nil || createResp.IncludeID == "" {
+		return "", fmt.Errorf("invalid response from create include API")
+	}
+	return createResp.IncludeID, nil
+}
+
+// IsIncludeVersionEditable reports whether an include version was never activated; activated
+// versions are locked and changes need a new version
+func (c *Client) IsIncludeVersionEditable(ctx context.Context, includeID string, version int, contractID, groupID string) (bool, error) {
+	versionResp, err := c.papiClient.GetIncludeVersion(ctx, papi.GetIncludeVersionRequest{
+		IncludeID:  includeID,
+		Version:    version,
+		ContractID: contractID,
+		GroupID:    groupID,
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to get version %d of include %s: %w", version, includeID, err)
+	}
+	if versionResp == nil {
+		return false, fmt.Errorf("empty response from get include version API")
+	}
+	v := versionResp.IncludeVersion
+	return v.StagingStatus == papi.VersionStatusInactive && v.ProductionStatus == papi.VersionStatusInactive, nil
+}
+
+// CreateIncludeVersion creates a new include version from an existing one and returns its number
+func (c *Client) CreateIncludeVersion(ctx context.Context, includeID string, fromVersion int) (int, error) {
+	versionResp, err := c.papiClient.CreateIncludeVersion(ctx, papi.CreateIncludeVersionRequest{
+		IncludeID: includeID,
+		IncludeVersionRequest: papi.IncludeVersionRequest{
+			CreateFromVersion: fromVersion,
+		},
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to create version of include %s from version %d: %w", includeID, fromVersion, err)
+	}
+	if versionResp == nil || versionResp.Version == 0 {
+		return 0, fmt.Errorf("invalid response from create include version API")
+	}
+	return versionResp.Version, nil
+}
+
+// GetIncludeVersionRules retrieves the rule tree of an include version
+func (c *Client) GetIncludeVersionRules(ctx context.Context, includeID string, version int, contractID, groupID string) (*IncludeRules, error) {
+	rulesResp, err := c.papiClient.GetIncludeRuleTree(ctx, papi.GetIncludeRuleTreeRequest{
+		IncludeID:      includeID,
+		IncludeVersion: version,
+		ContractID:     contractID,
+		GroupID:        groupID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rules of include %s version %d: %w", includeID, version, err)
+	}
+	if rulesResp == nil {
+		return nil, fmt.Errorf("empty response from get include rule tree API")
+	}
+
+	return &IncludeRules{
+		IncludeID:      includeID,
+		IncludeName:    rulesResp.IncludeName,
+		IncludeVersion: version,
+		Etag:           rulesResp.Etag,
+		Rules:          rulesResp.Rules,
+	}, nil
+}
+
+// UpdateIncludeRules replaces the rule tree of an include version. A non-empty etag guards the
+// update against concurrent edits like UpdatePropertyRules.
+func (c *Client) UpdateIncludeRules(ctx context.Context, includeID string, version int, contractID, groupID string, rules interface{}, etag string) (*IncludeRules, error) {
+	papiRules, err := toPapiRules(rules)
+	if err != nil {
+		return nil, err
+	}
+	update := papi.RulesUpdate{Rules: papiRules}
+	if err := c.checkRuleTreeSize(update); err != nil {
+		return nil, err
+	}
+	if etag != "" {
+		ctx = session.ContextWithOptions(ctx, session.WithContextHeaders(http.Header{"If-Match": []string{etag}}))
+	}
+
+	updateResp, err := c.papiClient.UpdateIncludeRuleTree(ctx, papi.UpdateIncludeRuleTreeRequest{
+		IncludeID:      includeID,
+		IncludeVersion: version,
+		ContractID:     contractID,
+		GroupID:        groupID,
+		Rules:          update,
+		ValidateRules:  true,
+		ValidateMode:   "full",
+	})
+	if isEtagConflict(err) {
+		return nil, fmt.Errorf("%w: include %s version %d", ErrEtagConflict, includeID, version)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to update rules of include %s version %d: %w", includeID, version, err)
+	}
+	if updateResp == nil {
+		return nil, fmt.Errorf("empty response from update include rule tree API")
+	}
+
+	includeRules := &IncludeRules{
+		IncludeID:      includeID,
+		IncludeName:    updateResp.IncludeName,
+		IncludeVersion: version,
+		Etag:           updateResp.Etag,
+		Rules:          updateResp.Rules,
+		Warnings:       dedupeRuleWarnings(toRuleWarnings(updateResp.Warnings)),
+	}
+	if len(updateResp.Errors) > 0 {
+		var errorMessages []string
+		for _, ruleError := range updateResp.Errors {
+			errorMessages = append(errorMessages, fmt.Sprintf("%s: %s", ruleError.Title, ruleError.Detail))
+		}
+		return includeRules, fmt.Errorf("rule validation errors: %v", errorMessages)
+	}
+	return includeRules, nil
+}
+
+// ActivateInclude activates an include version on the specified network and returns the ID of
+// the activation
+func (c *Client) ActivateInclude(ctx context.Context, includeID string, version int, network, note string, notifyEmails []string, acknowledgeAllWarnings bool) (string, error) {
+	activationResp, err := c.papiClient.ActivateInclude(ctx, papi.ActivateIncludeRequest{
+		IncludeID:              includeID,
+		Version:                version,
+		Network:                papi.ActivationNetwork(network),
+		Note:                   note,
+		NotifyEmails:           notifyEmails,
+		AcknowledgeAllWarnings: acknowledgeAllWarnings,
+	})
+	if err != nil {
+		if warnings := activationWarningsFromError(err); len(warnings) > 0 {
+			return "", &WarningsNotAcknowledgedError{Warnings: warnings}
+		}
+		return "", fmt.Errorf("failed to activate include %s version %d on %s: %w", includeID, version, network, err)
+	}
+	if activationResp == nil {
+		return "", fmt.Errorf("invalid response from activate include API")
+	}
+	if activationResp.ActivationID != "" {
+		return activationResp.ActivationID, nil
+	}
+	if activationID := extractActivationIDFromLink(activationResp.ActivationLink); activationID != "" {
+		return activationID, nil
+	}
+	return "", fmt.Errorf("invalid response from activate include API")
+}
+
+// GetIncludeActivationStatus returns the status of an include activation, e.g. PENDING or ACTIVE
+func (c *Client) GetIncludeActivationStatus(ctx context.Context, includeID, activationID string) (string, error) {
+	activationResp, err := c.papiClient.GetIncludeActivation(ctx, papi.GetIncludeActivationRequest{
+		IncludeID:    includeID,
+		ActivationID: activationID,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to get activation %s of include %s: %w", activationID, includeID, err)
+	}
+	if activationResp == nil {
+		return "", fmt.Errorf("empty response from get include activation API")
+	}
+	return string(activationResp.Activation.Status), nil
+}
+
+// toRuleWarnings converts the warnings of an include rule tree update, which PAPI returns as
+// errors, to rule warnings
+func toRuleWarnings(warnings []*papi.Error) []papi.RuleWarnings {
+	converted := make([]papi.RuleWarnings, 0, len(warnings))
+	for _, w := range warnings {
+		if w == nil {
+			continue
+		}
+		converted = append(converted, papi.RuleWarnings{Title: w.Title, Type: w.Type, ErrorLocation: w.ErrorLocation, Detail: w.Detail})
+	}
+	return converted
+}
+
+// toInclude converts a PAPI include
+func toInclude(include papi.Include) *Include {
+	converted := &Include{
+		IncludeID:     include.IncludeID,
+		IncludeName:   include.IncludeName,
+		IncludeType:   string(include.IncludeType),
+		LatestVersion: include.LatestVersion,
+	}
+	if include.StagingVersion != nil {
+		converted.StagingVersion = *include.StagingVersion
+	}
+	if include.ProductionVersion != nil {
+		converted.ProductionVersion = *include.ProductionVersion
+	}
+	return converted
+}
@@ -0,0 +1,110 @@
+package akamai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v8/pkg/networklists"
+)
+
+// Network list activation statuses
+const (
+	NetworkListStatusActive            = "ACTIVE"
+	NetworkListStatusInactive          = "INACTIVE"
+	NetworkListStatusPendingActivation = "PENDING_ACTIVATION"
+	NetworkListStatusFailed            = "FAILED"
+)
+
+// GetNetworkList retrieves a network list with its elements, returning nil when it doesn't exist
+func (c *Client) GetNetworkList(ctx context.Context, uniqueID string) (*networklists.GetNetworkListResponse, error) {
+	list, err := c.networkListsClient.GetNetworkList(ctx, networklists.GetNetworkListRequest{UniqueID: uniqueID})
+	if err != nil {
+		if isNetworkListNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get network list %s: %w", uniqueID, err)
+	}
+	return list, nil
+}
+
+// FindNetworkList returns the ID of the network list with exactly the given name and type, or ""
+// when there is none
+func (c *Client) FindNetworkList(ctx context.Context, name, listType string) (string, error) {
+	resp, err := c.networkListsClient.GetNetworkLists(ctx, networklists.GetNetworkListsRequest{Name: name, Type: listType})
+	if err != nil {
+		return "", fmt.Errorf("failed to search network list %s: %w", name, err)
+	}
+	// The search matches parts of the name too
+	for _, list := range resp.NetworkLists {
+		if list.Name == name && list.Type == listType {
+			return list.UniqueID, nil
+		}
+	}
+	return "", nil
+}
+
+// CreateNetworkList creates a network list and returns its ID
+func (c *Client) CreateNetworkList(ctx context.Context, list networklists.CreateNetworkListRequest) (string, error) {
+	resp, err := c.networkListsClient.CreateNetworkList(ctx, list)
+	if err != nil {
+		return "", fmt.Errorf("failed to create network list %s: %w", list.Name, err)
+	}
+	return resp.UniqueID, nil
+}
+
+// UpdateNetworkList replaces the description and the elements of a network list. The sync point
+// must be the current one, so concurrent changes aren't overwritten.
+func (c *Client) UpdateNetworkList(ctx context.Context, list networklists.UpdateNetworkListRequest) error {
+	if _, err := c.networkListsClient.UpdateNetworkList(ctx, list); err != nil {
+		return fmt.Errorf("failed to update network list %s: %w", list.UniqueID, err)
+	}
+	return nil
+}
+
+// DeleteNetworkList deletes a network list; a list that doesn't exist anymore is not an error.
+// Akamai refuses lists that are active or used by a security configuration.
+func (c *Client) DeleteNetworkList(ctx context.Context, uniqueID string) error {
+	if _, err := c.networkListsClient.RemoveNetworkList(ctx, networklists.RemoveNetworkListRequest{UniqueID: uniqueID}); err != nil && !isNetworkListNotFound(err) {
+		return fmt.Errorf("failed to delete network list %s: %w", uniqueID, err)
+	}
+	return nil
+}
+
+// GetNetworkListActivation retrieves the activation status of a network list on a network
+// (STAGING or PRODUCTION); a list never activated there is INACTIVE
+func (c *Client) GetNetworkListActivation(ctx context.Context, uniqueID, network string) (*networklists.GetActivationsResponse, error) {
+	resp, err := c.networkListsClient.GetActivations(ctx, networklists.GetActivationsRequest{UniqueID: uniqueID, Network: network})
+	if err != nil {
+		if isNetworkListNotFound(err) {
+			return &networklists.GetActivationsResponse{UniqueID: uniqueID, ActivationStatus: NetworkListStatusInactive}, nil
+		}
+		return nil, fmt.Errorf("failed to get %s activation of network list %s: %w", network, uniqueID, err)
+	}
+	return resp, nil
+}
+
+// ActivateNetworkList activates the current sync point of a network list on a network
+func (c *Client) ActivateNetworkList(ctx context.Context, uniqueID, network, comments string, notify []string) (*networklists.CreateActivationsResponse, error) {
+	// The API refuses a missing list of recipients
+	if notify == nil {
+		notify = []string{}
+	}
+	resp, err := c.networkListsClient.CreateActivations(ctx, networklists.CreateActivationsRequest{
+		UniqueID:               uniqueID,
+		Network:                network,
+		Comments:               comments,
+		NotificationRecipients: notify,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to activate network list %s on %s: %w", uniqueID, network, err)
+	}
+	return resp, nil
+}
+
+// isNetworkListNotFound reports whether a Network Lists request failed because the list doesn't exist
+func isNetworkListNotFound(err error) bool {
+	var listErr *networklists.Error
+	return errors.As(err, &listErr) && listErr.StatusCode == http.StatusNotFound
+}
@@ -0,0 +1,39 @@
+package akamai
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"sync/atomic"
+)
+
+// ErrObserveOnly is returned for requests that would change something in Akamai while the
+// operator only observes
+var ErrObserveOnly = errors.New("mutating Akamai request refused in observe-only mode")
+
+var observeOnly atomic.Bool
+
+// SetObserveOnly makes all clients refuse requests that change something in Akamai. Reads, rule
+// validations (dry runs), searches and reports still go through.
+func SetObserveOnly(enabled bool) {
+	observeOnly.Store(enabled)
+}
+
+// ObserveOnly reports whether clients refuse requests that change something in Akamai
+func ObserveOnly() bool {
+	return observeOnly.Load()
+}
+
+// readOnlyRequest reports whether a request leaves Akamai unchanged
+func readOnlyRequest(r *http.Request) bool {
+	switch r.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	}
+	if r.URL.Query().Get("dryRun") == "true" {
+		return true
+	}
+	// Searches and reports are queries sent as POST
+	return r.Method == http.MethodPost &&
+		(r.URL.Path == "/papi/v1/search/find-by-value" || strings.HasPrefix(r.URL.Path, "/reporting-api/"))
+}
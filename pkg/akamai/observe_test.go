@@ -0,0 +1,50 @@
+package akamai
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestReadOnlyRequest(t *testing.T) {
+	tests := []struct {
+		method   string
+		url      string
+		expected bool
+	}{
+		{method: http.MethodGet, url: "/papi/v1/properties/prp_1?contractId=ctr_1-ABC", expected: true},
+		{method: http.MethodPut, url: "/papi/v1/properties/prp_1/versions/3/rules?contractId=ctr_1-ABC&dryRun=true", expected: true},
+		{method: http.MethodPost, url: "/papi/v1/search/find-by-value", expected: true},
+		{method: http.MethodPost, url: "/reporting-api/v1/reports/delivery/traffic/current/versions/1/report-data", expected: true},
+		{method: http.MethodPut, url: "/papi/v1/properties/prp_1/versions/3/rules?contractId=ctr_1-ABC"},
+		{method: http.MethodPost, url: "/papi/v1/properties?contractId=ctr_1-ABC"},
+		{method: http.MethodPatch, url: "/papi/v1/properties/prp_1/versions/3/hostnames"},
+		{method: http.MethodDelete, url: "/papi/v1/properties/prp_1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.url, func(t *testing.T) {
+			r, err := http.NewRequest(tt.method, tt.url, nil)
+			if err != nil {
+				t.Fatalf("failed to build request: %v", err)
+			}
+			if got := readOnlyRequest(r); got != tt.expected {
+				t.Errorf("readOnlyRequest() = %v, expected %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestObserveOnlySession(t *testing.T) {
+	SetObserveOnly(true)
+	defer SetObserveOnly(false)
+
+	r, err := http.NewRequest(http.MethodDelete, "/papi/v1/properties/prp_1", nil)
+	if err != nil {
+		t.Fatalf("failed to build request: %v", err)
+	}
+	// The request is refused before it reaches the limiter or the session
+	if _, err := (rateLimitedSession{}).Exec(r, nil); !errors.Is(err, ErrObserveOnly) {
+		t.Errorf("Exec() = %v, expected %v", err, ErrObserveOnly)
+	}
+}
@@ -0,0 +1,48 @@
+package akamai
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v8/pkg/papi"
+)
+
+// MaxRuleTreeSize is the largest serialized rule tree the operator sends to PAPI; larger rule
+// trees are rejected by the API and should be split into includes
+const MaxRuleTreeSize = 2 * 1024 * 1024
+
+// PayloadTooLargeError is returned before a rule tree is sent when its serialized size exceeds
+// the PAPI limit
+type PayloadTooLargeError struct {
+	// Size is the serialized size of the request body in bytes
+	Size int
+	// Limit is the exceeded limit in bytes
+	Limit int
+	// LimitName describes the exceeded limit
+	LimitName string
+}
+
+func (e *PayloadTooLargeError) Error() string {
+	return fmt.Sprintf("rule tree is %s, exceeding the %s of %s; move rules into includes to reduce its size",
+		formatBytes(e.Size), e.LimitName, formatBytes(e.Limit))
+}
+
+// checkRuleTreeSize fails with a PayloadTooLargeError if the serialized rule tree exceeds the
+// PAPI limit, which would otherwise surface as an opaque API error halfway through the update.
+// The max body of the EdgeGrid config only caps how much of a POST body is signed; it doesn't
+// limit rule trees, which are PUT.
+func (c *Client) checkRuleTreeSize(update papi.RulesUpdate) error {
+	body, err := json.Marshal(update)
+	if err != nil {
+		return fmt.Errorf("failed to marshal rule tree: %w", err)
+	}
+	if len(body) > MaxRuleTreeSize {
+		return &PayloadTooLargeError{Size: len(body), Limit: MaxRuleTreeSize, LimitName: "PAPI rule tree limit"}
+	}
+	return nil
+}
+
+// formatBytes renders a byte count with its KiB equivalent, e.g. "150.0 KiB (153600 bytes)"
+func formatBytes(size int) string {
+	return fmt.Sprintf("%.1f KiB (%d bytes)", float64(size)/1024, size)
+}
@@ -0,0 +1,64 @@
+package akamai
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v8/pkg/papi"
+)
+
+func TestCheckRuleTreeSize(t *testing.T) {
+	// rulesOfSize returns a rule tree whose comments pad it to roughly size bytes
+	rulesOfSize := func(size int) papi.RulesUpdate {
+		return papi.RulesUpdate{Rules: papi.Rules{Name: "default", Comments: strings.Repeat("x", size)}}
+	}
+
+	tests := []struct {
+		name          string
+		update        papi.RulesUpdate
+		expectedLimit int
+	}{
+		{name: "small rule tree", update: rulesOfSize(1024)},
+		{name: "larger than the default max body", update: rulesOfSize(200 * 1024)},
+		{name: "exceeds PAPI limit", update: rulesOfSize(MaxRuleTreeSize), expectedLimit: MaxRuleTreeSize},
+	}
+
+	// The default config signs requests with a max body of 128 KiB
+	c, err := NewClient(Credentials{
+		Host:         "akab-test.luna.akamaiapis.net",
+		ClientToken:  "akab-client-token-0123456789",
+		ClientSecret: "client-secret-0123456789",
+		AccessToken:  "akab-access-token-0123456789",
+	})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := c.checkRuleTreeSize(tt.update)
+			if tt.expectedLimit == 0 {
+				if err != nil {
+					t.Fatalf("checkRuleTreeSize() unexpected error: %v", err)
+				}
+				return
+			}
+			var tooLarge *PayloadTooLargeError
+			if !errors.As(err, &tooLarge) {
+				t.Fatalf("checkRuleTreeSize() error = %v, expected a PayloadTooLargeError", err)
+			}
+			if tooLarge.Limit != tt.expectedLimit || tooLarge.Size <= tt.expectedLimit {
+				t.Errorf("checkRuleTreeSize() = size %d, limit %d, expected a size above limit %d", tooLarge.Size, tooLarge.Limit, tt.expectedLimit)
+			}
+		})
+	}
+}
+
+func TestPayloadTooLargeErrorMessage(t *testing.T) {
+	err := &PayloadTooLargeError{Size: 2200000, Limit: MaxRuleTreeSize, LimitName: "PAPI rule tree limit"}
+	expected := "rule tree is 2148.4 KiB (2200000 bytes), exceeding the PAPI rule tree limit of 2048.0 KiB (2097152 bytes); move rules into includes to reduce its size"
+	if err.Error() != expected {
+		t.Errorf("Error() = %q, expected %q", err.Error(), expected)
+	}
+}
@@ -0,0 +1,18 @@
+package akamai
+
+import (
+	"net/http"
+)
+
+// PermissionDenied reports whether err is an API response denying the credentials access, and
+// returns the API's explanation if it gave one
+func PermissionDenied(err error) (string, bool) {
+	apiErr, ok := AsAPIError(err)
+	if !ok || apiErr.StatusCode != http.StatusForbidden {
+		return "", false
+	}
+	if apiErr.Detail != "" {
+		return apiErr.Detail, true
+	}
+	return apiErr.Title, true
+}
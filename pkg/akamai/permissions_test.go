@@ -0,0 +1,49 @@
+package akamai
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v8/pkg/papi"
+)
+
+func TestPermissionDenied(t *testing.T) {
+	tests := []struct {
+		name           string
+		err            error
+		expectedDetail string
+		expectedDenied bool
+	}{
+		{name: "no error", err: nil},
+		{name: "plain error", err: errors.New("timeout")},
+		{name: "other API error", err: &papi.Error{StatusCode: http.StatusNotFound, Detail: "not found"}},
+		{
+			name:           "forbidden with detail",
+			err:            fmt.Errorf("create version: %w", &papi.Error{StatusCode: http.StatusForbidden, Title: "Forbidden", Detail: "not allowed to write to ctr_1"}),
+			expectedDetail: "not allowed to write to ctr_1",
+			expectedDenied: true,
+		},
+		{name: "forbidden with title only", err: &papi.Error{StatusCode: http.StatusForbidden, Title: "Forbidden"}, expectedDetail: "Forbidden", expectedDenied: true},
+		{
+			name: "raw request forbidden",
+			err: fmt.Errorf("failed to patch property hostnames: %w", newAPIError(&http.Response{StatusCode: http.StatusForbidden,
+				Body: io.NopCloser(strings.NewReader(`{"title": "Forbidden", "detail": "no access to prp_1"}`))})),
+			expectedDetail: "no access to prp_1",
+			expectedDenied: true,
+		},
+		{name: "raw request forbidden without body", err: newAPIError(&http.Response{StatusCode: http.StatusForbidden}), expectedDenied: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			detail, denied := PermissionDenied(tt.err)
+			if denied != tt.expectedDenied || detail != tt.expectedDetail {
+				t.Errorf("PermissionDenied() = (%q, %v), expected (%q, %v)", detail, denied, tt.expectedDetail, tt.expectedDenied)
+			}
+		})
+	}
+}
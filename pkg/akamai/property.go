@@ -10,6 +10,20 @@ import (
 
 // CreateProperty creates a new property in Akamai
 func (c *Client) CreateProperty(ctx context.Context, spec *akamaiV1alpha1.AkamaiPropertySpec) (string, error) {
+	return c.createProperty(ctx, spec, nil)
+}
+
+// CloneProperty creates a new property in Akamai whose first version is a copy of the rules of
+// version fromVersion of another property. Hostnames are not copied.
+func (c *Client) CloneProperty(ctx context.Context, spec *akamaiV1alpha1.AkamaiPropertySpec, fromPropertyID string, fromVersion int) (string, error) {
+	return c.createProperty(ctx, spec, &papi.PropertyCloneFrom{
+		PropertyID: fromPropertyID,
+		Version:    fromVersion,
+	})
+}
+
+// createProperty creates a new property, optionally cloned from another property
+func (c *Client) createProperty(ctx context.Context, spec *akamaiV1alpha1.AkamaiPropertySpec, cloneFrom *papi.PropertyCloneFrom) (string, error) {
 	// Create property request
 	createReq := papi.CreatePropertyRequest{
 		ContractID: spec.ContractID,
@@ -17,7 +31,8 @@ func (c *Client) CreateProperty(ctx context.Context, spec *akamaiV1alpha1.Akamai
 		Property: papi.PropertyCreate{
 			PropertyName: spec.PropertyName,
 			ProductID:    spec.ProductID,
-			RuleFormat:   "v2023-01-05", // Use a recent rule format
+			RuleFormat:   RuleFormatOrDefault(spec.RuleFormat),
+			CloneFrom:    cloneFrom,
 		},
 	}
 
@@ -26,6 +41,8 @@ func (c *Client) CreateProperty(ctx context.Context, spec *akamaiV1alpha1.Akamai
 	if err != nil {
 		return "", fmt.Errorf("failed to create property: %w", err)
 	}
+	// A new property changes the results of name searches used for duplicate detection
+	c.search.invalidate()
 
 	if createResp == nil || createResp.PropertyLink == "" {
 		return "", fmt.Errorf("invalid response from create property API")
@@ -113,114 +130,88 @@ func (c *Client) IsVersionPublished(ctx context.Context, propertyID string, vers
 	return false, "", nil
 }
 
-// GetOrCreateUnpublishedVersion returns the latest version if it's not published,
-// or creates a new version if the latest is published
-func (c *Client) GetOrCreateUnpublishedVersion(ctx context.Context, propertyID, contractID, groupID string) (int, bool, error) {
-	// Get property details
-	property, err := c.GetProperty(ctx, propertyID)
-	if err != nil {
-		return 0, false, fmt.Errorf("failed to get property: %w", err)
-	}
-
-	// Check if the latest version is published
-	isPublished, _, err := c.IsVersionPublished(ctx, propertyID, property.LatestVersion)
-	if err != nil {
-		return 0, false, fmt.Errorf("failed to check if version is published: %w", err)
-	}
-
-	if !isPublished {
-		// Latest version is not published, we can use it
-		return property.LatestVersion, false, nil
-	}
-
-	// Latest version is published, create a new one
+// CreatePropertyVersion creates a new property version cloned from fromVersion and returns its number
+func (c *Client) CreatePropertyVersion(ctx context.Context, propertyID, contractID, groupID string, fromVersion int) (int, error) {
 	newVersionReq := papi.CreatePropertyVersionRequest{
 		PropertyID: propertyID,
 		ContractID: contractID,
 		GroupID:    groupID,
 		Version: papi.PropertyVersionCreate{
-			CreateFromVersion: property.LatestVersion,
+			CreateFromVersion: fromVersion,
 		},
 	}
 
 	newVersionResp, err := c.papiClient.CreatePropertyVersion(ctx, newVersionReq)
 	if err != nil {
-		return 0, false, fmt.Errorf("failed to create new property version: %w", err)
+		return 0, fmt.Errorf("failed to create new property version from version %d: %w", fromVersion, err)
 	}
 
 	if newVersionResp == nil || newVersionResp.VersionLink == "" {
-		return 0, false, fmt.Errorf("invalid response from create property version API")
+		return 0, fmt.Errorf("invalid response from create property version API")
 	}
 
-	newVersion := newVersionResp.VersionLink
-	versionNumber, err := extractVersionFromLink(newVersion)
+	versionNumber, err := extractVersionFromLink(newVersionResp.VersionLink)
 	if err != nil {
-		return 0, false, fmt.Errorf("failed to extract version number: %w", err)
+		return 0, fmt.Errorf("failed to extract version number: %w", err)
 	}
 
-	return versionNumber, true, nil
+	return versionNumber, nil
 }
 
-// UpdateProperty updates an existing property in Akamai
-func (c *Client) UpdateProperty(ctx context.Context, propertyID string, spec *akamaiV1alpha1.AkamaiPropertySpec) (int, error) {
-	// First, get the current property to get the latest version
+// GetOrCreateUnpublishedVersion returns the latest version if it's not published,
+// or creates a new version if the latest is published
+func (c *Client) GetOrCreateUnpublishedVersion(ctx context.Context, propertyID, contractID, groupID string) (int, bool, error) {
+	// Get property details
 	property, err := c.GetProperty(ctx, propertyID)
 	if err != nil {
-		return 0, fmt.Errorf("failed to get current property: %w", err)
+		return 0, false, fmt.Errorf("failed to get property: %w", err)
 	}
 
-	// Check if the latest version is published on staging or production
-	isPublished, network, err := c.IsVersionPublished(ctx, propertyID, property.LatestVersion)
+	// Check if the latest version is published
+	isPublished, _, err := c.IsVersionPublished(ctx, propertyID, property.LatestVersion)
 	if err != nil {
-		return 0, fmt.Errorf("failed to check if version is published: %w", err)
-	}
-
-	var versionToUpdate int
-	if isPublished {
-		// The latest version is published, we need to create a new version
-		// Create a new version of the property
-		newVersionReq := papi.CreatePropertyVersionRequest{
-			PropertyID: propertyID,
-			ContractID: spec.ContractID,
-			GroupID:    spec.GroupID,
-			Version: papi.PropertyVersionCreate{
-				CreateFromVersion: property.LatestVersion,
-			},
-		}
+		return 0, false, fmt.Errorf("failed to check if version is published: %w", err)
+	}
 
-		newVersionResp, err := c.papiClient.CreatePropertyVersion(ctx, newVersionReq)
-		if err != nil {
-			return 0, fmt.Errorf("failed to create new property version (latest version %d is published on %s): %w", property.LatestVersion, network, err)
-		}
+	if !isPublished {
+		// Latest version is not published, we can use it
+		return property.LatestVersion, false, nil
+	}
 
-		if newVersionResp == nil || newVersionResp.VersionLink == "" {
-			return 0, fmt.Errorf("invalid response from create property version API")
-		}
+	// Latest version is published, create a new one
+	versionNumber, err := c.CreatePropertyVersion(ctx, propertyID, contractID, groupID, property.LatestVersion)
+	if err != nil {
+		return 0, false, err
+	}
+
+	return versionNumber, true, nil
+}
 
-		newVersion := newVersionResp.VersionLink
-		versionNumber, err := extractVersionFromLink(newVersion)
+// UpdateProperty applies the spec to an editable property version. Hostnames are updated with a
+// differential PATCH; managedHostnames lists the hostnames previously applied by the operator.
+// Hostnames active on another property are not added and fail the update with ErrHostnameConflict.
+// Which version is edited is decided by the caller according to the property's version strategy.
+func (c *Client) UpdateProperty(ctx context.Context, propertyID string, version int, spec *akamaiV1alpha1.AkamaiPropertySpec, managedHostnames []string) error {
+	// Update hostnames if specified in spec, sending only the delta to the version's hostnames
+	if len(spec.Hostnames) > 0 {
+		currentHostnames, err := c.GetPropertyHostnames(ctx, propertyID, spec.ContractID, spec.GroupID, version)
 		if err != nil {
-			return 0, fmt.Errorf("failed to extract version number: %w", err)
+			return fmt.Errorf("failed to get property hostnames: %w", err)
 		}
 
-		versionToUpdate = versionNumber
-	} else {
-		// The latest version is not published, we can update it directly
-		versionToUpdate = property.LatestVersion
-	}
-
-	// Update hostnames if specified in spec
-	if len(spec.Hostnames) > 0 {
-		err = c.SetPropertyHostnames(ctx, propertyID, spec.ContractID, spec.GroupID, versionToUpdate, spec.Hostnames)
+		add, remove := ComputeHostnameDelta(spec.Hostnames, currentHostnames, managedHostnames)
+		if err := c.checkHostnameConflicts(ctx, propertyID, add); err != nil {
+			return err
+		}
+		err = c.PatchPropertyHostnames(ctx, propertyID, spec.ContractID, spec.GroupID, version, add, remove)
 		if err != nil {
-			return 0, fmt.Errorf("failed to update property hostnames: %w", err)
+			return fmt.Errorf("failed to update property hostnames: %w", err)
 		}
 	}
 
-	// TODO: Update property rules if needed
 	// Rules are handled separately by the controller
 
-	return versionToUpdate, nil
+	return nil
 }
 
 // DeleteProperty deletes a property from Akamai
@@ -234,6 +225,7 @@ func (c *Client) DeleteProperty(ctx context.Context, propertyID string) error {
 	if err != nil {
 		return fmt.Errorf("failed to remove property: %w", err)
 	}
+	c.search.invalidate()
 
 	return nil
 }
@@ -0,0 +1,107 @@
+package akamai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+// Fast Purge actions
+const (
+	PurgeActionInvalidate = "invalidate"
+	PurgeActionDelete     = "delete"
+)
+
+// Fast Purge object types
+const (
+	PurgeTypeURL    = "url"
+	PurgeTypeCPCode = "cpcode"
+	PurgeTypeTag    = "tag"
+)
+
+// maxPurgeBodySize is the largest request body the Fast Purge API accepts; larger lists of
+// objects are purged in several requests
+const maxPurgeBodySize = 50000
+
+// PurgeResult is the outcome of the Fast Purge requests of a list of objects
+type PurgeResult struct {
+	// PurgeIDs are the IDs of the accepted requests, one per batch of objects
+	PurgeIDs []string
+
+	// EstimatedSeconds is the longest time Akamai estimated a request to take
+	EstimatedSeconds int
+}
+
+// purgeRequest is the body of a Fast Purge request
+type purgeRequest struct {
+	Objects []interface{} `json:"objects"`
+}
+
+// purgeResponse is the relevant part of an accepted Fast Purge request
+type purgeResponse struct {
+	EstimatedSeconds int    `json:"estimatedSeconds"`
+	PurgeID          string `json:"purgeId"`
+}
+
+// Purge invalidates or deletes URLs, CP codes or cache tags on a network (staging or
+// production) with the Fast Purge API
+func (c *Client) Purge(ctx context.Context, action, objectType, network string, objects []string) (*PurgeResult, error) {
+	values := make([]interface{}, 0, len(objects))
+	for _, object := range objects {
+		if objectType != PurgeTypeCPCode {
+			values = append(values, object)
+			continue
+		}
+		cpCode, err := strconv.ParseInt(object, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CP code %q: %w", object, err)
+		}
+		values = append(values, cpCode)
+	}
+
+	result := &PurgeResult{}
+	purgeURL := fmt.Sprintf("/ccu/v3/%s/%s/%s", action, objectType, network)
+	for _, batch := range purgeBatches(values) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, purgeURL, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create purge request: %w", err)
+		}
+		var purge purgeResponse
+		resp, err := c.session.Exec(req, &purge, purgeRequest{Objects: batch})
+		if err != nil {
+			return nil, fmt.Errorf("failed to %s %d objects on %s: %w", action, len(batch), network, err)
+		}
+		if resp.StatusCode != http.StatusCreated {
+			return nil, fmt.Errorf("failed to %s %d objects on %s: %w", action, len(batch), network, newAPIError(resp))
+		}
+		result.PurgeIDs = append(result.PurgeIDs, purge.PurgeID)
+		result.EstimatedSeconds = max(result.EstimatedSeconds, purge.EstimatedSeconds)
+	}
+	return result, nil
+}
+
+// purgeBatches splits the objects into batches whose request bodies stay within the size the
+// Fast Purge API accepts
+func purgeBatches(objects []interface{}) [][]interface{} {
+	emptyBody, _ := json.Marshal(purgeRequest{Objects: []interface{}{}})
+	var batches [][]interface{}
+	var batch []interface{}
+	size := len(emptyBody)
+	for _, object := range objects {
+		encoded, _ := json.Marshal(object)
+		// Objects after the first are separated by a comma
+		objectSize := len(encoded) + 1
+		if len(batch) > 0 && size+objectSize > maxPurgeBodySize {
+			batches = append(batches, batch)
+			batch, size = nil, len(emptyBody)
+		}
+		batch = append(batch, object)
+		size += objectSize
+	}
+	if len(batch) > 0 {
+		batches = append(batches, batch)
+	}
+	return batches
+}
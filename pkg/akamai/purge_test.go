@@ -0,0 +1,37 @@
+package akamai
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestPurgeBatches(t *testing.T) {
+	tests := []struct {
+		name     string
+		objects  int
+		size     int
+		expected []int
+	}{
+		{name: "no objects", objects: 0, size: 10},
+		{name: "single batch", objects: 100, size: 100, expected: []int{100}},
+		{name: "split at the body limit", objects: 1000, size: 100, expected: []int{485, 485, 30}},
+		{name: "oversized object", objects: 2, size: maxPurgeBodySize, expected: []int{1, 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			objects := make([]interface{}, tt.objects)
+			for i := range objects {
+				objects[i] = strings.Repeat("a", tt.size-len(fmt.Sprint(i))) + fmt.Sprint(i)
+			}
+			var got []int
+			for _, batch := range purgeBatches(objects) {
+				got = append(got, len(batch))
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.expected) {
+				t.Errorf("purgeBatches() sizes = %v, expected %v", got, tt.expected)
+			}
+		})
+	}
+}
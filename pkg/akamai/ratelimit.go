@@ -0,0 +1,231 @@
+package akamai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v8/pkg/session"
+	"golang.org/x/time/rate"
+)
+
+// Default client-side request budget of an account, below the PAPI rate limits so a full resync
+// of many properties doesn't run into 429 responses
+const (
+	DefaultRequestRate  = 5.0
+	DefaultRequestBurst = 20
+)
+
+// rateLimitFallbackPause is how long requests pause after a 429 response without a hint when to retry
+const rateLimitFallbackPause = 5 * time.Second
+
+// rateLimiters holds the limiter of each account, shared by all clients of the account
+var rateLimiters = newRateLimiterRegistry(DefaultRequestRate, DefaultRequestBurst)
+
+// SetRateLimit configures the client-side request budget of each account for clients created
+// afterwards: requestsPerSecond with bursts of up to burst requests. A rate of zero disables the
+// budget; pauses requested by the X-RateLimit-* response headers are honored regardless.
+func SetRateLimit(requestsPerSecond float64, burst int) {
+	rateLimiters.configure(requestsPerSecond, burst)
+}
+
+// rateLimiterRegistry creates one limiter per account
+type rateLimiterRegistry struct {
+	mu                  sync.Mutex
+	rate                float64
+	burst               int
+	contractConcurrency int
+	limiters            map[string]*accountLimiter
+}
+
+func newRateLimiterRegistry(requestsPerSecond float64, burst int) *rateLimiterRegistry {
+	return &rateLimiterRegistry{
+		rate:                requestsPerSecond,
+		burst:               burst,
+		contractConcurrency: DefaultContractConcurrency,
+		limiters:            make(map[string]*accountLimiter),
+	}
+}
+
+func (r *rateLimiterRegistry) configure(requestsPerSecond float64, burst int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.rate = requestsPerSecond
+	r.burst = burst
+	r.limiters = make(map[string]*accountLimiter)
+}
+
+func (r *rateLimiterRegistry) configureContracts(requests int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.contractConcurrency = requests
+	r.limiters = make(map[string]*accountLimiter)
+}
+
+// forAccount returns the limiter of the account identified by the API host and the account
+// switch key
+func (r *rateLimiterRegistry) forAccount(host, accountSwitchKey string) *accountLimiter {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	key := accountKey(host, accountSwitchKey)
+	if limiter, ok := r.limiters[key]; ok {
+		return limiter
+	}
+	limiter := newAccountLimiter(r.rate, r.burst)
+	limiter.contracts = newContractSlots(r.contractConcurrency)
+	r.limiters[key] = limiter
+	return limiter
+}
+
+// accountKey identifies an account by the API host of its API client and the account switch key
+func accountKey(host, accountSwitchKey string) string {
+	return host + "/" + accountSwitchKey
+}
+
+// accountLimiter is a token bucket for the requests of an account, paused when the API reports
+// the account's rate limit as exhausted
+type accountLimiter struct {
+	limiter *rate.Limiter
+
+	// contracts bounds the requests in flight per contract; nil doesn't limit them
+	contracts *contractSlots
+
+	mu          sync.Mutex
+	now         func() time.Time
+	pausedUntil time.Time
+}
+
+func newAccountLimiter(requestsPerSecond float64, burst int) *accountLimiter {
+	limit := rate.Limit(requestsPerSecond)
+	if requestsPerSecond <= 0 {
+		limit = rate.Inf
+	}
+	if burst < 1 {
+		burst = 1
+	}
+	return &accountLimiter{limiter: rate.NewLimiter(limit, burst), now: time.Now}
+}
+
+// wait blocks until a request may be sent or ctx is done
+func (l *accountLimiter) wait(ctx context.Context) error {
+	l.mu.Lock()
+	pause := l.pausedUntil.Sub(l.now())
+	l.mu.Unlock()
+	if pause > 0 {
+		timer := time.NewTimer(pause)
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+	return l.limiter.Wait(ctx)
+}
+
+// observe pauses the requests of the account when a response reports its rate limit as
+// exhausted. Akamai sends X-RateLimit-Remaining with X-RateLimit-Next, the time the next request
+// is accepted; 429 responses may carry Retry-After instead.
+func (l *accountLimiter) observe(resp *http.Response) {
+	if resp == nil {
+		return
+	}
+	remaining := resp.Header.Get("X-RateLimit-Remaining")
+	if resp.StatusCode != http.StatusTooManyRequests && remaining != "0" {
+		return
+	}
+
+	now := l.now()
+	until := now.Add(rateLimitFallbackPause)
+	if next, err := time.Parse(time.RFC3339, resp.Header.Get("X-RateLimit-Next")); err == nil {
+		until = next
+	} else if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+		until = now.Add(time.Duration(seconds) * time.Second)
+	} else if resp.StatusCode != http.StatusTooManyRequests {
+		// The last request of the window was accepted; the bucket keeps the pace until then
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if until.After(l.pausedUntil) {
+		l.pausedUntil = until
+	}
+}
+
+// retryAfter returns how long the requests of the account are paused, at least
+// rateLimitFallbackPause as the limiter was asked because a request was throttled
+func (l *accountLimiter) retryAfter() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if pause := l.pausedUntil.Sub(l.now()); pause > rateLimitFallbackPause {
+		return pause
+	}
+	return rateLimitFallbackPause
+}
+
+// RateLimitedError is a request Akamai rejected with 429 Too Many Requests. RetryAfter is how
+// long until the account's rate limit accepts requests again.
+type RateLimitedError struct {
+	RetryAfter time.Duration
+	Err        error
+}
+
+// Error renders the wrapped error and when to retry
+func (e *RateLimitedError) Error() string {
+	return fmt.Sprintf("rate limited, retry after %s: %v", e.RetryAfter, e.Err)
+}
+
+// Unwrap returns the wrapped error
+func (e *RateLimitedError) Unwrap() error {
+	return e.Err
+}
+
+// AsRateLimited returns err as RateLimitedError when it wraps a 429 response of the client's
+// account. The papi package drops the response headers from its errors, so the delay is taken
+// from the account's limiter, which saw the response.
+func (c *Client) AsRateLimited(err error) (*RateLimitedError, bool) {
+	var limited *RateLimitedError
+	if errors.As(err, &limited) {
+		return limited, true
+	}
+	apiErr, ok := AsAPIError(err)
+	if !ok || apiErr.StatusCode != http.StatusTooManyRequests {
+		return nil, false
+	}
+	retryAfter := rateLimitFallbackPause
+	if c != nil && c.limiter != nil {
+		retryAfter = c.limiter.retryAfter()
+	}
+	return &RateLimitedError{RetryAfter: retryAfter, Err: err}, true
+}
+
+// rateLimitedSession waits for a slot of the request's contract and the account's limiter before
+// a request is signed and sent
+type rateLimitedSession struct {
+	session.Session
+	limiter *accountLimiter
+}
+
+// Exec waits for the contract slot and the limiter, then signs and executes the request. In
+// observe-only mode requests changing something in Akamai are refused before they are sent.
+func (s rateLimitedSession) Exec(r *http.Request, out interface{}, in ...interface{}) (*http.Response, error) {
+	if ObserveOnly() && !readOnlyRequest(r) {
+		return nil, fmt.Errorf("%s %s: %w", r.Method, r.URL.Path, ErrObserveOnly)
+	}
+	release, err := s.limiter.contracts.acquire(r.Context(), requestContract(r))
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	if err := s.limiter.wait(r.Context()); err != nil {
+		return nil, err
+	}
+	resp, err := s.Session.Exec(r, out, in...)
+	s.limiter.observe(resp)
+	return resp, err
+}
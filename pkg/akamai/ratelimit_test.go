@@ -0,0 +1,167 @@
+package akamai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v8/pkg/papi"
+)
+
+func TestAccountLimiterObserve(t *testing.T) {
+	now := time.Date(2026, 10, 18, 10, 0, 0, 0, time.UTC)
+	response := func(status int, headers map[string]string) *http.Response {
+		resp := &http.Response{StatusCode: status, Header: http.Header{}}
+		for key, value := range headers {
+			resp.Header.Set(key, value)
+		}
+		return resp
+	}
+
+	tests := []struct {
+		name     string
+		resp     *http.Response
+		expected time.Time
+	}{
+		{name: "budget left", resp: response(http.StatusOK, map[string]string{"X-RateLimit-Limit": "100", "X-RateLimit-Remaining": "42"})},
+		{
+			name:     "budget exhausted",
+			resp:     response(http.StatusOK, map[string]string{"X-RateLimit-Remaining": "0", "X-RateLimit-Next": "2026-10-18T10:00:30Z"}),
+			expected: now.Add(30 * time.Second),
+		},
+		{name: "last request of the window without next", resp: response(http.StatusOK, map[string]string{"X-RateLimit-Remaining": "0"})},
+		{
+			name:     "throttled with retry after",
+			resp:     response(http.StatusTooManyRequests, map[string]string{"Retry-After": "12"}),
+			expected: now.Add(12 * time.Second),
+		},
+		{name: "throttled without hint", resp: response(http.StatusTooManyRequests, nil), expected: now.Add(rateLimitFallbackPause)},
+		{name: "no response"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limiter := newAccountLimiter(DefaultRequestRate, DefaultRequestBurst)
+			limiter.now = func() time.Time { return now }
+			limiter.observe(tt.resp)
+			if !limiter.pausedUntil.Equal(tt.expected) {
+				t.Errorf("pausedUntil = %s, expected %s", limiter.pausedUntil, tt.expected)
+			}
+		})
+	}
+}
+
+func TestAccountLimiterWait(t *testing.T) {
+	limiter := newAccountLimiter(0, 1)
+	if err := limiter.wait(context.Background()); err != nil {
+		t.Fatalf("wait() of an unlimited budget unexpected error: %v", err)
+	}
+
+	limiter.pausedUntil = time.Now().Add(time.Hour)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := limiter.wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("wait() during a pause = %v, expected %v", err, context.DeadlineExceeded)
+	}
+
+	// One request per hour: the second request of the burst has to wait
+	limiter = newAccountLimiter(1.0/3600, 1)
+	if err := limiter.wait(context.Background()); err != nil {
+		t.Fatalf("wait() unexpected error: %v", err)
+	}
+	ctx, cancel = context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := limiter.wait(ctx); err == nil {
+		t.Error("wait() expected the budget to be exhausted")
+	}
+}
+
+func TestRateLimiterRegistry(t *testing.T) {
+	registry := newRateLimiterRegistry(DefaultRequestRate, DefaultRequestBurst)
+	account := registry.forAccount("akab-1.luna.akamaiapis.net", "")
+	if registry.forAccount("akab-1.luna.akamaiapis.net", "") != account {
+		t.Error("clients of the same account must share a limiter")
+	}
+	if registry.forAccount("akab-1.luna.akamaiapis.net", "1-ABCD") == account {
+		t.Error("a switched account must have its own limiter")
+	}
+	if registry.forAccount("akab-2.luna.akamaiapis.net", "") == account {
+		t.Error("another API client must have its own limiter")
+	}
+}
+
+func TestClientAccount(t *testing.T) {
+	credentials := Credentials{
+		Host:         "https://akab-1.luna.akamaiapis.net/",
+		ClientToken:  "akab-client-token-0123456789",
+		ClientSecret: "client-secret-0123456789",
+		AccessToken:  "akab-access-token-0123456789",
+	}
+	account := func(credentials Credentials) string {
+		t.Helper()
+		c, err := NewClient(credentials)
+		if err != nil {
+			t.Fatalf("NewClient() error = %v", err)
+		}
+		return c.Account()
+	}
+
+	first := account(credentials)
+	if first != "akab-1.luna.akamaiapis.net/" || account(credentials) != first {
+		t.Errorf("Account() = %q, expected clients of the same credentials to share the account", first)
+	}
+	credentials.AccountSwitchKey = "1-ABCD"
+	if account(credentials) == first {
+		t.Error("a switched account must have its own identity")
+	}
+	if (*Client)(nil).Account() != "" {
+		t.Error("expected a nil client to have an empty account")
+	}
+}
+
+func TestAsRateLimited(t *testing.T) {
+	now := time.Date(2026, 10, 18, 10, 0, 0, 0, time.UTC)
+	limiter := newAccountLimiter(DefaultRequestRate, DefaultRequestBurst)
+	limiter.now = func() time.Time { return now }
+	limiter.pausedUntil = now.Add(42 * time.Second)
+	client := &Client{limiter: limiter}
+	throttled := fmt.Errorf("failed to update property rules: %w", &papi.Error{StatusCode: http.StatusTooManyRequests})
+
+	tests := []struct {
+		name     string
+		client   *Client
+		err      error
+		expected time.Duration
+		ok       bool
+	}{
+		{name: "throttled", client: client, err: throttled, expected: 42 * time.Second, ok: true},
+		{name: "throttled without a client", err: throttled, expected: rateLimitFallbackPause, ok: true},
+		{
+			name:     "already typed",
+			client:   client,
+			err:      fmt.Errorf("failed: %w", &RateLimitedError{RetryAfter: time.Minute, Err: throttled}),
+			expected: time.Minute,
+			ok:       true,
+		},
+		{name: "other status", client: client, err: &papi.Error{StatusCode: http.StatusConflict}},
+		{name: "network error", client: client, err: errors.New("connection refused")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limited, ok := tt.client.AsRateLimited(tt.err)
+			if ok != tt.ok {
+				t.Fatalf("AsRateLimited() ok = %v, expected %v", ok, tt.ok)
+			}
+			if ok && limited.RetryAfter != tt.expected {
+				t.Errorf("RetryAfter = %s, expected %s", limited.RetryAfter, tt.expected)
+			}
+			if ok && !errors.Is(limited, tt.err) && !errors.Is(tt.err, limited) {
+				t.Errorf("AsRateLimited() lost the wrapped error")
+			}
+		})
+	}
+}
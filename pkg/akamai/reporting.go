@@ -0,0 +1,110 @@
+package akamai
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// Reporting API reports used for the traffic metrics
+const (
+	reportBytesByCPCode = "bytes-by-cpcode"
+	reportHitsByCPCode  = "hits-by-cpcode"
+)
+
+// reportRequest is the body of a Reporting API report-data request
+type reportRequest struct {
+	ObjectType string   `json:"objectType"`
+	ObjectIDs  []string `json:"objectIds"`
+	Metrics    []string `json:"metrics"`
+}
+
+// reportResponse is the relevant part of a Reporting API report-data response
+type reportResponse struct {
+	Data []map[string]interface{} `json:"data"`
+}
+
+// GetCPCodeTraffic returns the traffic and offload metrics of the given CP codes between start and end
+func (c *Client) GetCPCodeTraffic(ctx context.Context, cpCodes []string, start, end time.Time) ([]CPCodeTraffic, error) {
+	if len(cpCodes) == 0 {
+		return nil, nil
+	}
+
+	bytes, err := c.getReportData(ctx, reportBytesByCPCode, cpCodes,
+		[]string{"edgeBytesTotal", "originBytesTotal", "bytesOffload"}, start, end)
+	if err != nil {
+		return nil, err
+	}
+	hits, err := c.getReportData(ctx, reportHitsByCPCode, cpCodes,
+		[]string{"edgeHitsTotal", "originHitsTotal", "hitsOffload"}, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	traffic := make([]CPCodeTraffic, 0, len(cpCodes))
+	for _, cpCode := range cpCodes {
+		traffic = append(traffic, CPCodeTraffic{
+			CPCode:       cpCode,
+			EdgeBytes:    bytes[cpCode]["edgeBytesTotal"],
+			OriginBytes:  bytes[cpCode]["originBytesTotal"],
+			BytesOffload: bytes[cpCode]["bytesOffload"],
+			EdgeHits:     hits[cpCode]["edgeHitsTotal"],
+			OriginHits:   hits[cpCode]["originHitsTotal"],
+			HitsOffload:  hits[cpCode]["hitsOffload"],
+		})
+	}
+	return traffic, nil
+}
+
+// getReportData runs a Reporting API report for the CP codes and returns the metrics per CP code
+func (c *Client) getReportData(ctx context.Context, report string, cpCodes, metrics []string, start, end time.Time) (map[string]map[string]float64, error) {
+	query := url.Values{}
+	query.Set("start", start.UTC().Format(time.RFC3339))
+	query.Set("end", end.UTC().Format(time.RFC3339))
+	reportURL := fmt.Sprintf("/reporting-api/v1/reports/%s/versions/1/report-data?%s", report, query.Encode())
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, reportURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s report request: %w", report, err)
+	}
+
+	var result reportResponse
+	body := reportRequest{ObjectType: "cpcode", ObjectIDs: cpCodes, Metrics: metrics}
+	resp, err := c.session.Exec(req, &result, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s report: %w", report, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to get %s report: %w", report, newAPIError(resp))
+	}
+
+	return parseReportData(result.Data, metrics), nil
+}
+
+// parseReportData indexes report rows by CP code. The Reporting API returns metric values either
+// as numbers or as numeric strings; values that cannot be parsed are skipped.
+func parseReportData(rows []map[string]interface{}, metrics []string) map[string]map[string]float64 {
+	data := make(map[string]map[string]float64, len(rows))
+	for _, row := range rows {
+		cpCode := fmt.Sprint(row["cpcode"])
+		if row["cpcode"] == nil || cpCode == "" {
+			continue
+		}
+		values := make(map[string]float64, len(metrics))
+		for _, metric := range metrics {
+			switch v := row[metric].(type) {
+			case float64:
+				values[metric] = v
+			case string:
+				if f, err := strconv.ParseFloat(v, 64); err == nil {
+					values[metric] = f
+				}
+			}
+		}
+		data[cpCode] = values
+	}
+	return data
+}
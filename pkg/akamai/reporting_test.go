@@ -0,0 +1,23 @@
+package akamai
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseReportData(t *testing.T) {
+	rows := []map[string]interface{}{
+		{"cpcode": "123456", "edgeBytesTotal": "1024", "bytesOffload": 97.5},
+		{"cpcode": "456789", "edgeBytesTotal": "not-a-number"},
+		{"edgeBytesTotal": "1"},
+	}
+	expected := map[string]map[string]float64{
+		"123456": {"edgeBytesTotal": 1024, "bytesOffload": 97.5},
+		"456789": {},
+	}
+
+	got := parseReportData(rows, []string{"edgeBytesTotal", "originBytesTotal", "bytesOffload"})
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("parseReportData() = %v, expected %v", got, expected)
+	}
+}
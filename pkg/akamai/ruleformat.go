@@ -0,0 +1,46 @@
+package akamai
+
+import (
+	"context"
+	"fmt"
+)
+
+const (
+	// DefaultRuleFormat is the rule format used when the spec does not set one
+	DefaultRuleFormat = "v2023-01-05"
+
+	// RuleFormatLatest is the unfrozen rule format that always tracks the newest behaviors
+	RuleFormatLatest = "latest"
+)
+
+// RuleFormatOrDefault returns ruleFormat, or DefaultRuleFormat if it is empty
+func RuleFormatOrDefault(ruleFormat string) string {
+	if ruleFormat == "" {
+		return DefaultRuleFormat
+	}
+	return ruleFormat
+}
+
+// ListRuleFormats returns the rule formats available to the API client
+func (c *Client) ListRuleFormats(ctx context.Context) ([]string, error) {
+	resp, err := c.papiClient.GetRuleFormats(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list rule formats: %w", err)
+	}
+	if resp == nil {
+		return nil, fmt.Errorf("invalid response from rule formats API")
+	}
+	return resp.RuleFormats.Items, nil
+}
+
+// IsRuleFormatSupported reports whether the product has a rule tree schema for the rule format,
+// i.e. whether PAPI accepts properties of the product with that rule format
+func (c *Client) IsRuleFormatSupported(ctx context.Context, productID, ruleFormat string) (bool, error) {
+	if _, err := c.GetRuleSchema(ctx, productID, ruleFormat); err != nil {
+		if IsUnsupportedRuleFormat(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
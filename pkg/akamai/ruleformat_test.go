@@ -0,0 +1,20 @@
+package akamai
+
+import "testing"
+
+func TestRuleFormatOrDefault(t *testing.T) {
+	tests := []struct {
+		ruleFormat string
+		expected   string
+	}{
+		{ruleFormat: "", expected: DefaultRuleFormat},
+		{ruleFormat: "v2024-02-12", expected: "v2024-02-12"},
+		{ruleFormat: RuleFormatLatest, expected: RuleFormatLatest},
+	}
+
+	for _, tt := range tests {
+		if got := RuleFormatOrDefault(tt.ruleFormat); got != tt.expected {
+			t.Errorf("RuleFormatOrDefault(%q) = %q, expected %q", tt.ruleFormat, got, tt.expected)
+		}
+	}
+}
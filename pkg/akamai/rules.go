@@ -3,10 +3,13 @@ package akamai
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net/http"
 	"strings"
 
 	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v8/pkg/papi"
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v8/pkg/session"
 )
 
 // GetPropertyRules retrieves the rule tree for a property version
@@ -37,42 +40,30 @@ func (c *Client) GetPropertyRules(ctx context.Context, propertyID string, versio
 		PropertyVersion: getRulesResp.PropertyVersion,
 		Etag:            getRulesResp.Etag,
 		RuleFormat:      getRulesResp.RuleFormat,
+		Comments:        getRulesResp.Comments,
 		Rules:           getRulesResp.Rules,
 	}
 
 	return propertyRules, nil
 }
 
-// UpdatePropertyRules updates the rule tree for a property version
-func (c *Client) UpdatePropertyRules(ctx context.Context, propertyID string, version int, contractID, groupID string, rules interface{}, etag string) (*PropertyRules, error) {
-	// Check if the version is published on staging or production
-	isPublished, network, err := c.IsVersionPublished(ctx, propertyID, version)
+// UpdatePropertyRules updates the rule tree for a property version.
+// A non-empty versionNotes replaces the notes (comments) of the property version.
+func (c *Client) UpdatePropertyRules(ctx context.Context, propertyID string, version int, contractID, groupID string, rules interface{}, etag, versionNotes string) (*PropertyRules, error) {
+	// Check if the version is published or being activated on staging or production
+	versionState, err := c.GetPropertyVersion(ctx, propertyID, version, contractID, groupID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to check if version is published: %w", err)
 	}
 
-	if isPublished {
-		return nil, fmt.Errorf("cannot update rules for version %d: it is currently published on %s", version, network)
+	if !versionState.IsEditable() {
+		return nil, fmt.Errorf("cannot update rules for version %d: staging status %s, production status %s",
+			version, versionState.StagingStatus, versionState.ProductionStatus)
 	}
 
-	// Convert interface{} to papi.Rules - we expect it to be a proper Rules structure
-	var papiRules papi.Rules
-	switch r := rules.(type) {
-	case papi.Rules:
-		papiRules = r
-	case map[string]interface{}:
-		// For flexibility, allow map input and try to marshal/unmarshal
-		// This is not type-safe but allows for dynamic rule structures
-		// In a production environment, you might want stricter typing
-		ruleBytes, err := json.Marshal(r)
-		if err != nil {
-			return nil, fmt.Errorf("failed to marshal rules: %w", err)
-		}
-		if err := json.Unmarshal(ruleBytes, &papiRules); err != nil {
-			return nil, fmt.Errorf("failed to unmarshal rules to papi.Rules: %w", err)
-		}
-	default:
-		return nil, fmt.Errorf("unsupported rules type: %T", rules)
+	papiRules, err := toPapiRules(rules)
+	if err != nil {
+		return nil, err
 	}
 
 	// Try with full validation first, fallback to no validation if fast validation is not supported
@@ -82,15 +73,27 @@ func (c *Client) UpdatePropertyRules(ctx context.Context, propertyID string, ver
 		ContractID:      contractID,
 		GroupID:         groupID,
 		Rules: papi.RulesUpdate{
-			Rules: papiRules,
+			Comments: versionNotes,
+			Rules:    papiRules,
 		},
 		ValidateRules: true,   // Enable validation for safety
 		ValidateMode:  "full", // Use full validation
 		DryRun:        false,  // Actually apply the changes
 	}
+	if err := c.checkRuleTreeSize(updateRequest.Rules); err != nil {
+		return nil, err
+	}
+
+	// Guard against concurrent edits: PAPI rejects the update with 412 if the etag changed
+	if etag != "" {
+		ctx = session.ContextWithOptions(ctx, session.WithContextHeaders(http.Header{"If-Match": []string{etag}}))
+	}
 
 	// Update property rules using UpdateRuleTree
 	updateResp, err := c.papiClient.UpdateRuleTree(ctx, updateRequest)
+	if isEtagConflict(err) {
+		return nil, fmt.Errorf("%w: version %d", ErrEtagConflict, version)
+	}
 	if err != nil {
 		// If validation fails, try without validation as a fallback
 		if strings.Contains(err.Error(), "not a feature") || strings.Contains(err.Error(), "validate") {
@@ -120,7 +123,9 @@ func (c *Client) UpdatePropertyRules(ctx context.Context, propertyID string, ver
 		PropertyVersion: updateResp.PropertyVersion,
 		Etag:            updateResp.Etag,
 		RuleFormat:      updateResp.RuleFormat,
+		Comments:        updateResp.Comments,
 		Rules:           updateResp.Rules,
+		Warnings:        dedupeRuleWarnings(updateResp.Warnings),
 	}
 
 	// Check for validation errors or warnings
@@ -134,3 +139,126 @@ func (c *Client) UpdatePropertyRules(ctx context.Context, propertyID string, ver
 
 	return propertyRules, nil
 }
+
+// toPapiRules converts a rule tree given as papi.Rules or generic map into papi.Rules
+func toPapiRules(rules interface{}) (papi.Rules, error) {
+	// Convert interface{} to papi.Rules - we expect it to be a proper Rules structure
+	var papiRules papi.Rules
+	switch r := rules.(type) {
+	case papi.Rules:
+		papiRules = r
+	case map[string]interface{}:
+		// For flexibility, allow map input and try to marshal/unmarshal
+		// This is not type-safe but allows for dynamic rule structures
+		// In a production environment, you might want stricter typing
+		ruleBytes, err := json.Marshal(r)
+		if err != nil {
+			return papi.Rules{}, fmt.Errorf("failed to marshal rules: %w", err)
+		}
+		if err := json.Unmarshal(ruleBytes, &papiRules); err != nil {
+			return papi.Rules{}, fmt.Errorf("failed to unmarshal rules to papi.Rules: %w", err)
+		}
+	default:
+		return papi.Rules{}, fmt.Errorf("unsupported rules type: %T", rules)
+	}
+
+	return papiRules, nil
+}
+
+// ValidatePropertyRules validates a candidate rule tree against a property version with a PAPI
+// dry run, which neither saves the rules nor creates a version. Validation errors are returned
+// in the result; the error is only set when the validation itself could not be performed.
+func (c *Client) ValidatePropertyRules(ctx context.Context, propertyID string, version int, contractID, groupID string, rules interface{}) (*RuleValidationResult, error) {
+	papiRules, err := toPapiRules(rules)
+	if err != nil {
+		return nil, err
+	}
+
+	update := papi.RulesUpdate{Rules: papiRules}
+	if err := c.checkRuleTreeSize(update); err != nil {
+		return nil, err
+	}
+
+	resp, err := c.papiClient.UpdateRuleTree(ctx, papi.UpdateRulesRequest{
+		PropertyID:      propertyID,
+		PropertyVersion: version,
+		ContractID:      contractID,
+		GroupID:         groupID,
+		Rules:           update,
+		ValidateRules:   true,
+		ValidateMode:    "full",
+		DryRun:          true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to validate property rules against version %d: %w", version, err)
+	}
+	if resp == nil {
+		return nil, fmt.Errorf("empty response from validate property rules API")
+	}
+
+	result := &RuleValidationResult{
+		PropertyVersion: version,
+		RuleFormat:      resp.RuleFormat,
+		Warnings:        dedupeRuleWarnings(resp.Warnings),
+	}
+	for _, ruleError := range resp.Errors {
+		result.Errors = append(result.Errors, RuleError{
+			Type:          ruleError.Type,
+			Title:         ruleError.Title,
+			Detail:        ruleError.Detail,
+			ErrorLocation: ruleError.ErrorLocation,
+		})
+	}
+	return result, nil
+}
+
+// dedupeRuleWarnings converts PAPI rule warnings, dropping repeated warnings of the same type at the same location
+func dedupeRuleWarnings(warnings []papi.RuleWarnings) []RuleWarning {
+	if len(warnings) == 0 {
+		return nil
+	}
+
+	seen := make(map[string]bool, len(warnings))
+	result := make([]RuleWarning, 0, len(warnings))
+	for _, w := range warnings {
+		key := w.Type + "\x00" + w.ErrorLocation + "\x00" + w.Detail
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		result = append(result, RuleWarning{
+			Type:          w.Type,
+			Title:         w.Title,
+			Detail:        w.Detail,
+			ErrorLocation: w.ErrorLocation,
+		})
+	}
+	return result
+}
+
+// ErrEtagConflict is returned by UpdatePropertyRules when the rule tree was modified since its etag was read
+var ErrEtagConflict = errors.New("property rules were modified concurrently")
+
+// isEtagConflict reports whether err is PAPI's response to a stale If-Match etag
+func isEtagConflict(err error) bool {
+	var apiErr *papi.Error
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusPreconditionFailed
+}
+
+// SetVersionNotes replaces the notes of an editable property version, keeping its rule tree
+func (c *Client) SetVersionNotes(ctx context.Context, propertyID string, version int, contractID, groupID, notes string) error {
+	currentRules, err := c.GetPropertyRules(ctx, propertyID, version, contractID, groupID)
+	if err != nil {
+		return fmt.Errorf("failed to get rules for version %d: %w", version, err)
+	}
+
+	if currentRules.Comments == notes {
+		return nil
+	}
+
+	if _, err := c.UpdatePropertyRules(ctx, propertyID, version, contractID, groupID, currentRules.Rules, currentRules.Etag, notes); err != nil {
+		return fmt.Errorf("failed to set notes for version %d: %w", version, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,75 @@
+package akamai
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v8/pkg/papi"
+)
+
+func TestIsEtagConflict(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{name: "no error", err: nil, expected: false},
+		{name: "plain error", err: errors.New("timeout"), expected: false},
+		{name: "other API error", err: &papi.Error{StatusCode: http.StatusBadRequest}, expected: false},
+		{name: "precondition failed", err: &papi.Error{StatusCode: http.StatusPreconditionFailed}, expected: true},
+		{name: "wrapped precondition failed", err: fmt.Errorf("update: %w", &papi.Error{StatusCode: http.StatusPreconditionFailed}), expected: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isEtagConflict(tt.err); got != tt.expected {
+				t.Errorf("isEtagConflict() = %v, expected %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestDedupeRuleWarnings(t *testing.T) {
+	deprecated := papi.RuleWarnings{
+		Type:          "https://problems.luna.akamaiapis.net/papi/v0/validation/product_behavior_issue.deprecated_behavior",
+		Title:         "Deprecated behavior",
+		Detail:        "The `sureRoute` behavior is deprecated.",
+		ErrorLocation: "#/rules/children/0/behaviors/1",
+	}
+	otherLocation := deprecated
+	otherLocation.ErrorLocation = "#/rules/children/2/behaviors/0"
+
+	tests := []struct {
+		name     string
+		warnings []papi.RuleWarnings
+		expected []string
+	}{
+		{name: "no warnings", warnings: nil, expected: nil},
+		{name: "single warning", warnings: []papi.RuleWarnings{deprecated}, expected: []string{deprecated.ErrorLocation}},
+		{name: "repeated warning", warnings: []papi.RuleWarnings{deprecated, deprecated}, expected: []string{deprecated.ErrorLocation}},
+		{
+			name:     "same warning at different locations",
+			warnings: []papi.RuleWarnings{deprecated, otherLocation, deprecated},
+			expected: []string{deprecated.ErrorLocation, otherLocation.ErrorLocation},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := dedupeRuleWarnings(tt.warnings)
+			if len(got) != len(tt.expected) {
+				t.Fatalf("dedupeRuleWarnings() returned %d warnings, expected %d", len(got), len(tt.expected))
+			}
+			for i, w := range got {
+				if w.ErrorLocation != tt.expected[i] {
+					t.Errorf("warning %d location = %q, expected %q", i, w.ErrorLocation, tt.expected[i])
+				}
+				if w.Type != deprecated.Type || w.Detail != deprecated.Detail {
+					t.Errorf("warning %d = %+v, expected type and detail to be copied", i, w)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,470 @@
+package akamai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// LatestRuleSchemaTTL is how long the schema of the latest rule format is reused; schemas of
+	// frozen rule formats never change and are kept for the lifetime of the operator
+	LatestRuleSchemaTTL = time.Hour
+
+	// maxRuleSchemaSize bounds the size of a rule format schema read from PAPI
+	maxRuleSchemaSize = 32 << 20
+
+	// maxSchemaDepth bounds the nesting of schemas and $refs followed while validating
+	maxSchemaDepth = 64
+)
+
+// ruleSchemas caches the rule format schemas of all clients; schemas are the same for every account
+var ruleSchemas = &ruleSchemaCache{now: time.Now, entries: make(map[string]ruleSchemaEntry)}
+
+// RuleSchema is the JSON schema PAPI publishes for the rule trees of a product and rule format.
+// It describes the options of every behavior and criterion available in the rule format.
+type RuleSchema struct {
+	doc       map[string]any
+	behaviors map[string]any
+	criteria  map[string]any
+
+	mu       sync.Mutex
+	patterns map[string]*regexp.Regexp
+}
+
+// SchemaViolation is a part of a rule tree that does not match the rule format schema. Path is a
+// JSON pointer into the rule tree request, e.g. "#/rules/children/1/behaviors/0/options/ttl".
+type SchemaViolation struct {
+	Path    string
+	Message string
+}
+
+func (v SchemaViolation) String() string {
+	return v.Path + ": " + v.Message
+}
+
+// ParseRuleSchema parses a rule format schema as returned by PAPI
+func ParseRuleSchema(data []byte) (*RuleSchema, error) {
+	var doc map[string]any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse rule format schema: %w", err)
+	}
+	catalog, _ := lookupPointer(doc, "#/definitions/catalog").(map[string]any)
+	behaviors, _ := catalog["behaviors"].(map[string]any)
+	criteria, _ := catalog["criteria"].(map[string]any)
+	if behaviors == nil || criteria == nil {
+		return nil, fmt.Errorf("rule format schema has no behavior and criteria catalog")
+	}
+	return &RuleSchema{doc: doc, behaviors: behaviors, criteria: criteria, patterns: make(map[string]*regexp.Regexp)}, nil
+}
+
+// ValidateRules checks the behaviors and criteria of every rule in the tree against the catalog
+// of the schema. Options holding PAPI variable expressions like "{{user.PMUSER_ORIGIN}}" are only
+// resolved by Akamai, and placeholders like "${secret:ns/name/key}" or "${include:name}" only by
+// the operator, so neither is checked. The structure of the rules themselves is not checked.
+func (s *RuleSchema) ValidateRules(rules any) ([]SchemaViolation, error) {
+	raw, err := json.Marshal(rules)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal rules: %w", err)
+	}
+	var tree any
+	if err := json.Unmarshal(raw, &tree); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal rules: %w", err)
+	}
+	var violations []SchemaViolation
+	s.validateRule(tree, "#/rules", &violations)
+	return violations, nil
+}
+
+// validateRule validates the behaviors and criteria of a rule and its children
+func (s *RuleSchema) validateRule(rule any, path string, violations *[]SchemaViolation) {
+	object, ok := rule.(map[string]any)
+	if !ok {
+		return
+	}
+	for _, list := range []struct {
+		kind, singular string
+		catalog        map[string]any
+	}{{"behaviors", "behavior", s.behaviors}, {"criteria", "criterion", s.criteria}} {
+		items, _ := object[list.kind].([]any)
+		for i, item := range items {
+			itemPath := fmt.Sprintf("%s/%s/%d", path, list.kind, i)
+			name, _ := item.(map[string]any)["name"].(string)
+			entry, ok := list.catalog[name]
+			if !ok {
+				*violations = append(*violations, SchemaViolation{Path: itemPath, Message: fmt.Sprintf("unknown %s %q", list.singular, name)})
+				continue
+			}
+			s.check(entry, item, itemPath, violations, 0)
+		}
+	}
+	children, _ := object["children"].([]any)
+	for i, child := range children {
+		s.validateRule(child, fmt.Sprintf("%s/children/%d", path, i), violations)
+	}
+}
+
+// check validates value against a JSON schema (draft 4), supporting the keywords used by the
+// rule format schemas
+func (s *RuleSchema) check(schema, value any, path string, violations *[]SchemaViolation, depth int) {
+	node, ok := schema.(map[string]any)
+	if !ok || depth > maxSchemaDepth {
+		return
+	}
+	report := func(format string, args ...any) {
+		*violations = append(*violations, SchemaViolation{Path: path, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if ref, ok := node["$ref"].(string); ok {
+		s.check(lookupPointer(s.doc, ref), value, path, violations, depth+1)
+		return
+	}
+	if text, ok := value.(string); ok && isVariableExpression(text) {
+		return
+	}
+
+	if types := schemaTypes(node["type"]); len(types) > 0 && !matchesType(types, value) {
+		report("expected %s, got %s", strings.Join(types, " or "), jsonType(value))
+		return
+	}
+	if enum, ok := node["enum"].([]any); ok && !containsValue(enum, value) {
+		report("%s is not one of %s", describeValue(value), describeEnum(enum))
+	}
+
+	switch v := value.(type) {
+	case map[string]any:
+		properties, _ := node["properties"].(map[string]any)
+		for _, name := range sortedKeys(v) {
+			propertyPath := path + "/" + escapePointer(name)
+			if propertySchema, ok := properties[name]; ok {
+				s.check(propertySchema, v[name], propertyPath, violations, depth+1)
+				continue
+			}
+			switch additional := node["additionalProperties"].(type) {
+			case bool:
+				if !additional {
+					*violations = append(*violations, SchemaViolation{Path: propertyPath, Message: "unknown option"})
+				}
+			case map[string]any:
+				s.check(additional, v[name], propertyPath, violations, depth+1)
+			}
+		}
+		required, _ := node["required"].([]any)
+		for _, name := range required {
+			if name, ok := name.(string); ok {
+				if _, ok := v[name]; !ok {
+					report("missing required %q", name)
+				}
+			}
+		}
+	case []any:
+		if items, ok := node["items"].(map[string]any); ok {
+			for i, item := range v {
+				s.check(items, item, fmt.Sprintf("%s/%d", path, i), violations, depth+1)
+			}
+		}
+		if minItems, ok := node["minItems"].(float64); ok && float64(len(v)) < minItems {
+			report("expected at least %v items, got %d", minItems, len(v))
+		}
+		if maxItems, ok := node["maxItems"].(float64); ok && float64(len(v)) > maxItems {
+			report("expected at most %v items, got %d", maxItems, len(v))
+		}
+	case string:
+		if minLength, ok := node["minLength"].(float64); ok && float64(len([]rune(v))) < minLength {
+			report("expected at least %v characters", minLength)
+		}
+		if maxLength, ok := node["maxLength"].(float64); ok && float64(len([]rune(v))) > maxLength {
+			report("expected at most %v characters", maxLength)
+		}
+		if pattern, ok := node["pattern"].(string); ok {
+			if re := s.pattern(pattern); re != nil && !re.MatchString(v) {
+				report("%s does not match %s", describeValue(v), pattern)
+			}
+		}
+	case float64:
+		if minimum, ok := node["minimum"].(float64); ok && v < minimum {
+			report("%v is less than the minimum %v", v, minimum)
+		}
+		if maximum, ok := node["maximum"].(float64); ok && v > maximum {
+			report("%v is greater than the maximum %v", v, maximum)
+		}
+	}
+
+	if allOf, ok := node["allOf"].([]any); ok {
+		for _, branch := range allOf {
+			s.check(branch, value, path, violations, depth+1)
+		}
+	}
+	for _, keyword := range []string{"anyOf", "oneOf"} {
+		branches, ok := node[keyword].([]any)
+		if !ok || len(branches) == 0 {
+			continue
+		}
+		matched := false
+		for _, branch := range branches {
+			var branchViolations []SchemaViolation
+			s.check(branch, value, path, &branchViolations, depth+1)
+			if len(branchViolations) == 0 {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			report("%s does not match any of the allowed forms", describeValue(value))
+		}
+	}
+}
+
+// pattern compiles and caches a pattern of the schema; patterns Go cannot compile are ignored
+func (s *RuleSchema) pattern(pattern string) *regexp.Regexp {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if re, ok := s.patterns[pattern]; ok {
+		return re
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		re = nil
+	}
+	s.patterns[pattern] = re
+	return re
+}
+
+// isVariableExpression reports whether an option value references a PAPI variable or a value
+// the operator resolves from a Secret, ConfigMap or AkamaiPropertyInclude
+func isVariableExpression(value string) bool {
+	return strings.Contains(value, "{{builtin.") || strings.Contains(value, "{{user.") ||
+		strings.Contains(value, "${secret:") || strings.Contains(value, "${configmap:") ||
+		strings.Contains(value, "${include:")
+}
+
+// lookupPointer resolves a local JSON pointer like "#/definitions/catalog" in doc
+func lookupPointer(doc map[string]any, pointer string) any {
+	if !strings.HasPrefix(pointer, "#") {
+		return nil
+	}
+	var current any = doc
+	for _, token := range strings.Split(strings.TrimPrefix(pointer, "#"), "/") {
+		if token == "" {
+			continue
+		}
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+		switch node := current.(type) {
+		case map[string]any:
+			current = node[token]
+		case []any:
+			index, err := strconv.Atoi(token)
+			if err != nil || index < 0 || index >= len(node) {
+				return nil
+			}
+			current = node[index]
+		default:
+			return nil
+		}
+	}
+	return current
+}
+
+// escapePointer escapes a property name for a JSON pointer
+func escapePointer(name string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(name, "~", "~0"), "/", "~1")
+}
+
+// schemaTypes returns the types allowed by the type keyword of a schema
+func schemaTypes(value any) []string {
+	switch t := value.(type) {
+	case string:
+		return []string{t}
+	case []any:
+		types := make([]string, 0, len(t))
+		for _, item := range t {
+			if name, ok := item.(string); ok {
+				types = append(types, name)
+			}
+		}
+		return types
+	default:
+		return nil
+	}
+}
+
+// matchesType reports whether value has one of the JSON schema types
+func matchesType(types []string, value any) bool {
+	for _, t := range types {
+		switch t {
+		case "integer":
+			if number, ok := value.(float64); ok && number == math.Trunc(number) {
+				return true
+			}
+		case "number":
+			if _, ok := value.(float64); ok {
+				return true
+			}
+		default:
+			if jsonType(value) == t {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// jsonType returns the JSON schema type of a decoded JSON value
+func jsonType(value any) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case []any:
+		return "array"
+	default:
+		return "object"
+	}
+}
+
+// containsValue reports whether enum contains value
+func containsValue(enum []any, value any) bool {
+	encoded, _ := json.Marshal(value)
+	for _, item := range enum {
+		if candidate, _ := json.Marshal(item); string(candidate) == string(encoded) {
+			return true
+		}
+	}
+	return false
+}
+
+// describeValue renders a JSON value for violation messages
+func describeValue(value any) string {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	if len(encoded) > 64 {
+		return string(encoded[:61]) + "..."
+	}
+	return string(encoded)
+}
+
+// describeEnum renders the allowed values of an enum, abbreviating long lists
+func describeEnum(enum []any) string {
+	const maxShown = 10
+	values := make([]string, 0, min(len(enum), maxShown))
+	for i, item := range enum {
+		if i == maxShown {
+			values = append(values, fmt.Sprintf("... (%d more)", len(enum)-maxShown))
+			break
+		}
+		values = append(values, describeValue(item))
+	}
+	return "[" + strings.Join(values, ", ") + "]"
+}
+
+// sortedKeys returns the keys of an object in a stable order
+func sortedKeys(object map[string]any) []string {
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// ruleSchemaCache keeps the parsed rule format schemas by product and rule format
+type ruleSchemaCache struct {
+	mu      sync.Mutex
+	now     func() time.Time
+	entries map[string]ruleSchemaEntry
+}
+
+type ruleSchemaEntry struct {
+	schema  *RuleSchema
+	expires time.Time
+}
+
+func ruleSchemaKey(productID, ruleFormat string) string {
+	return productID + "/" + ruleFormat
+}
+
+func (c *ruleSchemaCache) get(productID, ruleFormat string) (*RuleSchema, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[ruleSchemaKey(productID, ruleFormat)]
+	if !ok || (!entry.expires.IsZero() && !c.now().Before(entry.expires)) {
+		return nil, false
+	}
+	return entry.schema, true
+}
+
+func (c *ruleSchemaCache) put(productID, ruleFormat string, schema *RuleSchema) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry := ruleSchemaEntry{schema: schema}
+	if ruleFormat == RuleFormatLatest {
+		entry.expires = c.now().Add(LatestRuleSchemaTTL)
+	}
+	c.entries[ruleSchemaKey(productID, ruleFormat)] = entry
+}
+
+// GetRuleSchema returns the rule tree schema of the product for the rule format. Schemas are
+// cached; see LatestRuleSchemaTTL. A rule format the product does not support fails with an
+// APIError of status 400 or 404.
+func (c *Client) GetRuleSchema(ctx context.Context, productID, ruleFormat string) (*RuleSchema, error) {
+	ruleFormat = RuleFormatOrDefault(ruleFormat)
+	if schema, ok := ruleSchemas.get(productID, ruleFormat); ok {
+		return schema, nil
+	}
+	if c.session == nil {
+		return nil, fmt.Errorf("failed to get rule format schema: client has no EdgeGrid session")
+	}
+
+	schemaURL := fmt.Sprintf("/papi/v1/schemas/products/%s/%s", url.PathEscape(productID), url.PathEscape(ruleFormat))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, schemaURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create rule format schema request: %w", err)
+	}
+	resp, err := c.session.Exec(req, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rule format schema: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to get rule format schema: %w", newAPIError(resp))
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxRuleSchemaSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rule format schema: %w", err)
+	}
+	if len(data) > maxRuleSchemaSize {
+		return nil, fmt.Errorf("rule format schema of %s %s exceeds %d bytes", productID, ruleFormat, maxRuleSchemaSize)
+	}
+	schema, err := ParseRuleSchema(data)
+	if err != nil {
+		return nil, err
+	}
+	ruleSchemas.put(productID, ruleFormat, schema)
+	return schema, nil
+}
+
+// IsUnsupportedRuleFormat reports whether an error of GetRuleSchema means the product has no
+// schema for the rule format
+func IsUnsupportedRuleFormat(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && (apiErr.StatusCode == http.StatusBadRequest || apiErr.StatusCode == http.StatusNotFound)
+}
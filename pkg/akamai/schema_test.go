@@ -0,0 +1,165 @@
+package akamai
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+// testRuleSchema is an excerpt of a PAPI rule format schema
+const testRuleSchema = `{
+  "$schema": "http://json-schema.org/draft-04/schema#",
+  "definitions": {
+    "catalog": {
+      "behaviors": {
+        "caching": {
+          "type": "object",
+          "properties": {
+            "name": {"type": "string", "enum": ["caching"]},
+            "options": {
+              "type": "object",
+              "additionalProperties": false,
+              "required": ["behavior"],
+              "properties": {
+                "behavior": {"type": "string", "enum": ["MAX_AGE", "NO_STORE", "BYPASS_CACHE"]},
+                "ttl": {"type": "string", "pattern": "^[0-9]+[smhd]$"},
+                "mustRevalidate": {"type": "boolean"}
+              }
+            }
+          }
+        },
+        "origin": {
+          "type": "object",
+          "properties": {
+            "name": {"type": "string"},
+            "options": {
+              "type": "object",
+              "properties": {
+                "hostname": {"$ref": "#/definitions/type_hostname"},
+                "httpPort": {"type": "integer", "minimum": 1, "maximum": 65535}
+              }
+            }
+          }
+        }
+      },
+      "criteria": {
+        "path": {
+          "type": "object",
+          "properties": {
+            "name": {"type": "string"},
+            "options": {
+              "type": "object",
+              "properties": {
+                "values": {"type": "array", "minItems": 1, "items": {"type": "string"}}
+              }
+            }
+          }
+        }
+      }
+    },
+    "type_hostname": {"type": "string", "minLength": 1}
+  }
+}`
+
+func TestRuleSchemaValidateRules(t *testing.T) {
+	schema, err := ParseRuleSchema([]byte(testRuleSchema))
+	if err != nil {
+		t.Fatalf("failed to parse schema: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		rules    map[string]any
+		expected []string
+	}{
+		{
+			name: "valid rules",
+			rules: map[string]any{
+				"name": "default",
+				"behaviors": []any{
+					map[string]any{"name": "origin", "options": map[string]any{"hostname": "origin.example.com", "httpPort": 80}},
+					map[string]any{"name": "caching", "options": map[string]any{"behavior": "MAX_AGE", "ttl": "1d"}},
+				},
+				"children": []any{map[string]any{
+					"name":     "Static",
+					"criteria": []any{map[string]any{"name": "path", "options": map[string]any{"values": []any{"/static/*"}}}},
+				}},
+			},
+		},
+		{
+			name: "variable expressions are left to Akamai",
+			rules: map[string]any{"name": "default", "behaviors": []any{
+				map[string]any{"name": "origin", "options": map[string]any{"hostname": "{{user.PMUSER_ORIGIN}}", "httpPort": "{{user.PMUSER_PORT}}"}},
+			}},
+		},
+		{
+			name: "field level errors",
+			rules: map[string]any{
+				"name": "default",
+				"behaviors": []any{
+					map[string]any{"name": "origin", "options": map[string]any{"hostname": "", "httpPort": 80.5}},
+					map[string]any{"name": "caching", "options": map[string]any{"behavior": "FOREVER", "ttl": "1y", "mustRevalidate": "yes", "extra": 1}},
+					map[string]any{"name": "caching", "options": map[string]any{}},
+					map[string]any{"name": "gzip"},
+				},
+				"children": []any{map[string]any{
+					"name":     "Static",
+					"criteria": []any{map[string]any{"name": "path", "options": map[string]any{"values": []any{}}}, map[string]any{"name": "filename"}},
+				}},
+			},
+			expected: []string{
+				"#/rules/behaviors/0/options/hostname: expected at least 1 characters",
+				"#/rules/behaviors/0/options/httpPort: expected integer, got number",
+				`#/rules/behaviors/1/options/behavior: "FOREVER" is not one of ["MAX_AGE", "NO_STORE", "BYPASS_CACHE"]`,
+				"#/rules/behaviors/1/options/extra: unknown option",
+				"#/rules/behaviors/1/options/mustRevalidate: expected boolean, got string",
+				`#/rules/behaviors/1/options/ttl: "1y" does not match ^[0-9]+[smhd]$`,
+				`#/rules/behaviors/2/options: missing required "behavior"`,
+				`#/rules/behaviors/3: unknown behavior "gzip"`,
+				"#/rules/children/0/criteria/0/options/values: expected at least 1 items, got 0",
+				`#/rules/children/0/criteria/1: unknown criterion "filename"`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			violations, err := schema.ValidateRules(tt.rules)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			var got []string
+			for _, violation := range violations {
+				got = append(got, violation.String())
+			}
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("violations = %q\nexpected %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseRuleSchemaWithoutCatalog(t *testing.T) {
+	if _, err := ParseRuleSchema([]byte(`{"definitions": {}}`)); err == nil {
+		t.Error("expected a schema without catalog to be rejected")
+	}
+}
+
+func TestRuleSchemaCache(t *testing.T) {
+	now := time.Now()
+	cache := &ruleSchemaCache{now: func() time.Time { return now }, entries: make(map[string]ruleSchemaEntry)}
+	frozen, latest := &RuleSchema{}, &RuleSchema{}
+	cache.put("prd_Fresca", "v2023-01-05", frozen)
+	cache.put("prd_Fresca", RuleFormatLatest, latest)
+
+	now = now.Add(LatestRuleSchemaTTL)
+	if schema, ok := cache.get("prd_Fresca", "v2023-01-05"); !ok || schema != frozen {
+		t.Error("expected the schema of a frozen rule format to be kept")
+	}
+	if _, ok := cache.get("prd_Fresca", RuleFormatLatest); ok {
+		t.Error("expected the schema of the latest rule format to expire")
+	}
+	if _, ok := cache.get("prd_SPM", "v2023-01-05"); ok {
+		t.Error("expected schemas to be cached per product")
+	}
+}
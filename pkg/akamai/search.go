@@ -0,0 +1,172 @@
+package akamai
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v8/pkg/papi"
+)
+
+// DefaultSearchCacheTTL is how long property search results are reused before PAPI is queried again
+const DefaultSearchCacheTTL = time.Minute
+
+// searchCache keeps recent property search results so adoption, duplicate and hostname conflict
+// checks of many resources don't each hit the PAPI search endpoint
+type searchCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	now     func() time.Time
+	entries map[string]searchCacheEntry
+}
+
+type searchCacheEntry struct {
+	results []PropertySearchResult
+	expires time.Time
+}
+
+func newSearchCache(ttl time.Duration) *searchCache {
+	return &searchCache{
+		ttl:     ttl,
+		now:     time.Now,
+		entries: make(map[string]searchCacheEntry),
+	}
+}
+
+func searchCacheKey(key, value string) string {
+	return key + "=" + strings.ToLower(value)
+}
+
+func (s *searchCache) get(key, value string) ([]PropertySearchResult, bool) {
+	if s == nil {
+		return nil, false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.entries[searchCacheKey(key, value)]
+	if !ok || !s.now().Before(entry.expires) {
+		return nil, false
+	}
+	return entry.results, true
+}
+
+func (s *searchCache) put(key, value string, results []PropertySearchResult) {
+	if s == nil || s.ttl <= 0 {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Drop expired entries so the cache doesn't grow with every hostname ever searched
+	now := s.now()
+	for k, entry := range s.entries {
+		if !now.Before(entry.expires) {
+			delete(s.entries, k)
+		}
+	}
+	s.entries[searchCacheKey(key, value)] = searchCacheEntry{results: results, expires: now.Add(s.ttl)}
+}
+
+func (s *searchCache) invalidate() {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries = make(map[string]searchCacheEntry)
+}
+
+// searchProperties runs a PAPI find-by-value search, returning one result per matching property
+// version. The endpoint has no paging parameters and returns all matches in a single response.
+func (c *Client) searchProperties(ctx context.Context, key, value string) ([]PropertySearchResult, error) {
+	if results, ok := c.search.get(key, value); ok {
+		return results, nil
+	}
+
+	resp, err := c.papiClient.SearchProperties(ctx, papi.SearchRequest{Key: key, Value: value})
+	if err != nil {
+		return nil, fmt.Errorf("failed to search properties by %s %q: %w", key, value, err)
+	}
+
+	results := []PropertySearchResult{}
+	if resp != nil {
+		results = make([]PropertySearchResult, 0, len(resp.Versions.Items))
+		for _, item := range resp.Versions.Items {
+			results = append(results, PropertySearchResult{
+				PropertyID:       item.PropertyID,
+				PropertyName:     item.PropertyName,
+				AccountID:        item.AccountID,
+				ContractID:       item.ContractID,
+				GroupID:          item.GroupID,
+				PropertyVersion:  item.PropertyVersion,
+				Hostname:         item.Hostname,
+				EdgeHostname:     item.EdgeHostname,
+				StagingStatus:    item.StagingStatus,
+				ProductionStatus: item.ProductionStatus,
+				UpdatedByUser:    item.UpdatedByUser,
+				UpdatedDate:      item.UpdatedDate,
+			})
+		}
+	}
+
+	c.search.put(key, value, results)
+	return results, nil
+}
+
+// FindPropertyByName returns the latest version of the property with exactly the given name,
+// or nil if no property has that name
+func (c *Client) FindPropertyByName(ctx context.Context, propertyName string) (*PropertySearchResult, error) {
+	results, err := c.searchProperties(ctx, papi.SearchKeyPropertyName, propertyName)
+	if err != nil {
+		return nil, err
+	}
+
+	// The search matches property names case-insensitively, while PAPI names are case-sensitive
+	var matches []PropertySearchResult
+	for _, result := range results {
+		if result.PropertyName == propertyName {
+			matches = append(matches, result)
+		}
+	}
+
+	latest := latestPerProperty(matches)
+	if len(latest) == 0 {
+		return nil, nil
+	}
+	return &latest[0], nil
+}
+
+// FindPropertiesByHostname returns the properties a hostname is currently active on, one result per
+// property with the highest matching version. Hostnames only match once activated on a network.
+func (c *Client) FindPropertiesByHostname(ctx context.Context, hostname string) ([]PropertySearchResult, error) {
+	results, err := c.searchProperties(ctx, papi.SearchKeyHostname, hostname)
+	if err != nil {
+		return nil, err
+	}
+	return latestPerProperty(results), nil
+}
+
+// latestPerProperty collapses per-version search results into the highest version of each
+// property, sorted by property ID
+func latestPerProperty(results []PropertySearchResult) []PropertySearchResult {
+	latest := make(map[string]PropertySearchResult, len(results))
+	for _, result := range results {
+		if current, ok := latest[result.PropertyID]; ok && current.PropertyVersion >= result.PropertyVersion {
+			continue
+		}
+		latest[result.PropertyID] = result
+	}
+
+	collapsed := make([]PropertySearchResult, 0, len(latest))
+	for _, result := range latest {
+		collapsed = append(collapsed, result)
+	}
+	sort.Slice(collapsed, func(i, j int) bool {
+		return collapsed[i].PropertyID < collapsed[j].PropertyID
+	})
+	return collapsed
+}
@@ -0,0 +1,138 @@
+package akamai
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v8/pkg/papi"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+)
+
+// searchPAPI stubs the PAPI search endpoint and counts its calls
+type searchPAPI struct {
+	papi.PAPI
+	items []papi.SearchItem
+	calls int
+}
+
+func (s *searchPAPI) SearchProperties(_ context.Context, _ papi.SearchRequest) (*papi.SearchResponse, error) {
+	s.calls++
+	return &papi.SearchResponse{Versions: papi.SearchItems{Items: s.items}}, nil
+}
+
+func TestFindPropertyByName(t *testing.T) {
+	stub := &searchPAPI{items: []papi.SearchItem{
+		{PropertyID: "prp_1", PropertyName: "www.example.com", PropertyVersion: 3},
+		{PropertyID: "prp_1", PropertyName: "www.example.com", PropertyVersion: 5},
+		{PropertyID: "prp_2", PropertyName: "WWW.example.com", PropertyVersion: 9},
+	}}
+	c := &Client{papiClient: stub, search: newSearchCache(time.Minute)}
+
+	result, err := c.FindPropertyByName(context.Background(), "www.example.com")
+	if err != nil {
+		t.Fatalf("FindPropertyByName() error = %v", err)
+	}
+	if result == nil || result.PropertyID != "prp_1" || result.PropertyVersion != 5 {
+		t.Errorf("FindPropertyByName() = %+v, expected prp_1 version 5", result)
+	}
+
+	result, err = c.FindPropertyByName(context.Background(), "other.example.com")
+	if err != nil {
+		t.Fatalf("FindPropertyByName() error = %v", err)
+	}
+	if result != nil {
+		t.Errorf("FindPropertyByName() = %+v, expected no match", result)
+	}
+}
+
+func TestFindPropertiesByHostname(t *testing.T) {
+	stub := &searchPAPI{items: []papi.SearchItem{
+		{PropertyID: "prp_2", Hostname: "www.example.com", PropertyVersion: 1},
+		{PropertyID: "prp_1", Hostname: "www.example.com", PropertyVersion: 4},
+		{PropertyID: "prp_2", Hostname: "www.example.com", PropertyVersion: 2},
+	}}
+	c := &Client{papiClient: stub, search: newSearchCache(time.Minute)}
+
+	results, err := c.FindPropertiesByHostname(context.Background(), "www.example.com")
+	if err != nil {
+		t.Fatalf("FindPropertiesByHostname() error = %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("FindPropertiesByHostname() returned %d results, expected 2", len(results))
+	}
+	if results[0].PropertyID != "prp_1" || results[1].PropertyID != "prp_2" || results[1].PropertyVersion != 2 {
+		t.Errorf("FindPropertiesByHostname() = %+v, expected prp_1 v4 and prp_2 v2", results)
+	}
+}
+
+func TestSearchCache(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	stub := &searchPAPI{items: []papi.SearchItem{{PropertyID: "prp_1", Hostname: "www.example.com"}}}
+	c := &Client{papiClient: stub, search: newSearchCache(time.Minute)}
+	c.search.now = func() time.Time { return now }
+
+	search := func() {
+		t.Helper()
+		if _, err := c.FindPropertiesByHostname(context.Background(), "www.example.com"); err != nil {
+			t.Fatalf("FindPropertiesByHostname() error = %v", err)
+		}
+	}
+
+	tests := []struct {
+		name          string
+		before        func()
+		expectedCalls int
+	}{
+		{name: "first search queries PAPI", before: func() {}, expectedCalls: 1},
+		{name: "repeated search is cached", before: func() { now = now.Add(30 * time.Second) }, expectedCalls: 1},
+		{name: "expired entry queries PAPI", before: func() { now = now.Add(time.Minute) }, expectedCalls: 2},
+		{name: "invalidated cache queries PAPI", before: func() { c.search.invalidate() }, expectedCalls: 3},
+	}
+
+	for _, tt := range tests {
+		tt.before()
+		search()
+		if stub.calls != tt.expectedCalls {
+			t.Errorf("%s: PAPI called %d times, expected %d", tt.name, stub.calls, tt.expectedCalls)
+		}
+	}
+
+	// Without a cache every search goes to PAPI
+	c.search = nil
+	search()
+	search()
+	if stub.calls != 5 {
+		t.Errorf("uncached searches: PAPI called %d times, expected 5", stub.calls)
+	}
+}
+
+func TestCheckHostnameConflicts(t *testing.T) {
+	hostnames := []akamaiV1alpha1.Hostname{{CNAMEFrom: "www.example.com"}}
+
+	tests := []struct {
+		name     string
+		items    []papi.SearchItem
+		conflict bool
+	}{
+		{name: "not active anywhere", items: nil},
+		{name: "active on the same property", items: []papi.SearchItem{{PropertyID: "prp_1", Hostname: "www.example.com"}}},
+		{
+			name:     "active on another property",
+			items:    []papi.SearchItem{{PropertyID: "prp_2", PropertyName: "other", Hostname: "www.example.com"}},
+			conflict: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Client{papiClient: &searchPAPI{items: tt.items}, search: newSearchCache(time.Minute)}
+			err := c.checkHostnameConflicts(context.Background(), "prp_1", hostnames)
+			if errors.Is(err, ErrHostnameConflict) != tt.conflict {
+				t.Errorf("checkHostnameConflicts() error = %v, expected conflict %v", err, tt.conflict)
+			}
+		})
+	}
+}
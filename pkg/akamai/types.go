@@ -14,11 +14,45 @@ type Property struct {
 	Hostnames         []Hostname `json:"hostnames"`
 }
 
+// PropertySearchResult is a property version matched by a PAPI property search
+type PropertySearchResult struct {
+	PropertyID       string `json:"propertyId"`
+	PropertyName     string `json:"propertyName"`
+	AccountID        string `json:"accountId"`
+	ContractID       string `json:"contractId"`
+	GroupID          string `json:"groupId"`
+	PropertyVersion  int    `json:"propertyVersion"`
+	Hostname         string `json:"hostname,omitempty"`
+	EdgeHostname     string `json:"edgeHostname,omitempty"`
+	StagingStatus    string `json:"stagingStatus"`
+	ProductionStatus string `json:"productionStatus"`
+	UpdatedByUser    string `json:"updatedByUser"`
+	UpdatedDate      string `json:"updatedDate"`
+}
+
 // Hostname represents a hostname configuration
 type Hostname struct {
 	CNAMEFrom            string `json:"cnameFrom"`
 	CNAMETo              string `json:"cnameTo"`
 	CertProvisioningType string `json:"certProvisioningType"`
+
+	// StagingCertStatus and ProductionCertStatus are the Default DV certificate status per
+	// network; empty for hostnames whose certificate is not managed by PAPI (e.g. CPS)
+	StagingCertStatus    string `json:"stagingCertStatus,omitempty"`
+	ProductionCertStatus string `json:"productionCertStatus,omitempty"`
+
+	// ValidationCNAME and ValidationTarget are the CNAME record the certificate authority
+	// validates the domain of a Default DV certificate with
+	ValidationCNAME  string `json:"validationCname,omitempty"`
+	ValidationTarget string `json:"validationTarget,omitempty"`
+}
+
+// CertStatusDeployed is the certificate status of a hostname whose certificate is live
+const CertStatusDeployed = "DEPLOYED"
+
+// CertReady reports whether the hostname's certificate is usable on production
+func (h Hostname) CertReady() bool {
+	return h.ProductionCertStatus == "" || h.ProductionCertStatus == CertStatusDeployed
 }
 
 // Activation represents an activation status
@@ -27,6 +61,7 @@ type Activation struct {
 	PropertyID      string   `json:"propertyId"`
 	PropertyVersion int      `json:"propertyVersion"`
 	Network         string   `json:"network"`
+	ActivationType  string   `json:"activationType"`
 	Status          string   `json:"status"`
 	SubmitDate      string   `json:"submitDate"`
 	UpdateDate      string   `json:"updateDate"`
@@ -45,5 +80,107 @@ type PropertyRules struct {
 	PropertyVersion int         `json:"propertyVersion"`
 	Etag            string      `json:"etag"`
 	RuleFormat      string      `json:"ruleFormat"`
+	Comments        string      `json:"comments,omitempty"`
 	Rules           interface{} `json:"rules"`
+
+	// Warnings are the de-duplicated validation warnings returned by the last rules update
+	Warnings []RuleWarning `json:"warnings,omitempty"`
+}
+
+// IncludeRules holds the rule tree of an include version
+type IncludeRules struct {
+	IncludeID      string      `json:"includeId"`
+	IncludeName    string      `json:"includeName"`
+	IncludeVersion int         `json:"includeVersion"`
+	Etag           string      `json:"etag,omitempty"`
+	Rules          interface{} `json:"rules"`
+
+	// Warnings are the de-duplicated validation warnings returned by the last rules update
+	Warnings []RuleWarning `json:"warnings,omitempty"`
+}
+
+// Include describes an include and its versions active on the staging and production networks;
+// StagingVersion and ProductionVersion are 0 while no version is active
+type Include struct {
+	IncludeID         string `json:"includeId"`
+	IncludeName       string `json:"includeName"`
+	IncludeType       string `json:"includeType"`
+	LatestVersion     int    `json:"latestVersion"`
+	StagingVersion    int    `json:"stagingVersion,omitempty"`
+	ProductionVersion int    `json:"productionVersion,omitempty"`
+}
+
+// RuleError is a validation error that keeps a rule tree from being saved
+type RuleError struct {
+	Type          string `json:"type"`
+	Title         string `json:"title"`
+	Detail        string `json:"detail"`
+	ErrorLocation string `json:"errorLocation,omitempty"`
+}
+
+// RuleValidationResult is the outcome of validating a rule tree without saving it
+type RuleValidationResult struct {
+	PropertyVersion int           `json:"propertyVersion"`
+	RuleFormat      string        `json:"ruleFormat"`
+	Errors          []RuleError   `json:"errors,omitempty"`
+	Warnings        []RuleWarning `json:"warnings,omitempty"`
+}
+
+// RuleWarning is a non-blocking validation warning about a rule tree
+type RuleWarning struct {
+	Type          string `json:"type"`
+	Title         string `json:"title"`
+	Detail        string `json:"detail"`
+	ErrorLocation string `json:"errorLocation,omitempty"`
+}
+
+// Contract represents an Akamai contract available to the API client
+type Contract struct {
+	ContractID       string `json:"contractId"`
+	ContractTypeName string `json:"contractTypeName"`
+}
+
+// Group represents an Akamai group available to the API client
+type Group struct {
+	GroupID       string   `json:"groupId"`
+	GroupName     string   `json:"groupName"`
+	ParentGroupID string   `json:"parentGroupId,omitempty"`
+	ContractIDs   []string `json:"contractIds"`
+}
+
+// Product represents a product available on a contract
+type Product struct {
+	ProductID   string `json:"productId"`
+	ProductName string `json:"productName"`
+}
+
+// PropertyVersion represents a single property version and its activation state per network
+type PropertyVersion struct {
+	PropertyVersion  int    `json:"propertyVersion"`
+	Etag             string `json:"etag"`
+	Note             string `json:"note"`
+	RuleFormat       string `json:"ruleFormat"`
+	StagingStatus    string `json:"stagingStatus"`
+	ProductionStatus string `json:"productionStatus"`
+	UpdatedByUser    string `json:"updatedByUser"`
+	UpdatedDate      string `json:"updatedDate"`
+}
+
+// ActivationWarning is a warning returned by Akamai that must be acknowledged before activating
+type ActivationWarning struct {
+	MessageID string `json:"messageId"`
+	Type      string `json:"type"`
+	Title     string `json:"title"`
+	Detail    string `json:"detail"`
+}
+
+// CPCodeTraffic holds delivery metrics of a CP code over a reporting window
+type CPCodeTraffic struct {
+	CPCode       string
+	EdgeBytes    float64
+	OriginBytes  float64
+	BytesOffload float64
+	EdgeHits     float64
+	OriginHits   float64
+	HitsOffload  float64
 }
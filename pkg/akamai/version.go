@@ -0,0 +1,53 @@
+package akamai
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/akamai/AkamaiOPEN-edgegrid-golang/v8/pkg/papi"
+)
+
+// GetPropertyVersion retrieves a single property version. This is considerably lighter than
+// GetProperty and is used to poll the state of a specific version.
+func (c *Client) GetPropertyVersion(ctx context.Context, propertyID string, version int, contractID, groupID string) (*PropertyVersion, error) {
+	resp, err := c.papiClient.GetPropertyVersion(ctx, papi.GetPropertyVersionRequest{
+		PropertyID:      propertyID,
+		PropertyVersion: version,
+		ContractID:      contractID,
+		GroupID:         groupID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get property version %d: %w", version, err)
+	}
+
+	if resp == nil {
+		return nil, fmt.Errorf("empty response from get property version API")
+	}
+
+	item := resp.Version
+	return &PropertyVersion{
+		PropertyVersion:  item.PropertyVersion,
+		Etag:             item.Etag,
+		Note:             item.Note,
+		RuleFormat:       item.RuleFormat,
+		StagingStatus:    string(item.StagingStatus),
+		ProductionStatus: string(item.ProductionStatus),
+		UpdatedByUser:    item.UpdatedByUser,
+		UpdatedDate:      item.UpdatedDate,
+	}, nil
+}
+
+// IsPublished reports whether the version is active on staging or production
+func (v *PropertyVersion) IsPublished() bool {
+	return v.StagingStatus == string(papi.VersionStatusActive) || v.ProductionStatus == string(papi.VersionStatusActive)
+}
+
+// IsPending reports whether the version is currently being activated or deactivated
+func (v *PropertyVersion) IsPending() bool {
+	return v.StagingStatus == string(papi.VersionStatusPending) || v.ProductionStatus == string(papi.VersionStatusPending)
+}
+
+// IsEditable reports whether the version can still be modified
+func (v *PropertyVersion) IsEditable() bool {
+	return !v.IsPublished() && !v.IsPending()
+}
@@ -0,0 +1,181 @@
+package lint
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Guardrail names
+const (
+	GuardrailCachingRemoved   = "caching-removed"
+	GuardrailZeroTTLAtDefault = "zero-ttl-at-default"
+	GuardrailNoStoreAtDefault = "no-store-at-default"
+)
+
+// guardrails are the known guardrails with a short description used in findings
+var guardrails = map[string]string{
+	GuardrailCachingRemoved:   "the new version removes a caching behavior present in the active version",
+	GuardrailZeroTTLAtDefault: "the new version sets a caching TTL of 0 on the default rule",
+	GuardrailNoStoreAtDefault: "the new version disables caching on the default rule",
+}
+
+// GuardFinding is a guardrail violated by a new version
+type GuardFinding struct {
+	Guardrail string
+	Path      string
+	Message   string
+}
+
+// String renders the finding for conditions and logs
+func (f GuardFinding) String() string {
+	return fmt.Sprintf("%s: %s (%s)", f.Guardrail, f.Message, f.Path)
+}
+
+// ParseGuardrails parses a comma separated list of guardrail names, e.g.
+// "caching-removed,zero-ttl-at-default"
+func ParseGuardrails(value string) ([]string, error) {
+	var names []string
+	for _, name := range strings.Split(value, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if _, ok := guardrails[name]; !ok {
+			return nil, fmt.Errorf("unknown guardrail %q", name)
+		}
+		names = append(names, name)
+	}
+	return names, nil
+}
+
+// Guard runs the enabled guardrails against the rendered rule tree of a new version, comparing
+// it with the rule tree of the version active on the network (nil when none is active). Both
+// trees are in the format returned by the PAPI rules endpoint. Findings are sorted by guardrail
+// and path.
+func Guard(enabled []string, rules, active interface{}) []GuardFinding {
+	on := make(map[string]bool, len(enabled))
+	for _, name := range enabled {
+		on[name] = true
+	}
+	if len(on) == 0 || rules == nil {
+		return nil
+	}
+
+	newTree := cachingByPath(toRuleMap(rules), "default")
+	activeTree := map[string][]map[string]interface{}{}
+	if active != nil {
+		activeTree = cachingByPath(toRuleMap(active), "default")
+	}
+
+	var findings []GuardFinding
+	report := func(name, path string) {
+		if on[name] {
+			findings = append(findings, GuardFinding{Guardrail: name, Path: path, Message: guardrails[name]})
+		}
+	}
+
+	for path, behaviors := range activeTree {
+		if len(behaviors) > 0 && len(newTree[path]) == 0 {
+			report(GuardrailCachingRemoved, path)
+		}
+	}
+
+	// Findings on the default rule are only reported when the active version did not already have them
+	if hasCaching(newTree["default"], isZeroTTL) && !hasCaching(activeTree["default"], isZeroTTL) {
+		report(GuardrailZeroTTLAtDefault, "default")
+	}
+	if hasCaching(newTree["default"], isNoStore) && !hasCaching(activeTree["default"], isNoStore) {
+		report(GuardrailNoStoreAtDefault, "default")
+	}
+
+	sort.Slice(findings, func(i, j int) bool {
+		if findings[i].Guardrail != findings[j].Guardrail {
+			return findings[i].Guardrail < findings[j].Guardrail
+		}
+		return findings[i].Path < findings[j].Path
+	})
+	return findings
+}
+
+// toRuleMap converts a rule tree of any JSON compatible type into a generic map
+func toRuleMap(rules interface{}) map[string]interface{} {
+	if rule, ok := rules.(map[string]interface{}); ok {
+		return rule
+	}
+	data, err := json.Marshal(rules)
+	if err != nil {
+		return nil
+	}
+	var rule map[string]interface{}
+	if err := json.Unmarshal(data, &rule); err != nil {
+		return nil
+	}
+	return rule
+}
+
+// cachingByPath returns the options of the caching behaviors of every rule, keyed by the rule
+// path made of the rule names joined with "/"
+func cachingByPath(rule map[string]interface{}, path string) map[string][]map[string]interface{} {
+	result := map[string][]map[string]interface{}{}
+	if rule == nil {
+		return result
+	}
+
+	behaviors, _ := rule["behaviors"].([]interface{})
+	result[path] = nil
+	for _, item := range behaviors {
+		behavior, _ := item.(map[string]interface{})
+		if behavior == nil || behavior["name"] != "caching" {
+			continue
+		}
+		options, _ := behavior["options"].(map[string]interface{})
+		result[path] = append(result[path], options)
+	}
+
+	children, _ := rule["children"].([]interface{})
+	for _, item := range children {
+		child, _ := item.(map[string]interface{})
+		if child == nil {
+			continue
+		}
+		name, _ := child["name"].(string)
+		for childPath, options := range cachingByPath(child, path+"/"+name) {
+			result[childPath] = options
+		}
+	}
+	return result
+}
+
+// hasCaching reports whether any of the caching behavior options match
+func hasCaching(behaviors []map[string]interface{}, match func(map[string]interface{}) bool) bool {
+	for _, options := range behaviors {
+		if match(options) {
+			return true
+		}
+	}
+	return false
+}
+
+// isZeroTTL reports whether caching options cache with a TTL of 0, e.g. "0s" or "0m"
+func isZeroTTL(options map[string]interface{}) bool {
+	switch options["behavior"] {
+	case "MAX_AGE", "CACHE_CONTROL", "EXPIRES", "CACHE_CONTROL_AND_EXPIRES":
+	default:
+		return false
+	}
+	ttl, _ := options["ttl"].(string)
+	ttl = strings.TrimRight(strings.TrimSpace(ttl), "smhd")
+	if ttl == "" {
+		return false
+	}
+	value, err := strconv.ParseFloat(ttl, 64)
+	return err == nil && value == 0
+}
+
+// isNoStore reports whether caching options disable caching
+func isNoStore(options map[string]interface{}) bool {
+	return options["behavior"] == "NO_STORE" || options["behavior"] == "BYPASS_CACHE"
+}
@@ -0,0 +1,87 @@
+package lint
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestGuard(t *testing.T) {
+	tree := func(raw string) interface{} {
+		var rules interface{}
+		if err := json.Unmarshal([]byte(raw), &rules); err != nil {
+			t.Fatalf("invalid test rules: %v", err)
+		}
+		return rules
+	}
+	cached := tree(`{"name": "default", "behaviors": [{"name": "caching", "options": {"behavior": "MAX_AGE", "ttl": "1d"}}],
+		"children": [{"name": "Static", "behaviors": [{"name": "caching", "options": {"behavior": "MAX_AGE", "ttl": "7d"}}]}]}`)
+	all := []string{GuardrailCachingRemoved, GuardrailNoStoreAtDefault, GuardrailZeroTTLAtDefault}
+
+	tests := []struct {
+		name     string
+		enabled  []string
+		rules    interface{}
+		active   interface{}
+		expected []string
+	}{
+		{
+			name:    "unchanged caching",
+			enabled: all,
+			rules:   cached,
+			active:  cached,
+		},
+		{
+			name:     "caching removed from a child rule",
+			enabled:  all,
+			rules:    tree(`{"name": "default", "behaviors": [{"name": "caching", "options": {"behavior": "MAX_AGE", "ttl": "1d"}}], "children": [{"name": "Static"}]}`),
+			active:   cached,
+			expected: []string{"caching-removed: default/Static"},
+		},
+		{
+			name:     "zero ttl and no store at default",
+			enabled:  all,
+			rules:    tree(`{"name": "default", "behaviors": [{"name": "caching", "options": {"behavior": "MAX_AGE", "ttl": "0s"}}, {"name": "caching", "options": {"behavior": "NO_STORE"}}]}`),
+			expected: []string{"no-store-at-default: default", "zero-ttl-at-default: default"},
+		},
+		{
+			name:    "already active zero ttl is not reported",
+			enabled: all,
+			rules:   tree(`{"name": "default", "behaviors": [{"name": "caching", "options": {"behavior": "MAX_AGE", "ttl": "0m"}}]}`),
+			active:  tree(`{"name": "default", "behaviors": [{"name": "caching", "options": {"behavior": "MAX_AGE", "ttl": "0s"}}]}`),
+		},
+		{
+			name:     "disabled guardrails are skipped",
+			enabled:  []string{GuardrailZeroTTLAtDefault},
+			rules:    tree(`{"name": "default"}`),
+			active:   cached,
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			findings := Guard(tt.enabled, tt.rules, tt.active)
+			if len(findings) != len(tt.expected) {
+				t.Fatalf("expected %d findings, got %v", len(tt.expected), findings)
+			}
+			for i, finding := range findings {
+				if got := finding.Guardrail + ": " + finding.Path; got != tt.expected[i] {
+					t.Errorf("finding %d = %q, expected %q", i, got, tt.expected[i])
+				}
+			}
+		})
+	}
+}
+
+func TestParseGuardrails(t *testing.T) {
+	names, err := ParseGuardrails(" caching-removed, zero-ttl-at-default ,")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(names) != 2 || names[0] != GuardrailCachingRemoved || names[1] != GuardrailZeroTTLAtDefault {
+		t.Errorf("unexpected guardrails %v", names)
+	}
+	if _, err := ParseGuardrails("offload-collapse"); err == nil {
+		t.Error("expected an error for an unknown guardrail")
+	}
+}
@@ -0,0 +1,197 @@
+package lint
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+)
+
+// Severity controls how a lint finding is handled
+type Severity string
+
+const (
+	// SeverityOff disables a lint rule
+	SeverityOff Severity = "off"
+	// SeverityWarning reports a finding without blocking the update
+	SeverityWarning Severity = "warning"
+	// SeverityBlocking reports a finding and blocks the update
+	SeverityBlocking Severity = "blocking"
+)
+
+// Lint rule names
+const (
+	RuleCachingNoStoreAtDefault = "caching-no-store-at-default"
+	RuleMissingCPCode           = "missing-cpcode"
+	RuleHTTPWithoutRedirect     = "http-without-redirect"
+)
+
+// defaultSeverities are the severities applied when no override is configured
+var defaultSeverities = map[string]Severity{
+	RuleCachingNoStoreAtDefault: SeverityWarning,
+	RuleMissingCPCode:           SeverityWarning,
+	RuleHTTPWithoutRedirect:     SeverityWarning,
+}
+
+// Finding is a single lint result
+type Finding struct {
+	Rule     string
+	Severity Severity
+	Path     string
+	Message  string
+}
+
+// String renders the finding for conditions and logs
+func (f Finding) String() string {
+	return fmt.Sprintf("[%s] %s: %s (%s)", f.Severity, f.Rule, f.Message, f.Path)
+}
+
+// Linter runs lint rules against a property rule tree
+type Linter struct {
+	severities map[string]Severity
+}
+
+// NewLinter creates a linter using the default severities with the given overrides applied
+func NewLinter(overrides map[string]Severity) *Linter {
+	severities := make(map[string]Severity, len(defaultSeverities))
+	for rule, severity := range defaultSeverities {
+		severities[rule] = severity
+	}
+	for rule, severity := range overrides {
+		severities[rule] = severity
+	}
+	return &Linter{severities: severities}
+}
+
+// ParseSeverities parses a comma separated list of rule=severity pairs,
+// e.g. "missing-cpcode=warning,http-without-redirect=blocking"
+func ParseSeverities(value string) (map[string]Severity, error) {
+	severities := make(map[string]Severity)
+	if strings.TrimSpace(value) == "" {
+		return severities, nil
+	}
+
+	for _, pair := range strings.Split(value, ",") {
+		parts := strings.SplitN(strings.TrimSpace(pair), "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid lint severity %q: expected rule=severity", pair)
+		}
+		rule, severity := parts[0], Severity(parts[1])
+		if _, ok := defaultSeverities[rule]; !ok {
+			return nil, fmt.Errorf("unknown lint rule %q", rule)
+		}
+		switch severity {
+		case SeverityOff, SeverityWarning, SeverityBlocking:
+		default:
+			return nil, fmt.Errorf("invalid severity %q for lint rule %q", severity, rule)
+		}
+		severities[rule] = severity
+	}
+
+	return severities, nil
+}
+
+// Lint runs all enabled rules against the rule tree and returns the findings sorted by rule
+func (l *Linter) Lint(rules *akamaiV1alpha1.PropertyRules) []Finding {
+	if rules == nil {
+		return nil
+	}
+
+	tree := flatten(rules)
+	var findings []Finding
+	report := func(rule, path, message string) {
+		severity := l.severities[rule]
+		if severity == SeverityOff || severity == "" {
+			return
+		}
+		findings = append(findings, Finding{Rule: rule, Severity: severity, Path: path, Message: message})
+	}
+
+	// Caching NO_STORE on the default rule disables caching for the whole property
+	for _, behavior := range rules.Behaviors {
+		if behavior.Name == "caching" && optionString(behavior.Options.Raw, "behavior") == "NO_STORE" {
+			report(RuleCachingNoStoreAtDefault, "default", "caching behavior NO_STORE on the default rule disables caching for the whole property")
+		}
+	}
+
+	// The default rule needs a cpCode behavior for reporting and billing
+	hasCPCode := false
+	for _, behavior := range rules.Behaviors {
+		if behavior.Name == "cpCode" {
+			hasCPCode = true
+		}
+	}
+	if !hasCPCode {
+		report(RuleMissingCPCode, "default", "default rule has no cpCode behavior")
+	}
+
+	// Plain HTTP requests should be redirected to HTTPS somewhere in the tree
+	hasRedirect := false
+	for _, node := range tree {
+		if node.matchesHTTP && node.hasRedirect {
+			hasRedirect = true
+		}
+	}
+	if !hasRedirect {
+		report(RuleHTTPWithoutRedirect, "default", "no rule redirects requestProtocol HTTP to HTTPS")
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool { return findings[i].Rule < findings[j].Rule })
+	return findings
+}
+
+// HasBlocking reports whether any finding is blocking
+func HasBlocking(findings []Finding) bool {
+	for _, finding := range findings {
+		if finding.Severity == SeverityBlocking {
+			return true
+		}
+	}
+	return false
+}
+
+// ruleNode is a flattened view of a rule used by the tree-wide lint rules
+type ruleNode struct {
+	matchesHTTP bool
+	hasRedirect bool
+}
+
+// flatten walks the rule tree and returns one node per rule
+func flatten(rules *akamaiV1alpha1.PropertyRules) []ruleNode {
+	var node ruleNode
+	for _, criterion := range rules.Criteria {
+		if criterion.Name == "requestProtocol" && optionString(criterion.Options.Raw, "value") == "HTTP" {
+			node.matchesHTTP = true
+		}
+	}
+	for _, behavior := range rules.Behaviors {
+		if behavior.Name == "redirect" || behavior.Name == "redirectplus" {
+			node.hasRedirect = true
+		}
+	}
+
+	nodes := []ruleNode{node}
+	for _, childRaw := range rules.Children {
+		var child akamaiV1alpha1.PropertyRules
+		if err := json.Unmarshal(childRaw.Raw, &child); err != nil {
+			continue
+		}
+		nodes = append(nodes, flatten(&child)...)
+	}
+	return nodes
+}
+
+// optionString returns a string option from raw behavior or criterion options
+func optionString(raw []byte, key string) string {
+	if raw == nil {
+		return ""
+	}
+	var options map[string]interface{}
+	if err := json.Unmarshal(raw, &options); err != nil {
+		return ""
+	}
+	value, _ := options[key].(string)
+	return value
+}
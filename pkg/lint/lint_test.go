@@ -0,0 +1,88 @@
+package lint
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/runtime"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+)
+
+func TestLint(t *testing.T) {
+	redirectChild := runtime.RawExtension{Raw: []byte(`{
+		"name": "Redirect to HTTPS",
+		"criteria": [{"name": "requestProtocol", "options": {"value": "HTTP"}}],
+		"behaviors": [{"name": "redirect", "options": {"destinationProtocol": "HTTPS"}}]
+	}`)}
+
+	tests := []struct {
+		name      string
+		overrides map[string]Severity
+		rules     *akamaiV1alpha1.PropertyRules
+		expected  []string
+		blocking  bool
+	}{
+		{
+			name: "clean rule tree",
+			rules: &akamaiV1alpha1.PropertyRules{
+				Name: "default",
+				Behaviors: []akamaiV1alpha1.RuleBehavior{
+					{Name: "cpCode", Options: runtime.RawExtension{Raw: []byte(`{"value":{"id":123}}`)}},
+				},
+				Children: []runtime.RawExtension{redirectChild},
+			},
+		},
+		{
+			name:      "all findings",
+			overrides: map[string]Severity{RuleMissingCPCode: SeverityBlocking},
+			rules: &akamaiV1alpha1.PropertyRules{
+				Name: "default",
+				Behaviors: []akamaiV1alpha1.RuleBehavior{
+					{Name: "caching", Options: runtime.RawExtension{Raw: []byte(`{"behavior":"NO_STORE"}`)}},
+				},
+			},
+			expected: []string{RuleCachingNoStoreAtDefault, RuleHTTPWithoutRedirect, RuleMissingCPCode},
+			blocking: true,
+		},
+		{
+			name:      "defaults warn and overrides disable rules",
+			overrides: map[string]Severity{RuleHTTPWithoutRedirect: SeverityOff},
+			rules:     &akamaiV1alpha1.PropertyRules{Name: "default"},
+			expected:  []string{RuleMissingCPCode},
+			blocking:  false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			findings := NewLinter(tt.overrides).Lint(tt.rules)
+			if len(findings) != len(tt.expected) {
+				t.Fatalf("expected %d findings, got %v", len(tt.expected), findings)
+			}
+			for i, finding := range findings {
+				if finding.Rule != tt.expected[i] {
+					t.Errorf("finding %d: expected rule %s, got %s", i, tt.expected[i], finding.Rule)
+				}
+			}
+			if HasBlocking(findings) != tt.blocking {
+				t.Errorf("expected blocking=%v", tt.blocking)
+			}
+		})
+	}
+}
+
+func TestParseSeverities(t *testing.T) {
+	severities, err := ParseSeverities("missing-cpcode=warning, http-without-redirect=off")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if severities[RuleMissingCPCode] != SeverityWarning || severities[RuleHTTPWithoutRedirect] != SeverityOff {
+		t.Errorf("unexpected severities: %v", severities)
+	}
+
+	for _, invalid := range []string{"missing-cpcode", "unknown=warning", "missing-cpcode=fatal"} {
+		if _, err := ParseSeverities(invalid); err == nil {
+			t.Errorf("expected error for %q", invalid)
+		}
+	}
+}
@@ -0,0 +1,147 @@
+package render
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os/exec"
+	"strings"
+	"time"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+)
+
+// externalTimeout bounds a single call of an external renderer
+const externalTimeout = 30 * time.Second
+
+// maxRenderedSize bounds the rule tree read from an external renderer
+const maxRenderedSize = 8 << 20
+
+// errRenderedTooLarge is returned when an external renderer outputs more than maxRenderedSize
+var errRenderedTooLarge = fmt.Errorf("rendered rule tree exceeds %d bytes", maxRenderedSize)
+
+// limitedBuffer is a buffer failing writes beyond maxRenderedSize, so a runaway renderer is
+// stopped instead of filling the operator's memory. The buffer isn't embedded, so io.Copy can't
+// bypass the limit through its ReadFrom method.
+type limitedBuffer struct {
+	buf      bytes.Buffer
+	exceeded bool
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if b.buf.Len()+len(p) > maxRenderedSize {
+		b.exceeded = true
+		return 0, errRenderedTooLarge
+	}
+	return b.buf.Write(p)
+}
+
+// Exec renders the rule tree with an external command. The command receives the Input as JSON
+// on stdin and writes the rule tree to stdout.
+type Exec struct {
+	Command string
+	Args    []string
+}
+
+// Render runs the command
+func (e Exec) Render(ctx context.Context, input Input) (*akamaiV1alpha1.PropertyRules, error) {
+	payload, err := json.Marshal(input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal renderer input: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, externalTimeout)
+	defer cancel()
+	var stdout limitedBuffer
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, e.Command, e.Args...)
+	cmd.Stdin = bytes.NewReader(payload)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	err = cmd.Run()
+	if stdout.exceeded {
+		return nil, fmt.Errorf("renderer %s failed: %w", e.Command, errRenderedTooLarge)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("renderer %s failed: %w: %s", e.Command, err, strings.TrimSpace(stderr.String()))
+	}
+	return ParseRules(stdout.buf.Bytes())
+}
+
+// Webhook renders the rule tree with an HTTP service. The Input is POSTed as JSON and the
+// response body holds the rule tree.
+type Webhook struct {
+	URL    string
+	Client *http.Client
+}
+
+// Render calls the webhook
+func (w Webhook) Render(ctx context.Context, input Input) (*akamaiV1alpha1.PropertyRules, error) {
+	payload, err := json.Marshal(input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal renderer input: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, externalTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.URL, bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create renderer request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	client := w.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("renderer %s failed: %w", w.URL, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxRenderedSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read renderer response: %w", err)
+	}
+	if len(body) > maxRenderedSize {
+		return nil, fmt.Errorf("renderer %s failed: %w", w.URL, errRenderedTooLarge)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("renderer %s returned %s: %s", w.URL, resp.Status, strings.TrimSpace(string(body)))
+	}
+	return ParseRules(body)
+}
+
+// ParseExternal parses a comma separated list of external renderers as name=target pairs. A
+// target starting with http:// or https:// is a webhook, any other target is a command line,
+// optionally prefixed with exec:, e.g. "corp=exec:/usr/local/bin/render-rules,api=https://rules.internal/render"
+func ParseExternal(value string) (map[string]Renderer, error) {
+	renderers := make(map[string]Renderer)
+	if strings.TrimSpace(value) == "" {
+		return renderers, nil
+	}
+
+	for _, pair := range strings.Split(value, ",") {
+		name, target, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok || name == "" || target == "" {
+			return nil, fmt.Errorf("invalid external renderer %q: expected name=target", pair)
+		}
+		if _, exists := renderers[name]; exists {
+			return nil, fmt.Errorf("duplicate external renderer %q", name)
+		}
+		if strings.HasPrefix(target, "http://") || strings.HasPrefix(target, "https://") {
+			renderers[name] = Webhook{URL: target}
+			continue
+		}
+		fields := strings.Fields(strings.TrimPrefix(target, "exec:"))
+		if len(fields) == 0 {
+			return nil, fmt.Errorf("invalid external renderer %q: empty command", pair)
+		}
+		renderers[name] = Exec{Command: fields[0], Args: fields[1:]}
+	}
+	return renderers, nil
+}
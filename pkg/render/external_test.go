@@ -0,0 +1,96 @@
+package render
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+)
+
+func TestExternalRenderers(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		var input Input
+		if err := json.NewDecoder(req.Body).Decode(&input); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"rules": map[string]interface{}{"name": "default", "comments": input.Property.Name + " " + input.Values["env"]},
+		})
+	}))
+	defer server.Close()
+
+	script := filepath.Join(t.TempDir(), "render.sh")
+	if err := os.WriteFile(script, []byte("#!/bin/sh\ncat >/dev/null\necho '{\"name\":\"default\",\"comments\":\"'$1'\"}'\n"), 0o755); err != nil {
+		t.Fatalf("failed to write script: %v", err)
+	}
+
+	renderers, err := ParseExternal("web=" + server.URL + ", cmd=exec:" + script + " exec")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	input := Input{
+		Property: &akamaiV1alpha1.AkamaiProperty{ObjectMeta: metav1.ObjectMeta{Name: "shop"}},
+		Values:   map[string]string{"env": "prod"},
+	}
+
+	tests := []struct {
+		name     string
+		expected string
+	}{
+		{name: "web", expected: "shop prod"},
+		{name: "cmd", expected: "exec"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			renderer, ok := renderers[tt.name]
+			if !ok {
+				t.Fatalf("renderer %q not registered", tt.name)
+			}
+			rules, err := renderer.Render(context.Background(), input)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if rules.Comments != tt.expected {
+				t.Errorf("comments = %q, expected %q", rules.Comments, tt.expected)
+			}
+		})
+	}
+}
+
+func TestExternalRenderersTooLarge(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		_, _ = w.Write(make([]byte, maxRenderedSize+1))
+	}))
+	defer server.Close()
+
+	script := filepath.Join(t.TempDir(), "render.sh")
+	if err := os.WriteFile(script, []byte(fmt.Sprintf("#!/bin/sh\ncat >/dev/null\nhead -c %d /dev/zero\n", maxRenderedSize+1)), 0o755); err != nil {
+		t.Fatalf("failed to write script: %v", err)
+	}
+
+	for name, renderer := range map[string]Renderer{"web": Webhook{URL: server.URL}, "cmd": Exec{Command: script}} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := renderer.Render(context.Background(), Input{}); !errors.Is(err, errRenderedTooLarge) {
+				t.Errorf("error = %v, expected %v", err, errRenderedTooLarge)
+			}
+		})
+	}
+}
+
+func TestParseExternalErrors(t *testing.T) {
+	for _, value := range []string{"missing-target", "a=exec:", "a=/bin/a,a=/bin/b"} {
+		if _, err := ParseExternal(value); err == nil {
+			t.Errorf("expected an error for %q", value)
+		}
+	}
+}
@@ -0,0 +1,547 @@
+package render
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+)
+
+// remoteTimeout bounds loading the files of a remote source
+const remoteTimeout = 60 * time.Second
+
+// maxRemoteFileSize bounds a single file of a remote source
+const maxRemoteFileSize = 8 << 20
+
+// maxCachedURLs and maxCachedArtifacts bound the sources a Fetcher keeps; the least recently used
+// ones are evicted first
+const (
+	maxCachedURLs      = 256
+	maxCachedArtifacts = 64
+)
+
+// dockerHubTokenHost hands out the bearer tokens of Docker Hub, which references without a
+// registry resolve to
+const dockerHubTokenHost = "auth.docker.io"
+
+// ociTitleAnnotation names the file of an OCI artifact layer, as set by e.g. `oras push`
+const ociTitleAnnotation = "org.opencontainers.image.title"
+
+// manifestMediaTypes are the manifest formats accepted from registries
+var manifestMediaTypes = []string{
+	"application/vnd.oci.image.manifest.v1+json",
+	"application/vnd.docker.distribution.manifest.v2+json",
+}
+
+// digestPattern matches the content digests used for checksum pinning
+var digestPattern = regexp.MustCompile(`^sha256:[a-f0-9]{64}$`)
+
+// Remote is a source of renderer files outside the cluster: a single file at an HTTPS URL or
+// the layers of an OCI artifact
+type Remote struct {
+	// URL is an HTTPS URL; its content is stored as the entry file
+	URL string
+
+	// OCIRef references an OCI artifact as registry/repository:tag or registry/repository@digest.
+	// Every layer with an org.opencontainers.image.title annotation is a file.
+	OCIRef string
+
+	// Checksum pins the content to a sha256:<hex> digest: of the file of URL, or of the manifest
+	// of OCIRef
+	Checksum string
+
+	// Entry is the name the file of URL is stored as
+	Entry string
+
+	// Username and Password authenticate to the web server or registry, if set
+	Username string
+	Password string
+}
+
+// Fetched are the files of a remote source and the digest they were loaded at
+type Fetched struct {
+	Files  map[string]string
+	Digest string
+}
+
+// cachedURL is the last response of a URL, revalidated with its ETag
+type cachedURL struct {
+	etag    string
+	content string
+	digest  string
+}
+
+// Fetcher loads remote sources and caches them: files of URLs until the server reports a change,
+// OCI artifacts by manifest digest. Sources pinned to a cached checksum are not requested again.
+// Cached content is only served to sources with the credentials it was loaded with.
+type Fetcher struct {
+	// Client sends the requests. Defaults to http.DefaultClient.
+	Client *http.Client
+
+	// TokenHosts are the hosts, besides the registry itself and Docker Hub's auth.docker.io, that
+	// registries may send credentials to in exchange for a bearer token
+	TokenHosts []string
+
+	mu        sync.Mutex
+	urls      *lruCache[cachedURL]
+	artifacts *lruCache[map[string]string]
+}
+
+// Fetch loads the files of a remote source, verifying its checksum when it is pinned
+func (f *Fetcher) Fetch(ctx context.Context, remote Remote) (*Fetched, error) {
+	if remote.Checksum != "" && !digestPattern.MatchString(remote.Checksum) {
+		return nil, fmt.Errorf("invalid checksum %q, expected sha256:<hex>", remote.Checksum)
+	}
+	ctx, cancel := context.WithTimeout(ctx, remoteTimeout)
+	defer cancel()
+	if remote.OCIRef != "" {
+		return f.fetchArtifact(ctx, remote)
+	}
+	return f.fetchURL(ctx, remote)
+}
+
+// fetchURL loads a single file, revalidating the cached copy with its ETag
+func (f *Fetcher) fetchURL(ctx context.Context, remote Remote) (*Fetched, error) {
+	if !strings.HasPrefix(remote.URL, "https://") {
+		return nil, fmt.Errorf("url %q must use https", remote.URL)
+	}
+	entry := remote.Entry
+	if entry == "" {
+		entry = DefaultEntry
+	}
+
+	key := credentialsKey(remote) + remote.URL
+	f.mu.Lock()
+	cached, ok := f.urls.get(key)
+	f.mu.Unlock()
+	if ok && remote.Checksum != "" && cached.digest == remote.Checksum {
+		return &Fetched{Files: map[string]string{entry: cached.content}, Digest: cached.digest}, nil
+	}
+
+	header := http.Header{}
+	if ok && cached.etag != "" {
+		header.Set("If-None-Match", cached.etag)
+	}
+	resp, err := f.get(ctx, remote.URL, header, basicAuth(remote))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotModified && ok:
+	case resp.StatusCode == http.StatusOK:
+		content, err := readLimited(resp.Body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", remote.URL, err)
+		}
+		cached = cachedURL{etag: resp.Header.Get("ETag"), content: string(content), digest: digestOf(content)}
+	default:
+		return nil, fmt.Errorf("GET %s returned %s", remote.URL, resp.Status)
+	}
+	if remote.Checksum != "" && cached.digest != remote.Checksum {
+		return nil, fmt.Errorf("checksum mismatch for %s: expected %s, got %s", remote.URL, remote.Checksum, cached.digest)
+	}
+
+	f.mu.Lock()
+	if f.urls == nil {
+		f.urls = newLRUCache[cachedURL](maxCachedURLs)
+	}
+	f.urls.add(key, cached)
+	f.mu.Unlock()
+	return &Fetched{Files: map[string]string{entry: cached.content}, Digest: cached.digest}, nil
+}
+
+// ociManifest is the part of an image manifest describing the layers
+type ociManifest struct {
+	MediaType string          `json:"mediaType"`
+	Layers    []ociDescriptor `json:"layers"`
+}
+
+// ociDescriptor describes a blob of an artifact
+type ociDescriptor struct {
+	MediaType   string            `json:"mediaType"`
+	Digest      string            `json:"digest"`
+	Size        int64             `json:"size"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// fetchArtifact loads the titled layers of an OCI artifact. The manifest is requested on every
+// fetch unless its digest is pinned and cached; layers are only downloaded for a new digest.
+func (f *Fetcher) fetchArtifact(ctx context.Context, remote Remote) (*Fetched, error) {
+	registry, repository, reference, err := ParseOCIRef(remote.OCIRef)
+	if err != nil {
+		return nil, err
+	}
+	pinned := remote.Checksum
+	if strings.HasPrefix(reference, "sha256:") {
+		if pinned != "" && pinned != reference {
+			return nil, fmt.Errorf("checksum %s does not match the digest of %s", pinned, remote.OCIRef)
+		}
+		pinned = reference
+	}
+	if files, ok := f.cachedArtifact(remote, pinned); ok {
+		return &Fetched{Files: files, Digest: pinned}, nil
+	}
+
+	auth := &registryAuth{fetcher: f, remote: remote, registry: registry, repository: repository}
+	base := "https://" + registry + "/v2/" + repository
+	header := http.Header{"Accept": []string{strings.Join(manifestMediaTypes, ", ")}}
+	resp, err := auth.get(ctx, base+"/manifests/"+reference, header)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to get manifest of %s: %s", remote.OCIRef, resp.Status)
+	}
+	raw, err := readLimited(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest of %s: %w", remote.OCIRef, err)
+	}
+	digest := digestOf(raw)
+	if pinned != "" && digest != pinned {
+		return nil, fmt.Errorf("checksum mismatch for %s: expected %s, got %s", remote.OCIRef, pinned, digest)
+	}
+	if files, ok := f.cachedArtifact(remote, digest); ok {
+		return &Fetched{Files: files, Digest: digest}, nil
+	}
+
+	var manifest ociManifest
+	if err := json.Unmarshal(raw, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse manifest of %s: %w", remote.OCIRef, err)
+	}
+	files := make(map[string]string)
+	for _, layer := range manifest.Layers {
+		name := layer.Annotations[ociTitleAnnotation]
+		if name == "" {
+			continue
+		}
+		if layer.Size > maxRemoteFileSize {
+			return nil, fmt.Errorf("file %q of %s exceeds %d bytes", name, remote.OCIRef, maxRemoteFileSize)
+		}
+		content, err := auth.blob(ctx, base+"/blobs/"+layer.Digest)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get file %q of %s: %w", name, remote.OCIRef, err)
+		}
+		if got := digestOf(content); got != layer.Digest {
+			return nil, fmt.Errorf("file %q of %s has digest %s, expected %s", name, remote.OCIRef, got, layer.Digest)
+		}
+		files[name] = string(content)
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("artifact %s has no layers with an %s annotation", remote.OCIRef, ociTitleAnnotation)
+	}
+
+	f.mu.Lock()
+	if f.artifacts == nil {
+		f.artifacts = newLRUCache[map[string]string](maxCachedArtifacts)
+	}
+	f.artifacts.add(credentialsKey(remote)+digest, files)
+	f.mu.Unlock()
+	return &Fetched{Files: files, Digest: digest}, nil
+}
+
+// cachedArtifact returns the files of an artifact loaded before with the credentials of remote
+func (f *Fetcher) cachedArtifact(remote Remote, digest string) (map[string]string, bool) {
+	if digest == "" {
+		return nil, false
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.artifacts.get(credentialsKey(remote) + digest)
+}
+
+// registryAuth authenticates the requests to a registry, exchanging the credentials for a bearer
+// token when the registry asks for one
+type registryAuth struct {
+	fetcher    *Fetcher
+	remote     Remote
+	registry   string
+	repository string
+	token      string
+}
+
+// get sends a request, answering an authentication challenge once
+func (a *registryAuth) get(ctx context.Context, target string, header http.Header) (*http.Response, error) {
+	resp, err := a.fetcher.get(ctx, target, header, a.authorization())
+	if err != nil || resp.StatusCode != http.StatusUnauthorized || a.token != "" {
+		return resp, err
+	}
+	challenge := resp.Header.Get("WWW-Authenticate")
+	resp.Body.Close()
+	if !strings.HasPrefix(strings.ToLower(challenge), "bearer ") {
+		return nil, fmt.Errorf("GET %s: registry denied access", target)
+	}
+	if a.token, err = a.requestToken(ctx, challenge); err != nil {
+		return nil, err
+	}
+	return a.fetcher.get(ctx, target, header, a.authorization())
+}
+
+// blob downloads a blob
+func (a *registryAuth) blob(ctx context.Context, target string) ([]byte, error) {
+	resp, err := a.get(ctx, target, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GET %s returned %s", target, resp.Status)
+	}
+	return readLimited(resp.Body)
+}
+
+// authorization returns the Authorization header of the next request
+func (a *registryAuth) authorization() string {
+	if a.token != "" {
+		return "Bearer " + a.token
+	}
+	return basicAuth(a.remote)
+}
+
+// requestToken obtains a pull token from the realm of a bearer challenge. The realm must use
+// https, and the credentials are only sent to the registry itself or a trusted token host.
+func (a *registryAuth) requestToken(ctx context.Context, challenge string) (string, error) {
+	params := parseChallenge(challenge[len("bearer "):])
+	realm := params["realm"]
+	if realm == "" {
+		return "", fmt.Errorf("registry challenge %q has no realm", challenge)
+	}
+	realmURL, err := url.Parse(realm)
+	if err != nil || realmURL.Scheme != "https" || realmURL.Host == "" {
+		return "", fmt.Errorf("registry %s asked for a token from %q, which is not an https URL", a.registry, realm)
+	}
+	authorization := basicAuth(a.remote)
+	if authorization != "" && !a.trustsTokenHost(realmURL) {
+		return "", fmt.Errorf("registry %s asked for a token from %s, which is not trusted with its credentials; add the host to the trusted token hosts",
+			a.registry, realmURL.Host)
+	}
+	query := url.Values{}
+	if service := params["service"]; service != "" {
+		query.Set("service", service)
+	}
+	scope := params["scope"]
+	if scope == "" {
+		scope = "repository:" + a.repository + ":pull"
+	}
+	query.Set("scope", scope)
+	separator := "?"
+	if strings.Contains(realm, "?") {
+		separator = "&"
+	}
+
+	resp, err := a.fetcher.get(ctx, realm+separator+query.Encode(), nil, authorization)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("registry token request returned %s", resp.Status)
+	}
+	var token struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxRemoteFileSize)).Decode(&token); err != nil {
+		return "", fmt.Errorf("failed to parse registry token: %w", err)
+	}
+	if token.Token != "" {
+		return token.Token, nil
+	}
+	if token.AccessToken != "" {
+		return token.AccessToken, nil
+	}
+	return "", fmt.Errorf("registry token response has no token")
+}
+
+// trustsTokenHost reports whether the credentials may be sent to the token realm
+func (a *registryAuth) trustsTokenHost(realm *url.URL) bool {
+	if strings.EqualFold(realm.Host, a.registry) {
+		return true
+	}
+	host := realm.Hostname()
+	if a.registry == "registry-1.docker.io" && strings.EqualFold(host, dockerHubTokenHost) {
+		return true
+	}
+	for _, trusted := range a.fetcher.TokenHosts {
+		if strings.EqualFold(host, trusted) || strings.EqualFold(realm.Host, trusted) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseChallenge parses the comma separated key="value" parameters of an authentication challenge
+func parseChallenge(params string) map[string]string {
+	parsed := make(map[string]string)
+	for _, match := range regexp.MustCompile(`(\w+)="([^"]*)"`).FindAllStringSubmatch(params, -1) {
+		parsed[strings.ToLower(match[1])] = match[2]
+	}
+	return parsed
+}
+
+// get sends a GET request with the given headers and Authorization
+func (f *Fetcher) get(ctx context.Context, target string, header http.Header, authorization string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request for %s: %w", target, err)
+	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+	client := f.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("GET %s failed: %w", target, err)
+	}
+	return resp, nil
+}
+
+// ParseOCIRef splits an OCI reference into registry, repository and tag or digest. References
+// without a registry are resolved against Docker Hub, and the tag defaults to "latest".
+func ParseOCIRef(ref string) (registry, repository, reference string, err error) {
+	name := strings.TrimPrefix(ref, "oci://")
+	if name == "" {
+		return "", "", "", fmt.Errorf("empty OCI reference")
+	}
+	if at := strings.Index(name, "@"); at != -1 {
+		name, reference = name[:at], name[at+1:]
+		if !digestPattern.MatchString(reference) {
+			return "", "", "", fmt.Errorf("invalid digest in OCI reference %q", ref)
+		}
+	}
+	if reference == "" {
+		reference = "latest"
+		if colon := strings.LastIndex(name, ":"); colon > strings.LastIndex(name, "/") {
+			name, reference = name[:colon], name[colon+1:]
+		}
+	}
+
+	registry, repository, found := strings.Cut(name, "/")
+	if !found || !strings.ContainsAny(registry, ".:") && registry != "localhost" {
+		registry, repository = "registry-1.docker.io", name
+		if !strings.Contains(repository, "/") {
+			repository = "library/" + repository
+		}
+	}
+	if repository == "" || reference == "" {
+		return "", "", "", fmt.Errorf("invalid OCI reference %q", ref)
+	}
+	return registry, repository, reference, nil
+}
+
+// basicAuth returns the basic Authorization header of the credentials of a remote source
+func basicAuth(remote Remote) string {
+	if remote.Username == "" && remote.Password == "" {
+		return ""
+	}
+	req := &http.Request{Header: http.Header{}}
+	req.SetBasicAuth(remote.Username, remote.Password)
+	return req.Header.Get("Authorization")
+}
+
+// credentialsKey identifies the credentials of a remote source in cache keys without exposing them
+func credentialsKey(remote Remote) string {
+	authorization := basicAuth(remote)
+	if authorization == "" {
+		return "anonymous "
+	}
+	sum := sha256.Sum256([]byte(authorization))
+	return hex.EncodeToString(sum[:]) + " "
+}
+
+// lruCache holds up to max entries, evicting the least recently used one. It is not safe for
+// concurrent use; the Fetcher guards it with its mutex.
+type lruCache[V any] struct {
+	max     int
+	order   *list.List
+	entries map[string]*list.Element
+}
+
+// lruEntry is an element of an lruCache
+type lruEntry[V any] struct {
+	key   string
+	value V
+}
+
+func newLRUCache[V any](max int) *lruCache[V] {
+	return &lruCache[V]{max: max, order: list.New(), entries: make(map[string]*list.Element)}
+}
+
+// get returns the value of key, marking it as recently used
+func (c *lruCache[V]) get(key string) (V, bool) {
+	var zero V
+	if c == nil {
+		return zero, false
+	}
+	element, ok := c.entries[key]
+	if !ok {
+		return zero, false
+	}
+	c.order.MoveToFront(element)
+	return element.Value.(*lruEntry[V]).value, true
+}
+
+// add stores value under key, evicting the least recently used entry when the cache is full
+func (c *lruCache[V]) add(key string, value V) {
+	if element, ok := c.entries[key]; ok {
+		element.Value.(*lruEntry[V]).value = value
+		c.order.MoveToFront(element)
+		return
+	}
+	c.entries[key] = c.order.PushFront(&lruEntry[V]{key: key, value: value})
+	if c.order.Len() > c.max {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*lruEntry[V]).key)
+	}
+}
+
+// readLimited reads a body of at most maxRemoteFileSize bytes
+func readLimited(body io.Reader) ([]byte, error) {
+	content, err := io.ReadAll(io.LimitReader(body, maxRemoteFileSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(content) > maxRemoteFileSize {
+		return nil, fmt.Errorf("content exceeds %d bytes", maxRemoteFileSize)
+	}
+	return content, nil
+}
+
+// digestOf returns the sha256 digest of content
+func digestOf(content []byte) string {
+	sum := sha256.Sum256(content)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// File renders the entry file as the rule tree, e.g. a rule tree loaded from a remote source
+type File struct{}
+
+// Render parses the entry file
+func (File) Render(_ context.Context, input Input) (*akamaiV1alpha1.PropertyRules, error) {
+	entry := entryOf(input)
+	content, ok := input.Files[entry]
+	if !ok {
+		return nil, fmt.Errorf("file %q not found", entry)
+	}
+	return ParseRules([]byte(content))
+}
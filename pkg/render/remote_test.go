@@ -0,0 +1,272 @@
+package render
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestFetchURL(t *testing.T) {
+	content := `{"name":"default","comments":"from url"}`
+	var requests, revalidated atomic.Int32
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		requests.Add(1)
+		if req.Header.Get("If-None-Match") == `"v1"` {
+			revalidated.Add(1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		_, _ = w.Write([]byte(content))
+	}))
+	defer server.Close()
+
+	fetcher := &Fetcher{Client: server.Client()}
+	digest := digestOf([]byte(content))
+	remote := Remote{URL: server.URL + "/shop.json", Checksum: digest, Entry: "shop.json"}
+
+	fetched, err := fetcher.Fetch(context.Background(), remote)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if fetched.Digest != digest || fetched.Files["shop.json"] != content {
+		t.Fatalf("unexpected fetch result %+v", fetched)
+	}
+	rules, err := File{}.Render(context.Background(), Input{Files: fetched.Files, Entry: "shop.json"})
+	if err != nil || rules.Comments != "from url" {
+		t.Fatalf("File.Render() = %+v, %v", rules, err)
+	}
+
+	// Pinned content is served from the cache, unpinned content is revalidated
+	if _, err := fetcher.Fetch(context.Background(), remote); err != nil || requests.Load() != 1 {
+		t.Fatalf("expected the pinned URL to be cached, got %d requests, error %v", requests.Load(), err)
+	}
+	remote.Checksum = ""
+	if fetched, err := fetcher.Fetch(context.Background(), remote); err != nil || fetched.Files["shop.json"] != content || revalidated.Load() != 1 {
+		t.Fatalf("expected a revalidated copy, got %+v, error %v", fetched, err)
+	}
+
+	remote.Checksum = "sha256:" + strings.Repeat("0", 64)
+	if _, err := fetcher.Fetch(context.Background(), remote); err == nil || !strings.Contains(err.Error(), "checksum mismatch") {
+		t.Errorf("expected a checksum mismatch, got %v", err)
+	}
+}
+
+func TestFetchArtifact(t *testing.T) {
+	files := map[string]string{"main.json": `{"name":"default"}`, "origin.json": `{"name":"origin"}`}
+	blobs := make(map[string]string)
+	manifest := ociManifest{MediaType: manifestMediaTypes[0]}
+	for _, name := range []string{"main.json", "origin.json"} {
+		digest := digestOf([]byte(files[name]))
+		blobs[digest] = files[name]
+		manifest.Layers = append(manifest.Layers, ociDescriptor{
+			MediaType:   "application/json",
+			Digest:      digest,
+			Size:        int64(len(files[name])),
+			Annotations: map[string]string{ociTitleAnnotation: name},
+		})
+	}
+	rawManifest, err := json.Marshal(manifest)
+	if err != nil {
+		t.Fatalf("failed to marshal manifest: %v", err)
+	}
+	manifestDigest := digestOf(rawManifest)
+
+	var blobRequests atomic.Int32
+	var server *httptest.Server
+	server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path == "/token" {
+			if username, password, ok := req.BasicAuth(); !ok || username != "ci" || password != "secret" ||
+				req.URL.Query().Get("scope") != "repository:akamai/shop-rules:pull" {
+				http.Error(w, "denied", http.StatusUnauthorized)
+				return
+			}
+			_ = json.NewEncoder(w).Encode(map[string]string{"token": "pull-token"})
+			return
+		}
+		if req.Header.Get("Authorization") != "Bearer pull-token" {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="`+server.URL+`/token",service="registry",scope="repository:akamai/shop-rules:pull"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch {
+		case req.URL.Path == "/v2/akamai/shop-rules/manifests/v1":
+			w.Header().Set("Content-Type", manifestMediaTypes[0])
+			_, _ = w.Write(rawManifest)
+		case strings.HasPrefix(req.URL.Path, "/v2/akamai/shop-rules/blobs/"):
+			blobRequests.Add(1)
+			blob, ok := blobs[strings.TrimPrefix(req.URL.Path, "/v2/akamai/shop-rules/blobs/")]
+			if !ok {
+				http.NotFound(w, req)
+				return
+			}
+			_, _ = w.Write([]byte(blob))
+		default:
+			http.NotFound(w, req)
+		}
+	}))
+	defer server.Close()
+
+	fetcher := &Fetcher{Client: server.Client()}
+	ref := strings.TrimPrefix(server.URL, "https://") + "/akamai/shop-rules:v1"
+	remote := Remote{OCIRef: ref, Username: "ci", Password: "secret"}
+
+	fetched, err := fetcher.Fetch(context.Background(), remote)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if fetched.Digest != manifestDigest || len(fetched.Files) != 2 || fetched.Files["origin.json"] != files["origin.json"] {
+		t.Fatalf("unexpected fetch result %+v", fetched)
+	}
+
+	// An unchanged manifest digest reuses the downloaded files
+	remote.Checksum = manifestDigest
+	if _, err := fetcher.Fetch(context.Background(), remote); err != nil || blobRequests.Load() != 2 {
+		t.Fatalf("expected cached files, got %d blob requests, error %v", blobRequests.Load(), err)
+	}
+
+	remote.Checksum = "sha256:" + strings.Repeat("0", 64)
+	if _, err := fetcher.Fetch(context.Background(), remote); err == nil || !strings.Contains(err.Error(), "checksum mismatch") {
+		t.Errorf("expected a checksum mismatch, got %v", err)
+	}
+	remote.Checksum, remote.Password = "", "wrong"
+	if _, err := (&Fetcher{Client: server.Client()}).Fetch(context.Background(), remote); err == nil {
+		t.Error("expected wrong credentials to fail")
+	}
+}
+
+func TestRegistryTokenRealm(t *testing.T) {
+	var tokenRequests atomic.Int32
+	tokenServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		tokenRequests.Add(1)
+		_ = json.NewEncoder(w).Encode(map[string]string{"token": "pull-token"})
+	}))
+	defer tokenServer.Close()
+	tokenHost := strings.TrimPrefix(tokenServer.URL, "https://")
+
+	tests := []struct {
+		name       string
+		realm      string
+		tokenHosts []string
+		username   string
+		err        string
+	}{
+		{name: "http realm", realm: "http://" + tokenHost + "/token", username: "ci", err: "not an https URL"},
+		{name: "untrusted token host", realm: tokenServer.URL + "/token", username: "ci", err: "not trusted with its credentials"},
+		{name: "trusted token host", realm: tokenServer.URL + "/token", tokenHosts: []string{tokenHost}, username: "ci"},
+		{name: "anonymous token", realm: tokenServer.URL + "/token"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tokenRequests.Store(0)
+			registry := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				if req.Header.Get("Authorization") != "Bearer pull-token" {
+					w.Header().Set("WWW-Authenticate", `Bearer realm="`+tt.realm+`",service="registry"`)
+					w.WriteHeader(http.StatusUnauthorized)
+					return
+				}
+				http.NotFound(w, req)
+			}))
+			defer registry.Close()
+
+			fetcher := &Fetcher{Client: registry.Client(), TokenHosts: tt.tokenHosts}
+			remote := Remote{OCIRef: strings.TrimPrefix(registry.URL, "https://") + "/akamai/shop-rules:v1", Username: tt.username, Password: "secret"}
+			if tt.username == "" {
+				remote.Password = ""
+			}
+			_, err := fetcher.Fetch(context.Background(), remote)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) || tokenRequests.Load() != 0 {
+					t.Fatalf("Fetch() error = %v after %d token requests, expected %q without a token request", err, tokenRequests.Load(), tt.err)
+				}
+				return
+			}
+			// The token is accepted; the manifest itself doesn't exist
+			if err == nil || !strings.Contains(err.Error(), "404") || tokenRequests.Load() != 1 {
+				t.Fatalf("Fetch() error = %v after %d token requests, expected a token and a missing manifest", err, tokenRequests.Load())
+			}
+		})
+	}
+}
+
+func TestFetchURLCacheIsolatesCredentials(t *testing.T) {
+	content := `{"name":"default","comments":"private"}`
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if _, password, ok := req.BasicAuth(); !ok || password != "secret" {
+			http.Error(w, "denied", http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(content))
+	}))
+	defer server.Close()
+
+	fetcher := &Fetcher{Client: server.Client()}
+	remote := Remote{URL: server.URL + "/private.json", Checksum: digestOf([]byte(content)), Username: "ci", Password: "secret"}
+	if _, err := fetcher.Fetch(context.Background(), remote); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// The cached copy isn't served to a source without the credentials
+	remote.Username, remote.Password = "", ""
+	if _, err := fetcher.Fetch(context.Background(), remote); err == nil || !strings.Contains(err.Error(), "401") {
+		t.Errorf("expected the anonymous fetch to be denied, got %v", err)
+	}
+}
+
+func TestLRUCache(t *testing.T) {
+	cache := newLRUCache[int](2)
+	cache.add("a", 1)
+	cache.add("b", 2)
+	if _, ok := cache.get("a"); !ok {
+		t.Fatal("expected a to be cached")
+	}
+	// b is the least recently used entry
+	cache.add("c", 3)
+	if _, ok := cache.get("b"); ok {
+		t.Error("expected b to be evicted")
+	}
+	for key, expected := range map[string]int{"a": 1, "c": 3} {
+		if value, ok := cache.get(key); !ok || value != expected {
+			t.Errorf("get(%q) = %d, %v, expected %d", key, value, ok, expected)
+		}
+	}
+}
+
+func TestParseOCIRef(t *testing.T) {
+	digest := "sha256:" + strings.Repeat("a", 64)
+	tests := []struct {
+		ref        string
+		registry   string
+		repository string
+		reference  string
+		err        bool
+	}{
+		{ref: "ghcr.io/example/rules:v1", registry: "ghcr.io", repository: "example/rules", reference: "v1"},
+		{ref: "oci://localhost:5000/rules", registry: "localhost:5000", repository: "rules", reference: "latest"},
+		{ref: "registry.example.com/team/rules@" + digest, registry: "registry.example.com", repository: "team/rules", reference: digest},
+		{ref: "example/rules:v2", registry: "registry-1.docker.io", repository: "example/rules", reference: "v2"},
+		{ref: "rules", registry: "registry-1.docker.io", repository: "library/rules", reference: "latest"},
+		{ref: "ghcr.io/example/rules@sha256:abc", err: true},
+		{ref: "", err: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.ref, func(t *testing.T) {
+			registry, repository, reference, err := ParseOCIRef(tt.ref)
+			if tt.err {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if registry != tt.registry || repository != tt.repository || reference != tt.reference {
+				t.Errorf("ParseOCIRef() = %s, %s, %s", registry, repository, reference)
+			}
+		})
+	}
+}
@@ -0,0 +1,226 @@
+package render
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+)
+
+// DefaultEntry is the file rendered first by the Template and Pipeline renderers
+const DefaultEntry = "main.json"
+
+// Input is what a renderer receives: the property and the references resolved from its spec
+type Input struct {
+	// Property is the AkamaiProperty the rule tree is rendered for
+	Property *akamaiV1alpha1.AkamaiProperty `json:"property"`
+
+	// Files are the entries of the ConfigMap referenced by spec.renderer.configMapRef or the files
+	// loaded from spec.rulesFrom
+	Files map[string]string `json:"files,omitempty"`
+
+	// Entry is the file rendered first
+	Entry string `json:"entry,omitempty"`
+
+	// Values are the values of spec.renderer.values
+	Values map[string]string `json:"values,omitempty"`
+}
+
+// Renderer turns a property and its references into the final rule tree
+type Renderer interface {
+	Render(ctx context.Context, input Input) (*akamaiV1alpha1.PropertyRules, error)
+}
+
+// Inline renders the rule tree of spec.rules as is
+type Inline struct{}
+
+// Render returns spec.rules
+func (Inline) Render(_ context.Context, input Input) (*akamaiV1alpha1.PropertyRules, error) {
+	if input.Property.Spec.Rules == nil {
+		return nil, fmt.Errorf("spec.rules is empty")
+	}
+	return input.Property.Spec.Rules, nil
+}
+
+// Template renders the entry file as a Go template. Every file is available as a named template
+// and the data holds .Name, .PropertyName, .Labels, .Annotations and .Values.
+type Template struct{}
+
+// Render executes the entry template and parses the output as rule tree
+func (Template) Render(_ context.Context, input Input) (*akamaiV1alpha1.PropertyRules, error) {
+	entry := entryOf(input)
+	if _, ok := input.Files[entry]; !ok {
+		return nil, fmt.Errorf("template %q not found", entry)
+	}
+
+	root := template.New(entry).Option("missingkey=error")
+	for _, name := range sortedKeys(input.Files) {
+		if _, err := root.New(name).Parse(input.Files[name]); err != nil {
+			return nil, fmt.Errorf("failed to parse template %q: %w", name, err)
+		}
+	}
+
+	data := map[string]interface{}{
+		"Name":         input.Property.Name,
+		"PropertyName": input.Property.Spec.PropertyName,
+		"Labels":       input.Property.Labels,
+		"Annotations":  input.Property.Annotations,
+		"Values":       input.Values,
+	}
+	var out bytes.Buffer
+	if err := root.ExecuteTemplate(&out, entry, data); err != nil {
+		return nil, fmt.Errorf("failed to execute template %q: %w", entry, err)
+	}
+	return ParseRules(out.Bytes())
+}
+
+// Pipeline renders files in the Akamai CLI pipeline format: string values "#include:<file>" are
+// replaced by the parsed content of that file and "${env.<name>}" placeholders by values.
+// A value that is a complete placeholder takes the JSON type of the value, e.g. a number.
+type Pipeline struct{}
+
+// pipelineVariable matches the variable placeholders of the pipeline format
+var pipelineVariable = regexp.MustCompile(`\$\{env\.([A-Za-z0-9_.-]+)\}`)
+
+// Render resolves the includes and variables of the entry file
+func (Pipeline) Render(_ context.Context, input Input) (*akamaiV1alpha1.PropertyRules, error) {
+	tree, err := pipelineFile(input, entryOf(input), nil)
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(tree)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal rendered rules: %w", err)
+	}
+	return ParseRules(data)
+}
+
+// pipelineFile parses a file and resolves its includes and variables; stack holds the files
+// being included to detect cycles
+func pipelineFile(input Input, name string, stack []string) (interface{}, error) {
+	for _, including := range stack {
+		if including == name {
+			return nil, fmt.Errorf("include cycle: %s -> %s", strings.Join(stack, " -> "), name)
+		}
+	}
+	content, ok := input.Files[name]
+	if !ok {
+		return nil, fmt.Errorf("file %q not found", name)
+	}
+	var tree interface{}
+	if err := json.Unmarshal([]byte(content), &tree); err != nil {
+		return nil, fmt.Errorf("failed to parse %q: %w", name, err)
+	}
+	return pipelineResolve(input, tree, append(stack, name))
+}
+
+// pipelineResolve replaces includes and variables in a parsed file
+func pipelineResolve(input Input, node interface{}, stack []string) (interface{}, error) {
+	switch value := node.(type) {
+	case map[string]interface{}:
+		for key, child := range value {
+			resolved, err := pipelineResolve(input, child, stack)
+			if err != nil {
+				return nil, err
+			}
+			value[key] = resolved
+		}
+		return value, nil
+	case []interface{}:
+		for i, child := range value {
+			resolved, err := pipelineResolve(input, child, stack)
+			if err != nil {
+				return nil, err
+			}
+			value[i] = resolved
+		}
+		return value, nil
+	case string:
+		if name, ok := strings.CutPrefix(value, "#include:"); ok {
+			return pipelineFile(input, name, stack)
+		}
+		return pipelineSubstitute(input.Values, value)
+	default:
+		return node, nil
+	}
+}
+
+// pipelineSubstitute replaces the variable placeholders of a string value
+func pipelineSubstitute(values map[string]string, value string) (interface{}, error) {
+	var missing []string
+	lookup := func(name string) string {
+		v, ok := values[name]
+		if !ok {
+			missing = append(missing, name)
+		}
+		return v
+	}
+
+	if match := pipelineVariable.FindStringSubmatch(value); match != nil && match[0] == value {
+		v := lookup(match[1])
+		if len(missing) > 0 {
+			return nil, fmt.Errorf("undefined variable %q", missing[0])
+		}
+		var typed interface{}
+		if err := json.Unmarshal([]byte(v), &typed); err == nil {
+			return typed, nil
+		}
+		return v, nil
+	}
+
+	result := pipelineVariable.ReplaceAllStringFunc(value, func(placeholder string) string {
+		return lookup(pipelineVariable.FindStringSubmatch(placeholder)[1])
+	})
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("undefined variable %q", missing[0])
+	}
+	return result, nil
+}
+
+// ParseRules parses a rendered rule tree. Both a bare top-level rule and the PAPI format
+// wrapping it in a "rules" field are accepted.
+func ParseRules(data []byte) (*akamaiV1alpha1.PropertyRules, error) {
+	var wrapper struct {
+		Name  string          `json:"name"`
+		Rules json.RawMessage `json:"rules"`
+	}
+	if err := json.Unmarshal(data, &wrapper); err != nil {
+		return nil, fmt.Errorf("rendered rules are not a JSON object: %w", err)
+	}
+	if wrapper.Name == "" && len(wrapper.Rules) > 0 {
+		data = wrapper.Rules
+	}
+
+	var rules akamaiV1alpha1.PropertyRules
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return nil, fmt.Errorf("failed to parse rendered rules: %w", err)
+	}
+	if rules.Name != "default" {
+		return nil, fmt.Errorf("rendered top-level rule must be named \"default\", got %q", rules.Name)
+	}
+	return &rules, nil
+}
+
+// entryOf returns the entry file of the input
+func entryOf(input Input) string {
+	if input.Entry != "" {
+		return input.Entry
+	}
+	return DefaultEntry
+}
+
+// sortedKeys returns the keys of a map in a stable order
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,117 @@
+package render
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	akamaiV1alpha1 "github.com/mmz-srf/akamai-operator/api/v1alpha1"
+)
+
+func TestRender(t *testing.T) {
+	property := &akamaiV1alpha1.AkamaiProperty{
+		ObjectMeta: metav1.ObjectMeta{Name: "shop", Labels: map[string]string{"env": "prod"}},
+		Spec:       akamaiV1alpha1.AkamaiPropertySpec{PropertyName: "shop.example.com"},
+	}
+
+	tests := []struct {
+		name     string
+		renderer Renderer
+		input    Input
+		expected string
+		err      string
+	}{
+		{
+			name:     "template with a named snippet",
+			renderer: Template{},
+			input: Input{
+				Property: property,
+				Files: map[string]string{
+					"main.json":   `{"name": "default", "comments": "{{.Name}} {{.Labels.env}}", "behaviors": [{{template "cpcode.json" .}}]}`,
+					"cpcode.json": `{"name": "cpCode", "options": {"value": {"id": {{.Values.cpCode}}}}}`,
+				},
+				Values: map[string]string{"cpCode": "123"},
+			},
+			expected: `{"name":"default","comments":"shop prod","behaviors":[{"name":"cpCode","options":{"value":{"id":123}}}],"options":null,"customOverride":null}`,
+		},
+		{
+			name:     "template with a missing value",
+			renderer: Template{},
+			input: Input{
+				Property: property,
+				Files:    map[string]string{"main.json": `{"name": "default", "comments": "{{.Values.missing}}"}`},
+				Values:   map[string]string{},
+			},
+			err: "map has no entry",
+		},
+		{
+			name:     "pipeline includes and variables",
+			renderer: Pipeline{},
+			input: Input{
+				Property: property,
+				Entry:    "rules.json",
+				Files: map[string]string{
+					"rules.json":  `{"rules": {"name": "default", "behaviors": [{"name": "cpCode", "options": {"value": {"id": "${env.cpCode}"}}}], "children": ["#include:static.json"]}}`,
+					"static.json": `{"name": "Static", "comments": "served by ${env.origin}"}`,
+				},
+				Values: map[string]string{"cpCode": "123", "origin": "origin.example.com"},
+			},
+			expected: `{"name":"default","behaviors":[{"name":"cpCode","options":{"value":{"id":123}}}],"children":[{"comments":"served by origin.example.com","name":"Static"}],"options":null,"customOverride":null}`,
+		},
+		{
+			name:     "pipeline include cycle",
+			renderer: Pipeline{},
+			input: Input{
+				Property: property,
+				Files: map[string]string{
+					"main.json": `{"name": "default", "children": ["#include:a.json"]}`,
+					"a.json":    `{"name": "a", "children": ["#include:main.json"]}`,
+				},
+			},
+			err: "include cycle",
+		},
+		{
+			name:     "pipeline undefined variable",
+			renderer: Pipeline{},
+			input: Input{
+				Property: property,
+				Files:    map[string]string{"main.json": `{"name": "default", "comments": "${env.team}"}`},
+			},
+			err: `undefined variable "team"`,
+		},
+		{
+			name:     "rendered top-level rule must be default",
+			renderer: Pipeline{},
+			input: Input{
+				Property: property,
+				Files:    map[string]string{"main.json": `{"name": "other"}`},
+			},
+			err: "must be named",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rules, err := tt.renderer.Render(context.Background(), tt.input)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("expected error containing %q, got %v", tt.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			got, err := json.Marshal(rules)
+			if err != nil {
+				t.Fatalf("failed to marshal rules: %v", err)
+			}
+			if string(got) != tt.expected {
+				t.Errorf("rendered rules = %s, expected %s", got, tt.expected)
+			}
+		})
+	}
+}
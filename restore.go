@@ -0,0 +1,82 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+
+	"github.com/mmz-srf/akamai-operator/controllers"
+	"github.com/mmz-srf/akamai-operator/pkg/akamai"
+)
+
+// runRestore implements the restore command: it re-creates the properties of AkamaiProperty
+// manifests in Akamai and writes the retargeted manifests for the operator to adopt them
+func runRestore(args []string) int {
+	flags := flag.NewFlagSet("restore", flag.ExitOnError)
+	var manifests, output, contractID, groupID, edgeHostnameTemplate, edgercPath, edgercSection string
+	var dryRun bool
+	flags.StringVar(&manifests, "manifests", "", "Comma separated AkamaiProperty manifest files or directories (YAML or JSON) to restore.")
+	flags.StringVar(&output, "output", "", "Directory the retargeted manifests with the akamai.com/property-id annotation are written to.")
+	flags.StringVar(&contractID, "contract-id", "", "Contract to restore the properties in, replacing spec.contractId.")
+	flags.StringVar(&groupID, "group-id", "", "Group to restore the properties in, replacing spec.groupId.")
+	flags.StringVar(&edgeHostnameTemplate, "edge-hostname-template", "", "Naming template for edge hostname domain prefixes, as used by the operator.")
+	flags.BoolVar(&dryRun, "dry-run", false, "Only report which properties would be restored.")
+	flags.StringVar(&edgercPath, "edgerc", os.Getenv("AKAMAI_EDGERC"), "Path of an .edgerc file with the credentials of the account to restore into.")
+	flags.StringVar(&edgercSection, "edgerc-section", os.Getenv("AKAMAI_EDGERC_SECTION"), "Section of the .edgerc file to use.")
+	opts := zap.Options{Development: true}
+	opts.BindFlags(flags)
+	_ = flags.Parse(args)
+
+	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
+	logger := ctrl.Log.WithName("restore")
+
+	if manifests == "" {
+		fmt.Fprintln(os.Stderr, "restore: --manifests is required")
+		flags.Usage()
+		return 2
+	}
+	properties, err := controllers.LoadPropertyManifests(splitList(manifests))
+	if err != nil {
+		logger.Error(err, "unable to load manifests")
+		return 1
+	}
+	if len(properties) == 0 {
+		logger.Info("No AkamaiProperty manifests found", "manifests", manifests)
+		return 0
+	}
+
+	akamaiClient, err := akamai.NewClient(akamai.Credentials{EdgercPath: edgercPath, EdgercSection: edgercSection})
+	if err != nil {
+		logger.Error(err, "unable to create Akamai client")
+		return 1
+	}
+	restorer := &controllers.Restorer{
+		AkamaiClient:         akamaiClient,
+		ContractID:           contractID,
+		GroupID:              groupID,
+		EdgeHostnameTemplate: edgeHostnameTemplate,
+		DryRun:               dryRun,
+	}
+
+	ctx := ctrl.LoggerInto(ctrl.SetupSignalHandler(), logger)
+	results, restoreErr := restorer.Restore(ctx, properties)
+	for _, result := range results {
+		fmt.Println(result)
+	}
+
+	if output != "" && !dryRun {
+		if err := controllers.WriteRestoredManifests(output, properties); err != nil {
+			logger.Error(err, "unable to write restored manifests")
+			return 1
+		}
+		logger.Info("Wrote restored manifests", "output", output, "count", len(properties))
+	}
+	if restoreErr != nil {
+		logger.Error(restoreErr, "restore incomplete")
+		return 1
+	}
+	return 0
+}